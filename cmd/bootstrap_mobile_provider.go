@@ -30,7 +30,11 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/mysteriumnetwork/node/config"
 	"github.com/mysteriumnetwork/node/consumer/entertainment"
+	"github.com/mysteriumnetwork/node/consumer/forecast"
+	consumer_reservation "github.com/mysteriumnetwork/node/consumer/reservation"
+	"github.com/mysteriumnetwork/node/core/earnings"
 	"github.com/mysteriumnetwork/node/core/node"
+	"github.com/mysteriumnetwork/node/identity/backup"
 	"github.com/mysteriumnetwork/node/services"
 	"github.com/mysteriumnetwork/node/tequilapi"
 	tequilapi_client "github.com/mysteriumnetwork/node/tequilapi/client"
@@ -53,6 +57,7 @@ func (di *Dependencies) bootstrapTequilapi(nodeOptions node.Options, listener ne
 		listener,
 		nodeOptions,
 		di.JWTAuthenticator,
+		di.Authenticator,
 		[]func(engine *gin.Engine) error{
 			func(e *gin.Engine) error {
 				if err := tequilapi_endpoints.AddRoutesForSSE(e, di.StateKeeper, di.EventBus); err != nil {
@@ -67,22 +72,48 @@ func (di *Dependencies) bootstrapTequilapi(nodeOptions node.Options, listener ne
 				return nil
 			},
 			func(e *gin.Engine) error {
-				e.GET("/healthcheck", tequilapi_endpoints.HealthCheckEndpointFactory(time.Now, os.Getpid).HealthCheck)
+				healthCheck := tequilapi_endpoints.HealthCheckEndpointFactory(time.Now, os.Getpid, di.NodeProfile)
+				if di.Watchdog != nil {
+					healthCheck.SetWatchdog(di.Watchdog)
+				}
+				e.GET("/healthcheck", healthCheck.HealthCheck)
 				return nil
 			},
 			tequilapi_endpoints.AddRouteForStop(utils.SoftKiller(di.Shutdown)),
-			tequilapi_endpoints.AddRoutesForAuthentication(di.Authenticator, di.JWTAuthenticator, di.SSOMystnodes),
+			tequilapi_endpoints.AddRoutesForAuthentication(di.Authenticator, di.Authenticator, di.LoginLimiter, di.JWTAuthenticator, di.SSOMystnodes),
 			tequilapi_endpoints.AddRoutesForIdentities(di.IdentityManager, di.IdentitySelector, di.IdentityRegistry, di.ConsumerBalanceTracker, di.AddressProvider, di.HermesChannelRepository, di.BCHelper, di.Transactor, di.BeneficiaryProvider, di.IdentityMover, di.BeneficiaryAddressStorage, di.HermesMigrator),
-			tequilapi_endpoints.AddRoutesForConnection(di.MultiConnectionManager, di.StateKeeper, di.ProposalRepository, di.IdentityRegistry, di.EventBus, di.AddressProvider),
+			tequilapi_endpoints.AddRoutesForAttestation(di.IdentityManager, di.SignerFactory, di.startedAt, time.Now),
+			tequilapi_endpoints.AddRoutesForIdentityBackup(
+				backup.NewBackup(di.IdentityMover, di.BeneficiaryAddressStorage, di.IdentityRegistry, []int64{config.GetInt64(config.FlagChain1ChainID), config.GetInt64(config.FlagChain2ChainID)}),
+				di.IdentitySelector,
+			),
+			tequilapi_endpoints.AddRoutesForIdentityUnlockPolicy(di.IdentityUnlocker),
+			tequilapi_endpoints.AddRoutesForIdentityOverrides(di.IdentityOverrides),
+			tequilapi_endpoints.AddRoutesForConnection(di.MultiConnectionManager, di.StateKeeper, di.ProposalRepository, di.IdentityRegistry, di.EventBus, di.AddressProvider, di.ProviderBlocklist),
 			tequilapi_endpoints.AddRoutesForSessions(di.SessionStorage),
+			tequilapi_endpoints.AddRoutesForForecast(forecast.NewForecaster(di.SessionStorage, di.ProposalRepository)),
+			tequilapi_endpoints.AddRoutesForEarningsProjection(earnings.NewProjector(di.SessionStorage, di.PricingHelper, di.LocationResolver)),
+			func(e *gin.Engine) error {
+				reserver := consumer_reservation.NewReserver(di.ProposalRepository, di.P2PDialer, di.ConsumerTotalsStorage, di.AddressProvider, di.Keystore)
+				if di.ReservationBook == nil {
+					return tequilapi_endpoints.AddRoutesForReservations(reserver, nil)(e)
+				}
+				return tequilapi_endpoints.AddRoutesForReservations(reserver, di.ReservationBook)(e)
+			},
 			tequilapi_endpoints.AddRoutesForConnectionLocation(di.IPResolver, di.LocationResolver, di.LocationResolver),
 			tequilapi_endpoints.AddRoutesForProposals(di.ProposalRepository, di.PricingHelper, di.LocationResolver, di.FilterPresetStorage, di.NATProber),
 			tequilapi_endpoints.AddRoutesForService(di.ServicesManager, services.JSONParsersByType, di.ProposalRepository, tequilaApiClient),
 			tequilapi_endpoints.AddRoutesForAccessPolicies(di.HTTPClient, config.GetString(config.FlagAccessPolicyAddress)),
-			tequilapi_endpoints.AddRoutesForNAT(di.StateKeeper, di.NATProber),
+			tequilapi_endpoints.AddRoutesForNAT(di.StateKeeper, di.NATProber, di.NATStatusTracker),
+			tequilapi_endpoints.AddRoutesForState(di.StateKeeper),
 			tequilapi_endpoints.AddRoutesForNodeUI(versionmanager.NewVersionManager(di.UIServer, di.HTTPClient, di.uiVersionConfig)),
 			tequilapi_endpoints.AddRoutesForNode(di.NodeStatusTracker, di.NodeStatsTracker),
-			tequilapi_endpoints.AddRoutesForTransactor(di.IdentityRegistry, di.Transactor, di.Affiliator, di.HermesPromiseSettler, di.SettlementHistoryStorage, di.AddressProvider, di.BeneficiaryProvider, di.BeneficiarySaver, di.PilvytisAPI),
+			tequilapi_endpoints.AddRoutesForNodeProfile(di.NodeProfile),
+			tequilapi_endpoints.AddRoutesForTransactor(di.IdentityRegistry, di.Transactor, di.Affiliator, di.HermesPromiseSettler, di.SettlementHistoryStorage, di.AddressProvider, di.BeneficiaryProvider, di.BeneficiarySaver, di.PilvytisAPI, di.Compliance, di.AddressBook),
+			tequilapi_endpoints.AddRoutesForTransactorAudit(di.TransactorAudit),
+			tequilapi_endpoints.AddRoutesForBeneficiary(di.BeneficiaryManager, di.IdentityManager, di.Compliance, di.AddressBook),
+			tequilapi_endpoints.AddRoutesForAddressBook(di.AddressBook),
+			tequilapi_endpoints.AddRoutesForStake(di.StakeManager, di.IdentityManager, di.Compliance),
 			tequilapi_endpoints.AddRoutesForAffiliator(di.Affiliator),
 			tequilapi_endpoints.AddRoutesForConfig,
 			tequilapi_endpoints.AddRoutesForMMN(di.MMN, di.SSOMystnodes, di.Authenticator),
@@ -90,8 +121,9 @@ func (di *Dependencies) bootstrapTequilapi(nodeOptions node.Options, listener ne
 			tequilapi_endpoints.AddRoutesForConnectivityStatus(di.SessionConnectivityStatusStorage),
 			tequilapi_endpoints.AddRoutesForDocs,
 			tequilapi_endpoints.AddRoutesForCurrencyExchange(di.PilvytisAPI),
-			tequilapi_endpoints.AddRoutesForPilvytis(di.PilvytisAPI, di.PilvytisOrderIssuer, di.LocationResolver),
-			tequilapi_endpoints.AddRoutesForTerms,
+			tequilapi_endpoints.AddRoutesForPilvytis(di.PilvytisAPI, di.PilvytisOrderIssuer, di.LocationResolver, di.Compliance),
+			tequilapi_endpoints.AddRoutesForTerms(di.Compliance),
+			tequilapi_endpoints.AddRoutesForAnalytics(di.Analytics),
 			tequilapi_endpoints.AddEntertainmentRoutes(entertainment.NewEstimator(
 				config.FlagPaymentPriceGiB.Value,
 				config.FlagPaymentPriceHour.Value,
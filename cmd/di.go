@@ -22,8 +22,11 @@ import (
 	"net"
 	"net/http"
 	"net/url"
+	"os"
 	"path/filepath"
 	"reflect"
+	"runtime"
+	"strings"
 	"time"
 
 	"github.com/ethereum/go-ethereum/accounts/keystore"
@@ -35,33 +38,53 @@ import (
 	"github.com/mysteriumnetwork/node/config"
 	"github.com/mysteriumnetwork/node/consumer/migration"
 	consumer_session "github.com/mysteriumnetwork/node/consumer/session"
+	"github.com/mysteriumnetwork/node/core/analytics"
 	"github.com/mysteriumnetwork/node/core/auth"
 	"github.com/mysteriumnetwork/node/core/beneficiary"
+	"github.com/mysteriumnetwork/node/core/chainswitch"
+	"github.com/mysteriumnetwork/node/core/compliance"
 	"github.com/mysteriumnetwork/node/core/connection"
+	"github.com/mysteriumnetwork/node/core/connection/capture"
 	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
+	"github.com/mysteriumnetwork/node/core/connection/external"
+	"github.com/mysteriumnetwork/node/core/connection/onlinecheck"
+	"github.com/mysteriumnetwork/node/core/connection/routing"
 	"github.com/mysteriumnetwork/node/core/discovery"
 	"github.com/mysteriumnetwork/node/core/discovery/proposal"
+	"github.com/mysteriumnetwork/node/core/diskusage"
 	"github.com/mysteriumnetwork/node/core/ip"
+	"github.com/mysteriumnetwork/node/core/lifecycle"
 	"github.com/mysteriumnetwork/node/core/location"
 	"github.com/mysteriumnetwork/node/core/monitoring"
+	"github.com/mysteriumnetwork/node/core/mqtt"
 	"github.com/mysteriumnetwork/node/core/node"
 	nodevent "github.com/mysteriumnetwork/node/core/node/event"
 	"github.com/mysteriumnetwork/node/core/policy"
 	"github.com/mysteriumnetwork/node/core/policy/localcopy"
 	"github.com/mysteriumnetwork/node/core/port"
+	"github.com/mysteriumnetwork/node/core/profile"
 	"github.com/mysteriumnetwork/node/core/quality"
+	"github.com/mysteriumnetwork/node/core/rpcfailover"
 	"github.com/mysteriumnetwork/node/core/service"
+	"github.com/mysteriumnetwork/node/core/service/capacity"
+	"github.com/mysteriumnetwork/node/core/service/reservation"
+	"github.com/mysteriumnetwork/node/core/stake"
 	"github.com/mysteriumnetwork/node/core/state"
 	"github.com/mysteriumnetwork/node/core/storage/boltdb"
 	"github.com/mysteriumnetwork/node/core/storage/boltdb/migrations/history"
 	"github.com/mysteriumnetwork/node/core/storage/boltdb/migrator"
+	"github.com/mysteriumnetwork/node/core/watchdog"
+	"github.com/mysteriumnetwork/node/core/webhook"
 	"github.com/mysteriumnetwork/node/dns"
 	"github.com/mysteriumnetwork/node/eventbus"
 	"github.com/mysteriumnetwork/node/feedback"
 	"github.com/mysteriumnetwork/node/firewall"
 	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/identity/overrides"
 	"github.com/mysteriumnetwork/node/identity/registry"
+	identity_remote "github.com/mysteriumnetwork/node/identity/remote"
 	identity_selector "github.com/mysteriumnetwork/node/identity/selector"
+	"github.com/mysteriumnetwork/node/identity/unlock"
 	"github.com/mysteriumnetwork/node/logconfig"
 	"github.com/mysteriumnetwork/node/market/mysterium"
 	"github.com/mysteriumnetwork/node/metadata"
@@ -81,14 +104,20 @@ import (
 	"github.com/mysteriumnetwork/node/services/wireguard/endpoint"
 	"github.com/mysteriumnetwork/node/session/connectivity"
 	"github.com/mysteriumnetwork/node/session/pingpong"
+	"github.com/mysteriumnetwork/node/session/stats"
 	"github.com/mysteriumnetwork/node/sleep"
 	"github.com/mysteriumnetwork/node/tequilapi"
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
+	"github.com/mysteriumnetwork/node/tequilapi/recorder"
 	"github.com/mysteriumnetwork/node/tequilapi/sso"
+	"github.com/mysteriumnetwork/node/testkit/replay"
+	"github.com/mysteriumnetwork/node/trace/otlp"
 	"github.com/mysteriumnetwork/node/ui/versionmanager"
 	"github.com/mysteriumnetwork/node/utils/netutil"
 	paymentClient "github.com/mysteriumnetwork/payments/client"
 	psort "github.com/mysteriumnetwork/payments/client/sort"
 	"github.com/mysteriumnetwork/payments/observer"
+	"github.com/mysteriumnetwork/terms/terms-go"
 )
 
 // UIServer represents our web server
@@ -104,6 +133,7 @@ type Dependencies struct {
 
 	HTTPTransport *http.Transport
 	HTTPClient    *requests.HTTPClient
+	UpstreamProxy *requests.UpstreamProxy
 
 	NetworkDefinition metadata.NetworkDefinition
 	MysteriumAPI      *mysterium.MysteriumAPI
@@ -115,20 +145,30 @@ type Dependencies struct {
 	SorterClientL2 *psort.MultiClientSorter
 
 	EtherClients []*paymentClient.ReconnectableEthClient
+	RPCFailover  *rpcfailover.MultichainClient
 
 	BrokerConnector  *nats.BrokerConnector
 	BrokerConnection nats.Connection
+	Recorder         *replay.Recorder
+	recordingServers []*http.Server
 
 	NATService       nat.NATService
 	NATProber        natprobe.NATProber
+	NATStatusTracker *natprobe.StatusTracker
+	KeepAliveTuner   *natprobe.KeepAliveTuner
 	Storage          *boltdb.Bolt
 	Keystore         *identity.Keystore
 	IdentityManager  identity.Manager
+	IdentityUnlocker *unlock.Manager
 	SignerFactory    identity.SignerFactory
 	IdentityRegistry registry.IdentityRegistry
 	IdentitySelector identity_selector.Handler
 	IdentityMover    *identity.Mover
 	FreeRegistrar    *registry.FreeRegistrar
+	RegistryWatcher  *registry.RegistryWatcher
+	ChainChecker     *registry.ChainChecker
+	WebhookEmitter   *webhook.Emitter
+	MQTTBridge       *mqtt.Bridge
 
 	DiscoveryFactory    service.DiscoveryFactory
 	ProposalRepository  *discovery.PricedServiceProposalRepository
@@ -136,9 +176,12 @@ type Dependencies struct {
 	DiscoveryWorker     discovery.Worker
 
 	QualityClient *quality.MysteriumMORQA
+	Analytics     *analytics.Pipeline
+	TraceExporter *otlp.Exporter
 
-	IPResolver       ip.Resolver
-	LocationResolver *location.Cache
+	IPResolver        ip.Resolver
+	LocationResolver  *location.Cache
+	LocationDBUpdater *location.UpdatingDBResolver
 
 	dnsProxy *dns.Proxy
 
@@ -146,32 +189,50 @@ type Dependencies struct {
 	PolicyProvider policy.Provider
 
 	SessionStorage                   *consumer_session.Storage
+	SessionArchive                   *boltdb.Bolt
+	ArchivedSessionStorage           *consumer_session.Storage
 	SessionConnectivityStatusStorage connectivity.StatusStorage
 
 	EventBus eventbus.EventBus
 
 	MultiConnectionManager connection.MultiManager
 	ConnectionRegistry     *connection.Registry
+	ProviderBlocklist      *connection.ProviderBlocklist
+	ExternalEndpoints      *external.Repository
+	ConnectionRoutes       *routing.Repository
 
 	ServicesManager *service.Manager
+	Compliance      *compliance.Gate
 	ServiceRegistry *service.Registry
 	ServiceSessions *service.SessionPool
 	ServiceFirewall firewall.IncomingTrafficFirewall
+	CapacityMonitor *capacity.Monitor
+	LoadShedder     *capacity.Shedder
+	ReservationBook *reservation.Book
+	DiskUsage       *diskusage.Monitor
+	Watchdog        *watchdog.Watchdog
+	ChainSwitcher   *chainswitch.Switcher
+	SessionStats    *stats.Sampler
 
 	WireguardClientFactory *endpoint.WgClientFactory
+	PacketCapturer         *capture.Capturer
+	TequilapiRecorder      *recorder.Recorder
 
 	PortPool   *port.Pool
 	PortMapper mapping.PortMapper
 
 	StateKeeper *state.Keeper
+	NodeProfile *profile.Store
 
 	P2PDialer   p2p.Dialer
 	P2PListener p2p.Listener
 
 	Authenticator    *auth.Authenticator
 	JWTAuthenticator *auth.JWTAuthenticator
+	LoginLimiter     *auth.LoginLimiter
 	UIServer         UIServer
 	Transactor       *registry.Transactor
+	TransactorAudit  *registry.TransactorAuditLog
 	Affiliator       *registry.Affiliator
 	BCHelper         *paymentClient.MultichainBlockchainClient
 	SSOMystnodes     *sso.Mystnodes
@@ -181,6 +242,11 @@ type Dependencies struct {
 
 	BeneficiarySaver    *beneficiary.Saver
 	BeneficiaryProvider *beneficiary.Provider
+	BeneficiaryManager  *beneficiary.Manager
+
+	IdentityOverrides *overrides.Storage
+
+	StakeManager *stake.Manager
 
 	ProviderInvoiceStorage   *pingpong.ProviderInvoiceStorage
 	ConsumerTotalsStorage    *pingpong.ConsumerTotalsStorage
@@ -207,13 +273,16 @@ type Dependencies struct {
 	ResidentCountry *identity.ResidentCountry
 
 	BeneficiaryAddressStorage beneficiary.BeneficiaryStorage
+	AddressBook               *beneficiary.AddressBook
 	NodeStatusTracker         *monitoring.StatusTracker
 	NodeStatsTracker          *node.StatsTracker
 	uiVersionConfig           versionmanager.NodeUIVersionConfig
+	startedAt                 time.Time
 }
 
 // Bootstrap initiates all container dependencies
 func (di *Dependencies) Bootstrap(nodeOptions node.Options) error {
+	di.startedAt = time.Now()
 	logconfig.Configure(&nodeOptions.LogOptions)
 
 	netutil.LogNetworkStats()
@@ -241,6 +310,8 @@ func (di *Dependencies) Bootstrap(nodeOptions node.Options) error {
 	if err := di.bootstrapStorage(nodeOptions.Directories.Storage); err != nil {
 		return err
 	}
+	di.bootstrapDiskUsage(nodeOptions.Directories)
+	di.bootstrapWatchdog()
 
 	if err := di.bootstrapNetworkComponents(nodeOptions); err != nil {
 		return err
@@ -283,18 +354,39 @@ func (di *Dependencies) Bootstrap(nodeOptions node.Options) error {
 	di.bootstrapP2P()
 	di.SessionConnectivityStatusStorage = connectivity.NewStatusStorage()
 
+	if err := di.bootstrapCompliance(); err != nil {
+		return err
+	}
 	if err := di.bootstrapServices(nodeOptions); err != nil {
 		return err
 	}
 
+	if err := di.bootstrapAnalytics(nodeOptions.Analytics); err != nil {
+		return err
+	}
+	if err := di.bootstrapTracing(); err != nil {
+		return err
+	}
 	if err := di.bootstrapQualityComponents(nodeOptions.Quality); err != nil {
 		return err
 	}
 
+	if err := di.bootstrapWebhook(nodeOptions.Webhook); err != nil {
+		return err
+	}
+
+	if err := di.bootstrapMQTT(nodeOptions.MQTT); err != nil {
+		return err
+	}
+
 	if err := di.bootstrapNodeComponents(nodeOptions, tequilaListener); err != nil {
 		return err
 	}
 
+	if err := di.Compliance.PublishOutdated(); err != nil {
+		log.Warn().Err(err).Msg("Failed to check accepted terms version")
+	}
+
 	di.registerConnections(nodeOptions)
 	if err = di.handleConnStateChange(); err != nil {
 		return err
@@ -356,7 +448,10 @@ func (di *Dependencies) bootstrapP2P() {
 	}
 
 	di.P2PListener = p2p.NewListener(di.BrokerConnection, di.SignerFactory, identity.NewVerifierSigned(), di.IPResolver, di.EventBus)
-	di.P2PDialer = p2p.NewDialer(di.BrokerConnector, di.SignerFactory, verifierFactory, di.IPResolver, di.PortPool, di.EventBus)
+	di.P2PDialer = p2p.NewDialer(di.BrokerConnector, di.SignerFactory, verifierFactory, di.IPResolver, di.PortPool, di.EventBus, p2p.DialerOptions{
+		RandomizePorts: config.GetBool(config.FlagTrafficRandomizePorts),
+		PadControl:     config.GetBool(config.FlagTrafficPadControl),
+	})
 }
 
 func (di *Dependencies) createTequilaListener(nodeOptions node.Options) (net.Listener, error) {
@@ -372,6 +467,8 @@ func (di *Dependencies) createTequilaListener(nodeOptions node.Options) (net.Lis
 }
 
 func (di *Dependencies) bootstrapStateKeeper(options node.Options) error {
+	di.NodeProfile = profile.NewStore(config.Current, di.EventBus)
+
 	deps := state.KeeperDeps{
 		Publisher:                 di.EventBus,
 		ServiceLister:             di.ServicesManager,
@@ -382,6 +479,7 @@ func (di *Dependencies) bootstrapStateKeeper(options node.Options) error {
 		EarningsProvider:          di.HermesChannelRepository,
 		ChainID:                   options.ChainID,
 		ProposalPricer:            di.ProposalRepository,
+		ProfileProvider:           di.NodeProfile,
 	}
 
 	di.StateKeeper = state.NewKeeper(deps, state.DefaultDebounceDuration)
@@ -413,82 +511,161 @@ func (di *Dependencies) registerNoopConnection() {
 }
 
 // Shutdown stops container
-func (di *Dependencies) Shutdown() (err error) {
-	var errs []error
-	defer func() {
-		for i := range errs {
-			log.Error().Err(errs[i]).Msg("Dependencies shutdown failed")
-			if err == nil {
-				err = errs[i]
-			}
-		}
-	}()
-
-	// Kill node first which includes current active VPN connection cleanup.
-	if di.Node != nil {
-		if err := di.Node.Kill(); err != nil {
-			errs = append(errs, err)
-		}
+// Shutdown stops the running components in reverse dependency order, each bounded by its timeout.
+// The process is forced to exit if the whole shutdown does not finish in time.
+func (di *Dependencies) Shutdown() error {
+	if timeout := config.GetDuration(config.FlagShutdownTimeout); timeout > 0 {
+		timer := time.AfterFunc(timeout, func() {
+			log.Error().Msgf("Dependencies shutdown did not finish within %s, forcing exit", timeout)
+			os.Exit(1)
+		})
+		defer timer.Stop()
+	}
+
+	graph, err := di.lifecycleGraph()
+	if err != nil {
+		return err
 	}
+	return graph.Shutdown()
+}
 
-	if di.ServicesManager != nil {
-		if err := di.ServicesManager.Kill(); err != nil {
-			errs = append(errs, err)
+// lifecycleGraph describes the running components along with the components they use.
+// Components are added in start order, so that independent ones are stopped in reverse of it.
+func (di *Dependencies) lifecycleGraph() (*lifecycle.Graph, error) {
+	g := lifecycle.NewGraph()
+	add := func(name string, stop func() error, dependsOn ...string) {
+		g.Add(lifecycle.Component{Name: name, Stop: stop, DependsOn: dependsOn})
+	}
+	stopper := func(stop func()) func() error {
+		return func() error {
+			stop()
+			return nil
 		}
 	}
 
-	if di.PolicyOracle != nil {
-		di.PolicyOracle.Stop()
+	// Wipe decrypted private keys once nothing is left to sign.
+	if di.Keystore != nil {
+		add("keystore", stopper(func() { di.Keystore.LockAll() }))
 	}
-
-	if di.NATService != nil {
-		if err := di.NATService.Disable(); err != nil {
-			errs = append(errs, err)
+	if di.Storage != nil {
+		add("storage", di.Storage.Close)
+	}
+	if di.SessionArchive != nil {
+		add("session-archive", di.SessionArchive.Close)
+	}
+	add("router", router.Clean)
+	add("firewall", stopper(func() {
+		if di.ServiceFirewall != nil {
+			di.ServiceFirewall.Teardown()
 		}
+		firewall.Reset()
+	}))
+
+	if di.TraceExporter != nil {
+		add("trace-exporter", stopper(di.TraceExporter.Stop))
+	}
+	if di.Analytics != nil {
+		add("analytics", stopper(di.Analytics.Stop))
+	}
+	if di.QualityClient != nil {
+		add("quality-client", stopper(di.QualityClient.Stop))
+	}
+	if di.Recorder != nil {
+		add("recorder", di.stopRecording)
+	}
+	if di.BrokerConnection != nil {
+		add("broker", stopper(di.BrokerConnection.Close), "recorder")
 	}
 
 	if di.EtherClientL1 != nil {
-		di.EtherClientL1.Close()
+		add("ether-client-l1", stopper(di.EtherClientL1.Close), "recorder")
 	}
 	if di.SorterClientL1 != nil {
-		di.SorterClientL1.Stop()
+		add("sorter-client-l1", stopper(di.SorterClientL1.Stop), "ether-client-l1")
 	}
-
 	if di.EtherClientL2 != nil {
-		di.EtherClientL2.Close()
+		add("ether-client-l2", stopper(di.EtherClientL2.Close), "recorder")
 	}
 	if di.SorterClientL2 != nil {
-		di.SorterClientL2.Stop()
+		add("sorter-client-l2", stopper(di.SorterClientL2.Stop), "ether-client-l2")
 	}
 
-	if di.DiscoveryWorker != nil {
-		di.DiscoveryWorker.Stop()
+	if di.RPCFailover != nil {
+		add("rpc-failover", stopper(di.RPCFailover.Stop), "recorder")
+	}
+
+	if di.NATService != nil {
+		add("nat", di.NATService.Disable, "firewall")
+	}
+	if di.MQTTBridge != nil {
+		add("mqtt-bridge", stopper(di.MQTTBridge.Stop))
+	}
+	if di.WebhookEmitter != nil {
+		add("webhook-emitter", stopper(di.WebhookEmitter.Stop))
+	}
+	if di.IdentityUnlocker != nil {
+		add("identity-unlocker", stopper(di.IdentityUnlocker.Stop), "keystore")
 	}
 	if di.PilvytisTracker != nil {
-		di.PilvytisTracker.Stop()
+		add("pilvytis-tracker", stopper(di.PilvytisTracker.Stop))
 	}
-	if di.BrokerConnection != nil {
-		di.BrokerConnection.Close()
+	if di.LocationDBUpdater != nil {
+		add("location-updater", stopper(di.LocationDBUpdater.Stop))
 	}
-
-	if di.QualityClient != nil {
-		di.QualityClient.Stop()
+	if di.DiscoveryWorker != nil {
+		add("discovery", stopper(di.DiscoveryWorker.Stop), "broker")
 	}
-
-	if di.ServiceFirewall != nil {
-		di.ServiceFirewall.Teardown()
+	if di.PolicyOracle != nil {
+		add("policy-oracle", stopper(di.PolicyOracle.Stop))
+	}
+	if di.SessionStats != nil {
+		add("session-stats", stopper(di.SessionStats.Stop), "storage")
 	}
-	firewall.Reset()
 
-	if di.Storage != nil {
-		if err := di.Storage.Close(); err != nil {
-			errs = append(errs, err)
-		}
+	if di.KeepAliveTuner != nil {
+		add("keepalive-tuner", stopper(di.KeepAliveTuner.Stop))
+	}
+	if di.RegistryWatcher != nil {
+		add("registry-watcher", stopper(di.RegistryWatcher.Stop), "storage", "ether-client-l1", "ether-client-l2")
+	}
+	if di.BeneficiaryManager != nil {
+		add("beneficiary-manager", stopper(di.BeneficiaryManager.Stop), "storage")
+	}
+	if di.Watchdog != nil {
+		add("watchdog", stopper(di.Watchdog.Stop))
+	}
+	if di.DiskUsage != nil {
+		add("disk-usage", stopper(di.DiskUsage.Stop), "storage")
+	}
+	if di.CapacityMonitor != nil {
+		add("capacity-monitor", stopper(di.CapacityMonitor.Stop))
+	}
+	if di.LoadShedder != nil {
+		add("load-shedder", stopper(di.LoadShedder.Stop))
 	}
 
-	router.Clean()
+	if di.ServicesManager != nil {
+		g.Add(lifecycle.Component{
+			Name:      "services",
+			Stop:      di.ServicesManager.Kill,
+			DependsOn: []string{"keystore", "storage", "router", "firewall", "broker", "nat", "policy-oracle"},
+			Timeout:   30 * time.Second,
+		})
+	}
+	// Node is stopped first, it includes current active VPN connection cleanup.
+	if di.Node != nil {
+		g.Add(lifecycle.Component{
+			Name:      "node",
+			Stop:      di.Node.Kill,
+			DependsOn: []string{"services", "keystore", "storage", "router", "firewall", "broker"},
+			Timeout:   30 * time.Second,
+		})
+	}
 
-	return nil
+	if _, err := g.Order(); err != nil {
+		return nil, fmt.Errorf("invalid component dependencies: %w", err)
+	}
+	return g, nil
 }
 
 func (di *Dependencies) bootstrapStorage(path string) error {
@@ -509,11 +686,61 @@ func (di *Dependencies) bootstrapStorage(path string) error {
 	di.ProviderInvoiceStorage = pingpong.NewProviderInvoiceStorage(invoiceStorage)
 	di.ConsumerTotalsStorage = pingpong.NewConsumerTotalsStorage(di.EventBus)
 	di.HermesPromiseStorage = pingpong.NewHermesPromiseStorage(di.Storage)
-	di.SessionStorage = consumer_session.NewSessionStorage(di.Storage)
+	reconciliation := consumer_session.ReconciliationConfig{
+		Tolerance: config.GetFloat64(config.FlagSessionReconciliationTolerance),
+		DataSlack: config.GetUInt64(config.FlagSessionReconciliationDataSlack),
+	}
+	di.SessionStorage = consumer_session.NewSessionStorage(di.Storage, reconciliation)
 	di.SettlementHistoryStorage = pingpong.NewSettlementHistoryStorage(di.Storage)
+
+	if archivePath := config.GetString(config.FlagSessionHistoryArchive); archivePath != "" {
+		archive, err := boltdb.OpenReadOnly(archivePath)
+		if err != nil {
+			return err
+		}
+		di.SessionArchive = archive
+		di.ArchivedSessionStorage = consumer_session.NewSessionStorage(archive, reconciliation)
+	}
+
 	return di.SessionStorage.Subscribe(di.EventBus)
 }
 
+func (di *Dependencies) bootstrapDiskUsage(dirs node.OptionsDirectory) {
+	interval := config.GetDuration(config.FlagDiskUsageInterval)
+	if interval <= 0 {
+		return
+	}
+
+	di.DiskUsage = diskusage.NewMonitor(diskusage.Config{
+		DataDir:  dirs.Data,
+		LogDir:   config.GetString(config.FlagLogDir),
+		Interval: interval,
+		Horizon:  config.GetDuration(config.FlagDiskUsageHorizon),
+	}, di.Storage, di.EventBus)
+	di.DiskUsage.Start()
+}
+
+func (di *Dependencies) bootstrapWatchdog() {
+	interval := config.GetDuration(config.FlagWatchdogInterval)
+	if interval <= 0 {
+		return
+	}
+
+	di.Watchdog = watchdog.NewWatchdog(watchdog.Config{
+		Interval: interval,
+		Restart:  config.GetBool(config.FlagWatchdogRestart),
+	}, di.EventBus)
+	di.Watchdog.Start()
+}
+
+// heartbeat registers the long-running component in the watchdog, nil heartbeat is returned if the watchdog is disabled.
+func (di *Dependencies) heartbeat(name string, timeout time.Duration) *watchdog.Heartbeat {
+	if di.Watchdog == nil {
+		return nil
+	}
+	return di.Watchdog.Register(name, timeout, nil)
+}
+
 func (di *Dependencies) getHermesURL(nodeOptions node.Options) (string, error) {
 	log.Info().Msgf("Node chain id %v", nodeOptions.ChainID)
 	addr := common.HexToAddress(nodeOptions.Chains.Chain2.HermesID)
@@ -554,7 +781,6 @@ func (di *Dependencies) bootstrapNodeComponents(nodeOptions node.Options, tequil
 		return errors.Wrap(err, "could not subscribe consumer balance tracker to relevant events")
 	}
 
-	di.BeneficiaryAddressStorage = beneficiary.NewAddressStorage(di.Storage)
 	di.bootstrapBeneficiaryProvider(nodeOptions)
 
 	di.HermesPromiseHandler = pingpong.NewHermesPromiseHandler(pingpong.HermesPromiseHandlerDeps{
@@ -579,8 +805,43 @@ func (di *Dependencies) bootstrapNodeComponents(nodeOptions node.Options, tequil
 	}
 
 	di.bootstrapBeneficiarySaver(nodeOptions)
+	if err := di.bootstrapBeneficiaryManager(nodeOptions); err != nil {
+		return err
+	}
+	di.StakeManager = stake.NewManager(di.Transactor, di.HermesPromiseSettler, di.BCHelper, di.AddressProvider, di.EventBus)
 
 	di.ConnectionRegistry = connection.NewRegistry()
+	di.ConnectionRoutes = routing.NewRepository(di.Storage)
+	di.ProviderBlocklist = connection.NewProviderBlocklist(
+		config.GetInt(config.FlagProviderBlocklistFailures),
+		config.GetDuration(config.FlagProviderBlocklistPeriod),
+	)
+	if err := di.ProviderBlocklist.Subscribe(di.EventBus); err != nil {
+		return err
+	}
+	connectionConfig := connection.DefaultConfig()
+	connectionConfig.KeyRotation = config.GetDuration(config.FlagWireguardKeyRotation)
+	connectionConfig.RoamingCheck = config.GetDuration(config.FlagSessionRoamingCheck)
+	connectionConfig.Mitigations = connection.TrafficMitigations{
+		RandomizePorts:  config.GetBool(config.FlagTrafficRandomizePorts),
+		KeepAliveJitter: config.GetBool(config.FlagTrafficKeepAliveJitter),
+		PadControl:      config.GetBool(config.FlagTrafficPadControl),
+	}
+	if config.GetBool(config.FlagConnectivityCheckEnabled) {
+		checker, err := onlinecheck.NewChecker(onlinecheck.Config{
+			Targets: config.GetStringSlice(config.FlagConnectivityCheckURLs),
+			Skip:    config.GetStringSlice(config.FlagConnectivityCheckSkip),
+			Quorum:  config.GetInt(config.FlagConnectivityCheckQuorum),
+		})
+		if err != nil {
+			return fmt.Errorf("invalid connectivity check configuration: %w", err)
+		}
+		connectionConfig.OnlineCheck = connection.OnlineCheckConfig{
+			Checker:     checker,
+			Interval:    config.GetDuration(config.FlagConnectivityCheckInterval),
+			MaxFailures: config.GetInt(config.FlagConnectivityCheckMaxFailures),
+		}
+	}
 	di.MultiConnectionManager = connection.NewMultiConnectionManager(func() connection.Manager {
 		return connection.NewManager(
 			pingpong.ExchangeFactoryFunc(
@@ -595,19 +856,29 @@ func (di *Dependencies) bootstrapNodeComponents(nodeOptions node.Options, tequil
 			di.EventBus,
 			di.IPResolver,
 			di.LocationResolver,
-			connection.DefaultConfig(),
+			connectionConfig,
 			config.GetDuration(config.FlagStatsReportInterval),
 			connection.NewValidator(
 				di.ConsumerBalanceTracker,
 				di.IdentityManager,
 			),
 			di.P2PDialer,
+			di.SessionStorage.Reconciler(consumer_session.DirectionConsumed),
+			di.ExternalEndpoints,
 			di.allowTrustedDomainBypassTunnel,
 			di.disallowTrustedDomainBypassTunnel,
 		)
 	})
 
 	di.NATProber = natprobe.NewNATProber(di.MultiConnectionManager, di.EventBus)
+	di.NATStatusTracker = natprobe.NewStatusTracker()
+	if err := di.NATStatusTracker.Subscribe(di.EventBus); err != nil {
+		return err
+	}
+	if config.GetBool(config.FlagKeepAliveAutoTune) {
+		di.KeepAliveTuner = natprobe.NewKeepAliveTuner(di.MultiConnectionManager, di.EventBus)
+		di.KeepAliveTuner.Start()
+	}
 
 	di.LogCollector = logconfig.NewCollector(&logconfig.CurrentLogOptions)
 	reporter, err := feedback.NewReporter(di.LogCollector, di.IdentityManager, di.LocationResolver, nodeOptions.FeedbackURL)
@@ -647,6 +918,18 @@ func (di *Dependencies) bootstrapNodeComponents(nodeOptions node.Options, tequil
 		return fmt.Errorf("error during subscribe: %w", err)
 	}
 
+	if di.ServicesManager != nil {
+		di.ChainSwitcher = chainswitch.NewSwitcher(
+			[]int64{nodeOptions.Chains.Chain1.ChainID, nodeOptions.Chains.Chain2.ChainID},
+			di.AddressProvider,
+			di.ServiceSessions,
+			di.MultiConnectionManager,
+			di.RegistryWatcher,
+			di.ServicesManager,
+			config.Current,
+		)
+	}
+
 	tequilapiHTTPServer, err := di.bootstrapTequilapi(nodeOptions, tequilaListener)
 	if err != nil {
 		return err
@@ -655,6 +938,11 @@ func (di *Dependencies) bootstrapNodeComponents(nodeOptions node.Options, tequil
 	sleepNotifier := sleep.NewNotifier(di.MultiConnectionManager, di.EventBus)
 	sleepNotifier.Subscribe()
 
+	// Identities are unlocked once all the components are subscribed to identity events.
+	if err := di.IdentityUnlocker.Start(); err != nil {
+		log.Error().Err(err).Msg("Could not apply identity unlock policy")
+	}
+
 	di.Node = NewNode(di.MultiConnectionManager, tequilapiHTTPServer, di.EventBus, di.UIServer, sleepNotifier)
 
 	return nil
@@ -701,8 +989,26 @@ func (di *Dependencies) bootstrapNetworkComponents(options node.Options) (err er
 	}
 	resolver := resolver.NewResolverMap(dnsMap)
 
+	if options.BindInterface != "" {
+		if err := router.BindInterface(options.BindInterface); err != nil {
+			return err
+		}
+		log.Info().Msgf("Binding outbound node traffic to interface: %s", options.BindInterface)
+	}
+	if options.BindInterface != "" || options.BindAddress != config.FlagBindAddress.Value {
+		requests.BindDefaultTransport(options.BindAddress)
+	}
+
 	dialer := requests.NewDialerSwarm(options.BindAddress, options.SwarmDialerDNSHeadstart)
 	dialer.ResolveContext = resolver
+	if options.UpstreamProxy != "" {
+		if di.UpstreamProxy, err = requests.ParseUpstreamProxy(options.UpstreamProxy); err != nil {
+			return err
+		}
+		log.Info().Msgf("Routing node control traffic through upstream proxy: %s", di.UpstreamProxy)
+		dialer.Dialer = di.UpstreamProxy.Wrap(dialer.Dialer)
+		requests.ProxyDefaultTransport(di.UpstreamProxy)
+	}
 	di.HTTPTransport = requests.NewTransport(dialer.DialContext)
 	di.HTTPClient = requests.NewHTTPClientWithTransport(di.HTTPTransport, requests.DefaultTimeout)
 	di.MysteriumAPI = mysterium.NewClient(di.HTTPClient, network.DiscoveryAddress)
@@ -726,6 +1032,12 @@ func (di *Dependencies) bootstrapNetworkComponents(options node.Options) (err er
 		return err
 	}
 
+	if path := config.GetString(config.FlagRecordPath); path != "" {
+		if err := di.bootstrapRecorder(path, &network); err != nil {
+			return err
+		}
+	}
+
 	log.Info().Msgf("Using L1 Eth endpoints: %v", network.Chain1.EtherClientRPC)
 	log.Info().Msgf("Using L2 Eth endpoints: %v", network.Chain2.EtherClientRPC)
 
@@ -787,6 +1099,17 @@ func (di *Dependencies) bootstrapNetworkComponents(options node.Options) (err er
 	clients[options.Chains.Chain2.ChainID] = bcL2
 
 	di.BCHelper = paymentClient.NewMultichainBlockchainClient(clients)
+
+	failoverCfg := rpcfailover.Config{
+		CallTimeout:         options.EtherClientFailoverTimeout,
+		HealthCheckInterval: options.EtherClientHealthCheckInterval,
+	}
+	di.RPCFailover = rpcfailover.NewMultichainClient(map[int64]*rpcfailover.Client{
+		options.Chains.Chain1.ChainID: rpcfailover.NewClient(options.Chains.Chain1.ChainID, bcClientsL1, di.EventBus, failoverCfg),
+		options.Chains.Chain2.ChainID: rpcfailover.NewClient(options.Chains.Chain2.ChainID, bcClientsL2, di.EventBus, failoverCfg),
+	})
+	di.RPCFailover.Start()
+
 	di.ObserverAPI = observer.NewAPI(options.ObserverAddress, time.Second*30)
 	di.bootstrapAddressProvider(options)
 	di.HermesURLGetter = pingpong.NewHermesURLGetter(di.BCHelper, di.AddressProvider, di.ObserverAPI)
@@ -799,14 +1122,37 @@ func (di *Dependencies) bootstrapNetworkComponents(options node.Options) (err er
 	}
 
 	di.HermesCaller = pingpong.NewHermesCaller(di.HTTPClient, hermesURL)
-	di.SignerFactory = func(id identity.Identity) identity.Signer {
-		return identity.NewSigner(di.Keystore, id)
+	if options.Keystore.RemoteSigner.URL != "" {
+		remoteSigner, err := identity_remote.NewClient(identity_remote.Config{
+			URL:      options.Keystore.RemoteSigner.URL,
+			CertFile: options.Keystore.RemoteSigner.CertFile,
+			KeyFile:  options.Keystore.RemoteSigner.KeyFile,
+			CAFile:   options.Keystore.RemoteSigner.CAFile,
+		})
+		if err != nil {
+			return err
+		}
+		log.Info().Msgf("Using remote signer %s", options.Keystore.RemoteSigner.URL)
+		di.SignerFactory = remoteSigner.SignerFactory()
+	} else {
+		di.SignerFactory = func(id identity.Identity) identity.Signer {
+			return identity.NewSigner(di.Keystore, id)
+		}
 	}
 
 	if err := di.bootstrapSSOMystnodes(); err != nil {
 		return err
 	}
 
+	gasConfigs := make(map[int64]registry.GasStrategyConfig, len(options.Transactor.GasPrice))
+	for chainID, gas := range options.Transactor.GasPrice {
+		cfg, err := registry.ParseGasStrategyConfig(gas.Speed, gas.MaxTipCap)
+		if err != nil {
+			return fmt.Errorf("invalid gas price of chain %d: %w", chainID, err)
+		}
+		gasConfigs[chainID] = cfg
+	}
+
 	di.Transactor = registry.NewTransactor(
 		di.HTTPClient,
 		options.Transactor.TransactorEndpointAddress,
@@ -815,7 +1161,10 @@ func (di *Dependencies) bootstrapNetworkComponents(options node.Options) (err er
 		di.EventBus,
 		di.BCHelper,
 		options.Transactor.TransactorFeesValidTime,
+		registry.NewChainGasStrategy(di.BCHelper, gasConfigs),
 	)
+	di.TransactorAudit = registry.NewTransactorAuditLog(di.Storage)
+	di.Transactor.SetAuditLog(di.TransactorAudit)
 	di.Affiliator = registry.NewAffiliator(di.HTTPClient, options.Affiliator.AffiliatorEndpointAddress)
 
 	registryCfg := registry.IdentityRegistryConfig{
@@ -823,9 +1172,51 @@ func (di *Dependencies) bootstrapNetworkComponents(options node.Options) (err er
 		TransactorPollTimeout:  options.Payments.RegistryTransactorPollTimeout,
 	}
 
-	if di.IdentityRegistry, err = registry.NewIdentityRegistryContract(di.EtherClientL2, di.AddressProvider, registryStorage, di.EventBus, di.HermesCaller, di.Transactor, di.IdentitySelector, registryCfg); err != nil {
+	registryCaller, err := di.RPCFailover.Chain(options.Chains.Chain2.ChainID)
+	if err != nil {
+		return err
+	}
+	if di.IdentityRegistry, err = registry.NewIdentityRegistryContract(registryCaller, di.AddressProvider, registryStorage, di.EventBus, di.HermesCaller, di.Transactor, di.IdentitySelector, registryCfg); err != nil {
+		return err
+	}
+	if ttl := options.Payments.RegistrationStatusCacheTTL; ttl > 0 {
+		di.IdentityRegistry = registry.NewCachedRegistry(di.IdentityRegistry, ttl)
+	}
+
+	registryMigrations, err := registry.ParseMigrations(config.GetStringSlice(config.FlagRegistryMigrations))
+	if err != nil {
+		return err
+	}
+
+	di.BeneficiaryAddressStorage = beneficiary.NewAddressStorage(di.Storage)
+	di.AddressBook = beneficiary.NewAddressBook(di.Storage, di.BCHelper, beneficiary.AddressBookRules{
+		WarnContracts:   config.GetBool(config.FlagAddressBookWarnContracts),
+		ConfirmFirstUse: config.GetBool(config.FlagAddressBookConfirmFirstUse),
+		ConfirmationTTL: config.GetDuration(config.FlagAddressBookConfirmationTTL),
+	})
+	di.ChainChecker = registry.NewChainChecker(di.AddressProvider, di.RPCFailover, di.EventBus)
+	di.IdentityOverrides = overrides.NewStorage(di.Storage)
+	di.RegistryWatcher = registry.NewRegistryWatcher(
+		[]int64{options.Chains.Chain1.ChainID, options.Chains.Chain2.ChainID},
+		di.AddressProvider,
+		registryStorage,
+		di.IdentityRegistry,
+		di.Transactor,
+		di.BeneficiaryAddressStorage,
+		di.EventBus,
+		registryMigrations,
+		options.Transactor.TryFreeRegistration,
+		options.Payments.RegistrationRecheckInterval,
+	)
+	di.RegistryWatcher.SetChainChecker(di.ChainChecker)
+	di.RegistryWatcher.SetOverrides(di.IdentityOverrides)
+	if err := di.RegistryWatcher.Subscribe(di.EventBus); err != nil {
 		return err
 	}
+	if interval := options.Payments.RegistrationRecheckInterval; interval > 0 {
+		di.RegistryWatcher.SetHeartbeat(di.heartbeat("registry-watcher", 3*interval))
+	}
+	di.RegistryWatcher.Start()
 
 	allow := []string{
 		network.DiscoveryAddress,
@@ -841,7 +1232,23 @@ func (di *Dependencies) bootstrapNetworkComponents(options node.Options) (err er
 		return err
 	}
 
-	di.WireguardClientFactory = endpoint.NewWGClientFactory()
+	if config.GetBool(config.FlagPcapEnable) {
+		di.PacketCapturer = capture.NewCapturer(capture.Config{
+			Dir:         filepath.Join(options.Directories.Data, "pcap"),
+			MaxDuration: config.GetDuration(config.FlagPcapMaxDuration),
+			MaxSize:     int64(config.GetInt(config.FlagPcapMaxSize)) << 20,
+		})
+	}
+	di.WireguardClientFactory = endpoint.NewWGClientFactory(di.PacketCapturer)
+
+	if config.GetBool(config.FlagTequilapiRecordingEnable) {
+		di.TequilapiRecorder = recorder.NewRecorder(recorder.Config{
+			Dir:         filepath.Join(options.Directories.Data, "tequilapi-recordings"),
+			MaxDuration: config.GetDuration(config.FlagTequilapiRecordingMaxDuration),
+			MaxSize:     int64(config.GetInt(config.FlagTequilapiRecordingMaxSize)) << 20,
+			Version:     metadata.VersionAsString(),
+		})
+	}
 
 	return di.IdentityRegistry.Subscribe(di.EventBus)
 }
@@ -861,10 +1268,35 @@ func (di *Dependencies) bootstrapIdentityComponents(options node.Options) error
 	}
 
 	di.Keystore = identity.NewKeystoreFilesystem(options.Directories.Keystore, ks)
+	di.Keystore.SetMemoryLock(options.Keystore.LockMemory)
 	if di.ResidentCountry == nil {
 		return errMissingDependency("di.residentCountry")
 	}
-	di.IdentityManager = identity.NewIdentityManager(di.Keystore, di.EventBus, di.ResidentCountry)
+	identityManager := identity.NewIdentityManager(di.Keystore, di.EventBus, di.ResidentCountry)
+	di.IdentityManager = identityManager
+
+	unlockPolicy := unlock.Policy{Mode: unlock.Mode(options.Keystore.UnlockPolicy), IdleTimeout: options.Keystore.UnlockTTL}
+	if err := unlockPolicy.Validate(); err != nil {
+		return err
+	}
+	credentials := options.Keystore.UnlockCredentials
+	if credentials.File == "" {
+		credentials.File = filepath.Join(options.Directories.Data, "unlock-credentials")
+	}
+	if credentials.KeyFile == "" {
+		credentials.KeyFile = filepath.Join(options.Directories.Data, "unlock-credentials.key")
+	}
+	usage := newIdentityUsage()
+	if err := usage.subscribe(di.EventBus); err != nil {
+		return err
+	}
+	di.IdentityUnlocker = unlock.NewManager(
+		unlockPolicy,
+		identityManager,
+		usage.inUse,
+		unlock.NewCredentialsFile(credentials.File, credentials.KeyFile),
+		config.Current,
+	)
 
 	di.IdentitySelector = identity_selector.NewHandler(
 		di.IdentityManager,
@@ -876,13 +1308,106 @@ func (di *Dependencies) bootstrapIdentityComponents(options node.Options) error
 		di.EventBus,
 		di.SignerFactory)
 
+	di.Transactor.SetTxSigner(di.Keystore)
+
 	di.FreeRegistrar = registry.NewFreeRegistrar(di.IdentitySelector, di.Transactor, di.IdentityRegistry, options.Transactor.TryFreeRegistration)
+	di.FreeRegistrar.SetChainChecker(di.ChainChecker)
 	if err := di.FreeRegistrar.Subscribe(di.EventBus); err != nil {
 		return err
 	}
 	return nil
 }
 
+func (di *Dependencies) bootstrapWebhook(options node.OptionsWebhook) error {
+	if len(options.URLs) == 0 {
+		return nil
+	}
+
+	if err := di.AllowURLAccess(options.URLs...); err != nil {
+		return err
+	}
+
+	di.WebhookEmitter = webhook.NewEmitter(di.HTTPClient, webhook.Config{
+		URLs:       options.URLs,
+		Secret:     options.Secret,
+		Events:     options.Events,
+		MaxRetries: uint64(options.MaxRetries),
+	})
+	return di.WebhookEmitter.Subscribe(di.EventBus)
+}
+
+func (di *Dependencies) bootstrapMQTT(options node.OptionsMQTT) error {
+	if options.Broker == "" {
+		return nil
+	}
+
+	bridge := mqtt.NewBridge(mqtt.Config{
+		Broker:      options.Broker,
+		TopicPrefix: options.TopicPrefix,
+		ClientID:    options.ClientID,
+		Username:    options.Username,
+		Password:    options.Password,
+		Events:      options.Events,
+		KeepAlive:   time.Minute,
+		RetryDelay:  30 * time.Second,
+	})
+	if err := bridge.Subscribe(di.EventBus); err != nil {
+		return err
+	}
+
+	bridge.Start()
+	di.MQTTBridge = bridge
+	return nil
+}
+
+func (di *Dependencies) bootstrapAnalytics(options node.OptionsAnalytics) (err error) {
+	if options.URL != "" {
+		if err := di.AllowURLAccess(options.URL); err != nil {
+			return err
+		}
+	}
+
+	di.Analytics, err = analytics.NewPipeline(di.HTTPClient, analytics.Config{
+		Settings: analytics.Settings{
+			Enabled:    options.Enabled,
+			Categories: options.Categories,
+		},
+		URL:       options.URL,
+		BatchSize: options.BatchSize,
+		Interval:  options.Interval,
+	})
+	if err != nil {
+		return err
+	}
+
+	di.Analytics.Start()
+	return nil
+}
+
+func (di *Dependencies) bootstrapTracing() (err error) {
+	endpoint := config.GetString(config.FlagTracingOTLPEndpoint)
+	if endpoint == "" {
+		return nil
+	}
+	if err := di.AllowURLAccess(endpoint); err != nil {
+		return err
+	}
+
+	di.TraceExporter, err = otlp.NewExporter(endpoint, "myst", map[string]string{
+		"service.version": metadata.VersionAsString(),
+		"os.type":         runtime.GOOS,
+	}, config.GetDuration(config.FlagTracingOTLPInterval))
+	if err != nil {
+		return err
+	}
+	if err := di.TraceExporter.Subscribe(di.EventBus); err != nil {
+		return err
+	}
+
+	go di.TraceExporter.Start()
+	return nil
+}
+
 func (di *Dependencies) bootstrapQualityComponents(options node.OptionsQuality) (err error) {
 	if err := di.AllowURLAccess(options.Address); err != nil {
 		return err
@@ -909,6 +1434,9 @@ func (di *Dependencies) bootstrapQualityComponents(options node.OptionsQuality)
 	if err != nil {
 		return err
 	}
+	if di.Analytics != nil {
+		transport = quality.NewRecordingTransport(transport, di.Analytics)
+	}
 
 	// Quality metrics
 	qualitySender := quality.NewSender(transport, metadata.VersionAsString())
@@ -941,6 +1469,8 @@ func (di *Dependencies) bootstrapLocationComponents(options node.Options) (err e
 		resolver = location.NewStaticResolver(options.Location.Country, options.Location.City, options.Location.IPType, di.IPResolver)
 	case node.LocationTypeBuiltin:
 		resolver, err = location.NewBuiltInResolver(di.IPResolver)
+	case node.LocationTypeGeoIP:
+		resolver, err = di.bootstrapGeoIPResolver(options)
 	case node.LocationTypeMMDB:
 		resolver, err = location.NewExternalDBResolver(filepath.Join(options.Directories.Script, options.Location.Address), di.IPResolver)
 	case node.LocationTypeOracle:
@@ -955,6 +1485,10 @@ func (di *Dependencies) bootstrapLocationComponents(options node.Options) (err e
 		return err
 	}
 
+	if options.Location.OverrideCountry != "" && options.Location.Type != node.LocationTypeManual {
+		resolver = di.bootstrapLocationOverride(resolver, options)
+	}
+
 	di.LocationResolver = location.NewCache(resolver, di.EventBus, time.Minute*5)
 
 	if !config.GetBool(config.FlagProxyMode) && !config.GetBool(config.FlagDVPNMode) {
@@ -972,6 +1506,53 @@ func (di *Dependencies) bootstrapLocationComponents(options node.Options) (err e
 	return nil
 }
 
+func (di *Dependencies) bootstrapGeoIPResolver(options node.Options) (location.Resolver, error) {
+	if err := di.AllowURLAccess(options.Location.Address); err != nil {
+		return nil, err
+	}
+	if options.Location.DBUpdateURL != "" {
+		if err := di.AllowURLAccess(options.Location.DBUpdateURL); err != nil {
+			return nil, err
+		}
+	}
+
+	updater, err := location.NewUpdatingDBResolver(di.HTTPClient, di.IPResolver, location.DBUpdaterConfig{
+		URL:      options.Location.DBUpdateURL,
+		Signer:   options.Location.DBUpdateSigner,
+		Interval: options.Location.DBUpdateInterval,
+		Path:     filepath.Join(options.Directories.Data, "GeoLite2-Country.mmdb"),
+	})
+	if err != nil {
+		return nil, err
+	}
+	di.LocationDBUpdater = updater
+	go updater.Start()
+
+	return location.NewFallbackResolver([]location.Resolver{
+		updater,
+		location.NewOracleResolver(di.HTTPClient, options.Location.Address),
+	}), nil
+}
+
+func (di *Dependencies) bootstrapLocationOverride(resolver location.Resolver, options node.Options) location.Resolver {
+	var probes []location.Resolver
+	if builtin, err := location.NewBuiltInResolver(di.IPResolver); err != nil {
+		log.Warn().Err(err).Msg("Built-in location resolver is not available for location verification")
+	} else {
+		probes = append(probes, builtin)
+	}
+	if options.Location.Type != node.LocationTypeOracle && options.Location.Type != node.LocationTypeGeoIP {
+		if err := di.AllowURLAccess(options.Location.Address); err != nil {
+			log.Warn().Err(err).Msg("Oracle location resolver is not available for location verification")
+		} else {
+			probes = append(probes, location.NewOracleResolver(di.HTTPClient, options.Location.Address))
+		}
+	}
+
+	log.Info().Msgf("Advertising claimed location: %s %s", options.Location.OverrideCountry, options.Location.OverrideCity)
+	return location.NewOverrideResolver(resolver, options.Location.OverrideCountry, options.Location.OverrideCity, probes)
+}
+
 func (di *Dependencies) bootstrapAuthenticator() error {
 	key, err := auth.NewJWTEncryptionKey(di.Storage)
 	if err != nil {
@@ -979,6 +1560,13 @@ func (di *Dependencies) bootstrapAuthenticator() error {
 	}
 	di.Authenticator = auth.NewAuthenticator()
 	di.JWTAuthenticator = auth.NewJWTAuthenticator(key)
+	di.LoginLimiter = auth.NewLoginLimiter(
+		config.GetString(config.FlagDataDir),
+		config.GetInt(config.FlagTequilapiAuthLockoutThreshold),
+		config.GetDuration(config.FlagTequilapiAuthLockoutDuration),
+		config.GetDuration(config.FlagTequilapiAuthLockoutMax),
+		di.EventBus,
+	)
 
 	return nil
 }
@@ -1017,12 +1605,59 @@ func (di *Dependencies) bootstrapFirewall(options node.OptionsFirewall) error {
 	return nil
 }
 
+// bootstrapRecorder records broker and HTTP Ethereum RPC exchanges into the fixture file at path,
+// RPC endpoints of the network are replaced with local recording proxies.
+func (di *Dependencies) bootstrapRecorder(path string, network *metadata.NetworkDefinition) (err error) {
+	if di.Recorder, err = replay.NewRecorder(path); err != nil {
+		return err
+	}
+	log.Warn().Msgf("Recording broker and Ethereum RPC exchanges into %s", path)
+
+	di.BrokerConnection = nats.NewRecordingConnection(di.BrokerConnection, di.Recorder)
+	network.Chain1.EtherClientRPC = di.recordRPC(network.Chain1.EtherClientRPC)
+	network.Chain2.EtherClientRPC = di.recordRPC(network.Chain2.EtherClientRPC)
+	return nil
+}
+
+func (di *Dependencies) recordRPC(endpoints []string) []string {
+	client := &http.Client{Transport: di.HTTPTransport, Timeout: time.Second * 30}
+
+	res := make([]string, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		if !strings.HasPrefix(endpoint, "http") {
+			log.Warn().Msgf("Only HTTP RPC endpoints can be recorded, not recording %s", endpoint)
+			res = append(res, endpoint)
+			continue
+		}
+
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			log.Warn().Err(err).Msgf("Could not start recording proxy of %s", endpoint)
+			res = append(res, endpoint)
+			continue
+		}
+
+		server := &http.Server{Handler: replay.NewRPCRecorder(endpoint, client, di.Recorder)}
+		go server.Serve(listener)
+		di.recordingServers = append(di.recordingServers, server)
+		res = append(res, "http://"+listener.Addr().String())
+	}
+	return res
+}
+
+func (di *Dependencies) stopRecording() error {
+	for _, server := range di.recordingServers {
+		server.Close()
+	}
+	return di.Recorder.Close()
+}
+
 func (di *Dependencies) bootstrapBeneficiaryProvider(options node.Options) {
 	di.BeneficiaryProvider = beneficiary.NewProvider(
 		options.ChainID,
 		di.AddressProvider,
 		di.Storage,
-		di.BCHelper,
+		di.RPCFailover,
 	)
 }
 
@@ -1031,9 +1666,27 @@ func (di *Dependencies) bootstrapBeneficiarySaver(options node.Options) {
 		options.ChainID,
 		di.AddressProvider,
 		di.Storage,
-		di.BCHelper,
+		di.RPCFailover,
+		di.HermesPromiseSettler,
+	)
+}
+
+func (di *Dependencies) bootstrapBeneficiaryManager(options node.Options) error {
+	di.BeneficiaryManager = beneficiary.NewManager(
+		options.ChainID,
+		di.AddressProvider,
+		di.Storage,
+		di.RPCFailover,
 		di.HermesPromiseSettler,
+		di.BeneficiaryAddressStorage,
+		di.IdentityManager,
+		di.EventBus,
+		beneficiary.ManagerConfig{
+			PollInterval: 15 * time.Second,
+			PollTimeout:  10 * time.Minute,
+		},
 	)
+	return di.BeneficiaryManager.Subscribe(di.EventBus)
 }
 
 func (di *Dependencies) bootstrapHermesMigrator() *migration.HermesMigrator {
@@ -1049,6 +1702,7 @@ func (di *Dependencies) bootstrapHermesMigrator() *migration.HermesMigrator {
 		di.ConsumerBalanceTracker,
 		migration.NewStorage(di.Storage, di.AddressProvider),
 		di.BCHelper,
+		di.EventBus,
 	)
 }
 
@@ -1130,6 +1784,36 @@ func (di *Dependencies) bootstrapResidentCountry() error {
 	return nil
 }
 
+func (di *Dependencies) bootstrapCompliance() error {
+	restrictions, err := compliance.ParseRestrictions(config.GetStringSlice(config.FlagComplianceRestrictedServices))
+	if err != nil {
+		return err
+	}
+
+	di.Compliance = compliance.NewGate(di.Storage, di.EventBus, compliance.Config{
+		Jurisdiction:        config.GetString(config.FlagComplianceJurisdiction),
+		TermsVersion:        config.GetString(config.FlagComplianceTermsVersion),
+		CurrentTermsVersion: terms.TermsVersion,
+		Restrictions:        restrictions,
+	})
+
+	// Terms agreed before acceptances were recorded are kept in the user config.
+	if version := config.Current.GetString(contract.TermsVersion); version != "" {
+		for role, key := range map[string]string{
+			compliance.RoleProvider: contract.TermsProviderAgreed,
+			compliance.RoleConsumer: contract.TermsConsumerAgreed,
+		} {
+			if !config.Current.GetBool(key) {
+				continue
+			}
+			if err := di.Compliance.Import(role, version); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 func errMissingDependency(dep string) error {
 	return errors.New("Missing dependency: " + dep)
 }
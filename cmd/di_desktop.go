@@ -18,6 +18,7 @@
 package cmd
 
 import (
+	"math/big"
 	"time"
 
 	"github.com/mysteriumnetwork/node/core/policy/requested"
@@ -26,15 +27,23 @@ import (
 	"github.com/rs/zerolog/log"
 
 	"github.com/mysteriumnetwork/node/config"
+	consumer_session "github.com/mysteriumnetwork/node/consumer/session"
 	"github.com/mysteriumnetwork/node/core/connection"
+	"github.com/mysteriumnetwork/node/core/maintenance"
 	"github.com/mysteriumnetwork/node/core/node"
 	"github.com/mysteriumnetwork/node/core/policy/localcopy"
 	"github.com/mysteriumnetwork/node/core/service"
+	"github.com/mysteriumnetwork/node/core/service/capacity"
+	"github.com/mysteriumnetwork/node/core/service/replay"
+	"github.com/mysteriumnetwork/node/core/service/reservation"
 	"github.com/mysteriumnetwork/node/core/service/servicestate"
+	service_template "github.com/mysteriumnetwork/node/core/service/template"
 	"github.com/mysteriumnetwork/node/dns"
+	"github.com/mysteriumnetwork/node/identity"
 	"github.com/mysteriumnetwork/node/mmn"
 	"github.com/mysteriumnetwork/node/nat"
 	"github.com/mysteriumnetwork/node/p2p"
+	"github.com/mysteriumnetwork/node/services"
 	"github.com/mysteriumnetwork/node/services/datatransfer"
 	"github.com/mysteriumnetwork/node/services/dvpn"
 	service_noop "github.com/mysteriumnetwork/node/services/noop"
@@ -48,6 +57,10 @@ import (
 	"github.com/mysteriumnetwork/node/services/wireguard/resources"
 	wireguard_service "github.com/mysteriumnetwork/node/services/wireguard/service"
 	"github.com/mysteriumnetwork/node/session/pingpong"
+	"github.com/mysteriumnetwork/node/session/sla"
+	"github.com/mysteriumnetwork/node/session/stats"
+	"github.com/mysteriumnetwork/payments/crypto"
+	"github.com/mysteriumnetwork/payments/units"
 )
 
 // bootstrapServices loads all the components required for running services
@@ -106,6 +119,7 @@ func (di *Dependencies) bootstrapServiceWireguard(nodeOptions node.Options, reso
 				resourcesAllocator,
 				wgClientFactory,
 				di.dnsProxy,
+				di.SessionStats,
 			)
 			return svc, nil
 		},
@@ -130,6 +144,7 @@ func (di *Dependencies) bootstrapServiceScraping(nodeOptions node.Options, resou
 				resourcesAllocator,
 				wgClientFactory,
 				di.dnsProxy,
+				di.SessionStats,
 			)
 			return svc, nil
 		},
@@ -154,6 +169,7 @@ func (di *Dependencies) bootstrapServiceDataTransfer(nodeOptions node.Options, r
 				resourcesAllocator,
 				wgClientFactory,
 				di.dnsProxy,
+				di.SessionStats,
 			)
 			return svc, nil
 		},
@@ -178,6 +194,7 @@ func (di *Dependencies) bootstrapServiceDVPN(nodeOptions node.Options, resources
 				resourcesAllocator,
 				wgClientFactory,
 				di.dnsProxy,
+				di.SessionStats,
 			)
 			return svc, nil
 		},
@@ -207,6 +224,7 @@ func (di *Dependencies) bootstrapServiceOpenvpn(nodeOptions node.Options) {
 			di.PortPool,
 			di.EventBus,
 			di.ServiceFirewall,
+			di.SessionStats,
 		)
 		return manager, nil
 	}
@@ -270,6 +288,30 @@ func (di *Dependencies) bootstrapHermesPromiseSettler(nodeOptions node.Options)
 	if err := settler.Subscribe(di.EventBus); err != nil {
 		return errors.Wrap(err, "could not subscribe promise settler to relevant events")
 	}
+	settler.SetHeartbeat(di.heartbeat("settlement-requests", 5*time.Minute))
+	settler.SetOverrides(di.IdentityOverrides)
+
+	if autoSettle := nodeOptions.Payments.AutoSettle; autoSettle.Enabled {
+		if autoSettle.CheckInterval <= 0 {
+			return errors.New("auto settle check interval must be positive")
+		}
+		scheduler := pingpong.NewSettlementScheduler(
+			di.HermesChannelRepository,
+			di.BCHelper,
+			settler,
+			pingpong.SettlementSchedulerConfig{
+				CheckInterval:   autoSettle.CheckInterval,
+				ThresholdAmount: units.FloatEthToBigIntWei(autoSettle.ThresholdAmount),
+				StakeThreshold:  autoSettle.StakeThreshold,
+				MinInterval:     autoSettle.MinInterval,
+				MaxGasPrice:     units.FloatGweiToBigIntWei(autoSettle.MaxGasPriceGwei),
+			},
+		)
+		scheduler.SetOverrides(di.IdentityOverrides)
+		if err := scheduler.Subscribe(di.EventBus); err != nil {
+			return errors.Wrap(err, "could not subscribe settlement scheduler to relevant events")
+		}
+	}
 
 	di.HermesPromiseSettler = settler
 	return nil
@@ -300,6 +342,18 @@ func (di *Dependencies) bootstrapServiceComponents(nodeOptions node.Options) err
 
 	di.HermesStatusChecker = pingpong.NewHermesStatusChecker(di.BCHelper, di.ObserverAPI, nodeOptions.Payments.HermesStatusRecheckInterval)
 
+	di.SessionStats = stats.NewSampler(di.EventBus, stats.DefaultInterval)
+	if err := di.SessionStats.Subscribe(di.EventBus); err != nil {
+		return err
+	}
+	di.SessionStats.Start()
+
+	slaMonitor := sla.NewMonitor(sla.DefaultWindow)
+	if err := slaMonitor.Subscribe(di.EventBus); err != nil {
+		return err
+	}
+
+	sessionReplay := replay.NewStore(di.Storage, replay.DefaultTTL)
 	newP2PSessionHandler := func(serviceInstance *service.Instance, channel p2p.Channel) *service.SessionManager {
 		paymentEngineFactory := pingpong.InvoiceFactoryCreator(
 			channel, nodeOptions.Payments.ProviderInvoiceFrequency, nodeOptions.Payments.ProviderLimitInvoiceFrequency,
@@ -314,15 +368,48 @@ func (di *Dependencies) bootstrapServiceComponents(nodeOptions node.Options) err
 			di.AddressProvider,
 			di.ObserverAPI,
 		)
-		return service.NewSessionManager(
+		sessionConfig := service.DefaultConfig()
+		sessionConfig.MaxPause = config.GetDuration(config.FlagSessionMaxPause)
+		sessionManager := service.NewSessionManager(
 			serviceInstance,
 			di.ServiceSessions,
 			paymentEngineFactory,
 			di.EventBus,
 			channel,
-			service.DefaultConfig(),
+			sessionConfig,
 			di.PricingHelper,
+			di.SessionStorage.Reconciler(consumer_session.DirectionProvided),
+			slaMonitor,
+			sessionReplay,
 		)
+		if di.LoadShedder != nil {
+			sessionManager.SetLoadShedder(di.LoadShedder)
+		}
+		return sessionManager
+	}
+
+	maintenanceWindows, err := maintenance.ParseWindows(config.GetStringSlice(config.FlagMaintenanceWindows))
+	if err != nil {
+		return err
+	}
+	maintenanceSchedule := maintenance.NewSchedule(maintenanceWindows, config.GetDuration(config.FlagMaintenanceNotice))
+	if err := maintenanceSchedule.Subscribe(di.EventBus); err != nil {
+		return err
+	}
+
+	var reservations service.ReservationBook
+	if config.GetBool(config.FlagReservationsEnabled) {
+		fee, ok := new(big.Int).SetString(config.GetString(config.FlagReservationsFee), 10)
+		if !ok || fee.Sign() < 0 {
+			return errors.Errorf("invalid reservation fee %q", config.GetString(config.FlagReservationsFee))
+		}
+		di.ReservationBook = reservation.NewBook(
+			config.GetInt(config.FlagReservationsMaxConcurrent),
+			fee,
+			nodeOptions.ChainID,
+			deferredPromiseHandler{di: di},
+		)
+		reservations = di.ReservationBook
 	}
 
 	di.ServicesManager = service.NewManager(
@@ -335,6 +422,19 @@ func (di *Dependencies) bootstrapServiceComponents(nodeOptions node.Options) err
 		newP2PSessionHandler,
 		di.SessionConnectivityStatusStorage,
 		di.LocationResolver,
+		service_template.NewSource(
+			di.HTTPClient,
+			service_template.Config{
+				URL:    config.GetString(config.FlagServiceTemplateURL),
+				Signer: config.GetString(config.FlagServiceTemplateSigner),
+			},
+			services.JSONParsersByType,
+		),
+		nodeOptions.SLA.Proposal(),
+		nodeOptions.ProposalMetadata.Proposal(),
+		di.Compliance,
+		maintenanceSchedule,
+		reservations,
 	)
 
 	serviceCleaner := service.Cleaner{SessionStorage: di.ServiceSessions}
@@ -342,9 +442,44 @@ func (di *Dependencies) bootstrapServiceComponents(nodeOptions node.Options) err
 		log.Error().Err(err).Msg("Failed to subscribe service cleaner")
 	}
 
+	capacityConfig := capacity.Config{
+		BandwidthThreshold: config.GetUInt64(config.FlagCapacityBandwidthThreshold),
+		CPUThreshold:       config.GetFloat64(config.FlagCapacityCPUThreshold),
+		Sustain:            config.GetDuration(config.FlagCapacitySustain),
+		Interval:           10 * time.Second,
+	}
+	if capacityConfig.Enabled() {
+		di.CapacityMonitor = capacity.NewMonitor(di.ServicesManager, capacityConfig)
+		if err := di.CapacityMonitor.Subscribe(di.EventBus); err != nil {
+			return err
+		}
+		di.CapacityMonitor.Start()
+	}
+
+	shedConfig := capacity.ShedConfig{
+		MaxSessions:     config.GetInt(config.FlagCapacityMaxSessions),
+		CPUThreshold:    config.GetFloat64(config.FlagCapacityShedCPUThreshold),
+		MemoryThreshold: config.GetFloat64(config.FlagCapacityShedMemoryThreshold),
+		RetryAfter:      config.GetDuration(config.FlagCapacityRetryAfter),
+		Interval:        10 * time.Second,
+	}
+	if shedConfig.Enabled() {
+		di.LoadShedder = capacity.NewShedder(shedConfig)
+		di.LoadShedder.Start()
+	}
+
 	return nil
 }
 
+// deferredPromiseHandler resolves the hermes promise handler on use, as it is bootstrapped after the services.
+type deferredPromiseHandler struct {
+	di *Dependencies
+}
+
+func (h deferredPromiseHandler) RequestPromise(r []byte, em crypto.ExchangeMessage, providerID identity.Identity, sessionID string) <-chan error {
+	return h.di.HermesPromiseHandler.RequestPromise(r, em, providerID, sessionID)
+}
+
 func (di *Dependencies) registerConnections(nodeOptions node.Options) {
 	di.registerOpenvpnConnection(nodeOptions)
 	di.registerNoopConnection()
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/mysteriumnetwork/node/core/connection/external"
 	"github.com/mysteriumnetwork/node/core/discovery"
 	"github.com/mysteriumnetwork/node/core/discovery/apidiscovery"
 	"github.com/mysteriumnetwork/node/core/discovery/brokerdiscovery"
@@ -34,8 +35,16 @@ import (
 func (di *Dependencies) bootstrapDiscoveryComponents(options node.OptionsDiscovery) error {
 	di.FilterPresetStorage = proposal.NewFilterPresetStorage(di.Storage)
 	proposalRepository := discovery.NewRepository()
+	// Imported endpoints are local, so they are looked up before asking the network.
+	di.ExternalEndpoints = external.NewRepository(di.Storage)
+	proposalRepository.Add(di.ExternalEndpoints)
 	proposalRegistry := discovery.NewRegistry()
 	discoveryWorker := discovery.NewWorker()
+	// Proposals are considered stale once they miss a few announcements.
+	freshness := discovery.NewFreshnessTracker(3 * options.PingInterval)
+	if err := freshness.Subscribe(di.EventBus); err != nil {
+		return errors.Wrap(err, "failed to subscribe proposal freshness tracker")
+	}
 
 	for _, discoveryType := range options.Types {
 		switch discoveryType {
@@ -55,17 +64,27 @@ func (di *Dependencies) bootstrapDiscoveryComponents(options node.OptionsDiscove
 			proposalRepository.Add(brokerRepository)
 
 		case node.DiscoveryTypeDHT:
+			var exchange *dhtdiscovery.DigestExchange
+			if options.DHT.Experimental {
+				exchange = &dhtdiscovery.DigestExchange{
+					FetchInterval: options.FetchInterval,
+					MaxDigests:    dhtdiscovery.DefaultMaxDigests,
+					Storage:       di.Storage,
+				}
+			}
 			dhtNode, err := dhtdiscovery.NewNode(
 				fmt.Sprintf("/ip4/%s/%s/%d", options.DHT.Address, options.DHT.Protocol, options.DHT.Port),
 				options.DHT.BootstrapPeers,
+				exchange,
 			)
 			if err != nil {
 				return errors.Wrap(err, "failed to configure DHT node")
 			}
 			discoveryWorker.AddWorker(dhtNode)
 
-			proposalRegistry.AddRegistry(dhtdiscovery.NewRegistry())
-			proposalRepository.Add(dhtdiscovery.NewRepository())
+			// Digests have to outlive a missed ping so that proposals do not flap.
+			proposalRegistry.AddRegistry(dhtdiscovery.NewRegistry(dhtNode, 3*options.PingInterval))
+			proposalRepository.Add(dhtdiscovery.NewRepository(dhtNode))
 
 		default:
 			return errors.Errorf("unknown discovery adapter: %s", discoveryType)
@@ -77,9 +96,13 @@ func (di *Dependencies) bootstrapDiscoveryComponents(options node.OptionsDiscove
 		return errors.Wrap(err, "failed to start discovery")
 	}
 
-	di.ProposalRepository = discovery.NewPricedServiceProposalRepository(proposalRepository, di.PricingHelper, di.FilterPresetStorage)
+	di.ProposalRepository = discovery.NewPricedServiceProposalRepository(proposalRepository, di.PricingHelper, di.FilterPresetStorage, freshness)
+	announceConfig := discovery.DefaultAnnounceConfig(options.PingInterval)
+	announceConfig.Jitter = options.PingJitter
+	announceConfig.MaxBackoff = options.PingMaxBackoff
+	announceConfig.UnconfirmedTimeout = options.UnconfirmedTimeout
 	di.DiscoveryFactory = func() service.Discovery {
-		return discovery.NewService(di.IdentityRegistry, proposalRegistry, options.PingInterval, di.SignerFactory, di.EventBus)
+		return discovery.NewService(di.IdentityRegistry, proposalRegistry, announceConfig, di.SignerFactory, di.EventBus)
 	}
 	return nil
 }
@@ -0,0 +1,92 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
+	"github.com/mysteriumnetwork/node/core/service/servicestate"
+	"github.com/mysteriumnetwork/node/eventbus"
+	"github.com/mysteriumnetwork/node/identity"
+)
+
+// identityUsage tracks identities with active consumer sessions or running services,
+// so that they are not relocked while in use.
+type identityUsage struct {
+	mu       sync.Mutex
+	sessions map[string]string // session ID -> consumer identity
+	services map[string]string // service ID -> provider identity
+}
+
+func newIdentityUsage() *identityUsage {
+	return &identityUsage{
+		sessions: make(map[string]string),
+		services: make(map[string]string),
+	}
+}
+
+func (u *identityUsage) subscribe(bus eventbus.Subscriber) error {
+	if err := bus.Subscribe(connectionstate.AppTopicConnectionSession, u.handleConnectionSession); err != nil {
+		return err
+	}
+	return bus.Subscribe(servicestate.AppTopicServiceStatus, u.handleServiceStatus)
+}
+
+func (u *identityUsage) handleConnectionSession(e connectionstate.AppEventConnectionSession) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	sessionID := string(e.SessionInfo.SessionID)
+	switch e.Status {
+	case connectionstate.SessionCreatedStatus:
+		u.sessions[sessionID] = e.SessionInfo.ConsumerID.Address
+	case connectionstate.SessionEndedStatus:
+		delete(u.sessions, sessionID)
+	}
+}
+
+func (u *identityUsage) handleServiceStatus(e servicestate.AppEventServiceStatus) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if e.Status == string(servicestate.NotRunning) {
+		delete(u.services, e.ID)
+		return
+	}
+	u.services[e.ID] = e.ProviderID
+}
+
+// inUse reports whether the identity has an active session or a running service.
+func (u *identityUsage) inUse(id identity.Identity) bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	for _, address := range u.sessions {
+		if strings.EqualFold(address, id.Address) {
+			return true
+		}
+	}
+	for _, address := range u.services {
+		if strings.EqualFold(address, id.Address) {
+			return true
+		}
+	}
+	return false
+}
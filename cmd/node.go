@@ -22,6 +22,7 @@ import (
 
 	"github.com/mysteriumnetwork/node/core/connection"
 	"github.com/mysteriumnetwork/node/core/node/event"
+	"github.com/mysteriumnetwork/node/session"
 	"github.com/mysteriumnetwork/node/tequilapi"
 )
 
@@ -80,7 +81,7 @@ func (node *Node) Wait() error {
 
 // Kill stops Mysterium node
 func (node *Node) Kill() error {
-	err := node.connectionManager.Disconnect(-1)
+	err := node.connectionManager.DisconnectWithReason(-1, session.DisconnectReasonShutdown)
 	if err != nil {
 		switch err {
 		case connection.ErrNoConnection:
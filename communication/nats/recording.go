@@ -0,0 +1,123 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package nats
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	nats_lib "github.com/nats-io/nats.go"
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/testkit/replay"
+)
+
+// RecordingConnection records request-reply exchanges of the wrapped connection.
+type RecordingConnection struct {
+	Connection
+	rec *replay.Recorder
+}
+
+// NewRecordingConnection wraps the connection recording its request-reply exchanges.
+func NewRecordingConnection(conn Connection, rec *replay.Recorder) *RecordingConnection {
+	return &RecordingConnection{Connection: conn, rec: rec}
+}
+
+// Request sends a new request and records its reply.
+func (rc *RecordingConnection) Request(subject string, payload []byte, timeout time.Duration) (*nats_lib.Msg, error) {
+	msg, err := rc.Connection.Request(subject, payload, timeout)
+	rc.record(subject, payload, msg, err)
+	return msg, err
+}
+
+// RequestWithContext sends a new request and records its reply.
+func (rc *RecordingConnection) RequestWithContext(ctx context.Context, subject string, payload []byte) (*nats_lib.Msg, error) {
+	msg, err := rc.Connection.RequestWithContext(ctx, subject, payload)
+	rc.record(subject, payload, msg, err)
+	return msg, err
+}
+
+func (rc *RecordingConnection) record(subject string, payload []byte, msg *nats_lib.Msg, err error) {
+	ex := replay.Exchange{Kind: replay.KindBroker, Key: subject, Request: payload}
+	if err != nil {
+		ex.Error = err.Error()
+	} else if msg != nil {
+		ex.Response = msg.Data
+	}
+
+	if err := rc.rec.Record(ex); err != nil {
+		log.Warn().Err(err).Msgf("Could not record broker exchange of %s", subject)
+	}
+}
+
+// ReplayConnection replays the recorded request-reply exchanges.
+// Published messages are dropped and subscriptions never receive any messages.
+type ReplayConnection struct {
+	player *replay.Player
+}
+
+// NewReplayConnection returns a connection replaying the exchanges of the player.
+func NewReplayConnection(player *replay.Player) *ReplayConnection {
+	return &ReplayConnection{player: player}
+}
+
+// Open does nothing.
+func (rc *ReplayConnection) Open() error {
+	return nil
+}
+
+// Close does nothing.
+func (rc *ReplayConnection) Close() {}
+
+// Servers returns no servers.
+func (rc *ReplayConnection) Servers() []string {
+	return nil
+}
+
+// Publish drops the message.
+func (rc *ReplayConnection) Publish(subject string, payload []byte) error {
+	return nil
+}
+
+// Subscribe returns a subscription which never receives any messages.
+func (rc *ReplayConnection) Subscribe(subject string, handler nats_lib.MsgHandler) (*nats_lib.Subscription, error) {
+	return &nats_lib.Subscription{}, nil
+}
+
+// Request returns the recorded reply.
+func (rc *ReplayConnection) Request(subject string, payload []byte, timeout time.Duration) (*nats_lib.Msg, error) {
+	return rc.reply(subject, payload)
+}
+
+// RequestWithContext returns the recorded reply.
+func (rc *ReplayConnection) RequestWithContext(ctx context.Context, subject string, payload []byte) (*nats_lib.Msg, error) {
+	return rc.reply(subject, payload)
+}
+
+func (rc *ReplayConnection) reply(subject string, payload []byte) (*nats_lib.Msg, error) {
+	ex, ok := rc.player.Next(replay.KindBroker, subject, payload)
+	if !ok {
+		return nil, fmt.Errorf("no recorded exchange for %q: %w", subject, nats_lib.ErrNoResponders)
+	}
+	if ex.Error != "" {
+		return nil, errors.New(ex.Error)
+	}
+	return &nats_lib.Msg{Subject: subject, Data: ex.Response}, nil
+}
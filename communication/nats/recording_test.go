@@ -0,0 +1,56 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package nats
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mysteriumnetwork/node/testkit/replay"
+)
+
+func TestRecordingConnection_Replay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fixture.jsonl")
+	rec, err := replay.NewRecorder(path)
+	require.NoError(t, err)
+
+	mock := StartConnectionMock()
+	defer mock.Close()
+	mock.MockResponse("discovery", []byte("pong"))
+
+	conn := NewRecordingConnection(mock, rec)
+	msg, err := conn.Request("discovery", []byte("ping"), time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("pong"), msg.Data)
+	require.NoError(t, rec.Close())
+
+	exchanges, err := replay.Load(path)
+	require.NoError(t, err)
+
+	replayed := NewReplayConnection(replay.NewPlayer(exchanges))
+	msg, err = replayed.Request("discovery", []byte("ping"), time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("pong"), msg.Data)
+
+	_, err = replayed.Request("unknown", nil, time.Second)
+	assert.Error(t, err)
+}
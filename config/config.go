@@ -226,6 +226,14 @@ func (cfg *Config) Get(key string) interface{} {
 	return copyValue(defaultValue)
 }
 
+// IsCLI reports whether the value for key is passed via CLI flag and takes precedence over user configuration.
+func (cfg *Config) IsCLI(key string) bool {
+	segments := strings.Split(strings.ToLower(key), ".")
+	cfg.mu.RLock()
+	defer cfg.mu.RUnlock()
+	return SearchMap(cfg.cli, segments) != nil
+}
+
 // returns scalar values as is. deep-copies maps.
 func copyValue(value interface{}) interface{} {
 	switch v := value.(type) {
@@ -187,6 +187,19 @@ func TestUserConfig_Get(t *testing.T) {
 	assert.Equal(t, 1003, cfg.Get("openvpn.port"))
 }
 
+func TestConfig_IsCLI(t *testing.T) {
+	cfg := NewConfig()
+	cfg.SetDefault("chain-id", 137)
+	cfg.SetUser("chain-id", 80001)
+	assert.False(t, cfg.IsCLI("chain-id"))
+
+	cfg.SetCLI("chain-id", 137)
+	assert.True(t, cfg.IsCLI("chain-id"))
+
+	cfg.RemoveCLI("chain-id")
+	assert.False(t, cfg.IsCLI("chain-id"))
+}
+
 func TestUserConfig_GetConfig(t *testing.T) {
 	cfg := NewConfig()
 
@@ -0,0 +1,62 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package config
+
+import (
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	// FlagAddressBookWarnContracts requires confirmation of contract payout addresses.
+	FlagAddressBookWarnContracts = cli.BoolFlag{
+		Name:  "address-book.warn-contracts",
+		Usage: "Require confirmation before paying out to a contract address, contracts may be unable to use the received tokens",
+		Value: true,
+	}
+	// FlagAddressBookConfirmFirstUse requires confirmation of payout addresses never used before.
+	FlagAddressBookConfirmFirstUse = cli.BoolFlag{
+		Name:  "address-book.confirm-first-use",
+		Usage: "Require confirmation before paying out to an address for the first time",
+		Value: false,
+	}
+	// FlagAddressBookConfirmationTTL determines how long the payout address confirmation token is valid.
+	FlagAddressBookConfirmationTTL = cli.DurationFlag{
+		Name:  "address-book.confirmation-ttl",
+		Usage: "How long the payout address confirmation token stays valid",
+		Value: 10 * time.Minute,
+	}
+)
+
+// RegisterFlagsAddressBook function register address book flags to flag list
+func RegisterFlagsAddressBook(flags *[]cli.Flag) {
+	*flags = append(
+		*flags,
+		&FlagAddressBookWarnContracts,
+		&FlagAddressBookConfirmFirstUse,
+		&FlagAddressBookConfirmationTTL,
+	)
+}
+
+// ParseFlagsAddressBook function fills in address book options from CLI context
+func ParseFlagsAddressBook(ctx *cli.Context) {
+	Current.ParseBoolFlag(ctx, FlagAddressBookWarnContracts)
+	Current.ParseBoolFlag(ctx, FlagAddressBookConfirmFirstUse)
+	Current.ParseDurationFlag(ctx, FlagAddressBookConfirmationTTL)
+}
@@ -0,0 +1,77 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package config
+
+import (
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	// FlagAnalyticsEnabled opts in to anonymous usage analytics.
+	FlagAnalyticsEnabled = cli.BoolFlag{
+		Name:  "analytics.enabled",
+		Usage: "Opt in to sending anonymous usage analytics",
+		Value: false,
+	}
+	// FlagAnalyticsCategories analytics categories to send.
+	FlagAnalyticsCategories = cli.StringSliceFlag{
+		Name:  "analytics.categories",
+		Usage: `Analytics categories to send. Options: { "sessions", "connections", "discovery", "nat", "identity", "performance" }`,
+		Value: cli.NewStringSlice("sessions", "connections", "discovery", "nat", "identity", "performance"),
+	}
+	// FlagAnalyticsURL URL to upload analytics batches to.
+	FlagAnalyticsURL = cli.StringFlag{
+		Name:  "analytics.url",
+		Usage: "URL to POST anonymized analytics batches to, uploads are disabled when empty",
+	}
+	// FlagAnalyticsBatchSize maximal number of events uploaded at once.
+	FlagAnalyticsBatchSize = cli.IntFlag{
+		Name:  "analytics.batch-size",
+		Usage: "Maximal number of analytics events uploaded in a single batch",
+		Value: 100,
+	}
+	// FlagAnalyticsInterval interval between analytics uploads.
+	FlagAnalyticsInterval = cli.DurationFlag{
+		Name:  "analytics.interval",
+		Usage: "Interval between analytics uploads",
+		Value: 10 * time.Minute,
+	}
+)
+
+// RegisterFlagsAnalytics function register analytics flags to flag list
+func RegisterFlagsAnalytics(flags *[]cli.Flag) {
+	*flags = append(
+		*flags,
+		&FlagAnalyticsEnabled,
+		&FlagAnalyticsCategories,
+		&FlagAnalyticsURL,
+		&FlagAnalyticsBatchSize,
+		&FlagAnalyticsInterval,
+	)
+}
+
+// ParseFlagsAnalytics function fills in analytics options from CLI context
+func ParseFlagsAnalytics(ctx *cli.Context) {
+	Current.ParseBoolFlag(ctx, FlagAnalyticsEnabled)
+	Current.ParseStringSliceFlag(ctx, FlagAnalyticsCategories)
+	Current.ParseStringFlag(ctx, FlagAnalyticsURL)
+	Current.ParseIntFlag(ctx, FlagAnalyticsBatchSize)
+	Current.ParseDurationFlag(ctx, FlagAnalyticsInterval)
+}
@@ -0,0 +1,94 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package config
+
+import (
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	// FlagCapacityBandwidthThreshold provider throughput above which proposals are paused.
+	FlagCapacityBandwidthThreshold = cli.Uint64Flag{
+		Name:  "capacity.bandwidth-threshold",
+		Usage: "Provider throughput in Mbit/s above which new proposals are paused until the load drops. 0 disables the check",
+		Value: 0,
+	}
+	// FlagCapacityCPUThreshold provider CPU utilization above which proposals are paused.
+	FlagCapacityCPUThreshold = cli.Float64Flag{
+		Name:  "capacity.cpu-threshold",
+		Usage: "Host CPU utilization in percent above which new proposals are paused until the load drops. 0 disables the check",
+		Value: 0,
+	}
+	// FlagCapacitySustain period the load has to stay above or below the threshold before proposals are paused or resumed.
+	FlagCapacitySustain = cli.DurationFlag{
+		Name:  "capacity.sustain",
+		Usage: "Period the load has to stay above a threshold to pause proposals, or below it to resume them",
+		Value: 2 * time.Minute,
+	}
+	// FlagCapacityMaxSessions number of active sessions at which new session requests are rejected.
+	FlagCapacityMaxSessions = cli.IntFlag{
+		Name:  "capacity.max-sessions",
+		Usage: "Number of active sessions at which new session requests are rejected as busy. 0 disables the check",
+		Value: 0,
+	}
+	// FlagCapacityShedCPUThreshold provider CPU utilization above which new session requests are rejected.
+	FlagCapacityShedCPUThreshold = cli.Float64Flag{
+		Name:  "capacity.shed-cpu-threshold",
+		Usage: "Host CPU utilization in percent above which new session requests are rejected as busy. 0 disables the check",
+		Value: 0,
+	}
+	// FlagCapacityShedMemoryThreshold provider memory utilization above which new session requests are rejected.
+	FlagCapacityShedMemoryThreshold = cli.Float64Flag{
+		Name:  "capacity.shed-memory-threshold",
+		Usage: "Host memory utilization in percent above which new session requests are rejected as busy. 0 disables the check",
+		Value: 0,
+	}
+	// FlagCapacityRetryAfter period rejected consumers are asked to wait before retrying the provider.
+	FlagCapacityRetryAfter = cli.DurationFlag{
+		Name:  "capacity.retry-after",
+		Usage: "Period consumers rejected as busy are asked to wait before retrying the provider",
+		Value: 5 * time.Minute,
+	}
+)
+
+// RegisterFlagsCapacity function register provider capacity flags to flag list
+func RegisterFlagsCapacity(flags *[]cli.Flag) {
+	*flags = append(
+		*flags,
+		&FlagCapacityBandwidthThreshold,
+		&FlagCapacityCPUThreshold,
+		&FlagCapacitySustain,
+		&FlagCapacityMaxSessions,
+		&FlagCapacityShedCPUThreshold,
+		&FlagCapacityShedMemoryThreshold,
+		&FlagCapacityRetryAfter,
+	)
+}
+
+// ParseFlagsCapacity function fills in provider capacity options from CLI context
+func ParseFlagsCapacity(ctx *cli.Context) {
+	Current.ParseUInt64Flag(ctx, FlagCapacityBandwidthThreshold)
+	Current.ParseFloat64Flag(ctx, FlagCapacityCPUThreshold)
+	Current.ParseDurationFlag(ctx, FlagCapacitySustain)
+	Current.ParseIntFlag(ctx, FlagCapacityMaxSessions)
+	Current.ParseFloat64Flag(ctx, FlagCapacityShedCPUThreshold)
+	Current.ParseFloat64Flag(ctx, FlagCapacityShedMemoryThreshold)
+	Current.ParseDurationFlag(ctx, FlagCapacityRetryAfter)
+}
@@ -50,6 +50,12 @@ var (
 	FlagChain1KnownHermeses = getKnownHermesesFlag(1)
 	// FlagChain2KnownHermeses represents the known hermeses for chain2.
 	FlagChain2KnownHermeses = getKnownHermesesFlag(2)
+	// FlagRegistryMigrations represents the registry migration table of the chains.
+	FlagRegistryMigrations = cli.StringSliceFlag{
+		Name:  "registry.migrations",
+		Value: cli.NewStringSlice(),
+		Usage: "Registry migrations in chainID:from:to format, identities registered in the from registry are registered again once the chain uses the to registry",
+	}
 )
 
 // RegisterFlagsChains function registers chain flags to flag list.
@@ -67,6 +73,7 @@ func RegisterFlagsChains(flags *[]cli.Flag) {
 		&FlagChain2ChainID,
 		&FlagChain1KnownHermeses,
 		&FlagChain2KnownHermeses,
+		&FlagRegistryMigrations,
 	)
 }
 
@@ -84,6 +91,7 @@ func ParseFlagsChains(ctx *cli.Context) {
 	Current.ParseInt64Flag(ctx, FlagChain2ChainID)
 	Current.ParseStringSliceFlag(ctx, FlagChain1KnownHermeses)
 	Current.ParseStringSliceFlag(ctx, FlagChain2KnownHermeses)
+	Current.ParseStringSliceFlag(ctx, FlagRegistryMigrations)
 }
 
 func getChainFlagData(chainIndex int64) (metadata.ChainDefinition, metadata.ChainDefinitionFlagNames) {
@@ -0,0 +1,60 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package config
+
+import (
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	// FlagComplianceJurisdiction country code the provider operates under.
+	FlagComplianceJurisdiction = cli.StringFlag{
+		Name:  "compliance.jurisdiction",
+		Usage: "Country code of the jurisdiction provider operates under. Detected location country is used when empty",
+		Value: "",
+	}
+	// FlagComplianceTermsVersion minimal terms of use version provider has to accept.
+	FlagComplianceTermsVersion = cli.StringFlag{
+		Name:  "compliance.terms-version",
+		Usage: "Minimal terms of use version provider has to accept before starting services. Empty disables the requirement",
+		Value: "",
+	}
+	// FlagComplianceRestrictedServices service types which must not run in a jurisdiction.
+	FlagComplianceRestrictedServices = cli.StringSliceFlag{
+		Name:  "compliance.restricted-services",
+		Usage: "Service types which must not run in a jurisdiction, given as country:serviceType, e.g. US:scraping",
+		Value: cli.NewStringSlice(),
+	}
+)
+
+// RegisterFlagsCompliance function register compliance flags to flag list
+func RegisterFlagsCompliance(flags *[]cli.Flag) {
+	*flags = append(
+		*flags,
+		&FlagComplianceJurisdiction,
+		&FlagComplianceTermsVersion,
+		&FlagComplianceRestrictedServices,
+	)
+}
+
+// ParseFlagsCompliance function fills in compliance options from CLI context
+func ParseFlagsCompliance(ctx *cli.Context) {
+	Current.ParseStringFlag(ctx, FlagComplianceJurisdiction)
+	Current.ParseStringFlag(ctx, FlagComplianceTermsVersion)
+	Current.ParseStringSliceFlag(ctx, FlagComplianceRestrictedServices)
+}
@@ -0,0 +1,84 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package config
+
+import (
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	// FlagConnectivityCheckEnabled enables periodic check that the internet is reachable through the tunnel.
+	FlagConnectivityCheckEnabled = cli.BoolFlag{
+		Name:  "connectivity-check.enabled",
+		Usage: "Periodically check that the internet is reachable through the tunnel",
+		Value: false,
+	}
+	// FlagConnectivityCheckURLs custom check targets.
+	FlagConnectivityCheckURLs = cli.StringSliceFlag{
+		Name:  "connectivity-check.urls",
+		Usage: "HTTPS URLs requested by the connectivity check, built-in targets are used if not set",
+	}
+	// FlagConnectivityCheckSkip hosts never contacted by the check.
+	FlagConnectivityCheckSkip = cli.StringSliceFlag{
+		Name:  "connectivity-check.skip",
+		Usage: "Hosts never contacted by the connectivity check, including their subdomains",
+	}
+	// FlagConnectivityCheckQuorum number of targets which have to respond.
+	FlagConnectivityCheckQuorum = cli.IntFlag{
+		Name:  "connectivity-check.quorum",
+		Usage: "Number of connectivity check targets which have to respond for the connection to be online",
+		Value: 1,
+	}
+	// FlagConnectivityCheckInterval interval between checks.
+	FlagConnectivityCheckInterval = cli.DurationFlag{
+		Name:  "connectivity-check.interval",
+		Usage: "Interval between connectivity checks",
+		Value: time.Minute,
+	}
+	// FlagConnectivityCheckMaxFailures failed checks in a row after which connection is restored.
+	FlagConnectivityCheckMaxFailures = cli.IntFlag{
+		Name:  "connectivity-check.max-failures",
+		Usage: "Failed connectivity checks in a row after which the connection is restored if auto-reconnect is enabled, 0 only reports them",
+		Value: 3,
+	}
+)
+
+// RegisterFlagsConnectivityCheck function register connectivity check flags to flag list
+func RegisterFlagsConnectivityCheck(flags *[]cli.Flag) {
+	*flags = append(
+		*flags,
+		&FlagConnectivityCheckEnabled,
+		&FlagConnectivityCheckURLs,
+		&FlagConnectivityCheckSkip,
+		&FlagConnectivityCheckQuorum,
+		&FlagConnectivityCheckInterval,
+		&FlagConnectivityCheckMaxFailures,
+	)
+}
+
+// ParseFlagsConnectivityCheck function fills in connectivity check options from CLI context
+func ParseFlagsConnectivityCheck(ctx *cli.Context) {
+	Current.ParseBoolFlag(ctx, FlagConnectivityCheckEnabled)
+	Current.ParseStringSliceFlag(ctx, FlagConnectivityCheckURLs)
+	Current.ParseStringSliceFlag(ctx, FlagConnectivityCheckSkip)
+	Current.ParseIntFlag(ctx, FlagConnectivityCheckQuorum)
+	Current.ParseDurationFlag(ctx, FlagConnectivityCheckInterval)
+	Current.ParseIntFlag(ctx, FlagConnectivityCheckMaxFailures)
+}
@@ -0,0 +1,54 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package config
+
+import (
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	// FlagDiskUsageInterval interval between data directory usage samples.
+	FlagDiskUsageInterval = cli.DurationFlag{
+		Name:  "disk-usage.interval",
+		Usage: "Interval between data directory disk usage samples. 0 disables the monitor",
+		Value: 10 * time.Minute,
+	}
+	// FlagDiskUsageHorizon period within which projected disk exhaustion is reported.
+	FlagDiskUsageHorizon = cli.DurationFlag{
+		Name:  "disk-usage.horizon",
+		Usage: "Warn when data directory growth is projected to exhaust the disk within this period. 0 disables warnings",
+		Value: 7 * 24 * time.Hour,
+	}
+)
+
+// RegisterFlagsDiskUsage function register disk usage monitor flags to flag list
+func RegisterFlagsDiskUsage(flags *[]cli.Flag) {
+	*flags = append(
+		*flags,
+		&FlagDiskUsageInterval,
+		&FlagDiskUsageHorizon,
+	)
+}
+
+// ParseFlagsDiskUsage function fills in disk usage monitor options from CLI context
+func ParseFlagsDiskUsage(ctx *cli.Context) {
+	Current.ParseDurationFlag(ctx, FlagDiskUsageInterval)
+	Current.ParseDurationFlag(ctx, FlagDiskUsageHorizon)
+}
@@ -19,6 +19,7 @@ package config
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/mysteriumnetwork/node/metadata"
 	"github.com/urfave/cli/v2"
@@ -34,7 +35,7 @@ var (
 	// FlagLocationType location detector type.
 	FlagLocationType = cli.StringFlag{
 		Name:  "location.type",
-		Usage: "Location autodetect adapter. Options: { oracle, builtin, mmdb, manual }",
+		Usage: "Location autodetect adapter. Options: { oracle, builtin, geoip, mmdb, manual }",
 		Value: "oracle",
 	}
 	// FlagLocationAddress URL of location detector.
@@ -61,6 +62,32 @@ var (
 		Name:  "location.ip-type",
 		Usage: "Service location IP type (residential, datacenter, etc.)",
 	}
+	// FlagLocationOverrideCountry country advertised instead of the detected one.
+	FlagLocationOverrideCountry = cli.StringFlag{
+		Name:  "location.override-country",
+		Usage: "Advertise given country instead of the detected one. The claim is verified against GeoIP sources",
+	}
+	// FlagLocationOverrideCity city advertised instead of the detected one.
+	FlagLocationOverrideCity = cli.StringFlag{
+		Name:  "location.override-city",
+		Usage: "Advertise given city instead of the detected one. Used together with --location.override-country",
+	}
+	// FlagLocationDBUpdateURL URL of the GeoIP database updates.
+	FlagLocationDBUpdateURL = cli.StringFlag{
+		Name:  "location.db-update-url",
+		Usage: "URL of the signed GeoIP database used by 'geoip' location adapter. Signature is fetched from the same URL with '.sig' suffix",
+	}
+	// FlagLocationDBUpdateSigner address of the GeoIP database signer.
+	FlagLocationDBUpdateSigner = cli.StringFlag{
+		Name:  "location.db-update-signer",
+		Usage: "Address of the identity which signs GeoIP database updates",
+	}
+	// FlagLocationDBUpdateInterval interval of GeoIP database update checks.
+	FlagLocationDBUpdateInterval = cli.DurationFlag{
+		Name:  "location.db-update-interval",
+		Usage: "How often to check for GeoIP database updates",
+		Value: 24 * time.Hour,
+	}
 )
 
 // RegisterFlagsLocation function registers location flags to flag list.
@@ -72,6 +99,11 @@ func RegisterFlagsLocation(flags *[]cli.Flag) {
 		&FlagLocationCountry,
 		&FlagLocationCity,
 		&FlagLocationIPType,
+		&FlagLocationOverrideCountry,
+		&FlagLocationOverrideCity,
+		&FlagLocationDBUpdateURL,
+		&FlagLocationDBUpdateSigner,
+		&FlagLocationDBUpdateInterval,
 	)
 }
 
@@ -83,4 +115,9 @@ func ParseFlagsLocation(ctx *cli.Context) {
 	Current.ParseStringFlag(ctx, FlagLocationCountry)
 	Current.ParseStringFlag(ctx, FlagLocationCity)
 	Current.ParseStringFlag(ctx, FlagLocationIPType)
+	Current.ParseStringFlag(ctx, FlagLocationOverrideCountry)
+	Current.ParseStringFlag(ctx, FlagLocationOverrideCity)
+	Current.ParseStringFlag(ctx, FlagLocationDBUpdateURL)
+	Current.ParseStringFlag(ctx, FlagLocationDBUpdateSigner)
+	Current.ParseDurationFlag(ctx, FlagLocationDBUpdateInterval)
 }
@@ -0,0 +1,68 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package config
+
+import (
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	// FlagLogMaxSize size of the log file in megabytes after which it is rotated.
+	FlagLogMaxSize = cli.Uint64Flag{
+		Name:  "log.max-size",
+		Usage: "Size of the log file in megabytes after which it is rotated. 0 disables size based rotation",
+		Value: 50,
+	}
+	// FlagLogMaxAge age of the log file after which it is rotated.
+	FlagLogMaxAge = cli.DurationFlag{
+		Name:  "log.max-age",
+		Usage: "Age of the log file after which it is rotated, e.g. 24h. 0 disables age based rotation",
+		Value: 0,
+	}
+	// FlagLogMaxBackups number of rotated log files to retain.
+	FlagLogMaxBackups = cli.IntFlag{
+		Name:  "log.max-backups",
+		Usage: "Number of rotated log files to retain. 0 retains all of them",
+		Value: 5,
+	}
+	// FlagLogCompress compresses rotated log files.
+	FlagLogCompress = cli.BoolFlag{
+		Name:  "log.compress",
+		Usage: "Compress rotated log files with gzip",
+		Value: true,
+	}
+)
+
+// RegisterFlagsLogRotation function register log rotation flags to flag list
+func RegisterFlagsLogRotation(flags *[]cli.Flag) {
+	*flags = append(
+		*flags,
+		&FlagLogMaxSize,
+		&FlagLogMaxAge,
+		&FlagLogMaxBackups,
+		&FlagLogCompress,
+	)
+}
+
+// ParseFlagsLogRotation function fills in log rotation options from CLI context
+func ParseFlagsLogRotation(ctx *cli.Context) {
+	Current.ParseUInt64Flag(ctx, FlagLogMaxSize)
+	Current.ParseDurationFlag(ctx, FlagLogMaxAge)
+	Current.ParseIntFlag(ctx, FlagLogMaxBackups)
+	Current.ParseBoolFlag(ctx, FlagLogCompress)
+}
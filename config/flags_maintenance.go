@@ -0,0 +1,54 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package config
+
+import (
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	// FlagMaintenanceWindows upcoming provider maintenance windows.
+	FlagMaintenanceWindows = cli.StringSliceFlag{
+		Name:  "maintenance.windows",
+		Usage: "Upcoming provider maintenance windows in start/duration format e.g. 2024-05-01T02:00:00Z/2h, announced in the proposals and to the connected consumers",
+		Value: cli.NewStringSlice(),
+	}
+	// FlagMaintenanceNotice how long before the maintenance window the connected consumers are notified.
+	FlagMaintenanceNotice = cli.DurationFlag{
+		Name:  "maintenance.notice",
+		Usage: "How long before the maintenance window starts the connected consumers are notified, so they can fail over",
+		Value: 15 * time.Minute,
+	}
+)
+
+// RegisterFlagsMaintenance function register provider maintenance flags to flag list
+func RegisterFlagsMaintenance(flags *[]cli.Flag) {
+	*flags = append(
+		*flags,
+		&FlagMaintenanceWindows,
+		&FlagMaintenanceNotice,
+	)
+}
+
+// ParseFlagsMaintenance function fills in provider maintenance options from CLI context
+func ParseFlagsMaintenance(ctx *cli.Context) {
+	Current.ParseStringSliceFlag(ctx, FlagMaintenanceWindows)
+	Current.ParseDurationFlag(ctx, FlagMaintenanceNotice)
+}
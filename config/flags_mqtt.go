@@ -0,0 +1,81 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package config
+
+import (
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	// FlagMQTTBroker MQTT broker to publish node events to.
+	FlagMQTTBroker = cli.StringFlag{
+		Name:  "mqtt.broker",
+		Usage: `MQTT broker address, e.g. "tcp://localhost:1883" or "ssl://broker:8883". MQTT bridge is disabled when empty`,
+	}
+	// FlagMQTTTopicPrefix prefix of MQTT topics.
+	FlagMQTTTopicPrefix = cli.StringFlag{
+		Name:  "mqtt.topic-prefix",
+		Usage: "Prefix of MQTT topics node events are published to",
+		Value: "mysterium/node",
+	}
+	// FlagMQTTClientID MQTT client identifier.
+	FlagMQTTClientID = cli.StringFlag{
+		Name:  "mqtt.client-id",
+		Usage: "MQTT client identifier",
+		Value: "mysterium-node",
+	}
+	// FlagMQTTUsername MQTT broker username.
+	FlagMQTTUsername = cli.StringFlag{
+		Name:  "mqtt.username",
+		Usage: "MQTT broker username",
+	}
+	// FlagMQTTPassword MQTT broker password.
+	FlagMQTTPassword = cli.StringFlag{
+		Name:  "mqtt.password",
+		Usage: "MQTT broker password",
+	}
+	// FlagMQTTEvents events to publish.
+	FlagMQTTEvents = cli.StringSliceFlag{
+		Name:  "mqtt.events",
+		Usage: `Events to publish. Options: { "sessions", "traffic", "earnings", "services", "connection" }`,
+		Value: cli.NewStringSlice("sessions", "services", "connection"),
+	}
+)
+
+// RegisterFlagsMQTT function register MQTT flags to flag list
+func RegisterFlagsMQTT(flags *[]cli.Flag) {
+	*flags = append(
+		*flags,
+		&FlagMQTTBroker,
+		&FlagMQTTTopicPrefix,
+		&FlagMQTTClientID,
+		&FlagMQTTUsername,
+		&FlagMQTTPassword,
+		&FlagMQTTEvents,
+	)
+}
+
+// ParseFlagsMQTT function fills in MQTT options from CLI context
+func ParseFlagsMQTT(ctx *cli.Context) {
+	Current.ParseStringFlag(ctx, FlagMQTTBroker)
+	Current.ParseStringFlag(ctx, FlagMQTTTopicPrefix)
+	Current.ParseStringFlag(ctx, FlagMQTTClientID)
+	Current.ParseStringFlag(ctx, FlagMQTTUsername)
+	Current.ParseStringFlag(ctx, FlagMQTTPassword)
+	Current.ParseStringSliceFlag(ctx, FlagMQTTEvents)
+}
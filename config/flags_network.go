@@ -71,6 +71,18 @@ var (
 		Usage: "L2 URL or IPC socket to connect to ethereum node, anything what ethereum client accepts - works",
 		Value: cli.NewStringSlice(metadata.DefaultNetwork.Chain2.EtherClientRPC...),
 	}
+	// FlagEtherRPCFailoverTimeout is a time an Ethereum RPC endpoint gets to answer before the next one is used.
+	FlagEtherRPCFailoverTimeout = cli.DurationFlag{
+		Name:  "ether.client.failover.timeout",
+		Usage: "Time an ethereum RPC endpoint gets to answer beneficiary and registration status lookups before the next endpoint in the list is used",
+		Value: 10 * time.Second,
+	}
+	// FlagEtherRPCHealthCheckInterval is an interval of Ethereum RPC endpoint health checks.
+	FlagEtherRPCHealthCheckInterval = cli.DurationFlag{
+		Name:  "ether.client.failover.health-check-interval",
+		Usage: "Interval of ethereum RPC endpoint health checks, calls return to the first healthy endpoint in the list. Set 0 to disable",
+		Value: time.Minute,
+	}
 	// FlagNATHolePunching remove the deprecated flag once all users stop to call it.
 	FlagNATHolePunching = cli.BoolFlag{
 		Name:    "nat-hole-punching",
@@ -114,6 +126,12 @@ var (
 		Usage: "Comma separated list of STUN server to be used to detect NAT type",
 		Value: cli.NewStringSlice("stun.l.google.com:19302", "stun1.l.google.com:19302", "stun2.l.google.com:19302"),
 	}
+	// FlagKeepAliveAutoTune enables tuning of keep-alive intervals to the NAT mapping lifetime.
+	FlagKeepAliveAutoTune = cli.BoolFlag{
+		Name:  "keepalive.auto-tune",
+		Usage: "Measure how long NAT keeps idle UDP mappings and send connection keep-alives only as often as needed",
+		Value: true,
+	}
 	// FlagLocalServiceDiscovery enables SSDP and Bonjour local service discovery.
 	FlagLocalServiceDiscovery = cli.BoolFlag{
 		Name:  "local-service-discovery",
@@ -148,6 +166,26 @@ var (
 		Hidden: true,
 	}
 
+	// FlagProviderBlocklistFailures is a number of consecutive failed connections after which provider is blocked.
+	FlagProviderBlocklistFailures = cli.IntFlag{
+		Name:  "connection.blocklist.failures",
+		Usage: "Number of consecutive failed connections to a provider after which it is temporarily excluded from selection. Set 0 to disable",
+		Value: 3,
+	}
+	// FlagProviderBlocklistPeriod is a period for which failing provider is blocked.
+	FlagProviderBlocklistPeriod = cli.DurationFlag{
+		Name:  "connection.blocklist.period",
+		Usage: "Period for which a failing provider is excluded from selection, doubled on every further failure",
+		Value: 10 * time.Minute,
+	}
+	// FlagTrafficCategories enables local categorization of consumer tunnel traffic.
+	// Packet headers are only visible to the user space WireGuard, so kernel space one is not used while it is enabled.
+	FlagTrafficCategories = cli.BoolFlag{
+		Name:  "traffic.categories",
+		Usage: "Categorize consumer tunnel traffic (web, streaming, gaming, other) by packet headers and report it in session stats. Data never leaves the device. Forces user space WireGuard, which is slower than the kernel space one",
+		Value: false,
+	}
+
 	// FlagDNSListenPort sets the port for listening by DNS service.
 	FlagDNSListenPort = cli.IntFlag{
 		Name:  "dns.listen-port",
@@ -167,17 +205,23 @@ func RegisterFlagsNetwork(flags *[]cli.Flag) {
 		&FlagBrokerAddress,
 		&FlagEtherRPCL1,
 		&FlagEtherRPCL2,
+		&FlagEtherRPCFailoverTimeout,
+		&FlagEtherRPCHealthCheckInterval,
 		&FlagIncomingFirewall,
 		&FlagOutgoingFirewall,
 		&FlagChainID,
 		&FlagKeepConnectedOnFail,
 		&FlagAutoReconnect,
 		&FlagSTUNservers,
+		&FlagKeepAliveAutoTune,
 		&FlagLocalServiceDiscovery,
 		&FlagUDPListenPorts,
 		&FlagTraversal,
 		&FlagPortCheckServers,
 		&FlagStatsReportInterval,
+		&FlagProviderBlocklistFailures,
+		&FlagProviderBlocklistPeriod,
+		&FlagTrafficCategories,
 		&FlagDNSListenPort,
 	)
 }
@@ -189,6 +233,8 @@ func ParseFlagsNetwork(ctx *cli.Context) {
 	Current.ParseStringSliceFlag(ctx, FlagBrokerAddress)
 	Current.ParseStringSliceFlag(ctx, FlagEtherRPCL1)
 	Current.ParseStringSliceFlag(ctx, FlagEtherRPCL2)
+	Current.ParseDurationFlag(ctx, FlagEtherRPCFailoverTimeout)
+	Current.ParseDurationFlag(ctx, FlagEtherRPCHealthCheckInterval)
 	Current.ParseBoolFlag(ctx, FlagPortMapping)
 	Current.ParseBoolFlag(ctx, FlagNATHolePunching)
 	Current.ParseBoolFlag(ctx, FlagIncomingFirewall)
@@ -197,11 +243,15 @@ func ParseFlagsNetwork(ctx *cli.Context) {
 	Current.ParseBoolFlag(ctx, FlagKeepConnectedOnFail)
 	Current.ParseBoolFlag(ctx, FlagAutoReconnect)
 	Current.ParseStringSliceFlag(ctx, FlagSTUNservers)
+	Current.ParseBoolFlag(ctx, FlagKeepAliveAutoTune)
 	Current.ParseBoolFlag(ctx, FlagLocalServiceDiscovery)
 	Current.ParseStringFlag(ctx, FlagUDPListenPorts)
 	Current.ParseStringFlag(ctx, FlagTraversal)
 	Current.ParseStringFlag(ctx, FlagPortCheckServers)
 	Current.ParseDurationFlag(ctx, FlagStatsReportInterval)
+	Current.ParseIntFlag(ctx, FlagProviderBlocklistFailures)
+	Current.ParseDurationFlag(ctx, FlagProviderBlocklistPeriod)
+	Current.ParseBoolFlag(ctx, FlagTrafficCategories)
 	Current.ParseIntFlag(ctx, FlagDNSListenPort)
 }
 
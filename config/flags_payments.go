@@ -97,6 +97,42 @@ var (
 		Value: 20.0,
 		Usage: "The maximum amount of unsettled myst, after that we will always try to settle.",
 	}
+	// FlagPaymentsAutoSettleEnabled enables the background settlement scheduler.
+	FlagPaymentsAutoSettleEnabled = cli.BoolFlag{
+		Name:  "payments.auto-settle.enabled",
+		Value: false,
+		Usage: "Periodically settle channels whose unsettled balance is over the auto settle thresholds",
+	}
+	// FlagPaymentsAutoSettleCheckInterval determines how often the settlement scheduler checks unsettled balances.
+	FlagPaymentsAutoSettleCheckInterval = cli.DurationFlag{
+		Name:  "payments.auto-settle.check-interval",
+		Value: 10 * time.Minute,
+		Usage: "How often unsettled balances are checked by the settlement scheduler",
+	}
+	// FlagPaymentsAutoSettleThresholdAmount determines the unsettled amount of myst at which the scheduler settles.
+	FlagPaymentsAutoSettleThresholdAmount = cli.Float64Flag{
+		Name:  "payments.auto-settle.threshold-amount",
+		Value: 0,
+		Usage: "Amount of unsettled myst in a channel that triggers a settlement, 0 to disable",
+	}
+	// FlagPaymentsAutoSettleStakeThreshold determines the fraction of channel stake at which the scheduler settles.
+	FlagPaymentsAutoSettleStakeThreshold = cli.Float64Flag{
+		Name:  "payments.auto-settle.stake-percentage",
+		Value: 0,
+		Usage: "Unsettled amount as a fraction of the channel stake that triggers a settlement, e.g. 0.1, 0 to disable",
+	}
+	// FlagPaymentsAutoSettleMinInterval determines the minimum time between scheduled settlements of a channel.
+	FlagPaymentsAutoSettleMinInterval = cli.DurationFlag{
+		Name:  "payments.auto-settle.min-interval",
+		Value: 24 * time.Hour,
+		Usage: "Minimum time between scheduled settlements of the same channel",
+	}
+	// FlagPaymentsAutoSettleMaxGasPrice determines the gas price above which scheduled settlements are postponed.
+	FlagPaymentsAutoSettleMaxGasPrice = cli.Float64Flag{
+		Name:  "payments.auto-settle.max-gas-price",
+		Value: 0,
+		Usage: "Gas price in gwei above which scheduled settlements are postponed, 0 to disable",
+	}
 	// FlagPaymentsRegistryTransactorPollInterval The duration we'll wait before calling transactor to check for new status updates.
 	FlagPaymentsRegistryTransactorPollInterval = cli.DurationFlag{
 		Name:   "payments.registry-transactor-poll.interval",
@@ -111,6 +147,18 @@ var (
 		Usage:  "The duration we'll wait before giving up on transactors registration status",
 		Hidden: true,
 	}
+	// FlagPaymentsRegistrationRecheckInterval how often registrations of known provider identities are re-checked on chain.
+	FlagPaymentsRegistrationRecheckInterval = cli.DurationFlag{
+		Name:  "payments.registration-recheck.interval",
+		Value: time.Hour,
+		Usage: "How often registrations of known provider identities are re-checked on chain to re-register lapsed ones. 0 disables the check",
+	}
+	// FlagPaymentsRegistrationStatusCacheTTL how long registration statuses of identities are cached.
+	FlagPaymentsRegistrationStatusCacheTTL = cli.DurationFlag{
+		Name:  "payments.registration-status-cache.ttl",
+		Value: time.Minute,
+		Usage: "How long registration statuses of identities are cached before being checked again. 0 disables the cache",
+	}
 	// FlagPaymentsConsumerDataLeewayMegabytes sets the data amount the consumer agrees to pay before establishing a session
 	FlagPaymentsConsumerDataLeewayMegabytes = cli.Uint64Flag{
 		Name:  metadata.FlagNames.PaymentsDataLeewayMegabytes,
@@ -153,6 +201,34 @@ var (
 		Value: metadata.DefaultNetwork.ObserverAddress,
 	}
 
+	// FlagPaymentsRegistrationMaxFee sets the maximum registration fee accepted when identities are registered automatically.
+	FlagPaymentsRegistrationMaxFee = cli.StringFlag{
+		Name:  "payments.registration.max-fee",
+		Usage: "The maximum registration fee in wei to accept when registering identities automatically. Empty for no limit.",
+		Value: "",
+	}
+
+	// FlagPaymentsRegistrationReferralToken sets the referral token used when identities are registered automatically.
+	FlagPaymentsRegistrationReferralToken = cli.StringFlag{
+		Name:  "payments.registration.referral-token",
+		Usage: "The referral token to use when registering identities automatically, unless the identity has its own. Empty for none.",
+		Value: "",
+	}
+
+	// FlagPaymentsRegistrationDryRun makes automatic registration only report the identities it would register.
+	FlagPaymentsRegistrationDryRun = cli.BoolFlag{
+		Name:  "payments.registration.dry-run",
+		Usage: "Run all checks of automatic registration and report what would be registered, without sending the registration transaction",
+		Value: false,
+	}
+
+	// FlagPaymentsRegistrationAllChains makes automatic registration register identities on every configured chain.
+	FlagPaymentsRegistrationAllChains = cli.BoolFlag{
+		Name:  "payments.registration.all-chains",
+		Usage: "Register identities providing services on both L1 and L2 chains, not only on the active one",
+		Value: false,
+	}
+
 	// FlagPaymentsLimitUnpaidInvoiceValue sets the upper limit of session payment value before forcing an invoice
 	FlagPaymentsLimitUnpaidInvoiceValue = cli.StringFlag{
 		Name:  "payments.provider.max-unpaid-invoice-value-limit",
@@ -193,6 +269,12 @@ func RegisterFlagsPayments(flags *[]cli.Flag) {
 		&FlagPaymentsHermesPromiseSettleThreshold,
 		&FlagPaymentsPromiseSettleMaxFeeThreshold,
 		&FlagPaymentsUnsettledMaxAmount,
+		&FlagPaymentsAutoSettleEnabled,
+		&FlagPaymentsAutoSettleCheckInterval,
+		&FlagPaymentsAutoSettleThresholdAmount,
+		&FlagPaymentsAutoSettleStakeThreshold,
+		&FlagPaymentsAutoSettleMinInterval,
+		&FlagPaymentsAutoSettleMaxGasPrice,
 		&FlagPaymentsHermesPromiseSettleTimeout,
 		&FlagPaymentsHermesPromiseSettleCheckInterval,
 		&FlagPaymentsLongBalancePollInterval,
@@ -200,6 +282,12 @@ func RegisterFlagsPayments(flags *[]cli.Flag) {
 		&FlagPaymentsFastBalancePollTimeout,
 		&FlagPaymentsRegistryTransactorPollTimeout,
 		&FlagPaymentsRegistryTransactorPollInterval,
+		&FlagPaymentsRegistrationRecheckInterval,
+		&FlagPaymentsRegistrationStatusCacheTTL,
+		&FlagPaymentsRegistrationMaxFee,
+		&FlagPaymentsRegistrationReferralToken,
+		&FlagPaymentsRegistrationDryRun,
+		&FlagPaymentsRegistrationAllChains,
 		&FlagPaymentsConsumerDataLeewayMegabytes,
 		&FlagPaymentsHermesStatusRecheckInterval,
 		&FlagOffchainBalanceExpiration,
@@ -223,6 +311,12 @@ func ParseFlagsPayments(ctx *cli.Context) {
 	Current.ParseFloat64Flag(ctx, FlagPaymentsHermesPromiseSettleThreshold)
 	Current.ParseFloat64Flag(ctx, FlagPaymentsPromiseSettleMaxFeeThreshold)
 	Current.ParseFloat64Flag(ctx, FlagPaymentsUnsettledMaxAmount)
+	Current.ParseBoolFlag(ctx, FlagPaymentsAutoSettleEnabled)
+	Current.ParseDurationFlag(ctx, FlagPaymentsAutoSettleCheckInterval)
+	Current.ParseFloat64Flag(ctx, FlagPaymentsAutoSettleThresholdAmount)
+	Current.ParseFloat64Flag(ctx, FlagPaymentsAutoSettleStakeThreshold)
+	Current.ParseDurationFlag(ctx, FlagPaymentsAutoSettleMinInterval)
+	Current.ParseFloat64Flag(ctx, FlagPaymentsAutoSettleMaxGasPrice)
 	Current.ParseDurationFlag(ctx, FlagPaymentsHermesPromiseSettleTimeout)
 	Current.ParseDurationFlag(ctx, FlagPaymentsHermesPromiseSettleCheckInterval)
 	Current.ParseDurationFlag(ctx, FlagPaymentsFastBalancePollInterval)
@@ -231,6 +325,12 @@ func ParseFlagsPayments(ctx *cli.Context) {
 	Current.ParseDurationFlag(ctx, FlagPaymentsLongBalancePollInterval)
 	Current.ParseDurationFlag(ctx, FlagPaymentsRegistryTransactorPollInterval)
 	Current.ParseDurationFlag(ctx, FlagPaymentsRegistryTransactorPollTimeout)
+	Current.ParseDurationFlag(ctx, FlagPaymentsRegistrationRecheckInterval)
+	Current.ParseDurationFlag(ctx, FlagPaymentsRegistrationStatusCacheTTL)
+	Current.ParseStringFlag(ctx, FlagPaymentsRegistrationMaxFee)
+	Current.ParseStringFlag(ctx, FlagPaymentsRegistrationReferralToken)
+	Current.ParseBoolFlag(ctx, FlagPaymentsRegistrationDryRun)
+	Current.ParseBoolFlag(ctx, FlagPaymentsRegistrationAllChains)
 	Current.ParseUInt64Flag(ctx, FlagPaymentsConsumerDataLeewayMegabytes)
 	Current.ParseDurationFlag(ctx, FlagPaymentsHermesStatusRecheckInterval)
 	Current.ParseDurationFlag(ctx, FlagOffchainBalanceExpiration)
@@ -0,0 +1,63 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package config
+
+import (
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	// FlagPcapEnable allows capturing packet headers of consumer sessions via TequilAPI.
+	// Packet headers are only visible to the user space WireGuard, so kernel space one is not used while it is enabled.
+	FlagPcapEnable = cli.BoolFlag{
+		Name:  "pcap.enable",
+		Usage: "Allow admins to capture packet headers (never payloads) of consumer session tunnels via TequilAPI for debugging. Captures are stored locally. Forces user space WireGuard, which is slower than the kernel space one",
+		Value: false,
+	}
+	// FlagPcapMaxDuration limits the duration of a single packet capture.
+	FlagPcapMaxDuration = cli.DurationFlag{
+		Name:  "pcap.max-duration",
+		Usage: "Longest allowed packet capture",
+		Value: 5 * time.Minute,
+	}
+	// FlagPcapMaxSize limits the size of a single packet capture file.
+	FlagPcapMaxSize = cli.IntFlag{
+		Name:  "pcap.max-size",
+		Usage: "Largest allowed packet capture file in megabytes",
+		Value: 16,
+	}
+)
+
+// RegisterFlagsPcap function register packet capture flags to flag list
+func RegisterFlagsPcap(flags *[]cli.Flag) {
+	*flags = append(
+		*flags,
+		&FlagPcapEnable,
+		&FlagPcapMaxDuration,
+		&FlagPcapMaxSize,
+	)
+}
+
+// ParseFlagsPcap function fills in packet capture options from CLI context
+func ParseFlagsPcap(ctx *cli.Context) {
+	Current.ParseBoolFlag(ctx, FlagPcapEnable)
+	Current.ParseDurationFlag(ctx, FlagPcapMaxDuration)
+	Current.ParseIntFlag(ctx, FlagPcapMaxSize)
+}
@@ -0,0 +1,60 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package config
+
+import (
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	// FlagProposalNickname node nickname shown in proposals.
+	FlagProposalNickname = cli.StringFlag{
+		Name:  "proposal.nickname",
+		Usage: "Node nickname shown to consumers in service proposals, up to 32 characters",
+		Value: "",
+	}
+	// FlagProposalDescription node description shown in proposals.
+	FlagProposalDescription = cli.StringFlag{
+		Name:  "proposal.description",
+		Usage: "Node description shown to consumers in service proposals, up to 280 characters",
+		Value: "",
+	}
+	// FlagProposalContactURL node operator contact shown in proposals.
+	FlagProposalContactURL = cli.StringFlag{
+		Name:  "proposal.contact-url",
+		Usage: "Node operator http(s) or mailto contact URL shown to consumers in service proposals",
+		Value: "",
+	}
+)
+
+// RegisterFlagsProposalMetadata function register proposal metadata flags to flag list
+func RegisterFlagsProposalMetadata(flags *[]cli.Flag) {
+	*flags = append(
+		*flags,
+		&FlagProposalNickname,
+		&FlagProposalDescription,
+		&FlagProposalContactURL,
+	)
+}
+
+// ParseFlagsProposalMetadata function fills in proposal metadata options from CLI context
+func ParseFlagsProposalMetadata(ctx *cli.Context) {
+	Current.ParseStringFlag(ctx, FlagProposalNickname)
+	Current.ParseStringFlag(ctx, FlagProposalDescription)
+	Current.ParseStringFlag(ctx, FlagProposalContactURL)
+}
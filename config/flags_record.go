@@ -0,0 +1,44 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package config
+
+import (
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	// FlagRecordPath enables recording of broker and Ethereum RPC exchanges.
+	FlagRecordPath = cli.StringFlag{
+		Name:  "record.path",
+		Usage: "Record broker and HTTP Ethereum RPC exchanges into the given fixture file, to be replayed by regression tests",
+		Value: "",
+	}
+)
+
+// RegisterFlagsRecord function register recording flags to flag list
+func RegisterFlagsRecord(flags *[]cli.Flag) {
+	*flags = append(
+		*flags,
+		&FlagRecordPath,
+	)
+}
+
+// ParseFlagsRecord function fills in recording options from CLI context
+func ParseFlagsRecord(ctx *cli.Context) {
+	Current.ParseStringFlag(ctx, FlagRecordPath)
+}
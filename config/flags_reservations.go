@@ -0,0 +1,60 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package config
+
+import (
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	// FlagReservationsEnabled enables experimental capacity reservations.
+	FlagReservationsEnabled = cli.BoolFlag{
+		Name:  "reservations.enabled",
+		Usage: "Experimental: let consumers reserve provider capacity for a future time slot",
+		Value: false,
+	}
+	// FlagReservationsMaxConcurrent maximum concurrent sessions the provider serves, including the reserved ones.
+	FlagReservationsMaxConcurrent = cli.IntFlag{
+		Name:  "reservations.max-concurrent",
+		Usage: "Maximum number of concurrent sessions including the reserved ones, 0 means no limit",
+		Value: 0,
+	}
+	// FlagReservationsFee prepaid fee of a single reservation.
+	FlagReservationsFee = cli.StringFlag{
+		Name:  "reservations.fee",
+		Usage: "Prepaid fee of a single reservation in wei, paid with a promise before the reservation is confirmed",
+		Value: "0",
+	}
+)
+
+// RegisterFlagsReservations function register capacity reservation flags to flag list
+func RegisterFlagsReservations(flags *[]cli.Flag) {
+	*flags = append(
+		*flags,
+		&FlagReservationsEnabled,
+		&FlagReservationsMaxConcurrent,
+		&FlagReservationsFee,
+	)
+}
+
+// ParseFlagsReservations function fills in capacity reservation options from CLI context
+func ParseFlagsReservations(ctx *cli.Context) {
+	Current.ParseBoolFlag(ctx, FlagReservationsEnabled)
+	Current.ParseIntFlag(ctx, FlagReservationsMaxConcurrent)
+	Current.ParseStringFlag(ctx, FlagReservationsFee)
+}
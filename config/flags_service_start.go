@@ -63,6 +63,17 @@ var (
 		Value: 0.00006,
 	}
 
+	// FlagServiceTemplateURL base URL of the service templates published by the fleet operator.
+	FlagServiceTemplateURL = cli.StringFlag{
+		Name:  "service.template-url",
+		Usage: "Base URL of signed service templates. Template of the service type is fetched from '<url>/<type>.json' on service start",
+	}
+	// FlagServiceTemplateSigner address of the service templates signer.
+	FlagServiceTemplateSigner = cli.StringFlag{
+		Name:  "service.template-signer",
+		Usage: "Address of the identity which signs service templates",
+	}
+
 	// FlagActiveServices a comma-separated list of active services.
 	FlagActiveServices = cli.StringFlag{
 		Name:  "active-services",
@@ -88,6 +99,8 @@ func RegisterFlagsServiceStart(flags *[]cli.Flag) {
 		&FlagPaymentPriceGiB,
 		&FlagPaymentPriceHour,
 		&FlagAccessPolicyList,
+		&FlagServiceTemplateURL,
+		&FlagServiceTemplateSigner,
 		&FlagActiveServices,
 		&FlagStoppedServices,
 	)
@@ -101,6 +114,8 @@ func ParseFlagsServiceStart(ctx *cli.Context) {
 	Current.ParseFloat64Flag(ctx, FlagPaymentPriceGiB)
 	Current.ParseFloat64Flag(ctx, FlagPaymentPriceHour)
 	Current.ParseStringFlag(ctx, FlagAccessPolicyList)
+	Current.ParseStringFlag(ctx, FlagServiceTemplateURL)
+	Current.ParseStringFlag(ctx, FlagServiceTemplateSigner)
 	Current.ParseStringFlag(ctx, FlagActiveServices)
 	Current.ParseStringFlag(ctx, FlagStoppedServices)
 }
@@ -0,0 +1,46 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package config
+
+import (
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	// FlagShutdownTimeout bounds the whole node shutdown.
+	FlagShutdownTimeout = cli.DurationFlag{
+		Name:  "shutdown.timeout",
+		Usage: "Force exit if node shutdown does not finish within the given duration. 0 waits until every component stops",
+		Value: time.Minute,
+	}
+)
+
+// RegisterFlagsShutdown function register shutdown flags to flag list
+func RegisterFlagsShutdown(flags *[]cli.Flag) {
+	*flags = append(
+		*flags,
+		&FlagShutdownTimeout,
+	)
+}
+
+// ParseFlagsShutdown function fills in shutdown options from CLI context
+func ParseFlagsShutdown(ctx *cli.Context) {
+	Current.ParseDurationFlag(ctx, FlagShutdownTimeout)
+}
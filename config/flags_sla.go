@@ -0,0 +1,60 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package config
+
+import (
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	// FlagSLAMinThroughput minimal session throughput provider commits to.
+	FlagSLAMinThroughput = cli.Uint64Flag{
+		Name:  "sla.min-throughput",
+		Usage: "Minimal session throughput in Mbit/s advertised in the proposal SLA. 0 disables the commitment",
+		Value: 0,
+	}
+	// FlagSLAMaxSetupTime maximal session setup time provider commits to.
+	FlagSLAMaxSetupTime = cli.DurationFlag{
+		Name:  "sla.max-setup-time",
+		Usage: "Maximal session setup time advertised in the proposal SLA. 0 disables the commitment",
+		Value: 0,
+	}
+	// FlagSLARefund share of the session price refunded to the consumer on SLA breach.
+	FlagSLARefund = cli.IntFlag{
+		Name:  "sla.refund",
+		Usage: "Share of the session price in percent refunded to the consumer when the SLA is breached",
+		Value: 10,
+	}
+)
+
+// RegisterFlagsSLA function register provider SLA flags to flag list
+func RegisterFlagsSLA(flags *[]cli.Flag) {
+	*flags = append(
+		*flags,
+		&FlagSLAMinThroughput,
+		&FlagSLAMaxSetupTime,
+		&FlagSLARefund,
+	)
+}
+
+// ParseFlagsSLA function fills in provider SLA options from CLI context
+func ParseFlagsSLA(ctx *cli.Context) {
+	Current.ParseUInt64Flag(ctx, FlagSLAMinThroughput)
+	Current.ParseDurationFlag(ctx, FlagSLAMaxSetupTime)
+	Current.ParseIntFlag(ctx, FlagSLARefund)
+}
@@ -0,0 +1,62 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package config
+
+import (
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	// FlagTequilapiRecordingEnable allows recording Tequilapi requests and responses via TequilAPI.
+	FlagTequilapiRecordingEnable = cli.BoolFlag{
+		Name:  "tequilapi.recording.enable",
+		Usage: "Allow admins to record Tequilapi requests and responses with secrets redacted into local HAR files for debugging UI interactions",
+		Value: false,
+	}
+	// FlagTequilapiRecordingMaxDuration limits the duration of a single Tequilapi recording.
+	FlagTequilapiRecordingMaxDuration = cli.DurationFlag{
+		Name:  "tequilapi.recording.max-duration",
+		Usage: "Longest allowed Tequilapi recording",
+		Value: 30 * time.Minute,
+	}
+	// FlagTequilapiRecordingMaxSize limits the size of a single Tequilapi recording file.
+	FlagTequilapiRecordingMaxSize = cli.IntFlag{
+		Name:  "tequilapi.recording.max-size",
+		Usage: "Largest allowed Tequilapi recording in megabytes",
+		Value: 16,
+	}
+)
+
+// RegisterFlagsTequilapiRecording function register Tequilapi recording flags to flag list
+func RegisterFlagsTequilapiRecording(flags *[]cli.Flag) {
+	*flags = append(
+		*flags,
+		&FlagTequilapiRecordingEnable,
+		&FlagTequilapiRecordingMaxDuration,
+		&FlagTequilapiRecordingMaxSize,
+	)
+}
+
+// ParseFlagsTequilapiRecording function fills in Tequilapi recording options from CLI context
+func ParseFlagsTequilapiRecording(ctx *cli.Context) {
+	Current.ParseBoolFlag(ctx, FlagTequilapiRecordingEnable)
+	Current.ParseDurationFlag(ctx, FlagTequilapiRecordingMaxDuration)
+	Current.ParseIntFlag(ctx, FlagTequilapiRecordingMaxSize)
+}
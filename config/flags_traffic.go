@@ -0,0 +1,60 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package config
+
+import (
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	// FlagTrafficRandomizePorts picks consumer p2p ports from the whole unprivileged range.
+	FlagTrafficRandomizePorts = cli.BoolFlag{
+		Name:  "traffic.randomize-ports",
+		Usage: "Pick local p2p ports of each session from the whole unprivileged range instead of --udp.ports",
+		Value: false,
+	}
+	// FlagTrafficKeepAliveJitter randomizes the delay between keepalive pings.
+	FlagTrafficKeepAliveJitter = cli.BoolFlag{
+		Name:  "traffic.keepalive-jitter",
+		Usage: "Randomize the delay between p2p keepalive pings of each session",
+		Value: false,
+	}
+	// FlagTrafficPadControl pads small p2p control messages.
+	FlagTrafficPadControl = cli.BoolFlag{
+		Name:  "traffic.pad-control",
+		Usage: "Pad small p2p control messages to the same size and ask the provider to pad its replies",
+		Value: false,
+	}
+)
+
+// RegisterFlagsTraffic function register traffic correlation mitigation flags to flag list
+func RegisterFlagsTraffic(flags *[]cli.Flag) {
+	*flags = append(
+		*flags,
+		&FlagTrafficRandomizePorts,
+		&FlagTrafficKeepAliveJitter,
+		&FlagTrafficPadControl,
+	)
+}
+
+// ParseFlagsTraffic function fills in traffic correlation mitigation options from CLI context
+func ParseFlagsTraffic(ctx *cli.Context) {
+	Current.ParseBoolFlag(ctx, FlagTrafficRandomizePorts)
+	Current.ParseBoolFlag(ctx, FlagTrafficKeepAliveJitter)
+	Current.ParseBoolFlag(ctx, FlagTrafficPadControl)
+}
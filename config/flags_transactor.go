@@ -18,6 +18,7 @@
 package config
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/mysteriumnetwork/node/metadata"
@@ -50,6 +51,14 @@ var (
 		Usage: "if set to true, the provider will try to register for free. ",
 		Value: false,
 	}
+	// FlagChain1GasSpeed sets the gas price strategy of transactions on chain1.
+	FlagChain1GasSpeed = getGasSpeedFlag(1)
+	// FlagChain2GasSpeed sets the gas price strategy of transactions on chain2.
+	FlagChain2GasSpeed = getGasSpeedFlag(2)
+	// FlagChain1GasTipCap limits the priority fee of transactions on chain1.
+	FlagChain1GasTipCap = getGasTipCapFlag(1)
+	// FlagChain2GasTipCap limits the priority fee of transactions on chain2.
+	FlagChain2GasTipCap = getGasTipCapFlag(2)
 )
 
 // RegisterFlagsTransactor function register network flags to flag list
@@ -60,6 +69,10 @@ func RegisterFlagsTransactor(flags *[]cli.Flag) {
 		&FlagTransactorProviderMaxRegistrationAttempts,
 		&FlagTransactorFeesValidTime,
 		&FlagProviderTryFreeRegistration,
+		&FlagChain1GasSpeed,
+		&FlagChain2GasSpeed,
+		&FlagChain1GasTipCap,
+		&FlagChain2GasTipCap,
 	)
 }
 
@@ -69,4 +82,24 @@ func ParseFlagsTransactor(ctx *cli.Context) {
 	Current.ParseIntFlag(ctx, FlagTransactorProviderMaxRegistrationAttempts)
 	Current.ParseDurationFlag(ctx, FlagTransactorFeesValidTime)
 	Current.ParseBoolFlag(ctx, FlagProviderTryFreeRegistration)
+	Current.ParseStringFlag(ctx, FlagChain1GasSpeed)
+	Current.ParseStringFlag(ctx, FlagChain2GasSpeed)
+	Current.ParseStringFlag(ctx, FlagChain1GasTipCap)
+	Current.ParseStringFlag(ctx, FlagChain2GasTipCap)
+}
+
+func getGasSpeedFlag(chainIndex int64) cli.StringFlag {
+	return cli.StringFlag{
+		Name:  fmt.Sprintf("transactor.chain%d.gas-speed", chainIndex),
+		Usage: fmt.Sprintf("Gas price strategy of transactions on chain %v: economy, normal or fast", chainIndex),
+		Value: "normal",
+	}
+}
+
+func getGasTipCapFlag(chainIndex int64) cli.StringFlag {
+	return cli.StringFlag{
+		Name:  fmt.Sprintf("transactor.chain%d.gas-tip-cap", chainIndex),
+		Usage: fmt.Sprintf("Max EIP-1559 priority fee in wei of transactions on chain %v, unlimited if empty", chainIndex),
+		Value: "",
+	}
 }
@@ -0,0 +1,54 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package config
+
+import (
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	// FlagWatchdogInterval interval between health checks of the long-running components.
+	FlagWatchdogInterval = cli.DurationFlag{
+		Name:  "watchdog.interval",
+		Usage: "Interval between health checks of the long-running components. 0 disables the watchdog",
+		Value: 30 * time.Second,
+	}
+	// FlagWatchdogRestart enables restarting of the stalled components.
+	FlagWatchdogRestart = cli.BoolFlag{
+		Name:  "watchdog.restart",
+		Usage: "Restart stalled components which support it instead of only reporting degraded health",
+		Value: false,
+	}
+)
+
+// RegisterFlagsWatchdog function register watchdog flags to flag list
+func RegisterFlagsWatchdog(flags *[]cli.Flag) {
+	*flags = append(
+		*flags,
+		&FlagWatchdogInterval,
+		&FlagWatchdogRestart,
+	)
+}
+
+// ParseFlagsWatchdog function fills in watchdog options from CLI context
+func ParseFlagsWatchdog(ctx *cli.Context) {
+	Current.ParseDurationFlag(ctx, FlagWatchdogInterval)
+	Current.ParseBoolFlag(ctx, FlagWatchdogRestart)
+}
@@ -0,0 +1,66 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package config
+
+import (
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	// FlagWebhookURL URLs to deliver node events to.
+	FlagWebhookURL = cli.StringSliceFlag{
+		Name:  "webhook.url",
+		Usage: "URL(s) to POST selected node events to, webhooks are disabled when empty",
+	}
+	// FlagWebhookSecret secret used to sign webhook payloads.
+	FlagWebhookSecret = cli.StringFlag{
+		Name:  "webhook.secret",
+		Usage: "Secret for HMAC-SHA256 signature of webhook payloads, sent in X-Mysterium-Signature header",
+	}
+	// FlagWebhookEvents events to deliver.
+	FlagWebhookEvents = cli.StringSliceFlag{
+		Name:  "webhook.events",
+		Usage: `Events to deliver. Options: { "session_started", "session_ended", "settlement_complete", "registration_complete", "terms_reacceptance_required" }`,
+		Value: cli.NewStringSlice("session_started", "session_ended", "settlement_complete", "registration_complete", "terms_reacceptance_required"),
+	}
+	// FlagWebhookMaxRetries number of delivery retries.
+	FlagWebhookMaxRetries = cli.IntFlag{
+		Name:  "webhook.max-retries",
+		Usage: "Number of times a failed webhook delivery is retried with exponential backoff",
+		Value: 5,
+	}
+)
+
+// RegisterFlagsWebhook function register webhook flags to flag list
+func RegisterFlagsWebhook(flags *[]cli.Flag) {
+	*flags = append(
+		*flags,
+		&FlagWebhookURL,
+		&FlagWebhookSecret,
+		&FlagWebhookEvents,
+		&FlagWebhookMaxRetries,
+	)
+}
+
+// ParseFlagsWebhook function fills in webhook options from CLI context
+func ParseFlagsWebhook(ctx *cli.Context) {
+	Current.ParseStringSliceFlag(ctx, FlagWebhookURL)
+	Current.ParseStringFlag(ctx, FlagWebhookSecret)
+	Current.ParseStringSliceFlag(ctx, FlagWebhookEvents)
+	Current.ParseIntFlag(ctx, FlagWebhookMaxRetries)
+}
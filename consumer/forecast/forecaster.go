@@ -0,0 +1,279 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package forecast
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/mysteriumnetwork/node/consumer/session"
+	"github.com/mysteriumnetwork/node/core/discovery/proposal"
+	"github.com/mysteriumnetwork/node/datasize"
+	"github.com/mysteriumnetwork/node/identity"
+)
+
+const (
+	week = 7 * 24 * time.Hour
+	// historyWeeks is the number of past weeks used for forecasting.
+	historyWeeks = 4
+	// savingsThreshold is the minimum savings in percent worth advising on.
+	savingsThreshold = 5
+)
+
+// ErrNoHistory is returned when the consumer has no traffic to forecast from.
+var ErrNoHistory = errors.New("no consumed traffic in the history window")
+
+type sessionLister interface {
+	List(*session.Filter) ([]session.History, error)
+}
+
+type proposalRepository interface {
+	Proposals(filter *proposal.Filter) ([]proposal.PricedServiceProposal, error)
+}
+
+// Forecast is the expected consumption over the next week.
+type Forecast struct {
+	// Weeks is the number of past weeks the forecast is based on.
+	Weeks int
+	// Data is the expected traffic in bytes.
+	Data uint64
+	// Spend is the expected spend in wei.
+	Spend *big.Int
+	// PaidPerGiB is the average price per GiB paid during the history window.
+	PaidPerGiB *big.Int
+	Advice     Advice
+}
+
+// Advice compares the price paid with the prices currently offered.
+type Advice struct {
+	Country     string
+	ServiceType string
+	// PricePerGiB is the 25th percentile price per GiB of matching proposals, nil if there are none.
+	PricePerGiB *big.Int
+	// Savings is the share of spend that could have been saved, in percent.
+	Savings float64
+	// Optimal is true when switching providers would not save a meaningful amount.
+	Optimal bool
+	Message string
+}
+
+// Forecaster forecasts consumer data usage and spend from the sessions history.
+type Forecaster struct {
+	sessions  sessionLister
+	proposals proposalRepository
+	now       func() time.Time
+}
+
+// NewForecaster returns a new Forecaster.
+func NewForecaster(sessions sessionLister, proposals proposalRepository) *Forecaster {
+	return &Forecaster{
+		sessions:  sessions,
+		proposals: proposals,
+		now:       time.Now,
+	}
+}
+
+// Forecast forecasts next week usage of the given consumer. Cheaper providers are looked up
+// in the given country, or in the country the consumer has used the most if it is empty.
+func (f *Forecaster) Forecast(consumerID identity.Identity, country string) (Forecast, error) {
+	now := f.now().UTC()
+	filter := session.NewFilter().
+		SetStartedFrom(now.Add(-historyWeeks * week)).
+		SetStartedTo(now).
+		SetDirection(session.DirectionConsumed).
+		SetConsumerID(consumerID)
+	sessions, err := f.sessions.List(filter)
+	if err != nil {
+		return Forecast{}, fmt.Errorf("could not list sessions: %w", err)
+	}
+
+	usage := newUsage(now, sessions)
+	if usage.totalData == 0 {
+		return Forecast{}, ErrNoHistory
+	}
+
+	fc := Forecast{
+		Weeks:      usage.weeks,
+		Data:       usage.forecastData(),
+		Spend:      usage.forecastSpend(),
+		PaidPerGiB: pricePerGiB(usage.totalSpend, usage.totalData),
+	}
+
+	if country == "" {
+		country = top(usage.countries)
+	}
+	fc.Advice, err = f.advise(fc.PaidPerGiB, country, top(usage.serviceTypes))
+	if err != nil {
+		return Forecast{}, err
+	}
+	return fc, nil
+}
+
+func (f *Forecaster) advise(paidPerGiB *big.Int, country, serviceType string) (Advice, error) {
+	advice := Advice{
+		Country:     country,
+		ServiceType: serviceType,
+		Optimal:     true,
+		Message:     "Your current filter and pricing preferences are cost-optimal",
+	}
+
+	proposals, err := f.proposals.Proposals(&proposal.Filter{
+		LocationCountry:    country,
+		ServiceType:        serviceType,
+		ExcludeUnsupported: true,
+	})
+	if err != nil {
+		return Advice{}, fmt.Errorf("could not list proposals: %w", err)
+	}
+
+	p25 := percentilePricePerGiB(proposals, 25)
+	if p25 == nil {
+		return advice, nil
+	}
+	advice.PricePerGiB = p25
+
+	if paidPerGiB.Sign() == 0 || p25.Cmp(paidPerGiB) >= 0 {
+		return advice, nil
+	}
+	saved := new(big.Int).Sub(paidPerGiB, p25)
+	advice.Savings, _ = new(big.Rat).SetFrac(saved.Mul(saved, big.NewInt(100)), paidPerGiB).Float64()
+	if advice.Savings < savingsThreshold {
+		return advice, nil
+	}
+
+	where := "your country"
+	if country != "" {
+		where = country
+	}
+	advice.Optimal = false
+	advice.Message = fmt.Sprintf("Providers at p25 price in %s would have saved %.0f%%", where, advice.Savings)
+	return advice, nil
+}
+
+// usage aggregates the sessions history into weekly buckets, the most recent week first.
+type usage struct {
+	weeks        int
+	data         [historyWeeks]uint64
+	spend        [historyWeeks]*big.Int
+	totalData    uint64
+	totalSpend   *big.Int
+	countries    map[string]uint64
+	serviceTypes map[string]uint64
+}
+
+func newUsage(now time.Time, sessions []session.History) *usage {
+	u := &usage{
+		totalSpend:   new(big.Int),
+		countries:    make(map[string]uint64),
+		serviceTypes: make(map[string]uint64),
+	}
+	for i := range u.spend {
+		u.spend[i] = new(big.Int)
+	}
+
+	for _, se := range sessions {
+		bucket := int(now.Sub(se.Started) / week)
+		if bucket < 0 || bucket >= historyWeeks {
+			continue
+		}
+		if bucket+1 > u.weeks {
+			u.weeks = bucket + 1
+		}
+
+		data := se.DataSent + se.DataReceived
+		u.data[bucket] += data
+		u.totalData += data
+		if se.Tokens != nil {
+			u.spend[bucket].Add(u.spend[bucket], se.Tokens)
+			u.totalSpend.Add(u.totalSpend, se.Tokens)
+		}
+		u.countries[se.ProviderCountry] += data
+		u.serviceTypes[se.ServiceType] += data
+	}
+	return u
+}
+
+// weight gives recent weeks more influence on the forecast, the oldest week weighs 1.
+func (u *usage) weight(bucket int) int64 {
+	return int64(u.weeks - bucket)
+}
+
+func (u *usage) weightSum() int64 {
+	return int64(u.weeks * (u.weeks + 1) / 2)
+}
+
+func (u *usage) forecastData() uint64 {
+	sum := new(big.Int)
+	for i := 0; i < u.weeks; i++ {
+		sum.Add(sum, new(big.Int).Mul(new(big.Int).SetUint64(u.data[i]), big.NewInt(u.weight(i))))
+	}
+	return sum.Div(sum, big.NewInt(u.weightSum())).Uint64()
+}
+
+func (u *usage) forecastSpend() *big.Int {
+	sum := new(big.Int)
+	for i := 0; i < u.weeks; i++ {
+		sum.Add(sum, new(big.Int).Mul(u.spend[i], big.NewInt(u.weight(i))))
+	}
+	return sum.Div(sum, big.NewInt(u.weightSum()))
+}
+
+// top returns the key with the largest value, ties broken alphabetically.
+func top(m map[string]uint64) string {
+	var (
+		best  string
+		count uint64
+	)
+	for k, v := range m {
+		if v > count || (v == count && k < best) {
+			best, count = k, v
+		}
+	}
+	return best
+}
+
+func pricePerGiB(spend *big.Int, data uint64) *big.Int {
+	price := new(big.Int).Mul(spend, new(big.Int).SetUint64(datasize.GiB.Bytes()))
+	return price.Div(price, new(big.Int).SetUint64(data))
+}
+
+// percentilePricePerGiB returns the nearest rank percentile of proposal prices per GiB,
+// or nil if none of the proposals are priced.
+func percentilePricePerGiB(proposals []proposal.PricedServiceProposal, percentile int) *big.Int {
+	prices := make([]*big.Int, 0, len(proposals))
+	for _, p := range proposals {
+		if p.Price.PricePerGiB != nil {
+			prices = append(prices, p.Price.PricePerGiB)
+		}
+	}
+	if len(prices) == 0 {
+		return nil
+	}
+
+	sort.Slice(prices, func(i, j int) bool {
+		return prices[i].Cmp(prices[j]) < 0
+	})
+	rank := (percentile*len(prices) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return new(big.Int).Set(prices[rank-1])
+}
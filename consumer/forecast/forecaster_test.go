@@ -0,0 +1,183 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package forecast
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/mysteriumnetwork/node/consumer/session"
+	"github.com/mysteriumnetwork/node/core/discovery/proposal"
+	"github.com/mysteriumnetwork/node/datasize"
+	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/market"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	consumerID = identity.FromAddress("0x1")
+	now        = time.Date(2024, 3, 29, 12, 0, 0, 0, time.UTC)
+	gib        = datasize.GiB.Bytes()
+)
+
+type mockSessions struct {
+	sessions []session.History
+	filter   *session.Filter
+}
+
+func (m *mockSessions) List(filter *session.Filter) ([]session.History, error) {
+	m.filter = filter
+	return m.sessions, nil
+}
+
+type mockProposals struct {
+	prices []int64
+	filter *proposal.Filter
+}
+
+func (m *mockProposals) Proposals(filter *proposal.Filter) ([]proposal.PricedServiceProposal, error) {
+	m.filter = filter
+	proposals := make([]proposal.PricedServiceProposal, 0, len(m.prices))
+	for _, p := range m.prices {
+		proposals = append(proposals, proposal.PricedServiceProposal{
+			Price: market.Price{PricePerHour: big.NewInt(0), PricePerGiB: big.NewInt(p)},
+		})
+	}
+	return proposals, nil
+}
+
+func consumed(weeksAgo int, country string, data uint64, tokens int64) session.History {
+	return session.History{
+		Direction:       session.DirectionConsumed,
+		ConsumerID:      consumerID,
+		ServiceType:     "wireguard",
+		ProviderCountry: country,
+		DataReceived:    data,
+		Tokens:          big.NewInt(tokens),
+		Started:         now.Add(-time.Duration(weeksAgo)*week - time.Hour),
+	}
+}
+
+func newTestForecaster(sessions *mockSessions, proposals *mockProposals) *Forecaster {
+	f := NewForecaster(sessions, proposals)
+	f.now = func() time.Time { return now }
+	return f
+}
+
+func TestForecaster_Forecast(t *testing.T) {
+	// given
+	sessions := &mockSessions{sessions: []session.History{
+		consumed(0, "DE", 4*gib, 400),
+		consumed(1, "DE", 3*gib, 300),
+		consumed(2, "DE", 2*gib, 200),
+		consumed(3, "US", gib, 100),
+	}}
+	proposals := &mockProposals{prices: []int64{120, 82, 90, 150, 200, 100, 300, 85}}
+	f := newTestForecaster(sessions, proposals)
+
+	// when
+	fc, err := f.Forecast(consumerID, "")
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, 4, fc.Weeks)
+	assert.Equal(t, 3*gib, fc.Data)
+	assert.Equal(t, big.NewInt(300), fc.Spend)
+	assert.Equal(t, big.NewInt(100), fc.PaidPerGiB)
+
+	assert.Equal(t, session.DirectionConsumed, *sessions.filter.Direction)
+	assert.Equal(t, now.Add(-historyWeeks*week), *sessions.filter.StartedFrom)
+	assert.Equal(t, "DE", proposals.filter.LocationCountry)
+	assert.Equal(t, "wireguard", proposals.filter.ServiceType)
+
+	assert.Equal(t, Advice{
+		Country:     "DE",
+		ServiceType: "wireguard",
+		PricePerGiB: big.NewInt(85),
+		Savings:     15,
+		Optimal:     false,
+		Message:     "Providers at p25 price in DE would have saved 15%",
+	}, fc.Advice)
+}
+
+func TestForecaster_ForecastWeighsRecentWeeks(t *testing.T) {
+	// given
+	sessions := &mockSessions{sessions: []session.History{
+		consumed(0, "DE", 3*gib, 30),
+		consumed(1, "DE", 0, 0),
+	}}
+	f := newTestForecaster(sessions, &mockProposals{})
+
+	// when
+	fc, err := f.Forecast(consumerID, "")
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, 2, fc.Weeks)
+	assert.Equal(t, 2*gib, fc.Data)
+	assert.Equal(t, big.NewInt(20), fc.Spend)
+}
+
+func TestForecaster_ForecastOptimal(t *testing.T) {
+	// given
+	sessions := &mockSessions{sessions: []session.History{
+		consumed(0, "DE", gib, 100),
+	}}
+	proposals := &mockProposals{prices: []int64{97, 110, 150}}
+	f := newTestForecaster(sessions, proposals)
+
+	// when
+	fc, err := f.Forecast(consumerID, "FR")
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, "FR", proposals.filter.LocationCountry)
+	assert.True(t, fc.Advice.Optimal)
+	assert.Equal(t, big.NewInt(97), fc.Advice.PricePerGiB)
+	assert.InDelta(t, 3, fc.Advice.Savings, 0.001)
+	assert.Equal(t, "Your current filter and pricing preferences are cost-optimal", fc.Advice.Message)
+}
+
+func TestForecaster_ForecastWithoutProposals(t *testing.T) {
+	// given
+	sessions := &mockSessions{sessions: []session.History{
+		consumed(0, "DE", gib, 100),
+	}}
+	f := newTestForecaster(sessions, &mockProposals{})
+
+	// when
+	fc, err := f.Forecast(consumerID, "")
+
+	// then
+	require.NoError(t, err)
+	assert.True(t, fc.Advice.Optimal)
+	assert.Nil(t, fc.Advice.PricePerGiB)
+}
+
+func TestForecaster_ForecastWithoutHistory(t *testing.T) {
+	// given
+	f := newTestForecaster(&mockSessions{}, &mockProposals{})
+
+	// when
+	_, err := f.Forecast(consumerID, "")
+
+	// then
+	assert.Equal(t, ErrNoHistory, err)
+}
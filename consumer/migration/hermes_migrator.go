@@ -55,6 +55,7 @@ type HermesMigrator struct {
 	cbt                 *pingpong.ConsumerBalanceTracker
 	st                  *Storage
 	bc                  blockchain
+	progress            *progressTracker
 }
 
 // NewHermesMigrator create new HermesMigrator
@@ -68,6 +69,7 @@ func NewHermesMigrator(
 	cbt *pingpong.ConsumerBalanceTracker,
 	st *Storage,
 	bc blockchain,
+	publisher eventbus.Publisher,
 ) *HermesMigrator {
 	return &HermesMigrator{
 		transactor:          transactor,
@@ -79,6 +81,7 @@ func NewHermesMigrator(
 		cbt:                 cbt,
 		st:                  st,
 		bc:                  bc,
+		progress:            newProgressTracker(publisher),
 	}
 }
 
@@ -87,9 +90,25 @@ type HermesClient interface {
 	GetConsumerData(chainID int64, id string, cacheDuration time.Duration) (pingpong.HermesUserInfo, error)
 }
 
-// Start begins migration from old hermes to new
+// Start begins migration from old hermes to new.
+// It returns ErrMigrationInProgress if the identity is already being migrated.
 func (m *HermesMigrator) Start(id string) error {
 	chainID := config.GetInt64(config.FlagChainID)
+	if !m.progress.begin(chainID, id) {
+		return ErrMigrationInProgress
+	}
+
+	err := m.migrate(chainID, id)
+	m.progress.finish(chainID, id, err)
+	return err
+}
+
+// Progress returns the progress of the latest migration of the identity.
+func (m *HermesMigrator) Progress(id string) Progress {
+	return m.progress.get(id)
+}
+
+func (m *HermesMigrator) migrate(chainID int64, id string) error {
 	if !m.st.isMigrationRequired(chainID, id) {
 		log.Info().Msg("Migration is already done")
 		return nil
@@ -113,6 +132,7 @@ func (m *HermesMigrator) Start(id string) error {
 		return nil
 	}
 	oldHermes := *oldHermesPointer
+	m.progress.hermeses(id, oldHermes.Hex(), activeHermes.Hex())
 
 	// get registry address
 	registryAddress, err := m.addressProvider.GetRegistryAddress(chainID)
@@ -121,6 +141,7 @@ func (m *HermesMigrator) Start(id string) error {
 	}
 
 	// try open channel only if it's not opened yet.
+	m.progress.stage(chainID, id, StageOpeningChannel)
 	if err = m.openChannel(id, err, chainID, activeHermes, registryAddress); err != nil {
 		return fmt.Errorf("open channel error: %w", err)
 	}
@@ -146,6 +167,12 @@ func (m *HermesMigrator) Start(id string) error {
 	}
 	oldBalance := data.Balance
 
+	providerId := identity.FromAddress(id)
+
+	// settle promises which are still outstanding with the old hermes
+	m.progress.stage(chainID, id, StageSettling)
+	m.settleOutstanding(chainID, providerId, oldHermes)
+
 	// get channel implementation contract address
 	channelImpl, err := m.addressProvider.GetActiveChannelImplementation(chainID)
 	if err != nil {
@@ -157,8 +184,6 @@ func (m *HermesMigrator) Start(id string) error {
 		return fmt.Errorf("generate channel address erro: %w", err)
 	}
 
-	providerId := identity.FromAddress(id)
-
 	// check if balance enough for migration
 	if crypto.FloatToBigMyst(oldBalanceMigrationMinimumMyst).Cmp(oldBalance) >= 0 {
 		// If not enough balance we should still check that latest withdrawal succeeded
@@ -170,6 +195,7 @@ func (m *HermesMigrator) Start(id string) error {
 			log.Info().Msg("No promise saved")
 		} else if amountToWithdraw != nil && amountToWithdraw.Cmp(big.NewInt(0)) == 1 {
 			log.Debug().Msgf("Found withdrawal which is not settled, will retry to withdraw")
+			m.progress.stage(chainID, id, StageWithdrawing)
 			return m.hps.RetryWithdrawLatest(chainID, amountToWithdraw, chid, common.HexToAddress(newChannel), providerId)
 		}
 		log.Info().Msgf("Not enough balance for migration or already migrated (id: %s, old balance: %.2f)", id, crypto.BigMystToFloat(oldBalance))
@@ -180,6 +206,7 @@ func (m *HermesMigrator) Start(id string) error {
 	log.Debug().Msgf("Send transaction. Old Hermes: %s, new Hermes %s (channel: %s)", oldHermes, activeHermes, newChannel)
 
 	// send all money from old channel to new
+	m.progress.stage(chainID, id, StageWithdrawing)
 	if err := m.hps.Withdraw(chainID, chainID, providerId, oldHermes, common.HexToAddress(newChannel), nil); err != nil {
 		return err
 	}
@@ -189,6 +216,16 @@ func (m *HermesMigrator) Start(id string) error {
 	return nil
 }
 
+// settleOutstanding settles the earnings which are not settled with the old hermes yet.
+// Failures are not fatal: the promises stay with the old hermes and can be settled later.
+func (m *HermesMigrator) settleOutstanding(chainID int64, id identity.Identity, oldHermes common.Address) {
+	err := m.hps.ForceSettle(chainID, id, oldHermes)
+	if err == nil || errors.Is(err, pingpong.ErrNothingToSettle) {
+		return
+	}
+	log.Warn().Err(err).Msgf("Could not settle outstanding promises with old hermes %s", oldHermes.Hex())
+}
+
 func (m *HermesMigrator) openChannel(id string, err error, chainID int64, activeHermes common.Address, registryAddress common.Address) error {
 	statusResponse, err := m.transactor.ChannelStatus(chainID, id, activeHermes.Hex(), registryAddress.Hex())
 	if err != nil {
@@ -0,0 +1,157 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package migration
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/mysteriumnetwork/node/eventbus"
+)
+
+// AppTopicHermesMigration is the topic the hermes migration progress is published to.
+const AppTopicHermesMigration = "hermes-migration"
+
+// ErrMigrationInProgress is returned when a migration of the identity is already running.
+var ErrMigrationInProgress = errors.New("hermes migration is already in progress")
+
+// Stage is a step of the hermes migration.
+type Stage string
+
+const (
+	// StageNotStarted means no migration was started since the node start.
+	StageNotStarted Stage = "not_started"
+	// StageChecking means the identity channels are being checked against the known hermeses.
+	StageChecking Stage = "checking"
+	// StageOpeningChannel means a channel with the active hermes is being opened.
+	StageOpeningChannel Stage = "opening_channel"
+	// StageSettling means the outstanding promises are being settled with the old hermes.
+	StageSettling Stage = "settling"
+	// StageWithdrawing means the old channel balance is being moved to the new channel.
+	StageWithdrawing Stage = "withdrawing"
+	// StageFinished means the migration finished or was not needed.
+	StageFinished Stage = "finished"
+	// StageFailed means the migration stopped with an error and can be retried.
+	StageFailed Stage = "failed"
+)
+
+// Progress describes the hermes migration progress of an identity.
+type Progress struct {
+	Stage     Stage
+	OldHermes string
+	NewHermes string
+	Error     string
+	Started   time.Time
+	Updated   time.Time
+}
+
+// AppEventHermesMigration is published whenever the migration progress of an identity changes.
+type AppEventHermesMigration struct {
+	ChainID  int64
+	Identity string
+	Progress Progress
+}
+
+type progressTracker struct {
+	lock      sync.Mutex
+	progress  map[string]Progress
+	publisher eventbus.Publisher
+	now       func() time.Time
+}
+
+func newProgressTracker(publisher eventbus.Publisher) *progressTracker {
+	return &progressTracker{
+		progress:  make(map[string]Progress),
+		publisher: publisher,
+		now:       time.Now,
+	}
+}
+
+func (t *progressTracker) get(id string) Progress {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	p, ok := t.progress[id]
+	if !ok {
+		return Progress{Stage: StageNotStarted}
+	}
+	return p
+}
+
+// begin marks the migration of the identity as started, it returns false if it is already running.
+func (t *progressTracker) begin(chainID int64, id string) bool {
+	t.lock.Lock()
+	p := t.progress[id]
+	if p.Stage != "" && p.Stage != StageFinished && p.Stage != StageFailed {
+		t.lock.Unlock()
+		return false
+	}
+	now := t.now()
+	p = Progress{Stage: StageChecking, Started: now, Updated: now}
+	t.progress[id] = p
+	t.lock.Unlock()
+
+	t.publish(chainID, id, p)
+	return true
+}
+
+func (t *progressTracker) hermeses(id, oldHermes, newHermes string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	p := t.progress[id]
+	p.OldHermes, p.NewHermes = oldHermes, newHermes
+	t.progress[id] = p
+}
+
+func (t *progressTracker) stage(chainID int64, id string, stage Stage) {
+	t.update(chainID, id, func(p *Progress) {
+		p.Stage = stage
+	})
+}
+
+func (t *progressTracker) finish(chainID int64, id string, err error) {
+	t.update(chainID, id, func(p *Progress) {
+		if err != nil {
+			p.Stage = StageFailed
+			p.Error = err.Error()
+			return
+		}
+		p.Stage = StageFinished
+	})
+}
+
+func (t *progressTracker) update(chainID int64, id string, fn func(p *Progress)) {
+	t.lock.Lock()
+	p := t.progress[id]
+	fn(&p)
+	p.Updated = t.now()
+	t.progress[id] = p
+	t.lock.Unlock()
+
+	t.publish(chainID, id, p)
+}
+
+func (t *progressTracker) publish(chainID int64, id string, p Progress) {
+	t.publisher.Publish(AppTopicHermesMigration, AppEventHermesMigration{
+		ChainID:  chainID,
+		Identity: id,
+		Progress: p,
+	})
+}
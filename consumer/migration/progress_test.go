@@ -0,0 +1,71 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package migration
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mysteriumnetwork/node/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProgressTracker(t *testing.T) {
+	// given
+	bus := mocks.NewEventBus()
+	tracker := newProgressTracker(bus)
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	tracker.now = func() time.Time { return now }
+
+	// then
+	assert.Equal(t, Progress{Stage: StageNotStarted}, tracker.get("0x1"))
+
+	// when
+	assert.True(t, tracker.begin(1, "0x1"))
+	tracker.hermeses("0x1", "0xold", "0xnew")
+	now = now.Add(time.Minute)
+	tracker.stage(1, "0x1", StageSettling)
+
+	// then
+	assert.False(t, tracker.begin(1, "0x1"))
+	assert.Equal(t, Progress{
+		Stage:     StageSettling,
+		OldHermes: "0xold",
+		NewHermes: "0xnew",
+		Started:   now.Add(-time.Minute),
+		Updated:   now,
+	}, tracker.get("0x1"))
+	assert.Equal(t, AppEventHermesMigration{ChainID: 1, Identity: "0x1", Progress: tracker.get("0x1")}, bus.Pop())
+
+	// when
+	tracker.finish(1, "0x1", errors.New("boom"))
+
+	// then
+	assert.Equal(t, StageFailed, tracker.get("0x1").Stage)
+	assert.Equal(t, "boom", tracker.get("0x1").Error)
+	assert.Len(t, bus.GetEventHistory(), 3)
+
+	// when the migration is retried
+	assert.True(t, tracker.begin(1, "0x1"))
+	tracker.finish(1, "0x1", nil)
+
+	// then
+	assert.Equal(t, StageFinished, tracker.get("0x1").Stage)
+	assert.Empty(t, tracker.get("0x1").Error)
+}
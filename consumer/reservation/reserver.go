@@ -0,0 +1,234 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package reservation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/mysteriumnetwork/payments/crypto"
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/config"
+	"github.com/mysteriumnetwork/node/core/discovery/proposal"
+	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/market"
+	"github.com/mysteriumnetwork/node/p2p"
+	"github.com/mysteriumnetwork/node/pb"
+	"github.com/mysteriumnetwork/node/session/pingpong"
+	"github.com/mysteriumnetwork/node/trace"
+)
+
+const dialTimeout = 30 * time.Second
+
+// ErrProposalNotFound is returned when the provider does not offer the requested service.
+var ErrProposalNotFound = errors.New("proposal not found")
+
+type proposalRepository interface {
+	Proposal(id market.ProposalID) (*proposal.PricedServiceProposal, error)
+}
+
+type consumerTotalsStorage interface {
+	Get(chainID int64, id identity.Identity, hermesID common.Address) (*big.Int, error)
+	Add(chainID int64, id identity.Identity, hermesID common.Address, amount *big.Int) error
+}
+
+type addressProvider interface {
+	GetActiveChannelAddress(chainID int64, id common.Address) (common.Address, error)
+	GetActiveHermes(chainID int64) (common.Address, error)
+}
+
+type hashSigner interface {
+	SignHash(a accounts.Account, hash []byte) ([]byte, error)
+}
+
+// Reservation is the provider capacity reserved by the consumer.
+type Reservation struct {
+	ID          string
+	ConsumerID  identity.Identity
+	ProviderID  identity.Identity
+	ServiceType string
+	Start       time.Time
+	End         time.Time
+	// Fee is the prepaid reservation fee in wei.
+	Fee       *big.Int
+	CreatedAt time.Time
+}
+
+// Reserver reserves provider capacity ahead of time and pays the reservation fees.
+type Reserver struct {
+	proposals proposalRepository
+	dialer    p2p.Dialer
+	totals    consumerTotalsStorage
+	addresses addressProvider
+	signer    hashSigner
+
+	lock         sync.Mutex
+	reservations []Reservation
+}
+
+// NewReserver creates a new reserver.
+func NewReserver(proposals proposalRepository, dialer p2p.Dialer, totals consumerTotalsStorage, addresses addressProvider, signer hashSigner) *Reserver {
+	return &Reserver{
+		proposals: proposals,
+		dialer:    dialer,
+		totals:    totals,
+		addresses: addresses,
+		signer:    signer,
+	}
+}
+
+// Reserve reserves the provider service for the given time slot, paying the reservation fee if provider asks for one.
+// The reservation is converted into a session once the consumer connects to the provider during the slot.
+func (r *Reserver) Reserve(ctx context.Context, consumerID, providerID identity.Identity, serviceType string, start, end time.Time) (Reservation, error) {
+	prop, err := r.proposals.Proposal(market.ProposalID{ServiceType: serviceType, ProviderID: providerID.Address})
+	if err != nil {
+		return Reservation{}, err
+	}
+	if prop == nil {
+		return Reservation{}, ErrProposalNotFound
+	}
+
+	contactDef, err := p2p.ParseContact(prop.Contacts)
+	if err != nil {
+		return Reservation{}, fmt.Errorf("provider does not support p2p communication: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, dialTimeout)
+	defer cancel()
+
+	channel, err := r.dialer.Dial(ctx, consumerID, providerID, serviceType, contactDef, trace.NewTracer("Reservation"))
+	if err != nil {
+		return Reservation{}, fmt.Errorf("p2p dialer failed: %w", err)
+	}
+	defer channel.Close()
+
+	reply, err := channel.Send(ctx, p2p.TopicSessionReserve, p2p.ProtoMessage(&pb.SessionReservation{
+		ConsumerID: consumerID.Address,
+		Start:      start.Unix(),
+		End:        end.Unix(),
+	}))
+	if err != nil {
+		return Reservation{}, fmt.Errorf("could not reserve capacity: %w", err)
+	}
+
+	var sr pb.SessionReservation
+	if err := reply.UnmarshalProto(&sr); err != nil {
+		return Reservation{}, fmt.Errorf("could not unmarshal reservation: %w", err)
+	}
+
+	res := Reservation{
+		ID:          sr.GetReservationID(),
+		ConsumerID:  consumerID,
+		ProviderID:  providerID,
+		ServiceType: serviceType,
+		Start:       time.Unix(sr.GetStart(), 0),
+		End:         time.Unix(sr.GetEnd(), 0),
+		Fee:         new(big.Int),
+		CreatedAt:   time.Now(),
+	}
+	if sr.GetFee() != "" {
+		if err := r.pay(ctx, channel, &res, &sr); err != nil {
+			return Reservation{}, fmt.Errorf("could not pay reservation fee: %w", err)
+		}
+	}
+
+	r.lock.Lock()
+	r.reservations = append(r.reservations, res)
+	r.lock.Unlock()
+
+	return res, nil
+}
+
+func (r *Reserver) pay(ctx context.Context, channel p2p.ChannelSender, res *Reservation, sr *pb.SessionReservation) error {
+	fee, ok := new(big.Int).SetString(sr.GetFee(), 10)
+	if !ok {
+		return fmt.Errorf("invalid fee %q", sr.GetFee())
+	}
+	agreementID, ok := new(big.Int).SetString(sr.GetAgreementID(), 10)
+	if !ok {
+		return fmt.Errorf("invalid agreement id %q", sr.GetAgreementID())
+	}
+
+	chainID := config.GetInt64(config.FlagChainID)
+	if sr.GetChainID() != chainID {
+		return fmt.Errorf("provider asks for a fee on chain %d, consumer is on chain %d", sr.GetChainID(), chainID)
+	}
+
+	hermesID, err := r.addresses.GetActiveHermes(chainID)
+	if err != nil {
+		return fmt.Errorf("could not get active hermes: %w", err)
+	}
+	channelAddress, err := r.addresses.GetActiveChannelAddress(chainID, res.ConsumerID.ToCommonAddress())
+	if err != nil {
+		return fmt.Errorf("could not get channel address: %w", err)
+	}
+
+	promised, err := r.totals.Get(chainID, res.ConsumerID, hermesID)
+	if err != nil {
+		if !errors.Is(err, pingpong.ErrNotFound) {
+			return fmt.Errorf("could not get previous grand total: %w", err)
+		}
+		promised = new(big.Int)
+	}
+
+	invoice := crypto.Invoice{
+		AgreementID:    agreementID,
+		AgreementTotal: fee,
+		TransactorFee:  new(big.Int),
+		Hashlock:       sr.GetHashlock(),
+		Provider:       res.ProviderID.Address,
+		ChainID:        chainID,
+	}
+	em, err := crypto.CreateExchangeMessage(chainID, invoice, new(big.Int).Add(promised, fee), channelAddress.Hex(), hermesID.Hex(), r.signer, res.ConsumerID.ToCommonAddress())
+	if err != nil {
+		return fmt.Errorf("could not create exchange message: %w", err)
+	}
+
+	_, sendErr := channel.Send(ctx, p2p.TopicSessionReservePayment, p2p.ProtoMessage(pingpong.ExchangeMessageProto(*em)))
+	// The promise might have reached the provider even if the reply did not arrive, so it is always accounted for.
+	if err := r.totals.Add(chainID, res.ConsumerID, hermesID, fee); err != nil {
+		log.Error().Err(err).Msgf("Could not increment grand total for reservation %s", res.ID)
+	}
+	if sendErr != nil {
+		return sendErr
+	}
+
+	res.Fee = fee
+	return nil
+}
+
+// List returns the reservations made by the consumer sorted by their start time.
+func (r *Reserver) List() []Reservation {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	result := make([]Reservation, len(r.reservations))
+	copy(result, r.reservations)
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Start.Before(result[j].Start)
+	})
+	return result
+}
@@ -0,0 +1,188 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package reservation
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/config"
+	"github.com/mysteriumnetwork/node/core/discovery/proposal"
+	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/market"
+	"github.com/mysteriumnetwork/node/p2p"
+	"github.com/mysteriumnetwork/node/pb"
+	"github.com/mysteriumnetwork/node/session/pingpong"
+	"github.com/mysteriumnetwork/node/trace"
+)
+
+var (
+	providerID = identity.FromAddress("0x1")
+	hermesID   = common.HexToAddress("0x2")
+)
+
+type mockProposals struct{}
+
+func (mockProposals) Proposal(id market.ProposalID) (*proposal.PricedServiceProposal, error) {
+	if id.ProviderID != providerID.Address {
+		return nil, nil
+	}
+	return &proposal.PricedServiceProposal{
+		ServiceProposal: market.NewProposal(id.ProviderID, id.ServiceType, market.NewProposalOpts{
+			Contacts: []market.Contact{{Type: p2p.ContactTypeV1, Definition: p2p.ContactDefinition{}}},
+		}),
+	}, nil
+}
+
+type mockChannel struct {
+	p2p.Channel
+	reply *pb.SessionReservation
+	sent  map[string]*p2p.Message
+}
+
+func (m *mockChannel) Send(_ context.Context, topic string, msg *p2p.Message) (*p2p.Message, error) {
+	m.sent[topic] = msg
+	return p2p.ProtoMessage(m.reply), nil
+}
+
+func (m *mockChannel) Close() error {
+	return nil
+}
+
+type mockDialer struct {
+	channel *mockChannel
+}
+
+func (m *mockDialer) Dial(_ context.Context, _, _ identity.Identity, _ string, _ p2p.ContactDefinition, _ *trace.Tracer) (p2p.Channel, error) {
+	return m.channel, nil
+}
+
+type mockTotals struct {
+	total *big.Int
+}
+
+func (m *mockTotals) Get(int64, identity.Identity, common.Address) (*big.Int, error) {
+	if m.total == nil {
+		return nil, pingpong.ErrNotFound
+	}
+	return m.total, nil
+}
+
+func (m *mockTotals) Add(_ int64, _ identity.Identity, _ common.Address, amount *big.Int) error {
+	if m.total == nil {
+		m.total = new(big.Int)
+	}
+	m.total = new(big.Int).Add(m.total, amount)
+	return nil
+}
+
+type mockAddresses struct{}
+
+func (mockAddresses) GetActiveChannelAddress(int64, common.Address) (common.Address, error) {
+	return common.HexToAddress("0x441Da57A51e42DAB7Daf55909Af93A9b00eEF23C"), nil
+}
+
+func (mockAddresses) GetActiveHermes(int64) (common.Address, error) {
+	return hermesID, nil
+}
+
+func TestReserver_Reserve_Free(t *testing.T) {
+	start := time.Now().Add(time.Hour).Truncate(time.Second)
+	channel := &mockChannel{
+		reply: &pb.SessionReservation{ReservationID: "r1", Start: start.Unix(), End: start.Add(time.Hour).Unix()},
+		sent:  make(map[string]*p2p.Message),
+	}
+	totals := &mockTotals{}
+	reserver := NewReserver(mockProposals{}, &mockDialer{channel: channel}, totals, mockAddresses{}, identity.NewMockKeystore())
+
+	_, err := reserver.Reserve(context.Background(), identity.FromAddress("0x3"), identity.FromAddress("0x4"), "wireguard", start, start.Add(time.Hour))
+	assert.ErrorIs(t, err, ErrProposalNotFound)
+
+	res, err := reserver.Reserve(context.Background(), identity.FromAddress("0x3"), providerID, "wireguard", start, start.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, "r1", res.ID)
+	assert.Equal(t, start, res.Start)
+	assert.Equal(t, int64(0), res.Fee.Int64())
+	assert.NotContains(t, channel.sent, p2p.TopicSessionReservePayment)
+	assert.Nil(t, totals.total)
+	assert.Len(t, reserver.List(), 1)
+}
+
+func TestReserver_Reserve_PaysFee(t *testing.T) {
+	config.Current.SetUser(config.FlagChainID.Name, int64(1))
+	defer config.Current.RemoveUser(config.FlagChainID.Name)
+
+	ks := identity.NewMockKeystore()
+	acc, err := ks.NewAccount("")
+	assert.NoError(t, err)
+	assert.NoError(t, ks.Unlock(acc, ""))
+	consumerID := identity.FromAddress(acc.Address.Hex())
+
+	start := time.Now().Add(time.Hour)
+	channel := &mockChannel{
+		reply: &pb.SessionReservation{
+			ReservationID: "r1",
+			Start:         start.Unix(),
+			End:           start.Add(time.Hour).Unix(),
+			Fee:           "1000",
+			AgreementID:   "42",
+			Hashlock:      "0x" + strings.Repeat("ab", 32),
+			ChainID:       1,
+		},
+		sent: make(map[string]*p2p.Message),
+	}
+	totals := &mockTotals{total: big.NewInt(500)}
+	reserver := NewReserver(mockProposals{}, &mockDialer{channel: channel}, totals, mockAddresses{}, ks)
+
+	res, err := reserver.Reserve(context.Background(), consumerID, providerID, "wireguard", start, start.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(1000), res.Fee)
+	assert.Equal(t, big.NewInt(1500), totals.total)
+
+	var em pb.ExchangeMessage
+	assert.NoError(t, channel.sent[p2p.TopicSessionReservePayment].UnmarshalProto(&em))
+	assert.Equal(t, "1500", em.GetPromise().GetAmount())
+	assert.Equal(t, "1000", em.GetAgreementTotal())
+	assert.Equal(t, "42", em.GetAgreementID())
+	assert.Equal(t, providerID.Address, em.GetProvider())
+	assert.Equal(t, hermesID.Hex(), em.GetHermesID())
+}
+
+func TestReserver_Reserve_RejectsFeeOnOtherChain(t *testing.T) {
+	config.Current.SetUser(config.FlagChainID.Name, int64(1))
+	defer config.Current.RemoveUser(config.FlagChainID.Name)
+
+	start := time.Now().Add(time.Hour)
+	channel := &mockChannel{
+		reply: &pb.SessionReservation{ReservationID: "r1", Fee: "1000", AgreementID: "42", ChainID: 2},
+		sent:  make(map[string]*p2p.Message),
+	}
+	reserver := NewReserver(mockProposals{}, &mockDialer{channel: channel}, &mockTotals{}, mockAddresses{}, identity.NewMockKeystore())
+
+	_, err := reserver.Reserve(context.Background(), identity.FromAddress("0x3"), providerID, "wireguard", start, start.Add(time.Hour))
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, ErrProposalNotFound))
+	assert.Empty(t, reserver.List())
+}
@@ -0,0 +1,134 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package session
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"strings"
+	"time"
+)
+
+// minCountryConsumers is the least number of consumers a country needs to be reported on its own,
+// so that a single consumer can not be singled out by a rare country.
+const minCountryConsumers = 3
+
+// OtherCountry groups consumers of the countries which have too few consumers to be reported separately.
+const OtherCountry = "other"
+
+// ConsumerStats holds aggregate statistics about consumers of the provided sessions.
+type ConsumerStats struct {
+	Sessions           int
+	Consumers          int
+	NewConsumers       int
+	ReturningConsumers int
+	AverageDuration    time.Duration
+	ConsumersByCountry map[string]int
+}
+
+type consumerHash [sha256.Size]byte
+
+// consumerStatsBuilder aggregates consumer statistics knowing consumers only by salted hashes of their identities.
+// The salt is random and never leaves the builder, so the hashes can not be matched to identities afterwards.
+type consumerStatsBuilder struct {
+	salt []byte
+	from *time.Time
+
+	firstSeen map[consumerHash]time.Time
+	consumers map[consumerHash]struct{}
+	countries map[string]map[consumerHash]struct{}
+	sessions  int
+	duration  time.Duration
+}
+
+func newConsumerStatsBuilder(from *time.Time) (*consumerStatsBuilder, error) {
+	salt := make([]byte, 32)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	return &consumerStatsBuilder{
+		salt:      salt,
+		from:      from,
+		firstSeen: make(map[consumerHash]time.Time),
+		consumers: make(map[consumerHash]struct{}),
+		countries: make(map[string]map[consumerHash]struct{}),
+	}, nil
+}
+
+func (b *consumerStatsBuilder) hash(session History) consumerHash {
+	h := sha256.New()
+	h.Write(b.salt)
+	h.Write([]byte(strings.ToLower(session.ConsumerID.Address)))
+
+	var sum consumerHash
+	copy(sum[:], h.Sum(nil))
+	return sum
+}
+
+// add accounts the session, sessions started before the period only tell when the consumer was first seen.
+func (b *consumerStatsBuilder) add(session History) error {
+	h := b.hash(session)
+	if first, ok := b.firstSeen[h]; !ok || session.Started.Before(first) {
+		b.firstSeen[h] = session.Started
+	}
+	if b.from != nil && session.Started.Before(*b.from) {
+		return nil
+	}
+
+	b.sessions++
+	b.duration += session.GetDuration()
+	b.consumers[h] = struct{}{}
+
+	country := session.ConsumerCountry
+	if country == "" {
+		country = OtherCountry
+	}
+	if b.countries[country] == nil {
+		b.countries[country] = make(map[consumerHash]struct{})
+	}
+	b.countries[country][h] = struct{}{}
+	return nil
+}
+
+func (b *consumerStatsBuilder) stats() ConsumerStats {
+	stats := ConsumerStats{
+		Sessions:           b.sessions,
+		Consumers:          len(b.consumers),
+		ConsumersByCountry: make(map[string]int),
+	}
+	if b.sessions > 0 {
+		stats.AverageDuration = b.duration / time.Duration(b.sessions)
+	}
+
+	for h := range b.consumers {
+		if b.from != nil && b.firstSeen[h].Before(*b.from) {
+			stats.ReturningConsumers++
+		} else {
+			stats.NewConsumers++
+		}
+	}
+
+	for country, consumers := range b.countries {
+		if len(consumers) < minCountryConsumers {
+			country = OtherCountry
+		}
+		stats.ConsumersByCountry[country] += len(consumers)
+	}
+	return stats
+}
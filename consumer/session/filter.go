@@ -18,9 +18,14 @@
 package session
 
 import (
+	"errors"
+	"sort"
 	"time"
 
+	"github.com/asdine/storm/v3"
+	"github.com/asdine/storm/v3/index"
 	"github.com/asdine/storm/v3/q"
+	"github.com/mysteriumnetwork/node/core/storage/boltdb"
 	"github.com/mysteriumnetwork/node/identity"
 )
 
@@ -119,3 +124,69 @@ func (f *Filter) toMatcher() q.Matcher {
 	}
 	return q.And(where...)
 }
+
+// find loads the sessions matching the filter, newest first.
+func (f *Filter) find(node storm.Node) ([]History, error) {
+	result := []History{}
+	err := f.each(node, func(session History) error {
+		result = append(result, session)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Started.After(result[j].Started)
+	})
+	return result, nil
+}
+
+// each calls fn for every session matching the filter, in no particular order.
+// Candidates are looked up by the most selective index the filter allows and
+// only they are matched against the whole filter. Without indexed criteria, or
+// when the index is missing as in archives opened read-only, the bucket is
+// streamed record by record instead of being loaded at once.
+func (f *Filter) each(node storm.Node, fn func(History) error) error {
+	matcher := f.toMatcher()
+
+	var candidates []History
+	var err error
+	indexed := true
+	switch {
+	case f.ProviderID != nil && *f.ProviderID != (identity.Identity{}):
+		err = node.Find("ProviderID", *f.ProviderID, &candidates)
+	case f.StartedFrom != nil:
+		min, max := boltdb.TimeIndexRange(*f.StartedFrom, f.StartedTo)
+		err = node.Range("Started", min, max, &candidates)
+	case f.ServiceType != nil && *f.ServiceType != "":
+		err = node.Find("ServiceType", *f.ServiceType, &candidates)
+	default:
+		indexed = false
+	}
+	if !indexed || errors.Is(err, index.ErrNotFound) {
+		err = node.Select(matcher).Each(new(History), func(record interface{}) error {
+			return fn(*record.(*History))
+		})
+	}
+	if errors.Is(err, storm.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for i := range candidates {
+		ok, err := matcher.Match(&candidates[i])
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		if err := fn(candidates[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
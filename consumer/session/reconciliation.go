@@ -0,0 +1,189 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package session
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	session_node "github.com/mysteriumnetwork/node/session"
+)
+
+// ReconciliationConfig describes how strictly consumer and provider accounting has to match.
+type ReconciliationConfig struct {
+	// Tolerance is a relative difference between consumer and provider accounting which is still considered as matching.
+	Tolerance float64
+	// DataSlack covers the traffic which is in flight while session is being closed.
+	DataSlack uint64
+}
+
+// DefaultReconciliationConfig returns default reconciliation params.
+func DefaultReconciliationConfig() ReconciliationConfig {
+	return ReconciliationConfig{
+		Tolerance: 0.05,
+		DataSlack: 64 * 1024,
+	}
+}
+
+// Reconciler reconciles accounting of the sessions of a single direction only,
+// so that consumer and provider sides never touch each other's sessions.
+type Reconciler struct {
+	repo      *Storage
+	direction string
+}
+
+// Reconciler returns the reconciler of sessions in the given direction.
+func (repo *Storage) Reconciler(direction string) *Reconciler {
+	return &Reconciler{repo: repo, direction: direction}
+}
+
+// Accounting returns the local view of the session accounting.
+func (r *Reconciler) Accounting(sessionID session_node.ID) (session_node.Accounting, bool) {
+	row, ok := r.repo.session(sessionID)
+	if !ok || row.Direction != r.direction {
+		return session_node.Accounting{}, false
+	}
+	return row.accounting(), true
+}
+
+// Reconcile compares the peer view of the session accounting with the local one
+// and stores the result together with the session.
+func (r *Reconciler) Reconcile(sessionID session_node.ID, peer session_node.Accounting) error {
+	return r.repo.reconcile(sessionID, r.direction, peer)
+}
+
+// Reconciliation holds the peer view of the session accounting received at the end of the session.
+type Reconciliation struct {
+	PeerDataSent     uint64
+	PeerDataReceived uint64
+	PeerTokens       *big.Int
+	Discrepancy      bool
+	ReconciledAt     time.Time
+}
+
+func (h History) accounting() session_node.Accounting {
+	return session_node.Accounting{
+		DataSent:     h.DataSent,
+		DataReceived: h.DataReceived,
+		Tokens:       h.Tokens,
+	}
+}
+
+func (repo *Storage) reconcile(sessionID session_node.ID, direction string, peer session_node.Accounting) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	row, active := repo.sessionsActive[sessionID]
+	if !active {
+		if err := repo.storage.GetOneByField(sessionStorageBucketName, "SessionID", sessionID, &row); err != nil {
+			return fmt.Errorf("could not find session %v to reconcile: %w", sessionID, err)
+		}
+	}
+	if row.Direction != direction {
+		return fmt.Errorf("session %v is not %s", sessionID, strings.ToLower(direction))
+	}
+
+	peerTokens := peer.Tokens
+	if peerTokens == nil {
+		peerTokens = new(big.Int)
+	}
+	rec := &Reconciliation{
+		PeerDataSent:     peer.DataSent,
+		PeerDataReceived: peer.DataReceived,
+		PeerTokens:       peerTokens,
+		ReconciledAt:     repo.timeGetter().UTC(),
+	}
+	cfg := repo.reconciliation
+	rec.Discrepancy = !dataMatches(row.DataSent, peer.DataReceived, cfg) ||
+		!dataMatches(row.DataReceived, peer.DataSent, cfg) ||
+		!tokensMatch(row.Tokens, peerTokens, cfg.Tolerance)
+
+	if rec.Discrepancy {
+		log.Warn().Fields(map[string]interface{}{
+			"sessionID":        sessionID,
+			"direction":        row.Direction,
+			"dataSent":         row.DataSent,
+			"dataReceived":     row.DataReceived,
+			"tokens":           row.Tokens,
+			"peerDataSent":     peer.DataSent,
+			"peerDataReceived": peer.DataReceived,
+			"peerTokens":       peerTokens,
+		}).Msg("Session accounting discrepancy")
+	}
+
+	row.Reconciliation = rec
+	if err := repo.storage.Update(sessionStorageBucketName, &row); err != nil {
+		return fmt.Errorf("could not store session %v reconciliation: %w", sessionID, err)
+	}
+	if active {
+		repo.sessionsActive[sessionID] = row
+	}
+
+	return nil
+}
+
+func (repo *Storage) session(sessionID session_node.ID) (History, bool) {
+	repo.mu.RLock()
+	row, ok := repo.sessionsActive[sessionID]
+	repo.mu.RUnlock()
+	if ok {
+		return row, true
+	}
+
+	if err := repo.storage.GetOneByField(sessionStorageBucketName, "SessionID", sessionID, &row); err != nil {
+		return History{}, false
+	}
+	return row, true
+}
+
+func dataMatches(local, peer uint64, cfg ReconciliationConfig) bool {
+	diff := local - peer
+	if peer > local {
+		diff = peer - local
+	}
+	if diff <= cfg.DataSlack {
+		return true
+	}
+
+	max := local
+	if peer > max {
+		max = peer
+	}
+	return float64(diff) <= float64(max)*cfg.Tolerance
+}
+
+func tokensMatch(local, peer *big.Int, tolerance float64) bool {
+	if local == nil {
+		local = new(big.Int)
+	}
+	diff := new(big.Int).Abs(new(big.Int).Sub(local, peer))
+	if diff.Sign() == 0 {
+		return true
+	}
+
+	max := local
+	if peer.Cmp(max) > 0 {
+		max = peer
+	}
+	allowed, _ := new(big.Float).Mul(new(big.Float).SetInt(max), big.NewFloat(tolerance)).Int(nil)
+	return diff.Cmp(allowed) <= 0
+}
@@ -0,0 +1,143 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package session
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
+	session_node "github.com/mysteriumnetwork/node/session"
+	session_event "github.com/mysteriumnetwork/node/session/event"
+	"github.com/mysteriumnetwork/node/session/pingpong/event"
+)
+
+func TestSessionStorage_Reconcile(t *testing.T) {
+	tests := []struct {
+		name            string
+		peer            session_node.Accounting
+		wantDiscrepancy bool
+	}{
+		{
+			name:            "matching accounting",
+			peer:            session_node.Accounting{DataSent: 10_100_000, DataReceived: 5_000_000, Tokens: big.NewInt(1000)},
+			wantDiscrepancy: false,
+		},
+		{
+			name:            "data difference beyond tolerance",
+			peer:            session_node.Accounting{DataSent: 20_000_000, DataReceived: 5_000_000, Tokens: big.NewInt(1000)},
+			wantDiscrepancy: true,
+		},
+		{
+			name:            "tokens difference beyond tolerance",
+			peer:            session_node.Accounting{DataSent: 10_000_000, DataReceived: 5_000_000, Tokens: big.NewInt(2000)},
+			wantDiscrepancy: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage, storageCleanup := newStorage()
+			defer storageCleanup()
+
+			storage.consumeConnectionSessionEvent(connectionstate.AppEventConnectionSession{
+				Status:      connectionstate.SessionCreatedStatus,
+				SessionInfo: connectionSessionMock,
+			})
+			storage.consumeConnectionStatisticsEvent(connectionstate.AppEventConnectionStatistics{
+				Stats:       connectionstate.Statistics{BytesSent: 5_000_000, BytesReceived: 10_000_000},
+				SessionInfo: connectionSessionMock,
+			})
+			storage.consumeConnectionSpendingEvent(event.AppEventInvoicePaid{
+				SessionID: string(connectionSessionMock.SessionID),
+				Invoice:   connectionInvoiceMock,
+			})
+
+			reconciler := storage.Reconciler(DirectionConsumed)
+			own, ok := reconciler.Accounting(connectionSessionMock.SessionID)
+			assert.True(t, ok)
+			assert.Equal(t, uint64(5_000_000), own.DataSent)
+			assert.Equal(t, big.NewInt(1000), own.Tokens)
+
+			err := reconciler.Reconcile(connectionSessionMock.SessionID, tt.peer)
+			assert.NoError(t, err)
+
+			sessions, err := storage.GetAll()
+			assert.NoError(t, err)
+			assert.Len(t, sessions, 1)
+			assert.NotNil(t, sessions[0].Reconciliation)
+			assert.Equal(t, tt.wantDiscrepancy, sessions[0].Reconciliation.Discrepancy)
+			assert.Equal(t, tt.peer.DataSent, sessions[0].Reconciliation.PeerDataSent)
+		})
+	}
+}
+
+func TestSessionStorage_ReconcileUnknownSession(t *testing.T) {
+	storage, storageCleanup := newStorage()
+	defer storageCleanup()
+
+	reconciler := storage.Reconciler(DirectionConsumed)
+	_, ok := reconciler.Accounting("unknown")
+	assert.False(t, ok)
+
+	err := reconciler.Reconcile("unknown", session_node.Accounting{})
+	assert.Error(t, err)
+}
+
+func TestSessionStorage_ReconcileProvidedSession(t *testing.T) {
+	storage, storageCleanup := newStorage()
+	defer storageCleanup()
+
+	storage.consumeServiceSessionEvent(session_event.AppEventSession{
+		Status:  session_event.CreatedStatus,
+		Session: serviceSessionMock,
+	})
+	storage.consumeServiceSessionStatisticsEvent(session_event.AppEventDataTransferred{
+		ID:   serviceSessionMock.ID,
+		Up:   5_000_000,
+		Down: 10_000_000,
+	})
+	sessionID := session_node.ID(serviceSessionMock.ID)
+
+	_, ok := storage.Reconciler(DirectionConsumed).Accounting(sessionID)
+	assert.False(t, ok)
+	assert.Error(t, storage.Reconciler(DirectionConsumed).Reconcile(sessionID, session_node.Accounting{}))
+
+	reconciler := storage.Reconciler(DirectionProvided)
+	own, ok := reconciler.Accounting(sessionID)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(10_000_000), own.DataSent)
+
+	err := reconciler.Reconcile(sessionID, session_node.Accounting{DataSent: 5_000_000, DataReceived: 10_000_000, Tokens: big.NewInt(0)})
+	assert.NoError(t, err)
+
+	sessions, err := storage.GetAll()
+	assert.NoError(t, err)
+	assert.Len(t, sessions, 1)
+	assert.Equal(t, DirectionProvided, sessions[0].Direction)
+	assert.NotNil(t, sessions[0].Reconciliation)
+	assert.False(t, sessions[0].Reconciliation.Discrepancy)
+}
+
+func TestDataMatches_UsesConfiguredSlack(t *testing.T) {
+	assert.True(t, dataMatches(1000, 0, ReconciliationConfig{DataSlack: 1000}))
+	assert.False(t, dataMatches(1001, 0, ReconciliationConfig{DataSlack: 1000}))
+	assert.True(t, dataMatches(10_000_000, 9_000_000, ReconciliationConfig{Tolerance: 0.1}))
+}
@@ -21,6 +21,7 @@ import (
 	"math/big"
 	"time"
 
+	"github.com/mysteriumnetwork/node/core/connection/trafficcategory"
 	"github.com/mysteriumnetwork/node/identity"
 	node_session "github.com/mysteriumnetwork/node/session"
 )
@@ -45,18 +46,35 @@ type History struct {
 	Direction       string
 	ConsumerID      identity.Identity
 	HermesID        string
-	ProviderID      identity.Identity
-	ServiceType     string
+	ProviderID      identity.Identity `storm:"index"`
+	ServiceType     string            `storm:"index"`
 	ConsumerCountry string
 	ProviderCountry string
 	DataSent        uint64
 	DataReceived    uint64
 	Tokens          *big.Int
 
+	// TrafficCategories is filled only when local traffic categorization is enabled.
+	TrafficCategories trafficcategory.Breakdown
+
+	// SLABreach describes the breach of the provider SLA, empty if the SLA was fulfilled.
+	SLABreach string
+	// SLARefund is the refunded share of the session price in percent.
+	SLARefund uint8
+
+	// DisconnectReason is reported by the consumer when ending the session, empty if unknown.
+	DisconnectReason node_session.DisconnectReason
+
 	IPType string
 
+	Protocol node_session.Protocol
+	// Peer is the node version and capabilities announced by the other side of the session.
+	Peer node_session.Peer
+
+	Reconciliation *Reconciliation
+
 	Status  string
-	Started time.Time
+	Started time.Time `storm:"index"`
 	Updated time.Time
 }
 
@@ -18,12 +18,10 @@
 package session
 
 import (
-	"errors"
 	"math/big"
 	"sync"
 	"time"
 
-	"github.com/asdine/storm/v3"
 	"github.com/rs/zerolog/log"
 
 	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
@@ -41,18 +39,20 @@ type timeGetter func() time.Time
 
 // Storage contains functions for storing, getting session objects.
 type Storage struct {
-	storage    *boltdb.Bolt
-	timeGetter timeGetter
+	storage        *boltdb.Bolt
+	timeGetter     timeGetter
+	reconciliation ReconciliationConfig
 
 	mu             sync.RWMutex
 	sessionsActive map[session_node.ID]History
 }
 
 // NewSessionStorage creates session repository with given dependencies.
-func NewSessionStorage(storage *boltdb.Bolt) *Storage {
+func NewSessionStorage(storage *boltdb.Bolt, reconciliation ReconciliationConfig) *Storage {
 	return &Storage{
-		storage:    storage,
-		timeGetter: time.Now,
+		storage:        storage,
+		timeGetter:     time.Now,
+		reconciliation: reconciliation,
 
 		sessionsActive: make(map[session_node.ID]History),
 	}
@@ -63,7 +63,7 @@ func (repo *Storage) Subscribe(bus eventbus.Subscriber) error {
 	if err := bus.Subscribe(session_event.AppTopicSession, repo.consumeServiceSessionEvent); err != nil {
 		return err
 	}
-	if err := bus.SubscribeAsync(session_event.AppTopicDataTransferred, repo.consumeServiceSessionStatisticsEvent); err != nil {
+	if err := bus.SubscribeAsync(session_event.AppTopicDataTransferredBatch, repo.consumeServiceSessionStatisticsBatchEvent); err != nil {
 		return err
 	}
 	if err := bus.SubscribeAsync(session_event.AppTopicTokensEarned, repo.consumeServiceSessionEarningsEvent); err != nil {
@@ -75,6 +75,9 @@ func (repo *Storage) Subscribe(bus eventbus.Subscriber) error {
 	if err := bus.Subscribe(connectionstate.AppTopicConnectionStatistics, repo.consumeConnectionStatisticsEvent); err != nil {
 		return err
 	}
+	if err := bus.Subscribe(connectionstate.AppTopicSLABreach, repo.consumeSLABreachEvent); err != nil {
+		return err
+	}
 	return bus.Subscribe(pingpong_event.AppTopicInvoicePaid, repo.consumeConnectionSpendingEvent)
 }
 
@@ -87,52 +90,57 @@ func (repo *Storage) GetAll() ([]History, error) {
 func (repo *Storage) List(filter *Filter) (result []History, err error) {
 	repo.storage.RLock()
 	defer repo.storage.RUnlock()
-	query := repo.storage.DB().
-		From(sessionStorageBucketName).
-		Select(filter.toMatcher()).
-		OrderBy("Started").
-		Reverse()
-
-	err = query.Find(&result)
-	if errors.Is(err, storm.ErrNotFound) {
-		return []History{}, nil
-	}
+	return filter.find(repo.storage.DB().From(sessionStorageBucketName))
+}
 
-	return result, err
+// Each calls fn for every stored entry matching the filter, in no particular
+// order. Entries are decoded one at a time, so large histories can be
+// streamed without loading them all into memory.
+func (repo *Storage) Each(filter *Filter, fn func(History) error) error {
+	repo.storage.RLock()
+	defer repo.storage.RUnlock()
+	return filter.each(repo.storage.DB().From(sessionStorageBucketName), fn)
 }
 
 // Stats fetches aggregated statistics to Filter.Stats.
 func (repo *Storage) Stats(filter *Filter) (result Stats, err error) {
 	repo.storage.RLock()
 	defer repo.storage.RUnlock()
-	query := repo.storage.DB().
-		From(sessionStorageBucketName).
-		Select(filter.toMatcher()).
-		OrderBy("Started").
-		Reverse()
-
 	result = NewStats()
-	err = query.Each(new(History), func(record interface{}) error {
-		session := record.(*History)
-
-		result.Add(*session)
-
+	err = filter.each(repo.storage.DB().From(sessionStorageBucketName), func(session History) error {
+		result.Add(session)
 		return nil
 	})
 	return result, err
 }
 
+// ConsumerStats aggregates statistics about consumers of the sessions provided in the period of the filter.
+// Earlier provided sessions are only used to tell returning consumers from new ones,
+// so without the start of the period every consumer is counted as new.
+func (repo *Storage) ConsumerStats(filter *Filter) (ConsumerStats, error) {
+	builder, err := newConsumerStatsBuilder(filter.StartedFrom)
+	if err != nil {
+		return ConsumerStats{}, err
+	}
+
+	history := *filter
+	history.StartedFrom = nil
+	history.SetDirection(DirectionProvided)
+
+	repo.storage.RLock()
+	defer repo.storage.RUnlock()
+	if err := history.each(repo.storage.DB().From(sessionStorageBucketName), builder.add); err != nil {
+		return ConsumerStats{}, err
+	}
+	return builder.stats(), nil
+}
+
 const stepDay = 24 * time.Hour
 
 // StatsByDay retrieves aggregated statistics grouped by day to Filter.StatsByDay.
 func (repo *Storage) StatsByDay(filter *Filter) (result map[time.Time]Stats, err error) {
 	repo.storage.RLock()
 	defer repo.storage.RUnlock()
-	query := repo.storage.DB().
-		From(sessionStorageBucketName).
-		Select(filter.toMatcher()).
-		OrderBy("Started").
-		Reverse()
 
 	// fill the period with zeros
 	result = make(map[time.Time]Stats)
@@ -142,14 +150,11 @@ func (repo *Storage) StatsByDay(filter *Filter) (result map[time.Time]Stats, err
 		}
 	}
 
-	err = query.Each(new(History), func(record interface{}) error {
-		session := record.(*History)
-
+	err = filter.each(repo.storage.DB().From(sessionStorageBucketName), func(session History) error {
 		i := session.Started.Truncate(stepDay)
 		stats := result[i]
-		stats.Add(*session)
+		stats.Add(session)
 		result[i] = stats
-
 		return nil
 	})
 	return result, err
@@ -161,7 +166,7 @@ func (repo *Storage) consumeServiceSessionEvent(e session_event.AppEventSession)
 
 	switch e.Status {
 	case session_event.RemovedStatus:
-		repo.handleEndedEvent(sessionID)
+		repo.handleEndedEvent(sessionID, e.Session.DisconnectReason)
 	case session_event.CreatedStatus:
 		repo.mu.Lock()
 		repo.sessionsActive[sessionID] = History{
@@ -175,6 +180,8 @@ func (repo *Storage) consumeServiceSessionEvent(e session_event.AppEventSession)
 			ProviderCountry: e.Session.Proposal.Location.Country,
 			Started:         e.Session.StartedAt.UTC(),
 			Tokens:          new(big.Int),
+			Protocol:        e.Session.Protocol,
+			Peer:            e.Session.Peer,
 		}
 		repo.mu.Unlock()
 
@@ -182,6 +189,12 @@ func (repo *Storage) consumeServiceSessionEvent(e session_event.AppEventSession)
 	}
 }
 
+func (repo *Storage) consumeServiceSessionStatisticsBatchEvent(e session_event.AppEventDataTransferredBatch) {
+	for _, transferred := range e.Sessions {
+		repo.consumeServiceSessionStatisticsEvent(transferred)
+	}
+}
+
 func (repo *Storage) consumeServiceSessionStatisticsEvent(e session_event.AppEventDataTransferred) {
 	repo.mu.Lock()
 	defer repo.mu.Unlock()
@@ -236,7 +249,7 @@ func (repo *Storage) consumeConnectionSessionEvent(e connectionstate.AppEventCon
 
 	switch e.Status {
 	case connectionstate.SessionEndedStatus:
-		repo.handleEndedEvent(sessionID)
+		repo.handleEndedEvent(sessionID, e.DisconnectReason)
 	case connectionstate.SessionCreatedStatus:
 		repo.mu.Lock()
 		repo.sessionsActive[sessionID] = History{
@@ -251,6 +264,8 @@ func (repo *Storage) consumeConnectionSessionEvent(e connectionstate.AppEventCon
 			Started:         e.SessionInfo.StartedAt.UTC(),
 			IPType:          e.SessionInfo.Proposal.Location.IPType,
 			Tokens:          new(big.Int),
+			Protocol:        e.SessionInfo.Protocol,
+			Peer:            e.SessionInfo.Peer,
 		}
 		repo.mu.Unlock()
 
@@ -269,6 +284,29 @@ func (repo *Storage) consumeConnectionStatisticsEvent(e connectionstate.AppEvent
 
 	row.DataSent = e.Stats.BytesSent
 	row.DataReceived = e.Stats.BytesReceived
+	if len(e.Stats.Categories) > 0 {
+		row.TrafficCategories = e.Stats.Categories
+	}
+	repo.sessionsActive[e.SessionInfo.SessionID] = row
+}
+
+func (repo *Storage) consumeSLABreachEvent(e connectionstate.AppEventSLABreach) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	row, ok := repo.activeSession(e.SessionInfo.SessionID)
+	if !ok {
+		return
+	}
+	row.SLABreach = e.Reason
+	row.SLARefund = e.Refund
+
+	err := repo.storage.Update(sessionStorageBucketName, &row)
+	if err != nil {
+		log.Error().Err(err).Msgf("Session %v update failed", e.SessionInfo.SessionID)
+		return
+	}
+
 	repo.sessionsActive[e.SessionInfo.SessionID] = row
 }
 
@@ -294,7 +332,7 @@ func (repo *Storage) consumeConnectionSpendingEvent(e pingpong_event.AppEventInv
 	log.Debug().Msgf("Session %v updated", sessionID)
 }
 
-func (repo *Storage) handleEndedEvent(sessionID session_node.ID) {
+func (repo *Storage) handleEndedEvent(sessionID session_node.ID, reason session_node.DisconnectReason) {
 	repo.mu.Lock()
 	defer repo.mu.Unlock()
 
@@ -305,6 +343,7 @@ func (repo *Storage) handleEndedEvent(sessionID session_node.ID) {
 	}
 	row.Updated = repo.timeGetter().UTC()
 	row.Status = StatusCompleted
+	row.DisconnectReason = reason
 
 	err := repo.storage.Update(sessionStorageBucketName, &row)
 	if err != nil {
@@ -20,6 +20,7 @@ package session
 import (
 	"math/big"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -136,6 +137,125 @@ func TestSessionStorage_ListFiltersDirection(t *testing.T) {
 	assert.Equal(t, []History{}, result)
 }
 
+func TestSessionStorage_ListFiltersByIndexedFields(t *testing.T) {
+	// given
+	session1 := History{
+		SessionID:   session_node.ID("session1"),
+		Direction:   DirectionConsumed,
+		ProviderID:  identity.FromAddress("provider1"),
+		ServiceType: "wireguard",
+		Started:     time.Date(2020, 6, 17, 10, 0, 0, 0, time.UTC),
+	}
+	session2 := History{
+		SessionID:   session_node.ID("session2"),
+		Direction:   DirectionProvided,
+		ProviderID:  identity.FromAddress("provider1"),
+		ServiceType: "openvpn",
+		Started:     time.Date(2020, 6, 17, 10, 0, 0, 500, time.UTC),
+	}
+	session3 := History{
+		SessionID:   session_node.ID("session3"),
+		Direction:   DirectionConsumed,
+		ProviderID:  identity.FromAddress("provider2"),
+		ServiceType: "wireguard",
+		Started:     time.Date(2020, 6, 18, 10, 0, 0, 0, time.UTC),
+	}
+	storage, storageCleanup := newStorageWithSessions(session1, session2, session3)
+	defer storageCleanup()
+
+	// when
+	result, err := storage.List(NewFilter().SetProviderID(identity.FromAddress("provider1")))
+	// then
+	assert.Nil(t, err)
+	assert.Equal(t, []History{session2, session1}, result)
+
+	// when
+	result, err = storage.List(NewFilter().SetProviderID(identity.FromAddress("provider1")).SetDirection(DirectionConsumed))
+	// then
+	assert.Nil(t, err)
+	assert.Equal(t, []History{session1}, result)
+
+	// when
+	result, err = storage.List(NewFilter().SetStartedFrom(session2.Started).SetStartedTo(session3.Started))
+	// then
+	assert.Nil(t, err)
+	assert.Equal(t, []History{session3, session2}, result)
+
+	// when
+	result, err = storage.List(NewFilter().SetStartedFrom(session1.Started).SetServiceType("wireguard"))
+	// then
+	assert.Nil(t, err)
+	assert.Equal(t, []History{session3, session1}, result)
+
+	// when
+	result, err = storage.List(NewFilter().SetServiceType("openvpn"))
+	// then
+	assert.Nil(t, err)
+	assert.Equal(t, []History{session2}, result)
+
+	// when
+	result, err = storage.List(NewFilter().SetProviderID(identity.FromAddress("provider3")))
+	// then
+	assert.Nil(t, err)
+	assert.Equal(t, []History{}, result)
+}
+
+func TestSessionStorage_ReadsArchiveWithoutIndices(t *testing.T) {
+	// given
+	dir, err := os.MkdirTemp("", "sessionStorageTest")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	db, err := boltdb.NewStorage(dir)
+	assert.NoError(t, err)
+	{
+		// History as stored before the fields were indexed.
+		type History struct {
+			SessionID   session_node.ID `storm:"id"`
+			ProviderID  identity.Identity
+			ServiceType string
+			Started     time.Time
+		}
+		for _, id := range []string{"session1", "session2"} {
+			err := db.Store(sessionStorageBucketName, &History{
+				SessionID:   session_node.ID(id),
+				ProviderID:  identity.FromAddress("provider1"),
+				ServiceType: "wireguard",
+				Started:     time.Date(2020, 6, 17, 10, 0, 0, 0, time.UTC),
+			})
+			assert.NoError(t, err)
+		}
+	}
+	assert.NoError(t, db.Close())
+
+	archive, err := boltdb.OpenReadOnly(filepath.Join(dir, "myst.db"))
+	assert.NoError(t, err)
+	defer archive.Close()
+	storage := NewSessionStorage(archive, DefaultReconciliationConfig())
+
+	// when
+	result, err := storage.List(NewFilter().SetProviderID(identity.FromAddress("provider1")))
+	// then
+	assert.NoError(t, err)
+	assert.Len(t, result, 2)
+
+	// when
+	stats, err := storage.Stats(NewFilter().SetStartedFrom(time.Date(2020, 6, 17, 0, 0, 0, 0, time.UTC)))
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, 2, stats.Count)
+
+	// when
+	var exported []session_node.ID
+	err = storage.Each(NewFilter().SetServiceType("wireguard"), func(session History) error {
+		exported = append(exported, session.SessionID)
+		return nil
+	})
+	// then
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []session_node.ID{"session1", "session2"}, exported)
+}
+
 func TestSessionStorage_Stats(t *testing.T) {
 	// given
 	sessionExpected := History{
@@ -178,6 +298,59 @@ func TestSessionStorage_Stats(t *testing.T) {
 	assert.Equal(t, NewStats(), result)
 }
 
+func TestSessionStorage_ConsumerStats(t *testing.T) {
+	// given
+	provided := func(id, consumer, country string, started time.Time, duration time.Duration) History {
+		return History{
+			SessionID:       session_node.ID(id),
+			Direction:       DirectionProvided,
+			ConsumerID:      identity.FromAddress(consumer),
+			ConsumerCountry: country,
+			Started:         started,
+			Updated:         started.Add(duration),
+		}
+	}
+	day := func(d int) time.Time {
+		return time.Date(2020, 6, d, 10, 0, 0, 0, time.UTC)
+	}
+	consumed := provided("session6", "consumer5", "US", day(15), time.Hour)
+	consumed.Direction = DirectionConsumed
+	storage, storageCleanup := newStorageWithSessions(
+		provided("session1", "consumer1", "US", day(1), time.Minute),
+		provided("session2", "consumer1", "US", day(15), 10*time.Minute),
+		provided("session3", "consumer2", "US", day(16), 20*time.Minute),
+		provided("session4", "consumer3", "US", day(17), 30*time.Minute),
+		provided("session5", "consumer4", "LT", day(18), time.Hour),
+		consumed,
+	)
+	defer storageCleanup()
+
+	// when
+	result, err := storage.ConsumerStats(NewFilter().SetStartedFrom(day(10)))
+	// then
+	assert.Nil(t, err)
+	assert.Equal(
+		t,
+		ConsumerStats{
+			Sessions:           4,
+			Consumers:          4,
+			NewConsumers:       3,
+			ReturningConsumers: 1,
+			AverageDuration:    30 * time.Minute,
+			ConsumersByCountry: map[string]int{"US": 3, OtherCountry: 1},
+		},
+		result,
+	)
+
+	// when
+	result, err = storage.ConsumerStats(NewFilter())
+	// then
+	assert.Nil(t, err)
+	assert.Equal(t, 5, result.Sessions)
+	assert.Equal(t, 4, result.NewConsumers)
+	assert.Zero(t, result.ReturningConsumers)
+}
+
 func TestSessionStorage_StatsByDay(t *testing.T) {
 	// given
 	sessionExpected := History{
@@ -345,8 +518,9 @@ func TestSessionStorage_consumeEventEndedOK(t *testing.T) {
 		SessionInfo: connectionSessionMock,
 	})
 	storage.consumeConnectionSessionEvent(connectionstate.AppEventConnectionSession{
-		Status:      connectionstate.SessionEndedStatus,
-		SessionInfo: connectionSessionMock,
+		Status:           connectionstate.SessionEndedStatus,
+		SessionInfo:      connectionSessionMock,
+		DisconnectReason: session_node.DisconnectReasonUserRequest,
 	})
 
 	// then
@@ -356,19 +530,20 @@ func TestSessionStorage_consumeEventEndedOK(t *testing.T) {
 		t,
 		[]History{
 			{
-				SessionID:       session_node.ID("sessionID"),
-				Direction:       "Consumed",
-				ConsumerID:      identity.FromAddress("consumerID"),
-				HermesID:        "0x00000000000000000000000000000000000000AC",
-				ProviderID:      identity.FromAddress("providerID"),
-				ServiceType:     "serviceType",
-				ProviderCountry: "MU",
-				Started:         time.Date(2020, 4, 1, 10, 11, 12, 0, time.UTC),
-				Status:          "Completed",
-				Updated:         time.Date(2020, 4, 1, 12, 0, 0, 0, time.UTC),
-				DataSent:        connectionStatsMock.BytesSent,
-				DataReceived:    connectionStatsMock.BytesReceived,
-				Tokens:          big.NewInt(0),
+				SessionID:        session_node.ID("sessionID"),
+				Direction:        "Consumed",
+				ConsumerID:       identity.FromAddress("consumerID"),
+				HermesID:         "0x00000000000000000000000000000000000000AC",
+				ProviderID:       identity.FromAddress("providerID"),
+				ServiceType:      "serviceType",
+				ProviderCountry:  "MU",
+				Started:          time.Date(2020, 4, 1, 10, 11, 12, 0, time.UTC),
+				Status:           "Completed",
+				Updated:          time.Date(2020, 4, 1, 12, 0, 0, 0, time.UTC),
+				DataSent:         connectionStatsMock.BytesSent,
+				DataReceived:     connectionStatsMock.BytesReceived,
+				Tokens:           big.NewInt(0),
+				DisconnectReason: session_node.DisconnectReasonUserRequest,
 			},
 		},
 		sessions,
@@ -492,7 +667,7 @@ func newStorage() (*Storage, func()) {
 		panic(err)
 	}
 
-	return NewSessionStorage(db), func() {
+	return NewSessionStorage(db, DefaultReconciliationConfig()), func() {
 		err := db.Close()
 		if err != nil {
 			panic(err)
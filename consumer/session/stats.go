@@ -21,6 +21,7 @@ import (
 	"math/big"
 	"time"
 
+	"github.com/mysteriumnetwork/node/core/connection/trafficcategory"
 	"github.com/mysteriumnetwork/node/identity"
 )
 
@@ -40,6 +41,8 @@ type Stats struct {
 	SumDataReceived uint64
 	SumDuration     time.Duration
 	SumTokens       *big.Int
+
+	SumTrafficCategories trafficcategory.Breakdown
 }
 
 // Add accumulates given session to statistics.
@@ -55,5 +58,15 @@ func (s *Stats) Add(session History) {
 	s.SumDataReceived += session.DataReceived
 	s.SumDataSent += session.DataSent
 	s.SumDuration += session.GetDuration()
-	s.SumTokens = new(big.Int).Add(s.SumTokens, session.Tokens)
+	// Sessions stored before tokens were tracked have none.
+	if session.Tokens != nil {
+		s.SumTokens = new(big.Int).Add(s.SumTokens, session.Tokens)
+	}
+
+	if len(session.TrafficCategories) > 0 {
+		if s.SumTrafficCategories == nil {
+			s.SumTrafficCategories = make(trafficcategory.Breakdown)
+		}
+		s.SumTrafficCategories.Add(session.TrafficCategories)
+	}
 }
@@ -0,0 +1,108 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package analytics
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net"
+	"regexp"
+	"strings"
+)
+
+const redacted = "redacted"
+
+var (
+	addressPattern = regexp.MustCompile(`(?i)\b0x[0-9a-f]{40}\b`)
+	uuidPattern    = regexp.MustCompile(`(?i)\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b`)
+	ipv4Pattern    = regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}\b`)
+)
+
+// droppedKeys are the normalized names of fields which are never sent.
+var droppedKeys = map[string]bool{
+	"ip":         true,
+	"ipaddress":  true,
+	"publicip":   true,
+	"outboundip": true,
+	"localip":    true,
+	"gateways":   true,
+}
+
+// anonymizer strips network addresses and replaces identities and session IDs with pseudonyms.
+// Pseudonyms are salted per node run, so the same value is linkable within a batch, but not across restarts.
+type anonymizer struct {
+	salt []byte
+}
+
+func (a anonymizer) anonymize(data interface{}) (interface{}, error) {
+	blob, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var generic interface{}
+	if err := json.Unmarshal(blob, &generic); err != nil {
+		return nil, err
+	}
+	return a.walk(generic), nil
+}
+
+func (a anonymizer) walk(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if droppedKeys[normalizeKey(key)] {
+				delete(v, key)
+				continue
+			}
+			v[key] = a.walk(item)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = a.walk(item)
+		}
+		return v
+	case string:
+		return a.anonymizeString(v)
+	default:
+		return v
+	}
+}
+
+func (a anonymizer) anonymizeString(s string) string {
+	if net.ParseIP(s) != nil {
+		return redacted
+	}
+
+	s = addressPattern.ReplaceAllStringFunc(s, a.pseudonym)
+	s = uuidPattern.ReplaceAllStringFunc(s, a.pseudonym)
+	return ipv4Pattern.ReplaceAllString(s, redacted)
+}
+
+func (a anonymizer) pseudonym(value string) string {
+	hash := sha256.New()
+	hash.Write(a.salt)
+	hash.Write([]byte(strings.ToLower(value)))
+	return "anon-" + hex.EncodeToString(hash.Sum(nil)[:8])
+}
+
+func normalizeKey(key string) string {
+	return strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(key))
+}
@@ -0,0 +1,70 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package analytics
+
+const (
+	// CategorySessions covers session lifecycle, traffic and earnings.
+	CategorySessions = "sessions"
+	// CategoryConnections covers consumer connection lifecycle.
+	CategoryConnections = "connections"
+	// CategoryDiscovery covers proposal announcements.
+	CategoryDiscovery = "discovery"
+	// CategoryNAT covers NAT detection, port mapping and traversal.
+	CategoryNAT = "nat"
+	// CategoryIdentity covers identity registration and unlocking.
+	CategoryIdentity = "identity"
+	// CategoryPerformance covers timing traces.
+	CategoryPerformance = "performance"
+)
+
+// Categories lists all analytics categories.
+var Categories = []string{CategorySessions, CategoryConnections, CategoryDiscovery, CategoryNAT, CategoryIdentity, CategoryPerformance}
+
+// categoryByEvent maps reported event names to analytics categories.
+var categoryByEvent = map[string]string{
+	"session_event":            CategorySessions,
+	"session_data":             CategorySessions,
+	"session_tokens":           CategorySessions,
+	"ping_event":               CategorySessions,
+	"connection_event":         CategoryConnections,
+	"proposal_event":           CategoryDiscovery,
+	"nat_mapping":              CategoryNAT,
+	"stun_detection_event":     CategoryNAT,
+	"nat_type_detection_event": CategoryNAT,
+	"nat_traversal_method":     CategoryNAT,
+	"register_identity":        CategoryIdentity,
+	"unlock":                   CategoryIdentity,
+	"resident_country_event":   CategoryIdentity,
+	"trace_event":              CategoryPerformance,
+}
+
+// CategoryOf returns the category of the event, false if the event is not collected.
+func CategoryOf(eventName string) (string, bool) {
+	category, ok := categoryByEvent[eventName]
+	return category, ok
+}
+
+// IsCategory reports whether the analytics category exists.
+func IsCategory(category string) bool {
+	for _, c := range Categories {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,315 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package analytics
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// maxPending limits the number of events kept while waiting for upload, the oldest ones are dropped first.
+const maxPending = 1000
+
+// ErrUnknownCategory indicates that the analytics category does not exist.
+var ErrUnknownCategory = errors.New("unknown analytics category")
+
+// Settings are the user choices of what analytics are sent.
+type Settings struct {
+	Enabled    bool
+	Categories []string
+}
+
+// Config configures analytics pipeline.
+type Config struct {
+	Settings
+	URL       string
+	BatchSize int
+	Interval  time.Duration
+}
+
+// Event is an anonymized analytics event.
+type Event struct {
+	Category  string      `json:"category"`
+	Name      string      `json:"name"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
+
+// Batch is the body of an analytics upload.
+type Batch struct {
+	Events []Event `json:"events"`
+}
+
+type httpClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+type pendingEvent struct {
+	seq uint64
+	Event
+}
+
+// Pipeline collects reported events, anonymizes them on arrival and uploads them in batches.
+// Events are kept in memory even when analytics are disabled, so that the user can preview them before opting in,
+// but nothing leaves the node until analytics are enabled.
+type Pipeline struct {
+	client     httpClient
+	url        string
+	batchSize  int
+	interval   time.Duration
+	anonymizer anonymizer
+
+	lock     sync.Mutex
+	settings Settings
+	pending  []pendingEvent
+	seq      uint64
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewPipeline creates a new analytics pipeline.
+func NewPipeline(client httpClient, cfg Config) (*Pipeline, error) {
+	if err := validateCategories(cfg.Categories); err != nil {
+		return nil, err
+	}
+
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	batchSize := cfg.BatchSize
+	if batchSize <= 0 {
+		batchSize = maxPending
+	}
+
+	return &Pipeline{
+		client:     client,
+		url:        cfg.URL,
+		batchSize:  batchSize,
+		interval:   cfg.Interval,
+		anonymizer: anonymizer{salt: salt},
+		settings:   cfg.Settings,
+		stop:       make(chan struct{}),
+	}, nil
+}
+
+// Settings returns the current analytics settings.
+func (p *Pipeline) Settings() Settings {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	return Settings{
+		Enabled:    p.settings.Enabled,
+		Categories: append([]string(nil), p.settings.Categories...),
+	}
+}
+
+// SetSettings changes the analytics settings, pending events of disabled categories are discarded.
+func (p *Pipeline) SetSettings(settings Settings) error {
+	if err := validateCategories(settings.Categories); err != nil {
+		return err
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.settings = Settings{
+		Enabled:    settings.Enabled,
+		Categories: append([]string(nil), settings.Categories...),
+	}
+	p.pending = p.selected(len(p.pending))
+	return nil
+}
+
+// Record anonymizes the reported event and queues it for upload.
+func (p *Pipeline) Record(name string, createdAt time.Time, data interface{}) {
+	category, ok := CategoryOf(name)
+	if !ok {
+		return
+	}
+
+	anonymized, err := p.anonymizer.anonymize(data)
+	if err != nil {
+		log.Warn().Err(err).Msgf("Failed to anonymize analytics event %q", name)
+		return
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if !p.categoryEnabled(category) {
+		return
+	}
+
+	p.seq++
+	p.pending = append(p.pending, pendingEvent{
+		seq: p.seq,
+		Event: Event{
+			Category:  category,
+			Name:      name,
+			CreatedAt: createdAt.UTC(),
+			Data:      anonymized,
+		},
+	})
+	if len(p.pending) > maxPending {
+		p.pending = p.pending[len(p.pending)-maxPending:]
+	}
+}
+
+// Preview returns the batch which would be uploaded next with the current settings.
+func (p *Pipeline) Preview() Batch {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	return newBatch(p.selected(p.batchSize))
+}
+
+// Flush uploads the next batch of events if analytics are enabled.
+func (p *Pipeline) Flush() error {
+	p.lock.Lock()
+	if !p.settings.Enabled || p.url == "" {
+		p.lock.Unlock()
+		return nil
+	}
+	events := p.selected(p.batchSize)
+	p.lock.Unlock()
+
+	if len(events) == 0 {
+		return nil
+	}
+	if err := p.upload(newBatch(events)); err != nil {
+		return err
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	last := events[len(events)-1].seq
+	remaining := p.pending[:0]
+	for _, e := range p.pending {
+		if e.seq > last {
+			remaining = append(remaining, e)
+		}
+	}
+	p.pending = remaining
+	return nil
+}
+
+// Start periodically uploads collected events until stopped.
+func (p *Pipeline) Start() {
+	if p.interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-p.stop:
+				return
+			case <-ticker.C:
+				if err := p.Flush(); err != nil {
+					log.Warn().Err(err).Msg("Failed to upload analytics")
+				}
+			}
+		}
+	}()
+}
+
+// Stop stops periodic uploads.
+func (p *Pipeline) Stop() {
+	p.stopOnce.Do(func() {
+		close(p.stop)
+	})
+}
+
+func (p *Pipeline) upload(batch Batch) error {
+	body, err := json.Marshal(batch)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("analytics upload failed with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// selected returns up to limit pending events of the enabled categories, oldest first.
+func (p *Pipeline) selected(limit int) []pendingEvent {
+	var events []pendingEvent
+	for _, e := range p.pending {
+		if len(events) == limit {
+			break
+		}
+		if p.categoryEnabled(e.Category) {
+			events = append(events, e)
+		}
+	}
+	return events
+}
+
+func (p *Pipeline) categoryEnabled(category string) bool {
+	for _, c := range p.settings.Categories {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
+
+func newBatch(events []pendingEvent) Batch {
+	batch := Batch{Events: make([]Event, len(events))}
+	for i, e := range events {
+		batch.Events[i] = e.Event
+	}
+	return batch
+}
+
+func validateCategories(categories []string) error {
+	for _, c := range categories {
+		if !IsCategory(c) {
+			return fmt.Errorf("%w: %s", ErrUnknownCategory, c)
+		}
+	}
+	return nil
+}
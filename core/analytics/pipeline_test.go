@@ -0,0 +1,146 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package analytics
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type sessionContext struct {
+	ID         string
+	ConsumerID string
+	PublicIP   string `json:"public_ip"`
+	Country    string
+	Error      string
+	Rx         uint64
+}
+
+func newPipeline(t *testing.T, url string, settings Settings) *Pipeline {
+	pipeline, err := NewPipeline(http.DefaultClient, Config{
+		Settings:  settings,
+		URL:       url,
+		BatchSize: 2,
+	})
+	require.NoError(t, err)
+	return pipeline
+}
+
+func TestPipeline_RecordAnonymizesEvents(t *testing.T) {
+	pipeline := newPipeline(t, "", Settings{Categories: Categories})
+	createdAt := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	pipeline.Record("session_data", createdAt, sessionContext{
+		ID:         "7d7c1e52-5a9a-4a5e-9a3a-1a2b3c4d5e6f",
+		ConsumerID: "0x2F1C7C1D86a5B9cE1F1A5D2A1b3E7b1C0D9A8e7F",
+		PublicIP:   "203.0.113.5",
+		Country:    "LT",
+		Error:      "dial udp 203.0.113.5:51820: timeout",
+		Rx:         42,
+	})
+	pipeline.Record("unknown_event", createdAt, nil)
+
+	preview := pipeline.Preview()
+	require.Len(t, preview.Events, 1)
+	event := preview.Events[0]
+	assert.Equal(t, CategorySessions, event.Category)
+	assert.Equal(t, "session_data", event.Name)
+	assert.Equal(t, createdAt, event.CreatedAt)
+
+	data := event.Data.(map[string]interface{})
+	assert.NotContains(t, data, "public_ip")
+	assert.Equal(t, "LT", data["Country"])
+	assert.Equal(t, float64(42), data["Rx"])
+	assert.Regexp(t, `^anon-[0-9a-f]{16}$`, data["ID"])
+	assert.Regexp(t, `^anon-[0-9a-f]{16}$`, data["ConsumerID"])
+	assert.Equal(t, "dial udp redacted:51820: timeout", data["Error"])
+
+	pipeline.Record("session_event", createdAt, sessionContext{ConsumerID: "0x2f1c7c1d86a5b9ce1f1a5d2a1b3e7b1c0d9a8e7f"})
+	other := pipeline.Preview().Events[1].Data.(map[string]interface{})
+	assert.Equal(t, data["ConsumerID"], other["ConsumerID"], "pseudonyms should be stable within a run")
+}
+
+func TestPipeline_SettingsSelectCategories(t *testing.T) {
+	pipeline := newPipeline(t, "", Settings{Categories: []string{CategoryNAT, CategorySessions}})
+
+	err := pipeline.SetSettings(Settings{Categories: []string{"location"}})
+	assert.ErrorIs(t, err, ErrUnknownCategory)
+
+	pipeline.Record("nat_mapping", time.Now(), nil)
+	pipeline.Record("session_event", time.Now(), nil)
+	pipeline.Record("connection_event", time.Now(), nil)
+	assert.Len(t, pipeline.Preview().Events, 2)
+
+	require.NoError(t, pipeline.SetSettings(Settings{Enabled: true, Categories: []string{CategoryNAT}}))
+	preview := pipeline.Preview()
+	require.Len(t, preview.Events, 1)
+	assert.Equal(t, "nat_mapping", preview.Events[0].Name)
+	assert.Equal(t, Settings{Enabled: true, Categories: []string{CategoryNAT}}, pipeline.Settings())
+}
+
+func TestPipeline_FlushUploadsBatchesOnlyWhenEnabled(t *testing.T) {
+	batches := make(chan Batch, 3)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var batch Batch
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&batch))
+		batches <- batch
+	}))
+	defer server.Close()
+
+	pipeline := newPipeline(t, server.URL, Settings{Categories: Categories})
+	for _, name := range []string{"session_event", "nat_mapping", "unlock"} {
+		pipeline.Record(name, time.Now(), nil)
+	}
+
+	require.NoError(t, pipeline.Flush())
+	assert.Len(t, batches, 0, "nothing should be sent before opting in")
+
+	require.NoError(t, pipeline.SetSettings(Settings{Enabled: true, Categories: Categories}))
+	preview := pipeline.Preview()
+	require.NoError(t, pipeline.Flush())
+	require.Len(t, batches, 1)
+	assert.Equal(t, preview, <-batches, "uploaded batch should match the preview")
+
+	require.NoError(t, pipeline.Flush())
+	batch := <-batches
+	require.Len(t, batch.Events, 1)
+	assert.Equal(t, "unlock", batch.Events[0].Name)
+
+	require.NoError(t, pipeline.Flush())
+	assert.Len(t, batches, 0)
+	assert.Empty(t, pipeline.Preview().Events)
+}
+
+func TestPipeline_FlushKeepsEventsOnFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	pipeline := newPipeline(t, server.URL, Settings{Enabled: true, Categories: Categories})
+	pipeline.Record("session_event", time.Now(), nil)
+
+	assert.Error(t, pipeline.Flush())
+	assert.Len(t, pipeline.Preview().Events, 1)
+}
@@ -18,14 +18,22 @@
 package auth
 
 import (
+	"errors"
+
 	"github.com/mysteriumnetwork/node/config"
 	"github.com/rs/zerolog/log"
 )
 
+// ErrReservedUsername represents an error when managing the default admin user as a local user.
+var ErrReservedUsername = errors.New("username is reserved for the default admin user")
+
 // Authenticator wraps CredentialsManager to provide
 // an easy way of authentication for builtin UI.
+// The user configured with tequilapi flags is always an admin,
+// additional users with their own roles are kept in UserStore.
 type Authenticator struct {
 	manager *CredentialsManager
+	users   *UserStore
 }
 
 // NewAuthenticator creates an authenticator.
@@ -33,23 +41,48 @@ func NewAuthenticator() *Authenticator {
 	pswDir := config.GetString(config.FlagDataDir)
 	return &Authenticator{
 		manager: NewCredentialsManager(pswDir),
+		users:   NewUserStore(pswDir),
 	}
 }
 
 // CheckCredentials checks if provided username and password combo is valid
 // comparing it to stored credentials.
 func (a *Authenticator) CheckCredentials(username, password string) error {
-	return a.manager.Validate(username, password)
+	_, err := a.Authenticate(username, password)
+	return err
+}
+
+// Authenticate checks provided credentials and returns the role of the user.
+func (a *Authenticator) Authenticate(username, password string) (Role, error) {
+	if isDefaultUser(username) {
+		if err := a.manager.Validate(username, password); err != nil {
+			return "", err
+		}
+		return RoleAdmin, nil
+	}
+	return a.users.Validate(username, password)
+}
+
+// Role returns the current role of the user.
+func (a *Authenticator) Role(username string) (Role, error) {
+	if isDefaultUser(username) {
+		return RoleAdmin, nil
+	}
+	return a.users.Role(username)
 }
 
 // ChangePassword changes user password.
 func (a *Authenticator) ChangePassword(username, oldPassword, newPassword string) error {
-	err := a.manager.Validate(username, oldPassword)
+	_, err := a.Authenticate(username, oldPassword)
 	if err != nil {
 		log.Info().Err(err).Msg("Bad credentials for changing password")
 		return ErrUnauthorized
 	}
-	err = a.manager.SetPassword(newPassword)
+	if isDefaultUser(username) {
+		err = a.manager.SetPassword(newPassword)
+	} else {
+		err = a.users.SetPassword(username, newPassword)
+	}
 	if err != nil {
 		log.Info().Err(err).Msg("Error changing password")
 		return err
@@ -57,3 +90,52 @@ func (a *Authenticator) ChangePassword(username, oldPassword, newPassword string
 	log.Info().Msgf("%q user password changed successfully", username)
 	return nil
 }
+
+// Users returns all users including the default admin user.
+func (a *Authenticator) Users() ([]User, error) {
+	users, err := a.users.List()
+	if err != nil {
+		return nil, err
+	}
+	return append([]User{{Username: config.FlagTequilapiUsername.Value, Role: RoleAdmin}}, users...), nil
+}
+
+// AddUser creates a new local user with the given role.
+func (a *Authenticator) AddUser(username, password string, role Role) error {
+	if isDefaultUser(username) {
+		return ErrReservedUsername
+	}
+	if err := a.users.Add(username, password, role); err != nil {
+		return err
+	}
+	log.Info().Msgf("%q user added with role %q", username, role)
+	return nil
+}
+
+// SetUserRole changes the role of a local user.
+func (a *Authenticator) SetUserRole(username string, role Role) error {
+	if isDefaultUser(username) {
+		return ErrReservedUsername
+	}
+	if err := a.users.SetRole(username, role); err != nil {
+		return err
+	}
+	log.Info().Msgf("%q user role changed to %q", username, role)
+	return nil
+}
+
+// RemoveUser deletes a local user.
+func (a *Authenticator) RemoveUser(username string) error {
+	if isDefaultUser(username) {
+		return ErrReservedUsername
+	}
+	if err := a.users.Remove(username); err != nil {
+		return err
+	}
+	log.Info().Msgf("%q user removed", username)
+	return nil
+}
+
+func isDefaultUser(username string) bool {
+	return username == config.FlagTequilapiUsername.Value
+}
@@ -0,0 +1,246 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package auth
+
+import (
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/eventbus"
+)
+
+// AppTopicLoginLockout is published when a client IP or a user gets locked out after failed logins.
+const AppTopicLoginLockout = "LoginLockout"
+
+const recoveryTokenFile = "nodeui-recovery-token"
+
+// ErrBadRecoveryToken represents an error when the lockout recovery token does not match.
+var ErrBadRecoveryToken = errors.New("bad recovery token")
+
+// LockoutKind tells what was locked out.
+type LockoutKind string
+
+const (
+	// LockoutKindIP is a lockout of a client IP.
+	LockoutKindIP LockoutKind = "ip"
+	// LockoutKindUser is a lockout of a username.
+	LockoutKindUser LockoutKind = "user"
+)
+
+// LockoutEvent describes a login lockout.
+type LockoutEvent struct {
+	Kind     LockoutKind `json:"kind"`
+	Subject  string      `json:"subject"`
+	Failures int         `json:"failures"`
+	Until    time.Time   `json:"until"`
+}
+
+type loginAttempts struct {
+	failures    int
+	last        time.Time
+	lockedUntil time.Time
+}
+
+// LoginLimiter tracks failed logins per client IP and per user
+// and locks them out for exponentially growing periods.
+// Lockouts can be cleared with a token written to the data directory,
+// so only someone with local access to the node can recover.
+type LoginLimiter struct {
+	threshold int
+	base      time.Duration
+	max       time.Duration
+	tokenFile string
+	publisher eventbus.Publisher
+	now       func() time.Time
+
+	mu       sync.Mutex
+	attempts map[string]*loginAttempts
+}
+
+// NewLoginLimiter creates a login limiter locking out after threshold failed logins,
+// threshold of 0 disables lockouts.
+func NewLoginLimiter(dataDir string, threshold int, base, max time.Duration, publisher eventbus.Publisher) *LoginLimiter {
+	return &LoginLimiter{
+		threshold: threshold,
+		base:      base,
+		max:       max,
+		tokenFile: filepath.Join(dataDir, recoveryTokenFile),
+		publisher: publisher,
+		now:       time.Now,
+		attempts:  make(map[string]*loginAttempts),
+	}
+}
+
+// Check returns how long logins from the client IP or for the user are still locked out, zero if they are allowed.
+func (l *LoginLimiter) Check(ip, username string) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	var wait time.Duration
+	for _, key := range []string{ipKey(ip), userKey(username)} {
+		if a, ok := l.attempts[key]; ok && a.lockedUntil.After(now) {
+			if d := a.lockedUntil.Sub(now); d > wait {
+				wait = d
+			}
+		}
+	}
+	return wait
+}
+
+// Failure records a failed login.
+func (l *LoginLimiter) Failure(ip, username string) {
+	if l.threshold <= 0 {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.prune(now)
+
+	var events []LockoutEvent
+	for _, kv := range []struct {
+		key     string
+		kind    LockoutKind
+		subject string
+	}{
+		{ipKey(ip), LockoutKindIP, ip},
+		{userKey(username), LockoutKindUser, username},
+	} {
+		a, ok := l.attempts[kv.key]
+		if !ok {
+			a = &loginAttempts{}
+			l.attempts[kv.key] = a
+		}
+		a.failures++
+		a.last = now
+		if a.failures < l.threshold {
+			continue
+		}
+
+		a.lockedUntil = now.Add(l.lockoutDuration(a.failures))
+		events = append(events, LockoutEvent{
+			Kind:     kv.kind,
+			Subject:  kv.subject,
+			Failures: a.failures,
+			Until:    a.lockedUntil,
+		})
+	}
+
+	if len(events) == 0 {
+		return
+	}
+	if err := l.ensureRecoveryToken(); err != nil {
+		log.Error().Err(err).Msg("Could not write login lockout recovery token")
+	}
+	for _, e := range events {
+		log.Warn().Msgf("API login locked out for %s %q after %d failed attempts until %s, recovery token is in %s", e.Kind, e.Subject, e.Failures, e.Until.Format(time.RFC3339), l.tokenFile)
+		if l.publisher != nil {
+			l.publisher.Publish(AppTopicLoginLockout, e)
+		}
+	}
+}
+
+// Success clears failed logins of the client IP and the user.
+func (l *LoginLimiter) Success(ip, username string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.attempts, ipKey(ip))
+	delete(l.attempts, userKey(username))
+}
+
+// Recover clears all lockouts if the token matches the one in the data directory.
+// The token is single use.
+func (l *LoginLimiter) Recover(token string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	data, err := os.ReadFile(l.tokenFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return ErrBadRecoveryToken
+	}
+	if err != nil {
+		return fmt.Errorf("could not read recovery token: %w", err)
+	}
+	stored := strings.TrimSpace(string(data))
+	if stored == "" || subtle.ConstantTimeCompare([]byte(stored), []byte(strings.TrimSpace(token))) != 1 {
+		return ErrBadRecoveryToken
+	}
+
+	l.attempts = make(map[string]*loginAttempts)
+	if err := os.Remove(l.tokenFile); err != nil {
+		log.Warn().Err(err).Msg("Could not remove used login lockout recovery token")
+	}
+	log.Info().Msg("API login lockouts cleared with recovery token")
+	return nil
+}
+
+func (l *LoginLimiter) lockoutDuration(failures int) time.Duration {
+	d := l.base
+	for i := l.threshold; i < failures; i++ {
+		d *= 2
+		if d >= l.max {
+			return l.max
+		}
+	}
+	if d > l.max {
+		return l.max
+	}
+	return d
+}
+
+// prune forgets attempts that are neither locked out nor recent enough to count.
+func (l *LoginLimiter) prune(now time.Time) {
+	for key, a := range l.attempts {
+		if a.lockedUntil.Before(now) && now.Sub(a.last) > l.max {
+			delete(l.attempts, key)
+		}
+	}
+}
+
+func (l *LoginLimiter) ensureRecoveryToken() error {
+	if _, err := os.Stat(l.tokenFile); err == nil {
+		return nil
+	}
+
+	token, err := generateRandomBytes(32)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(l.tokenFile, []byte(hex.EncodeToString(token)+"\n"), 0600)
+}
+
+func ipKey(ip string) string {
+	return "ip:" + ip
+}
+
+func userKey(username string) string {
+	return "user:" + username
+}
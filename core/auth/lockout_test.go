@@ -0,0 +1,102 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package auth
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockPublisher struct {
+	events []LockoutEvent
+}
+
+func (m *mockPublisher) Publish(topic string, data interface{}) {
+	if topic == AppTopicLoginLockout {
+		m.events = append(m.events, data.(LockoutEvent))
+	}
+}
+
+func TestLoginLimiter_Lockout(t *testing.T) {
+	dir := t.TempDir()
+	publisher := &mockPublisher{}
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := NewLoginLimiter(dir, 3, 10*time.Second, time.Minute, publisher)
+	l.now = func() time.Time { return now }
+
+	l.Failure("10.0.0.2", "myst")
+	l.Failure("10.0.0.2", "myst")
+	assert.Zero(t, l.Check("10.0.0.2", "myst"))
+	assert.Empty(t, publisher.events)
+
+	l.Failure("10.0.0.2", "myst")
+	assert.Equal(t, 10*time.Second, l.Check("10.0.0.2", "myst"))
+	assert.Equal(t, 10*time.Second, l.Check("10.0.0.3", "myst"), "user is locked out from any IP")
+	assert.Equal(t, 10*time.Second, l.Check("10.0.0.2", "alice"), "IP is locked out for any user")
+	assert.Zero(t, l.Check("10.0.0.3", "alice"))
+	require.Len(t, publisher.events, 2)
+	assert.Equal(t, LockoutEvent{Kind: LockoutKindIP, Subject: "10.0.0.2", Failures: 3, Until: now.Add(10 * time.Second)}, publisher.events[0])
+	assert.Equal(t, LockoutKindUser, publisher.events[1].Kind)
+
+	now = now.Add(11 * time.Second)
+	assert.Zero(t, l.Check("10.0.0.2", "myst"))
+	l.Failure("10.0.0.2", "myst")
+	assert.Equal(t, 20*time.Second, l.Check("10.0.0.2", "myst"))
+
+	now = now.Add(21 * time.Second)
+	for i := 0; i < 5; i++ {
+		l.Failure("10.0.0.2", "myst")
+	}
+	assert.Equal(t, time.Minute, l.Check("10.0.0.2", "myst"))
+
+	l.Success("10.0.0.2", "myst")
+	assert.Zero(t, l.Check("10.0.0.2", "myst"))
+}
+
+func TestLoginLimiter_Recover(t *testing.T) {
+	dir := t.TempDir()
+	l := NewLoginLimiter(dir, 1, time.Minute, time.Hour, nil)
+
+	assert.ErrorIs(t, l.Recover("anything"), ErrBadRecoveryToken)
+
+	l.Failure("10.0.0.2", "myst")
+	assert.NotZero(t, l.Check("10.0.0.2", "myst"))
+
+	data, err := os.ReadFile(filepath.Join(dir, recoveryTokenFile))
+	require.NoError(t, err)
+	assert.ErrorIs(t, l.Recover("wrong"), ErrBadRecoveryToken)
+	assert.NotZero(t, l.Check("10.0.0.2", "myst"))
+
+	require.NoError(t, l.Recover(strings.TrimSpace(string(data))))
+	assert.Zero(t, l.Check("10.0.0.2", "myst"))
+	assert.ErrorIs(t, l.Recover(string(data)), ErrBadRecoveryToken, "token is single use")
+}
+
+func TestLoginLimiter_Disabled(t *testing.T) {
+	l := NewLoginLimiter(t.TempDir(), 0, time.Minute, time.Hour, nil)
+	for i := 0; i < 10; i++ {
+		l.Failure("10.0.0.2", "myst")
+	}
+	assert.Zero(t, l.Check("10.0.0.2", "myst"))
+}
@@ -0,0 +1,240 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const usersFile = "nodeui-users"
+
+var (
+	// ErrUserExists represents an error when adding a user with a taken username.
+	ErrUserExists = errors.New("user already exists")
+	// ErrUserNotFound represents an error when a user is not known.
+	ErrUserNotFound = errors.New("user not found")
+)
+
+// User is a local API user without credentials.
+type User struct {
+	Username string `json:"username"`
+	Role     Role   `json:"role"`
+}
+
+type storedUser struct {
+	User
+	PasswordHash string `json:"password_hash"`
+}
+
+// UserStore keeps additional local API users in the data directory.
+type UserStore struct {
+	fileLocation string
+	mu           sync.Mutex
+}
+
+// NewUserStore returns a user store persisted in the given data directory.
+func NewUserStore(dataDir string) *UserStore {
+	return &UserStore{
+		fileLocation: filepath.Join(dataDir, usersFile),
+	}
+}
+
+// Validate checks the password of a stored user and returns the user role.
+func (s *UserStore) Validate(username, password string) (Role, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	users, err := s.load()
+	if err != nil {
+		return "", fmt.Errorf("could not load users: %w", err)
+	}
+	u, ok := users[username]
+	if !ok {
+		return "", ErrBadCredentials
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(password)); err != nil {
+		return "", fmt.Errorf("bad credentials: %w", err)
+	}
+	return u.Role, nil
+}
+
+// Role returns the role of a stored user.
+func (s *UserStore) Role(username string) (Role, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	users, err := s.load()
+	if err != nil {
+		return "", fmt.Errorf("could not load users: %w", err)
+	}
+	u, ok := users[username]
+	if !ok {
+		return "", ErrUserNotFound
+	}
+	return u.Role, nil
+}
+
+// List returns stored users sorted by username.
+func (s *UserStore) List() ([]User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	users, err := s.load()
+	if err != nil {
+		return nil, fmt.Errorf("could not load users: %w", err)
+	}
+	result := make([]User, 0, len(users))
+	for _, u := range users {
+		result = append(result, u.User)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Username < result[j].Username
+	})
+	return result, nil
+}
+
+// Add stores a new user.
+func (s *UserStore) Add(username, password string, role Role) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	users, err := s.load()
+	if err != nil {
+		return fmt.Errorf("could not load users: %w", err)
+	}
+	if _, ok := users[username]; ok {
+		return ErrUserExists
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("unable to generate password hash: %w", err)
+	}
+	users[username] = storedUser{
+		User:         User{Username: username, Role: role},
+		PasswordHash: string(hash),
+	}
+	return s.save(users)
+}
+
+// SetRole changes the role of a stored user.
+func (s *UserStore) SetRole(username string, role Role) error {
+	return s.update(username, func(u *storedUser) error {
+		u.Role = role
+		return nil
+	})
+}
+
+// SetPassword changes the password of a stored user.
+func (s *UserStore) SetPassword(username, password string) error {
+	return s.update(username, func(u *storedUser) error {
+		hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+		if err != nil {
+			return fmt.Errorf("unable to generate password hash: %w", err)
+		}
+		u.PasswordHash = string(hash)
+		return nil
+	})
+}
+
+// Remove deletes a stored user.
+func (s *UserStore) Remove(username string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	users, err := s.load()
+	if err != nil {
+		return fmt.Errorf("could not load users: %w", err)
+	}
+	if _, ok := users[username]; !ok {
+		return ErrUserNotFound
+	}
+	delete(users, username)
+	return s.save(users)
+}
+
+func (s *UserStore) update(username string, fn func(u *storedUser) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	users, err := s.load()
+	if err != nil {
+		return fmt.Errorf("could not load users: %w", err)
+	}
+	u, ok := users[username]
+	if !ok {
+		return ErrUserNotFound
+	}
+	if err := fn(&u); err != nil {
+		return err
+	}
+	users[username] = u
+	return s.save(users)
+}
+
+func (s *UserStore) load() (map[string]storedUser, error) {
+	users := make(map[string]storedUser)
+	data, err := os.ReadFile(s.fileLocation)
+	if errors.Is(err, os.ErrNotExist) {
+		return users, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var list []storedUser
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("could not parse users file: %w", err)
+	}
+	for _, u := range list {
+		users[u.Username] = u
+	}
+	return users, nil
+}
+
+func (s *UserStore) save(users map[string]storedUser) error {
+	list := make([]storedUser, 0, len(users))
+	for _, u := range users {
+		list = append(list, u)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Username < list[j].Username
+	})
+	data, err := json.Marshal(list)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(s.fileLocation, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("could not open users file: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(data); err != nil {
+		return fmt.Errorf("could not write users: %w", err)
+	}
+	return f.Sync()
+}
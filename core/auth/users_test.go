@@ -0,0 +1,80 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mysteriumnetwork/node/config"
+)
+
+func TestAuthenticator_Users(t *testing.T) {
+	a := &Authenticator{
+		manager: NewCredentialsManager(t.TempDir()),
+		users:   NewUserStore(t.TempDir()),
+	}
+	admin := config.FlagTequilapiUsername.Value
+
+	role, err := a.Authenticate(admin, config.FlagTequilapiPassword.Value)
+	require.NoError(t, err)
+	assert.Equal(t, RoleAdmin, role)
+
+	require.NoError(t, a.AddUser("alice", "secret", RoleViewer))
+	assert.ErrorIs(t, a.AddUser("alice", "other", RoleAdmin), ErrUserExists)
+	assert.ErrorIs(t, a.AddUser(admin, "other", RoleViewer), ErrReservedUsername)
+
+	role, err = a.Authenticate("alice", "secret")
+	require.NoError(t, err)
+	assert.Equal(t, RoleViewer, role)
+	_, err = a.Authenticate("alice", "wrong")
+	assert.Error(t, err)
+	_, err = a.Authenticate("bob", "secret")
+	assert.Error(t, err)
+
+	require.NoError(t, a.SetUserRole("alice", RoleOperator))
+	role, err = a.Role("alice")
+	require.NoError(t, err)
+	assert.Equal(t, RoleOperator, role)
+	assert.ErrorIs(t, a.SetUserRole(admin, RoleViewer), ErrReservedUsername)
+
+	require.NoError(t, a.ChangePassword("alice", "secret", "changed"))
+	_, err = a.Authenticate("alice", "changed")
+	assert.NoError(t, err)
+
+	users, err := a.Users()
+	require.NoError(t, err)
+	assert.Equal(t, []User{{Username: admin, Role: RoleAdmin}, {Username: "alice", Role: RoleOperator}}, users)
+
+	require.NoError(t, a.RemoveUser("alice"))
+	assert.ErrorIs(t, a.RemoveUser("alice"), ErrUserNotFound)
+	_, err = a.Role("alice")
+	assert.ErrorIs(t, err, ErrUserNotFound)
+}
+
+func TestRole_Allows(t *testing.T) {
+	assert.True(t, RoleAdmin.Allows(RoleViewer))
+	assert.True(t, RoleOperator.Allows(RoleOperator))
+	assert.False(t, RoleViewer.Allows(RoleOperator))
+	assert.False(t, Role("root").Allows(RoleViewer))
+
+	_, err := ParseRole("root")
+	assert.Error(t, err)
+}
@@ -0,0 +1,319 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package beneficiary
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/asdine/storm/v3"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/mysteriumnetwork/payments/client"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	addressBookBucket     = "address-book"
+	addressBookUsedBucket = "address-book-used"
+
+	contractCheckTimeout = 10 * time.Second
+)
+
+var (
+	// ErrEntryNotFound is returned when the address book has no entry with the given name.
+	ErrEntryNotFound = errors.New("address book entry not found")
+	// ErrEntryExists is returned when the address book already has an entry with the given name.
+	ErrEntryExists = errors.New("address book entry already exists")
+	// ErrInvalidName is returned for names which can't be told apart from addresses.
+	ErrInvalidName = errors.New("invalid address book entry name")
+	// ErrConfirmationRequired is returned when the target has to be confirmed before it is used.
+	ErrConfirmationRequired = errors.New("address confirmation required")
+)
+
+// ConfirmationRequiredError is returned when the target has to be confirmed before it is used.
+// The request has to be repeated with the token to confirm it.
+type ConfirmationRequiredError struct {
+	Address   common.Address
+	Token     string
+	ExpiresAt time.Time
+	Warnings  []string
+}
+
+func (e *ConfirmationRequiredError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrConfirmationRequired, e.Address.Hex())
+}
+
+// Is allows matching the error with ErrConfirmationRequired.
+func (e *ConfirmationRequiredError) Is(target error) bool {
+	return target == ErrConfirmationRequired
+}
+
+// AddressBookEntry is a named beneficiary or withdrawal target.
+type AddressBookEntry struct {
+	Name      string `storm:"id"`
+	Address   common.Address
+	Note      string
+	CreatedAt time.Time
+}
+
+type usedAddress struct {
+	Address     string `storm:"id"`
+	FirstUsedAt time.Time
+}
+
+// AddressBookRules configure validation of the payout targets.
+type AddressBookRules struct {
+	// WarnContracts requires confirmation of contract addresses, they may not be able to receive earnings.
+	WarnContracts bool
+	// ConfirmFirstUse requires confirmation of the addresses never paid out to before.
+	ConfirmFirstUse bool
+	// ConfirmationTTL is how long a confirmation token stays valid.
+	ConfirmationTTL time.Duration
+}
+
+type addressBookStorage interface {
+	Store(bucket string, data interface{}) error
+	GetAllFrom(bucket string, data interface{}) error
+	GetOneByField(bucket string, fieldName string, key interface{}, to interface{}) error
+	Delete(bucket string, data interface{}) error
+}
+
+type chainClientProvider interface {
+	GetClientByChain(chainID int64) (client.BC, error)
+}
+
+type pendingConfirmation struct {
+	address   common.Address
+	expiresAt time.Time
+}
+
+// AddressBook keeps named payout targets and validates the targets before they are used.
+type AddressBook struct {
+	storage addressBookStorage
+	bc      chainClientProvider
+	rules   AddressBookRules
+
+	lock    sync.Mutex
+	pending map[string]pendingConfirmation
+	now     func() time.Time
+}
+
+// NewAddressBook returns a new address book. Contract addresses are not detected if bc is nil.
+func NewAddressBook(storage addressBookStorage, bc chainClientProvider, rules AddressBookRules) *AddressBook {
+	return &AddressBook{
+		storage: storage,
+		bc:      bc,
+		rules:   rules,
+		pending: make(map[string]pendingConfirmation),
+		now:     time.Now,
+	}
+}
+
+// Add stores a new named target.
+func (ab *AddressBook) Add(name, address, note string) (AddressBookEntry, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || common.IsHexAddress(name) {
+		return AddressBookEntry{}, ErrInvalidName
+	}
+	if !common.IsHexAddress(address) {
+		return AddressBookEntry{}, ErrInvalidAddress
+	}
+
+	if _, err := ab.Get(name); err == nil {
+		return AddressBookEntry{}, ErrEntryExists
+	} else if !errors.Is(err, ErrEntryNotFound) {
+		return AddressBookEntry{}, err
+	}
+
+	entry := AddressBookEntry{
+		Name:      name,
+		Address:   common.HexToAddress(address),
+		Note:      note,
+		CreatedAt: ab.now().UTC(),
+	}
+	if err := ab.storage.Store(addressBookBucket, &entry); err != nil {
+		return AddressBookEntry{}, errors.Wrap(err, "could not store address book entry")
+	}
+	return entry, nil
+}
+
+// Get returns the entry with the given name.
+func (ab *AddressBook) Get(name string) (AddressBookEntry, error) {
+	var entry AddressBookEntry
+	err := ab.storage.GetOneByField(addressBookBucket, "Name", name, &entry)
+	if errors.Is(err, storm.ErrNotFound) {
+		return AddressBookEntry{}, ErrEntryNotFound
+	}
+	return entry, err
+}
+
+// List returns all entries ordered by name.
+func (ab *AddressBook) List() ([]AddressBookEntry, error) {
+	entries := []AddressBookEntry{}
+	err := ab.storage.GetAllFrom(addressBookBucket, &entries)
+	if err != nil && !errors.Is(err, storm.ErrNotFound) {
+		return nil, err
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name < entries[j].Name
+	})
+	return entries, nil
+}
+
+// Remove deletes the entry with the given name.
+func (ab *AddressBook) Remove(name string) error {
+	entry, err := ab.Get(name)
+	if err != nil {
+		return err
+	}
+	return ab.storage.Delete(addressBookBucket, &entry)
+}
+
+// Address returns the address of the target, which is either an address book entry name or a hex address.
+func (ab *AddressBook) Address(target string) (common.Address, error) {
+	if common.IsHexAddress(target) {
+		return common.HexToAddress(target), nil
+	}
+	entry, err := ab.Get(target)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return entry.Address, nil
+}
+
+// Resolve returns the address of the target, which is either an address book entry name or a hex address,
+// after checking it against the rules. If the target needs to be confirmed *ConfirmationRequiredError is
+// returned, the call has to be repeated with its token.
+func (ab *AddressBook) Resolve(chainID int64, target, confirmationToken string) (common.Address, error) {
+	address, err := ab.Address(target)
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	var warnings []string
+	if ab.rules.WarnContracts && ab.isContract(chainID, address) {
+		warnings = append(warnings, fmt.Sprintf("%s is a contract, make sure it can receive the tokens", address.Hex()))
+	}
+	firstUse := false
+	if ab.rules.ConfirmFirstUse {
+		used, err := ab.isUsed(address)
+		if err != nil {
+			return common.Address{}, err
+		}
+		if !used {
+			firstUse = true
+			warnings = append(warnings, fmt.Sprintf("%s was never used before", address.Hex()))
+		}
+	}
+	if len(warnings) == 0 {
+		return address, nil
+	}
+
+	if !ab.confirm(confirmationToken, address) {
+		return common.Address{}, ab.requireConfirmation(address, warnings)
+	}
+	if firstUse {
+		used := usedAddress{Address: address.Hex(), FirstUsedAt: ab.now().UTC()}
+		if err := ab.storage.Store(addressBookUsedBucket, &used); err != nil {
+			return common.Address{}, errors.Wrap(err, "could not store used address")
+		}
+	}
+	return address, nil
+}
+
+func (ab *AddressBook) isUsed(address common.Address) (bool, error) {
+	var used usedAddress
+	err := ab.storage.GetOneByField(addressBookUsedBucket, "Address", address.Hex(), &used)
+	if errors.Is(err, storm.ErrNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (ab *AddressBook) confirm(token string, address common.Address) bool {
+	if token == "" {
+		return false
+	}
+
+	ab.lock.Lock()
+	defer ab.lock.Unlock()
+
+	pending, ok := ab.pending[token]
+	if !ok || pending.address != address || ab.now().After(pending.expiresAt) {
+		return false
+	}
+	delete(ab.pending, token)
+	return true
+}
+
+func (ab *AddressBook) requireConfirmation(address common.Address, warnings []string) error {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return errors.Wrap(err, "could not generate confirmation token")
+	}
+	token := hex.EncodeToString(buf)
+
+	ab.lock.Lock()
+	defer ab.lock.Unlock()
+
+	now := ab.now()
+	for t, p := range ab.pending {
+		if now.After(p.expiresAt) {
+			delete(ab.pending, t)
+		}
+	}
+	expiresAt := now.Add(ab.rules.ConfirmationTTL)
+	ab.pending[token] = pendingConfirmation{address: address, expiresAt: expiresAt}
+
+	return &ConfirmationRequiredError{
+		Address:   address,
+		Token:     token,
+		ExpiresAt: expiresAt,
+		Warnings:  warnings,
+	}
+}
+
+// isContract reports whether the address has code, failures of the check are ignored.
+func (ab *AddressBook) isContract(chainID int64, address common.Address) bool {
+	if ab.bc == nil {
+		return false
+	}
+
+	bc, err := ab.bc.GetClientByChain(chainID)
+	if err != nil {
+		log.Warn().Err(err).Msgf("Could not check address %s on chain %d", address.Hex(), chainID)
+		return false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), contractCheckTimeout)
+	defer cancel()
+
+	code, err := bc.Client().CodeAt(ctx, address, nil)
+	if err != nil {
+		log.Warn().Err(err).Msgf("Could not get code of address %s on chain %d", address.Hex(), chainID)
+		return false
+	}
+	return len(code) > 0
+}
@@ -0,0 +1,120 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package beneficiary
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var addressBookTestAddress = common.HexToAddress("0x4443189b9b945DD38E7bfB6167F9909451582eE5")
+
+func TestAddressBook_Entries(t *testing.T) {
+	book := NewAddressBook(newManagerTestStorage(t), nil, AddressBookRules{})
+
+	_, err := book.Add("exchange", addressBookTestAddress.Hex(), "deposit address")
+	require.NoError(t, err)
+	_, err = book.Add("cold wallet", "0x0000000000000000000000000000000000000002", "")
+	require.NoError(t, err)
+
+	_, err = book.Add("exchange", addressBookTestAddress.Hex(), "")
+	assert.ErrorIs(t, err, ErrEntryExists)
+	_, err = book.Add(addressBookTestAddress.Hex(), addressBookTestAddress.Hex(), "")
+	assert.ErrorIs(t, err, ErrInvalidName)
+	_, err = book.Add("broken", "not an address", "")
+	assert.ErrorIs(t, err, ErrInvalidAddress)
+
+	entries, err := book.List()
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "cold wallet", entries[0].Name)
+	assert.Equal(t, "exchange", entries[1].Name)
+	assert.Equal(t, addressBookTestAddress, entries[1].Address)
+	assert.Equal(t, "deposit address", entries[1].Note)
+
+	require.NoError(t, book.Remove("exchange"))
+	assert.ErrorIs(t, book.Remove("exchange"), ErrEntryNotFound)
+	_, err = book.Get("exchange")
+	assert.ErrorIs(t, err, ErrEntryNotFound)
+}
+
+func TestAddressBook_Resolve(t *testing.T) {
+	book := NewAddressBook(newManagerTestStorage(t), nil, AddressBookRules{})
+	_, err := book.Add("exchange", addressBookTestAddress.Hex(), "")
+	require.NoError(t, err)
+
+	address, err := book.Resolve(1, "exchange", "")
+	assert.NoError(t, err)
+	assert.Equal(t, addressBookTestAddress, address)
+
+	address, err = book.Resolve(1, "0x0000000000000000000000000000000000000002", "")
+	assert.NoError(t, err)
+	assert.Equal(t, common.HexToAddress("0x2"), address)
+
+	_, err = book.Resolve(1, "unknown", "")
+	assert.ErrorIs(t, err, ErrEntryNotFound)
+}
+
+func TestAddressBook_ConfirmsFirstUse(t *testing.T) {
+	book := NewAddressBook(newManagerTestStorage(t), nil, AddressBookRules{
+		ConfirmFirstUse: true,
+		ConfirmationTTL: time.Minute,
+	})
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	book.now = func() time.Time { return now }
+
+	_, err := book.Resolve(1, addressBookTestAddress.Hex(), "")
+	var confirmation *ConfirmationRequiredError
+	require.True(t, errors.As(err, &confirmation))
+	assert.ErrorIs(t, err, ErrConfirmationRequired)
+	assert.Equal(t, addressBookTestAddress, confirmation.Address)
+	assert.NotEmpty(t, confirmation.Warnings)
+
+	_, err = book.Resolve(1, "0x0000000000000000000000000000000000000002", confirmation.Token)
+	assert.ErrorIs(t, err, ErrConfirmationRequired, "token is bound to the address")
+
+	address, err := book.Resolve(1, addressBookTestAddress.Hex(), confirmation.Token)
+	assert.NoError(t, err)
+	assert.Equal(t, addressBookTestAddress, address)
+
+	address, err = book.Resolve(1, addressBookTestAddress.Hex(), "")
+	assert.NoError(t, err, "address was used already")
+	assert.Equal(t, addressBookTestAddress, address)
+}
+
+func TestAddressBook_ConfirmationExpires(t *testing.T) {
+	book := NewAddressBook(newManagerTestStorage(t), nil, AddressBookRules{
+		ConfirmFirstUse: true,
+		ConfirmationTTL: time.Minute,
+	})
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	book.now = func() time.Time { return now }
+
+	_, err := book.Resolve(1, addressBookTestAddress.Hex(), "")
+	var confirmation *ConfirmationRequiredError
+	require.True(t, errors.As(err, &confirmation))
+
+	now = now.Add(2 * time.Minute)
+	_, err = book.Resolve(1, addressBookTestAddress.Hex(), confirmation.Token)
+	assert.ErrorIs(t, err, ErrConfirmationRequired)
+}
@@ -0,0 +1,266 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package beneficiary
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/asdine/storm/v3"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rs/zerolog/log"
+
+	nodevent "github.com/mysteriumnetwork/node/core/node/event"
+	"github.com/mysteriumnetwork/node/eventbus"
+	"github.com/mysteriumnetwork/node/identity"
+)
+
+// AppTopicBeneficiaryChanged is published when beneficiary change is confirmed on chain or fails.
+const AppTopicBeneficiaryChanged = "beneficiary_changed"
+
+// AppEventBeneficiaryChanged describes the outcome of beneficiary change.
+type AppEventBeneficiaryChanged struct {
+	ID          identity.Identity
+	ChainID     int64
+	Beneficiary common.Address
+	// Error is empty if the change is confirmed on chain.
+	Error string
+}
+
+var (
+	// ErrChangeInProgress is returned when beneficiary change of the identity is already in progress.
+	ErrChangeInProgress = errors.New("beneficiary change is already in progress")
+	// ErrChangeNotConfirmed is returned when beneficiary change is not reflected on chain in time.
+	ErrChangeNotConfirmed = errors.New("beneficiary change was not confirmed on chain in time")
+)
+
+// ManagerConfig configures beneficiary manager.
+type ManagerConfig struct {
+	// PollInterval between on-chain beneficiary checks while change is being confirmed.
+	PollInterval time.Duration
+	// PollTimeout after which unconfirmed change is considered failed.
+	PollTimeout time.Duration
+}
+
+type identityLister interface {
+	GetIdentities() []identity.Identity
+}
+
+// Manager changes beneficiaries of identities, waits for the changes to be reflected on chain
+// and keeps the locally stored beneficiaries in sync with the chain.
+type Manager struct {
+	chainID   int64
+	cfg       ManagerConfig
+	ad        addressProvider
+	set       settler
+	provider  *Provider
+	addresses BeneficiaryStorage
+	ids       identityLister
+	publisher eventbus.Publisher
+	*beneficiaryChangeKeeper
+
+	lock     sync.Mutex
+	changing map[identity.Identity]struct{}
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewManager returns a new beneficiary manager of the given chain.
+func NewManager(currentChain int64, ad addressProvider, st storage, bc multiChainBC, set settler, addresses BeneficiaryStorage, ids identityLister, publisher eventbus.Publisher, cfg ManagerConfig) *Manager {
+	return &Manager{
+		chainID:                 currentChain,
+		cfg:                     cfg,
+		ad:                      ad,
+		set:                     set,
+		provider:                NewProvider(currentChain, ad, st, bc),
+		addresses:               addresses,
+		ids:                     ids,
+		publisher:               publisher,
+		beneficiaryChangeKeeper: newBeneficiaryChangeKeeper(currentChain, st),
+		changing:                make(map[identity.Identity]struct{}),
+		stop:                    make(chan struct{}),
+	}
+}
+
+// Subscribe reconciles stored beneficiaries with the chain once the node starts.
+func (m *Manager) Subscribe(bus eventbus.Subscriber) error {
+	return bus.SubscribeAsync(nodevent.AppTopicNode, func(ev nodevent.Payload) {
+		if ev.Status == nodevent.StatusStarted {
+			m.Reconcile(m.ids.GetIdentities())
+		}
+	})
+}
+
+// Stop stops waiting for the changes in progress, they are resumed by Reconcile after restart.
+func (m *Manager) Stop() {
+	m.stopOnce.Do(func() {
+		close(m.stop)
+	})
+}
+
+// RequestChange submits the beneficiary change transaction settling into the given hermeses,
+// the active hermes is used if none are given. Change is confirmed asynchronously, its progress is tracked by change status.
+func (m *Manager) RequestChange(id identity.Identity, beneficiary common.Address, hermeses []common.Address) error {
+	if beneficiary == (common.Address{}) {
+		return ErrInvalidAddress
+	}
+
+	if len(hermeses) == 0 {
+		hermes, err := m.ad.GetActiveHermes(m.chainID)
+		if err != nil {
+			return fmt.Errorf("could not get active hermes: %w", err)
+		}
+		hermeses = []common.Address{hermes}
+	}
+
+	if !m.begin(id) {
+		return ErrChangeInProgress
+	}
+	if _, err := m.updateChangeStatus(id, Pending, beneficiary.Hex(), nil); err != nil {
+		m.end(id)
+		return fmt.Errorf("failed to start tracking status change: %w", err)
+	}
+
+	go func() {
+		defer m.end(id)
+
+		if err := m.set.SettleWithBeneficiary(m.chainID, id, beneficiary, hermeses); err != nil {
+			m.complete(id, beneficiary, fmt.Errorf("could not submit beneficiary change: %w", err))
+			return
+		}
+		m.complete(id, beneficiary, m.waitOnChain(id, beneficiary))
+	}()
+	return nil
+}
+
+// Reconcile updates stored beneficiaries of the identities to the ones found on chain.
+// Changes left pending before restart are completed if they are reflected on chain, or waited for otherwise.
+func (m *Manager) Reconcile(ids []identity.Identity) {
+	for _, id := range ids {
+		current, err := m.provider.GetBeneficiary(id.ToCommonAddress())
+		if err != nil {
+			log.Warn().Err(err).Msgf("Could not get beneficiary of %s", id.Address)
+			continue
+		}
+
+		if current != (common.Address{}) {
+			stored, err := m.addresses.Address(id.Address)
+			if err != nil && !errors.Is(err, ErrNotFound) {
+				log.Warn().Err(err).Msgf("Could not get stored beneficiary of %s", id.Address)
+			} else if !common.IsHexAddress(stored) || common.HexToAddress(stored) != current {
+				if err := m.addresses.Save(id.Address, current.Hex()); err != nil {
+					log.Warn().Err(err).Msgf("Could not store beneficiary of %s", id.Address)
+				}
+			}
+		}
+
+		status, err := m.GetChangeStatus(id)
+		if err != nil {
+			if !errors.Is(err, storm.ErrNotFound) {
+				log.Warn().Err(err).Msgf("Could not get beneficiary change status of %s", id.Address)
+			}
+			continue
+		}
+		if status.State != Pending {
+			continue
+		}
+
+		beneficiary := common.HexToAddress(status.ChangeTo)
+		if beneficiary == current {
+			m.complete(id, beneficiary, nil)
+			continue
+		}
+		if !m.begin(id) {
+			continue
+		}
+		go func(id identity.Identity) {
+			defer m.end(id)
+			m.complete(id, beneficiary, m.waitOnChain(id, beneficiary))
+		}(id)
+	}
+}
+
+func (m *Manager) waitOnChain(id identity.Identity, beneficiary common.Address) error {
+	timeout := time.NewTimer(m.cfg.PollTimeout)
+	defer timeout.Stop()
+	ticker := time.NewTicker(m.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		current, err := m.provider.GetBeneficiary(id.ToCommonAddress())
+		if err != nil {
+			log.Warn().Err(err).Msgf("Could not get beneficiary of %s", id.Address)
+		} else if current == beneficiary {
+			return nil
+		}
+
+		select {
+		case <-m.stop:
+			return nil
+		case <-timeout.C:
+			return ErrChangeNotConfirmed
+		case <-ticker.C:
+		}
+	}
+}
+
+func (m *Manager) complete(id identity.Identity, beneficiary common.Address, changeErr error) {
+	select {
+	case <-m.stop:
+		// leave the change pending, it is resumed after restart
+		return
+	default:
+	}
+
+	if changeErr == nil {
+		if err := m.addresses.Save(id.Address, beneficiary.Hex()); err != nil {
+			log.Warn().Err(err).Msgf("Could not store beneficiary of %s", id.Address)
+		}
+	} else {
+		log.Error().Err(changeErr).Msgf("Beneficiary change of %s to %s failed", id.Address, beneficiary.Hex())
+	}
+
+	if _, err := m.updateChangeStatus(id, Completed, beneficiary.Hex(), changeErr); err != nil {
+		log.Err(err).Msg("saving beneficiary change status")
+	}
+
+	ev := AppEventBeneficiaryChanged{ID: id, ChainID: m.chainID, Beneficiary: beneficiary}
+	if changeErr != nil {
+		ev.Error = changeErr.Error()
+	}
+	m.publisher.Publish(AppTopicBeneficiaryChanged, ev)
+}
+
+func (m *Manager) begin(id identity.Identity) bool {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if _, ok := m.changing[id]; ok {
+		return false
+	}
+	m.changing[id] = struct{}{}
+	return true
+}
+
+func (m *Manager) end(id identity.Identity) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	delete(m.changing, id)
+}
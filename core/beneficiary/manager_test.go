@@ -0,0 +1,222 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package beneficiary
+
+import (
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mysteriumnetwork/node/core/storage/boltdb"
+	"github.com/mysteriumnetwork/node/identity"
+)
+
+var (
+	managerTestID          = identity.FromAddress("0x94bb756322a137a5f0b013dd972d227fe7caa698")
+	managerTestBeneficiary = common.HexToAddress("0x94bb756322a137a5f0b013dd972d227fe7caa000")
+	managerTestHermes      = common.HexToAddress("0x0000000000000000000000000000000000000001")
+)
+
+func TestManager_RequestChange(t *testing.T) {
+	// given:
+	db := newManagerTestStorage(t)
+	bc := &mockBC{}
+	set := &mockSettler{onSettle: func(beneficiary common.Address) { bc.setBeneficiary(beneficiary) }}
+	addresses := NewAddressStorage(db)
+	bus := &mockPublisher{}
+	m := newTestManager(db, bc, set, addresses, bus)
+	defer m.Stop()
+
+	// when:
+	err := m.RequestChange(managerTestID, managerTestBeneficiary, nil)
+
+	// then:
+	assert.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		return bus.Pop() != nil
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []common.Address{managerTestHermes}, set.hermeses)
+
+	status, err := m.GetChangeStatus(managerTestID)
+	require.NoError(t, err)
+	assert.Equal(t, Completed, status.State)
+	assert.Empty(t, status.Error)
+
+	stored, err := addresses.Address(managerTestID.Address)
+	assert.NoError(t, err)
+	assert.Equal(t, managerTestBeneficiary.Hex(), stored)
+}
+
+func TestManager_RequestChangeInProgress(t *testing.T) {
+	// given:
+	db := newManagerTestStorage(t)
+	release := make(chan struct{})
+	set := &mockSettler{onSettle: func(common.Address) { <-release }}
+	m := newTestManager(db, &mockBC{}, set, NewAddressStorage(db), &mockPublisher{})
+	defer m.Stop()
+	defer close(release)
+
+	// when:
+	err := m.RequestChange(managerTestID, managerTestBeneficiary, nil)
+	assert.NoError(t, err)
+	err = m.RequestChange(managerTestID, managerTestBeneficiary, nil)
+
+	// then:
+	assert.ErrorIs(t, err, ErrChangeInProgress)
+}
+
+func TestManager_RequestChangeNotConfirmed(t *testing.T) {
+	// given:
+	db := newManagerTestStorage(t)
+	bus := &mockPublisher{}
+	addresses := NewAddressStorage(db)
+	m := newTestManager(db, &mockBC{}, &mockSettler{}, addresses, bus)
+	defer m.Stop()
+
+	// when:
+	err := m.RequestChange(managerTestID, managerTestBeneficiary, nil)
+
+	// then:
+	assert.NoError(t, err)
+	var ev interface{}
+	assert.Eventually(t, func() bool {
+		ev = bus.Pop()
+		return ev != nil
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, ErrChangeNotConfirmed.Error(), ev.(AppEventBeneficiaryChanged).Error)
+
+	status, err := m.GetChangeStatus(managerTestID)
+	require.NoError(t, err)
+	assert.Equal(t, Completed, status.State)
+	assert.Equal(t, ErrChangeNotConfirmed.Error(), status.Error)
+
+	_, err = addresses.Address(managerTestID.Address)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestManager_Reconcile(t *testing.T) {
+	// given:
+	db := newManagerTestStorage(t)
+	bc := &mockBC{}
+	bc.setBeneficiary(managerTestBeneficiary)
+	addresses := NewAddressStorage(db)
+	require.NoError(t, addresses.Save(managerTestID.Address, "0x0000000000000000000000000000000000000002"))
+	m := newTestManager(db, bc, &mockSettler{}, addresses, &mockPublisher{})
+	defer m.Stop()
+	_, err := m.updateChangeStatus(managerTestID, Pending, managerTestBeneficiary.Hex(), nil)
+	require.NoError(t, err)
+
+	// when:
+	m.Reconcile([]identity.Identity{managerTestID})
+
+	// then:
+	stored, err := addresses.Address(managerTestID.Address)
+	assert.NoError(t, err)
+	assert.Equal(t, managerTestBeneficiary.Hex(), stored)
+
+	status, err := m.GetChangeStatus(managerTestID)
+	require.NoError(t, err)
+	assert.Equal(t, Completed, status.State)
+}
+
+func newManagerTestStorage(t *testing.T) *boltdb.Bolt {
+	dir, err := os.MkdirTemp("", "mysttest")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	db, err := boltdb.NewStorage(dir)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func newTestManager(db *boltdb.Bolt, bc multiChainBC, set settler, addresses BeneficiaryStorage, bus *mockPublisher) *Manager {
+	return NewManager(1, &mockAddressProvider{}, db, bc, set, addresses, nil, bus, ManagerConfig{
+		PollInterval: 10 * time.Millisecond,
+		PollTimeout:  100 * time.Millisecond,
+	})
+}
+
+type mockAddressProvider struct{}
+
+func (m *mockAddressProvider) GetActiveHermes(chainID int64) (common.Address, error) {
+	return managerTestHermes, nil
+}
+
+func (m *mockAddressProvider) GetRegistryAddress(chainID int64) (common.Address, error) {
+	return common.Address{}, nil
+}
+
+func (m *mockAddressProvider) GetActiveChannelAddress(chainID int64, id common.Address) (common.Address, error) {
+	return common.Address{}, nil
+}
+
+type mockBC struct {
+	lock        sync.Mutex
+	beneficiary common.Address
+}
+
+func (m *mockBC) setBeneficiary(beneficiary common.Address) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.beneficiary = beneficiary
+}
+
+func (m *mockBC) GetBeneficiary(chainID int64, registryAddress, identity common.Address) (common.Address, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.beneficiary, nil
+}
+
+type mockSettler struct {
+	hermeses []common.Address
+	onSettle func(beneficiary common.Address)
+}
+
+func (m *mockSettler) SettleWithBeneficiary(chainID int64, id identity.Identity, beneficiary common.Address, hermeses []common.Address) error {
+	m.hermeses = hermeses
+	if m.onSettle != nil {
+		m.onSettle(beneficiary)
+	}
+	return nil
+}
+
+type mockPublisher struct {
+	lock sync.Mutex
+	last interface{}
+}
+
+func (m *mockPublisher) Publish(topic string, data interface{}) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.last = data
+}
+
+// Pop returns the last published event and forgets it.
+func (m *mockPublisher) Pop() interface{} {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	ev := m.last
+	m.last = nil
+	return ev
+}
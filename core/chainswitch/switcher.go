@@ -0,0 +1,185 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package chainswitch
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/config"
+	"github.com/mysteriumnetwork/node/core/service"
+	"github.com/mysteriumnetwork/node/session"
+)
+
+var (
+	// ErrUnknownChain is returned when the chain is not one of the configured chains.
+	ErrUnknownChain = errors.New("chain is not configured")
+	// ErrInvalidChain is returned when registry or hermes of the chain can not be resolved.
+	ErrInvalidChain = errors.New("invalid chain configuration")
+	// ErrChainFlagSet is returned when the active chain is set by CLI flag, which can not be overridden.
+	ErrChainFlagSet = errors.New("active chain is set by command line flag")
+	// ErrSessionsActive is returned when sessions are active and draining them was not requested.
+	ErrSessionsActive = errors.New("sessions are active")
+)
+
+type addressProvider interface {
+	GetRegistryAddress(chainID int64) (common.Address, error)
+	GetActiveHermes(chainID int64) (common.Address, error)
+}
+
+type sessionPool interface {
+	GetAll() []*service.Session
+}
+
+type connectionManager interface {
+	List() []int
+	DisconnectWithReason(n int, reason session.DisconnectReason) error
+}
+
+type registrationChecker interface {
+	Recheck()
+}
+
+type proposalPublisher interface {
+	RepublishProposals()
+}
+
+type configStore interface {
+	GetInt64(key string) int64
+	IsCLI(key string) bool
+	SetUser(key string, value interface{})
+	SaveUserConfig() error
+}
+
+// Result describes the performed chain switch.
+type Result struct {
+	PreviousChainID   int64
+	ChainID           int64
+	ClosedSessions    int
+	ClosedConnections int
+}
+
+// Switcher switches the active chain of the running node without restarting it.
+type Switcher struct {
+	lock          sync.Mutex
+	chains        []int64
+	addresses     addressProvider
+	sessions      sessionPool
+	connections   connectionManager
+	registrations registrationChecker
+	proposals     proposalPublisher
+	config        configStore
+}
+
+// NewSwitcher creates new active chain switcher.
+func NewSwitcher(
+	chains []int64,
+	addresses addressProvider,
+	sessions sessionPool,
+	connections connectionManager,
+	registrations registrationChecker,
+	proposals proposalPublisher,
+	config configStore,
+) *Switcher {
+	return &Switcher{
+		chains:        chains,
+		addresses:     addresses,
+		sessions:      sessions,
+		connections:   connections,
+		registrations: registrations,
+		proposals:     proposals,
+		config:        config,
+	}
+}
+
+// Switch makes the given chain active. Provider sessions and consumer connections are closed if drain is set,
+// otherwise the switch is refused while any of them exist. Registrations are re-checked
+// and proposals are announced again on the new chain.
+func (s *Switcher) Switch(chainID int64, drain bool) (Result, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	result := Result{
+		PreviousChainID: s.config.GetInt64(config.FlagChainID.Name),
+		ChainID:         chainID,
+	}
+	if err := s.validate(chainID); err != nil {
+		return result, err
+	}
+	if chainID == result.PreviousChainID {
+		return result, nil
+	}
+	if s.config.IsCLI(config.FlagChainID.Name) {
+		return result, ErrChainFlagSet
+	}
+
+	sessions := s.sessions.GetAll()
+	connections := s.connections.List()
+	if !drain && (len(sessions) > 0 || len(connections) > 0) {
+		return result, fmt.Errorf("%w: %d provider sessions, %d connections", ErrSessionsActive, len(sessions), len(connections))
+	}
+
+	for _, sess := range sessions {
+		sess.CloseWithReason(session.DisconnectReasonChainSwitch)
+		result.ClosedSessions++
+	}
+	for _, n := range connections {
+		if err := s.connections.DisconnectWithReason(n, session.DisconnectReasonChainSwitch); err != nil {
+			log.Warn().Err(err).Msgf("Could not close connection %d before switching chain", n)
+			continue
+		}
+		result.ClosedConnections++
+	}
+
+	s.config.SetUser(config.FlagChainID.Name, chainID)
+	if err := s.config.SaveUserConfig(); err != nil {
+		s.config.SetUser(config.FlagChainID.Name, result.PreviousChainID)
+		return result, fmt.Errorf("could not save active chain: %w", err)
+	}
+	log.Info().Msgf("Active chain switched from %d to %d", result.PreviousChainID, chainID)
+
+	s.registrations.Recheck()
+	s.proposals.RepublishProposals()
+
+	return result, nil
+}
+
+func (s *Switcher) validate(chainID int64) error {
+	known := false
+	for _, c := range s.chains {
+		if c == chainID {
+			known = true
+			break
+		}
+	}
+	if !known {
+		return fmt.Errorf("%w: %d", ErrUnknownChain, chainID)
+	}
+
+	if _, err := s.addresses.GetRegistryAddress(chainID); err != nil {
+		return fmt.Errorf("%w: could not get registry of chain %d: %v", ErrInvalidChain, chainID, err)
+	}
+	if _, err := s.addresses.GetActiveHermes(chainID); err != nil {
+		return fmt.Errorf("%w: could not get hermes of chain %d: %v", ErrInvalidChain, chainID, err)
+	}
+	return nil
+}
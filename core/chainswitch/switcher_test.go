@@ -0,0 +1,176 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package chainswitch
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/config"
+	"github.com/mysteriumnetwork/node/core/service"
+	"github.com/mysteriumnetwork/node/pb"
+	"github.com/mysteriumnetwork/node/session"
+	"github.com/mysteriumnetwork/node/trace"
+)
+
+func TestSwitcher_Switch(t *testing.T) {
+	sess, err := service.NewSession(&service.Instance{}, &pb.SessionRequest{}, trace.NewTracer(""))
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		chainID     int64
+		drain       bool
+		cli         bool
+		addressErr  error
+		sessions    []*service.Session
+		connections []int
+		wantErr     error
+		wantChain   int64
+		wantClosed  int
+	}{
+		{name: "switches chain", chainID: 80001, wantChain: 80001},
+		{name: "unknown chain", chainID: 5, wantErr: ErrUnknownChain, wantChain: 137},
+		{name: "invalid chain config", chainID: 80001, addressErr: errors.New("no registry"), wantErr: ErrInvalidChain, wantChain: 137},
+		{name: "chain set by flag", chainID: 80001, cli: true, wantErr: ErrChainFlagSet, wantChain: 137},
+		{name: "refuses active sessions", chainID: 80001, sessions: []*service.Session{sess}, connections: []int{1}, wantErr: ErrSessionsActive, wantChain: 137},
+		{name: "drains active sessions", chainID: 80001, drain: true, sessions: []*service.Session{sess}, connections: []int{1}, wantChain: 80001, wantClosed: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &mockConfig{values: map[string]int64{config.FlagChainID.Name: 137}, cli: tt.cli}
+			connections := &mockConnections{ids: tt.connections}
+			registrations := &mockRegistrations{}
+			proposals := &mockProposals{}
+			switcher := NewSwitcher(
+				[]int64{137, 80001},
+				&mockAddressProvider{err: tt.addressErr},
+				&mockSessionPool{sessions: tt.sessions},
+				connections,
+				registrations,
+				proposals,
+				cfg,
+			)
+
+			result, err := switcher.Switch(tt.chainID, tt.drain)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.wantChain, cfg.values[config.FlagChainID.Name])
+			assert.Equal(t, int64(137), result.PreviousChainID)
+			assert.Equal(t, tt.wantClosed, result.ClosedSessions)
+			assert.Equal(t, tt.wantClosed, result.ClosedConnections)
+
+			switched := tt.wantErr == nil
+			assert.Equal(t, switched, cfg.saved)
+			assert.Equal(t, switched, registrations.rechecked)
+			assert.Equal(t, switched, proposals.republished)
+			if tt.drain {
+				assert.Equal(t, []session.DisconnectReason{session.DisconnectReasonChainSwitch}, connections.reasons)
+				assert.Equal(t, session.DisconnectReasonChainSwitch, sess.DisconnectReason())
+			}
+		})
+	}
+}
+
+func TestSwitcher_Switch_RevertsWhenConfigNotSaved(t *testing.T) {
+	cfg := &mockConfig{values: map[string]int64{config.FlagChainID.Name: 137}, saveErr: errors.New("read-only")}
+	registrations := &mockRegistrations{}
+	switcher := NewSwitcher([]int64{137, 80001}, &mockAddressProvider{}, &mockSessionPool{}, &mockConnections{}, registrations, &mockProposals{}, cfg)
+
+	_, err := switcher.Switch(80001, false)
+	assert.Error(t, err)
+	assert.Equal(t, int64(137), cfg.values[config.FlagChainID.Name])
+	assert.False(t, registrations.rechecked)
+}
+
+type mockAddressProvider struct {
+	err error
+}
+
+func (m *mockAddressProvider) GetRegistryAddress(chainID int64) (common.Address, error) {
+	return common.Address{}, m.err
+}
+
+func (m *mockAddressProvider) GetActiveHermes(chainID int64) (common.Address, error) {
+	return common.Address{}, nil
+}
+
+type mockSessionPool struct {
+	sessions []*service.Session
+}
+
+func (m *mockSessionPool) GetAll() []*service.Session {
+	return m.sessions
+}
+
+type mockConnections struct {
+	ids     []int
+	reasons []session.DisconnectReason
+}
+
+func (m *mockConnections) List() []int {
+	return m.ids
+}
+
+func (m *mockConnections) DisconnectWithReason(n int, reason session.DisconnectReason) error {
+	m.reasons = append(m.reasons, reason)
+	return nil
+}
+
+type mockRegistrations struct {
+	rechecked bool
+}
+
+func (m *mockRegistrations) Recheck() {
+	m.rechecked = true
+}
+
+type mockProposals struct {
+	republished bool
+}
+
+func (m *mockProposals) RepublishProposals() {
+	m.republished = true
+}
+
+type mockConfig struct {
+	values  map[string]int64
+	cli     bool
+	saved   bool
+	saveErr error
+}
+
+func (m *mockConfig) GetInt64(key string) int64 {
+	return m.values[key]
+}
+
+func (m *mockConfig) IsCLI(key string) bool {
+	return m.cli
+}
+
+func (m *mockConfig) SetUser(key string, value interface{}) {
+	m.values[key] = value.(int64)
+}
+
+func (m *mockConfig) SaveUserConfig() error {
+	m.saved = m.saveErr == nil
+	return m.saveErr
+}
@@ -0,0 +1,85 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package compliance
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Config describes compliance requirements set by the node operator.
+type Config struct {
+	// Jurisdiction is the country code the provider operates under.
+	// Country of the detected service location is used when empty.
+	Jurisdiction string
+	// TermsVersion is the minimal terms of use version provider has to accept before starting services.
+	// Empty version disables the requirement.
+	TermsVersion string
+	// CurrentTermsVersion is the terms of use version bundled with the node.
+	// Roles which accepted an older version have to accept it again before using provider services or payments.
+	CurrentTermsVersion string
+	// Restrictions lists service types which must not run in a jurisdiction, keyed by country code.
+	Restrictions map[string][]string
+}
+
+// ParseRestrictions parses restrictions given as "country:serviceType" entries.
+func ParseRestrictions(entries []string) (map[string][]string, error) {
+	restrictions := make(map[string][]string)
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		country, serviceType, ok := strings.Cut(entry, ":")
+		country, serviceType = strings.ToUpper(strings.TrimSpace(country)), strings.TrimSpace(serviceType)
+		if !ok || country == "" || serviceType == "" {
+			return nil, fmt.Errorf("invalid service restriction %q, expected country:serviceType", entry)
+		}
+		restrictions[country] = append(restrictions[country], serviceType)
+	}
+	return restrictions, nil
+}
+
+// compareVersions compares dot separated versions part by part, numerically where possible.
+// It returns a negative number when a is older than b, positive when newer and 0 when they are equal.
+func compareVersions(a, b string) int {
+	aParts := strings.Split(strings.TrimPrefix(a, "v"), ".")
+	bParts := strings.Split(strings.TrimPrefix(b, "v"), ".")
+	for i := 0; i < len(aParts) || i < len(bParts); i++ {
+		aPart, bPart := "0", "0"
+		if i < len(aParts) {
+			aPart = aParts[i]
+		}
+		if i < len(bParts) {
+			bPart = bParts[i]
+		}
+
+		aNum, aErr := strconv.Atoi(aPart)
+		bNum, bErr := strconv.Atoi(bPart)
+		if aErr != nil || bErr != nil {
+			if c := strings.Compare(aPart, bPart); c != 0 {
+				return c
+			}
+		} else if aNum != bNum {
+			return aNum - bNum
+		}
+	}
+	return 0
+}
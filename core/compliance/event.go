@@ -0,0 +1,30 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package compliance
+
+// AppTopicTerms is the topic of terms acceptance events.
+const AppTopicTerms = "terms"
+
+// AppEventTerms describes terms acceptance state of a role.
+type AppEventTerms struct {
+	Role            string
+	AcceptedVersion string
+	CurrentVersion  string
+	// ReacceptanceRequired is set when the accepted version is older than the current one.
+	ReacceptanceRequired bool
+}
@@ -0,0 +1,242 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package compliance
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mysteriumnetwork/node/core/storage"
+)
+
+const bucketName = "terms-acceptance"
+
+const (
+	// RoleProvider is the role of terms accepted for provider features.
+	RoleProvider = "provider"
+	// RoleConsumer is the role of terms accepted for consumer features.
+	RoleConsumer = "consumer"
+)
+
+var (
+	// ErrTermsNotAccepted indicates that the required terms of use version is not accepted.
+	ErrTermsNotAccepted = errors.New("terms of use not accepted")
+	// ErrTermsOutdated indicates that the accepted terms of use are older than the current ones and have to be accepted again.
+	ErrTermsOutdated = errors.New("terms of use have to be accepted again")
+	// ErrServiceRestricted indicates that the service type must not run in the jurisdiction.
+	ErrServiceRestricted = errors.New("service type is restricted in the jurisdiction")
+	// ErrInvalidAcceptance indicates that the acceptance misses role or version.
+	ErrInvalidAcceptance = errors.New("invalid terms acceptance")
+)
+
+type persistentStorage interface {
+	Store(bucket string, data interface{}) error
+	GetAllFrom(bucket string, data interface{}) error
+	GetOneByField(bucket string, fieldName string, key interface{}, to interface{}) error
+}
+
+type publisher interface {
+	Publish(topic string, data interface{})
+}
+
+// Acceptance records terms of use version accepted for a role.
+type Acceptance struct {
+	Role       string `storm:"id"`
+	Version    string
+	AcceptedAt time.Time
+}
+
+// Gate decides whether provider services may run under the configured compliance requirements.
+type Gate struct {
+	lock      sync.Mutex
+	storage   persistentStorage
+	publisher publisher
+	config    Config
+	now       func() time.Time
+}
+
+// NewGate creates a new compliance gate.
+func NewGate(storage persistentStorage, publisher publisher, config Config) *Gate {
+	return &Gate{
+		storage:   storage,
+		publisher: publisher,
+		config:    config,
+		now:       time.Now,
+	}
+}
+
+// Config returns the compliance requirements.
+func (g *Gate) Config() Config {
+	return g.config
+}
+
+// Accept records acceptance of the terms version for the role.
+func (g *Gate) Accept(role, version string) (Acceptance, error) {
+	if role != RoleProvider && role != RoleConsumer {
+		return Acceptance{}, fmt.Errorf("%w: unknown role %q", ErrInvalidAcceptance, role)
+	}
+	if version == "" {
+		return Acceptance{}, fmt.Errorf("%w: version is required", ErrInvalidAcceptance)
+	}
+
+	acceptance := Acceptance{
+		Role:       role,
+		Version:    version,
+		AcceptedAt: g.now().UTC(),
+	}
+
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	if err := g.storage.Store(bucketName, &acceptance); err != nil {
+		return Acceptance{}, err
+	}
+	g.publish(acceptance)
+	return acceptance, nil
+}
+
+// Import records acceptance of the role agreed outside of the gate, unless the same or a newer version is recorded already.
+func (g *Gate) Import(role, version string) error {
+	accepted, err := g.acceptedVersion(role)
+	if err != nil || (accepted != "" && compareVersions(accepted, version) >= 0) {
+		return err
+	}
+
+	_, err = g.Accept(role, version)
+	return err
+}
+
+// Acceptances returns terms acceptances of all roles.
+func (g *Gate) Acceptances() ([]Acceptance, error) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	var acceptances []Acceptance
+	if err := g.storage.GetAllFrom(bucketName, &acceptances); err != nil {
+		return nil, err
+	}
+	return acceptances, nil
+}
+
+// Outdated returns acceptances of the versions older than the current terms version.
+func (g *Gate) Outdated() ([]Acceptance, error) {
+	acceptances, err := g.Acceptances()
+	if err != nil {
+		return nil, err
+	}
+
+	var outdated []Acceptance
+	for _, acceptance := range acceptances {
+		if g.outdated(acceptance.Version) {
+			outdated = append(outdated, acceptance)
+		}
+	}
+	return outdated, nil
+}
+
+// PublishOutdated prompts to accept the current terms version for every role which accepted an older one.
+func (g *Gate) PublishOutdated() error {
+	outdated, err := g.Outdated()
+	if err != nil {
+		return err
+	}
+
+	for _, acceptance := range outdated {
+		g.publish(acceptance)
+	}
+	return nil
+}
+
+// CheckPayments returns an error if payment operations are blocked until the current terms are accepted.
+func (g *Gate) CheckPayments() error {
+	outdated, err := g.Outdated()
+	if err != nil {
+		return err
+	}
+	if len(outdated) > 0 {
+		return fmt.Errorf("%w: %s terms version %s is accepted, but %s is current", ErrTermsOutdated, outdated[0].Role, outdated[0].Version, g.config.CurrentTermsVersion)
+	}
+	return nil
+}
+
+// Jurisdiction returns the configured jurisdiction, falling back to the given country.
+func (g *Gate) Jurisdiction(country string) string {
+	if g.config.Jurisdiction != "" {
+		return strings.ToUpper(g.config.Jurisdiction)
+	}
+	return strings.ToUpper(country)
+}
+
+// CheckService returns an error if the service type may not be started by the provider located in the country.
+func (g *Gate) CheckService(serviceType, country string) error {
+	accepted, err := g.acceptedVersion(RoleProvider)
+	if err != nil {
+		return err
+	}
+	if required := g.config.TermsVersion; required != "" && (accepted == "" || compareVersions(accepted, required) < 0) {
+		return fmt.Errorf("%w: provider terms version %s or later is required", ErrTermsNotAccepted, required)
+	}
+	if g.outdated(accepted) {
+		return fmt.Errorf("%w: provider terms version %s is accepted, but %s is current", ErrTermsOutdated, accepted, g.config.CurrentTermsVersion)
+	}
+
+	jurisdiction := g.Jurisdiction(country)
+	for _, restricted := range g.config.Restrictions[jurisdiction] {
+		if restricted == serviceType {
+			return fmt.Errorf("%w: %s is not allowed in %s", ErrServiceRestricted, serviceType, jurisdiction)
+		}
+	}
+	return nil
+}
+
+func (g *Gate) acceptedVersion(role string) (string, error) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	var acceptance Acceptance
+	err := g.storage.GetOneByField(bucketName, "Role", role, &acceptance)
+	if errors.Is(err, storage.ErrNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return acceptance.Version, nil
+}
+
+// outdated reports whether the accepted version is older than the current terms version.
+// Roles which have not accepted any version are not considered outdated.
+func (g *Gate) outdated(accepted string) bool {
+	return accepted != "" && g.config.CurrentTermsVersion != "" && compareVersions(accepted, g.config.CurrentTermsVersion) < 0
+}
+
+func (g *Gate) publish(acceptance Acceptance) {
+	if g.publisher == nil {
+		return
+	}
+
+	g.publisher.Publish(AppTopicTerms, AppEventTerms{
+		Role:                 acceptance.Role,
+		AcceptedVersion:      acceptance.Version,
+		CurrentVersion:       g.config.CurrentTermsVersion,
+		ReacceptanceRequired: g.outdated(acceptance.Version),
+	})
+}
@@ -0,0 +1,159 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package compliance
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mysteriumnetwork/node/core/storage/boltdb"
+)
+
+func newGate(t *testing.T, config Config) (*Gate, *mockPublisher) {
+	dir, err := os.MkdirTemp("", "complianceTest")
+	require.NoError(t, err)
+	db, err := boltdb.NewStorage(dir)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		db.Close()
+		os.RemoveAll(dir)
+	})
+
+	bus := &mockPublisher{}
+	return NewGate(db, bus, config), bus
+}
+
+func TestParseRestrictions(t *testing.T) {
+	restrictions, err := ParseRestrictions([]string{"us:scraping", " US:data_transfer ", "DE:scraping", ""})
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{
+		"US": {"scraping", "data_transfer"},
+		"DE": {"scraping"},
+	}, restrictions)
+
+	_, err = ParseRestrictions([]string{"scraping"})
+	assert.Error(t, err)
+	_, err = ParseRestrictions([]string{"US:"})
+	assert.Error(t, err)
+}
+
+func TestCompareVersions(t *testing.T) {
+	assert.Zero(t, compareVersions("0.0.27", "v0.0.27"))
+	assert.Zero(t, compareVersions("1.0", "1.0.0"))
+	assert.Negative(t, compareVersions("0.0.9", "0.0.27"))
+	assert.Positive(t, compareVersions("0.1.0", "0.0.27"))
+}
+
+func TestGate_Accept(t *testing.T) {
+	gate, _ := newGate(t, Config{})
+
+	_, err := gate.Accept("operator", "0.0.27")
+	assert.ErrorIs(t, err, ErrInvalidAcceptance)
+	_, err = gate.Accept(RoleProvider, "")
+	assert.ErrorIs(t, err, ErrInvalidAcceptance)
+
+	_, err = gate.Accept(RoleProvider, "0.0.26")
+	require.NoError(t, err)
+	_, err = gate.Accept(RoleProvider, "0.0.27")
+	require.NoError(t, err)
+	_, err = gate.Accept(RoleConsumer, "0.0.27")
+	require.NoError(t, err)
+
+	acceptances, err := gate.Acceptances()
+	require.NoError(t, err)
+	require.Len(t, acceptances, 2)
+	for _, acceptance := range acceptances {
+		assert.Equal(t, "0.0.27", acceptance.Version)
+		assert.False(t, acceptance.AcceptedAt.IsZero())
+	}
+}
+
+func TestGate_CheckServiceRequiresTerms(t *testing.T) {
+	gate, _ := newGate(t, Config{TermsVersion: "0.0.27"})
+
+	assert.ErrorIs(t, gate.CheckService("wireguard", "LT"), ErrTermsNotAccepted)
+
+	_, err := gate.Accept(RoleProvider, "0.0.26")
+	require.NoError(t, err)
+	assert.ErrorIs(t, gate.CheckService("wireguard", "LT"), ErrTermsNotAccepted)
+
+	_, err = gate.Accept(RoleProvider, "0.0.27")
+	require.NoError(t, err)
+	assert.NoError(t, gate.CheckService("wireguard", "LT"))
+}
+
+func TestGate_CheckServiceRestrictions(t *testing.T) {
+	restrictions := map[string][]string{"US": {"scraping"}}
+
+	gate, _ := newGate(t, Config{Restrictions: restrictions})
+	assert.ErrorIs(t, gate.CheckService("scraping", "us"), ErrServiceRestricted)
+	assert.NoError(t, gate.CheckService("wireguard", "US"))
+	assert.NoError(t, gate.CheckService("scraping", "LT"))
+
+	gate, _ = newGate(t, Config{Jurisdiction: "us", Restrictions: restrictions})
+	assert.Equal(t, "US", gate.Jurisdiction("LT"))
+	assert.ErrorIs(t, gate.CheckService("scraping", "LT"), ErrServiceRestricted)
+}
+
+func TestGate_ReacceptanceOfUpdatedTerms(t *testing.T) {
+	gate, bus := newGate(t, Config{CurrentTermsVersion: "0.0.28"})
+
+	assert.NoError(t, gate.CheckService("wireguard", "LT"), "roles without acceptance are not outdated")
+	assert.NoError(t, gate.CheckPayments())
+
+	require.NoError(t, gate.Import(RoleProvider, "0.0.27"))
+	require.NoError(t, gate.Import(RoleProvider, "0.0.26"), "import should not downgrade recorded acceptance")
+	assert.ErrorIs(t, gate.CheckService("wireguard", "LT"), ErrTermsOutdated)
+	assert.ErrorIs(t, gate.CheckPayments(), ErrTermsOutdated)
+
+	require.NoError(t, gate.PublishOutdated())
+	assert.Equal(t, AppEventTerms{
+		Role:                 RoleProvider,
+		AcceptedVersion:      "0.0.27",
+		CurrentVersion:       "0.0.28",
+		ReacceptanceRequired: true,
+	}, bus.last())
+
+	_, err := gate.Accept(RoleProvider, "0.0.28")
+	require.NoError(t, err)
+	assert.NoError(t, gate.CheckService("wireguard", "LT"))
+	assert.NoError(t, gate.CheckPayments())
+	assert.Equal(t, AppEventTerms{
+		Role:            RoleProvider,
+		AcceptedVersion: "0.0.28",
+		CurrentVersion:  "0.0.28",
+	}, bus.last())
+}
+
+type mockPublisher struct {
+	events []interface{}
+}
+
+func (m *mockPublisher) Publish(_ string, data interface{}) {
+	m.events = append(m.events, data)
+}
+
+func (m *mockPublisher) last() interface{} {
+	if len(m.events) == 0 {
+		return nil
+	}
+	return m.events[len(m.events)-1]
+}
@@ -0,0 +1,200 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package connection
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
+	"github.com/mysteriumnetwork/node/eventbus"
+)
+
+// maxBlockPeriodFactor limits how long a provider can be blocked, as a multiple of the base period.
+const maxBlockPeriodFactor = 16
+
+// BlockedProvider holds failure details of a provider.
+type BlockedProvider struct {
+	ProviderID   string
+	Failures     int
+	LastError    string
+	LastFailure  time.Time
+	BlockedUntil time.Time
+}
+
+// ProviderBlocklist temporarily excludes providers whose last connection attempts failed.
+type ProviderBlocklist struct {
+	failures int
+	period   time.Duration
+
+	lock      sync.Mutex
+	providers map[string]*BlockedProvider
+
+	now func() time.Time
+}
+
+// NewProviderBlocklist creates a blocklist which blocks a provider after given number of consecutive failures.
+// The block lasts for the given period, doubled on every further failure. Zero failures disables the blocklist.
+func NewProviderBlocklist(failures int, period time.Duration) *ProviderBlocklist {
+	return &ProviderBlocklist{
+		failures:  failures,
+		period:    period,
+		providers: make(map[string]*BlockedProvider),
+		now:       time.Now,
+	}
+}
+
+// Subscribe subscribes to relevant events of event bus.
+func (pb *ProviderBlocklist) Subscribe(bus eventbus.Subscriber) error {
+	return bus.SubscribeAsync(connectionstate.AppTopicConnectionAttempt, pb.handleAttempt)
+}
+
+// Failed records a failed connection attempt to the provider.
+func (pb *ProviderBlocklist) Failed(providerID, reason string) {
+	if pb.failures <= 0 {
+		return
+	}
+
+	pb.lock.Lock()
+	defer pb.lock.Unlock()
+
+	pb.prune()
+
+	provider, ok := pb.providers[providerID]
+	if !ok {
+		provider = &BlockedProvider{ProviderID: providerID}
+		pb.providers[providerID] = provider
+	}
+	provider.Failures++
+	provider.LastError = reason
+	provider.LastFailure = pb.now()
+
+	if provider.Failures >= pb.failures {
+		provider.BlockedUntil = provider.LastFailure.Add(pb.blockPeriod(provider.Failures - pb.failures))
+		log.Info().Msgf("Provider %s blocked until %s after %d failed connections", providerID, provider.BlockedUntil, provider.Failures)
+	}
+}
+
+// Busy skips the provider which rejected the attempt as overloaded until it asked to retry.
+// It is not counted as a failure, as the provider itself is healthy.
+func (pb *ProviderBlocklist) Busy(providerID, reason string, retryAfter time.Duration) {
+	pb.lock.Lock()
+	defer pb.lock.Unlock()
+
+	pb.prune()
+
+	provider, ok := pb.providers[providerID]
+	if !ok {
+		provider = &BlockedProvider{ProviderID: providerID}
+		pb.providers[providerID] = provider
+	}
+	provider.LastError = reason
+	if until := pb.now().Add(retryAfter); until.After(provider.BlockedUntil) {
+		provider.BlockedUntil = until
+	}
+	log.Info().Msgf("Provider %s is busy, skipped until %s", providerID, provider.BlockedUntil)
+}
+
+// Succeeded forgets failures of the provider.
+func (pb *ProviderBlocklist) Succeeded(providerID string) {
+	pb.Remove(providerID)
+}
+
+// Blocked checks whether the provider is currently excluded from selection.
+func (pb *ProviderBlocklist) Blocked(providerID string) bool {
+	pb.lock.Lock()
+	defer pb.lock.Unlock()
+
+	provider, ok := pb.providers[providerID]
+	return ok && pb.now().Before(provider.BlockedUntil)
+}
+
+// List returns providers with recorded failures, blocked ones first.
+func (pb *ProviderBlocklist) List() []BlockedProvider {
+	pb.lock.Lock()
+	defer pb.lock.Unlock()
+
+	pb.prune()
+
+	result := make([]BlockedProvider, 0, len(pb.providers))
+	for _, provider := range pb.providers {
+		result = append(result, *provider)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].BlockedUntil.After(result[j].BlockedUntil)
+	})
+
+	return result
+}
+
+// Remove forgets failures of the provider, returns false if nothing was recorded.
+func (pb *ProviderBlocklist) Remove(providerID string) bool {
+	pb.lock.Lock()
+	defer pb.lock.Unlock()
+
+	_, ok := pb.providers[providerID]
+	delete(pb.providers, providerID)
+	return ok
+}
+
+// Clear forgets failures of all providers.
+func (pb *ProviderBlocklist) Clear() {
+	pb.lock.Lock()
+	defer pb.lock.Unlock()
+
+	pb.providers = make(map[string]*BlockedProvider)
+}
+
+func (pb *ProviderBlocklist) handleAttempt(e connectionstate.AppEventConnectionAttempt) {
+	if e.Error == "" {
+		pb.Succeeded(e.ProviderID)
+		return
+	}
+	if e.RetryAfter > 0 {
+		pb.Busy(e.ProviderID, e.Error, e.RetryAfter)
+		return
+	}
+
+	pb.Failed(e.ProviderID, e.Error)
+}
+
+func (pb *ProviderBlocklist) blockPeriod(extraFailures int) time.Duration {
+	factor := 1
+	for i := 0; i < extraFailures && factor < maxBlockPeriodFactor; i++ {
+		factor *= 2
+	}
+
+	return time.Duration(factor) * pb.period
+}
+
+// prune decays failures which were not repeated for the longest block period,
+// busy providers without failures are forgotten as soon as their retry time passes.
+func (pb *ProviderBlocklist) prune() {
+	now := pb.now()
+	for id, provider := range pb.providers {
+		if !now.After(provider.BlockedUntil) {
+			continue
+		}
+		if provider.Failures == 0 || now.Sub(provider.LastFailure) > maxBlockPeriodFactor*pb.period {
+			delete(pb.providers, id)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package connection
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
+	"github.com/mysteriumnetwork/node/core/discovery/proposal"
+	"github.com/mysteriumnetwork/node/market"
+)
+
+func TestProviderBlocklist(t *testing.T) {
+	now := time.Now()
+	blocklist := NewProviderBlocklist(2, time.Minute)
+	blocklist.now = func() time.Time { return now }
+
+	blocklist.Failed("0x1", "connection refused")
+	assert.False(t, blocklist.Blocked("0x1"))
+
+	blocklist.handleAttempt(connectionstate.AppEventConnectionAttempt{ProviderID: "0x1", Error: "payment failed"})
+	assert.True(t, blocklist.Blocked("0x1"))
+
+	list := blocklist.List()
+	assert.Len(t, list, 1)
+	assert.Equal(t, 2, list[0].Failures)
+	assert.Equal(t, "payment failed", list[0].LastError)
+	assert.Equal(t, now.Add(time.Minute), list[0].BlockedUntil)
+
+	// Block expires, but a further failure blocks for twice as long.
+	now = now.Add(2 * time.Minute)
+	assert.False(t, blocklist.Blocked("0x1"))
+	blocklist.Failed("0x1", "connection refused")
+	assert.True(t, blocklist.Blocked("0x1"))
+	assert.Equal(t, now.Add(2*time.Minute), blocklist.List()[0].BlockedUntil)
+
+	// Failures decay when not repeated for the longest block period.
+	now = now.Add(maxBlockPeriodFactor*time.Minute + time.Second)
+	assert.Empty(t, blocklist.List())
+
+	blocklist.Failed("0x1", "connection refused")
+	blocklist.Failed("0x1", "connection refused")
+	blocklist.handleAttempt(connectionstate.AppEventConnectionAttempt{ProviderID: "0x1"})
+	assert.False(t, blocklist.Blocked("0x1"))
+	assert.Empty(t, blocklist.List())
+}
+
+func TestProviderBlocklist_Disabled(t *testing.T) {
+	blocklist := NewProviderBlocklist(0, time.Minute)
+
+	blocklist.Failed("0x1", "connection refused")
+	assert.False(t, blocklist.Blocked("0x1"))
+	assert.Empty(t, blocklist.List())
+}
+
+func TestProviderBlocklist_Busy(t *testing.T) {
+	now := time.Now()
+	blocklist := NewProviderBlocklist(2, time.Minute)
+	blocklist.now = func() time.Time { return now }
+
+	blocklist.handleAttempt(connectionstate.AppEventConnectionAttempt{ProviderID: "0x1", Error: "peer is busy", RetryAfter: 5 * time.Minute})
+	assert.True(t, blocklist.Blocked("0x1"))
+
+	list := blocklist.List()
+	assert.Len(t, list, 1)
+	assert.Equal(t, 0, list[0].Failures)
+	assert.Equal(t, now.Add(5*time.Minute), list[0].BlockedUntil)
+
+	// Busy rejection does not count towards failures.
+	blocklist.Failed("0x1", "connection refused")
+	assert.Equal(t, 1, blocklist.List()[0].Failures)
+
+	now = now.Add(5*time.Minute + time.Second)
+	assert.False(t, blocklist.Blocked("0x1"))
+
+	// Busy providers without failures are forgotten once they can be retried.
+	blocklist.Busy("0x2", "peer is busy", time.Minute)
+	now = now.Add(time.Minute + time.Second)
+	for _, provider := range blocklist.List() {
+		assert.NotEqual(t, "0x2", provider.ProviderID)
+	}
+}
+
+func TestFilteredProposals_SkipsBlocked(t *testing.T) {
+	blocklist := NewProviderBlocklist(1, time.Minute)
+	blocklist.Failed("0x1", "connection refused")
+
+	repo := &mockProposalRepository{proposals: []proposal.PricedServiceProposal{
+		{ServiceProposal: market.ServiceProposal{ProviderID: "0x1"}},
+		{ServiceProposal: market.ServiceProposal{ProviderID: "0x2"}},
+	}}
+
+	p, err := FilteredProposals(&proposal.Filter{}, "", repo, blocklist)()
+	assert.NoError(t, err)
+	assert.Equal(t, "0x2", p.ProviderID)
+
+	p, err = FilteredProposals(&proposal.Filter{ProviderIDs: []string{"0x1"}}, "", repo, blocklist)()
+	assert.NoError(t, err)
+	assert.Equal(t, "0x1", p.ProviderID)
+}
+
+func TestFilteredProposals_SkipsMaintenance(t *testing.T) {
+	soon := time.Now().Add(10 * time.Minute)
+	repo := &mockProposalRepository{proposals: []proposal.PricedServiceProposal{
+		{ServiceProposal: market.ServiceProposal{
+			ProviderID:  "0x1",
+			Maintenance: []market.MaintenanceWindow{{Start: soon, End: soon.Add(time.Hour)}},
+		}},
+		{ServiceProposal: market.ServiceProposal{ProviderID: "0x2"}},
+	}}
+
+	p, err := FilteredProposals(&proposal.Filter{}, "", repo, nil)()
+	assert.NoError(t, err)
+	assert.Equal(t, "0x2", p.ProviderID)
+
+	p, err = FilteredProposals(&proposal.Filter{ProviderID: "0x1"}, "", repo, nil)()
+	assert.NoError(t, err)
+	assert.Equal(t, "0x1", p.ProviderID)
+}
+
+type mockProposalRepository struct {
+	proposals []proposal.PricedServiceProposal
+}
+
+func (m *mockProposalRepository) Proposals(filter *proposal.Filter) ([]proposal.PricedServiceProposal, error) {
+	return m.proposals, nil
+}
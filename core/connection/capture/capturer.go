@@ -0,0 +1,311 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package capture records packet headers of a consumer session tunnel into
+// local pcap files for debugging MTU and retransmission issues. Payloads are
+// never written and the files never leave the device.
+package capture
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// DefaultMaxDuration is the longest capture allowed unless configured otherwise.
+	DefaultMaxDuration = 5 * time.Minute
+	// DefaultMaxSize is the largest capture file allowed unless configured otherwise.
+	DefaultMaxSize = 16 << 20
+
+	fileExt = ".pcap"
+)
+
+var (
+	// ErrRunning is returned when a capture is already in progress.
+	ErrRunning = errors.New("capture is already running")
+	// ErrNotRunning is returned when there is no capture in progress.
+	ErrNotRunning = errors.New("capture is not running")
+	// ErrNotFound is returned when the requested capture file does not exist.
+	ErrNotFound = errors.New("capture not found")
+)
+
+// Config configures the capturer.
+type Config struct {
+	// Dir is where capture files are stored.
+	Dir string
+	// MaxDuration limits the duration of a single capture.
+	MaxDuration time.Duration
+	// MaxSize limits the size in bytes of a single capture file.
+	MaxSize int64
+}
+
+// Info describes a capture.
+type Info struct {
+	Name      string
+	SessionID string
+	StartedAt time.Time
+	// StoppedAt is zero while the capture is running.
+	StoppedAt time.Time
+	Packets   int
+	Size      int64
+}
+
+// File describes a stored capture file.
+type File struct {
+	Name      string
+	Size      int64
+	CreatedAt time.Time
+}
+
+// Capturer records packet headers of a single session at a time.
+type Capturer struct {
+	cfg Config
+
+	active atomic.Bool
+	lock   sync.Mutex
+	run    *run
+	last   *Info
+}
+
+type run struct {
+	info  Info
+	file  *os.File
+	w     *bufio.Writer
+	timer *time.Timer
+}
+
+// NewCapturer returns a new capturer storing captures in the configured directory.
+func NewCapturer(cfg Config) *Capturer {
+	if cfg.MaxDuration <= 0 {
+		cfg.MaxDuration = DefaultMaxDuration
+	}
+	if cfg.MaxSize <= 0 {
+		cfg.MaxSize = DefaultMaxSize
+	}
+	return &Capturer{cfg: cfg}
+}
+
+// MaxDuration returns the longest capture allowed.
+func (c *Capturer) MaxDuration() time.Duration {
+	return c.cfg.MaxDuration
+}
+
+// Start starts capturing packet headers of the given session for the given duration,
+// which is limited by the configured maximum.
+func (c *Capturer) Start(sessionID string, duration time.Duration) (Info, error) {
+	if sessionID == "" {
+		return Info{}, errors.New("session ID is required")
+	}
+	if duration <= 0 || duration > c.cfg.MaxDuration {
+		duration = c.cfg.MaxDuration
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.run != nil {
+		return Info{}, ErrRunning
+	}
+
+	if err := os.MkdirAll(c.cfg.Dir, 0700); err != nil {
+		return Info{}, fmt.Errorf("could not create capture directory: %w", err)
+	}
+
+	now := time.Now().UTC()
+	name := fmt.Sprintf("%s_%d%s", sanitize(sessionID), now.UnixNano(), fileExt)
+	file, err := os.OpenFile(filepath.Join(c.cfg.Dir, name), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return Info{}, fmt.Errorf("could not create capture file: %w", err)
+	}
+
+	w := bufio.NewWriter(file)
+	if err := writeFileHeader(w); err != nil {
+		file.Close()
+		return Info{}, fmt.Errorf("could not write capture header: %w", err)
+	}
+
+	r := &run{
+		info: Info{Name: name, SessionID: sessionID, StartedAt: now, Size: fileHeaderLen},
+		file: file,
+		w:    w,
+	}
+	r.timer = time.AfterFunc(duration, func() {
+		c.stopRun(r)
+	})
+	c.run = r
+	c.active.Store(true)
+
+	log.Info().Msgf("Started capturing packet headers of session %s for %s into %s", sessionID, duration, name)
+	return r.info, nil
+}
+
+// Stop stops the running capture.
+func (c *Capturer) Stop() (Info, error) {
+	c.lock.Lock()
+	r := c.run
+	c.lock.Unlock()
+
+	if r == nil {
+		return Info{}, ErrNotRunning
+	}
+	return c.stopRun(r)
+}
+
+// Status returns the running capture or the last finished one.
+func (c *Capturer) Status() (Info, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.run != nil {
+		return c.run.info, true
+	}
+	if c.last != nil {
+		return *c.last, true
+	}
+	return Info{}, false
+}
+
+// List returns the stored capture files, newest first.
+func (c *Capturer) List() ([]File, error) {
+	entries, err := os.ReadDir(c.cfg.Dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]File, 0, len(entries))
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), fileExt) {
+			continue
+		}
+		fi, err := e.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, File{Name: e.Name(), Size: fi.Size(), CreatedAt: fi.ModTime()})
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].CreatedAt.After(files[j].CreatedAt)
+	})
+	return files, nil
+}
+
+// Open opens the stored capture file for reading.
+func (c *Capturer) Open(name string) (*os.File, error) {
+	if name != filepath.Base(name) || !strings.HasSuffix(name, fileExt) {
+		return nil, ErrNotFound
+	}
+
+	f, err := os.Open(filepath.Join(c.cfg.Dir, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return f, err
+}
+
+// Tap returns the packet tap of the given session's tunnel.
+func (c *Capturer) Tap(sessionID string) *Tap {
+	return &Tap{capturer: c, sessionID: sessionID}
+}
+
+func (c *Capturer) stopRun(r *run) (Info, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.run != r {
+		return Info{}, ErrNotRunning
+	}
+
+	r.timer.Stop()
+	c.active.Store(false)
+	c.run = nil
+
+	r.info.StoppedAt = time.Now().UTC()
+	err := r.w.Flush()
+	if cerr := r.file.Close(); err == nil {
+		err = cerr
+	}
+	info := r.info
+	c.last = &info
+
+	log.Info().Msgf("Stopped capturing packet headers of session %s, %d packets captured", info.SessionID, info.Packets)
+	if err != nil {
+		return info, fmt.Errorf("could not write capture file: %w", err)
+	}
+	return info, nil
+}
+
+func (c *Capturer) write(sessionID string, packet []byte) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	r := c.run
+	if r == nil || r.info.SessionID != sessionID {
+		return
+	}
+
+	capLen := headerLen(packet)
+	if r.info.Size+recordHeaderLen+int64(capLen) > c.cfg.MaxSize {
+		c.active.Store(false)
+		go c.stopRun(r)
+		return
+	}
+
+	if err := writeRecord(r.w, time.Now(), packet[:capLen], len(packet)); err != nil {
+		log.Warn().Err(err).Msg("Could not write captured packet")
+		c.active.Store(false)
+		go c.stopRun(r)
+		return
+	}
+	r.info.Packets++
+	r.info.Size += recordHeaderLen + int64(capLen)
+}
+
+// Tap passes packets of a single session's tunnel to the capturer.
+type Tap struct {
+	capturer  *Capturer
+	sessionID string
+}
+
+// Packet records the headers of the given IP packet if the session is being captured.
+func (t *Tap) Packet(packet []byte) {
+	if t == nil || !t.capturer.active.Load() {
+		return
+	}
+	t.capturer.write(t.sessionID, packet)
+}
+
+func sanitize(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' {
+			return r
+		}
+		return '-'
+	}, s)
+}
@@ -0,0 +1,131 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package capture
+
+import (
+	"encoding/binary"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func tcpPacket(options, payload int) []byte {
+	p := make([]byte, 20+20+options+payload)
+	p[0] = 0x45
+	p[9] = protoTCP
+	p[32] = byte((20+options)/4) << 4
+	return p
+}
+
+func TestHeaderLen(t *testing.T) {
+	fragment := tcpPacket(0, 100)
+	binary.BigEndian.PutUint16(fragment[6:8], 100)
+
+	udp6 := make([]byte, 40+8+100)
+	udp6[0] = 0x60
+	udp6[6] = protoUDP
+
+	tests := map[string]struct {
+		packet []byte
+		want   int
+	}{
+		"tcp":             {tcpPacket(0, 1000), 40},
+		"tcp options":     {tcpPacket(12, 1000), 52},
+		"udp ipv6":        {udp6, 48},
+		"fragment":        {fragment, 20},
+		"truncated":       {tcpPacket(0, 0)[:30], 30},
+		"unknown version": {[]byte{0x10, 1, 2, 3}, 0},
+		"empty":           {nil, 0},
+	}
+	for name, tt := range tests {
+		assert.Equal(t, tt.want, headerLen(tt.packet), name)
+	}
+}
+
+func TestCapturer_CapturesHeadersOfSession(t *testing.T) {
+	c := NewCapturer(Config{Dir: t.TempDir()})
+	tap := c.Tap("session-1")
+	other := c.Tap("session-2")
+
+	tap.Packet(tcpPacket(0, 100))
+
+	info, err := c.Start("session-1", time.Minute)
+	require.NoError(t, err)
+
+	_, err = c.Start("session-1", time.Minute)
+	assert.ErrorIs(t, err, ErrRunning)
+
+	tap.Packet(tcpPacket(0, 100))
+	tap.Packet(tcpPacket(12, 1000))
+	other.Packet(tcpPacket(0, 100))
+
+	info, err = c.Stop()
+	require.NoError(t, err)
+	assert.Equal(t, 2, info.Packets)
+	assert.Equal(t, int64(fileHeaderLen+2*recordHeaderLen+40+52), info.Size)
+	assert.False(t, info.StoppedAt.IsZero())
+
+	tap.Packet(tcpPacket(0, 100))
+	_, err = c.Stop()
+	assert.ErrorIs(t, err, ErrNotRunning)
+
+	files, err := c.List()
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, info.Name, files[0].Name)
+	assert.Equal(t, info.Size, files[0].Size)
+
+	f, err := c.Open(info.Name)
+	require.NoError(t, err)
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(pcapMagic), binary.LittleEndian.Uint32(data[0:4]))
+	assert.Equal(t, uint32(linkTypeRaw), binary.LittleEndian.Uint32(data[20:24]))
+	// first record keeps the headers only but reports the original length
+	assert.Equal(t, uint32(40), binary.LittleEndian.Uint32(data[32:36]))
+	assert.Equal(t, uint32(140), binary.LittleEndian.Uint32(data[36:40]))
+
+	_, err = c.Open("../" + info.Name)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestCapturer_StopsOnLimits(t *testing.T) {
+	c := NewCapturer(Config{Dir: t.TempDir(), MaxDuration: 50 * time.Millisecond, MaxSize: fileHeaderLen + recordHeaderLen + 40})
+	tap := c.Tap("session-1")
+
+	_, err := c.Start("session-1", time.Hour)
+	require.NoError(t, err)
+	tap.Packet(tcpPacket(0, 100))
+	tap.Packet(tcpPacket(0, 100))
+
+	assert.Eventually(t, func() bool {
+		info, ok := c.Status()
+		return ok && !info.StoppedAt.IsZero() && info.Packets == 1
+	}, time.Second, 10*time.Millisecond)
+
+	_, err = c.Start("session-1", time.Hour)
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		info, ok := c.Status()
+		return ok && !info.StoppedAt.IsZero()
+	}, time.Second, 10*time.Millisecond)
+}
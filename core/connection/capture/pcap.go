@@ -0,0 +1,117 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package capture
+
+import (
+	"encoding/binary"
+	"io"
+	"time"
+)
+
+const (
+	fileHeaderLen   = 24
+	recordHeaderLen = 16
+
+	pcapMagic = 0xa1b2c3d4
+	// linkTypeRaw means the records start with an IPv4 or IPv6 header.
+	linkTypeRaw = 101
+	snapLen     = 65535
+
+	protoICMP   = 1
+	protoTCP    = 6
+	protoUDP    = 17
+	protoICMPv6 = 58
+)
+
+func writeFileHeader(w io.Writer) error {
+	var h [fileHeaderLen]byte
+	binary.LittleEndian.PutUint32(h[0:4], pcapMagic)
+	binary.LittleEndian.PutUint16(h[4:6], 2)
+	binary.LittleEndian.PutUint16(h[6:8], 4)
+	binary.LittleEndian.PutUint32(h[16:20], snapLen)
+	binary.LittleEndian.PutUint32(h[20:24], linkTypeRaw)
+	_, err := w.Write(h[:])
+	return err
+}
+
+func writeRecord(w io.Writer, at time.Time, data []byte, origLen int) error {
+	var h [recordHeaderLen]byte
+	binary.LittleEndian.PutUint32(h[0:4], uint32(at.Unix()))
+	binary.LittleEndian.PutUint32(h[4:8], uint32(at.Nanosecond()/1000))
+	binary.LittleEndian.PutUint32(h[8:12], uint32(len(data)))
+	binary.LittleEndian.PutUint32(h[12:16], uint32(origLen))
+	if _, err := w.Write(h[:]); err != nil {
+		return err
+	}
+	_, err := w.Write(data)
+	return err
+}
+
+// headerLen returns the length of the IP and transport headers of the packet,
+// so that everything after them is left out of the capture.
+func headerLen(packet []byte) int {
+	if len(packet) == 0 {
+		return 0
+	}
+
+	var ipLen int
+	var proto byte
+	switch packet[0] >> 4 {
+	case 4:
+		if len(packet) < 20 {
+			return len(packet)
+		}
+		ipLen = int(packet[0]&0x0f) * 4
+		proto = packet[9]
+		if fragmentOffset := binary.BigEndian.Uint16(packet[6:8]) & 0x1fff; fragmentOffset != 0 {
+			return min(ipLen, len(packet))
+		}
+	case 6:
+		ipLen = 40
+		if len(packet) < ipLen {
+			return len(packet)
+		}
+		proto = packet[6]
+	default:
+		return 0
+	}
+	if ipLen > len(packet) {
+		return len(packet)
+	}
+
+	var l4Len int
+	switch proto {
+	case protoTCP:
+		l4Len = 20
+		if len(packet) >= ipLen+13 {
+			if offset := int(packet[ipLen+12]>>4) * 4; offset > l4Len {
+				l4Len = offset
+			}
+		}
+	case protoUDP, protoICMP, protoICMPv6:
+		l4Len = 8
+	}
+	return min(ipLen+l4Len, len(packet))
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
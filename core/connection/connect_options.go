@@ -19,6 +19,7 @@ package connection
 
 import (
 	"net"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 
@@ -35,6 +36,8 @@ type ConnectParams struct {
 	DNS DNSOption
 
 	ProxyPort int
+	// disconnect the session after no traffic was transferred for the given period, disabled if zero
+	InactivityTimeout time.Duration
 }
 
 // ConnectOptions represents the params we need to ensure a successful connection
@@ -48,4 +51,6 @@ type ConnectOptions struct {
 	ProviderNATConn *net.UDPConn
 	ChannelConn     *net.UDPConn
 	HermesID        common.Address
+	// KeepAliveInterval is tuned to the NAT mapping lifetime, zero if it was not measured.
+	KeepAliveInterval time.Duration
 }
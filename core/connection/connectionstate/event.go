@@ -25,6 +25,7 @@ import (
 	"github.com/mysteriumnetwork/node/core/discovery/proposal"
 	"github.com/mysteriumnetwork/node/core/location/locationstate"
 	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/market"
 	"github.com/mysteriumnetwork/node/session"
 )
 
@@ -36,6 +37,14 @@ const (
 	AppTopicConnectionStatistics = "Statistics"
 	// AppTopicConnectionSession represents the session lifetime changes
 	AppTopicConnectionSession = "Session"
+	// AppTopicConnectionInactivity represents the warning about upcoming disconnect of the inactive session
+	AppTopicConnectionInactivity = "Inactivity"
+	// AppTopicConnectionAttempt represents the outcome of an attempt to connect to a provider
+	AppTopicConnectionAttempt = "ConnectionAttempt"
+	// AppTopicSLABreach represents the breach of the SLA advertised by the provider
+	AppTopicSLABreach = "SLABreach"
+	// AppTopicProviderMaintenance represents the maintenance announced by the provider of the active session
+	AppTopicProviderMaintenance = "ProviderMaintenance"
 )
 
 // AppEventConnectionState is the struct we'll emit on a AppEventConnectionState topic event
@@ -69,6 +78,8 @@ const (
 	StateConnectionFailed = State("ConnectionFailed")
 	// StateOnHold means that underlying connection failed, but manager keeps it not removed to prevent traffic leaks.
 	StateOnHold = State("OnHold")
+	// StatePaused means that session is kept alive, but data flow and invoicing are suspended until resumed.
+	StatePaused = State("Paused")
 )
 
 // Status holds connection state, session id and proposal of the connection
@@ -80,6 +91,23 @@ type Status struct {
 	State            State
 	SessionID        session.ID
 	Proposal         proposal.PricedServiceProposal
+	Protocol         session.Protocol
+	// Peer is the node version and capabilities announced by the provider.
+	Peer session.Peer
+	// Mitigations lists the measures against traffic correlation enabled for the session.
+	Mitigations []string
+	// Online is the result of the last connectivity check through the tunnel, nil if it was not checked.
+	Online *OnlineStatus
+}
+
+// OnlineStatus is the result of the check that the internet is reachable through the tunnel.
+type OnlineStatus struct {
+	Online bool
+	// Succeeded is the number of check targets which responded, Quorum of them are required out of Total.
+	Succeeded int
+	Quorum    int
+	Total     int
+	CheckedAt time.Time
 }
 
 // Duration returns elapsed time from marked session start
@@ -102,6 +130,44 @@ const (
 type AppEventConnectionSession struct {
 	Status      string
 	SessionInfo Status
+	// DisconnectReason is set for the session end event only.
+	DisconnectReason session.DisconnectReason
+}
+
+// AppEventConnectionInactivity represents a warning that the session will be disconnected due to inactivity
+type AppEventConnectionInactivity struct {
+	UUID         string
+	SessionInfo  Status
+	IdleFor      time.Duration
+	DisconnectIn time.Duration
+}
+
+// AppEventSLABreach represents the breach of the SLA advertised by the provider.
+// Refund is zero when the provider has not confirmed the refund.
+type AppEventSLABreach struct {
+	UUID        string
+	SessionInfo Status
+	Reason      string
+	Refund      uint8
+}
+
+// AppEventProviderMaintenance represents the maintenance window announced by the provider.
+// Failover is set when the consumer is going to reconnect to another provider ahead of the window.
+type AppEventProviderMaintenance struct {
+	UUID        string
+	SessionInfo Status
+	Window      market.MaintenanceWindow
+	Failover    bool
+}
+
+// AppEventConnectionAttempt represents the outcome of an attempt to connect to a provider.
+// Error is empty when the attempt succeeded. RetryAfter is set when the provider rejected the attempt as busy.
+type AppEventConnectionAttempt struct {
+	UUID        string
+	ProviderID  string
+	ServiceType string
+	Error       string
+	RetryAfter  time.Duration
 }
 
 // AppEventConnectionStatistics represents a session statistics event
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/mysteriumnetwork/node/core/connection/trafficcategory"
 	"github.com/mysteriumnetwork/node/datasize"
 )
 
@@ -29,6 +30,8 @@ type Statistics struct {
 	At            time.Time
 	BytesSent     uint64
 	BytesReceived uint64
+	// Categories is a local only breakdown of the traffic by destination category, if enabled.
+	Categories trafficcategory.Breakdown
 }
 
 // Diff calculates the difference in bytes between the old stats and new.
@@ -0,0 +1,285 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package external
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/mysteriumnetwork/node/services/openvpn"
+	wg "github.com/mysteriumnetwork/node/services/wireguard"
+)
+
+const defaultOpenVPNPort = 1194
+
+// ErrUnsupportedServiceType indicates that endpoints of the given service type can not be imported.
+var ErrUnsupportedServiceType = errors.New("unsupported service type")
+
+// sessionConfig turns raw endpoint config into the session config understood by the connection of the service type.
+// Endpoint hosts are resolved on every call, so that endpoints with dynamic addresses keep working.
+func sessionConfig(serviceType, config string) ([]byte, error) {
+	switch serviceType {
+	case wg.ServiceType:
+		c, err := ParseWireGuard(config)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(c)
+	case openvpn.ServiceType:
+		c, err := ParseOpenVPN(config)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(c)
+	}
+	return nil, ErrUnsupportedServiceType
+}
+
+// ParseWireGuard parses wg-quick style config with a single peer.
+// Traffic is always routed through the tunnel, so allowed IPs of the peer are ignored.
+func ParseWireGuard(config string) (wg.ServiceConfig, error) {
+	var (
+		result   wg.ServiceConfig
+		section  string
+		peers    int
+		address  string
+		endpoint string
+		dnsIPs   []string
+	)
+
+	scanner := bufio.NewScanner(strings.NewReader(config))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.ToLower(strings.TrimSpace(line[1 : len(line)-1]))
+			if section == "peer" {
+				peers++
+			}
+			continue
+		}
+
+		name, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return result, fmt.Errorf("invalid line %q", line)
+		}
+		value = strings.TrimSpace(value)
+
+		switch section + "." + strings.ToLower(strings.TrimSpace(name)) {
+		case "interface.privatekey":
+			result.Consumer.PrivateKey = value
+		case "interface.address":
+			for _, addr := range splitList(value) {
+				if ip, _, err := net.ParseCIDR(addr); err == nil && ip.To4() != nil {
+					address = addr
+					break
+				}
+			}
+		case "interface.dns":
+			for _, dns := range splitList(value) {
+				if net.ParseIP(dns) != nil {
+					dnsIPs = append(dnsIPs, dns)
+				}
+			}
+		case "peer.publickey":
+			result.Provider.PublicKey = value
+		case "peer.endpoint":
+			endpoint = value
+		case "peer.presharedkey":
+			return result, errors.New("preshared keys are not supported")
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return result, err
+	}
+
+	switch {
+	case peers != 1:
+		return result, fmt.Errorf("exactly one peer is expected, got %d", peers)
+	case !validWireGuardKey(result.Consumer.PrivateKey):
+		return result, errors.New("invalid or missing interface private key")
+	case !validWireGuardKey(result.Provider.PublicKey):
+		return result, errors.New("invalid or missing peer public key")
+	case address == "":
+		return result, errors.New("interface IPv4 address is required")
+	case endpoint == "":
+		return result, errors.New("peer endpoint is required")
+	}
+
+	ip, ipnet, _ := net.ParseCIDR(address)
+	result.Consumer.IPAddress = *ipnet
+	result.Consumer.IPAddress.IP = ip
+	result.Consumer.DNSIPs = strings.Join(dnsIPs, ",")
+
+	udpAddr, err := net.ResolveUDPAddr("udp", endpoint)
+	if err != nil {
+		return result, fmt.Errorf("could not resolve peer endpoint: %w", err)
+	}
+	result.Provider.Endpoint = *udpAddr
+
+	return result, nil
+}
+
+// ParseOpenVPN parses client config with inline certificates and keys.
+// Only servers using tls-crypt and client certificate authentication are supported,
+// the connection also expects the server to accept the cipher suites used with the network providers.
+func ParseOpenVPN(config string) (openvpn.VPNConfig, error) {
+	var (
+		result = openvpn.VPNConfig{RemoteProtocol: "udp"}
+		host   string
+		dnsIPs []string
+		block  string
+		blocks = make(map[string]string)
+	)
+
+	scanner := bufio.NewScanner(strings.NewReader(config))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if block != "" {
+			if line == "</"+block+">" {
+				block = ""
+			} else if line != "" && !strings.HasPrefix(line, "#") {
+				blocks[block] += line + "\n"
+			}
+			continue
+		}
+
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "<") && strings.HasSuffix(line, ">") {
+			block = line[1 : len(line)-1]
+			continue
+		}
+
+		fields := strings.Fields(line)
+		switch fields[0] {
+		case "remote":
+			// Only the first remote is used, the connection does not fail over.
+			if host != "" {
+				continue
+			}
+			if len(fields) < 2 {
+				return result, errors.New("remote host is missing")
+			}
+			host = fields[1]
+			if len(fields) > 2 {
+				port, err := strconv.Atoi(fields[2])
+				if err != nil {
+					return result, fmt.Errorf("invalid remote port: %w", err)
+				}
+				result.RemotePort = port
+			}
+			if len(fields) > 3 {
+				result.RemoteProtocol = openVPNProtocol(fields[3])
+			}
+		case "port":
+			if len(fields) < 2 {
+				return result, errors.New("port is missing")
+			}
+			port, err := strconv.Atoi(fields[1])
+			if err != nil {
+				return result, fmt.Errorf("invalid port: %w", err)
+			}
+			if result.RemotePort == 0 {
+				result.RemotePort = port
+			}
+		case "proto":
+			if len(fields) < 2 {
+				return result, errors.New("protocol is missing")
+			}
+			result.RemoteProtocol = openVPNProtocol(fields[1])
+		case "dhcp-option":
+			if len(fields) > 2 && fields[1] == "DNS" {
+				dnsIPs = append(dnsIPs, fields[2])
+			}
+		case "auth-user-pass":
+			return result, errors.New("username and password authentication is not supported")
+		case "tls-auth":
+			return result, errors.New("tls-auth is not supported, tls-crypt is required")
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return result, err
+	}
+	if block != "" {
+		return result, fmt.Errorf("inline block <%s> is not closed", block)
+	}
+
+	if host == "" {
+		return result, errors.New("remote is required")
+	}
+	if result.RemotePort == 0 {
+		result.RemotePort = defaultOpenVPNPort
+	}
+	result.CACertificate = blocks["ca"]
+	result.TLSPresharedKey = blocks["tls-crypt"]
+	result.ClientCertificate = blocks["cert"]
+	result.ClientKey = blocks["key"]
+	result.DNSIPs = strings.Join(dnsIPs, ",")
+	if result.ClientCertificate == "" || result.ClientKey == "" {
+		return result, errors.New("inline client certificate and key are required")
+	}
+
+	ipAddr, err := net.ResolveIPAddr("ip4", host)
+	if err != nil {
+		return result, fmt.Errorf("could not resolve remote: %w", err)
+	}
+	result.RemoteIP = ipAddr.IP.String()
+
+	return result, openvpn.NewDefaultValidator().IsValid(result)
+}
+
+func openVPNProtocol(proto string) string {
+	switch {
+	case strings.HasPrefix(proto, "tcp"):
+		return "tcp"
+	case strings.HasPrefix(proto, "udp"):
+		return "udp"
+	}
+	return proto
+}
+
+func validWireGuardKey(key string) bool {
+	k, err := base64.StdEncoding.DecodeString(key)
+	return err == nil && len(k) == 32
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,61 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package external
+
+import (
+	"strings"
+	"time"
+
+	"github.com/mysteriumnetwork/node/market"
+)
+
+// providerIDPrefix marks provider IDs of the proposals created for external endpoints.
+const providerIDPrefix = "external:"
+
+// Endpoint is a VPN endpoint imported from outside of the network.
+// Its raw config, including the private keys, is kept in the node database.
+type Endpoint struct {
+	ID          string `storm:"id"`
+	Name        string
+	ServiceType string
+	Country     string
+	Config      string
+	CreatedAt   time.Time
+}
+
+// ProviderID returns the provider ID of the proposal created for the endpoint.
+func (e Endpoint) ProviderID() string {
+	return providerIDPrefix + e.ID
+}
+
+// Proposal returns a local proposal which allows connecting to the endpoint as to any other provider.
+func (e Endpoint) Proposal() market.ServiceProposal {
+	return market.NewProposal(e.ProviderID(), e.ServiceType, market.NewProposalOpts{
+		Location: &market.Location{Country: e.Country},
+		Contacts: []market.Contact{{Type: market.ContactTypeExternal}},
+	})
+}
+
+// IsExternal checks if provider ID belongs to an external endpoint.
+func IsExternal(providerID string) bool {
+	return strings.HasPrefix(providerID, providerIDPrefix)
+}
+
+func endpointID(providerID string) string {
+	return strings.TrimPrefix(providerID, providerIDPrefix)
+}
@@ -0,0 +1,191 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package external
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/mysteriumnetwork/node/core/discovery/proposal"
+	"github.com/mysteriumnetwork/node/core/storage"
+	"github.com/mysteriumnetwork/node/market"
+)
+
+const bucketName = "external-endpoints"
+
+var (
+	// ErrNotFound indicates that there is no endpoint with the given ID.
+	ErrNotFound = errors.New("external endpoint not found")
+	// ErrInvalidConfig indicates that endpoint config can not be used for connecting.
+	ErrInvalidConfig = errors.New("invalid endpoint config")
+)
+
+type persistentStorage interface {
+	Store(bucket string, data interface{}) error
+	GetAllFrom(bucket string, data interface{}) error
+	GetOneByField(bucket string, fieldName string, key interface{}, to interface{}) error
+	Delete(bucket string, data interface{}) error
+}
+
+// Repository keeps imported endpoints and provides them as proposals, so that they are connected
+// to by the same connection manager as the network providers.
+type Repository struct {
+	lock    sync.Mutex
+	storage persistentStorage
+	now     func() time.Time
+}
+
+// NewRepository creates a new external endpoint repository.
+func NewRepository(storage persistentStorage) *Repository {
+	return &Repository{
+		storage: storage,
+		now:     time.Now,
+	}
+}
+
+// Add validates the raw config of the given service type and stores it as a new endpoint.
+func (r *Repository) Add(name, serviceType, country, config string) (Endpoint, error) {
+	if _, err := sessionConfig(serviceType, config); err != nil {
+		return Endpoint{}, fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	}
+
+	id, err := uuid.NewV4()
+	if err != nil {
+		return Endpoint{}, err
+	}
+
+	endpoint := Endpoint{
+		ID:          id.String(),
+		Name:        name,
+		ServiceType: serviceType,
+		Country:     country,
+		Config:      config,
+		CreatedAt:   r.now().UTC(),
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if err := r.storage.Store(bucketName, &endpoint); err != nil {
+		return Endpoint{}, err
+	}
+	return endpoint, nil
+}
+
+// List returns all imported endpoints.
+func (r *Repository) List() ([]Endpoint, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	var endpoints []Endpoint
+	if err := r.storage.GetAllFrom(bucketName, &endpoints); err != nil {
+		return nil, err
+	}
+	return endpoints, nil
+}
+
+// Remove deletes the endpoint with the given ID.
+func (r *Repository) Remove(id string) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	endpoint, err := r.get(id)
+	if err != nil {
+		return err
+	}
+	return r.storage.Delete(bucketName, &endpoint)
+}
+
+// SessionConfig returns the session config of the endpoint the proposal was created for.
+func (r *Repository) SessionConfig(providerID string) ([]byte, error) {
+	r.lock.Lock()
+	endpoint, err := r.get(endpointID(providerID))
+	r.lock.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	return sessionConfig(endpoint.ServiceType, endpoint.Config)
+}
+
+// Proposal returns the proposal of an imported endpoint.
+func (r *Repository) Proposal(id market.ProposalID) (*market.ServiceProposal, error) {
+	if !IsExternal(id.ProviderID) {
+		return nil, ErrNotFound
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	endpoint, err := r.get(endpointID(id.ProviderID))
+	if err != nil {
+		return nil, err
+	}
+	if endpoint.ServiceType != id.ServiceType {
+		return nil, ErrNotFound
+	}
+
+	p := endpoint.Proposal()
+	return &p, nil
+}
+
+// Proposals returns proposals of the imported endpoints matching the filter.
+func (r *Repository) Proposals(filter *proposal.Filter) ([]market.ServiceProposal, error) {
+	endpoints, err := r.List()
+	if err != nil {
+		return nil, err
+	}
+
+	proposals := make([]market.ServiceProposal, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		p := endpoint.Proposal()
+		if filter == nil || filter.Matches(p) {
+			proposals = append(proposals, p)
+		}
+	}
+	return proposals, nil
+}
+
+// Countries returns number of imported endpoints per country matching the filter.
+func (r *Repository) Countries(filter *proposal.Filter) (map[string]int, error) {
+	proposals, err := r.Proposals(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	countries := make(map[string]int)
+	for _, p := range proposals {
+		if p.Location.Country != "" {
+			countries[p.Location.Country]++
+		}
+	}
+	return countries, nil
+}
+
+func (r *Repository) get(id string) (Endpoint, error) {
+	var endpoint Endpoint
+	err := r.storage.GetOneByField(bucketName, "ID", id, &endpoint)
+	if errors.Is(err, storage.ErrNotFound) {
+		return endpoint, ErrNotFound
+	}
+	return endpoint, err
+}
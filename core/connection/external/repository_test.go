@@ -0,0 +1,121 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package external
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mysteriumnetwork/node/core/discovery/proposal"
+	"github.com/mysteriumnetwork/node/core/storage/boltdb"
+	"github.com/mysteriumnetwork/node/market"
+	wg "github.com/mysteriumnetwork/node/services/wireguard"
+)
+
+func newRepository(t *testing.T) *Repository {
+	dir, err := os.MkdirTemp("", "externalEndpointsTest")
+	require.NoError(t, err)
+	db, err := boltdb.NewStorage(dir)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		db.Close()
+		os.RemoveAll(dir)
+	})
+
+	return NewRepository(db)
+}
+
+func TestRepository_Add(t *testing.T) {
+	repo := newRepository(t)
+
+	_, err := repo.Add("home", wg.ServiceType, "LT", "[Interface]")
+	assert.ErrorIs(t, err, ErrInvalidConfig)
+	_, err = repo.Add("home", "scraping", "LT", newWireGuardConfig(t, ""))
+	assert.ErrorIs(t, err, ErrInvalidConfig)
+
+	endpoint, err := repo.Add("home", wg.ServiceType, "LT", newWireGuardConfig(t, ""))
+	require.NoError(t, err)
+
+	endpoints, err := repo.List()
+	require.NoError(t, err)
+	require.Len(t, endpoints, 1)
+	assert.Equal(t, endpoint.ID, endpoints[0].ID)
+	assert.Equal(t, "home", endpoints[0].Name)
+}
+
+func TestRepository_Proposals(t *testing.T) {
+	repo := newRepository(t)
+	endpoint, err := repo.Add("home", wg.ServiceType, "LT", newWireGuardConfig(t, ""))
+	require.NoError(t, err)
+
+	proposals, err := repo.Proposals(nil)
+	require.NoError(t, err)
+	require.Len(t, proposals, 1)
+	assert.Equal(t, endpoint.ProviderID(), proposals[0].ProviderID)
+	assert.True(t, proposals[0].IsExternal())
+	assert.True(t, IsExternal(proposals[0].ProviderID))
+
+	proposals, err = repo.Proposals(&proposal.Filter{ServiceType: "openvpn"})
+	require.NoError(t, err)
+	assert.Len(t, proposals, 0)
+
+	countries, err := repo.Countries(nil)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"LT": 1}, countries)
+
+	p, err := repo.Proposal(market.ProposalID{ProviderID: endpoint.ProviderID(), ServiceType: wg.ServiceType})
+	require.NoError(t, err)
+	assert.Equal(t, endpoint.ProviderID(), p.ProviderID)
+
+	_, err = repo.Proposal(market.ProposalID{ProviderID: "0x1", ServiceType: wg.ServiceType})
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestRepository_SessionConfig(t *testing.T) {
+	repo := newRepository(t)
+	endpoint, err := repo.Add("home", wg.ServiceType, "", newWireGuardConfig(t, ""))
+	require.NoError(t, err)
+
+	raw, err := repo.SessionConfig(endpoint.ProviderID())
+	require.NoError(t, err)
+
+	var config wg.ServiceConfig
+	require.NoError(t, json.Unmarshal(raw, &config))
+	assert.NotEmpty(t, config.Consumer.PrivateKey)
+	assert.Equal(t, "127.0.0.1:51820", config.Provider.Endpoint.String())
+}
+
+func TestRepository_Remove(t *testing.T) {
+	repo := newRepository(t)
+	endpoint, err := repo.Add("home", wg.ServiceType, "", newWireGuardConfig(t, ""))
+	require.NoError(t, err)
+
+	require.NoError(t, repo.Remove(endpoint.ID))
+	assert.ErrorIs(t, repo.Remove(endpoint.ID), ErrNotFound)
+
+	_, err = repo.SessionConfig(endpoint.ProviderID())
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	endpoints, err := repo.List()
+	require.NoError(t, err)
+	assert.Len(t, endpoints, 0)
+}
@@ -0,0 +1,85 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package connection
+
+import (
+	"time"
+
+	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
+)
+
+type inactivityAction int
+
+const (
+	inactivityNone inactivityAction = iota
+	inactivityWarn
+	inactivityDisconnect
+)
+
+// inactivityTracker keeps track of the last time the traffic has been transferred through the connection.
+type inactivityTracker struct {
+	timeout    time.Duration
+	warnBefore time.Duration
+
+	lastBytes  uint64
+	lastActive time.Time
+	warned     bool
+}
+
+func newInactivityTracker(timeout, warnBefore time.Duration, now time.Time) *inactivityTracker {
+	if warnBefore > timeout/2 {
+		warnBefore = timeout / 2
+	}
+
+	return &inactivityTracker{
+		timeout:    timeout,
+		warnBefore: warnBefore,
+		lastActive: now,
+	}
+}
+
+// update takes the latest connection statistics and decides what should be done with the connection.
+func (t *inactivityTracker) update(stats connectionstate.Statistics, now time.Time) inactivityAction {
+	total := stats.BytesSent + stats.BytesReceived
+	if total != t.lastBytes {
+		t.lastBytes = total
+		t.lastActive = now
+		t.warned = false
+		return inactivityNone
+	}
+
+	idle := t.idle(now)
+	switch {
+	case idle >= t.timeout:
+		return inactivityDisconnect
+	case idle >= t.timeout-t.warnBefore && !t.warned:
+		t.warned = true
+		return inactivityWarn
+	}
+	return inactivityNone
+}
+
+// touch marks the connection as active at the given time.
+func (t *inactivityTracker) touch(now time.Time) {
+	t.lastActive = now
+	t.warned = false
+}
+
+func (t *inactivityTracker) idle(now time.Time) time.Duration {
+	return now.Sub(t.lastActive)
+}
@@ -0,0 +1,65 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package connection
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
+)
+
+func TestInactivityTracker_Update(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tracker := newInactivityTracker(10*time.Minute, time.Minute, start)
+
+	idle := connectionstate.Statistics{}
+	active := connectionstate.Statistics{BytesReceived: 100}
+
+	assert.Equal(t, inactivityNone, tracker.update(idle, start.Add(5*time.Minute)))
+	assert.Equal(t, inactivityWarn, tracker.update(idle, start.Add(9*time.Minute)))
+	assert.Equal(t, inactivityNone, tracker.update(idle, start.Add(9*time.Minute+30*time.Second)))
+
+	// traffic resets the timer and the warning
+	assert.Equal(t, inactivityNone, tracker.update(active, start.Add(9*time.Minute+40*time.Second)))
+	assert.Equal(t, inactivityNone, tracker.update(active, start.Add(15*time.Minute)))
+	assert.Equal(t, inactivityWarn, tracker.update(active, start.Add(19*time.Minute)))
+	assert.Equal(t, inactivityDisconnect, tracker.update(active, start.Add(20*time.Minute)))
+}
+
+func TestInactivityTracker_WarningIsCappedByTimeout(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tracker := newInactivityTracker(time.Minute, 5*time.Minute, start)
+
+	assert.Equal(t, inactivityNone, tracker.update(connectionstate.Statistics{}, start.Add(20*time.Second)))
+	assert.Equal(t, inactivityWarn, tracker.update(connectionstate.Statistics{}, start.Add(30*time.Second)))
+	assert.Equal(t, inactivityDisconnect, tracker.update(connectionstate.Statistics{}, start.Add(time.Minute)))
+}
+
+func TestInactivityTracker_Touch(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tracker := newInactivityTracker(10*time.Minute, time.Minute, start)
+
+	assert.Equal(t, inactivityWarn, tracker.update(connectionstate.Statistics{}, start.Add(9*time.Minute)))
+
+	tracker.touch(start.Add(30 * time.Minute))
+	assert.Equal(t, inactivityNone, tracker.update(connectionstate.Statistics{}, start.Add(35*time.Minute)))
+	assert.Equal(t, inactivityWarn, tracker.update(connectionstate.Statistics{}, start.Add(39*time.Minute)))
+}
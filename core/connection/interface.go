@@ -24,6 +24,7 @@ import (
 
 	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
 	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/session"
 )
 
 // ConsumerConfig are the parameters used for the initiation of connection
@@ -39,6 +40,20 @@ type Connection interface {
 	Statistics() (connectionstate.Statistics, error)
 }
 
+// KeyRotator is implemented by connections able to re-negotiate tunnel keys during the session.
+type KeyRotator interface {
+	// NewRotationKey generates a new key to offer to the provider and returns its public part.
+	NewRotationKey() (publicKey string, err error)
+	// RotateKeys switches the tunnel to the generated key and the given provider public key.
+	RotateKeys(providerPublicKey string) error
+}
+
+// Roamer is implemented by connections which have to rebind tunnel sockets once the host switches networks.
+type Roamer interface {
+	// Roam rebinds the tunnel to the current network of the host keeping the tunnel itself intact.
+	Roam() error
+}
+
 // StateChannel is the channel we receive state change events on
 type StateChannel chan connectionstate.State
 
@@ -52,10 +67,20 @@ type Manager interface {
 	Stats() connectionstate.Statistics
 	// Disconnect closes established connection, reports error if no connection
 	Disconnect() error
+	// DisconnectWithReason closes established connection and reports the reason to provider
+	DisconnectWithReason(reason session.DisconnectReason) error
 	// CheckChannel checks if current session channel is alive, returns error on failed keep-alive ping
 	CheckChannel(context.Context) error
 	// Reconnect reconnects current session
 	Reconnect()
+	// Pause suspends data flow and invoicing of the established connection without tearing it down
+	Pause() error
+	// Resume restores data flow and invoicing of the paused connection
+	Resume() error
+	// Roam moves the established connection to the current network of the host without tearing it down
+	Roam() error
+	// LastTrace returns the timings of the last connection attempt, false if there was none
+	LastTrace() (Trace, bool)
 }
 
 // MultiManager interface provides methods to manage connection
@@ -64,12 +89,24 @@ type MultiManager interface {
 	Connect(consumerID identity.Identity, hermesID common.Address, proposal ProposalLookup, params ConnectParams) error
 	// Status queries current status of connection
 	Status(n int) connectionstate.Status
+	// List returns IDs of the established connections
+	List() []int
 	// Stats provides connection statistics information.
 	Stats(n int) connectionstate.Statistics
 	// Disconnect closes established connection, reports error if no connection
 	Disconnect(n int) error
+	// DisconnectWithReason closes established connection and reports the reason to provider
+	DisconnectWithReason(n int, reason session.DisconnectReason) error
 	// CheckChannel checks if current session channel is alive, returns error on failed keep-alive ping
 	CheckChannel(context.Context) error
 	// Reconnect reconnects current session
 	Reconnect(n int)
+	// Pause suspends data flow and invoicing of the established connection without tearing it down
+	Pause(n int) error
+	// Resume restores data flow and invoicing of the paused connection
+	Resume(n int) error
+	// Roam moves the established connection to the current network of the host without tearing it down
+	Roam(n int) error
+	// LastTrace returns the timings of the last connection attempt, false if there was none
+	LastTrace(n int) (Trace, bool)
 }
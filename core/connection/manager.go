@@ -23,6 +23,8 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"math/rand"
+	"net"
 	"sync"
 	"time"
 
@@ -32,6 +34,7 @@ import (
 
 	"github.com/mysteriumnetwork/node/config"
 	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
+	"github.com/mysteriumnetwork/node/core/connection/onlinecheck"
 	"github.com/mysteriumnetwork/node/core/discovery/proposal"
 	"github.com/mysteriumnetwork/node/core/ip"
 	"github.com/mysteriumnetwork/node/core/location"
@@ -40,10 +43,13 @@ import (
 	"github.com/mysteriumnetwork/node/firewall"
 	"github.com/mysteriumnetwork/node/identity"
 	"github.com/mysteriumnetwork/node/market"
+	"github.com/mysteriumnetwork/node/metadata"
+	natprobe "github.com/mysteriumnetwork/node/nat/behavior"
 	"github.com/mysteriumnetwork/node/p2p"
 	"github.com/mysteriumnetwork/node/pb"
 	"github.com/mysteriumnetwork/node/session"
 	"github.com/mysteriumnetwork/node/session/connectivity"
+	"github.com/mysteriumnetwork/node/session/sla"
 	"github.com/mysteriumnetwork/node/trace"
 )
 
@@ -66,6 +72,10 @@ var (
 	ErrInsufficientBalance = errors.New("insufficient balance")
 	// ErrUnlockRequired indicates that the consumer identity has not been unlocked yet
 	ErrUnlockRequired = errors.New("unlock required")
+	// ErrConnectionNotActive indicates that action applied to manager expects established connection (i.e. pause)
+	ErrConnectionNotActive = errors.New("connection is not established")
+	// ErrUnsupportedByExternal indicates that action requires a provider session which external endpoints do not have
+	ErrUnsupportedByExternal = errors.New("action is not supported by external endpoints")
 )
 
 // IPCheckConfig contains common params for connection ip check.
@@ -81,10 +91,69 @@ type KeepAliveConfig struct {
 	MaxSendErrCount int
 }
 
+// OnlineChecker checks whether the internet is reachable through the tunnel.
+type OnlineChecker interface {
+	Check(ctx context.Context) onlinecheck.Result
+}
+
+// OnlineCheckConfig contains options of the periodic check that the internet is reachable through the tunnel.
+type OnlineCheckConfig struct {
+	// Checker performs the check, it is disabled if nil.
+	Checker  OnlineChecker
+	Interval time.Duration
+	// MaxFailures is the number of failed checks in a row after which the connection is put on hold
+	// for auto reconnect to restore it, the failures are only reported if zero.
+	MaxFailures int
+}
+
+// Names of the traffic mitigations reported in connection status.
+const (
+	MitigationRandomizedPorts = "randomized-ports"
+	MitigationKeepAliveJitter = "keepalive-jitter"
+	MitigationControlPadding  = "control-padding"
+)
+
+// TrafficMitigations holds the measures against traffic correlation applied to every session.
+type TrafficMitigations struct {
+	// RandomizePorts is set when p2p dialer picks local ports from the whole unprivileged range.
+	RandomizePorts bool
+	// KeepAliveJitter randomizes the delay between keepalive pings.
+	KeepAliveJitter bool
+	// PadControl is set when p2p dialer pads small control messages.
+	PadControl bool
+}
+
+// Names returns names of the enabled mitigations, nil if none are enabled.
+func (t TrafficMitigations) Names() []string {
+	var names []string
+	if t.RandomizePorts {
+		names = append(names, MitigationRandomizedPorts)
+	}
+	if t.KeepAliveJitter {
+		names = append(names, MitigationKeepAliveJitter)
+	}
+	if t.PadControl {
+		names = append(names, MitigationControlPadding)
+	}
+	return names
+}
+
 // Config contains common configuration options for connection manager.
 type Config struct {
 	IPCheck   IPCheckConfig
 	KeepAlive KeepAliveConfig
+	// Mitigations against traffic correlation, reported in connection status.
+	Mitigations TrafficMitigations
+	// OnlineCheck verifies the internet is reachable through the tunnel.
+	OnlineCheck OnlineCheckConfig
+	// InactivityWarning is how long before the inactivity disconnect the warning event is published.
+	InactivityWarning time.Duration
+	// KeyRotation is how often tunnel keys are re-negotiated with the provider, disabled if zero.
+	KeyRotation time.Duration
+	// SLAWindow is the period over which the session throughput is measured against the provider SLA.
+	SLAWindow time.Duration
+	// RoamingCheck is how often the local address is checked to move the session to a new network, disabled if zero.
+	RoamingCheck time.Duration
 }
 
 // DefaultConfig returns default params.
@@ -99,6 +168,8 @@ func DefaultConfig() Config {
 			SendTimeout:     5 * time.Second,
 			MaxSendErrCount: 3,
 		},
+		InactivityWarning: time.Minute,
+		SLAWindow:         sla.DefaultWindow,
 	}
 }
 
@@ -120,6 +191,17 @@ type PriceGetter interface {
 	GetCurrentPrice(nodeType string, country string) (market.Price, error)
 }
 
+// SessionReconciler compares the session accounting of both session sides.
+type SessionReconciler interface {
+	Accounting(sessionID session.ID) (session.Accounting, bool)
+	Reconcile(sessionID session.ID, peer session.Accounting) error
+}
+
+// ExternalEndpoints provides session configs of the endpoints imported from outside of the network.
+type ExternalEndpoints interface {
+	SessionConfig(providerID string) ([]byte, error)
+}
+
 type validator interface {
 	Validate(chainID int64, consumerID identity.Identity, p market.Price) error
 }
@@ -144,7 +226,10 @@ type connectionManager struct {
 	statsReportInterval  time.Duration
 	validator            validator
 	p2pDialer            p2p.Dialer
+	reconciler           SessionReconciler
+	external             ExternalEndpoints
 	timeGetter           TimeGetter
+	routeSource          func(remote net.IP) (net.IP, error)
 
 	// These are populated by Connect at runtime.
 	ctx                    context.Context
@@ -166,9 +251,18 @@ type connectionManager struct {
 	discoLock      sync.Mutex
 	connectOptions ConnectOptions
 
+	reasonLock       sync.Mutex
+	disconnectReason session.DisconnectReason
+
 	activeConnection Connection
 	statsTracker     statsTracker
 
+	attemptLock sync.RWMutex
+	lastAttempt *connectAttempt
+
+	keepAliveLock     sync.RWMutex
+	keepAliveInterval time.Duration
+
 	uuid string
 }
 
@@ -183,6 +277,8 @@ func NewManager(
 	statsReportInterval time.Duration,
 	validator validator,
 	p2pDialer p2p.Dialer,
+	reconciler SessionReconciler,
+	external ExternalEndpoints,
 	preReconnect, postReconnect func(),
 ) *connectionManager {
 	uuid, err := uuid.NewV4()
@@ -203,13 +299,17 @@ func NewManager(
 		statsReportInterval:  statsReportInterval,
 		validator:            validator,
 		p2pDialer:            p2pDialer,
+		reconciler:           reconciler,
+		external:             external,
 		timeGetter:           time.Now,
+		routeSource:          routeSourceIP,
 		preReconnect:         preReconnect,
 		postReconnect:        postReconnect,
 		uuid:                 uuid.String(),
 	}
 
 	m.eventBus.SubscribeAsync(connectionstate.AppTopicConnectionState, m.reconnectOnHold)
+	m.eventBus.SubscribeAsync(natprobe.AppTopicKeepAliveTuned, m.handleKeepAliveTuned)
 
 	return m
 }
@@ -221,17 +321,19 @@ func (m *connectionManager) chainID() int64 {
 func (m *connectionManager) Connect(consumerID identity.Identity, hermesID common.Address, proposalLookup ProposalLookup, params ConnectParams) (err error) {
 	var sessionID session.ID
 
-	proposal, err := proposalLookup()
-	if err != nil {
-		return fmt.Errorf("failed to lookup proposal: %w", err)
-	}
-
-	tracer := trace.NewTracer("Consumer whole Connect")
+	attempt := newConnectAttempt("Consumer whole Connect", m.timeGetter())
+	tracer := attempt.tracer
 	defer func() {
 		traceResult := tracer.Finish(m.eventBus, string(sessionID))
+		attempt.finish(sessionID, err, m.timeGetter())
 		log.Debug().Msgf("Consumer connection trace: %s", traceResult)
 	}()
 
+	proposal, err := m.lookupProposal(attempt, proposalLookup)
+	if err != nil {
+		return fmt.Errorf("failed to lookup proposal: %w", err)
+	}
+
 	// make sure cache is cleared when connect terminates at any stage as part of disconnect
 	// we assume that IPResolver might be used / cache IP before connect
 	m.addCleanup(func() error {
@@ -242,32 +344,39 @@ func (m *connectionManager) Connect(consumerID identity.Identity, hermesID commo
 	if m.Status().State != connectionstate.NotConnected {
 		return ErrAlreadyExists
 	}
+	m.setLastAttempt(attempt)
 
 	prc := m.priceFromProposal(*proposal)
 
-	err = m.validator.Validate(m.chainID(), consumerID, prc)
-	if err != nil {
-		return err
+	// External endpoints are paid for outside of the network.
+	if !proposal.IsExternal() {
+		err = m.validator.Validate(m.chainID(), consumerID, prc)
+		if err != nil {
+			return err
+		}
 	}
 
 	m.ctxLock.Lock()
 	m.ctx, m.cancel = context.WithCancel(context.Background())
 	m.ctxLock.Unlock()
 
+	m.setDisconnectReason(session.DisconnectReasonUnknown)
 	m.statusConnecting(consumerID, hermesID, *proposal)
 	defer func() {
 		if err != nil {
 			log.Err(err).Msg("Connect failed, disconnecting")
 			m.disconnect()
 		}
+		m.publishAttempt(*proposal, err)
 	}()
 
 	m.connectOptions = ConnectOptions{
-		ConsumerID:     consumerID,
-		HermesID:       hermesID,
-		Proposal:       *proposal,
-		ProposalLookup: proposalLookup,
-		Params:         params,
+		ConsumerID:        consumerID,
+		HermesID:          hermesID,
+		Proposal:          *proposal,
+		ProposalLookup:    proposalLookup,
+		Params:            params,
+		KeepAliveInterval: m.tunedKeepAliveInterval(),
 	}
 
 	m.activeConnection, err = m.newConnection(proposal.ServiceType)
@@ -282,12 +391,12 @@ func (m *connectionManager) Connect(consumerID identity.Identity, hermesID commo
 
 	originalPublicIP := m.getPublicIP()
 
+	traceTunnel := tracer.StartStage(TraceStageTunnel)
 	err = m.startConnection(m.currentCtx(), m.activeConnection, m.activeConnection.Start, m.connectOptions, tracer)
-	if err != nil {
-		return m.handleStartError(sessionID, err)
+	if err == nil {
+		err = m.waitForConnectedState(m.activeConnection.State())
 	}
-
-	err = m.waitForConnectedState(m.activeConnection.State())
+	tracer.EndStage(traceTunnel)
 	if err != nil {
 		return m.handleStartError(sessionID, err)
 	}
@@ -303,6 +412,13 @@ func (m *connectionManager) Connect(consumerID identity.Identity, hermesID commo
 
 	go m.consumeConnectionStates(m.activeConnection.State())
 	go m.checkSessionIP(m.channel, m.connectOptions.ConsumerID, m.connectOptions.SessionID, originalPublicIP)
+	go m.onlineCheckLoop()
+	if timeout := m.connectOptions.Params.InactivityTimeout; timeout > 0 {
+		go m.inactivityLoop(timeout)
+	}
+	if proposal.SLA != nil {
+		go m.slaLoop(*proposal.SLA, m.timeGetter().Sub(m.Status().StartedAt))
+	}
 
 	return nil
 }
@@ -310,25 +426,33 @@ func (m *connectionManager) Connect(consumerID identity.Identity, hermesID commo
 func (m *connectionManager) autoReconnect() (err error) {
 	var sessionID session.ID
 
-	tracer := trace.NewTracer("Consumer whole autoReconnect")
+	attempt := newConnectAttempt("Consumer whole autoReconnect", m.timeGetter())
+	tracer := attempt.tracer
+	m.setLastAttempt(attempt)
 	defer func() {
 		traceResult := tracer.Finish(m.eventBus, string(sessionID))
+		attempt.finish(sessionID, err, m.timeGetter())
 		log.Debug().Msgf("Consumer connection trace: %s", traceResult)
 	}()
 
-	proposal, err := m.connectOptions.ProposalLookup()
+	proposal, err := m.lookupProposal(attempt, m.connectOptions.ProposalLookup)
 	if err != nil {
 		return fmt.Errorf("failed to lookup proposal: %w", err)
 	}
 
 	m.connectOptions.Proposal = *proposal
+	defer func() {
+		m.publishAttempt(*proposal, err)
+	}()
 
 	sessionID, err = m.initSession(tracer, m.priceFromProposal(m.connectOptions.Proposal))
 	if err != nil {
 		return err
 	}
 
+	traceTunnel := tracer.StartStage(TraceStageTunnel)
 	err = m.startConnection(m.currentCtx(), m.activeConnection, m.activeConnection.Reconnect, m.connectOptions, tracer)
+	tracer.EndStage(traceTunnel)
 	if err != nil {
 		return m.handleStartError(sessionID, err)
 	}
@@ -336,6 +460,48 @@ func (m *connectionManager) autoReconnect() (err error) {
 	return nil
 }
 
+func (m *connectionManager) lookupProposal(attempt *connectAttempt, proposalLookup ProposalLookup) (*proposal.PricedServiceProposal, error) {
+	traceLookup := attempt.tracer.StartStage(TraceStageProposalLookup)
+	defer attempt.tracer.EndStage(traceLookup)
+
+	proposal, err := proposalLookup()
+	if err != nil {
+		return nil, err
+	}
+	attempt.setProposal(*proposal)
+	return proposal, nil
+}
+
+func (m *connectionManager) setLastAttempt(attempt *connectAttempt) {
+	m.attemptLock.Lock()
+	defer m.attemptLock.Unlock()
+
+	m.lastAttempt = attempt
+}
+
+// LastTrace returns the trace of the last connection attempt, false if there was none.
+func (m *connectionManager) LastTrace() (Trace, bool) {
+	m.attemptLock.RLock()
+	attempt := m.lastAttempt
+	m.attemptLock.RUnlock()
+
+	if attempt == nil {
+		return Trace{}, false
+	}
+	return attempt.snapshot(), true
+}
+
+// traceFirstByte records the first byte received through the tunnel of the last connection attempt.
+func (m *connectionManager) traceFirstByte() {
+	m.attemptLock.RLock()
+	attempt := m.lastAttempt
+	m.attemptLock.RUnlock()
+
+	if attempt != nil {
+		attempt.firstByte(m.timeGetter())
+	}
+}
+
 func (m *connectionManager) priceFromProposal(proposal proposal.PricedServiceProposal) market.Price {
 	p := market.Price{
 		PricePerHour: proposal.Price.PricePerHour,
@@ -359,6 +525,10 @@ func (m *connectionManager) priceFromProposal(proposal proposal.PricedServicePro
 }
 
 func (m *connectionManager) initSession(tracer *trace.Tracer, prc market.Price) (sessionID session.ID, err error) {
+	if m.connectOptions.Proposal.IsExternal() {
+		return m.initExternalSession()
+	}
+
 	err = m.createP2PChannel(m.connectOptions, tracer)
 	if err != nil {
 		return sessionID, fmt.Errorf("could not create p2p channel during connect: %w", err)
@@ -367,7 +537,9 @@ func (m *connectionManager) initSession(tracer *trace.Tracer, prc market.Price)
 	m.connectOptions.ProviderNATConn = m.channel.ServiceConn()
 	m.connectOptions.ChannelConn = m.channel.Conn()
 
+	tracePayment := tracer.StartStage(TraceStagePaymentSetup)
 	paymentSession, err := m.paymentLoop(m.connectOptions, prc)
+	tracer.EndStage(tracePayment)
 	if err != nil {
 		return sessionID, err
 	}
@@ -375,14 +547,35 @@ func (m *connectionManager) initSession(tracer *trace.Tracer, prc market.Price)
 	sessionDTO, err := m.createP2PSession(m.activeConnection, m.connectOptions, tracer, prc)
 	sessionID = session.ID(sessionDTO.GetID())
 	if err != nil {
-		m.sendSessionStatus(m.channel, m.connectOptions.ConsumerID, sessionID, connectivity.StatusSessionEstablishmentFailed, err)
+		// Busy provider rejected the request before creating a session, there is nothing to report.
+		var busy *p2p.BusyError
+		if !errors.As(err, &busy) {
+			m.sendSessionStatus(m.channel, m.connectOptions.ConsumerID, sessionID, connectivity.StatusSessionEstablishmentFailed, err)
+		}
 		return sessionID, err
 	}
 
 	traceStart := tracer.StartStage("Consumer session creation (start)")
-	go m.keepAliveLoop(m.channel, sessionID)
+	go m.keepAliveLoop(m.channel, sessionID, m.connectOptions.KeepAliveInterval)
+	m.handleMaintenance(m.channel, sessionID)
+	if m.config.KeyRotation > 0 {
+		go m.keyRotationLoop(m.channel, m.activeConnection, m.connectOptions.ConsumerID, sessionID)
+	}
+	if m.config.RoamingCheck > 0 {
+		go m.roamingLoop(m.channel, sessionID)
+	}
+	protocol := session.LocalProtocol().Negotiate(session.Protocol{
+		Version:      sessionDTO.GetProtocolVersion(),
+		Capabilities: session.Capability(sessionDTO.GetCapabilities()),
+	})
 	m.setStatus(func(status *connectionstate.Status) {
 		status.SessionID = sessionID
+		status.Protocol = protocol
+		status.Peer = session.Peer{
+			Version:      sessionDTO.GetNodeVersion(),
+			Capabilities: session.Capability(sessionDTO.GetSupportedCapabilities()),
+		}
+		status.Mitigations = m.config.Mitigations.Names()
 	})
 	m.publishSessionCreate(sessionID)
 	paymentSession.SetSessionID(string(sessionID))
@@ -394,6 +587,36 @@ func (m *connectionManager) initSession(tracer *trace.Tracer, prc market.Price)
 	return sessionID, nil
 }
 
+// initExternalSession starts a local session with an endpoint imported from outside of the network.
+// There is no provider to negotiate with, so p2p channel, payments, keep-alive and key rotation are skipped.
+func (m *connectionManager) initExternalSession() (sessionID session.ID, err error) {
+	if m.external == nil {
+		return sessionID, ErrUnsupportedServiceType
+	}
+
+	sessionConfig, err := m.external.SessionConfig(m.connectOptions.Proposal.ProviderID)
+	if err != nil {
+		return sessionID, fmt.Errorf("could not get external endpoint config: %w", err)
+	}
+
+	uid, err := uuid.NewV4()
+	if err != nil {
+		return sessionID, err
+	}
+	sessionID = session.ID(uid.String())
+
+	m.channel = nil
+	m.setStatus(func(status *connectionstate.Status) {
+		status.SessionID = sessionID
+	})
+	m.publishSessionCreate(sessionID)
+
+	m.connectOptions.SessionID = sessionID
+	m.connectOptions.SessionConfig = sessionConfig
+
+	return sessionID, nil
+}
+
 func (m *connectionManager) handleStartError(sessionID session.ID, err error) error {
 	if errors.Is(err, context.Canceled) {
 		return ErrConnectionCancelled
@@ -404,7 +627,9 @@ func (m *connectionManager) handleStartError(sessionID session.ID, err error) er
 	m.publishStateEvent(connectionstate.StateConnectionFailed)
 
 	log.Info().Err(err).Msg("Cancelling connection initiation: ")
-	m.Cancel()
+	// Failed start is not a user request, so provider gets no particular reason.
+	m.statusCanceled()
+	logDisconnectError(m.DisconnectWithReason(session.DisconnectReasonUnknown))
 	return err
 }
 
@@ -448,8 +673,69 @@ func (m *connectionManager) checkSessionIP(channel p2p.Channel, consumerID ident
 	}
 }
 
+// onlineCheckLoop periodically checks that the internet is reachable through the tunnel and reports the result
+// in connection status. Connection is put on hold after too many failed checks, if auto reconnect is enabled.
+func (m *connectionManager) onlineCheckLoop() {
+	cfg := m.config.OnlineCheck
+	if cfg.Checker == nil || cfg.Interval <= 0 {
+		return
+	}
+	// Traffic does not go through the tunnel by default in these modes.
+	if config.GetBool(config.FlagProxyMode) || config.GetBool(config.FlagDVPNMode) {
+		return
+	}
+
+	ctx := m.currentCtx()
+	var failures int
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(cfg.Interval):
+			if m.Status().State != connectionstate.Connected {
+				failures = 0
+				continue
+			}
+
+			checkCtx, cancel := context.WithTimeout(ctx, cfg.Interval)
+			res := cfg.Checker.Check(checkCtx)
+			cancel()
+			if ctx.Err() != nil {
+				return
+			}
+
+			m.setStatus(func(status *connectionstate.Status) {
+				status.Online = &connectionstate.OnlineStatus{
+					Online:    res.Online,
+					Succeeded: res.Succeeded,
+					Quorum:    res.Quorum,
+					Total:     len(res.Targets),
+					CheckedAt: res.CheckedAt,
+				}
+			})
+			if res.Online {
+				failures = 0
+				continue
+			}
+
+			failures++
+			log.Warn().Msgf("Connectivity check failed, %d of %d required targets responded. Failures in a row: %d", res.Succeeded, res.Quorum, failures)
+			if cfg.MaxFailures > 0 && failures >= cfg.MaxFailures && config.GetBool(config.FlagAutoReconnect) {
+				log.Warn().Msg("Internet is not reachable through the tunnel, reconnecting")
+				failures = 0
+				m.statusOnHold()
+			}
+		}
+	}
+}
+
 // sendSessionStatus sends session connectivity status to other peer.
 func (m *connectionManager) sendSessionStatus(channel p2p.ChannelSender, consumerID identity.Identity, sessionID session.ID, code connectivity.StatusCode, errDetails error) error {
+	// Sessions with external endpoints have no peer to report to.
+	if channel == nil {
+		return nil
+	}
+
 	var errDetailsMsg string
 	if errDetails != nil {
 		errDetailsMsg = errDetails.Error()
@@ -503,7 +789,7 @@ func (m *connectionManager) paymentLoop(opts ConnectOptions, price market.Price)
 			if config.GetBool(config.FlagKeepConnectedOnFail) {
 				m.statusOnHold()
 			} else {
-				err = m.Disconnect()
+				err = m.DisconnectWithReason(session.DisconnectReasonLowBalance)
 				if err != nil {
 					log.Error().Err(err).Msg("Could not disconnect gracefully")
 				}
@@ -542,7 +828,7 @@ func (m *connectionManager) cleanAfterDisconnect() {
 }
 
 func (m *connectionManager) createP2PChannel(opts ConnectOptions, tracer *trace.Tracer) error {
-	trace := tracer.StartStage("Consumer P2P channel creation")
+	trace := tracer.StartStage(TraceStageTraversal)
 	defer tracer.EndStage(trace)
 
 	contactDef, err := p2p.ParseContact(opts.Proposal.Contacts)
@@ -582,7 +868,7 @@ func (m *connectionManager) addCleanup(fn func() error) {
 }
 
 func (m *connectionManager) createP2PSession(c Connection, opts ConnectOptions, tracer *trace.Tracer, requestedPrice market.Price) (*pb.SessionResponse, error) {
-	trace := tracer.StartStage("Consumer session creation")
+	trace := tracer.StartStage(TraceStageSession)
 	defer tracer.EndStage(trace)
 
 	sessionCreateConfig, err := c.GetConfig()
@@ -608,8 +894,11 @@ func (m *connectionManager) createP2PSession(c Connection, opts ConnectOptions,
 				PerHour: requestedPrice.PricePerHour.Bytes(),
 			},
 		},
-		ProposalID: opts.Proposal.ID,
-		Config:     config,
+		ProposalID:      opts.Proposal.ID,
+		Config:          config,
+		ProtocolVersion: session.ProtocolVersion,
+		Capabilities:    uint64(session.LocalCapabilities),
+		NodeVersion:     metadata.VersionAsString(),
 	}
 	log.Debug().Msgf("Sending P2P message to %q: %s", p2p.TopicSessionCreate, sessionRequest.String())
 	ctx, cancel := context.WithTimeout(m.currentCtx(), 20*time.Second)
@@ -643,9 +932,18 @@ func (m *connectionManager) createP2PSession(c Connection, opts ConnectOptions,
 		log.Trace().Msg("Cleaning: requesting session destroy")
 		defer log.Trace().Msg("Cleaning: requesting session destroy DONE")
 
+		protocol := session.LocalProtocol().Negotiate(session.Protocol{
+			Version:      sessionResponse.GetProtocolVersion(),
+			Capabilities: session.Capability(sessionResponse.GetCapabilities()),
+		})
+		if protocol.Has(session.CapabilityReconciliation) {
+			m.reconcileSession(channel, opts.ConsumerID, session.ID(sessionResponse.GetID()))
+		}
+
 		sessionDestroy := &pb.SessionInfo{
 			ConsumerID: opts.ConsumerID.Address,
 			SessionID:  sessionResponse.GetID(),
+			Reason:     string(m.getDisconnectReason()),
 		}
 
 		log.Debug().Msgf("Sending P2P message to %q: %s", p2p.TopicSessionDestroy, sessionDestroy.String())
@@ -662,6 +960,56 @@ func (m *connectionManager) createP2PSession(c Connection, opts ConnectOptions,
 	return &sessionResponse, nil
 }
 
+// reconcileSession exchanges session accounting with the provider so that both sides could detect discrepancies.
+func (m *connectionManager) reconcileSession(channel p2p.Channel, consumerID identity.Identity, sessionID session.ID) {
+	own, ok := m.reconciler.Accounting(sessionID)
+	if !ok {
+		log.Warn().Msgf("No accounting found for session %s, skipping reconciliation", sessionID)
+		return
+	}
+
+	tokens := "0"
+	if own.Tokens != nil {
+		tokens = own.Tokens.String()
+	}
+	sr := &pb.SessionReconciliation{
+		ConsumerID:   consumerID.Address,
+		SessionID:    string(sessionID),
+		DataSent:     own.DataSent,
+		DataReceived: own.DataReceived,
+		Tokens:       tokens,
+	}
+
+	log.Debug().Msgf("Sending P2P message to %q: %s", p2p.TopicSessionReconcile, sr.String())
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+	res, err := channel.Send(ctx, p2p.TopicSessionReconcile, p2p.ProtoMessage(sr))
+	if err != nil {
+		log.Warn().Err(err).Msgf("Could not reconcile session %s", sessionID)
+		return
+	}
+
+	var peer pb.SessionReconciliation
+	if err := res.UnmarshalProto(&peer); err != nil {
+		log.Warn().Err(err).Msg("Could not unmarshal session reconciliation reply to proto")
+		return
+	}
+	peerTokens, ok := new(big.Int).SetString(peer.GetTokens(), 10)
+	if !ok {
+		log.Warn().Msgf("Invalid tokens value in session reconciliation reply: %q", peer.GetTokens())
+		return
+	}
+
+	err = m.reconciler.Reconcile(sessionID, session.Accounting{
+		DataSent:     peer.GetDataSent(),
+		DataReceived: peer.GetDataReceived(),
+		Tokens:       peerTokens,
+	})
+	if err != nil {
+		log.Warn().Err(err).Msgf("Could not reconcile session %s", sessionID)
+	}
+}
+
 func (m *connectionManager) publishSessionCreate(sessionID session.ID) {
 	sessionInfo := m.Status()
 	// avoid printing IP address in logs
@@ -681,8 +1029,9 @@ func (m *connectionManager) publishSessionCreate(sessionID session.ID) {
 		sessionInfo.ConsumerLocation.IP = ""
 
 		m.eventBus.Publish(connectionstate.AppTopicConnectionSession, connectionstate.AppEventConnectionSession{
-			Status:      connectionstate.SessionEndedStatus,
-			SessionInfo: sessionInfo,
+			Status:           connectionstate.SessionEndedStatus,
+			SessionInfo:      sessionInfo,
+			DisconnectReason: m.getDisconnectReason(),
 		})
 		return nil
 	})
@@ -783,6 +1132,12 @@ func (m *connectionManager) statusDisconnecting() {
 	})
 }
 
+func (m *connectionManager) statusPaused() {
+	m.setStatus(func(status *connectionstate.Status) {
+		status.State = connectionstate.StatePaused
+	})
+}
+
 func (m *connectionManager) statusCanceled() {
 	m.setStatus(func(status *connectionstate.Status) {
 		status.State = connectionstate.Canceled
@@ -801,17 +1156,147 @@ func (m *connectionManager) Cancel() {
 }
 
 func (m *connectionManager) Disconnect() error {
+	return m.DisconnectWithReason(session.DisconnectReasonUserRequest)
+}
+
+// DisconnectWithReason closes established connection and tells provider why the session was ended.
+func (m *connectionManager) DisconnectWithReason(reason session.DisconnectReason) error {
 	if m.Status().State == connectionstate.NotConnected {
 		return ErrNoConnection
 	}
 
+	m.setDisconnectReason(reason)
 	m.statusDisconnecting()
 	m.disconnect()
 
 	return nil
 }
 
+func (m *connectionManager) setDisconnectReason(reason session.DisconnectReason) {
+	m.reasonLock.Lock()
+	defer m.reasonLock.Unlock()
+
+	m.disconnectReason = reason
+}
+
+func (m *connectionManager) getDisconnectReason() session.DisconnectReason {
+	m.reasonLock.Lock()
+	defer m.reasonLock.Unlock()
+
+	return m.disconnectReason
+}
+
+// Pause asks provider to suspend data flow and invoicing of the session, while keeping the session,
+// p2p channel and traversal state alive for a quick resume.
+func (m *connectionManager) Pause() error {
+	status := m.Status()
+	switch status.State {
+	case connectionstate.StatePaused:
+		return nil
+	case connectionstate.NotConnected:
+		return ErrNoConnection
+	case connectionstate.Connected:
+	default:
+		return ErrConnectionNotActive
+	}
+	if status.Proposal.IsExternal() {
+		return ErrUnsupportedByExternal
+	}
+
+	if err := m.sendSessionPause(p2p.TopicSessionPause, status.ConsumerID, status.SessionID); err != nil {
+		return fmt.Errorf("could not pause session: %w", err)
+	}
+
+	m.statusPaused()
+	return nil
+}
+
+// Resume asks provider to restore data flow and invoicing of the paused session.
+func (m *connectionManager) Resume() error {
+	status := m.Status()
+	switch status.State {
+	case connectionstate.Connected:
+		return nil
+	case connectionstate.NotConnected:
+		return ErrNoConnection
+	case connectionstate.StatePaused:
+	default:
+		return ErrConnectionNotActive
+	}
+
+	if err := m.sendSessionPause(p2p.TopicSessionResume, status.ConsumerID, status.SessionID); err != nil {
+		return fmt.Errorf("could not resume session: %w", err)
+	}
+
+	m.statusConnected()
+	return nil
+}
+
+func (m *connectionManager) sendSessionPause(topic string, consumerID identity.Identity, sessionID session.ID) error {
+	msg := &pb.SessionInfo{
+		ConsumerID: consumerID.Address,
+		SessionID:  string(sessionID),
+	}
+
+	log.Debug().Msgf("Sending P2P message to %q: %s", topic, msg.String())
+	ctx, cancel := context.WithTimeout(m.currentCtx(), 10*time.Second)
+	defer cancel()
+	_, err := m.channel.Send(ctx, topic, p2p.ProtoMessage(msg))
+	return err
+}
+
+// channelRebinder is implemented by p2p channels able to reopen their connection on the current network.
+type channelRebinder interface {
+	Rebind() error
+}
+
+// Roam moves the established session to the current network of the host without tearing it down.
+// P2P channel and tunnel are rebound first, so that the provider could be asked to switch to the new consumer address.
+// Payments keep going over the same channel, so payment state is preserved.
+func (m *connectionManager) Roam() error {
+	status := m.Status()
+	switch status.State {
+	case connectionstate.NotConnected:
+		return ErrNoConnection
+	case connectionstate.Connected, connectionstate.StatePaused:
+	default:
+		return ErrConnectionNotActive
+	}
+	if status.Proposal.IsExternal() {
+		return ErrUnsupportedByExternal
+	}
+
+	if rebinder, ok := m.channel.(channelRebinder); ok {
+		if err := rebinder.Rebind(); err != nil {
+			return fmt.Errorf("could not rebind p2p channel: %w", err)
+		}
+	}
+	if roamer, ok := m.activeConnection.(Roamer); ok {
+		if err := roamer.Roam(); err != nil {
+			return fmt.Errorf("could not rebind tunnel: %w", err)
+		}
+	}
+
+	msg := &pb.SessionInfo{
+		ConsumerID: status.ConsumerID.Address,
+		SessionID:  string(status.SessionID),
+	}
+
+	log.Debug().Msgf("Sending P2P message to %q: %s", p2p.TopicSessionRoam, msg.String())
+	ctx, cancel := context.WithTimeout(m.currentCtx(), 20*time.Second)
+	defer cancel()
+	if _, err := m.channel.Send(ctx, p2p.TopicSessionRoam, p2p.ProtoMessage(msg)); err != nil {
+		return fmt.Errorf("could not roam session: %w", err)
+	}
+
+	log.Info().Msgf("Session moved to the new network. SessionID=%s", status.SessionID)
+	return nil
+}
+
 func (m *connectionManager) CheckChannel(ctx context.Context) error {
+	if status := m.Status(); status.Proposal.IsExternal() {
+		return nil
+	}
 	if err := m.sendKeepAlivePing(ctx, m.channel, m.Status().SessionID); err != nil {
 		return fmt.Errorf("keep alive ping failed: %w", err)
 	}
@@ -875,7 +1360,10 @@ func (m *connectionManager) onStateChanged(state connectionstate.State) {
 	// React just to certain stains from connection. Because disconnect happens in connectionWaiter
 	switch state {
 	case connectionstate.Connected:
-		m.statusConnected()
+		// Tunnel reports being up, but the session stays paused until resumed explicitly.
+		if m.Status().State != connectionstate.StatePaused {
+			m.statusConnected()
+		}
 	case connectionstate.Reconnecting:
 		m.statusReconnecting()
 	}
@@ -942,7 +1430,46 @@ func (m *connectionManager) publishStateEvent(state connectionstate.State) {
 	})
 }
 
-func (m *connectionManager) keepAliveLoop(channel p2p.Channel, sessionID session.ID) {
+func (m *connectionManager) publishAttempt(p proposal.PricedServiceProposal, err error) {
+	// Cancellation is requested by the user, it says nothing about the provider.
+	if errors.Is(err, ErrConnectionCancelled) || errors.Is(err, context.Canceled) {
+		return
+	}
+
+	event := connectionstate.AppEventConnectionAttempt{
+		UUID:        m.uuid,
+		ProviderID:  p.ProviderID,
+		ServiceType: p.ServiceType,
+	}
+	if err != nil {
+		event.Error = err.Error()
+	}
+	var busy *p2p.BusyError
+	if errors.As(err, &busy) {
+		event.RetryAfter = busy.RetryAfter
+	}
+	m.eventBus.Publish(connectionstate.AppTopicConnectionAttempt, event)
+}
+
+func (m *connectionManager) handleKeepAliveTuned(ev natprobe.AppEventKeepAliveTuned) {
+	m.keepAliveLock.Lock()
+	defer m.keepAliveLock.Unlock()
+	m.keepAliveInterval = ev.Interval
+}
+
+func (m *connectionManager) tunedKeepAliveInterval() time.Duration {
+	m.keepAliveLock.RLock()
+	defer m.keepAliveLock.RUnlock()
+	return m.keepAliveInterval
+}
+
+// keepAliveLoop pings the provider over p2p channel, as often as the tuned interval requires if it is known.
+func (m *connectionManager) keepAliveLoop(channel p2p.Channel, sessionID session.ID, tunedInterval time.Duration) {
+	sendInterval := m.config.KeepAlive.SendInterval
+	if tunedInterval > 0 {
+		sendInterval = tunedInterval
+	}
+
 	// Register handler for handling p2p keep alive pings from provider.
 	channel.Handle(p2p.TopicKeepAlive, func(c p2p.Context) error {
 		var ping pb.P2PKeepAlivePing
@@ -961,7 +1488,7 @@ func (m *connectionManager) keepAliveLoop(channel p2p.Channel, sessionID session
 		case <-m.currentCtx().Done():
 			log.Debug().Msgf("Stopping p2p keepalive: %v", m.currentCtx().Err())
 			return
-		case <-time.After(m.config.KeepAlive.SendInterval):
+		case <-time.After(m.keepAliveDelay(sendInterval)):
 			ctx, cancel := context.WithTimeout(context.Background(), m.config.KeepAlive.SendTimeout)
 			if err := m.sendKeepAlivePing(ctx, channel, sessionID); err != nil {
 				log.Err(err).Msgf("Failed to send p2p keepalive ping. SessionID=%s", sessionID)
@@ -971,7 +1498,7 @@ func (m *connectionManager) keepAliveLoop(channel p2p.Channel, sessionID session
 					if config.GetBool(config.FlagKeepConnectedOnFail) {
 						m.statusOnHold()
 					} else {
-						m.Disconnect()
+						m.DisconnectWithReason(session.DisconnectReasonQualityFailover)
 					}
 					cancel()
 					return
@@ -984,6 +1511,306 @@ func (m *connectionManager) keepAliveLoop(channel p2p.Channel, sessionID session
 	}
 }
 
+// keepAliveDelay returns the delay before the next keepalive ping. With jitter enabled it is picked
+// from the upper half of the interval, so pings never come later than the NAT mapping requires.
+func (m *connectionManager) keepAliveDelay(interval time.Duration) time.Duration {
+	if !m.config.Mitigations.KeepAliveJitter || interval < 2 {
+		return interval
+	}
+	return interval - time.Duration(rand.Int63n(int64(interval/2)))
+}
+
+// handleMaintenance listens for the maintenance windows announced by the provider and fails over to another provider
+// ahead of the window if auto reconnect is enabled.
+func (m *connectionManager) handleMaintenance(channel p2p.Channel, sessionID session.ID) {
+	channel.Handle(p2p.TopicSessionMaintenance, func(c p2p.Context) error {
+		var msg pb.SessionMaintenance
+		if err := c.Request().UnmarshalProto(&msg); err != nil {
+			return err
+		}
+		if msg.SessionID != string(sessionID) {
+			return c.Error(fmt.Errorf("unknown session %q", msg.SessionID))
+		}
+
+		window := market.MaintenanceWindow{
+			Start: time.Unix(msg.Start, 0).UTC(),
+			End:   time.Unix(msg.End, 0).UTC(),
+		}
+		failover := config.GetBool(config.FlagAutoReconnect)
+		log.Warn().Msgf("Provider announced maintenance %s, failover: %t. SessionID=%s", window, failover, sessionID)
+
+		m.eventBus.Publish(connectionstate.AppTopicProviderMaintenance, connectionstate.AppEventProviderMaintenance{
+			UUID:        m.uuid,
+			SessionInfo: m.Status(),
+			Window:      window,
+			Failover:    failover,
+		})
+		if failover {
+			go m.statusOnHold()
+		}
+		return c.OK()
+	})
+}
+
+// keyRotationLoop periodically re-negotiates tunnel keys with the provider over the p2p channel.
+func (m *connectionManager) keyRotationLoop(channel p2p.Channel, conn Connection, consumerID identity.Identity, sessionID session.ID) {
+	rotator, ok := conn.(KeyRotator)
+	if !ok {
+		return
+	}
+
+	ctx := m.currentCtx()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(m.config.KeyRotation):
+			err := m.rotateKeys(ctx, channel, rotator, consumerID, sessionID)
+			if ctx.Err() != nil {
+				return
+			}
+
+			switch {
+			case err == nil:
+				log.Info().Msgf("Rotated tunnel keys. SessionID=%s", sessionID)
+			case errors.Is(err, p2p.ErrHandlerNotFound):
+				log.Info().Msgf("Provider does not support tunnel key rotation. SessionID=%s", sessionID)
+				return
+			case errors.Is(err, errKeysDiverged):
+				// Provider might have switched to the new keys already, the only safe way forward is a new session.
+				log.Warn().Err(err).Msgf("Tunnel key rotation result is unknown, reconnecting. SessionID=%s", sessionID)
+				go m.Reconnect()
+				return
+			default:
+				log.Warn().Err(err).Msgf("Failed to rotate tunnel keys. SessionID=%s", sessionID)
+			}
+		}
+	}
+}
+
+// roamingLoop watches the local address used to reach the provider and roams the session once it changes,
+// e.g. when the host switches from WiFi to mobile data. Session is reconnected if roaming fails.
+func (m *connectionManager) roamingLoop(channel p2p.Channel, sessionID session.ID) {
+	serviceConn := channel.ServiceConn()
+	if serviceConn == nil {
+		return
+	}
+	provider, ok := serviceConn.RemoteAddr().(*net.UDPAddr)
+	if !ok {
+		return
+	}
+
+	local, _ := m.routeSource(provider.IP)
+	ctx := m.currentCtx()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(m.config.RoamingCheck):
+			current, err := m.routeSource(provider.IP)
+			if err != nil {
+				// Host is offline in between the networks, check again later.
+				continue
+			}
+			if local == nil || current.Equal(local) {
+				local = current
+				continue
+			}
+
+			log.Info().Msgf("Local address changed, moving session to the new network. SessionID=%s", sessionID)
+			err = m.Roam()
+			if ctx.Err() != nil {
+				return
+			}
+
+			switch {
+			case err == nil:
+				local = current
+			case errors.Is(err, ErrConnectionNotActive):
+				// Session is being re-established already, try again once it is up.
+			default:
+				log.Warn().Err(err).Msgf("Failed to move session to the new network, reconnecting. SessionID=%s", sessionID)
+				go m.Reconnect()
+				return
+			}
+		}
+	}
+}
+
+// routeSourceIP returns the local IP which the host currently uses to reach the given remote IP.
+// No packets are sent, connecting UDP socket only resolves the route.
+func routeSourceIP(remote net.IP) (net.IP, error) {
+	conn, err := net.DialUDP("udp4", nil, &net.UDPAddr{IP: remote, Port: 1})
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	return conn.LocalAddr().(*net.UDPAddr).IP, nil
+}
+
+// errKeysDiverged is returned when the provider might use different tunnel keys than the consumer.
+var errKeysDiverged = errors.New("tunnel keys diverged")
+
+// rotateKeys offers a new key to the provider and switches to the new keys once the provider acknowledged them.
+// Provider keeps the current keys until the acknowledgement, so a failed offer leaves the tunnel intact.
+func (m *connectionManager) rotateKeys(ctx context.Context, channel p2p.Channel, rotator KeyRotator, consumerID identity.Identity, sessionID session.ID) error {
+	publicKey, err := rotator.NewRotationKey()
+	if err != nil {
+		return err
+	}
+
+	msg := &pb.SessionKeyRotation{
+		ConsumerID: consumerID.Address,
+		SessionID:  string(sessionID),
+		PublicKey:  publicKey,
+	}
+
+	log.Debug().Msgf("Sending P2P message to %q: session %s", p2p.TopicSessionKeyRotate, sessionID)
+	ctx, cancel := context.WithTimeout(ctx, 20*time.Second)
+	defer cancel()
+	res, err := channel.Send(ctx, p2p.TopicSessionKeyRotate, p2p.ProtoMessage(msg))
+	if err != nil {
+		return err
+	}
+
+	var reply pb.SessionKeyRotation
+	if err := res.UnmarshalProto(&reply); err != nil {
+		return fmt.Errorf("could not unmarshal key rotation reply: %w", err)
+	}
+
+	ack := &pb.SessionKeyRotation{
+		ConsumerID: consumerID.Address,
+		SessionID:  string(sessionID),
+		PublicKey:  reply.GetPublicKey(),
+	}
+
+	log.Debug().Msgf("Sending P2P message to %q: session %s", p2p.TopicSessionKeyRotateAck, sessionID)
+	if _, err := channel.Send(ctx, p2p.TopicSessionKeyRotateAck, p2p.ProtoMessage(ack)); err != nil {
+		if errors.Is(err, p2p.ErrSendTimeout) {
+			return fmt.Errorf("%w: %v", errKeysDiverged, err)
+		}
+		return err
+	}
+
+	if err := rotator.RotateKeys(reply.GetPublicKey()); err != nil {
+		return fmt.Errorf("%w: %v", errKeysDiverged, err)
+	}
+
+	return nil
+}
+
+// inactivityLoop disconnects the session when no traffic was transferred for the given period of time.
+func (m *connectionManager) inactivityLoop(timeout time.Duration) {
+	tracker := newInactivityTracker(timeout, m.config.InactivityWarning, m.timeGetter())
+	ctx := m.currentCtx()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(m.statsReportInterval):
+			now := m.timeGetter()
+			if m.Status().State == connectionstate.StatePaused {
+				// Paused session is idle on purpose, start counting once it is resumed.
+				tracker.touch(now)
+				continue
+			}
+
+			switch tracker.update(m.statsTracker.stats(), now) {
+			case inactivityWarn:
+				idle := tracker.idle(now)
+				log.Info().Msgf("Session is inactive for %s, disconnecting in %s", idle, timeout-idle)
+				m.eventBus.Publish(connectionstate.AppTopicConnectionInactivity, connectionstate.AppEventConnectionInactivity{
+					UUID:         m.uuid,
+					SessionInfo:  m.Status(),
+					IdleFor:      idle,
+					DisconnectIn: timeout - idle,
+				})
+			case inactivityDisconnect:
+				log.Info().Msgf("Session is inactive for %s, disconnecting", tracker.idle(now))
+				if err := m.DisconnectWithReason(session.DisconnectReasonInactivity); err != nil {
+					log.Warn().Err(err).Msg("Could not disconnect inactive session")
+				}
+				return
+			}
+		}
+	}
+}
+
+// slaLoop monitors the session against the SLA advertised by the provider and claims the refund on breach.
+func (m *connectionManager) slaLoop(agreement market.SLA, setupTime time.Duration) {
+	if reason := sla.SetupBreach(agreement, setupTime); reason != "" {
+		m.reportSLABreach(agreement, reason)
+		return
+	}
+	if agreement.MinThroughput == 0 {
+		return
+	}
+
+	tracker := sla.NewTracker(agreement, m.config.SLAWindow, m.timeGetter())
+	ctx := m.currentCtx()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(m.statsReportInterval):
+			now := m.timeGetter()
+			if m.Status().State == connectionstate.StatePaused {
+				tracker.Reset(now)
+				continue
+			}
+
+			stats := m.statsTracker.stats()
+			if reason := tracker.Update(stats.BytesSent+stats.BytesReceived, now); reason != "" {
+				m.reportSLABreach(agreement, reason)
+				return
+			}
+		}
+	}
+}
+
+func (m *connectionManager) reportSLABreach(agreement market.SLA, reason string) {
+	status := m.Status()
+	log.Warn().Msgf("Provider SLA breached: %s. SessionID=%s", reason, status.SessionID)
+
+	refund, err := m.claimSLARefund(status.ConsumerID, status.SessionID, agreement, reason)
+	if err != nil {
+		log.Warn().Err(err).Msgf("Could not claim SLA refund. SessionID=%s", status.SessionID)
+	}
+
+	m.eventBus.Publish(connectionstate.AppTopicSLABreach, connectionstate.AppEventSLABreach{
+		UUID:        m.uuid,
+		SessionInfo: status,
+		Reason:      reason,
+		Refund:      refund,
+	})
+}
+
+func (m *connectionManager) claimSLARefund(consumerID identity.Identity, sessionID session.ID, agreement market.SLA, reason string) (uint8, error) {
+	msg := &pb.SessionSLABreach{
+		ConsumerID: consumerID.Address,
+		SessionID:  string(sessionID),
+		Reason:     reason,
+	}
+
+	log.Debug().Msgf("Sending P2P message to %q: %s", p2p.TopicSessionSLABreach, msg.String())
+	ctx, cancel := context.WithTimeout(m.currentCtx(), 10*time.Second)
+	defer cancel()
+	res, err := m.channel.Send(ctx, p2p.TopicSessionSLABreach, p2p.ProtoMessage(msg))
+	if err != nil {
+		return 0, err
+	}
+
+	var reply pb.SessionSLABreach
+	if err := res.UnmarshalProto(&reply); err != nil {
+		return 0, fmt.Errorf("could not unmarshal SLA breach reply: %w", err)
+	}
+	if reply.GetRefund() != uint32(agreement.Refund) {
+		return 0, fmt.Errorf("provider refunded %d%%, but %d%% was agreed", reply.GetRefund(), agreement.Refund)
+	}
+	return agreement.Refund, nil
+}
+
 func (m *connectionManager) sendKeepAlivePing(ctx context.Context, channel p2p.Channel, sessionID session.ID) error {
 	msg := &pb.P2PKeepAlivePing{
 		SessionID: string(sessionID),
@@ -1011,7 +1838,7 @@ func (m *connectionManager) currentCtx() context.Context {
 }
 
 func (m *connectionManager) Reconnect() {
-	err := m.Disconnect()
+	err := m.DisconnectWithReason(session.DisconnectReasonQualityFailover)
 	if err != nil {
 		log.Error().Err(err).Msgf("Failed to disconnect stale session")
 	}
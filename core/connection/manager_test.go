@@ -33,7 +33,9 @@ import (
 	"google.golang.org/protobuf/proto"
 
 	"github.com/mysteriumnetwork/node/communication/nats"
+	"github.com/mysteriumnetwork/node/config"
 	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
+	"github.com/mysteriumnetwork/node/core/connection/onlinecheck"
 	"github.com/mysteriumnetwork/node/core/discovery/proposal"
 	"github.com/mysteriumnetwork/node/core/ip"
 	"github.com/mysteriumnetwork/node/core/location"
@@ -41,6 +43,7 @@ import (
 	"github.com/mysteriumnetwork/node/identity"
 	"github.com/mysteriumnetwork/node/market"
 	"github.com/mysteriumnetwork/node/mocks"
+	natprobe "github.com/mysteriumnetwork/node/nat/behavior"
 	"github.com/mysteriumnetwork/node/p2p"
 	"github.com/mysteriumnetwork/node/pb"
 	"github.com/mysteriumnetwork/node/session"
@@ -158,6 +161,8 @@ func (tc *testContext) SetupTest() {
 		tc.statsReportInterval,
 		&mockValidator{},
 		tc.mockP2P,
+		&mockReconciler{},
+		&mockExternalEndpoints{config: []byte(`{"external":true}`)},
 		func() {}, func() {},
 	)
 	tc.connManager.timeGetter = func() time.Time {
@@ -205,6 +210,54 @@ func (tc *testContext) TestWhenManagerMadeConnectionStatusReturnsConnectedStateA
 	)
 }
 
+func (tc *testContext) TestConnectUsesTunedKeepAliveInterval() {
+	tc.connManager.handleKeepAliveTuned(natprobe.AppEventKeepAliveTuned{MappingLifetime: time.Minute, Interval: 45 * time.Second})
+	err := tc.connManager.Connect(consumerID, hermesID, activeProposalLookup, ConnectParams{})
+	assert.NoError(tc.T(), err)
+	assert.Equal(tc.T(), 45*time.Second, tc.connManager.connectOptions.KeepAliveInterval)
+}
+
+func (tc *testContext) TestStatusReportsEnabledMitigations() {
+	tc.connManager.config.Mitigations = TrafficMitigations{KeepAliveJitter: true, PadControl: true}
+	err := tc.connManager.Connect(consumerID, hermesID, activeProposalLookup, ConnectParams{})
+	assert.NoError(tc.T(), err)
+	assert.Equal(tc.T(), []string{MitigationKeepAliveJitter, MitigationControlPadding}, tc.connManager.Status().Mitigations)
+}
+
+func (tc *testContext) TestKeepAliveDelayIsJitteredWithinInterval() {
+	interval := 10 * time.Second
+	assert.Equal(tc.T(), interval, tc.connManager.keepAliveDelay(interval))
+
+	tc.connManager.config.Mitigations.KeepAliveJitter = true
+	for i := 0; i < 100; i++ {
+		delay := tc.connManager.keepAliveDelay(interval)
+		assert.Greater(tc.T(), delay, interval/2)
+		assert.LessOrEqual(tc.T(), delay, interval)
+	}
+}
+
+func (tc *testContext) TestOnlineCheckReportsResultAndPutsConnectionOnHold() {
+	config.Current.SetUser(config.FlagAutoReconnect.Name, true)
+	defer config.Current.RemoveUser(config.FlagAutoReconnect.Name)
+
+	checker := &mockOnlineChecker{result: onlinecheck.Result{Online: true, Succeeded: 2, Quorum: 2, Targets: make([]onlinecheck.TargetResult, 3)}}
+	tc.connManager.config.OnlineCheck = OnlineCheckConfig{Checker: checker, Interval: 10 * time.Millisecond, MaxFailures: 2}
+	err := tc.connManager.Connect(consumerID, hermesID, activeProposalLookup, ConnectParams{})
+	assert.NoError(tc.T(), err)
+
+	assert.Eventually(tc.T(), func() bool {
+		online := tc.connManager.Status().Online
+		return online != nil && online.Online && online.Succeeded == 2 && online.Total == 3
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(tc.T(), connectionstate.Connected, tc.connManager.Status().State)
+
+	checker.setResult(onlinecheck.Result{Succeeded: 1, Quorum: 2, Targets: make([]onlinecheck.TargetResult, 3)})
+	assert.Eventually(tc.T(), func() bool {
+		return tc.connManager.Status().State == connectionstate.StateOnHold
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.False(tc.T(), tc.connManager.Status().Online.Online)
+}
+
 func (tc *testContext) TestStatusReportsConnectingWhenConnectionIsInProgress() {
 	tc.fakeConnectionFactory.mockConnection.onStartReportStates = []fakeState{}
 
@@ -286,10 +339,139 @@ func (tc *testContext) TestConnectResultsInAlreadyConnectedErrorWhenConnectionEx
 	assert.Equal(tc.T(), ErrAlreadyExists, tc.connManager.Connect(consumerID, hermesID, activeProposalLookup, ConnectParams{}))
 }
 
+func (tc *testContext) TestLastTraceRecordsConnectStages() {
+	_, ok := tc.connManager.LastTrace()
+	assert.False(tc.T(), ok)
+
+	assert.NoError(tc.T(), tc.connManager.Connect(consumerID, hermesID, activeProposalLookup, ConnectParams{}))
+	waitABit()
+
+	trace, ok := tc.connManager.LastTrace()
+	assert.True(tc.T(), ok)
+	assert.Equal(tc.T(), tc.connManager.Status().SessionID, trace.SessionID)
+	assert.Equal(tc.T(), activeProviderID.Address, trace.ProviderID)
+	assert.Equal(tc.T(), activeServiceType, trace.ServiceType)
+	assert.Equal(tc.T(), tc.mockTime, trace.Started)
+	assert.Equal(tc.T(), tc.mockTime, trace.Finished)
+	assert.Equal(tc.T(), tc.mockTime, trace.FirstByte)
+	assert.Empty(tc.T(), trace.Error)
+
+	var stages []string
+	for _, stage := range trace.Stages {
+		assert.False(tc.T(), stage.End.IsZero(), stage.Key)
+		stages = append(stages, stage.Key)
+	}
+	assert.Subset(
+		tc.T(),
+		stages,
+		[]string{TraceStageProposalLookup, TraceStageTraversal, TraceStagePaymentSetup, TraceStageSession, TraceStageTunnel},
+	)
+
+	// a rejected connect keeps the trace of the established connection
+	assert.Equal(tc.T(), ErrAlreadyExists, tc.connManager.Connect(consumerID, hermesID, activeProposalLookup, ConnectParams{}))
+	again, ok := tc.connManager.LastTrace()
+	assert.True(tc.T(), ok)
+	assert.Equal(tc.T(), trace.SessionID, again.SessionID)
+	assert.Empty(tc.T(), again.Error)
+}
+
+func (tc *testContext) TestLastTraceRecordsConnectError() {
+	tc.fakeConnectionFactory.mockError = errors.New("fatal connection error")
+
+	assert.Error(tc.T(), tc.connManager.Connect(consumerID, hermesID, activeProposalLookup, ConnectParams{}))
+
+	trace, ok := tc.connManager.LastTrace()
+	assert.True(tc.T(), ok)
+	assert.Equal(tc.T(), "fatal connection error", trace.Error)
+	assert.True(tc.T(), trace.FirstByte.IsZero())
+}
+
 func (tc *testContext) TestDisconnectReturnsErrorWhenNoConnectionExists() {
 	assert.Equal(tc.T(), ErrNoConnection, tc.connManager.Disconnect())
 }
 
+func (tc *testContext) TestPauseAndResumeChangeConnectionState() {
+	assert.Equal(tc.T(), ErrNoConnection, tc.connManager.Pause())
+	assert.Equal(tc.T(), ErrNoConnection, tc.connManager.Resume())
+
+	assert.NoError(tc.T(), tc.connManager.Connect(consumerID, hermesID, activeProposalLookup, ConnectParams{}))
+	assert.NoError(tc.T(), tc.connManager.Pause())
+	assert.Equal(tc.T(), connectionstate.StatePaused, tc.connManager.Status().State)
+
+	// tunnel state updates do not resume the session
+	tc.fakeConnectionFactory.mockConnection.reportState(connectedState)
+	waitABit()
+	assert.Equal(tc.T(), connectionstate.StatePaused, tc.connManager.Status().State)
+
+	assert.NoError(tc.T(), tc.connManager.Resume())
+	assert.Equal(tc.T(), connectionstate.Connected, tc.connManager.Status().State)
+}
+
+func (tc *testContext) TestRoamKeepsSession() {
+	assert.Equal(tc.T(), ErrNoConnection, tc.connManager.Roam())
+
+	assert.NoError(tc.T(), tc.connManager.Connect(consumerID, hermesID, activeProposalLookup, ConnectParams{}))
+	sessionID := tc.connManager.Status().SessionID
+
+	assert.NoError(tc.T(), tc.connManager.Roam())
+	assert.Equal(tc.T(), connectionstate.Connected, tc.connManager.Status().State)
+	assert.Equal(tc.T(), sessionID, tc.connManager.Status().SessionID)
+	assert.False(tc.T(), tc.MockPaymentIssuer.StopCalled())
+	assert.Equal(tc.T(), 1, tc.mockP2P.ch.getRoams())
+}
+
+func (tc *testContext) TestRoamsSessionWhenLocalAddressChanges() {
+	var lock sync.Mutex
+	localIP := net.ParseIP("192.168.1.2")
+	tc.connManager.config.RoamingCheck = time.Millisecond
+	tc.connManager.routeSource = func(net.IP) (net.IP, error) {
+		lock.Lock()
+		defer lock.Unlock()
+		return localIP, nil
+	}
+
+	assert.NoError(tc.T(), tc.connManager.Connect(consumerID, hermesID, activeProposalLookup, ConnectParams{}))
+	waitABit()
+	assert.Equal(tc.T(), 0, tc.mockP2P.ch.getRoams())
+
+	lock.Lock()
+	localIP = net.ParseIP("10.20.30.40")
+	lock.Unlock()
+
+	assert.Eventually(tc.T(), func() bool { return tc.mockP2P.ch.getRoams() == 1 }, time.Second, time.Millisecond)
+	waitABit()
+	assert.Equal(tc.T(), 1, tc.mockP2P.ch.getRoams())
+	assert.Equal(tc.T(), connectionstate.Connected, tc.connManager.Status().State)
+}
+
+func (tc *testContext) TestConnectsToExternalEndpointWithoutProviderSession() {
+	externalProposal := proposal.PricedServiceProposal{
+		ServiceProposal: market.ServiceProposal{
+			ProviderID:  "external:1",
+			Contacts:    []market.Contact{{Type: market.ContactTypeExternal}},
+			ServiceType: activeServiceType,
+		},
+		Price: *market.NewPrice(0, 0),
+	}
+	// external endpoints are not paid for, so balance is not validated
+	tc.connManager.validator = &mockValidator{errorToReturn: ErrInsufficientBalance}
+
+	err := tc.connManager.Connect(consumerID, hermesID, func() (*proposal.PricedServiceProposal, error) {
+		return &externalProposal, nil
+	}, ConnectParams{})
+	assert.NoError(tc.T(), err)
+
+	status := tc.connManager.Status()
+	assert.Equal(tc.T(), connectionstate.Connected, status.State)
+	assert.NotEmpty(tc.T(), status.SessionID)
+	assert.Nil(tc.T(), tc.connManager.channel)
+	assert.Equal(tc.T(), []byte(`{"external":true}`), tc.connManager.connectOptions.SessionConfig)
+
+	assert.Equal(tc.T(), ErrUnsupportedByExternal, tc.connManager.Pause())
+	assert.NoError(tc.T(), tc.connManager.CheckChannel(context.Background()))
+	assert.NoError(tc.T(), tc.connManager.Disconnect())
+}
+
 func (tc *testContext) TestReconnectingStatusIsReportedWhenOpenVpnGoesIntoReconnectingState() {
 	assert.NoError(tc.T(), tc.connManager.Connect(consumerID, hermesID, activeProposalLookup, ConnectParams{}))
 	tc.fakeConnectionFactory.mockConnection.reportState(reconnectingState)
@@ -428,6 +610,7 @@ func (tc *testContext) Test_SessionEndPublished_OnConnectError() {
 				assert.Equal(tc.T(), establishedSessionID, event.SessionInfo.SessionID)
 				assert.Equal(tc.T(), activeProposal.ProviderID, event.SessionInfo.Proposal.ProviderID)
 				assert.Equal(tc.T(), activeProposal.ServiceType, event.SessionInfo.Proposal.ServiceType)
+				assert.Equal(tc.T(), session.DisconnectReasonUnknown, event.DisconnectReason)
 			}
 		}
 	}
@@ -435,6 +618,48 @@ func (tc *testContext) Test_SessionEndPublished_OnConnectError() {
 	assert.True(tc.T(), found)
 }
 
+func (tc *testContext) Test_SessionEndPublished_WithDisconnectReason() {
+	err := tc.connManager.Connect(consumerID, hermesID, activeProposalLookup, ConnectParams{})
+	assert.NoError(tc.T(), err)
+	waitABit()
+
+	tc.stubPublisher.Clear()
+	assert.NoError(tc.T(), tc.connManager.DisconnectWithReason(session.DisconnectReasonLowBalance))
+	waitABit()
+
+	found := false
+	for _, v := range tc.stubPublisher.GetEventHistory() {
+		if v.Topic != connectionstate.AppTopicConnectionSession {
+			continue
+		}
+		if event := v.Event.(connectionstate.AppEventConnectionSession); event.Status == connectionstate.SessionEndedStatus {
+			found = true
+			assert.Equal(tc.T(), session.DisconnectReasonLowBalance, event.DisconnectReason)
+		}
+	}
+	assert.True(tc.T(), found)
+}
+
+func (tc *testContext) Test_ConnectionAttemptPublished_OnConnectError() {
+	tc.stubPublisher.Clear()
+
+	tc.fakeConnectionFactory.mockConnection.onStartReturnError = errors.New("fatal connection error")
+	err := tc.connManager.Connect(consumerID, hermesID, activeProposalLookup, ConnectParams{})
+	assert.Error(tc.T(), err)
+
+	var attempts []connectionstate.AppEventConnectionAttempt
+	for _, v := range tc.stubPublisher.GetEventHistory() {
+		if v.Topic == connectionstate.AppTopicConnectionAttempt {
+			attempts = append(attempts, v.Event.(connectionstate.AppEventConnectionAttempt))
+		}
+	}
+
+	assert.Len(tc.T(), attempts, 1)
+	assert.Equal(tc.T(), activeProposal.ProviderID, attempts[0].ProviderID)
+	assert.Equal(tc.T(), activeProposal.ServiceType, attempts[0].ServiceType)
+	assert.Contains(tc.T(), attempts[0].Error, "fatal connection error")
+}
+
 func (tc *testContext) Test_ManagerPublishesEvents() {
 	tc.stubPublisher.Clear()
 
@@ -628,6 +853,7 @@ func (m mockP2PDialer) Dial(ctx context.Context, consumerID identity.Identity, p
 
 type mockP2PChannel struct {
 	status proto.Message
+	roams  int
 	lock   sync.Mutex
 }
 
@@ -641,6 +867,12 @@ func (m *mockP2PChannel) getSentMsg() proto.Message {
 	return m.status
 }
 
+func (m *mockP2PChannel) getRoams() int {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.roams
+}
+
 func (m *mockP2PChannel) Send(_ context.Context, topic string, msg *p2p.Message) (*p2p.Message, error) {
 	switch topic {
 	case p2p.TopicSessionCreate:
@@ -655,7 +887,13 @@ func (m *mockP2PChannel) Send(_ context.Context, topic string, msg *p2p.Message)
 		m.lock.Unlock()
 
 		return nil, nil
-	case p2p.TopicSessionAcknowledge:
+	case p2p.TopicSessionRoam:
+		m.lock.Lock()
+		m.roams++
+		m.lock.Unlock()
+
+		return nil, nil
+	case p2p.TopicSessionAcknowledge, p2p.TopicSessionPause, p2p.TopicSessionResume:
 		return nil, nil
 	}
 
@@ -691,8 +929,43 @@ func (mv *mockValidator) Validate(chainID int64, consumerID identity.Identity, p
 	return mv.errorToReturn
 }
 
+type mockExternalEndpoints struct {
+	config []byte
+}
+
+func (me *mockExternalEndpoints) SessionConfig(providerID string) ([]byte, error) {
+	return me.config, nil
+}
+
+type mockReconciler struct{}
+
+func (mr *mockReconciler) Accounting(session.ID) (session.Accounting, bool) {
+	return session.Accounting{}, false
+}
+
+func (mr *mockReconciler) Reconcile(session.ID, session.Accounting) error {
+	return nil
+}
+
 type mockLocationResolver struct{}
 
 func (mlr *mockLocationResolver) GetOrigin() locationstate.Location {
 	return consumerLocation
 }
+
+type mockOnlineChecker struct {
+	mu     sync.Mutex
+	result onlinecheck.Result
+}
+
+func (m *mockOnlineChecker) Check(ctx context.Context) onlinecheck.Result {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.result
+}
+
+func (m *mockOnlineChecker) setResult(result onlinecheck.Result) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.result = result
+}
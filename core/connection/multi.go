@@ -19,6 +19,7 @@ package connection
 
 import (
 	"context"
+	"sort"
 	"sync"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -26,6 +27,7 @@ import (
 
 	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
 	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/session"
 )
 
 type multiConnectionManager struct {
@@ -72,6 +74,22 @@ func (mcm *multiConnectionManager) Status(id int) connectionstate.Status {
 	}
 }
 
+// List returns IDs of the connections which are not disconnected, in ascending order.
+func (mcm *multiConnectionManager) List() []int {
+	mcm.mu.RLock()
+	defer mcm.mu.RUnlock()
+
+	ids := make([]int, 0, len(mcm.cms))
+	for id, m := range mcm.cms {
+		if m.Status().State != connectionstate.NotConnected {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+
+	return ids
+}
+
 // Stats provides connection statistics information.
 func (mcm *multiConnectionManager) Stats(id int) connectionstate.Statistics {
 	mcm.mu.RLock()
@@ -86,12 +104,18 @@ func (mcm *multiConnectionManager) Stats(id int) connectionstate.Statistics {
 
 // Disconnect closes established connection, reports error if no connection.
 func (mcm *multiConnectionManager) Disconnect(id int) error {
+	return mcm.DisconnectWithReason(id, session.DisconnectReasonUserRequest)
+}
+
+// DisconnectWithReason closes established connection and reports the reason to provider.
+// Negative id disconnects all established connections.
+func (mcm *multiConnectionManager) DisconnectWithReason(id int, reason session.DisconnectReason) error {
 	mcm.mu.RLock()
 	m, ok := mcm.cms[id]
 	mcm.mu.RUnlock()
 
 	if ok {
-		err := m.Disconnect()
+		err := m.DisconnectWithReason(reason)
 		return err
 	}
 
@@ -100,7 +124,7 @@ func (mcm *multiConnectionManager) Disconnect(id int) error {
 		defer mcm.mu.RUnlock()
 
 		for _, m := range mcm.cms {
-			if err := m.Disconnect(); err != nil {
+			if err := m.DisconnectWithReason(reason); err != nil {
 				log.Error().Err(err).Msg("Failed to disconnect active connection")
 			}
 		}
@@ -121,3 +145,54 @@ func (mcm *multiConnectionManager) Reconnect(id int) {
 		m.Reconnect()
 	}
 }
+
+// Pause suspends data flow and invoicing of the established connection, reports error if no connection.
+func (mcm *multiConnectionManager) Pause(id int) error {
+	mcm.mu.RLock()
+	m, ok := mcm.cms[id]
+	mcm.mu.RUnlock()
+
+	if !ok {
+		return ErrNoConnection
+	}
+
+	return m.Pause()
+}
+
+// Resume restores data flow and invoicing of the paused connection, reports error if no connection.
+func (mcm *multiConnectionManager) Resume(id int) error {
+	mcm.mu.RLock()
+	m, ok := mcm.cms[id]
+	mcm.mu.RUnlock()
+
+	if !ok {
+		return ErrNoConnection
+	}
+
+	return m.Resume()
+}
+
+// Roam moves the established connection to the current network of the host, reports error if no connection.
+func (mcm *multiConnectionManager) Roam(id int) error {
+	mcm.mu.RLock()
+	m, ok := mcm.cms[id]
+	mcm.mu.RUnlock()
+
+	if !ok {
+		return ErrNoConnection
+	}
+
+	return m.Roam()
+}
+
+// LastTrace returns the timings of the last connection attempt, false if there was none.
+func (mcm *multiConnectionManager) LastTrace(id int) (Trace, bool) {
+	mcm.mu.RLock()
+	defer mcm.mu.RUnlock()
+
+	if m, ok := mcm.cms[id]; ok {
+		return m.LastTrace()
+	}
+
+	return Trace{}, false
+}
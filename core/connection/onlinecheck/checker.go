@@ -0,0 +1,214 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package onlinecheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultTargets are the check URLs requested unless custom ones are configured.
+var DefaultTargets = []string{
+	"https://cp.cloudflare.com/generate_204",
+	"https://www.gstatic.com/generate_204",
+	"https://detectportal.firefox.com/success.txt",
+}
+
+// DefaultTimeout limits a single target request.
+const DefaultTimeout = 10 * time.Second
+
+// ErrNoTargets is returned when every check target was skipped.
+var ErrNoTargets = errors.New("no connectivity check targets left")
+
+// Config contains options of the connectivity check.
+type Config struct {
+	// Targets are HTTPS URLs requested through the tunnel, DefaultTargets are used if empty.
+	Targets []string
+	// Skip lists hosts which are never contacted, subdomains of the listed hosts are skipped too.
+	Skip []string
+	// Quorum is the number of targets which have to respond for the connection to be online.
+	// It is capped at the number of targets, one target is enough if not set.
+	Quorum int
+	// Timeout limits a single target request, DefaultTimeout is used if not set.
+	Timeout time.Duration
+}
+
+// Result is the outcome of a single connectivity check.
+type Result struct {
+	Online    bool
+	Succeeded int
+	Quorum    int
+	Targets   []TargetResult
+	CheckedAt time.Time
+}
+
+// TargetResult is the outcome of a single target request.
+type TargetResult struct {
+	URL     string
+	Latency time.Duration
+	// Error is empty if target responded successfully.
+	Error string
+}
+
+// Checker checks whether the internet is reachable through the tunnel
+// by requesting the configured targets.
+type Checker struct {
+	targets []string
+	quorum  int
+	timeout time.Duration
+	client  *http.Client
+}
+
+// NewChecker creates connectivity checker, targets have to be valid HTTPS URLs.
+func NewChecker(cfg Config) (*Checker, error) {
+	candidates := cfg.Targets
+	if len(candidates) == 0 {
+		candidates = DefaultTargets
+	}
+
+	var targets []string
+	for _, target := range candidates {
+		u, err := url.Parse(target)
+		if err != nil {
+			return nil, fmt.Errorf("invalid connectivity check target %q: %w", target, err)
+		}
+		if u.Scheme != "https" || u.Hostname() == "" {
+			return nil, fmt.Errorf("connectivity check target %q is not an HTTPS URL", target)
+		}
+		if skipped(u.Hostname(), cfg.Skip) {
+			continue
+		}
+		targets = append(targets, u.String())
+	}
+	if len(targets) == 0 {
+		return nil, ErrNoTargets
+	}
+
+	quorum := cfg.Quorum
+	if quorum <= 0 {
+		quorum = 1
+	}
+	if quorum > len(targets) {
+		quorum = len(targets)
+	}
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	return &Checker{
+		targets: targets,
+		quorum:  quorum,
+		timeout: timeout,
+		client: &http.Client{
+			// Every check opens new connections, so that they go through the current tunnel.
+			Transport: &http.Transport{DisableKeepAlives: true, Proxy: nil},
+			// Redirect usually comes from a captive portal, it does not count as a response.
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}, nil
+}
+
+// Targets returns the URLs requested by the check.
+func (c *Checker) Targets() []string {
+	return append([]string(nil), c.targets...)
+}
+
+// Check requests all targets concurrently and reports whether the quorum of them responded.
+func (c *Checker) Check(ctx context.Context) Result {
+	results := make([]TargetResult, len(c.targets))
+
+	var wg sync.WaitGroup
+	for i, target := range c.targets {
+		wg.Add(1)
+		go func(i int, target string) {
+			defer wg.Done()
+			results[i] = c.request(ctx, target)
+		}(i, target)
+	}
+	wg.Wait()
+
+	var succeeded int
+	for _, r := range results {
+		if r.Error == "" {
+			succeeded++
+		}
+	}
+	return Result{
+		Online:    succeeded >= c.quorum,
+		Succeeded: succeeded,
+		Quorum:    c.quorum,
+		Targets:   results,
+		CheckedAt: time.Now(),
+	}
+}
+
+func (c *Checker) request(ctx context.Context, target string) TargetResult {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	result := TargetResult{URL: target}
+	start := time.Now()
+	err := c.get(ctx, target)
+	result.Latency = time.Since(start)
+	if err != nil {
+		result.Error = err.Error()
+	}
+	return result
+}
+
+func (c *Checker) get(ctx context.Context, target string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return err
+	}
+	res, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(res.Body, 4096))
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("unexpected response status: %s", res.Status)
+	}
+	return nil
+}
+
+func skipped(host string, skip []string) bool {
+	host = strings.ToLower(host)
+	for _, s := range skip {
+		s = strings.ToLower(strings.TrimSpace(s))
+		if s == "" {
+			continue
+		}
+		if host == s || strings.HasSuffix(host, "."+s) {
+			return true
+		}
+	}
+	return false
+}
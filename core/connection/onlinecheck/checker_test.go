@@ -0,0 +1,72 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package onlinecheck
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewChecker_ValidatesAndSkipsTargets(t *testing.T) {
+	_, err := NewChecker(Config{Targets: []string{"http://example.com/check"}})
+	assert.Error(t, err)
+
+	_, err = NewChecker(Config{Targets: []string{"https://check.example.com"}, Skip: []string{"example.com"}})
+	assert.ErrorIs(t, err, ErrNoTargets)
+
+	c, err := NewChecker(Config{Skip: []string{"gstatic.com", "CP.CLOUDFLARE.COM"}, Quorum: 5})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://detectportal.firefox.com/success.txt"}, c.Targets())
+	assert.Equal(t, 1, c.quorum)
+}
+
+func TestChecker_CheckRequiresQuorum(t *testing.T) {
+	ok := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ok.Close()
+	portal := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "https://portal.example.com/login", http.StatusFound)
+	}))
+	defer portal.Close()
+	failing := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+
+	c, err := NewChecker(Config{Targets: []string{ok.URL, portal.URL, failing.URL}, Quorum: 1})
+	require.NoError(t, err)
+	c.client.Transport = ok.Client().Transport
+
+	res := c.Check(context.Background())
+	assert.True(t, res.Online)
+	assert.Equal(t, 1, res.Succeeded)
+	assert.Equal(t, 1, res.Quorum)
+	require.Len(t, res.Targets, 3)
+	assert.Empty(t, res.Targets[0].Error)
+	assert.Contains(t, res.Targets[1].Error, "302")
+	assert.Contains(t, res.Targets[2].Error, "503")
+
+	c.quorum = 2
+	assert.False(t, c.Check(context.Background()).Online)
+}
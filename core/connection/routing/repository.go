@@ -0,0 +1,130 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package routing
+
+import (
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/mysteriumnetwork/node/core/storage"
+)
+
+const bucketName = "connection-routes"
+
+// ErrNotFound indicates that there is no route with the given ID.
+var ErrNotFound = errors.New("route not found")
+
+type persistentStorage interface {
+	Store(bucket string, data interface{}) error
+	GetAllFrom(bucket string, data interface{}) error
+	GetOneByField(bucket string, fieldName string, key interface{}, to interface{}) error
+	Delete(bucket string, data interface{}) error
+}
+
+// Repository keeps the routing policy of consumer connections.
+// Routes outlive the connections, so that reconnecting on the same proxy port keeps the policy.
+// Clients resolve the connection for an application or destination and bind the traffic to its proxy port.
+type Repository struct {
+	lock    sync.Mutex
+	storage persistentStorage
+	now     func() time.Time
+}
+
+// NewRepository creates a new route repository.
+func NewRepository(storage persistentStorage) *Repository {
+	return &Repository{
+		storage: storage,
+		now:     time.Now,
+	}
+}
+
+// Add stores a new route of the given connection.
+func (r *Repository) Add(connectionID int, app, cidr string) (Route, error) {
+	route, err := newRoute(connectionID, app, cidr)
+	if err != nil {
+		return Route{}, err
+	}
+
+	id, err := uuid.NewV4()
+	if err != nil {
+		return Route{}, err
+	}
+	route.ID = id.String()
+	route.CreatedAt = r.now().UTC()
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if err := r.storage.Store(bucketName, &route); err != nil {
+		return Route{}, err
+	}
+	return route, nil
+}
+
+// List returns all routes.
+func (r *Repository) List() ([]Route, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	var routes []Route
+	if err := r.storage.GetAllFrom(bucketName, &routes); err != nil {
+		return nil, err
+	}
+	return routes, nil
+}
+
+// Remove deletes the route with the given ID.
+func (r *Repository) Remove(id string) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	var route Route
+	err := r.storage.GetOneByField(bucketName, "ID", id, &route)
+	if errors.Is(err, storage.ErrNotFound) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	return r.storage.Delete(bucketName, &route)
+}
+
+// Resolve returns the most specific route selecting traffic of the application to the destination IP.
+// Either of them may be empty.
+func (r *Repository) Resolve(app string, ip net.IP) (Route, error) {
+	routes, err := r.List()
+	if err != nil {
+		return Route{}, err
+	}
+
+	var best Route
+	bestScore := -1
+	for _, route := range routes {
+		if score, ok := route.match(app, ip); ok && score > bestScore {
+			best, bestScore = route, score
+		}
+	}
+	if bestScore < 0 {
+		return Route{}, ErrNotFound
+	}
+	return best, nil
+}
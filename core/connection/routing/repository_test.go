@@ -0,0 +1,108 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package routing
+
+import (
+	"net"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mysteriumnetwork/node/core/storage/boltdb"
+)
+
+func newRepository(t *testing.T) *Repository {
+	dir, err := os.MkdirTemp("", "connectionRoutesTest")
+	require.NoError(t, err)
+	db, err := boltdb.NewStorage(dir)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		db.Close()
+		os.RemoveAll(dir)
+	})
+
+	return NewRepository(db)
+}
+
+func TestRepository_Add(t *testing.T) {
+	repo := newRepository(t)
+
+	_, err := repo.Add(10000, "", "")
+	assert.ErrorIs(t, err, ErrInvalidRoute)
+	_, err = repo.Add(10000, "", "10.0.0.1")
+	assert.ErrorIs(t, err, ErrInvalidRoute)
+	_, err = repo.Add(-1, "firefox", "")
+	assert.ErrorIs(t, err, ErrInvalidRoute)
+
+	route, err := repo.Add(10000, "", "10.0.0.1/8")
+	require.NoError(t, err)
+	assert.Equal(t, "10.0.0.0/8", route.CIDR)
+
+	routes, err := repo.List()
+	require.NoError(t, err)
+	require.Len(t, routes, 1)
+	assert.Equal(t, route.ID, routes[0].ID)
+	assert.Equal(t, 10000, routes[0].ConnectionID)
+}
+
+func TestRepository_Remove(t *testing.T) {
+	repo := newRepository(t)
+	route, err := repo.Add(10000, "firefox", "")
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, repo.Remove("unknown"), ErrNotFound)
+	require.NoError(t, repo.Remove(route.ID))
+
+	routes, err := repo.List()
+	require.NoError(t, err)
+	assert.Len(t, routes, 0)
+}
+
+func TestRepository_Resolve(t *testing.T) {
+	repo := newRepository(t)
+	_, err := repo.Add(0, "", "10.0.0.0/8")
+	require.NoError(t, err)
+	_, err = repo.Add(10000, "", "10.1.0.0/16")
+	require.NoError(t, err)
+	_, err = repo.Add(10001, "Firefox", "")
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		app        string
+		ip         string
+		connection int
+		err        error
+	}{
+		{ip: "10.2.0.1", connection: 0},
+		{ip: "10.1.0.1", connection: 10000},
+		{app: "firefox", ip: "10.1.0.1", connection: 10001},
+		{app: "firefox", connection: 10001},
+		{app: "chrome", ip: "192.168.0.1", err: ErrNotFound},
+		{err: ErrNotFound},
+	} {
+		route, err := repo.Resolve(tc.app, net.ParseIP(tc.ip))
+		if tc.err != nil {
+			assert.ErrorIs(t, err, tc.err, "app %q ip %q", tc.app, tc.ip)
+			continue
+		}
+		require.NoError(t, err, "app %q ip %q", tc.app, tc.ip)
+		assert.Equal(t, tc.connection, route.ConnectionID, "app %q ip %q", tc.app, tc.ip)
+	}
+}
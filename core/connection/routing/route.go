@@ -0,0 +1,86 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package routing
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// ErrInvalidRoute indicates that route does not select any traffic.
+var ErrInvalidRoute = errors.New("invalid route")
+
+// Route links traffic of an application or to a destination network to one of the consumer connections.
+// Connection ID is the proxy port the connection serves, 0 stands for the system wide tunnel.
+type Route struct {
+	ID           string `storm:"id"`
+	ConnectionID int
+	App          string
+	CIDR         string
+	CreatedAt    time.Time
+}
+
+// newRoute validates the selectors and returns a route with a normalized CIDR.
+func newRoute(connectionID int, app, cidr string) (Route, error) {
+	route := Route{
+		ConnectionID: connectionID,
+		App:          strings.TrimSpace(app),
+	}
+	if connectionID < 0 {
+		return route, fmt.Errorf("%w: connection ID %d is negative", ErrInvalidRoute, connectionID)
+	}
+
+	if cidr = strings.TrimSpace(cidr); cidr != "" {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return route, fmt.Errorf("%w: %v", ErrInvalidRoute, err)
+		}
+		route.CIDR = network.String()
+	}
+
+	if route.App == "" && route.CIDR == "" {
+		return route, fmt.Errorf("%w: either app or CIDR is required", ErrInvalidRoute)
+	}
+	return route, nil
+}
+
+// match reports whether the route selects the traffic and how specific the selection is.
+// App routes are more specific than network ones, longer network prefixes are more specific than shorter ones.
+func (r Route) match(app string, ip net.IP) (int, bool) {
+	score := 0
+	if r.App != "" {
+		if app == "" || !strings.EqualFold(r.App, app) {
+			return 0, false
+		}
+		score += 256
+	}
+
+	if r.CIDR != "" {
+		_, network, err := net.ParseCIDR(r.CIDR)
+		if err != nil || ip == nil || !network.Contains(ip) {
+			return 0, false
+		}
+		ones, _ := network.Mask.Size()
+		score += ones + 1
+	}
+
+	return score, true
+}
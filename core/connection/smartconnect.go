@@ -24,13 +24,23 @@ import (
 	"github.com/mysteriumnetwork/node/core/discovery/proposal"
 )
 
+// maintenanceHorizon is how far ahead provider maintenance windows make the provider ineligible for new connections.
+const maintenanceHorizon = time.Hour
+
 type proposalRepository interface {
 	Proposals(filter *proposal.Filter) ([]proposal.PricedServiceProposal, error)
 }
 
+type providerBlocklist interface {
+	Blocked(providerID string) bool
+}
+
 // FilteredProposals create an function to keep getting proposals from the discovery based on the provided filters.
-func FilteredProposals(f *proposal.Filter, sortBy string, repo proposalRepository) func() (*proposal.PricedServiceProposal, error) {
+// Providers in the blocklist or with maintenance coming up are skipped unless the filter asks for a single provider explicitly,
+// blocklist is optional.
+func FilteredProposals(f *proposal.Filter, sortBy string, repo proposalRepository, blocklist providerBlocklist) func() (*proposal.PricedServiceProposal, error) {
 	usedProposals := make(map[string]time.Time)
+	explicitProvider := f.ProviderID != "" || len(f.ProviderIDs) == 1
 
 	return func() (*proposal.PricedServiceProposal, error) {
 		proposals, err := repo.Proposals(f)
@@ -38,6 +48,21 @@ func FilteredProposals(f *proposal.Filter, sortBy string, repo proposalRepositor
 			return nil, err
 		}
 
+		if !explicitProvider {
+			now := time.Now()
+			allowed := make([]proposal.PricedServiceProposal, 0, len(proposals))
+			for _, p := range proposals {
+				if blocklist != nil && blocklist.Blocked(p.ProviderID) {
+					continue
+				}
+				if _, ok := p.MaintenanceWithin(now, maintenanceHorizon); ok {
+					continue
+				}
+				allowed = append(allowed, p)
+			}
+			proposals = allowed
+		}
+
 		proposals, err = proposal.Sort(proposals, sortBy)
 		if err != nil {
 			return nil, fmt.Errorf("failed to sort proposals: %w", err)
@@ -49,6 +49,7 @@ func newStatsTracker(bus eventbus.Publisher, interval time.Duration) statsTracke
 }
 
 func (s *statsTracker) start(sessionSupplier *connectionManager, statsSupplier statsSupplier) {
+	firstByte := false
 	for {
 		select {
 		case <-time.After(s.interval):
@@ -57,6 +58,10 @@ func (s *statsTracker) start(sessionSupplier *connectionManager, statsSupplier s
 				log.Warn().Err(err).Msg("Could not get connection statistics")
 				continue
 			}
+			if !firstByte && stats.BytesReceived > 0 {
+				firstByte = true
+				sessionSupplier.traceFirstByte()
+			}
 
 			s.bus.Publish(connectionstate.AppTopicConnectionStatistics, connectionstate.AppEventConnectionStatistics{
 				Stats:       stats,
@@ -0,0 +1,104 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package connection
+
+import (
+	"sync"
+	"time"
+
+	"github.com/mysteriumnetwork/node/core/discovery/proposal"
+	"github.com/mysteriumnetwork/node/session"
+	"github.com/mysteriumnetwork/node/trace"
+)
+
+// Stages of the connect flow recorded in the connection trace.
+const (
+	TraceStageProposalLookup = "Consumer proposal lookup"
+	TraceStageTraversal      = "Consumer P2P channel creation"
+	TraceStagePaymentSetup   = "Consumer payment setup"
+	TraceStageSession        = "Consumer session creation"
+	TraceStageTunnel         = "Consumer tunnel handshake"
+)
+
+// Trace holds the timings of a single connection attempt.
+type Trace struct {
+	SessionID   session.ID
+	ProviderID  string
+	ServiceType string
+	Started     time.Time
+	// Finished is zero while the attempt is in progress.
+	Finished time.Time
+	// FirstByte is zero until the first byte is received through the tunnel.
+	FirstByte time.Time
+	Stages    []trace.Stage
+	Error     string
+}
+
+// connectAttempt records the trace of a connection attempt while it is in progress
+// and after it has finished, until the first byte is received.
+type connectAttempt struct {
+	tracer *trace.Tracer
+
+	mu    sync.Mutex
+	trace Trace
+}
+
+func newConnectAttempt(name string, now time.Time) *connectAttempt {
+	return &connectAttempt{
+		tracer: trace.NewTracer(name),
+		trace:  Trace{Started: now},
+	}
+}
+
+func (a *connectAttempt) setProposal(p proposal.PricedServiceProposal) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.trace.ProviderID = p.ProviderID
+	a.trace.ServiceType = p.ServiceType
+}
+
+func (a *connectAttempt) finish(sessionID session.ID, err error, now time.Time) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.trace.SessionID = sessionID
+	a.trace.Finished = now
+	if err != nil {
+		a.trace.Error = err.Error()
+	}
+}
+
+// firstByte records the time the first byte was received, only the first call has an effect.
+func (a *connectAttempt) firstByte(now time.Time) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.trace.FirstByte.IsZero() && a.trace.Error == "" {
+		a.trace.FirstByte = now
+	}
+}
+
+func (a *connectAttempt) snapshot() Trace {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	t := a.trace
+	t.Stages = a.tracer.Stages()
+	return t
+}
@@ -0,0 +1,198 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package trafficcategory classifies tunnel traffic into coarse destination
+// categories using packet header metadata only. Payloads are never inspected
+// and the results never leave the device.
+package trafficcategory
+
+import (
+	"encoding/binary"
+	"sync/atomic"
+)
+
+// Category is a coarse destination category of the traffic.
+type Category string
+
+const (
+	// Web is traffic to HTTP(S) and QUIC ports.
+	Web Category = "web"
+	// Streaming is traffic to well known media streaming ports.
+	Streaming Category = "streaming"
+	// Gaming is traffic to well known game server ports.
+	Gaming Category = "gaming"
+	// Other is any traffic not matching the categories above.
+	Other Category = "other"
+)
+
+// Categories lists all known categories.
+var Categories = []Category{Web, Streaming, Gaming, Other}
+
+const (
+	protoTCP = 6
+	protoUDP = 17
+)
+
+type portRange struct {
+	from, to uint16
+}
+
+type rule struct {
+	category Category
+	tcp      []portRange
+	udp      []portRange
+}
+
+var rules = []rule{
+	{
+		category: Web,
+		tcp:      []portRange{{80, 80}, {443, 443}, {8080, 8080}, {8443, 8443}},
+		udp:      []portRange{{443, 443}},
+	},
+	{
+		category: Streaming,
+		tcp:      []portRange{{554, 554}, {1755, 1755}, {1935, 1935}, {8554, 8554}},
+		udp:      []portRange{{554, 554}, {5004, 5005}, {8554, 8554}},
+	},
+	{
+		category: Gaming,
+		tcp:      []portRange{{3074, 3074}, {6112, 6119}, {25565, 25565}, {27014, 27050}},
+		udp:      []portRange{{3074, 3074}, {3478, 3480}, {6112, 6119}, {7777, 7788}, {27000, 27050}},
+	},
+}
+
+// Classify returns the category of the given IP packet. Destination port is
+// used for outbound packets and source port for inbound ones, so both
+// directions of a flow end up in the same category.
+func Classify(packet []byte, outbound bool) Category {
+	proto, l4, ok := transport(packet)
+	if !ok || len(l4) < 4 {
+		return Other
+	}
+
+	port := binary.BigEndian.Uint16(l4[0:2])
+	if outbound {
+		port = binary.BigEndian.Uint16(l4[2:4])
+	}
+
+	for _, r := range rules {
+		ranges := r.tcp
+		if proto == protoUDP {
+			ranges = r.udp
+		}
+		for _, pr := range ranges {
+			if port >= pr.from && port <= pr.to {
+				return r.category
+			}
+		}
+	}
+
+	return Other
+}
+
+// transport returns the transport protocol and its header of a TCP or UDP packet.
+func transport(packet []byte) (proto byte, l4 []byte, ok bool) {
+	if len(packet) == 0 {
+		return 0, nil, false
+	}
+
+	switch packet[0] >> 4 {
+	case 4:
+		if len(packet) < 20 {
+			return 0, nil, false
+		}
+		headerLen := int(packet[0]&0x0f) * 4
+		fragmentOffset := binary.BigEndian.Uint16(packet[6:8]) & 0x1fff
+		if headerLen < 20 || len(packet) < headerLen || fragmentOffset != 0 {
+			return 0, nil, false
+		}
+		proto, l4 = packet[9], packet[headerLen:]
+	case 6:
+		if len(packet) < 40 {
+			return 0, nil, false
+		}
+		proto, l4 = packet[6], packet[40:]
+	default:
+		return 0, nil, false
+	}
+
+	return proto, l4, proto == protoTCP || proto == protoUDP
+}
+
+// Counters holds the amount of traffic in a single category.
+type Counters struct {
+	BytesSent     uint64 `json:"bytes_sent"`
+	BytesReceived uint64 `json:"bytes_received"`
+}
+
+// Breakdown holds traffic amounts per category.
+type Breakdown map[Category]Counters
+
+// Add accumulates the given breakdown into this one.
+func (b Breakdown) Add(other Breakdown) {
+	for category, c := range other {
+		sum := b[category]
+		sum.BytesSent += c.BytesSent
+		sum.BytesReceived += c.BytesReceived
+		b[category] = sum
+	}
+}
+
+// Analyzer aggregates classified traffic of a single tunnel.
+type Analyzer struct {
+	sent     [4]uint64
+	received [4]uint64
+}
+
+// NewAnalyzer returns a new traffic analyzer.
+func NewAnalyzer() *Analyzer {
+	return &Analyzer{}
+}
+
+// Count classifies the given packet and accounts its size.
+func (a *Analyzer) Count(packet []byte, outbound bool) {
+	idx := index(Classify(packet, outbound))
+	if outbound {
+		atomic.AddUint64(&a.sent[idx], uint64(len(packet)))
+	} else {
+		atomic.AddUint64(&a.received[idx], uint64(len(packet)))
+	}
+}
+
+// Breakdown returns the traffic counted so far. Categories without traffic are omitted.
+func (a *Analyzer) Breakdown() Breakdown {
+	res := make(Breakdown)
+	for i, category := range Categories {
+		c := Counters{
+			BytesSent:     atomic.LoadUint64(&a.sent[i]),
+			BytesReceived: atomic.LoadUint64(&a.received[i]),
+		}
+		if c.BytesSent > 0 || c.BytesReceived > 0 {
+			res[category] = c
+		}
+	}
+	return res
+}
+
+func index(category Category) int {
+	for i, c := range Categories {
+		if c == category {
+			return i
+		}
+	}
+	return len(Categories) - 1
+}
@@ -0,0 +1,98 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package trafficcategory
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func ipv4Packet(proto byte, srcPort, dstPort uint16, payload int) []byte {
+	p := make([]byte, 20+8+payload)
+	p[0] = 0x45
+	p[9] = proto
+	binary.BigEndian.PutUint16(p[20:22], srcPort)
+	binary.BigEndian.PutUint16(p[22:24], dstPort)
+	return p
+}
+
+func ipv6Packet(proto byte, srcPort, dstPort uint16) []byte {
+	p := make([]byte, 40+8)
+	p[0] = 0x60
+	p[6] = proto
+	binary.BigEndian.PutUint16(p[40:42], srcPort)
+	binary.BigEndian.PutUint16(p[42:44], dstPort)
+	return p
+}
+
+func TestClassify(t *testing.T) {
+	fragment := ipv4Packet(protoTCP, 50000, 443, 0)
+	binary.BigEndian.PutUint16(fragment[6:8], 100)
+
+	tests := map[string]struct {
+		packet   []byte
+		outbound bool
+		want     Category
+	}{
+		"https outbound":           {ipv4Packet(protoTCP, 50000, 443, 0), true, Web},
+		"https inbound":            {ipv4Packet(protoTCP, 443, 50000, 0), false, Web},
+		"quic outbound":            {ipv4Packet(protoUDP, 50000, 443, 0), true, Web},
+		"rtmp outbound":            {ipv4Packet(protoTCP, 50000, 1935, 0), true, Streaming},
+		"steam inbound":            {ipv4Packet(protoUDP, 27015, 50000, 0), false, Gaming},
+		"ipv6 rtp outbound":        {ipv6Packet(protoUDP, 50000, 5004), true, Streaming},
+		"unknown port":             {ipv4Packet(protoTCP, 50000, 22, 0), true, Other},
+		"tcp only gaming port udp": {ipv4Packet(protoUDP, 50000, 25565, 0), true, Other},
+		"non transport protocol":   {ipv4Packet(1, 0, 443, 0), true, Other},
+		"fragment":                 {fragment, true, Other},
+		"truncated":                {[]byte{0x45, 0, 0}, true, Other},
+		"empty":                    {nil, true, Other},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Classify(tt.packet, tt.outbound))
+		})
+	}
+}
+
+func TestAnalyzer_Breakdown(t *testing.T) {
+	a := NewAnalyzer()
+	assert.Equal(t, Breakdown{}, a.Breakdown())
+
+	a.Count(ipv4Packet(protoTCP, 50000, 443, 12), true)
+	a.Count(ipv4Packet(protoTCP, 443, 50000, 72), false)
+	a.Count(ipv4Packet(protoUDP, 27015, 50000, 2), false)
+	a.Count(ipv4Packet(protoTCP, 50000, 22, 0), true)
+
+	assert.Equal(t, Breakdown{
+		Web:    {BytesSent: 40, BytesReceived: 100},
+		Gaming: {BytesReceived: 30},
+		Other:  {BytesSent: 28},
+	}, a.Breakdown())
+}
+
+func TestBreakdown_Add(t *testing.T) {
+	b := Breakdown{Web: {BytesSent: 1, BytesReceived: 2}}
+	b.Add(Breakdown{Web: {BytesSent: 10}, Other: {BytesReceived: 5}})
+
+	assert.Equal(t, Breakdown{
+		Web:   {BytesSent: 11, BytesReceived: 2},
+		Other: {BytesReceived: 5},
+	}, b)
+}
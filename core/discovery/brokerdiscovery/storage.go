@@ -19,6 +19,7 @@ package brokerdiscovery
 
 import (
 	"fmt"
+	"sort"
 	"sync"
 
 	"github.com/mysteriumnetwork/node/core/discovery"
@@ -30,12 +31,16 @@ import (
 // ProposalReducer proposal match function
 type ProposalReducer func(proposal market.ServiceProposal) bool
 
+type proposalSet map[market.ProposalID]struct{}
+
 // NewStorage creates new instance of ProposalStorage
 func NewStorage(eventPublisher eventbus.Publisher) *ProposalStorage {
-	return &ProposalStorage{
+	s := &ProposalStorage{
 		eventPublisher: eventPublisher,
 		proposals:      make([]market.ServiceProposal, 0),
 	}
+	s.reindex()
+	return s
 }
 
 // ProposalStorage represents table of currently active proposals in Mysterium Discovery
@@ -44,12 +49,18 @@ type ProposalStorage struct {
 
 	proposals []market.ServiceProposal
 	mutex     sync.RWMutex
+
+	// Indices of proposals, so that filtering by country or service type
+	// does not have to go through all the proposals.
+	positions     map[market.ProposalID]int
+	byCountry     map[string]proposalSet
+	byServiceType map[string]proposalSet
 }
 
 // Proposals returns list of proposals in storage
 func (s *ProposalStorage) Proposals() []market.ServiceProposal {
-	s.mutex.Lock()
-	defer s.mutex.Unlock()
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
 
 	proposals := make([]market.ServiceProposal, 0)
 	proposals = append(proposals, s.proposals...)
@@ -58,8 +69,8 @@ func (s *ProposalStorage) Proposals() []market.ServiceProposal {
 
 // MatchProposals fetches currently active service proposals from storage by match function
 func (s *ProposalStorage) MatchProposals(match ProposalReducer) ([]market.ServiceProposal, error) {
-	s.mutex.Lock()
-	defer s.mutex.Unlock()
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
 
 	proposals := make([]market.ServiceProposal, 0)
 	for _, p := range s.proposals {
@@ -72,12 +83,26 @@ func (s *ProposalStorage) MatchProposals(match ProposalReducer) ([]market.Servic
 
 // FindProposals fetches currently active service proposals from storage by given filter
 func (s *ProposalStorage) FindProposals(filter *proposal.Filter) ([]market.ServiceProposal, error) {
-	return s.MatchProposals(filter.Matches)
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	candidates, indexed := s.candidates(filter)
+	if !indexed {
+		candidates = s.proposals
+	}
+
+	proposals := make([]market.ServiceProposal, 0)
+	for _, p := range candidates {
+		if filter.Matches(p) {
+			proposals = append(proposals, p)
+		}
+	}
+	return proposals, nil
 }
 
 // Countries fetches currently active service proposals from storage by given filter
 func (s *ProposalStorage) Countries(filter *proposal.Filter) (map[string]int, error) {
-	proposals, err := s.MatchProposals(filter.Matches)
+	proposals, err := s.FindProposals(filter)
 	if err != nil {
 		return nil, err
 	}
@@ -95,37 +120,40 @@ func (s *ProposalStorage) Set(proposals []market.ServiceProposal) {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
-	proposalsOld := s.proposals
+	updated := make(map[market.ProposalID]struct{}, len(proposals))
 	for _, p := range proposals {
-		index, exist := s.getProposalIndex(proposalsOld, p.UniqueID())
-		if exist {
+		id := p.UniqueID()
+		if _, exist := s.positions[id]; exist {
 			go s.eventPublisher.Publish(discovery.AppTopicProposalUpdated, p)
-			proposalsOld = append(proposalsOld[:index], proposalsOld[index+1:]...)
+			updated[id] = struct{}{}
 		} else {
 			go s.eventPublisher.Publish(discovery.AppTopicProposalAdded, p)
 		}
 	}
-	for _, p := range proposalsOld {
-		go s.eventPublisher.Publish(discovery.AppTopicProposalRemoved, p)
+	for _, p := range s.proposals {
+		if _, exist := updated[p.UniqueID()]; !exist {
+			go s.eventPublisher.Publish(discovery.AppTopicProposalRemoved, p)
+		}
 	}
 	s.proposals = proposals
+	s.reindex()
 }
 
 // HasProposal checks if proposal exists in storage
 func (s *ProposalStorage) HasProposal(id market.ProposalID) bool {
-	s.mutex.Lock()
-	defer s.mutex.Unlock()
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
 
-	_, exist := s.getProposalIndex(s.proposals, id)
+	_, exist := s.positions[id]
 	return exist
 }
 
 // GetProposal returns proposal from storage
 func (s *ProposalStorage) GetProposal(id market.ProposalID) (*market.ServiceProposal, error) {
-	s.mutex.Lock()
-	defer s.mutex.Unlock()
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
 
-	index, exist := s.getProposalIndex(s.proposals, id)
+	index, exist := s.positions[id]
 	if !exist {
 		return nil, fmt.Errorf(`proposal does not exist: %v`, id)
 	}
@@ -138,12 +166,17 @@ func (s *ProposalStorage) AddProposal(proposals ...market.ServiceProposal) {
 	defer s.mutex.Unlock()
 
 	for _, p := range proposals {
-		if index, exist := s.getProposalIndex(s.proposals, p.UniqueID()); !exist {
+		id := p.UniqueID()
+		if index, exist := s.positions[id]; !exist {
 			s.eventPublisher.Publish(discovery.AppTopicProposalAdded, p)
 			s.proposals = append(s.proposals, p)
+			s.positions[id] = len(s.proposals) - 1
+			s.index(id, p)
 		} else {
 			s.eventPublisher.Publish(discovery.AppTopicProposalUpdated, p)
+			s.unindex(id, s.proposals[index])
 			s.proposals[index] = p
+			s.index(id, p)
 		}
 	}
 }
@@ -153,18 +186,82 @@ func (s *ProposalStorage) RemoveProposal(id market.ProposalID) {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
-	if index, exist := s.getProposalIndex(s.proposals, id); exist {
+	if index, exist := s.positions[id]; exist {
 		go s.eventPublisher.Publish(discovery.AppTopicProposalRemoved, s.proposals[index])
 		s.proposals = append(s.proposals[:index], s.proposals[index+1:]...)
+		s.reindex()
 	}
 }
 
-func (s *ProposalStorage) getProposalIndex(proposals []market.ServiceProposal, id market.ProposalID) (int, bool) {
-	for index, p := range proposals {
-		if p.UniqueID() == id {
-			return index, true
+// candidates narrows down the proposals to the ones in the smallest index matching the filter.
+// It reports false when the filter can not be answered by the indices.
+func (s *ProposalStorage) candidates(filter *proposal.Filter) ([]market.ServiceProposal, bool) {
+	var sets []proposalSet
+	if filter.LocationCountry != "" {
+		sets = append(sets, s.byCountry[filter.LocationCountry])
+	}
+	if filter.ServiceType != "" {
+		sets = append(sets, s.byServiceType[filter.ServiceType])
+	}
+	if len(sets) == 0 {
+		return nil, false
+	}
+
+	smallest := sets[0]
+	for _, set := range sets[1:] {
+		if len(set) < len(smallest) {
+			smallest = set
 		}
 	}
 
-	return 0, false
+	// Keep the storage order of proposals.
+	positions := make([]int, 0, len(smallest))
+	for id := range smallest {
+		positions = append(positions, s.positions[id])
+	}
+	sort.Ints(positions)
+
+	candidates := make([]market.ServiceProposal, len(positions))
+	for i, position := range positions {
+		candidates[i] = s.proposals[position]
+	}
+	return candidates, true
+}
+
+func (s *ProposalStorage) reindex() {
+	s.positions = make(map[market.ProposalID]int, len(s.proposals))
+	s.byCountry = make(map[string]proposalSet)
+	s.byServiceType = make(map[string]proposalSet)
+	for i, p := range s.proposals {
+		id := p.UniqueID()
+		s.positions[id] = i
+		s.index(id, p)
+	}
+}
+
+func (s *ProposalStorage) index(id market.ProposalID, p market.ServiceProposal) {
+	addToIndex(s.byCountry, p.Location.Country, id)
+	addToIndex(s.byServiceType, p.ServiceType, id)
+}
+
+func (s *ProposalStorage) unindex(id market.ProposalID, p market.ServiceProposal) {
+	removeFromIndex(s.byCountry, p.Location.Country, id)
+	removeFromIndex(s.byServiceType, p.ServiceType, id)
+}
+
+func addToIndex(index map[string]proposalSet, key string, id market.ProposalID) {
+	set, ok := index[key]
+	if !ok {
+		set = make(proposalSet)
+		index[key] = set
+	}
+	set[id] = struct{}{}
+}
+
+func removeFromIndex(index map[string]proposalSet, key string, id market.ProposalID) {
+	set := index[key]
+	delete(set, id)
+	if len(set) == 0 {
+		delete(index, key)
+	}
 }
@@ -18,6 +18,7 @@
 package brokerdiscovery
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/mysteriumnetwork/node/core/discovery/proposal"
@@ -94,6 +95,35 @@ func Test_Finder_FindProposals(t *testing.T) {
 	)
 }
 
+func Test_Finder_FindProposals_FollowsIndexedChanges(t *testing.T) {
+	storage := createFullStorage()
+	moved := proposalProvider1Noop
+	moved.Location.Country = "LT"
+
+	storage.AddProposal(moved)
+	proposals, err := storage.FindProposals(&proposal.Filter{LocationCountry: "LT"})
+	assert.NoError(t, err)
+	assert.Exactly(t, []market.ServiceProposal{moved}, proposals)
+
+	proposals, err = storage.FindProposals(&proposal.Filter{LocationCountry: "LT", ServiceType: "streaming"})
+	assert.NoError(t, err)
+	assert.Empty(t, proposals)
+
+	storage.RemoveProposal(proposalProvider1Streaming.UniqueID())
+	proposals, err = storage.FindProposals(&proposal.Filter{ServiceType: "streaming"})
+	assert.NoError(t, err)
+	assert.Exactly(t, []market.ServiceProposal{proposalProvider2Streaming}, proposals)
+
+	storage.Set([]market.ServiceProposal{proposalProvider1Streaming})
+	proposals, err = storage.FindProposals(&proposal.Filter{LocationCountry: "LT"})
+	assert.NoError(t, err)
+	assert.Empty(t, proposals)
+
+	countries, err := storage.Countries(&proposal.Filter{ServiceType: "streaming"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"": 1}, countries)
+}
+
 func Test_Storage_HasProposal(t *testing.T) {
 	storage := createEmptyStorage()
 	assert.False(t, storage.HasProposal(market.ProposalID{ServiceType: "unknown", ProviderID: "0x1"}))
@@ -211,17 +241,39 @@ func Test_Storage_RemoveProposal(t *testing.T) {
 }
 
 func createEmptyStorage() *ProposalStorage {
-	return &ProposalStorage{
-		eventPublisher: eventbus.New(),
-		proposals:      []market.ServiceProposal{},
-	}
+	return NewStorage(eventbus.New())
 }
 
 func createFullStorage() *ProposalStorage {
-	return &ProposalStorage{
-		eventPublisher: eventbus.New(),
-		proposals: []market.ServiceProposal{
-			proposalProvider1Streaming, proposalProvider1Noop, proposalProvider2Streaming,
-		},
+	storage := NewStorage(eventbus.New())
+	storage.proposals = []market.ServiceProposal{
+		proposalProvider1Streaming, proposalProvider1Noop, proposalProvider2Streaming,
+	}
+	storage.reindex()
+	return storage
+}
+
+func BenchmarkStorage_FindProposals(b *testing.B) {
+	countries := []string{"US", "DE", "LT", "GB", "FR", "NL", "CA", "JP", "BR", "IN"}
+	serviceTypes := []string{"wireguard", "scraping", "data_transfer", "dvpn"}
+
+	proposals := make([]market.ServiceProposal, 10_000)
+	for i := range proposals {
+		proposals[i] = market.ServiceProposal{
+			ProviderID:  fmt.Sprintf("0x%d", i),
+			ServiceType: serviceTypes[i%len(serviceTypes)],
+			Location:    market.Location{Country: countries[i%len(countries)]},
+		}
+	}
+	storage := createEmptyStorage()
+	storage.proposals = proposals
+	storage.reindex()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := storage.FindProposals(&proposal.Filter{LocationCountry: "LT", ServiceType: "wireguard"}); err != nil {
+			b.Fatal(err)
+		}
 	}
 }
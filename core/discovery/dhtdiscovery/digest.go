@@ -0,0 +1,97 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package dhtdiscovery
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/market"
+)
+
+// Digest is a compact service proposal announcement gossiped to the connected peers.
+type Digest struct {
+	Proposal  market.ServiceProposal `json:"proposal"`
+	ExpiresAt int64                  `json:"expires_at"`
+}
+
+// NewDigest creates digest of the proposal which stays valid for the given ttl.
+// Only the fields needed to pick and contact the provider are kept, quality is measured by consumers.
+func NewDigest(proposal market.ServiceProposal, ttl time.Duration) Digest {
+	return Digest{
+		Proposal: market.ServiceProposal{
+			ID:            proposal.ID,
+			Format:        proposal.Format,
+			Compatibility: proposal.Compatibility,
+			ProviderID:    proposal.ProviderID,
+			ServiceType:   proposal.ServiceType,
+			Location: market.Location{
+				Country: proposal.Location.Country,
+				IPType:  proposal.Location.IPType,
+			},
+			Contacts:       proposal.Contacts,
+			AccessPolicies: proposal.AccessPolicies,
+			Metadata:       proposal.Metadata,
+		},
+		ExpiresAt: time.Now().Add(ttl).Unix(),
+	}
+}
+
+// Key identifies the proposal the digest announces, newer digests replace older ones of the same key.
+func (d Digest) Key() string {
+	return d.Proposal.ProviderID + "|" + d.Proposal.ServiceType
+}
+
+func (d Digest) expired(now time.Time) bool {
+	return now.Unix() >= d.ExpiresAt
+}
+
+// SignedDigest is a digest signed by the identity of the proposal provider.
+type SignedDigest struct {
+	Digest    []byte `json:"digest"`
+	Signature string `json:"signature"`
+}
+
+func signDigest(digest Digest, signer identity.Signer) (SignedDigest, error) {
+	payload, err := json.Marshal(digest)
+	if err != nil {
+		return SignedDigest{}, fmt.Errorf("could not marshal digest: %w", err)
+	}
+
+	signature, err := signer.Sign(payload)
+	if err != nil {
+		return SignedDigest{}, fmt.Errorf("could not sign digest: %w", err)
+	}
+
+	return SignedDigest{Digest: payload, Signature: signature.Base64()}, nil
+}
+
+func (sd SignedDigest) verify() (Digest, error) {
+	var digest Digest
+	if err := json.Unmarshal(sd.Digest, &digest); err != nil {
+		return Digest{}, fmt.Errorf("could not unmarshal digest: %w", err)
+	}
+
+	provider := identity.FromAddress(digest.Proposal.ProviderID)
+	if ok, _ := identity.NewVerifierIdentity(provider).Verify(sd.Digest, identity.SignatureBase64(sd.Signature)); !ok {
+		return Digest{}, fmt.Errorf("digest is not signed by provider %s", provider.Address)
+	}
+	return digest, nil
+}
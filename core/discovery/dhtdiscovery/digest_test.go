@@ -0,0 +1,157 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package dhtdiscovery
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/market"
+)
+
+type keySigner struct {
+	key *ecdsa.PrivateKey
+}
+
+func (s *keySigner) Sign(message []byte) (identity.Signature, error) {
+	signature, err := crypto.Sign(crypto.Keccak256(message), s.key)
+	return identity.SignatureBytes(signature), err
+}
+
+func newTestProposal(t *testing.T) (market.ServiceProposal, identity.Signer) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	proposal := market.ServiceProposal{
+		Format:      "service-proposal/v3",
+		ProviderID:  crypto.PubkeyToAddress(key.PublicKey).Hex(),
+		ServiceType: "wireguard",
+		Location:    market.Location{Country: "LT", City: "Vilnius", IPType: "residential"},
+		Quality:     market.Quality{Quality: 2},
+	}
+	return proposal, &keySigner{key: key}
+}
+
+func TestDigestStore_Put(t *testing.T) {
+	proposal, signer := newTestProposal(t)
+	store := newDigestStore(0, nil)
+
+	signed, err := signDigest(NewDigest(proposal, time.Minute), signer)
+	require.NoError(t, err)
+	_, err = store.put(signed)
+	require.NoError(t, err)
+
+	proposals := store.proposals()
+	require.Len(t, proposals, 1)
+	assert.Equal(t, proposal.ProviderID, proposals[0].ProviderID)
+	assert.Equal(t, "LT", proposals[0].Location.Country)
+	assert.Empty(t, proposals[0].Location.City)
+
+	// digest signed by another identity is rejected
+	other, otherSigner := newTestProposal(t)
+	forged, err := signDigest(NewDigest(proposal, time.Minute), otherSigner)
+	require.NoError(t, err)
+	_, err = store.put(forged)
+	assert.Error(t, err)
+
+	expired, err := signDigest(NewDigest(other, -time.Second), otherSigner)
+	require.NoError(t, err)
+	_, err = store.put(expired)
+	assert.ErrorIs(t, err, errDigestExpired)
+
+	store.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	assert.Empty(t, store.proposals())
+}
+
+func TestDigestStore_EvictsDigestsExpiringFirst(t *testing.T) {
+	store := newDigestStore(2, nil)
+
+	put := func(ttl time.Duration) (market.ServiceProposal, error) {
+		proposal, signer := newTestProposal(t)
+		signed, err := signDigest(NewDigest(proposal, ttl), signer)
+		require.NoError(t, err)
+		_, err = store.put(signed)
+		return proposal, err
+	}
+
+	shortLived, err := put(time.Minute)
+	require.NoError(t, err)
+	_, err = put(time.Hour)
+	require.NoError(t, err)
+
+	// Shorter lived digest than the stored ones is rejected.
+	_, err = put(time.Second * 30)
+	assert.ErrorIs(t, err, errStoreFull)
+
+	_, err = put(2 * time.Hour)
+	require.NoError(t, err)
+
+	proposals := store.proposals()
+	assert.Len(t, proposals, 2)
+	for _, p := range proposals {
+		assert.NotEqual(t, shortLived.ProviderID, p.ProviderID)
+	}
+}
+
+func TestDigestStore_Persists(t *testing.T) {
+	storage := &mockDigestStorage{values: make(map[string][]SignedDigest)}
+	proposal, signer := newTestProposal(t)
+	signed, err := signDigest(NewDigest(proposal, time.Minute), signer)
+	require.NoError(t, err)
+
+	store := newDigestStore(10, storage)
+	_, err = store.put(signed)
+	require.NoError(t, err)
+	store.save()
+
+	restored := newDigestStore(10, storage)
+	restored.load()
+	proposals := restored.proposals()
+	require.Len(t, proposals, 1)
+	assert.Equal(t, proposal.ProviderID, proposals[0].ProviderID)
+
+	expired := newDigestStore(10, storage)
+	expired.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	expired.load()
+	assert.Empty(t, expired.proposals())
+}
+
+type mockDigestStorage struct {
+	values map[string][]SignedDigest
+}
+
+func (m *mockDigestStorage) GetValue(bucket string, key interface{}, to interface{}) error {
+	v, ok := m.values[bucket]
+	if !ok {
+		return errors.New("not found")
+	}
+	*to.(*[]SignedDigest) = v
+	return nil
+}
+
+func (m *mockDigestStorage) SetValue(bucket string, key interface{}, to interface{}) error {
+	m.values[bucket] = to.([]SignedDigest)
+	return nil
+}
@@ -20,15 +20,21 @@ package dhtdiscovery
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/libp2p/go-libp2p"
 	"github.com/libp2p/go-libp2p/core/host"
+	"github.com/libp2p/go-libp2p/core/network"
 	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/multiformats/go-multiaddr"
 	"github.com/rs/zerolog/log"
 )
 
-// Node represents DHT server-client in P2P network.
+// Node represents libp2p node of DHT discovery type. Despite the name it is not a Kademlia DHT:
+// there is no routing table and no lookup by key, the node only talks to the peers it is directly
+// connected to, which are the bootstrap peers. Proposal digests are gossiped one hop at a time,
+// so a consumer discovers only providers whose digests reached its peers. It supplements
+// the discovery API and does not replace it.
 type Node struct {
 	libP2PConfig     libp2p.Config
 	libP2PNode       host.Host
@@ -36,12 +42,42 @@ type Node struct {
 	libP2PNodeCancel context.CancelFunc
 
 	bootstrapPeers []*peer.AddrInfo
+	exchange       *DigestExchange
+	store          *digestStore
 }
 
-// NewNode create an instance of DHT node.
-func NewNode(listenAddress string, bootstrapPeerAddresses []string) (*Node, error) {
+// DigestExchange configures exchange of signed proposal digests with directly connected peers.
+// Published digests are pushed to the connected peers, which hand out every digest they hold
+// when their peers fetch. Nothing is routed further than the connected peers.
+type DigestExchange struct {
+	// FetchInterval is how often digests are requested from the connected peers.
+	FetchInterval time.Duration
+	// MaxDigests limits the number of digests kept in memory.
+	MaxDigests int
+	// Storage persists the digests between node restarts, optional.
+	Storage digestStorage
+}
+
+const (
+	// DefaultMaxDigests is the default limit of digests kept by the node.
+	DefaultMaxDigests = 10000
+
+	// maxPublishPeers is the number of connected peers a digest is pushed to, others learn it on fetch.
+	maxPublishPeers = 20
+	streamTimeout   = 10 * time.Second
+)
+
+// NewNode create an instance of DHT discovery node. Digests are exchanged with peers only if exchange is configured.
+func NewNode(listenAddress string, bootstrapPeerAddresses []string, exchange *DigestExchange) (*Node, error) {
 	node := &Node{
 		bootstrapPeers: make([]*peer.AddrInfo, len(bootstrapPeerAddresses)),
+		exchange:       exchange,
+	}
+	if exchange != nil {
+		node.store = newDigestStore(exchange.MaxDigests, exchange.Storage)
+		node.store.load()
+	} else {
+		node.store = newDigestStore(0, nil)
 	}
 
 	// Parse and validate configuration
@@ -72,7 +108,7 @@ func NewNode(listenAddress string, bootstrapPeerAddresses []string) (*Node, erro
 	return node, nil
 }
 
-// Start begins DHT bootstrapping process.
+// Start starts libp2p host and connects to the bootstrap peers.
 func (n *Node) Start() (err error) {
 	// Prepare context which stops the libp2p host.
 	n.libP2PNodeCtx, n.libP2PNodeCancel = context.WithCancel(context.Background())
@@ -90,6 +126,11 @@ func (n *Node) Start() (err error) {
 		go n.connectToPeer(*peerInfo)
 	}
 
+	if n.exchange != nil {
+		n.libP2PNode.SetStreamHandler(digestProtocolID, n.handleStream)
+		go n.fetchLoop()
+	}
+
 	return nil
 }
 
@@ -97,6 +138,7 @@ func (n *Node) Start() (err error) {
 func (n *Node) Stop() {
 	n.libP2PNodeCancel()
 	n.libP2PNode.Close()
+	n.store.save()
 }
 
 func (n *Node) connectToPeer(peerInfo peer.AddrInfo) {
@@ -108,3 +150,122 @@ func (n *Node) connectToPeer(peerInfo peer.AddrInfo) {
 
 	log.Info().Msgf("Connection established with DHT peer: %v", peerInfo)
 }
+
+// exchangeEnabled reports whether digests are exchanged with peers.
+func (n *Node) exchangeEnabled() bool {
+	return n.exchange != nil
+}
+
+// Publish stores the digest locally and pushes it to some of the connected peers.
+func (n *Node) Publish(signed SignedDigest) error {
+	if !n.exchangeEnabled() {
+		return nil
+	}
+	if _, err := n.store.put(signed); err != nil {
+		return err
+	}
+	if n.libP2PNode == nil {
+		return nil
+	}
+
+	peers := n.libP2PNode.Network().Peers()
+	if len(peers) > maxPublishPeers {
+		peers = peers[:maxPublishPeers]
+	}
+	for _, id := range peers {
+		go func(id peer.ID) {
+			if err := n.send(id, message{Type: messagePut, Digests: []SignedDigest{signed}}, false); err != nil {
+				log.Debug().Err(err).Msgf("Failed to publish digest to DHT peer %s", id)
+			}
+		}(id)
+	}
+	return nil
+}
+
+// Unpublish removes the digest from the local store, remote copies expire on their own.
+func (n *Node) Unpublish(key string) {
+	n.store.remove(key)
+}
+
+func (n *Node) fetchLoop() {
+	if n.exchange.FetchInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(n.exchange.FetchInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-n.libP2PNodeCtx.Done():
+			return
+		case <-ticker.C:
+			n.fetch()
+			n.store.save()
+		}
+	}
+}
+
+func (n *Node) fetch() {
+	for _, id := range n.libP2PNode.Network().Peers() {
+		if err := n.send(id, message{Type: messageGet}, true); err != nil {
+			log.Debug().Err(err).Msgf("Failed to fetch digests from DHT peer %s", id)
+		}
+	}
+}
+
+func (n *Node) send(id peer.ID, msg message, expectReply bool) error {
+	ctx, cancel := context.WithTimeout(n.libP2PNodeCtx, streamTimeout)
+	defer cancel()
+
+	s, err := n.libP2PNode.NewStream(ctx, id, digestProtocolID)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+	s.SetDeadline(time.Now().Add(streamTimeout))
+
+	if err := writeMessage(s, msg); err != nil {
+		return err
+	}
+	if err := s.CloseWrite(); err != nil {
+		return err
+	}
+	if !expectReply {
+		return nil
+	}
+
+	reply, err := readMessage(s)
+	if err != nil {
+		return err
+	}
+	n.storeDigests(reply.Digests)
+	return nil
+}
+
+func (n *Node) handleStream(s network.Stream) {
+	defer s.Close()
+	s.SetDeadline(time.Now().Add(streamTimeout))
+
+	msg, err := readMessage(s)
+	if err != nil {
+		log.Debug().Err(err).Msgf("Invalid message from DHT peer %s", s.Conn().RemotePeer())
+		return
+	}
+
+	switch msg.Type {
+	case messagePut:
+		n.storeDigests(msg.Digests)
+	case messageGet:
+		if err := writeMessage(s, message{Type: messagePut, Digests: n.store.signed()}); err != nil {
+			log.Debug().Err(err).Msgf("Failed to reply to DHT peer %s", s.Conn().RemotePeer())
+		}
+	}
+}
+
+func (n *Node) storeDigests(digests []SignedDigest) {
+	for _, d := range digests {
+		if _, err := n.store.put(d); err != nil {
+			log.Debug().Err(err).Msg("Skipping DHT digest")
+		}
+	}
+}
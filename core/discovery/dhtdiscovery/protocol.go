@@ -0,0 +1,56 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package dhtdiscovery
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/protocol"
+)
+
+const (
+	digestProtocolID = protocol.ID("/mysterium/discovery/digests/1.0.0")
+
+	messagePut = "put"
+	messageGet = "get"
+
+	maxMessageSize = 4 << 20
+)
+
+type message struct {
+	Type    string         `json:"type"`
+	Digests []SignedDigest `json:"digests,omitempty"`
+}
+
+func writeMessage(s network.Stream, msg message) error {
+	if err := json.NewEncoder(s).Encode(msg); err != nil {
+		return fmt.Errorf("could not write DHT message: %w", err)
+	}
+	return nil
+}
+
+func readMessage(s network.Stream) (message, error) {
+	var msg message
+	if err := json.NewDecoder(io.LimitReader(s, maxMessageSize)).Decode(&msg); err != nil {
+		return message{}, fmt.Errorf("could not read DHT message: %w", err)
+	}
+	return msg, nil
+}
@@ -18,29 +18,50 @@
 package dhtdiscovery
 
 import (
+	"time"
+
 	"github.com/mysteriumnetwork/node/identity"
 	"github.com/mysteriumnetwork/node/market"
 )
 
 type registryDHT struct {
+	node *Node
+	ttl  time.Duration
 }
 
-// NewRegistry create an instance of Broker registryBroker.
-func NewRegistry() *registryDHT {
-	return &registryDHT{}
+// NewRegistry create an instance of DHT discovery registry, which gossips digests of the registered proposals.
+// Published digests stay valid for the ttl and have to be refreshed by pings.
+func NewRegistry(node *Node, ttl time.Duration) *registryDHT {
+	return &registryDHT{
+		node: node,
+		ttl:  ttl,
+	}
 }
 
 // RegisterProposal registers service proposal to discovery service.
 func (rd *registryDHT) RegisterProposal(proposal market.ServiceProposal, signer identity.Signer) error {
-	return nil
+	return rd.publish(proposal, signer)
 }
 
 // UnregisterProposal unregisters a service proposal when client disconnects.
 func (rd *registryDHT) UnregisterProposal(proposal market.ServiceProposal, signer identity.Signer) error {
+	rd.node.Unpublish(NewDigest(proposal, 0).Key())
 	return nil
 }
 
 // PingProposal pings service proposal as being alive.
 func (rd *registryDHT) PingProposal(proposal market.ServiceProposal, signer identity.Signer) error {
-	return nil
+	return rd.publish(proposal, signer)
+}
+
+func (rd *registryDHT) publish(proposal market.ServiceProposal, signer identity.Signer) error {
+	if !rd.node.exchangeEnabled() {
+		return nil
+	}
+
+	signed, err := signDigest(NewDigest(proposal, rd.ttl), signer)
+	if err != nil {
+		return err
+	}
+	return rd.node.Publish(signed)
 }
@@ -18,38 +18,61 @@
 package dhtdiscovery
 
 import (
+	"fmt"
 	"sync"
 
 	"github.com/mysteriumnetwork/node/core/discovery/proposal"
 	"github.com/mysteriumnetwork/node/market"
 )
 
-// Repository provides proposals from the DHT.
+// Repository provides proposals from digests received from the connected peers.
 type Repository struct {
+	node     *Node
 	stopOnce sync.Once
 	stopChan chan struct{}
 }
 
-// NewRepository constructs a new proposal repository (backed by the DHT).
-func NewRepository() *Repository {
+// NewRepository constructs a new proposal repository (backed by the digests known to the node).
+func NewRepository(node *Node) *Repository {
 	return &Repository{
+		node:     node,
 		stopChan: make(chan struct{}),
 	}
 }
 
 // Proposal returns a single proposal by its ID.
 func (r *Repository) Proposal(id market.ProposalID) (*market.ServiceProposal, error) {
-	return nil, nil
+	for _, p := range r.node.store.proposals() {
+		if p.UniqueID() == id {
+			return &p, nil
+		}
+	}
+	return nil, fmt.Errorf("proposal does not exist: %v", id)
 }
 
 // Proposals returns proposals matching the filter.
 func (r *Repository) Proposals(filter *proposal.Filter) ([]market.ServiceProposal, error) {
-	return []market.ServiceProposal{}, nil
+	proposals := make([]market.ServiceProposal, 0)
+	for _, p := range r.node.store.proposals() {
+		if filter.Matches(p) {
+			proposals = append(proposals, p)
+		}
+	}
+	return proposals, nil
 }
 
 // Countries returns proposals per country matching the filter.
 func (r *Repository) Countries(filter *proposal.Filter) (map[string]int, error) {
-	return nil, nil
+	proposals, err := r.Proposals(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	countries := make(map[string]int)
+	for _, p := range proposals {
+		countries[p.Location.Country]++
+	}
+	return countries, nil
 }
 
 // Start begins proposals synchronization to storage.
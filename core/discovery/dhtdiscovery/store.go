@@ -0,0 +1,180 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package dhtdiscovery
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/market"
+)
+
+const (
+	digestBucket = "dht-digests"
+	digestKey    = "digests"
+)
+
+var (
+	errDigestExpired = errors.New("digest expired")
+	errStoreFull     = errors.New("digest store is full")
+)
+
+// digestStorage persists digests between node restarts.
+type digestStorage interface {
+	GetValue(bucket string, key interface{}, to interface{}) error
+	SetValue(bucket string, key interface{}, to interface{}) error
+}
+
+type storedDigest struct {
+	signed SignedDigest
+	digest Digest
+}
+
+// digestStore keeps verified digests until they expire.
+// Once the limit is reached, digests expiring first are evicted in favour of the longer lived ones.
+type digestStore struct {
+	mu      sync.Mutex
+	digests map[string]storedDigest
+	limit   int
+	storage digestStorage
+	now     func() time.Time
+}
+
+func newDigestStore(limit int, storage digestStorage) *digestStore {
+	return &digestStore{
+		digests: make(map[string]storedDigest),
+		limit:   limit,
+		storage: storage,
+		now:     time.Now,
+	}
+}
+
+// put verifies and stores the digest unless a newer one is already known.
+func (s *digestStore) put(signed SignedDigest) (Digest, error) {
+	digest, err := signed.verify()
+	if err != nil {
+		return Digest{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if digest.expired(s.now()) {
+		return Digest{}, errDigestExpired
+	}
+	existing, ok := s.digests[digest.Key()]
+	if ok && existing.digest.ExpiresAt >= digest.ExpiresAt {
+		return existing.digest, nil
+	}
+	if !ok && s.limit > 0 && len(s.digests) >= s.limit {
+		s.prune()
+		if len(s.digests) >= s.limit && !s.evictBefore(digest.ExpiresAt) {
+			return Digest{}, errStoreFull
+		}
+	}
+	s.digests[digest.Key()] = storedDigest{signed: signed, digest: digest}
+	return digest, nil
+}
+
+// evictBefore removes the digest expiring first if it expires before the given time.
+func (s *digestStore) evictBefore(expiresAt int64) bool {
+	var oldestKey string
+	var oldest int64
+	for key, d := range s.digests {
+		if oldestKey == "" || d.digest.ExpiresAt < oldest {
+			oldestKey, oldest = key, d.digest.ExpiresAt
+		}
+	}
+	if oldestKey == "" || oldest >= expiresAt {
+		return false
+	}
+	delete(s.digests, oldestKey)
+	return true
+}
+
+func (s *digestStore) remove(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.digests, key)
+}
+
+func (s *digestStore) signed() []SignedDigest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.prune()
+	result := make([]SignedDigest, 0, len(s.digests))
+	for _, d := range s.digests {
+		result = append(result, d.signed)
+	}
+	return result
+}
+
+func (s *digestStore) proposals() []market.ServiceProposal {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.prune()
+	result := make([]market.ServiceProposal, 0, len(s.digests))
+	for _, d := range s.digests {
+		result = append(result, d.digest.Proposal)
+	}
+	return result
+}
+
+func (s *digestStore) prune() {
+	now := s.now()
+	for key, d := range s.digests {
+		if d.digest.expired(now) {
+			delete(s.digests, key)
+		}
+	}
+}
+
+// load restores persisted digests which are still valid.
+func (s *digestStore) load() {
+	if s.storage == nil {
+		return
+	}
+
+	var persisted []SignedDigest
+	if err := s.storage.GetValue(digestBucket, digestKey, &persisted); err != nil {
+		log.Debug().Err(err).Msg("No persisted DHT digests")
+		return
+	}
+	for _, signed := range persisted {
+		if _, err := s.put(signed); err != nil {
+			log.Debug().Err(err).Msg("Skipping persisted DHT digest")
+		}
+	}
+}
+
+// save persists the digests which are still valid.
+func (s *digestStore) save() {
+	if s.storage == nil {
+		return
+	}
+
+	if err := s.storage.SetValue(digestBucket, digestKey, s.signed()); err != nil {
+		log.Warn().Err(err).Msg("Failed to persist DHT digests")
+	}
+}
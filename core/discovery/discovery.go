@@ -18,6 +18,7 @@
 package discovery
 
 import (
+	"math/rand"
 	"sync"
 	"time"
 
@@ -46,12 +47,38 @@ const (
 	StatusUndefined
 )
 
+// AnnounceConfig describes how proposal is announced to discovery.
+type AnnounceConfig struct {
+	// PingInterval is how often the registered proposal is pinged.
+	PingInterval time.Duration
+	// Jitter randomizes every delay by up to the given share of it, e.g. 0.1 for +-10%.
+	Jitter float64
+	// RetryDelay is the delay before retrying the first failed announcement, doubled with every next failure.
+	RetryDelay time.Duration
+	// MaxBackoff limits the delay between retries of failed announcements.
+	MaxBackoff time.Duration
+	// UnconfirmedTimeout is how long proposal may stay unconfirmed by discovery
+	// until AppTopicProposalUnconfirmed is published, disabled if zero.
+	UnconfirmedTimeout time.Duration
+}
+
+// DefaultAnnounceConfig returns announcement config with the given ping interval and default failure handling.
+func DefaultAnnounceConfig(pingInterval time.Duration) AnnounceConfig {
+	return AnnounceConfig{
+		PingInterval:       pingInterval,
+		Jitter:             0.1,
+		RetryDelay:         15 * time.Second,
+		MaxBackoff:         5 * time.Minute,
+		UnconfirmedTimeout: 10 * time.Minute,
+	}
+}
+
 // Discovery structure holds discovery service state
 type Discovery struct {
 	identityRegistry registry.IdentityRegistry
 	ownIdentity      identity.Identity
 	proposalRegistry ProposalRegistry
-	proposalPingTTL  time.Duration
+	announceConfig   AnnounceConfig
 	signerCreate     identity.SignerFactory
 	signer           identity.Signer
 	proposal         func() market.ServiceProposal
@@ -64,20 +91,26 @@ type Discovery struct {
 	once                        sync.Once
 
 	mu sync.RWMutex
+
+	announceLock        sync.Mutex
+	failures            int
+	nextPing            time.Duration
+	lastConfirmed       time.Time
+	unconfirmedReported bool
 }
 
 // NewService creates new discovery service
 func NewService(
 	identityRegistry registry.IdentityRegistry,
 	proposalRegistry ProposalRegistry,
-	proposalPingTTL time.Duration,
+	announceConfig AnnounceConfig,
 	signerCreate identity.SignerFactory,
 	eventBus eventbus.EventBus,
 ) *Discovery {
 	return &Discovery{
 		identityRegistry:            identityRegistry,
 		proposalRegistry:            proposalRegistry,
-		proposalPingTTL:             proposalPingTTL,
+		announceConfig:              announceConfig,
 		eventBus:                    eventBus,
 		signerCreate:                signerCreate,
 		statusChan:                  make(chan Status),
@@ -96,6 +129,7 @@ func (d *Discovery) Start(ownIdentity identity.Identity, proposal func() market.
 	d.ownIdentity = ownIdentity
 	d.signer = d.signerCreate(ownIdentity)
 	d.proposal = proposal
+	d.resetConfirmation(time.Now())
 
 	d.proposalAnnouncementStopped.Add(1)
 
@@ -185,12 +219,13 @@ func (d *Discovery) registerIdentity() {
 func (d *Discovery) registerProposal() {
 	proposal := d.proposal()
 	err := d.proposalRegistry.RegisterProposal(proposal, d.signer)
+	delay := d.announced(proposal, err, time.Now())
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to register proposal, retrying after 1 min")
+		log.Error().Err(err).Msgf("Failed to register proposal, retrying after %s", delay)
 		select {
 		case <-d.stop:
 			return
-		case <-time.After(1 * time.Minute):
+		case <-time.After(delay):
 			d.changeStatus(RegisterProposal)
 			return
 		}
@@ -203,17 +238,95 @@ func (d *Discovery) pingProposal() {
 	select {
 	case <-d.stop:
 		return
-	case <-time.After(d.proposalPingTTL):
+	case <-time.After(d.pingDelay()):
 		proposal := d.proposal()
 		err := d.proposalRegistry.PingProposal(proposal, d.signer)
+		delay := d.announced(proposal, err, time.Now())
 		if err != nil {
-			log.Error().Err(err).Msg("Failed to ping proposal")
+			log.Error().Err(err).Msgf("Failed to ping proposal, retrying after %s", delay)
 		}
 		d.eventBus.Publish(AppTopicProposalAnnounce, proposal)
 		d.changeStatus(PingProposal)
 	}
 }
 
+func (d *Discovery) resetConfirmation(now time.Time) {
+	d.announceLock.Lock()
+	defer d.announceLock.Unlock()
+
+	d.failures = 0
+	d.nextPing = jitter(d.announceConfig.PingInterval, d.announceConfig.Jitter)
+	d.lastConfirmed = now
+	d.unconfirmedReported = false
+}
+
+func (d *Discovery) pingDelay() time.Duration {
+	d.announceLock.Lock()
+	defer d.announceLock.Unlock()
+
+	return d.nextPing
+}
+
+// announced records the result of proposal announcement and returns the delay until the next one.
+// Failed announcements are retried with exponential backoff, proposal which stays unconfirmed
+// by discovery for too long is reported once until it is confirmed again.
+func (d *Discovery) announced(proposal market.ServiceProposal, err error, now time.Time) time.Duration {
+	d.announceLock.Lock()
+	defer d.announceLock.Unlock()
+
+	cfg := d.announceConfig
+	if err == nil {
+		if d.unconfirmedReported {
+			log.Info().Msgf("Proposal is confirmed by discovery again after %s", now.Sub(d.lastConfirmed))
+		}
+		d.failures = 0
+		d.lastConfirmed = now
+		d.unconfirmedReported = false
+		d.nextPing = jitter(cfg.PingInterval, cfg.Jitter)
+		return d.nextPing
+	}
+
+	d.failures++
+	unconfirmed := now.Sub(d.lastConfirmed)
+	if cfg.UnconfirmedTimeout > 0 && unconfirmed >= cfg.UnconfirmedTimeout && !d.unconfirmedReported {
+		d.unconfirmedReported = true
+		log.Warn().Msgf("Proposal is not confirmed by discovery for %s, it might not be visible to consumers", unconfirmed)
+		d.eventBus.Publish(AppTopicProposalUnconfirmed, AppEventProposalUnconfirmed{
+			ProviderID:    proposal.ProviderID,
+			ServiceType:   proposal.ServiceType,
+			LastConfirmed: d.lastConfirmed,
+			Failures:      d.failures,
+		})
+	}
+
+	d.nextPing = jitter(backoff(cfg.RetryDelay, cfg.MaxBackoff, d.failures), cfg.Jitter)
+	return d.nextPing
+}
+
+// backoff returns the delay before the given attempt to retry, doubling the initial delay up to the max one.
+func backoff(initial, maxDelay time.Duration, failures int) time.Duration {
+	delay := initial
+	for i := 1; i < failures && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	if maxDelay > 0 && delay > maxDelay {
+		delay = maxDelay
+	}
+	return delay
+}
+
+// jitter randomizes the delay by up to the given share of it in both directions.
+func jitter(delay time.Duration, share float64) time.Duration {
+	if share <= 0 || delay <= 0 {
+		return delay
+	}
+	if share > 1 {
+		share = 1
+	}
+
+	return delay + time.Duration((rand.Float64()*2-1)*share*float64(delay))
+}
+
 func (d *Discovery) unregisterProposal() {
 	proposal := d.proposal()
 	err := d.proposalRegistry.UnregisterProposal(proposal, d.signer)
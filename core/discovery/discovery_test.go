@@ -18,6 +18,7 @@
 package discovery
 
 import (
+	"errors"
 	"sync"
 	"testing"
 	"time"
@@ -45,7 +46,7 @@ func discoveryWithMockedDependencies() *Discovery {
 			return &identity.SignerFake{}
 		},
 		proposalRegistry: &mockedProposalRegistry{},
-		proposalPingTTL:  1 * time.Minute,
+		announceConfig:   DefaultAnnounceConfig(1 * time.Minute),
 		eventBus:         eventbus.New(),
 		stop:             make(chan struct{}),
 	}
@@ -114,6 +115,52 @@ func TestStartStopUnregisterProposal(t *testing.T) {
 	assert.Equal(t, ProposalUnregistered, actualStatus)
 }
 
+func TestAnnouncedBacksOffAndReportsUnconfirmedProposal(t *testing.T) {
+	d := discoveryWithMockedDependencies()
+	d.announceConfig = AnnounceConfig{
+		PingInterval:       time.Minute,
+		RetryDelay:         10 * time.Second,
+		MaxBackoff:         30 * time.Second,
+		UnconfirmedTimeout: 25 * time.Second,
+	}
+	events := make(chan AppEventProposalUnconfirmed, 10)
+	assert.NoError(t, d.eventBus.Subscribe(AppTopicProposalUnconfirmed, func(e AppEventProposalUnconfirmed) {
+		events <- e
+	}))
+
+	start := time.Now()
+	d.resetConfirmation(start)
+	failed := errors.New("discovery is down")
+
+	assert.Equal(t, 10*time.Second, d.announced(serviceProposal, failed, start.Add(10*time.Second)))
+	assert.Equal(t, 20*time.Second, d.announced(serviceProposal, failed, start.Add(20*time.Second)))
+	assert.Len(t, events, 0)
+
+	assert.Equal(t, 30*time.Second, d.announced(serviceProposal, failed, start.Add(40*time.Second)))
+	assert.Equal(t, 30*time.Second, d.announced(serviceProposal, failed, start.Add(70*time.Second)))
+	assert.Equal(t, 30*time.Second, d.pingDelay())
+	assert.Eventually(t, func() bool { return len(events) == 1 }, time.Second, 10*time.Millisecond)
+	event := <-events
+	assert.Equal(t, providerID.Address, event.ProviderID)
+	assert.Equal(t, start, event.LastConfirmed)
+	assert.Equal(t, 3, event.Failures)
+
+	// Confirmed proposal is pinged at the regular interval and reported again after the next outage.
+	confirmed := start.Add(100 * time.Second)
+	assert.Equal(t, time.Minute, d.announced(serviceProposal, nil, confirmed))
+	assert.Equal(t, 10*time.Second, d.announced(serviceProposal, failed, confirmed.Add(time.Minute)))
+	assert.Eventually(t, func() bool { return len(events) == 1 }, time.Second, 10*time.Millisecond)
+}
+
+func TestJitterStaysWithinShare(t *testing.T) {
+	assert.Equal(t, time.Minute, jitter(time.Minute, 0))
+	for i := 0; i < 100; i++ {
+		delay := jitter(time.Minute, 0.1)
+		assert.GreaterOrEqual(t, delay, 54*time.Second)
+		assert.LessOrEqual(t, delay, 66*time.Second)
+	}
+}
+
 func observeStatus(d *Discovery, status Status) Status {
 	for {
 		d.mu.RLock()
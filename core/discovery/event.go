@@ -17,6 +17,8 @@
 
 package discovery
 
+import "time"
+
 // Topic represents the different topics a consumer can subscribe to
 const (
 	// AppTopicProposalAdded represents newly announced proposal
@@ -27,4 +29,15 @@ const (
 	AppTopicProposalRemoved = "ProposalRemoved"
 	// AppTopicProposalAnnounce represent proposal events topic.
 	AppTopicProposalAnnounce = "proposalEvent"
+	// AppTopicProposalUnconfirmed represents own proposal which is not confirmed by discovery for too long.
+	AppTopicProposalUnconfirmed = "ProposalUnconfirmed"
 )
+
+// AppEventProposalUnconfirmed is published once own proposal is not confirmed by discovery for too long,
+// meaning that provider has likely dropped off the market.
+type AppEventProposalUnconfirmed struct {
+	ProviderID    string
+	ServiceType   string
+	LastConfirmed time.Time
+	Failures      int
+}
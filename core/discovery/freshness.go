@@ -0,0 +1,149 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package discovery
+
+import (
+	"sync"
+	"time"
+
+	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
+	"github.com/mysteriumnetwork/node/eventbus"
+	"github.com/mysteriumnetwork/node/market"
+)
+
+// FreshnessTracker remembers when each proposal was last confirmed live,
+// either by a provider ping received from the broker or by a working connection to its provider.
+type FreshnessTracker struct {
+	window time.Duration
+
+	lock       sync.RWMutex
+	confirmed  map[market.ProposalID]time.Time
+	lastPruned time.Time
+
+	now func() time.Time
+}
+
+// NewFreshnessTracker creates a tracker. Proposals not confirmed for the given window score zero.
+func NewFreshnessTracker(window time.Duration) *FreshnessTracker {
+	return &FreshnessTracker{
+		window:    window,
+		confirmed: make(map[market.ProposalID]time.Time),
+		now:       time.Now,
+	}
+}
+
+// Subscribe subscribes to relevant events of event bus.
+func (ft *FreshnessTracker) Subscribe(bus eventbus.Subscriber) error {
+	subscription := map[string]interface{}{
+		AppTopicProposalAdded:                        ft.handleProposal,
+		AppTopicProposalUpdated:                      ft.handleProposal,
+		AppTopicProposalRemoved:                      ft.handleProposalRemoved,
+		connectionstate.AppTopicConnectionState:      ft.handleConnectionState,
+		connectionstate.AppTopicConnectionStatistics: ft.handleConnectionStatistics,
+	}
+
+	for topic, fn := range subscription {
+		if err := bus.SubscribeAsync(topic, fn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Confirm marks given proposals as live at the current moment.
+func (ft *FreshnessTracker) Confirm(ids ...market.ProposalID) {
+	now := ft.now()
+
+	ft.lock.Lock()
+	defer ft.lock.Unlock()
+
+	for _, id := range ids {
+		ft.confirmed[id] = now
+	}
+	ft.prune(now)
+}
+
+// prune forgets proposals not confirmed within the window, as they score zero anyway.
+// It runs at most once per window, since proposals evicted without a removal event are rare.
+func (ft *FreshnessTracker) prune(now time.Time) {
+	if now.Sub(ft.lastPruned) < ft.window {
+		return
+	}
+	ft.lastPruned = now
+
+	for id, at := range ft.confirmed {
+		if now.Sub(at) >= ft.window {
+			delete(ft.confirmed, id)
+		}
+	}
+}
+
+// LastConfirmed returns the moment the proposal was last confirmed live.
+func (ft *FreshnessTracker) LastConfirmed(id market.ProposalID) (time.Time, bool) {
+	ft.lock.RLock()
+	defer ft.lock.RUnlock()
+
+	at, ok := ft.confirmed[id]
+	return at, ok
+}
+
+// Score returns the proposal freshness from 1 (just confirmed) down to 0 (not confirmed within the window).
+func (ft *FreshnessTracker) Score(id market.ProposalID) float64 {
+	at, ok := ft.LastConfirmed(id)
+	if !ok || ft.window <= 0 {
+		return 0
+	}
+
+	age := ft.now().Sub(at)
+	if age <= 0 {
+		return 1
+	}
+	if age >= ft.window {
+		return 0
+	}
+
+	return 1 - float64(age)/float64(ft.window)
+}
+
+func (ft *FreshnessTracker) handleProposal(p market.ServiceProposal) {
+	ft.Confirm(p.UniqueID())
+}
+
+func (ft *FreshnessTracker) handleProposalRemoved(p market.ServiceProposal) {
+	ft.lock.Lock()
+	defer ft.lock.Unlock()
+
+	delete(ft.confirmed, p.UniqueID())
+}
+
+func (ft *FreshnessTracker) handleConnectionState(e connectionstate.AppEventConnectionState) {
+	if e.State != connectionstate.Connected {
+		return
+	}
+
+	ft.Confirm(e.SessionInfo.Proposal.UniqueID())
+}
+
+func (ft *FreshnessTracker) handleConnectionStatistics(e connectionstate.AppEventConnectionStatistics) {
+	if e.SessionInfo.State != connectionstate.Connected {
+		return
+	}
+
+	ft.Confirm(e.SessionInfo.Proposal.UniqueID())
+}
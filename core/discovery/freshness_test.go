@@ -0,0 +1,96 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package discovery
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
+	"github.com/mysteriumnetwork/node/core/discovery/proposal"
+	"github.com/mysteriumnetwork/node/market"
+)
+
+func TestFreshnessTracker_Score(t *testing.T) {
+	now := time.Now()
+	tracker := NewFreshnessTracker(time.Minute)
+	tracker.now = func() time.Time { return now }
+
+	id := mockProposal.UniqueID()
+	assert.Equal(t, 0.0, tracker.Score(id))
+
+	tracker.handleProposal(mockProposal)
+	assert.Equal(t, 1.0, tracker.Score(id))
+
+	now = now.Add(15 * time.Second)
+	assert.InDelta(t, 0.75, tracker.Score(id), 0.0001)
+
+	now = now.Add(time.Minute)
+	assert.Equal(t, 0.0, tracker.Score(id))
+
+	tracker.handleConnectionState(connectionstate.AppEventConnectionState{
+		State:       connectionstate.Connected,
+		SessionInfo: connectionstate.Status{Proposal: proposal.PricedServiceProposal{ServiceProposal: mockProposal}},
+	})
+	assert.Equal(t, 1.0, tracker.Score(id))
+
+	tracker.handleProposalRemoved(mockProposal)
+	_, ok := tracker.LastConfirmed(id)
+	assert.False(t, ok)
+}
+
+func TestFreshnessTracker_PrunesExpiredConfirmations(t *testing.T) {
+	now := time.Now()
+	tracker := NewFreshnessTracker(time.Minute)
+	tracker.now = func() time.Time { return now }
+
+	stale := mockProposal
+	stale.ProviderID = "0x1"
+	tracker.Confirm(stale.UniqueID())
+
+	now = now.Add(2 * time.Minute)
+	tracker.Confirm(mockProposal.UniqueID())
+
+	_, ok := tracker.LastConfirmed(stale.UniqueID())
+	assert.False(t, ok)
+	_, ok = tracker.LastConfirmed(mockProposal.UniqueID())
+	assert.True(t, ok)
+}
+
+func TestGetProposals_FreshnessMin(t *testing.T) {
+	now := time.Now()
+	tracker := NewFreshnessTracker(time.Minute)
+	tracker.now = func() time.Time { return now }
+
+	stale := mockProposal
+	stale.ProviderID = "0x1"
+	tracker.Confirm(mockProposal.UniqueID())
+
+	repo := NewPricedServiceProposalRepository(&mockRepository{
+		proposalsToReturn: []market.ServiceProposal{mockProposal, stale},
+	}, &mockPriceInfoProvider{}, presetRepository, tracker)
+
+	res, err := repo.Proposals(&proposal.Filter{FreshnessMin: 0.5})
+	assert.NoError(t, err)
+	assert.Len(t, res, 1)
+	assert.Equal(t, mockProposal.ProviderID, res[0].ProviderID)
+	assert.Equal(t, 1.0, res[0].Freshness)
+	assert.Equal(t, now, res[0].LastConfirmedAt)
+}
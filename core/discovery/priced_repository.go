@@ -18,17 +18,24 @@
 package discovery
 
 import (
+	"runtime"
+	"sync"
+
 	"github.com/rs/zerolog/log"
 
 	"github.com/mysteriumnetwork/node/core/discovery/proposal"
 	"github.com/mysteriumnetwork/node/market"
 )
 
+// parallelPricingMin is the number of proposals from which pricing is spread over several workers.
+const parallelPricingMin = 500
+
 // PricedServiceProposalRepository enriches proposals with price data as pricing data is not available on raw proposals.
 type PricedServiceProposalRepository struct {
 	baseRepo      proposal.Repository
 	pip           PriceInfoProvider
 	filterPresets proposal.FilterPresetRepository
+	freshness     *FreshnessTracker
 }
 
 // PriceInfoProvider allows to fetch the current pricing for services.
@@ -37,11 +44,13 @@ type PriceInfoProvider interface {
 }
 
 // NewPricedServiceProposalRepository returns a new instance of PricedServiceProposalRepository.
-func NewPricedServiceProposalRepository(baseRepo proposal.Repository, pip PriceInfoProvider, filterPresets proposal.FilterPresetRepository) *PricedServiceProposalRepository {
+// Freshness tracker is optional, proposals are not scored without it.
+func NewPricedServiceProposalRepository(baseRepo proposal.Repository, pip PriceInfoProvider, filterPresets proposal.FilterPresetRepository, freshness *FreshnessTracker) *PricedServiceProposalRepository {
 	return &PricedServiceProposalRepository{
 		baseRepo:      baseRepo,
 		pip:           pip,
 		filterPresets: filterPresets,
+		freshness:     freshness,
 	}
 }
 
@@ -77,6 +86,16 @@ func (pspr *PricedServiceProposalRepository) Proposals(filter *proposal.Filter)
 		priced = preset.Filter(priced)
 	}
 
+	if filter != nil && filter.FreshnessMin > 0 {
+		fresh := make([]proposal.PricedServiceProposal, 0, len(priced))
+		for _, p := range priced {
+			if p.Freshness >= filter.FreshnessMin {
+				fresh = append(fresh, p)
+			}
+		}
+		priced = fresh
+	}
+
 	return priced, nil
 }
 
@@ -90,28 +109,119 @@ func (pspr *PricedServiceProposalRepository) EnrichProposalWithPrice(in market.S
 	return pspr.toPricedProposal(in)
 }
 
+// priceBucket groups proposals which are priced the same.
+type priceBucket struct {
+	ipType, country, serviceType string
+}
+
+func priceBucketOf(in market.ServiceProposal) priceBucket {
+	return priceBucket{
+		ipType:      in.Location.IPType,
+		country:     in.Location.Country,
+		serviceType: in.ServiceType,
+	}
+}
+
+type bucketPrice struct {
+	price market.Price
+	err   error
+}
+
+// toPricedProposals looks up the price once per price bucket and then prices
+// the proposals on a bounded number of workers, keeping their order.
 func (pspr *PricedServiceProposalRepository) toPricedProposals(in []market.ServiceProposal) []proposal.PricedServiceProposal {
-	res := make([]proposal.PricedServiceProposal, 0)
+	prices := make(map[priceBucket]bucketPrice)
 	for i := range in {
-		priced, err := pspr.toPricedProposal(in[i])
-		if err != nil {
-			log.Warn().Err(err).Msgf("could not add pricing info to proposal %v(%v)", in[i].ProviderID, in[i].ServiceType)
+		if in[i].IsExternal() {
 			continue
 		}
-		res = append(res, priced)
+		bucket := priceBucketOf(in[i])
+		if _, ok := prices[bucket]; !ok {
+			price, err := pspr.pip.GetCurrentPrice(bucket.ipType, bucket.country, bucket.serviceType)
+			prices[bucket] = bucketPrice{price: price, err: err}
+		}
+	}
+
+	priced := make([]proposal.PricedServiceProposal, len(in))
+	ok := make([]bool, len(in))
+	price := func(from, to int) {
+		for i := from; i < to; i++ {
+			if in[i].IsExternal() {
+				priced[i], ok[i] = pspr.toExternalProposal(in[i]), true
+				continue
+			}
+
+			bp := prices[priceBucketOf(in[i])]
+			if bp.err != nil {
+				log.Warn().Err(bp.err).Msgf("could not add pricing info to proposal %v(%v)", in[i].ProviderID, in[i].ServiceType)
+				continue
+			}
+			priced[i], ok[i] = pspr.withPrice(in[i], bp.price), true
+		}
+	}
+
+	workers := runtime.GOMAXPROCS(0)
+	if len(in) < parallelPricingMin || workers == 1 {
+		price(0, len(in))
+	} else {
+		chunk := (len(in) + workers - 1) / workers
+		var wg sync.WaitGroup
+		for from := 0; from < len(in); from += chunk {
+			to := from + chunk
+			if to > len(in) {
+				to = len(in)
+			}
+
+			wg.Add(1)
+			go func(from, to int) {
+				defer wg.Done()
+				price(from, to)
+			}(from, to)
+		}
+		wg.Wait()
+	}
+
+	res := priced[:0]
+	for i := range priced {
+		if ok[i] {
+			res = append(res, priced[i])
+		}
 	}
 
 	return res
 }
 
 func (pspr *PricedServiceProposalRepository) toPricedProposal(in market.ServiceProposal) (proposal.PricedServiceProposal, error) {
+	// External endpoints are not priced by the network.
+	if in.IsExternal() {
+		return pspr.toExternalProposal(in), nil
+	}
+
 	price, err := pspr.pip.GetCurrentPrice(in.Location.IPType, in.Location.Country, in.ServiceType)
 	if err != nil {
 		return proposal.PricedServiceProposal{}, err
 	}
 
+	return pspr.withPrice(in, price), nil
+}
+
+func (pspr *PricedServiceProposalRepository) toExternalProposal(in market.ServiceProposal) proposal.PricedServiceProposal {
 	return proposal.PricedServiceProposal{
+		ServiceProposal: in,
+		Price:           *market.NewPrice(0, 0),
+	}
+}
+
+func (pspr *PricedServiceProposalRepository) withPrice(in market.ServiceProposal, price market.Price) proposal.PricedServiceProposal {
+	priced := proposal.PricedServiceProposal{
 		ServiceProposal: in,
 		Price:           price,
-	}, nil
+	}
+	if pspr.freshness != nil {
+		id := in.UniqueID()
+		priced.Freshness = pspr.freshness.Score(id)
+		priced.LastConfirmedAt, _ = pspr.freshness.LastConfirmed(id)
+	}
+
+	return priced
 }
@@ -19,8 +19,11 @@ package discovery
 
 import (
 	"errors"
+	"fmt"
 	"math/big"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -68,7 +71,7 @@ func TestGetProposal(t *testing.T) {
 			errToReturn:      nil,
 		}
 
-		repo := NewPricedServiceProposalRepository(mr, mp, presetRepository)
+		repo := NewPricedServiceProposalRepository(mr, mp, presetRepository, nil)
 
 		result, err := repo.Proposal(market.ProposalID{})
 		assert.NoError(t, err)
@@ -82,7 +85,7 @@ func TestGetProposal(t *testing.T) {
 			errToReturn: mockError,
 		}
 
-		repo := NewPricedServiceProposalRepository(mr, &mockPriceInfoProvider{}, presetRepository)
+		repo := NewPricedServiceProposalRepository(mr, &mockPriceInfoProvider{}, presetRepository, nil)
 		_, err := repo.Proposal(market.ProposalID{})
 		assert.Error(t, err)
 		assert.Equal(t, mockError, err)
@@ -94,7 +97,7 @@ func TestGetProposal(t *testing.T) {
 		}
 		repo := NewPricedServiceProposalRepository(&mockRepository{
 			proposalToReturn: &mockProposal,
-		}, mp, nil)
+		}, mp, nil, nil)
 
 		_, err := repo.Proposal(market.ProposalID{})
 		assert.Error(t, err)
@@ -117,7 +120,7 @@ func TestGetProposals(t *testing.T) {
 			errToReturn:       nil,
 		}
 
-		repo := NewPricedServiceProposalRepository(mr, mp, presetRepository)
+		repo := NewPricedServiceProposalRepository(mr, mp, presetRepository, nil)
 
 		result, err := repo.Proposals(nil)
 		assert.NoError(t, err)
@@ -131,7 +134,7 @@ func TestGetProposals(t *testing.T) {
 			errToReturn: mockError,
 		}
 
-		repo := NewPricedServiceProposalRepository(mr, &mockPriceInfoProvider{}, presetRepository)
+		repo := NewPricedServiceProposalRepository(mr, &mockPriceInfoProvider{}, presetRepository, nil)
 		_, err := repo.Proposals(nil)
 		assert.Error(t, err)
 		assert.Equal(t, mockError, err)
@@ -143,12 +146,104 @@ func TestGetProposals(t *testing.T) {
 		}
 		repo := NewPricedServiceProposalRepository(&mockRepository{
 			proposalsToReturn: []market.ServiceProposal{mockProposal},
-		}, mp, presetRepository)
+		}, mp, presetRepository, nil)
 
 		res, err := repo.Proposals(nil)
 		assert.NoError(t, err)
 		assert.Len(t, res, 0)
 	})
+	t.Run("does not price external proposals", func(t *testing.T) {
+		external := market.ServiceProposal{
+			ProviderID:  "external:1",
+			ServiceType: "wireguard",
+			Contacts:    market.ContactList{{Type: market.ContactTypeExternal}},
+		}
+		mp := &mockPriceInfoProvider{
+			errorToReturn: errors.New("boom"),
+		}
+		repo := NewPricedServiceProposalRepository(&mockRepository{
+			proposalsToReturn: []market.ServiceProposal{external},
+		}, mp, presetRepository, nil)
+
+		res, err := repo.Proposals(nil)
+		assert.NoError(t, err)
+		assert.Len(t, res, 1)
+		assert.Zero(t, res[0].Price.PricePerHour.Int64())
+		assert.Zero(t, res[0].Price.PricePerGiB.Int64())
+	})
+	t.Run("prices many proposals in order once per price bucket", func(t *testing.T) {
+		proposals := generateProposals(2 * parallelPricingMin)
+		mp := &countryPriceInfoProvider{failCountry: "LT"}
+		repo := NewPricedServiceProposalRepository(&mockRepository{
+			proposalsToReturn: proposals,
+		}, mp, presetRepository, nil)
+
+		res, err := repo.Proposals(nil)
+		assert.NoError(t, err)
+
+		var expected []market.ServiceProposal
+		buckets := make(map[string]struct{})
+		for _, p := range proposals {
+			buckets[p.Location.Country+p.ServiceType] = struct{}{}
+			if p.Location.Country != "LT" {
+				expected = append(expected, p)
+			}
+		}
+		assert.Len(t, res, len(expected))
+		for i := range res {
+			assert.Equal(t, expected[i], res[i].ServiceProposal)
+			assert.Equal(t, int64(len(res[i].Location.Country)), res[i].Price.PricePerHour.Int64())
+		}
+		assert.Equal(t, int64(len(buckets)), mp.calls.Load())
+	})
+}
+
+func BenchmarkGetProposals(b *testing.B) {
+	repo := NewPricedServiceProposalRepository(&mockRepository{
+		proposalsToReturn: generateProposals(10_000),
+	}, &countryPriceInfoProvider{}, presetRepository, NewFreshnessTracker(time.Minute))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := repo.Proposals(&proposal.Filter{}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+var (
+	proposalCountries    = []string{"US", "DE", "LT", "GB", "FR", "NL", "CA", "JP", "BR", "IN"}
+	proposalServiceTypes = []string{"wireguard", "scraping", "data_transfer", "dvpn"}
+)
+
+func generateProposals(count int) []market.ServiceProposal {
+	proposals := make([]market.ServiceProposal, count)
+	for i := range proposals {
+		proposals[i] = market.ServiceProposal{
+			ProviderID:  fmt.Sprintf("0x%d", i),
+			ServiceType: proposalServiceTypes[i%len(proposalServiceTypes)],
+			Location: market.Location{
+				IPType:  "residential",
+				Country: proposalCountries[i%len(proposalCountries)],
+			},
+		}
+	}
+	return proposals
+}
+
+// countryPriceInfoProvider prices proposals by the length of their country.
+type countryPriceInfoProvider struct {
+	failCountry string
+	calls       atomic.Int64
+}
+
+func (p *countryPriceInfoProvider) GetCurrentPrice(nodeType string, country string, serviceType string) (market.Price, error) {
+	p.calls.Add(1)
+	if country == p.failCountry {
+		return market.Price{}, errors.New("no data available")
+	}
+	return *market.NewPrice(int64(len(country)), 1), nil
 }
 
 type mockRepository struct {
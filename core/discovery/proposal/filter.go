@@ -38,6 +38,7 @@ type Filter struct {
 	CompatibilityMin, CompatibilityMax int
 	BandwidthMin                       float64
 	QualityMin                         float32
+	FreshnessMin                       float64
 	ExcludeUnsupported                 bool
 	IncludeMonitoringFailed            bool
 	NATCompatibility                   nat.NATType
@@ -18,6 +18,8 @@
 package proposal
 
 import (
+	"time"
+
 	"github.com/mysteriumnetwork/node/market"
 )
 
@@ -34,4 +36,8 @@ type Repository interface {
 type PricedServiceProposal struct {
 	market.ServiceProposal
 	Price market.Price `json:"price,omitempty"`
+
+	// Freshness scores how recently the proposal was confirmed live, from 0 to 1.
+	Freshness       float64   `json:"freshness,omitempty"`
+	LastConfirmedAt time.Time `json:"last_confirmed_at,omitempty"`
 }
@@ -0,0 +1,313 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package diskusage
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// AppTopicDiskUsageWarning is published when the data directory is projected to exhaust the disk.
+const AppTopicDiskUsageWarning = "Disk usage warning"
+
+// Category names of tracked data directory usage.
+const (
+	CategorySessions = "sessions"
+	CategoryPayments = "payments"
+	CategoryLogs     = "logs"
+	CategoryOther    = "other"
+)
+
+// window is the period of samples kept for growth rate calculation.
+const window = 24 * time.Hour
+
+// warnEvery limits how often a warning is repeated while the projection stays within horizon.
+const warnEvery = 6 * time.Hour
+
+var bucketCategories = map[string]string{
+	"session-history":    CategorySessions,
+	"sent_invoices":      CategoryPayments,
+	"agreement_r":        CategoryPayments,
+	"settlement-history": CategoryPayments,
+	"hermes_promises":    CategoryPayments,
+	"hermes_migration":   CategoryPayments,
+}
+
+// Config configures the disk usage monitor.
+type Config struct {
+	// DataDir is the node data directory.
+	DataDir string
+	// LogDir is the log directory, it may be located outside of DataDir.
+	LogDir string
+	// Interval between usage samples.
+	Interval time.Duration
+	// Horizon within which projected disk exhaustion is reported, 0 disables warnings.
+	Horizon time.Duration
+}
+
+// WarningEvent is published when the disk is projected to be exhausted within the horizon.
+type WarningEvent struct {
+	Free        uint64
+	GrowthPerH  float64
+	ProjectedAt time.Time
+}
+
+// Sample is a single measurement of data directory usage in bytes.
+type Sample struct {
+	At         time.Time
+	Total      int64
+	Free       uint64
+	Categories map[string]int64
+}
+
+// Report describes the current data directory usage and its growth.
+type Report struct {
+	DataDir     string
+	Horizon     time.Duration
+	Samples     []Sample
+	GrowthPerH  float64
+	ProjectedAt *time.Time
+}
+
+type bucketSizer interface {
+	BucketSizes() (map[string]int64, error)
+}
+
+type publisher interface {
+	Publish(topic string, data interface{})
+}
+
+// Monitor periodically samples the size of the node data directory and warns
+// when its growth rate is projected to exhaust the disk.
+type Monitor struct {
+	cfg       Config
+	buckets   bucketSizer
+	publisher publisher
+	now       func() time.Time
+	free      func(path string) (uint64, error)
+	dirSize   func(path string) (int64, error)
+
+	lock     sync.Mutex
+	samples  []Sample
+	warnedAt time.Time
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewMonitor creates a new disk usage monitor.
+func NewMonitor(cfg Config, buckets bucketSizer, publisher publisher) *Monitor {
+	return &Monitor{
+		cfg:       cfg,
+		buckets:   buckets,
+		publisher: publisher,
+		now:       time.Now,
+		free:      freeSpace,
+		dirSize:   dirSize,
+		stop:      make(chan struct{}),
+	}
+}
+
+// Start samples the data directory periodically until stopped.
+func (m *Monitor) Start() {
+	go func() {
+		m.check()
+
+		ticker := time.NewTicker(m.cfg.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-m.stop:
+				return
+			case <-ticker.C:
+				m.check()
+			}
+		}
+	}()
+}
+
+// Stop stops the monitor.
+func (m *Monitor) Stop() {
+	m.stopOnce.Do(func() {
+		close(m.stop)
+	})
+}
+
+// Report returns the collected samples along with the growth projection.
+func (m *Monitor) Report() Report {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	report := Report{
+		DataDir: m.cfg.DataDir,
+		Horizon: m.cfg.Horizon,
+		Samples: append([]Sample(nil), m.samples...),
+	}
+	report.GrowthPerH, report.ProjectedAt = m.projection()
+	return report
+}
+
+func (m *Monitor) check() {
+	sample, err := m.sample()
+	if err != nil {
+		log.Warn().Err(err).Msg("Could not measure data directory usage")
+		return
+	}
+
+	m.lock.Lock()
+	m.samples = append(m.samples, sample)
+	cutoff := sample.At.Add(-window)
+	for len(m.samples) > 2 && m.samples[0].At.Before(cutoff) {
+		m.samples = m.samples[1:]
+	}
+
+	growth, projected := m.projection()
+	warn := projected != nil && m.cfg.Horizon > 0 &&
+		projected.Sub(sample.At) <= m.cfg.Horizon &&
+		(m.warnedAt.IsZero() || sample.At.Sub(m.warnedAt) >= warnEvery)
+	if warn {
+		m.warnedAt = sample.At
+	}
+	m.lock.Unlock()
+
+	if !warn {
+		return
+	}
+
+	log.Warn().
+		Uint64("free", sample.Free).
+		Float64("growth_per_hour", growth).
+		Time("projected_full_at", *projected).
+		Msgf("Data directory %s is projected to exhaust the disk", m.cfg.DataDir)
+	if m.publisher != nil {
+		m.publisher.Publish(AppTopicDiskUsageWarning, WarningEvent{
+			Free:        sample.Free,
+			GrowthPerH:  growth,
+			ProjectedAt: *projected,
+		})
+	}
+}
+
+// projection returns growth rate in bytes per hour and the time disk is
+// projected to be full, nil if the usage is not growing. Must be called with lock held.
+func (m *Monitor) projection() (float64, *time.Time) {
+	if len(m.samples) < 2 {
+		return 0, nil
+	}
+
+	first, last := m.samples[0], m.samples[len(m.samples)-1]
+	hours := last.At.Sub(first.At).Hours()
+	if hours <= 0 {
+		return 0, nil
+	}
+
+	growth := float64(last.Total-first.Total) / hours
+	if growth <= 0 {
+		return growth, nil
+	}
+
+	projected := last.At.Add(time.Duration(float64(last.Free) / growth * float64(time.Hour)))
+	return growth, &projected
+}
+
+func (m *Monitor) sample() (Sample, error) {
+	total, err := m.dirSize(m.cfg.DataDir)
+	if err != nil {
+		return Sample{}, err
+	}
+
+	logs, err := m.dirSize(m.cfg.LogDir)
+	if err != nil {
+		return Sample{}, err
+	}
+	if !within(m.cfg.LogDir, m.cfg.DataDir) {
+		total += logs
+	}
+
+	free, err := m.free(m.cfg.DataDir)
+	if err != nil {
+		return Sample{}, err
+	}
+
+	categories := map[string]int64{
+		CategorySessions: 0,
+		CategoryPayments: 0,
+		CategoryLogs:     logs,
+		CategoryOther:    0,
+	}
+	if m.buckets != nil {
+		sizes, err := m.buckets.BucketSizes()
+		if err != nil {
+			return Sample{}, err
+		}
+		for bucket, size := range sizes {
+			category, ok := bucketCategories[bucket]
+			if !ok {
+				category = CategoryOther
+			}
+			categories[category] += size
+		}
+	}
+
+	return Sample{
+		At:         m.now(),
+		Total:      total,
+		Free:       free,
+		Categories: categories,
+	}, nil
+}
+
+func within(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+func dirSize(path string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		size += info.Size()
+		return nil
+	})
+	return size, err
+}
@@ -0,0 +1,153 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package diskusage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockBuckets map[string]int64
+
+func (m mockBuckets) BucketSizes() (map[string]int64, error) {
+	return m, nil
+}
+
+type mockPublisher struct {
+	published []interface{}
+}
+
+func (m *mockPublisher) Publish(_ string, data interface{}) {
+	m.published = append(m.published, data)
+}
+
+type fakeDisk struct {
+	now   time.Time
+	total int64
+	free  uint64
+}
+
+func newTestMonitor(cfg Config, disk *fakeDisk, buckets bucketSizer, pub publisher) *Monitor {
+	m := NewMonitor(cfg, buckets, pub)
+	m.now = func() time.Time { return disk.now }
+	m.free = func(string) (uint64, error) { return disk.free, nil }
+	m.dirSize = func(path string) (int64, error) {
+		if path == cfg.DataDir {
+			return disk.total, nil
+		}
+		return 0, nil
+	}
+	return m
+}
+
+func TestMonitor_SampleCategories(t *testing.T) {
+	disk := &fakeDisk{now: time.Now(), total: 1000, free: 5000}
+	buckets := mockBuckets{
+		"session-history": 10,
+		"sent_invoices":   20,
+		"hermes_promises": 30,
+		"jwt":             5,
+	}
+	m := newTestMonitor(Config{DataDir: "/data", LogDir: "/data/logs", Horizon: time.Hour}, disk, buckets, nil)
+
+	m.check()
+
+	report := m.Report()
+	require.Len(t, report.Samples, 1)
+	assert.Equal(t, int64(1000), report.Samples[0].Total)
+	assert.Equal(t, map[string]int64{
+		CategorySessions: 10,
+		CategoryPayments: 50,
+		CategoryLogs:     0,
+		CategoryOther:    5,
+	}, report.Samples[0].Categories)
+	assert.Nil(t, report.ProjectedAt)
+}
+
+func TestMonitor_WarnsWithinHorizon(t *testing.T) {
+	start := time.Now()
+	disk := &fakeDisk{now: start, total: 1000, free: 10000}
+	pub := &mockPublisher{}
+	m := newTestMonitor(Config{DataDir: "/data", LogDir: "/data/logs", Horizon: 8 * time.Hour}, disk, nil, pub)
+
+	m.check()
+
+	// Growing 1000 bytes per hour with 9000 bytes left is beyond the horizon.
+	disk.now, disk.total, disk.free = start.Add(time.Hour), 2000, 9000
+	m.check()
+	assert.Empty(t, pub.published)
+
+	report := m.Report()
+	assert.Equal(t, float64(1000), report.GrowthPerH)
+	require.NotNil(t, report.ProjectedAt)
+	assert.Equal(t, start.Add(10*time.Hour), *report.ProjectedAt)
+
+	m.cfg.Horizon = 12 * time.Hour
+	disk.now, disk.total, disk.free = start.Add(2*time.Hour), 3000, 8000
+	m.check()
+	require.Len(t, pub.published, 1)
+	assert.Equal(t, start.Add(10*time.Hour), pub.published[0].(WarningEvent).ProjectedAt)
+
+	// Warning is not repeated until warnEvery passes.
+	disk.now, disk.total, disk.free = start.Add(3*time.Hour), 4000, 7000
+	m.check()
+	assert.Len(t, pub.published, 1)
+}
+
+func TestMonitor_DropsSamplesOutsideWindow(t *testing.T) {
+	start := time.Now()
+	disk := &fakeDisk{now: start, total: 1000, free: 10000}
+	m := newTestMonitor(Config{DataDir: "/data", LogDir: "/data/logs"}, disk, nil, nil)
+
+	for i := 0; i <= 30; i++ {
+		disk.now = start.Add(time.Duration(i) * time.Hour)
+		m.check()
+	}
+
+	report := m.Report()
+	assert.Len(t, report.Samples, 25)
+	assert.Equal(t, start.Add(6*time.Hour), report.Samples[0].At)
+}
+
+func TestMonitor_LogDirOutsideDataDir(t *testing.T) {
+	dir := t.TempDir()
+	dataDir, logDir := filepath.Join(dir, "data"), filepath.Join(dir, "logs")
+	require.NoError(t, os.MkdirAll(dataDir, 0700))
+	require.NoError(t, os.MkdirAll(logDir, 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(dataDir, "myst.db"), make([]byte, 100), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(logDir, "mysterium-node.log"), make([]byte, 40), 0600))
+
+	m := NewMonitor(Config{DataDir: dataDir, LogDir: logDir}, nil, nil)
+	m.free = func(string) (uint64, error) { return 0, nil }
+
+	sample, err := m.sample()
+	require.NoError(t, err)
+	assert.Equal(t, int64(140), sample.Total)
+	assert.Equal(t, int64(40), sample.Categories[CategoryLogs])
+
+	m.cfg.LogDir = filepath.Join(dataDir, "logs")
+	require.NoError(t, os.Rename(logDir, m.cfg.LogDir))
+	sample, err = m.sample()
+	require.NoError(t, err)
+	assert.Equal(t, int64(140), sample.Total)
+}
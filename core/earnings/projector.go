@@ -0,0 +1,200 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package earnings
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/mysteriumnetwork/node/consumer/session"
+	"github.com/mysteriumnetwork/node/core/location/locationstate"
+	"github.com/mysteriumnetwork/node/datasize"
+	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/market"
+)
+
+const (
+	day = 24 * time.Hour
+	// historyDays is the number of past days the projection is based on.
+	historyDays = 28
+	// monthDays is the length of the projected month.
+	monthDays = 30
+)
+
+// ErrNoHistory is returned when the provider has no sessions to project earnings from.
+var ErrNoHistory = errors.New("no provided sessions in the history window")
+
+type sessionLister interface {
+	List(*session.Filter) ([]session.History, error)
+}
+
+type pricer interface {
+	GetCurrentPrice(nodeType string, country string, serviceType string) (market.Price, error)
+}
+
+type locationResolver interface {
+	DetectLocation() (locationstate.Location, error)
+}
+
+// Projection is the expected provider earnings at the current prices.
+type Projection struct {
+	// Days is the number of past days the projection is based on.
+	Days int
+	// Daily, Weekly and Monthly are the expected earnings in wei, before fees.
+	Daily   *big.Int
+	Weekly  *big.Int
+	Monthly *big.Int
+	// Services breaks the projection down per service type, sorted by service type.
+	Services []ServiceProjection
+}
+
+// ServiceProjection is the expected earnings of a single service type.
+type ServiceProjection struct {
+	ServiceType string
+	// Stats aggregates the provided sessions of the service type during the history window.
+	Stats session.Stats
+	// Earned is the average daily earnings during the history window in wei.
+	Earned *big.Int
+	// Price is the current price the projection is based on.
+	Price   market.Price
+	Daily   *big.Int
+	Weekly  *big.Int
+	Monthly *big.Int
+}
+
+// Projector projects provider earnings from the provided sessions history and current prices.
+type Projector struct {
+	sessions sessionLister
+	pricer   pricer
+	location locationResolver
+	now      func() time.Time
+}
+
+// NewProjector returns a new Projector.
+func NewProjector(sessions sessionLister, pricer pricer, location locationResolver) *Projector {
+	return &Projector{
+		sessions: sessions,
+		pricer:   pricer,
+		location: location,
+		now:      time.Now,
+	}
+}
+
+// Project projects daily, weekly and monthly earnings of the given provider.
+// Traffic and session time served per day during the history window are priced at the current prices
+// of the node location, so that the projection follows price changes.
+func (p *Projector) Project(providerID identity.Identity) (Projection, error) {
+	now := p.now().UTC()
+	filter := session.NewFilter().
+		SetStartedFrom(now.Add(-historyDays * day)).
+		SetStartedTo(now).
+		SetDirection(session.DirectionProvided).
+		SetProviderID(providerID)
+	sessions, err := p.sessions.List(filter)
+	if err != nil {
+		return Projection{}, fmt.Errorf("could not list sessions: %w", err)
+	}
+	if len(sessions) == 0 {
+		return Projection{}, ErrNoHistory
+	}
+
+	loc, err := p.location.DetectLocation()
+	if err != nil {
+		return Projection{}, fmt.Errorf("could not detect location: %w", err)
+	}
+
+	days := historyWindow(now, sessions)
+	stats := make(map[string]session.Stats)
+	for _, se := range sessions {
+		s, ok := stats[se.ServiceType]
+		if !ok {
+			s = session.NewStats()
+		}
+		s.Add(se)
+		stats[se.ServiceType] = s
+	}
+
+	projection := Projection{
+		Days:    days,
+		Daily:   new(big.Int),
+		Weekly:  new(big.Int),
+		Monthly: new(big.Int),
+	}
+	for serviceType, s := range stats {
+		price, err := p.pricer.GetCurrentPrice(loc.IPType, loc.Country, serviceType)
+		if err != nil {
+			return Projection{}, fmt.Errorf("could not get current %s price: %w", serviceType, err)
+		}
+
+		daily := dailyEarnings(s, price, days)
+		projection.Services = append(projection.Services, ServiceProjection{
+			ServiceType: serviceType,
+			Stats:       s,
+			Earned:      new(big.Int).Div(s.SumTokens, big.NewInt(int64(days))),
+			Price:       price,
+			Daily:       daily,
+			Weekly:      new(big.Int).Mul(daily, big.NewInt(7)),
+			Monthly:     new(big.Int).Mul(daily, big.NewInt(monthDays)),
+		})
+		projection.Daily.Add(projection.Daily, daily)
+	}
+	sort.Slice(projection.Services, func(i, j int) bool {
+		return projection.Services[i].ServiceType < projection.Services[j].ServiceType
+	})
+	projection.Weekly.Mul(projection.Daily, big.NewInt(7))
+	projection.Monthly.Mul(projection.Daily, big.NewInt(monthDays))
+
+	return projection, nil
+}
+
+// historyWindow returns the number of days since the first session in the window,
+// so that new providers are not projected from days they were not providing yet.
+func historyWindow(now time.Time, sessions []session.History) int {
+	first := now
+	for _, se := range sessions {
+		if se.Started.Before(first) {
+			first = se.Started
+		}
+	}
+
+	days := int((now.Sub(first) + day - 1) / day)
+	if days < 1 {
+		return 1
+	}
+	if days > historyDays {
+		return historyDays
+	}
+	return days
+}
+
+func dailyEarnings(s session.Stats, price market.Price, days int) *big.Int {
+	earnings := new(big.Int)
+	if price.PricePerGiB != nil {
+		data := new(big.Int).SetUint64(s.SumDataSent + s.SumDataReceived)
+		perData := data.Mul(data, price.PricePerGiB)
+		earnings.Add(earnings, perData.Div(perData, new(big.Int).SetUint64(datasize.GiB.Bytes())))
+	}
+	if price.PricePerHour != nil {
+		perTime := new(big.Int).Mul(big.NewInt(int64(s.SumDuration)), price.PricePerHour)
+		earnings.Add(earnings, perTime.Div(perTime, big.NewInt(int64(time.Hour))))
+	}
+	return earnings.Div(earnings, big.NewInt(int64(days)))
+}
@@ -0,0 +1,156 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package earnings
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mysteriumnetwork/node/consumer/session"
+	"github.com/mysteriumnetwork/node/core/location/locationstate"
+	"github.com/mysteriumnetwork/node/datasize"
+	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/market"
+)
+
+var (
+	providerID = identity.FromAddress("0x1")
+	now        = time.Date(2024, 3, 29, 12, 0, 0, 0, time.UTC)
+	gib        = datasize.GiB.Bytes()
+)
+
+type mockSessions struct {
+	sessions []session.History
+	filter   *session.Filter
+}
+
+func (m *mockSessions) List(filter *session.Filter) ([]session.History, error) {
+	m.filter = filter
+	return m.sessions, nil
+}
+
+type mockPricer struct {
+	prices   map[string]market.Price
+	nodeType string
+	country  string
+}
+
+func (m *mockPricer) GetCurrentPrice(nodeType string, country string, serviceType string) (market.Price, error) {
+	m.nodeType, m.country = nodeType, country
+	price, ok := m.prices[serviceType]
+	if !ok {
+		return market.Price{}, errors.New("no data available")
+	}
+	return price, nil
+}
+
+type mockLocation struct{}
+
+func (mockLocation) DetectLocation() (locationstate.Location, error) {
+	return locationstate.Location{Country: "DE", IPType: "residential"}, nil
+}
+
+func provided(daysAgo int, serviceType string, data uint64, duration time.Duration, tokens int64) session.History {
+	started := now.Add(-time.Duration(daysAgo)*day - time.Hour)
+	return session.History{
+		Direction:   session.DirectionProvided,
+		ProviderID:  providerID,
+		ServiceType: serviceType,
+		DataSent:    data,
+		Tokens:      big.NewInt(tokens),
+		Started:     started,
+		Updated:     started.Add(duration),
+	}
+}
+
+func newTestProjector(sessions *mockSessions, pricer *mockPricer) *Projector {
+	p := NewProjector(sessions, pricer, mockLocation{})
+	p.now = func() time.Time { return now }
+	return p
+}
+
+func TestProjector_Project(t *testing.T) {
+	// given
+	sessions := &mockSessions{sessions: []session.History{
+		provided(1, "wireguard", 2*gib, 2*time.Hour, 100),
+		provided(3, "wireguard", 2*gib, 2*time.Hour, 100),
+		provided(2, "scraping", 4*gib, 0, 400),
+	}}
+	pricer := &mockPricer{prices: map[string]market.Price{
+		"wireguard": {PricePerGiB: big.NewInt(100), PricePerHour: big.NewInt(10)},
+		"scraping":  {PricePerGiB: big.NewInt(50), PricePerHour: big.NewInt(0)},
+	}}
+
+	// when
+	projection, err := newTestProjector(sessions, pricer).Project(providerID)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, session.DirectionProvided, *sessions.filter.Direction)
+	assert.Equal(t, providerID, *sessions.filter.ProviderID)
+	assert.Equal(t, "DE", pricer.country)
+	assert.Equal(t, "residential", pricer.nodeType)
+
+	// first session started 3 days and 1 hour ago, so the history spans 4 days
+	assert.Equal(t, 4, projection.Days)
+	require.Len(t, projection.Services, 2)
+
+	scraping := projection.Services[0]
+	assert.Equal(t, "scraping", scraping.ServiceType)
+	assert.Equal(t, 1, scraping.Stats.Count)
+	assert.Equal(t, big.NewInt(100), scraping.Earned)
+	assert.Equal(t, big.NewInt(50), scraping.Daily)
+	assert.Equal(t, big.NewInt(350), scraping.Weekly)
+	assert.Equal(t, big.NewInt(1500), scraping.Monthly)
+
+	wireguard := projection.Services[1]
+	assert.Equal(t, "wireguard", wireguard.ServiceType)
+	assert.Equal(t, 2, wireguard.Stats.Count)
+	assert.Equal(t, big.NewInt(50), wireguard.Earned)
+	// (4 GiB * 100 + 4 h * 10) / 4 days
+	assert.Equal(t, big.NewInt(110), wireguard.Daily)
+
+	assert.Equal(t, big.NewInt(160), projection.Daily)
+	assert.Equal(t, big.NewInt(1120), projection.Weekly)
+	assert.Equal(t, big.NewInt(4800), projection.Monthly)
+}
+
+func TestProjector_ProjectWithoutHistory(t *testing.T) {
+	_, err := newTestProjector(&mockSessions{}, &mockPricer{}).Project(providerID)
+
+	assert.ErrorIs(t, err, ErrNoHistory)
+}
+
+func TestProjector_ProjectWithoutPrice(t *testing.T) {
+	sessions := &mockSessions{sessions: []session.History{provided(1, "wireguard", gib, time.Hour, 10)}}
+
+	_, err := newTestProjector(sessions, &mockPricer{}).Project(providerID)
+
+	assert.Error(t, err)
+}
+
+func TestHistoryWindow(t *testing.T) {
+	assert.Equal(t, 1, historyWindow(now, []session.History{{Started: now.Add(-time.Minute)}}))
+	assert.Equal(t, 2, historyWindow(now, []session.History{{Started: now.Add(-day - time.Minute)}}))
+	assert.Equal(t, historyDays, historyWindow(now, []session.History{{Started: now.Add(-100 * day)}}))
+}
@@ -20,11 +20,13 @@ package ip
 import (
 	"net"
 	"sync"
+	"syscall"
 
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
 
 	"github.com/mysteriumnetwork/node/requests"
+	"github.com/mysteriumnetwork/node/router"
 )
 
 const apiClient = "goclient-v0.1"
@@ -74,7 +76,17 @@ func (r *ResolverImpl) getOutboundIP() (net.IP, error) {
 	ipAddress := net.ParseIP(r.bindAddress)
 	localIPAddress := net.UDPAddr{IP: ipAddress}
 
-	dialer := net.Dialer{LocalAddr: &localIPAddress}
+	dialer := net.Dialer{
+		LocalAddr: &localIPAddress,
+		// Outbound IP is the one of the interface the node traffic is bound to.
+		Control: func(network, address string, c syscall.RawConn) error {
+			return c.Control(func(fd uintptr) {
+				if err := router.Protect(int(fd)); err != nil {
+					log.Warn().Err(err).Msg("Failed to protect outbound IP check connection")
+				}
+			})
+		},
+	}
 
 	conn, err := dialer.Dial("udp4", checkAddress)
 	if err != nil {
@@ -0,0 +1,149 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package lifecycle
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// DefaultStopTimeout bounds stopping of components which do not declare their own timeout.
+const DefaultStopTimeout = 10 * time.Second
+
+// ErrStopTimeout is returned when a component does not stop within its timeout.
+var ErrStopTimeout = errors.New("component did not stop in time")
+
+// Component is a subsystem of the node which has to be stopped on shutdown.
+type Component struct {
+	Name string
+	// DependsOn lists components used by this one, they are stopped only after this one is stopped.
+	DependsOn []string
+	Stop      func() error
+	// Timeout bounds Stop, DefaultStopTimeout is used if zero.
+	Timeout time.Duration
+}
+
+// Graph holds components along with their dependencies and stops them in dependency order.
+type Graph struct {
+	components map[string]Component
+	names      []string
+}
+
+// NewGraph creates an empty component graph.
+func NewGraph() *Graph {
+	return &Graph{components: make(map[string]Component)}
+}
+
+// Add adds the component to the graph. Components which are not running should not be added,
+// dependencies on missing components are ignored.
+func (g *Graph) Add(c Component) {
+	if _, ok := g.components[c.Name]; !ok {
+		g.names = append(g.names, c.Name)
+	}
+	g.components[c.Name] = c
+}
+
+// Order returns component names ordered so that every component follows its dependencies.
+// Independent components keep the order they were added in.
+func (g *Graph) Order() ([]string, error) {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int, len(g.names))
+	order := make([]string, 0, len(g.names))
+
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		c, ok := g.components[name]
+		if !ok {
+			return nil
+		}
+		switch state[name] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("dependency cycle: %v", append(path, name))
+		}
+
+		state[name] = visiting
+		for _, dep := range c.DependsOn {
+			if err := visit(dep, append(path, name)); err != nil {
+				return err
+			}
+		}
+		state[name] = visited
+		order = append(order, name)
+		return nil
+	}
+
+	for _, name := range g.names {
+		if err := visit(name, nil); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+// Shutdown stops the components in reverse dependency order. Each component is given its timeout,
+// a component which does not stop in time is abandoned so that it does not prevent the rest from stopping.
+// Every failure is logged, the first one is returned.
+func (g *Graph) Shutdown() error {
+	order, err := g.Order()
+	if err != nil {
+		return err
+	}
+
+	var first error
+	for i := len(order) - 1; i >= 0; i-- {
+		c := g.components[order[i]]
+		if err := stop(c); err != nil {
+			log.Error().Err(err).Msgf("Failed to stop %s", c.Name)
+			if first == nil {
+				first = fmt.Errorf("%s: %w", c.Name, err)
+			}
+		}
+	}
+	return first
+}
+
+func stop(c Component) error {
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = DefaultStopTimeout
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- c.Stop()
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case err := <-done:
+		return err
+	case <-timer.C:
+		return fmt.Errorf("%w after %s", ErrStopTimeout, timeout)
+	}
+}
@@ -0,0 +1,85 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package lifecycle
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGraph_Order(t *testing.T) {
+	g := NewGraph()
+	g.Add(Component{Name: "node", DependsOn: []string{"services", "storage"}})
+	g.Add(Component{Name: "services", DependsOn: []string{"storage", "broker"}})
+	g.Add(Component{Name: "storage"})
+	g.Add(Component{Name: "monitor", DependsOn: []string{"missing"}})
+	g.Add(Component{Name: "broker"})
+
+	order, err := g.Order()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"storage", "broker", "services", "node", "monitor"}, order)
+}
+
+func TestGraph_Order_Cycle(t *testing.T) {
+	g := NewGraph()
+	g.Add(Component{Name: "a", DependsOn: []string{"b"}})
+	g.Add(Component{Name: "b", DependsOn: []string{"a"}})
+
+	_, err := g.Order()
+	assert.EqualError(t, err, "dependency cycle: [a b a]")
+}
+
+func TestGraph_Shutdown(t *testing.T) {
+	var stopped []string
+	stopper := func(name string, err error) func() error {
+		return func() error {
+			stopped = append(stopped, name)
+			return err
+		}
+	}
+	blocked := make(chan struct{})
+	defer close(blocked)
+
+	g := NewGraph()
+	g.Add(Component{Name: "storage", Stop: stopper("storage", nil)})
+	g.Add(Component{Name: "broker", Stop: func() error {
+		<-blocked
+		return nil
+	}, Timeout: 10 * time.Millisecond})
+	g.Add(Component{Name: "services", DependsOn: []string{"storage", "broker"}, Stop: stopper("services", errors.New("kill failed"))})
+	g.Add(Component{Name: "node", DependsOn: []string{"services"}, Stop: stopper("node", nil)})
+
+	err := g.Shutdown()
+	assert.EqualError(t, err, "services: kill failed")
+	// blocked broker does not prevent storage from stopping
+	assert.Equal(t, []string{"node", "services", "storage"}, stopped)
+}
+
+func TestStop_Timeout(t *testing.T) {
+	blocked := make(chan struct{})
+	defer close(blocked)
+
+	err := stop(Component{Name: "nats", Stop: func() error {
+		<-blocked
+		return nil
+	}, Timeout: 10 * time.Millisecond})
+	assert.ErrorIs(t, err, ErrStopTimeout)
+}
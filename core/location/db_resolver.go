@@ -67,6 +67,11 @@ func (r *DBResolver) DetectProxyLocation(proxyPort int) (loc locationstate.Locat
 	return r.detectLocation(ipAddress)
 }
 
+// Close releases the database resources.
+func (r *DBResolver) Close() error {
+	return r.dbReader.Close()
+}
+
 func (r *DBResolver) detectLocation(ipAddress string) (loc locationstate.Location, err error) {
 	log.Debug().Msg("Detecting with DB resolver")
 
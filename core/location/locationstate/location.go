@@ -29,4 +29,11 @@ type Location struct {
 	City      string `json:"city"`
 
 	IPType string `json:"ip_type"`
+
+	// Overridden is set when country and city were claimed by the provider instead of detected.
+	Overridden bool `json:"overridden,omitempty"`
+	// Mismatch is set when the claimed location is not confirmed by the measured one.
+	Mismatch bool `json:"mismatch,omitempty"`
+	// Unverified is set when none of the sources managed to measure the location to check the claim against.
+	Unverified bool `json:"unverified,omitempty"`
 }
@@ -0,0 +1,119 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package location
+
+import (
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/core/location/locationstate"
+)
+
+// OverrideResolver advertises a location claimed by the provider instead of the detected one
+// and cross-checks the claim against independent GeoIP sources.
+type OverrideResolver struct {
+	resolver Resolver
+	country  string
+	city     string
+	probes   []Resolver
+}
+
+// NewOverrideResolver returns a resolver which replaces country and city detected by the given resolver
+// with the claimed ones. Probes are used to verify the claim.
+func NewOverrideResolver(resolver Resolver, country, city string, probes []Resolver) *OverrideResolver {
+	return &OverrideResolver{
+		resolver: resolver,
+		country:  country,
+		city:     city,
+		probes:   probes,
+	}
+}
+
+// DetectLocation detects current location and applies the claimed location on top of it.
+func (r *OverrideResolver) DetectLocation() (locationstate.Location, error) {
+	loc, err := r.resolver.DetectLocation()
+	if err != nil {
+		return loc, err
+	}
+
+	return r.override(loc, func(probe Resolver) (locationstate.Location, error) {
+		return probe.DetectLocation()
+	}), nil
+}
+
+// DetectProxyLocation detects proxy location and applies the claimed location on top of it.
+func (r *OverrideResolver) DetectProxyLocation(proxyPort int) (locationstate.Location, error) {
+	loc, err := r.resolver.DetectProxyLocation(proxyPort)
+	if err != nil {
+		return loc, err
+	}
+
+	return r.override(loc, func(probe Resolver) (locationstate.Location, error) {
+		return probe.DetectProxyLocation(proxyPort)
+	}), nil
+}
+
+func (r *OverrideResolver) override(loc locationstate.Location, detect func(Resolver) (locationstate.Location, error)) locationstate.Location {
+	measured := loc.Country
+	loc.Country = r.country
+	if r.city != "" {
+		loc.City = r.city
+	}
+	loc.Overridden = true
+	loc.Mismatch, loc.Unverified = r.verify(measured, detect)
+
+	return loc
+}
+
+// verify reports a mismatch when the claimed country is not confirmed by the majority of the sources
+// which managed to resolve the location, a tie counts as a mismatch. If no source resolved the location,
+// the claim is reported as unverified instead.
+func (r *OverrideResolver) verify(measured string, detect func(Resolver) (locationstate.Location, error)) (mismatch, unverified bool) {
+	var agree, disagree int
+	count := func(country string) {
+		if country == "" {
+			return
+		}
+		if strings.EqualFold(country, r.country) {
+			agree++
+		} else {
+			disagree++
+		}
+	}
+
+	count(measured)
+	for _, probe := range r.probes {
+		loc, err := detect(probe)
+		if err != nil {
+			log.Debug().Err(err).Msg("Location verification probe failed")
+			continue
+		}
+		count(loc.Country)
+	}
+
+	if agree+disagree == 0 {
+		log.Warn().Msgf("Claimed location country %s could not be verified, no source resolved the location", r.country)
+		return false, true
+	}
+	if disagree >= agree {
+		log.Warn().Msgf("Claimed location country %s is not confirmed by measured one (%d of %d sources disagree)", r.country, disagree, agree+disagree)
+		return true, false
+	}
+	return false, false
+}
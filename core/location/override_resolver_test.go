@@ -0,0 +1,89 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package location
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/core/ip"
+)
+
+func TestOverrideResolver_DetectLocation(t *testing.T) {
+	ipResolver := ip.NewResolverMock("1.2.3.4")
+	static := func(country string) Resolver {
+		return NewStaticResolver(country, "", "datacenter", ipResolver)
+	}
+
+	tests := []struct {
+		name           string
+		resolver       Resolver
+		probes         []Resolver
+		wantMismatch   bool
+		wantUnverified bool
+	}{
+		{
+			name:         "claim confirmed by probes",
+			probes:       []Resolver{static("DE"), static("de")},
+			wantMismatch: false,
+		},
+		{
+			name:         "claim rejected by probes",
+			probes:       []Resolver{static("LT"), static("DE")},
+			wantMismatch: true,
+		},
+		{
+			name:         "failing probes are ignored",
+			probes:       []Resolver{NewFailingResolver(errors.New("boom")), static("DE"), static("DE")},
+			wantMismatch: false,
+		},
+		{
+			name:         "tie leaves claim unverified",
+			probes:       []Resolver{static("DE")},
+			wantMismatch: true,
+		},
+		{
+			name:           "no source resolved",
+			resolver:       static(""),
+			probes:         []Resolver{NewFailingResolver(errors.New("boom"))},
+			wantMismatch:   false,
+			wantUnverified: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			detected := tt.resolver
+			if detected == nil {
+				detected = static("LT")
+			}
+			resolver := NewOverrideResolver(detected, "DE", "Berlin", tt.probes)
+
+			loc, err := resolver.DetectLocation()
+			assert.NoError(t, err)
+			assert.Equal(t, "DE", loc.Country)
+			assert.Equal(t, "Berlin", loc.City)
+			assert.Equal(t, "datacenter", loc.IPType)
+			assert.True(t, loc.Overridden)
+			assert.Equal(t, tt.wantMismatch, loc.Mismatch)
+			assert.Equal(t, tt.wantUnverified, loc.Unverified)
+		})
+	}
+}
@@ -0,0 +1,245 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package location
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/oschwald/geoip2-golang"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/core/ip"
+	"github.com/mysteriumnetwork/node/core/location/locationstate"
+	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/requests"
+)
+
+// maxDBSize limits the size of the downloaded database to protect from misbehaving update servers.
+const maxDBSize = 128 << 20
+
+// DBUpdaterConfig describes how the GeoIP database gets updated.
+type DBUpdaterConfig struct {
+	// URL of the database file. Signature is expected at the same URL with ".sig" suffix.
+	URL string
+	// Signer is the address of the identity which signs published databases.
+	Signer string
+	// Interval between update checks.
+	Interval time.Duration
+	// Path where the last verified database is persisted between restarts.
+	Path string
+}
+
+// UpdatingDBResolver resolves location using the built-in GeoIP database
+// and periodically replaces it with a newer, signed one.
+type UpdatingDBResolver struct {
+	cfg        DBUpdaterConfig
+	httpClient *requests.HTTPClient
+	ipResolver ip.Resolver
+	verifier   identity.Verifier
+
+	lock     sync.RWMutex
+	resolver *DBResolver
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewUpdatingDBResolver returns a resolver initialized from the last persisted database
+// or from the built-in one if nothing was persisted yet.
+func NewUpdatingDBResolver(httpClient *requests.HTTPClient, ipResolver ip.Resolver, cfg DBUpdaterConfig) (*UpdatingDBResolver, error) {
+	r := &UpdatingDBResolver{
+		cfg:        cfg,
+		httpClient: httpClient,
+		ipResolver: ipResolver,
+		verifier:   identity.NewVerifierIdentity(identity.FromAddress(cfg.Signer)),
+		stop:       make(chan struct{}),
+	}
+
+	resolver, err := r.loadPersisted()
+	if err != nil {
+		log.Warn().Err(err).Msg("Could not load persisted GeoIP database, using built-in one")
+		resolver, err = NewBuiltInResolver(ipResolver)
+		if err != nil {
+			return nil, err
+		}
+	}
+	r.resolver = resolver
+
+	return r, nil
+}
+
+// DetectLocation detects current IP-address provides location information for the IP.
+func (r *UpdatingDBResolver) DetectLocation() (locationstate.Location, error) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return r.resolver.DetectLocation()
+}
+
+// DetectProxyLocation detects proxy IP-address provides location information for the IP.
+func (r *UpdatingDBResolver) DetectProxyLocation(proxyPort int) (locationstate.Location, error) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return r.resolver.DetectProxyLocation(proxyPort)
+}
+
+// Start periodically checks for database updates until stopped.
+func (r *UpdatingDBResolver) Start() {
+	if r.cfg.URL == "" || r.cfg.Signer == "" || r.cfg.Interval <= 0 {
+		log.Info().Msg("GeoIP database updates disabled")
+		return
+	}
+
+	for {
+		if err := r.Update(); err != nil {
+			log.Warn().Err(err).Msg("GeoIP database update failed")
+		}
+
+		select {
+		case <-r.stop:
+			return
+		case <-time.After(r.cfg.Interval):
+		}
+	}
+}
+
+// Stop stops database updates.
+func (r *UpdatingDBResolver) Stop() {
+	r.stopOnce.Do(func() {
+		close(r.stop)
+	})
+}
+
+// Update downloads the database, verifies its signature and swaps it in.
+func (r *UpdatingDBResolver) Update() error {
+	data, err := r.fetch(r.cfg.URL)
+	if err != nil {
+		return errors.Wrap(err, "failed to download database")
+	}
+
+	sig, err := r.fetch(r.cfg.URL + ".sig")
+	if err != nil {
+		return errors.Wrap(err, "failed to download database signature")
+	}
+
+	reader, err := r.verifiedReader(data, sig)
+	if err != nil {
+		return err
+	}
+
+	if err := r.persist(data, sig); err != nil {
+		log.Warn().Err(err).Msg("Failed to persist GeoIP database")
+	}
+
+	// Lookups hold the read lock, so the old database is not in use once the write lock is taken.
+	r.lock.Lock()
+	old := r.resolver
+	r.resolver = &DBResolver{dbReader: reader, ipResolver: r.ipResolver}
+	if err := old.Close(); err != nil {
+		log.Warn().Err(err).Msg("Failed to close previous GeoIP database")
+	}
+	r.lock.Unlock()
+
+	log.Info().Msgf("GeoIP database updated, build epoch: %d", reader.Metadata().BuildEpoch)
+	return nil
+}
+
+func (r *UpdatingDBResolver) verifiedReader(data, sig []byte) (*geoip2.Reader, error) {
+	if ok, _ := r.verifier.Verify(data, identity.SignatureHex(strings.TrimSpace(string(sig)))); !ok {
+		return nil, errors.New("database signature verification failed")
+	}
+
+	reader, err := geoip2.FromBytes(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load database")
+	}
+	return reader, nil
+}
+
+func (r *UpdatingDBResolver) fetch(url string) ([]byte, error) {
+	req, err := requests.NewGetRequest(url, "", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("unexpected response status: %s", resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxDBSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxDBSize {
+		return nil, errors.New("response is too large")
+	}
+	return data, nil
+}
+
+func (r *UpdatingDBResolver) loadPersisted() (*DBResolver, error) {
+	if r.cfg.Path == "" {
+		return nil, errors.New("database path is not configured")
+	}
+
+	data, err := os.ReadFile(r.cfg.Path)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := os.ReadFile(r.cfg.Path + ".sig")
+	if err != nil {
+		return nil, err
+	}
+
+	reader, err := r.verifiedReader(data, sig)
+	if err != nil {
+		return nil, err
+	}
+	return &DBResolver{dbReader: reader, ipResolver: r.ipResolver}, nil
+}
+
+func (r *UpdatingDBResolver) persist(data, sig []byte) error {
+	if r.cfg.Path == "" {
+		return nil
+	}
+
+	if err := writeFileAtomic(r.cfg.Path+".sig", sig); err != nil {
+		return err
+	}
+	return writeFileAtomic(r.cfg.Path, data)
+}
+
+func writeFileAtomic(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,140 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package location
+
+import (
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/core/ip"
+	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/requests"
+)
+
+func TestUpdatingDBResolver_Update(t *testing.T) {
+	db, err := os.ReadFile("db/GeoLite2-Country.mmdb")
+	assert.NoError(t, err)
+
+	key, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+	sig, err := crypto.Sign(crypto.Keccak256(db), key)
+	assert.NoError(t, err)
+	signer := crypto.PubkeyToAddress(key.PublicKey).Hex()
+
+	tests := []struct {
+		name      string
+		signer    string
+		signature string
+		wantErr   bool
+	}{
+		{"valid signature", signer, hex.EncodeToString(sig), false},
+		{"invalid signature", signer, hex.EncodeToString(sig[1:]), true},
+		{"unexpected signer", "0x0000000000000000000000000000000000000001", hex.EncodeToString(sig), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/db.mmdb":
+					w.Write(db)
+				case "/db.mmdb.sig":
+					w.Write([]byte(tt.signature))
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer server.Close()
+
+			path := filepath.Join(t.TempDir(), "db.mmdb")
+			resolver, err := NewUpdatingDBResolver(
+				requests.NewHTTPClient("0.0.0.0", time.Second),
+				ip.NewResolverMock("95.85.39.36"),
+				DBUpdaterConfig{
+					URL:      server.URL + "/db.mmdb",
+					Signer:   tt.signer,
+					Interval: time.Hour,
+					Path:     path,
+				},
+			)
+			assert.NoError(t, err)
+
+			err = resolver.Update()
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.NoFileExists(t, path)
+			} else {
+				assert.NoError(t, err)
+				assert.FileExists(t, path)
+			}
+
+			loc, err := resolver.DetectLocation()
+			assert.NoError(t, err)
+			assert.Equal(t, "NL", loc.Country)
+		})
+	}
+}
+
+func TestUpdatingDBResolver_LoadPersistedVerifiesSignature(t *testing.T) {
+	db, err := os.ReadFile("db/GeoLite2-Country.mmdb")
+	assert.NoError(t, err)
+
+	key, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+	sig, err := crypto.Sign(crypto.Keccak256(db), key)
+	assert.NoError(t, err)
+	signer := crypto.PubkeyToAddress(key.PublicKey).Hex()
+
+	tests := []struct {
+		name      string
+		signature string
+		wantErr   bool
+	}{
+		{"valid signature", hex.EncodeToString(sig), false},
+		{"tampered signature", hex.EncodeToString(sig[1:]), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "db.mmdb")
+			assert.NoError(t, os.WriteFile(path, db, 0600))
+			assert.NoError(t, os.WriteFile(path+".sig", []byte(tt.signature), 0600))
+
+			r := &UpdatingDBResolver{
+				cfg:        DBUpdaterConfig{Path: path, Signer: signer},
+				ipResolver: ip.NewResolverMock("95.85.39.36"),
+				verifier:   identity.NewVerifierIdentity(identity.FromAddress(signer)),
+			}
+			resolver, err := r.loadPersisted()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NoError(t, resolver.Close())
+		})
+	}
+}
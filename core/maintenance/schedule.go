@@ -0,0 +1,141 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package maintenance
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mysteriumnetwork/node/config"
+	"github.com/mysteriumnetwork/node/eventbus"
+	"github.com/mysteriumnetwork/node/market"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cast"
+)
+
+// maxAnnounced is the maximal number of upcoming windows attached to the proposal.
+const maxAnnounced = 5
+
+// Schedule keeps the upcoming maintenance windows of the provider.
+type Schedule struct {
+	lock    sync.Mutex
+	windows []market.MaintenanceWindow
+	notice  time.Duration
+	changed chan struct{}
+	now     func() time.Time
+}
+
+// NewSchedule returns a new Schedule of the given windows.
+// Consumers are notified the notice duration before the window starts.
+func NewSchedule(windows []market.MaintenanceWindow, notice time.Duration) *Schedule {
+	return &Schedule{
+		windows: sorted(windows),
+		notice:  notice,
+		changed: make(chan struct{}),
+		now:     time.Now,
+	}
+}
+
+// ParseWindows parses the maintenance windows, failing on the first invalid window.
+func ParseWindows(values []string) ([]market.MaintenanceWindow, error) {
+	windows := make([]market.MaintenanceWindow, 0, len(values))
+	for _, v := range values {
+		w, err := market.ParseMaintenanceWindow(v)
+		if err != nil {
+			return nil, err
+		}
+		windows = append(windows, w)
+	}
+	return windows, nil
+}
+
+// Subscribe subscribes to the maintenance windows configuration changes.
+func (s *Schedule) Subscribe(eb eventbus.Subscriber) error {
+	return eb.SubscribeAsync(config.AppTopicConfig(config.FlagMaintenanceWindows.Name), s.handleWindowsChange)
+}
+
+func (s *Schedule) handleWindowsChange(value interface{}) {
+	windows, err := ParseWindows(cast.ToStringSlice(value))
+	if err != nil {
+		log.Error().Err(err).Msg("Ignoring invalid maintenance windows")
+		return
+	}
+	s.Set(windows)
+}
+
+// Set replaces the maintenance windows.
+func (s *Schedule) Set(windows []market.MaintenanceWindow) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.windows = sorted(windows)
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+// Changed returns a channel which is closed once the windows change.
+func (s *Schedule) Changed() <-chan struct{} {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.changed
+}
+
+// Notice returns how long before the window starts the consumers are notified.
+func (s *Schedule) Notice() time.Duration {
+	return s.notice
+}
+
+// Upcoming returns the windows which have not ended yet, the earliest first.
+func (s *Schedule) Upcoming() []market.MaintenanceWindow {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	now := s.now()
+	var upcoming []market.MaintenanceWindow
+	for _, w := range s.windows {
+		if len(upcoming) == maxAnnounced {
+			break
+		}
+		if w.End.After(now) {
+			upcoming = append(upcoming, w)
+		}
+	}
+	return upcoming
+}
+
+// Next returns the earliest window which has not ended at the given time.
+func (s *Schedule) Next(at time.Time) (market.MaintenanceWindow, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, w := range s.windows {
+		if w.End.After(at) {
+			return w, true
+		}
+	}
+	return market.MaintenanceWindow{}, false
+}
+
+func sorted(windows []market.MaintenanceWindow) []market.MaintenanceWindow {
+	result := append([]market.MaintenanceWindow(nil), windows...)
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Start.Before(result[j].Start)
+	})
+	return result
+}
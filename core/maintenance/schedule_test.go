@@ -0,0 +1,91 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package maintenance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mysteriumnetwork/node/market"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func window(start time.Time, d time.Duration) market.MaintenanceWindow {
+	return market.MaintenanceWindow{Start: start, End: start.Add(d)}
+}
+
+func TestSchedule(t *testing.T) {
+	// given
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	past := window(now.Add(-3*time.Hour), time.Hour)
+	current := window(now.Add(-time.Hour), 2*time.Hour)
+	next := window(now.Add(24*time.Hour), time.Hour)
+	s := NewSchedule([]market.MaintenanceWindow{next, past, current}, 10*time.Minute)
+	s.now = func() time.Time { return now }
+
+	// then
+	assert.Equal(t, []market.MaintenanceWindow{current, next}, s.Upcoming())
+	w, ok := s.Next(now)
+	assert.True(t, ok)
+	assert.Equal(t, current, w)
+	w, ok = s.Next(current.End)
+	assert.True(t, ok)
+	assert.Equal(t, next, w)
+	_, ok = s.Next(next.End)
+	assert.False(t, ok)
+	assert.Equal(t, 10*time.Minute, s.Notice())
+}
+
+func TestSchedule_ConfigChange(t *testing.T) {
+	// given
+	s := NewSchedule(nil, time.Minute)
+	changed := s.Changed()
+
+	// when
+	s.handleWindowsChange([]interface{}{"invalid"})
+
+	// then
+	select {
+	case <-changed:
+		t.Fatal("invalid windows must be ignored")
+	default:
+	}
+
+	// when
+	s.handleWindowsChange([]interface{}{"2099-01-01T00:00:00Z/1h"})
+
+	// then
+	select {
+	case <-changed:
+	default:
+		t.Fatal("changed channel is not closed")
+	}
+	upcoming := s.Upcoming()
+	require.Len(t, upcoming, 1)
+	assert.Equal(t, time.Date(2099, 1, 1, 1, 0, 0, 0, time.UTC), upcoming[0].End)
+}
+
+func TestParseWindows(t *testing.T) {
+	windows, err := ParseWindows([]string{"2024-05-01T02:00:00Z/2h", "2024-05-02T02:00:00Z/30m"})
+	assert.NoError(t, err)
+	assert.Len(t, windows, 2)
+
+	_, err = ParseWindows([]string{"2024-05-01T02:00:00Z/2h", "tomorrow"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,309 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package mqtt
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
+	"github.com/mysteriumnetwork/node/core/service/servicestate"
+	"github.com/mysteriumnetwork/node/eventbus"
+	sessionEvent "github.com/mysteriumnetwork/node/session/event"
+	"github.com/mysteriumnetwork/payments/crypto"
+)
+
+const (
+	// EventsSessions publishes session start/end events and the number of active sessions.
+	EventsSessions = "sessions"
+	// EventsTraffic publishes traffic transferred during provider sessions.
+	EventsTraffic = "traffic"
+	// EventsEarnings publishes tokens earned during provider sessions.
+	EventsEarnings = "earnings"
+	// EventsServices publishes service status changes.
+	EventsServices = "services"
+	// EventsConnection publishes consumer connection state changes.
+	EventsConnection = "connection"
+
+	statusOnline  = "online"
+	statusOffline = "offline"
+
+	// Node events are informational, so they are sent at most once.
+	qos = 0
+	// stopTimeout bounds the time spent announcing the offline status on stop.
+	stopTimeout = 2 * time.Second
+)
+
+// Config configures the MQTT bridge.
+type Config struct {
+	Broker      string
+	TopicPrefix string
+	ClientID    string
+	Username    string
+	Password    string
+	Events      []string
+	KeepAlive   time.Duration
+	RetryDelay  time.Duration
+}
+
+// Bridge publishes selected node events to an MQTT broker.
+type Bridge struct {
+	cfg    Config
+	events map[string]struct{}
+	client paho.Client
+
+	lock      sync.Mutex
+	connected bool
+	retained  map[string][]byte
+	sessions  map[string]struct{}
+}
+
+// NewBridge creates an MQTT bridge.
+func NewBridge(cfg Config) *Bridge {
+	events := make(map[string]struct{}, len(cfg.Events))
+	for _, e := range cfg.Events {
+		events[e] = struct{}{}
+	}
+
+	b := &Bridge{
+		cfg:      cfg,
+		events:   events,
+		retained: make(map[string][]byte),
+		sessions: make(map[string]struct{}),
+	}
+
+	opts := paho.NewClientOptions().
+		AddBroker(cfg.Broker).
+		SetClientID(cfg.ClientID).
+		SetUsername(cfg.Username).
+		SetPassword(cfg.Password).
+		SetKeepAlive(cfg.KeepAlive).
+		SetWill(b.topic("status"), statusOffline, qos, true).
+		SetAutoReconnect(true).
+		SetConnectRetry(true).
+		SetConnectRetryInterval(cfg.RetryDelay).
+		SetMaxReconnectInterval(cfg.RetryDelay).
+		SetOnConnectHandler(b.handleConnected).
+		SetConnectionLostHandler(b.handleConnectionLost)
+	b.client = paho.NewClient(opts)
+
+	return b
+}
+
+// Subscribe subscribes to relevant events of event bus.
+func (b *Bridge) Subscribe(bus eventbus.Subscriber) error {
+	subscription := map[string]interface{}{
+		sessionEvent.AppTopicSession:              b.handleSession,
+		sessionEvent.AppTopicDataTransferredBatch: b.handleDataTransferredBatch,
+		sessionEvent.AppTopicTokensEarned:         b.handleTokensEarned,
+		servicestate.AppTopicServiceStatus:        b.handleServiceStatus,
+		connectionstate.AppTopicConnectionState:   b.handleConnectionState,
+	}
+
+	for topic, fn := range subscription {
+		if err := bus.SubscribeAsync(topic, fn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Start connects to the broker in the background, the connection is retried until stopped.
+func (b *Bridge) Start() {
+	b.client.Connect()
+}
+
+// Stop announces the node going offline and disconnects from the broker.
+func (b *Bridge) Stop() {
+	if b.isConnected() {
+		b.client.Publish(b.topic("status"), qos, true, statusOffline).WaitTimeout(stopTimeout)
+	}
+	b.client.Disconnect(uint(stopTimeout / time.Millisecond))
+}
+
+// handleConnected re-publishes all retained state, so that a fresh connection catches up.
+func (b *Bridge) handleConnected(c paho.Client) {
+	log.Info().Msgf("Connected to MQTT broker %s", b.cfg.Broker)
+
+	b.lock.Lock()
+	b.connected = true
+	retained := make(map[string][]byte, len(b.retained))
+	for topic, payload := range b.retained {
+		retained[topic] = payload
+	}
+	b.lock.Unlock()
+
+	b.send(b.topic("status"), []byte(statusOnline), true)
+	for topic, payload := range retained {
+		b.send(topic, payload, true)
+	}
+}
+
+func (b *Bridge) handleConnectionLost(_ paho.Client, err error) {
+	log.Warn().Err(err).Msgf("Lost connection to MQTT broker %s", b.cfg.Broker)
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.connected = false
+}
+
+func (b *Bridge) isConnected() bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.connected
+}
+
+func (b *Bridge) publish(subtopic string, payload []byte, retain bool) {
+	topic := b.topic(subtopic)
+
+	b.lock.Lock()
+	if retain {
+		b.retained[topic] = payload
+	}
+	connected := b.connected
+	b.lock.Unlock()
+
+	// Messages are dropped while disconnected, retained state catches up on reconnect.
+	if connected {
+		b.send(topic, payload, retain)
+	}
+}
+
+// send hands the message over to the client without waiting for it to be written.
+func (b *Bridge) send(topic string, payload []byte, retain bool) {
+	token := b.client.Publish(topic, qos, retain, payload)
+	go func() {
+		<-token.Done()
+		if err := token.Error(); err != nil {
+			log.Warn().Err(err).Msgf("Failed to publish MQTT message to %s", topic)
+		}
+	}()
+}
+
+func (b *Bridge) publishJSON(subtopic string, v interface{}, retain bool) {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		log.Err(err).Msgf("Failed to marshal MQTT message for %s", subtopic)
+		return
+	}
+	b.publish(subtopic, payload, retain)
+}
+
+func (b *Bridge) topic(subtopic string) string {
+	if b.cfg.TopicPrefix == "" {
+		return subtopic
+	}
+	return b.cfg.TopicPrefix + "/" + subtopic
+}
+
+func (b *Bridge) selected(events string) bool {
+	_, ok := b.events[events]
+	return ok
+}
+
+func (b *Bridge) handleSession(e sessionEvent.AppEventSession) {
+	if !b.selected(EventsSessions) {
+		return
+	}
+
+	b.lock.Lock()
+	switch e.Status {
+	case sessionEvent.CreatedStatus:
+		b.sessions[e.Session.ID] = struct{}{}
+	case sessionEvent.RemovedStatus:
+		delete(b.sessions, e.Session.ID)
+	default:
+		b.lock.Unlock()
+		return
+	}
+	active := len(b.sessions)
+	b.lock.Unlock()
+
+	b.publishJSON("sessions/event", sessionMessage{
+		Status:      string(e.Status),
+		SessionID:   e.Session.ID,
+		ServiceID:   e.Service.ID,
+		ServiceType: e.Session.Proposal.ServiceType,
+		ConsumerID:  e.Session.ConsumerID.Address,
+	}, false)
+	b.publish("sessions/active", []byte(strconv.Itoa(active)), true)
+}
+
+func (b *Bridge) handleDataTransferredBatch(e sessionEvent.AppEventDataTransferredBatch) {
+	for _, transferred := range e.Sessions {
+		b.handleDataTransferred(transferred)
+	}
+}
+
+func (b *Bridge) handleDataTransferred(e sessionEvent.AppEventDataTransferred) {
+	if !b.selected(EventsTraffic) {
+		return
+	}
+
+	b.publishJSON(fmt.Sprintf("sessions/%s/traffic", e.ID), trafficMessage{Up: e.Up, Down: e.Down}, false)
+}
+
+func (b *Bridge) handleTokensEarned(e sessionEvent.AppEventTokensEarned) {
+	if !b.selected(EventsEarnings) || e.Total == nil {
+		return
+	}
+
+	b.publishJSON(fmt.Sprintf("sessions/%s/earnings", e.SessionID), earningsMessage{
+		Total: crypto.BigMystToFloat(e.Total),
+	}, false)
+}
+
+func (b *Bridge) handleServiceStatus(e servicestate.AppEventServiceStatus) {
+	if !b.selected(EventsServices) {
+		return
+	}
+
+	b.publish(fmt.Sprintf("services/%s/status", e.Type), []byte(e.Status), true)
+}
+
+func (b *Bridge) handleConnectionState(e connectionstate.AppEventConnectionState) {
+	if !b.selected(EventsConnection) {
+		return
+	}
+
+	b.publish("connection/state", []byte(e.State), true)
+}
+
+type sessionMessage struct {
+	Status      string `json:"status"`
+	SessionID   string `json:"session_id"`
+	ServiceID   string `json:"service_id"`
+	ServiceType string `json:"service_type"`
+	ConsumerID  string `json:"consumer_id"`
+}
+
+type trafficMessage struct {
+	Up   uint64 `json:"up"`
+	Down uint64 `json:"down"`
+}
+
+type earningsMessage struct {
+	Total float64 `json:"total"`
+}
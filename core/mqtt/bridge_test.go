@@ -0,0 +1,192 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package mqtt
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/core/service/servicestate"
+	sessionEvent "github.com/mysteriumnetwork/node/session/event"
+)
+
+// MQTT control packet types and flags used by the test broker.
+const (
+	packetConnect    byte = 0x10
+	packetConnack    byte = 0x20
+	packetPublish    byte = 0x30
+	packetDisconnect byte = 0xe0
+	flagRetain       byte = 0x01
+)
+
+type message struct {
+	topic   string
+	payload string
+	retain  bool
+}
+
+func TestBridge_PublishesEvents(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer listener.Close()
+
+	connected := make(chan string, 1)
+	messages := make(chan message, 10)
+	go serveBroker(t, listener, connected, messages)
+
+	bridge := NewBridge(Config{
+		Broker:      "tcp://" + listener.Addr().String(),
+		TopicPrefix: "home/node",
+		ClientID:    "test-node",
+		Events:      []string{EventsSessions},
+		KeepAlive:   time.Minute,
+		RetryDelay:  time.Second,
+	})
+
+	// Retained state is published as soon as connection is established.
+	bridge.handleServiceStatus(servicestate.AppEventServiceStatus{Type: "wireguard", Status: "Running"})
+	bridge.handleSession(sessionEvent.AppEventSession{
+		Status:  sessionEvent.CreatedStatus,
+		Session: sessionEvent.SessionContext{ID: "session-1"},
+	})
+	bridge.Start()
+
+	select {
+	case clientID := <-connected:
+		assert.Equal(t, "test-node", clientID)
+	case <-time.After(2 * time.Second):
+		t.Fatal("bridge did not connect")
+	}
+
+	assert.Equal(t, message{topic: "home/node/status", payload: statusOnline, retain: true}, receive(t, messages))
+	assert.Equal(t, message{topic: "home/node/sessions/active", payload: "1", retain: true}, receive(t, messages))
+
+	bridge.handleSession(sessionEvent.AppEventSession{
+		Status:  sessionEvent.RemovedStatus,
+		Session: sessionEvent.SessionContext{ID: "session-1"},
+	})
+	event := receive(t, messages)
+	assert.Equal(t, "home/node/sessions/event", event.topic)
+	assert.Contains(t, event.payload, `"status":"RemovedStatus"`)
+	assert.False(t, event.retain)
+	assert.Equal(t, message{topic: "home/node/sessions/active", payload: "0", retain: true}, receive(t, messages))
+
+	bridge.Stop()
+	assert.Equal(t, message{topic: "home/node/status", payload: statusOffline, retain: true}, receive(t, messages))
+}
+
+func TestBridge_StopWithoutBroker(t *testing.T) {
+	bridge := NewBridge(Config{
+		Broker:     "tcp://127.0.0.1:1",
+		ClientID:   "test-node",
+		KeepAlive:  time.Minute,
+		RetryDelay: time.Second,
+	})
+
+	stopped := make(chan struct{})
+	go func() {
+		bridge.Stop()
+		bridge.Start()
+		bridge.Stop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("bridge did not stop")
+	}
+}
+
+func serveBroker(t *testing.T, listener net.Listener, connected chan<- string, messages chan<- message) {
+	conn, err := listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	header, body, err := readPacket(r)
+	if !assert.NoError(t, err) || !assert.Equal(t, packetConnect, header) {
+		return
+	}
+	conn.Write([]byte{packetConnack, 2, 0, 0})
+	// Client identifier follows protocol name, level, flags and keep alive.
+	connected <- readString(body[10:])
+
+	for {
+		header, body, err := readPacket(r)
+		if err != nil || header == packetDisconnect {
+			return
+		}
+		if header&0xf0 != packetPublish {
+			continue
+		}
+		topic := readString(body)
+		messages <- message{
+			topic:   topic,
+			payload: string(body[2+len(topic):]),
+			retain:  header&flagRetain != 0,
+		}
+	}
+}
+
+func readPacket(r *bufio.Reader) (byte, []byte, error) {
+	header, err := r.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	length, multiplier := 0, 1
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+		length += int(b&0x7f) * multiplier
+		if b&0x80 == 0 {
+			break
+		}
+		multiplier *= 128
+	}
+
+	body := make([]byte, length)
+	_, err = io.ReadFull(r, body)
+	return header, body, err
+}
+
+func readString(b []byte) string {
+	length := binary.BigEndian.Uint16(b)
+	return string(b[2 : 2+length])
+}
+
+func receive(t *testing.T, messages <-chan message) message {
+	select {
+	case m := <-messages:
+		return m
+	case <-time.After(2 * time.Second):
+		t.Fatal("message was not published")
+		return message{}
+	}
+}
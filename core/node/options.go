@@ -26,6 +26,7 @@ import (
 
 	"github.com/mysteriumnetwork/node/config"
 	"github.com/mysteriumnetwork/node/logconfig"
+	"github.com/mysteriumnetwork/node/logconfig/rollingwriter"
 	"github.com/mysteriumnetwork/node/metadata"
 	openvpn_core "github.com/mysteriumnetwork/node/services/openvpn/core"
 )
@@ -62,6 +63,8 @@ type Options struct {
 	TequilapiEnabled       bool
 	TequilapiSecured       bool
 	BindAddress            string
+	BindInterface          string
+	UpstreamProxy          string
 	UI                     OptionsUI
 	FeedbackURL            string
 
@@ -88,17 +91,24 @@ type Options struct {
 	PilvytisAddress         string
 	ObserverAddress         string
 	SSE                     OptionsSSE
+	Webhook                 OptionsWebhook
+	MQTT                    OptionsMQTT
+	SLA                     OptionsSLA
+	ProposalMetadata        OptionsProposalMetadata
+	Analytics               OptionsAnalytics
 }
 
 // GetOptions retrieves node options from the app configuration.
 func GetOptions() *Options {
 	network := OptionsNetwork{
-		Network:          config.GetBlockchainNetwork(config.FlagBlockchainNetwork),
-		DiscoveryAddress: config.GetString(config.FlagDiscoveryAddress),
-		BrokerAddresses:  config.GetStringSlice(config.FlagBrokerAddress),
-		EtherClientRPCL1: config.GetStringSlice(config.FlagEtherRPCL1),
-		EtherClientRPCL2: config.GetStringSlice(config.FlagEtherRPCL2),
-		ChainID:          config.GetInt64(config.FlagChainID),
+		Network:                        config.GetBlockchainNetwork(config.FlagBlockchainNetwork),
+		DiscoveryAddress:               config.GetString(config.FlagDiscoveryAddress),
+		BrokerAddresses:                config.GetStringSlice(config.FlagBrokerAddress),
+		EtherClientRPCL1:               config.GetStringSlice(config.FlagEtherRPCL1),
+		EtherClientRPCL2:               config.GetStringSlice(config.FlagEtherRPCL2),
+		EtherClientFailoverTimeout:     config.GetDuration(config.FlagEtherRPCFailoverTimeout),
+		EtherClientHealthCheckInterval: config.GetDuration(config.FlagEtherRPCHealthCheckInterval),
+		ChainID:                        config.GetInt64(config.FlagChainID),
 		DNSMap: map[string][]string{
 			"location.mysterium.network": {"51.158.129.204"},
 			"quality.mysterium.network":  {"51.158.129.204"},
@@ -119,6 +129,8 @@ func GetOptions() *Options {
 		FlagTequilapiDebugMode: config.GetBool(config.FlagTequilapiDebugMode),
 		TequilapiEnabled:       true,
 		BindAddress:            config.GetString(config.FlagBindAddress),
+		BindInterface:          config.GetString(config.FlagBindInterface),
+		UpstreamProxy:          config.GetString(config.FlagUpstreamProxy),
 		UI: OptionsUI{
 			UIEnabled:     config.GetBool(config.FlagUIEnable),
 			UIBindAddress: config.GetString(config.FlagUIAddress),
@@ -128,6 +140,19 @@ func GetOptions() *Options {
 		FeedbackURL:             config.GetString(config.FlagFeedbackURL),
 		Keystore: OptionsKeystore{
 			UseLightweight: config.GetBool(config.FlagKeystoreLightweight),
+			UnlockTTL:      config.GetDuration(config.FlagKeystoreUnlockTTL),
+			UnlockPolicy:   config.GetString(config.FlagKeystoreUnlockPolicy),
+			UnlockCredentials: OptionsUnlockCredentials{
+				File:    config.GetString(config.FlagKeystoreUnlockCredentials),
+				KeyFile: config.GetString(config.FlagKeystoreUnlockCredentialsKey),
+			},
+			LockMemory: config.GetBool(config.FlagKeystoreLockMemory),
+			RemoteSigner: OptionsRemoteSigner{
+				URL:      config.GetString(config.FlagKeystoreRemoteSignerURL),
+				CertFile: config.GetString(config.FlagKeystoreRemoteSignerCert),
+				KeyFile:  config.GetString(config.FlagKeystoreRemoteSignerKey),
+				CAFile:   config.GetString(config.FlagKeystoreRemoteSignerCA),
+			},
 		},
 		LogOptions:     *GetLogOptions(),
 		OptionsNetwork: network,
@@ -143,12 +168,28 @@ func GetOptions() *Options {
 			Country:       config.GetString(config.FlagLocationCountry),
 			City:          config.GetString(config.FlagLocationCity),
 			IPType:        config.GetString(config.FlagLocationIPType),
+
+			OverrideCountry:  config.GetString(config.FlagLocationOverrideCountry),
+			OverrideCity:     config.GetString(config.FlagLocationOverrideCity),
+			DBUpdateURL:      config.GetString(config.FlagLocationDBUpdateURL),
+			DBUpdateSigner:   config.GetString(config.FlagLocationDBUpdateSigner),
+			DBUpdateInterval: config.GetDuration(config.FlagLocationDBUpdateInterval),
 		},
 		Transactor: OptionsTransactor{
 			TransactorEndpointAddress:       config.GetString(config.FlagTransactorAddress),
 			ProviderMaxRegistrationAttempts: config.GetInt(config.FlagTransactorProviderMaxRegistrationAttempts),
 			TransactorFeesValidTime:         config.GetDuration(config.FlagTransactorFeesValidTime),
 			TryFreeRegistration:             config.GetBool(config.FlagProviderTryFreeRegistration),
+			GasPrice: map[int64]OptionsGasPrice{
+				config.GetInt64(config.FlagChain1ChainID): {
+					Speed:     config.GetString(config.FlagChain1GasSpeed),
+					MaxTipCap: config.GetString(config.FlagChain1GasTipCap),
+				},
+				config.GetInt64(config.FlagChain2ChainID): {
+					Speed:     config.GetString(config.FlagChain2GasSpeed),
+					MaxTipCap: config.GetString(config.FlagChain2GasTipCap),
+				},
+			},
 		},
 		Affiliator: OptionsAffiliator{
 			AffiliatorEndpointAddress: config.GetString(config.FlagAffiliatorAddress),
@@ -166,9 +207,19 @@ func GetOptions() *Options {
 			BalanceFastPollTimeout:         config.GetDuration(config.FlagPaymentsFastBalancePollTimeout),
 			RegistryTransactorPollInterval: config.GetDuration(config.FlagPaymentsRegistryTransactorPollInterval),
 			RegistryTransactorPollTimeout:  config.GetDuration(config.FlagPaymentsRegistryTransactorPollTimeout),
+			RegistrationRecheckInterval:    config.GetDuration(config.FlagPaymentsRegistrationRecheckInterval),
+			RegistrationStatusCacheTTL:     config.GetDuration(config.FlagPaymentsRegistrationStatusCacheTTL),
 			ConsumerDataLeewayMegabytes:    config.GetUInt64(config.FlagPaymentsConsumerDataLeewayMegabytes),
 			HermesStatusRecheckInterval:    config.GetDuration(config.FlagPaymentsHermesStatusRecheckInterval),
 			MinAutoSettleAmount:            config.GetFloat64(config.FlagPaymentsZeroStakeUnsettledAmount),
+			AutoSettle: OptionsAutoSettle{
+				Enabled:         config.GetBool(config.FlagPaymentsAutoSettleEnabled),
+				CheckInterval:   config.GetDuration(config.FlagPaymentsAutoSettleCheckInterval),
+				ThresholdAmount: config.GetFloat64(config.FlagPaymentsAutoSettleThresholdAmount),
+				StakeThreshold:  config.GetFloat64(config.FlagPaymentsAutoSettleStakeThreshold),
+				MinInterval:     config.GetDuration(config.FlagPaymentsAutoSettleMinInterval),
+				MaxGasPriceGwei: config.GetFloat64(config.FlagPaymentsAutoSettleMaxGasPrice),
+			},
 
 			ProviderInvoiceFrequency:      config.GetDuration(config.FlagPaymentsProviderInvoiceFrequency),
 			ProviderLimitInvoiceFrequency: config.GetDuration(config.FlagPaymentsLimitProviderInvoiceFrequency),
@@ -205,6 +256,37 @@ func GetOptions() *Options {
 		SSE: OptionsSSE{
 			Enabled: config.GetBool(config.FlagSSEEnable),
 		},
+		Webhook: OptionsWebhook{
+			URLs:       config.GetStringSlice(config.FlagWebhookURL),
+			Secret:     config.GetString(config.FlagWebhookSecret),
+			Events:     config.GetStringSlice(config.FlagWebhookEvents),
+			MaxRetries: config.GetInt(config.FlagWebhookMaxRetries),
+		},
+		MQTT: OptionsMQTT{
+			Broker:      config.GetString(config.FlagMQTTBroker),
+			TopicPrefix: config.GetString(config.FlagMQTTTopicPrefix),
+			ClientID:    config.GetString(config.FlagMQTTClientID),
+			Username:    config.GetString(config.FlagMQTTUsername),
+			Password:    config.GetString(config.FlagMQTTPassword),
+			Events:      config.GetStringSlice(config.FlagMQTTEvents),
+		},
+		SLA: OptionsSLA{
+			MinThroughput: config.GetUInt64(config.FlagSLAMinThroughput),
+			MaxSetupTime:  config.GetDuration(config.FlagSLAMaxSetupTime),
+			Refund:        config.GetInt(config.FlagSLARefund),
+		},
+		ProposalMetadata: OptionsProposalMetadata{
+			Nickname:    config.GetString(config.FlagProposalNickname),
+			Description: config.GetString(config.FlagProposalDescription),
+			ContactURL:  config.GetString(config.FlagProposalContactURL),
+		},
+		Analytics: OptionsAnalytics{
+			Enabled:    config.GetBool(config.FlagAnalyticsEnabled),
+			Categories: config.GetStringSlice(config.FlagAnalyticsCategories),
+			URL:        config.GetString(config.FlagAnalyticsURL),
+			BatchSize:  config.GetInt(config.FlagAnalyticsBatchSize),
+			Interval:   config.GetDuration(config.FlagAnalyticsInterval),
+		},
 	}
 }
 
@@ -223,6 +305,17 @@ func GetLogOptions() *logconfig.LogOptions {
 		LogLevel: level,
 		LogHTTP:  config.GetBool(config.FlagLogHTTP),
 		Filepath: filepath,
+		Rotation: GetLogRotation(),
+	}
+}
+
+// GetLogRotation retrieves log file rotation options from the app configuration.
+func GetLogRotation() rollingwriter.Config {
+	return rollingwriter.Config{
+		MaxSize:    int64(config.GetUInt64(config.FlagLogMaxSize)) * 1024 * 1024,
+		MaxAge:     config.GetDuration(config.FlagLogMaxAge),
+		MaxBackups: config.GetInt(config.FlagLogMaxBackups),
+		Compress:   config.GetBool(config.FlagLogCompress),
 	}
 }
 
@@ -235,11 +328,14 @@ func GetDiscoveryOptions() *OptionsDiscovery {
 	}
 
 	return &OptionsDiscovery{
-		Types:         types,
-		PingInterval:  config.GetDuration(config.FlagDiscoveryPingInterval),
-		FetchEnabled:  true,
-		FetchInterval: config.GetDuration(config.FlagDiscoveryFetchInterval),
-		DHT:           *GetDHTOptions(),
+		Types:              types,
+		PingInterval:       config.GetDuration(config.FlagDiscoveryPingInterval),
+		PingJitter:         config.GetFloat64(config.FlagDiscoveryPingJitter),
+		PingMaxBackoff:     config.GetDuration(config.FlagDiscoveryPingMaxBackoff),
+		UnconfirmedTimeout: config.GetDuration(config.FlagDiscoveryUnconfirmedTimeout),
+		FetchEnabled:       true,
+		FetchInterval:      config.GetDuration(config.FlagDiscoveryFetchInterval),
+		DHT:                *GetDHTOptions(),
 	}
 }
 
@@ -250,10 +346,30 @@ func GetDHTOptions() *OptionsDHT {
 		Port:           config.GetInt(config.FlagDHTPort),
 		Protocol:       config.GetString(config.FlagDHTProtocol),
 		BootstrapPeers: config.GetStringSlice(config.FlagDHTBootstrapPeers),
+		Experimental:   config.GetBool(config.FlagDHTExperimental),
 	}
 }
 
 // OptionsKeystore stores the keystore configuration
 type OptionsKeystore struct {
-	UseLightweight bool
+	UseLightweight    bool
+	UnlockTTL         time.Duration
+	UnlockPolicy      string
+	UnlockCredentials OptionsUnlockCredentials
+	LockMemory        bool
+	RemoteSigner      OptionsRemoteSigner
+}
+
+// OptionsUnlockCredentials stores the location of the encrypted credentials used by the startup unlock policy
+type OptionsUnlockCredentials struct {
+	File    string
+	KeyFile string
+}
+
+// OptionsRemoteSigner stores the remote signing service configuration
+type OptionsRemoteSigner struct {
+	URL      string
+	CertFile string
+	KeyFile  string
+	CAFile   string
 }
@@ -33,12 +33,15 @@ const (
 
 // OptionsDiscovery describes possible parameters of discovery configuration.
 type OptionsDiscovery struct {
-	Types         []DiscoveryType
-	Address       string
-	PingInterval  time.Duration
-	FetchEnabled  bool
-	FetchInterval time.Duration
-	DHT           OptionsDHT
+	Types              []DiscoveryType
+	Address            string
+	PingInterval       time.Duration
+	PingJitter         float64
+	PingMaxBackoff     time.Duration
+	UnconfirmedTimeout time.Duration
+	FetchEnabled       bool
+	FetchInterval      time.Duration
+	DHT                OptionsDHT
 }
 
 // OptionsDHT describes possible parameters of DHT configuration.
@@ -47,4 +50,5 @@ type OptionsDHT struct {
 	Port           int
 	Protocol       string
 	BootstrapPeers []string
+	Experimental   bool
 }
@@ -17,6 +17,8 @@
 
 package node
 
+import "time"
+
 // LocationType identifies location type
 type LocationType string
 
@@ -25,6 +27,8 @@ const (
 	LocationTypeManual = LocationType("manual")
 	// LocationTypeBuiltin defines type which resolves location from built in DB
 	LocationTypeBuiltin = LocationType("builtin")
+	// LocationTypeGeoIP defines type which resolves location from self-updating built in DB with oracle fallback
+	LocationTypeGeoIP = LocationType("geoip")
 	// LocationTypeMMDB defines type which resolves location from given MMDB file
 	LocationTypeMMDB = LocationType("mmdb")
 	// LocationTypeOracle defines type which resolves location from given URL of LocationOracle
@@ -40,4 +44,11 @@ type OptionsLocation struct {
 	Country string
 	City    string
 	IPType  string
+
+	OverrideCountry string
+	OverrideCity    string
+
+	DBUpdateURL      string
+	DBUpdateSigner   string
+	DBUpdateInterval time.Duration
 }
@@ -17,15 +17,21 @@
 
 package node
 
-import "github.com/mysteriumnetwork/node/config"
+import (
+	"time"
+
+	"github.com/mysteriumnetwork/node/config"
+)
 
 // OptionsNetwork describes possible parameters of network configuration
 type OptionsNetwork struct {
-	Network          config.BlockchainNetwork
-	DiscoveryAddress string
-	BrokerAddresses  []string
-	EtherClientRPCL1 []string
-	EtherClientRPCL2 []string
-	ChainID          int64
-	DNSMap           map[string][]string
+	Network                        config.BlockchainNetwork
+	DiscoveryAddress               string
+	BrokerAddresses                []string
+	EtherClientRPCL1               []string
+	EtherClientRPCL2               []string
+	EtherClientFailoverTimeout     time.Duration
+	EtherClientHealthCheckInterval time.Duration
+	ChainID                        int64
+	DNSMap                         map[string][]string
 }
@@ -37,8 +37,11 @@ type OptionsPayments struct {
 	BalanceLongPollInterval        time.Duration
 	RegistryTransactorPollInterval time.Duration
 	RegistryTransactorPollTimeout  time.Duration
+	RegistrationRecheckInterval    time.Duration
+	RegistrationStatusCacheTTL     time.Duration
 	MinAutoSettleAmount            float64
 	MaxUnSettledAmount             float64
+	AutoSettle                     OptionsAutoSettle
 
 	ProviderInvoiceFrequency      time.Duration
 	ProviderLimitInvoiceFrequency time.Duration
@@ -46,3 +49,13 @@ type OptionsPayments struct {
 	MaxUnpaidInvoiceValue   *big.Int
 	LimitUnpaidInvoiceValue *big.Int
 }
+
+// OptionsAutoSettle describes the background settlement scheduler.
+type OptionsAutoSettle struct {
+	Enabled         bool
+	CheckInterval   time.Duration
+	ThresholdAmount float64
+	StakeThreshold  float64
+	MinInterval     time.Duration
+	MaxGasPriceGwei float64
+}
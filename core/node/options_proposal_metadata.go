@@ -0,0 +1,49 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package node
+
+import (
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/market"
+)
+
+// OptionsProposalMetadata describes human-readable node information shown in proposals
+type OptionsProposalMetadata struct {
+	Nickname    string
+	Description string
+	ContactURL  string
+}
+
+// Proposal returns the sanitized metadata attached to proposals, nil if none is configured.
+func (o OptionsProposalMetadata) Proposal() *market.ProposalMetadata {
+	metadata := market.ProposalMetadata{
+		Nickname:    o.Nickname,
+		Description: o.Description,
+		ContactURL:  o.ContactURL,
+	}
+	sanitized := metadata.Sanitize()
+	if sanitized != metadata {
+		log.Warn().Msgf("Proposal metadata was sanitized to %+v", sanitized)
+	}
+	if sanitized.IsEmpty() {
+		return nil
+	}
+
+	return &sanitized
+}
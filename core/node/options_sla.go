@@ -0,0 +1,56 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package node
+
+import (
+	"time"
+
+	"github.com/mysteriumnetwork/node/market"
+)
+
+// OptionsSLA describes the service level provider commits to in proposals
+type OptionsSLA struct {
+	// MinThroughput in Mbit/s, 0 disables the commitment.
+	MinThroughput uint64
+	// MaxSetupTime of the session, 0 disables the commitment.
+	MaxSetupTime time.Duration
+	// Refund in percent of the session price.
+	Refund int
+}
+
+// Proposal returns the SLA advertised in proposals, nil if none is configured.
+func (o OptionsSLA) Proposal() *market.SLA {
+	refund := o.Refund
+	if refund <= 0 {
+		return nil
+	}
+	if refund > 100 {
+		refund = 100
+	}
+
+	sla := market.SLA{
+		MinThroughput: o.MinThroughput * 1_000_000,
+		MaxSetupTime:  uint64(o.MaxSetupTime.Milliseconds()),
+		Refund:        uint8(refund),
+	}
+	if sla.MinThroughput == 0 && sla.MaxSetupTime == 0 {
+		return nil
+	}
+
+	return &sla
+}
@@ -25,4 +25,14 @@ type OptionsTransactor struct {
 	ProviderMaxRegistrationAttempts int
 	TransactorFeesValidTime         time.Duration
 	TryFreeRegistration             bool
+	// GasPrice configures the gas price of transactions per chain ID.
+	GasPrice map[int64]OptionsGasPrice
+}
+
+// OptionsGasPrice describes the gas price strategy of transactions on a single chain.
+type OptionsGasPrice struct {
+	// Speed is one of economy, normal or fast.
+	Speed string
+	// MaxTipCap is the max priority fee in wei, unlimited if empty.
+	MaxTipCap string
 }
@@ -30,6 +30,9 @@ type Range struct {
 	Start, End int
 }
 
+// UnprivilegedRange covers all ports which can be bound without elevated privileges.
+var UnprivilegedRange = Range{Start: 1024, End: 65535}
+
 // ParseRange parses port range expression, e.g. "1000:1200" into Range struct
 func ParseRange(rangeExpr string) (Range, error) {
 	bounds := strings.Split(rangeExpr, ":")
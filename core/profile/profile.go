@@ -0,0 +1,54 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package profile
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+
+	"github.com/mysteriumnetwork/node/market"
+)
+
+// AppTopicProfile is published when the node profile changes.
+const AppTopicProfile = "Node profile"
+
+// ErrInvalidAvatarHash represents an error when the avatar hash is not a hex encoded SHA-256 hash.
+var ErrInvalidAvatarHash = errors.New("avatar hash must be a hex encoded SHA-256 hash")
+
+// Profile holds the friendly node name and avatar shown instead of the identity address.
+type Profile struct {
+	Nickname   string `json:"nickname,omitempty"`
+	AvatarHash string `json:"avatar_hash,omitempty"`
+}
+
+// Sanitize returns the profile with the nickname sanitized the same way as in proposals
+// and the avatar hash normalized to lower case.
+func (p Profile) Sanitize() (Profile, error) {
+	hash := strings.ToLower(strings.TrimSpace(p.AvatarHash))
+	if hash != "" {
+		if raw, err := hex.DecodeString(hash); err != nil || len(raw) != 32 {
+			return Profile{}, ErrInvalidAvatarHash
+		}
+	}
+
+	return Profile{
+		Nickname:   market.ProposalMetadata{Nickname: p.Nickname}.Sanitize().Nickname,
+		AvatarHash: hash,
+	}, nil
+}
@@ -0,0 +1,91 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package profile
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/config"
+	"github.com/mysteriumnetwork/node/eventbus"
+)
+
+type configStore interface {
+	GetString(key string) string
+	SetUser(key string, value interface{})
+	SaveUserConfig() error
+}
+
+// Store keeps the node profile in the user config.
+type Store struct {
+	config    configStore
+	publisher eventbus.Publisher
+
+	mu      sync.Mutex
+	profile Profile
+}
+
+// NewStore creates the profile store loading the profile from config.
+func NewStore(cfg configStore, publisher eventbus.Publisher) *Store {
+	stored := Profile{
+		Nickname:   cfg.GetString(config.FlagNodeNickname.Name),
+		AvatarHash: cfg.GetString(config.FlagNodeAvatarHash.Name),
+	}
+	profile, err := stored.Sanitize()
+	if err != nil {
+		log.Warn().Err(err).Msg("Ignoring configured node avatar")
+		profile, _ = Profile{Nickname: stored.Nickname}.Sanitize()
+	}
+
+	return &Store{
+		config:    cfg,
+		publisher: publisher,
+		profile:   profile,
+	}
+}
+
+// Get returns the current node profile.
+func (s *Store) Get() Profile {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.profile
+}
+
+// Set sanitizes and stores the node profile.
+func (s *Store) Set(p Profile) (Profile, error) {
+	profile, err := p.Sanitize()
+	if err != nil {
+		return Profile{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.config.SetUser(config.FlagNodeNickname.Name, profile.Nickname)
+	s.config.SetUser(config.FlagNodeAvatarHash.Name, profile.AvatarHash)
+	if err := s.config.SaveUserConfig(); err != nil {
+		return Profile{}, fmt.Errorf("could not save node profile: %w", err)
+	}
+	s.profile = profile
+
+	s.publisher.Publish(AppTopicProfile, profile)
+	return profile, nil
+}
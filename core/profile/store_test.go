@@ -0,0 +1,81 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package profile
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mysteriumnetwork/node/config"
+)
+
+type mockConfig struct {
+	values map[string]interface{}
+	saved  int
+}
+
+func (m *mockConfig) GetString(key string) string {
+	v, _ := m.values[key].(string)
+	return v
+}
+
+func (m *mockConfig) SetUser(key string, value interface{}) {
+	m.values[key] = value
+}
+
+func (m *mockConfig) SaveUserConfig() error {
+	m.saved++
+	return nil
+}
+
+type mockPublisher struct {
+	published []Profile
+}
+
+func (m *mockPublisher) Publish(topic string, data interface{}) {
+	if topic == AppTopicProfile {
+		m.published = append(m.published, data.(Profile))
+	}
+}
+
+func TestStore(t *testing.T) {
+	cfg := &mockConfig{values: map[string]interface{}{
+		config.FlagNodeNickname.Name:   " Garage\tnode ",
+		config.FlagNodeAvatarHash.Name: "not-a-hash",
+	}}
+	publisher := &mockPublisher{}
+	store := NewStore(cfg, publisher)
+	assert.Equal(t, Profile{Nickname: "Garage node"}, store.Get())
+
+	hash := strings.Repeat("AB", 32)
+	profile, err := store.Set(Profile{Nickname: "Attic <node>", AvatarHash: hash})
+	require.NoError(t, err)
+	expected := Profile{Nickname: "Attic node", AvatarHash: strings.ToLower(hash)}
+	assert.Equal(t, expected, profile)
+	assert.Equal(t, expected, store.Get())
+	assert.Equal(t, []Profile{expected}, publisher.published)
+	assert.Equal(t, "Attic node", cfg.values[config.FlagNodeNickname.Name])
+	assert.Equal(t, 1, cfg.saved)
+
+	_, err = store.Set(Profile{AvatarHash: "abcd"})
+	assert.ErrorIs(t, err, ErrInvalidAvatarHash)
+	assert.Equal(t, expected, store.Get())
+}
@@ -38,6 +38,8 @@ const (
 	StageConnectionAlreadyExists = "connection_already_exists"
 	// StageConnectionUnknownError describes unknown connection event.
 	StageConnectionUnknownError = "connection_unknown_error"
+	// StageConnectionProviderBusy describes connection rejected by an overloaded provider.
+	StageConnectionProviderBusy = "connection_provider_busy"
 
 	// StageRegistrationGetStatus describes getting registration status event.
 	StageRegistrationGetStatus = "registration_get_status"
@@ -0,0 +1,44 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package quality
+
+import "time"
+
+// Recorder records reported events for anonymous usage analytics.
+type Recorder interface {
+	Record(name string, createdAt time.Time, data interface{})
+}
+
+// NewRecordingTransport creates transport which sends events using the given transport
+// and passes them to the analytics recorder as well.
+func NewRecordingTransport(transport Transport, recorder Recorder) *recordingTransport {
+	return &recordingTransport{
+		transport: transport,
+		recorder:  recorder,
+	}
+}
+
+type recordingTransport struct {
+	transport Transport
+	recorder  Recorder
+}
+
+func (transport *recordingTransport) SendEvent(event Event) error {
+	transport.recorder.Record(event.EventName, time.Unix(event.CreatedAt, 0), event.Context)
+	return transport.transport.SendEvent(event)
+}
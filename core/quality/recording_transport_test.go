@@ -0,0 +1,46 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package quality
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordingTransport_SendEvent(t *testing.T) {
+	recorder := &mockRecorder{}
+	transport := NewRecordingTransport(NewNoopTransport(), recorder)
+
+	assert.NoError(t, transport.SendEvent(Event{EventName: unlockEventName, CreatedAt: 1704207845, Context: "0x1234567890abcdef"}))
+
+	assert.Equal(t, unlockEventName, recorder.name)
+	assert.Equal(t, time.Unix(1704207845, 0), recorder.createdAt)
+	assert.Equal(t, "0x1234567890abcdef", recorder.data)
+}
+
+type mockRecorder struct {
+	name      string
+	createdAt time.Time
+	data      interface{}
+}
+
+func (m *mockRecorder) Record(name string, createdAt time.Time, data interface{}) {
+	m.name, m.createdAt, m.data = name, createdAt, data
+}
@@ -188,7 +188,7 @@ func (s *Sender) Subscribe(bus eventbus.Subscriber) error {
 		registry.AppTopicIdentityRegistration:        s.sendRegistrationEvent,
 		sessionEvent.AppTopicSession:                 s.sendServiceSessionEvent,
 		trace.AppTopicTraceEvent:                     s.sendTraceEvent,
-		sessionEvent.AppTopicDataTransferred:         s.sendServiceDataStatistics,
+		sessionEvent.AppTopicDataTransferredBatch:    s.sendServiceDataStatisticsBatch,
 		AppTopicConsumerPingP2P:                      s.sendConsumerPingDistance,
 		AppTopicProviderPingP2P:                      s.sendProviderPingDistance,
 		identity.AppTopicResidentCountry:             s.sendResidentCountry,
@@ -266,6 +266,12 @@ func (s *Sender) sendConnectionEvent(e ConnectionEvent) {
 	s.sendEvent(connectionEvent, e)
 }
 
+func (s *Sender) sendServiceDataStatisticsBatch(e sessionEvent.AppEventDataTransferredBatch) {
+	for _, transferred := range e.Sessions {
+		s.sendServiceDataStatistics(transferred)
+	}
+}
+
 func (s *Sender) sendServiceDataStatistics(e sessionEvent.AppEventDataTransferred) {
 	session, err := s.recoverSessionContext(e.ID)
 	if err != nil {
@@ -283,7 +289,7 @@ func (s *Sender) sendServiceDataStatistics(e sessionEvent.AppEventDataTransferre
 
 // sendSessionData sends transferred information about session.
 func (s *Sender) sendSessionData(e connectionstate.AppEventConnectionStatistics) {
-	if e.SessionInfo.SessionID == "" {
+	if !isReported(e.SessionInfo) {
 		return
 	}
 
@@ -310,7 +316,7 @@ func (s *Sender) sendSessionEarning(e pingpongEvent.AppEventInvoicePaid) {
 
 // sendConnStateEvent sends session update events.
 func (s *Sender) sendConnStateEvent(e connectionstate.AppEventConnectionState) {
-	if e.SessionInfo.SessionID == "" {
+	if !isReported(e.SessionInfo) {
 		return
 	}
 
@@ -352,7 +358,7 @@ func (s *Sender) sendServiceSessionEvent(e sessionEvent.AppEventSession) {
 
 // sendSessionEvent sends session update events.
 func (s *Sender) sendSessionEvent(e connectionstate.AppEventConnectionSession) {
-	if e.SessionInfo.SessionID == "" {
+	if !isReported(e.SessionInfo) {
 		return
 	}
 
@@ -490,6 +496,11 @@ func (s *Sender) recoverSessionContext(sessionID string) (sessionContext, error)
 	return context, nil
 }
 
+// isReported tells whether consumer session events are sent, sessions with external endpoints are kept private.
+func isReported(session connectionstate.Status) bool {
+	return session.SessionID != "" && !session.Proposal.IsExternal()
+}
+
 func (s *Sender) toSessionContext(session connectionstate.Status) sessionContext {
 	return sessionContext{
 		ID:              string(session.SessionID),
@@ -0,0 +1,307 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package rpcfailover
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/mysteriumnetwork/payments/bindings"
+	paymentClient "github.com/mysteriumnetwork/payments/client"
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/eventbus"
+)
+
+// AppTopicEndpointSwitched is published when the active RPC endpoint of a chain changes.
+const AppTopicEndpointSwitched = "rpc-endpoint-switched"
+
+// Reasons of the endpoint switch.
+const (
+	// ReasonTimeout means a call to the active endpoint timed out.
+	ReasonTimeout = "timeout"
+	// ReasonUnhealthy means the active endpoint failed a health check.
+	ReasonUnhealthy = "unhealthy"
+	// ReasonRecovered means an endpoint preferred over the active one is healthy again.
+	ReasonRecovered = "recovered"
+)
+
+// DefaultCallTimeout is used when the call timeout is not configured.
+const DefaultCallTimeout = 10 * time.Second
+
+// ErrNoEndpoints is returned when the client has no RPC endpoints to call.
+var ErrNoEndpoints = errors.New("no rpc endpoints configured")
+
+// AppEventEndpointSwitched describes the switch of the active RPC endpoint.
+type AppEventEndpointSwitched struct {
+	ChainID  int64  `json:"chain_id"`
+	Previous string `json:"previous"`
+	Current  string `json:"current"`
+	Reason   string `json:"reason"`
+}
+
+// Config configures the failover client.
+type Config struct {
+	// CallTimeout is the time a single endpoint gets to answer a call before the next one is tried.
+	CallTimeout time.Duration
+	// HealthCheckInterval is the interval of endpoint health checks, zero disables them.
+	HealthCheckInterval time.Duration
+}
+
+type endpoint struct {
+	url     string
+	client  paymentClient.EthClientGetter
+	healthy bool
+}
+
+// Client calls the blockchain through an ordered list of RPC endpoints of a single chain.
+// Calls go to the first healthy endpoint and move to the next one once a call times out.
+type Client struct {
+	chainID   int64
+	cfg       Config
+	publisher eventbus.Publisher
+
+	lock      sync.Mutex
+	endpoints []*endpoint
+	active    int
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewClient creates new failover client for the given chain. Clients are used in the given order of preference.
+func NewClient(chainID int64, clients []paymentClient.AddressableEthClientGetter, publisher eventbus.Publisher, cfg Config) *Client {
+	if cfg.CallTimeout <= 0 {
+		cfg.CallTimeout = DefaultCallTimeout
+	}
+
+	endpoints := make([]*endpoint, 0, len(clients))
+	for _, c := range clients {
+		endpoints = append(endpoints, &endpoint{url: c.Address(), client: c, healthy: true})
+	}
+
+	return &Client{
+		chainID:   chainID,
+		cfg:       cfg,
+		publisher: publisher,
+		endpoints: endpoints,
+		stop:      make(chan struct{}),
+	}
+}
+
+// Active returns the URL of the endpoint the calls currently go to.
+func (c *Client) Active() string {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if len(c.endpoints) == 0 {
+		return ""
+	}
+	return c.endpoints[c.active].url
+}
+
+// GetBeneficiary looks up beneficiary of the identity in the given registry.
+func (c *Client) GetBeneficiary(registryAddress, identity common.Address) (common.Address, error) {
+	caller, err := bindings.NewRegistryCaller(registryAddress, c)
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	return caller.GetBeneficiary(&bind.CallOpts{Context: context.Background()}, identity)
+}
+
+// CodeAt returns the code of the given account.
+func (c *Client) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) (code []byte, err error) {
+	err = c.do(ctx, func(ctx context.Context, ec paymentClient.EtherClient) error {
+		code, err = ec.CodeAt(ctx, contract, blockNumber)
+		return err
+	})
+	return code, err
+}
+
+// CallContract executes an Ethereum contract call with the specified data as the input.
+func (c *Client) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) (result []byte, err error) {
+	err = c.do(ctx, func(ctx context.Context, ec paymentClient.EtherClient) error {
+		result, err = ec.CallContract(ctx, call, blockNumber)
+		return err
+	})
+	return result, err
+}
+
+// Start starts health checks of the endpoints.
+func (c *Client) Start() {
+	if c.cfg.HealthCheckInterval <= 0 || len(c.endpoints) < 2 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(c.cfg.HealthCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-c.stop:
+				return
+			case <-ticker.C:
+				c.checkHealth()
+			}
+		}
+	}()
+}
+
+// Stop stops health checks of the endpoints.
+func (c *Client) Stop() {
+	c.stopOnce.Do(func() {
+		close(c.stop)
+	})
+}
+
+func (c *Client) do(parent context.Context, call func(ctx context.Context, ec paymentClient.EtherClient) error) error {
+	if len(c.endpoints) == 0 {
+		return ErrNoEndpoints
+	}
+
+	var err error
+	for range c.endpoints {
+		i, ep := c.current()
+
+		ctx, cancel := context.WithTimeout(parent, c.cfg.CallTimeout)
+		err = call(ctx, ep.client.Client())
+		cancel()
+
+		if !isTimeout(err) || parent.Err() != nil {
+			return err
+		}
+		log.Warn().Err(err).Int64("chain", c.chainID).Msgf("RPC endpoint %s timed out", ep.url)
+		c.failover(i)
+	}
+	return err
+}
+
+func (c *Client) current() (int, *endpoint) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.active, c.endpoints[c.active]
+}
+
+// failover marks the endpoint as unhealthy and moves calls to the next healthy one.
+func (c *Client) failover(failed int) {
+	c.lock.Lock()
+	c.endpoints[failed].healthy = false
+	if c.active != failed || len(c.endpoints) < 2 {
+		// Another call has already moved away from it.
+		c.lock.Unlock()
+		return
+	}
+
+	next := (failed + 1) % len(c.endpoints)
+	for i := 1; i < len(c.endpoints); i++ {
+		candidate := (failed + i) % len(c.endpoints)
+		if c.endpoints[candidate].healthy {
+			next = candidate
+			break
+		}
+	}
+	ev := c.switchTo(next, ReasonTimeout)
+	c.lock.Unlock()
+
+	c.publish(ev)
+}
+
+func (c *Client) checkHealth() {
+	c.lock.Lock()
+	endpoints := append([]*endpoint(nil), c.endpoints...)
+	c.lock.Unlock()
+
+	healthy := make([]bool, len(endpoints))
+	for i, ep := range endpoints {
+		ctx, cancel := context.WithTimeout(context.Background(), c.cfg.CallTimeout)
+		_, err := ep.client.Client().BlockNumber(ctx)
+		cancel()
+
+		healthy[i] = err == nil
+		if err != nil {
+			log.Debug().Err(err).Int64("chain", c.chainID).Msgf("RPC endpoint %s failed health check", ep.url)
+		}
+	}
+
+	c.lock.Lock()
+	preferred := -1
+	for i, ep := range c.endpoints {
+		ep.healthy = healthy[i]
+		if ep.healthy && preferred < 0 {
+			preferred = i
+		}
+	}
+
+	var ev *AppEventEndpointSwitched
+	if preferred >= 0 && preferred != c.active {
+		reason := ReasonUnhealthy
+		if c.endpoints[c.active].healthy {
+			reason = ReasonRecovered
+		}
+		ev = c.switchTo(preferred, reason)
+	}
+	c.lock.Unlock()
+
+	c.publish(ev)
+}
+
+// switchTo must be called with the lock held.
+func (c *Client) switchTo(next int, reason string) *AppEventEndpointSwitched {
+	ev := &AppEventEndpointSwitched{
+		ChainID:  c.chainID,
+		Previous: c.endpoints[c.active].url,
+		Current:  c.endpoints[next].url,
+		Reason:   reason,
+	}
+	c.active = next
+
+	return ev
+}
+
+func (c *Client) publish(ev *AppEventEndpointSwitched) {
+	if ev == nil {
+		return
+	}
+
+	log.Info().Int64("chain", ev.ChainID).Str("reason", ev.Reason).Msgf("Switched RPC endpoint from %s to %s", ev.Previous, ev.Current)
+	if c.publisher != nil {
+		c.publisher.Publish(AppTopicEndpointSwitched, *ev)
+	}
+}
+
+func isTimeout(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
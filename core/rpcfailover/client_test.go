@@ -0,0 +1,175 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package rpcfailover
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	paymentClient "github.com/mysteriumnetwork/payments/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mysteriumnetwork/node/mocks"
+)
+
+var (
+	registryAddress = common.HexToAddress("0x1")
+	identityAddress = common.HexToAddress("0x2")
+	beneficiary     = common.HexToAddress("0x3")
+)
+
+type fakeEndpoint struct {
+	paymentClient.EtherClient
+
+	lock    sync.Mutex
+	address string
+	err     error
+	calls   int
+}
+
+func (f *fakeEndpoint) Client() paymentClient.EtherClient { return f }
+func (f *fakeEndpoint) Address() string                   { return f.address }
+
+func (f *fakeEndpoint) setErr(err error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.err = err
+}
+
+func (f *fakeEndpoint) callCount() int {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return f.calls
+}
+
+func (f *fakeEndpoint) CallContract(_ context.Context, _ ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	return common.LeftPadBytes(beneficiary.Bytes(), 32), nil
+}
+
+func (f *fakeEndpoint) BlockNumber(_ context.Context) (uint64, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return 1, f.err
+}
+
+func newTestClient(bus *mocks.EventBus, endpoints ...*fakeEndpoint) *Client {
+	clients := make([]paymentClient.AddressableEthClientGetter, 0, len(endpoints))
+	for _, ep := range endpoints {
+		clients = append(clients, ep)
+	}
+	return NewClient(1, clients, bus, Config{CallTimeout: time.Second})
+}
+
+func TestClient_FailsOverOnTimeout(t *testing.T) {
+	primary := &fakeEndpoint{address: "primary", err: context.DeadlineExceeded}
+	secondary := &fakeEndpoint{address: "secondary"}
+	bus := mocks.NewEventBus()
+	client := newTestClient(bus, primary, secondary)
+
+	got, err := client.GetBeneficiary(registryAddress, identityAddress)
+	require.NoError(t, err)
+	assert.Equal(t, beneficiary, got)
+	assert.Equal(t, "secondary", client.Active())
+	assert.Equal(t, AppEventEndpointSwitched{
+		ChainID:  1,
+		Previous: "primary",
+		Current:  "secondary",
+		Reason:   ReasonTimeout,
+	}, bus.Pop())
+
+	// Further calls stay on the endpoint that answered.
+	_, err = client.GetBeneficiary(registryAddress, identityAddress)
+	require.NoError(t, err)
+	assert.Equal(t, 1, primary.callCount())
+	assert.Equal(t, 2, secondary.callCount())
+}
+
+func TestClient_DoesNotFailOverOnOtherErrors(t *testing.T) {
+	reverted := errors.New("execution reverted")
+	primary := &fakeEndpoint{address: "primary", err: reverted}
+	secondary := &fakeEndpoint{address: "secondary"}
+	bus := mocks.NewEventBus()
+	client := newTestClient(bus, primary, secondary)
+
+	_, err := client.GetBeneficiary(registryAddress, identityAddress)
+	assert.ErrorIs(t, err, reverted)
+	assert.Equal(t, "primary", client.Active())
+	assert.Equal(t, 0, secondary.callCount())
+	assert.Nil(t, bus.Pop())
+}
+
+func TestClient_ReturnsErrorWhenAllEndpointsTimeOut(t *testing.T) {
+	primary := &fakeEndpoint{address: "primary", err: context.DeadlineExceeded}
+	secondary := &fakeEndpoint{address: "secondary", err: context.DeadlineExceeded}
+	client := newTestClient(mocks.NewEventBus(), primary, secondary)
+
+	_, err := client.GetBeneficiary(registryAddress, identityAddress)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 1, primary.callCount())
+	assert.Equal(t, 1, secondary.callCount())
+}
+
+func TestClient_HealthCheckFollowsPreferredHealthyEndpoint(t *testing.T) {
+	primary := &fakeEndpoint{address: "primary", err: context.DeadlineExceeded}
+	secondary := &fakeEndpoint{address: "secondary"}
+	bus := mocks.NewEventBus()
+	client := newTestClient(bus, primary, secondary)
+
+	client.checkHealth()
+	assert.Equal(t, "secondary", client.Active())
+	assert.Equal(t, AppEventEndpointSwitched{
+		ChainID:  1,
+		Previous: "primary",
+		Current:  "secondary",
+		Reason:   ReasonUnhealthy,
+	}, bus.Pop())
+
+	primary.setErr(nil)
+	client.checkHealth()
+	assert.Equal(t, "primary", client.Active())
+	assert.Equal(t, AppEventEndpointSwitched{
+		ChainID:  1,
+		Previous: "secondary",
+		Current:  "primary",
+		Reason:   ReasonRecovered,
+	}, bus.Pop())
+}
+
+func TestMultichainClient_UnknownChain(t *testing.T) {
+	client := NewMultichainClient(map[int64]*Client{1: newTestClient(mocks.NewEventBus(), &fakeEndpoint{address: "primary"})})
+
+	_, err := client.GetBeneficiary(2, registryAddress, identityAddress)
+	assert.Error(t, err)
+
+	got, err := client.GetBeneficiary(1, registryAddress, identityAddress)
+	require.NoError(t, err)
+	assert.Equal(t, beneficiary, got)
+}
@@ -0,0 +1,76 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package rpcfailover
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// MultichainClient routes calls to the failover client of the requested chain.
+type MultichainClient struct {
+	clients map[int64]*Client
+}
+
+// NewMultichainClient creates new multichain failover client.
+func NewMultichainClient(clients map[int64]*Client) *MultichainClient {
+	return &MultichainClient{clients: clients}
+}
+
+// Chain returns the failover client of the given chain.
+func (m *MultichainClient) Chain(chainID int64) (*Client, error) {
+	c, ok := m.clients[chainID]
+	if !ok {
+		return nil, fmt.Errorf("no rpc endpoints for chain %d", chainID)
+	}
+	return c, nil
+}
+
+// GetBeneficiary looks up beneficiary of the identity in the given registry of the chain.
+func (m *MultichainClient) GetBeneficiary(chainID int64, registryAddress, identity common.Address) (common.Address, error) {
+	c, err := m.Chain(chainID)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return c.GetBeneficiary(registryAddress, identity)
+}
+
+// CodeAt returns the latest code of the given account on the chain.
+func (m *MultichainClient) CodeAt(ctx context.Context, chainID int64, account common.Address) ([]byte, error) {
+	c, err := m.Chain(chainID)
+	if err != nil {
+		return nil, err
+	}
+	return c.CodeAt(ctx, account, nil)
+}
+
+// Start starts health checks of the endpoints of all chains.
+func (m *MultichainClient) Start() {
+	for _, c := range m.clients {
+		c.Start()
+	}
+}
+
+// Stop stops health checks of the endpoints of all chains.
+func (m *MultichainClient) Stop() {
+	for _, c := range m.clients {
+		c.Stop()
+	}
+}
@@ -0,0 +1,80 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package capacity
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+type procStatMeter struct {
+	idle, total uint64
+}
+
+func newCPUMeter() cpuMeter {
+	return &procStatMeter{}
+}
+
+// Usage reads aggregated CPU times from /proc/stat.
+func (m *procStatMeter) Usage() (float64, error) {
+	f, err := os.Open("/proc/stat")
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	line, err := bufio.NewReader(f).ReadString('\n')
+	if err != nil {
+		return 0, fmt.Errorf("could not read /proc/stat: %w", err)
+	}
+
+	idle, total, err := parseCPULine(line)
+	if err != nil {
+		return 0, err
+	}
+
+	idleDelta, totalDelta := idle-m.idle, total-m.total
+	m.idle, m.total = idle, total
+	if totalDelta == 0 {
+		return 0, nil
+	}
+	return 100 * float64(totalDelta-idleDelta) / float64(totalDelta), nil
+}
+
+func parseCPULine(line string) (idle, total uint64, err error) {
+	fields := strings.Fields(line)
+	if len(fields) < 5 || fields[0] != "cpu" {
+		return 0, 0, fmt.Errorf("unexpected /proc/stat format: %q", line)
+	}
+
+	for i, field := range fields[1:] {
+		v, err := strconv.ParseUint(field, 10, 64)
+		if err != nil {
+			return 0, 0, fmt.Errorf("could not parse /proc/stat: %w", err)
+		}
+		total += v
+		// idle and iowait
+		if i == 3 || i == 4 {
+			idle += v
+		}
+	}
+	return idle, total, nil
+}
@@ -0,0 +1,239 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package capacity
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/eventbus"
+	"github.com/mysteriumnetwork/node/session/event"
+)
+
+// resumeRatio of the threshold the load has to drop below for proposals to be resumed.
+const resumeRatio = 0.8
+
+// Config configures the capacity monitor.
+type Config struct {
+	// BandwidthThreshold in Mbit/s, 0 disables the bandwidth check.
+	BandwidthThreshold uint64
+	// CPUThreshold in percent, 0 disables the CPU check.
+	CPUThreshold float64
+	// Sustain is the period the load has to stay above or below thresholds before acting.
+	Sustain time.Duration
+	// Interval between load samples.
+	Interval time.Duration
+}
+
+// Enabled returns true if at least one threshold is configured.
+func (c Config) Enabled() bool {
+	return c.BandwidthThreshold > 0 || c.CPUThreshold > 0
+}
+
+type proposalPauser interface {
+	PauseProposals(reason string)
+	ResumeProposals()
+}
+
+// Monitor pauses publishing of new proposals while provider utilization stays
+// above configured thresholds and resumes it when the load drops.
+type Monitor struct {
+	services proposalPauser
+	cfg      Config
+	cpu      cpuMeter
+	now      func() time.Time
+
+	lock        sync.Mutex
+	transferred map[string]uint64
+	bytes       uint64
+
+	paused    bool
+	overSince time.Time
+	okSince   time.Time
+	lastAt    time.Time
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewMonitor creates a new capacity monitor.
+func NewMonitor(services proposalPauser, cfg Config) *Monitor {
+	return &Monitor{
+		services:    services,
+		cfg:         cfg,
+		cpu:         newCPUMeter(),
+		now:         time.Now,
+		transferred: make(map[string]uint64),
+		stop:        make(chan struct{}),
+	}
+}
+
+// Subscribe subscribes to provider session traffic events.
+func (m *Monitor) Subscribe(bus eventbus.Subscriber) error {
+	if err := bus.SubscribeAsync(event.AppTopicDataTransferredBatch, m.handleDataTransferredBatch); err != nil {
+		return err
+	}
+	return bus.SubscribeAsync(event.AppTopicSession, m.handleSession)
+}
+
+func (m *Monitor) handleDataTransferredBatch(e event.AppEventDataTransferredBatch) {
+	for _, transferred := range e.Sessions {
+		m.handleDataTransferred(transferred)
+	}
+}
+
+func (m *Monitor) handleDataTransferred(e event.AppEventDataTransferred) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	total := e.Up + e.Down
+	last := m.transferred[e.ID]
+	if total >= last {
+		m.bytes += total - last
+	} else {
+		m.bytes += total
+	}
+	m.transferred[e.ID] = total
+}
+
+func (m *Monitor) handleSession(e event.AppEventSession) {
+	if e.Status != event.RemovedStatus {
+		return
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	delete(m.transferred, e.Session.ID)
+}
+
+// Start samples the load periodically until stopped.
+func (m *Monitor) Start() {
+	m.lock.Lock()
+	m.lastAt = m.now()
+	m.lock.Unlock()
+
+	// The first measurement only sets the baseline of CPU usage.
+	if m.cfg.CPUThreshold > 0 {
+		if _, err := m.cpu.Usage(); err != nil {
+			log.Warn().Err(err).Msg("CPU usage is not available, only bandwidth threshold is enforced")
+			m.cfg.CPUThreshold = 0
+		}
+	}
+
+	go func() {
+		ticker := time.NewTicker(m.cfg.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-m.stop:
+				return
+			case <-ticker.C:
+				m.check()
+			}
+		}
+	}()
+}
+
+// Stop stops the monitor.
+func (m *Monitor) Stop() {
+	m.stopOnce.Do(func() {
+		close(m.stop)
+	})
+}
+
+func (m *Monitor) check() {
+	bandwidth := m.bandwidth()
+
+	var cpu float64
+	if m.cfg.CPUThreshold > 0 {
+		usage, err := m.cpu.Usage()
+		if err != nil {
+			log.Debug().Err(err).Msg("Could not measure CPU usage")
+		} else {
+			cpu = usage
+		}
+	}
+
+	m.evaluate(bandwidth, cpu)
+}
+
+// bandwidth returns provider throughput in Mbit/s since the previous sample.
+func (m *Monitor) bandwidth() float64 {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	now := m.now()
+	elapsed := now.Sub(m.lastAt).Seconds()
+	bytes := m.bytes
+	m.bytes, m.lastAt = 0, now
+
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(bytes) * 8 / elapsed / 1_000_000
+}
+
+func (m *Monitor) evaluate(bandwidth, cpu float64) {
+	now := m.now()
+
+	var reason string
+	okBelow := true
+	if limit := float64(m.cfg.BandwidthThreshold); limit > 0 {
+		if bandwidth > limit {
+			reason = fmt.Sprintf("bandwidth %.1f Mbit/s above %.0f Mbit/s threshold", bandwidth, limit)
+		}
+		okBelow = okBelow && bandwidth < limit*resumeRatio
+	}
+	if limit := m.cfg.CPUThreshold; limit > 0 {
+		if cpu > limit && reason == "" {
+			reason = fmt.Sprintf("CPU usage %.0f%% above %.0f%% threshold", cpu, limit)
+		}
+		okBelow = okBelow && cpu < limit*resumeRatio
+	}
+
+	if reason != "" {
+		m.okSince = time.Time{}
+		if m.overSince.IsZero() {
+			m.overSince = now
+		}
+		if !m.paused && now.Sub(m.overSince) >= m.cfg.Sustain {
+			log.Warn().Msgf("Provider is overloaded (%s), pausing new proposals", reason)
+			m.paused = true
+			m.services.PauseProposals(reason)
+		}
+		return
+	}
+
+	m.overSince = time.Time{}
+	if !okBelow {
+		m.okSince = time.Time{}
+		return
+	}
+	if m.okSince.IsZero() {
+		m.okSince = now
+	}
+	if m.paused && now.Sub(m.okSince) >= m.cfg.Sustain {
+		log.Info().Msg("Provider load dropped, resuming proposals")
+		m.paused = false
+		m.services.ResumeProposals()
+	}
+}
@@ -0,0 +1,138 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package capacity
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/session/event"
+)
+
+type mockPauser struct {
+	reason  string
+	paused  int
+	resumed int
+}
+
+func (m *mockPauser) PauseProposals(reason string) {
+	m.reason = reason
+	m.paused++
+}
+
+func (m *mockPauser) ResumeProposals() {
+	m.resumed++
+}
+
+type failingCPUMeter struct {
+	calls int
+}
+
+func (m *failingCPUMeter) Usage() (float64, error) {
+	m.calls++
+	return 0, errors.New("not supported")
+}
+
+func newTestMonitor(cfg Config) (*Monitor, *mockPauser, *time.Time) {
+	pauser := &mockPauser{}
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	m := NewMonitor(pauser, cfg)
+	m.now = func() time.Time { return now }
+	m.lastAt = now
+	return m, pauser, &now
+}
+
+func TestMonitor_PausesAndResumesOnSustainedBandwidth(t *testing.T) {
+	m, pauser, now := newTestMonitor(Config{BandwidthThreshold: 100, Sustain: time.Minute})
+
+	m.evaluate(150, 0)
+	assert.Equal(t, 0, pauser.paused, "should wait for sustained load")
+
+	*now = now.Add(30 * time.Second)
+	m.evaluate(90, 0)
+	*now = now.Add(30 * time.Second)
+	m.evaluate(150, 0)
+	assert.Equal(t, 0, pauser.paused, "load spike should not pause")
+
+	*now = now.Add(time.Minute)
+	m.evaluate(120, 0)
+	assert.Equal(t, 1, pauser.paused)
+	assert.Contains(t, pauser.reason, "bandwidth")
+
+	*now = now.Add(2 * time.Minute)
+	m.evaluate(90, 0)
+	*now = now.Add(2 * time.Minute)
+	m.evaluate(90, 0)
+	assert.Equal(t, 0, pauser.resumed, "load has to drop below resume ratio")
+
+	m.evaluate(50, 0)
+	*now = now.Add(time.Minute)
+	m.evaluate(50, 0)
+	assert.Equal(t, 1, pauser.resumed)
+	assert.Equal(t, 1, pauser.paused)
+}
+
+func TestMonitor_CPUThreshold(t *testing.T) {
+	m, pauser, now := newTestMonitor(Config{CPUThreshold: 80, Sustain: 0})
+
+	m.evaluate(1000, 50)
+	assert.Equal(t, 0, pauser.paused, "bandwidth check is disabled")
+
+	m.evaluate(0, 95)
+	assert.Equal(t, 1, pauser.paused)
+	assert.Contains(t, pauser.reason, "CPU")
+
+	*now = now.Add(time.Second)
+	m.evaluate(0, 10)
+	assert.Equal(t, 1, pauser.resumed)
+}
+
+func TestMonitor_Bandwidth(t *testing.T) {
+	m, _, now := newTestMonitor(Config{BandwidthThreshold: 1})
+
+	m.handleDataTransferred(event.AppEventDataTransferred{ID: "s1", Up: 1_000_000, Down: 1_000_000})
+	m.handleDataTransferred(event.AppEventDataTransferred{ID: "s1", Up: 2_000_000, Down: 2_000_000})
+	m.handleDataTransferred(event.AppEventDataTransferred{ID: "s2", Up: 500_000, Down: 500_000})
+
+	*now = now.Add(8 * time.Second)
+	assert.Equal(t, 5.0, m.bandwidth())
+
+	m.handleSession(event.AppEventSession{Status: event.RemovedStatus, Session: event.SessionContext{ID: "s1"}})
+	m.handleDataTransferred(event.AppEventDataTransferred{ID: "s2", Up: 1_000_000, Down: 1_000_000})
+
+	*now = now.Add(8 * time.Second)
+	assert.Equal(t, 1.0, m.bandwidth())
+	assert.NotContains(t, m.transferred, "s1")
+}
+
+func TestMonitor_StartIgnoresUnavailableCPU(t *testing.T) {
+	m, pauser, _ := newTestMonitor(Config{CPUThreshold: 80, Interval: time.Hour})
+	cpu := &failingCPUMeter{}
+	m.cpu = cpu
+
+	m.Start()
+	defer m.Stop()
+	assert.Equal(t, 1, cpu.calls)
+
+	m.check()
+	assert.Equal(t, 1, cpu.calls, "CPU should not be measured after it failed")
+	assert.Equal(t, 0, pauser.paused)
+}
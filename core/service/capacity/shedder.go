@@ -0,0 +1,159 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package capacity
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/p2p"
+)
+
+// ShedConfig configures rejection of new sessions while the provider is overloaded.
+type ShedConfig struct {
+	// MaxSessions is the number of active sessions at which new ones are rejected, 0 disables the check.
+	MaxSessions int
+	// CPUThreshold in percent, 0 disables the CPU check.
+	CPUThreshold float64
+	// MemoryThreshold in percent of host memory in use, 0 disables the memory check.
+	MemoryThreshold float64
+	// RetryAfter is how long rejected consumers are asked to wait before trying the provider again.
+	RetryAfter time.Duration
+	// Interval between CPU and memory samples.
+	Interval time.Duration
+}
+
+// Enabled returns true if at least one limit is configured.
+func (c ShedConfig) Enabled() bool {
+	return c.MaxSessions > 0 || c.CPUThreshold > 0 || c.MemoryThreshold > 0
+}
+
+// Shedder rejects new sessions while the provider is overloaded, so that consumers
+// try other providers instead of waiting for session creation to time out.
+// CPU and memory are sampled periodically, active sessions are counted on every request.
+type Shedder struct {
+	cpu    cpuMeter
+	memory memoryMeter
+
+	lock   sync.Mutex
+	cfg    ShedConfig
+	cpuPct float64
+	memPct float64
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewShedder creates a new session load shedder.
+func NewShedder(cfg ShedConfig) *Shedder {
+	return &Shedder{
+		cfg:    cfg,
+		cpu:    newCPUMeter(),
+		memory: newMemoryMeter(),
+		stop:   make(chan struct{}),
+	}
+}
+
+// Start samples CPU and memory usage periodically until stopped.
+func (s *Shedder) Start() {
+	s.lock.Lock()
+	// The first measurement only sets the baseline of CPU usage.
+	if s.cfg.CPUThreshold > 0 {
+		if _, err := s.cpu.Usage(); err != nil {
+			log.Warn().Err(err).Msg("CPU usage is not available, sessions are not rejected on CPU load")
+			s.cfg.CPUThreshold = 0
+		}
+	}
+	if s.cfg.MemoryThreshold > 0 {
+		if _, err := s.memory.Usage(); err != nil {
+			log.Warn().Err(err).Msg("Memory usage is not available, sessions are not rejected on memory load")
+			s.cfg.MemoryThreshold = 0
+		}
+	}
+	sampled := s.cfg.CPUThreshold > 0 || s.cfg.MemoryThreshold > 0
+	s.lock.Unlock()
+
+	if !sampled {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(s.cfg.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+				s.sample()
+			}
+		}
+	}()
+}
+
+// Stop stops sampling.
+func (s *Shedder) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stop)
+	})
+}
+
+func (s *Shedder) sample() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.cfg.CPUThreshold > 0 {
+		if usage, err := s.cpu.Usage(); err != nil {
+			log.Debug().Err(err).Msg("Could not measure CPU usage")
+		} else {
+			s.cpuPct = usage
+		}
+	}
+	if s.cfg.MemoryThreshold > 0 {
+		if usage, err := s.memory.Usage(); err != nil {
+			log.Debug().Err(err).Msg("Could not measure memory usage")
+		} else {
+			s.memPct = usage
+		}
+	}
+}
+
+// Admit returns p2p.BusyError if the provider is too loaded to start another session,
+// active is the number of currently running sessions.
+func (s *Shedder) Admit(active int) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var reason string
+	switch {
+	case s.cfg.MaxSessions > 0 && active >= s.cfg.MaxSessions:
+		reason = fmt.Sprintf("%d active sessions, limit is %d", active, s.cfg.MaxSessions)
+	case s.cfg.CPUThreshold > 0 && s.cpuPct > s.cfg.CPUThreshold:
+		reason = fmt.Sprintf("CPU usage %.0f%% above %.0f%% threshold", s.cpuPct, s.cfg.CPUThreshold)
+	case s.cfg.MemoryThreshold > 0 && s.memPct > s.cfg.MemoryThreshold:
+		reason = fmt.Sprintf("memory usage %.0f%% above %.0f%% threshold", s.memPct, s.cfg.MemoryThreshold)
+	default:
+		return nil
+	}
+
+	return &p2p.BusyError{Reason: reason, RetryAfter: s.cfg.RetryAfter}
+}
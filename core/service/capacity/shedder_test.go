@@ -0,0 +1,79 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package capacity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mysteriumnetwork/node/p2p"
+)
+
+type fixedMeter struct {
+	usage float64
+}
+
+func (m *fixedMeter) Usage() (float64, error) {
+	return m.usage, nil
+}
+
+func TestShedder_Admit(t *testing.T) {
+	s := NewShedder(ShedConfig{MaxSessions: 2, CPUThreshold: 80, MemoryThreshold: 90, RetryAfter: time.Minute, Interval: time.Hour})
+	cpu, memory := &fixedMeter{usage: 10}, &fixedMeter{usage: 10}
+	s.cpu, s.memory = cpu, memory
+	s.sample()
+
+	assert.NoError(t, s.Admit(1))
+
+	err := s.Admit(2)
+	var busy *p2p.BusyError
+	require.ErrorAs(t, err, &busy)
+	assert.Contains(t, busy.Reason, "sessions")
+	assert.Equal(t, time.Minute, busy.RetryAfter)
+
+	cpu.usage = 95
+	s.sample()
+	require.ErrorAs(t, s.Admit(0), &busy)
+	assert.Contains(t, busy.Reason, "CPU")
+
+	cpu.usage, memory.usage = 10, 95
+	s.sample()
+	require.ErrorAs(t, s.Admit(0), &busy)
+	assert.Contains(t, busy.Reason, "memory")
+
+	memory.usage = 50
+	s.sample()
+	assert.NoError(t, s.Admit(0))
+}
+
+func TestShedder_StartIgnoresUnavailableMeters(t *testing.T) {
+	s := NewShedder(ShedConfig{MaxSessions: 1, CPUThreshold: 80, MemoryThreshold: 80, Interval: time.Hour})
+	cpu := &failingCPUMeter{}
+	s.cpu, s.memory = cpu, cpu
+
+	s.Start()
+	defer s.Stop()
+	assert.Equal(t, 2, cpu.calls)
+
+	s.sample()
+	assert.Equal(t, 2, cpu.calls, "usage should not be measured after it failed")
+	assert.NoError(t, s.Admit(0))
+}
@@ -19,6 +19,7 @@ package service
 
 import (
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/gofrs/uuid"
@@ -76,6 +77,11 @@ type locationResolver interface {
 	DetectLocation() (locationstate.Location, error)
 }
 
+// complianceGate decides whether the service may be started in the provider's jurisdiction.
+type complianceGate interface {
+	CheckService(serviceType, country string) error
+}
+
 // WaitForNATHole blocks until NAT hole is punched towards consumer through local NAT or until hole punching failed
 type WaitForNATHole func() error
 
@@ -90,6 +96,12 @@ func NewManager(
 	sessionManager func(service *Instance, channel p2p.Channel) *SessionManager,
 	statusStorage connectivity.StatusStorage,
 	location locationResolver,
+	templates TemplateSource,
+	sla *market.SLA,
+	metadata *market.ProposalMetadata,
+	compliance complianceGate,
+	maintenance MaintenanceSchedule,
+	reservations ReservationBook,
 ) *Manager {
 	return &Manager{
 		serviceRegistry:  serviceRegistry,
@@ -102,6 +114,12 @@ func NewManager(
 		sessionManager:   sessionManager,
 		statusStorage:    statusStorage,
 		location:         location,
+		templates:        templates,
+		sla:              sla,
+		metadata:         metadata,
+		compliance:       compliance,
+		maintenance:      maintenance,
+		reservations:     reservations,
 	}
 }
 
@@ -119,6 +137,16 @@ type Manager struct {
 	sessionManager func(service *Instance, channel p2p.Channel) *SessionManager
 	statusStorage  connectivity.StatusStorage
 	location       locationResolver
+	templates      TemplateSource
+	sla            *market.SLA
+	metadata       *market.ProposalMetadata
+	compliance     complianceGate
+	maintenance    MaintenanceSchedule
+	reservations   ReservationBook
+
+	pauseOpLock sync.Mutex
+	pauseLock   sync.Mutex
+	pauseReason string
 }
 
 // Start starts an instance of the given service type if knows one in service registry.
@@ -131,6 +159,23 @@ func (manager *Manager) Start(providerID identity.Identity, serviceType string,
 		"policyIDs":   policyIDs,
 		"options":     options,
 	}).Msg("Starting service")
+
+	var template *Template
+	if manager.templates != nil {
+		template, err = manager.templates.Template(serviceType)
+		if err != nil {
+			return id, fmt.Errorf("could not apply service template: %w", err)
+		}
+		if template != nil {
+			if len(policyIDs) == 0 {
+				policyIDs = template.AccessPolicies
+			}
+			if options == nil {
+				options = template.Options
+			}
+		}
+	}
+
 	service, err := manager.serviceRegistry.Create(serviceType, options)
 	if err != nil {
 		return id, err
@@ -156,10 +201,18 @@ func (manager *Manager) Start(providerID identity.Identity, serviceType string,
 		return "", err
 	}
 
+	if manager.compliance != nil {
+		if err := manager.compliance.CheckService(serviceType, location.Country); err != nil {
+			return "", err
+		}
+	}
+
 	proposal := market.NewProposal(providerID.Address, serviceType, market.NewProposalOpts{
 		Location:       market.NewLocation(location),
 		AccessPolicies: accessPolicies,
 		Contacts:       []market.Contact{manager.p2pListener.GetContact()},
+		SLA:            manager.sla,
+		Metadata:       manager.metadata,
 	})
 
 	discovery := manager.discoveryFactory()
@@ -181,9 +234,13 @@ func (manager *Manager) Start(providerID identity.Identity, serviceType string,
 		discovery:      discovery,
 		eventPublisher: manager.eventPublisher,
 		location:       manager.location,
+		maintenance:    manager.maintenance,
+		reservations:   manager.reservations,
+	}
+	if template != nil {
+		instance.TemplateVersion = template.Version
+		instance.Shaping = template.Shaping
 	}
-
-	discovery.Start(providerID, instance.proposalWithCurrentLocation)
 
 	channelHandlers := func(ch p2p.Channel) {
 		chID := "channel:" + ch.ID()
@@ -198,14 +255,32 @@ func (manager *Manager) Start(providerID identity.Identity, serviceType string,
 		subscribeSessionStatus(ch, manager.statusStorage)
 		subscribeSessionAcknowledge(mng, ch)
 		subscribeSessionDestroy(mng, ch)
+		subscribeSessionReconcile(mng, ch)
+		subscribeSessionKeyRotate(mng, ch)
+		subscribeSessionKeyRotateAck(mng, ch)
+		subscribeSessionPause(mng, ch)
+		subscribeSessionRoam(mng, ch)
+		subscribeSessionSLABreach(mng, ch)
 		subscribeSessionPayments(mng, ch)
+		if manager.reservations != nil {
+			subscribeSessionReserve(mng, ch)
+		}
 	}
 	stopP2PListener, err := manager.p2pListener.Listen(providerID, serviceType, channelHandlers)
 	if err != nil {
 		return id, fmt.Errorf("could not subscribe to p2p channels: %w", err)
 	}
 
+	// Instance is added under the pause lock, so that concurrent pausing does not miss it.
+	manager.pauseLock.Lock()
+	if manager.pauseReason != "" {
+		instance.pauseReason = manager.pauseReason
+		instance.discovery = nil
+	} else {
+		discovery.Start(providerID, instance.proposalWithCurrentLocation)
+	}
 	manager.servicePool.Add(instance)
+	manager.pauseLock.Unlock()
 
 	go func() {
 		instance.setState(servicestate.Running)
@@ -222,7 +297,7 @@ func (manager *Manager) Start(providerID identity.Identity, serviceType string,
 			log.Error().Err(stopErr).Msg("Service stop failed")
 		}
 
-		discovery.Wait()
+		instance.waitDiscovery()
 	}()
 
 	netutil.LogNetworkStats()
@@ -272,6 +347,48 @@ func (manager *Manager) List(includeAll bool) []*Instance {
 	return result
 }
 
+// PauseProposals stops announcing proposals of all services, including the ones started later.
+// Running services and their sessions are not affected.
+func (manager *Manager) PauseProposals(reason string) {
+	manager.pauseOpLock.Lock()
+	defer manager.pauseOpLock.Unlock()
+
+	// Services are started concurrently, so the pause lock is not held while waiting for unregistration.
+	for _, instance := range manager.setPauseReason(reason) {
+		instance.pauseDiscovery(reason)
+	}
+}
+
+// ResumeProposals starts announcing proposals of all services again.
+func (manager *Manager) ResumeProposals() {
+	manager.pauseOpLock.Lock()
+	defer manager.pauseOpLock.Unlock()
+
+	for _, instance := range manager.setPauseReason("") {
+		instance.resumeDiscovery(manager.discoveryFactory)
+	}
+}
+
+// RepublishProposals unregisters proposals of the running services and announces them again,
+// e.g. after the active chain has changed. Paused proposals stay paused.
+func (manager *Manager) RepublishProposals() {
+	manager.pauseOpLock.Lock()
+	defer manager.pauseOpLock.Unlock()
+
+	for _, instance := range manager.servicePool.List() {
+		instance.restartDiscovery(manager.discoveryFactory)
+	}
+}
+
+// setPauseReason sets the pause reason applied to services started later and returns the running ones.
+func (manager *Manager) setPauseReason(reason string) []*Instance {
+	manager.pauseLock.Lock()
+	defer manager.pauseLock.Unlock()
+
+	manager.pauseReason = reason
+	return manager.servicePool.List()
+}
+
 // Kill stops all services.
 func (manager *Manager) Kill() error {
 	return manager.servicePool.StopAll()
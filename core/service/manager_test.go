@@ -19,6 +19,7 @@ package service
 
 import (
 	"errors"
+	"sync"
 	"testing"
 	"time"
 
@@ -27,6 +28,7 @@ import (
 
 	"github.com/mysteriumnetwork/node/core/location/locationstate"
 	"github.com/mysteriumnetwork/node/core/service/servicestate"
+	"github.com/mysteriumnetwork/node/core/shaper"
 	"github.com/mysteriumnetwork/node/identity"
 	"github.com/mysteriumnetwork/node/market"
 	"github.com/mysteriumnetwork/node/mocks"
@@ -62,7 +64,7 @@ func TestManager_StartRemovesServiceFromPoolIfServiceCrashes(t *testing.T) {
 		mocks.NewEventBus(),
 		mockPolicyOracle,
 		mockPolicyProvider,
-		&mockP2PListener{}, nil, nil, mockLocationResolver{},
+		&mockP2PListener{}, nil, nil, mockLocationResolver{}, nil, nil, nil, nil, nil, nil,
 	)
 	_, err := manager.Start(identity.FromAddress(proposalMock.ProviderID), serviceType, nil, struct{}{})
 	assert.Nil(t, err)
@@ -88,7 +90,7 @@ func TestManager_StartDoesNotCrashIfStoppedByUser(t *testing.T) {
 		mockPolicyOracle,
 		mockPolicyProvider,
 		&mockP2PListener{}, nil, nil,
-		mockLocationResolver{},
+		mockLocationResolver{}, nil, nil, nil, nil, nil, nil,
 	)
 	id, err := manager.Start(identity.FromAddress(proposalMock.ProviderID), serviceType, nil, struct{}{})
 	assert.Nil(t, err)
@@ -116,7 +118,7 @@ func TestManager_StopSendsEvent_SucceedsAndPublishesEvent(t *testing.T) {
 		mockPolicyOracle,
 		mockPolicyProvider,
 		&mockP2PListener{}, nil, nil,
-		mockLocationResolver{},
+		mockLocationResolver{}, nil, nil, nil, nil, nil, nil,
 	)
 
 	id, err := manager.Start(identity.FromAddress(proposalMock.ProviderID), serviceType, nil, struct{}{})
@@ -154,6 +156,231 @@ func TestManager_StopSendsEvent_SucceedsAndPublishesEvent(t *testing.T) {
 	assert.True(t, matchFound)
 }
 
+func TestManager_StartAppliesTemplate(t *testing.T) {
+	registry := NewRegistry()
+	var createdWith Options
+	registry.Register(serviceType, func(options Options) (Service, error) {
+		createdWith = options
+		mockCopy := *serviceMock
+		mockCopy.mockProcess = make(chan struct{})
+		return &mockCopy, nil
+	})
+
+	templateOptions := struct{ Port int }{Port: 1194}
+	templates := &mockTemplateSource{template: &Template{
+		Version: "v42",
+		Options: templateOptions,
+		Shaping: &shaper.Limits{Enabled: true, BandwidthKBps: 100},
+	}}
+
+	discovery := mockDiscovery{}
+	manager := NewManager(
+		registry,
+		MockDiscoveryFactoryFunc(&discovery),
+		mocks.NewEventBus(),
+		mockPolicyOracle,
+		mockPolicyProvider,
+		&mockP2PListener{}, nil, nil,
+		mockLocationResolver{}, templates, nil, nil, nil, nil, nil,
+	)
+
+	id, err := manager.Start(identity.FromAddress(proposalMock.ProviderID), serviceType, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, templateOptions, createdWith)
+	assert.Equal(t, "v42", manager.Service(id).TemplateVersion)
+	assert.Equal(t, templates.template.Shaping, manager.Service(id).Shaping)
+	assert.NoError(t, manager.Stop(id))
+	discovery.Wait()
+
+	// Options passed explicitly take precedence over the template.
+	callerOptions := struct{ Port int }{Port: 51820}
+	id, err = manager.Start(identity.FromAddress(proposalMock.ProviderID), serviceType, nil, callerOptions)
+	assert.NoError(t, err)
+	assert.Equal(t, callerOptions, createdWith)
+	assert.Equal(t, "v42", manager.Service(id).TemplateVersion)
+	assert.NoError(t, manager.Stop(id))
+	discovery.Wait()
+
+	templates.err = errors.New("signature verification failed")
+	_, err = manager.Start(identity.FromAddress(proposalMock.ProviderID), serviceType, nil, struct{}{})
+	assert.Error(t, err)
+}
+
+func TestManager_StartIsRejectedByComplianceGate(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(serviceType, func(options Options) (Service, error) {
+		mockCopy := *serviceMock
+		mockCopy.mockProcess = make(chan struct{})
+		return &mockCopy, nil
+	})
+
+	gate := &mockComplianceGate{err: errors.New("service type is restricted in the jurisdiction")}
+	manager := NewManager(
+		registry,
+		MockDiscoveryFactoryFunc(&mockDiscovery{}),
+		mocks.NewEventBus(),
+		mockPolicyOracle,
+		mockPolicyProvider,
+		&mockP2PListener{}, nil, nil,
+		mockLocationResolver{}, nil, nil, nil, gate, nil, nil,
+	)
+
+	_, err := manager.Start(identity.FromAddress(proposalMock.ProviderID), serviceType, nil, struct{}{})
+	assert.ErrorIs(t, err, gate.err)
+	assert.Equal(t, serviceType, gate.serviceType)
+	assert.Len(t, manager.servicePool.List(), 0)
+}
+
+type mockComplianceGate struct {
+	serviceType string
+	err         error
+}
+
+func (m *mockComplianceGate) CheckService(serviceType, _ string) error {
+	m.serviceType = serviceType
+	return m.err
+}
+
+func TestManager_StartIsNotBlockedByPausing(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(serviceType, func(options Options) (Service, error) {
+		mockCopy := *serviceMock
+		mockCopy.mockProcess = make(chan struct{})
+		return &mockCopy, nil
+	})
+
+	discovery := &blockingDiscovery{release: make(chan struct{})}
+	manager := NewManager(
+		registry,
+		MockDiscoveryFactoryFunc(discovery),
+		mocks.NewEventBus(),
+		mockPolicyOracle,
+		mockPolicyProvider,
+		&mockP2PListener{}, nil, nil,
+		mockLocationResolver{}, nil, nil, nil, nil, nil, nil,
+	)
+
+	_, err := manager.Start(identity.FromAddress(proposalMock.ProviderID), serviceType, nil, struct{}{})
+	assert.NoError(t, err)
+
+	paused := make(chan struct{})
+	go func() {
+		manager.PauseProposals("overloaded")
+		close(paused)
+	}()
+	assert.Eventually(t, func() bool { return discovery.stopped() }, time.Second, 10*time.Millisecond)
+
+	// Unregistration is still in progress, yet new services can be started.
+	id, err := manager.Start(identity.FromAddress(proposalMock.ProviderID), serviceType, nil, struct{}{})
+	assert.NoError(t, err)
+	assert.Nil(t, manager.Service(id).discovery, "service started while paused should not be announced")
+
+	close(discovery.release)
+	<-paused
+	assert.NoError(t, manager.Kill())
+}
+
+func TestManager_RepublishProposals(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(serviceType, func(options Options) (Service, error) {
+		mockCopy := *serviceMock
+		mockCopy.mockProcess = make(chan struct{})
+		return &mockCopy, nil
+	})
+
+	discovery := &countingDiscovery{}
+	manager := NewManager(
+		registry,
+		MockDiscoveryFactoryFunc(discovery),
+		mocks.NewEventBus(),
+		mockPolicyOracle,
+		mockPolicyProvider,
+		&mockP2PListener{}, nil, nil,
+		mockLocationResolver{}, nil, nil, nil, nil, nil, nil,
+	)
+
+	running, err := manager.Start(identity.FromAddress(proposalMock.ProviderID), serviceType, nil, struct{}{})
+	assert.NoError(t, err)
+	manager.RepublishProposals()
+	assert.Equal(t, 2, discovery.startCount())
+	assert.Equal(t, 1, discovery.stopCount())
+	assert.NotNil(t, manager.Service(running).discovery)
+
+	// Paused proposals are not announced until resumed.
+	manager.PauseProposals("overloaded")
+	manager.RepublishProposals()
+	assert.Equal(t, 2, discovery.startCount())
+	assert.Equal(t, 2, discovery.stopCount())
+	assert.Nil(t, manager.Service(running).discovery)
+
+	assert.NoError(t, manager.Kill())
+}
+
+type countingDiscovery struct {
+	lock   sync.Mutex
+	starts int
+	stops  int
+}
+
+func (d *countingDiscovery) Start(_ identity.Identity, _ func() market.ServiceProposal) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.starts++
+}
+
+func (d *countingDiscovery) Stop() {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.stops++
+}
+
+func (d *countingDiscovery) Wait() {}
+
+func (d *countingDiscovery) startCount() int {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	return d.starts
+}
+
+func (d *countingDiscovery) stopCount() int {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	return d.stops
+}
+
+type blockingDiscovery struct {
+	lock    sync.Mutex
+	stops   int
+	release chan struct{}
+}
+
+func (d *blockingDiscovery) Start(_ identity.Identity, _ func() market.ServiceProposal) {}
+
+func (d *blockingDiscovery) Stop() {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.stops++
+}
+
+func (d *blockingDiscovery) stopped() bool {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	return d.stops > 0
+}
+
+func (d *blockingDiscovery) Wait() {
+	<-d.release
+}
+
+type mockTemplateSource struct {
+	template *Template
+	err      error
+}
+
+func (m *mockTemplateSource) Template(_ string) (*Template, error) {
+	return m.template, m.err
+}
+
 type mockP2PListener struct {
 }
 
@@ -26,6 +26,7 @@ import (
 
 	"github.com/mysteriumnetwork/node/core/policy"
 	"github.com/mysteriumnetwork/node/core/service/servicestate"
+	"github.com/mysteriumnetwork/node/core/shaper"
 	"github.com/mysteriumnetwork/node/identity"
 	"github.com/mysteriumnetwork/node/market"
 	"github.com/mysteriumnetwork/node/p2p"
@@ -161,11 +162,19 @@ type Instance struct {
 	muProposal      sync.Mutex
 	Proposal        market.ServiceProposal
 	policyProvider  policy.Provider
+	discoveryLock   sync.Mutex
 	discovery       Discovery
+	pauseReason     string
 	eventPublisher  Publisher
 	p2pChannelsLock sync.Mutex
 	p2pChannels     []p2p.Channel
 	location        locationResolver
+	maintenance     MaintenanceSchedule
+	reservations    ReservationBook
+	// TemplateVersion is the version of the operator template applied on start, empty if none.
+	TemplateVersion string
+	// Shaping holds the traffic shaping of the applied template, nil if node configuration is used.
+	Shaping *shaper.Limits
 }
 
 // Service returns the running service implementation.
@@ -186,6 +195,12 @@ func (i *Instance) State() servicestate.State {
 }
 
 func (i *Instance) proposalWithCurrentLocation() market.ServiceProposal {
+	if i.maintenance != nil {
+		i.muProposal.Lock()
+		i.Proposal.Maintenance = i.maintenance.Upcoming()
+		i.muProposal.Unlock()
+	}
+
 	location, err := i.location.DetectLocation()
 	if err != nil {
 		log.Warn().Err(err).Msg("Failed to get current location for proposal, using last known location")
@@ -199,6 +214,80 @@ func (i *Instance) proposalWithCurrentLocation() market.ServiceProposal {
 	return i.Proposal
 }
 
+// ProposalPauseReason returns the reason proposal announcements are paused for, empty if they are not paused.
+func (i *Instance) ProposalPauseReason() string {
+	i.discoveryLock.Lock()
+	defer i.discoveryLock.Unlock()
+	return i.pauseReason
+}
+
+// pauseDiscovery stops announcing the proposal and waits for it to be unregistered.
+// The service itself and its sessions keep running.
+func (i *Instance) pauseDiscovery(reason string) {
+	i.discoveryLock.Lock()
+	wasPaused := i.pauseReason != ""
+	i.pauseReason = reason
+	discovery := i.discovery
+	if wasPaused || discovery == nil {
+		i.discoveryLock.Unlock()
+		return
+	}
+	i.discovery = nil
+	i.discoveryLock.Unlock()
+
+	discovery.Stop()
+	discovery.Wait()
+}
+
+// resumeDiscovery starts announcing the proposal again with a fresh discovery.
+func (i *Instance) resumeDiscovery(newDiscovery DiscoveryFactory) {
+	i.discoveryLock.Lock()
+	defer i.discoveryLock.Unlock()
+
+	if i.pauseReason == "" || i.State() == servicestate.NotRunning {
+		return
+	}
+	i.pauseReason = ""
+
+	i.discovery = newDiscovery()
+	i.discovery.Start(i.ProviderID, i.proposalWithCurrentLocation)
+}
+
+// restartDiscovery unregisters the proposal and announces it again with a fresh discovery.
+// Paused proposals are left paused, they are announced with a fresh discovery once resumed.
+func (i *Instance) restartDiscovery(newDiscovery DiscoveryFactory) {
+	i.discoveryLock.Lock()
+	discovery := i.discovery
+	if i.pauseReason != "" || discovery == nil || i.State() == servicestate.NotRunning {
+		i.discoveryLock.Unlock()
+		return
+	}
+	i.discovery = nil
+	i.discoveryLock.Unlock()
+
+	discovery.Stop()
+	discovery.Wait()
+
+	i.discoveryLock.Lock()
+	defer i.discoveryLock.Unlock()
+
+	if i.pauseReason != "" || i.State() == servicestate.NotRunning {
+		return
+	}
+	i.discovery = newDiscovery()
+	i.discovery.Start(i.ProviderID, i.proposalWithCurrentLocation)
+}
+
+func (i *Instance) waitDiscovery() {
+	i.discoveryLock.Lock()
+	discovery := i.discovery
+	i.discoveryLock.Unlock()
+
+	if discovery != nil {
+		discovery.Wait()
+	}
+}
+
 func (i *Instance) setState(newState servicestate.State) {
 	i.stateLock.Lock()
 	defer i.stateLock.Unlock()
@@ -216,9 +305,11 @@ func (i *Instance) addP2PChannel(ch p2p.Channel) {
 
 func (i *Instance) stop() error {
 	errStop := utils.ErrorCollection{}
+	i.discoveryLock.Lock()
 	if i.discovery != nil {
 		i.discovery.Stop()
 	}
+	i.discoveryLock.Unlock()
 	if i.service != nil {
 		errStop.Add(i.service.Stop())
 	}
@@ -256,6 +347,9 @@ func (i *Instance) CopyProposal() market.ServiceProposal {
 	if i.Proposal.Contacts == nil {
 		proposal.Contacts = nil
 	}
+	if i.Proposal.Maintenance == nil {
+		proposal.Maintenance = nil
+	}
 
 	return proposal
 }
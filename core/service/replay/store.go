@@ -0,0 +1,152 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package replay
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const bucketName = "session-replay"
+
+// DefaultTTL is how long handled session create requests and issued session IDs are remembered.
+const DefaultTTL = 24 * time.Hour
+
+const (
+	kindRequest = "request"
+	kindSession = "session"
+)
+
+// ErrReplayed indicates that the session create request was already handled, possibly before a restart.
+var ErrReplayed = errors.New("session create request is replayed")
+
+type persistentStorage interface {
+	Store(bucket string, data interface{}) error
+	GetAllFrom(bucket string, data interface{}) error
+	Delete(bucket string, data interface{}) error
+}
+
+type record struct {
+	Key       string `storm:"id"`
+	Kind      string
+	ExpiresAt time.Time
+}
+
+// Store persists recently handled session create requests and issued session IDs,
+// so that requests replayed by stale consumers after a provider restart are rejected.
+type Store struct {
+	storage persistentStorage
+	ttl     time.Duration
+	now     func() time.Time
+
+	lock    sync.Mutex
+	loaded  bool
+	records map[string]record
+}
+
+// NewStore creates a replay store remembering requests and sessions for the given ttl.
+func NewStore(storage persistentStorage, ttl time.Duration) *Store {
+	return &Store{
+		storage: storage,
+		ttl:     ttl,
+		now:     time.Now,
+		records: make(map[string]record),
+	}
+}
+
+// RememberRequest records the session create request of the consumer identified by key,
+// ErrReplayed is returned if the same request was already recorded.
+func (s *Store) RememberRequest(consumerID, key string) error {
+	return s.remember(kindRequest, consumerID+"/"+key, ErrReplayed)
+}
+
+// RememberSession records the session ID issued to a consumer.
+func (s *Store) RememberSession(sessionID string) error {
+	return s.remember(kindSession, sessionID, nil)
+}
+
+// Issued returns true if the session ID was issued recently, including before a restart.
+func (s *Store) Issued(sessionID string) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if err := s.load(); err != nil {
+		return false
+	}
+	r, ok := s.records[recordKey(kindSession, sessionID)]
+	return ok && s.now().Before(r.ExpiresAt)
+}
+
+func (s *Store) remember(kind, key string, errSeen error) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if err := s.load(); err != nil {
+		return err
+	}
+	s.prune()
+
+	id := recordKey(kind, key)
+	if r, ok := s.records[id]; ok && errSeen != nil && s.now().Before(r.ExpiresAt) {
+		return errSeen
+	}
+
+	r := record{Key: id, Kind: kind, ExpiresAt: s.now().Add(s.ttl)}
+	if err := s.storage.Store(bucketName, &r); err != nil {
+		return fmt.Errorf("could not store %s replay record: %w", kind, err)
+	}
+	s.records[id] = r
+	return nil
+}
+
+// load reads records persisted before the restart. Must be called with lock held.
+func (s *Store) load() error {
+	if s.loaded {
+		return nil
+	}
+
+	var records []record
+	if err := s.storage.GetAllFrom(bucketName, &records); err != nil {
+		return fmt.Errorf("could not load replay records: %w", err)
+	}
+	for _, r := range records {
+		s.records[r.Key] = r
+	}
+	s.loaded = true
+	return nil
+}
+
+// prune drops expired records. Must be called with lock held.
+func (s *Store) prune() {
+	now := s.now()
+	for id, r := range s.records {
+		if now.Before(r.ExpiresAt) {
+			continue
+		}
+		if err := s.storage.Delete(bucketName, &r); err != nil {
+			continue
+		}
+		delete(s.records, id)
+	}
+}
+
+func recordKey(kind, key string) string {
+	return kind + ":" + key
+}
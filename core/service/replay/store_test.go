@@ -0,0 +1,72 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package replay
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mysteriumnetwork/node/core/storage/boltdb"
+)
+
+func TestStore_RejectsReplayedRequestAfterRestart(t *testing.T) {
+	dir := t.TempDir()
+	storage, err := boltdb.NewStorage(dir)
+	require.NoError(t, err)
+
+	store := NewStore(storage, time.Hour)
+	assert.NoError(t, store.RememberRequest("0x1", "nonce:1"))
+	assert.ErrorIs(t, store.RememberRequest("0x1", "nonce:1"), ErrReplayed)
+	assert.NoError(t, store.RememberRequest("0x2", "nonce:1"))
+	assert.NoError(t, store.RememberSession("session-1"))
+	require.NoError(t, storage.Close())
+
+	storage, err = boltdb.NewStorage(dir)
+	require.NoError(t, err)
+	defer storage.Close()
+
+	restarted := NewStore(storage, time.Hour)
+	assert.ErrorIs(t, restarted.RememberRequest("0x1", "nonce:1"), ErrReplayed)
+	assert.NoError(t, restarted.RememberRequest("0x1", "nonce:2"))
+	assert.True(t, restarted.Issued("session-1"))
+	assert.False(t, restarted.Issued("session-2"))
+}
+
+func TestStore_ForgetsExpiredRecords(t *testing.T) {
+	storage, err := boltdb.NewStorage(t.TempDir())
+	require.NoError(t, err)
+	defer storage.Close()
+
+	now := time.Now()
+	store := NewStore(storage, time.Hour)
+	store.now = func() time.Time { return now }
+
+	assert.NoError(t, store.RememberRequest("0x1", "nonce:1"))
+	assert.NoError(t, store.RememberSession("session-1"))
+
+	now = now.Add(time.Hour)
+	assert.False(t, store.Issued("session-1"))
+	assert.NoError(t, store.RememberRequest("0x1", "nonce:1"))
+
+	var records []record
+	require.NoError(t, storage.GetAllFrom(bucketName, &records))
+	assert.Len(t, records, 1)
+}
@@ -0,0 +1,305 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package reservation
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/mysteriumnetwork/payments/crypto"
+
+	"github.com/mysteriumnetwork/node/identity"
+)
+
+// Status represents the state of the reservation.
+type Status string
+
+const (
+	// StatusPending means that the reservation waits for the prepaid fee.
+	StatusPending Status = "pending"
+	// StatusConfirmed means that the reservation is paid and holds the capacity.
+	StatusConfirmed Status = "confirmed"
+	// StatusConverted means that the reservation was turned into a session.
+	StatusConverted Status = "converted"
+)
+
+const (
+	// ClaimLead is how long before the start of the slot the reservation can be turned into a session.
+	ClaimLead = 5 * time.Minute
+	// MaxDuration is the longest time slot which can be reserved.
+	MaxDuration = 24 * time.Hour
+	// MaxAhead is how far in the future the time slot can start.
+	MaxAhead = 7 * 24 * time.Hour
+
+	paymentTimeout = time.Minute
+)
+
+var (
+	// ErrNoCapacity is returned when the time slot is fully reserved.
+	ErrNoCapacity = errors.New("no capacity left for the time slot")
+	// ErrInvalidSlot is returned when the requested time slot is not acceptable.
+	ErrInvalidSlot = errors.New("invalid reservation time slot")
+	// ErrNotFound is returned when the reservation does not exist or belongs to another consumer.
+	ErrNotFound = errors.New("reservation not found")
+	// ErrInvalidPayment is returned when the exchange message does not pay for the reservation.
+	ErrInvalidPayment = errors.New("invalid reservation payment")
+)
+
+// Reservation represents capacity reserved by the consumer for a future time slot.
+type Reservation struct {
+	ID          string
+	ProviderID  identity.Identity
+	ConsumerID  identity.Identity
+	ServiceType string
+	Start       time.Time
+	End         time.Time
+	// Fee is the prepaid reservation fee, zero if reservations are free.
+	Fee *big.Int
+	// Invoice of the reservation fee, empty if reservations are free.
+	Invoice   crypto.Invoice
+	Status    Status
+	CreatedAt time.Time
+}
+
+type promiseHandler interface {
+	RequestPromise(r []byte, em crypto.ExchangeMessage, providerID identity.Identity, sessionID string) <-chan error
+}
+
+type entry struct {
+	Reservation
+	r []byte
+}
+
+// Book tracks reservations of the provider capacity against the concurrency limit.
+// Reservations are kept in memory only, they are lost on node restart.
+type Book struct {
+	lock       sync.Mutex
+	limit      int
+	fee        *big.Int
+	chainID    int64
+	promises   promiseHandler
+	entries    map[string]*entry
+	timeGetter func() time.Time
+}
+
+// NewBook creates reservation book for the given number of concurrent sessions, zero limit means no limit.
+// Reservations with non zero fee are confirmed once the fee promise is redeemed from hermes.
+func NewBook(limit int, fee *big.Int, chainID int64, promises promiseHandler) *Book {
+	if fee == nil {
+		fee = new(big.Int)
+	}
+
+	return &Book{
+		limit:      limit,
+		fee:        fee,
+		chainID:    chainID,
+		promises:   promises,
+		entries:    make(map[string]*entry),
+		timeGetter: time.Now,
+	}
+}
+
+// Reserve reserves capacity for the consumer in the given time slot.
+// Reservation is pending until its fee is paid, unless reservations are free.
+func (b *Book) Reserve(providerID, consumerID identity.Identity, serviceType string, start, end time.Time) (Reservation, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	now := b.timeGetter()
+	if !end.After(start) || end.Sub(start) > MaxDuration || !end.After(now) || start.Sub(now) > MaxAhead {
+		return Reservation{}, ErrInvalidSlot
+	}
+
+	b.prune(now)
+	if b.limit > 0 && b.overlapping(start, end) >= b.limit {
+		return Reservation{}, ErrNoCapacity
+	}
+
+	id, err := uuid.NewV4()
+	if err != nil {
+		return Reservation{}, err
+	}
+
+	e := &entry{Reservation: Reservation{
+		ID:          id.String(),
+		ProviderID:  providerID,
+		ConsumerID:  consumerID,
+		ServiceType: serviceType,
+		Start:       start,
+		End:         end,
+		Fee:         new(big.Int).Set(b.fee),
+		Status:      StatusConfirmed,
+		CreatedAt:   now,
+	}}
+	if b.fee.Sign() > 0 {
+		if e.r, err = crypto.GenerateR(); err != nil {
+			return Reservation{}, err
+		}
+		agreementID, err := crypto.GenerateR()
+		if err != nil {
+			return Reservation{}, err
+		}
+		e.Invoice, err = crypto.CreateInvoice(new(big.Int).SetBytes(agreementID), b.fee, new(big.Int), e.r, b.chainID)
+		if err != nil {
+			return Reservation{}, fmt.Errorf("failed to create invoice: %w", err)
+		}
+		e.Invoice.Provider = providerID.Address
+		e.Status = StatusPending
+	}
+
+	b.entries[e.ID] = e
+	return e.Reservation, nil
+}
+
+// Pay confirms the pending reservation paid by the exchange message once its promise is redeemed from hermes.
+// Reservation is looked up by the hashlock of its invoice.
+func (b *Book) Pay(consumerID identity.Identity, em crypto.ExchangeMessage) (Reservation, error) {
+	hashlock := hex.EncodeToString(em.Promise.Hashlock)
+
+	b.lock.Lock()
+	var e *entry
+	for _, candidate := range b.entries {
+		if candidate.Invoice.Hashlock != "" && candidate.Invoice.Hashlock == hashlock {
+			e = candidate
+			break
+		}
+	}
+	if e == nil || e.ConsumerID != consumerID {
+		b.lock.Unlock()
+		return Reservation{}, ErrNotFound
+	}
+	res, r := e.Reservation, e.r
+	b.lock.Unlock()
+
+	if res.Status != StatusPending {
+		return res, nil
+	}
+	if err := validatePayment(res, em); err != nil {
+		return Reservation{}, err
+	}
+	// Promise handler closes the channel once the promise is redeemed.
+	if err := <-b.promises.RequestPromise(r, em, res.ProviderID, res.ID); err != nil {
+		return Reservation{}, fmt.Errorf("could not redeem reservation fee: %w", err)
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if e.Status == StatusPending {
+		e.Status = StatusConfirmed
+	}
+	return e.Reservation, nil
+}
+
+func validatePayment(res Reservation, em crypto.ExchangeMessage) error {
+	if !em.IsMessageValid(res.ConsumerID.ToCommonAddress()) {
+		return fmt.Errorf("%w: exchange message is not signed by the consumer", ErrInvalidPayment)
+	}
+	signer, err := em.Promise.RecoverSigner()
+	if err != nil || signer != res.ConsumerID.ToCommonAddress() {
+		return fmt.Errorf("%w: promise is not signed by the consumer", ErrInvalidPayment)
+	}
+	if em.ChainID != res.Invoice.ChainID {
+		return fmt.Errorf("%w: wrong chain %d", ErrInvalidPayment, em.ChainID)
+	}
+	if em.AgreementID == nil || em.AgreementID.Cmp(res.Invoice.AgreementID) != 0 {
+		return fmt.Errorf("%w: wrong agreement", ErrInvalidPayment)
+	}
+	if em.AgreementTotal == nil || em.AgreementTotal.Cmp(res.Fee) < 0 {
+		return fmt.Errorf("%w: expected %s, got %s", ErrInvalidPayment, res.Fee, em.AgreementTotal)
+	}
+	return nil
+}
+
+// Admit checks whether a new session of the consumer fits into the concurrency limit next to the active sessions.
+// Confirmed reservation of the consumer for the current time slot is converted into the session and its ID is returned,
+// such session is always admitted.
+func (b *Book) Admit(consumerID identity.Identity, serviceType string, active int) (string, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	now := b.timeGetter()
+	b.prune(now)
+	for _, e := range b.entries {
+		if e.ConsumerID == consumerID && e.ServiceType == serviceType && e.Status == StatusConfirmed && e.claimable(now) {
+			e.Status = StatusConverted
+			return e.ID, nil
+		}
+	}
+
+	if b.limit > 0 && active+b.held(now) >= b.limit {
+		return "", ErrNoCapacity
+	}
+	return "", nil
+}
+
+// List returns the current reservations ordered by the start of their time slot.
+func (b *Book) List() []Reservation {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.prune(b.timeGetter())
+	result := make([]Reservation, 0, len(b.entries))
+	for _, e := range b.entries {
+		result = append(result, e.Reservation)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Start.Before(result[j].Start)
+	})
+	return result
+}
+
+// overlapping returns the number of reservations overlapping the time slot.
+func (b *Book) overlapping(start, end time.Time) int {
+	var count int
+	for _, e := range b.entries {
+		if e.Start.Before(end) && start.Before(e.End) {
+			count++
+		}
+	}
+	return count
+}
+
+// held returns the number of confirmed reservations which are not turned into sessions yet, but can be at the moment.
+func (b *Book) held(now time.Time) int {
+	var count int
+	for _, e := range b.entries {
+		if e.Status == StatusConfirmed && e.claimable(now) {
+			count++
+		}
+	}
+	return count
+}
+
+// prune removes ended reservations and the ones which were not paid in time.
+func (b *Book) prune(now time.Time) {
+	for id, e := range b.entries {
+		if !now.Before(e.End) || (e.Status == StatusPending && now.Sub(e.CreatedAt) > paymentTimeout) {
+			delete(b.entries, id)
+		}
+	}
+}
+
+func (e *entry) claimable(now time.Time) bool {
+	return !now.Before(e.Start.Add(-ClaimLead)) && now.Before(e.End)
+}
@@ -0,0 +1,177 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package reservation
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/mysteriumnetwork/payments/crypto"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/identity"
+)
+
+var (
+	providerID = identity.FromAddress("0x1")
+	now        = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+)
+
+func TestBook_Reserve_RespectsLimit(t *testing.T) {
+	book := newTestBook(2, nil, nil)
+
+	_, err := book.Reserve(providerID, identity.FromAddress("0x2"), "wireguard", now.Add(time.Hour), now.Add(2*time.Hour))
+	assert.NoError(t, err)
+	_, err = book.Reserve(providerID, identity.FromAddress("0x3"), "wireguard", now.Add(90*time.Minute), now.Add(3*time.Hour))
+	assert.NoError(t, err)
+
+	_, err = book.Reserve(providerID, identity.FromAddress("0x4"), "wireguard", now.Add(100*time.Minute), now.Add(110*time.Minute))
+	assert.ErrorIs(t, err, ErrNoCapacity)
+
+	_, err = book.Reserve(providerID, identity.FromAddress("0x4"), "wireguard", now.Add(3*time.Hour), now.Add(4*time.Hour))
+	assert.NoError(t, err)
+}
+
+func TestBook_Reserve_RejectsInvalidSlot(t *testing.T) {
+	book := newTestBook(0, nil, nil)
+	consumerID := identity.FromAddress("0x2")
+
+	for name, slot := range map[string][2]time.Time{
+		"empty":     {now.Add(time.Hour), now.Add(time.Hour)},
+		"ended":     {now.Add(-2 * time.Hour), now.Add(-time.Hour)},
+		"too long":  {now, now.Add(MaxDuration + time.Minute)},
+		"too early": {now.Add(MaxAhead + time.Hour), now.Add(MaxAhead + 2*time.Hour)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := book.Reserve(providerID, consumerID, "wireguard", slot[0], slot[1])
+			assert.ErrorIs(t, err, ErrInvalidSlot)
+		})
+	}
+}
+
+func TestBook_Admit_ConvertsReservation(t *testing.T) {
+	book := newTestBook(1, nil, nil)
+	consumerID := identity.FromAddress("0x2")
+
+	res, err := book.Reserve(providerID, consumerID, "wireguard", now.Add(time.Minute), now.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, StatusConfirmed, res.Status)
+
+	// capacity is held for the reservation
+	_, err = book.Admit(identity.FromAddress("0x3"), "wireguard", 0)
+	assert.ErrorIs(t, err, ErrNoCapacity)
+
+	// other service of the consumer does not convert the reservation
+	_, err = book.Admit(consumerID, "scraping", 0)
+	assert.ErrorIs(t, err, ErrNoCapacity)
+
+	id, err := book.Admit(consumerID, "wireguard", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, res.ID, id)
+	assert.Equal(t, StatusConverted, book.List()[0].Status)
+
+	// converted reservation is counted as an active session
+	_, err = book.Admit(identity.FromAddress("0x3"), "wireguard", 1)
+	assert.ErrorIs(t, err, ErrNoCapacity)
+	id, err = book.Admit(identity.FromAddress("0x3"), "wireguard", 0)
+	assert.NoError(t, err)
+	assert.Empty(t, id)
+}
+
+func TestBook_Pay(t *testing.T) {
+	promises := &mockPromiseHandler{}
+	book := newTestBook(1, big.NewInt(1000), promises)
+
+	ks := identity.NewMockKeystore()
+	acc, err := ks.NewAccount("")
+	assert.NoError(t, err)
+	assert.NoError(t, ks.Unlock(acc, ""))
+	consumerID := identity.FromAddress(acc.Address.Hex())
+
+	res, err := book.Reserve(providerID, consumerID, "wireguard", now.Add(time.Hour), now.Add(2*time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, StatusPending, res.Status)
+	assert.Equal(t, big.NewInt(1000), res.Invoice.AgreementTotal)
+	assert.Equal(t, providerID.Address, res.Invoice.Provider)
+
+	channel := "0x441Da57A51e42DAB7Daf55909Af93A9b00eEF23C"
+	short, err := crypto.CreateExchangeMessage(1, crypto.Invoice{
+		AgreementID:    res.Invoice.AgreementID,
+		AgreementTotal: big.NewInt(10),
+		TransactorFee:  new(big.Int),
+		Hashlock:       res.Invoice.Hashlock,
+		ChainID:        1,
+	}, big.NewInt(10), channel, "", ks, acc.Address)
+	assert.NoError(t, err)
+	_, err = book.Pay(consumerID, *short)
+	assert.ErrorIs(t, err, ErrInvalidPayment)
+
+	em, err := crypto.CreateExchangeMessage(1, res.Invoice, big.NewInt(1000), channel, "", ks, acc.Address)
+	assert.NoError(t, err)
+	_, err = book.Pay(identity.FromAddress("0x3"), *em)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	promises.err = errors.New("hermes unavailable")
+	_, err = book.Pay(consumerID, *em)
+	assert.Error(t, err)
+	assert.Equal(t, StatusPending, book.List()[0].Status)
+
+	promises.err = nil
+	paid, err := book.Pay(consumerID, *em)
+	assert.NoError(t, err)
+	assert.Equal(t, StatusConfirmed, paid.Status)
+	assert.Equal(t, res.ID, promises.sessionID)
+}
+
+func TestBook_PrunesUnpaidReservations(t *testing.T) {
+	book := newTestBook(1, big.NewInt(1000), &mockPromiseHandler{})
+
+	_, err := book.Reserve(providerID, identity.FromAddress("0x2"), "wireguard", now.Add(time.Hour), now.Add(2*time.Hour))
+	assert.NoError(t, err)
+	_, err = book.Reserve(providerID, identity.FromAddress("0x3"), "wireguard", now.Add(time.Hour), now.Add(2*time.Hour))
+	assert.ErrorIs(t, err, ErrNoCapacity)
+
+	book.timeGetter = func() time.Time { return now.Add(2 * paymentTimeout) }
+	assert.Empty(t, book.List())
+	_, err = book.Reserve(providerID, identity.FromAddress("0x3"), "wireguard", now.Add(time.Hour), now.Add(2*time.Hour))
+	assert.NoError(t, err)
+}
+
+func newTestBook(limit int, fee *big.Int, promises promiseHandler) *Book {
+	book := NewBook(limit, fee, 1, promises)
+	book.timeGetter = func() time.Time { return now }
+	return book
+}
+
+type mockPromiseHandler struct {
+	err       error
+	sessionID string
+}
+
+func (m *mockPromiseHandler) RequestPromise(r []byte, em crypto.ExchangeMessage, providerID identity.Identity, sessionID string) <-chan error {
+	ch := make(chan error, 1)
+	if m.err != nil {
+		ch <- m.err
+	} else {
+		m.sessionID = sessionID
+	}
+	close(ch)
+	return ch
+}
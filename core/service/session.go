@@ -42,12 +42,21 @@ type Session struct {
 	Proposal         market.ServiceProposal
 	ServiceID        string
 	CreatedAt        time.Time
+	Protocol         session.Protocol
+	Peer             session.Peer
 	request          *pb.SessionRequest
 	done             chan struct{}
 	cleanupLock      sync.Mutex
 	cleanup          []func() error
 	tracer           *trace.Tracer
 	once             sync.Once
+	paymentsLock     sync.Mutex
+	paused           bool
+	pauseTimer       *time.Timer
+	refund           uint8
+	payments         PaymentEngine
+	reasonLock       sync.Mutex
+	disconnectReason session.DisconnectReason
 }
 
 // Close ends session.
@@ -69,11 +78,113 @@ func (s *Session) Close() {
 	})
 }
 
+// CloseWithReason ends session and records why it was ended.
+// Reason is ignored if the session was already closed.
+func (s *Session) CloseWithReason(reason session.DisconnectReason) {
+	select {
+	case <-s.done:
+	default:
+		s.reasonLock.Lock()
+		s.disconnectReason = reason
+		s.reasonLock.Unlock()
+	}
+	s.Close()
+}
+
+// DisconnectReason returns the reason reported by the consumer when ending the session.
+func (s *Session) DisconnectReason() session.DisconnectReason {
+	s.reasonLock.Lock()
+	defer s.reasonLock.Unlock()
+
+	return s.disconnectReason
+}
+
 // Done returns readonly done channel.
 func (s *Session) Done() <-chan struct{} {
 	return s.done
 }
 
+// Paused reports whether data flow and invoicing of the session are suspended.
+func (s *Session) Paused() bool {
+	s.paymentsLock.Lock()
+	defer s.paymentsLock.Unlock()
+
+	return s.paused
+}
+
+// setPaused suspends or restores invoicing of the session.
+// Session paused for longer than maxPause is ended, zero means no limit.
+func (s *Session) setPaused(paused bool, maxPause time.Duration) {
+	s.paymentsLock.Lock()
+	defer s.paymentsLock.Unlock()
+
+	s.paused = paused
+	switch {
+	case paused && maxPause > 0 && s.pauseTimer == nil:
+		s.pauseTimer = time.AfterFunc(maxPause, func() {
+			log.Info().Msgf("Session %s was paused for longer than %s, ending it", s.ID, maxPause)
+			s.Close()
+		})
+	case !paused && s.pauseTimer != nil:
+		s.pauseTimer.Stop()
+		s.pauseTimer = nil
+	}
+
+	if s.payments == nil {
+		return
+	}
+
+	if paused {
+		s.payments.Pause()
+	} else {
+		s.payments.Resume()
+	}
+}
+
+func (s *Session) setPayments(engine PaymentEngine) {
+	s.paymentsLock.Lock()
+	defer s.paymentsLock.Unlock()
+
+	s.payments = engine
+	if s.paused {
+		engine.Pause()
+	}
+	if s.refund > 0 {
+		engine.Refund(s.refund)
+	}
+}
+
+// paymentsResyncer is implemented by payment engines able to catch up after the consumer was unreachable for a while.
+type paymentsResyncer interface {
+	Resync()
+}
+
+// resyncPayments makes session payments catch up after the consumer changed its network.
+func (s *Session) resyncPayments() {
+	s.paymentsLock.Lock()
+	defer s.paymentsLock.Unlock()
+
+	if resyncer, ok := s.payments.(paymentsResyncer); ok {
+		resyncer.Resync()
+	}
+}
+
+// claimRefund applies the SLA refund to the session payments, refund can be claimed only once.
+func (s *Session) claimRefund(refund uint8) bool {
+	s.paymentsLock.Lock()
+	defer s.paymentsLock.Unlock()
+
+	if s.refund > 0 {
+		return false
+	}
+
+	s.refund = refund
+	if s.payments != nil {
+		s.payments.Refund(refund)
+	}
+	return true
+}
+
 func (s *Session) addCleanup(fn func() error) {
 	s.cleanupLock.Lock()
 	defer s.cleanupLock.Unlock()
@@ -104,6 +215,9 @@ func (s *Session) toEvent(status event.Status) event.AppEventSession {
 			ConsumerLocation: s.ConsumerLocation,
 			HermesID:         s.HermesID,
 			Proposal:         s.Proposal,
+			Protocol:         s.Protocol,
+			Peer:             s.Peer,
+			DisconnectReason: s.DisconnectReason(),
 		},
 	}
 }
@@ -120,6 +234,11 @@ func NewSession(service *Instance, request *pb.SessionRequest, tracer *trace.Tra
 		consumerLocation.Country = location.GetCountry()
 	}
 
+	protocol := session.LocalProtocol().Negotiate(session.Protocol{
+		Version:      request.GetProtocolVersion(),
+		Capabilities: session.Capability(request.GetCapabilities()),
+	})
+
 	return &Session{
 		ID:               session.ID(uid.String()),
 		ConsumerID:       identity.FromAddress(request.GetConsumer().GetId()),
@@ -128,9 +247,14 @@ func NewSession(service *Instance, request *pb.SessionRequest, tracer *trace.Tra
 		Proposal:         service.CopyProposal(),
 		ServiceID:        string(service.ID),
 		CreatedAt:        time.Now().UTC(),
-		request:          request,
-		done:             make(chan struct{}),
-		cleanup:          make([]func() error, 0),
-		tracer:           tracer,
+		Protocol:         protocol,
+		Peer: session.Peer{
+			Version:      request.GetNodeVersion(),
+			Capabilities: session.Capability(request.GetCapabilities()),
+		},
+		request: request,
+		done:    make(chan struct{}),
+		cleanup: make([]func() error, 0),
+		tracer:  tracer,
 	}, nil
 }
@@ -24,6 +24,7 @@ import (
 	"fmt"
 	"math/big"
 	"net"
+	"strconv"
 	"sync"
 	"time"
 
@@ -32,8 +33,10 @@ import (
 
 	"github.com/mysteriumnetwork/node/config"
 	"github.com/mysteriumnetwork/node/core/quality"
+	"github.com/mysteriumnetwork/node/core/service/reservation"
 	"github.com/mysteriumnetwork/node/identity"
 	"github.com/mysteriumnetwork/node/market"
+	"github.com/mysteriumnetwork/node/metadata"
 	"github.com/mysteriumnetwork/node/nat/event"
 	"github.com/mysteriumnetwork/node/p2p"
 	"github.com/mysteriumnetwork/node/pb"
@@ -48,8 +51,22 @@ var (
 	ErrorInvalidProposal = errors.New("proposal does not exist")
 	// ErrorSessionNotExists returned when consumer tries to destroy session that does not exists
 	ErrorSessionNotExists = errors.New("session does not exists")
+	// ErrorSessionEnded returned when consumer refers to session which was issued recently, but is already closed, e.g. by provider restart
+	ErrorSessionEnded = errors.New("session has already ended")
 	// ErrorWrongSessionOwner returned when consumer tries to destroy session that does not belongs to him
 	ErrorWrongSessionOwner = errors.New("wrong session owner")
+	// ErrorKeyRotationUnsupported returned when service does not support tunnel key rotation
+	ErrorKeyRotationUnsupported = errors.New("key rotation is not supported by service")
+	// ErrorSessionPauseUnsupported returned when service is not able to suspend data flow of a session
+	ErrorSessionPauseUnsupported = errors.New("session pause is not supported by service")
+	// ErrorRoamingUnsupported returned when p2p channel of the session is not able to follow consumer address changes
+	ErrorRoamingUnsupported = errors.New("session roaming is not supported by channel")
+	// ErrorSLANotAdvertised returned when consumer claims SLA refund of the session without SLA
+	ErrorSLANotAdvertised = errors.New("SLA is not advertised for session")
+	// ErrorSLARefundClaimed returned when consumer claims SLA refund of the session for the second time
+	ErrorSLARefundClaimed = errors.New("SLA refund is already claimed")
+	// ErrorSLANotBreached returned when consumer claims SLA refund, but provider measured no breach
+	ErrorSLANotBreached = errors.New("SLA breach is not confirmed by provider")
 )
 
 // IDGenerator defines method for session id generation
@@ -79,6 +96,8 @@ type KeepAliveConfig struct {
 // Config contains common configuration options for session manager.
 type Config struct {
 	KeepAlive KeepAliveConfig
+	// MaxPause is how long a session may stay paused before it is ended, unlimited if zero.
+	MaxPause time.Duration
 }
 
 // DefaultConfig returns default params.
@@ -89,6 +108,7 @@ func DefaultConfig() Config {
 			SendTimeout:     5 * time.Second,
 			MaxSendErrCount: 5,
 		},
+		MaxPause: 30 * time.Minute,
 	}
 }
 
@@ -97,6 +117,22 @@ type ConfigProvider interface {
 	ProvideConfig(sessionID string, sessionConfig json.RawMessage, conn *net.UDPConn) (*ConfigParams, error)
 }
 
+// KeyRotator is implemented by services able to re-negotiate tunnel keys of a running session.
+type KeyRotator interface {
+	RotateKey(sessionID, consumerPublicKey string) (providerPublicKey string, err error)
+	ConfirmKeyRotation(sessionID, providerPublicKey string) error
+}
+
+// SessionPauser is implemented by services able to suspend data flow of a running session.
+type SessionPauser interface {
+	SetSessionPaused(sessionID string, paused bool) error
+}
+
+// roamingChannel is implemented by p2p channels able to switch to the new address of a roamed peer.
+type roamingChannel interface {
+	AcceptRoamedPeer() (*net.UDPAddr, error)
+}
+
 // DestroyCallback cleanups session
 type DestroyCallback func()
 
@@ -120,9 +156,50 @@ type PriceValidator interface {
 type PaymentEngine interface {
 	Start() error
 	WaitFirstInvoice(time.Duration) error
+	Pause()
+	Resume()
+	Refund(percent uint8)
 	Stop()
 }
 
+// SessionReconciler compares the session accounting of both session sides.
+type SessionReconciler interface {
+	Accounting(sessionID session.ID) (session.Accounting, bool)
+	Reconcile(sessionID session.ID, peer session.Accounting) error
+}
+
+// SLAMonitor measures the service level of provider sessions.
+type SLAMonitor interface {
+	Breach(sessionID session.ID) string
+}
+
+// MaintenanceSchedule provides the upcoming provider maintenance windows.
+type MaintenanceSchedule interface {
+	Upcoming() []market.MaintenanceWindow
+	Next(at time.Time) (market.MaintenanceWindow, bool)
+	Notice() time.Duration
+	Changed() <-chan struct{}
+}
+
+// ReservationBook keeps the capacity reserved by consumers ahead of time.
+type ReservationBook interface {
+	Reserve(providerID, consumerID identity.Identity, serviceType string, start, end time.Time) (reservation.Reservation, error)
+	Pay(consumerID identity.Identity, em crypto.ExchangeMessage) (reservation.Reservation, error)
+	Admit(consumerID identity.Identity, serviceType string, active int) (string, error)
+}
+
+// ReplayGuard remembers handled session create requests and issued session IDs across provider restarts.
+type ReplayGuard interface {
+	RememberRequest(consumerID, key string) error
+	RememberSession(sessionID string) error
+	Issued(sessionID string) bool
+}
+
+// LoadShedder rejects new sessions while the provider is overloaded.
+type LoadShedder interface {
+	Admit(active int) error
+}
+
 // NATEventGetter lets us access the last known traversal event
 type NATEventGetter interface {
 	LastEvent() *event.Event
@@ -137,6 +214,9 @@ func NewSessionManager(
 	channel p2p.Channel,
 	config Config,
 	priceValidator PriceValidator,
+	reconciler SessionReconciler,
+	slaMonitor SLAMonitor,
+	replay ReplayGuard,
 ) *SessionManager {
 	return &SessionManager{
 		service:              service,
@@ -147,6 +227,9 @@ func NewSessionManager(
 		channel:              channel,
 		config:               config,
 		priceValidator:       priceValidator,
+		reconciler:           reconciler,
+		slaMonitor:           slaMonitor,
+		replay:               replay,
 	}
 }
 
@@ -160,11 +243,28 @@ type SessionManager struct {
 	channel              p2p.Channel
 	config               Config
 	priceValidator       PriceValidator
+	reconciler           SessionReconciler
+	slaMonitor           SLAMonitor
+	replay               ReplayGuard
+	loadShedder          LoadShedder
+}
+
+// SetLoadShedder makes the manager reject new sessions while the given shedder reports overload.
+func (manager *SessionManager) SetLoadShedder(shedder LoadShedder) {
+	manager.loadShedder = shedder
 }
 
 // Start starts a session on the provider side for the given consumer.
 // Multiple sessions per peerID is possible in case different services are used
 func (manager *SessionManager) Start(request *pb.SessionRequest) (_ pb.SessionResponse, err error) {
+	// Reject early, before any work is done for the session.
+	if manager.loadShedder != nil {
+		if err := manager.loadShedder.Admit(len(manager.sessionStorage.GetAll())); err != nil {
+			log.Warn().Err(err).Msgf("Rejecting session request from %s", request.GetConsumer().GetId())
+			return pb.SessionResponse{}, err
+		}
+	}
+
 	session, err := NewSession(manager.service, request, manager.channel.Tracer())
 	if err != nil {
 		return pb.SessionResponse{}, fmt.Errorf("cannot create new session: %w", err)
@@ -214,9 +314,24 @@ func (manager *SessionManager) Start(request *pb.SessionRequest) (_ pb.SessionRe
 		return pb.SessionResponse{}, validationError
 	}
 
+	if reservations := manager.service.reservations; reservations != nil {
+		reservationID, err := reservations.Admit(session.ConsumerID, manager.service.Type, len(manager.sessionStorage.GetAll()))
+		if err != nil {
+			return pb.SessionResponse{}, err
+		}
+		if reservationID != "" {
+			log.Info().Msgf("Reservation %s converted into session %s", reservationID, session.ID)
+		}
+	}
+
 	if err = manager.startSession(session, prices); err != nil {
 		return pb.SessionResponse{}, err
 	}
+	if manager.replay != nil {
+		if err := manager.replay.RememberSession(string(session.ID)); err != nil {
+			log.Warn().Err(err).Msgf("Could not remember issued session %s", session.ID)
+		}
+	}
 
 	if err = manager.paymentLoop(session, prices); err != nil {
 		return pb.SessionResponse{}, err
@@ -250,18 +365,55 @@ func (manager *SessionManager) remapPricing(in *pb.Pricing) market.Price {
 
 // Acknowledge marks the session as successfully established as far as the consumer is concerned.
 func (manager *SessionManager) Acknowledge(consumerID identity.Identity, sessionID string) error {
-	session, found := manager.sessionStorage.Find(session.ID(sessionID))
-	if !found {
-		return ErrorSessionNotExists
-	}
-	if session.ConsumerID != consumerID {
-		return ErrorWrongSessionOwner
+	session, err := manager.findSession(consumerID, sessionID)
+	if err != nil {
+		return err
 	}
 
 	manager.publisher.Publish(sevent.AppTopicSession, session.toEvent(sevent.AcknowledgedStatus))
 	return nil
 }
 
+// Reserve reserves service capacity for the consumer in the given time slot.
+func (manager *SessionManager) Reserve(consumerID identity.Identity, start, end time.Time) (reservation.Reservation, error) {
+	if manager.service.reservations == nil {
+		return reservation.Reservation{}, errors.New("reservations are not enabled")
+	}
+	return manager.service.reservations.Reserve(manager.service.ProviderID, consumerID, manager.service.Type, start, end)
+}
+
+// PayReservation confirms the consumer reservation paid by the given exchange message.
+func (manager *SessionManager) PayReservation(consumerID identity.Identity, em crypto.ExchangeMessage) (reservation.Reservation, error) {
+	if manager.service.reservations == nil {
+		return reservation.Reservation{}, errors.New("reservations are not enabled")
+	}
+	return manager.service.reservations.Pay(consumerID, em)
+}
+
+// RememberRequest records nonce of the signed session create request, so that its replay is rejected even after provider restart.
+// Requests of consumers which do not sign control messages carry no nonce and are not recorded.
+func (manager *SessionManager) RememberRequest(consumerID identity.Identity, nonce uint64) error {
+	if manager.replay == nil || nonce == 0 {
+		return nil
+	}
+	return manager.replay.RememberRequest(consumerID.Address, strconv.FormatUint(nonce, 16))
+}
+
+// findSession returns the session owned by the consumer.
+func (manager *SessionManager) findSession(consumerID identity.Identity, sessionID string) (*Session, error) {
+	s, found := manager.sessionStorage.Find(session.ID(sessionID))
+	if !found {
+		if manager.replay != nil && manager.replay.Issued(sessionID) {
+			return nil, ErrorSessionEnded
+		}
+		return nil, ErrorSessionNotExists
+	}
+	if s.ConsumerID != consumerID {
+		return nil, ErrorWrongSessionOwner
+	}
+	return s, nil
+}
+
 func (manager *SessionManager) startSession(session *Session, prices market.Price) error {
 	trace := session.tracer.StartStage("Provider session create (start)")
 	defer session.tracer.EndStage(trace)
@@ -275,6 +427,9 @@ func (manager *SessionManager) startSession(session *Session, prices market.Pric
 	})
 
 	go manager.keepAliveLoop(session, manager.channel)
+	if manager.service.maintenance != nil {
+		go manager.maintenanceLoop(session, manager.channel, manager.service.maintenance)
+	}
 
 	return nil
 }
@@ -302,20 +457,149 @@ func (manager *SessionManager) clearStaleSession(consumerID identity.Identity, s
 	}
 }
 
-// Destroy destroys session by given sessionID
-func (manager *SessionManager) Destroy(consumerID identity.Identity, sessionID string) error {
-	session, found := manager.sessionStorage.Find(session.ID(sessionID))
-	if !found {
-		return ErrorSessionNotExists
+// Destroy destroys session by given sessionID and records the reason reported by the consumer.
+func (manager *SessionManager) Destroy(consumerID identity.Identity, sessionID string, reason session.DisconnectReason) error {
+	s, err := manager.findSession(consumerID, sessionID)
+	if err != nil {
+		return err
+	}
+
+	log.Info().Msgf("Consumer ended session %s, reason: %q", s.ID, reason)
+	s.CloseWithReason(reason)
+	return nil
+}
+
+// Reconcile compares consumer view of the session accounting with the provider one.
+// Provider view is returned so that consumer could do the same.
+func (manager *SessionManager) Reconcile(consumerID identity.Identity, sessionID string, peer session.Accounting) (session.Accounting, error) {
+	s, err := manager.findSession(consumerID, sessionID)
+	if err != nil {
+		return session.Accounting{}, err
+	}
+
+	own, ok := manager.reconciler.Accounting(s.ID)
+	if !ok {
+		return session.Accounting{}, ErrorSessionNotExists
+	}
+	if err := manager.reconciler.Reconcile(s.ID, peer); err != nil {
+		return session.Accounting{}, err
+	}
+
+	return own, nil
+}
+
+// RotateKey prepares tunnel key rotation of the session with the given consumer public key
+// and returns the new provider public key. Keys are switched once the consumer confirms the rotation.
+func (manager *SessionManager) RotateKey(consumerID identity.Identity, sessionID, consumerPublicKey string) (string, error) {
+	rotator, err := manager.keyRotator(consumerID, sessionID)
+	if err != nil {
+		return "", err
+	}
+
+	return rotator.RotateKey(sessionID, consumerPublicKey)
+}
+
+// ConfirmKeyRotation switches tunnel of the session to the keys prepared by RotateKey.
+func (manager *SessionManager) ConfirmKeyRotation(consumerID identity.Identity, sessionID, providerPublicKey string) error {
+	rotator, err := manager.keyRotator(consumerID, sessionID)
+	if err != nil {
+		return err
+	}
+
+	return rotator.ConfirmKeyRotation(sessionID, providerPublicKey)
+}
+
+func (manager *SessionManager) keyRotator(consumerID identity.Identity, sessionID string) (KeyRotator, error) {
+	if _, err := manager.findSession(consumerID, sessionID); err != nil {
+		return nil, err
+	}
+
+	rotator, ok := manager.service.Service().(KeyRotator)
+	if !ok {
+		return nil, ErrorKeyRotationUnsupported
 	}
-	if session.ConsumerID != consumerID {
-		return ErrorWrongSessionOwner
+
+	return rotator, nil
+}
+
+// Pause suspends data flow and invoicing of the session, keeping the session itself alive.
+func (manager *SessionManager) Pause(consumerID identity.Identity, sessionID string) error {
+	return manager.setPaused(consumerID, sessionID, true)
+}
+
+// Resume restores data flow and invoicing of the paused session.
+func (manager *SessionManager) Resume(consumerID identity.Identity, sessionID string) error {
+	return manager.setPaused(consumerID, sessionID, false)
+}
+
+func (manager *SessionManager) setPaused(consumerID identity.Identity, sessionID string, paused bool) error {
+	s, err := manager.findSession(consumerID, sessionID)
+	if err != nil {
+		return err
+	}
+
+	pauser, ok := manager.service.Service().(SessionPauser)
+	if !ok {
+		return ErrorSessionPauseUnsupported
+	}
+	if err := pauser.SetSessionPaused(sessionID, paused); err != nil {
+		return err
+	}
+
+	s.setPaused(paused, manager.config.MaxPause)
+	return nil
+}
+
+// Roam switches the session channel to the new consumer address after the consumer moved to another network.
+// Tunnel follows the consumer on its own, while payments are resynced to settle invoices missed in between.
+func (manager *SessionManager) Roam(consumerID identity.Identity, sessionID string) error {
+	s, err := manager.findSession(consumerID, sessionID)
+	if err != nil {
+		return err
+	}
+
+	roamer, ok := manager.channel.(roamingChannel)
+	if !ok {
+		return ErrorRoamingUnsupported
+	}
+	addr, err := roamer.AcceptRoamedPeer()
+	if err != nil {
+		return err
 	}
 
-	session.Close()
+	if addr != nil {
+		log.Info().Msgf("Consumer %s of session %s moved to another network", consumerID.Address, sessionID)
+	}
+	s.resyncPayments()
 	return nil
 }
 
+// ClaimSLABreach applies the refund agreed in the proposal SLA to the session payments
+// and returns the refunded share of the session price in percent.
+// The claim is accepted only if the breach is confirmed by provider's own measurements.
+func (manager *SessionManager) ClaimSLABreach(consumerID identity.Identity, sessionID, reason string) (uint8, error) {
+	s, err := manager.findSession(consumerID, sessionID)
+	if err != nil {
+		return 0, err
+	}
+
+	sla := s.Proposal.SLA
+	if sla == nil || sla.Refund == 0 {
+		return 0, ErrorSLANotAdvertised
+	}
+	measured := manager.slaMonitor.Breach(s.ID)
+	if measured == "" {
+		log.Warn().Msgf("Consumer %s claimed unconfirmed SLA breach of session %s: %s", consumerID.Address, sessionID, reason)
+		return 0, ErrorSLANotBreached
+	}
+	if !s.claimRefund(sla.Refund) {
+		return 0, ErrorSLARefundClaimed
+	}
+
+	log.Info().Msgf("Consumer %s claimed SLA breach of session %s: %s, measured: %s, refunding %d%%", consumerID.Address, sessionID, reason, measured, sla.Refund)
+	return sla.Refund, nil
+}
+
 func (manager *SessionManager) paymentLoop(session *Session, price market.Price) error {
 	trace := session.tracer.StartStage("Provider session create (payment)")
 	defer session.tracer.EndStage(trace)
@@ -333,6 +617,7 @@ func (manager *SessionManager) paymentLoop(session *Session, price market.Price)
 		engine.Stop()
 		return nil
 	})
+	session.setPayments(engine)
 
 	go func() {
 		err := engine.Start()
@@ -350,17 +635,17 @@ func (manager *SessionManager) paymentLoop(session *Session, price market.Price)
 	return nil
 }
 
-func (manager *SessionManager) providerService(session *Session, channel p2p.Channel) (pb.SessionResponse, error) {
-	trace := session.tracer.StartStage("Provider session create (configure)")
-	defer session.tracer.EndStage(trace)
+func (manager *SessionManager) providerService(sess *Session, channel p2p.Channel) (pb.SessionResponse, error) {
+	trace := sess.tracer.StartStage("Provider session create (configure)")
+	defer sess.tracer.EndStage(trace)
 
-	config, err := manager.service.Service().ProvideConfig(string(session.ID), session.request.GetConfig(), channel.ServiceConn())
+	config, err := manager.service.Service().ProvideConfig(string(sess.ID), sess.request.GetConfig(), channel.ServiceConn())
 	if err != nil {
-		return pb.SessionResponse{}, fmt.Errorf("cannot get provider config for session %s: %w", string(session.ID), err)
+		return pb.SessionResponse{}, fmt.Errorf("cannot get provider config for session %s: %w", string(sess.ID), err)
 	}
 
 	if config.SessionDestroyCallback != nil {
-		session.addCleanup(func() error {
+		sess.addCleanup(func() error {
 			config.SessionDestroyCallback()
 			return nil
 		})
@@ -368,13 +653,17 @@ func (manager *SessionManager) providerService(session *Session, channel p2p.Cha
 
 	data, err := json.Marshal(config.SessionServiceConfig)
 	if err != nil {
-		return pb.SessionResponse{}, fmt.Errorf("cannot pack session %s service config: %w", string(session.ID), err)
+		return pb.SessionResponse{}, fmt.Errorf("cannot pack session %s service config: %w", string(sess.ID), err)
 	}
 
 	return pb.SessionResponse{
-		ID:          string(session.ID),
-		PaymentInfo: "v3",
-		Config:      data,
+		ID:                    string(sess.ID),
+		PaymentInfo:           "v3",
+		Config:                data,
+		ProtocolVersion:       sess.Protocol.Version,
+		Capabilities:          uint64(sess.Protocol.Capabilities),
+		NodeVersion:           metadata.VersionAsString(),
+		SupportedCapabilities: uint64(session.LocalCapabilities),
 	}, nil
 }
 
@@ -428,3 +717,66 @@ func (manager *SessionManager) sendKeepAlivePing(channel p2p.Channel, sessionID
 
 	return err
 }
+
+// maintenanceLoop notifies the consumer about every maintenance window the notice duration before it starts,
+// or at once if the window is closer than that. Each window is announced once per session.
+func (manager *SessionManager) maintenanceLoop(sess *Session, channel p2p.ChannelSender, schedule MaintenanceSchedule) {
+	var announced market.MaintenanceWindow
+	for {
+		changed := schedule.Changed()
+		now := time.Now()
+
+		var wait time.Duration
+		window, ok := schedule.Next(now)
+		switch {
+		case !ok:
+			wait = -1
+		case window == announced:
+			wait = window.End.Sub(now)
+		default:
+			wait = window.Start.Add(-schedule.Notice()).Sub(now)
+			if wait <= 0 {
+				if err := manager.sendMaintenance(channel, sess, window); err != nil {
+					log.Warn().Err(err).Msgf("Failed to notify consumer about maintenance. SessionID=%s", sess.ID)
+				}
+				announced = window
+				continue
+			}
+		}
+
+		var timer *time.Timer
+		var timeout <-chan time.Time
+		if wait >= 0 {
+			timer = time.NewTimer(wait)
+			timeout = timer.C
+		}
+		select {
+		case <-sess.Done():
+		case <-changed:
+		case <-timeout:
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+		select {
+		case <-sess.Done():
+			return
+		default:
+		}
+	}
+}
+
+func (manager *SessionManager) sendMaintenance(channel p2p.ChannelSender, sess *Session, window market.MaintenanceWindow) error {
+	ctx, cancel := context.WithTimeout(context.Background(), manager.config.KeepAlive.SendTimeout)
+	defer cancel()
+
+	msg := &pb.SessionMaintenance{
+		ConsumerID: sess.ConsumerID.Address,
+		SessionID:  string(sess.ID),
+		Start:      window.Start.Unix(),
+		End:        window.End.Unix(),
+	}
+	log.Debug().Msgf("Sending P2P message to %q: %s", p2p.TopicSessionMaintenance, msg.String())
+	_, err := channel.Send(ctx, p2p.TopicSessionMaintenance, p2p.ProtoMessage(msg))
+	return err
+}
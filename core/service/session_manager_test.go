@@ -25,6 +25,7 @@ import (
 	"net"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"testing"
 	"time"
 
@@ -33,12 +34,15 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/mysteriumnetwork/node/core/maintenance"
+	"github.com/mysteriumnetwork/node/core/service/reservation"
 	"github.com/mysteriumnetwork/node/core/service/servicestate"
 	"github.com/mysteriumnetwork/node/identity"
 	"github.com/mysteriumnetwork/node/market"
 	"github.com/mysteriumnetwork/node/mocks"
 	"github.com/mysteriumnetwork/node/p2p"
 	"github.com/mysteriumnetwork/node/pb"
+	"github.com/mysteriumnetwork/node/session"
 	sessionEvent "github.com/mysteriumnetwork/node/session/event"
 	"github.com/mysteriumnetwork/node/trace"
 	"github.com/mysteriumnetwork/node/utils/reftracker"
@@ -82,6 +86,49 @@ func (m mockBalanceTracker) WaitFirstInvoice(time.Duration) error {
 	return m.firstPaymentError
 }
 
+func (m mockBalanceTracker) Pause() {
+}
+
+func (m mockBalanceTracker) Resume() {
+}
+
+func (m mockBalanceTracker) Refund(uint8) {
+}
+
+type mockSLAMonitor struct {
+	breach string
+}
+
+func (m *mockSLAMonitor) Breach(session.ID) string {
+	return m.breach
+}
+
+type mockReplayGuard struct {
+	requests map[string]bool
+	sessions map[string]bool
+}
+
+func newMockReplayGuard() *mockReplayGuard {
+	return &mockReplayGuard{requests: make(map[string]bool), sessions: make(map[string]bool)}
+}
+
+func (m *mockReplayGuard) RememberRequest(consumerID, key string) error {
+	if m.requests[consumerID+"/"+key] {
+		return errors.New("session create request is replayed")
+	}
+	m.requests[consumerID+"/"+key] = true
+	return nil
+}
+
+func (m *mockReplayGuard) RememberSession(sessionID string) error {
+	m.sessions[sessionID] = true
+	return nil
+}
+
+func (m *mockReplayGuard) Issued(sessionID string) bool {
+	return m.sessions[sessionID]
+}
+
 type mockP2PChannel struct {
 	tracer *trace.Tracer
 }
@@ -294,6 +341,231 @@ func TestManager_AcknowledgeSession_RejectsBadClient(t *testing.T) {
 	assert.Exactly(t, ErrorWrongSessionOwner, err)
 }
 
+func TestManager_RejectsReplayedRequestsAndEndedSessions(t *testing.T) {
+	publisher := mocks.NewEventBus()
+	sessionStore := NewSessionPool(mocks.NewEventBus())
+	manager := newManager(currentService, sessionStore, publisher, &mockBalanceTracker{}, true)
+	replay := newMockReplayGuard()
+	manager.replay = replay
+
+	assert.NoError(t, manager.RememberRequest(consumerID, 0))
+	assert.NoError(t, manager.RememberRequest(consumerID, 0))
+	assert.Empty(t, replay.requests)
+
+	assert.NoError(t, manager.RememberRequest(consumerID, 42))
+	assert.Error(t, manager.RememberRequest(consumerID, 42))
+
+	session, err := manager.Start(&pb.SessionRequest{
+		Consumer: &pb.ConsumerInfo{
+			Id:       consumerID.Address,
+			HermesID: hermesID.String(),
+			Pricing: &pb.Pricing{
+				PerGib:  big.NewInt(1).Bytes(),
+				PerHour: big.NewInt(1).Bytes(),
+			},
+		},
+		ProposalID: int64(currentProposalID),
+	})
+	assert.NoError(t, err)
+	assert.True(t, replay.Issued(session.ID))
+
+	// Session issued before the restart is not in the pool anymore.
+	replay.sessions["issued-before-restart"] = true
+	assert.Exactly(t, ErrorSessionEnded, manager.Acknowledge(consumerID, "issued-before-restart"))
+	assert.Exactly(t, ErrorSessionEnded, manager.Destroy(consumerID, "issued-before-restart", ""))
+	assert.Exactly(t, ErrorSessionNotExists, manager.Acknowledge(consumerID, "unknown"))
+}
+
+func TestManager_PauseSession_RejectsUnsupportedService(t *testing.T) {
+	publisher := mocks.NewEventBus()
+	sessionStore := NewSessionPool(mocks.NewEventBus())
+	manager := newManager(currentService, sessionStore, publisher, &mockBalanceTracker{}, true)
+
+	assert.Exactly(t, ErrorSessionNotExists, manager.Pause(consumerID, ""))
+
+	session, err := manager.Start(&pb.SessionRequest{
+		Consumer: &pb.ConsumerInfo{
+			Id:       consumerID.Address,
+			HermesID: hermesID.String(),
+			Pricing: &pb.Pricing{
+				PerGib:  big.NewInt(1).Bytes(),
+				PerHour: big.NewInt(1).Bytes(),
+			},
+		},
+		ProposalID: int64(currentProposalID),
+	})
+	assert.Nil(t, err)
+
+	assert.Exactly(t, ErrorWrongSessionOwner, manager.Pause(identity.FromAddress("some other id"), string(session.ID)))
+	assert.Exactly(t, ErrorSessionPauseUnsupported, manager.Pause(consumerID, string(session.ID)))
+
+	assert.False(t, sessionStore.GetAll()[0].Paused())
+}
+
+func TestManager_PauseAndResumeSession(t *testing.T) {
+	publisher := mocks.NewEventBus()
+	sessionStore := NewSessionPool(mocks.NewEventBus())
+	pauser := &mockPausableService{paused: map[string]bool{}}
+	service := NewInstance(
+		identity.FromAddress(currentProposal.ProviderID),
+		currentProposal.ServiceType,
+		struct{}{},
+		currentProposal,
+		servicestate.Running,
+		pauser,
+		localcopy.NewRepository(),
+		&mockDiscovery{},
+	)
+	payments := &mockPausablePayments{}
+	manager := newManager(service, sessionStore, publisher, payments, true)
+
+	session, err := manager.Start(&pb.SessionRequest{
+		Consumer: &pb.ConsumerInfo{
+			Id:       consumerID.Address,
+			HermesID: hermesID.String(),
+			Pricing: &pb.Pricing{
+				PerGib:  big.NewInt(1).Bytes(),
+				PerHour: big.NewInt(1).Bytes(),
+			},
+		},
+		ProposalID: int64(currentProposalID),
+	})
+	assert.Nil(t, err)
+	sessionID := string(session.ID)
+
+	assert.NoError(t, manager.Pause(consumerID, sessionID))
+	assert.True(t, pauser.isPaused(sessionID))
+	assert.True(t, sessionStore.GetAll()[0].Paused())
+	assert.Equal(t, 1, payments.pauses())
+
+	assert.NoError(t, manager.Resume(consumerID, sessionID))
+	assert.False(t, pauser.isPaused(sessionID))
+	assert.False(t, sessionStore.GetAll()[0].Paused())
+	assert.Equal(t, 1, payments.resumes())
+
+	// Session paused for too long is ended.
+	manager.config.MaxPause = 10 * time.Millisecond
+	assert.NoError(t, manager.Pause(consumerID, sessionID))
+	assert.Eventually(t, func() bool { return len(sessionStore.GetAll()) == 0 }, time.Second, 10*time.Millisecond)
+}
+
+type mockPausableService struct {
+	mockService
+	lock   sync.Mutex
+	paused map[string]bool
+}
+
+func (m *mockPausableService) SetSessionPaused(sessionID string, paused bool) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.paused[sessionID] = paused
+	return nil
+}
+
+func (m *mockPausableService) isPaused(sessionID string) bool {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.paused[sessionID]
+}
+
+type mockPausablePayments struct {
+	mockBalanceTracker
+	lock        sync.Mutex
+	pauseCount  int
+	resumeCount int
+}
+
+func (m *mockPausablePayments) Pause() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.pauseCount++
+}
+
+func (m *mockPausablePayments) Resume() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.resumeCount++
+}
+
+func (m *mockPausablePayments) pauses() int {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.pauseCount
+}
+
+func (m *mockPausablePayments) resumes() int {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.resumeCount
+}
+
+func TestManager_RoamSession(t *testing.T) {
+	sessionStore := NewSessionPool(mocks.NewEventBus())
+	payments := &mockResyncingPayments{}
+	manager := newManager(currentService, sessionStore, mocks.NewEventBus(), payments, true)
+
+	session, err := manager.Start(&pb.SessionRequest{
+		Consumer: &pb.ConsumerInfo{
+			Id:       consumerID.Address,
+			HermesID: hermesID.String(),
+			Pricing: &pb.Pricing{
+				PerGib:  big.NewInt(1).Bytes(),
+				PerHour: big.NewInt(1).Bytes(),
+			},
+		},
+		ProposalID: int64(currentProposalID),
+	})
+	assert.Nil(t, err)
+	sessionID := string(session.ID)
+
+	assert.Exactly(t, ErrorRoamingUnsupported, manager.Roam(consumerID, sessionID))
+	assert.Zero(t, payments.resyncs())
+
+	ch := &mockRoamingChannel{addr: &net.UDPAddr{IP: net.ParseIP("10.0.0.2"), Port: 1234}}
+	manager.channel = ch
+	assert.Exactly(t, ErrorWrongSessionOwner, manager.Roam(identity.FromAddress("some other id"), sessionID))
+	assert.NoError(t, manager.Roam(consumerID, sessionID))
+	assert.Equal(t, 1, ch.accepted)
+	assert.Equal(t, 1, payments.resyncs())
+
+	ch.err = p2p.ErrRoamingUnverified
+	assert.ErrorIs(t, manager.Roam(consumerID, sessionID), p2p.ErrRoamingUnverified)
+	assert.Equal(t, 1, payments.resyncs())
+}
+
+type mockRoamingChannel struct {
+	mockP2PChannel
+	addr     *net.UDPAddr
+	err      error
+	accepted int
+}
+
+func (m *mockRoamingChannel) AcceptRoamedPeer() (*net.UDPAddr, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.accepted++
+	return m.addr, nil
+}
+
+type mockResyncingPayments struct {
+	mockBalanceTracker
+	lock        sync.Mutex
+	resyncCount int
+}
+
+func (m *mockResyncingPayments) Resync() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.resyncCount++
+}
+
+func (m *mockResyncingPayments) resyncs() int {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.resyncCount
+}
+
 func TestManager_AcknowledgeSession_PublishesEvent(t *testing.T) {
 	publisher := mocks.NewEventBus()
 
@@ -321,6 +593,143 @@ func TestManager_AcknowledgeSession_PublishesEvent(t *testing.T) {
 	}, 2*time.Second, 10*time.Millisecond)
 }
 
+func TestManager_Destroy_RecordsDisconnectReason(t *testing.T) {
+	publisher := mocks.NewEventBus()
+
+	sessionStore := NewSessionPool(publisher)
+	sessionInstance, _ := NewSession(
+		currentService,
+		&pb.SessionRequest{Consumer: &pb.ConsumerInfo{Id: consumerID.Address}},
+		trace.NewTracer(""),
+	)
+	sessionStore.Add(sessionInstance)
+	sessionInstance.addCleanup(func() error {
+		sessionStore.Remove(sessionInstance.ID)
+		return nil
+	})
+
+	manager := newManager(currentService, sessionStore, publisher, &mockBalanceTracker{}, true)
+
+	err := manager.Destroy(consumerID, string(sessionInstance.ID), session.DisconnectReasonLowBalance)
+	assert.NoError(t, err)
+	assert.Equal(t, session.DisconnectReasonLowBalance, sessionInstance.DisconnectReason())
+	assert.Eventually(t, func() bool {
+		for _, v := range publisher.GetEventHistory() {
+			if v.Topic != sessionEvent.AppTopicSession {
+				continue
+			}
+			e := v.Event.(sessionEvent.AppEventSession)
+			if e.Status == sessionEvent.RemovedStatus {
+				return e.Session.DisconnectReason == session.DisconnectReasonLowBalance
+			}
+		}
+		return false
+	}, 2*time.Second, 10*time.Millisecond)
+
+	// Reason of the already ended session is not overwritten.
+	sessionInstance.CloseWithReason(session.DisconnectReasonShutdown)
+	assert.Equal(t, session.DisconnectReasonLowBalance, sessionInstance.DisconnectReason())
+}
+
+func TestNewSession_RecordsConsumerPeer(t *testing.T) {
+	sess, err := NewSession(
+		currentService,
+		&pb.SessionRequest{
+			Consumer:        &pb.ConsumerInfo{Id: consumerID.Address},
+			ProtocolVersion: session.ProtocolVersion,
+			Capabilities:    uint64(session.CapabilityReconciliation | 1<<10),
+			NodeVersion:     "1.30.0",
+		},
+		trace.NewTracer(""),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, session.Peer{Version: "1.30.0", Capabilities: session.CapabilityReconciliation | 1<<10}, sess.Peer)
+	assert.Equal(t, session.CapabilityReconciliation, sess.Protocol.Capabilities)
+	assert.Equal(t, sess.Peer, sess.toEvent(sessionEvent.CreatedStatus).Session.Peer)
+
+	legacy, err := NewSession(currentService, &pb.SessionRequest{Consumer: &pb.ConsumerInfo{Id: consumerID.Address}}, trace.NewTracer(""))
+	assert.NoError(t, err)
+	assert.Equal(t, session.Peer{}, legacy.Peer)
+}
+
+func TestManager_ClaimSLABreach_RequiresMeasuredBreach(t *testing.T) {
+	publisher := mocks.NewEventBus()
+	sessionStore := NewSessionPool(publisher)
+	sess, _ := NewSession(
+		currentService,
+		&pb.SessionRequest{Consumer: &pb.ConsumerInfo{Id: consumerID.Address}},
+		trace.NewTracer(""),
+	)
+	sess.Proposal.SLA = &market.SLA{MinThroughput: 1_000_000, Refund: 10}
+	sessionStore.Add(sess)
+
+	monitor := &mockSLAMonitor{}
+	manager := newManager(currentService, sessionStore, publisher, &mockBalanceTracker{}, true)
+	manager.slaMonitor = monitor
+
+	_, err := manager.ClaimSLABreach(identity.FromAddress("some other id"), string(sess.ID), "slow")
+	assert.Exactly(t, ErrorWrongSessionOwner, err)
+
+	_, err = manager.ClaimSLABreach(consumerID, string(sess.ID), "slow")
+	assert.Exactly(t, ErrorSLANotBreached, err)
+
+	monitor.breach = "throughput 400000 bps is below 1000000 bps"
+	refund, err := manager.ClaimSLABreach(consumerID, string(sess.ID), "slow")
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(10), refund)
+
+	_, err = manager.ClaimSLABreach(consumerID, string(sess.ID), "slow")
+	assert.Exactly(t, ErrorSLARefundClaimed, err)
+}
+
+func TestManager_MaintenanceLoop_AnnouncesWindows(t *testing.T) {
+	publisher := mocks.NewEventBus()
+	manager := newManager(currentService, NewSessionPool(publisher), publisher, &mockBalanceTracker{}, true)
+	sess, _ := NewSession(
+		currentService,
+		&pb.SessionRequest{Consumer: &pb.ConsumerInfo{Id: consumerID.Address}},
+		trace.NewTracer(""),
+	)
+
+	start := time.Now().Add(time.Hour).Truncate(time.Second)
+	window := market.MaintenanceWindow{Start: start, End: start.Add(time.Hour)}
+	schedule := maintenance.NewSchedule([]market.MaintenanceWindow{window}, 2*time.Hour)
+	sender := &mockMaintenanceSender{sent: make(chan *pb.SessionMaintenance, 10)}
+
+	done := make(chan struct{})
+	go func() {
+		manager.maintenanceLoop(sess, sender, schedule)
+		close(done)
+	}()
+
+	msg := <-sender.sent
+	assert.Equal(t, string(sess.ID), msg.SessionID)
+	assert.Equal(t, start.Unix(), msg.Start)
+	assert.Equal(t, start.Add(time.Hour).Unix(), msg.End)
+
+	later := market.MaintenanceWindow{Start: start.Add(-30 * time.Minute), End: start}
+	schedule.Set([]market.MaintenanceWindow{later})
+	msg = <-sender.sent
+	assert.Equal(t, later.Start.Unix(), msg.Start)
+
+	sess.Close()
+	<-done
+	assert.Len(t, sender.sent, 0)
+}
+
+type mockMaintenanceSender struct {
+	sent chan *pb.SessionMaintenance
+}
+
+func (m *mockMaintenanceSender) Send(_ context.Context, _ string, msg *p2p.Message) (*p2p.Message, error) {
+	var maintenance pb.SessionMaintenance
+	if err := msg.UnmarshalProto(&maintenance); err != nil {
+		return nil, err
+	}
+	m.sent <- &maintenance
+	return nil, nil
+}
+
 func newManager(service *Instance, sessions *SessionPool, publisher publisher, paymentEngine PaymentEngine, isPriceValid bool) *SessionManager {
 	ch := &mockP2PChannel{tracer: trace.NewTracer("Provider connect")}
 	m := NewSessionManager(
@@ -335,6 +744,9 @@ func newManager(service *Instance, sessions *SessionPool, publisher publisher, p
 		&mockPriceValidator{
 			toReturn: isPriceValid,
 		},
+		nil,
+		&mockSLAMonitor{},
+		nil,
 	)
 	reftracker.Singleton().Put("channel:"+ch.ID(), 10*time.Second, func() { ch.Close() })
 	return m
@@ -360,6 +772,71 @@ func TestManager_Start_RejectsInvalidPricing(t *testing.T) {
 	assert.Equal(t, "consumer asking for invalid price", err.Error())
 }
 
+func TestManager_Start_RejectsSessionOverReservedCapacity(t *testing.T) {
+	service := NewInstance(
+		identity.FromAddress(currentProposal.ProviderID),
+		currentProposal.ServiceType,
+		struct{}{},
+		currentProposal,
+		servicestate.Running,
+		&mockService{},
+		localcopy.NewRepository(),
+		&mockDiscovery{},
+	)
+	service.reservations = reservation.NewBook(1, nil, 1, nil)
+	publisher := mocks.NewEventBus()
+	sessionStore := NewSessionPool(publisher)
+	manager := newManager(service, sessionStore, publisher, &mockBalanceTracker{}, true)
+
+	_, err := service.reservations.Reserve(service.ProviderID, identity.FromAddress("0x2"), service.Type, time.Now(), time.Now().Add(time.Hour))
+	assert.NoError(t, err)
+
+	_, err = manager.Start(&pb.SessionRequest{
+		Consumer: &pb.ConsumerInfo{
+			Id:       consumerID.Address,
+			HermesID: hermesID.String(),
+			Pricing: &pb.Pricing{
+				PerGib:  big.NewInt(1).Bytes(),
+				PerHour: big.NewInt(1).Bytes(),
+			},
+		},
+		ProposalID: int64(currentProposalID),
+	})
+	assert.ErrorIs(t, err, reservation.ErrNoCapacity)
+	assert.Empty(t, sessionStore.GetAll())
+}
+
+func TestManager_Start_RejectsSessionWhenBusy(t *testing.T) {
+	publisher := mocks.NewEventBus()
+	sessionStore := NewSessionPool(publisher)
+	manager := newManager(currentService, sessionStore, publisher, &mockBalanceTracker{}, true)
+	manager.SetLoadShedder(&mockLoadShedder{err: &p2p.BusyError{Reason: "too many sessions", RetryAfter: time.Minute}})
+
+	_, err := manager.Start(&pb.SessionRequest{
+		Consumer: &pb.ConsumerInfo{
+			Id:       consumerID.Address,
+			HermesID: hermesID.String(),
+			Pricing: &pb.Pricing{
+				PerGib:  big.NewInt(1).Bytes(),
+				PerHour: big.NewInt(1).Bytes(),
+			},
+		},
+		ProposalID: int64(currentProposalID),
+	})
+	var busy *p2p.BusyError
+	assert.ErrorAs(t, err, &busy)
+	assert.Equal(t, time.Minute, busy.RetryAfter)
+	assert.Empty(t, sessionStore.GetAll())
+}
+
+type mockLoadShedder struct {
+	err error
+}
+
+func (m *mockLoadShedder) Admit(_ int) error {
+	return m.err
+}
+
 type mockPriceValidator struct {
 	toReturn bool
 }
@@ -25,6 +25,7 @@ import (
 	"github.com/mysteriumnetwork/node/identity"
 	"github.com/mysteriumnetwork/node/p2p"
 	"github.com/mysteriumnetwork/node/pb"
+	"github.com/mysteriumnetwork/node/session"
 	"github.com/mysteriumnetwork/node/session/connectivity"
 	"github.com/mysteriumnetwork/payments/crypto"
 	"github.com/rs/zerolog/log"
@@ -45,6 +46,10 @@ func subscribeSessionCreate(mng *SessionManager, ch p2p.ChannelHandler) {
 
 		log.Debug().Msgf("Received P2P message for %q: %s", p2p.TopicSessionCreate, request.String())
 
+		if err := mng.RememberRequest(c.PeerID(), c.Request().Nonce); err != nil {
+			return fmt.Errorf("cannot start session: %w", err)
+		}
+
 		response, err := mng.Start(&request)
 		if err != nil {
 			return fmt.Errorf("cannot start session: %s: %w", response.ID, err)
@@ -101,7 +106,9 @@ func subscribeSessionDestroy(mng *SessionManager, ch p2p.ChannelHandler) {
 			consumerID := identity.FromAddress(si.GetConsumerID())
 			sessionID := si.GetSessionID()
 
-			err := mng.Destroy(consumerID, sessionID)
+			reason := session.ParseDisconnectReason(si.GetReason())
+
+			err := mng.Destroy(consumerID, sessionID, reason)
 			if err != nil {
 				log.Err(err).Msgf("Could not destroy session %s: %v", sessionID, err)
 			}
@@ -111,6 +118,181 @@ func subscribeSessionDestroy(mng *SessionManager, ch p2p.ChannelHandler) {
 	})
 }
 
+func subscribeSessionReconcile(mng *SessionManager, ch p2p.ChannelHandler) {
+	ch.Handle(p2p.TopicSessionReconcile, func(c p2p.Context) error {
+		var sr pb.SessionReconciliation
+		if err := c.Request().UnmarshalProto(&sr); err != nil {
+			return err
+		}
+		if identity.FromAddress(sr.GetConsumerID()) != c.PeerID() {
+			return fmt.Errorf("wrong consumer identity in session reconcile request. Expected: %s, got: %s",
+				c.PeerID().ToCommonAddress(),
+				identity.FromAddress(sr.GetConsumerID()),
+			)
+		}
+
+		log.Debug().Msgf("Received P2P message for %q: %s", p2p.TopicSessionReconcile, sr.String())
+
+		tokens, ok := new(big.Int).SetString(sr.GetTokens(), bigIntBase)
+		if !ok {
+			return fmt.Errorf("could not unmarshal field tokens of value %v", sr.GetTokens())
+		}
+
+		own, err := mng.Reconcile(c.PeerID(), sr.GetSessionID(), session.Accounting{
+			DataSent:     sr.GetDataSent(),
+			DataReceived: sr.GetDataReceived(),
+			Tokens:       tokens,
+		})
+		if err != nil {
+			return fmt.Errorf("cannot reconcile session %s: %w", sr.GetSessionID(), err)
+		}
+
+		ownTokens := "0"
+		if own.Tokens != nil {
+			ownTokens = own.Tokens.String()
+		}
+		return c.OkWithReply(p2p.ProtoMessage(&pb.SessionReconciliation{
+			ConsumerID:   sr.GetConsumerID(),
+			SessionID:    sr.GetSessionID(),
+			DataSent:     own.DataSent,
+			DataReceived: own.DataReceived,
+			Tokens:       ownTokens,
+		}))
+	})
+}
+
+func subscribeSessionKeyRotate(mng *SessionManager, ch p2p.ChannelHandler) {
+	ch.Handle(p2p.TopicSessionKeyRotate, func(c p2p.Context) error {
+		var kr pb.SessionKeyRotation
+		if err := c.Request().UnmarshalProto(&kr); err != nil {
+			return err
+		}
+		if identity.FromAddress(kr.GetConsumerID()) != c.PeerID() {
+			return fmt.Errorf("wrong consumer identity in session key rotation request. Expected: %s, got: %s",
+				c.PeerID().ToCommonAddress(),
+				identity.FromAddress(kr.GetConsumerID()),
+			)
+		}
+
+		log.Debug().Msgf("Received P2P message for %q: session %s", p2p.TopicSessionKeyRotate, kr.GetSessionID())
+
+		publicKey, err := mng.RotateKey(c.PeerID(), kr.GetSessionID(), kr.GetPublicKey())
+		if err != nil {
+			return fmt.Errorf("cannot rotate keys of session %s: %w", kr.GetSessionID(), err)
+		}
+
+		return c.OkWithReply(p2p.ProtoMessage(&pb.SessionKeyRotation{
+			ConsumerID: kr.GetConsumerID(),
+			SessionID:  kr.GetSessionID(),
+			PublicKey:  publicKey,
+		}))
+	})
+}
+
+func subscribeSessionPause(mng *SessionManager, ch p2p.ChannelHandler) {
+	handle := func(topic string, apply func(consumerID identity.Identity, sessionID string) error) {
+		ch.Handle(topic, func(c p2p.Context) error {
+			var si pb.SessionInfo
+			if err := c.Request().UnmarshalProto(&si); err != nil {
+				return err
+			}
+			if identity.FromAddress(si.GetConsumerID()) != c.PeerID() {
+				return fmt.Errorf("wrong consumer identity in %q request. Expected: %s, got: %s",
+					topic,
+					c.PeerID().ToCommonAddress(),
+					identity.FromAddress(si.GetConsumerID()),
+				)
+			}
+
+			log.Debug().Msgf("Received P2P message for %q: %s", topic, si.String())
+
+			if err := apply(c.PeerID(), si.GetSessionID()); err != nil {
+				return fmt.Errorf("cannot handle %q of session %s: %w", topic, si.GetSessionID(), err)
+			}
+
+			return c.OK()
+		})
+	}
+
+	handle(p2p.TopicSessionPause, mng.Pause)
+	handle(p2p.TopicSessionResume, mng.Resume)
+}
+
+func subscribeSessionRoam(mng *SessionManager, ch p2p.ChannelHandler) {
+	ch.Handle(p2p.TopicSessionRoam, func(c p2p.Context) error {
+		var si pb.SessionInfo
+		if err := c.Request().UnmarshalProto(&si); err != nil {
+			return err
+		}
+		if identity.FromAddress(si.GetConsumerID()) != c.PeerID() {
+			return fmt.Errorf("wrong consumer identity in session roam request. Expected: %s, got: %s",
+				c.PeerID().ToCommonAddress(),
+				identity.FromAddress(si.GetConsumerID()),
+			)
+		}
+
+		log.Debug().Msgf("Received P2P message for %q: %s", p2p.TopicSessionRoam, si.String())
+
+		if err := mng.Roam(c.PeerID(), si.GetSessionID()); err != nil {
+			return fmt.Errorf("cannot roam session %s: %w", si.GetSessionID(), err)
+		}
+
+		return c.OK()
+	})
+}
+
+func subscribeSessionSLABreach(mng *SessionManager, ch p2p.ChannelHandler) {
+	ch.Handle(p2p.TopicSessionSLABreach, func(c p2p.Context) error {
+		var sb pb.SessionSLABreach
+		if err := c.Request().UnmarshalProto(&sb); err != nil {
+			return err
+		}
+		if identity.FromAddress(sb.GetConsumerID()) != c.PeerID() {
+			return fmt.Errorf("wrong consumer identity in session SLA breach request. Expected: %s, got: %s",
+				c.PeerID().ToCommonAddress(),
+				identity.FromAddress(sb.GetConsumerID()),
+			)
+		}
+
+		log.Debug().Msgf("Received P2P message for %q: %s", p2p.TopicSessionSLABreach, sb.String())
+
+		refund, err := mng.ClaimSLABreach(c.PeerID(), sb.GetSessionID(), sb.GetReason())
+		if err != nil {
+			return fmt.Errorf("cannot refund SLA breach of session %s: %w", sb.GetSessionID(), err)
+		}
+
+		return c.OkWithReply(p2p.ProtoMessage(&pb.SessionSLABreach{
+			ConsumerID: sb.GetConsumerID(),
+			SessionID:  sb.GetSessionID(),
+			Reason:     sb.GetReason(),
+			Refund:     uint32(refund),
+		}))
+	})
+}
+
+func subscribeSessionKeyRotateAck(mng *SessionManager, ch p2p.ChannelHandler) {
+	ch.Handle(p2p.TopicSessionKeyRotateAck, func(c p2p.Context) error {
+		var kr pb.SessionKeyRotation
+		if err := c.Request().UnmarshalProto(&kr); err != nil {
+			return err
+		}
+		if identity.FromAddress(kr.GetConsumerID()) != c.PeerID() {
+			return fmt.Errorf("wrong consumer identity in session key rotation acknowledgement. Expected: %s, got: %s",
+				c.PeerID().ToCommonAddress(),
+				identity.FromAddress(kr.GetConsumerID()),
+			)
+		}
+
+		log.Debug().Msgf("Received P2P message for %q: session %s", p2p.TopicSessionKeyRotateAck, kr.GetSessionID())
+
+		if err := mng.ConfirmKeyRotation(c.PeerID(), kr.GetSessionID(), kr.GetPublicKey()); err != nil {
+			return fmt.Errorf("cannot confirm key rotation of session %s: %w", kr.GetSessionID(), err)
+		}
+
+		return c.OK()
+	})
+}
+
 func subscribeSessionAcknowledge(mng *SessionManager, ch p2p.ChannelHandler) {
 	ch.Handle(p2p.TopicSessionAcknowledge, func(c p2p.Context) error {
 		var si pb.SessionInfo
@@ -147,44 +329,115 @@ func subscribeSessionPayments(mng *SessionManager, ch p2p.ChannelHandler) {
 		}
 		log.Debug().Msgf("Received P2P message for %q: %s", p2p.TopicPaymentMessage, msg.String())
 
-		amount, ok := new(big.Int).SetString(msg.GetPromise().GetAmount(), bigIntBase)
-		if !ok {
-			return fmt.Errorf("could not unmarshal field amount of value %v", amount)
+		em, err := exchangeMessageFromProto(&msg)
+		if err != nil {
+			return err
 		}
 
-		fee, ok := new(big.Int).SetString(msg.GetPromise().GetFee(), bigIntBase)
-		if !ok {
-			return fmt.Errorf("could not unmarshal field fee of value %v", fee)
+		mng.paymentEngineChan <- em
+
+		return nil
+	})
+}
+
+func subscribeSessionReserve(mng *SessionManager, ch p2p.ChannelHandler) {
+	ch.Handle(p2p.TopicSessionReserve, func(c p2p.Context) error {
+		var sr pb.SessionReservation
+		if err := c.Request().UnmarshalProto(&sr); err != nil {
+			return err
+		}
+		if identity.FromAddress(sr.GetConsumerID()) != c.PeerID() {
+			return fmt.Errorf("wrong consumer identity in session reservation request. Expected: %s, got: %s",
+				c.PeerID().ToCommonAddress(),
+				identity.FromAddress(sr.GetConsumerID()),
+			)
 		}
 
-		agreementID, ok := new(big.Int).SetString(msg.GetAgreementID(), bigIntBase)
-		if !ok {
-			return fmt.Errorf("could not unmarshal field agreementID of value %v", agreementID)
+		log.Debug().Msgf("Received P2P message for %q: %s", p2p.TopicSessionReserve, sr.String())
+
+		res, err := mng.Reserve(c.PeerID(), time.Unix(sr.GetStart(), 0), time.Unix(sr.GetEnd(), 0))
+		if err != nil {
+			return fmt.Errorf("cannot reserve capacity: %w", err)
 		}
 
-		agreementTotal, ok := new(big.Int).SetString(msg.GetAgreementTotal(), bigIntBase)
-		if !ok {
-			return fmt.Errorf("could not unmarshal field agreementTotal of value %v", agreementTotal)
-		}
-
-		mng.paymentEngineChan <- crypto.ExchangeMessage{
-			Promise: crypto.Promise{
-				ChannelID: msg.GetPromise().GetChannelID(),
-				Amount:    amount,
-				Fee:       fee,
-				Hashlock:  msg.GetPromise().GetHashlock(),
-				R:         msg.GetPromise().GetR(),
-				Signature: msg.GetPromise().GetSignature(),
-				ChainID:   msg.GetPromise().GetChainID(),
-			},
-			AgreementID:    agreementID,
-			AgreementTotal: agreementTotal,
-			Provider:       msg.GetProvider(),
-			Signature:      msg.GetSignature(),
-			HermesID:       msg.GetHermesID(),
-			ChainID:        msg.GetChainID(),
+		reply := &pb.SessionReservation{
+			ConsumerID:    sr.GetConsumerID(),
+			ReservationID: res.ID,
+			Start:         res.Start.Unix(),
+			End:           res.End.Unix(),
+		}
+		if res.Fee.Sign() > 0 {
+			reply.Fee = res.Fee.String()
+			reply.AgreementID = res.Invoice.AgreementID.String()
+			reply.Hashlock = res.Invoice.Hashlock
+			reply.ChainID = res.Invoice.ChainID
 		}
+		return c.OkWithReply(p2p.ProtoMessage(reply))
+	})
 
-		return nil
+	ch.Handle(p2p.TopicSessionReservePayment, func(c p2p.Context) error {
+		var msg pb.ExchangeMessage
+		if err := c.Request().UnmarshalProto(&msg); err != nil {
+			return fmt.Errorf("could not unmarshal exchange message proto: %w", err)
+		}
+		log.Debug().Msgf("Received P2P message for %q: %s", p2p.TopicSessionReservePayment, msg.String())
+
+		em, err := exchangeMessageFromProto(&msg)
+		if err != nil {
+			return err
+		}
+
+		res, err := mng.PayReservation(c.PeerID(), em)
+		if err != nil {
+			return fmt.Errorf("cannot pay reservation: %w", err)
+		}
+
+		return c.OkWithReply(p2p.ProtoMessage(&pb.SessionReservation{
+			ConsumerID:    c.PeerID().Address,
+			ReservationID: res.ID,
+			Start:         res.Start.Unix(),
+			End:           res.End.Unix(),
+			Fee:           res.Fee.String(),
+		}))
 	})
 }
+
+func exchangeMessageFromProto(msg *pb.ExchangeMessage) (crypto.ExchangeMessage, error) {
+	amount, ok := new(big.Int).SetString(msg.GetPromise().GetAmount(), bigIntBase)
+	if !ok {
+		return crypto.ExchangeMessage{}, fmt.Errorf("could not unmarshal field amount of value %v", amount)
+	}
+
+	fee, ok := new(big.Int).SetString(msg.GetPromise().GetFee(), bigIntBase)
+	if !ok {
+		return crypto.ExchangeMessage{}, fmt.Errorf("could not unmarshal field fee of value %v", fee)
+	}
+
+	agreementID, ok := new(big.Int).SetString(msg.GetAgreementID(), bigIntBase)
+	if !ok {
+		return crypto.ExchangeMessage{}, fmt.Errorf("could not unmarshal field agreementID of value %v", agreementID)
+	}
+
+	agreementTotal, ok := new(big.Int).SetString(msg.GetAgreementTotal(), bigIntBase)
+	if !ok {
+		return crypto.ExchangeMessage{}, fmt.Errorf("could not unmarshal field agreementTotal of value %v", agreementTotal)
+	}
+
+	return crypto.ExchangeMessage{
+		Promise: crypto.Promise{
+			ChannelID: msg.GetPromise().GetChannelID(),
+			Amount:    amount,
+			Fee:       fee,
+			Hashlock:  msg.GetPromise().GetHashlock(),
+			R:         msg.GetPromise().GetR(),
+			Signature: msg.GetPromise().GetSignature(),
+			ChainID:   msg.GetPromise().GetChainID(),
+		},
+		AgreementID:    agreementID,
+		AgreementTotal: agreementTotal,
+		Provider:       msg.GetProvider(),
+		Signature:      msg.GetSignature(),
+		HermesID:       msg.GetHermesID(),
+		ChainID:        msg.GetChainID(),
+	}, nil
+}
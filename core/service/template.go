@@ -0,0 +1,37 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package service
+
+import "github.com/mysteriumnetwork/node/core/shaper"
+
+// Template is a service configuration published by the fleet operator and applied on service start.
+type Template struct {
+	Version string
+	// AccessPolicies are used if the service is started without access policies.
+	AccessPolicies []string
+	// Options are used if the service is started without options.
+	Options Options
+	// Shaping overrides node wide traffic shaping for the service, nil if not set.
+	Shaping *shaper.Limits
+}
+
+// TemplateSource provides service templates by service type.
+// Nil template is returned if there is no template for the service type.
+type TemplateSource interface {
+	Template(serviceType string) (*Template, error)
+}
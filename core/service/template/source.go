@@ -0,0 +1,199 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package template
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/core/service"
+	"github.com/mysteriumnetwork/node/core/shaper"
+	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/requests"
+	"github.com/mysteriumnetwork/node/services"
+)
+
+// maxTemplateSize limits the size of the downloaded template.
+const maxTemplateSize = 1 << 20
+
+// errNotFound indicates that operator does not publish a template for the service type.
+var errNotFound = errors.New("template not found")
+
+// Config describes where service templates are published.
+type Config struct {
+	// URL is the base URL of templates. Template of the service type is expected at
+	// "<URL>/<service type>.json" and its signature at the same URL with ".sig" suffix.
+	URL string
+	// Signer is the address of the identity which signs published templates.
+	Signer string
+}
+
+// Shaping holds bandwidth limitation set by the template.
+type Shaping struct {
+	Enabled       bool   `json:"enabled"`
+	BandwidthKBps uint64 `json:"bandwidth_kbps,omitempty"`
+}
+
+// Document is a service template as published by the operator.
+type Document struct {
+	Version        string           `json:"version"`
+	AccessPolicies []string         `json:"access_policies,omitempty"`
+	Options        *json.RawMessage `json:"options,omitempty"`
+	Shaping        *Shaping         `json:"shaping,omitempty"`
+}
+
+// Source fetches and verifies service templates published by the fleet operator.
+type Source struct {
+	cfg        Config
+	httpClient *requests.HTTPClient
+	verifier   identity.Verifier
+	parsers    map[string]services.ServiceOptionsParser
+
+	lock     sync.Mutex
+	verified map[string]*service.Template
+}
+
+// NewSource returns a template source. Options of the template are parsed with the given parsers.
+func NewSource(httpClient *requests.HTTPClient, cfg Config, parsers map[string]services.ServiceOptionsParser) *Source {
+	return &Source{
+		cfg:        cfg,
+		httpClient: httpClient,
+		verifier:   identity.NewVerifierIdentity(identity.FromAddress(cfg.Signer)),
+		parsers:    parsers,
+		verified:   make(map[string]*service.Template),
+	}
+}
+
+// Template returns the template of the given service type.
+// If the operator is unreachable, the last verified template is returned, or nil if there is none,
+// so that the service starts with the local configuration. Templates which fail verification are rejected.
+func (s *Source) Template(serviceType string) (*service.Template, error) {
+	if s.cfg.URL == "" || s.cfg.Signer == "" {
+		return nil, nil
+	}
+
+	url := strings.TrimSuffix(s.cfg.URL, "/") + "/" + serviceType + ".json"
+	data, err := s.fetch(url)
+	if errors.Is(err, errNotFound) {
+		s.store(serviceType, nil)
+		return nil, nil
+	}
+	if err != nil {
+		return s.lastVerified(serviceType, err), nil
+	}
+
+	sig, err := s.fetch(url + ".sig")
+	if err != nil {
+		return s.lastVerified(serviceType, errors.Wrap(err, "failed to download template signature")), nil
+	}
+	if ok, _ := s.verifier.Verify(data, identity.SignatureHex(strings.TrimSpace(string(sig)))); !ok {
+		return nil, errors.New("template signature verification failed")
+	}
+
+	var doc Document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, errors.Wrap(err, "failed to parse template")
+	}
+	if doc.Version == "" {
+		return nil, errors.New("template version is missing")
+	}
+
+	tpl := &service.Template{
+		Version:        doc.Version,
+		AccessPolicies: doc.AccessPolicies,
+	}
+	if doc.Shaping != nil {
+		tpl.Shaping = &shaper.Limits{
+			Enabled:       doc.Shaping.Enabled,
+			BandwidthKBps: doc.Shaping.BandwidthKBps,
+		}
+	}
+	if doc.Options != nil {
+		parse, ok := s.parsers[serviceType]
+		if !ok {
+			return nil, service.ErrUnsupportedServiceType
+		}
+		if tpl.Options, err = parse(doc.Options); err != nil {
+			return nil, errors.Wrap(err, "failed to parse template options")
+		}
+	}
+
+	s.store(serviceType, tpl)
+	log.Info().Msgf("Using %s service template version %s", serviceType, doc.Version)
+	return tpl, nil
+}
+
+func (s *Source) store(serviceType string, tpl *service.Template) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if tpl == nil {
+		delete(s.verified, serviceType)
+		return
+	}
+	s.verified[serviceType] = tpl
+}
+
+func (s *Source) lastVerified(serviceType string, err error) *service.Template {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	tpl, ok := s.verified[serviceType]
+	if !ok {
+		log.Warn().Err(err).Msgf("Could not fetch %s service template, using local configuration", serviceType)
+		return nil
+	}
+	log.Warn().Err(err).Msgf("Could not fetch %s service template, using last verified version %s", serviceType, tpl.Version)
+	return tpl
+}
+
+func (s *Source) fetch(url string) ([]byte, error) {
+	req, err := requests.NewGetRequest(url, "", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, errNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response status: %s", resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxTemplateSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxTemplateSize {
+		return nil, errors.New("response is too large")
+	}
+	return data, nil
+}
@@ -0,0 +1,159 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package template
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/core/service"
+	"github.com/mysteriumnetwork/node/core/shaper"
+	"github.com/mysteriumnetwork/node/requests"
+	"github.com/mysteriumnetwork/node/services"
+)
+
+type testOptions struct {
+	Port int `json:"port"`
+}
+
+var testParsers = map[string]services.ServiceOptionsParser{
+	"test": func(raw *json.RawMessage) (service.Options, error) {
+		var opts testOptions
+		err := json.Unmarshal(*raw, &opts)
+		return opts, err
+	},
+}
+
+func TestSource_Template(t *testing.T) {
+	doc := []byte(`{"version":"v2","access_policies":["mysterium"],"options":{"port":1194},"shaping":{"enabled":true,"bandwidth_kbps":1000}}`)
+
+	key, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+	sig, err := crypto.Sign(crypto.Keccak256(doc), key)
+	assert.NoError(t, err)
+	signer := crypto.PubkeyToAddress(key.PublicKey).Hex()
+
+	tests := []struct {
+		name      string
+		signature string
+		wantErr   bool
+	}{
+		{"valid signature", hex.EncodeToString(sig), false},
+		{"invalid signature", hex.EncodeToString(sig[1:]), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/test.json":
+					w.Write(doc)
+				case "/test.json.sig":
+					w.Write([]byte(tt.signature))
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer server.Close()
+
+			source := NewSource(
+				requests.NewHTTPClient("0.0.0.0", time.Second),
+				Config{URL: server.URL, Signer: signer},
+				testParsers,
+			)
+
+			tpl, err := source.Template("test")
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Nil(t, tpl)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, &service.Template{
+				Version:        "v2",
+				AccessPolicies: []string{"mysterium"},
+				Options:        testOptions{Port: 1194},
+				Shaping:        &shaper.Limits{Enabled: true, BandwidthKBps: 1000},
+			}, tpl)
+
+			tpl, err = source.Template("missing")
+			assert.NoError(t, err)
+			assert.Nil(t, tpl)
+		})
+	}
+}
+
+func TestSource_Template_FallsBackToLastVerified(t *testing.T) {
+	doc := []byte(`{"version":"v3","access_policies":["mysterium"]}`)
+
+	key, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+	sig, err := crypto.Sign(crypto.Keccak256(doc), key)
+	assert.NoError(t, err)
+	signer := crypto.PubkeyToAddress(key.PublicKey).Hex()
+
+	sigAvailable := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/test.json":
+			w.Write(doc)
+		case r.URL.Path == "/test.json.sig" && sigAvailable:
+			w.Write([]byte(hex.EncodeToString(sig)))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	source := NewSource(
+		requests.NewHTTPClient("0.0.0.0", time.Second),
+		Config{URL: server.URL, Signer: signer},
+		testParsers,
+	)
+
+	// Signature is unavailable and nothing was verified yet, local configuration is used.
+	sigAvailable = false
+	tpl, err := source.Template("test")
+	assert.NoError(t, err)
+	assert.Nil(t, tpl)
+
+	sigAvailable = true
+	tpl, err = source.Template("test")
+	assert.NoError(t, err)
+	assert.Equal(t, "v3", tpl.Version)
+
+	// Signature fetch failure is handled like unreachable operator.
+	sigAvailable = false
+	tpl, err = source.Template("test")
+	assert.NoError(t, err)
+	assert.Equal(t, "v3", tpl.Version)
+
+	// Unreachable operator.
+	server.Close()
+	tpl, err = source.Template("test")
+	assert.NoError(t, err)
+	assert.Equal(t, "v3", tpl.Version)
+}
@@ -29,7 +29,19 @@ type eventListener interface {
 	SubscribeAsync(topic string, fn interface{}) error
 }
 
+// Limits overrides the node wide shaping configuration for a single service.
+type Limits struct {
+	Enabled       bool
+	BandwidthKBps uint64
+}
+
 // New creates a traffic shaper (linux) or no-op.
 func New(listener eventListener) (shaper Shaper) {
-	return create(listener)
+	return create(listener, nil)
+}
+
+// NewWithLimits creates a traffic shaper applying the given limits instead of node configuration.
+// Node configuration is used if limits are nil.
+func NewWithLimits(listener eventListener, limits *Limits) (shaper Shaper) {
+	return create(listener, limits)
 }
@@ -28,7 +28,7 @@ import (
 type noopShaper struct {
 }
 
-func create(_ eventListener) *noopShaper {
+func create(_ eventListener, _ *Limits) *noopShaper {
 	return &noopShaper{}
 }
 
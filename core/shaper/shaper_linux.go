@@ -29,6 +29,7 @@ type linuxShaper struct {
 	ws          *wondershaper.Shaper
 	listener    eventListener
 	listenTopic string
+	limits      *Limits
 }
 
 type linuxShaperNoop struct{}
@@ -41,7 +42,7 @@ func (s *linuxShaperNoop) Clear(interfaceName string) {
 	return
 }
 
-func create(listener eventListener) Shaper {
+func create(listener eventListener, limits *Limits) Shaper {
 	// return a noop filter if userspace flag is set
 	if config.GetBool(config.FlagUserspace) {
 		return &linuxShaperNoop{}
@@ -54,6 +55,7 @@ func create(listener eventListener) Shaper {
 		ws:          ws,
 		listener:    listener,
 		listenTopic: config.AppTopicConfig(config.FlagShaperEnabled.Name),
+		limits:      limits,
 	}
 }
 
@@ -62,13 +64,14 @@ func (s *linuxShaper) Start(interfaceName string) error {
 	applyLimits := func() error {
 		s.ws.Clear(interfaceName)
 
-		if config.GetBool(config.FlagShaperEnabled) {
-			err := s.ws.LimitDownlink(interfaceName, int(config.GetUInt64(config.FlagShaperBandwidth))*8)
+		enabled, bandwidth := s.current()
+		if enabled {
+			err := s.ws.LimitDownlink(interfaceName, int(bandwidth)*8)
 			if err != nil {
 				log.Error().Err(err).Msg("Could not limit download speed")
 				return err
 			}
-			err = s.ws.LimitUplink(interfaceName, int(config.GetUInt64(config.FlagShaperBandwidth))*8)
+			err = s.ws.LimitUplink(interfaceName, int(bandwidth)*8)
 			if err != nil {
 				log.Error().Err(err).Msg("Could not limit upload speed")
 				return err
@@ -85,6 +88,18 @@ func (s *linuxShaper) Start(interfaceName string) error {
 	return applyLimits()
 }
 
+// current returns the shaping limits of the service, falling back to node configuration.
+func (s *linuxShaper) current() (enabled bool, bandwidthKBps uint64) {
+	enabled, bandwidthKBps = config.GetBool(config.FlagShaperEnabled), config.GetUInt64(config.FlagShaperBandwidth)
+	if s.limits != nil {
+		enabled = s.limits.Enabled
+		if s.limits.BandwidthKBps > 0 {
+			bandwidthKBps = s.limits.BandwidthKBps
+		}
+	}
+	return enabled, bandwidthKBps
+}
+
 // Clear clears shaping rules.
 func (s *linuxShaper) Clear(interfaceName string) {
 	s.ws.Clear(interfaceName)
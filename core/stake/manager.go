@@ -0,0 +1,183 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package stake
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/mysteriumnetwork/payments/client"
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/eventbus"
+	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/identity/registry"
+)
+
+// AppTopicStakeChanged is published once stake increase or decrease completes.
+const AppTopicStakeChanged = "stake_changed"
+
+// Operation is the kind of stake change.
+type Operation string
+
+const (
+	// OperationIncrease settles the earnings into stake.
+	OperationIncrease Operation = "increase"
+	// OperationDecrease moves part of the stake back to the balance.
+	OperationDecrease Operation = "decrease"
+)
+
+// AppEventStakeChanged describes the outcome of stake change.
+type AppEventStakeChanged struct {
+	ID        identity.Identity
+	ChainID   int64
+	Operation Operation
+	// Amount of the decrease, empty for the increase.
+	Amount *big.Int
+	// Error is empty if the change succeeded.
+	Error string
+}
+
+var (
+	// ErrInvalidAmount is returned when stake decrease amount is not positive.
+	ErrInvalidAmount = errors.New("amount must be positive")
+	// ErrInsufficientStake is returned when stake decrease amount exceeds the current stake.
+	ErrInsufficientStake = errors.New("amount exceeds current stake")
+	// ErrBelowMinimumStake is returned when the stake left after decrease would be below the minimum.
+	ErrBelowMinimumStake = errors.New("stake would be below the minimum stake")
+)
+
+type transactor interface {
+	FetchStakeDecreaseFee(chainID int64) (registry.FeesResponse, error)
+	DecreaseStake(id string, chainID int64, amount, transactorFee *big.Int) error
+}
+
+type settler interface {
+	SettleIntoStake(chainID int64, providerID identity.Identity, hermesID ...common.Address) error
+}
+
+type blockchain interface {
+	GetProviderChannel(chainID int64, hermesAddress common.Address, addressToCheck common.Address, pending bool) (client.ProviderChannel, error)
+	GetStakeThresholds(chainID int64, hermesID common.Address) (min, max *big.Int, err error)
+}
+
+type addressProvider interface {
+	GetActiveHermes(chainID int64) (common.Address, error)
+}
+
+// Manager increases and decreases provider stakes.
+type Manager struct {
+	transactor transactor
+	settler    settler
+	bc         blockchain
+	ad         addressProvider
+	publisher  eventbus.Publisher
+}
+
+// NewManager returns a new stake manager.
+func NewManager(transactor transactor, settler settler, bc blockchain, ad addressProvider, publisher eventbus.Publisher) *Manager {
+	return &Manager{
+		transactor: transactor,
+		settler:    settler,
+		bc:         bc,
+		ad:         ad,
+		publisher:  publisher,
+	}
+}
+
+// Stake returns the current and the minimum stake of the identity in the active hermes.
+func (m *Manager) Stake(chainID int64, id identity.Identity) (current, minimum *big.Int, err error) {
+	hermes, err := m.ad.GetActiveHermes(chainID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not get active hermes: %w", err)
+	}
+
+	channel, err := m.bc.GetProviderChannel(chainID, hermes, id.ToCommonAddress(), false)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not get provider channel: %w", err)
+	}
+	minimum, _, err = m.bc.GetStakeThresholds(chainID, hermes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not get stake thresholds: %w", err)
+	}
+
+	current = channel.Stake
+	if current == nil {
+		current = new(big.Int)
+	}
+	return current, minimum, nil
+}
+
+// IncreaseStake settles the earnings in the given hermeses into stake, the active hermes is used if none are given.
+// Settlement is done asynchronously, its outcome is published as AppEventStakeChanged.
+func (m *Manager) IncreaseStake(chainID int64, id identity.Identity, hermeses ...common.Address) error {
+	if len(hermeses) == 0 {
+		hermes, err := m.ad.GetActiveHermes(chainID)
+		if err != nil {
+			return fmt.Errorf("could not get active hermes: %w", err)
+		}
+		hermeses = []common.Address{hermes}
+	}
+
+	go func() {
+		err := m.settler.SettleIntoStake(chainID, id, hermeses...)
+		m.publish(chainID, id, OperationIncrease, nil, err)
+	}()
+	return nil
+}
+
+// DecreaseStake requests the stake decrease by amount, the stake left must not be below the minimum stake.
+// Transaction is submitted asynchronously, its outcome is published as AppEventStakeChanged.
+func (m *Manager) DecreaseStake(chainID int64, id identity.Identity, amount *big.Int) error {
+	if amount == nil || amount.Sign() <= 0 {
+		return ErrInvalidAmount
+	}
+
+	current, minimum, err := m.Stake(chainID, id)
+	if err != nil {
+		return err
+	}
+	if amount.Cmp(current) > 0 {
+		return ErrInsufficientStake
+	}
+	if minimum != nil && new(big.Int).Sub(current, amount).Cmp(minimum) < 0 {
+		return fmt.Errorf("%w of %s", ErrBelowMinimumStake, minimum)
+	}
+
+	fees, err := m.transactor.FetchStakeDecreaseFee(chainID)
+	if err != nil {
+		return fmt.Errorf("could not fetch stake decrease fee: %w", err)
+	}
+
+	go func() {
+		err := m.transactor.DecreaseStake(id.Address, chainID, amount, fees.Fee)
+		m.publish(chainID, id, OperationDecrease, amount, err)
+	}()
+	return nil
+}
+
+func (m *Manager) publish(chainID int64, id identity.Identity, op Operation, amount *big.Int, err error) {
+	ev := AppEventStakeChanged{ID: id, ChainID: chainID, Operation: op, Amount: amount}
+	if err != nil {
+		log.Error().Err(err).Msgf("Stake %s of %s failed", op, id.Address)
+		ev.Error = err.Error()
+	}
+	m.publisher.Publish(AppTopicStakeChanged, ev)
+}
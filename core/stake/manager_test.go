@@ -0,0 +1,148 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package stake
+
+import (
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/mysteriumnetwork/payments/client"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/identity/registry"
+	"github.com/mysteriumnetwork/node/mocks"
+)
+
+var (
+	testID     = identity.FromAddress("0x000000000000000000000000000000000000000a")
+	testHermes = common.HexToAddress("0x0000000000000000000000000000000000000001")
+)
+
+func TestManager_DecreaseStake(t *testing.T) {
+	tests := []struct {
+		name    string
+		amount  *big.Int
+		wantErr error
+	}{
+		{name: "decreases", amount: big.NewInt(40)},
+		{name: "zero amount", amount: big.NewInt(0), wantErr: ErrInvalidAmount},
+		{name: "exceeds stake", amount: big.NewInt(101), wantErr: ErrInsufficientStake},
+		{name: "below minimum", amount: big.NewInt(60), wantErr: ErrBelowMinimumStake},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := &mockTransactor{}
+			bus := mocks.NewEventBus()
+			m := NewManager(tr, &mockSettler{}, &mockBlockchain{stake: big.NewInt(100), min: big.NewInt(50)}, &mockAddressProvider{}, bus)
+
+			err := m.DecreaseStake(1, testID, tt.amount)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Eventually(t, func() bool { return bus.Pop() != nil }, time.Second, 10*time.Millisecond)
+			amount, fee := tr.decreased()
+			assert.Equal(t, tt.amount, amount)
+			assert.Equal(t, big.NewInt(3), fee)
+		})
+	}
+}
+
+func TestManager_IncreaseStake(t *testing.T) {
+	set := &mockSettler{}
+	bus := mocks.NewEventBus()
+	m := NewManager(&mockTransactor{}, set, &mockBlockchain{}, &mockAddressProvider{}, bus)
+
+	err := m.IncreaseStake(1, testID)
+	assert.NoError(t, err)
+
+	var ev interface{}
+	assert.Eventually(t, func() bool {
+		ev = bus.Pop()
+		return ev != nil
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, AppEventStakeChanged{ID: testID, ChainID: 1, Operation: OperationIncrease}, ev)
+	assert.Equal(t, []common.Address{testHermes}, set.settled())
+}
+
+type mockTransactor struct {
+	lock   sync.Mutex
+	amount *big.Int
+	fee    *big.Int
+}
+
+func (m *mockTransactor) FetchStakeDecreaseFee(chainID int64) (registry.FeesResponse, error) {
+	return registry.FeesResponse{Fee: big.NewInt(3)}, nil
+}
+
+func (m *mockTransactor) DecreaseStake(id string, chainID int64, amount, transactorFee *big.Int) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.amount, m.fee = amount, transactorFee
+	return nil
+}
+
+func (m *mockTransactor) decreased() (*big.Int, *big.Int) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.amount, m.fee
+}
+
+type mockSettler struct {
+	lock     sync.Mutex
+	hermeses []common.Address
+}
+
+func (m *mockSettler) SettleIntoStake(chainID int64, providerID identity.Identity, hermesID ...common.Address) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.hermeses = hermesID
+	return nil
+}
+
+func (m *mockSettler) settled() []common.Address {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.hermeses
+}
+
+type mockBlockchain struct {
+	stake *big.Int
+	min   *big.Int
+}
+
+func (m *mockBlockchain) GetProviderChannel(chainID int64, hermesAddress common.Address, addressToCheck common.Address, pending bool) (client.ProviderChannel, error) {
+	return client.ProviderChannel{Stake: m.stake}, nil
+}
+
+func (m *mockBlockchain) GetStakeThresholds(chainID int64, hermesID common.Address) (min, max *big.Int, err error) {
+	return m.min, nil, nil
+}
+
+type mockAddressProvider struct{}
+
+func (m *mockAddressProvider) GetActiveHermes(chainID int64) (common.Address, error) {
+	return testHermes, nil
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/mysteriumnetwork/node/consumer/bandwidth"
 	"github.com/mysteriumnetwork/node/consumer/session"
 	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
+	"github.com/mysteriumnetwork/node/core/profile"
 	"github.com/mysteriumnetwork/node/datasize"
 	"github.com/mysteriumnetwork/node/identity/registry"
 	"github.com/mysteriumnetwork/node/money"
@@ -40,11 +41,15 @@ const AppTopicState = "State change"
 
 // State represents the node state at the current moment. It's a read only object, used only to display data.
 type State struct {
+	// Version is incremented every time a state change is announced.
+	Version uint64
+
 	Services         []contract.ServiceInfoDTO
 	Sessions         []session.History
 	Connections      map[string]Connection
 	Identities       []Identity
 	ProviderChannels []pingpong.HermesChannel
+	Profile          profile.Profile
 }
 
 // Identity represents identity and its status.
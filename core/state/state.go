@@ -30,6 +30,7 @@ import (
 	"github.com/mysteriumnetwork/node/consumer/session"
 	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
 	"github.com/mysteriumnetwork/node/core/discovery/proposal"
+	"github.com/mysteriumnetwork/node/core/profile"
 	"github.com/mysteriumnetwork/node/core/service"
 	"github.com/mysteriumnetwork/node/core/service/servicestate"
 	stateEvent "github.com/mysteriumnetwork/node/core/state/event"
@@ -104,6 +105,11 @@ type KeeperDeps struct {
 	EarningsProvider          earningsProvider
 	ChainID                   int64
 	ProposalPricer            proposalPricer
+	ProfileProvider           profileProvider
+}
+
+type profileProvider interface {
+	Get() profile.Profile
 }
 
 type proposalPricer interface {
@@ -121,6 +127,9 @@ func NewKeeper(deps KeeperDeps, debounceDuration time.Duration) *Keeper {
 	}
 	k.state.Identities = k.fetchIdentities()
 	k.state.ProviderChannels = k.deps.EarningsProvider.List(deps.ChainID)
+	if deps.ProfileProvider != nil {
+		k.state.Profile = deps.ProfileProvider.Get()
+	}
 
 	// provider
 	k.consumeServiceStateEvent = debounce(k.updateServiceState, debounceDuration)
@@ -179,7 +188,7 @@ func (k *Keeper) Subscribe(bus eventbus.Subscriber) error {
 	if err := bus.SubscribeAsync(sessionEvent.AppTopicSession, k.consumeServiceSessionEvent); err != nil {
 		return err
 	}
-	if err := bus.SubscribeAsync(sessionEvent.AppTopicDataTransferred, k.consumeServiceSessionStatisticsEvent); err != nil {
+	if err := bus.SubscribeAsync(sessionEvent.AppTopicDataTransferredBatch, k.consumeServiceSessionStatisticsEvent); err != nil {
 		return err
 	}
 	if err := bus.SubscribeAsync(sessionEvent.AppTopicTokensEarned, k.consumeServiceSessionEarningsEvent); err != nil {
@@ -209,14 +218,18 @@ func (k *Keeper) Subscribe(bus eventbus.Subscriber) error {
 	if err := bus.SubscribeAsync(pingpongEvent.AppTopicEarningsChanged, k.consumeEarningsChangedEvent); err != nil {
 		return err
 	}
+	if err := bus.SubscribeAsync(profile.AppTopicProfile, k.consumeProfileEvent); err != nil {
+		return err
+	}
 	return nil
 }
 
 func (k *Keeper) announceState(_ interface{}) {
 	var state stateEvent.State
 	func() {
-		k.lock.RLock()
-		defer k.lock.RUnlock()
+		k.lock.Lock()
+		defer k.lock.Unlock()
+		k.state.Version++
 		if err := copier.CopyWithOption(&state, *k.state, copier.Option{DeepCopy: true}); err != nil {
 			panic(err)
 		}
@@ -256,6 +269,7 @@ func (k *Keeper) updateServices() {
 			Type:                 v.Type,
 			Options:              v.Options,
 			Status:               string(v.State()),
+			TemplateVersion:      v.TemplateVersion,
 			Proposal:             &prop,
 			ConnectionStatistics: match.ConnectionStatistics,
 		}
@@ -329,29 +343,35 @@ func (k *Keeper) updateSessionStats(e interface{}) {
 	k.lock.Lock()
 	defer k.lock.Unlock()
 
-	evt, ok := e.(sessionEvent.AppEventDataTransferred)
+	batch, ok := e.(sessionEvent.AppEventDataTransferredBatch)
 	if !ok {
 		log.Warn().Msg("Received a wrong kind of event for session state update")
 		return
 	}
 
-	var session *session.History
-	for i := range k.state.Sessions {
-		if string(k.state.Sessions[i].SessionID) == evt.ID {
-			session = &k.state.Sessions[i]
+	changed := false
+	for _, evt := range batch.Sessions {
+		var session *session.History
+		for i := range k.state.Sessions {
+			if string(k.state.Sessions[i].SessionID) == evt.ID {
+				session = &k.state.Sessions[i]
+			}
+		}
+		if session == nil {
+			log.Warn().Msgf("Couldn't find a matching session for data transferred change: %+v", evt)
+			continue
 		}
+
+		// From a server perspective, bytes up are the actual bytes the client downloaded(aka the bytes we pushed to the consumer)
+		// To lessen the confusion, I suggest having the bytes reversed on the session instance.
+		// This way, the session will show that it downloaded the bytes in a manner that is easier to comprehend.
+		session.DataReceived = evt.Up
+		session.DataSent = evt.Down
+		changed = true
 	}
-	if session == nil {
-		log.Warn().Msgf("Couldn't find a matching session for data transferred change: %+v", evt)
-		return
+	if changed {
+		go k.announceStateChanges(nil)
 	}
-
-	// From a server perspective, bytes up are the actual bytes the client downloaded(aka the bytes we pushed to the consumer)
-	// To lessen the confusion, I suggest having the bytes reversed on the session instance.
-	// This way, the session will show that it downloaded the bytes in a manner that is easier to comprehend.
-	session.DataReceived = evt.Up
-	session.DataSent = evt.Down
-	go k.announceStateChanges(nil)
 }
 
 // updates total tokens earned during the session.
@@ -512,6 +532,13 @@ func (k *Keeper) consumeIdentityCreatedEvent(_ interface{}) {
 	go k.announceStateChanges(nil)
 }
 
+func (k *Keeper) consumeProfileEvent(p profile.Profile) {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+	k.state.Profile = p
+	go k.announceStateChanges(nil)
+}
+
 func (k *Keeper) consumeIdentityRegistrationEvent(e interface{}) {
 	k.lock.Lock()
 	defer k.lock.Unlock()
@@ -260,10 +260,10 @@ func Test_consumeServiceSessionStatisticsEvent(t *testing.T) {
 	}
 
 	// when
-	eventBus.Publish(sessionEvent.AppTopicDataTransferred, sessionEvent.AppEventDataTransferred{
-		ID:   "1",
-		Up:   1,
-		Down: 2,
+	eventBus.Publish(sessionEvent.AppTopicDataTransferredBatch, sessionEvent.AppEventDataTransferredBatch{
+		Sessions: []sessionEvent.AppEventDataTransferred{
+			{ID: "1", Up: 1, Down: 2},
+		},
 	})
 
 	// then
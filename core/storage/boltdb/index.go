@@ -0,0 +1,38 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package boltdb
+
+import "time"
+
+// maxIndexedTime is the upper bound used for open ended time index ranges.
+var maxIndexedTime = time.Date(9999, 12, 31, 23, 59, 59, 0, time.UTC)
+
+// TimeIndexRange returns the bounds of a storm Range query over a time index
+// which cover every record between from and to, an absent to meaning no upper bound.
+//
+// Storm indexes times by their RFC 3339 representation, which only sorts
+// chronologically across whole seconds for UTC values, so the bounds are
+// widened to the neighbouring seconds. Callers must apply the exact filter
+// to the records found.
+func TimeIndexRange(from time.Time, to *time.Time) (min, max time.Time) {
+	min = from.UTC().Truncate(time.Second).Add(-time.Second)
+	if to == nil {
+		return min, maxIndexedTime
+	}
+	return min, to.UTC().Truncate(time.Second).Add(time.Second)
+}
@@ -0,0 +1,74 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package boltdb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type timeIndexedType struct {
+	ID   int64     `storm:"id"`
+	Time time.Time `storm:"index"`
+}
+
+func Test_TimeIndexRange_CoversSubSecondTimes(t *testing.T) {
+	storage, close, err := createMockStorage(t)
+	assert.NoError(t, err)
+	defer close()
+
+	base := time.Date(2021, 4, 5, 10, 0, 0, 0, time.UTC)
+	offsets := []time.Duration{
+		-time.Second - time.Millisecond,
+		-time.Millisecond,
+		0,
+		time.Nanosecond,
+		500 * time.Millisecond,
+		time.Second,
+		time.Second + 999*time.Millisecond,
+		2 * time.Second,
+		2*time.Second + time.Millisecond,
+	}
+	for i, offset := range offsets {
+		err := storage.Store(bucket, &timeIndexedType{ID: int64(i + 1), Time: base.Add(offset)})
+		assert.NoError(t, err)
+	}
+
+	from, to := base, base.Add(2*time.Second)
+	min, max := TimeIndexRange(from.In(time.FixedZone("EET", 2*60*60)), &to)
+
+	var found []timeIndexedType
+	err = storage.DB().From(bucket).Range("Time", min, max, &found)
+	assert.NoError(t, err)
+
+	var ids []int64
+	for _, record := range found {
+		if !record.Time.Before(from) && !record.Time.After(to) {
+			ids = append(ids, record.ID)
+		}
+	}
+	assert.ElementsMatch(t, []int64{3, 4, 5, 6, 7, 8}, ids)
+
+	min, max = TimeIndexRange(from, nil)
+	found = nil
+	err = storage.DB().From(bucket).Range("Time", min, max, &found)
+	assert.NoError(t, err)
+	assert.Len(t, found, 7)
+}
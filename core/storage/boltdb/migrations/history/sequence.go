@@ -26,27 +26,43 @@ import (
 // Sequence contains the whole migration sequence for boltdb
 var Sequence = []migrations.Migration{
 	{
-		Name: "session-to-session-history",
+		Name:    "session-to-session-history",
+		Version: 1,
+		Buckets: []string{"session-history"},
 		Date: time.Date(
 			2018, 12, 04, 12, 00, 00, 0, time.UTC),
 		Migrate: migrations.MigrateSessionToHistory,
 	},
 	{
-		Name: "settlements-to-rows",
+		Name:    "settlements-to-rows",
+		Version: 2,
+		Buckets: []string{"settlement_history", "settlement-history"},
 		Date: time.Date(
 			2020, 8, 17, 14, 27, 00, 0, time.UTC),
 		Migrate: migrations.SettlementValuesToRows,
 	},
 	{
-		Name: "registration-status-to-new",
+		Name:    "registration-status-to-new",
+		Version: 3,
+		Buckets: []string{"registry_statuses"},
 		Date: time.Date(
 			2021, 3, 15, 16, 00, 00, 0, time.UTC),
 		Migrate: migrations.MigrateRegistrationState,
 	},
 	{
-		Name: "registration-status-to-new-mainnet",
+		Name:    "registration-status-to-new-mainnet",
+		Version: 4,
+		Buckets: []string{"registry_statuses"},
 		Date: time.Date(
 			2021, 10, 11, 0, 00, 00, 0, time.UTC),
 		Migrate: migrations.MigrateRegistrationState,
 	},
+	{
+		Name:    "index-session-and-settlement-history",
+		Version: 5,
+		Buckets: []string{"session-history", "settlement-history"},
+		Date: time.Date(
+			2026, 10, 16, 0, 00, 00, 0, time.UTC),
+		Migrate: migrations.IndexHistory,
+	},
 }
@@ -0,0 +1,75 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package migrations
+
+import (
+	"github.com/asdine/storm/v3"
+	"github.com/rs/zerolog/log"
+
+	consumer_session "github.com/mysteriumnetwork/node/consumer/session"
+	"github.com/mysteriumnetwork/node/session/pingpong"
+)
+
+const sessionHistoryBucket = "session-history"
+
+// IndexHistory backfills the session and settlement history indices for the
+// records stored before the indexed fields were introduced. Indexed times are
+// normalized to UTC so that the time indices sort chronologically.
+func IndexHistory(db *storm.DB) error {
+	tx, err := db.Begin(true)
+	if err != nil {
+		return err
+	}
+
+	if err := indexHistory(tx); err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			log.Error().Err(rollbackErr).Msg("History index backfill rollback failed")
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func indexHistory(tx storm.Node) error {
+	sessionBucket := tx.From(sessionHistoryBucket)
+	var sessions []consumer_session.History
+	if err := sessionBucket.All(&sessions); err != nil {
+		return err
+	}
+	for i := range sessions {
+		sessions[i].Started = sessions[i].Started.UTC()
+		if err := sessionBucket.Save(&sessions[i]); err != nil {
+			return err
+		}
+	}
+
+	settlementBucket := tx.From(settlementHistoryBucketNew)
+	var settlements []pingpong.SettlementHistoryEntry
+	if err := settlementBucket.All(&settlements); err != nil {
+		return err
+	}
+	for i := range settlements {
+		settlements[i].Time = settlements[i].Time.UTC()
+		if err := settlementBucket.Save(&settlements[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
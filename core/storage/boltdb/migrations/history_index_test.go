@@ -0,0 +1,105 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package migrations
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+
+	consumer_session "github.com/mysteriumnetwork/node/consumer/session"
+	"github.com/mysteriumnetwork/node/core/storage/boltdb/boltdbtest"
+	"github.com/mysteriumnetwork/node/identity"
+	node_session "github.com/mysteriumnetwork/node/session"
+	"github.com/mysteriumnetwork/node/session/pingpong"
+)
+
+func TestIndexHistoryWithNoData(t *testing.T) {
+	file, db := boltdbtest.CreateDB(t)
+	defer boltdbtest.CleanupDB(t, file, db)
+
+	err := IndexHistory(db)
+	assert.NoError(t, err)
+}
+
+func TestIndexHistoryWithData(t *testing.T) {
+	file, db := boltdbtest.CreateDB(t)
+	defer boltdbtest.CleanupDB(t, file, db)
+
+	// Records stored before the fields were indexed.
+	type History struct {
+		SessionID   node_session.ID `storm:"id"`
+		ProviderID  identity.Identity
+		ServiceType string
+		Started     time.Time
+	}
+	type SettlementHistoryEntry struct {
+		TxHash     common.Hash `storm:"id"`
+		ProviderID identity.Identity
+		Time       time.Time
+	}
+	started := time.Date(2021, 4, 5, 12, 0, 0, 0, time.FixedZone("EET", 2*60*60))
+	err := db.From(sessionHistoryBucket).Save(&History{
+		SessionID:   "session1",
+		ProviderID:  providerID,
+		ServiceType: serviceType,
+		Started:     started,
+	})
+	assert.NoError(t, err)
+	err = db.From(settlementHistoryBucketNew).Save(&SettlementHistoryEntry{
+		TxHash:     common.BigToHash(big.NewInt(1)),
+		ProviderID: providerID,
+		Time:       started,
+	})
+	assert.NoError(t, err)
+
+	var sessions []consumer_session.History
+	err = db.From(sessionHistoryBucket).Find("ProviderID", providerID, &sessions)
+	assert.Error(t, err)
+
+	// when
+	err = IndexHistory(db)
+	assert.NoError(t, err)
+
+	// then
+	err = db.From(sessionHistoryBucket).Find("ProviderID", providerID, &sessions)
+	assert.NoError(t, err)
+	assert.Len(t, sessions, 1)
+	assert.Equal(t, started.UTC(), sessions[0].Started)
+
+	err = db.From(sessionHistoryBucket).Find("ServiceType", serviceType, &sessions)
+	assert.NoError(t, err)
+	assert.Len(t, sessions, 1)
+
+	min, max := started.UTC().Add(-time.Second), started.UTC().Add(time.Second)
+	err = db.From(sessionHistoryBucket).Range("Started", min, max, &sessions)
+	assert.NoError(t, err)
+	assert.Len(t, sessions, 1)
+
+	var settlements []pingpong.SettlementHistoryEntry
+	err = db.From(settlementHistoryBucketNew).Find("ProviderID", providerID, &settlements)
+	assert.NoError(t, err)
+	assert.Len(t, settlements, 1)
+
+	err = db.From(settlementHistoryBucketNew).Range("Time", min, max, &settlements)
+	assert.NoError(t, err)
+	assert.Len(t, settlements, 1)
+}
@@ -25,7 +25,12 @@ import (
 
 // Migration represents a migration we want to run on bolt db
 type Migration struct {
-	Name    string `storm:"id"`
-	Date    time.Time
+	Name string `storm:"id"`
+	Date time.Time
+	// Version is the schema version the database is at once the migration is applied.
+	// Versions must be unique and define the order migrations are run in.
+	Version int
+	// Buckets lists the buckets whose schema the migration changes.
+	Buckets []string
 	Migrate func(*storm.DB) error `json:"-"`
 }
@@ -18,6 +18,8 @@
 package migrator
 
 import (
+	"errors"
+	"fmt"
 	"sort"
 
 	"github.com/mysteriumnetwork/node/core/storage/boltdb"
@@ -27,6 +29,9 @@ import (
 
 const migrationIndexBucketName = "migrations"
 
+// ErrSchemaTooNew is returned when the database contains migrations unknown to this node version.
+var ErrSchemaTooNew = errors.New("database schema is newer than supported")
+
 // Migrator represents the component responsible for running migrations on bolt db
 type Migrator struct {
 	db *boltdb.Bolt
@@ -39,9 +44,14 @@ func NewMigrator(db *boltdb.Bolt) *Migrator {
 	}
 }
 
-func (m *Migrator) isApplied(migration migrations.Migration) (bool, error) {
+func (m *Migrator) applied() ([]migrations.Migration, error) {
 	migrations := []migrations.Migration{}
 	err := m.db.GetAllFrom(migrationIndexBucketName, &migrations)
+	return migrations, err
+}
+
+func (m *Migrator) isApplied(migration migrations.Migration) (bool, error) {
+	migrations, err := m.applied()
 	if err != nil {
 		return true, err
 	}
@@ -81,19 +91,147 @@ func (m *Migrator) migrate(migration migrations.Migration) error {
 
 func (m *Migrator) sortMigrations(sequence []migrations.Migration) []migrations.Migration {
 	sort.Slice(sequence, func(i, j int) bool {
-		return sequence[i].Date.Before(sequence[j].Date)
+		return sequence[i].Version < sequence[j].Version
 	})
 	return sequence
 }
 
-// RunMigrations runs the given sequence of migrations
+// SchemaVersion returns the schema version of the database, 0 if no migrations were applied.
+func (m *Migrator) SchemaVersion() (int, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return 0, err
+	}
+
+	version := 0
+	for _, migration := range applied {
+		if migration.Version > version {
+			version = migration.Version
+		}
+	}
+	return version, nil
+}
+
+// BucketVersion returns the schema version of the given bucket, 0 if no migration changed it.
+func (m *Migrator) BucketVersion(bucket string) (int, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return 0, err
+	}
+
+	version := 0
+	for _, migration := range applied {
+		for _, b := range migration.Buckets {
+			if b == bucket && migration.Version > version {
+				version = migration.Version
+			}
+		}
+	}
+	return version, nil
+}
+
+// RunMigrations runs the given sequence of migrations ordered by their versions.
+// The database is backed up before pending migrations are applied and restored from
+// the backup if any of them fails.
 func (m *Migrator) RunMigrations(sequence []migrations.Migration) error {
+	if err := validateSequence(sequence); err != nil {
+		return err
+	}
 	sorted := m.sortMigrations(sequence)
+
+	if err := m.backfillVersions(sorted); err != nil {
+		return err
+	}
+
+	var pending []migrations.Migration
 	for i := range sorted {
-		err := m.migrate(sorted[i])
+		isRun, err := m.isApplied(sorted[i])
 		if err != nil {
 			return err
 		}
+		if !isRun {
+			pending = append(pending, sorted[i])
+		}
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	version, err := m.SchemaVersion()
+	if err != nil {
+		return err
+	}
+
+	backup := fmt.Sprintf("%s.v%d.bak", m.db.Path(), version)
+	log.Info().Msgf("Backing up database at schema version %d to %s", version, backup)
+	if err := m.db.Backup(backup); err != nil {
+		return err
+	}
+
+	for i := range pending {
+		err := m.migrate(pending[i])
+		if err == nil {
+			continue
+		}
+
+		log.Error().Err(err).Msgf("Migration %s failed, restoring database from %s", pending[i].Name, backup)
+		if restoreErr := m.db.Restore(backup); restoreErr != nil {
+			return fmt.Errorf("migration %s failed: %v, restore from backup %s failed: %w", pending[i].Name, err, backup, restoreErr)
+		}
+		return fmt.Errorf("migration %s failed, database restored to schema version %d: %w", pending[i].Name, version, err)
+	}
+	return nil
+}
+
+// backfillVersions records versions and buckets of migrations applied before they were
+// tracked and refuses to work with databases migrated by a newer node.
+func (m *Migrator) backfillVersions(sequence []migrations.Migration) error {
+	known := make(map[string]migrations.Migration, len(sequence))
+	for _, migration := range sequence {
+		known[migration.Name] = migration
+	}
+
+	applied, err := m.applied()
+	if err != nil {
+		return err
+	}
+
+	for _, migration := range applied {
+		current, ok := known[migration.Name]
+		if !ok {
+			return fmt.Errorf("%w: unknown migration %s", ErrSchemaTooNew, migration.Name)
+		}
+		if migration.Version == current.Version && len(migration.Buckets) == len(current.Buckets) {
+			continue
+		}
+
+		migration.Version = current.Version
+		migration.Buckets = current.Buckets
+		if err := m.saveMigrationRun(migration); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateSequence(sequence []migrations.Migration) error {
+	names := make(map[string]struct{}, len(sequence))
+	versions := make(map[int]string, len(sequence))
+	for _, migration := range sequence {
+		if migration.Name == "" {
+			return errors.New("migration name is required")
+		}
+		if migration.Version <= 0 {
+			return fmt.Errorf("migration %s has no schema version", migration.Name)
+		}
+		if _, ok := names[migration.Name]; ok {
+			return fmt.Errorf("duplicate migration %s", migration.Name)
+		}
+		if other, ok := versions[migration.Version]; ok {
+			return fmt.Errorf("migrations %s and %s share schema version %d", other, migration.Name, migration.Version)
+		}
+		names[migration.Name] = struct{}{}
+		versions[migration.Version] = migration.Name
 	}
 	return nil
 }
@@ -161,10 +161,12 @@ func TestMigrationSorter(t *testing.T) {
 	defer boltdbtest.RemoveTempDir(t, dir)
 
 	firstMigration := mockMigration
-	firstMigration.Date = time.Date(2018, 12, 04, 12, 00, 00, 0, time.UTC)
+	firstMigration.Version = 1
+	firstMigration.Date = time.Date(2018, 12, 05, 12, 00, 00, 0, time.UTC)
 
 	secondMigration := mockMigration
-	secondMigration.Date = time.Date(2018, 12, 05, 12, 00, 00, 0, time.UTC)
+	secondMigration.Version = 2
+	secondMigration.Date = time.Date(2018, 12, 04, 12, 00, 00, 0, time.UTC)
 
 	migrations := []migrations.Migration{
 		secondMigration, firstMigration,
@@ -173,8 +175,8 @@ func TestMigrationSorter(t *testing.T) {
 	_, migrator := createDBAndMigrator(t, dir)
 	sorted := migrator.sortMigrations(migrations)
 
-	assert.Equal(t, sorted[0].Date, firstMigration.Date)
-	assert.Equal(t, sorted[1].Date, secondMigration.Date)
+	assert.Equal(t, sorted[0].Version, firstMigration.Version)
+	assert.Equal(t, sorted[1].Version, secondMigration.Version)
 }
 
 func TestRunsMigrationsInOrder(t *testing.T) {
@@ -184,6 +186,7 @@ func TestRunsMigrationsInOrder(t *testing.T) {
 	firstMockApplier := &mockMigrationApplier{}
 	firstMigration := migrations.Migration{
 		Name:    "first",
+		Version: 1,
 		Date:    time.Date(2018, 12, 04, 12, 00, 00, 0, time.UTC),
 		Migrate: firstMockApplier.Migrate,
 	}
@@ -191,6 +194,7 @@ func TestRunsMigrationsInOrder(t *testing.T) {
 	secondMockApplier := &mockMigrationApplier{}
 	secondMigration := migrations.Migration{
 		Name:    "second",
+		Version: 2,
 		Date:    time.Date(2018, 12, 05, 12, 00, 00, 0, time.UTC),
 		Migrate: secondMockApplier.Migrate,
 	}
@@ -205,3 +209,127 @@ func TestRunsMigrationsInOrder(t *testing.T) {
 
 	assert.True(t, firstMockApplier.calledAt.Before(secondMockApplier.calledAt))
 }
+
+func TestRejectsInvalidSequence(t *testing.T) {
+	dir := boltdbtest.CreateTempDir(t)
+	defer boltdbtest.RemoveTempDir(t, dir)
+
+	_, migrator := createDBAndMigrator(t, dir)
+
+	unversioned := mockMigration
+	assert.Error(t, migrator.RunMigrations([]migrations.Migration{unversioned}))
+
+	first := mockMigration
+	first.Version = 1
+	second := mockMigration
+	second.Name = "other"
+	second.Version = 1
+	assert.Error(t, migrator.RunMigrations([]migrations.Migration{first, second}))
+}
+
+func TestTracksSchemaVersions(t *testing.T) {
+	dir := boltdbtest.CreateTempDir(t)
+	defer boltdbtest.RemoveTempDir(t, dir)
+
+	_, migrator := createDBAndMigrator(t, dir)
+
+	noop := func(*storm.DB) error { return nil }
+	err := migrator.RunMigrations([]migrations.Migration{
+		{Name: "first", Version: 1, Buckets: []string{"a"}, Migrate: noop},
+		{Name: "second", Version: 2, Buckets: []string{"b"}, Migrate: noop},
+	})
+	assert.NoError(t, err)
+
+	version, err := migrator.SchemaVersion()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, version)
+
+	version, err = migrator.BucketVersion("a")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, version)
+
+	version, err = migrator.BucketVersion("c")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, version)
+}
+
+func TestBackfillsVersionsOfUnversionedRuns(t *testing.T) {
+	dir := boltdbtest.CreateTempDir(t)
+	defer boltdbtest.RemoveTempDir(t, dir)
+
+	_, migrator := createDBAndMigrator(t, dir)
+
+	err := migrator.saveMigrationRun(mockMigration)
+	assert.NoError(t, err)
+
+	mockApplier := &mockMigrationApplier{}
+	versioned := mockMigration
+	versioned.Version = 3
+	versioned.Buckets = []string{"a"}
+	versioned.Migrate = mockApplier.Migrate
+
+	err = migrator.RunMigrations([]migrations.Migration{versioned})
+	assert.NoError(t, err)
+	assert.True(t, mockApplier.calledAt.IsZero())
+
+	version, err := migrator.BucketVersion("a")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, version)
+}
+
+func TestRefusesNewerSchema(t *testing.T) {
+	dir := boltdbtest.CreateTempDir(t)
+	defer boltdbtest.RemoveTempDir(t, dir)
+
+	_, migrator := createDBAndMigrator(t, dir)
+
+	future := mockMigration
+	future.Name = "future"
+	future.Version = 2
+	err := migrator.saveMigrationRun(future)
+	assert.NoError(t, err)
+
+	current := mockMigration
+	current.Version = 1
+	err = migrator.RunMigrations([]migrations.Migration{current})
+	assert.ErrorIs(t, err, ErrSchemaTooNew)
+}
+
+func TestRestoresBackupOnFailure(t *testing.T) {
+	dir := boltdbtest.CreateTempDir(t)
+	defer boltdbtest.RemoveTempDir(t, dir)
+
+	bolt, migrator := createDBAndMigrator(t, dir)
+	defer bolt.Close()
+
+	err := bolt.SetValue("data", "key", "original")
+	assert.NoError(t, err)
+
+	write := func(value string) func(*storm.DB) error {
+		return func(db *storm.DB) error {
+			return db.Set("data", "key", value)
+		}
+	}
+	failure := errors.New("boom")
+	err = migrator.RunMigrations([]migrations.Migration{
+		{Name: "first", Version: 1, Migrate: write("first")},
+		{Name: "second", Version: 2, Migrate: func(db *storm.DB) error {
+			if err := write("second")(db); err != nil {
+				return err
+			}
+			return failure
+		}},
+	})
+	assert.ErrorIs(t, err, failure)
+
+	var value string
+	err = bolt.GetValue("data", "key", &value)
+	assert.NoError(t, err)
+	assert.Equal(t, "original", value)
+
+	version, err := migrator.SchemaVersion()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, version)
+
+	assert.FileExists(t, bolt.Path()+".v0.bak")
+}
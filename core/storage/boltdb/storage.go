@@ -18,11 +18,15 @@
 package boltdb
 
 import (
+	"io"
+	"os"
 	"path/filepath"
 	"sync"
+	"time"
 
 	"github.com/asdine/storm/v3"
 	"github.com/pkg/errors"
+	"go.etcd.io/bbolt"
 )
 
 // Bolt is a wrapper around boltdb
@@ -44,6 +48,23 @@ func openDB(name string) (*Bolt, error) {
 	}, errors.Wrap(err, "failed to open boltDB")
 }
 
+// OpenReadOnly opens an existing BoltDB file, such as an archived copy of the
+// node database, in read-only mode. The file is memory-mapped and records are
+// decoded only when read, so large histories can be streamed without loading
+// them into heap. Other processes may keep the same file open for reading.
+func OpenReadOnly(name string) (*Bolt, error) {
+	db, err := storm.Open(name, storm.BoltOptions(0400, &bbolt.Options{
+		ReadOnly: true,
+		Timeout:  time.Second,
+	}))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open boltDB read-only")
+	}
+	return &Bolt{
+		db: db,
+	}, nil
+}
+
 // GetValue gets key value
 func (b *Bolt) GetValue(bucket string, key interface{}, to interface{}) error {
 	b.mux.RLock()
@@ -114,6 +135,89 @@ func (b *Bolt) GetBuckets() []string {
 	return b.db.Bucket()
 }
 
+// BucketSizes returns the number of bytes allocated by each top level bucket.
+func (b *Bolt) BucketSizes() (map[string]int64, error) {
+	b.mux.RLock()
+	defer b.mux.RUnlock()
+
+	sizes := make(map[string]int64)
+	err := b.db.Bolt.View(func(tx *bbolt.Tx) error {
+		return tx.ForEach(func(name []byte, bucket *bbolt.Bucket) error {
+			stats := bucket.Stats()
+			sizes[string(name)] = int64(stats.BranchAlloc + stats.LeafAlloc + stats.InlineBucketInuse)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not calculate bucket sizes")
+	}
+	return sizes, nil
+}
+
+// Path returns the path of the database file.
+func (b *Bolt) Path() string {
+	return b.db.Bolt.Path()
+}
+
+// Backup writes a consistent copy of the database to the given file.
+func (b *Bolt) Backup(path string) error {
+	b.mux.RLock()
+	defer b.mux.RUnlock()
+
+	err := b.db.Bolt.View(func(tx *bbolt.Tx) error {
+		return tx.CopyFile(path, 0600)
+	})
+	return errors.Wrap(err, "could not back up boltDB")
+}
+
+// Restore replaces the database with the contents of the given backup file and reopens it.
+// It must not be called while raw storm DB handles are in use.
+func (b *Bolt) Restore(backup string) error {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+
+	path := b.db.Bolt.Path()
+	if err := b.db.Close(); err != nil {
+		return errors.Wrap(err, "could not close boltDB")
+	}
+
+	restoreErr := copyFile(backup, path)
+
+	db, err := storm.Open(path)
+	if err != nil {
+		return errors.Wrap(err, "failed to reopen boltDB")
+	}
+	b.db = db
+
+	return errors.Wrap(restoreErr, "could not restore boltDB")
+}
+
+func copyFile(from, to string) error {
+	src, err := os.Open(from)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	tmp := to + ".restore"
+	dst, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		return err
+	}
+	if err := dst.Sync(); err != nil {
+		dst.Close()
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, to)
+}
+
 // DB returns raw storm DB.
 func (b *Bolt) DB() *storm.DB {
 	return b.db
@@ -18,9 +18,11 @@
 package boltdb
 
 import (
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"go.etcd.io/bbolt"
 
 	"github.com/mysteriumnetwork/node/core/storage/boltdb/boltdbtest"
 )
@@ -79,6 +81,21 @@ func Test_StorageGetByID_NotFound(t *testing.T) {
 	assert.Equal(t, "not found", err.Error())
 }
 
+func Test_BucketSizes(t *testing.T) {
+	storage, close, err := createMockStorage(t)
+	assert.Nil(t, err)
+	defer close()
+
+	for i := int64(1); i <= 10; i++ {
+		err = storage.Store(bucket, &myTestType{ID: i})
+		assert.Nil(t, err)
+	}
+
+	sizes, err := storage.BucketSizes()
+	assert.Nil(t, err)
+	assert.Greater(t, sizes[bucket], int64(0))
+}
+
 func Test_GetLastEntryInBucket(t *testing.T) {
 	storage, close, err := createMockStorage(t)
 	assert.Nil(t, err)
@@ -113,3 +130,34 @@ func Test_StorageGetLast_ErrsOnEmpty(t *testing.T) {
 	err = storage.GetLast(bucket, &result)
 	assert.Equal(t, "not found", err.Error())
 }
+
+func Test_OpenReadOnly(t *testing.T) {
+	dir := boltdbtest.CreateTempDir(t)
+	defer boltdbtest.RemoveTempDir(t, dir)
+
+	storage, err := NewStorage(dir)
+	assert.NoError(t, err)
+	err = storage.Store(bucket, &myTestType{ID: 1})
+	assert.NoError(t, err)
+	assert.NoError(t, storage.Close())
+
+	archive, err := OpenReadOnly(filepath.Join(dir, "myst.db"))
+	assert.NoError(t, err)
+	defer archive.Close()
+
+	var result myTestType
+	err = archive.GetOneByField(bucket, "ID", int64(1), &result)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), result.ID)
+
+	err = archive.Store(bucket, &myTestType{ID: 2})
+	assert.ErrorIs(t, err, bbolt.ErrDatabaseReadOnly)
+}
+
+func Test_OpenReadOnly_MissingFile(t *testing.T) {
+	dir := boltdbtest.CreateTempDir(t)
+	defer boltdbtest.RemoveTempDir(t, dir)
+
+	_, err := OpenReadOnly(filepath.Join(dir, "myst.db"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,285 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package watchdog
+
+import (
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// AppTopicComponentHealth is published when a registered component stalls or recovers.
+const AppTopicComponentHealth = "Component health"
+
+// AppEventComponentHealth describes health change of a single component.
+type AppEventComponentHealth struct {
+	Component string
+	Stalled   bool
+	LastBeat  time.Time
+}
+
+// Config configures the watchdog.
+type Config struct {
+	// Interval between component health checks.
+	Interval time.Duration
+	// Restart enables restarting of the stalled components which support it.
+	Restart bool
+}
+
+// ComponentStatus describes health of a single registered component.
+type ComponentStatus struct {
+	Name     string
+	Timeout  time.Duration
+	LastBeat time.Time
+	Stalled  bool
+	Restarts int
+}
+
+type publisher interface {
+	Publish(topic string, data interface{})
+}
+
+type component struct {
+	timeout  time.Duration
+	restart  func()
+	lastBeat time.Time
+	stalled  bool
+	restarts int
+}
+
+// Watchdog keeps track of the long-running components and detects the ones which stopped sending heartbeats.
+// Stalled components are logged along with goroutine stacks and restarted if they support it and restarts are enabled.
+type Watchdog struct {
+	cfg       Config
+	publisher publisher
+	now       func() time.Time
+	stacks    func() []byte
+
+	lock       sync.Mutex
+	components map[string]*component
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewWatchdog creates a new watchdog.
+func NewWatchdog(cfg Config, publisher publisher) *Watchdog {
+	return &Watchdog{
+		cfg:        cfg,
+		publisher:  publisher,
+		now:        time.Now,
+		stacks:     stacks,
+		components: make(map[string]*component),
+		stop:       make(chan struct{}),
+	}
+}
+
+// Register starts tracking the component which is expected to beat at least once per timeout.
+// Restart is called when the component stalls, it may be nil if the component can not be restarted.
+// Registering the same name again replaces the previous registration.
+func (w *Watchdog) Register(name string, timeout time.Duration, restart func()) *Heartbeat {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.components[name] = &component{
+		timeout:  timeout,
+		restart:  restart,
+		lastBeat: w.now(),
+	}
+	return &Heartbeat{watchdog: w, name: name}
+}
+
+// Start checks health of the registered components periodically until stopped.
+func (w *Watchdog) Start() {
+	go func() {
+		ticker := time.NewTicker(w.cfg.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-w.stop:
+				return
+			case <-ticker.C:
+				w.check()
+			}
+		}
+	}()
+}
+
+// Stop stops the watchdog.
+func (w *Watchdog) Stop() {
+	w.stopOnce.Do(func() {
+		close(w.stop)
+	})
+}
+
+// Status returns health of the registered components sorted by name.
+func (w *Watchdog) Status() []ComponentStatus {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	result := make([]ComponentStatus, 0, len(w.components))
+	for name, c := range w.components {
+		result = append(result, ComponentStatus{
+			Name:     name,
+			Timeout:  c.timeout,
+			LastBeat: c.lastBeat,
+			Stalled:  c.stalled,
+			Restarts: c.restarts,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// Degraded returns names of the stalled components.
+func (w *Watchdog) Degraded() []string {
+	var result []string
+	for _, s := range w.Status() {
+		if s.Stalled {
+			result = append(result, s.Name)
+		}
+	}
+	return result
+}
+
+func (w *Watchdog) beat(name string) {
+	w.lock.Lock()
+	c, ok := w.components[name]
+	if !ok {
+		w.lock.Unlock()
+		return
+	}
+	now := w.now()
+	c.lastBeat = now
+	recovered := c.stalled
+	c.stalled = false
+	w.lock.Unlock()
+
+	if recovered {
+		log.Info().Msgf("Component %s recovered", name)
+		w.publish(name, false, now)
+	}
+}
+
+func (w *Watchdog) unregister(name string) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	delete(w.components, name)
+}
+
+func (w *Watchdog) check() {
+	now := w.now()
+
+	type stall struct {
+		name     string
+		lastBeat time.Time
+		restart  func()
+	}
+	var stalled []stall
+
+	w.lock.Lock()
+	for name, c := range w.components {
+		if c.stalled || now.Sub(c.lastBeat) <= c.timeout {
+			continue
+		}
+
+		s := stall{name: name, lastBeat: c.lastBeat}
+		if w.cfg.Restart && c.restart != nil {
+			// restarted component gets another timeout to resume beating
+			s.restart = c.restart
+			c.restarts++
+			c.lastBeat = now
+		} else {
+			c.stalled = true
+		}
+		stalled = append(stalled, s)
+	}
+	w.lock.Unlock()
+
+	if len(stalled) == 0 {
+		return
+	}
+
+	stacks := string(w.stacks())
+	for _, s := range stalled {
+		log.Error().
+			Time("last_beat", s.lastBeat).
+			Str("stacks", stacks).
+			Msgf("Component %s stalled, no heartbeat for %s", s.name, now.Sub(s.lastBeat))
+		w.publish(s.name, true, s.lastBeat)
+
+		if s.restart != nil {
+			log.Warn().Msgf("Restarting stalled component %s", s.name)
+			go s.restart()
+		}
+	}
+}
+
+func (w *Watchdog) publish(name string, stalled bool, lastBeat time.Time) {
+	if w.publisher == nil {
+		return
+	}
+	w.publisher.Publish(AppTopicComponentHealth, AppEventComponentHealth{
+		Component: name,
+		Stalled:   stalled,
+		LastBeat:  lastBeat,
+	})
+}
+
+// Heartbeat is used by the registered component to report it is alive.
+// Nil heartbeat is valid and does nothing, so that components work without the watchdog.
+type Heartbeat struct {
+	watchdog *Watchdog
+	name     string
+}
+
+// Beat reports the component is alive.
+func (h *Heartbeat) Beat() {
+	if h == nil {
+		return
+	}
+	h.watchdog.beat(h.name)
+}
+
+// Unregister stops tracking the component.
+func (h *Heartbeat) Unregister() {
+	if h == nil {
+		return
+	}
+	h.watchdog.unregister(h.name)
+}
+
+func stacks() []byte {
+	buf := make([]byte, 1<<16)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			return buf[:n]
+		}
+		if len(buf) >= 1<<22 {
+			return buf
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
@@ -0,0 +1,106 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package watchdog
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockPublisher struct {
+	published []interface{}
+}
+
+func (m *mockPublisher) Publish(_ string, data interface{}) {
+	m.published = append(m.published, data)
+}
+
+func newTestWatchdog(cfg Config, now *time.Time, pub publisher) *Watchdog {
+	w := NewWatchdog(cfg, pub)
+	w.now = func() time.Time { return *now }
+	w.stacks = func() []byte { return []byte("goroutine 1 [running]") }
+	return w
+}
+
+func TestWatchdog_DetectsStalledComponent(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	pub := &mockPublisher{}
+	w := newTestWatchdog(Config{}, &now, pub)
+
+	registrar := w.Register("registrar", time.Minute, nil)
+	settler := w.Register("settler", time.Minute, nil)
+
+	now = now.Add(30 * time.Second)
+	settler.Beat()
+	now = now.Add(45 * time.Second)
+	w.check()
+	assert.Equal(t, []string{"registrar"}, w.Degraded())
+	assert.Equal(t, []interface{}{
+		AppEventComponentHealth{Component: "registrar", Stalled: true, LastBeat: now.Add(-75 * time.Second)},
+	}, pub.published)
+
+	// stall is reported once
+	now = now.Add(time.Minute)
+	w.check()
+	assert.ElementsMatch(t, []string{"registrar", "settler"}, w.Degraded())
+	assert.Len(t, pub.published, 2)
+
+	registrar.Beat()
+	assert.Equal(t, []string{"settler"}, w.Degraded())
+	assert.Equal(t, AppEventComponentHealth{Component: "registrar", Stalled: false, LastBeat: now}, pub.published[2])
+
+	settler.Unregister()
+	assert.Empty(t, w.Degraded())
+	status := w.Status()
+	assert.Len(t, status, 1)
+	assert.Equal(t, ComponentStatus{Name: "registrar", Timeout: time.Minute, LastBeat: now}, status[0])
+}
+
+func TestWatchdog_RestartsStalledComponent(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	restarted := make(chan struct{}, 1)
+	restart := func() { restarted <- struct{}{} }
+
+	w := newTestWatchdog(Config{Restart: true}, &now, nil)
+	w.Register("restartable", time.Minute, restart)
+	w.Register("fixed", time.Minute, nil)
+
+	now = now.Add(2 * time.Minute)
+	w.check()
+
+	select {
+	case <-restarted:
+	case <-time.After(time.Second):
+		t.Fatal("stalled component was not restarted")
+	}
+	assert.Equal(t, []string{"fixed"}, w.Degraded())
+	status := w.Status()
+	assert.Equal(t, "restartable", status[1].Name)
+	assert.Equal(t, 1, status[1].Restarts)
+	assert.Equal(t, now, status[1].LastBeat)
+}
+
+func TestHeartbeat_Nil(t *testing.T) {
+	var h *Heartbeat
+	assert.NotPanics(t, func() {
+		h.Beat()
+		h.Unregister()
+	})
+}
@@ -0,0 +1,282 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/core/compliance"
+	"github.com/mysteriumnetwork/node/eventbus"
+	"github.com/mysteriumnetwork/node/identity/registry"
+	sessionEvent "github.com/mysteriumnetwork/node/session/event"
+	pingpongEvent "github.com/mysteriumnetwork/node/session/pingpong/event"
+)
+
+const (
+	// EventSessionStarted is sent when a provider session is created.
+	EventSessionStarted = "session_started"
+	// EventSessionEnded is sent when a provider session is removed.
+	EventSessionEnded = "session_ended"
+	// EventSettlementComplete is sent when provider promises are settled.
+	EventSettlementComplete = "settlement_complete"
+	// EventRegistrationComplete is sent when an identity becomes registered.
+	EventRegistrationComplete = "registration_complete"
+	// EventTermsReacceptanceRequired is sent when the accepted terms of use are outdated and have to be accepted again.
+	EventTermsReacceptanceRequired = "terms_reacceptance_required"
+
+	// EventHeader holds the name of the delivered event.
+	EventHeader = "X-Mysterium-Event"
+	// SignatureHeader holds hex encoded HMAC-SHA256 of the request body, prefixed with "sha256=".
+	SignatureHeader = "X-Mysterium-Signature"
+)
+
+// Events lists all events which can be delivered.
+var Events = []string{EventSessionStarted, EventSessionEnded, EventSettlementComplete, EventRegistrationComplete, EventTermsReacceptanceRequired}
+
+// Config configures webhook delivery.
+type Config struct {
+	URLs       []string
+	Secret     string
+	Events     []string
+	MaxRetries uint64
+}
+
+// Payload is the body of a webhook request.
+type Payload struct {
+	Event     string      `json:"event"`
+	Timestamp time.Time   `json:"timestamp"`
+	Data      interface{} `json:"data"`
+}
+
+// SessionData describes a session in webhook payload.
+type SessionData struct {
+	SessionID   string    `json:"session_id"`
+	ServiceID   string    `json:"service_id"`
+	ServiceType string    `json:"service_type"`
+	ConsumerID  string    `json:"consumer_id"`
+	HermesID    string    `json:"hermes_id"`
+	StartedAt   time.Time `json:"started_at"`
+}
+
+// SettlementData describes a settlement in webhook payload.
+type SettlementData struct {
+	ProviderID string `json:"provider_id"`
+	HermesID   string `json:"hermes_id"`
+	ChainID    int64  `json:"chain_id"`
+}
+
+// RegistrationData describes a registration in webhook payload.
+type RegistrationData struct {
+	Identity string `json:"identity"`
+	ChainID  int64  `json:"chain_id"`
+}
+
+// TermsData describes outdated terms acceptance in webhook payload.
+type TermsData struct {
+	Role            string `json:"role"`
+	AcceptedVersion string `json:"accepted_version"`
+	CurrentVersion  string `json:"current_version"`
+}
+
+type httpClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Emitter delivers selected node events to operator URLs.
+type Emitter struct {
+	client httpClient
+	cfg    Config
+	events map[string]struct{}
+
+	ctx     context.Context
+	cancel  context.CancelFunc
+	pending sync.WaitGroup
+
+	newBackOff func() backoff.BackOff
+}
+
+// NewEmitter creates a webhook emitter.
+func NewEmitter(client httpClient, cfg Config) *Emitter {
+	events := make(map[string]struct{}, len(cfg.Events))
+	for _, e := range cfg.Events {
+		events[e] = struct{}{}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Emitter{
+		client: client,
+		cfg:    cfg,
+		events: events,
+		ctx:    ctx,
+		cancel: cancel,
+		newBackOff: func() backoff.BackOff {
+			return backoff.NewExponentialBackOff()
+		},
+	}
+}
+
+// Subscribe subscribes to relevant events of event bus.
+func (e *Emitter) Subscribe(bus eventbus.Subscriber) error {
+	subscription := map[string]interface{}{
+		sessionEvent.AppTopicSession:             e.handleSessionEvent,
+		pingpongEvent.AppTopicSettlementComplete: e.handleSettlementEvent,
+		registry.AppTopicIdentityRegistration:    e.handleRegistrationEvent,
+		compliance.AppTopicTerms:                 e.handleTermsEvent,
+	}
+
+	for topic, fn := range subscription {
+		if err := bus.SubscribeAsync(topic, fn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Stop cancels pending deliveries and waits for them to finish.
+func (e *Emitter) Stop() {
+	e.cancel()
+	e.pending.Wait()
+}
+
+// Emit delivers the event to all configured URLs, unless it is not selected.
+func (e *Emitter) Emit(event string, data interface{}) {
+	if _, ok := e.events[event]; !ok {
+		return
+	}
+
+	body, err := json.Marshal(Payload{
+		Event:     event,
+		Timestamp: time.Now().UTC(),
+		Data:      data,
+	})
+	if err != nil {
+		log.Err(err).Msgf("Failed to marshal webhook %s payload", event)
+		return
+	}
+
+	for _, url := range e.cfg.URLs {
+		e.pending.Add(1)
+		go func(url string) {
+			defer e.pending.Done()
+			if err := e.deliver(url, event, body); err != nil {
+				log.Warn().Err(err).Msgf("Failed to deliver webhook %s to %s", event, url)
+			}
+		}(url)
+	}
+}
+
+func (e *Emitter) deliver(url, event string, body []byte) error {
+	boff := backoff.WithContext(backoff.WithMaxRetries(e.newBackOff(), e.cfg.MaxRetries), e.ctx)
+
+	return backoff.Retry(func() error {
+		req, err := http.NewRequestWithContext(e.ctx, http.MethodPost, url, bytes.NewReader(body))
+		if err != nil {
+			return backoff.Permanent(err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(EventHeader, event)
+		if e.cfg.Secret != "" {
+			req.Header.Set(SignatureHeader, "sha256="+Sign(e.cfg.Secret, body))
+		}
+
+		res, err := e.client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer res.Body.Close()
+
+		if res.StatusCode >= 200 && res.StatusCode < 300 {
+			return nil
+		}
+		err = fmt.Errorf("unexpected response status %d", res.StatusCode)
+		// Client errors will not go away by retrying, except for rate limiting.
+		if res.StatusCode >= 400 && res.StatusCode < 500 && res.StatusCode != http.StatusTooManyRequests {
+			return backoff.Permanent(err)
+		}
+		return err
+	}, boff)
+}
+
+// Sign returns hex encoded HMAC-SHA256 of the body.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (e *Emitter) handleSessionEvent(ev sessionEvent.AppEventSession) {
+	data := SessionData{
+		SessionID:   ev.Session.ID,
+		ServiceID:   ev.Service.ID,
+		ServiceType: ev.Session.Proposal.ServiceType,
+		ConsumerID:  ev.Session.ConsumerID.Address,
+		HermesID:    ev.Session.HermesID.Hex(),
+		StartedAt:   ev.Session.StartedAt,
+	}
+
+	switch ev.Status {
+	case sessionEvent.CreatedStatus:
+		e.Emit(EventSessionStarted, data)
+	case sessionEvent.RemovedStatus:
+		e.Emit(EventSessionEnded, data)
+	}
+}
+
+func (e *Emitter) handleSettlementEvent(ev pingpongEvent.AppEventSettlementComplete) {
+	e.Emit(EventSettlementComplete, SettlementData{
+		ProviderID: ev.ProviderID.Address,
+		HermesID:   ev.HermesID.Hex(),
+		ChainID:    ev.ChainID,
+	})
+}
+
+func (e *Emitter) handleRegistrationEvent(ev registry.AppEventIdentityRegistration) {
+	if ev.Status != registry.Registered {
+		return
+	}
+
+	e.Emit(EventRegistrationComplete, RegistrationData{
+		Identity: ev.ID.Address,
+		ChainID:  ev.ChainID,
+	})
+}
+
+func (e *Emitter) handleTermsEvent(ev compliance.AppEventTerms) {
+	if !ev.ReacceptanceRequired {
+		return
+	}
+
+	e.Emit(EventTermsReacceptanceRequired, TermsData{
+		Role:            ev.Role,
+		AcceptedVersion: ev.AcceptedVersion,
+		CurrentVersion:  ev.CurrentVersion,
+	})
+}
@@ -0,0 +1,143 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package webhook
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mysteriumnetwork/node/core/compliance"
+	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/identity/registry"
+	sessionEvent "github.com/mysteriumnetwork/node/session/event"
+)
+
+func TestEmitter_DeliversSignedPayload(t *testing.T) {
+	var calls int32
+	received := make(chan Payload, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Fail the first attempt to make sure delivery is retried.
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, EventSessionStarted, r.Header.Get(EventHeader))
+		assert.Equal(t, "sha256="+Sign("secret", body), r.Header.Get(SignatureHeader))
+
+		var p Payload
+		assert.NoError(t, json.Unmarshal(body, &p))
+		received <- p
+	}))
+	defer server.Close()
+
+	emitter := newTestEmitter(server.URL, []string{EventSessionStarted})
+	emitter.handleSessionEvent(sessionEvent.AppEventSession{
+		Status:  sessionEvent.CreatedStatus,
+		Session: sessionEvent.SessionContext{ID: "session-1", ConsumerID: identity.FromAddress("0x1")},
+	})
+
+	select {
+	case p := <-received:
+		assert.Equal(t, EventSessionStarted, p.Event)
+		assert.Equal(t, "session-1", p.Data.(map[string]interface{})["session_id"])
+	case <-time.After(2 * time.Second):
+		t.Fatal("webhook was not delivered")
+	}
+	emitter.Stop()
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestEmitter_SkipsUnselectedEvents(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+	}))
+	defer server.Close()
+
+	emitter := newTestEmitter(server.URL, []string{EventSessionStarted})
+	emitter.handleSessionEvent(sessionEvent.AppEventSession{Status: sessionEvent.RemovedStatus})
+	emitter.handleRegistrationEvent(registry.AppEventIdentityRegistration{Status: registry.Registered})
+	emitter.Stop()
+
+	assert.Equal(t, int32(0), atomic.LoadInt32(&calls))
+}
+
+func TestEmitter_DoesNotRetryClientErrors(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	emitter := newTestEmitter(server.URL, Events)
+	emitter.handleRegistrationEvent(registry.AppEventIdentityRegistration{Status: registry.Registered})
+	emitter.pending.Wait()
+	emitter.Stop()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestEmitter_DeliversOnlyOutdatedTerms(t *testing.T) {
+	received := make(chan Payload, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var p Payload
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&p))
+		received <- p
+	}))
+	defer server.Close()
+
+	emitter := newTestEmitter(server.URL, Events)
+	emitter.handleTermsEvent(compliance.AppEventTerms{Role: "provider", AcceptedVersion: "0.0.28", CurrentVersion: "0.0.28"})
+	emitter.handleTermsEvent(compliance.AppEventTerms{Role: "provider", AcceptedVersion: "0.0.27", CurrentVersion: "0.0.28", ReacceptanceRequired: true})
+	emitter.pending.Wait()
+	emitter.Stop()
+
+	require.Len(t, received, 1)
+	p := <-received
+	assert.Equal(t, EventTermsReacceptanceRequired, p.Event)
+	assert.Equal(t, map[string]interface{}{
+		"role":             "provider",
+		"accepted_version": "0.0.27",
+		"current_version":  "0.0.28",
+	}, p.Data)
+}
+
+func newTestEmitter(url string, events []string) *Emitter {
+	emitter := NewEmitter(http.DefaultClient, Config{
+		URLs:       []string{url},
+		Secret:     "secret",
+		Events:     events,
+		MaxRetries: 3,
+	})
+	emitter.newBackOff = func() backoff.BackOff {
+		return backoff.NewConstantBackOff(10 * time.Millisecond)
+	}
+	return emitter
+}
@@ -0,0 +1,78 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package identity
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Attestation is a statement about the node, signed by its identity. The nonce is supplied
+// by the verifying party, so that a signed attestation can not be replayed by anyone else.
+type Attestation struct {
+	Identity  string `json:"identity"`
+	Nonce     string `json:"nonce"`
+	Version   string `json:"version"`
+	Uptime    uint64 `json:"uptime"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+// SignedAttestation holds the attestation along with the exact signed message and its signature.
+type SignedAttestation struct {
+	Attestation
+	Message   []byte
+	Signature Signature
+}
+
+// Attest signs the attestation with the given signer.
+func Attest(signer Signer, attestation Attestation) (SignedAttestation, error) {
+	message, err := json.Marshal(attestation)
+	if err != nil {
+		return SignedAttestation{}, err
+	}
+
+	signature, err := signer.Sign(message)
+	if err != nil {
+		return SignedAttestation{}, fmt.Errorf("could not sign attestation: %w", err)
+	}
+
+	return SignedAttestation{
+		Attestation: attestation,
+		Message:     message,
+		Signature:   signature,
+	}, nil
+}
+
+// VerifyAttestation checks that the message was signed by the identity it attests and returns the attestation.
+func VerifyAttestation(message []byte, signature Signature) (Attestation, error) {
+	var attestation Attestation
+	if err := json.Unmarshal(message, &attestation); err != nil {
+		return Attestation{}, fmt.Errorf("could not parse attestation: %w", err)
+	}
+
+	signer, err := NewExtractor().Extract(message, signature)
+	if err != nil {
+		return Attestation{}, fmt.Errorf("could not extract attestation signer: %w", err)
+	}
+	if !strings.EqualFold(signer.Address, attestation.Identity) {
+		return Attestation{}, fmt.Errorf("attestation of %s is signed by %s", attestation.Identity, signer.Address)
+	}
+
+	return attestation, nil
+}
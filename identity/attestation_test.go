@@ -0,0 +1,66 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package identity
+
+import (
+	"testing"
+
+	ethKs "github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/eventbus"
+)
+
+func TestAttestation_SignAndVerify(t *testing.T) {
+	ks := NewKeystoreFilesystem("dir", &ethKeystoreMock{account: signerAccount})
+	ks.loadKey = func(addr common.Address, filename, auth string) (*ethKs.Key, error) {
+		return &ethKs.Key{Address: addr, PrivateKey: signerKey}, nil
+	}
+
+	bus := eventbus.New()
+	manager := NewIdentityManager(ks, bus, NewResidentCountry(bus, newMockLocationResolver("LT")))
+	assert.NoError(t, manager.Unlock(signerChainID, signerAddress, ""))
+
+	attestation := Attestation{
+		Identity:  "0x" + signerAddress,
+		Nonce:     "server-nonce",
+		Version:   "1.2.3",
+		Uptime:    3600,
+		Timestamp: 1700000000,
+	}
+	signed, err := Attest(NewSigner(ks, FromAddress(signerAddress)), attestation)
+	assert.NoError(t, err)
+
+	verified, err := VerifyAttestation(signed.Message, signed.Signature)
+	assert.NoError(t, err)
+	assert.Equal(t, attestation, verified)
+
+	// attestation of another identity is rejected
+	forged := attestation
+	forged.Identity = "0x0000000000000000000000000000000000000001"
+	forgedSigned, err := Attest(NewSigner(ks, FromAddress(signerAddress)), forged)
+	assert.NoError(t, err)
+	_, err = VerifyAttestation(forgedSigned.Message, forgedSigned.Signature)
+	assert.Error(t, err)
+
+	// tampered message is rejected
+	tampered := []byte(string(signed.Message[:len(signed.Message)-1]) + " }")
+	_, err = VerifyAttestation(tampered, signed.Signature)
+	assert.Error(t, err)
+}
@@ -0,0 +1,266 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package backup
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"golang.org/x/crypto/scrypt"
+
+	"github.com/mysteriumnetwork/node/core/beneficiary"
+	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/identity/registry"
+)
+
+const (
+	version = 1
+	kdf     = "scrypt"
+
+	// scrypt parameters of the standard keystore encryption are used, as bundles are stored outside the node.
+	scryptN = 1 << 18
+	scryptR = 8
+	scryptP = 1
+	keyLen  = 32
+	saltLen = 32
+)
+
+var (
+	// ErrDecrypt is returned when the bundle can not be decrypted with the given passphrase.
+	ErrDecrypt = errors.New("could not decrypt the bundle, wrong passphrase or corrupted data")
+	// ErrUnsupportedBundle is returned for bundles of unknown version or format.
+	ErrUnsupportedBundle = errors.New("unsupported identity bundle")
+)
+
+type mover interface {
+	Export(address, currPass, newPass string) ([]byte, error)
+	Import(blob []byte, currPass, newPass string) (identity.Identity, error)
+}
+
+type registrationChecker interface {
+	GetRegistrationStatus(chainID int64, id identity.Identity) (registry.RegistrationStatus, error)
+}
+
+// Registration is the identity registration status on a single chain.
+type Registration struct {
+	ChainID int64                       `json:"chain_id"`
+	Status  registry.RegistrationStatus `json:"status"`
+}
+
+// Bundle holds everything needed to move identity to another node.
+type Bundle struct {
+	Address string `json:"address"`
+	// Key is the keystore JSON encrypted with the bundle passphrase.
+	Key           json.RawMessage `json:"key"`
+	Beneficiary   string          `json:"beneficiary,omitempty"`
+	Registrations []Registration  `json:"registrations"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+// Restored describes the identity imported from the bundle.
+type Restored struct {
+	Identity    identity.Identity
+	Beneficiary string
+	// Registrations are checked again after import, bundle statuses are used only when the check fails.
+	Registrations []Registration
+}
+
+type envelope struct {
+	Version    int    `json:"version"`
+	Address    string `json:"address"`
+	KDF        string `json:"kdf"`
+	N          int    `json:"n"`
+	R          int    `json:"r"`
+	P          int    `json:"p"`
+	Salt       []byte `json:"salt"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// Backup exports identities into passphrase encrypted bundles and imports them back.
+type Backup struct {
+	mover         mover
+	beneficiaries beneficiary.BeneficiaryStorage
+	registry      registrationChecker
+	chains        []int64
+	scryptN       int
+	timeGetter    func() time.Time
+}
+
+// NewBackup creates identity backup for the given chains.
+func NewBackup(mover mover, beneficiaries beneficiary.BeneficiaryStorage, registry registrationChecker, chains []int64) *Backup {
+	return &Backup{
+		mover:         mover,
+		beneficiaries: beneficiaries,
+		registry:      registry,
+		chains:        chains,
+		scryptN:       scryptN,
+		timeGetter:    time.Now,
+	}
+}
+
+// Export exports identity keystore, beneficiary and registration statuses as a bundle encrypted with the passphrase.
+func (b *Backup) Export(address, currPass, passphrase string) ([]byte, error) {
+	key, err := b.mover.Export(address, currPass, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	id := identity.FromAddress(address)
+	bundle := Bundle{
+		Address:       id.Address,
+		Key:           key,
+		Registrations: make([]Registration, 0, len(b.chains)),
+		CreatedAt:     b.timeGetter().UTC(),
+	}
+
+	bundle.Beneficiary, err = b.beneficiaries.Address(id.Address)
+	if err != nil && !errors.Is(err, beneficiary.ErrNotFound) {
+		return nil, fmt.Errorf("could not get beneficiary: %w", err)
+	}
+
+	for _, chainID := range b.chains {
+		status, err := b.registry.GetRegistrationStatus(chainID, id)
+		if err != nil {
+			return nil, fmt.Errorf("could not get registration status on chain %d: %w", chainID, err)
+		}
+		bundle.Registrations = append(bundle.Registrations, Registration{ChainID: chainID, Status: status})
+	}
+
+	plaintext, err := json.Marshal(bundle)
+	if err != nil {
+		return nil, err
+	}
+	return b.seal(id.Address, plaintext, passphrase)
+}
+
+// Import imports identity from the bundle, storing its key with the new passphrase,
+// and checks its registration status again, as it might have changed since the export.
+func (b *Backup) Import(data []byte, passphrase, newPass string) (Restored, error) {
+	plaintext, err := open(data, passphrase)
+	if err != nil {
+		return Restored{}, err
+	}
+
+	var bundle Bundle
+	if err := json.Unmarshal(plaintext, &bundle); err != nil {
+		return Restored{}, fmt.Errorf("%w: %v", ErrUnsupportedBundle, err)
+	}
+
+	id, err := b.mover.Import(bundle.Key, passphrase, newPass)
+	if err != nil {
+		return Restored{}, err
+	}
+
+	restored := Restored{Identity: id, Beneficiary: bundle.Beneficiary}
+	if bundle.Beneficiary != "" {
+		if err := b.beneficiaries.Save(id.Address, bundle.Beneficiary); err != nil {
+			return restored, fmt.Errorf("could not save beneficiary: %w", err)
+		}
+	}
+
+	known := make(map[int64]registry.RegistrationStatus, len(bundle.Registrations))
+	for _, r := range bundle.Registrations {
+		known[r.ChainID] = r.Status
+	}
+	for _, chainID := range b.chains {
+		reg := Registration{ChainID: chainID, Status: known[chainID]}
+		status, err := b.registry.GetRegistrationStatus(chainID, id)
+		if err != nil {
+			log.Warn().Err(err).Msgf("Could not check registration of imported identity %s on chain %d", id.Address, chainID)
+		} else {
+			reg.Status = status
+		}
+		restored.Registrations = append(restored.Registrations, reg)
+	}
+
+	return restored, nil
+}
+
+func (b *Backup) seal(address string, plaintext []byte, passphrase string) ([]byte, error) {
+	salt := make([]byte, saltLen)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, err
+	}
+
+	env := envelope{
+		Version: version,
+		Address: address,
+		KDF:     kdf,
+		N:       b.scryptN,
+		R:       scryptR,
+		P:       scryptP,
+		Salt:    salt,
+	}
+	gcm, err := env.cipher(passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	env.Ciphertext = gcm.Seal(nonce, nonce, plaintext, []byte(address))
+
+	return json.Marshal(env)
+}
+
+func open(data []byte, passphrase string) ([]byte, error) {
+	var env envelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedBundle, err)
+	}
+	if env.Version != version || env.KDF != kdf || env.N > scryptN {
+		return nil, ErrUnsupportedBundle
+	}
+
+	gcm, err := env.cipher(passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	nonceSize := gcm.NonceSize()
+	if len(env.Ciphertext) < nonceSize {
+		return nil, ErrDecrypt
+	}
+	plaintext, err := gcm.Open(nil, env.Ciphertext[:nonceSize], env.Ciphertext[nonceSize:], []byte(env.Address))
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	return plaintext, nil
+}
+
+func (env envelope) cipher(passphrase string) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), env.Salt, env.N, env.R, env.P, keyLen)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedBundle, err)
+	}
+
+	c, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(c)
+}
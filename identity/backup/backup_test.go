@@ -0,0 +1,165 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package backup
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mysteriumnetwork/node/core/beneficiary"
+	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/identity/registry"
+)
+
+const address = "0x000000000000000000000000000000000000000a"
+
+type mockMover struct {
+	key      []byte
+	imported []byte
+	pass     string
+	newPass  string
+}
+
+func (m *mockMover) Export(_, _, newPass string) ([]byte, error) {
+	m.pass = newPass
+	return m.key, nil
+}
+
+func (m *mockMover) Import(blob []byte, currPass, newPass string) (identity.Identity, error) {
+	if currPass != m.pass {
+		return identity.Identity{}, errors.New("could not decrypt key with given password")
+	}
+	m.imported = blob
+	m.newPass = newPass
+	return identity.FromAddress(address), nil
+}
+
+type mockBeneficiaries struct {
+	addresses map[string]string
+}
+
+func (m *mockBeneficiaries) Address(identity string) (string, error) {
+	addr, ok := m.addresses[identity]
+	if !ok {
+		return "", beneficiary.ErrNotFound
+	}
+	return addr, nil
+}
+
+func (m *mockBeneficiaries) Save(identity, address string) error {
+	m.addresses[identity] = address
+	return nil
+}
+
+type mockRegistry struct {
+	statuses map[int64]registry.RegistrationStatus
+	err      error
+}
+
+func (m *mockRegistry) GetRegistrationStatus(chainID int64, _ identity.Identity) (registry.RegistrationStatus, error) {
+	return m.statuses[chainID], m.err
+}
+
+func newTestBackup(mover *mockMover, beneficiaries *mockBeneficiaries, reg *mockRegistry) *Backup {
+	b := NewBackup(mover, beneficiaries, reg, []int64{1, 137})
+	b.scryptN = 1 << 10
+	b.timeGetter = func() time.Time { return time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC) }
+	return b
+}
+
+func TestBackup_ExportImport(t *testing.T) {
+	mover := &mockMover{key: []byte(`{"address":"000000000000000000000000000000000000000a"}`)}
+	exported := newTestBackup(
+		mover,
+		&mockBeneficiaries{addresses: map[string]string{address: "0x00000000000000000000000000000000000000bb"}},
+		&mockRegistry{statuses: map[int64]registry.RegistrationStatus{1: registry.Registered, 137: registry.Unregistered}},
+	)
+
+	data, err := exported.Export(address, "", "secret")
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "00000000000000000000000000000000000000bb")
+
+	beneficiaries := &mockBeneficiaries{addresses: map[string]string{}}
+	imported := newTestBackup(
+		mover,
+		beneficiaries,
+		&mockRegistry{statuses: map[int64]registry.RegistrationStatus{1: registry.Registered, 137: registry.Registered}},
+	)
+
+	_, err = imported.Import(data, "wrong", "")
+	assert.ErrorIs(t, err, ErrDecrypt)
+
+	restored, err := imported.Import(data, "secret", "new")
+	require.NoError(t, err)
+	assert.Equal(t, address, restored.Identity.Address)
+	assert.JSONEq(t, string(mover.key), string(mover.imported))
+	assert.Equal(t, "new", mover.newPass)
+	assert.Equal(t, "0x00000000000000000000000000000000000000bb", beneficiaries.addresses[address])
+	assert.Equal(t, []Registration{
+		{ChainID: 1, Status: registry.Registered},
+		{ChainID: 137, Status: registry.Registered},
+	}, restored.Registrations)
+}
+
+func TestBackup_ImportKeepsBundleStatusWhenCheckFails(t *testing.T) {
+	mover := &mockMover{key: []byte(`{}`)}
+	reg := &mockRegistry{statuses: map[int64]registry.RegistrationStatus{1: registry.Registered, 137: registry.InProgress}}
+	b := newTestBackup(mover, &mockBeneficiaries{addresses: map[string]string{}}, reg)
+
+	data, err := b.Export(address, "", "secret")
+	require.NoError(t, err)
+
+	reg.err = errors.New("blockchain unavailable")
+	restored, err := b.Import(data, "secret", "")
+	require.NoError(t, err)
+	assert.Empty(t, restored.Beneficiary)
+	assert.Equal(t, []Registration{
+		{ChainID: 1, Status: registry.Registered},
+		{ChainID: 137, Status: registry.InProgress},
+	}, restored.Registrations)
+}
+
+func TestBackup_ImportRejectsTamperedBundle(t *testing.T) {
+	b := newTestBackup(&mockMover{key: []byte(`{}`)}, &mockBeneficiaries{addresses: map[string]string{}}, &mockRegistry{})
+
+	data, err := b.Export(address, "", "secret")
+	require.NoError(t, err)
+
+	var env envelope
+	require.NoError(t, json.Unmarshal(data, &env))
+
+	env.Address = "0x000000000000000000000000000000000000000b"
+	tampered, err := json.Marshal(env)
+	require.NoError(t, err)
+	_, err = b.Import(tampered, "secret", "")
+	assert.ErrorIs(t, err, ErrDecrypt)
+
+	env.N = scryptN * 2
+	tampered, err = json.Marshal(env)
+	require.NoError(t, err)
+	_, err = b.Import(tampered, "secret", "")
+	assert.ErrorIs(t, err, ErrUnsupportedBundle)
+
+	_, err = b.Import([]byte("not a bundle"), "secret", "")
+	assert.ErrorIs(t, err, ErrUnsupportedBundle)
+}
@@ -26,14 +26,19 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math/big"
 	"os"
+	"path/filepath"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/ethereum/go-ethereum/accounts"
 	ethKs "github.com/ethereum/go-ethereum/accounts/keystore"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/rs/zerolog/log"
 	"golang.org/x/crypto/hkdf"
 )
 
@@ -44,6 +49,7 @@ type ethKeystore interface {
 	Find(a accounts.Account) (accounts.Account, error)
 	Export(a accounts.Account, passphrase, newPassphrase string) ([]byte, error)
 	Import(keyJSON []byte, passphrase, newPassphrase string) (accounts.Account, error)
+	Update(a accounts.Account, passphrase, newPassphrase string) error
 }
 
 // NewKeystoreFilesystem create new keystore, which keeps keys in filesystem.
@@ -51,6 +57,7 @@ func NewKeystoreFilesystem(directory string, ks ethKeystore) *Keystore {
 	return &Keystore{
 		ethKeystore: ks,
 		loadKey:     loadStoredKey,
+		now:         time.Now,
 		unlocked:    make(map[common.Address]*unlocked),
 	}
 }
@@ -59,9 +66,22 @@ func NewKeystoreFilesystem(directory string, ks ethKeystore) *Keystore {
 type Keystore struct {
 	ethKeystore
 	loadKey func(addr common.Address, filename, auth string) (*ethKs.Key, error)
+	now     func() time.Time
 
 	unlocked map[common.Address]*unlocked // Currently unlocked account (decrypted private keys)
 	mu       sync.RWMutex
+
+	// lockMemory keeps decrypted private keys in memory which is never swapped.
+	lockMemory bool
+}
+
+// SetMemoryLock sets whether decrypted private keys are kept in memory which is never swapped,
+// on platforms supporting it. Applies to accounts unlocked afterwards.
+func (ks *Keystore) SetMemoryLock(enabled bool) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	ks.lockMemory = enabled
 }
 
 // Unlock unlocks the given account indefinitely.
@@ -106,6 +126,7 @@ func (ks *Keystore) TimedUnlock(a accounts.Account, passphrase string, timeout t
 		}
 		// Terminate the expire goroutine and replace it below.
 		close(u.abort)
+		u.wipe()
 	}
 	if timeout > 0 {
 		u = &unlocked{Key: key, abort: make(chan struct{})}
@@ -113,10 +134,58 @@ func (ks *Keystore) TimedUnlock(a accounts.Account, passphrase string, timeout t
 	} else {
 		u = &unlocked{Key: key}
 	}
+	if ks.lockMemory {
+		release, err := lockKeyMemory(key.PrivateKey)
+		if err != nil {
+			log.Warn().Err(err).Msgf("Could not lock memory of %s private key, it may be swapped", a.Address.Hex())
+		}
+		u.releaseMemory = release
+	}
+	u.touch(ks.now())
 	ks.unlocked[a.Address] = u
 	return nil
 }
 
+// LockIdle removes from memory the private keys which were not used for longer than ttl
+// and returns addresses of the locked accounts. Accounts for which keep returns true stay unlocked.
+func (ks *Keystore) LockIdle(ttl time.Duration, keep func(common.Address) bool) []common.Address {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	now := ks.now()
+	var locked []common.Address
+	for addr, u := range ks.unlocked {
+		if u.idleFor(now) < ttl || keep(addr) {
+			continue
+		}
+		if u.abort != nil {
+			close(u.abort)
+		}
+		u.wipe()
+		delete(ks.unlocked, addr)
+		locked = append(locked, addr)
+	}
+	return locked
+}
+
+// LockAll removes all private keys from memory, e.g. before the process exits,
+// and returns addresses of the locked accounts.
+func (ks *Keystore) LockAll() []common.Address {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	var locked []common.Address
+	for addr, u := range ks.unlocked {
+		if u.abort != nil {
+			close(u.abort)
+		}
+		u.wipe()
+		delete(ks.unlocked, addr)
+		locked = append(locked, addr)
+	}
+	return locked
+}
+
 func (ks *Keystore) getDecryptedKey(a accounts.Account, auth string) (accounts.Account, *ethKs.Key, error) {
 	a, err := ks.ethKeystore.Find(a)
 	if err != nil {
@@ -139,13 +208,49 @@ func (ks *Keystore) expire(addr common.Address, u *unlocked, timeout time.Durati
 		// because the map stores a new pointer every time the key is
 		// unlocked.
 		if ks.unlocked[addr] == u {
-			zeroKey(u.PrivateKey)
+			u.wipe()
 			delete(ks.unlocked, addr)
 		}
 		ks.mu.Unlock()
 	}
 }
 
+// ChangePassphrase re-encrypts the key file of the given account with the new passphrase.
+// The previous key file is kept as a backup until the re-encrypted one is verified and
+// restored if verification fails. Backup is hidden so that the account cache ignores it.
+func (ks *Keystore) ChangePassphrase(a accounts.Account, passphrase, newPassphrase string) error {
+	a, err := ks.ethKeystore.Find(a)
+	if err != nil {
+		return err
+	}
+
+	path := a.URL.Path
+	original, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("could not read key file: %w", err)
+	}
+	backup := filepath.Join(filepath.Dir(path), "."+filepath.Base(path)+".bak")
+	if err := os.WriteFile(backup, original, 0600); err != nil {
+		return fmt.Errorf("could not backup key file: %w", err)
+	}
+
+	if err := ks.ethKeystore.Update(a, passphrase, newPassphrase); err != nil {
+		os.Remove(backup)
+		return err
+	}
+
+	key, err := ks.loadKey(a.Address, path, newPassphrase)
+	if err != nil {
+		if restoreErr := os.Rename(backup, path); restoreErr != nil {
+			return fmt.Errorf("could not verify re-encrypted key: %v, backup left at %s: %w", err, backup, restoreErr)
+		}
+		return fmt.Errorf("could not verify re-encrypted key, previous key restored: %w", err)
+	}
+	zeroKey(key.PrivateKey)
+
+	return os.Remove(backup)
+}
+
 // Encrypt takes a derived key for the given address and encrypts the plaintext.
 func (ks *Keystore) Encrypt(addr common.Address, plaintext []byte) ([]byte, error) {
 	ks.mu.RLock()
@@ -155,11 +260,13 @@ func (ks *Keystore) Encrypt(addr common.Address, plaintext []byte) ([]byte, erro
 	if !found {
 		return nil, ethKs.ErrLocked
 	}
+	key.touch(ks.now())
 
 	keyDerived, err := key.deriveKey()
 	if err != nil {
 		return nil, err
 	}
+	defer zeroBytes(keyDerived)
 
 	c, err := aes.NewCipher(keyDerived)
 	if err != nil {
@@ -188,11 +295,13 @@ func (ks *Keystore) Decrypt(addr common.Address, encrypted []byte) ([]byte, erro
 	if !found {
 		return nil, ethKs.ErrLocked
 	}
+	key.touch(ks.now())
 
 	keyDerived, err := key.deriveKey()
 	if err != nil {
 		return nil, err
 	}
+	defer zeroBytes(keyDerived)
 
 	c, err := aes.NewCipher(keyDerived)
 	if err != nil {
@@ -224,10 +333,21 @@ func (ks *Keystore) SignHash(a accounts.Account, hash []byte) ([]byte, error) {
 	if !found {
 		return nil, ethKs.ErrLocked
 	}
+	unlockedKey.touch(ks.now())
 	// Sign the hash using plain ECDSA operations
 	return crypto.Sign(hash, unlockedKey.PrivateKey)
 }
 
+// SignTx signs the given transaction for the chain with the unlocked key of the account.
+func (ks *Keystore) SignTx(a accounts.Account, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	signer := types.LatestSignerForChainID(chainID)
+	signature, err := ks.SignHash(a, signer.Hash(tx).Bytes())
+	if err != nil {
+		return nil, err
+	}
+	return tx.WithSignature(signer, signature)
+}
+
 // zeroKey zeroes a private key in memory.
 func zeroKey(k *ecdsa.PrivateKey) {
 	b := k.D.Bits()
@@ -236,14 +356,44 @@ func zeroKey(k *ecdsa.PrivateKey) {
 	}
 }
 
+// zeroBytes zeroes secret bytes in memory.
+func zeroBytes(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
+
 type unlocked struct {
 	*ethKs.Key
-	abort chan struct{}
+	abort    chan struct{}
+	lastUsed int64 // Unix nanoseconds of the last key usage, accessed atomically
+
+	// releaseMemory frees the locked memory holding the private key, nil if memory is not locked.
+	releaseMemory func()
+}
+
+// wipe zeroes the private key and releases its locked memory.
+func (u *unlocked) wipe() {
+	zeroKey(u.PrivateKey)
+	if u.releaseMemory != nil {
+		u.releaseMemory()
+	}
+}
+
+func (u *unlocked) touch(now time.Time) {
+	atomic.StoreInt64(&u.lastUsed, now.UnixNano())
+}
+
+func (u *unlocked) idleFor(now time.Time) time.Duration {
+	return now.Sub(time.Unix(0, atomic.LoadInt64(&u.lastUsed)))
 }
 
 func (u *unlocked) deriveKey() ([]byte, error) {
 	hashFunc := sha512.New
-	hkdfDerived := hkdf.New(hashFunc, u.Key.PrivateKey.D.Bytes(), nil, nil)
+	secret := u.Key.PrivateKey.D.Bytes()
+	defer zeroBytes(secret)
+
+	hkdfDerived := hkdf.New(hashFunc, secret, nil, nil)
 	key := make([]byte, 32)
 	_, err := io.ReadFull(hkdfDerived, key)
 	return key, err
@@ -18,11 +18,17 @@
 package identity
 
 import (
+	"crypto/ecdsa"
+	"math/big"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/ethereum/go-ethereum/accounts"
 	ethKs "github.com/ethereum/go-ethereum/accounts/keystore"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
@@ -110,6 +116,133 @@ func Benchmark_DerivedDecryption(b *testing.B) {
 	result = r
 }
 
+func TestKeystore_ChangePassphrase(t *testing.T) {
+	dir := t.TempDir()
+	ks := NewKeystoreFilesystem(dir, ethKs.NewKeyStore(dir, ethKs.LightScryptN, ethKs.LightScryptP))
+
+	account, err := ks.NewAccount("old")
+	assert.NoError(t, err)
+
+	err = ks.ChangePassphrase(account, "wrong", "new")
+	assert.ErrorIs(t, err, ethKs.ErrDecrypt)
+	assert.NoError(t, ks.Unlock(account, "old"))
+	assert.NoError(t, ks.Lock(account.Address))
+
+	err = ks.ChangePassphrase(account, "old", "new")
+	assert.NoError(t, err)
+	assert.Error(t, ks.Unlock(account, "old"))
+	assert.NoError(t, ks.Unlock(account, "new"))
+
+	backups, err := filepath.Glob(filepath.Join(dir, ".*.bak"))
+	assert.NoError(t, err)
+	assert.Empty(t, backups)
+
+	files, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, files, 1)
+}
+
+func TestKeystore_ChangePassphrase_RestoresBackupOnFailedVerification(t *testing.T) {
+	dir := t.TempDir()
+	ks := NewKeystoreFilesystem(dir, ethKs.NewKeyStore(dir, ethKs.LightScryptN, ethKs.LightScryptP))
+
+	account, err := ks.NewAccount("old")
+	assert.NoError(t, err)
+
+	loadKey := ks.loadKey
+	ks.loadKey = func(addr common.Address, filename, auth string) (*ethKs.Key, error) {
+		return nil, errors.New("corrupted key file")
+	}
+	err = ks.ChangePassphrase(account, "old", "new")
+	assert.ErrorContains(t, err, "previous key restored")
+	ks.loadKey = loadKey
+
+	assert.Error(t, ks.Unlock(account, "new"))
+	assert.NoError(t, ks.Unlock(account, "old"))
+
+	backups, err := filepath.Glob(filepath.Join(dir, ".*.bak"))
+	assert.NoError(t, err)
+	assert.Empty(t, backups)
+}
+
+func TestKeystore_LockIdle(t *testing.T) {
+	dir := t.TempDir()
+	ks := NewKeystoreFilesystem(dir, ethKs.NewKeyStore(dir, ethKs.LightScryptN, ethKs.LightScryptP))
+
+	now := time.Now()
+	ks.now = func() time.Time { return now }
+	keepNone := func(common.Address) bool { return false }
+
+	account, err := ks.NewAccount("")
+	assert.NoError(t, err)
+	assert.NoError(t, ks.Unlock(account, ""))
+
+	assert.Empty(t, ks.LockIdle(time.Hour, keepNone))
+	_, err = ks.SignHash(account, crypto.Keccak256([]byte(secretMessage)))
+	assert.NoError(t, err)
+
+	now = now.Add(time.Minute)
+	assert.Empty(t, ks.LockIdle(time.Second, func(common.Address) bool { return true }))
+	assert.Equal(t, []common.Address{account.Address}, ks.LockIdle(time.Second, keepNone))
+
+	_, err = ks.SignHash(account, crypto.Keccak256([]byte(secretMessage)))
+	assert.ErrorIs(t, err, ethKs.ErrLocked)
+}
+
+func TestKeystore_LockAll(t *testing.T) {
+	dir := t.TempDir()
+	ks := NewKeystoreFilesystem(dir, ethKs.NewKeyStore(dir, ethKs.LightScryptN, ethKs.LightScryptP))
+
+	account, err := ks.NewAccount("")
+	assert.NoError(t, err)
+	assert.NoError(t, ks.TimedUnlock(account, "", time.Hour))
+
+	ks.mu.RLock()
+	key := ks.unlocked[account.Address].PrivateKey
+	ks.mu.RUnlock()
+
+	assert.Equal(t, []common.Address{account.Address}, ks.LockAll())
+	assertKeyZeroed(t, key)
+
+	_, err = ks.SignHash(account, crypto.Keccak256([]byte(secretMessage)))
+	assert.ErrorIs(t, err, ethKs.ErrLocked)
+	assert.Empty(t, ks.LockAll())
+}
+
+func TestKeystore_MemoryLock(t *testing.T) {
+	buf, err := allocLocked(1)
+	if err != nil {
+		t.Skipf("Memory locking is not available: %v", err)
+	}
+	assert.NoError(t, freeLocked(buf))
+
+	ks := NewKeystoreFilesystem("", &ethKeystoreMock{account: encryptionAccount})
+	ks.SetMemoryLock(true)
+
+	var key *ecdsa.PrivateKey
+	ks.loadKey = func(addr common.Address, filename, auth string) (*ethKs.Key, error) {
+		key, err = crypto.HexToECDSA("6f88637b68ee88816e73f663aef709d7009836c98ae91ef31e3dfac7be3a1657")
+		return &ethKs.Key{Address: addr, PrivateKey: key}, err
+	}
+	assert.NoError(t, ks.Unlock(encryptionAccount, ""))
+
+	hash := crypto.Keccak256([]byte(secretMessage))
+	signature, err := ks.SignHash(encryptionAccount, hash)
+	assert.NoError(t, err)
+	pubKey, err := crypto.SigToPub(hash, signature)
+	assert.NoError(t, err)
+	assert.Equal(t, encryptionAddress, crypto.PubkeyToAddress(*pubKey))
+
+	assert.NoError(t, ks.Lock(encryptionAddress))
+	assertKeyZeroed(t, key)
+}
+
+func assertKeyZeroed(t *testing.T, key *ecdsa.PrivateKey) {
+	for _, w := range key.D.Bits() {
+		assert.Zero(t, w)
+	}
+}
+
 type ethKeystoreMock struct {
 	account  accounts.Account
 	unlocked bool
@@ -136,6 +269,10 @@ func (ekm *ethKeystoreMock) Import(keyJSON []byte, passphrase, newPassphrase str
 	return ekm.account, nil
 }
 
+func (ekm *ethKeystoreMock) Update(a accounts.Account, passphrase, newPassphrase string) error {
+	return nil
+}
+
 func (ekm *ethKeystoreMock) Accounts() []accounts.Account {
 	return []accounts.Account{ekm.account}
 }
@@ -150,3 +287,24 @@ func (ekm *ethKeystoreMock) Find(a accounts.Account) (accounts.Account, error) {
 func (ekm *ethKeystoreMock) NewAccount(passphrase string) (accounts.Account, error) {
 	return accounts.Account{}, errors.New("not implemented yet")
 }
+
+func TestKeystore_SignTx(t *testing.T) {
+	dir := t.TempDir()
+	ks := NewKeystoreFilesystem(dir, ethKs.NewKeyStore(dir, ethKs.LightScryptN, ethKs.LightScryptP))
+
+	account, err := ks.NewAccount("")
+	assert.NoError(t, err)
+
+	chainID := big.NewInt(137)
+	tx := types.NewTx(&types.DynamicFeeTx{ChainID: chainID, Nonce: 1, Gas: 21000, To: &encryptionAddress})
+	_, err = ks.SignTx(account, tx, chainID)
+	assert.ErrorIs(t, err, ethKs.ErrLocked)
+
+	assert.NoError(t, ks.Unlock(account, ""))
+	signed, err := ks.SignTx(account, tx, chainID)
+	assert.NoError(t, err)
+
+	sender, err := types.Sender(types.LatestSignerForChainID(chainID), signed)
+	assert.NoError(t, err)
+	assert.Equal(t, account.Address, sender)
+}
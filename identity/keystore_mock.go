@@ -22,6 +22,7 @@ import (
 	crand "crypto/rand"
 	"encoding/hex"
 	"sync"
+	"time"
 
 	"github.com/ethereum/go-ethereum/accounts"
 	ethKs "github.com/ethereum/go-ethereum/accounts/keystore"
@@ -32,6 +33,7 @@ import (
 type mockKeystore struct {
 	keys map[common.Address]MockKey
 	lock sync.Mutex
+	now  func() time.Time
 }
 
 // MockKeys represents the mocked keys
@@ -55,6 +57,7 @@ func NewMockKeystoreWith(keys map[common.Address]MockKey) *mockKeystore {
 	}
 	return &mockKeystore{
 		keys: copied,
+		now:  time.Now,
 	}
 }
 
@@ -64,6 +67,7 @@ type MockKey struct {
 	Pass       string
 	pk         *ecdsa.PrivateKey
 	isUnlocked bool
+	lastUsed   time.Time
 }
 
 func (mk *mockKeystore) Accounts() []accounts.Account {
@@ -86,6 +90,8 @@ func (mk *mockKeystore) SignHash(a accounts.Account, hash []byte) ([]byte, error
 		if !v.isUnlocked {
 			return nil, ethKs.ErrLocked
 		}
+		v.lastUsed = mk.now()
+		mk.keys[a.Address] = v
 		return crypto.Sign(hash, v.pk)
 	}
 	return nil, ethKs.ErrNoMatch
@@ -144,6 +150,23 @@ func (mk *mockKeystore) Unlock(a accounts.Account, passphrase string) error {
 
 		v.isUnlocked = true
 		v.pk = pk
+		v.lastUsed = mk.now()
+		mk.keys[a.Address] = v
+		return nil
+	}
+
+	return ethKs.ErrNoMatch
+}
+
+func (mk *mockKeystore) ChangePassphrase(a accounts.Account, passphrase, newPassphrase string) error {
+	mk.lock.Lock()
+	defer mk.lock.Unlock()
+
+	if v, ok := mk.keys[a.Address]; ok {
+		if v.Pass != passphrase {
+			return ethKs.ErrDecrypt
+		}
+		v.Pass = newPassphrase
 		mk.keys[a.Address] = v
 		return nil
 	}
@@ -162,6 +185,21 @@ func (mk *mockKeystore) Lock(addr common.Address) error {
 	return nil
 }
 
+func (mk *mockKeystore) LockIdle(ttl time.Duration, keep func(common.Address) bool) []common.Address {
+	mk.lock.Lock()
+	defer mk.lock.Unlock()
+
+	var locked []common.Address
+	for addr, v := range mk.keys {
+		if v.isUnlocked && mk.now().Sub(v.lastUsed) >= ttl && !keep(addr) {
+			v.isUnlocked = false
+			mk.keys[addr] = v
+			locked = append(locked, addr)
+		}
+	}
+	return locked
+}
+
 func (mk *mockKeystore) Find(a accounts.Account) (accounts.Account, error) {
 	mk.lock.Lock()
 	defer mk.lock.Unlock()
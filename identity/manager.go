@@ -22,8 +22,10 @@ package identity
 
 import (
 	"sync"
+	"time"
 
 	"github.com/ethereum/go-ethereum/accounts"
+	ethKs "github.com/ethereum/go-ethereum/accounts/keystore"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
@@ -34,15 +36,24 @@ import (
 // Identity events
 const (
 	AppTopicIdentityUnlock  = "identity-unlocked"
+	AppTopicIdentityLock    = "identity-locked"
 	AppTopicIdentityCreated = "identity-created"
 )
 
+// ErrInvalidPassphrase is returned when given passphrase does not decrypt identity keystore.
+var ErrInvalidPassphrase = errors.New("invalid passphrase")
+
 // AppEventIdentityUnlock represents the payload that is sent on identity unlock.
 type AppEventIdentityUnlock struct {
 	ChainID int64
 	ID      Identity
 }
 
+// AppEventIdentityLock represents the payload that is sent on identity relock.
+type AppEventIdentityLock struct {
+	ID Identity
+}
+
 // ResidentCountryEvent represent actual resident country changed event
 type ResidentCountryEvent struct {
 	ID      string
@@ -64,6 +75,8 @@ type keystore interface {
 	Find(a accounts.Account) (accounts.Account, error)
 	Unlock(a accounts.Account, passphrase string) error
 	SignHash(a accounts.Account, hash []byte) ([]byte, error)
+	ChangePassphrase(a accounts.Account, passphrase, newPassphrase string) error
+	LockIdle(ttl time.Duration, keep func(common.Address) bool) []common.Address
 }
 
 // NewIdentityManager creates and returns new identityManager
@@ -177,6 +190,55 @@ func (idm *identityManager) Unlock(chainID int64, address string, passphrase str
 	return nil
 }
 
+// RelockIdle locks identities which were not used for longer than ttl
+// and publishes an event for each of them. Identities which are in use stay unlocked.
+func (idm *identityManager) RelockIdle(ttl time.Duration, inUse UsageChecker) []Identity {
+	idm.unlockedMu.Lock()
+	defer idm.unlockedMu.Unlock()
+
+	keep := func(addr common.Address) bool {
+		return inUse(FromAddress(addr.Hex()))
+	}
+
+	var locked []Identity
+	for _, addr := range idm.keystoreManager.LockIdle(ttl, keep) {
+		id := FromAddress(addr.Hex())
+		for address := range idm.unlocked {
+			if common.HexToAddress(address) == addr {
+				delete(idm.unlocked, address)
+			}
+		}
+		log.Info().Msgf("Identity %s locked after being idle for %s", id.Address, ttl)
+		locked = append(locked, id)
+	}
+
+	if len(locked) > 0 {
+		go func() {
+			for _, id := range locked {
+				idm.eventBus.Publish(AppTopicIdentityLock, AppEventIdentityLock{ID: id})
+			}
+		}()
+	}
+	return locked
+}
+
+// ChangePassphrase re-encrypts identity keystore with the new passphrase.
+func (idm *identityManager) ChangePassphrase(address, passphrase, newPassphrase string) error {
+	account, err := idm.findAccount(address)
+	if err != nil {
+		return err
+	}
+
+	if err := idm.keystoreManager.ChangePassphrase(account, passphrase, newPassphrase); err != nil {
+		if errors.Is(err, ethKs.ErrDecrypt) {
+			return ErrInvalidPassphrase
+		}
+		return errors.Wrapf(err, "keystore failed to change passphrase of identity: %s", address)
+	}
+	log.Info().Msgf("Passphrase of identity %s changed", address)
+	return nil
+}
+
 func (idm *identityManager) findAccount(address string) (accounts.Account, error) {
 	account, err := idm.keystoreManager.Find(addressToAccount(address))
 	if err != nil {
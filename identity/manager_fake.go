@@ -27,12 +27,13 @@ type idmFake struct {
 	newIdentity          Identity
 	unlockFails          bool
 	isUnlocked           bool
+	changePassphraseErr  error
 }
 
 // NewIdentityManagerFake creates fake identity manager for testing purposes
 // TODO each caller should use it's own mocked manager part instead of global one
 func NewIdentityManagerFake(existingIdentities []Identity, newIdentity Identity) *idmFake {
-	return &idmFake{"", "", 0, existingIdentities, newIdentity, false, true}
+	return &idmFake{"", "", 0, existingIdentities, newIdentity, false, true, nil}
 }
 
 func (fakeIdm *idmFake) IsUnlocked(id string) bool {
@@ -43,6 +44,10 @@ func (fakeIdm *idmFake) MarkUnlockToFail() {
 	fakeIdm.unlockFails = true
 }
 
+func (fakeIdm *idmFake) MarkChangePassphraseToFail(err error) {
+	fakeIdm.changePassphraseErr = err
+}
+
 func (fakeIdm *idmFake) CreateNewIdentity(_ string) (Identity, error) {
 	return fakeIdm.newIdentity, nil
 }
@@ -66,6 +71,10 @@ func (fakeIdm *idmFake) HasIdentity(_ string) bool {
 	return true
 }
 
+func (fakeIdm *idmFake) ChangePassphrase(address, passphrase, newPassphrase string) error {
+	return fakeIdm.changePassphraseErr
+}
+
 func (fakeIdm *idmFake) Unlock(chainID int64, address string, passphrase string) error {
 	fakeIdm.LastUnlockAddress = address
 	fakeIdm.LastUnlockPassphrase = passphrase
@@ -19,6 +19,7 @@ package identity
 
 import (
 	"testing"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/stretchr/testify/assert"
@@ -69,3 +70,47 @@ func Test_IdentityManager(t *testing.T) {
 		assert.False(t, idm.HasIdentity("0x000000000000000000000000000000000000000B"))
 	})
 }
+
+func Test_IdentityManager_RelockIdle(t *testing.T) {
+	now := time.Now()
+	ks := NewMockKeystoreWith(MockKeys)
+	ks.now = func() time.Time { return now }
+	bus := eventbus.New()
+	idm := &identityManager{
+		keystoreManager: ks,
+		eventBus:        bus,
+		unlocked:        map[string]bool{},
+		residentCountry: NewResidentCountry(bus, newMockLocationResolver("LT")),
+	}
+
+	events := make(chan AppEventIdentityLock, 1)
+	assert.NoError(t, bus.SubscribeAsync(AppTopicIdentityLock, func(e AppEventIdentityLock) {
+		events <- e
+	}))
+
+	address := "0x53a835143c0ef3bbcbfa796d7eb738ca7dd28f68"
+	inUse := false
+	usage := func(id Identity) bool {
+		return inUse && id.Address == address
+	}
+
+	assert.NoError(t, idm.Unlock(1, address, ""))
+	assert.Empty(t, idm.RelockIdle(time.Hour, usage))
+	assert.True(t, idm.IsUnlocked(address))
+
+	now = now.Add(time.Minute)
+	inUse = true
+	assert.Empty(t, idm.RelockIdle(time.Second, usage))
+	assert.True(t, idm.IsUnlocked(address))
+
+	inUse = false
+	assert.Equal(t, []Identity{FromAddress(address)}, idm.RelockIdle(time.Second, usage))
+	assert.False(t, idm.IsUnlocked(address))
+
+	select {
+	case e := <-events:
+		assert.Equal(t, FromAddress(address), e.ID)
+	case <-time.After(time.Second):
+		t.Fatal("identity lock event was not published")
+	}
+}
@@ -0,0 +1,58 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package identity
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"unsafe"
+
+	"github.com/rs/zerolog/log"
+)
+
+// lockKeyMemory moves the private key to memory which is never swapped and returns
+// a function zeroing and freeing that memory. The key must not be used after it is called.
+func lockKeyMemory(k *ecdsa.PrivateKey) (func(), error) {
+	words := k.D.Bits()
+	if len(words) == 0 {
+		return nil, nil
+	}
+
+	buf, err := allocLocked(len(words) * int(unsafe.Sizeof(big.Word(0))))
+	if err != nil {
+		return nil, err
+	}
+
+	locked := unsafe.Slice((*big.Word)(unsafe.Pointer(&buf[0])), len(words))
+	copy(locked, words)
+	for i := range words {
+		words[i] = 0
+	}
+	k.D.SetBits(locked)
+
+	return func() {
+		for i := range locked {
+			locked[i] = 0
+		}
+		// Detach the key from the memory which is about to be freed.
+		k.D = new(big.Int)
+		if err := freeLocked(buf); err != nil {
+			log.Warn().Err(err).Msg("Could not free locked private key memory")
+		}
+	}, nil
+}
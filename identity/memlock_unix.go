@@ -0,0 +1,43 @@
+//go:build linux || darwin || freebsd || openbsd || netbsd
+
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package identity
+
+import "golang.org/x/sys/unix"
+
+// allocLocked allocates memory of the given size outside of the Go heap and locks it into RAM.
+func allocLocked(size int) ([]byte, error) {
+	buf, err := unix.Mmap(-1, 0, size, unix.PROT_READ|unix.PROT_WRITE, unix.MAP_ANON|unix.MAP_PRIVATE)
+	if err != nil {
+		return nil, err
+	}
+	if err := unix.Mlock(buf); err != nil {
+		unix.Munmap(buf)
+		return nil, err
+	}
+	return buf, nil
+}
+
+// freeLocked unlocks and frees memory allocated by allocLocked.
+func freeLocked(buf []byte) error {
+	if err := unix.Munlock(buf); err != nil {
+		return err
+	}
+	return unix.Munmap(buf)
+}
@@ -0,0 +1,103 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package overrides
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/asdine/storm/v3"
+
+	"github.com/mysteriumnetwork/node/identity"
+)
+
+const bucket = "identity-overrides"
+
+// ErrNotFound is returned when the identity has no overrides.
+var ErrNotFound = errors.New("identity has no overrides")
+
+type localStorage interface {
+	Store(bucket string, data interface{}) error
+	GetOneByField(bucket string, fieldName string, key interface{}, to interface{}) error
+	Delete(bucket string, data interface{}) error
+}
+
+// Overrides are payment and registration settings of a single identity,
+// used instead of the global configuration where set.
+type Overrides struct {
+	// Beneficiary receives the settled earnings of the identity, empty to use the saved beneficiary.
+	Beneficiary string
+	// SettleThreshold is the unsettled amount of MYST which triggers automatic settlement, nil to use the global setting.
+	SettleThreshold *float64
+	// ChainID is the chain the identity is registered on when it provides services, zero to use the global setting.
+	ChainID   int64
+	UpdatedAt time.Time
+}
+
+// Storage keeps identity overrides in the local database.
+type Storage struct {
+	storage localStorage
+}
+
+// NewStorage creates identity overrides storage.
+func NewStorage(storage localStorage) *Storage {
+	return &Storage{
+		storage: storage,
+	}
+}
+
+// Get returns overrides of the identity, ErrNotFound if there are none.
+func (s *Storage) Get(id identity.Identity) (Overrides, error) {
+	result := &storedOverrides{}
+	err := s.storage.GetOneByField(bucket, "ID", key(id), result)
+	if err != nil {
+		if errors.Is(err, storm.ErrNotFound) {
+			return Overrides{}, ErrNotFound
+		}
+		return Overrides{}, err
+	}
+	return result.Overrides, nil
+}
+
+// Set replaces overrides of the identity.
+func (s *Storage) Set(id identity.Identity, o Overrides) error {
+	o.UpdatedAt = time.Now().UTC()
+	return s.storage.Store(bucket, &storedOverrides{
+		ID:        key(id),
+		Overrides: o,
+	})
+}
+
+// Remove removes overrides of the identity, so that the global configuration is used again.
+func (s *Storage) Remove(id identity.Identity) error {
+	err := s.storage.Delete(bucket, &storedOverrides{ID: key(id)})
+	if errors.Is(err, storm.ErrNotFound) {
+		return nil
+	}
+	return err
+}
+
+func key(id identity.Identity) string {
+	return strings.ToLower(id.Address)
+}
+
+type storedOverrides struct {
+	ID string `storm:"id"`
+	Overrides
+}
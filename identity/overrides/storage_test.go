@@ -0,0 +1,74 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package overrides
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mysteriumnetwork/node/core/storage/boltdb"
+	"github.com/mysteriumnetwork/node/identity"
+)
+
+func TestStorage(t *testing.T) {
+	// given:
+	dir, err := os.MkdirTemp("/tmp", "mysttest")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	db, err := boltdb.NewStorage(dir)
+	require.NoError(t, err)
+	storage := NewStorage(db)
+
+	id := identity.FromAddress("0xaA11111111111111111111111111111111111111")
+	threshold := 2.5
+
+	// when
+	_, err = storage.Get(id)
+
+	// then
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	// when
+	err = storage.Set(id, Overrides{
+		Beneficiary:     "0x6666666666666666666666666666666666666666",
+		SettleThreshold: &threshold,
+		ChainID:         137,
+	})
+	require.NoError(t, err)
+	got, err := storage.Get(identity.FromAddress("0xaa11111111111111111111111111111111111111"))
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, "0x6666666666666666666666666666666666666666", got.Beneficiary)
+	require.NotNil(t, got.SettleThreshold)
+	assert.Equal(t, 2.5, *got.SettleThreshold)
+	assert.Equal(t, int64(137), got.ChainID)
+	assert.False(t, got.UpdatedAt.IsZero())
+
+	// when
+	require.NoError(t, storage.Remove(id))
+	_, err = storage.Get(id)
+
+	// then
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.NoError(t, storage.Remove(id))
+}
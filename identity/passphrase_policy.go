@@ -0,0 +1,64 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package identity
+
+import (
+	"fmt"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+// ErrWeakPassphrase is returned when passphrase does not satisfy the passphrase policy.
+var ErrWeakPassphrase = errors.New("passphrase does not satisfy the policy")
+
+// PassphrasePolicy describes requirements for identity passphrases.
+// Zero value policy accepts any passphrase, including the empty one.
+type PassphrasePolicy struct {
+	// MinLength is the minimal number of characters.
+	MinLength int
+	// RequireMixed requires lower and upper case letters and digits.
+	RequireMixed bool
+}
+
+// Validate checks whether passphrase satisfies the policy.
+func (p PassphrasePolicy) Validate(passphrase string) error {
+	if utf8.RuneCountInString(passphrase) < p.MinLength {
+		return fmt.Errorf("%w: must be at least %d characters long", ErrWeakPassphrase, p.MinLength)
+	}
+
+	if p.RequireMixed {
+		var lower, upper, digit bool
+		for _, r := range passphrase {
+			switch {
+			case unicode.IsLower(r):
+				lower = true
+			case unicode.IsUpper(r):
+				upper = true
+			case unicode.IsDigit(r):
+				digit = true
+			}
+		}
+		if !lower || !upper || !digit {
+			return fmt.Errorf("%w: must contain lower and upper case letters and digits", ErrWeakPassphrase)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package identity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPassphrasePolicy_Validate(t *testing.T) {
+	tests := []struct {
+		name       string
+		policy     PassphrasePolicy
+		passphrase string
+		wantErr    bool
+	}{
+		{"zero policy accepts empty", PassphrasePolicy{}, "", false},
+		{"too short", PassphrasePolicy{MinLength: 8}, "short", true},
+		{"long enough", PassphrasePolicy{MinLength: 8}, "long enough", false},
+		{"length counts characters", PassphrasePolicy{MinLength: 4}, "ąčęė", false},
+		{"not mixed", PassphrasePolicy{RequireMixed: true}, "lowercase1", true},
+		{"mixed", PassphrasePolicy{RequireMixed: true}, "Mixed1case", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.Validate(tt.passphrase)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrWeakPassphrase)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package registry
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+// ErrBeneficiaryDenied is returned when registration beneficiary would not be able to receive the earnings.
+var ErrBeneficiaryDenied = errors.New("beneficiary can't receive earnings")
+
+const beneficiaryCheckTimeout = 10 * time.Second
+
+// burnAddresses are the well known addresses nobody holds the key of.
+var burnAddresses = map[common.Address]struct{}{
+	common.Address{}: {},
+	common.HexToAddress("0x0000000000000000000000000000000000000001"): {},
+	common.HexToAddress("0x000000000000000000000000000000000000dEaD"): {},
+	common.HexToAddress("0xdEAD000000000000000042069420694206942069"): {},
+	common.HexToAddress("0xFFfFfFffFFfffFFfFFfFFFFFffFFFffffFfFFFfF"): {},
+}
+
+// validateBeneficiary rejects burn addresses and contracts which refuse plain transfers.
+// Contract is only checked if chain client is available, failures of the check itself do not reject the beneficiary.
+func (t *Transactor) validateBeneficiary(chainID int64, beneficiary string) error {
+	if !common.IsHexAddress(beneficiary) {
+		return fmt.Errorf("%w: %q is not a valid address", ErrBeneficiaryDenied, beneficiary)
+	}
+	address := common.HexToAddress(beneficiary)
+	if _, ok := burnAddresses[address]; ok {
+		return fmt.Errorf("%w: %s is a burn address", ErrBeneficiaryDenied, address.Hex())
+	}
+	if t.bc == nil {
+		return nil
+	}
+
+	bc, err := t.bc.GetClientByChain(chainID)
+	if err != nil {
+		log.Warn().Err(err).Msgf("Could not check beneficiary %s on chain %d", address.Hex(), chainID)
+		return nil
+	}
+	eth := bc.Client()
+
+	ctx, cancel := context.WithTimeout(context.Background(), beneficiaryCheckTimeout)
+	defer cancel()
+
+	code, err := eth.CodeAt(ctx, address, nil)
+	if err != nil {
+		log.Warn().Err(err).Msgf("Could not get code of beneficiary %s on chain %d", address.Hex(), chainID)
+		return nil
+	}
+	if len(code) == 0 {
+		return nil
+	}
+
+	// Simulate a plain transfer, contract without payable fallback reverts it. Simulation fails
+	// for other reasons too, e.g. if the zero sender has no funds on the chain, those are ignored.
+	_, err = eth.CallContract(ctx, ethereum.CallMsg{To: &address, Value: big.NewInt(1)}, nil)
+	if err != nil {
+		if isExecutionReverted(err) {
+			return fmt.Errorf("%w: contract %s does not accept transfers", ErrBeneficiaryDenied, address.Hex())
+		}
+		log.Warn().Err(err).Msgf("Could not simulate transfer to beneficiary %s on chain %d", address.Hex(), chainID)
+	}
+	return nil
+}
+
+func isExecutionReverted(err error) bool {
+	msg := err.Error()
+	return strings.Contains(msg, "execution reverted") || strings.Contains(msg, "invalid opcode")
+}
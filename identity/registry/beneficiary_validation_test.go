@@ -0,0 +1,139 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package registry
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/mysteriumnetwork/payments/client"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/eventbus"
+)
+
+var (
+	plainBeneficiary    = common.HexToAddress("0x1111111111111111111111111111111111111111")
+	payableContract     = common.HexToAddress("0x2222222222222222222222222222222222222222")
+	nonPayableContract  = common.HexToAddress("0x3333333333333333333333333333333333333333")
+	unreachableContract = common.HexToAddress("0x4444444444444444444444444444444444444444")
+)
+
+func TestTransactor_ValidateBeneficiary(t *testing.T) {
+	tr := NewTransactor(nil, "", nil, nil, nil, &mockChainClients{eth: &mockBeneficiaryEthClient{}}, time.Minute, nil)
+
+	for _, beneficiary := range []string{
+		"0x0000000000000000000000000000000000000000",
+		"0x000000000000000000000000000000000000dead",
+		"not-an-address",
+		nonPayableContract.Hex(),
+	} {
+		err := tr.validateBeneficiary(80001, beneficiary)
+		assert.ErrorIs(t, err, ErrBeneficiaryDenied, beneficiary)
+	}
+
+	for _, beneficiary := range []string{
+		plainBeneficiary.Hex(),
+		payableContract.Hex(),
+		unreachableContract.Hex(),
+	} {
+		assert.NoError(t, tr.validateBeneficiary(80001, beneficiary), beneficiary)
+	}
+}
+
+func TestTransactor_ValidateBeneficiaryWithoutChainClient(t *testing.T) {
+	tr := NewTransactor(nil, "", nil, nil, nil, nil, time.Minute, nil)
+
+	assert.NoError(t, tr.validateBeneficiary(80001, nonPayableContract.Hex()))
+	assert.ErrorIs(t, tr.validateBeneficiary(80001, "0x000000000000000000000000000000000000dEaD"), ErrBeneficiaryDenied)
+}
+
+func TestTransactor_CheckBeneficiaryPublishesRejection(t *testing.T) {
+	bus := eventbus.New()
+	var rejected []AppEventRegistrationBeneficiaryRejected
+	err := bus.Subscribe(AppTopicRegistrationBeneficiaryRejected, func(ev AppEventRegistrationBeneficiaryRejected) {
+		rejected = append(rejected, ev)
+	})
+	assert.NoError(t, err)
+	tr := NewTransactor(nil, "", nil, nil, bus, &mockChainClients{eth: &mockBeneficiaryEthClient{}}, time.Minute, nil)
+
+	err = tr.checkBeneficiary(IdentityRegistrationRequest{
+		Identity:    "0x5555555555555555555555555555555555555555",
+		Beneficiary: nonPayableContract.Hex(),
+		ChainID:     80001,
+	})
+	assert.ErrorIs(t, err, ErrBeneficiaryDenied)
+
+	assert.Len(t, rejected, 1)
+	ev := rejected[0]
+	assert.Equal(t, "0x5555555555555555555555555555555555555555", ev.ID.Address)
+	assert.Equal(t, int64(80001), ev.ChainID)
+	assert.Equal(t, nonPayableContract.Hex(), ev.Beneficiary)
+	assert.Contains(t, ev.Error, "does not accept transfers")
+}
+
+type mockChainClients struct {
+	eth client.EtherClient
+}
+
+func (m *mockChainClients) GetProviderChannel(chainID int64, hermesAddress common.Address, addressToCheck common.Address, pending bool) (client.ProviderChannel, error) {
+	return client.ProviderChannel{}, nil
+}
+
+func (m *mockChainClients) GetLastRegistryNonce(chainID int64, registry common.Address) (*big.Int, error) {
+	return new(big.Int), nil
+}
+
+func (m *mockChainClients) GetClientByChain(chainID int64) (client.BC, error) {
+	return &mockBC{eth: m.eth}, nil
+}
+
+type mockBC struct {
+	client.BC
+	eth client.EtherClient
+}
+
+func (m *mockBC) Client() client.EtherClient {
+	return m.eth
+}
+
+type mockBeneficiaryEthClient struct {
+	client.EtherClient
+}
+
+func (m *mockBeneficiaryEthClient) CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error) {
+	if account == plainBeneficiary {
+		return nil, nil
+	}
+	return []byte{0x60, 0x80}, nil
+}
+
+func (m *mockBeneficiaryEthClient) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	switch *msg.To {
+	case nonPayableContract:
+		return nil, errors.New("execution reverted")
+	case unreachableContract:
+		return nil, errors.New("insufficient funds for gas * price + value")
+	}
+	return nil, nil
+}
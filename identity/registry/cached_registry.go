@@ -0,0 +1,129 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package registry
+
+import (
+	"sync"
+	"time"
+
+	"github.com/mysteriumnetwork/node/eventbus"
+	"github.com/mysteriumnetwork/node/identity"
+)
+
+type statusCacheKey struct {
+	chainID int64
+	address string
+}
+
+type statusCacheEntry struct {
+	status    RegistrationStatus
+	expiresAt time.Time
+}
+
+// CachedRegistry decorates identity registry by caching registration statuses for a limited time.
+// Cached statuses are dropped as soon as a registration event for the identity is observed.
+type CachedRegistry struct {
+	IdentityRegistry
+	ttl time.Duration
+	now func() time.Time
+
+	lock        sync.Mutex
+	entries     map[statusCacheKey]statusCacheEntry
+	generations map[statusCacheKey]uint64
+}
+
+// NewCachedRegistry returns identity registry which caches registration statuses of the given one for ttl.
+func NewCachedRegistry(registry IdentityRegistry, ttl time.Duration) *CachedRegistry {
+	return &CachedRegistry{
+		IdentityRegistry: registry,
+		ttl:              ttl,
+		now:              time.Now,
+		entries:          make(map[statusCacheKey]statusCacheEntry),
+		generations:      make(map[statusCacheKey]uint64),
+	}
+}
+
+// Subscribe subscribes the underlying registry and cache invalidation to relevant events.
+func (cr *CachedRegistry) Subscribe(eb eventbus.Subscriber) error {
+	if err := cr.IdentityRegistry.Subscribe(eb); err != nil {
+		return err
+	}
+	if err := eb.Subscribe(AppTopicIdentityRegistration, cr.handleIdentityRegistration); err != nil {
+		return err
+	}
+	if err := eb.Subscribe(AppTopicRegistrationLapsed, cr.handleRegistrationLapsed); err != nil {
+		return err
+	}
+	return eb.Subscribe(AppTopicTransactorRegistration, cr.handleRegistrationRequest)
+}
+
+// GetRegistrationStatus returns the cached registration status of the identity, querying the underlying registry when it is missing or expired.
+func (cr *CachedRegistry) GetRegistrationStatus(chainID int64, id identity.Identity) (RegistrationStatus, error) {
+	key := newStatusCacheKey(chainID, id.Address)
+
+	cr.lock.Lock()
+	entry, ok := cr.entries[key]
+	if ok && cr.now().Before(entry.expiresAt) {
+		cr.lock.Unlock()
+		return entry.status, nil
+	}
+	generation := cr.generations[key]
+	cr.lock.Unlock()
+
+	status, err := cr.IdentityRegistry.GetRegistrationStatus(chainID, id)
+	if err != nil {
+		return status, err
+	}
+
+	cr.lock.Lock()
+	defer cr.lock.Unlock()
+	// Status might have changed while we were querying, do not cache a stale one.
+	if cr.generations[key] == generation {
+		cr.entries[key] = statusCacheEntry{
+			status:    status,
+			expiresAt: cr.now().Add(cr.ttl),
+		}
+	}
+	return status, nil
+}
+
+// Invalidate drops the cached registration status of the identity.
+func (cr *CachedRegistry) Invalidate(chainID int64, id identity.Identity) {
+	key := newStatusCacheKey(chainID, id.Address)
+
+	cr.lock.Lock()
+	defer cr.lock.Unlock()
+	delete(cr.entries, key)
+	cr.generations[key]++
+}
+
+func (cr *CachedRegistry) handleIdentityRegistration(ev AppEventIdentityRegistration) {
+	cr.Invalidate(ev.ChainID, ev.ID)
+}
+
+func (cr *CachedRegistry) handleRegistrationLapsed(ev AppEventRegistrationLapsed) {
+	cr.Invalidate(ev.ChainID, ev.ID)
+}
+
+func (cr *CachedRegistry) handleRegistrationRequest(ev IdentityRegistrationRequest) {
+	cr.Invalidate(ev.ChainID, identity.FromAddress(ev.Identity))
+}
+
+func newStatusCacheKey(chainID int64, address string) statusCacheKey {
+	return statusCacheKey{chainID: chainID, address: identity.FromAddress(address).Address}
+}
@@ -0,0 +1,141 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package registry
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mysteriumnetwork/node/eventbus"
+	"github.com/mysteriumnetwork/node/identity"
+	"github.com/stretchr/testify/assert"
+)
+
+type countingRegistry struct {
+	FakeRegistry
+	calls int
+}
+
+func (cr *countingRegistry) GetRegistrationStatus(chainID int64, id identity.Identity) (RegistrationStatus, error) {
+	cr.calls++
+	return cr.FakeRegistry.GetRegistrationStatus(chainID, id)
+}
+
+func TestCachedRegistry_CachesUntilExpired(t *testing.T) {
+	inner := &countingRegistry{FakeRegistry: FakeRegistry{RegistrationStatus: Unregistered}}
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cached := NewCachedRegistry(inner, time.Minute)
+	cached.now = func() time.Time { return now }
+	id := identity.FromAddress("0x001")
+
+	for i := 0; i < 3; i++ {
+		status, err := cached.GetRegistrationStatus(1, id)
+		assert.NoError(t, err)
+		assert.Equal(t, Unregistered, status)
+	}
+	assert.Equal(t, 1, inner.calls)
+
+	_, err := cached.GetRegistrationStatus(2, id)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, inner.calls, "statuses are cached per chain")
+
+	_, err = cached.GetRegistrationStatus(1, identity.FromAddress("0x002"))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, inner.calls, "statuses are cached per identity")
+
+	inner.RegistrationStatus = Registered
+	now = now.Add(time.Minute)
+	status, err := cached.GetRegistrationStatus(1, id)
+	assert.NoError(t, err)
+	assert.Equal(t, Registered, status)
+	assert.Equal(t, 4, inner.calls)
+}
+
+func TestCachedRegistry_DoesNotCacheErrors(t *testing.T) {
+	inner := &countingRegistry{FakeRegistry: FakeRegistry{RegistrationStatus: RegistrationError, RegistrationCheckError: errors.New("rpc unavailable")}}
+	cached := NewCachedRegistry(inner, time.Minute)
+	id := identity.FromAddress("0x001")
+
+	_, err := cached.GetRegistrationStatus(1, id)
+	assert.Error(t, err)
+
+	inner.RegistrationStatus = Registered
+	inner.RegistrationCheckError = nil
+	status, err := cached.GetRegistrationStatus(1, id)
+	assert.NoError(t, err)
+	assert.Equal(t, Registered, status)
+	assert.Equal(t, 2, inner.calls)
+}
+
+func TestCachedRegistry_InvalidatesOnRegistrationEvents(t *testing.T) {
+	inner := &countingRegistry{FakeRegistry: FakeRegistry{RegistrationStatus: Unregistered}}
+	cached := NewCachedRegistry(inner, time.Hour)
+	bus := eventbus.New()
+	assert.NoError(t, cached.Subscribe(bus))
+	id := identity.FromAddress("0x001")
+
+	_, err := cached.GetRegistrationStatus(1, id)
+	assert.NoError(t, err)
+
+	bus.Publish(AppTopicTransactorRegistration, IdentityRegistrationRequest{Identity: "0x001", ChainID: 1})
+	_, err = cached.GetRegistrationStatus(1, id)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, inner.calls)
+
+	bus.Publish(AppTopicIdentityRegistration, AppEventIdentityRegistration{ID: id, ChainID: 1, Status: Registered})
+	_, err = cached.GetRegistrationStatus(1, id)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, inner.calls)
+
+	bus.Publish(AppTopicRegistrationLapsed, AppEventRegistrationLapsed{ID: id, ChainID: 1, Status: Unregistered})
+	_, err = cached.GetRegistrationStatus(1, id)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, inner.calls)
+
+	bus.Publish(AppTopicIdentityRegistration, AppEventIdentityRegistration{ID: id, ChainID: 2, Status: Registered})
+	_, err = cached.GetRegistrationStatus(1, id)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, inner.calls, "events of other chains keep the status cached")
+}
+
+func TestCachedRegistry_DoesNotCacheStatusInvalidatedDuringQuery(t *testing.T) {
+	id := identity.FromAddress("0x001")
+	var cached *CachedRegistry
+	inner := &invalidatingRegistry{FakeRegistry: FakeRegistry{RegistrationStatus: InProgress}}
+	inner.onQuery = func() { cached.Invalidate(1, id) }
+	cached = NewCachedRegistry(inner, time.Hour)
+
+	_, err := cached.GetRegistrationStatus(1, id)
+	assert.NoError(t, err)
+	_, err = cached.GetRegistrationStatus(1, id)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, inner.calls)
+}
+
+type invalidatingRegistry struct {
+	FakeRegistry
+	calls   int
+	onQuery func()
+}
+
+func (ir *invalidatingRegistry) GetRegistrationStatus(chainID int64, id identity.Identity) (RegistrationStatus, error) {
+	ir.calls++
+	ir.onQuery()
+	return ir.FakeRegistry.GetRegistrationStatus(chainID, id)
+}
@@ -0,0 +1,179 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/eventbus"
+)
+
+// ErrChainMisconfigured is returned when contracts configured for the chain do not exist on it.
+var ErrChainMisconfigured = errors.New("configured contracts do not exist on chain")
+
+// AppTopicChainMisconfigured represents the topic of chains with missing contracts.
+const AppTopicChainMisconfigured = "registry_chain_misconfigured"
+
+// AppEventChainMisconfigured is published when contracts configured for the chain do not exist on it.
+// Automatic registration on the chain is refused until the configuration is fixed.
+type AppEventChainMisconfigured struct {
+	ChainID int64
+	Missing []ChainContract
+}
+
+// ChainContract is a contract node is configured to use on the chain.
+type ChainContract struct {
+	Name    string
+	Address common.Address
+}
+
+const chainCheckTimeout = 10 * time.Second
+
+type chainContractsProvider interface {
+	GetRegistryAddress(chainID int64) (common.Address, error)
+	GetActiveHermes(chainID int64) (common.Address, error)
+	GetActiveChannelImplementation(chainID int64) (common.Address, error)
+}
+
+type contractCodeGetter interface {
+	CodeAt(ctx context.Context, chainID int64, account common.Address) ([]byte, error)
+}
+
+// chainVerifier refuses chains with missing contracts.
+type chainVerifier interface {
+	Verify(chainID int64) error
+}
+
+type chainCheck struct {
+	contracts []ChainContract
+	err       error
+}
+
+// ChainChecker verifies that registry, hermes and channel implementation configured for the chain exist as contracts on it.
+// Results are kept until the configured addresses change, checks which could not be completed are repeated.
+type ChainChecker struct {
+	lock      sync.Mutex
+	addresses chainContractsProvider
+	code      contractCodeGetter
+	publisher eventbus.Publisher
+	checked   map[int64]chainCheck
+}
+
+// NewChainChecker creates new chain checker.
+func NewChainChecker(addresses chainContractsProvider, code contractCodeGetter, publisher eventbus.Publisher) *ChainChecker {
+	return &ChainChecker{
+		addresses: addresses,
+		code:      code,
+		publisher: publisher,
+		checked:   make(map[int64]chainCheck),
+	}
+}
+
+// Verify returns ErrChainMisconfigured if any of the contracts configured for the chain has no code on it.
+// Chain is not refused if the check itself fails, e.g. because blockchain is not reachable.
+func (c *ChainChecker) Verify(chainID int64) error {
+	ev, err := c.verify(chainID)
+	if ev != nil {
+		c.publisher.Publish(AppTopicChainMisconfigured, *ev)
+	}
+	return err
+}
+
+// verify checks the chain unless its configured contracts were checked already,
+// the returned event is not nil if the chain was found to be misconfigured by this check.
+func (c *ChainChecker) verify(chainID int64) (*AppEventChainMisconfigured, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	contracts, err := c.contracts(chainID)
+	if err != nil {
+		log.Warn().Err(err).Msgf("Could not get contracts of chain %d to check", chainID)
+		return nil, nil
+	}
+	if last, ok := c.checked[chainID]; ok && sameContracts(last.contracts, contracts) {
+		return nil, last.err
+	}
+
+	var missing []ChainContract
+	for _, contract := range contracts {
+		ctx, cancel := context.WithTimeout(context.Background(), chainCheckTimeout)
+		code, err := c.code.CodeAt(ctx, chainID, contract.Address)
+		cancel()
+		if err != nil {
+			log.Warn().Err(err).Msgf("Could not get code of %s %s on chain %d", contract.Name, contract.Address.Hex(), chainID)
+			return nil, nil
+		}
+		if len(code) == 0 {
+			missing = append(missing, contract)
+		}
+	}
+
+	if len(missing) == 0 {
+		c.checked[chainID] = chainCheck{contracts: contracts}
+		return nil, nil
+	}
+
+	names := make([]string, len(missing))
+	for i, m := range missing {
+		names[i] = fmt.Sprintf("%s %s", m.Name, m.Address.Hex())
+	}
+	err = fmt.Errorf("%w: chain %d: %s", ErrChainMisconfigured, chainID, strings.Join(names, ", "))
+	c.checked[chainID] = chainCheck{contracts: contracts, err: err}
+	return &AppEventChainMisconfigured{ChainID: chainID, Missing: missing}, err
+}
+
+func (c *ChainChecker) contracts(chainID int64) ([]ChainContract, error) {
+	registry, err := c.addresses.GetRegistryAddress(chainID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get registry address: %w", err)
+	}
+	hermes, err := c.addresses.GetActiveHermes(chainID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get hermes address: %w", err)
+	}
+	channel, err := c.addresses.GetActiveChannelImplementation(chainID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get channel implementation address: %w", err)
+	}
+
+	return []ChainContract{
+		{Name: "registry", Address: registry},
+		{Name: "hermes", Address: hermes},
+		{Name: "channel implementation", Address: channel},
+	}, nil
+}
+
+func sameContracts(a, b []ChainContract) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
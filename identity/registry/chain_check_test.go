@@ -0,0 +1,108 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package registry
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/eventbus"
+)
+
+type mockChainContracts struct {
+	registry, hermes, channel common.Address
+}
+
+func (m *mockChainContracts) GetRegistryAddress(chainID int64) (common.Address, error) {
+	return m.registry, nil
+}
+
+func (m *mockChainContracts) GetActiveHermes(chainID int64) (common.Address, error) {
+	return m.hermes, nil
+}
+
+func (m *mockChainContracts) GetActiveChannelImplementation(chainID int64) (common.Address, error) {
+	return m.channel, nil
+}
+
+type mockCodeGetter struct {
+	code  map[common.Address][]byte
+	err   error
+	calls int
+}
+
+func (m *mockCodeGetter) CodeAt(ctx context.Context, chainID int64, account common.Address) ([]byte, error) {
+	m.calls++
+	return m.code[account], m.err
+}
+
+func TestChainChecker_Verify(t *testing.T) {
+	contracts := &mockChainContracts{
+		registry: common.HexToAddress("0x1"),
+		hermes:   common.HexToAddress("0x2"),
+		channel:  common.HexToAddress("0x3"),
+	}
+	code := &mockCodeGetter{code: map[common.Address][]byte{
+		common.HexToAddress("0x1"): {1},
+		common.HexToAddress("0x3"): {1},
+	}}
+	bus := eventbus.New()
+	var events []AppEventChainMisconfigured
+	assert.NoError(t, bus.Subscribe(AppTopicChainMisconfigured, func(e AppEventChainMisconfigured) {
+		events = append(events, e)
+	}))
+	checker := NewChainChecker(contracts, code, bus)
+
+	err := checker.Verify(137)
+	assert.ErrorIs(t, err, ErrChainMisconfigured)
+	assert.Contains(t, err.Error(), "hermes "+common.HexToAddress("0x2").Hex())
+	assert.Equal(t, []AppEventChainMisconfigured{{
+		ChainID: 137,
+		Missing: []ChainContract{{Name: "hermes", Address: common.HexToAddress("0x2")}},
+	}}, events)
+
+	// result is kept while configuration stays the same
+	assert.ErrorIs(t, checker.Verify(137), ErrChainMisconfigured)
+	assert.Equal(t, 3, code.calls)
+	assert.Len(t, events, 1)
+
+	// configuration change is checked again
+	contracts.hermes = common.HexToAddress("0x3")
+	assert.NoError(t, checker.Verify(137))
+	assert.Equal(t, 6, code.calls)
+	assert.NoError(t, checker.Verify(137))
+	assert.Equal(t, 6, code.calls)
+	assert.Len(t, events, 1)
+}
+
+func TestChainChecker_Verify_IgnoresFailedCheck(t *testing.T) {
+	code := &mockCodeGetter{err: errors.New("rpc unreachable")}
+	checker := NewChainChecker(&mockChainContracts{}, code, eventbus.New())
+
+	assert.NoError(t, checker.Verify(1))
+	assert.Equal(t, 1, code.calls)
+
+	// failed check is repeated
+	code.err = nil
+	assert.ErrorIs(t, checker.Verify(1), ErrChainMisconfigured)
+	assert.Equal(t, 4, code.calls)
+}
@@ -0,0 +1,132 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package registry
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/mysteriumnetwork/payments/client"
+	"github.com/rs/zerolog/log"
+)
+
+// RegistrationMethod selects how identity registration is submitted to chain.
+type RegistrationMethod string
+
+const (
+	// RegistrationMethodTransactor submits registration through transactor, which pays for the transaction.
+	RegistrationMethodTransactor = RegistrationMethod("transactor")
+	// RegistrationMethodOnChain sends registration transaction directly to chain, paid from the identity wallet.
+	RegistrationMethodOnChain = RegistrationMethod("onchain")
+)
+
+// ErrDirectRegistrationUnavailable is returned when node can not sign transactions to register directly on chain.
+var ErrDirectRegistrationUnavailable = errors.New("direct registration is not available")
+
+// ErrWalletNotFunded is returned when identity wallet has no coins to pay for the registration transaction.
+var ErrWalletNotFunded = errors.New("identity wallet has no funds to pay for the transaction")
+
+// AppTopicDirectRegistration represents the topic of registration transactions sent directly to chain.
+const AppTopicDirectRegistration = "direct_identity_registration"
+
+// AppEventDirectRegistration is published when registration transaction is sent to chain bypassing transactor.
+type AppEventDirectRegistration struct {
+	IdentityRegistrationRequest
+	TxHash common.Hash
+}
+
+type txSigner interface {
+	SignTx(a accounts.Account, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+}
+
+// SetTxSigner enables registration directly on chain, transactions are signed by the identity itself.
+func (t *Transactor) SetTxSigner(signer txSigner) {
+	t.txSigner = signer
+}
+
+// RegisterIdentityOnChain registers identity by sending registration transaction to chain directly,
+// so that registration does not depend on transactor being available. Gas is paid from the identity wallet.
+func (t *Transactor) RegisterIdentityOnChain(id string, stake *big.Int, beneficiary string, chainID int64) (common.Hash, error) {
+	if t.txSigner == nil || t.bc == nil {
+		return common.Hash{}, ErrDirectRegistrationUnavailable
+	}
+
+	// Nobody relays the transaction, so there is no transactor fee to pay.
+	regReq, err := t.fillIdentityRegistrationRequest(id, stake, big.NewInt(0), beneficiary, chainID)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to fill in identity request: %w", err)
+	}
+
+	err = t.validateRegisterIdentityRequest(regReq)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("identity request validation failed: %w", err)
+	}
+
+	err = t.checkBeneficiary(regReq)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	bc, err := t.bc.GetClientByChain(chainID)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	address := common.HexToAddress(id)
+	balance, err := bc.GetEthBalance(address)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("could not get balance of %s: %w", id, err)
+	}
+	if balance.Sign() <= 0 {
+		return common.Hash{}, fmt.Errorf("%w: %s on chain %d", ErrWalletNotFunded, id, chainID)
+	}
+
+	tx, err := bc.RegisterIdentity(client.RegistrationRequest{
+		WriteRequest: client.WriteRequest{
+			Identity: address,
+			Signer: func(from common.Address, tx *types.Transaction) (*types.Transaction, error) {
+				return t.txSigner.SignTx(accounts.Account{Address: from}, tx, big.NewInt(chainID))
+			},
+		},
+		ChainID:         chainID,
+		HermesID:        common.HexToAddress(regReq.HermesID),
+		Stake:           regReq.Stake,
+		TransactorFee:   regReq.Fee,
+		Beneficiary:     common.HexToAddress(regReq.Beneficiary),
+		Signature:       common.FromHex(regReq.Signature),
+		RegistryAddress: common.HexToAddress(regReq.RegistryAddress),
+	})
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("could not send registration transaction: %w", err)
+	}
+
+	log.Info().Msgf("Registration transaction of identity %s sent to chain %d: %s", id, chainID, tx.Hash().Hex())
+
+	// This is left as a synchronous call on purpose.
+	// We need to notify registry before returning.
+	t.publisher.Publish(AppTopicDirectRegistration, AppEventDirectRegistration{
+		IdentityRegistrationRequest: regReq,
+		TxHash:                      tx.Hash(),
+	})
+
+	return tx.Hash(), nil
+}
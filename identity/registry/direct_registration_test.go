@@ -0,0 +1,153 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package registry
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/mysteriumnetwork/payments/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mysteriumnetwork/node/eventbus"
+	"github.com/mysteriumnetwork/node/identity"
+)
+
+type mockDirectAddresses struct {
+	AddressProvider
+}
+
+func (m *mockDirectAddresses) GetRegistryAddress(chainID int64) (common.Address, error) {
+	return common.HexToAddress("0x1"), nil
+}
+
+func (m *mockDirectAddresses) GetActiveHermes(chainID int64) (common.Address, error) {
+	return common.HexToAddress("0x2"), nil
+}
+
+func (m *mockDirectAddresses) GetActiveChannelImplementation(chainID int64) (common.Address, error) {
+	return common.HexToAddress("0x3"), nil
+}
+
+type mockDirectSigner struct{}
+
+func (m *mockDirectSigner) Sign(message []byte) (identity.Signature, error) {
+	return identity.SignatureBytes(make([]byte, 65)), nil
+}
+
+type mockTxSigner struct {
+	signed  []common.Address
+	chainID *big.Int
+}
+
+func (m *mockTxSigner) SignTx(a accounts.Account, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	m.signed = append(m.signed, a.Address)
+	m.chainID = chainID
+	return tx, nil
+}
+
+type mockDirectChainClients struct {
+	mockChainClients
+	bc *mockDirectBC
+}
+
+func (m *mockDirectChainClients) GetClientByChain(chainID int64) (client.BC, error) {
+	return m.bc, nil
+}
+
+type mockDirectBC struct {
+	client.BC
+	balance *big.Int
+	sent    []client.RegistrationRequest
+	err     error
+}
+
+func (m *mockDirectBC) Client() client.EtherClient {
+	return &mockBeneficiaryEthClient{}
+}
+
+func (m *mockDirectBC) GetEthBalance(address common.Address) (*big.Int, error) {
+	return m.balance, nil
+}
+
+func (m *mockDirectBC) RegisterIdentity(rr client.RegistrationRequest) (*types.Transaction, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.sent = append(m.sent, rr)
+	tx := types.NewTx(&types.DynamicFeeTx{Nonce: uint64(len(m.sent))})
+	return rr.Signer(rr.Identity, tx)
+}
+
+func TestTransactor_RegisterIdentityOnChain(t *testing.T) {
+	id := "0x5555555555555555555555555555555555555555"
+	bc := &mockDirectBC{balance: big.NewInt(1)}
+	bus := eventbus.New()
+	var sent []AppEventDirectRegistration
+	require.NoError(t, bus.Subscribe(AppTopicDirectRegistration, func(ev AppEventDirectRegistration) {
+		sent = append(sent, ev)
+	}))
+
+	signerFactory := func(identity.Identity) identity.Signer { return &mockDirectSigner{} }
+	tr := NewTransactor(nil, "", &mockDirectAddresses{}, signerFactory, bus, &mockDirectChainClients{bc: bc}, time.Minute, nil)
+
+	_, err := tr.RegisterIdentityOnChain(id, nil, "", 137)
+	assert.ErrorIs(t, err, ErrDirectRegistrationUnavailable)
+
+	signer := &mockTxSigner{}
+	tr.SetTxSigner(signer)
+
+	hash, err := tr.RegisterIdentityOnChain(id, nil, "", 137)
+	require.NoError(t, err)
+	require.Len(t, bc.sent, 1)
+	rr := bc.sent[0]
+	assert.Equal(t, common.HexToAddress(id), rr.Identity)
+	assert.Equal(t, common.HexToAddress("0x1"), rr.RegistryAddress)
+	assert.Equal(t, common.HexToAddress("0x2"), rr.HermesID)
+	assert.Equal(t, big.NewInt(0), rr.TransactorFee)
+	assert.Len(t, rr.Signature, 65)
+	assert.Equal(t, []common.Address{common.HexToAddress(id)}, signer.signed)
+	assert.Equal(t, big.NewInt(137), signer.chainID)
+
+	require.Len(t, sent, 1)
+	assert.Equal(t, hash, sent[0].TxHash)
+	assert.Equal(t, id, sent[0].Identity)
+	assert.Equal(t, int64(137), sent[0].ChainID)
+}
+
+func TestTransactor_RegisterIdentityOnChain_RequiresFunds(t *testing.T) {
+	bc := &mockDirectBC{balance: big.NewInt(0)}
+	signerFactory := func(identity.Identity) identity.Signer { return &mockDirectSigner{} }
+	tr := NewTransactor(nil, "", &mockDirectAddresses{}, signerFactory, eventbus.New(), &mockDirectChainClients{bc: bc}, time.Minute, nil)
+	tr.SetTxSigner(&mockTxSigner{})
+
+	_, err := tr.RegisterIdentityOnChain("0x5555555555555555555555555555555555555555", nil, "", 137)
+	assert.ErrorIs(t, err, ErrWalletNotFunded)
+	assert.Empty(t, bc.sent)
+
+	bc.balance = big.NewInt(1)
+	bc.err = errors.New("insufficient funds for gas * price + value")
+	_, err = tr.RegisterIdentityOnChain("0x5555555555555555555555555555555555555555", nil, "", 137)
+	assert.Error(t, err)
+}
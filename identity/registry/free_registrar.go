@@ -36,6 +36,7 @@ type FreeRegistrar struct {
 	transactor              transactor
 	contractRegistry        IdentityRegistry
 	freeRegistrationEnabled bool
+	chainCheck              chainVerifier
 }
 
 // NewFreeRegistrar creates new free registrar
@@ -48,6 +49,12 @@ func NewFreeRegistrar(selector identity_selector.Handler, transactor transactor,
 	}
 }
 
+// SetChainChecker sets the check refusing free registration on chains with missing contracts,
+// it must be called before Subscribe.
+func (f *FreeRegistrar) SetChainChecker(check chainVerifier) {
+	f.chainCheck = check
+}
+
 // Subscribe subscribes to Node events
 func (f *FreeRegistrar) Subscribe(eb eventbus.Subscriber) error {
 	if !f.freeRegistrationEnabled {
@@ -86,6 +93,12 @@ func (f *FreeRegistrar) handleStart() error {
 		return nil
 	}
 
+	if f.chainCheck != nil {
+		if err := f.chainCheck.Verify(chainID); err != nil {
+			return fmt.Errorf("free registration refused: %w", err)
+		}
+	}
+
 	eligible, err := f.transactor.GetFreeProviderRegistrationEligibility()
 	if err != nil {
 		return fmt.Errorf("failed to check free registration eligibility: %w", err)
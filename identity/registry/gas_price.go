@@ -0,0 +1,339 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package registry
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mysteriumnetwork/payments/client"
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/requests"
+)
+
+// GasSpeed is how fast a transaction should be mined, faster transactions pay higher tips.
+type GasSpeed string
+
+const (
+	// GasSpeedEconomy pays less than suggested by the chain and may be mined later.
+	GasSpeedEconomy GasSpeed = "economy"
+	// GasSpeedNormal pays the fees suggested by the chain.
+	GasSpeedNormal GasSpeed = "normal"
+	// GasSpeedFast pays more than suggested by the chain to be mined sooner.
+	GasSpeedFast GasSpeed = "fast"
+)
+
+// gasSpeedTipPercent scales the tip suggested by the chain for each speed.
+var gasSpeedTipPercent = map[GasSpeed]int64{
+	GasSpeedEconomy: 80,
+	GasSpeedNormal:  100,
+	GasSpeedFast:    150,
+}
+
+// ParseGasSpeed parses the gas speed, empty string defaults to normal.
+func ParseGasSpeed(s string) (GasSpeed, error) {
+	if s == "" {
+		return GasSpeedNormal, nil
+	}
+
+	speed := GasSpeed(strings.ToLower(s))
+	if _, ok := gasSpeedTipPercent[speed]; !ok {
+		return "", fmt.Errorf("unknown gas speed %q, expected one of economy, normal or fast", s)
+	}
+	return speed, nil
+}
+
+// GasPrice is the gas price transactor is asked to use for a transaction.
+// Unset fields are left for the transactor to decide.
+type GasPrice struct {
+	Speed GasSpeed
+	// MaxFeePerGas is the EIP-1559 fee cap in wei.
+	MaxFeePerGas *big.Int
+	// MaxPriorityFeePerGas is the EIP-1559 tip cap in wei.
+	MaxPriorityFeePerGas *big.Int
+}
+
+// Headers sent to transactor with the gas price of the transaction.
+const (
+	gasSpeedHeader             = "X-Gas-Speed"
+	maxFeePerGasHeader         = "X-Gas-Max-Fee-Per-Gas"
+	maxPriorityFeePerGasHeader = "X-Gas-Max-Priority-Fee-Per-Gas"
+)
+
+func (g GasPrice) setHeaders(h http.Header) {
+	if g.Speed != "" {
+		h.Set(gasSpeedHeader, string(g.Speed))
+	}
+	if g.MaxFeePerGas != nil {
+		h.Set(maxFeePerGasHeader, g.MaxFeePerGas.String())
+	}
+	if g.MaxPriorityFeePerGas != nil {
+		h.Set(maxPriorityFeePerGasHeader, g.MaxPriorityFeePerGas.String())
+	}
+}
+
+// override replaces the fees with the ones explicitly given in o.
+func (g GasPrice) override(o GasPrice) GasPrice {
+	if o.Speed != "" {
+		g.Speed = o.Speed
+	}
+	if o.MaxFeePerGas != nil {
+		g.MaxFeePerGas = o.MaxFeePerGas
+	}
+	if o.MaxPriorityFeePerGas != nil {
+		g.MaxPriorityFeePerGas = o.MaxPriorityFeePerGas
+	}
+	if g.MaxFeePerGas != nil && g.MaxPriorityFeePerGas != nil && g.MaxPriorityFeePerGas.Cmp(g.MaxFeePerGas) > 0 {
+		g.MaxPriorityFeePerGas = g.MaxFeePerGas
+	}
+	return g
+}
+
+// GasPriceStrategy picks the gas price of transactions sent to the given chain.
+type GasPriceStrategy interface {
+	// GasPrice returns the gas price for the given speed, empty speed means the configured one.
+	// The price returned along with an error leaves the fees it couldn't determine unset.
+	GasPrice(chainID int64, speed GasSpeed) (GasPrice, error)
+}
+
+// GasStrategyConfig configures the gas price of transactions on a single chain.
+type GasStrategyConfig struct {
+	Speed GasSpeed
+	// MaxTipCap limits the priority fee in wei, nil means no limit.
+	MaxTipCap *big.Int
+}
+
+// ParseGasStrategyConfig parses the gas speed and the tip cap in wei, empty tip cap means no limit.
+func ParseGasStrategyConfig(speed, maxTipCap string) (GasStrategyConfig, error) {
+	s, err := ParseGasSpeed(speed)
+	if err != nil {
+		return GasStrategyConfig{}, err
+	}
+
+	cfg := GasStrategyConfig{Speed: s}
+	if maxTipCap != "" {
+		tip, ok := new(big.Int).SetString(maxTipCap, 10)
+		if !ok || tip.Sign() < 0 {
+			return GasStrategyConfig{}, fmt.Errorf("invalid gas tip cap %q", maxTipCap)
+		}
+		cfg.MaxTipCap = tip
+	}
+	return cfg, nil
+}
+
+type gasClientProvider interface {
+	GetClientByChain(chainID int64) (client.BC, error)
+}
+
+const gasPriceQueryTimeout = 5 * time.Second
+
+// ChainGasStrategy prices transactions by scaling the fees currently suggested by the chain.
+type ChainGasStrategy struct {
+	bc     gasClientProvider
+	chains map[int64]GasStrategyConfig
+}
+
+// NewChainGasStrategy returns a new gas price strategy configured per chain,
+// chains without configuration use the normal speed.
+func NewChainGasStrategy(bc gasClientProvider, chains map[int64]GasStrategyConfig) *ChainGasStrategy {
+	return &ChainGasStrategy{
+		bc:     bc,
+		chains: chains,
+	}
+}
+
+// GasPrice returns the gas price of transactions on the given chain.
+func (s *ChainGasStrategy) GasPrice(chainID int64, speed GasSpeed) (GasPrice, error) {
+	cfg := s.chains[chainID]
+	if speed == "" {
+		speed = cfg.Speed
+	}
+	if speed == "" {
+		speed = GasSpeedNormal
+	}
+	percent, ok := gasSpeedTipPercent[speed]
+	if !ok {
+		return GasPrice{MaxPriorityFeePerGas: cfg.MaxTipCap}, fmt.Errorf("unknown gas speed %q", speed)
+	}
+
+	price := GasPrice{Speed: speed, MaxPriorityFeePerGas: cfg.MaxTipCap}
+	if s.bc == nil {
+		return price, nil
+	}
+
+	bc, err := s.bc.GetClientByChain(chainID)
+	if err != nil {
+		return price, fmt.Errorf("could not get client for chain %d: %w", chainID, err)
+	}
+	eth := bc.Client()
+
+	ctx, cancel := context.WithTimeout(context.Background(), gasPriceQueryTimeout)
+	defer cancel()
+
+	head, err := eth.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return price, fmt.Errorf("could not get latest header: %w", err)
+	}
+
+	if head.BaseFee == nil {
+		gasPrice, err := eth.SuggestGasPrice(ctx)
+		if err != nil {
+			return price, fmt.Errorf("could not get suggested gas price: %w", err)
+		}
+		price.MaxFeePerGas = scalePercent(gasPrice, percent)
+		price.MaxPriorityFeePerGas = nil
+		return price, nil
+	}
+
+	tip, err := eth.SuggestGasTipCap(ctx)
+	if err != nil {
+		return price, fmt.Errorf("could not get suggested gas tip: %w", err)
+	}
+	tip = scalePercent(tip, percent)
+	if cfg.MaxTipCap != nil && tip.Cmp(cfg.MaxTipCap) > 0 {
+		tip = new(big.Int).Set(cfg.MaxTipCap)
+	}
+
+	// Same headroom as go-ethereum leaves for the base fee to rise in the next blocks.
+	price.MaxFeePerGas = new(big.Int).Add(new(big.Int).Mul(head.BaseFee, big.NewInt(2)), tip)
+	price.MaxPriorityFeePerGas = tip
+	return price, nil
+}
+
+func scalePercent(v *big.Int, percent int64) *big.Int {
+	res := new(big.Int).Mul(v, big.NewInt(percent))
+	return res.Div(res, big.NewInt(100))
+}
+
+type gasOverrideKey struct {
+	chainID int64
+	id      string
+}
+
+type gasOverride struct {
+	price GasPrice
+	until time.Time
+}
+
+// gasOverrides holds gas prices requested for transactions of a single identity.
+type gasOverrides struct {
+	lock      sync.Mutex
+	overrides map[gasOverrideKey]*gasOverride
+}
+
+func newGasOverrides() *gasOverrides {
+	return &gasOverrides{overrides: make(map[gasOverrideKey]*gasOverride)}
+}
+
+func (o *gasOverrides) set(chainID int64, id string, price GasPrice, ttl time.Duration) func() {
+	key := gasOverrideKey{chainID: chainID, id: strings.ToLower(id)}
+	entry := &gasOverride{price: price, until: time.Now().Add(ttl)}
+
+	o.lock.Lock()
+	o.overrides[key] = entry
+	o.lock.Unlock()
+
+	return func() {
+		o.lock.Lock()
+		defer o.lock.Unlock()
+		// Don't lift an override set by a newer request.
+		if o.overrides[key] == entry {
+			delete(o.overrides, key)
+		}
+	}
+}
+
+func (o *gasOverrides) get(chainID int64, id string) (GasPrice, bool) {
+	key := gasOverrideKey{chainID: chainID, id: strings.ToLower(id)}
+
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	entry, ok := o.overrides[key]
+	if !ok {
+		return GasPrice{}, false
+	}
+	if time.Now().After(entry.until) {
+		delete(o.overrides, key)
+		return GasPrice{}, false
+	}
+	return entry.price, true
+}
+
+// OverrideGasPrice makes the transactions of the given identity on the given chain use the given gas price
+// instead of the configured strategy, until the returned func is called or ttl passes.
+func (t *Transactor) OverrideGasPrice(chainID int64, id string, price GasPrice, ttl time.Duration) func() {
+	return t.gasOverrides.set(chainID, id, price, ttl)
+}
+
+// gasPrice returns the gas price of a transaction sent on behalf of the given identity.
+func (t *Transactor) gasPrice(chainID int64, id string) GasPrice {
+	override, _ := t.gasOverrides.get(chainID, id)
+	if t.gas == nil {
+		return override
+	}
+
+	price, err := t.gas.GasPrice(chainID, override.Speed)
+	if err != nil {
+		log.Warn().Err(err).Msgf("Could not price gas for chain %d, leaving it to transactor", chainID)
+	}
+	return price.override(override)
+}
+
+// txRequest is a request submitting a transaction to transactor.
+type txRequest struct {
+	*http.Request
+	path    string
+	chainID int64
+	id      string
+	payload interface{}
+}
+
+// newTxRequest creates the request submitting a transaction to transactor.
+func (t *Transactor) newTxRequest(path string, chainID int64, id string, payload interface{}) (*txRequest, error) {
+	req, err := requests.NewPostRequest(t.endpointAddress, path, payload)
+	if err != nil {
+		return nil, err
+	}
+	t.gasPrice(chainID, id).setHeaders(req.Header)
+	return &txRequest{Request: req, path: path, chainID: chainID, id: id, payload: payload}, nil
+}
+
+// doTxRequest sends the transaction request to transactor, parsing the response into res unless it is nil,
+// and records the outcome to the audit log.
+func (t *Transactor) doTxRequest(req *txRequest, res interface{}) error {
+	var err error
+	if res == nil {
+		err = t.httpClient.DoRequest(req.Request)
+	} else {
+		err = t.httpClient.DoRequestAndParseResponse(req.Request, res)
+	}
+
+	if t.audit != nil {
+		if auditErr := t.audit.Record(req.path, req.chainID, req.id, req.payload, err); auditErr != nil {
+			log.Error().Err(auditErr).Msgf("Could not record transactor request %s to audit log", req.path)
+		}
+	}
+	return err
+}
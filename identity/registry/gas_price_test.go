@@ -0,0 +1,151 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package registry
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/mysteriumnetwork/payments/client"
+	pc "github.com/mysteriumnetwork/payments/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mysteriumnetwork/node/requests"
+	"github.com/mysteriumnetwork/node/testkit/fakeapi"
+)
+
+func TestChainGasStrategy_GasPrice(t *testing.T) {
+	eth := &mockGasEthClient{baseFee: big.NewInt(100), tip: big.NewInt(10)}
+	strategy := NewChainGasStrategy(&mockChainClients{eth: eth}, map[int64]GasStrategyConfig{
+		1:     {Speed: GasSpeedFast, MaxTipCap: big.NewInt(12)},
+		80001: {Speed: GasSpeedEconomy},
+	})
+
+	price, err := strategy.GasPrice(80001, "")
+	assert.NoError(t, err)
+	assert.Equal(t, GasPrice{Speed: GasSpeedEconomy, MaxFeePerGas: big.NewInt(208), MaxPriorityFeePerGas: big.NewInt(8)}, price)
+
+	price, err = strategy.GasPrice(80001, GasSpeedFast)
+	assert.NoError(t, err)
+	assert.Equal(t, GasPrice{Speed: GasSpeedFast, MaxFeePerGas: big.NewInt(215), MaxPriorityFeePerGas: big.NewInt(15)}, price)
+
+	// tip is capped by the chain configuration
+	price, err = strategy.GasPrice(1, "")
+	assert.NoError(t, err)
+	assert.Equal(t, GasPrice{Speed: GasSpeedFast, MaxFeePerGas: big.NewInt(212), MaxPriorityFeePerGas: big.NewInt(12)}, price)
+
+	// chains without EIP-1559 get a scaled legacy gas price
+	eth.baseFee = nil
+	eth.gasPrice = big.NewInt(1000)
+	price, err = strategy.GasPrice(80001, GasSpeedNormal)
+	assert.NoError(t, err)
+	assert.Equal(t, GasPrice{Speed: GasSpeedNormal, MaxFeePerGas: big.NewInt(1000)}, price)
+}
+
+func TestParseGasStrategyConfig(t *testing.T) {
+	cfg, err := ParseGasStrategyConfig("", "")
+	assert.NoError(t, err)
+	assert.Equal(t, GasStrategyConfig{Speed: GasSpeedNormal}, cfg)
+
+	cfg, err = ParseGasStrategyConfig("FAST", "30000000000")
+	assert.NoError(t, err)
+	assert.Equal(t, GasStrategyConfig{Speed: GasSpeedFast, MaxTipCap: big.NewInt(30000000000)}, cfg)
+
+	_, err = ParseGasStrategyConfig("turbo", "")
+	assert.Error(t, err)
+	_, err = ParseGasStrategyConfig("normal", "-1")
+	assert.Error(t, err)
+}
+
+func TestTransactor_SendsGasPrice(t *testing.T) {
+	fake := fakeapi.NewTransactor()
+	url := fake.Start()
+	defer fake.Close()
+
+	strategy := NewChainGasStrategy(nil, map[int64]GasStrategyConfig{80001: {Speed: GasSpeedEconomy, MaxTipCap: big.NewInt(5)}})
+	tr := NewTransactor(requests.NewHTTPClient("0.0.0.0", time.Second), url, nil, nil, nil, nil, time.Minute, strategy)
+
+	_, err := tr.SettleAndRebalance("0x1", "0xProvider", testPromise(80001))
+	require.NoError(t, err)
+
+	release := tr.OverrideGasPrice(80001, "0xprovider", GasPrice{Speed: GasSpeedFast, MaxFeePerGas: big.NewInt(3)}, time.Minute)
+	_, err = tr.SettleAndRebalance("0x1", "0xProvider", testPromise(80001))
+	require.NoError(t, err)
+
+	// overrides only apply to the given identity and chain
+	_, err = tr.SettleAndRebalance("0x1", "0xProvider", testPromise(1))
+	require.NoError(t, err)
+
+	release()
+	_, err = tr.SettleAndRebalance("0x1", "0xProvider", testPromise(80001))
+	require.NoError(t, err)
+
+	reqs := fake.Requests(http.MethodPost, "identity/settle_and_rebalance")
+	require.Len(t, reqs, 4)
+	assertGasHeaders(t, reqs[0].Header, "economy", "", "5")
+	assertGasHeaders(t, reqs[1].Header, "fast", "3", "3")
+	assertGasHeaders(t, reqs[2].Header, "normal", "", "")
+	assertGasHeaders(t, reqs[3].Header, "economy", "", "5")
+}
+
+func TestTransactor_GasPriceOverrideExpires(t *testing.T) {
+	tr := NewTransactor(nil, "", nil, nil, nil, nil, time.Minute, nil)
+
+	tr.OverrideGasPrice(80001, "0x1", GasPrice{Speed: GasSpeedFast}, -time.Second)
+	assert.Equal(t, GasPrice{}, tr.gasPrice(80001, "0x1"))
+
+	release := tr.OverrideGasPrice(80001, "0x1", GasPrice{Speed: GasSpeedFast}, time.Minute)
+	tr.OverrideGasPrice(80001, "0x1", GasPrice{Speed: GasSpeedEconomy}, time.Minute)
+	// releasing a replaced override keeps the newer one
+	release()
+	assert.Equal(t, GasPrice{Speed: GasSpeedEconomy}, tr.gasPrice(80001, "0x1"))
+}
+
+func testPromise(chainID int64) pc.Promise {
+	return pc.Promise{ChainID: chainID, ChannelID: []byte{1}, Amount: big.NewInt(10), Fee: big.NewInt(1), R: []byte{2}, Signature: []byte{3}}
+}
+
+func assertGasHeaders(t *testing.T, h http.Header, speed, maxFee, tip string) {
+	assert.Equal(t, speed, h.Get(gasSpeedHeader))
+	assert.Equal(t, maxFee, h.Get(maxFeePerGasHeader))
+	assert.Equal(t, tip, h.Get(maxPriorityFeePerGasHeader))
+}
+
+type mockGasEthClient struct {
+	client.EtherClient
+	baseFee  *big.Int
+	tip      *big.Int
+	gasPrice *big.Int
+}
+
+func (m *mockGasEthClient) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return &types.Header{BaseFee: m.baseFee}, nil
+}
+
+func (m *mockGasEthClient) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return m.tip, nil
+}
+
+func (m *mockGasEthClient) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return m.gasPrice, nil
+}
@@ -26,4 +26,6 @@ import (
 type IdentityRegistry interface {
 	Subscribe(eventbus.Subscriber) error
 	GetRegistrationStatus(chainID int64, id identity.Identity) (RegistrationStatus, error)
+	// GetChainRegistrationStatus returns registration status in the current registry contract, ignoring the stored one.
+	GetChainRegistrationStatus(chainID int64, id identity.Identity) (RegistrationStatus, error)
 }
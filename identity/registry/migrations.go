@@ -0,0 +1,77 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package registry
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Migration declares that identities registered in the From registry of the chain have to be registered in the To registry.
+type Migration struct {
+	ChainID int64
+	From    common.Address
+	To      common.Address
+}
+
+// String formats the migration as chainID:from:to.
+func (m Migration) String() string {
+	return fmt.Sprintf("%d:%s:%s", m.ChainID, m.From.Hex(), m.To.Hex())
+}
+
+// ParseMigration parses the migration from chainID:from:to format.
+func ParseMigration(value string) (Migration, error) {
+	parts := strings.Split(strings.TrimSpace(value), ":")
+	if len(parts) != 3 {
+		return Migration{}, fmt.Errorf("invalid registry migration %q, expected chainID:from:to", value)
+	}
+
+	chainID, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil || chainID <= 0 {
+		return Migration{}, fmt.Errorf("invalid chain ID of registry migration %q", value)
+	}
+	if !common.IsHexAddress(parts[1]) || !common.IsHexAddress(parts[2]) {
+		return Migration{}, fmt.Errorf("invalid registry address of registry migration %q", value)
+	}
+
+	m := Migration{
+		ChainID: chainID,
+		From:    common.HexToAddress(parts[1]),
+		To:      common.HexToAddress(parts[2]),
+	}
+	if m.From == m.To {
+		return Migration{}, fmt.Errorf("registry migration %q does not change the registry", value)
+	}
+	return m, nil
+}
+
+// ParseMigrations parses the migration table, failing on the first invalid migration.
+func ParseMigrations(values []string) ([]Migration, error) {
+	migrations := make([]Migration, 0, len(values))
+	for _, v := range values {
+		m, err := ParseMigration(v)
+		if err != nil {
+			return nil, err
+		}
+		migrations = append(migrations, m)
+	}
+	return migrations, nil
+}
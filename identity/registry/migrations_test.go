@@ -0,0 +1,63 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package registry
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMigration(t *testing.T) {
+	from := "0x87F0F4b7e0FAb14A565C87BAbbA6c40c92281b51"
+	to := "0x935F2f2AFb60Ee4DD8a4f0c4a12B79FA8A2c0c91"
+
+	m, err := ParseMigration("137:" + from + ":" + to)
+	assert.NoError(t, err)
+	assert.Equal(t, Migration{ChainID: 137, From: common.HexToAddress(from), To: common.HexToAddress(to)}, m)
+
+	parsed, err := ParseMigration(m.String())
+	assert.NoError(t, err)
+	assert.Equal(t, m, parsed)
+
+	for _, invalid := range []string{
+		"",
+		"137:" + from,
+		"polygon:" + from + ":" + to,
+		"0:" + from + ":" + to,
+		"137:0x1:" + to,
+		"137:" + from + ":" + from,
+	} {
+		_, err := ParseMigration(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestParseMigrations(t *testing.T) {
+	migrations, err := ParseMigrations([]string{
+		"137:0x0000000000000000000000000000000000000001:0x0000000000000000000000000000000000000002",
+		"80001:0x0000000000000000000000000000000000000003:0x0000000000000000000000000000000000000004",
+	})
+	assert.NoError(t, err)
+	assert.Len(t, migrations, 2)
+	assert.Equal(t, int64(80001), migrations[1].ChainID)
+
+	_, err = ParseMigrations([]string{"137:0x1:0x2"})
+	assert.Error(t, err)
+}
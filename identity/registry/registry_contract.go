@@ -31,7 +31,6 @@ import (
 	"github.com/mysteriumnetwork/node/identity"
 	identity_selector "github.com/mysteriumnetwork/node/identity/selector"
 	"github.com/mysteriumnetwork/payments/bindings"
-	paymentClient "github.com/mysteriumnetwork/payments/client"
 	"github.com/mysteriumnetwork/payments/crypto"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
@@ -50,6 +49,7 @@ type hermesCaller interface {
 type transactor interface {
 	FetchRegistrationStatus(id string) ([]TransactorStatusResponse, error)
 	GetFreeProviderRegistrationEligibility() (bool, error)
+	FetchRegistrationFees(chainID int64) (FeesResponse, error)
 	RegisterProviderIdentity(id string, stake, fee *big.Int, beneficiary string, chainID int64, referralToken *string) error
 }
 
@@ -59,7 +59,7 @@ type contractRegistry struct {
 	once       sync.Once
 	publisher  eventbus.Publisher
 	lock       sync.Mutex
-	ethC       paymentClient.EtherClient
+	ethC       bind.ContractCaller
 	ap         AddressProvider
 	hermes     hermesCaller
 	transactor transactor
@@ -74,7 +74,7 @@ type IdentityRegistryConfig struct {
 }
 
 // NewIdentityRegistryContract creates identity registry service which uses blockchain for information
-func NewIdentityRegistryContract(ethClient paymentClient.EtherClient, ap AddressProvider, registryStorage registryStorage, publisher eventbus.Publisher, caller hermesCaller, transactor transactor, selector identity_selector.Handler, cfg IdentityRegistryConfig) (*contractRegistry, error) {
+func NewIdentityRegistryContract(ethClient bind.ContractCaller, ap AddressProvider, registryStorage registryStorage, publisher eventbus.Publisher, caller hermesCaller, transactor transactor, selector identity_selector.Handler, cfg IdentityRegistryConfig) (*contractRegistry, error) {
 	return &contractRegistry{
 		storage:    registryStorage,
 		stop:       make(chan struct{}),
@@ -97,7 +97,11 @@ func (registry *contractRegistry) Subscribe(eb eventbus.Subscriber) error {
 	if err != nil {
 		return err
 	}
-	return eb.Subscribe(AppTopicTransactorRegistration, registry.handleRegistrationEvent)
+	err = eb.Subscribe(AppTopicTransactorRegistration, registry.handleRegistrationEvent)
+	if err != nil {
+		return err
+	}
+	return eb.Subscribe(AppTopicDirectRegistration, registry.handleDirectRegistrationEvent)
 }
 
 // GetRegistrationStatus returns the registration status of the provided identity
@@ -148,13 +152,13 @@ func (registry *contractRegistry) GetRegistrationStatus(chainID int64, id identi
 		return newStatus, errors.Wrap(err, "could not store registration status")
 	}
 
-	// If current status was not registered and we are now registered
-	// publish an event for that to make sure that wasn't missed.
-	if currentStatus != newStatus && newStatus == Registered {
+	// Publish every status change found on chain to make sure it wasn't missed.
+	if currentStatus != newStatus {
 		go registry.publisher.Publish(AppTopicIdentityRegistration, AppEventIdentityRegistration{
-			ID:      id,
-			Status:  newStatus,
-			ChainID: chainID,
+			ID:       id,
+			Status:   newStatus,
+			ChainID:  chainID,
+			Previous: currentStatus,
 		})
 	}
 	return newStatus, nil
@@ -172,13 +176,27 @@ func (registry *contractRegistry) handleNodeEvent(ev event.Payload) {
 }
 
 func (registry *contractRegistry) handleRegistrationEvent(ev IdentityRegistrationRequest) {
+	if registry.startRegistration(ev) {
+		go registry.subscribeToRegistrationEventViaTransactor(ev)
+	}
+}
+
+func (registry *contractRegistry) handleDirectRegistrationEvent(ev AppEventDirectRegistration) {
+	if registry.startRegistration(ev.IdentityRegistrationRequest) {
+		go registry.watchRegistrationOnChain(ev)
+	}
+}
+
+// startRegistration marks registration of the identity as in progress,
+// it returns false if the registration does not need to be watched.
+func (registry *contractRegistry) startRegistration(ev IdentityRegistrationRequest) bool {
 	registry.lock.Lock()
 	defer registry.lock.Unlock()
 
 	status, err := registry.storage.Get(ev.ChainID, identity.FromAddress(ev.Identity))
 	if err != nil && err != ErrNotFound {
 		log.Error().Err(err).Msg("Could not get status from local db")
-		return
+		return false
 	}
 
 	if err == ErrNotFound {
@@ -186,13 +204,13 @@ func (registry *contractRegistry) handleRegistrationEvent(ev IdentityRegistratio
 		err := registry.storage.Store(status)
 		if err != nil {
 			log.Error().Err(err).Stack().Msg("Could not store registration status")
-			return
+			return false
 		}
 	}
 
 	if status.RegistrationStatus == Registered {
 		log.Info().Msgf("Identity %q already registered, skipping", ev.Identity)
-		return
+		return false
 	}
 
 	s := InProgress
@@ -210,9 +228,10 @@ func (registry *contractRegistry) handleRegistrationEvent(ev IdentityRegistratio
 	ID := identity.FromAddress(ev.Identity)
 
 	go registry.publisher.Publish(AppTopicIdentityRegistration, AppEventIdentityRegistration{
-		ID:      ID,
-		Status:  s,
-		ChainID: ev.ChainID,
+		ID:       ID,
+		Status:   s,
+		ChainID:  ev.ChainID,
+		Previous: status.RegistrationStatus,
 	})
 	err = registry.storage.Store(StoredRegistrationStatus{
 		Identity:           ID,
@@ -223,7 +242,7 @@ func (registry *contractRegistry) handleRegistrationEvent(ev IdentityRegistratio
 		log.Error().Err(err).Stack().Msg("Could not store registration status")
 	}
 
-	go registry.subscribeToRegistrationEventViaTransactor(ev)
+	return true
 }
 
 func (registry *contractRegistry) subscribeToRegistrationEventViaTransactor(ev IdentityRegistrationRequest) {
@@ -283,6 +302,32 @@ func (registry *contractRegistry) subscribeToRegistrationEventViaTransactor(ev I
 	}
 }
 
+// watchRegistrationOnChain polls registration status on chain until the registration transaction is mined.
+func (registry *contractRegistry) watchRegistrationOnChain(ev AppEventDirectRegistration) {
+	timeout := time.After(registry.cfg.TransactorPollTimeout)
+	for {
+		select {
+		case <-registry.stop:
+			registry.saveRegistrationStatus(ev.ChainID, ev.Identity, RegistrationError)
+			return
+		case <-timeout:
+			log.Info().Msgf("Registration transaction %s was not mined in time", ev.TxHash.Hex())
+			registry.saveRegistrationStatus(ev.ChainID, ev.Identity, RegistrationError)
+			return
+		case <-time.After(registry.cfg.TransactorPollInterval):
+			status, err := registry.bcRegistrationStatus(ev.ChainID, identity.FromAddress(ev.Identity))
+			if err != nil {
+				log.Warn().Err(err).Msg("could not check registration status on chain")
+				break
+			}
+			if status == Registered {
+				registry.saveRegistrationStatus(ev.ChainID, ev.Identity, Registered)
+				return
+			}
+		}
+	}
+}
+
 func (registry *contractRegistry) resyncWithBC(chainID int64, id string) {
 	status, err := registry.bcRegistrationStatus(chainID, identity.FromAddress(id))
 	if err != nil {
@@ -294,6 +339,11 @@ func (registry *contractRegistry) resyncWithBC(chainID int64, id string) {
 }
 
 func (registry *contractRegistry) saveRegistrationStatus(chainID int64, id string, status RegistrationStatus) {
+	previous := Unregistered
+	if stored, err := registry.storage.Get(chainID, identity.FromAddress(id)); err == nil {
+		previous = stored.RegistrationStatus
+	}
+
 	err := registry.storage.Store(StoredRegistrationStatus{
 		Identity:           identity.FromAddress(id),
 		RegistrationStatus: status,
@@ -304,9 +354,10 @@ func (registry *contractRegistry) saveRegistrationStatus(chainID int64, id strin
 	}
 
 	registry.publisher.Publish(AppTopicIdentityRegistration, AppEventIdentityRegistration{
-		ID:      identity.FromAddress(id),
-		Status:  status,
-		ChainID: chainID,
+		ID:       identity.FromAddress(id),
+		Status:   status,
+		ChainID:  chainID,
+		Previous: previous,
 	})
 }
 
@@ -380,6 +431,11 @@ func (registry *contractRegistry) handleUnregisteredIdentityInitialLoad(chainID
 	return nil
 }
 
+// GetChainRegistrationStatus returns registration status of the identity in the current registry contract.
+func (registry *contractRegistry) GetChainRegistrationStatus(chainID int64, id identity.Identity) (RegistrationStatus, error) {
+	return registry.bcRegistrationStatus(chainID, id)
+}
+
 func (registry *contractRegistry) bcRegistrationStatus(chainID int64, id identity.Identity) (RegistrationStatus, error) {
 	reg, err := registry.ap.GetRegistryAddress(chainID)
 	if err != nil {
@@ -33,6 +33,11 @@ func (registry *FakeRegistry) GetRegistrationStatus(chainID int64, id identity.I
 	return registry.RegistrationStatus, registry.RegistrationCheckError
 }
 
+// GetChainRegistrationStatus returns fake identity registration status within payments contract
+func (registry *FakeRegistry) GetChainRegistrationStatus(chainID int64, id identity.Identity) (RegistrationStatus, error) {
+	return registry.RegistrationStatus, registry.RegistrationCheckError
+}
+
 // Subscribe does nothing
 func (registry *FakeRegistry) Subscribe(eventbus.Subscriber) error {
 	return nil
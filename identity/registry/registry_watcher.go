@@ -0,0 +1,786 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package registry
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cast"
+
+	"github.com/mysteriumnetwork/node/config"
+	"github.com/mysteriumnetwork/node/core/beneficiary"
+	"github.com/mysteriumnetwork/node/core/node/event"
+	"github.com/mysteriumnetwork/node/core/service/servicestate"
+	"github.com/mysteriumnetwork/node/core/watchdog"
+	"github.com/mysteriumnetwork/node/eventbus"
+	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/identity/overrides"
+)
+
+const (
+	registrationRetryBackoff    = time.Minute
+	maxRegistrationRetryBackoff = 6 * time.Hour
+)
+
+// ErrRegistrationFeeTooHigh is returned when the current registration fee exceeds the configured maximum.
+var ErrRegistrationFeeTooHigh = errors.New("registration fee exceeds the configured maximum")
+
+type registryAddressStorage interface {
+	GetAll() ([]StoredRegistrationStatus, error)
+	Reset(chainID int64, identity identity.Identity) error
+	GetRegistryAddress(chainID int64) (common.Address, error)
+	StoreRegistryAddress(chainID int64, address common.Address) error
+	StoreRetry(retry StoredRegistrationRetry) error
+	GetRetries() ([]StoredRegistrationRetry, error)
+	DeleteRetry(chainID int64, identity identity.Identity) error
+	GetReferralToken(identity identity.Identity) (string, error)
+	SetReferralToken(identity identity.Identity, token string) error
+}
+
+type registryAddressProvider interface {
+	GetRegistryAddress(chainID int64) (common.Address, error)
+}
+
+type beneficiaryResolver interface {
+	Address(identity string) (string, error)
+}
+
+type identityOverrides interface {
+	Get(id identity.Identity) (overrides.Overrides, error)
+}
+
+type registrationKey struct {
+	chainID  int64
+	identity identity.Identity
+}
+
+// registrationRetry tracks failed registrations of a single identity,
+// so that the failing identity is retried with backoff without holding back the others.
+// Retries are persisted, so that pending registrations survive node restarts.
+type registrationRetry struct {
+	attempts  int
+	lastError string
+	next      time.Time
+}
+
+// IdentityRegistration describes registration state of a single identity on a single chain.
+type IdentityRegistration struct {
+	Identity  identity.Identity
+	ChainID   int64
+	Status    RegistrationStatus
+	UpdatedAt time.Time
+	// Services is the number of services currently provided by the identity.
+	Services    int
+	Beneficiary string
+	// Attempts, LastError and NextAttempt describe failed registration attempts, zero if there were none.
+	Attempts    int
+	LastError   string
+	NextAttempt time.Time
+}
+
+// RegistrationFee describes the current registration fee of a chain
+// and whether it is accepted when identities are registered automatically.
+type RegistrationFee struct {
+	ChainID    int64
+	Fee        *big.Int
+	ValidUntil time.Time
+	// MaxFee is the configured maximum fee, nil if there is no limit.
+	MaxFee  *big.Int
+	Allowed bool
+}
+
+// RegistryWatcher detects registry contract changes of the configured chains
+// and re-runs registration of the identities which were registered in the previous registry.
+// Registry migrations declared in the migration table are applied even if the previous registry was not seen.
+// It also periodically re-checks registrations of known identities to notice the ones which lapsed,
+// and registers every identity which provides services, each with its own beneficiary and retry schedule.
+// If chain checker is set, identities are not registered on chains whose configured contracts do not exist.
+// Beneficiary and registration chain overrides of the identity, if set, take precedence over the saved
+// beneficiary and the global configuration.
+type RegistryWatcher struct {
+	lock             sync.Mutex
+	chains           []int64
+	addressProvider  registryAddressProvider
+	storage          registryAddressStorage
+	registry         IdentityRegistry
+	transactor       transactor
+	beneficiaries    beneficiaryResolver
+	publisher        eventbus.Publisher
+	freeRegistration bool
+	interval         time.Duration
+	timeGetter       func() time.Time
+
+	providers  map[identity.Identity]map[string]struct{}
+	retries    map[registrationKey]registrationRetry
+	migrations []Migration
+	applied    map[Migration]struct{}
+
+	chainCheck        chainVerifier
+	identityOverrides identityOverrides
+	heartbeat         *watchdog.Heartbeat
+	stop              chan struct{}
+	stopOnce          sync.Once
+}
+
+// NewRegistryWatcher creates new registry watcher.
+func NewRegistryWatcher(
+	chains []int64,
+	addressProvider registryAddressProvider,
+	storage registryAddressStorage,
+	registry IdentityRegistry,
+	transactor transactor,
+	beneficiaries beneficiaryResolver,
+	publisher eventbus.Publisher,
+	migrations []Migration,
+	freeRegistration bool,
+	interval time.Duration,
+) *RegistryWatcher {
+	return &RegistryWatcher{
+		chains:           chains,
+		addressProvider:  addressProvider,
+		storage:          storage,
+		registry:         registry,
+		transactor:       transactor,
+		beneficiaries:    beneficiaries,
+		publisher:        publisher,
+		freeRegistration: freeRegistration,
+		interval:         interval,
+		timeGetter:       time.Now,
+		providers:        make(map[identity.Identity]map[string]struct{}),
+		retries:          make(map[registrationKey]registrationRetry),
+		migrations:       migrations,
+		applied:          make(map[Migration]struct{}),
+		stop:             make(chan struct{}),
+	}
+}
+
+// Start periodically re-checks registrations of known identities until stopped.
+func (w *RegistryWatcher) Start() {
+	if w.interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-w.stop:
+				return
+			case <-ticker.C:
+				w.Recheck()
+				w.heartbeat.Beat()
+			}
+		}
+	}()
+}
+
+// SetHeartbeat sets the heartbeat reported after every periodic check, it must be called before Start.
+func (w *RegistryWatcher) SetHeartbeat(heartbeat *watchdog.Heartbeat) {
+	w.heartbeat = heartbeat
+}
+
+// SetChainChecker sets the check refusing automatic registration on chains with missing contracts,
+// it must be called before Subscribe.
+func (w *RegistryWatcher) SetChainChecker(check chainVerifier) {
+	w.chainCheck = check
+}
+
+// SetOverrides sets the identity overrides of beneficiary and registration chain, it must be called before Subscribe.
+func (w *RegistryWatcher) SetOverrides(o identityOverrides) {
+	w.identityOverrides = o
+}
+
+// Stop stops periodic registration checks.
+func (w *RegistryWatcher) Stop() {
+	w.stopOnce.Do(func() {
+		close(w.stop)
+	})
+}
+
+// Subscribe subscribes to node start, service status, registry configuration and migration table changes.
+func (w *RegistryWatcher) Subscribe(eb eventbus.Subscriber) error {
+	if err := eb.SubscribeAsync(event.AppTopicNode, w.handleNodeEvent); err != nil {
+		return err
+	}
+	if err := eb.SubscribeAsync(servicestate.AppTopicServiceStatus, w.handleServiceEvent); err != nil {
+		return err
+	}
+	if err := eb.SubscribeAsync(config.AppTopicConfig(config.FlagRegistryMigrations.Name), w.handleMigrationsChange); err != nil {
+		return err
+	}
+	for _, flag := range []string{config.FlagChain1RegistryAddress.Name, config.FlagChain2RegistryAddress.Name} {
+		if err := eb.SubscribeAsync(config.AppTopicConfig(flag), w.handleConfigChange); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *RegistryWatcher) handleNodeEvent(ev event.Payload) {
+	if ev.Status == event.StatusStarted {
+		w.checkChains()
+		w.restoreRetries()
+		w.Check()
+	}
+}
+
+// checkChains runs the pre-flight check of the configured chains before any registration is attempted.
+func (w *RegistryWatcher) checkChains() {
+	if w.chainCheck == nil {
+		return
+	}
+	for _, chainID := range w.chains {
+		if err := w.chainCheck.Verify(chainID); err != nil {
+			log.Error().Err(err).Msgf("Automatic registration on chain %d is disabled", chainID)
+		}
+	}
+}
+
+func (w *RegistryWatcher) handleConfigChange(_ interface{}) {
+	w.Check()
+}
+
+func (w *RegistryWatcher) handleMigrationsChange(value interface{}) {
+	migrations, err := ParseMigrations(cast.ToStringSlice(value))
+	if err != nil {
+		log.Error().Err(err).Msg("Ignoring invalid registry migration table")
+		return
+	}
+
+	w.lock.Lock()
+	w.migrations = migrations
+	w.lock.Unlock()
+
+	w.Check()
+}
+
+func (w *RegistryWatcher) handleServiceEvent(ev servicestate.AppEventServiceStatus) {
+	id := identity.FromAddress(ev.ProviderID)
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	switch servicestate.State(ev.Status) {
+	case servicestate.Running:
+		if w.providers[id] == nil {
+			w.providers[id] = make(map[string]struct{})
+		}
+		w.providers[id][ev.ID] = struct{}{}
+		for _, chainID := range w.registrationChains(id) {
+			w.registerProvider(chainID, id)
+		}
+	case servicestate.NotRunning:
+		delete(w.providers[id], ev.ID)
+		if len(w.providers[id]) == 0 {
+			delete(w.providers, id)
+		}
+	}
+}
+
+func (w *RegistryWatcher) chainID() int64 {
+	return config.GetInt64(config.FlagChainID)
+}
+
+// registrationChains returns the chains the identity providing services is registered on,
+// the chain it overrides if it is a configured one, otherwise every configured chain
+// if registration on all chains is enabled, otherwise the active one.
+func (w *RegistryWatcher) registrationChains(id identity.Identity) []int64 {
+	if chainID := w.overridesOf(id).ChainID; chainID != 0 {
+		for _, c := range w.chains {
+			if c == chainID {
+				return []int64{chainID}
+			}
+		}
+		log.Warn().Msgf("Chain %d preferred by identity %s is not configured, ignoring it", chainID, id.Address)
+	}
+	if config.GetBool(config.FlagPaymentsRegistrationAllChains) {
+		return w.chains
+	}
+	return []int64{w.chainID()}
+}
+
+// Check compares registry addresses of the configured chains with the last seen ones
+// and re-registers known identities on the chains where registry has changed.
+func (w *RegistryWatcher) Check() {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	for _, chainID := range w.chains {
+		if err := w.checkChain(chainID); err != nil {
+			log.Error().Err(err).Msgf("Could not check registry of chain %d", chainID)
+		}
+	}
+}
+
+// Recheck queries registration status of the identities known as registered on the configured chains
+// and re-registers the ones which are no longer registered. Identities providing services are registered
+// on the active chain, or on every configured chain if enabled, if they are not registered yet.
+func (w *RegistryWatcher) Recheck() {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	for _, chainID := range w.chains {
+		if err := w.reregister(chainID); err != nil {
+			log.Error().Err(err).Msgf("Could not re-check registrations on chain %d", chainID)
+		}
+	}
+
+	for id := range w.providers {
+		for _, chainID := range w.registrationChains(id) {
+			w.registerProvider(chainID, id)
+		}
+	}
+	w.replayRetries()
+}
+
+// Registrations returns registration state of the known and the service providing identities.
+func (w *RegistryWatcher) Registrations() ([]IdentityRegistration, error) {
+	statuses, err := w.storage.GetAll()
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, fmt.Errorf("could not get registration statuses: %w", err)
+	}
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	result := make([]IdentityRegistration, 0, len(statuses)+len(w.providers))
+	seen := make(map[registrationKey]struct{})
+	for _, s := range statuses {
+		key := registrationKey{chainID: s.ChainID, identity: s.Identity}
+		seen[key] = struct{}{}
+		result = append(result, w.registration(key, s.RegistrationStatus, s.UpdatedAt))
+	}
+
+	for id := range w.providers {
+		for _, chainID := range w.registrationChains(id) {
+			key := registrationKey{chainID: chainID, identity: id}
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			status, err := w.registry.GetRegistrationStatus(chainID, id)
+			if err != nil {
+				status = Unknown
+			}
+			result = append(result, w.registration(key, status, time.Time{}))
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Identity.Address != result[j].Identity.Address {
+			return result[i].Identity.Address < result[j].Identity.Address
+		}
+		return result[i].ChainID < result[j].ChainID
+	})
+	return result, nil
+}
+
+func (w *RegistryWatcher) registration(key registrationKey, status RegistrationStatus, updatedAt time.Time) IdentityRegistration {
+	retry := w.retries[key]
+	return IdentityRegistration{
+		Identity:    key.identity,
+		ChainID:     key.chainID,
+		Status:      status,
+		UpdatedAt:   updatedAt,
+		Services:    len(w.providers[key.identity]),
+		Beneficiary: w.beneficiary(key.identity),
+		Attempts:    retry.attempts,
+		LastError:   retry.lastError,
+		NextAttempt: retry.next,
+	}
+}
+
+func (w *RegistryWatcher) checkChain(chainID int64) error {
+	current, err := w.addressProvider.GetRegistryAddress(chainID)
+	if err != nil {
+		return fmt.Errorf("could not get registry address: %w", err)
+	}
+
+	previous, err := w.storage.GetRegistryAddress(chainID)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+
+	seen := err == nil
+	migration, migrate := w.pendingMigration(chainID, current, previous, seen)
+	switch {
+	case seen && previous != current:
+		log.Info().Msgf("Registry of chain %d changed from %s to %s, re-registering identities", chainID, previous.Hex(), current.Hex())
+	case migrate:
+		log.Info().Msgf("Applying registry migration %s, re-registering identities", migration)
+	default:
+		return w.storage.StoreRegistryAddress(chainID, current)
+	}
+
+	if err := w.reregister(chainID); err != nil {
+		return err
+	}
+	if migrate {
+		w.applied[migration] = struct{}{}
+	}
+	return w.storage.StoreRegistryAddress(chainID, current)
+}
+
+// pendingMigration returns the not yet applied migration into the current registry of the chain.
+// Migration is pending unless the chain was already seen using a registry other than the one it migrates from.
+func (w *RegistryWatcher) pendingMigration(chainID int64, current, previous common.Address, seen bool) (Migration, bool) {
+	for _, m := range w.migrations {
+		if m.ChainID != chainID || m.To != current {
+			continue
+		}
+		if _, ok := w.applied[m]; ok {
+			continue
+		}
+		if seen && previous != m.From {
+			continue
+		}
+		return m, true
+	}
+	return Migration{}, false
+}
+
+func (w *RegistryWatcher) reregister(chainID int64) error {
+	statuses, err := w.storage.GetAll()
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return fmt.Errorf("could not get registration statuses: %w", err)
+	}
+
+	// Registry address is stored only if every identity was checked, so that failed checks are retried.
+	// Failure of a single identity does not prevent checking the rest of them.
+	var failed []string
+	for _, s := range statuses {
+		if s.ChainID != chainID || s.RegistrationStatus != Registered {
+			continue
+		}
+
+		status, err := w.registry.GetChainRegistrationStatus(chainID, s.Identity)
+		if err != nil {
+			log.Error().Err(err).Msgf("Could not check registration status of %s on chain %d", s.Identity.Address, chainID)
+			failed = append(failed, s.Identity.Address)
+			continue
+		}
+		if status == Registered {
+			continue
+		}
+
+		log.Warn().Msgf("Registration of identity %s on chain %d lapsed, status: %s", s.Identity.Address, chainID, status)
+		w.publisher.Publish(AppTopicRegistrationLapsed, AppEventRegistrationLapsed{
+			ID:      s.Identity,
+			ChainID: chainID,
+			Status:  status,
+		})
+
+		if err := w.register(chainID, s.Identity, s.RegistrationStatus); err != nil {
+			failed = append(failed, s.Identity.Address)
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("could not check registrations of %v", failed)
+	}
+	return nil
+}
+
+// registerProvider registers the service providing identity unless it is registered or registration is in progress.
+func (w *RegistryWatcher) registerProvider(chainID int64, id identity.Identity) {
+	status, err := w.registry.GetRegistrationStatus(chainID, id)
+	if err != nil {
+		log.Error().Err(err).Msgf("Could not check registration status of provider %s", id.Address)
+		return
+	}
+	if status == Registered || status == InProgress {
+		w.clearRetry(registrationKey{chainID: chainID, identity: id})
+		return
+	}
+
+	if err := w.register(chainID, id, status); err != nil {
+		log.Error().Err(err).Msgf("Could not register provider %s", id.Address)
+	}
+}
+
+// register registers the identity for free with its own beneficiary and referral token, if there is one.
+// Failed attempts are retried with exponential backoff of the identity.
+func (w *RegistryWatcher) register(chainID int64, id identity.Identity, previous RegistrationStatus) error {
+	key := registrationKey{chainID: chainID, identity: id}
+	retry := w.retries[key]
+	if w.timeGetter().Before(retry.next) {
+		log.Debug().Msgf("Registration of identity %s is retried after %s", id.Address, retry.next)
+		return nil
+	}
+
+	if w.chainCheck != nil {
+		if err := w.chainCheck.Verify(chainID); err != nil {
+			return fmt.Errorf("automatic registration of %s refused: %w", id.Address, err)
+		}
+	}
+
+	token, err := w.referralToken(id)
+	if err != nil {
+		w.registrationFailed(key, err)
+		return nil
+	}
+
+	// Registration with referral token is covered by the referrer, so eligibility and fee are not checked.
+	if token == nil {
+		ok, err := w.canRegisterForFree(id.Address)
+		if err != nil {
+			w.registrationFailed(key, err)
+			return nil
+		}
+		if !ok {
+			return nil
+		}
+
+		fee, err := w.EstimateRegistrationFee(chainID)
+		if err != nil {
+			w.registrationFailed(key, err)
+			return nil
+		}
+		if !fee.Allowed {
+			w.registrationFailed(key, fmt.Errorf("%w: fee %s, maximum %s", ErrRegistrationFeeTooHigh, fee.Fee, fee.MaxFee))
+			return nil
+		}
+	}
+
+	beneficiary := w.beneficiary(id)
+	if config.GetBool(config.FlagPaymentsRegistrationDryRun) {
+		log.Info().Msgf("Dry run: identity %s would be registered on chain %d with beneficiary %q", id.Address, chainID, beneficiary)
+		w.publisher.Publish(AppTopicRegistrationDryRun, AppEventRegistrationDryRun{
+			ID:            id,
+			ChainID:       chainID,
+			Beneficiary:   beneficiary,
+			ReferralToken: token != nil,
+		})
+		return nil
+	}
+
+	if err := w.storage.Reset(chainID, id); err != nil {
+		return fmt.Errorf("could not reset registration status of %s: %w", id.Address, err)
+	}
+	w.publisher.Publish(AppTopicIdentityRegistration, AppEventIdentityRegistration{
+		ID:       id,
+		Status:   Unregistered,
+		ChainID:  chainID,
+		Previous: previous,
+	})
+	if err := w.transactor.RegisterProviderIdentity(id.Address, big.NewInt(0), big.NewInt(0), beneficiary, chainID, token); err != nil {
+		if errors.Is(err, ErrReferralTokenRejected) {
+			log.Warn().Err(err).Msgf("Referral token of identity %s was rejected on chain %d", id.Address, chainID)
+			w.publisher.Publish(AppTopicReferralTokenRejected, AppEventReferralTokenRejected{
+				ID:      id,
+				ChainID: chainID,
+				Error:   err.Error(),
+			})
+		}
+		w.registrationFailed(key, err)
+		return nil
+	}
+
+	w.clearRetry(key)
+	return nil
+}
+
+// ReferralToken returns the referral token set for the identity, empty if there is none.
+func (w *RegistryWatcher) ReferralToken(id identity.Identity) (string, error) {
+	return w.storage.GetReferralToken(id)
+}
+
+// SetReferralToken sets the referral token used to register the identity automatically, empty token removes it.
+func (w *RegistryWatcher) SetReferralToken(id identity.Identity, token string) error {
+	return w.storage.SetReferralToken(id, token)
+}
+
+// referralToken returns the referral token of the identity, falling back to the configured one, nil if there is none.
+func (w *RegistryWatcher) referralToken(id identity.Identity) (*string, error) {
+	token, err := w.storage.GetReferralToken(id)
+	if err != nil {
+		return nil, fmt.Errorf("could not get referral token: %w", err)
+	}
+	if token == "" {
+		token = config.GetString(config.FlagPaymentsRegistrationReferralToken)
+	}
+	if token == "" {
+		return nil, nil
+	}
+	return &token, nil
+}
+
+// EstimateRegistrationFee fetches the current registration fee of the chain
+// and compares it against the maximum fee configured for automatic registration.
+func (w *RegistryWatcher) EstimateRegistrationFee(chainID int64) (RegistrationFee, error) {
+	fees, err := w.transactor.FetchRegistrationFees(chainID)
+	if err != nil {
+		return RegistrationFee{}, fmt.Errorf("failed to fetch registration fee: %w", err)
+	}
+
+	fee := RegistrationFee{
+		ChainID:    chainID,
+		Fee:        fees.Fee,
+		ValidUntil: fees.ValidUntil,
+		MaxFee:     config.GetBigInt(config.FlagPaymentsRegistrationMaxFee),
+		Allowed:    true,
+	}
+	if fee.MaxFee != nil && fee.Fee != nil && fee.Fee.Cmp(fee.MaxFee) > 0 {
+		fee.Allowed = false
+	}
+	return fee, nil
+}
+
+func (w *RegistryWatcher) registrationFailed(key registrationKey, err error) {
+	retry := w.retries[key]
+	backoff := registrationRetryBackoff << retry.attempts
+	if backoff <= 0 || backoff > maxRegistrationRetryBackoff {
+		backoff = maxRegistrationRetryBackoff
+	}
+
+	retry.attempts++
+	retry.lastError = err.Error()
+	retry.next = w.timeGetter().Add(backoff)
+	w.retries[key] = retry
+
+	if err := w.storage.StoreRetry(StoredRegistrationRetry{
+		Identity:    key.identity,
+		ChainID:     key.chainID,
+		Attempts:    retry.attempts,
+		LastError:   retry.lastError,
+		NextAttempt: retry.next,
+	}); err != nil {
+		log.Warn().Err(err).Msgf("Could not persist registration retry of identity %s", key.identity.Address)
+	}
+
+	log.Error().Err(err).Msgf("Could not register identity %s on chain %d, attempt %d, retrying in %s", key.identity.Address, key.chainID, retry.attempts, backoff)
+}
+
+func (w *RegistryWatcher) clearRetry(key registrationKey) {
+	if _, ok := w.retries[key]; !ok {
+		return
+	}
+
+	delete(w.retries, key)
+	if err := w.storage.DeleteRetry(key.chainID, key.identity); err != nil {
+		log.Warn().Err(err).Msgf("Could not delete registration retry of identity %s", key.identity.Address)
+	}
+}
+
+// restoreRetries loads registration retries persisted before the node restart and replays the due ones.
+func (w *RegistryWatcher) restoreRetries() {
+	retries, err := w.storage.GetRetries()
+	if err != nil {
+		log.Error().Err(err).Msg("Could not restore registration retries")
+		return
+	}
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	for _, r := range retries {
+		key := registrationKey{chainID: r.ChainID, identity: r.Identity}
+		if _, ok := w.retries[key]; ok {
+			continue
+		}
+		w.retries[key] = registrationRetry{
+			attempts:  r.Attempts,
+			lastError: r.LastError,
+			next:      r.NextAttempt,
+		}
+	}
+	w.replayRetries()
+}
+
+// replayRetries re-runs the due registration retries. Identities which got registered meanwhile are dropped.
+func (w *RegistryWatcher) replayRetries() {
+	now := w.timeGetter()
+	for key, retry := range w.retries {
+		if now.Before(retry.next) {
+			continue
+		}
+
+		status, err := w.registry.GetChainRegistrationStatus(key.chainID, key.identity)
+		if err != nil {
+			log.Error().Err(err).Msgf("Could not check registration status of %s on chain %d", key.identity.Address, key.chainID)
+			continue
+		}
+		if status == Registered || status == InProgress {
+			w.clearRetry(key)
+			continue
+		}
+
+		if err := w.register(key.chainID, key.identity, status); err != nil {
+			log.Error().Err(err).Msgf("Could not retry registration of identity %s", key.identity.Address)
+		}
+	}
+}
+
+// beneficiary returns beneficiary chosen for the identity, empty if there is none and the default one is used.
+func (w *RegistryWatcher) beneficiary(id identity.Identity) string {
+	if address := w.overridesOf(id).Beneficiary; address != "" {
+		return address
+	}
+	if w.beneficiaries == nil {
+		return ""
+	}
+
+	address, err := w.beneficiaries.Address(id.Address)
+	if err != nil {
+		if !errors.Is(err, beneficiary.ErrNotFound) {
+			log.Warn().Err(err).Msgf("Could not get beneficiary of identity %s", id.Address)
+		}
+		return ""
+	}
+	return address
+}
+
+// overridesOf returns overrides of the identity, zero value if there are none.
+func (w *RegistryWatcher) overridesOf(id identity.Identity) overrides.Overrides {
+	if w.identityOverrides == nil {
+		return overrides.Overrides{}
+	}
+
+	o, err := w.identityOverrides.Get(id)
+	if err != nil {
+		if !errors.Is(err, overrides.ErrNotFound) {
+			log.Warn().Err(err).Msgf("Could not get overrides of identity %s", id.Address)
+		}
+		return overrides.Overrides{}
+	}
+	return o
+}
+
+func (w *RegistryWatcher) canRegisterForFree(id string) (bool, error) {
+	if !w.freeRegistration {
+		log.Warn().Msgf("Identity %s has to be registered again", id)
+		return false, nil
+	}
+
+	eligible, err := w.transactor.GetFreeProviderRegistrationEligibility()
+	if err != nil {
+		return false, fmt.Errorf("failed to check free registration eligibility: %w", err)
+	}
+	if !eligible {
+		log.Warn().Msgf("Identity %s is not eligible for free re-registration", id)
+	}
+	return eligible, nil
+}
@@ -0,0 +1,687 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package registry
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/config"
+	"github.com/mysteriumnetwork/node/core/beneficiary"
+	"github.com/mysteriumnetwork/node/core/service/servicestate"
+	"github.com/mysteriumnetwork/node/core/storage/boltdb"
+	"github.com/mysteriumnetwork/node/eventbus"
+	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/identity/overrides"
+)
+
+func TestRegistryWatcher_Check(t *testing.T) {
+	var chainID int64 = 137
+	bolt, err := boltdb.NewStorage(t.TempDir())
+	assert.NoError(t, err)
+	defer bolt.Close()
+	storage := NewRegistrationStatusStorage(bolt)
+
+	id := identity.FromAddress("0x001")
+	assert.NoError(t, storage.Store(StoredRegistrationStatus{Identity: id, RegistrationStatus: Registered, ChainID: chainID}))
+
+	addresses := &mockRegistryAddressProvider{address: common.HexToAddress("0x1")}
+	tr := &mockRegistrationTransactor{eligible: true}
+	watcher := NewRegistryWatcher([]int64{chainID}, addresses, storage, &FakeRegistry{RegistrationStatus: Unregistered}, tr, nil, eventbus.New(), nil, true, 0)
+
+	// first check only remembers the registry
+	watcher.Check()
+	assert.Empty(t, tr.registered)
+	stored, err := storage.GetRegistryAddress(chainID)
+	assert.NoError(t, err)
+	assert.Equal(t, addresses.address, stored)
+
+	watcher.Check()
+	assert.Empty(t, tr.registered)
+
+	addresses.address = common.HexToAddress("0x2")
+	watcher.Check()
+	assert.Equal(t, []string{id.Address}, tr.registered)
+
+	status, err := storage.Get(chainID, id)
+	assert.NoError(t, err)
+	assert.Equal(t, Unregistered, status.RegistrationStatus)
+	stored, err = storage.GetRegistryAddress(chainID)
+	assert.NoError(t, err)
+	assert.Equal(t, addresses.address, stored)
+}
+
+func TestRegistryWatcher_Check_AppliesMigrationOnce(t *testing.T) {
+	var chainID int64 = 137
+	bolt, err := boltdb.NewStorage(t.TempDir())
+	assert.NoError(t, err)
+	defer bolt.Close()
+	storage := NewRegistrationStatusStorage(bolt)
+
+	id := identity.FromAddress("0x001")
+	assert.NoError(t, storage.Store(StoredRegistrationStatus{Identity: id, RegistrationStatus: Registered, ChainID: chainID}))
+
+	addresses := &mockRegistryAddressProvider{address: common.HexToAddress("0x2")}
+	tr := &mockRegistrationTransactor{eligible: true}
+	migrations := []Migration{{ChainID: chainID, From: common.HexToAddress("0x1"), To: common.HexToAddress("0x2")}}
+	watcher := NewRegistryWatcher([]int64{chainID}, addresses, storage, &FakeRegistry{RegistrationStatus: Unregistered}, tr, nil, eventbus.New(), migrations, true, 0)
+
+	// previous registry was never seen, yet the declared migration is applied
+	watcher.Check()
+	assert.Equal(t, []string{id.Address}, tr.registered)
+
+	assert.NoError(t, storage.Store(StoredRegistrationStatus{Identity: id, RegistrationStatus: Registered, ChainID: chainID}))
+	watcher.Check()
+	assert.Equal(t, []string{id.Address}, tr.registered)
+}
+
+func TestRegistryWatcher_Check_SkipsUnrelatedMigrations(t *testing.T) {
+	var chainID int64 = 137
+	bolt, err := boltdb.NewStorage(t.TempDir())
+	assert.NoError(t, err)
+	defer bolt.Close()
+	storage := NewRegistrationStatusStorage(bolt)
+
+	id := identity.FromAddress("0x001")
+	assert.NoError(t, storage.Store(StoredRegistrationStatus{Identity: id, RegistrationStatus: Registered, ChainID: chainID}))
+
+	addresses := &mockRegistryAddressProvider{address: common.HexToAddress("0x2")}
+	tr := &mockRegistrationTransactor{eligible: true}
+	migrations := []Migration{{ChainID: chainID, From: common.HexToAddress("0x1"), To: common.HexToAddress("0x3")}}
+	watcher := NewRegistryWatcher([]int64{chainID}, addresses, storage, &FakeRegistry{RegistrationStatus: Unregistered}, tr, nil, eventbus.New(), migrations, true, 0)
+
+	// migration into another registry, the current one is only remembered
+	watcher.Check()
+	assert.Empty(t, tr.registered)
+
+	// chain was seen using the current registry, not the one migrated from
+	watcher.handleMigrationsChange([]string{"137:0x0000000000000000000000000000000000000001:0x0000000000000000000000000000000000000002"})
+	assert.Empty(t, tr.registered)
+	assert.Len(t, watcher.migrations, 1)
+
+	watcher.handleMigrationsChange([]string{"137:0x1:0x2"})
+	assert.Len(t, watcher.migrations, 1, "invalid migration table is ignored")
+	assert.Equal(t, common.HexToAddress("0x2"), watcher.migrations[0].To)
+}
+
+func TestRegistryWatcher_Check_KeepsStatusWhenNotReregistering(t *testing.T) {
+	var chainID int64 = 137
+
+	tests := []struct {
+		name             string
+		chainStatus      RegistrationStatus
+		freeRegistration bool
+	}{
+		{"registered in the new registry", Registered, true},
+		{"free registration disabled", Unregistered, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bolt, err := boltdb.NewStorage(t.TempDir())
+			assert.NoError(t, err)
+			defer bolt.Close()
+			storage := NewRegistrationStatusStorage(bolt)
+
+			id := identity.FromAddress("0x001")
+			assert.NoError(t, storage.Store(StoredRegistrationStatus{Identity: id, RegistrationStatus: Registered, ChainID: chainID}))
+
+			addresses := &mockRegistryAddressProvider{address: common.HexToAddress("0x1")}
+			tr := &mockRegistrationTransactor{eligible: true}
+			watcher := NewRegistryWatcher([]int64{chainID}, addresses, storage, &FakeRegistry{RegistrationStatus: tt.chainStatus}, tr, nil, eventbus.New(), nil, tt.freeRegistration, 0)
+			watcher.Check()
+
+			addresses.address = common.HexToAddress("0x2")
+			watcher.Check()
+			assert.Empty(t, tr.registered)
+
+			status, err := storage.Get(chainID, id)
+			assert.NoError(t, err)
+			assert.Equal(t, Registered, status.RegistrationStatus)
+		})
+	}
+}
+
+func TestRegistryWatcher_Check_RetriesFailedChainQuery(t *testing.T) {
+	var chainID int64 = 137
+	bolt, err := boltdb.NewStorage(t.TempDir())
+	assert.NoError(t, err)
+	defer bolt.Close()
+	storage := NewRegistrationStatusStorage(bolt)
+
+	id := identity.FromAddress("0x001")
+	assert.NoError(t, storage.Store(StoredRegistrationStatus{Identity: id, RegistrationStatus: Registered, ChainID: chainID}))
+
+	addresses := &mockRegistryAddressProvider{address: common.HexToAddress("0x1")}
+	tr := &mockRegistrationTransactor{eligible: true}
+	reg := &FakeRegistry{RegistrationStatus: Unregistered}
+	watcher := NewRegistryWatcher([]int64{chainID}, addresses, storage, reg, tr, nil, eventbus.New(), nil, true, 0)
+	watcher.Check()
+
+	addresses.address = common.HexToAddress("0x2")
+	reg.RegistrationCheckError = errors.New("rpc unavailable")
+	watcher.Check()
+	assert.Empty(t, tr.registered)
+	stored, err := storage.GetRegistryAddress(chainID)
+	assert.NoError(t, err)
+	assert.Equal(t, common.HexToAddress("0x1"), stored)
+
+	reg.RegistrationCheckError = nil
+	watcher.Check()
+	assert.Equal(t, []string{id.Address}, tr.registered)
+}
+
+func TestRegistryWatcher_Recheck(t *testing.T) {
+	var chainID int64 = 137
+	bolt, err := boltdb.NewStorage(t.TempDir())
+	assert.NoError(t, err)
+	defer bolt.Close()
+	storage := NewRegistrationStatusStorage(bolt)
+
+	id := identity.FromAddress("0x001")
+	assert.NoError(t, storage.Store(StoredRegistrationStatus{Identity: id, RegistrationStatus: Registered, ChainID: chainID}))
+
+	bus := eventbus.New()
+	var lapsed []AppEventRegistrationLapsed
+	assert.NoError(t, bus.Subscribe(AppTopicRegistrationLapsed, func(e AppEventRegistrationLapsed) {
+		lapsed = append(lapsed, e)
+	}))
+	var registrations []AppEventIdentityRegistration
+	assert.NoError(t, bus.Subscribe(AppTopicIdentityRegistration, func(e AppEventIdentityRegistration) {
+		registrations = append(registrations, e)
+	}))
+
+	addresses := &mockRegistryAddressProvider{address: common.HexToAddress("0x1")}
+	tr := &mockRegistrationTransactor{eligible: true}
+	reg := &FakeRegistry{RegistrationStatus: Registered}
+	watcher := NewRegistryWatcher([]int64{chainID}, addresses, storage, reg, tr, nil, bus, nil, true, time.Hour)
+
+	watcher.Recheck()
+	assert.Empty(t, tr.registered)
+	assert.Empty(t, lapsed)
+
+	reg.RegistrationStatus = Unregistered
+	watcher.Recheck()
+	assert.Equal(t, []string{id.Address}, tr.registered)
+	assert.Equal(t, []AppEventRegistrationLapsed{{ID: id, ChainID: chainID, Status: Unregistered}}, lapsed)
+	assert.Equal(t, []AppEventIdentityRegistration{{ID: id, ChainID: chainID, Status: Unregistered, Previous: Registered}}, registrations)
+
+	status, err := storage.Get(chainID, id)
+	assert.NoError(t, err)
+	assert.Equal(t, Unregistered, status.RegistrationStatus)
+}
+
+func TestRegistryWatcher_Recheck_BacksOffFailedIdentityOnly(t *testing.T) {
+	var chainID int64 = 137
+	bolt, err := boltdb.NewStorage(t.TempDir())
+	assert.NoError(t, err)
+	defer bolt.Close()
+	storage := NewRegistrationStatusStorage(bolt)
+
+	failing := identity.FromAddress("0x001")
+	healthy := identity.FromAddress("0x002")
+	storeRegistered := func() {
+		for _, id := range []identity.Identity{failing, healthy} {
+			assert.NoError(t, storage.Store(StoredRegistrationStatus{Identity: id, RegistrationStatus: Registered, ChainID: chainID}))
+		}
+	}
+	storeRegistered()
+
+	tr := &mockRegistrationTransactor{
+		eligible: true,
+		errs:     map[string]error{failing.Address: errors.New("transactor unavailable")},
+	}
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	watcher := NewRegistryWatcher([]int64{chainID}, &mockRegistryAddressProvider{}, storage, &FakeRegistry{RegistrationStatus: Unregistered}, tr, nil, eventbus.New(), nil, true, time.Hour)
+	watcher.timeGetter = func() time.Time { return now }
+
+	watcher.Recheck()
+	assert.ElementsMatch(t, []string{failing.Address, healthy.Address}, tr.registered)
+
+	// failed identity waits for its backoff, the healthy one is re-registered right away
+	storeRegistered()
+	tr.registered = nil
+	watcher.Recheck()
+	assert.Equal(t, []string{healthy.Address}, tr.registered)
+
+	registrations, err := watcher.Registrations()
+	assert.NoError(t, err)
+	assert.Len(t, registrations, 2)
+	assert.Equal(t, failing, registrations[0].Identity)
+	assert.Equal(t, 1, registrations[0].Attempts)
+	assert.Equal(t, "transactor unavailable", registrations[0].LastError)
+	assert.Equal(t, now.Add(registrationRetryBackoff), registrations[0].NextAttempt)
+	assert.Equal(t, healthy, registrations[1].Identity)
+	assert.Zero(t, registrations[1].Attempts)
+
+	now = now.Add(registrationRetryBackoff)
+	storeRegistered()
+	tr.registered = nil
+	watcher.Recheck()
+	assert.ElementsMatch(t, []string{failing.Address, healthy.Address}, tr.registered)
+
+	registrations, err = watcher.Registrations()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, registrations[0].Attempts)
+	assert.Equal(t, now.Add(2*registrationRetryBackoff), registrations[0].NextAttempt)
+}
+
+func TestRegistryWatcher_Recheck_SkipsRegistrationAboveMaxFee(t *testing.T) {
+	var chainID int64 = 137
+	config.Current.SetUser(config.FlagPaymentsRegistrationMaxFee.Name, "100")
+	defer config.Current.RemoveUser(config.FlagPaymentsRegistrationMaxFee.Name)
+
+	bolt, err := boltdb.NewStorage(t.TempDir())
+	assert.NoError(t, err)
+	defer bolt.Close()
+	storage := NewRegistrationStatusStorage(bolt)
+
+	id := identity.FromAddress("0x001")
+	assert.NoError(t, storage.Store(StoredRegistrationStatus{Identity: id, RegistrationStatus: Registered, ChainID: chainID}))
+
+	tr := &mockRegistrationTransactor{eligible: true, fee: big.NewInt(101)}
+	watcher := NewRegistryWatcher([]int64{chainID}, &mockRegistryAddressProvider{}, storage, &FakeRegistry{RegistrationStatus: Unregistered}, tr, nil, eventbus.New(), nil, true, time.Hour)
+
+	fee, err := watcher.EstimateRegistrationFee(chainID)
+	assert.NoError(t, err)
+	assert.Equal(t, RegistrationFee{ChainID: chainID, Fee: big.NewInt(101), MaxFee: big.NewInt(100), Allowed: false}, fee)
+
+	watcher.Recheck()
+	assert.Empty(t, tr.registered)
+
+	registrations, err := watcher.Registrations()
+	assert.NoError(t, err)
+	assert.Len(t, registrations, 1)
+	assert.Equal(t, 1, registrations[0].Attempts)
+	assert.Contains(t, registrations[0].LastError, ErrRegistrationFeeTooHigh.Error())
+
+	tr.fee = big.NewInt(100)
+	fee, err = watcher.EstimateRegistrationFee(chainID)
+	assert.NoError(t, err)
+	assert.True(t, fee.Allowed)
+}
+
+func TestRegistryWatcher_RegistersRunningProviders(t *testing.T) {
+	var chainID int64 = 137
+	config.Current.SetUser(config.FlagChainID.Name, chainID)
+	defer config.Current.RemoveUser(config.FlagChainID.Name)
+
+	bolt, err := boltdb.NewStorage(t.TempDir())
+	assert.NoError(t, err)
+	defer bolt.Close()
+	storage := NewRegistrationStatusStorage(bolt)
+
+	first := identity.FromAddress("0x001")
+	second := identity.FromAddress("0x002")
+	beneficiaries := &mockBeneficiaryResolver{addresses: map[string]string{first.Address: "0xbeneficiary"}}
+
+	tr := &mockRegistrationTransactor{eligible: true}
+	reg := &FakeRegistry{RegistrationStatus: Unregistered}
+	watcher := NewRegistryWatcher([]int64{chainID}, &mockRegistryAddressProvider{}, storage, reg, tr, beneficiaries, eventbus.New(), nil, true, time.Hour)
+
+	watcher.handleServiceEvent(servicestate.AppEventServiceStatus{ID: "wg", ProviderID: first.Address, Status: string(servicestate.Running)})
+	watcher.handleServiceEvent(servicestate.AppEventServiceStatus{ID: "scraping", ProviderID: first.Address, Status: string(servicestate.Running)})
+	watcher.handleServiceEvent(servicestate.AppEventServiceStatus{ID: "dvpn", ProviderID: second.Address, Status: string(servicestate.Running)})
+	assert.Equal(t, []string{first.Address, first.Address, second.Address}, tr.registered)
+	assert.Equal(t, []string{"0xbeneficiary", "0xbeneficiary", ""}, tr.beneficiaries)
+
+	registrations, err := watcher.Registrations()
+	assert.NoError(t, err)
+	assert.Len(t, registrations, 2)
+	assert.Equal(t, first, registrations[0].Identity)
+	assert.Equal(t, Unregistered, registrations[0].Status)
+	assert.Equal(t, 2, registrations[0].Services)
+	assert.Equal(t, "0xbeneficiary", registrations[0].Beneficiary)
+	assert.Equal(t, second, registrations[1].Identity)
+	assert.Equal(t, 1, registrations[1].Services)
+
+	watcher.handleServiceEvent(servicestate.AppEventServiceStatus{ID: "dvpn", ProviderID: second.Address, Status: string(servicestate.NotRunning)})
+	tr.registered = nil
+	watcher.Recheck()
+	assert.Equal(t, []string{first.Address}, tr.registered)
+}
+
+func TestRegistryWatcher_RegistersProvidersOnAllChains(t *testing.T) {
+	config.Current.SetUser(config.FlagChainID.Name, int64(137))
+	defer config.Current.RemoveUser(config.FlagChainID.Name)
+	config.Current.SetUser(config.FlagPaymentsRegistrationAllChains.Name, true)
+	defer config.Current.RemoveUser(config.FlagPaymentsRegistrationAllChains.Name)
+
+	bolt, err := boltdb.NewStorage(t.TempDir())
+	assert.NoError(t, err)
+	defer bolt.Close()
+	storage := NewRegistrationStatusStorage(bolt)
+
+	id := identity.FromAddress("0x001")
+	tr := &mockRegistrationTransactor{eligible: true, chainErrs: map[int64]error{1: errors.New("transactor unavailable")}}
+	reg := &FakeRegistry{RegistrationStatus: Unregistered}
+	watcher := NewRegistryWatcher([]int64{1, 137}, &mockRegistryAddressProvider{}, storage, reg, tr, nil, eventbus.New(), nil, true, time.Hour)
+
+	watcher.handleServiceEvent(servicestate.AppEventServiceStatus{ID: "wg", ProviderID: id.Address, Status: string(servicestate.Running)})
+	assert.Equal(t, []int64{1, 137}, tr.chains)
+
+	registrations, err := watcher.Registrations()
+	assert.NoError(t, err)
+	assert.Len(t, registrations, 2)
+	assert.Equal(t, int64(1), registrations[0].ChainID)
+	assert.Equal(t, 1, registrations[0].Attempts)
+	assert.Equal(t, "transactor unavailable", registrations[0].LastError)
+	assert.Equal(t, int64(137), registrations[1].ChainID)
+	assert.Equal(t, 0, registrations[1].Attempts)
+
+	// failed chain backs off without holding back the other one
+	tr.chains = nil
+	watcher.Recheck()
+	assert.Equal(t, []int64{137}, tr.chains)
+}
+
+func TestRegistryWatcher_RestoresRetriesAfterRestart(t *testing.T) {
+	var chainID int64 = 137
+	bolt, err := boltdb.NewStorage(t.TempDir())
+	assert.NoError(t, err)
+	defer bolt.Close()
+	storage := NewRegistrationStatusStorage(bolt)
+
+	failing := identity.FromAddress("0x001")
+	registered := identity.FromAddress("0x002")
+	for _, id := range []identity.Identity{failing, registered} {
+		assert.NoError(t, storage.Store(StoredRegistrationStatus{Identity: id, RegistrationStatus: Registered, ChainID: chainID}))
+	}
+
+	tr := &mockRegistrationTransactor{
+		eligible: true,
+		errs: map[string]error{
+			failing.Address:    errors.New("transactor unavailable"),
+			registered.Address: errors.New("transactor unavailable"),
+		},
+	}
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	watcher := NewRegistryWatcher([]int64{chainID}, &mockRegistryAddressProvider{}, storage, &FakeRegistry{RegistrationStatus: Unregistered}, tr, nil, eventbus.New(), nil, true, time.Hour)
+	watcher.timeGetter = func() time.Time { return now }
+	watcher.Recheck()
+	assert.ElementsMatch(t, []string{failing.Address, registered.Address}, tr.registered)
+
+	retries, err := storage.GetRetries()
+	assert.NoError(t, err)
+	assert.Len(t, retries, 2)
+
+	// node restarts before the backoff passes, pending retries wait for it
+	reg := &FakeRegistry{RegistrationStatus: Unregistered}
+	tr = &mockRegistrationTransactor{eligible: true}
+	watcher = NewRegistryWatcher([]int64{chainID}, &mockRegistryAddressProvider{}, storage, reg, tr, nil, eventbus.New(), nil, true, time.Hour)
+	watcher.timeGetter = func() time.Time { return now }
+	watcher.restoreRetries()
+	assert.Empty(t, tr.registered)
+
+	registrations, err := watcher.Registrations()
+	assert.NoError(t, err)
+	assert.Len(t, registrations, 2)
+	assert.Equal(t, 1, registrations[0].Attempts)
+	assert.Equal(t, now.Add(registrationRetryBackoff), registrations[0].NextAttempt)
+
+	// identity registered meanwhile is dropped instead of being registered again
+	now = now.Add(registrationRetryBackoff)
+	reg.RegistrationStatus = Registered
+	watcher = NewRegistryWatcher([]int64{chainID}, &mockRegistryAddressProvider{}, storage, reg, tr, nil, eventbus.New(), nil, true, time.Hour)
+	watcher.timeGetter = func() time.Time { return now }
+	assert.NoError(t, storage.StoreRetry(StoredRegistrationRetry{Identity: failing, ChainID: chainID, Attempts: 1, NextAttempt: now.Add(time.Hour)}))
+	watcher.restoreRetries()
+	assert.Empty(t, tr.registered)
+
+	retries, err = storage.GetRetries()
+	assert.NoError(t, err)
+	assert.Equal(t, []StoredRegistrationRetry{{Identity: failing, ChainID: chainID, Attempts: 1, NextAttempt: now.Add(time.Hour)}}, retries)
+
+	// due retry is replayed and removed once it succeeds
+	reg.RegistrationStatus = Unregistered
+	now = now.Add(time.Hour)
+	watcher.Recheck()
+	assert.Equal(t, []string{failing.Address}, tr.registered)
+
+	retries, err = storage.GetRetries()
+	assert.NoError(t, err)
+	assert.Empty(t, retries)
+}
+
+func TestRegistryWatcher_RegistersWithReferralToken(t *testing.T) {
+	var chainID int64 = 137
+	config.Current.SetUser(config.FlagPaymentsRegistrationReferralToken.Name, "configured")
+	defer config.Current.RemoveUser(config.FlagPaymentsRegistrationReferralToken.Name)
+
+	bolt, err := boltdb.NewStorage(t.TempDir())
+	assert.NoError(t, err)
+	defer bolt.Close()
+	storage := NewRegistrationStatusStorage(bolt)
+
+	own := identity.FromAddress("0x001")
+	other := identity.FromAddress("0x002")
+	for _, id := range []identity.Identity{own, other} {
+		assert.NoError(t, storage.Store(StoredRegistrationStatus{Identity: id, RegistrationStatus: Registered, ChainID: chainID}))
+	}
+
+	bus := eventbus.New()
+	var rejected []AppEventReferralTokenRejected
+	assert.NoError(t, bus.Subscribe(AppTopicReferralTokenRejected, func(e AppEventReferralTokenRejected) {
+		rejected = append(rejected, e)
+	}))
+
+	// not eligible for free registration, referral tokens cover it
+	rejection := fmt.Errorf("%w: token expired", ErrReferralTokenRejected)
+	tr := &mockRegistrationTransactor{errs: map[string]error{other.Address: rejection}}
+	watcher := NewRegistryWatcher([]int64{chainID}, &mockRegistryAddressProvider{}, storage, &FakeRegistry{RegistrationStatus: Unregistered}, tr, nil, bus, nil, true, time.Hour)
+	assert.NoError(t, watcher.SetReferralToken(own, "own"))
+
+	token, err := watcher.ReferralToken(own)
+	assert.NoError(t, err)
+	assert.Equal(t, "own", token)
+
+	watcher.Recheck()
+	assert.Equal(t, []string{own.Address, other.Address}, tr.registered)
+	assert.Equal(t, []string{"own", "configured"}, tr.tokens)
+	assert.Equal(t, []AppEventReferralTokenRejected{{ID: other, ChainID: chainID, Error: rejection.Error()}}, rejected)
+
+	registrations, err := watcher.Registrations()
+	assert.NoError(t, err)
+	assert.Zero(t, registrations[0].Attempts)
+	assert.Equal(t, 1, registrations[1].Attempts)
+	assert.Equal(t, rejection.Error(), registrations[1].LastError)
+
+	assert.NoError(t, watcher.SetReferralToken(own, ""))
+	token, err = watcher.ReferralToken(own)
+	assert.NoError(t, err)
+	assert.Empty(t, token)
+}
+
+func TestRegistryWatcher_DryRun(t *testing.T) {
+	var chainID int64 = 137
+	config.Current.SetUser(config.FlagPaymentsRegistrationDryRun.Name, true)
+	defer config.Current.RemoveUser(config.FlagPaymentsRegistrationDryRun.Name)
+
+	bolt, err := boltdb.NewStorage(t.TempDir())
+	assert.NoError(t, err)
+	defer bolt.Close()
+	storage := NewRegistrationStatusStorage(bolt)
+
+	id := identity.FromAddress("0x001")
+	assert.NoError(t, storage.Store(StoredRegistrationStatus{Identity: id, RegistrationStatus: Registered, ChainID: chainID}))
+
+	bus := eventbus.New()
+	var dryRuns []AppEventRegistrationDryRun
+	assert.NoError(t, bus.Subscribe(AppTopicRegistrationDryRun, func(e AppEventRegistrationDryRun) {
+		dryRuns = append(dryRuns, e)
+	}))
+
+	beneficiaries := &mockBeneficiaryResolver{addresses: map[string]string{id.Address: "0xbeneficiary"}}
+	tr := &mockRegistrationTransactor{eligible: true}
+	watcher := NewRegistryWatcher([]int64{chainID}, &mockRegistryAddressProvider{}, storage, &FakeRegistry{RegistrationStatus: Unregistered}, tr, beneficiaries, bus, nil, true, time.Hour)
+
+	watcher.Recheck()
+	assert.Empty(t, tr.registered)
+	assert.Equal(t, []AppEventRegistrationDryRun{{ID: id, ChainID: chainID, Beneficiary: "0xbeneficiary"}}, dryRuns)
+
+	status, err := storage.Get(chainID, id)
+	assert.NoError(t, err)
+	assert.Equal(t, Registered, status.RegistrationStatus)
+
+	// checks still apply in dry run
+	tr.eligible = false
+	dryRuns = nil
+	watcher.Recheck()
+	assert.Empty(t, dryRuns)
+}
+
+type mockRegistryAddressProvider struct {
+	address common.Address
+}
+
+func (m *mockRegistryAddressProvider) GetRegistryAddress(chainID int64) (common.Address, error) {
+	return m.address, nil
+}
+
+type mockRegistrationTransactor struct {
+	eligible      bool
+	errs          map[string]error
+	chainErrs     map[int64]error
+	fee           *big.Int
+	registered    []string
+	chains        []int64
+	beneficiaries []string
+	tokens        []string
+}
+
+func (m *mockRegistrationTransactor) FetchRegistrationStatus(id string) ([]TransactorStatusResponse, error) {
+	return nil, nil
+}
+
+func (m *mockRegistrationTransactor) GetFreeProviderRegistrationEligibility() (bool, error) {
+	return m.eligible, nil
+}
+
+func (m *mockRegistrationTransactor) FetchRegistrationFees(chainID int64) (FeesResponse, error) {
+	fee := m.fee
+	if fee == nil {
+		fee = big.NewInt(0)
+	}
+	return FeesResponse{Fee: fee}, nil
+}
+
+func (m *mockRegistrationTransactor) RegisterProviderIdentity(id string, stake, fee *big.Int, beneficiary string, chainID int64, referralToken *string) error {
+	m.registered = append(m.registered, id)
+	m.chains = append(m.chains, chainID)
+	m.beneficiaries = append(m.beneficiaries, beneficiary)
+	if referralToken != nil {
+		m.tokens = append(m.tokens, *referralToken)
+	}
+	if err, ok := m.chainErrs[chainID]; ok {
+		return err
+	}
+	return m.errs[id]
+}
+
+type mockBeneficiaryResolver struct {
+	addresses map[string]string
+}
+
+func (m *mockBeneficiaryResolver) Address(id string) (string, error) {
+	address, ok := m.addresses[id]
+	if !ok {
+		return "", beneficiary.ErrNotFound
+	}
+	return address, nil
+}
+
+type mockChainVerifier struct {
+	errs    map[int64]error
+	checked []int64
+}
+
+func (m *mockChainVerifier) Verify(chainID int64) error {
+	m.checked = append(m.checked, chainID)
+	return m.errs[chainID]
+}
+
+func TestRegistryWatcher_RefusesRegistrationOnMisconfiguredChain(t *testing.T) {
+	config.Current.SetUser(config.FlagPaymentsRegistrationAllChains.Name, true)
+	defer config.Current.RemoveUser(config.FlagPaymentsRegistrationAllChains.Name)
+
+	bolt, err := boltdb.NewStorage(t.TempDir())
+	assert.NoError(t, err)
+	defer bolt.Close()
+	storage := NewRegistrationStatusStorage(bolt)
+
+	tr := &mockRegistrationTransactor{eligible: true}
+	watcher := NewRegistryWatcher([]int64{1, 137}, &mockRegistryAddressProvider{}, storage, &FakeRegistry{RegistrationStatus: Unregistered}, tr, nil, eventbus.New(), nil, true, time.Hour)
+	chains := &mockChainVerifier{errs: map[int64]error{1: fmt.Errorf("%w: chain 1", ErrChainMisconfigured)}}
+	watcher.SetChainChecker(chains)
+
+	watcher.checkChains()
+	assert.Equal(t, []int64{1, 137}, chains.checked)
+
+	id := identity.FromAddress("0x001")
+	watcher.handleServiceEvent(servicestate.AppEventServiceStatus{ID: "wg", ProviderID: id.Address, Status: string(servicestate.Running)})
+	assert.Equal(t, []string{id.Address}, tr.registered)
+	assert.Equal(t, []int64{137}, tr.chains)
+}
+
+func TestRegistryWatcher_RegistersProvidersWithOverrides(t *testing.T) {
+	config.Current.SetUser(config.FlagChainID.Name, int64(137))
+	defer config.Current.RemoveUser(config.FlagChainID.Name)
+
+	bolt, err := boltdb.NewStorage(t.TempDir())
+	assert.NoError(t, err)
+	defer bolt.Close()
+	storage := NewRegistrationStatusStorage(bolt)
+
+	overridden := identity.FromAddress("0x001")
+	misconfigured := identity.FromAddress("0x002")
+	tr := &mockRegistrationTransactor{eligible: true}
+	beneficiaries := &mockBeneficiaryResolver{addresses: map[string]string{
+		overridden.Address:    "0xsaved",
+		misconfigured.Address: "0xsaved",
+	}}
+	watcher := NewRegistryWatcher([]int64{1, 137}, &mockRegistryAddressProvider{}, storage, &FakeRegistry{RegistrationStatus: Unregistered}, tr, beneficiaries, eventbus.New(), nil, true, time.Hour)
+	watcher.SetOverrides(&mockIdentityOverrides{overrides: map[identity.Identity]overrides.Overrides{
+		overridden:    {Beneficiary: "0xoverride", ChainID: 1},
+		misconfigured: {ChainID: 5},
+	}})
+
+	watcher.handleServiceEvent(servicestate.AppEventServiceStatus{ID: "wg", ProviderID: overridden.Address, Status: string(servicestate.Running)})
+	watcher.handleServiceEvent(servicestate.AppEventServiceStatus{ID: "wg", ProviderID: misconfigured.Address, Status: string(servicestate.Running)})
+
+	assert.Equal(t, []string{overridden.Address, misconfigured.Address}, tr.registered)
+	assert.Equal(t, []int64{1, 137}, tr.chains)
+	assert.Equal(t, []string{"0xoverride", "0xsaved"}, tr.beneficiaries)
+}
+
+type mockIdentityOverrides struct {
+	overrides map[identity.Identity]overrides.Overrides
+}
+
+func (m *mockIdentityOverrides) Get(id identity.Identity) (overrides.Overrides, error) {
+	o, ok := m.overrides[id]
+	if !ok {
+		return overrides.Overrides{}, overrides.ErrNotFound
+	}
+	return o, nil
+}
@@ -84,8 +84,58 @@ func (srs StoredRegistrationStatus) FromEvent(status RegistrationStatus, ev Iden
 const AppTopicIdentityRegistration = "registration_event_topic"
 
 // AppEventIdentityRegistration represents the registration event payload.
+// It is published whenever registration status of identity changes, so consumers do not have to poll for it.
 type AppEventIdentityRegistration struct {
 	ID      identity.Identity
 	Status  RegistrationStatus
 	ChainID int64
+	// Previous is the registration status known before the change.
+	Previous RegistrationStatus
+}
+
+// AppTopicRegistrationLapsed represents the topic of lapsed provider registrations.
+const AppTopicRegistrationLapsed = "registration_lapsed"
+
+// AppEventRegistrationLapsed is published when identity known as registered is no longer registered on chain.
+type AppEventRegistrationLapsed struct {
+	ID      identity.Identity
+	ChainID int64
+	// Status is the registration status found on chain.
+	Status RegistrationStatus
+}
+
+// AppTopicReferralTokenRejected represents the topic of referral tokens rejected during automatic registration.
+const AppTopicReferralTokenRejected = "registration_referral_token_rejected"
+
+// AppEventReferralTokenRejected is published when transactor rejects the referral token of identity being registered.
+type AppEventReferralTokenRejected struct {
+	ID      identity.Identity
+	ChainID int64
+	Error   string
+}
+
+// AppTopicRegistrationDryRun represents the topic of registrations skipped in dry-run mode.
+const AppTopicRegistrationDryRun = "registration_dry_run"
+
+// AppEventRegistrationDryRun is published when identity passed all registration checks,
+// but was not registered because automatic registration runs in dry-run mode.
+type AppEventRegistrationDryRun struct {
+	ID      identity.Identity
+	ChainID int64
+	// Beneficiary is the beneficiary identity would be registered with, empty for the default one.
+	Beneficiary string
+	// ReferralToken is true if identity would be registered with a referral token.
+	ReferralToken bool
+}
+
+// AppTopicRegistrationBeneficiaryRejected represents the topic of registrations rejected because of their beneficiary.
+const AppTopicRegistrationBeneficiaryRejected = "registration_beneficiary_rejected"
+
+// AppEventRegistrationBeneficiaryRejected is published when registration is not submitted,
+// because its beneficiary would not be able to receive the earnings.
+type AppEventRegistrationBeneficiaryRejected struct {
+	ID          identity.Identity
+	ChainID     int64
+	Beneficiary string
+	Error       string
 }
@@ -22,16 +22,23 @@ import (
 	"sync"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/mysteriumnetwork/node/identity"
 	"github.com/pkg/errors"
 )
 
-const registrationStatusBucket = "registry_statuses"
+const (
+	registrationStatusBucket = "registry_statuses"
+	registryAddressBucket    = "registry_addresses"
+	registrationRetryBucket  = "registration_retries"
+	referralTokenBucket      = "registration_referral_tokens"
+)
 
 type persistentStorage interface {
 	Store(bucket string, data interface{}) error
 	GetOneByField(bucket string, fieldName string, key interface{}, to interface{}) error
 	GetAllFrom(bucket string, data interface{}) error
+	Delete(bucket string, data interface{}) error
 }
 
 var errBoltNotFound = "not found"
@@ -131,6 +138,146 @@ func (rss *RegistrationStatusStorage) GetAll() ([]StoredRegistrationStatus, erro
 	return result, nil
 }
 
+// Reset marks the identity as unregistered on the given chain, overriding any previous status.
+func (rss *RegistrationStatusStorage) Reset(chainID int64, identity identity.Identity) error {
+	rss.lock.Lock()
+	defer rss.lock.Unlock()
+
+	return rss.store(StoredRegistrationStatus{
+		Identity:           identity,
+		RegistrationStatus: Unregistered,
+		ChainID:            chainID,
+	})
+}
+
+type storedRegistryAddress struct {
+	ChainID int64 `storm:"id"`
+	Address string
+}
+
+// GetRegistryAddress returns the registry address last seen on the given chain.
+func (rss *RegistrationStatusStorage) GetRegistryAddress(chainID int64) (common.Address, error) {
+	rss.lock.Lock()
+	defer rss.lock.Unlock()
+
+	result := &storedRegistryAddress{}
+	err := rss.bolt.GetOneByField(registryAddressBucket, "ChainID", chainID, result)
+	if err != nil {
+		if err.Error() == errBoltNotFound {
+			return common.Address{}, ErrNotFound
+		}
+		return common.Address{}, errors.Wrap(err, "could not get registry address")
+	}
+	return common.HexToAddress(result.Address), nil
+}
+
+// StoreRegistryAddress stores the registry address seen on the given chain.
+func (rss *RegistrationStatusStorage) StoreRegistryAddress(chainID int64, address common.Address) error {
+	rss.lock.Lock()
+	defer rss.lock.Unlock()
+
+	err := rss.bolt.Store(registryAddressBucket, &storedRegistryAddress{ChainID: chainID, Address: address.Hex()})
+	return errors.Wrap(err, "could not store registry address")
+}
+
+// StoredRegistrationRetry represents a pending registration retry of the identity on the given chain.
+type StoredRegistrationRetry struct {
+	Identity    identity.Identity
+	ChainID     int64
+	Attempts    int
+	LastError   string
+	NextAttempt time.Time
+}
+
+type storedRegistrationRetry struct {
+	ID string `storm:"id"`
+	StoredRegistrationRetry
+}
+
+// StoreRetry stores the pending registration retry, overriding the previous one of the identity.
+func (rss *RegistrationStatusStorage) StoreRetry(retry StoredRegistrationRetry) error {
+	rss.lock.Lock()
+	defer rss.lock.Unlock()
+
+	store := &storedRegistrationRetry{
+		ID:                      rss.makeKey(retry.Identity, retry.ChainID),
+		StoredRegistrationRetry: retry,
+	}
+	err := rss.bolt.Store(registrationRetryBucket, store)
+	return errors.Wrap(err, "could not store registration retry")
+}
+
+// GetRetries fetches all the pending registration retries.
+func (rss *RegistrationStatusStorage) GetRetries() ([]StoredRegistrationRetry, error) {
+	rss.lock.Lock()
+	defer rss.lock.Unlock()
+
+	list := []storedRegistrationRetry{}
+	err := rss.bolt.GetAllFrom(registrationRetryBucket, &list)
+	if err != nil {
+		if err.Error() == errBoltNotFound {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "could not get registration retries")
+	}
+
+	result := make([]StoredRegistrationRetry, len(list))
+	for i, l := range list {
+		result[i] = l.StoredRegistrationRetry
+	}
+	return result, nil
+}
+
+// DeleteRetry removes the pending registration retry of the identity on the given chain.
+func (rss *RegistrationStatusStorage) DeleteRetry(chainID int64, identity identity.Identity) error {
+	rss.lock.Lock()
+	defer rss.lock.Unlock()
+
+	err := rss.bolt.Delete(registrationRetryBucket, &storedRegistrationRetry{ID: rss.makeKey(identity, chainID)})
+	if err != nil && err.Error() == errBoltNotFound {
+		return nil
+	}
+	return errors.Wrap(err, "could not delete registration retry")
+}
+
+type storedReferralToken struct {
+	Identity string `storm:"id"`
+	Token    string
+}
+
+// GetReferralToken returns the referral token set for the identity, empty if there is none.
+func (rss *RegistrationStatusStorage) GetReferralToken(identity identity.Identity) (string, error) {
+	rss.lock.Lock()
+	defer rss.lock.Unlock()
+
+	result := &storedReferralToken{}
+	err := rss.bolt.GetOneByField(referralTokenBucket, "Identity", identity.Address, result)
+	if err != nil {
+		if err.Error() == errBoltNotFound {
+			return "", nil
+		}
+		return "", errors.Wrap(err, "could not get referral token")
+	}
+	return result.Token, nil
+}
+
+// SetReferralToken sets the referral token used to register the identity, empty token removes it.
+func (rss *RegistrationStatusStorage) SetReferralToken(identity identity.Identity, token string) error {
+	rss.lock.Lock()
+	defer rss.lock.Unlock()
+
+	if token == "" {
+		err := rss.bolt.Delete(referralTokenBucket, &storedReferralToken{Identity: identity.Address})
+		if err != nil && err.Error() == errBoltNotFound {
+			return nil
+		}
+		return errors.Wrap(err, "could not delete referral token")
+	}
+
+	err := rss.bolt.Store(referralTokenBucket, &storedReferralToken{Identity: identity.Address, Token: token})
+	return errors.Wrap(err, "could not store referral token")
+}
+
 func (rss *RegistrationStatusStorage) makeKey(identity identity.Identity, chainID int64) string {
 	return fmt.Sprintf("%s|%d", identity.Address, chainID)
 }
@@ -20,6 +20,7 @@ package registry
 import (
 	"os"
 	"testing"
+	"time"
 
 	"github.com/mysteriumnetwork/node/core/storage/boltdb"
 	"github.com/mysteriumnetwork/node/identity"
@@ -135,6 +136,62 @@ func TestRegistrationStatusStorage(t *testing.T) {
 	assert.Equal(t, RegistrationError, res.RegistrationStatus)
 }
 
+func TestRegistrationStatusStorage_Retries(t *testing.T) {
+	bolt, err := boltdb.NewStorage(t.TempDir())
+	assert.NoError(t, err)
+	defer bolt.Close()
+	storage := NewRegistrationStatusStorage(bolt)
+
+	retries, err := storage.GetRetries()
+	assert.NoError(t, err)
+	assert.Empty(t, retries)
+	assert.NoError(t, storage.DeleteRetry(1, identity.FromAddress("0x001")))
+
+	next := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	retry := StoredRegistrationRetry{Identity: identity.FromAddress("0x001"), ChainID: 1, Attempts: 1, LastError: "failed", NextAttempt: next}
+	assert.NoError(t, storage.StoreRetry(retry))
+	assert.NoError(t, storage.StoreRetry(StoredRegistrationRetry{Identity: retry.Identity, ChainID: 2, Attempts: 1}))
+
+	retry.Attempts = 2
+	assert.NoError(t, storage.StoreRetry(retry))
+
+	retries, err = storage.GetRetries()
+	assert.NoError(t, err)
+	assert.Len(t, retries, 2)
+	assert.Equal(t, 2, retries[0].Attempts)
+	assert.Equal(t, "failed", retries[0].LastError)
+	assert.True(t, next.Equal(retries[0].NextAttempt))
+
+	assert.NoError(t, storage.DeleteRetry(1, retry.Identity))
+	retries, err = storage.GetRetries()
+	assert.NoError(t, err)
+	assert.Len(t, retries, 1)
+	assert.Equal(t, int64(2), retries[0].ChainID)
+}
+
+func TestRegistrationStatusStorage_ReferralTokens(t *testing.T) {
+	bolt, err := boltdb.NewStorage(t.TempDir())
+	assert.NoError(t, err)
+	defer bolt.Close()
+	storage := NewRegistrationStatusStorage(bolt)
+
+	id := identity.FromAddress("0x001")
+	token, err := storage.GetReferralToken(id)
+	assert.NoError(t, err)
+	assert.Empty(t, token)
+	assert.NoError(t, storage.SetReferralToken(id, ""))
+
+	assert.NoError(t, storage.SetReferralToken(id, "token"))
+	token, err = storage.GetReferralToken(id)
+	assert.NoError(t, err)
+	assert.Equal(t, "token", token)
+
+	assert.NoError(t, storage.SetReferralToken(id, ""))
+	token, err = storage.GetReferralToken(id)
+	assert.NoError(t, err)
+	assert.Empty(t, token)
+}
+
 func statusesEqual(t *testing.T, a, b StoredRegistrationStatus) {
 	assert.Equal(t, a.RegistrationStatus, b.RegistrationStatus)
 	assert.Equal(t, a.Identity.Address, b.Identity.Address)
@@ -25,6 +25,7 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/mysteriumnetwork/go-rest/apierror"
 	"github.com/mysteriumnetwork/payments/client"
 	pc "github.com/mysteriumnetwork/payments/crypto"
 	"github.com/mysteriumnetwork/payments/registration"
@@ -39,9 +40,13 @@ import (
 // AppTopicTransactorRegistration represents the registration topic to which events regarding registration attempts on transactor will occur
 const AppTopicTransactorRegistration = "transactor_identity_registration"
 
+// ErrReferralTokenRejected is returned when transactor refuses to register identity with the given referral token.
+var ErrReferralTokenRejected = errors.New("referral token rejected")
+
 type channelProvider interface {
 	GetProviderChannel(chainID int64, hermesAddress common.Address, addressToCheck common.Address, pending bool) (client.ProviderChannel, error)
 	GetLastRegistryNonce(chainID int64, registry common.Address) (*big.Int, error)
+	GetClientByChain(chainID int64) (client.BC, error)
 }
 
 // AddressProvider provides sc addresses.
@@ -115,10 +120,18 @@ type Transactor struct {
 	bc              channelProvider
 	addresser       AddressProvider
 	feeCache        *feeCacher
+	gas             GasPriceStrategy
+	gasOverrides    *gasOverrides
+	audit           transactorAuditor
+	txSigner        txSigner
+}
+
+type transactorAuditor interface {
+	Record(endpoint string, chainID int64, id string, payload interface{}, reqErr error) error
 }
 
-// NewTransactor creates and returns new Transactor instance
-func NewTransactor(httpClient *requests.HTTPClient, endpointAddress string, addresser AddressProvider, signerFactory identity.SignerFactory, publisher eventbus.Publisher, bc channelProvider, feesValidTime time.Duration) *Transactor {
+// NewTransactor creates and returns new Transactor instance, nil gas strategy leaves gas prices to transactor
+func NewTransactor(httpClient *requests.HTTPClient, endpointAddress string, addresser AddressProvider, signerFactory identity.SignerFactory, publisher eventbus.Publisher, bc channelProvider, feesValidTime time.Duration, gas GasPriceStrategy) *Transactor {
 	return &Transactor{
 		httpClient:      httpClient,
 		endpointAddress: endpointAddress,
@@ -127,9 +140,16 @@ func NewTransactor(httpClient *requests.HTTPClient, endpointAddress string, addr
 		publisher:       publisher,
 		bc:              bc,
 		feeCache:        newFeeCacher(feesValidTime),
+		gas:             gas,
+		gasOverrides:    newGasOverrides(),
 	}
 }
 
+// SetAuditLog sets the log every transaction request sent to transactor is recorded to.
+func (t *Transactor) SetAuditLog(audit transactorAuditor) {
+	t.audit = audit
+}
+
 // FeesResponse represents fees applied by Transactor
 type FeesResponse struct {
 	Fee        *big.Int  `json:"fee"`
@@ -271,12 +291,12 @@ func (t *Transactor) SettleAndRebalance(hermesID, providerID string, promise pc.
 		ChainID:       promise.ChainID,
 	}
 
-	req, err := requests.NewPostRequest(t.endpointAddress, "identity/settle_and_rebalance", payload)
+	req, err := t.newTxRequest("identity/settle_and_rebalance", promise.ChainID, providerID, payload)
 	if err != nil {
 		return "", errors.Wrap(err, "failed to create settle and rebalance request")
 	}
 	res := SettleResponse{}
-	return res.ID, t.httpClient.DoRequestAndParseResponse(req, &res)
+	return res.ID, t.doTxRequest(req, &res)
 }
 
 func (t *Transactor) registerIdentity(endpoint string, id string, stake, fee *big.Int, beneficiary string, chainID int64) error {
@@ -290,12 +310,17 @@ func (t *Transactor) registerIdentity(endpoint string, id string, stake, fee *bi
 		return errors.Wrap(err, "identity request validation failed")
 	}
 
-	req, err := requests.NewPostRequest(t.endpointAddress, endpoint, regReq)
+	err = t.checkBeneficiary(regReq)
+	if err != nil {
+		return err
+	}
+
+	req, err := t.newTxRequest(endpoint, chainID, id, regReq)
 	if err != nil {
 		return errors.Wrap(err, "failed to create RegisterIdentity request")
 	}
 
-	err = t.httpClient.DoRequest(req)
+	err = t.doTxRequest(req, nil)
 	if err != nil {
 		return err
 	}
@@ -323,18 +348,27 @@ func (t *Transactor) registerIdentityWithReferralToken(id string, stake *big.Int
 		return errors.Wrap(err, "identity request validation failed")
 	}
 
+	err = t.checkBeneficiary(regReq)
+	if err != nil {
+		return err
+	}
+
 	r := identityRegistrationRequestWithToken{
 		IdentityRegistrationRequest: regReq,
 		Token:                       token,
 	}
 
-	req, err := requests.NewPostRequest(t.endpointAddress, "identity/register/referer", r)
+	req, err := t.newTxRequest("identity/register/referer", chainID, id, r)
 	if err != nil {
 		return errors.Wrap(err, "failed to create RegisterIdentity request")
 	}
 
-	err = t.httpClient.DoRequest(req)
+	err = t.doTxRequest(req, nil)
 	if err != nil {
+		var apiErr *apierror.APIError
+		if errors.As(err, &apiErr) && apiErr.Status >= 400 && apiErr.Status < 500 {
+			return fmt.Errorf("%w: %s", ErrReferralTokenRejected, apiErr.Detail())
+		}
 		return err
 	}
 
@@ -446,6 +480,26 @@ func (t *Transactor) validateRegisterIdentityRequest(regReq IdentityRegistration
 	return nil
 }
 
+// checkBeneficiary validates beneficiary of the registration request and notifies about the rejected ones,
+// so that a registration transaction doomed to lose the earnings is never submitted.
+func (t *Transactor) checkBeneficiary(regReq IdentityRegistrationRequest) error {
+	err := t.validateBeneficiary(regReq.ChainID, regReq.Beneficiary)
+	if err == nil {
+		return nil
+	}
+
+	log.Warn().Err(err).Msgf("Rejected registration of identity %s on chain %d", regReq.Identity, regReq.ChainID)
+	if t.publisher != nil {
+		t.publisher.Publish(AppTopicRegistrationBeneficiaryRejected, AppEventRegistrationBeneficiaryRejected{
+			ID:          identity.FromAddress(regReq.Identity),
+			ChainID:     regReq.ChainID,
+			Beneficiary: regReq.Beneficiary,
+			Error:       err.Error(),
+		})
+	}
+	return err
+}
+
 func (t *Transactor) signRegistrationRequest(signer identity.Signer, regReq IdentityRegistrationRequest) ([]byte, error) {
 	req := registration.Request{
 		RegistryAddress: strings.ToLower(regReq.RegistryAddress),
@@ -532,12 +586,12 @@ func (t *Transactor) OpenChannel(chainID int64, id, hermesID, registryAddress st
 		return fmt.Errorf("failed to create open channel request: %w", err)
 	}
 
-	req, err := requests.NewPostRequest(t.endpointAddress, endpoint, request)
+	req, err := t.newTxRequest(endpoint, chainID, id, request)
 	if err != nil {
 		return fmt.Errorf("failed to do open channel request: %w", err)
 	}
 
-	return t.httpClient.DoRequest(req)
+	return t.doTxRequest(req, nil)
 }
 
 // ChannelStatusRequest request for channel status
@@ -656,13 +710,13 @@ func (t *Transactor) SettleWithBeneficiary(id, beneficiary, hermesID string, pro
 		Registry:    registry.Hex(),
 	}
 
-	req, err := requests.NewPostRequest(t.endpointAddress, "identity/settle_with_beneficiary", payload)
+	req, err := t.newTxRequest("identity/settle_with_beneficiary", promise.ChainID, id, payload)
 	if err != nil {
 		return "", fmt.Errorf("failed to create RegisterIdentity request %w", err)
 	}
 
 	res := SettleResponse{}
-	return res.ID, t.httpClient.DoRequestAndParseResponse(req, &res)
+	return res.ID, t.doTxRequest(req, &res)
 }
 
 func (t *Transactor) fillSetBeneficiaryRequest(chainID int64, id, beneficiary, registry string) (pc.SetBeneficiaryRequest, error) {
@@ -757,12 +811,12 @@ func (t *Transactor) SettleIntoStake(hermesID, providerID string, promise pc.Pro
 		ChainID:       promise.ChainID,
 	}
 
-	req, err := requests.NewPostRequest(t.endpointAddress, "identity/settle/into_stake", payload)
+	req, err := t.newTxRequest("identity/settle/into_stake", promise.ChainID, providerID, payload)
 	if err != nil {
 		return "", errors.Wrap(err, "failed to create settle into stake request")
 	}
 	res := SettleResponse{}
-	return res.ID, t.httpClient.DoRequestAndParseResponse(req, &res)
+	return res.ID, t.doTxRequest(req, &res)
 }
 
 // EligibilityResponse shows if one is eligible for free registration.
@@ -820,12 +874,12 @@ func (t *Transactor) PayAndSettle(hermesID, providerID string, promise pc.Promis
 		BeneficiarySignature: beneficiarySignature,
 	}
 
-	req, err := requests.NewPostRequest(t.endpointAddress, "identity/pay_and_settle", payload)
+	req, err := t.newTxRequest("identity/pay_and_settle", promise.ChainID, providerID, payload)
 	if err != nil {
 		return "", errors.Wrap(err, "failed to create pay and settle request")
 	}
 	res := SettleResponse{}
-	return res.ID, t.httpClient.DoRequestAndParseResponse(req, &res)
+	return res.ID, t.doTxRequest(req, &res)
 }
 
 // DecreaseProviderStakeRequest represents all the parameters required for decreasing provider stake.
@@ -849,11 +903,11 @@ func (t *Transactor) DecreaseStake(id string, chainID int64, amount, transactorF
 
 	log.Debug().Msgf("req chid %v", payload.ChannelID)
 
-	req, err := requests.NewPostRequest(t.endpointAddress, "stake/decrease", payload)
+	req, err := t.newTxRequest("stake/decrease", chainID, id, payload)
 	if err != nil {
 		return errors.Wrap(err, "failed to create decrease stake request")
 	}
-	return t.httpClient.DoRequest(req)
+	return t.doTxRequest(req, nil)
 }
 
 func (t *Transactor) fillDecreaseStakeRequest(id string, chainID int64, amount, transactorFee *big.Int) (DecreaseProviderStakeRequest, error) {
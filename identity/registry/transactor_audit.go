@@ -0,0 +1,182 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package registry
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/asdine/storm/v3"
+
+	"github.com/mysteriumnetwork/node/core/storage/boltdb"
+)
+
+const transactorAuditBucket = "transactor-audit"
+
+const (
+	// TransactorAuditResultOK marks a request accepted by transactor.
+	TransactorAuditResultOK = "ok"
+	// TransactorAuditResultError marks a request which failed or was rejected by transactor.
+	TransactorAuditResultError = "error"
+)
+
+// ErrAuditLogTampered is returned when the hash chain of the audit log does not hold.
+var ErrAuditLogTampered = errors.New("transactor audit log has been tampered with")
+
+// TransactorAuditEntry represents a single request sent to transactor.
+// Every entry is chained to the previous one by its hash,
+// so altering or removing an entry breaks the chain from that entry on.
+type TransactorAuditEntry struct {
+	Seq         uint64 `storm:"id"`
+	Time        time.Time
+	Endpoint    string
+	Identity    string
+	ChainID     int64
+	PayloadHash string
+	Result      string
+	Error       string
+	PrevHash    string
+	Hash        string
+}
+
+func (e TransactorAuditEntry) digest() string {
+	h := sha256.New()
+	for _, field := range []string{
+		strconv.FormatUint(e.Seq, 10),
+		e.Time.UTC().Format(time.RFC3339Nano),
+		e.Endpoint,
+		e.Identity,
+		strconv.FormatInt(e.ChainID, 10),
+		e.PayloadHash,
+		e.Result,
+		e.Error,
+		e.PrevHash,
+	} {
+		h.Write([]byte(strconv.Itoa(len(field))))
+		h.Write([]byte{':'})
+		h.Write([]byte(field))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// TransactorAuditLog keeps a tamper-evident log of the requests sent to transactor.
+type TransactorAuditLog struct {
+	bolt *boltdb.Bolt
+	now  func() time.Time
+}
+
+// NewTransactorAuditLog returns a new instance of the transactor audit log.
+func NewTransactorAuditLog(bolt *boltdb.Bolt) *TransactorAuditLog {
+	return &TransactorAuditLog{
+		bolt: bolt,
+		now:  time.Now,
+	}
+}
+
+// Record appends the outcome of a transactor request to the log.
+func (tal *TransactorAuditLog) Record(endpoint string, chainID int64, id string, payload interface{}, reqErr error) error {
+	payloadHash, err := hashPayload(payload)
+	if err != nil {
+		return fmt.Errorf("could not hash transactor payload: %w", err)
+	}
+
+	entry := TransactorAuditEntry{
+		Time:        tal.now().UTC(),
+		Endpoint:    endpoint,
+		Identity:    strings.ToLower(id),
+		ChainID:     chainID,
+		PayloadHash: payloadHash,
+		Result:      TransactorAuditResultOK,
+	}
+	if reqErr != nil {
+		entry.Result = TransactorAuditResultError
+		entry.Error = reqErr.Error()
+	}
+
+	tal.bolt.Lock()
+	defer tal.bolt.Unlock()
+
+	node := tal.bolt.DB().From(transactorAuditBucket)
+	var last TransactorAuditEntry
+	err = node.Select().Reverse().First(&last)
+	if err != nil && !errors.Is(err, storm.ErrNotFound) {
+		return fmt.Errorf("could not get last transactor audit entry: %w", err)
+	}
+
+	entry.Seq = last.Seq + 1
+	entry.PrevHash = last.Hash
+	entry.Hash = entry.digest()
+	return node.Save(&entry)
+}
+
+// List returns all the entries of the log, newest first.
+func (tal *TransactorAuditLog) List() ([]TransactorAuditEntry, error) {
+	entries, err := tal.all()
+	if err != nil {
+		return nil, err
+	}
+
+	for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+		entries[i], entries[j] = entries[j], entries[i]
+	}
+	return entries, nil
+}
+
+// Verify walks the whole log and checks that the hash chain is intact.
+func (tal *TransactorAuditLog) Verify() error {
+	entries, err := tal.all()
+	if err != nil {
+		return err
+	}
+
+	var prev TransactorAuditEntry
+	for _, entry := range entries {
+		if entry.Seq != prev.Seq+1 || entry.PrevHash != prev.Hash || entry.Hash != entry.digest() {
+			return fmt.Errorf("%w: entry %d", ErrAuditLogTampered, prev.Seq+1)
+		}
+		prev = entry
+	}
+	return nil
+}
+
+func (tal *TransactorAuditLog) all() ([]TransactorAuditEntry, error) {
+	tal.bolt.RLock()
+	defer tal.bolt.RUnlock()
+
+	var entries []TransactorAuditEntry
+	err := tal.bolt.DB().From(transactorAuditBucket).All(&entries)
+	if err != nil && !errors.Is(err, storm.ErrNotFound) {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func hashPayload(payload interface{}) (string, error) {
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
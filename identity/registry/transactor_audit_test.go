@@ -0,0 +1,136 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package registry
+
+import (
+	"errors"
+	"math/big"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	pc "github.com/mysteriumnetwork/payments/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mysteriumnetwork/node/core/storage/boltdb"
+	"github.com/mysteriumnetwork/node/requests"
+	"github.com/mysteriumnetwork/node/testkit/fakeapi"
+)
+
+func newTestAuditLog(t *testing.T) *TransactorAuditLog {
+	dir, err := os.MkdirTemp("", "transactorAuditLogTest")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	bolt, err := boltdb.NewStorage(dir)
+	require.NoError(t, err)
+	t.Cleanup(func() { bolt.Close() })
+
+	return NewTransactorAuditLog(bolt)
+}
+
+func TestTransactorAuditLog_RecordsChainedEntries(t *testing.T) {
+	audit := newTestAuditLog(t)
+
+	entries, err := audit.List()
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+	assert.NoError(t, audit.Verify())
+
+	assert.NoError(t, audit.Record("identity/register", 1, "0xAB", map[string]string{"a": "b"}, nil))
+	assert.NoError(t, audit.Record("stake/decrease", 137, "0xab", map[string]string{"c": "d"}, errors.New("boom")))
+
+	entries, err = audit.List()
+	assert.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	assert.Equal(t, uint64(2), entries[0].Seq)
+	assert.Equal(t, "stake/decrease", entries[0].Endpoint)
+	assert.Equal(t, int64(137), entries[0].ChainID)
+	assert.Equal(t, TransactorAuditResultError, entries[0].Result)
+	assert.Equal(t, "boom", entries[0].Error)
+	assert.Equal(t, entries[1].Hash, entries[0].PrevHash)
+
+	assert.Equal(t, uint64(1), entries[1].Seq)
+	assert.Equal(t, "0xab", entries[1].Identity)
+	assert.Equal(t, TransactorAuditResultOK, entries[1].Result)
+	assert.Empty(t, entries[1].PrevHash)
+	assert.NotEqual(t, entries[0].PayloadHash, entries[1].PayloadHash)
+
+	assert.NoError(t, audit.Verify())
+}
+
+func TestTransactorAuditLog_VerifyDetectsTampering(t *testing.T) {
+	audit := newTestAuditLog(t)
+	for i := 0; i < 3; i++ {
+		require.NoError(t, audit.Record("identity/settle_and_rebalance", 1, "0x1", i, errors.New("rejected")))
+	}
+
+	var entry TransactorAuditEntry
+	require.NoError(t, audit.bolt.DB().From(transactorAuditBucket).One("Seq", uint64(2), &entry))
+	entry.Result = TransactorAuditResultOK
+	entry.Error = ""
+	require.NoError(t, audit.bolt.DB().From(transactorAuditBucket).Save(&entry))
+
+	err := audit.Verify()
+	assert.ErrorIs(t, err, ErrAuditLogTampered)
+	assert.Contains(t, err.Error(), "entry 2")
+
+	// removing the entry breaks the chain as well
+	require.NoError(t, audit.bolt.DB().From(transactorAuditBucket).DeleteStruct(&entry))
+	err = audit.Verify()
+	assert.ErrorIs(t, err, ErrAuditLogTampered)
+	assert.Contains(t, err.Error(), "entry 2")
+}
+
+func TestTransactor_RecordsTxRequestsToAuditLog(t *testing.T) {
+	fake := fakeapi.NewTransactor()
+	url := fake.Start()
+	defer fake.Close()
+
+	audit := newTestAuditLog(t)
+	tr := NewTransactor(requests.NewHTTPClient("0.0.0.0", time.Second), url, nil, nil, nil, nil, time.Minute, nil)
+	tr.SetAuditLog(audit)
+
+	promise := pc.Promise{ChainID: 1, Amount: big.NewInt(10), Fee: big.NewInt(1)}
+	_, err := tr.SettleIntoStake("0x2", "0x1", promise)
+	assert.NoError(t, err)
+
+	fake.On(http.MethodPost, "identity/settle/into_stake", fakeapi.TransactorError(http.StatusServiceUnavailable, "unavailable", "try again later"))
+	_, err = tr.SettleIntoStake("0x2", "0x1", promise)
+	assert.Error(t, err)
+
+	_, err = tr.FetchSettleFees(1)
+	assert.NoError(t, err)
+
+	entries, err := audit.List()
+	assert.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, TransactorAuditResultError, entries[0].Result)
+	assert.NotEmpty(t, entries[0].Error)
+	assert.Equal(t, TransactorAuditResultOK, entries[1].Result)
+	for _, entry := range entries {
+		assert.Equal(t, "identity/settle/into_stake", entry.Endpoint)
+		assert.Equal(t, "0x1", entry.Identity)
+		assert.Equal(t, int64(1), entry.ChainID)
+	}
+	assert.Equal(t, entries[0].PayloadHash, entries[1].PayloadHash)
+	assert.NoError(t, audit.Verify())
+}
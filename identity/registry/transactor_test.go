@@ -0,0 +1,63 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package registry
+
+import (
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/requests"
+	"github.com/mysteriumnetwork/node/testkit/fakeapi"
+)
+
+func TestTransactor_FetchRegistrationFees(t *testing.T) {
+	fake := fakeapi.NewTransactor()
+	url := fake.Start()
+	defer fake.Close()
+
+	fake.SetFee("register", big.NewInt(100))
+	tr := NewTransactor(requests.NewHTTPClient("0.0.0.0", time.Second), url, nil, nil, nil, nil, time.Minute, nil)
+
+	fees, err := tr.FetchRegistrationFees(80001)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(100), fees.Fee)
+	assert.True(t, fees.IsValid())
+
+	// fees are cached until they expire
+	fake.SetFee("register", big.NewInt(200))
+	fees, err = tr.FetchRegistrationFees(80001)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(100), fees.Fee)
+	assert.Len(t, fake.Requests(http.MethodGet, "fee/*/register"), 1)
+}
+
+func TestTransactor_FetchSettleFeesFailure(t *testing.T) {
+	fake := fakeapi.NewTransactor()
+	url := fake.Start()
+	defer fake.Close()
+
+	fake.On(http.MethodGet, "fee/*/settle", fakeapi.TransactorError(http.StatusServiceUnavailable, "unavailable", "try again later"))
+	tr := NewTransactor(requests.NewHTTPClient("0.0.0.0", time.Second), url, nil, nil, nil, nil, time.Minute, nil)
+
+	_, err := tr.FetchSettleFees(80001)
+	assert.Error(t, err)
+}
@@ -0,0 +1,87 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package identity
+
+import (
+	"sync"
+	"time"
+)
+
+const maxRelockInterval = time.Minute
+
+// UsageChecker reports whether the identity is in use, e.g. has active sessions or running services,
+// in which case it stays unlocked regardless of how long it was idle.
+type UsageChecker func(id Identity) bool
+
+type idleLocker interface {
+	RelockIdle(ttl time.Duration, inUse UsageChecker) []Identity
+}
+
+// Relocker periodically locks identities which were idle for longer than the unlock TTL.
+type Relocker struct {
+	locker   idleLocker
+	inUse    UsageChecker
+	ttl      time.Duration
+	interval time.Duration
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewRelocker creates a new instance of Relocker.
+func NewRelocker(locker idleLocker, ttl time.Duration, inUse UsageChecker) *Relocker {
+	interval := ttl / 4
+	if interval > maxRelockInterval {
+		interval = maxRelockInterval
+	}
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	return &Relocker{
+		locker:   locker,
+		inUse:    inUse,
+		ttl:      ttl,
+		interval: interval,
+		stop:     make(chan struct{}),
+	}
+}
+
+// Start starts checking for idle identities in the background.
+func (r *Relocker) Start() {
+	go func() {
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-r.stop:
+				return
+			case <-ticker.C:
+				r.locker.RelockIdle(r.ttl, r.inUse)
+			}
+		}
+	}()
+}
+
+// Stop stops the relocker.
+func (r *Relocker) Stop() {
+	r.stopOnce.Do(func() {
+		close(r.stop)
+	})
+}
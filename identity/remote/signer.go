@@ -0,0 +1,153 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package remote
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/requests"
+)
+
+// ErrSignatureMismatch is returned when signing service responds with a signature of another identity.
+var ErrSignatureMismatch = errors.New("remote signature does not match identity")
+
+// Config describes connection to the remote signing service.
+type Config struct {
+	URL string
+	// CertFile and KeyFile hold the client certificate presented to the signing service.
+	CertFile string
+	KeyFile  string
+	// CAFile holds the CA certificate used to verify the signing service, system roots are used when empty.
+	CAFile  string
+	Timeout time.Duration
+}
+
+// Client signs messages using remote signing service which keeps the private keys.
+type Client struct {
+	http *requests.HTTPClient
+	url  string
+}
+
+// NewClient creates a new remote signing service client authenticated with mutual TLS.
+func NewClient(cfg Config) (*Client, error) {
+	if cfg.URL == "" {
+		return nil, errors.New("remote signer URL is not set")
+	}
+
+	tlsConfig, err := newTLSConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = requests.DefaultTimeout
+	}
+
+	transport := requests.NewTransport(requests.NewDialer("").DialContext)
+	transport.TLSClientConfig = tlsConfig
+
+	return &Client{
+		http: requests.NewHTTPClientWithTransport(transport, timeout),
+		url:  strings.TrimRight(cfg.URL, "/"),
+	}, nil
+}
+
+func newTLSConfig(cfg Config) (*tls.Config, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if cfg.CertFile != "" || cfg.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("could not load remote signer client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	if cfg.CAFile != "" {
+		caPEM, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("could not read remote signer CA: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, errors.New("remote signer CA contains no certificates")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	return tlsConfig, nil
+}
+
+// SignerFactory returns a factory of signers backed by the remote signing service.
+func (c *Client) SignerFactory() identity.SignerFactory {
+	return func(id identity.Identity) identity.Signer {
+		return &signer{client: c, id: identity.FromAddress(id.Address)}
+	}
+}
+
+type signRequest struct {
+	Address string `json:"address"`
+	Hash    string `json:"hash"`
+}
+
+type signResponse struct {
+	Signature string `json:"signature"`
+}
+
+type signer struct {
+	client *Client
+	id     identity.Identity
+}
+
+// Sign asks signing service to sign the Keccak256 hash of the message and verifies the result.
+func (s *signer) Sign(message []byte) (identity.Signature, error) {
+	req, err := requests.NewPostRequest(s.client.url, "sign", signRequest{
+		Address: s.id.Address,
+		Hash:    hexutil.Encode(crypto.Keccak256(message)),
+	})
+	if err != nil {
+		return identity.Signature{}, err
+	}
+
+	var resp signResponse
+	if err := s.client.http.DoRequestAndParseResponse(req, &resp); err != nil {
+		return identity.Signature{}, fmt.Errorf("remote signer failed to sign message: %w", err)
+	}
+
+	signatureBytes, err := hexutil.Decode(resp.Signature)
+	if err != nil {
+		return identity.Signature{}, fmt.Errorf("could not decode remote signature: %w", err)
+	}
+
+	signature := identity.SignatureBytes(signatureBytes)
+	if ok, _ := identity.NewVerifierIdentity(s.id).Verify(message, signature); !ok {
+		return identity.Signature{}, ErrSignatureMismatch
+	}
+	return signature, nil
+}
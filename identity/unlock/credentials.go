@@ -0,0 +1,156 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package unlock
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+const credentialsKeyLen = 32
+
+// Credential holds the passphrase of the identity unlocked by the startup policy.
+type Credential struct {
+	Address    string `json:"address"`
+	Passphrase string `json:"passphrase"`
+}
+
+// CredentialsFile stores identity credentials encrypted with AES-GCM.
+// The encryption key is kept in a separate file, which is generated when credentials are saved for the first time.
+type CredentialsFile struct {
+	path    string
+	keyPath string
+}
+
+// NewCredentialsFile creates encrypted credentials file stored at path, encrypted with the key stored at keyPath.
+func NewCredentialsFile(path, keyPath string) *CredentialsFile {
+	return &CredentialsFile{
+		path:    path,
+		keyPath: keyPath,
+	}
+}
+
+// Load decrypts and returns the stored credentials.
+func (f *CredentialsFile) Load() ([]Credential, error) {
+	data, err := os.ReadFile(f.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNoCredentials
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read credentials file: %w", err)
+	}
+
+	key, err := os.ReadFile(f.keyPath)
+	if err != nil {
+		return nil, fmt.Errorf("could not read credentials key: %w", err)
+	}
+	gcm, err := newCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonceSize := gcm.NonceSize()
+	if len(data) < nonceSize {
+		return nil, errors.New("credentials file is corrupted")
+	}
+	plaintext, err := gcm.Open(nil, data[:nonceSize], data[nonceSize:], nil)
+	if err != nil {
+		return nil, fmt.Errorf("could not decrypt credentials file: %w", err)
+	}
+
+	var creds []Credential
+	if err := json.Unmarshal(plaintext, &creds); err != nil {
+		return nil, fmt.Errorf("could not parse credentials file: %w", err)
+	}
+	if len(creds) == 0 {
+		return nil, ErrNoCredentials
+	}
+	return creds, nil
+}
+
+// Save encrypts and stores the credentials, replacing the previously stored ones.
+func (f *CredentialsFile) Save(creds []Credential) error {
+	key, err := f.key()
+	if err != nil {
+		return err
+	}
+	gcm, err := newCipher(key)
+	if err != nil {
+		return err
+	}
+
+	plaintext, err := json.Marshal(creds)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return err
+	}
+
+	return writeFile(f.path, gcm.Seal(nonce, nonce, plaintext, nil))
+}
+
+func (f *CredentialsFile) key() ([]byte, error) {
+	key, err := os.ReadFile(f.keyPath)
+	if err == nil {
+		return key, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("could not read credentials key: %w", err)
+	}
+
+	key = make([]byte, credentialsKeyLen)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, err
+	}
+	if err := writeFile(f.keyPath, key); err != nil {
+		return nil, fmt.Errorf("could not save credentials key: %w", err)
+	}
+	return key, nil
+}
+
+func newCipher(key []byte) (cipher.AEAD, error) {
+	if len(key) != credentialsKeyLen {
+		return nil, fmt.Errorf("invalid credentials key length %d", len(key))
+	}
+	c, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(c)
+}
+
+// writeFile replaces the file contents atomically, so that a failed write does not lose the previous contents.
+func writeFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,58 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package unlock
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCredentialsFile_SaveLoad(t *testing.T) {
+	dir := t.TempDir()
+	file := NewCredentialsFile(filepath.Join(dir, "creds"), filepath.Join(dir, "creds.key"))
+
+	_, err := file.Load()
+	assert.ErrorIs(t, err, ErrNoCredentials)
+
+	creds := []Credential{{Address: "0x1", Passphrase: "secret"}}
+	require.NoError(t, file.Save(creds))
+
+	data, err := os.ReadFile(filepath.Join(dir, "creds"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "secret")
+	info, err := os.Stat(filepath.Join(dir, "creds.key"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	loaded, err := file.Load()
+	require.NoError(t, err)
+	assert.Equal(t, creds, loaded)
+}
+
+func TestCredentialsFile_LoadWithOtherKeyFails(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, NewCredentialsFile(filepath.Join(dir, "creds"), filepath.Join(dir, "creds.key")).Save([]Credential{{Address: "0x1"}}))
+	require.NoError(t, NewCredentialsFile(filepath.Join(dir, "other"), filepath.Join(dir, "other.key")).Save([]Credential{{Address: "0x2"}}))
+
+	_, err := NewCredentialsFile(filepath.Join(dir, "creds"), filepath.Join(dir, "other.key")).Load()
+	assert.Error(t, err)
+}
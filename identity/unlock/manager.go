@@ -0,0 +1,217 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package unlock
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/config"
+	"github.com/mysteriumnetwork/node/identity"
+)
+
+type identityUnlocker interface {
+	Unlock(chainID int64, address string, passphrase string) error
+	RelockIdle(ttl time.Duration, inUse identity.UsageChecker) []identity.Identity
+}
+
+type credentialStore interface {
+	Load() ([]Credential, error)
+	Save(creds []Credential) error
+}
+
+type configStore interface {
+	GetInt64(key string) int64
+	IsCLI(key string) bool
+	SetUser(key string, value interface{})
+	SaveUserConfig() error
+}
+
+// Manager applies the identity unlock policy: it unlocks identities at startup
+// and locks identities which were idle for longer than the policy allows.
+type Manager struct {
+	lock        sync.Mutex
+	policy      Policy
+	identities  identityUnlocker
+	inUse       identity.UsageChecker
+	credentials credentialStore
+	config      configStore
+
+	relocker *identity.Relocker
+
+	// pinned holds identities unlocked by the startup policy, which are never locked.
+	// It has a separate lock, as it is checked by the relocker while the identity manager is locked.
+	pinnedLock sync.Mutex
+	pinned     map[string]struct{}
+}
+
+// NewManager creates new identity unlock policy manager.
+func NewManager(policy Policy, identities identityUnlocker, inUse identity.UsageChecker, credentials credentialStore, config configStore) *Manager {
+	return &Manager{
+		policy:      policy,
+		identities:  identities,
+		inUse:       inUse,
+		credentials: credentials,
+		config:      config,
+		pinned:      make(map[string]struct{}),
+	}
+}
+
+// Start applies the configured policy.
+// Identities which fail to unlock at startup are skipped, so that the node can still be started.
+func (m *Manager) Start() error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	var err error
+	if m.policy.Mode == ModeStartup {
+		var creds []Credential
+		creds, err = m.credentials.Load()
+		if err == nil {
+			for _, cred := range creds {
+				if unlockErr := m.unlock(cred); unlockErr != nil {
+					log.Error().Err(unlockErr).Msgf("Could not unlock identity %s at startup", cred.Address)
+				}
+			}
+		}
+	}
+	m.restartRelocker()
+
+	if err != nil {
+		return fmt.Errorf("could not load identity credentials: %w", err)
+	}
+	return nil
+}
+
+// Stop stops locking idle identities.
+func (m *Manager) Stop() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.relocker != nil {
+		m.relocker.Stop()
+		m.relocker = nil
+	}
+}
+
+// Policy returns the active unlock policy.
+func (m *Manager) Policy() Policy {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return m.policy
+}
+
+// SetPolicy changes the active unlock policy and persists it to the config file.
+// Switching to the startup policy unlocks identities with the given credentials, which replace the stored ones,
+// or with the previously stored credentials if none are given.
+func (m *Manager) SetPolicy(policy Policy, creds []Credential) error {
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.config.IsCLI(config.FlagKeystoreUnlockPolicy.Name) || m.config.IsCLI(config.FlagKeystoreUnlockTTL.Name) {
+		return ErrPolicyFlagSet
+	}
+
+	if policy.Mode == ModeStartup {
+		stored := len(creds) == 0
+		if stored {
+			var err error
+			if creds, err = m.credentials.Load(); err != nil {
+				return err
+			}
+		}
+		for _, cred := range creds {
+			if err := m.identities.Unlock(m.chainID(), identity.FromAddress(cred.Address).Address, cred.Passphrase); err != nil {
+				return fmt.Errorf("%w %s: %v", ErrInvalidCredentials, cred.Address, err)
+			}
+		}
+		if !stored {
+			if err := m.credentials.Save(creds); err != nil {
+				return fmt.Errorf("could not save identity credentials: %w", err)
+			}
+		}
+	}
+
+	prev := m.policy
+	m.config.SetUser(config.FlagKeystoreUnlockPolicy.Name, string(policy.Mode))
+	m.config.SetUser(config.FlagKeystoreUnlockTTL.Name, policy.IdleTimeout.String())
+	if err := m.config.SaveUserConfig(); err != nil {
+		m.config.SetUser(config.FlagKeystoreUnlockPolicy.Name, string(prev.Mode))
+		m.config.SetUser(config.FlagKeystoreUnlockTTL.Name, prev.IdleTimeout.String())
+		return fmt.Errorf("could not save unlock policy: %w", err)
+	}
+
+	m.policy = policy
+	pinned := make(map[string]struct{})
+	if policy.Mode == ModeStartup {
+		for _, cred := range creds {
+			pinned[identity.FromAddress(cred.Address).Address] = struct{}{}
+		}
+	}
+	m.pinnedLock.Lock()
+	m.pinned = pinned
+	m.pinnedLock.Unlock()
+	m.restartRelocker()
+
+	log.Info().Msgf("Identity unlock policy changed to %s with idle timeout %s", policy.Mode, policy.IdleTimeout)
+	return nil
+}
+
+func (m *Manager) unlock(cred Credential) error {
+	id := identity.FromAddress(cred.Address)
+	if err := m.identities.Unlock(m.chainID(), id.Address, cred.Passphrase); err != nil {
+		return err
+	}
+	m.pinnedLock.Lock()
+	m.pinned[id.Address] = struct{}{}
+	m.pinnedLock.Unlock()
+	return nil
+}
+
+func (m *Manager) restartRelocker() {
+	if m.relocker != nil {
+		m.relocker.Stop()
+		m.relocker = nil
+	}
+	if m.policy.IdleTimeout <= 0 {
+		return
+	}
+
+	m.relocker = identity.NewRelocker(m.identities, m.policy.IdleTimeout, m.keepUnlocked)
+	m.relocker.Start()
+}
+
+func (m *Manager) keepUnlocked(id identity.Identity) bool {
+	m.pinnedLock.Lock()
+	_, pinned := m.pinned[id.Address]
+	m.pinnedLock.Unlock()
+
+	return pinned || m.inUse(id)
+}
+
+func (m *Manager) chainID() int64 {
+	return m.config.GetInt64(config.FlagChainID.Name)
+}
@@ -0,0 +1,261 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package unlock
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mysteriumnetwork/node/config"
+	"github.com/mysteriumnetwork/node/identity"
+)
+
+func TestManager_StartUnlocksStoredCredentials(t *testing.T) {
+	// given
+	ids := newMockIdentities(map[string]string{"0x1": "secret", "0x2": "other"})
+	creds := &mockCredentials{creds: []Credential{{Address: "0x1", Passphrase: "secret"}, {Address: "0x2", Passphrase: "wrong"}}}
+	m := NewManager(Policy{Mode: ModeStartup}, ids, notInUse, creds, newMockConfig())
+
+	// when
+	err := m.Start()
+	defer m.Stop()
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"0x1"}, ids.unlockedAddresses())
+	assert.True(t, m.keepUnlocked(identity.FromAddress("0x1")))
+	assert.False(t, m.keepUnlocked(identity.FromAddress("0x2")))
+}
+
+func TestManager_StartOnDemandDoesNotUnlock(t *testing.T) {
+	// given
+	ids := newMockIdentities(map[string]string{"0x1": "secret"})
+	creds := &mockCredentials{creds: []Credential{{Address: "0x1", Passphrase: "secret"}}}
+	m := NewManager(Policy{Mode: ModeOnDemand}, ids, notInUse, creds, newMockConfig())
+
+	// when
+	err := m.Start()
+	defer m.Stop()
+
+	// then
+	assert.NoError(t, err)
+	assert.Empty(t, ids.unlockedAddresses())
+}
+
+func TestManager_RelocksIdleIdentities(t *testing.T) {
+	// given
+	ids := newMockIdentities(nil)
+	m := NewManager(Policy{Mode: ModeOnDemand, IdleTimeout: 40 * time.Millisecond}, ids, notInUse, &mockCredentials{}, newMockConfig())
+
+	// when
+	require.NoError(t, m.Start())
+	defer m.Stop()
+
+	// then
+	assert.Eventually(t, func() bool {
+		return ids.relockTTL() == 40*time.Millisecond
+	}, 3*time.Second, 10*time.Millisecond)
+}
+
+func TestManager_SetPolicy(t *testing.T) {
+	// given
+	ids := newMockIdentities(map[string]string{"0x1": "secret"})
+	creds := &mockCredentials{}
+	cfg := newMockConfig()
+	m := NewManager(Policy{Mode: ModeOnDemand}, ids, notInUse, creds, cfg)
+	require.NoError(t, m.Start())
+	defer m.Stop()
+
+	// when
+	err := m.SetPolicy(Policy{Mode: ModeStartup, IdleTimeout: time.Minute}, []Credential{{Address: "0x1", Passphrase: "secret"}})
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, Policy{Mode: ModeStartup, IdleTimeout: time.Minute}, m.Policy())
+	assert.Equal(t, []Credential{{Address: "0x1", Passphrase: "secret"}}, creds.creds)
+	assert.Equal(t, []string{"0x1"}, ids.unlockedAddresses())
+	assert.True(t, m.keepUnlocked(identity.FromAddress("0x1")))
+	assert.Equal(t, "startup", cfg.values[config.FlagKeystoreUnlockPolicy.Name])
+	assert.Equal(t, "1m0s", cfg.values[config.FlagKeystoreUnlockTTL.Name])
+	assert.True(t, cfg.saved)
+
+	// when
+	err = m.SetPolicy(Policy{Mode: ModeOnDemand}, nil)
+
+	// then
+	assert.NoError(t, err)
+	assert.False(t, m.keepUnlocked(identity.FromAddress("0x1")))
+	assert.Equal(t, "on-demand", cfg.values[config.FlagKeystoreUnlockPolicy.Name])
+}
+
+func TestManager_SetPolicyFailures(t *testing.T) {
+	tests := map[string]struct {
+		policy  Policy
+		creds   []Credential
+		config  *mockConfig
+		wantErr error
+	}{
+		"unknown mode": {
+			policy:  Policy{Mode: "always"},
+			config:  newMockConfig(),
+			wantErr: ErrUnknownMode,
+		},
+		"set by flag": {
+			policy:  Policy{Mode: ModeStartup},
+			config:  &mockConfig{values: map[string]interface{}{}, cli: true},
+			wantErr: ErrPolicyFlagSet,
+		},
+		"no credentials": {
+			policy:  Policy{Mode: ModeStartup},
+			config:  newMockConfig(),
+			wantErr: ErrNoCredentials,
+		},
+		"wrong passphrase": {
+			policy:  Policy{Mode: ModeStartup},
+			creds:   []Credential{{Address: "0x1", Passphrase: "wrong"}},
+			config:  newMockConfig(),
+			wantErr: ErrInvalidCredentials,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			creds := &mockCredentials{}
+			m := NewManager(Policy{Mode: ModeOnDemand}, newMockIdentities(map[string]string{"0x1": "secret"}), notInUse, creds, tt.config)
+
+			err := m.SetPolicy(tt.policy, tt.creds)
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, Policy{Mode: ModeOnDemand}, m.Policy())
+			assert.Nil(t, creds.creds)
+			assert.False(t, tt.config.saved)
+		})
+	}
+}
+
+func TestManager_SetPolicyRevertsConfigOnSaveFailure(t *testing.T) {
+	// given
+	cfg := newMockConfig()
+	cfg.saveErr = errors.New("disk full")
+	m := NewManager(Policy{Mode: ModeOnDemand, IdleTimeout: time.Hour}, newMockIdentities(nil), notInUse, &mockCredentials{}, cfg)
+
+	// when
+	err := m.SetPolicy(Policy{Mode: ModeOnDemand, IdleTimeout: time.Minute}, nil)
+
+	// then
+	assert.Error(t, err)
+	assert.Equal(t, Policy{Mode: ModeOnDemand, IdleTimeout: time.Hour}, m.Policy())
+	assert.Equal(t, "1h0m0s", cfg.values[config.FlagKeystoreUnlockTTL.Name])
+}
+
+func notInUse(identity.Identity) bool {
+	return false
+}
+
+type mockIdentities struct {
+	lock        sync.Mutex
+	passphrases map[string]string
+	unlocked    []string
+	ttl         time.Duration
+}
+
+func newMockIdentities(passphrases map[string]string) *mockIdentities {
+	return &mockIdentities{passphrases: passphrases}
+}
+
+func (m *mockIdentities) Unlock(_ int64, address string, passphrase string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.passphrases[address] != passphrase {
+		return errors.New("wrong passphrase")
+	}
+	m.unlocked = append(m.unlocked, address)
+	return nil
+}
+
+func (m *mockIdentities) RelockIdle(ttl time.Duration, inUse identity.UsageChecker) []identity.Identity {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.ttl = ttl
+	return nil
+}
+
+func (m *mockIdentities) unlockedAddresses() []string {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return m.unlocked
+}
+
+func (m *mockIdentities) relockTTL() time.Duration {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return m.ttl
+}
+
+type mockCredentials struct {
+	creds []Credential
+}
+
+func (m *mockCredentials) Load() ([]Credential, error) {
+	if len(m.creds) == 0 {
+		return nil, ErrNoCredentials
+	}
+	return m.creds, nil
+}
+
+func (m *mockCredentials) Save(creds []Credential) error {
+	m.creds = creds
+	return nil
+}
+
+type mockConfig struct {
+	values  map[string]interface{}
+	cli     bool
+	saved   bool
+	saveErr error
+}
+
+func newMockConfig() *mockConfig {
+	return &mockConfig{values: map[string]interface{}{}}
+}
+
+func (m *mockConfig) GetInt64(key string) int64 {
+	v, _ := m.values[key].(int64)
+	return v
+}
+
+func (m *mockConfig) IsCLI(key string) bool {
+	return m.cli
+}
+
+func (m *mockConfig) SetUser(key string, value interface{}) {
+	m.values[key] = value
+}
+
+func (m *mockConfig) SaveUserConfig() error {
+	m.saved = m.saveErr == nil
+	return m.saveErr
+}
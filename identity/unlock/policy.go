@@ -0,0 +1,68 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package unlock
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Mode defines when identities are unlocked and for how long they stay unlocked.
+type Mode string
+
+const (
+	// ModeOnDemand keeps identities locked until they are unlocked via tequilapi
+	// and locks them again after they were idle for the idle timeout.
+	ModeOnDemand Mode = "on-demand"
+	// ModeStartup unlocks identities from the encrypted credentials file when the node starts
+	// and keeps them unlocked.
+	ModeStartup Mode = "startup"
+)
+
+var (
+	// ErrUnknownMode is returned for unlock policies of unknown mode.
+	ErrUnknownMode = errors.New("unknown unlock policy mode")
+	// ErrPolicyFlagSet is returned when the policy is set by command line flags and can not be changed.
+	ErrPolicyFlagSet = errors.New("unlock policy is set by command line flag")
+	// ErrNoCredentials is returned when the startup policy has no credentials to unlock identities with.
+	ErrNoCredentials = errors.New("no identity credentials stored")
+	// ErrInvalidCredentials is returned when identity can not be unlocked with the given credentials.
+	ErrInvalidCredentials = errors.New("could not unlock identity with the given credentials")
+)
+
+// Policy describes identity unlock lifetimes.
+type Policy struct {
+	Mode Mode
+	// IdleTimeout locks identities after they were not used for the given duration, 0 keeps them unlocked.
+	// Identities unlocked by the startup policy are never locked.
+	IdleTimeout time.Duration
+}
+
+// Validate checks whether the policy is known.
+func (p Policy) Validate() error {
+	switch p.Mode {
+	case ModeOnDemand, ModeStartup:
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownMode, p.Mode)
+	}
+	if p.IdleTimeout < 0 {
+		return fmt.Errorf("idle timeout can not be negative: %s", p.IdleTimeout)
+	}
+	return nil
+}
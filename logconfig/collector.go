@@ -75,7 +75,7 @@ func (c *Collector) logFilepaths() (result []string, err error) {
 		}
 		if f.Name() == filename+".log" {
 			result = append(result, path.Join(dir, f.Name()))
-		} else if strings.Contains(f.Name(), ".log.gz") && fInfo.Mode().IsRegular() &&
+		} else if isRotatedLog(f.Name(), filename) && fInfo.Mode().IsRegular() &&
 			(mostRecent == nil || fInfo.ModTime().After(mostRecent.ModTime())) {
 			mostRecent = fInfo
 		}
@@ -85,3 +85,10 @@ func (c *Collector) logFilepaths() (result []string, err error) {
 	}
 	return result, nil
 }
+
+func isRotatedLog(name, filename string) bool {
+	if strings.Contains(name, ".log.gz") {
+		return true
+	}
+	return strings.HasPrefix(name, filename+".") && strings.HasSuffix(name, ".log")
+}
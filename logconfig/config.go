@@ -24,6 +24,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/mysteriumnetwork/go-openvpn/openvpn"
@@ -37,6 +38,11 @@ const (
 	timestampFmt = "2006-01-02T15:04:05.000"
 )
 
+var (
+	fileWriterMux sync.Mutex
+	fileWriter    *rollingwriter.RollingWriter
+)
+
 // Bootstrap configures logger defaults (console).
 func Bootstrap() {
 	var trimPrefixes = []string{
@@ -74,21 +80,44 @@ func Configure(opts *LogOptions) {
 	log.Info().Msgf("Log level: %s", opts.LogLevel)
 	if opts.Filepath != "" {
 		log.Info().Msgf("Log file path: %s", opts.Filepath)
-		rollingWriter, err := rollingwriter.NewRollingWriter(opts.Filepath)
+		rollingWriter, err := rollingwriter.NewRollingWriterWithConfig(opts.Filepath, opts.Rotation)
 		if err != nil {
 			log.Err(err).Msg("Failed to configure file logger")
 		} else {
-			multiWriter := io.MultiWriter(consoleWriter(), zeroLogger(rollingWriter.Writer))
+			multiWriter := io.MultiWriter(consoleWriter(), zeroLogger(rollingWriter))
 			logger := makeLogger(multiWriter)
 			setGlobalLogger(&logger)
-		}
-		if err := rollingWriter.CleanObsoleteLogs(); err != nil {
-			log.Err(err).Msg("Failed to cleanup obsolete logs")
+
+			fileWriterMux.Lock()
+			fileWriter = rollingWriter
+			fileWriterMux.Unlock()
+
+			if err := rollingWriter.CleanObsoleteLogs(); err != nil {
+				log.Err(err).Msg("Failed to cleanup obsolete logs")
+			}
 		}
 	}
 	log.Logger = log.Logger.Level(opts.LogLevel)
 }
 
+// SetRotation changes log file rotation of the running node.
+func SetRotation(rotation rollingwriter.Config) error {
+	if err := rotation.Validate(); err != nil {
+		return err
+	}
+
+	fileWriterMux.Lock()
+	defer fileWriterMux.Unlock()
+
+	if fileWriter != nil {
+		if err := fileWriter.SetConfig(rotation); err != nil {
+			return err
+		}
+	}
+	CurrentLogOptions.Rotation = rotation
+	return nil
+}
+
 func consoleWriter() io.Writer {
 	return zerolog.ConsoleWriter{
 		Out:        os.Stderr,
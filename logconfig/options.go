@@ -19,6 +19,8 @@ package logconfig
 
 import (
 	"github.com/rs/zerolog"
+
+	"github.com/mysteriumnetwork/node/logconfig/rollingwriter"
 )
 
 // LogOptions describes logging options.
@@ -26,10 +28,12 @@ type LogOptions struct {
 	LogLevel zerolog.Level
 	LogHTTP  bool
 	Filepath string
+	Rotation rollingwriter.Config
 }
 
 // CurrentLogOptions stores global LogOptions.
 var CurrentLogOptions = LogOptions{
 	LogLevel: zerolog.DebugLevel,
 	LogHTTP:  false,
+	Rotation: rollingwriter.DefaultConfig,
 }
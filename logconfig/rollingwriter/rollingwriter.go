@@ -18,77 +18,334 @@
 package rollingwriter
 
 import (
+	"compress/gzip"
 	"fmt"
 	"io"
 	"os"
 	"path"
 	"sort"
 	"strings"
+	"sync"
+	"time"
 
-	"github.com/arthurkiller/rollingwriter"
 	"github.com/rs/zerolog/log"
 )
 
+const backupTimeFormat = "20060102T150405"
+
+// Config configures rotation of log files.
+type Config struct {
+	// MaxSize of the active log file in bytes before it is rotated, 0 disables size based rotation.
+	MaxSize int64
+	// MaxAge of the active log file before it is rotated, 0 disables age based rotation.
+	MaxAge time.Duration
+	// MaxBackups is the number of rotated log files to retain, 0 retains all of them.
+	MaxBackups int
+	// Compress rotated log files with gzip.
+	Compress bool
+}
+
+// Validate checks that config values are usable.
+func (c Config) Validate() error {
+	if c.MaxSize < 0 {
+		return fmt.Errorf("max size can not be negative: %d", c.MaxSize)
+	}
+	if c.MaxAge < 0 {
+		return fmt.Errorf("max age can not be negative: %s", c.MaxAge)
+	}
+	if c.MaxBackups < 0 {
+		return fmt.Errorf("max backups can not be negative: %d", c.MaxBackups)
+	}
+	return nil
+}
+
+// DefaultConfig rotates log files every 50MB and retains 5 compressed backups.
+var DefaultConfig = Config{
+	MaxSize:    50 * 1024 * 1024,
+	MaxBackups: 5,
+	Compress:   true,
+}
+
 // RollingWriter represents logs writer with logs rolling and cleanup support.
 type RollingWriter struct {
-	config rollingwriter.Config
-	Writer io.Writer
+	dir  string
+	base string
+	now  func() time.Time
+
+	mux      sync.Mutex
+	config   Config
+	file     *os.File
+	size     int64
+	openedAt time.Time
+
+	// compressMux serializes compression and cleanup of rotated files.
+	compressMux sync.Mutex
+	compressing sync.WaitGroup
+}
+
+// NewRollingWriter creates new rolling writer with the default rotation config.
+func NewRollingWriter(filepath string) (*RollingWriter, error) {
+	return NewRollingWriterWithConfig(filepath, DefaultConfig)
+}
+
+// NewRollingWriterWithConfig creates new rolling writer writing to filepath with ".log" extension.
+func NewRollingWriterWithConfig(filepath string, config Config) (*RollingWriter, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	w := &RollingWriter{
+		dir:    path.Dir(filepath),
+		base:   path.Base(filepath),
+		now:    time.Now,
+		config: config,
+	}
+	if err := os.MkdirAll(w.dir, 0700); err != nil {
+		return nil, err
+	}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Config returns the rotation config in use.
+func (w *RollingWriter) Config() Config {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	return w.config
 }
 
-// NewRollingWriter creates new rolling writer.
-func NewRollingWriter(filepath string) (writer *RollingWriter, err error) {
-	writer = &RollingWriter{}
-	writer.config = rollingwriter.Config{
-		TimeTagFormat:     "20060102T150405",
-		LogPath:           path.Dir(filepath),
-		FileName:          path.Base(filepath),
-		RollingPolicy:     rollingwriter.VolumeRolling,
-		RollingVolumeSize: "50MB",
-		Compress:          true,
-		WriterMode:        "lock",
-		MaxRemain:         5,
+// SetConfig changes rotation config, it takes effect with the next write.
+func (w *RollingWriter) SetConfig(config Config) error {
+	if err := config.Validate(); err != nil {
+		return err
 	}
-	writer.Writer, err = rollingwriter.NewWriterFromConfig(&writer.config)
-	return writer, err
+
+	w.mux.Lock()
+	w.config = config
+	w.mux.Unlock()
+
+	return w.CleanObsoleteLogs()
 }
 
-// Write writes to underlying rolling writer.
+// Write writes to the active log file rotating it if needed.
 func (w *RollingWriter) Write(b []byte) (int, error) {
-	return w.Writer.Write(b)
+	w.mux.Lock()
+	defer w.mux.Unlock()
+
+	if w.file == nil {
+		if err := w.open(); err != nil {
+			return 0, err
+		}
+	}
+	if w.shouldRotate(int64(len(b))) {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := w.file.Write(b)
+	w.size += int64(n)
+	return n, err
+}
+
+// Rotate rotates the active log file regardless of its size and age.
+func (w *RollingWriter) Rotate() error {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+
+	if w.file == nil {
+		return w.open()
+	}
+	return w.rotate()
+}
+
+// Close closes the active log file and waits for rotated files to be compressed.
+func (w *RollingWriter) Close() error {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+
+	w.compressing.Wait()
+	if w.file == nil {
+		return nil
+	}
+	err := w.file.Close()
+	w.file = nil
+	return err
+}
+
+func (w *RollingWriter) filename() string {
+	return path.Join(w.dir, w.base+".log")
+}
+
+func (w *RollingWriter) open() error {
+	file, err := os.OpenFile(w.filename(), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("could not open log file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("could not stat log file: %w", err)
+	}
+
+	w.file, w.size, w.openedAt = file, info.Size(), w.now()
+	return nil
+}
+
+func (w *RollingWriter) shouldRotate(next int64) bool {
+	if w.size == 0 {
+		return false
+	}
+	if w.config.MaxSize > 0 && w.size+next > w.config.MaxSize {
+		return true
+	}
+	return w.config.MaxAge > 0 && w.now().Sub(w.openedAt) >= w.config.MaxAge
+}
+
+func (w *RollingWriter) rotate() error {
+	if err := w.file.Close(); err != nil {
+		return fmt.Errorf("could not close log file: %w", err)
+	}
+	w.file = nil
+
+	backup := w.backupName()
+	if err := os.Rename(w.filename(), backup); err != nil {
+		return fmt.Errorf("could not rotate log file: %w", err)
+	}
+	if err := w.open(); err != nil {
+		return err
+	}
+
+	compress, maxBackups := w.config.Compress, w.config.MaxBackups
+	w.compressing.Add(1)
+	go func() {
+		defer w.compressing.Done()
+
+		w.compressMux.Lock()
+		defer w.compressMux.Unlock()
+
+		if compress {
+			if err := compressFile(backup); err != nil {
+				log.Warn().Err(err).Msg("Failed to compress rotated log file: " + backup)
+			}
+		}
+		if err := w.cleanObsoleteLogs(maxBackups); err != nil {
+			log.Warn().Err(err).Msg("Failed to cleanup obsolete logs")
+		}
+	}()
+	return nil
+}
+
+func (w *RollingWriter) backupName() string {
+	stamp := w.now().Format(backupTimeFormat)
+	name := path.Join(w.dir, fmt.Sprintf("%s.%s.log", w.base, stamp))
+	for i := 1; exists(name) || exists(name+".gz"); i++ {
+		name = path.Join(w.dir, fmt.Sprintf("%s.%s-%d.log", w.base, stamp, i))
+	}
+	return name
 }
 
-// CleanObsoleteLogs cleans obsolete logs so that the count of remaining log files is equal to w.config.MaxRemain.
-// rollingWriter only handles file rolling at runtime, but if the node is restarted, the count is lost thus we have
-// to do this manually.
+// CleanObsoleteLogs cleans obsolete logs so that the count of remaining log files is equal to MaxBackups.
 func (w *RollingWriter) CleanObsoleteLogs() error {
-	files, err := os.ReadDir(w.config.LogPath)
+	maxBackups := w.Config().MaxBackups
+
+	w.compressMux.Lock()
+	defer w.compressMux.Unlock()
+
+	return w.cleanObsoleteLogs(maxBackups)
+}
+
+func (w *RollingWriter) cleanObsoleteLogs(maxBackups int) error {
+	if maxBackups == 0 {
+		return nil
+	}
+
+	files, err := os.ReadDir(w.dir)
 	if err != nil {
 		return err
 	}
 	var oldLogFiles []os.FileInfo
-	baseFilename := w.config.FileName + ".log"
 	for _, file := range files {
-		if strings.Contains(file.Name(), baseFilename) && file.Name() != baseFilename {
-			fInfo, err := file.Info()
-			if err != nil {
-				return fmt.Errorf("failed to get file info: %w", err)
-			}
-			oldLogFiles = append(oldLogFiles, fInfo)
+		if !w.isBackup(file.Name()) {
+			continue
+		}
+		fInfo, err := file.Info()
+		if err != nil {
+			return fmt.Errorf("failed to get file info: %w", err)
 		}
+		oldLogFiles = append(oldLogFiles, fInfo)
 	}
-	if len(oldLogFiles) <= w.config.MaxRemain {
-		log.Debug().Msgf("Found %d old log files in log directory, skipping cleanup", len(oldLogFiles))
+	if len(oldLogFiles) <= maxBackups {
 		return nil
 	}
 	log.Debug().Msgf("Found %d old log files in log directory, proceeding to cleanup", len(oldLogFiles))
 	sort.Slice(oldLogFiles, func(i, j int) bool {
 		return oldLogFiles[i].ModTime().After(oldLogFiles[j].ModTime())
 	})
-	for i := w.config.MaxRemain; i < len(oldLogFiles); i++ {
-		fp := path.Join(w.config.LogPath, oldLogFiles[i].Name())
+	for i := maxBackups; i < len(oldLogFiles); i++ {
+		fp := path.Join(w.dir, oldLogFiles[i].Name())
 		if err := os.Remove(fp); err != nil {
 			log.Warn().Err(err).Msg("Failed to remove log file: " + fp)
 		}
 	}
 	return nil
 }
+
+// isBackup returns true for rotated log files, including the ones named by older node versions.
+func (w *RollingWriter) isBackup(name string) bool {
+	active := w.base + ".log"
+	if name == active || !strings.HasPrefix(name, w.base+".") {
+		return false
+	}
+	return strings.HasPrefix(name, active+".") || strings.HasSuffix(name, ".log") || strings.HasSuffix(name, ".log.gz")
+}
+
+func compressFile(name string) error {
+	src, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	info, err := src.Stat()
+	if err != nil {
+		return err
+	}
+
+	tmp := name + ".gz.tmp"
+	dst, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(dst)
+	if _, err := io.Copy(gz, src); err != nil {
+		dst.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		dst.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	if err := os.Chtimes(tmp, info.ModTime(), info.ModTime()); err != nil {
+		log.Debug().Err(err).Msg("Could not preserve modification time of compressed log")
+	}
+	if err := os.Rename(tmp, name+".gz"); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Remove(name)
+}
+
+func exists(name string) bool {
+	_, err := os.Stat(name)
+	return err == nil
+}
@@ -0,0 +1,165 @@
+/*
+ * Copyright (C) 2019 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package rollingwriter
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func listBackups(t *testing.T, w *RollingWriter) []string {
+	files, err := os.ReadDir(w.dir)
+	require.NoError(t, err)
+
+	var names []string
+	for _, f := range files {
+		if w.isBackup(f.Name()) {
+			names = append(names, f.Name())
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestRollingWriter_RotatesBySize(t *testing.T) {
+	dir := t.TempDir()
+	w, err := NewRollingWriterWithConfig(path.Join(dir, "node"), Config{MaxSize: 10})
+	require.NoError(t, err)
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	w.now = func() time.Time { return now }
+
+	_, err = w.Write([]byte("12345678"))
+	require.NoError(t, err)
+	_, err = w.Write([]byte("abcd"))
+	require.NoError(t, err)
+	_, err = w.Write([]byte("efgh"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	active, err := os.ReadFile(path.Join(dir, "node.log"))
+	require.NoError(t, err)
+	assert.Equal(t, "abcdefgh", string(active))
+	assert.Equal(t, []string{"node.20240101T120000.log"}, listBackups(t, w))
+
+	rotated, err := os.ReadFile(path.Join(dir, "node.20240101T120000.log"))
+	require.NoError(t, err)
+	assert.Equal(t, "12345678", string(rotated))
+}
+
+func TestRollingWriter_RotatesByAge(t *testing.T) {
+	dir := t.TempDir()
+	w, err := NewRollingWriterWithConfig(path.Join(dir, "node"), Config{MaxAge: time.Hour})
+	require.NoError(t, err)
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	w.now = func() time.Time { return now }
+	w.openedAt = now
+
+	_, err = w.Write([]byte("first"))
+	require.NoError(t, err)
+	now = now.Add(30 * time.Minute)
+	_, err = w.Write([]byte("second"))
+	require.NoError(t, err)
+	assert.Empty(t, listBackups(t, w))
+
+	now = now.Add(30 * time.Minute)
+	_, err = w.Write([]byte("third"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	assert.Equal(t, []string{"node.20240101T130000.log"}, listBackups(t, w))
+}
+
+func TestRollingWriter_CompressesAndRetainsBackups(t *testing.T) {
+	dir := t.TempDir()
+	w, err := NewRollingWriterWithConfig(path.Join(dir, "node"), Config{MaxBackups: 2, Compress: true})
+	require.NoError(t, err)
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	w.now = func() time.Time { return now }
+
+	for i := 0; i < 4; i++ {
+		_, err = w.Write([]byte("line " + string(rune('a'+i))))
+		require.NoError(t, err)
+		now = now.Add(time.Minute)
+		require.NoError(t, w.Rotate())
+		w.compressing.Wait()
+		// cleanup orders backups by modification time
+		backup := path.Join(dir, "node."+now.Format(backupTimeFormat)+".log.gz")
+		require.NoError(t, os.Chtimes(backup, now, now))
+	}
+	require.NoError(t, w.Close())
+
+	assert.Equal(t, []string{"node.20240101T120300.log.gz", "node.20240101T120400.log.gz"}, listBackups(t, w))
+
+	f, err := os.Open(path.Join(dir, "node.20240101T120400.log.gz"))
+	require.NoError(t, err)
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	require.NoError(t, err)
+	content, err := io.ReadAll(gz)
+	require.NoError(t, err)
+	assert.Equal(t, "line d", string(content))
+}
+
+func TestRollingWriter_SetConfigAppliesRetention(t *testing.T) {
+	dir := t.TempDir()
+	for i, name := range []string{"node.log.20220101T000000.gz", "node.20230101T000000.log", "node.20230102T000000.log.gz"} {
+		fp := path.Join(dir, name)
+		require.NoError(t, os.WriteFile(fp, []byte(name), 0600))
+		at := time.Date(2024, 1, 1+i, 0, 0, 0, 0, time.UTC)
+		require.NoError(t, os.Chtimes(fp, at, at))
+	}
+	require.NoError(t, os.WriteFile(path.Join(dir, "node.zip"), nil, 0600))
+
+	w, err := NewRollingWriterWithConfig(path.Join(dir, "node"), Config{})
+	require.NoError(t, err)
+	defer w.Close()
+	require.NoError(t, w.CleanObsoleteLogs())
+	assert.Len(t, listBackups(t, w), 3)
+
+	require.NoError(t, w.SetConfig(Config{MaxBackups: 1}))
+	assert.Equal(t, []string{"node.20230102T000000.log.gz"}, listBackups(t, w))
+	assert.FileExists(t, path.Join(dir, "node.zip"))
+	assert.Equal(t, Config{MaxBackups: 1}, w.Config())
+
+	assert.Error(t, w.SetConfig(Config{MaxSize: -1}))
+}
+
+func TestRollingWriter_BackupNameCollision(t *testing.T) {
+	dir := t.TempDir()
+	w, err := NewRollingWriterWithConfig(path.Join(dir, "node"), Config{})
+	require.NoError(t, err)
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	w.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		_, err = w.Write([]byte("x"))
+		require.NoError(t, err)
+		require.NoError(t, w.Rotate())
+	}
+	require.NoError(t, w.Close())
+
+	assert.Equal(t, []string{"node.20240101T120000-1.log", "node.20240101T120000.log"}, listBackups(t, w))
+}
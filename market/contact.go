@@ -19,6 +19,10 @@ package market
 
 import "encoding/json"
 
+// ContactTypeExternal is a contact type of proposals describing endpoints imported from outside of the network.
+// Such proposals are never announced and are connected to directly.
+const ContactTypeExternal = "external"
+
 // ContactList is list of Contact structures, to have custom JSON marshaling
 type ContactList []Contact
 
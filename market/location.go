@@ -28,6 +28,10 @@ type Location struct {
 	ASN       int    `json:"asn,omitempty"`
 	ISP       string `json:"isp,omitempty"`
 	IPType    string `json:"ip_type,omitempty"`
+
+	Overridden bool `json:"overridden,omitempty"`
+	Mismatch   bool `json:"mismatch,omitempty"`
+	Unverified bool `json:"unverified,omitempty"`
 }
 
 // NewLocation creates a new Location.
@@ -40,5 +44,9 @@ func NewLocation(loc locationstate.Location) *Location {
 		ASN:       loc.ASN,
 		ISP:       loc.ISP,
 		IPType:    loc.IPType,
+
+		Overridden: loc.Overridden,
+		Mismatch:   loc.Mismatch,
+		Unverified: loc.Unverified,
 	}
 }
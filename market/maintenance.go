@@ -0,0 +1,59 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package market
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MaintenanceWindow is a period the provider plans to be unavailable in.
+type MaintenanceWindow struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// ParseMaintenanceWindow parses the window from start/duration format, start is in RFC3339 e.g. 2024-05-01T02:00:00Z/2h.
+func ParseMaintenanceWindow(value string) (MaintenanceWindow, error) {
+	parts := strings.Split(strings.TrimSpace(value), "/")
+	if len(parts) != 2 {
+		return MaintenanceWindow{}, fmt.Errorf("invalid maintenance window %q, expected start/duration", value)
+	}
+
+	start, err := time.Parse(time.RFC3339, parts[0])
+	if err != nil {
+		return MaintenanceWindow{}, fmt.Errorf("invalid start of maintenance window %q: %w", value, err)
+	}
+	duration, err := time.ParseDuration(parts[1])
+	if err != nil || duration <= 0 {
+		return MaintenanceWindow{}, fmt.Errorf("invalid duration of maintenance window %q", value)
+	}
+
+	return MaintenanceWindow{Start: start.UTC(), End: start.UTC().Add(duration)}, nil
+}
+
+// String formats the window as start/duration.
+func (w MaintenanceWindow) String() string {
+	return w.Start.Format(time.RFC3339) + "/" + w.End.Sub(w.Start).String()
+}
+
+// Overlaps returns true if the window overlaps the given period.
+func (w MaintenanceWindow) Overlaps(from, to time.Time) bool {
+	return w.Start.Before(to) && w.End.After(from)
+}
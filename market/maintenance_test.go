@@ -0,0 +1,47 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package market
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMaintenanceWindow(t *testing.T) {
+	w, err := ParseMaintenanceWindow(" 2024-05-01T04:00:00+02:00/90m ")
+	assert.NoError(t, err)
+	assert.Equal(t, MaintenanceWindow{
+		Start: time.Date(2024, 5, 1, 2, 0, 0, 0, time.UTC),
+		End:   time.Date(2024, 5, 1, 3, 30, 0, 0, time.UTC),
+	}, w)
+	assert.Equal(t, "2024-05-01T02:00:00Z/1h30m0s", w.String())
+
+	for _, value := range []string{
+		"",
+		"2024-05-01T02:00:00Z",
+		"2024-05-01/2h",
+		"2024-05-01T02:00:00Z/soon",
+		"2024-05-01T02:00:00Z/-2h",
+		"2024-05-01T02:00:00Z/2h/1h",
+	} {
+		_, err := ParseMaintenanceWindow(value)
+		assert.Error(t, err, value)
+	}
+}
@@ -0,0 +1,115 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package market
+
+import (
+	"net/url"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// MaxNicknameLength limits the node nickname in proposal metadata, in characters.
+	MaxNicknameLength = 32
+	// MaxDescriptionLength limits the node description in proposal metadata, in characters.
+	MaxDescriptionLength = 280
+	// MaxContactURLLength limits the contact URL in proposal metadata, in bytes.
+	MaxContactURLLength = 256
+)
+
+// ProposalMetadata is optional human-readable information about the provider node.
+// It is informational only and comes from the provider, so it is sanitized on both ends.
+type ProposalMetadata struct {
+	// Nickname of the node.
+	Nickname string `json:"nickname,omitempty"`
+	// Description of the node or the community it belongs to.
+	Description string `json:"description,omitempty"`
+	// ContactURL is a http(s) or mailto URL of the node operator.
+	ContactURL string `json:"contact_url,omitempty"`
+}
+
+// IsEmpty checks if metadata has no information.
+func (m ProposalMetadata) IsEmpty() bool {
+	return m.Nickname == "" && m.Description == "" && m.ContactURL == ""
+}
+
+// Sanitize returns metadata without control, formatting and markup characters,
+// with texts truncated to their limits and unsupported contact URLs dropped.
+func (m ProposalMetadata) Sanitize() ProposalMetadata {
+	return ProposalMetadata{
+		Nickname:    sanitizeText(m.Nickname, MaxNicknameLength, false),
+		Description: sanitizeText(m.Description, MaxDescriptionLength, true),
+		ContactURL:  sanitizeContactURL(m.ContactURL),
+	}
+}
+
+func sanitizeText(s string, maxLength int, multiline bool) string {
+	var b strings.Builder
+	length := 0
+	space := false
+	for _, r := range s {
+		switch {
+		case r == utf8.RuneError:
+			continue
+		case r == '\n' && multiline:
+		case unicode.IsSpace(r):
+			space = true
+			continue
+		case unicode.IsControl(r), unicode.Is(unicode.Cf, r), r == '<', r == '>':
+			continue
+		}
+
+		if space && b.Len() > 0 && r != '\n' {
+			b.WriteRune(' ')
+			length++
+		}
+		space = false
+		if length >= maxLength {
+			break
+		}
+		b.WriteRune(r)
+		length++
+	}
+	return strings.TrimSpace(b.String())
+}
+
+func sanitizeContactURL(s string) string {
+	s = strings.TrimSpace(s)
+	if s == "" || len(s) > MaxContactURLLength {
+		return ""
+	}
+
+	u, err := url.Parse(s)
+	if err != nil || u.User != nil {
+		return ""
+	}
+	switch u.Scheme {
+	case "http", "https":
+		if u.Host == "" {
+			return ""
+		}
+	case "mailto":
+		if u.Opaque == "" {
+			return ""
+		}
+	default:
+		return ""
+	}
+	return u.String()
+}
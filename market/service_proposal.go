@@ -19,6 +19,7 @@ package market
 
 import (
 	"encoding/json"
+	"time"
 
 	validation "github.com/go-ozzo/ozzo-validation"
 	"github.com/mysteriumnetwork/node/p2p/compat"
@@ -56,6 +57,15 @@ type ServiceProposal struct {
 
 	// Quality represents the service quality.
 	Quality Quality `json:"quality"`
+
+	// SLA represents the service level provider commits to, if any.
+	SLA *SLA `json:"sla,omitempty"`
+
+	// Metadata is optional human-readable information about the provider node.
+	Metadata *ProposalMetadata `json:"metadata,omitempty"`
+
+	// Maintenance lists upcoming periods the provider plans to be unavailable in.
+	Maintenance []MaintenanceWindow `json:"maintenance,omitempty"`
 }
 
 // NewProposalOpts optional params for the new proposal creation.
@@ -64,6 +74,8 @@ type NewProposalOpts struct {
 	AccessPolicies []AccessPolicy
 	Contacts       []Contact
 	Quality        *Quality
+	SLA            *SLA
+	Metadata       *ProposalMetadata
 }
 
 // NewProposal creates a new proposal.
@@ -89,6 +101,8 @@ func NewProposal(providerID, serviceType string, opts NewProposalOpts) ServicePr
 	if q := opts.Quality; q != nil {
 		p.Quality = *q
 	}
+	p.SLA = opts.SLA
+	p.Metadata = sanitizeMetadata(opts.Metadata)
 	return p
 }
 
@@ -114,15 +128,18 @@ func (proposal *ServiceProposal) UniqueID() ProposalID {
 // UnmarshalJSON is custom json unmarshaler to dynamically fill in ServiceProposal values
 func (proposal *ServiceProposal) UnmarshalJSON(data []byte) error {
 	var jsonData struct {
-		ID             int64            `json:"id"`
-		Format         string           `json:"format"`
-		ProviderID     string           `json:"provider_id"`
-		ServiceType    string           `json:"service_type"`
-		Compatibility  int              `json:"compatibility"`
-		Location       Location         `json:"location"`
-		Contacts       *json.RawMessage `json:"contacts"`
-		AccessPolicies *[]AccessPolicy  `json:"access_policies,omitempty"`
-		Quality        Quality          `json:"quality"`
+		ID             int64               `json:"id"`
+		Format         string              `json:"format"`
+		ProviderID     string              `json:"provider_id"`
+		ServiceType    string              `json:"service_type"`
+		Compatibility  int                 `json:"compatibility"`
+		Location       Location            `json:"location"`
+		Contacts       *json.RawMessage    `json:"contacts"`
+		AccessPolicies *[]AccessPolicy     `json:"access_policies,omitempty"`
+		Quality        Quality             `json:"quality"`
+		SLA            *SLA                `json:"sla,omitempty"`
+		Metadata       *ProposalMetadata   `json:"metadata,omitempty"`
+		Maintenance    []MaintenanceWindow `json:"maintenance,omitempty"`
 	}
 	if err := json.Unmarshal(data, &jsonData); err != nil {
 		return err
@@ -139,10 +156,34 @@ func (proposal *ServiceProposal) UnmarshalJSON(data []byte) error {
 	proposal.Contacts = unserializeContacts(jsonData.Contacts)
 	proposal.AccessPolicies = jsonData.AccessPolicies
 	proposal.Quality = jsonData.Quality
+	proposal.SLA = jsonData.SLA
+	proposal.Metadata = sanitizeMetadata(jsonData.Metadata)
+	proposal.Maintenance = jsonData.Maintenance
 
 	return nil
 }
 
+func sanitizeMetadata(m *ProposalMetadata) *ProposalMetadata {
+	if m == nil {
+		return nil
+	}
+	sanitized := m.Sanitize()
+	if sanitized.IsEmpty() {
+		return nil
+	}
+	return &sanitized
+}
+
+// MaintenanceWithin returns the first maintenance window overlapping the given period from now.
+func (proposal *ServiceProposal) MaintenanceWithin(now time.Time, period time.Duration) (MaintenanceWindow, bool) {
+	for _, w := range proposal.Maintenance {
+		if w.Overlaps(now, now.Add(period)) {
+			return w, true
+		}
+	}
+	return MaintenanceWindow{}, false
+}
+
 // IsSupported returns true if this service proposal can be used for connections by service consumer
 // can be used as a filter to filter out all proposals which are unsupported for any reason
 func (proposal *ServiceProposal) IsSupported() bool {
@@ -161,6 +202,16 @@ func (proposal *ServiceProposal) IsSupported() bool {
 	return false
 }
 
+// IsExternal returns true if this service proposal describes an endpoint imported from outside of the network
+func (proposal *ServiceProposal) IsExternal() bool {
+	for _, contact := range proposal.Contacts {
+		if contact.Type == ContactTypeExternal {
+			return true
+		}
+	}
+	return false
+}
+
 var supportedServices = make(map[string]struct{})
 
 // RegisterServiceType registers a supported service type.
@@ -20,6 +20,7 @@ package market
 import (
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/mysteriumnetwork/node/config"
 	"github.com/stretchr/testify/assert"
@@ -51,7 +52,7 @@ func Test_ServiceProposal_Serialize(t *testing.T) {
 	assert.Nil(t, err)
 
 	expectedJSON := `{
-      "compatibility": 2,
+      "compatibility": 3,
 	  "format": "service-proposal/v3",
 	  "service_type": "mock_service",
 	  "provider_id": "node",
@@ -150,3 +151,84 @@ func Test_ServiceProposal_UnserializeAccessPolicy(t *testing.T) {
 	assert.Equal(t, expected, actual)
 	assert.True(t, actual.IsSupported())
 }
+
+func Test_ServiceProposal_UnserializeSLA(t *testing.T) {
+	RegisterServiceType("mock_service")
+	jsonData := []byte(`{
+		"id": 1,
+		"format": "service-proposal/v3",
+		"service_type": "mock_service",
+		"provider_id": "node",
+		"contacts": [],
+		"sla": {
+			"min_throughput": 10000000,
+			"max_setup_time": 5000,
+			"refund": 20
+		}
+	}`)
+
+	var actual ServiceProposal
+	err := json.Unmarshal(jsonData, &actual)
+	assert.NoError(t, err)
+	assert.Equal(t, &SLA{MinThroughput: 10000000, MaxSetupTime: 5000, Refund: 20}, actual.SLA)
+	assert.Equal(t, 5*time.Second, actual.SLA.SetupTimeLimit())
+}
+
+func Test_ServiceProposal_UnserializeMetadata(t *testing.T) {
+	RegisterServiceType("mock_service")
+	jsonData := []byte(`{
+		"id": 1,
+		"format": "service-proposal/v3",
+		"service_type": "mock_service",
+		"provider_id": "node",
+		"contacts": [],
+		"metadata": {
+			"nickname": "  Community\u202e <b>node</b>  ",
+			"description": "Run by\tthe\u0000 local club\nJoin us",
+			"contact_url": "javascript:alert(1)"
+		}
+	}`)
+
+	var actual ServiceProposal
+	err := json.Unmarshal(jsonData, &actual)
+	assert.NoError(t, err)
+	assert.Equal(t, &ProposalMetadata{
+		Nickname:    "Community bnode/b",
+		Description: "Run by the local club\nJoin us",
+	}, actual.Metadata)
+
+	err = json.Unmarshal([]byte(`{"format": "service-proposal/v3", "metadata": {"contact_url": "ftp://example.com"}}`), &actual)
+	assert.NoError(t, err)
+	assert.Nil(t, actual.Metadata)
+}
+
+func Test_ServiceProposal_UnserializeMaintenance(t *testing.T) {
+	RegisterServiceType("mock_service")
+	jsonData := []byte(`{
+		"id": 1,
+		"format": "service-proposal/v3",
+		"service_type": "mock_service",
+		"provider_id": "node",
+		"contacts": [],
+		"maintenance": [
+			{"start": "2024-05-01T02:00:00Z", "end": "2024-05-01T04:00:00Z"}
+		]
+	}`)
+
+	var actual ServiceProposal
+	err := json.Unmarshal(jsonData, &actual)
+	assert.NoError(t, err)
+	window := MaintenanceWindow{
+		Start: time.Date(2024, 5, 1, 2, 0, 0, 0, time.UTC),
+		End:   time.Date(2024, 5, 1, 4, 0, 0, 0, time.UTC),
+	}
+	assert.Equal(t, []MaintenanceWindow{window}, actual.Maintenance)
+
+	_, ok := actual.MaintenanceWithin(window.Start.Add(-2*time.Hour), time.Hour)
+	assert.False(t, ok)
+	w, ok := actual.MaintenanceWithin(window.Start.Add(-30*time.Minute), time.Hour)
+	assert.True(t, ok)
+	assert.Equal(t, window, w)
+	_, ok = actual.MaintenanceWithin(window.End, time.Hour)
+	assert.False(t, ok)
+}
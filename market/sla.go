@@ -0,0 +1,36 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package market
+
+import "time"
+
+// SLA represents a simple service level agreement advertised by the provider.
+// Consumer monitors the session against it and claims the refund on breach.
+type SLA struct {
+	// MinThroughput is the minimal session throughput in bits per second, 0 means no commitment.
+	MinThroughput uint64 `json:"min_throughput,omitempty"`
+	// MaxSetupTime is the maximal session setup time in milliseconds, 0 means no commitment.
+	MaxSetupTime uint64 `json:"max_setup_time,omitempty"`
+	// Refund is the share of the session price in percent, refunded to the consumer on breach.
+	Refund uint8 `json:"refund"`
+}
+
+// SetupTimeLimit returns the maximal session setup time.
+func (s SLA) SetupTimeLimit() time.Duration {
+	return time.Duration(s.MaxSetupTime) * time.Millisecond
+}
@@ -37,6 +37,8 @@ type NodeClaimRequest struct {
 	Arch        string `json:"arch"`
 	NodeVersion string `json:"node_version"`
 	RedirectURL string `json:"redirect_url,omitempty"`
+	Nickname    string `json:"nickname,omitempty"`
+	AvatarHash  string `json:"avatar_hash,omitempty"`
 }
 
 func (ncr NodeClaimRequest) json() ([]byte, error) {
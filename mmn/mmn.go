@@ -36,6 +36,7 @@ import (
 	"github.com/mysteriumnetwork/node/config"
 	"github.com/mysteriumnetwork/node/core/ip"
 	nodevent "github.com/mysteriumnetwork/node/core/node/event"
+	"github.com/mysteriumnetwork/node/core/profile"
 	"github.com/mysteriumnetwork/node/core/service/servicestate"
 	"github.com/mysteriumnetwork/node/eventbus"
 	"github.com/mysteriumnetwork/node/identity"
@@ -115,6 +116,13 @@ func (m *MMN) claimRequest(redirectURL *url.URL) NodeClaimRequest {
 	if redirectURL != nil {
 		rru = fmt.Sprint(redirectURL)
 	}
+	nodeProfile, err := profile.Profile{
+		Nickname:   config.GetString(config.FlagNodeNickname),
+		AvatarHash: config.GetString(config.FlagNodeAvatarHash),
+	}.Sanitize()
+	if err != nil {
+		log.Warn().Err(err).Msg("Not reporting invalid node profile to MMN")
+	}
 	return NodeClaimRequest{
 		LocalIP:     m.lastIP,
 		Identity:    m.lastIdentity.Address,
@@ -124,6 +132,8 @@ func (m *MMN) claimRequest(redirectURL *url.URL) NodeClaimRequest {
 		OS:          getOS(),
 		NodeVersion: metadata.VersionAsString(),
 		RedirectURL: rru,
+		Nickname:    nodeProfile.Nickname,
+		AvatarHash:  nodeProfile.AvatarHash,
 	}
 }
 
@@ -48,6 +48,7 @@ import (
 	"github.com/mysteriumnetwork/node/identity/registry"
 	"github.com/mysteriumnetwork/node/identity/selector"
 	"github.com/mysteriumnetwork/node/logconfig"
+	"github.com/mysteriumnetwork/node/logconfig/rollingwriter"
 	"github.com/mysteriumnetwork/node/metadata"
 	natprobe "github.com/mysteriumnetwork/node/nat/behavior"
 	"github.com/mysteriumnetwork/node/pilvytis"
@@ -71,6 +72,7 @@ type MobileNode struct {
 	node                      *cmd.Node
 	stateKeeper               *state.Keeper
 	connectionManager         connection.MultiManager
+	providerBlocklist         *connection.ProviderBlocklist
 	locationResolver          *location.Cache
 	natProber                 natprobe.NATProber
 	identitySelector          selector.Handler
@@ -97,6 +99,7 @@ type MobileNode struct {
 	hermesMigrator            *migration.HermesMigrator
 	servicesManager           *service.Manager
 	earningsProvider          earningsProvider
+	tunnelProfiles            *tunnelProfiles
 }
 
 type earningsProvider interface {
@@ -216,6 +219,10 @@ func NewNode(appPath string, options *MobileNodeOptions) (*MobileNode, error) {
 	config.Current.SetDefault(config.FlagSTUNservers.Name, []string{"stun.l.google.com:19302", "stun1.l.google.com:19302", "stun2.l.google.com:19302"})
 	config.Current.SetDefault(config.FlagUDPListenPorts.Name, "10000:60000")
 	config.Current.SetDefault(config.FlagStatsReportInterval.Name, time.Second)
+	config.Current.SetDefault(config.FlagProviderBlocklistFailures.Name, config.FlagProviderBlocklistFailures.Value)
+	config.Current.SetDefault(config.FlagProviderBlocklistPeriod.Name, config.FlagProviderBlocklistPeriod.Value)
+	config.Current.SetDefault(config.FlagSessionReconciliationTolerance.Name, config.FlagSessionReconciliationTolerance.Value)
+	config.Current.SetDefault(config.FlagSessionReconciliationDataSlack.Name, config.FlagSessionReconciliationDataSlack.Value)
 	config.Current.SetDefault(config.FlagUIFeatures.Name, options.UIFeaturesEnabled)
 	config.Current.SetDefault(config.FlagActiveServices.Name, "scraping")
 
@@ -223,6 +230,9 @@ func NewNode(appPath string, options *MobileNodeOptions) (*MobileNode, error) {
 		config.Current.SetDefault(config.FlagUserspace.Name, "true")
 		config.Current.SetDefault(config.FlagAgreedTermsConditions.Name, "true")
 		config.Current.SetDefault(config.FlagDiscoveryPingInterval.Name, "3m")
+		config.Current.SetDefault(config.FlagDiscoveryPingJitter.Name, config.FlagDiscoveryPingJitter.Value)
+		config.Current.SetDefault(config.FlagDiscoveryPingMaxBackoff.Name, config.FlagDiscoveryPingMaxBackoff.Value)
+		config.Current.SetDefault(config.FlagDiscoveryUnconfirmedTimeout.Name, config.FlagDiscoveryUnconfirmedTimeout.Value)
 		config.Current.SetDefault(config.FlagDiscoveryFetchInterval.Name, "3m")
 		config.Current.SetDefault(config.FlagAccessPolicyFetchInterval.Name, "10m")
 		config.Current.SetDefault(config.FlagAccessPolicyFetchingEnabled.Name, "false")
@@ -247,12 +257,14 @@ func NewNode(appPath string, options *MobileNodeOptions) (*MobileNode, error) {
 	config.Current.SetDefaultsByNetwork(bcNetwork)
 
 	network := node.OptionsNetwork{
-		Network:          bcNetwork,
-		DiscoveryAddress: options.DiscoveryAddress,
-		BrokerAddresses:  options.BrokerAddresses,
-		EtherClientRPCL1: options.EtherClientRPCL1,
-		EtherClientRPCL2: options.EtherClientRPCL2,
-		ChainID:          options.ActiveChainID,
+		Network:                        bcNetwork,
+		DiscoveryAddress:               options.DiscoveryAddress,
+		BrokerAddresses:                options.BrokerAddresses,
+		EtherClientRPCL1:               options.EtherClientRPCL1,
+		EtherClientRPCL2:               options.EtherClientRPCL2,
+		EtherClientFailoverTimeout:     config.FlagEtherRPCFailoverTimeout.Value,
+		EtherClientHealthCheckInterval: config.FlagEtherRPCHealthCheckInterval.Value,
+		ChainID:                        options.ActiveChainID,
 		DNSMap: map[string][]string{
 			"location.mysterium.network": {"51.158.129.204"},
 			"quality.mysterium.network":  {"51.158.129.204"},
@@ -272,6 +284,7 @@ func NewNode(appPath string, options *MobileNodeOptions) (*MobileNode, error) {
 		LogLevel: zerolog.DebugLevel,
 		LogHTTP:  false,
 		Filepath: filepath.Join(dataDir, "mysterium-node"),
+		Rotation: rollingwriter.DefaultConfig,
 	}
 
 	nodeOptions := node.Options{
@@ -287,6 +300,7 @@ func NewNode(appPath string, options *MobileNodeOptions) (*MobileNode, error) {
 		SwarmDialerDNSHeadstart: time.Millisecond * 1500,
 		Keystore: node.OptionsKeystore{
 			UseLightweight: true,
+			UnlockPolicy:   config.FlagKeystoreUnlockPolicy.Value,
 		},
 		FeedbackURL:    options.FeedbackURL,
 		OptionsNetwork: network,
@@ -354,6 +368,9 @@ func NewNode(appPath string, options *MobileNodeOptions) (*MobileNode, error) {
 	if options.IsProvider {
 		nodeOptions.Discovery.FetchEnabled = true
 		nodeOptions.Discovery.PingInterval = config.GetDuration(config.FlagDiscoveryPingInterval)
+		nodeOptions.Discovery.PingJitter = config.GetFloat64(config.FlagDiscoveryPingJitter)
+		nodeOptions.Discovery.PingMaxBackoff = config.GetDuration(config.FlagDiscoveryPingMaxBackoff)
+		nodeOptions.Discovery.UnconfirmedTimeout = config.GetDuration(config.FlagDiscoveryUnconfirmedTimeout)
 		nodeOptions.Discovery.FetchInterval = config.GetDuration(config.FlagDiscoveryFetchInterval)
 		nodeOptions.Payments = node.OptionsPayments{
 			MaxAllowedPaymentPercentile:    3000,
@@ -401,6 +418,7 @@ func NewNode(appPath string, options *MobileNodeOptions) (*MobileNode, error) {
 		node:                      di.Node,
 		stateKeeper:               di.StateKeeper,
 		connectionManager:         di.MultiConnectionManager,
+		providerBlocklist:         di.ProviderBlocklist,
 		locationResolver:          di.LocationResolver,
 		natProber:                 di.NATProber,
 		identitySelector:          di.IdentitySelector,
@@ -434,6 +452,7 @@ func NewNode(appPath string, options *MobileNodeOptions) (*MobileNode, error) {
 		filterPresetStorage: di.FilterPresetStorage,
 		hermesMigrator:      di.HermesMigrator,
 		earningsProvider:    di.HermesChannelRepository,
+		tunnelProfiles:      newTunnelProfiles(),
 	}
 
 	if options.IsProvider {
@@ -599,6 +618,8 @@ type ConnectRequest struct {
 	SortBy                  string
 	DNSOption               string
 	IncludeMonitoringFailed bool
+	// InactivityTimeoutMinutes disconnects the session after given amount of minutes without traffic, disabled if zero.
+	InactivityTimeoutMinutes int
 }
 
 func (cr *ConnectRequest) dnsOption() (connection.DNSOption, error) {
@@ -637,7 +658,7 @@ func (mb *MobileNode) Connect(req *ConnectRequest) *ConnectResponse {
 		ExcludeUnsupported:      true,
 	}
 
-	proposalLookup := connection.FilteredProposals(f, req.SortBy, mb.proposalsManager.repository)
+	proposalLookup := connection.FilteredProposals(f, req.SortBy, mb.proposalsManager.repository, mb.providerBlocklist)
 
 	qualityEvent := quality.ConnectionEvent{
 		ServiceType: req.ServiceType,
@@ -656,7 +677,8 @@ func (mb *MobileNode) Connect(req *ConnectRequest) *ConnectResponse {
 		}
 	}
 	connectOptions := connection.ConnectParams{
-		DNS: dnsOption,
+		DNS:               dnsOption,
+		InactivityTimeout: time.Duration(req.InactivityTimeoutMinutes) * time.Minute,
 	}
 
 	hermes, err := mb.identityChannelCalculator.GetActiveHermes(mb.chainID)
@@ -779,6 +801,7 @@ func (mb *MobileNode) OverrideWireguardConnection(wgTunnelSetup WireguardTunnelS
 		opts := wireGuardOptions{
 			statsUpdateInterval: 1 * time.Second,
 			handshakeTimeout:    1 * time.Minute,
+			tunnelProfile:       mb.tunnelProfiles.current,
 		}
 
 		return NewWireGuardConnection(
@@ -25,6 +25,7 @@ import (
 
 	"github.com/mysteriumnetwork/node/identity"
 	"github.com/mysteriumnetwork/node/identity/registry"
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
 	"github.com/mysteriumnetwork/payments/crypto"
 )
 
@@ -208,3 +209,8 @@ func (mb *MobileNode) MigrateHermesStatus(id string) ([]byte, error) {
 
 	return json.Marshal(MigrationStatusResponse{Status: status})
 }
+
+// MigrateHermesProgress returns the progress of the latest migration from old to active Hermes
+func (mb *MobileNode) MigrateHermesProgress(id string) ([]byte, error) {
+	return json.Marshal(contract.NewMigrationProgressResponse(mb.hermesMigrator.Progress(id)))
+}
@@ -0,0 +1,242 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package mysterium
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
+)
+
+// Access network types reported by the app with OnNetworkTypeChange.
+const (
+	NetworkTypeUnknown = "unknown"
+	NetworkTypeWiFi    = "wifi"
+	NetworkTypeLTE     = "lte"
+	NetworkType5G      = "5g"
+)
+
+const (
+	minTunnelMTU = 576
+	maxTunnelMTU = 1500
+)
+
+// TunnelProfile contains tunnel settings tuned for an access network type.
+type TunnelProfile struct {
+	// MTU of the tunnel interface.
+	MTU int
+	// KeepAliveSeconds is the WireGuard keep-alive period, zero keeps the one tuned to the NAT mapping lifetime.
+	KeepAliveSeconds int
+	// HandshakeTimeoutSeconds is how long a tunnel start waits for the handshake before it fails and is retried,
+	// lower values give up on a dead network faster.
+	HandshakeTimeoutSeconds int
+}
+
+func (p TunnelProfile) validate() error {
+	if p.MTU < minTunnelMTU || p.MTU > maxTunnelMTU {
+		return fmt.Errorf("MTU %d is out of range %d..%d", p.MTU, minTunnelMTU, maxTunnelMTU)
+	}
+	if p.KeepAliveSeconds < 0 {
+		return fmt.Errorf("negative keep-alive period %d", p.KeepAliveSeconds)
+	}
+	if p.HandshakeTimeoutSeconds < 0 {
+		return fmt.Errorf("negative handshake timeout %d", p.HandshakeTimeoutSeconds)
+	}
+	return nil
+}
+
+func (p TunnelProfile) keepAlive() time.Duration {
+	return time.Duration(p.KeepAliveSeconds) * time.Second
+}
+
+func (p TunnelProfile) handshakeTimeout() time.Duration {
+	return time.Duration(p.HandshakeTimeoutSeconds) * time.Second
+}
+
+// Cellular carriers drop idle UDP mappings quickly and hand over between cells often,
+// so keep-alives are sent more often and a stuck handshake is given up sooner there.
+var defaultTunnelProfiles = map[string]TunnelProfile{
+	NetworkTypeUnknown: {MTU: androidTunMtu, HandshakeTimeoutSeconds: 60},
+	NetworkTypeWiFi:    {MTU: 1420, HandshakeTimeoutSeconds: 60},
+	NetworkTypeLTE:     {MTU: androidTunMtu, KeepAliveSeconds: 15, HandshakeTimeoutSeconds: 20},
+	NetworkType5G:      {MTU: androidTunMtu, KeepAliveSeconds: 20, HandshakeTimeoutSeconds: 20},
+}
+
+// TunnelProfileChangeCallback represents tunnel profile change callback.
+type TunnelProfileChangeCallback interface {
+	OnChange(networkType string, mtu int, keepAliveSeconds int, handshakeTimeoutSeconds int)
+}
+
+type tunnelProfiles struct {
+	lock        sync.Mutex
+	networkType string
+	profiles    map[string]TunnelProfile
+	callbacks   []TunnelProfileChangeCallback
+}
+
+func newTunnelProfiles() *tunnelProfiles {
+	profiles := make(map[string]TunnelProfile, len(defaultTunnelProfiles))
+	for networkType, profile := range defaultTunnelProfiles {
+		profiles[networkType] = profile
+	}
+
+	return &tunnelProfiles{
+		networkType: NetworkTypeUnknown,
+		profiles:    profiles,
+	}
+}
+
+func normalizeNetworkType(networkType string) string {
+	networkType = strings.ToLower(strings.TrimSpace(networkType))
+	if _, ok := defaultTunnelProfiles[networkType]; !ok {
+		return NetworkTypeUnknown
+	}
+	return networkType
+}
+
+// current returns the profile of the current access network.
+func (tp *tunnelProfiles) current() TunnelProfile {
+	tp.lock.Lock()
+	defer tp.lock.Unlock()
+
+	return tp.profiles[tp.networkType]
+}
+
+func (tp *tunnelProfiles) get(networkType string) TunnelProfile {
+	tp.lock.Lock()
+	defer tp.lock.Unlock()
+
+	return tp.profiles[normalizeNetworkType(networkType)]
+}
+
+// setNetworkType switches to the profile of the given access network and returns the previous profile.
+func (tp *tunnelProfiles) setNetworkType(networkType string) (previous, current TunnelProfile) {
+	networkType = normalizeNetworkType(networkType)
+
+	tp.lock.Lock()
+	previous = tp.profiles[tp.networkType]
+	tp.networkType = networkType
+	current = tp.profiles[networkType]
+	tp.lock.Unlock()
+
+	if previous != current {
+		tp.notify(networkType, current)
+	}
+	return previous, current
+}
+
+// set overrides the profile of the given access network.
+func (tp *tunnelProfiles) set(networkType string, profile TunnelProfile) error {
+	if err := profile.validate(); err != nil {
+		return err
+	}
+	networkType = normalizeNetworkType(networkType)
+
+	tp.lock.Lock()
+	tp.profiles[networkType] = profile
+	active := tp.networkType == networkType
+	tp.lock.Unlock()
+
+	if active {
+		tp.notify(networkType, profile)
+	}
+	return nil
+}
+
+func (tp *tunnelProfiles) reset() {
+	tp.lock.Lock()
+	for networkType, profile := range defaultTunnelProfiles {
+		tp.profiles[networkType] = profile
+	}
+	networkType := tp.networkType
+	tp.lock.Unlock()
+
+	tp.notify(networkType, defaultTunnelProfiles[networkType])
+}
+
+func (tp *tunnelProfiles) register(cb TunnelProfileChangeCallback) {
+	tp.lock.Lock()
+	defer tp.lock.Unlock()
+
+	tp.callbacks = append(tp.callbacks, cb)
+}
+
+func (tp *tunnelProfiles) notify(networkType string, profile TunnelProfile) {
+	tp.lock.Lock()
+	callbacks := append([]TunnelProfileChangeCallback(nil), tp.callbacks...)
+	tp.lock.Unlock()
+
+	for _, cb := range callbacks {
+		cb.OnChange(networkType, profile.MTU, profile.KeepAliveSeconds, profile.HandshakeTimeoutSeconds)
+	}
+}
+
+// OnNetworkTypeChange should be called from the platform connectivity callback once the access network
+// type changes. The tunnel profile of the network is applied to the tunnels started from now on.
+// An established connection is moved to the new network keeping the session, it is reconnected
+// if the tunnel MTU has to change or the session could not be moved.
+func (mb *MobileNode) OnNetworkTypeChange(networkType string) {
+	previous, current := mb.tunnelProfiles.setNetworkType(networkType)
+	log.Info().Msgf("Access network changed to %q, tunnel profile: %+v", normalizeNetworkType(networkType), current)
+
+	if mb.connectionManager.Status(0).State != connectionstate.Connected {
+		return
+	}
+	if previous.MTU != current.MTU {
+		log.Info().Msgf("Tunnel MTU changed from %d to %d, reconnecting", previous.MTU, current.MTU)
+		go mb.connectionManager.Reconnect(0)
+		return
+	}
+
+	go func() {
+		if err := mb.connectionManager.Roam(0); err != nil {
+			log.Warn().Err(err).Msg("Failed to move session to the new network, reconnecting")
+			mb.connectionManager.Reconnect(0)
+		}
+	}()
+}
+
+// SetTunnelProfile overrides the tunnel profile used on the given access network type.
+func (mb *MobileNode) SetTunnelProfile(networkType string, profile *TunnelProfile) error {
+	if profile == nil {
+		return fmt.Errorf("tunnel profile is missing")
+	}
+	return mb.tunnelProfiles.set(networkType, *profile)
+}
+
+// GetTunnelProfile returns the tunnel profile used on the given access network type.
+func (mb *MobileNode) GetTunnelProfile(networkType string) *TunnelProfile {
+	profile := mb.tunnelProfiles.get(networkType)
+	return &profile
+}
+
+// ResetTunnelProfiles restores the default tunnel profiles of all access network types.
+func (mb *MobileNode) ResetTunnelProfiles() {
+	mb.tunnelProfiles.reset()
+}
+
+// RegisterTunnelProfileChangeCallback registers callback which is called once the tunnel profile
+// of the current access network changes.
+func (mb *MobileNode) RegisterTunnelProfileChangeCallback(cb TunnelProfileChangeCallback) {
+	mb.tunnelProfiles.register(cb)
+}
@@ -0,0 +1,95 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package mysterium
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type profileChange struct {
+	networkType string
+	profile     TunnelProfile
+}
+
+type recordingProfileCallback struct {
+	changes []profileChange
+}
+
+func (r *recordingProfileCallback) OnChange(networkType string, mtu int, keepAliveSeconds int, handshakeTimeoutSeconds int) {
+	r.changes = append(r.changes, profileChange{
+		networkType: networkType,
+		profile:     TunnelProfile{MTU: mtu, KeepAliveSeconds: keepAliveSeconds, HandshakeTimeoutSeconds: handshakeTimeoutSeconds},
+	})
+}
+
+func TestTunnelProfiles_FollowNetworkType(t *testing.T) {
+	profiles := newTunnelProfiles()
+	cb := &recordingProfileCallback{}
+	profiles.register(cb)
+	assert.Equal(t, defaultTunnelProfiles[NetworkTypeUnknown], profiles.current())
+
+	previous, current := profiles.setNetworkType("LTE")
+	assert.Equal(t, defaultTunnelProfiles[NetworkTypeUnknown], previous)
+	assert.Equal(t, defaultTunnelProfiles[NetworkTypeLTE], current)
+	assert.Equal(t, current, profiles.current())
+
+	_, current = profiles.setNetworkType("satellite")
+	assert.Equal(t, defaultTunnelProfiles[NetworkTypeUnknown], current)
+
+	assert.Equal(t, []profileChange{
+		{networkType: NetworkTypeLTE, profile: defaultTunnelProfiles[NetworkTypeLTE]},
+		{networkType: NetworkTypeUnknown, profile: defaultTunnelProfiles[NetworkTypeUnknown]},
+	}, cb.changes)
+}
+
+func TestTunnelProfiles_Override(t *testing.T) {
+	profiles := newTunnelProfiles()
+	cb := &recordingProfileCallback{}
+	profiles.register(cb)
+	profiles.setNetworkType(NetworkTypeWiFi)
+	cb.changes = nil
+
+	assert.Error(t, profiles.set(NetworkTypeWiFi, TunnelProfile{MTU: 9000}))
+	assert.Error(t, profiles.set(NetworkTypeWiFi, TunnelProfile{MTU: 1400, KeepAliveSeconds: -1}))
+
+	custom := TunnelProfile{MTU: 1400, KeepAliveSeconds: 10, HandshakeTimeoutSeconds: 30}
+	assert.NoError(t, profiles.set(NetworkType5G, custom))
+	assert.Empty(t, cb.changes, "profile of inactive network changed")
+	assert.Equal(t, custom, profiles.get("5G"))
+
+	assert.NoError(t, profiles.set(NetworkTypeWiFi, custom))
+	assert.Equal(t, custom, profiles.current())
+	assert.Equal(t, []profileChange{{networkType: NetworkTypeWiFi, profile: custom}}, cb.changes)
+
+	profiles.reset()
+	assert.Equal(t, defaultTunnelProfiles[NetworkTypeWiFi], profiles.current())
+	assert.Equal(t, defaultTunnelProfiles[NetworkType5G], profiles.get(NetworkType5G))
+}
+
+func TestWireGuardOptions_Profile(t *testing.T) {
+	opts := wireGuardOptions{handshakeTimeout: time.Minute}
+	assert.Equal(t, TunnelProfile{MTU: androidTunMtu, HandshakeTimeoutSeconds: 60}, opts.profile())
+
+	opts.tunnelProfile = func() TunnelProfile {
+		return TunnelProfile{MTU: 1420, KeepAliveSeconds: 15}
+	}
+	assert.Equal(t, TunnelProfile{MTU: 1420, KeepAliveSeconds: 15, HandshakeTimeoutSeconds: 60}, opts.profile())
+}
@@ -64,6 +64,19 @@ type WireguardTunnelSetup interface {
 type wireGuardOptions struct {
 	statsUpdateInterval time.Duration
 	handshakeTimeout    time.Duration
+	// tunnelProfile returns settings tuned for the current access network, read on every tunnel start.
+	tunnelProfile func() TunnelProfile
+}
+
+func (o wireGuardOptions) profile() TunnelProfile {
+	profile := TunnelProfile{MTU: androidTunMtu}
+	if o.tunnelProfile != nil {
+		profile = o.tunnelProfile()
+	}
+	if profile.HandshakeTimeoutSeconds == 0 {
+		profile.HandshakeTimeoutSeconds = int(o.handshakeTimeout.Seconds())
+	}
+	return profile
 }
 
 // NewWireGuardConnection creates a new wireguard connection
@@ -96,7 +109,10 @@ type wireguardConnection struct {
 	handshakeWaiter wireguard_connection.HandshakeWaiter
 }
 
-var _ connection.Connection = &wireguardConnection{}
+var (
+	_ connection.Connection = &wireguardConnection{}
+	_ connection.Roamer     = &wireguardConnection{}
+)
 
 func (c *wireguardConnection) State() <-chan connectionstate.State {
 	return c.stateCh
@@ -114,6 +130,11 @@ func (c *wireguardConnection) Statistics() (connectionstate.Statistics, error) {
 	}, nil
 }
 
+// Roam rebinds the tunnel to the current network of the device once it switched networks.
+func (c *wireguardConnection) Roam() error {
+	return c.device.Rebind()
+}
+
 func (c *wireguardConnection) Reconnect(ctx context.Context, options connection.ConnectOptions) (err error) {
 	return c.Start(ctx, options)
 }
@@ -124,6 +145,12 @@ func (c *wireguardConnection) Start(ctx context.Context, options connection.Conn
 	if err != nil {
 		return errors.Wrap(err, "could not parse wireguard session config")
 	}
+	config.KeepAlive = options.KeepAliveInterval
+
+	profile := c.opts.profile()
+	if profile.KeepAliveSeconds > 0 {
+		config.KeepAlive = profile.keepAlive()
+	}
 
 	c.stateCh <- connectionstate.Connecting
 
@@ -139,11 +166,11 @@ func (c *wireguardConnection) Start(ctx context.Context, options connection.Conn
 		config.Provider.Endpoint.Port = options.ProviderNATConn.RemoteAddr().(*net.UDPAddr).Port
 	}
 
-	if err = c.device.Start(c.privateKey, config, options.ChannelConn, options.Params.DNS); err != nil {
+	if err = c.device.Start(c.privateKey, config, profile.MTU, options.ChannelConn, options.Params.DNS); err != nil {
 		return errors.Wrap(err, "could not start device")
 	}
 
-	if err = c.handshakeWaiter.Wait(ctx, c.device.Stats, c.opts.handshakeTimeout, c.done); err != nil {
+	if err = c.handshakeWaiter.Wait(ctx, c.device.Stats, profile.handshakeTimeout(), c.done); err != nil {
 		return errors.Wrap(err, "failed to handshake")
 	}
 
@@ -179,9 +206,10 @@ func (c *wireguardConnection) GetConfig() (connection.ConsumerConfig, error) {
 }
 
 type wireguardDevice interface {
-	Start(privateKey string, config wireguard.ServiceConfig, channelConn *net.UDPConn, dns connection.DNSOption) error
+	Start(privateKey string, config wireguard.ServiceConfig, mtu int, channelConn *net.UDPConn, dns connection.DNSOption) error
 	Stop()
 	Stats() (wgcfg.Stats, error)
+	Rebind() error
 }
 
 func newWireguardDevice(tunnelSetup WireguardTunnelSetup) wireguardDevice {
@@ -194,9 +222,9 @@ type wireguardDeviceImpl struct {
 	device *device.Device
 }
 
-func (w *wireguardDeviceImpl) Start(privateKey string, config wireguard.ServiceConfig, channelConn *net.UDPConn, dns connection.DNSOption) error {
-	log.Debug().Msg("Creating tunnel device")
-	tunDevice, err := w.newTunnDevice(w.tunnelSetup, config, dns)
+func (w *wireguardDeviceImpl) Start(privateKey string, config wireguard.ServiceConfig, mtu int, channelConn *net.UDPConn, dns connection.DNSOption) error {
+	log.Debug().Msgf("Creating tunnel device with MTU %d", mtu)
+	tunDevice, err := w.newTunnDevice(w.tunnelSetup, config, mtu, dns)
 	if err != nil {
 		return errors.Wrap(err, "could not create tunnel device")
 	}
@@ -239,6 +267,23 @@ func (w *wireguardDeviceImpl) Start(privateKey string, config wireguard.ServiceC
 	return nil
 }
 
+// Rebind reopens the tunnel socket on the current network and excludes it from the tunnel again.
+func (w *wireguardDeviceImpl) Rebind() error {
+	if w.device == nil {
+		return errors.New("device is not started")
+	}
+
+	if err := w.device.BindUpdate(); err != nil {
+		return errors.Wrap(err, "could not rebind device")
+	}
+	socket, err := peekLookAtSocketFd4(w.device)
+	if err != nil {
+		return errors.Wrap(err, "could not get socket")
+	}
+
+	return errors.Wrap(w.tunnelSetup.Protect(socket), "could not protect socket")
+}
+
 func (w *wireguardDeviceImpl) Stop() {
 	if w.device != nil {
 		w.device.Close()
@@ -267,7 +312,7 @@ func (w *wireguardDeviceImpl) applyConfig(devApi *device.Device, privateKey stri
 		Peer: wgcfg.Peer{
 			Endpoint:               &config.Provider.Endpoint,
 			PublicKey:              config.Provider.PublicKey,
-			KeepAlivePeriodSeconds: 18,
+			KeepAlivePeriodSeconds: config.KeepAlivePeriodSeconds(),
 			// All traffic through this peer (unfortunately 0.0.0.0/0 didn't work as it was treated as ipv6)
 			AllowedIPs: []string{"0.0.0.0/1", "128.0.0.0/1"},
 		},
@@ -280,13 +325,13 @@ func (w *wireguardDeviceImpl) applyConfig(devApi *device.Device, privateKey stri
 	return nil
 }
 
-func (w *wireguardDeviceImpl) newTunnDevice(wgTunnSetup WireguardTunnelSetup, config wireguard.ServiceConfig, dns connection.DNSOption) (tun.Device, error) {
+func (w *wireguardDeviceImpl) newTunnDevice(wgTunnSetup WireguardTunnelSetup, config wireguard.ServiceConfig, mtu int, dns connection.DNSOption) (tun.Device, error) {
 	consumerIP := config.Consumer.IPAddress
 	prefixLen, _ := consumerIP.Mask.Size()
 	wgTunnSetup.NewTunnel()
 	wgTunnSetup.SetSessionName("wg-tun-session")
 	wgTunnSetup.AddTunnelAddress(consumerIP.IP.String(), prefixLen)
-	wgTunnSetup.SetMTU(androidTunMtu)
+	wgTunnSetup.SetMTU(mtu)
 	wgTunnSetup.SetBlocking(true)
 
 	dnsIPs, err := dns.ResolveIPs(config.Consumer.DNSIPs)
@@ -115,8 +115,9 @@ func newServiceConfig() wg.ServiceConfig {
 			Endpoint:  *endpoint,
 		},
 		Consumer: struct {
-			IPAddress net.IPNet
-			DNSIPs    string
+			IPAddress  net.IPNet
+			DNSIPs     string
+			PrivateKey string
 		}{
 			IPAddress: net.IPNet{
 				IP:   net.IPv4(127, 0, 0, 1),
@@ -129,7 +130,7 @@ func newServiceConfig() wg.ServiceConfig {
 
 type mockWireGuardDevice struct{}
 
-func (m mockWireGuardDevice) Start(_ string, _ wg.ServiceConfig, _ *net.UDPConn, _ connection.DNSOption) error {
+func (m mockWireGuardDevice) Start(_ string, _ wg.ServiceConfig, _ int, _ *net.UDPConn, _ connection.DNSOption) error {
 	return nil
 }
 
@@ -140,6 +141,10 @@ func (m mockWireGuardDevice) Stats() (wgcfg.Stats, error) {
 	return wgcfg.Stats{BytesSent: 10, BytesReceived: 11}, nil
 }
 
+func (m mockWireGuardDevice) Rebind() error {
+	return nil
+}
+
 type mockHandshakeWaiter struct {
 	err error
 }
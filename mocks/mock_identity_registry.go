@@ -37,3 +37,8 @@ func (i IdentityRegistry) Subscribe(_ eventbus.Subscriber) error {
 func (i IdentityRegistry) GetRegistrationStatus(_ int64, _ identity.Identity) (registry.RegistrationStatus, error) {
 	return i.Status, nil
 }
+
+// GetChainRegistrationStatus returns a pre-defined RegistrationStatus.
+func (i IdentityRegistry) GetChainRegistrationStatus(_ int64, _ identity.Identity) (registry.RegistrationStatus, error) {
+	return i.Status, nil
+}
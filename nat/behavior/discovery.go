@@ -26,6 +26,7 @@ import (
 	"time"
 
 	"github.com/mysteriumnetwork/node/nat"
+	"github.com/mysteriumnetwork/node/router"
 	"github.com/pion/stun"
 )
 
@@ -292,6 +293,10 @@ func connect(address string) (*stunServerConn, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err := router.ProtectUDPConn(c); err != nil {
+		c.Close()
+		return nil, err
+	}
 	serverConn := &stunServerConn{
 		conn:        c,
 		LocalAddr:   c.LocalAddr(),
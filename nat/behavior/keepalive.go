@@ -0,0 +1,225 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package behavior
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pion/stun"
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
+	"github.com/mysteriumnetwork/node/eventbus"
+)
+
+const (
+	// AppTopicKeepAliveTuned represents keep-alive interval tuning topic.
+	AppTopicKeepAliveTuned = "keepalive-tuned"
+
+	// Mapping lifetime is searched within these bounds, mappings are assumed to survive at least the minimum.
+	minMappingLifetime       = 10 * time.Second
+	maxMappingLifetime       = 2 * time.Minute
+	mappingLifetimePrecision = 5 * time.Second
+
+	minKeepAliveInterval = 5 * time.Second
+	maxKeepAliveInterval = time.Minute
+)
+
+// AppEventKeepAliveTuned is published when NAT mapping lifetime was measured.
+type AppEventKeepAliveTuned struct {
+	MappingLifetime time.Duration
+	// Interval is the longest keep-alive interval which still refreshes the mapping in time.
+	Interval time.Duration
+}
+
+// KeepAliveInterval returns the longest keep-alive interval which safely refreshes
+// NAT mapping of the given lifetime, leaving a quarter of the lifetime for delayed packets.
+func KeepAliveInterval(lifetime time.Duration) time.Duration {
+	interval := lifetime * 3 / 4
+	if interval < minKeepAliveInterval {
+		return minKeepAliveInterval
+	}
+	if interval > maxKeepAliveInterval {
+		return maxKeepAliveInterval
+	}
+	return interval
+}
+
+// DiscoverMappingLifetime measures how long NAT keeps idle UDP mapping by binary searching
+// for the longest idle period after which the STUN server still sees the same mapped address.
+// Every probe stays idle for the probed period, so the measurement takes a few minutes.
+func DiscoverMappingLifetime(ctx context.Context, address string, timeout time.Duration) (time.Duration, error) {
+	if timeout == 0 {
+		timeout = DefaultTimeout
+	}
+
+	return searchMappingLifetime(ctx, minMappingLifetime, maxMappingLifetime, mappingLifetimePrecision, func(ctx context.Context, idle time.Duration) (bool, error) {
+		return mappingSurvives(ctx, address, idle, timeout)
+	})
+}
+
+type mappingProbe func(ctx context.Context, idle time.Duration) (bool, error)
+
+func searchMappingLifetime(ctx context.Context, min, max, precision time.Duration, survives mappingProbe) (time.Duration, error) {
+	ok, err := survives(ctx, max)
+	if err != nil {
+		return 0, err
+	}
+	if ok {
+		return max, nil
+	}
+
+	for max-min > precision {
+		mid := min + (max-min)/2
+		ok, err := survives(ctx, mid)
+		if err != nil {
+			return 0, err
+		}
+		if ok {
+			min = mid
+		} else {
+			max = mid
+		}
+	}
+	return min, nil
+}
+
+// mappingSurvives tells whether NAT mapping of an idle socket survives the given period.
+// An expired mapping is recreated by the next request, usually for another port.
+func mappingSurvives(ctx context.Context, address string, idle, timeout time.Duration) (bool, error) {
+	conn, err := connect(address)
+	if err != nil {
+		return false, fmt.Errorf("STUN connection init failed: %w", err)
+	}
+	defer conn.Close()
+
+	mapped := func() (string, error) {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		resp, err := conn.roundTrip(ctx, stun.MustBuild(stun.TransactionID, stun.BindingRequest), conn.RemoteAddr)
+		if err != nil {
+			return "", err
+		}
+		xorAddr := parse(resp).xorAddr
+		if xorAddr == nil {
+			return "", ErrNoXorAddress
+		}
+		return xorAddr.String(), nil
+	}
+
+	before, err := mapped()
+	if err != nil {
+		return false, fmt.Errorf("mapping lifetime test RT failed: %w", err)
+	}
+
+	select {
+	case <-ctx.Done():
+		return false, ctx.Err()
+	case <-time.After(idle):
+	}
+
+	after, err := mapped()
+	if err != nil {
+		return false, fmt.Errorf("mapping lifetime test RT after %s failed: %w", idle, err)
+	}
+	return before == after, nil
+}
+
+// KeepAliveTuner measures NAT mapping lifetime while the node is not connected
+// and publishes keep-alive interval derived from it for the following connections.
+type KeepAliveTuner struct {
+	servers            []string
+	timeout            time.Duration
+	connStatusProvider ConnectionStatusProvider
+	publisher          eventbus.Publisher
+	discover           func(ctx context.Context, address string, timeout time.Duration) (time.Duration, error)
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewKeepAliveTuner creates keep-alive tuner measuring against the RFC 5780 compatible STUN servers.
+func NewKeepAliveTuner(connStatusProvider ConnectionStatusProvider, publisher eventbus.Publisher) *KeepAliveTuner {
+	return &KeepAliveTuner{
+		servers:            compatibleSTUNServers,
+		timeout:            concurrentRequestTimeout,
+		connStatusProvider: connStatusProvider,
+		publisher:          publisher,
+		discover:           DiscoverMappingLifetime,
+		stop:               make(chan struct{}),
+	}
+}
+
+// Start measures mapping lifetime in the background.
+func (t *KeepAliveTuner) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		select {
+		case <-t.stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	go func() {
+		defer cancel()
+		if _, err := t.Tune(ctx); err != nil {
+			log.Warn().Err(err).Msg("Could not tune keep-alive interval, using defaults")
+		}
+	}()
+}
+
+// Stop interrupts measurement in progress.
+func (t *KeepAliveTuner) Stop() {
+	t.stopOnce.Do(func() {
+		close(t.stop)
+	})
+}
+
+// Tune measures mapping lifetime against the first STUN server which answers
+// and publishes keep-alive interval derived from it.
+func (t *KeepAliveTuner) Tune(ctx context.Context) (AppEventKeepAliveTuned, error) {
+	if t.connStatusProvider.Status(0).State != connectionstate.NotConnected {
+		return AppEventKeepAliveTuned{}, ErrInappropriateState
+	}
+
+	var lastErr error
+	for _, server := range t.servers {
+		lifetime, err := t.discover(ctx, server, t.timeout)
+		if err != nil {
+			if ctx.Err() != nil {
+				return AppEventKeepAliveTuned{}, ctx.Err()
+			}
+			lastErr = err
+			continue
+		}
+
+		ev := AppEventKeepAliveTuned{
+			MappingLifetime: lifetime,
+			Interval:        KeepAliveInterval(lifetime),
+		}
+		log.Info().Msgf("NAT mapping lifetime is %s, using keep-alive interval %s", ev.MappingLifetime, ev.Interval)
+		t.publisher.Publish(AppTopicKeepAliveTuned, ev)
+		return ev, nil
+	}
+	return AppEventKeepAliveTuned{}, fmt.Errorf("could not measure NAT mapping lifetime: %w", lastErr)
+}
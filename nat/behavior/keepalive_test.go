@@ -0,0 +1,64 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package behavior
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchMappingLifetime(t *testing.T) {
+	tests := map[string]struct {
+		lifetime time.Duration
+		want     time.Duration
+	}{
+		"long lived mapping":      {lifetime: 10 * time.Minute, want: maxMappingLifetime},
+		"short lived mapping":     {lifetime: 30 * time.Second, want: 30 * time.Second},
+		"shorter than the bounds": {lifetime: time.Second, want: minMappingLifetime},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var probes []time.Duration
+			got, err := searchMappingLifetime(context.Background(), minMappingLifetime, maxMappingLifetime, mappingLifetimePrecision, func(_ context.Context, idle time.Duration) (bool, error) {
+				probes = append(probes, idle)
+				return idle < tt.lifetime, nil
+			})
+			assert.NoError(t, err)
+			assert.LessOrEqual(t, got, tt.want)
+			assert.Greater(t, got, tt.want-mappingLifetimePrecision)
+			assert.LessOrEqual(t, len(probes), 6)
+		})
+	}
+}
+
+func TestSearchMappingLifetime_StopsOnError(t *testing.T) {
+	_, err := searchMappingLifetime(context.Background(), minMappingLifetime, maxMappingLifetime, mappingLifetimePrecision, func(_ context.Context, _ time.Duration) (bool, error) {
+		return false, errors.New("timeout")
+	})
+	assert.EqualError(t, err, "timeout")
+}
+
+func TestKeepAliveInterval(t *testing.T) {
+	assert.Equal(t, minKeepAliveInterval, KeepAliveInterval(minMappingLifetime/4))
+	assert.Equal(t, 30*time.Second, KeepAliveInterval(40*time.Second))
+	assert.Equal(t, maxKeepAliveInterval, KeepAliveInterval(maxMappingLifetime))
+}
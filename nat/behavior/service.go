@@ -37,6 +37,8 @@ var compatibleSTUNServers = []string{
 const (
 	// AppTopicNATTypeDetected represents NAT type detection topic.
 	AppTopicNATTypeDetected = "NAT-type-detected"
+	// AppTopicNATProbed represents topic of NAT probe results, including failed ones.
+	AppTopicNATProbed = "NAT-probed"
 
 	concurrentRequestTimeout = 1 * time.Second
 )
@@ -45,6 +47,13 @@ const (
 // is active
 var ErrInappropriateState = errors.New("NAT probing is impossible at this connection state")
 
+// AppEventNATProbed is published after every NAT probe.
+type AppEventNATProbed struct {
+	Type  nat.NATType
+	Error error
+	Time  time.Time
+}
+
 // NATProber is an abstaction over instances capable probing NAT and
 // returning either it's type or error.
 type NATProber interface {
@@ -104,6 +113,7 @@ func (p *gatedNATProber) Probe(ctx context.Context) (nat.NATType, error) {
 	}
 
 	s, err := p.next.Probe(ctx)
+	p.eventbus.Publish(AppTopicNATProbed, AppEventNATProbed{Type: s, Error: err, Time: time.Now()})
 	if err != nil {
 		return "", err
 	}
@@ -0,0 +1,117 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package behavior
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mysteriumnetwork/node/eventbus"
+	"github.com/mysteriumnetwork/node/nat"
+	"github.com/mysteriumnetwork/node/nat/event"
+)
+
+// MethodStatus is the last outcome of a NAT traversal method.
+type MethodStatus struct {
+	Successful bool
+	Error      string
+	Time       time.Time
+}
+
+// Status summarizes what is known about NAT of this node.
+type Status struct {
+	Type       nat.NATType
+	ProbeError string
+	LastProbe  time.Time
+	// LastSuccessfulMethod is the traversal method which succeeded most recently, empty if none did.
+	LastSuccessfulMethod string
+	Methods              map[string]MethodStatus
+}
+
+// StatusTracker keeps the latest NAT probe result and outcomes of every traversal method.
+type StatusTracker struct {
+	lock   sync.Mutex
+	status Status
+}
+
+// NewStatusTracker creates a new NAT status tracker.
+func NewStatusTracker() *StatusTracker {
+	return &StatusTracker{
+		status: Status{Methods: make(map[string]MethodStatus)},
+	}
+}
+
+// Subscribe subscribes to relevant events of event bus.
+func (t *StatusTracker) Subscribe(bus eventbus.Subscriber) error {
+	if err := bus.Subscribe(AppTopicNATProbed, t.consumeProbe); err != nil {
+		return err
+	}
+	return bus.Subscribe(event.AppTopicTraversal, t.consumeTraversal)
+}
+
+// Status returns the current NAT status.
+func (t *StatusTracker) Status() Status {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	status := t.status
+	status.Methods = make(map[string]MethodStatus, len(t.status.Methods))
+	for method, s := range t.status.Methods {
+		status.Methods[method] = s
+	}
+	return status
+}
+
+func (t *StatusTracker) consumeProbe(e AppEventNATProbed) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.status.LastProbe = e.Time
+	if e.Error != nil {
+		t.status.ProbeError = e.Error.Error()
+		return
+	}
+	t.status.Type = e.Type
+	t.status.ProbeError = ""
+}
+
+func (t *StatusTracker) consumeTraversal(e event.Event) {
+	// Noop stages only tell that traversal is disabled.
+	if strings.HasPrefix(e.Stage, "noop_") {
+		return
+	}
+
+	method := e.Stage
+	if e.Method != "" {
+		method = e.Method
+	}
+
+	s := MethodStatus{Successful: e.Successful, Time: time.Now()}
+	if e.Error != nil {
+		s.Error = e.Error.Error()
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.status.Methods[method] = s
+	if e.Successful {
+		t.status.LastSuccessfulMethod = method
+	}
+}
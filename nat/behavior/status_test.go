@@ -0,0 +1,56 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package behavior
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/nat"
+	"github.com/mysteriumnetwork/node/nat/event"
+)
+
+func TestStatusTracker(t *testing.T) {
+	tracker := NewStatusTracker()
+	assert.Empty(t, tracker.Status().Methods)
+
+	probed := time.Now()
+	tracker.consumeProbe(AppEventNATProbed{Type: nat.NATTypeFullCone, Time: probed})
+	tracker.consumeProbe(AppEventNATProbed{Error: errors.New("timeout"), Time: probed.Add(time.Minute)})
+
+	tracker.consumeTraversal(event.Event{Stage: "port_mapping", Method: "upnp", Error: errors.New("no gateway")})
+	tracker.consumeTraversal(event.Event{Stage: "hole_punching", Successful: true})
+	tracker.consumeTraversal(event.Event{Stage: "noop_pinger", Successful: true})
+
+	status := tracker.Status()
+	assert.Equal(t, nat.NATTypeFullCone, status.Type)
+	assert.Equal(t, "timeout", status.ProbeError)
+	assert.Equal(t, probed.Add(time.Minute), status.LastProbe)
+	assert.Equal(t, "hole_punching", status.LastSuccessfulMethod)
+	assert.Len(t, status.Methods, 2)
+	assert.False(t, status.Methods["upnp"].Successful)
+	assert.Equal(t, "no gateway", status.Methods["upnp"].Error)
+	assert.True(t, status.Methods["hole_punching"].Successful)
+
+	// Returned status is a copy.
+	status.Methods["upnp"] = MethodStatus{Successful: true}
+	assert.False(t, tracker.Status().Methods["upnp"].Successful)
+}
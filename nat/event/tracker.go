@@ -36,4 +36,6 @@ type Event struct {
 	Stage      string `json:"stage"`
 	Successful bool   `json:"successful"`
 	Error      error  `json:"error,omitempty"`
+	// Method narrows down the stage when it can be done in several ways, e.g. port mapping via UPnP or NAT-PMP.
+	Method string `json:"method,omitempty"`
 }
@@ -20,6 +20,7 @@ package mapping
 import (
 	"errors"
 	"net"
+	"strings"
 	"time"
 
 	portmap "github.com/ethereum/go-ethereum/p2p/nat"
@@ -31,6 +32,13 @@ import (
 // StageName is used to indicate port mapping NAT traversal stage
 const StageName = "port_mapping"
 
+const (
+	// MethodUPnP is port mapping done via router's UPnP.
+	MethodUPnP = "upnp"
+	// MethodNATPMP is port mapping done via router's NAT-PMP.
+	MethodNATPMP = "nat_pmp"
+)
+
 // DefaultConfig returns default port mapping config.
 func DefaultConfig() *Config {
 	return &Config{
@@ -136,10 +144,26 @@ func (p *portMapper) routerIPPublic() bool {
 }
 
 func (p *portMapper) notify(id string, err error) {
+	var e event.Event
 	if err != nil {
-		p.publisher.Publish(event.AppTopicTraversal, event.BuildFailureEvent(id, StageName, err))
+		e = event.BuildFailureEvent(id, StageName, err)
 	} else {
-		p.publisher.Publish(event.AppTopicTraversal, event.BuildSuccessfulEvent(id, StageName))
+		e = event.BuildSuccessfulEvent(id, StageName)
+	}
+	e.Method = mappingMethod(p.config.MapInterface)
+	p.publisher.Publish(event.AppTopicTraversal, e)
+}
+
+// mappingMethod tells which protocol the router was mapped with, empty if it was not discovered yet.
+func mappingMethod(mapper portmap.Interface) string {
+	name := mapper.String()
+	switch {
+	case strings.HasPrefix(name, "UPNP"):
+		return MethodUPnP
+	case strings.HasPrefix(name, "NAT-PMP"):
+		return MethodNATPMP
+	default:
+		return ""
 	}
 }
 
@@ -179,3 +179,23 @@ func (m *mockRouter) ExternalIP() (net.IP, error) {
 func (m *mockRouter) String() string {
 	return ""
 }
+
+func TestMappingMethod(t *testing.T) {
+	for name, want := range map[string]string{
+		"UPNP IGDv1-IP1":       MethodUPnP,
+		"NAT-PMP(192.168.1.1)": MethodNATPMP,
+		"any":                  "",
+		"ExtIP(203.0.113.1)":   "",
+	} {
+		assert.Equal(t, want, mappingMethod(namedRouter{mockRouter: &mockRouter{}, name: name}), name)
+	}
+}
+
+type namedRouter struct {
+	*mockRouter
+	name string
+}
+
+func (r namedRouter) String() string {
+	return r.name
+}
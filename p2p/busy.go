@@ -0,0 +1,54 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package p2p
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BusyError rejects a request because the peer is overloaded.
+// The rejected peer is expected to retry after the given period, not to treat the peer as failing.
+type BusyError struct {
+	Reason     string
+	RetryAfter time.Duration
+}
+
+// Error returns the rejection description, which is also what peers unaware of busy replies see.
+func (e *BusyError) Error() string {
+	return fmt.Sprintf("peer is busy: %s, retry after %s", e.Reason, e.RetryAfter)
+}
+
+// marshal encodes the rejection as the reply payload: retry period in milliseconds and the reason.
+func (e *BusyError) marshal() []byte {
+	return []byte(strconv.FormatInt(e.RetryAfter.Milliseconds(), 10) + " " + e.Reason)
+}
+
+func unmarshalBusyError(data []byte) (*BusyError, error) {
+	millis, reason, _ := strings.Cut(string(data), " ")
+	ms, err := strconv.ParseInt(millis, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse busy reply %q: %w", string(data), err)
+	}
+	return &BusyError{
+		Reason:     reason,
+		RetryAfter: time.Duration(ms) * time.Millisecond,
+	}, nil
+}
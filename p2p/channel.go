@@ -32,6 +32,7 @@ import (
 	"golang.org/x/crypto/nacl/box"
 
 	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/p2p/compat"
 	"github.com/mysteriumnetwork/node/router"
 	"github.com/mysteriumnetwork/node/trace"
 )
@@ -45,12 +46,19 @@ var (
 
 	// ErrHandlerNotFound indicates that peer is not registered handler yet.
 	ErrHandlerNotFound = errors.New("p2p peer handler not found")
+
+	// ErrChannelClosed indicates that channel is already closed.
+	ErrChannelClosed = errors.New("p2p channel is closed")
+
+	// ErrRoamingUnverified indicates that peer address change can't be trusted as peer does not sign control messages.
+	ErrRoamingUnverified = errors.New("p2p peer roaming requires signed control messages")
 )
 
 const (
 	kcpMTUSize            = 1280
 	mtuLimit              = 1500
 	initialTrafficTimeout = 30 * time.Second
+	replySendTimeout      = 10 * time.Second
 )
 
 // ChannelSender is used to send messages.
@@ -99,6 +107,10 @@ type peer struct {
 	sync.RWMutex
 	publicKey  PublicKey
 	remoteAddr *net.UDPAddr
+
+	// roamAddr is the latest address from a different IP the peer was seen at.
+	// It replaces remoteAddr only once the peer confirms roaming over the channel.
+	roamAddr *net.UDPAddr
 }
 
 func (p *peer) addr() *net.UDPAddr {
@@ -115,6 +127,25 @@ func (p *peer) updateAddr(addr *net.UDPAddr) {
 	p.remoteAddr = addr
 }
 
+func (p *peer) setRoamAddr(addr *net.UDPAddr) {
+	p.Lock()
+	defer p.Unlock()
+
+	p.roamAddr = addr
+}
+
+func (p *peer) acceptRoamAddr() (*net.UDPAddr, bool) {
+	p.Lock()
+	defer p.Unlock()
+
+	if p.roamAddr == nil {
+		return nil, false
+	}
+
+	p.remoteAddr, p.roamAddr = p.roamAddr, nil
+	return p.remoteAddr, true
+}
+
 // transport wraps network primitives for sending and receiving packets.
 type transport struct {
 	// wireReader is used to read p2p message envelopes.
@@ -127,9 +158,10 @@ type transport struct {
 	session *kcp.UDPSession
 
 	// remoteConn is initial conn which should be created from NAT hole punching or manually. It contains
-	// initial local and remote peer addresses.
-	remoteConn *net.UDPConn
-	localConn  *net.UDPConn
+	// initial local and remote peer addresses. It is reopened on rebind, so it is guarded by remoteConnLock.
+	remoteConn     *net.UDPConn
+	remoteConnLock sync.RWMutex
+	localConn      *net.UDPConn
 
 	// proxyConn is used for KCP session as a remote. Since KCP doesn't expose it's data read loop
 	// this is needed to detect remote peer address changes as we can simply use conn.ReadFromUDP and
@@ -137,6 +169,13 @@ type transport struct {
 	proxyConn *net.UDPConn
 }
 
+func (tr *transport) conn() *net.UDPConn {
+	tr.remoteConnLock.RLock()
+	defer tr.remoteConnLock.RUnlock()
+
+	return tr.remoteConn
+}
+
 // channel implements Channel interface.
 type channel struct {
 	mu   sync.RWMutex
@@ -155,6 +194,16 @@ type channel struct {
 	// peer identity authenticated by its signature in initial exchange
 	peerID identity.Identity
 
+	// controlSigned is set if peer signs control messages and requires them to be signed.
+	controlSigned bool
+
+	// signer signs control messages sent to peer, verifier checks signatures of control messages received from it.
+	signer   identity.Signer
+	verifier identity.Verifier
+
+	// replayGuard rejects stale and replayed control messages.
+	replayGuard *replayGuard
+
 	// topicHandlers is similar to HTTP Server handlers and is responsible for handling peer requests.
 	topicHandlers map[string]HandlerFunc
 
@@ -172,9 +221,9 @@ type channel struct {
 	// localSessionAddr is KCP UDP conn address to which packets are written from remote conn.
 	localSessionAddr *net.UDPAddr
 
-	// sendQueue is a queue to which channel puts messages for sending. Message is not send directly to remote peer
+	// mux schedules messages of logical streams for sending. Message is not send directly to remote peer
 	// but to proxy conn which is when responsible for sending to remote
-	sendQueue chan *transportMsg
+	mux *streamMux
 
 	// upnpPortsRelease should be called to close mapped upnp ports when channel is closed.
 	upnpPortsRelease func()
@@ -185,7 +234,7 @@ type channel struct {
 
 // newChannel creates new p2p channel with initialized crypto primitives for data encryption
 // and starts listening for connections.
-func newChannel(remoteConn *net.UDPConn, privateKey PrivateKey, peerPubKey PublicKey, peerCompatibility int) (*channel, error) {
+func newChannel(remoteConn *net.UDPConn, privateKey PrivateKey, peerPubKey PublicKey, peerCompatibility int, peerCapabilities uint64) (*channel, error) {
 	peerAddr := remoteConn.RemoteAddr().(*net.UDPAddr)
 	localAddr := remoteConn.LocalAddr().(*net.UDPAddr)
 	remoteConn, err := reopenConn(remoteConn)
@@ -213,7 +262,7 @@ func newChannel(remoteConn *net.UDPConn, privateKey PrivateKey, peerPubKey Publi
 
 	tr := transport{
 		wireReader: newCompatibleWireReader(udpSession, peerCompatibility),
-		wireWriter: newCompatibleWireWriter(udpSession, peerCompatibility),
+		wireWriter: newCompatibleWireWriter(udpSession, peerCompatibility, peerCapabilities),
 		session:    udpSession,
 		remoteConn: remoteConn,
 		localConn:  localConn,
@@ -225,6 +274,7 @@ func newChannel(remoteConn *net.UDPConn, privateKey PrivateKey, peerPubKey Publi
 		remoteAddr: peerAddr,
 	}
 
+	stop := make(chan struct{}, 1)
 	c := channel{
 		tr:               &tr,
 		topicHandlers:    make(map[string]HandlerFunc),
@@ -233,8 +283,10 @@ func newChannel(remoteConn *net.UDPConn, privateKey PrivateKey, peerPubKey Publi
 		peer:             &peer,
 		localSessionAddr: localConn.LocalAddr().(*net.UDPAddr),
 		serviceConn:      nil,
-		stop:             make(chan struct{}, 1),
-		sendQueue:        make(chan *transportMsg, 100),
+		stop:             stop,
+		mux:              newStreamMux(DefaultStreamConfigs(), stop),
+		controlSigned:    controlSigningRequired(peerCompatibility, peerCapabilities),
+		replayGuard:      newReplayGuard(controlMaxAge),
 	}
 
 	return &c, nil
@@ -258,7 +310,6 @@ func (c *channel) launchReadSendLoops() {
 // If remote peer addr changes it will be updated and next send will use new addr.
 func (c *channel) remoteReadLoop(tr *transport) {
 	buf := make([]byte, mtuLimit)
-	latestPeerAddr := c.peer.addr()
 
 	for {
 		select {
@@ -267,8 +318,13 @@ func (c *channel) remoteReadLoop(tr *transport) {
 		default:
 		}
 
-		n, addr, err := tr.remoteConn.ReadFrom(buf)
+		conn := tr.conn()
+		n, addr, err := conn.ReadFrom(buf)
 		if err != nil {
+			if tr.conn() != conn {
+				// Conn was reopened by rebind, continue reading from the new one.
+				continue
+			}
 			if !errNetClose(err) {
 				log.Error().Err(err).Msg("Read from remote conn failed")
 			}
@@ -276,12 +332,17 @@ func (c *channel) remoteReadLoop(tr *transport) {
 			return
 		}
 
-		// Check if peer port changed.
+		// Check if peer port changed. Address change to another IP is only remembered,
+		// peer has to confirm roaming before packets are sent there.
 		if addr, ok := addr.(*net.UDPAddr); ok {
-			if addr.IP.Equal(latestPeerAddr.IP) && addr.Port != latestPeerAddr.Port {
-				log.Debug().Msgf("Peer port changed from %v to %v", latestPeerAddr, addr)
-				c.peer.updateAddr(addr)
-				latestPeerAddr = addr
+			latestPeerAddr := c.peer.addr()
+			if addr.IP.Equal(latestPeerAddr.IP) {
+				if addr.Port != latestPeerAddr.Port {
+					log.Debug().Msgf("Peer port changed from %v to %v", latestPeerAddr, addr)
+					c.peer.updateAddr(addr)
+				}
+			} else {
+				c.peer.setRoamAddr(addr)
 			}
 		}
 
@@ -317,8 +378,13 @@ func (c *channel) remoteSendLoop(tr *transport) {
 			return
 		}
 
-		_, err = tr.remoteConn.WriteToUDP(buf[:n], c.peer.addr())
+		conn := tr.conn()
+		_, err = conn.WriteToUDP(buf[:n], c.peer.addr())
 		if err != nil {
+			if tr.conn() != conn {
+				// Packet is lost while conn is reopened by rebind, KCP will resend it.
+				continue
+			}
 			if !errNetClose(err) {
 				log.Error().Err(err).Msgf("Write to remote peer conn failed")
 			}
@@ -363,24 +429,20 @@ func (c *channel) localReadLoop(tr *transport) {
 // localSendLoop sends data to local proxy conn.
 func (c *channel) localSendLoop(tr *transport) {
 	for {
-		select {
-		case <-c.stop:
+		msg, ok := c.mux.dequeue()
+		if !ok {
 			return
-		case msg, more := <-c.sendQueue:
-			if !more {
-				return
-			}
+		}
 
-			if debugTransport {
-				fmt.Printf("send to %s: %+v\n", tr.session.RemoteAddr(), msg)
-			}
+		if debugTransport {
+			fmt.Printf("send to %s: %+v\n", tr.session.RemoteAddr(), msg)
+		}
 
-			if err := msg.writeTo(tr.wireWriter); err != nil {
-				if !errPipeClosed(err) && !errNetClose(err) {
-					log.Err(err).Msg("Write to wireproto writer failed")
-				}
-				return
+		if err := msg.writeTo(tr.wireWriter); err != nil {
+			if !errPipeClosed(err) && !errNetClose(err) {
+				log.Err(err).Msg("Write to wireproto writer failed")
 			}
+			return
 		}
 	}
 }
@@ -410,18 +472,33 @@ func (c *channel) handleRequest(msg *transportMsg) {
 		errMsg := fmt.Sprintf("handler %q not found", msg.topic)
 		log.Err(errors.New(errMsg))
 		resMsg.data = []byte(errMsg)
-		c.sendQueue <- &resMsg
+		c.sendReply(msg.topic, &resMsg)
+		return
+	}
+
+	if err := c.verifyControlMsg(msg); err != nil {
+		log.Warn().Err(err).Msgf("Rejected %q request from peer %s", msg.topic, c.peerID.Address)
+		resMsg.statusCode = countRejectedControl(err)
+		resMsg.data = []byte(err.Error())
+		c.sendReply(msg.topic, &resMsg)
 		return
 	}
 
 	ctx := defaultContext{
 		req: &Message{
-			Data: msg.data,
+			Data:  msg.data,
+			Nonce: msg.nonce,
 		},
 		peerID: c.peerID,
 	}
 	err := handler(&ctx)
-	if err != nil {
+	var busy *BusyError
+	if errors.As(err, &busy) {
+		log.Warn().Err(err).Msgf("Handler %q rejected request, node is busy", msg.topic)
+		resMsg.statusCode = statusCodeBusyErr
+		resMsg.msg = busy.Error()
+		resMsg.data = busy.marshal()
+	} else if err != nil {
 		log.Err(err).Msgf("Handler %q internal error", msg.topic)
 		resMsg.statusCode = statusCodeInternalErr
 		resMsg.msg = err.Error()
@@ -435,7 +512,18 @@ func (c *channel) handleRequest(msg *transportMsg) {
 			resMsg.data = ctx.res.Data
 		}
 	}
-	c.sendQueue <- &resMsg
+	c.sendReply(msg.topic, &resMsg)
+}
+
+// sendReply schedules reply on the same logical stream as the request.
+// Reply is dropped if the stream queue stays full for too long.
+func (c *channel) sendReply(topic string, msg *transportMsg) {
+	ctx, cancel := context.WithTimeout(context.Background(), replySendTimeout)
+	defer cancel()
+
+	if err := c.mux.enqueue(ctx, c.mux.topicStream(topic), msg); err != nil {
+		log.Debug().Err(err).Msgf("Reply to %q dropped", topic)
+	}
 }
 
 // Tracer returns tracer which tracks channel establishment
@@ -465,7 +553,7 @@ func (c *channel) Close() error {
 			closeErr = fmt.Errorf("could not close remote conn: %w", err)
 		}
 
-		if err := c.tr.remoteConn.Close(); err != nil {
+		if err := c.tr.conn().Close(); err != nil {
 			closeErr = fmt.Errorf("could not close remote conn: %w", err)
 		}
 
@@ -488,7 +576,7 @@ func (c *channel) Close() error {
 		c.tr = nil
 	})
 
-	if err := router.RemoveExcludedIP(c.peer.remoteAddr.IP); err != nil {
+	if err := router.RemoveExcludedIP(c.peer.addr().IP); err != nil {
 		return err
 	}
 
@@ -497,7 +585,51 @@ func (c *channel) Close() error {
 
 // Conn returns underlying channel's UDP connection.
 func (c *channel) Conn() *net.UDPConn {
-	return c.tr.remoteConn
+	return c.tr.conn()
+}
+
+// Rebind reopens the channel's UDP connection on the same local port, but on all local addresses,
+// so that the peer is reached from the current address after the host has switched networks.
+// Peer keeps sending to the previous address until it is asked to accept the roamed one.
+func (c *channel) Rebind() error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.tr == nil {
+		return ErrChannelClosed
+	}
+
+	tr := c.tr
+	tr.remoteConnLock.Lock()
+	defer tr.remoteConnLock.Unlock()
+
+	// Hold the lock until the new conn is set, so read and send loops don't stop on the closed one.
+	localAddr := tr.remoteConn.LocalAddr().(*net.UDPAddr)
+	tr.remoteConn.Close()
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{Port: localAddr.Port})
+	if err != nil {
+		return fmt.Errorf("could not listen UDP: %w", err)
+	}
+
+	if err := router.ProtectUDPConn(conn); err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to protect udp connection: %w", err)
+	}
+
+	tr.remoteConn = conn
+	return nil
+}
+
+// AcceptRoamedPeer switches sending to the latest address the peer was seen at after its IP changed
+// and returns it, or nil if the peer was not seen at another address. It should be called only after
+// the peer itself confirmed roaming over the channel, so roaming is refused if control messages are not signed.
+func (c *channel) AcceptRoamedPeer() (*net.UDPAddr, error) {
+	if !c.controlSigned {
+		return nil, ErrRoamingUnverified
+	}
+
+	addr, _ := c.peer.acceptRoamAddr()
+	return addr, nil
 }
 
 // Send sends message to given topic. Peer listening to topic will receive message.
@@ -519,11 +651,24 @@ func (c *channel) Handle(topic string, handler HandlerFunc) {
 
 // sendRequest sends message to send queue and waits for response.
 func (c *channel) sendRequest(ctx context.Context, topic string, m *Message) (*Message, error) {
+	id := c.mux.topicStream(topic)
+	if err := c.mux.acquire(ctx, id); err != nil {
+		return nil, fmt.Errorf("%s stream window is full for %q: %w", id, topic, ErrSendTimeout)
+	}
+	defer c.mux.release(id)
+
 	s := c.addStream()
 	defer c.deleteStream(s.id)
 
+	msg := &transportMsg{id: s.id, topic: topic, data: m.Data}
+	if err := c.signControlMsg(msg); err != nil {
+		return nil, fmt.Errorf("could not sign request to %q: %w", topic, err)
+	}
+
 	// Send request.
-	c.sendQueue <- &transportMsg{id: s.id, topic: topic, data: m.Data}
+	if err := c.mux.enqueue(ctx, id, msg); err != nil {
+		return nil, fmt.Errorf("could not queue request to %q: %w", topic, ErrSendTimeout)
+	}
 
 	// Wait for response.
 	select {
@@ -537,12 +682,74 @@ func (c *channel) sendRequest(ctx context.Context, topic string, m *Message) (*M
 			if res.statusCode == statusCodeHandlerNotFoundErr {
 				return nil, fmt.Errorf("%s: %w", string(res.data), ErrHandlerNotFound)
 			}
+			if err, ok := controlStatusErrors[res.statusCode]; ok {
+				return nil, fmt.Errorf("peer rejected request to %q: %w", topic, err)
+			}
+			if res.statusCode == statusCodeBusyErr {
+				busy, err := unmarshalBusyError(res.data)
+				if err != nil {
+					return nil, fmt.Errorf("peer error: %w", errors.New(res.msg))
+				}
+				return nil, fmt.Errorf("peer rejected request to %q: %w", topic, busy)
+			}
 			return nil, fmt.Errorf("peer error: %w", errors.New(res.msg))
 		}
 		return &Message{Data: res.data}, nil
 	}
 }
 
+// controlSigningRequired tells whether control messages exchanged with the peer have to be signed.
+// Signing is mandatory for peers at the compatibility level which introduced it, older peers opt in by capability.
+func controlSigningRequired(peerCompatibility int, peerCapabilities uint64) bool {
+	if compat.FeatureSignedControlRequired(peerCompatibility) {
+		return true
+	}
+	return compat.FeaturePBP2P(peerCompatibility) && compat.FeatureSignedControl(peerCapabilities)
+}
+
+// signControlMsg signs control message if peer requires it.
+func (c *channel) signControlMsg(msg *transportMsg) error {
+	if !c.controlSigned || !controlTopics[msg.topic] {
+		return nil
+	}
+	if c.signer == nil {
+		return errors.New("control message signer is not set")
+	}
+
+	nonce, err := newControlNonce()
+	if err != nil {
+		return fmt.Errorf("could not generate nonce: %w", err)
+	}
+	msg.nonce = nonce
+	msg.timestamp = time.Now().UnixNano()
+
+	signature, err := c.signer.Sign(controlPayload(msg.topic, msg.nonce, msg.timestamp, msg.data))
+	if err != nil {
+		return err
+	}
+	msg.signature = signature.Bytes()
+	return nil
+}
+
+// verifyControlMsg checks that control message is signed by peer, fresh and not replayed.
+func (c *channel) verifyControlMsg(msg *transportMsg) error {
+	if !c.controlSigned || !controlTopics[msg.topic] {
+		return nil
+	}
+	if len(msg.signature) == 0 {
+		return ErrControlUnsigned
+	}
+	if c.verifier == nil {
+		return ErrControlSignatureInvalid
+	}
+
+	payload := controlPayload(msg.topic, msg.nonce, msg.timestamp, msg.data)
+	if ok, _ := c.verifier.Verify(payload, identity.SignatureBytes(msg.signature)); !ok {
+		return ErrControlSignatureInvalid
+	}
+	return c.replayGuard.check(msg.nonce, msg.timestamp)
+}
+
 func (c *channel) addStream() *stream {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -582,6 +789,25 @@ func (c *channel) setPeerID(id identity.Identity) {
 	c.peerID = id
 }
 
+func (c *channel) setControlSigning(signer identity.Signer, verifier identity.Verifier) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.signer = signer
+	c.verifier = verifier
+}
+
+// setPadding enables padding of small messages. It has no effect for peers
+// using the legacy text wire format.
+func (c *channel) setPadding(enabled bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if w, ok := c.tr.wireWriter.(*protobufWireWriter); ok {
+		w.pad = enabled
+	}
+}
+
 func (c *channel) setUpnpPortsRelease(release func()) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -20,6 +20,7 @@ package p2p
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net"
 	"sync"
 	"testing"
@@ -29,6 +30,8 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/mysteriumnetwork/node/core/port"
+	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/p2p/compat"
 	"github.com/mysteriumnetwork/node/pb"
 )
 
@@ -170,6 +173,18 @@ func TestChannelFullCommunicationFlow(t *testing.T) {
 		assert.EqualError(t, err, "peer error: I don't like you")
 	})
 
+	t.Run("Test peer returns busy error", func(t *testing.T) {
+		provider.Handle("get-error", func(c Context) error {
+			return fmt.Errorf("cannot start session: %w", &BusyError{Reason: "too many sessions", RetryAfter: 90 * time.Second})
+		})
+
+		_, err := consumer.Send(context.Background(), "get-error", &Message{Data: []byte("hello")})
+		var busy *BusyError
+		require.ErrorAs(t, err, &busy)
+		assert.Equal(t, "too many sessions", busy.Reason)
+		assert.Equal(t, 90*time.Second, busy.RetryAfter)
+	})
+
 	t.Run("Test peer returns handler not found error", func(t *testing.T) {
 		_, err := consumer.Send(context.Background(), "ping", &Message{Data: []byte("hello")})
 		if !errors.Is(err, ErrHandlerNotFound) {
@@ -260,6 +275,67 @@ func TestChannel_Detect_And_Update_Peer_Addr(t *testing.T) {
 	_, err = consumer.Send(ctx, "ping", &Message{Data: []byte("pingasssas")})
 }
 
+func TestChannel_Rebind_Keeps_Communication(t *testing.T) {
+	provider, consumer, err := createTestChannels()
+	require.NoError(t, err)
+	defer provider.Close()
+	defer consumer.Close()
+
+	provider.Handle("ping", func(c Context) error {
+		return c.OK()
+	})
+
+	port := consumer.Conn().LocalAddr().(*net.UDPAddr).Port
+	require.NoError(t, consumer.(*channel).Rebind())
+	assert.Equal(t, port, consumer.Conn().LocalAddr().(*net.UDPAddr).Port)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	_, err = consumer.Send(ctx, "ping", &Message{Data: []byte("ping")})
+	assert.NoError(t, err)
+}
+
+func TestChannel_AcceptRoamedPeer(t *testing.T) {
+	provider, consumer, err := createTestChannelsWithCapabilities(compat.CapabilitySignedControl, compat.CapabilitySignedControl)
+	require.NoError(t, err)
+	defer provider.Close()
+	defer consumer.Close()
+
+	addr, err := provider.AcceptRoamedPeer()
+	assert.NoError(t, err)
+	assert.Nil(t, addr)
+
+	roamed, err := net.DialUDP("udp4", &net.UDPAddr{IP: net.ParseIP("127.0.0.2")}, &net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: provider.Conn().LocalAddr().(*net.UDPAddr).Port})
+	require.NoError(t, err)
+	defer roamed.Close()
+
+	_, err = roamed.Write([]byte("hello from another network"))
+	require.NoError(t, err)
+
+	// Packet from another IP does not change the peer address on its own.
+	assert.Eventually(t, func() bool {
+		provider.peer.RLock()
+		defer provider.peer.RUnlock()
+		return provider.peer.roamAddr != nil
+	}, time.Second, 10*time.Millisecond)
+	assert.NotEqual(t, roamed.LocalAddr().String(), provider.peer.addr().String())
+
+	addr, err = provider.AcceptRoamedPeer()
+	assert.NoError(t, err)
+	assert.Equal(t, roamed.LocalAddr().String(), addr.String())
+	assert.Equal(t, roamed.LocalAddr().String(), provider.peer.addr().String())
+}
+
+func TestChannel_AcceptRoamedPeer_RequiresSignedControl(t *testing.T) {
+	provider, consumer, err := createTestChannels()
+	require.NoError(t, err)
+	defer provider.Close()
+	defer consumer.Close()
+
+	_, err = provider.(*channel).AcceptRoamedPeer()
+	assert.ErrorIs(t, err, ErrRoamingUnverified)
+}
+
 func BenchmarkChannel_Send(b *testing.B) {
 	provider, consumer, err := createTestChannels()
 	require.NoError(b, err)
@@ -279,12 +355,129 @@ func BenchmarkChannel_Send(b *testing.B) {
 	}
 }
 
+func TestChannelControlMessagesAreSignedAndVerified(t *testing.T) {
+	provider, consumer, err := createTestChannelsWithCapabilities(compat.CapabilitySignedControl, compat.CapabilitySignedControl)
+	require.NoError(t, err)
+	defer provider.Close()
+	defer consumer.Close()
+
+	handled := make(chan struct{}, 10)
+	provider.Handle(TopicSessionDestroy, func(c Context) error {
+		handled <- struct{}{}
+		return c.OK()
+	})
+
+	_, err = consumer.Send(context.Background(), TopicSessionDestroy, &Message{Data: []byte("session")})
+	require.NoError(t, err)
+	assert.Len(t, handled, 1)
+
+	send := func(msg *transportMsg) *transportMsg {
+		s := consumer.addStream()
+		defer consumer.deleteStream(s.id)
+
+		msg.id = s.id
+		require.NoError(t, consumer.mux.enqueue(context.Background(), consumer.mux.topicStream(msg.topic), msg))
+		select {
+		case res := <-s.resCh:
+			return res
+		case <-time.After(time.Second):
+			t.Fatal("did not receive reply")
+			return nil
+		}
+	}
+
+	t.Run("replayed message is rejected", func(t *testing.T) {
+		msg := &transportMsg{topic: TopicSessionDestroy, data: []byte("session")}
+		require.NoError(t, consumer.signControlMsg(msg))
+
+		assert.Equal(t, uint64(statusCodeOK), send(msg).statusCode)
+		replayed := *msg
+		assert.Equal(t, uint64(statusCodeControlReplayedErr), send(&replayed).statusCode)
+	})
+
+	t.Run("stale message is rejected", func(t *testing.T) {
+		msg := &transportMsg{topic: TopicSessionDestroy, data: []byte("session"), nonce: 1, timestamp: time.Now().Add(-time.Hour).UnixNano()}
+		signature, err := consumer.signer.Sign(controlPayload(msg.topic, msg.nonce, msg.timestamp, msg.data))
+		require.NoError(t, err)
+		msg.signature = signature.Bytes()
+
+		assert.Equal(t, uint64(statusCodeControlStaleErr), send(msg).statusCode)
+	})
+
+	t.Run("tampered message is rejected", func(t *testing.T) {
+		msg := &transportMsg{topic: TopicSessionDestroy, data: []byte("session")}
+		require.NoError(t, consumer.signControlMsg(msg))
+		msg.data = []byte("other session")
+
+		assert.Equal(t, uint64(statusCodeControlSignatureErr), send(msg).statusCode)
+	})
+
+	t.Run("unsigned message is rejected", func(t *testing.T) {
+		msg := &transportMsg{topic: TopicSessionDestroy, data: []byte("session")}
+
+		assert.Equal(t, uint64(statusCodeControlUnsignedErr), send(msg).statusCode)
+	})
+
+	t.Run("non control message is not signed", func(t *testing.T) {
+		msg := &transportMsg{topic: "ping.pong"}
+		require.NoError(t, consumer.signControlMsg(msg))
+
+		assert.Empty(t, msg.signature)
+	})
+
+	assert.Len(t, handled, 2)
+}
+
+func TestChannelRejectsUnsignedControlMessagesOfSigningPeer(t *testing.T) {
+	// Consumer advertises signing of control messages to provider, but does not sign them.
+	provider, consumer, err := createTestChannelsWithCapabilities(0, compat.CapabilitySignedControl)
+	require.NoError(t, err)
+	defer provider.Close()
+	defer consumer.Close()
+
+	provider.Handle(TopicPaymentMessage, func(c Context) error {
+		return c.OK()
+	})
+
+	_, err = consumer.Send(context.Background(), TopicPaymentMessage, &Message{Data: []byte("promise")})
+	assert.ErrorIs(t, err, ErrControlUnsigned)
+}
+
+func TestChannelRequiresSignedControlMessagesOfCurrentPeers(t *testing.T) {
+	provider, consumer, err := createTestChannelsWithCompatibility(compat.Compatibility, 0, 0)
+	require.NoError(t, err)
+	defer provider.Close()
+	defer consumer.Close()
+	assert.True(t, provider.controlSigned)
+	assert.True(t, consumer.controlSigned)
+
+	provider.Handle(TopicPaymentMessage, func(c Context) error {
+		return c.OK()
+	})
+
+	// Consumer at current compatibility level without the capability bit does not get to skip signing.
+	consumer.controlSigned = false
+	_, err = consumer.Send(context.Background(), TopicPaymentMessage, &Message{Data: []byte("promise")})
+	assert.ErrorIs(t, err, ErrControlUnsigned)
+
+	consumer.controlSigned = true
+	_, err = consumer.Send(context.Background(), TopicPaymentMessage, &Message{Data: []byte("promise")})
+	assert.NoError(t, err)
+}
+
+func TestControlSigningRequired(t *testing.T) {
+	assert.False(t, controlSigningRequired(0, compat.CapabilitySignedControl))
+	assert.False(t, controlSigningRequired(2, 0))
+	assert.True(t, controlSigningRequired(2, compat.CapabilitySignedControl))
+	assert.True(t, controlSigningRequired(compat.Compatibility, 0))
+}
+
 func reopenChannel(c *channel, addr *net.UDPAddr) (*channel, error) {
 	punchedConn, err := net.DialUDP("udp4", addr, c.peer.addr())
 	if err != nil {
 		return nil, err
 	}
-	ch, err := newChannel(punchedConn, c.privateKey, c.peer.publicKey, 1)
+	ch, err := newChannel(punchedConn, c.privateKey, c.peer.publicKey, 1, 0)
 	if err != nil {
 		return nil, err
 	}
@@ -297,7 +490,7 @@ func reopenChannelWithNewLocalAddr(c *channel) (*channel, error) {
 	if err != nil {
 		return nil, err
 	}
-	ch, err := newChannel(punchedConn, c.privateKey, c.peer.publicKey, 1)
+	ch, err := newChannel(punchedConn, c.privateKey, c.peer.publicKey, 1, 0)
 	if err != nil {
 		return nil, err
 	}
@@ -306,6 +499,18 @@ func reopenChannelWithNewLocalAddr(c *channel) (*channel, error) {
 }
 
 func createTestChannels() (Channel, Channel, error) {
+	provider, consumer, err := createTestChannelsWithCapabilities(0, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	return provider, consumer, nil
+}
+
+func createTestChannelsWithCapabilities(providerCapabilities, consumerCapabilities uint64) (*channel, *channel, error) {
+	return createTestChannelsWithCompatibility(1, providerCapabilities, consumerCapabilities)
+}
+
+func createTestChannelsWithCompatibility(compatibility int, providerCapabilities, consumerCapabilities uint64) (*channel, *channel, error) {
 	ports, err := acquirePorts(2)
 	if err != nil {
 		return nil, nil, err
@@ -332,16 +537,19 @@ func createTestChannels() (Channel, Channel, error) {
 		return nil, nil, err
 	}
 
-	provider, err := newChannel(providerConn, providerPrivateKey, consumerPublicKey, 1)
+	// Each peer learns capabilities of the other one.
+	provider, err := newChannel(providerConn, providerPrivateKey, consumerPublicKey, compatibility, consumerCapabilities)
 	if err != nil {
 		return nil, nil, err
 	}
+	provider.setControlSigning(&identity.SignerFake{}, &identity.VerifierFake{})
 	provider.launchReadSendLoops()
 
-	consumer, err := newChannel(consumerConn, consumerPrivateKey, providerPublicKey, 1)
+	consumer, err := newChannel(consumerConn, consumerPrivateKey, providerPublicKey, compatibility, providerCapabilities)
 	if err != nil {
 		return nil, nil, err
 	}
+	consumer.setControlSigning(&identity.SignerFake{}, &identity.VerifierFake{})
 	consumer.launchReadSendLoops()
 
 	return provider, consumer, nil
@@ -18,7 +18,45 @@
 package compat
 
 // Compatibility level of P2P protocol
-const Compatibility = 2
+const Compatibility = 3
+
+// Capability flags advertised in P2P handshake. Unlike compatibility level
+// they describe optional features which peers may enable independently.
+const (
+	// CapabilityZstd means peer accepts zstd compressed message payloads.
+	CapabilityZstd uint64 = 1 << iota
+	// CapabilitySignedControl means peer signs control messages and rejects unsigned,
+	// stale or replayed control messages. Peers of compatibility level 3 and later
+	// have to sign control messages whether they advertise it or not.
+	CapabilitySignedControl
+	// CapabilityPadding means peer pads its small messages and wants replies padded too.
+	// It is advertised per session only when padding is enabled.
+	CapabilityPadding
+)
+
+// Capabilities is a set of optional features supported by this node.
+const Capabilities = CapabilityZstd | CapabilitySignedControl
+
+// FeatureZstd reports whether peer accepts zstd compressed payloads.
+func FeatureZstd(peerCapabilities uint64) bool {
+	return peerCapabilities&CapabilityZstd != 0
+}
+
+// FeatureSignedControl reports whether peer signs and verifies control messages.
+func FeatureSignedControl(peerCapabilities uint64) bool {
+	return peerCapabilities&CapabilitySignedControl != 0
+}
+
+// FeaturePadding reports whether peer asks for small messages to be padded.
+func FeaturePadding(peerCapabilities uint64) bool {
+	return peerCapabilities&CapabilityPadding != 0
+}
+
+// FeatureSignedControlRequired reports whether peer is at a compatibility level which
+// mandates signed control messages, regardless of the advertised capabilities.
+func FeatureSignedControlRequired(peerCompatibility int) bool {
+	return peerCompatibility >= 3
+}
 
 // FeaturePBP2P reports whether peer supports new wire format
 // for transportMsg envelopes
@@ -0,0 +1,85 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package p2p
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	payloadEncodingRaw  uint32 = 0
+	payloadEncodingZstd uint32 = 1
+
+	// compressMinLen is the payload size from which compression starts to pay off.
+	compressMinLen = 1024
+	// maxDecompressedLen limits memory used by decompression of a single payload.
+	maxDecompressedLen = 8 * maxTransportMsgLen
+)
+
+var (
+	zstdOnce    sync.Once
+	zstdEncoder *zstd.Encoder
+	zstdDecoder *zstd.Decoder
+	zstdErr     error
+)
+
+func initZstd() error {
+	zstdOnce.Do(func() {
+		zstdEncoder, zstdErr = zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedDefault))
+		if zstdErr != nil {
+			return
+		}
+		zstdDecoder, zstdErr = zstd.NewReader(nil, zstd.WithDecoderMaxMemory(maxDecompressedLen))
+	})
+	return zstdErr
+}
+
+// encodePayload compresses large payloads. Raw payload is returned
+// if it is too small or compression does not reduce its size.
+func encodePayload(data []byte) (uint32, []byte) {
+	if len(data) < compressMinLen {
+		return payloadEncodingRaw, data
+	}
+	if err := initZstd(); err != nil {
+		return payloadEncodingRaw, data
+	}
+
+	compressed := zstdEncoder.EncodeAll(data, make([]byte, 0, len(data)/2))
+	if len(compressed) >= len(data) {
+		return payloadEncodingRaw, data
+	}
+	return payloadEncodingZstd, compressed
+}
+
+// decodePayload restores payload according to its encoding.
+func decodePayload(encoding uint32, data []byte) ([]byte, error) {
+	switch encoding {
+	case payloadEncodingRaw:
+		return data, nil
+	case payloadEncodingZstd:
+		if err := initZstd(); err != nil {
+			return nil, err
+		}
+		return zstdDecoder.DecodeAll(data, nil)
+	default:
+		return nil, fmt.Errorf("unknown payload encoding: %d", encoding)
+	}
+}
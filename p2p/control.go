@@ -0,0 +1,170 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package p2p
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	// ErrControlUnsigned indicates that peer rejected control message without signature.
+	ErrControlUnsigned = errors.New("p2p control message is not signed")
+
+	// ErrControlSignatureInvalid indicates that peer rejected control message with invalid signature.
+	ErrControlSignatureInvalid = errors.New("p2p control message signature is invalid")
+
+	// ErrControlStale indicates that peer rejected control message sent too long ago or in the future.
+	ErrControlStale = errors.New("p2p control message is stale")
+
+	// ErrControlReplayed indicates that peer rejected already received control message.
+	ErrControlReplayed = errors.New("p2p control message is replayed")
+)
+
+// controlMaxAge is the maximum difference between control message timestamp and the local clock.
+const controlMaxAge = 2 * time.Minute
+
+// controlTopics are topics of session and payment control messages which peers supporting it must sign.
+var controlTopics = map[string]bool{
+	TopicSessionCreate:         true,
+	TopicSessionAcknowledge:    true,
+	TopicSessionStatus:         true,
+	TopicSessionDestroy:        true,
+	TopicSessionReconcile:      true,
+	TopicSessionKeyRotate:      true,
+	TopicSessionKeyRotateAck:   true,
+	TopicSessionPause:          true,
+	TopicSessionResume:         true,
+	TopicSessionSLABreach:      true,
+	TopicSessionMaintenance:    true,
+	TopicSessionReserve:        true,
+	TopicSessionReservePayment: true,
+	TopicSessionRoam:           true,
+	TopicPaymentMessage:        true,
+	TopicPaymentInvoice:        true,
+}
+
+// ControlMessageCounters holds numbers of rejected control messages by the rejection reason.
+type ControlMessageCounters struct {
+	Unsigned         uint64
+	InvalidSignature uint64
+	Stale            uint64
+	Replayed         uint64
+}
+
+var rejectedControl ControlMessageCounters
+
+// RejectedControlMessages returns numbers of control messages rejected since the node start.
+func RejectedControlMessages() ControlMessageCounters {
+	return ControlMessageCounters{
+		Unsigned:         atomic.LoadUint64(&rejectedControl.Unsigned),
+		InvalidSignature: atomic.LoadUint64(&rejectedControl.InvalidSignature),
+		Stale:            atomic.LoadUint64(&rejectedControl.Stale),
+		Replayed:         atomic.LoadUint64(&rejectedControl.Replayed),
+	}
+}
+
+// countRejectedControl increments counter of the given rejection and returns the wire status code for it.
+func countRejectedControl(err error) uint64 {
+	switch {
+	case errors.Is(err, ErrControlUnsigned):
+		atomic.AddUint64(&rejectedControl.Unsigned, 1)
+		return statusCodeControlUnsignedErr
+	case errors.Is(err, ErrControlSignatureInvalid):
+		atomic.AddUint64(&rejectedControl.InvalidSignature, 1)
+		return statusCodeControlSignatureErr
+	case errors.Is(err, ErrControlStale):
+		atomic.AddUint64(&rejectedControl.Stale, 1)
+		return statusCodeControlStaleErr
+	default:
+		atomic.AddUint64(&rejectedControl.Replayed, 1)
+		return statusCodeControlReplayedErr
+	}
+}
+
+// controlStatusErrors maps wire status codes of rejected control messages to errors.
+var controlStatusErrors = map[uint64]error{
+	statusCodeControlUnsignedErr:  ErrControlUnsigned,
+	statusCodeControlSignatureErr: ErrControlSignatureInvalid,
+	statusCodeControlStaleErr:     ErrControlStale,
+	statusCodeControlReplayedErr:  ErrControlReplayed,
+}
+
+// controlPayload returns bytes covered by control message signature.
+func controlPayload(topic string, nonce uint64, timestamp int64, data []byte) []byte {
+	b := make([]byte, 0, len(topic)+1+16+len(data))
+	b = append(b, topic...)
+	b = append(b, 0)
+	b = binary.BigEndian.AppendUint64(b, nonce)
+	b = binary.BigEndian.AppendUint64(b, uint64(timestamp))
+	return append(b, data...)
+}
+
+func newControlNonce() (uint64, error) {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint64(b[:]), nil
+}
+
+// replayGuard rejects stale control messages and remembers nonces of fresh ones to reject their replays.
+type replayGuard struct {
+	maxAge time.Duration
+	now    func() time.Time
+
+	mu   sync.Mutex
+	seen map[uint64]time.Time
+}
+
+func newReplayGuard(maxAge time.Duration) *replayGuard {
+	return &replayGuard{
+		maxAge: maxAge,
+		now:    time.Now,
+		seen:   make(map[uint64]time.Time),
+	}
+}
+
+// check returns an error if message with given nonce and timestamp is stale or was already seen.
+func (g *replayGuard) check(nonce uint64, timestamp int64) error {
+	now := g.now()
+	sent := time.Unix(0, timestamp)
+	if sent.Before(now.Add(-g.maxAge)) || sent.After(now.Add(g.maxAge)) {
+		return ErrControlStale
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	// Nonces of messages which would be rejected as stale anyway are no longer needed.
+	for n, t := range g.seen {
+		if t.Before(now.Add(-g.maxAge)) {
+			delete(g.seen, n)
+		}
+	}
+
+	if _, ok := g.seen[nonce]; ok {
+		return ErrControlReplayed
+	}
+	g.seen[nonce] = sent
+	return nil
+}
@@ -0,0 +1,69 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package p2p
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplayGuardRejectsStaleAndReplayedMessages(t *testing.T) {
+	now := time.Unix(1000, 0)
+	guard := newReplayGuard(time.Minute)
+	guard.now = func() time.Time { return now }
+
+	assert.NoError(t, guard.check(1, now.UnixNano()))
+	assert.ErrorIs(t, guard.check(1, now.UnixNano()), ErrControlReplayed)
+	assert.NoError(t, guard.check(2, now.Add(-59*time.Second).UnixNano()))
+
+	assert.ErrorIs(t, guard.check(3, now.Add(-61*time.Second).UnixNano()), ErrControlStale)
+	assert.ErrorIs(t, guard.check(3, now.Add(61*time.Second).UnixNano()), ErrControlStale)
+
+	// Seen nonces are forgotten once their messages become stale.
+	now = now.Add(2 * time.Minute)
+	assert.NoError(t, guard.check(4, now.UnixNano()))
+	assert.Len(t, guard.seen, 1)
+	assert.NoError(t, guard.check(1, now.UnixNano()))
+}
+
+func TestControlPayloadCoversAllSignedFields(t *testing.T) {
+	payload := controlPayload("topic", 1, 2, []byte("data"))
+
+	assert.NotEqual(t, payload, controlPayload("topic2", 1, 2, []byte("data")))
+	assert.NotEqual(t, payload, controlPayload("topic", 3, 2, []byte("data")))
+	assert.NotEqual(t, payload, controlPayload("topic", 1, 3, []byte("data")))
+	assert.NotEqual(t, payload, controlPayload("topic", 1, 2, []byte("data2")))
+}
+
+func TestCountRejectedControl(t *testing.T) {
+	before := RejectedControlMessages()
+
+	assert.Equal(t, uint64(statusCodeControlUnsignedErr), countRejectedControl(ErrControlUnsigned))
+	assert.Equal(t, uint64(statusCodeControlSignatureErr), countRejectedControl(ErrControlSignatureInvalid))
+	assert.Equal(t, uint64(statusCodeControlStaleErr), countRejectedControl(ErrControlStale))
+	assert.Equal(t, uint64(statusCodeControlReplayedErr), countRejectedControl(ErrControlReplayed))
+	assert.Equal(t, uint64(statusCodeControlReplayedErr), countRejectedControl(ErrControlReplayed))
+
+	after := RejectedControlMessages()
+	assert.Equal(t, before.Unsigned+1, after.Unsigned)
+	assert.Equal(t, before.InvalidSignature+1, after.InvalidSignature)
+	assert.Equal(t, before.Stale+1, after.Stale)
+	assert.Equal(t, before.Replayed+2, after.Replayed)
+}
@@ -51,8 +51,19 @@ type Dialer interface {
 	Dial(ctx context.Context, consumerID, providerID identity.Identity, serviceType string, contactDef ContactDefinition, tracer *trace.Tracer) (Channel, error)
 }
 
+// DialerOptions holds the consumer side measures against traffic fingerprinting.
+type DialerOptions struct {
+	// RandomizePorts picks local ports from the whole unprivileged range instead of the configured pool.
+	RandomizePorts bool
+	// PadControl pads small messages and asks the provider to pad its replies.
+	PadControl bool
+}
+
 // NewDialer creates new p2p communication dialer which is used on consumer side.
-func NewDialer(broker brokerConnector, signer identity.SignerFactory, verifierFactory identity.VerifierFactory, ipResolver ip.Resolver, portPool port.ServicePortSupplier, eventBus eventbus.EventBus) Dialer {
+func NewDialer(broker brokerConnector, signer identity.SignerFactory, verifierFactory identity.VerifierFactory, ipResolver ip.Resolver, portPool port.ServicePortSupplier, eventBus eventbus.EventBus, opts DialerOptions) Dialer {
+	if opts.RandomizePorts {
+		portPool = port.NewFixedRangePool(port.UnprivilegedRange)
+	}
 	return &dialer{
 		broker:          broker,
 		ipResolver:      ipResolver,
@@ -61,6 +72,7 @@ func NewDialer(broker brokerConnector, signer identity.SignerFactory, verifierFa
 		portPool:        portPool,
 		consumerPinger:  traversal.NewPinger(traversal.DefaultPingConfig(), eventbus.New()),
 		eventBus:        eventBus,
+		padControl:      opts.PadControl,
 	}
 }
 
@@ -73,6 +85,7 @@ type dialer struct {
 	verifierFactory identity.VerifierFactory
 	ipResolver      ip.Resolver
 	eventBus        eventbus.EventBus
+	padControl      bool
 }
 
 // Dial exchanges p2p configuration via broker, performs NAT pinging if needed
@@ -150,13 +163,15 @@ func (m *dialer) Dial(ctx context.Context, consumerID, providerID identity.Ident
 		return nil, errors.New("timeout while performing configuration exchange")
 	}
 
-	channel, err := newChannel(conn1, config.privateKey, config.peerPubKey, config.compatibility)
+	channel, err := newChannel(conn1, config.privateKey, config.peerPubKey, config.compatibility, config.capabilities)
 	if err != nil {
 		return nil, fmt.Errorf("could not create p2p channel during dial: %w", err)
 	}
 	channel.setTracer(tracer)
 	channel.setServiceConn(conn2)
 	channel.setPeerID(providerID)
+	channel.setControlSigning(m.signer(consumerID), m.verifierFactory(providerID))
+	channel.setPadding(m.padControl)
 	channel.launchReadSendLoops()
 	config.tracer.EndStage(traceAck)
 
@@ -227,6 +242,7 @@ func (m *dialer) startConfigExchange(config *p2pConnectConfig, ctx context.Conte
 
 	config.publicKey = pubKey
 	config.compatibility = int(peerConnConfig.Compatibility)
+	config.capabilities = peerConnConfig.Capabilities
 	config.privateKey = privateKey
 	config.peerPubKey = peerPubKey
 	config.peerPublicIP = peerConnConfig.PublicIP
@@ -242,6 +258,7 @@ func (m *dialer) ackConfigExchange(config *p2pConnectConfig, ctx context.Context
 		PublicIP:      config.publicIP,
 		Ports:         intToInt32Slice(config.publicPorts),
 		Compatibility: compat.Compatibility,
+		Capabilities:  m.capabilities(),
 	}
 	connConfigCiphertext, err := encryptConnConfigMsg(connConfig, config.privateKey, config.peerPubKey)
 	if err != nil {
@@ -270,6 +287,14 @@ func (m *dialer) ackConfigExchange(config *p2pConnectConfig, ctx context.Context
 	return nil
 }
 
+// capabilities returns optional features advertised to provider for this session.
+func (m *dialer) capabilities() uint64 {
+	if m.padControl {
+		return compat.Capabilities | compat.CapabilityPadding
+	}
+	return compat.Capabilities
+}
+
 func (m *dialer) prepareLocalPorts(config *p2pConnectConfig) (string, []int, error) {
 	trace := config.tracer.StartStage("Consumer P2P exchange (ports)")
 	defer config.tracer.EndStage(trace)
@@ -37,6 +37,7 @@ import (
 	"github.com/mysteriumnetwork/node/p2p/compat"
 	"github.com/mysteriumnetwork/node/p2p/nat"
 	"github.com/mysteriumnetwork/node/pb"
+	"github.com/mysteriumnetwork/node/router"
 	"github.com/mysteriumnetwork/node/trace"
 )
 
@@ -81,6 +82,7 @@ type p2pConnectConfig struct {
 	publicIP         string
 	peerPublicIP     string
 	compatibility    int
+	capabilities     uint64
 	peerPorts        []int
 	localPorts       []int
 	publicPorts      []int
@@ -189,11 +191,19 @@ func (m *listener) Listen(providerID identity.Identity, serviceType string, chan
 				log.Err(err).Msg("Could not create UDP conn for service")
 				return
 			}
+			if err := router.ProtectUDPConn(conn1); err != nil {
+				log.Err(err).Msg("Could not protect UDP conn for p2p channel")
+				return
+			}
+			if err := router.ProtectUDPConn(conn2); err != nil {
+				log.Err(err).Msg("Could not protect UDP conn for service")
+				return
+			}
 			config.tracer.EndStage(traceDial)
 		}
 
 		traceAck := config.tracer.StartStage("Provider P2P dial ack")
-		channel, err := newChannel(conn1, config.privateKey, config.peerPubKey, config.compatibility)
+		channel, err := newChannel(conn1, config.privateKey, config.peerPubKey, config.compatibility, config.capabilities)
 		if err != nil {
 			log.Err(err).Msg("Could not create channel")
 			return
@@ -201,6 +211,8 @@ func (m *listener) Listen(providerID identity.Identity, serviceType string, chan
 		channel.setTracer(config.tracer)
 		channel.setServiceConn(conn2)
 		channel.setPeerID(config.peerID)
+		channel.setControlSigning(m.signer(providerID), identity.NewVerifierIdentity(config.peerID))
+		channel.setPadding(compat.FeaturePadding(config.capabilities))
 		channel.setUpnpPortsRelease(config.upnpPortsRelease)
 
 		channelHandlers(channel)
@@ -283,6 +295,7 @@ func (m *listener) providerStartConfigExchange(providerID identity.Identity, msg
 		PublicIP:      publicIP,
 		Ports:         intToInt32Slice(p2pConnConfig.publicPorts),
 		Compatibility: compat.Compatibility,
+		Capabilities:  compat.Capabilities,
 	}
 	configCiphertext, err := encryptConnConfigMsg(&config, privateKey, peerPubKey)
 	if err != nil {
@@ -370,6 +383,7 @@ func (m *listener) providerAckConfigExchange(msg *nats_lib.Msg) (*p2pConnectConf
 		peerPublicIP:     peerConfig.PublicIP,
 		peerPorts:        int32ToIntSlice(peerConfig.Ports),
 		compatibility:    int(peerConfig.Compatibility),
+		capabilities:     peerConfig.Capabilities,
 		localPorts:       config.localPorts,
 		publicKey:        config.publicKey,
 		privateKey:       config.privateKey,
@@ -34,6 +34,26 @@ const (
 	TopicSessionStatus = "p2p-session-connectivity-status"
 	// TopicSessionDestroy is a session destroy endpoint for p2p communication.
 	TopicSessionDestroy = "p2p-session-destroy"
+	// TopicSessionReconcile is a session accounting reconciliation endpoint for p2p communication.
+	TopicSessionReconcile = "p2p-session-reconcile"
+	// TopicSessionKeyRotate is a tunnel key re-negotiation endpoint for p2p communication.
+	TopicSessionKeyRotate = "p2p-session-key-rotate"
+	// TopicSessionKeyRotateAck is a tunnel key re-negotiation acknowledgement endpoint for p2p communication.
+	TopicSessionKeyRotateAck = "p2p-session-key-rotate-ack"
+	// TopicSessionPause is a session data flow and invoicing suspension endpoint for p2p communication.
+	TopicSessionPause = "p2p-session-pause"
+	// TopicSessionResume is a paused session resumption endpoint for p2p communication.
+	TopicSessionResume = "p2p-session-resume"
+	// TopicSessionSLABreach is a session SLA breach refund claim endpoint for p2p communication.
+	TopicSessionSLABreach = "p2p-session-sla-breach"
+	// TopicSessionMaintenance is a provider maintenance announcement endpoint for p2p communication.
+	TopicSessionMaintenance = "p2p-session-maintenance"
+	// TopicSessionReserve is a capacity reservation endpoint for p2p communication.
+	TopicSessionReserve = "p2p-session-reserve"
+	// TopicSessionReservePayment is a reservation fee payment endpoint for p2p communication.
+	TopicSessionReservePayment = "p2p-session-reserve-payment"
+	// TopicSessionRoam is a consumer address change confirmation endpoint for p2p communication.
+	TopicSessionRoam = "p2p-session-roam"
 
 	// TopicPaymentMessage is a payment messages endpoint for p2p communication.
 	TopicPaymentMessage = "p2p-payment-message"
@@ -44,6 +64,8 @@ const (
 // Message represent message with data bytes.
 type Message struct {
 	Data []byte
+	// Nonce of the signed control message, 0 if the peer does not sign control messages.
+	Nonce uint64
 }
 
 // UnmarshalProto is convenient helper to unmarshal message data into strongly typed proto message.
@@ -68,6 +90,13 @@ const (
 	statusCodePublicErr          = 2
 	statusCodeInternalErr        = 3
 	statusCodeHandlerNotFoundErr = 4
+
+	statusCodeControlUnsignedErr  = 5
+	statusCodeControlSignatureErr = 6
+	statusCodeControlStaleErr     = 7
+	statusCodeControlReplayedErr  = 8
+
+	statusCodeBusyErr = 9
 )
 
 // transportMsg is internal structure for sending and receiving messages.
@@ -80,6 +109,11 @@ type transportMsg struct {
 
 	// Data field.
 	data []byte
+
+	// Signature fields of control messages.
+	nonce     uint64
+	timestamp int64
+	signature []byte
 }
 
 func (m *transportMsg) readFrom(conn wireReader) error {
@@ -20,6 +20,7 @@ package p2p
 import (
 	"bytes"
 	"crypto/rand"
+	"encoding/binary"
 	"testing"
 )
 
@@ -136,3 +137,71 @@ func TestTransportMessagePiping(t *testing.T) {
 	}
 
 }
+
+func TestTransportMessageCompression(t *testing.T) {
+	var out bytes.Buffer
+	conn := newProtobufWireWriter(&out)
+	conn.compress = true
+	conn2 := newProtobufWireReader(&out)
+
+	data := bytes.Repeat([]byte("proposal"), 4096)
+	msg := transportMsg{
+		topic: "test",
+		data:  data,
+	}
+	if err := msg.writeTo(conn); err != nil {
+		t.Fatalf("Can't write data into conn: %v", err)
+	}
+	if out.Len() >= len(data) {
+		t.Fatalf("Payload wasn't compressed: %d >= %d", out.Len(), len(data))
+	}
+
+	var msg2 transportMsg
+	if err := msg2.readFrom(conn2); err != nil {
+		t.Fatalf("Can't read data from conn2: %v", err)
+	}
+	if !bytes.Equal(data, msg2.data) {
+		t.Fatal("Data wasn't properly recovered")
+	}
+}
+
+func TestTransportMessagePadding(t *testing.T) {
+	var out bytes.Buffer
+	conn := newProtobufWireWriter(&out)
+	conn.pad = true
+	conn2 := newProtobufWireReader(&out)
+
+	for _, size := range []int{0, 1, 100, 126, 127, 200} {
+		out.Reset()
+		data := bytes.Repeat([]byte("k"), size)
+		msg := transportMsg{topic: "p2p-keepalive", data: data}
+		if err := msg.writeTo(conn); err != nil {
+			t.Fatalf("Can't write data into conn: %v", err)
+		}
+		msgLen, err := binary.ReadUvarint(bytes.NewReader(out.Bytes()))
+		if err != nil {
+			t.Fatalf("Can't read message length: %v", err)
+		}
+		if msgLen < padMinLen-1 || msgLen > padMinLen {
+			t.Fatalf("Message with %d bytes of data wasn't padded: %d", size, msgLen)
+		}
+
+		var msg2 transportMsg
+		if err := msg2.readFrom(conn2); err != nil {
+			t.Fatalf("Can't read data from conn2: %v", err)
+		}
+		if !bytes.Equal(data, msg2.data) {
+			t.Fatal("Data wasn't properly recovered")
+		}
+	}
+
+	out.Reset()
+	data := bytes.Repeat([]byte("k"), 2*padMinLen)
+	msg := transportMsg{topic: "test", data: data}
+	if err := msg.writeTo(conn); err != nil {
+		t.Fatalf("Can't write data into conn: %v", err)
+	}
+	if out.Len() > len(data)+32 {
+		t.Fatalf("Large message was padded: %d", out.Len())
+	}
+}
@@ -0,0 +1,197 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package p2p
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var errMuxStopped = errors.New("stream mux stopped")
+
+// StreamID identifies a logical stream multiplexed over a single p2p channel.
+type StreamID uint8
+
+const (
+	// StreamControl carries session lifecycle and keep alive messages.
+	StreamControl StreamID = iota
+	// StreamPayment carries payment messages and invoices.
+	StreamPayment
+	// StreamDiagnostics carries connectivity statuses and other diagnostic messages.
+	StreamDiagnostics
+	// StreamBulk carries large transfers which should not delay other streams.
+	StreamBulk
+
+	streamCount = int(StreamBulk) + 1
+)
+
+// String returns stream name.
+func (id StreamID) String() string {
+	switch id {
+	case StreamControl:
+		return "control"
+	case StreamPayment:
+		return "payment"
+	case StreamDiagnostics:
+		return "diagnostics"
+	case StreamBulk:
+		return "bulk"
+	}
+	return fmt.Sprintf("stream-%d", id)
+}
+
+// StreamConfig holds flow control settings of a single logical stream.
+type StreamConfig struct {
+	// Window is the max number of requests waiting for the peer reply.
+	Window int
+	// QueueSize is the max number of messages waiting to be written to the transport.
+	QueueSize int
+}
+
+// DefaultStreamConfigs returns flow control settings used for every stream by default.
+func DefaultStreamConfigs() map[StreamID]StreamConfig {
+	return map[StreamID]StreamConfig{
+		StreamControl:     {Window: 32, QueueSize: 100},
+		StreamPayment:     {Window: 16, QueueSize: 50},
+		StreamDiagnostics: {Window: 8, QueueSize: 20},
+		StreamBulk:        {Window: 4, QueueSize: 10},
+	}
+}
+
+// defaultTopicStreams assigns topics to logical streams. Topics are sent over the control stream
+// unless assigned otherwise. Both peers must use the same assignment for replies to be
+// scheduled on the same stream as requests.
+func defaultTopicStreams() map[string]StreamID {
+	return map[string]StreamID{
+		TopicPaymentMessage: StreamPayment,
+		TopicPaymentInvoice: StreamPayment,
+		TopicSessionStatus:  StreamDiagnostics,
+	}
+}
+
+type muxStream struct {
+	queue  chan *transportMsg
+	window chan struct{}
+}
+
+// streamMux schedules messages of logical streams to a single transport
+// in a round robin manner so that busy stream could not starve the others.
+type streamMux struct {
+	streams [streamCount]*muxStream
+	ready   chan struct{}
+	stop    <-chan struct{}
+	next    int
+
+	topicsMu sync.RWMutex
+	topics   map[string]StreamID
+}
+
+func newStreamMux(configs map[StreamID]StreamConfig, stop <-chan struct{}) *streamMux {
+	m := &streamMux{
+		ready:  make(chan struct{}, 1),
+		stop:   stop,
+		topics: defaultTopicStreams(),
+	}
+	for i := range m.streams {
+		cfg := configs[StreamID(i)]
+		if cfg.Window <= 0 {
+			cfg.Window = 1
+		}
+		if cfg.QueueSize <= 0 {
+			cfg.QueueSize = 1
+		}
+		m.streams[i] = &muxStream{
+			queue:  make(chan *transportMsg, cfg.QueueSize),
+			window: make(chan struct{}, cfg.Window),
+		}
+	}
+	return m
+}
+
+// assignTopic sends messages of the given topic over the logical stream.
+func (m *streamMux) assignTopic(topic string, id StreamID) {
+	m.topicsMu.Lock()
+	defer m.topicsMu.Unlock()
+
+	m.topics[topic] = id
+}
+
+// topicStream returns the logical stream messages of the given topic are sent over.
+func (m *streamMux) topicStream(topic string) StreamID {
+	m.topicsMu.RLock()
+	defer m.topicsMu.RUnlock()
+
+	return m.topics[topic]
+}
+
+// acquire reserves a slot in the stream window for the request waiting for the reply.
+func (m *streamMux) acquire(ctx context.Context, id StreamID) error {
+	select {
+	case m.streams[id].window <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-m.stop:
+		return errMuxStopped
+	}
+}
+
+// release frees the slot reserved by acquire.
+func (m *streamMux) release(id StreamID) {
+	<-m.streams[id].window
+}
+
+// enqueue puts the message to the stream queue blocking while the queue is full.
+func (m *streamMux) enqueue(ctx context.Context, id StreamID, msg *transportMsg) error {
+	select {
+	case m.streams[id].queue <- msg:
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-m.stop:
+		return errMuxStopped
+	}
+
+	select {
+	case m.ready <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// dequeue returns the next message to be written to the transport.
+func (m *streamMux) dequeue() (*transportMsg, bool) {
+	for {
+		for i := 0; i < streamCount; i++ {
+			idx := (m.next + i) % streamCount
+			select {
+			case msg := <-m.streams[idx].queue:
+				m.next = (idx + 1) % streamCount
+				return msg, true
+			default:
+			}
+		}
+
+		select {
+		case <-m.ready:
+		case <-m.stop:
+			return nil, false
+		}
+	}
+}
@@ -0,0 +1,93 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package p2p
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamMux_RoundRobin(t *testing.T) {
+	stop := make(chan struct{})
+	defer close(stop)
+	mux := newStreamMux(DefaultStreamConfigs(), stop)
+
+	ctx := context.Background()
+	for i := uint64(1); i <= 3; i++ {
+		require.NoError(t, mux.enqueue(ctx, StreamBulk, &transportMsg{id: i}))
+	}
+	require.NoError(t, mux.enqueue(ctx, StreamPayment, &transportMsg{id: 10}))
+
+	var order []uint64
+	for i := 0; i < 4; i++ {
+		msg, ok := mux.dequeue()
+		require.True(t, ok)
+		order = append(order, msg.id)
+	}
+	assert.Equal(t, []uint64{10, 1, 2, 3}, order)
+}
+
+func TestStreamMux_WindowIsPerStream(t *testing.T) {
+	stop := make(chan struct{})
+	defer close(stop)
+	mux := newStreamMux(map[StreamID]StreamConfig{StreamBulk: {Window: 1, QueueSize: 1}}, stop)
+
+	require.NoError(t, mux.acquire(context.Background(), StreamBulk))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, mux.acquire(ctx, StreamBulk), context.DeadlineExceeded)
+	assert.NoError(t, mux.acquire(context.Background(), StreamControl))
+
+	mux.release(StreamBulk)
+	assert.NoError(t, mux.acquire(context.Background(), StreamBulk))
+}
+
+func TestStreamMux_Stop(t *testing.T) {
+	stop := make(chan struct{})
+	mux := newStreamMux(DefaultStreamConfigs(), stop)
+	close(stop)
+
+	_, ok := mux.dequeue()
+	assert.False(t, ok)
+}
+
+func TestStreamMux_TopicStream(t *testing.T) {
+	mux := newStreamMux(DefaultStreamConfigs(), make(chan struct{}))
+	assert.Equal(t, StreamPayment, mux.topicStream(TopicPaymentInvoice))
+	assert.Equal(t, StreamControl, mux.topicStream(TopicSessionCreate))
+
+	mux.assignTopic("test-bulk", StreamBulk)
+	assert.Equal(t, StreamBulk, mux.topicStream("test-bulk"))
+
+	other := newStreamMux(DefaultStreamConfigs(), make(chan struct{}))
+	assert.Equal(t, StreamControl, other.topicStream("test-bulk"))
+}
+
+func TestStreamMux_EnqueueGivesUpWhenQueueIsFull(t *testing.T) {
+	mux := newStreamMux(map[StreamID]StreamConfig{StreamControl: {Window: 1, QueueSize: 1}}, make(chan struct{}))
+	require.NoError(t, mux.enqueue(context.Background(), StreamControl, &transportMsg{id: 1}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, mux.enqueue(ctx, StreamControl, &transportMsg{id: 2}), context.DeadlineExceeded)
+}
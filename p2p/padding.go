@@ -0,0 +1,43 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package p2p
+
+import (
+	"google.golang.org/protobuf/proto"
+
+	"github.com/mysteriumnetwork/node/pb"
+)
+
+// padMinLen is the envelope size small messages are padded up to, so keepalives,
+// pings and other control messages can't be told apart by their length.
+const padMinLen = 256
+
+// padEnvelope fills envelope up to padMinLen. Envelopes which are already
+// large enough are left as is.
+func padEnvelope(msg *pb.P2PChannelEnvelope) {
+	size := proto.Size(msg)
+	if size >= padMinLen {
+		return
+	}
+
+	// Padding field adds a tag byte and a varint length on top of its data.
+	msg.Padding = make([]byte, padMinLen-size-2)
+	if over := proto.Size(msg) - padMinLen; over > 0 {
+		msg.Padding = msg.Padding[:len(msg.Padding)-over]
+	}
+}
@@ -30,6 +30,7 @@ import (
 	"github.com/mysteriumnetwork/node/eventbus"
 	"github.com/mysteriumnetwork/node/identity"
 	"github.com/mysteriumnetwork/node/requests/resolver"
+	"github.com/mysteriumnetwork/node/router"
 )
 
 // AppTopicSTUN represents the STUN detection topic.
@@ -114,6 +115,12 @@ func multiServerSTUN(servers []string, p, limit int) (respPort []int) {
 		return nil
 	}
 
+	if err := router.ProtectUDPConn(conn); err != nil {
+		log.Error().Err(err).Msg("failed to protect UDP connection for STUN server")
+		conn.Close()
+		return nil
+	}
+
 	if err := conn.SetDeadline(time.Now().Add(2 * time.Second)); err != nil {
 		log.Error().Err(err).Msg("failed to set connection deadline for STUN server")
 		return nil
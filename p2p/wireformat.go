@@ -67,10 +67,12 @@ func newCompatibleWireReader(c io.Reader, peerCompatibility int) wireReader {
 	return newTextWireReader(c)
 }
 
-func newCompatibleWireWriter(c io.Writer, peerCompatibility int) wireWriter {
+func newCompatibleWireWriter(c io.Writer, peerCompatibility int, peerCapabilities uint64) wireWriter {
 	if compat.FeaturePBP2P(peerCompatibility) {
 		log.Debug().Msg("Using protobufWireWriter")
-		return newProtobufWireWriter(c)
+		w := newProtobufWireWriter(c)
+		w.compress = compat.FeatureZstd(peerCapabilities)
+		return w
 	}
 	log.Debug().Msg("Using textWireWriter")
 	return newTextWireWriter(c)
@@ -175,13 +177,23 @@ func (r *protobufWireReader) readMsg(m *transportMsg) error {
 	m.statusCode = pbMsg.StatusCode
 	m.topic = pbMsg.Topic
 	m.msg = pbMsg.Msg
-	m.data = pbMsg.Data
+	m.nonce = pbMsg.Nonce
+	m.timestamp = pbMsg.Timestamp
+	m.signature = pbMsg.Signature
+	m.data, err = decodePayload(pbMsg.Encoding, pbMsg.Data)
+	if err != nil {
+		return fmt.Errorf("could not decode %q payload: %w", pbMsg.Topic, err)
+	}
 
 	return nil
 }
 
 type protobufWireWriter struct {
 	w *bufio.Writer
+	// compress enables compression of large payloads, peer must advertise support for it.
+	compress bool
+	// pad fills small envelopes up to padMinLen, peers without support ignore the padding.
+	pad bool
 }
 
 func newProtobufWireWriter(c io.Writer) *protobufWireWriter {
@@ -197,6 +209,15 @@ func (w *protobufWireWriter) writeMsg(m *transportMsg) error {
 		Topic:      m.topic,
 		Msg:        m.msg,
 		Data:       m.data,
+		Nonce:      m.nonce,
+		Timestamp:  m.timestamp,
+		Signature:  m.signature,
+	}
+	if w.compress {
+		pbMsg.Encoding, pbMsg.Data = encodePayload(m.data)
+	}
+	if w.pad {
+		padEnvelope(&pbMsg)
 	}
 
 	msgBytes, err := proto.Marshal(&pbMsg)
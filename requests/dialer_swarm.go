@@ -61,21 +61,25 @@ func NewDialerSwarm(srcIP string, dnsHeadstart time.Duration) *DialerSwarm {
 					return fmt.Errorf("ipv6 not supported")
 				}
 
-				err = c.Control(func(f uintptr) {
-					log.Trace().Msgf("Protecting connection to: %s (%s)", address, net)
-
-					fd := int(f)
-					err := router.Protect(fd)
-					if err != nil {
-						log.Error().Err(err).Msgf("Failed to protect connection to: %s (%s)", address, net)
-					}
-				})
-				return err
+				return protectControl(net, address, c)
 			},
 		})).DialContext,
 	}
 }
 
+// protectControl passes the socket to the router for protection before it is connected.
+func protectControl(net, address string, c syscall.RawConn) error {
+	return c.Control(func(f uintptr) {
+		log.Trace().Msgf("Protecting connection to: %s (%s)", address, net)
+
+		fd := int(f)
+		err := router.Protect(fd)
+		if err != nil {
+			log.Error().Err(err).Msgf("Failed to protect connection to: %s (%s)", address, net)
+		}
+	})
+}
+
 // DialContext connects to the address on the named network using the provided context.
 func (ds *DialerSwarm) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
 	if ds.ResolveContext != nil {
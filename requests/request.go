@@ -91,7 +91,16 @@ func NewSignedPutRequest(apiURI, path string, requestBody interface{}, signer id
 	return NewSignedRequest(http.MethodPut, apiURI, path, requestBody, signer)
 }
 
+// JSONAppender is implemented by request bodies which encode themselves
+// without the reflection of encoding/json.
+type JSONAppender interface {
+	AppendJSON(dst []byte) []byte
+}
+
 func encodeToJSON(value interface{}) ([]byte, error) {
+	if appender, ok := value.(JSONAppender); ok {
+		return appender.AppendJSON(nil), nil
+	}
 	return json.Marshal(value)
 }
 
@@ -18,6 +18,7 @@
 package requests
 
 import (
+	"net"
 	"net/http"
 	"time"
 )
@@ -37,3 +38,34 @@ func NewTransport(dialFunc DialContext) *http.Transport {
 		DisableKeepAlives:     true,
 	}
 }
+
+// BindDefaultTransport makes connections of the default HTTP transport, used by third party clients
+// (i.e. blockchain RPC), originate from the given source IP and get protected as the node's own ones.
+func BindDefaultTransport(srcIP string) {
+	transport, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		return
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   protectControl,
+	}
+	if ip := net.ParseIP(srcIP); ip != nil && !ip.IsUnspecified() {
+		dialer.LocalAddr = &net.TCPAddr{IP: ip}
+	}
+	transport.DialContext = dialer.DialContext
+}
+
+// ProxyDefaultTransport makes connections of the default HTTP transport go through the upstream proxy.
+func ProxyDefaultTransport(upstream *UpstreamProxy) {
+	transport, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		return
+	}
+
+	// Proxy from environment would be chained after the upstream one.
+	transport.Proxy = nil
+	transport.DialContext = upstream.Wrap(transport.DialContext)
+}
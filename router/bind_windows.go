@@ -0,0 +1,43 @@
+//go:build windows
+
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package router
+
+import (
+	"encoding/binary"
+	"net"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// ipUnicastIf is the IP_UNICAST_IF and IPV6_UNICAST_IF socket option.
+const ipUnicastIf = 31
+
+func bindToInterface(fd int, iface *net.Interface) error {
+	// IPv4 option expects the interface index in network byte order.
+	var index [4]byte
+	binary.BigEndian.PutUint32(index[:], uint32(iface.Index))
+	ipv4Index := *(*uint32)(unsafe.Pointer(&index[0]))
+
+	if err := windows.SetsockoptInt(windows.Handle(fd), windows.IPPROTO_IP, ipUnicastIf, int(ipv4Index)); err == nil {
+		return nil
+	}
+	return windows.SetsockoptInt(windows.Handle(fd), windows.IPPROTO_IPV6, ipUnicastIf, iface.Index)
+}
@@ -18,6 +18,7 @@
 package router
 
 import (
+	"fmt"
 	"net"
 	"sync"
 )
@@ -35,6 +36,20 @@ func SetProtectFunc(f func(fd int) error) {
 	protect = f
 }
 
+// BindInterface pins sockets protected by the node to the given network interface,
+// so that its own traffic leaves the host through it regardless of the routing table.
+func BindInterface(name string) error {
+	iface, err := net.InterfaceByName(name)
+	if err != nil {
+		return fmt.Errorf("could not find network interface %q: %w", name, err)
+	}
+
+	SetProtectFunc(func(fd int) error {
+		return bindToInterface(fd, iface)
+	})
+	return nil
+}
+
 // Protect protects provided connection from going through the tunnel.
 func Protect(fd int) error {
 	mu.RLock()
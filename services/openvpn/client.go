@@ -71,9 +71,13 @@ func NewClient(openvpnBinary, scriptDir, runtimeDir string,
 		signer := signerFactory(options.ConsumerID)
 
 		stateMiddleware := newStateMiddleware(stateCh)
-		authMiddleware := newAuthMiddleware(options.SessionID, signer)
 		byteCountMiddleware := openvpn_bytescount.NewMiddleware(client.OnStats, config.GetDuration(config.FlagStatsReportInterval))
-		proc := openvpn.CreateNewProcess(openvpnBinary, vpnClientConfig.GenericConfig, stateMiddleware, byteCountMiddleware, authMiddleware)
+		middlewares := []management.Middleware{stateMiddleware, byteCountMiddleware}
+		// Servers imported from outside of the network authenticate clients by certificate only.
+		if sessionConfig.ClientCertificate == "" {
+			middlewares = append(middlewares, newAuthMiddleware(options.SessionID, signer))
+		}
+		proc := openvpn.CreateNewProcess(openvpnBinary, vpnClientConfig.GenericConfig, middlewares...)
 		return proc, vpnClientConfig, nil
 	}
 
@@ -180,6 +184,9 @@ type VPNConfig struct {
 	RemoteProtocol  string `json:"protocol"`
 	TLSPresharedKey string `json:"TLSPresharedKey"`
 	CACertificate   string `json:"CACertificate"`
+	// ClientCertificate and ClientKey are set only for servers imported from outside of the network.
+	ClientCertificate string `json:"ClientCertificate,omitempty"`
+	ClientKey         string `json:"ClientKey,omitempty"`
 }
 
 func newAuthMiddleware(sessionID session.ID, signer identity.Signer) management.Middleware {
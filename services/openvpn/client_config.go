@@ -40,12 +40,22 @@ func (c *ClientConfig) SetClientMode(serverIP string, serverPort, localPort int)
 	c.SetPort(serverPort)
 	c.SetParam("lport", strconv.Itoa(localPort))
 	c.SetFlag("float")
+}
+
+// SetProviderAuth makes client verify provider certificate and authenticate with session credentials
+func (c *ClientConfig) SetProviderAuth() {
 	// more on this: https://www.v13.gr/blog/?p=386
 	c.SetParam("remote-cert-ku", "84")
 	c.SetFlag("auth-user-pass")
 	c.SetFlag("management-query-passwords")
 }
 
+// SetCertificateAuth makes client verify server certificate and authenticate with the given client certificate
+func (c *ClientConfig) SetCertificateAuth(certificate, key string) {
+	c.SetParam("remote-cert-tls", "server")
+	c.SetTLSPrivatePubKeys(certificate, key)
+}
+
 // SetProtocol specifies openvpn connection protocol type (tcp or udp)
 func (c *ClientConfig) SetProtocol(protocol string) {
 	if protocol == "tcp" {
@@ -112,6 +122,11 @@ func NewClientConfigFromSession(vpnConfig VPNConfig, scriptDir string, runtimeDi
 	clientFileConfig.VpnConfig = &vpnConfig
 	clientFileConfig.SetReconnectRetry(2)
 	clientFileConfig.SetClientMode(vpnConfig.RemoteIP, remotePort, localPort)
+	if vpnConfig.ClientCertificate != "" {
+		clientFileConfig.SetCertificateAuth(vpnConfig.ClientCertificate, vpnConfig.ClientKey)
+	} else {
+		clientFileConfig.SetProviderAuth()
+	}
 	clientFileConfig.SetProtocol(vpnConfig.RemoteProtocol)
 	clientFileConfig.SetTLSCACertificate(vpnConfig.CACertificate)
 	clientFileConfig.SetTLSCrypt(vpnConfig.TLSPresharedKey)
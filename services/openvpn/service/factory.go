@@ -41,6 +41,7 @@ func NewManager(nodeOptions node.Options,
 	portPool port.ServicePortSupplier,
 	bus eventbus.EventBus,
 	trafficFirewall firewall.IncomingTrafficFirewall,
+	statsRecorder statsRecorder,
 ) *Manager {
 	return &Manager{
 		nodeOptions:     nodeOptions,
@@ -49,6 +50,7 @@ func NewManager(nodeOptions node.Options,
 		ports:           portPool,
 		bus:             bus,
 		trafficFirewall: trafficFirewall,
+		statsRecorder:   statsRecorder,
 		country:         country,
 		ipResolver:      ipResolver,
 
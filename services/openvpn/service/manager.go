@@ -57,6 +57,7 @@ type Manager struct {
 	dnsProxy        *dns.Proxy
 	bus             eventbus.EventBus
 	trafficFirewall firewall.IncomingTrafficFirewall
+	statsRecorder   statsRecorder
 	vpnNetwork      net.IPNet
 	vpnServerPort   int
 	openvpnProcess  openvpn.Process
@@ -145,7 +146,7 @@ func (m *Manager) Serve(instance *service.Instance) (err error) {
 		return fmt.Errorf("failed to setup NAT/firewall rules: %w", err)
 	}
 
-	s := shaper.New(m.bus)
+	s := shaper.NewWithLimits(m.bus, instance.Shaping)
 	err = s.Start(m.openvpnProcess.DeviceName())
 	if err != nil {
 		log.Error().Err(err).Msg("Could not start traffic shaper")
@@ -244,7 +245,7 @@ func (m *Manager) startServer() error {
 				close(stateChannel)
 			}
 		}),
-		newStatsPublisher(m.openvpnClients, m.bus, 1),
+		newStatsPublisher(m.openvpnClients, m.statsRecorder, 1),
 	)
 	if err := m.openvpnProcess.Start(); err != nil {
 		return err
@@ -19,16 +19,18 @@ package service
 
 import (
 	"github.com/mysteriumnetwork/go-openvpn/openvpn/middlewares/server/bytecount"
-	"github.com/mysteriumnetwork/node/eventbus"
-	"github.com/mysteriumnetwork/node/session/event"
 	"github.com/rs/zerolog/log"
 )
 
-func newStatsPublisher(clientMap *clientMap, bus eventbus.Publisher, frequencySeconds int) *statsPublisher {
+type statsRecorder interface {
+	Update(sessionID string, up, down uint64)
+}
+
+func newStatsPublisher(clientMap *clientMap, recorder statsRecorder, frequencySeconds int) *statsPublisher {
 	sb := new(statsPublisher)
 	sb.Middleware = bytecount.NewMiddleware(sb.handleStatsEvent, frequencySeconds)
 	sb.clientMap = clientMap
-	sb.bus = bus
+	sb.recorder = recorder
 	return sb
 }
 
@@ -36,7 +38,7 @@ type statsPublisher struct {
 	*bytecount.Middleware
 
 	clientMap *clientMap
-	bus       eventbus.Publisher
+	recorder  statsRecorder
 }
 
 func (sb *statsPublisher) handleStatsEvent(clientStats bytecount.SessionByteCount) {
@@ -46,9 +48,5 @@ func (sb *statsPublisher) handleStatsEvent(clientStats bytecount.SessionByteCoun
 		return
 	}
 
-	sb.bus.Publish(event.AppTopicDataTransferred, event.AppEventDataTransferred{
-		ID:   string(session),
-		Up:   clientStats.BytesOut,
-		Down: clientStats.BytesIn,
-	})
+	sb.recorder.Update(string(session), clientStats.BytesOut, clientStats.BytesIn)
 }
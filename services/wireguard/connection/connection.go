@@ -70,7 +70,9 @@ type Connection struct {
 	stateCh  chan connectionstate.State
 
 	ports               []int
+	keyLock             sync.Mutex
 	privateKey          string
+	rotationPrivateKey  string
 	ipResolver          ip.Resolver
 	connectionEndpoint  wg.ConnectionEndpoint
 	removeAllowedIPRule func()
@@ -80,6 +82,7 @@ type Connection struct {
 }
 
 var _ connection.Connection = &Connection{}
+var _ connection.KeyRotator = &Connection{}
 
 // State returns connection state channel.
 func (c *Connection) State() <-chan connectionstate.State {
@@ -96,6 +99,7 @@ func (c *Connection) Statistics() (connectionstate.Statistics, error) {
 		At:            time.Now(),
 		BytesSent:     stats.BytesSent,
 		BytesReceived: stats.BytesReceived,
+		Categories:    stats.Categories,
 	}, nil
 }
 
@@ -114,6 +118,12 @@ func (c *Connection) start(ctx context.Context, start startConn, options connect
 	if err = json.Unmarshal(options.SessionConfig, &config); err != nil {
 		return errors.Wrap(err, "failed to unmarshal connection config")
 	}
+	config.KeepAlive = options.KeepAliveInterval
+	if config.Consumer.PrivateKey != "" {
+		c.keyLock.Lock()
+		c.privateKey = config.Consumer.PrivateKey
+		c.keyLock.Unlock()
+	}
 
 	var removeAllowedIPRule firewall.OutgoingRuleRemove
 	removeAllowedIPRule, err = firewall.AllowIPAccess(config.Provider.Endpoint.IP.String())
@@ -147,7 +157,7 @@ func (c *Connection) start(ctx context.Context, start startConn, options connect
 	conn, err = start(wgcfg.DeviceConfig{
 		IfaceName:    "", // Interface name will be generated by connection endpoint.
 		Subnet:       config.Consumer.IPAddress,
-		PrivateKey:   c.privateKey,
+		PrivateKey:   c.currentPrivateKey(),
 		ListenPort:   config.LocalPort,
 		DNS:          dnsIPs,
 		DNSScriptDir: c.opts.DNSScriptDir,
@@ -155,10 +165,11 @@ func (c *Connection) start(ctx context.Context, start startConn, options connect
 			Endpoint:               &config.Provider.Endpoint,
 			PublicKey:              config.Provider.PublicKey,
 			AllowedIPs:             []string{"0.0.0.0/0", "::/0"},
-			KeepAlivePeriodSeconds: 18,
+			KeepAlivePeriodSeconds: config.KeepAlivePeriodSeconds(),
 		},
 		ReplacePeers: true,
 		ProxyPort:    options.Params.ProxyPort,
+		SessionID:    string(options.SessionID),
 	})
 	if err != nil {
 		return errors.Wrap(err, "could not start new connection")
@@ -207,9 +218,49 @@ func (c *Connection) restartConn(conf wgcfg.DeviceConfig) (wg.ConnectionEndpoint
 	return conn, nil
 }
 
+// NewRotationKey generates a private key to be used after the next key rotation and returns its public key.
+func (c *Connection) NewRotationKey() (string, error) {
+	privateKey, err := key.GeneratePrivateKey()
+	if err != nil {
+		return "", errors.Wrap(err, "could not generate private key")
+	}
+
+	c.keyLock.Lock()
+	c.rotationPrivateKey = privateKey
+	c.keyLock.Unlock()
+
+	return key.PrivateKeyToPublicKey(privateKey)
+}
+
+// RotateKeys switches the tunnel to the key generated by NewRotationKey and the new provider public key.
+func (c *Connection) RotateKeys(providerPublicKey string) error {
+	c.keyLock.Lock()
+	defer c.keyLock.Unlock()
+
+	if c.rotationPrivateKey == "" {
+		return errors.New("rotation key is not generated")
+	}
+	if c.connectionEndpoint == nil {
+		return errors.New("connection is not started")
+	}
+
+	if err := c.connectionEndpoint.RotateKeys(c.rotationPrivateKey, providerPublicKey); err != nil {
+		return err
+	}
+	c.privateKey, c.rotationPrivateKey = c.rotationPrivateKey, ""
+
+	return nil
+}
+
+func (c *Connection) currentPrivateKey() string {
+	c.keyLock.Lock()
+	defer c.keyLock.Unlock()
+	return c.privateKey
+}
+
 // GetConfig returns the consumer configuration for session creation
 func (c *Connection) GetConfig() (connection.ConsumerConfig, error) {
-	publicKey, err := key.PrivateKeyToPublicKey(c.privateKey)
+	publicKey, err := key.PrivateKeyToPublicKey(c.currentPrivateKey())
 	if err != nil {
 		return nil, errors.Wrap(err, "could not get public key from private key")
 	}
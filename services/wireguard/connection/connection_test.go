@@ -31,6 +31,7 @@ import (
 	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
 	"github.com/mysteriumnetwork/node/core/ip"
 	wg "github.com/mysteriumnetwork/node/services/wireguard"
+	"github.com/mysteriumnetwork/node/services/wireguard/key"
 	"github.com/mysteriumnetwork/node/services/wireguard/wgcfg"
 )
 
@@ -128,8 +129,9 @@ func newServiceConfig() wg.ServiceConfig {
 			Endpoint:  *endpoint,
 		},
 		Consumer: struct {
-			IPAddress net.IPNet
-			DNSIPs    string
+			IPAddress  net.IPNet
+			DNSIPs     string
+			PrivateKey string
 		}{
 			IPAddress: net.IPNet{
 				IP:   net.IPv4(127, 0, 0, 1),
@@ -140,7 +142,41 @@ func newServiceConfig() wg.ServiceConfig {
 	}
 }
 
-type mockConnectionEndpoint struct{}
+func TestConnectionRotateKeys(t *testing.T) {
+	conn := newConn(t)
+
+	err := conn.RotateKeys("provider-key")
+	assert.Error(t, err, "rotation key has to be generated first")
+
+	_, err = conn.NewRotationKey()
+	assert.NoError(t, err)
+	err = conn.RotateKeys("provider-key")
+	assert.Error(t, err, "connection has to be started")
+
+	endpoint := &mockConnectionEndpoint{}
+	conn.connectionEndpoint = endpoint
+	publicKey, err := conn.NewRotationKey()
+	assert.NoError(t, err)
+
+	err = conn.RotateKeys("provider-key")
+	assert.NoError(t, err)
+	assert.Equal(t, "provider-key", endpoint.rotatedPeerKey)
+	rotatedPublicKey, err := key.PrivateKeyToPublicKey(endpoint.rotatedPrivateKey)
+	assert.NoError(t, err)
+	assert.Equal(t, publicKey, rotatedPublicKey)
+
+	config, err := conn.GetConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, publicKey, config.(wg.ConsumerConfig).PublicKey)
+
+	err = conn.RotateKeys("provider-key")
+	assert.Error(t, err, "rotation key can be used only once")
+}
+
+type mockConnectionEndpoint struct {
+	rotatedPrivateKey string
+	rotatedPeerKey    string
+}
 
 func (mce *mockConnectionEndpoint) ReconfigureConsumerMode(config wgcfg.DeviceConfig) error {
 	return nil
@@ -149,6 +185,13 @@ func (mce *mockConnectionEndpoint) StartConsumerMode(config wgcfg.DeviceConfig)
 func (mce *mockConnectionEndpoint) StartProviderMode(ip string, config wgcfg.DeviceConfig) error {
 	return nil
 }
+func (mce *mockConnectionEndpoint) RotateKeys(privateKey, peerPublicKey string) error {
+	mce.rotatedPrivateKey, mce.rotatedPeerKey = privateKey, peerPublicKey
+	return nil
+}
+func (mce *mockConnectionEndpoint) SetPaused(bool) error {
+	return nil
+}
 func (mce *mockConnectionEndpoint) InterfaceName() string                { return "mce0" }
 func (mce *mockConnectionEndpoint) Stop() error                          { return nil }
 func (mce *mockConnectionEndpoint) Config() (wg.ServiceConfig, error)    { return wg.ServiceConfig{}, nil }
@@ -30,6 +30,8 @@ type ConnectionEndpoint interface {
 	StartConsumerMode(config wgcfg.DeviceConfig) error
 	ReconfigureConsumerMode(config wgcfg.DeviceConfig) error
 	StartProviderMode(publicIP string, config wgcfg.DeviceConfig) error
+	RotateKeys(privateKey, peerPublicKey string) error
+	SetPaused(paused bool) error
 	PeerStats() (wgcfg.Stats, error)
 	Config() (ServiceConfig, error)
 	InterfaceName() string
@@ -20,6 +20,7 @@ package endpoint
 import (
 	"fmt"
 	"net"
+	"sync"
 
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
@@ -50,6 +51,8 @@ type connectionEndpoint struct {
 	endpoint          net.UDPAddr
 	resourceAllocator *resources.Allocator
 	wgClient          WgClient
+	paused            bool
+	reconfigureLock   sync.Mutex
 }
 
 // StartConsumerMode starts and configure wireguard network interface running in consumer mode.
@@ -84,10 +87,13 @@ func (ce *connectionEndpoint) StartConsumerMode(cfg wgcfg.DeviceConfig) error {
 }
 
 func (ce *connectionEndpoint) ReconfigureConsumerMode(cfg wgcfg.DeviceConfig) error {
+	ce.reconfigureLock.Lock()
+	defer ce.reconfigureLock.Unlock()
+
 	cfg.IfaceName = ce.cfg.IfaceName
 	ce.cfg = cfg
 
-	if err := ce.wgClient.ReConfigureDevice(cfg); err != nil {
+	if err := ce.wgClient.ReConfigureDevice(ce.applied(cfg)); err != nil {
 		return fmt.Errorf("could not reconfigure device: %w", err)
 	}
 
@@ -125,6 +131,50 @@ func (ce *connectionEndpoint) StartProviderMode(publicIP string, config wgcfg.De
 	return nil
 }
 
+// RotateKeys replaces own private key and the peer public key keeping the rest of device configuration.
+func (ce *connectionEndpoint) RotateKeys(privateKey, peerPublicKey string) error {
+	ce.reconfigureLock.Lock()
+	defer ce.reconfigureLock.Unlock()
+
+	cfg := ce.cfg
+	cfg.PrivateKey = privateKey
+	cfg.Peer.PublicKey = peerPublicKey
+	cfg.ReplacePeers = true
+
+	if err := ce.wgClient.ReConfigureDevice(ce.applied(cfg)); err != nil {
+		return fmt.Errorf("could not reconfigure device with rotated keys: %w", err)
+	}
+	ce.cfg = cfg
+
+	return nil
+}
+
+// SetPaused withdraws allowed IPs of the peer so that no traffic flows through the tunnel,
+// while the peer itself stays configured for a quick resume. Unpausing restores them.
+func (ce *connectionEndpoint) SetPaused(paused bool) error {
+	ce.reconfigureLock.Lock()
+	defer ce.reconfigureLock.Unlock()
+
+	cfg := ce.cfg
+	cfg.ReplacePeers = true
+
+	ce.paused = paused
+	if err := ce.wgClient.ReConfigureDevice(ce.applied(cfg)); err != nil {
+		ce.paused = !paused
+		return fmt.Errorf("could not reconfigure device: %w", err)
+	}
+
+	return nil
+}
+
+// applied returns the configuration to be applied to the device, taking the pause into account.
+func (ce *connectionEndpoint) applied(cfg wgcfg.DeviceConfig) wgcfg.DeviceConfig {
+	if ce.paused {
+		cfg.Peer.AllowedIPs = nil
+	}
+	return cfg
+}
+
 // InterfaceName returns a connection endpoint interface name.
 func (ce *connectionEndpoint) InterfaceName() string {
 	return ce.cfg.IfaceName
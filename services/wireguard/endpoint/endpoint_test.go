@@ -0,0 +1,98 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+package endpoint
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/services/wireguard/wgcfg"
+)
+
+func TestConnectionEndpoint_SetPaused(t *testing.T) {
+	client := &mockWgClient{}
+	ce := &connectionEndpoint{
+		wgClient: client,
+		cfg: wgcfg.DeviceConfig{
+			IfaceName: "myst0",
+			Peer:      wgcfg.Peer{PublicKey: "peer", AllowedIPs: []string{"0.0.0.0/0"}},
+		},
+	}
+
+	err := ce.SetPaused(true)
+	assert.NoError(t, err)
+	assert.Empty(t, client.last().Peer.AllowedIPs)
+	assert.True(t, client.last().ReplacePeers)
+
+	// Reconfiguration of the paused tunnel keeps it paused.
+	err = ce.ReconfigureConsumerMode(wgcfg.DeviceConfig{
+		Peer: wgcfg.Peer{PublicKey: "peer2", AllowedIPs: []string{"0.0.0.0/0", "::/0"}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "myst0", client.last().IfaceName)
+	assert.Equal(t, "peer2", client.last().Peer.PublicKey)
+	assert.Empty(t, client.last().Peer.AllowedIPs)
+
+	err = ce.RotateKeys("private", "peer3")
+	assert.NoError(t, err)
+	assert.Equal(t, "peer3", client.last().Peer.PublicKey)
+	assert.Empty(t, client.last().Peer.AllowedIPs)
+
+	err = ce.SetPaused(false)
+	assert.NoError(t, err)
+	assert.Equal(t, "peer3", client.last().Peer.PublicKey)
+	assert.Equal(t, []string{"0.0.0.0/0", "::/0"}, client.last().Peer.AllowedIPs)
+}
+
+func TestConnectionEndpoint_SetPausedFailure(t *testing.T) {
+	client := &mockWgClient{err: errors.New("device is gone")}
+	ce := &connectionEndpoint{
+		wgClient: client,
+		cfg:      wgcfg.DeviceConfig{Peer: wgcfg.Peer{AllowedIPs: []string{"0.0.0.0/0"}}},
+	}
+
+	err := ce.SetPaused(true)
+	assert.Error(t, err)
+	assert.False(t, ce.paused)
+}
+
+type mockWgClient struct {
+	configs []wgcfg.DeviceConfig
+	err     error
+}
+
+func (m *mockWgClient) last() wgcfg.DeviceConfig {
+	return m.configs[len(m.configs)-1]
+}
+
+func (m *mockWgClient) ConfigureDevice(config wgcfg.DeviceConfig) error {
+	m.configs = append(m.configs, config)
+	return m.err
+}
+
+func (m *mockWgClient) ReConfigureDevice(config wgcfg.DeviceConfig) error {
+	m.configs = append(m.configs, config)
+	return m.err
+}
+
+func (m *mockWgClient) DestroyDevice(string) error { return nil }
+
+func (m *mockWgClient) PeerStats(string) (wgcfg.Stats, error) { return wgcfg.Stats{}, nil }
+
+func (m *mockWgClient) Close() error { return nil }
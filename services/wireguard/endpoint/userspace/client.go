@@ -27,6 +27,8 @@ import (
 	"golang.zx2c4.com/wireguard/device"
 	"golang.zx2c4.com/wireguard/tun"
 
+	"github.com/mysteriumnetwork/node/core/connection/capture"
+	"github.com/mysteriumnetwork/node/core/connection/trafficcategory"
 	"github.com/mysteriumnetwork/node/services/wireguard/connection/dns"
 	"github.com/mysteriumnetwork/node/services/wireguard/wgcfg"
 	"github.com/mysteriumnetwork/node/utils/actionstack"
@@ -37,12 +39,17 @@ type client struct {
 	tun        tun.Device
 	devAPI     *device.Device
 	dnsManager dns.Manager
+	analyzer   *trafficcategory.Analyzer
+	capturer   *capture.Capturer
 }
 
 // NewWireguardClient creates new wireguard user space client.
-func NewWireguardClient() (*client, error) {
+// Traffic categories are counted only when analyzer is given and packets can be captured only when capturer is given.
+func NewWireguardClient(analyzer *trafficcategory.Analyzer, capturer *capture.Capturer) (*client, error) {
 	return &client{
 		dnsManager: dns.NewManager(),
+		analyzer:   analyzer,
+		capturer:   capturer,
 	}, nil
 }
 
@@ -51,6 +58,12 @@ func (c *client) ConfigureDevice(config wgcfg.DeviceConfig) (err error) {
 	if c.tun, err = CreateTUN(config.IfaceName, config.Subnet); err != nil {
 		return errors.Wrap(err, "failed to create TUN device")
 	}
+	if c.analyzer != nil {
+		c.tun = newAnalyzedTUN(c.tun, c.analyzer)
+	}
+	if c.capturer != nil && config.SessionID != "" {
+		c.tun = newCapturedTUN(c.tun, c.capturer.Tap(config.SessionID))
+	}
 
 	devAPI := device.NewDevice(c.tun, conn.NewDefaultBind(), device.NewLogger(device.LogLevelVerbose, "[userspace-wg]"))
 	c.devAPI = devAPI
@@ -117,6 +130,9 @@ func (c *client) PeerStats(string) (wgcfg.Stats, error) {
 	if err != nil {
 		return wgcfg.Stats{}, err
 	}
+	if c.analyzer != nil {
+		stats.Categories = c.analyzer.Breakdown()
+	}
 	return stats, nil
 }
 
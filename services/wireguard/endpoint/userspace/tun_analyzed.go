@@ -0,0 +1,51 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package userspace
+
+import (
+	"golang.zx2c4.com/wireguard/tun"
+
+	"github.com/mysteriumnetwork/node/core/connection/trafficcategory"
+)
+
+// analyzedTUN passes packet headers crossing the TUN device to traffic analyzer.
+type analyzedTUN struct {
+	tun.Device
+	analyzer *trafficcategory.Analyzer
+}
+
+func newAnalyzedTUN(dev tun.Device, analyzer *trafficcategory.Analyzer) tun.Device {
+	return &analyzedTUN{Device: dev, analyzer: analyzer}
+}
+
+// Read reads packets leaving the host through the tunnel.
+func (t *analyzedTUN) Read(bufs [][]byte, sizes []int, offset int) (int, error) {
+	n, err := t.Device.Read(bufs, sizes, offset)
+	for i := 0; i < n; i++ {
+		t.analyzer.Count(bufs[i][offset:offset+sizes[i]], true)
+	}
+	return n, err
+}
+
+// Write writes packets arriving to the host from the tunnel.
+func (t *analyzedTUN) Write(bufs [][]byte, offset int) (int, error) {
+	for _, buf := range bufs {
+		t.analyzer.Count(buf[offset:], false)
+	}
+	return t.Device.Write(bufs, offset)
+}
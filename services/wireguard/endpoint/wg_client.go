@@ -24,6 +24,8 @@ import (
 	"github.com/rs/zerolog/log"
 
 	"github.com/mysteriumnetwork/node/config"
+	"github.com/mysteriumnetwork/node/core/connection/capture"
+	"github.com/mysteriumnetwork/node/core/connection/trafficcategory"
 	"github.com/mysteriumnetwork/node/services/wireguard/endpoint/dvpnclient"
 	"github.com/mysteriumnetwork/node/services/wireguard/endpoint/kernelspace"
 	netstack_provider "github.com/mysteriumnetwork/node/services/wireguard/endpoint/netstack-provider"
@@ -47,11 +49,13 @@ type WgClient interface {
 type WgClientFactory struct {
 	once                         sync.Once
 	isKernelSpaceSupportedResult bool
+	capturer                     *capture.Capturer
 }
 
 // NewWGClientFactory returns a new client factory.
-func NewWGClientFactory() *WgClientFactory {
-	return &WgClientFactory{}
+// Session packets can be captured only when capturer is given.
+func NewWGClientFactory(capturer *capture.Capturer) *WgClientFactory {
+	return &WgClientFactory{capturer: capturer}
 }
 
 // NewWGClient returns a new wireguard client.
@@ -72,6 +76,16 @@ func (wcf *WgClientFactory) NewWGClient() (WgClient, error) {
 		return remoteclient.New()
 	}
 
+	// Traffic categories and packet capture need packet headers, which only the user space implementation exposes.
+	if config.GetBool(config.FlagTrafficCategories) {
+		log.Info().Msgf("Traffic categories are enabled by --%s, using Wireguard user space implementation.", config.FlagTrafficCategories.Name)
+		return userspace.NewWireguardClient(trafficcategory.NewAnalyzer(), wcf.capturer)
+	}
+	if wcf.capturer != nil {
+		log.Info().Msgf("Packet capture is enabled by --%s, using Wireguard user space implementation.", config.FlagPcapEnable.Name)
+		return userspace.NewWireguardClient(nil, wcf.capturer)
+	}
+
 	wcf.once.Do(func() {
 		wcf.isKernelSpaceSupportedResult = wcf.isKernelSpaceSupported()
 	})
@@ -82,7 +96,7 @@ func (wcf *WgClientFactory) NewWGClient() (WgClient, error) {
 
 	log.Info().Msg("Wireguard kernel space is not supported. Switching to user space implementation.")
 
-	return userspace.NewWireguardClient()
+	return userspace.NewWireguardClient(nil, nil)
 }
 
 func (wcf *WgClientFactory) isKernelSpaceSupported() bool {
@@ -22,7 +22,6 @@ import (
 	"fmt"
 	"net"
 	"sync"
-	"time"
 
 	"github.com/jinzhu/copier"
 	"github.com/pkg/errors"
@@ -54,6 +53,7 @@ func NewManager(
 	resourcesAllocator *resources.Allocator,
 	wgClientFactory *endpoint.WgClientFactory,
 	dnsProxy *dns.Proxy,
+	statsSampler statsSampler,
 ) *Manager {
 	return &Manager{
 		done:               make(chan struct{}),
@@ -63,12 +63,15 @@ func NewManager(
 		eventBus:           eventBus,
 		trafficFirewall:    trafficFirewall,
 		dnsProxy:           dnsProxy,
+		statsSampler:       statsSampler,
 
 		connEndpointFactory: func() (wg.ConnectionEndpoint, error) {
 			return endpoint.NewConnectionEndpoint(resourcesAllocator, wgClientFactory)
 		},
-		country:        country,
-		sessionCleanup: map[string]func(){},
+		country:          country,
+		sessionCleanup:   map[string]func(){},
+		sessionEndpoints: map[string]wg.ConnectionEndpoint{},
+		pendingKeys:      map[string]pendingKey{},
 	}
 }
 
@@ -83,7 +86,8 @@ type Manager struct {
 	eventBus        eventbus.EventBus
 	trafficFirewall firewall.IncomingTrafficFirewall
 
-	dnsProxy *dns.Proxy
+	dnsProxy     *dns.Proxy
+	statsSampler statsSampler
 
 	connEndpointFactory func() (wg.ConnectionEndpoint, error)
 
@@ -91,6 +95,8 @@ type Manager struct {
 
 	serviceInstance  *service.Instance
 	sessionCleanup   map[string]func()
+	sessionEndpoints map[string]wg.ConnectionEndpoint
+	pendingKeys      map[string]pendingKey
 	sessionCleanupMu sync.Mutex
 
 	country    string
@@ -149,11 +155,10 @@ func (m *Manager) ProvideConfig(sessionID string, sessionConfig json.RawMessage,
 		return nil, errors.Wrap(err, "failed to setup NAT/firewall rules")
 	}
 
-	statsPublisher := newStatsPublisher(m.eventBus, time.Second)
-	go statsPublisher.start(sessionID, conn)
+	m.statsSampler.Track(sessionID, peerStatsSource(conn))
 
 	ifaceName := conn.InterfaceName()
-	s := shaper.New(m.eventBus)
+	s := shaper.NewWithLimits(m.eventBus, m.serviceInstance.Shaping)
 	err = s.Start(ifaceName)
 	if err != nil {
 		log.Error().Err(err).Msg("Could not start traffic shaper")
@@ -169,8 +174,10 @@ func (m *Manager) ProvideConfig(sessionID string, sessionConfig json.RawMessage,
 			return
 		}
 		delete(m.sessionCleanup, sessionID)
+		delete(m.sessionEndpoints, sessionID)
+		delete(m.pendingKeys, sessionID)
 
-		statsPublisher.stop()
+		m.statsSampler.Untrack(sessionID)
 
 		s.Clear(ifaceName)
 
@@ -197,11 +204,89 @@ func (m *Manager) ProvideConfig(sessionID string, sessionConfig json.RawMessage,
 
 	m.sessionCleanupMu.Lock()
 	m.sessionCleanup[sessionID] = destroy
+	m.sessionEndpoints[sessionID] = conn
 	m.sessionCleanupMu.Unlock()
 
 	return &service.ConfigParams{SessionServiceConfig: config, SessionDestroyCallback: destroy}, nil
 }
 
+// pendingKey is a rotated key pair waiting for the consumer acknowledgement.
+type pendingKey struct {
+	privateKey        string
+	publicKey         string
+	consumerPublicKey string
+}
+
+// RotateKey prepares tunnel key rotation of the given session and returns public part of a freshly generated
+// provider key. Tunnel keeps using the current keys until the consumer acknowledges the rotation,
+// so a lost reply does not break the session.
+func (m *Manager) RotateKey(sessionID, consumerPublicKey string) (string, error) {
+	privateKey, err := key.GeneratePrivateKey()
+	if err != nil {
+		return "", fmt.Errorf("could not generate private key: %w", err)
+	}
+	publicKey, err := key.PrivateKeyToPublicKey(privateKey)
+	if err != nil {
+		return "", fmt.Errorf("could not get public key from private key: %w", err)
+	}
+
+	m.sessionCleanupMu.Lock()
+	defer m.sessionCleanupMu.Unlock()
+
+	if _, ok := m.sessionEndpoints[sessionID]; !ok {
+		return "", fmt.Errorf("session %s not found", sessionID)
+	}
+	m.pendingKeys[sessionID] = pendingKey{
+		privateKey:        privateKey,
+		publicKey:         publicKey,
+		consumerPublicKey: consumerPublicKey,
+	}
+
+	return publicKey, nil
+}
+
+// ConfirmKeyRotation switches the session tunnel to the keys prepared by RotateKey.
+// Provider public key has to match the one returned by the latest RotateKey call.
+func (m *Manager) ConfirmKeyRotation(sessionID, providerPublicKey string) error {
+	m.sessionCleanupMu.Lock()
+	defer m.sessionCleanupMu.Unlock()
+
+	conn, ok := m.sessionEndpoints[sessionID]
+	if !ok {
+		return fmt.Errorf("session %s not found", sessionID)
+	}
+	pending, ok := m.pendingKeys[sessionID]
+	if !ok || pending.publicKey != providerPublicKey {
+		return fmt.Errorf("no pending key rotation of session %s for the given key", sessionID)
+	}
+
+	if err := conn.RotateKeys(pending.privateKey, pending.consumerPublicKey); err != nil {
+		return err
+	}
+	delete(m.pendingKeys, sessionID)
+
+	log.Info().Msgf("Rotated WireGuard keys of session %s", sessionID)
+	return nil
+}
+
+// SetSessionPaused stops or restores routing of the session traffic through the tunnel.
+// Tunnel peer stays configured, so the session is resumed without a new handshake over the p2p channel.
+func (m *Manager) SetSessionPaused(sessionID string, paused bool) error {
+	m.sessionCleanupMu.Lock()
+	conn, ok := m.sessionEndpoints[sessionID]
+	m.sessionCleanupMu.Unlock()
+	if !ok {
+		return fmt.Errorf("session %s not found", sessionID)
+	}
+
+	if err := conn.SetPaused(paused); err != nil {
+		return err
+	}
+
+	log.Info().Msgf("Set WireGuard session %s paused: %t", sessionID, paused)
+	return nil
+}
+
 func (m *Manager) createProviderConfig(listenPort int, peerPublicKey string) (wgcfg.DeviceConfig, error) {
 	network, err := m.resourcesAllocator.AllocateIPNet()
 	if err != nil {
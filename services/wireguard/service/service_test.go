@@ -34,7 +34,9 @@ import (
 	"github.com/mysteriumnetwork/node/market"
 	"github.com/mysteriumnetwork/node/nat"
 	wg "github.com/mysteriumnetwork/node/services/wireguard"
+	"github.com/mysteriumnetwork/node/services/wireguard/key"
 	"github.com/mysteriumnetwork/node/services/wireguard/wgcfg"
+	"github.com/mysteriumnetwork/node/session/stats"
 )
 
 var (
@@ -129,12 +131,44 @@ func Test_Manager_ProviderConfig_FailsWhenSessionConfigIsInvalid(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func Test_Manager_RotateKey(t *testing.T) {
+	manager := newManagerStub(pubIP, outIP, country)
+	endpoint := &mockConnectionEndpoint{}
+	manager.sessionEndpoints = map[string]wg.ConnectionEndpoint{"1": endpoint}
+	manager.pendingKeys = map[string]pendingKey{}
+
+	_, err := manager.RotateKey("unknown", "consumer-key")
+	assert.Error(t, err)
+
+	publicKey, err := manager.RotateKey("1", "consumer-key")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, publicKey)
+	assert.Empty(t, endpoint.rotatedPeerKey, "keys should be kept until the consumer acknowledges them")
+
+	err = manager.ConfirmKeyRotation("1", "other-key")
+	assert.Error(t, err)
+	assert.Empty(t, endpoint.rotatedPeerKey)
+
+	err = manager.ConfirmKeyRotation("1", publicKey)
+	assert.NoError(t, err)
+	assert.Equal(t, "consumer-key", endpoint.rotatedPeerKey)
+	rotatedPublicKey, err := key.PrivateKeyToPublicKey(endpoint.rotatedPrivateKey)
+	assert.NoError(t, err)
+	assert.Equal(t, publicKey, rotatedPublicKey)
+
+	err = manager.ConfirmKeyRotation("1", publicKey)
+	assert.Error(t, err, "rotation should be confirmed only once")
+}
+
 // usually time.Sleep call gives a chance for other goroutines to kick in important when testing async code
 func waitABit() {
 	time.Sleep(10 * time.Millisecond)
 }
 
-type mockConnectionEndpoint struct{}
+type mockConnectionEndpoint struct {
+	rotatedPrivateKey string
+	rotatedPeerKey    string
+}
 
 func (mce *mockConnectionEndpoint) StartConsumerMode(config wgcfg.DeviceConfig) error { return nil }
 func (mce *mockConnectionEndpoint) ReconfigureConsumerMode(config wgcfg.DeviceConfig) error {
@@ -144,6 +178,13 @@ func (mce *mockConnectionEndpoint) ReconfigureConsumerMode(config wgcfg.DeviceCo
 func (mce *mockConnectionEndpoint) StartProviderMode(ip string, config wgcfg.DeviceConfig) error {
 	return nil
 }
+func (mce *mockConnectionEndpoint) RotateKeys(privateKey, peerPublicKey string) error {
+	mce.rotatedPrivateKey, mce.rotatedPeerKey = privateKey, peerPublicKey
+	return nil
+}
+func (mce *mockConnectionEndpoint) SetPaused(bool) error {
+	return nil
+}
 func (mce *mockConnectionEndpoint) InterfaceName() string                { return "mce0" }
 func (mce *mockConnectionEndpoint) Stop() error                          { return nil }
 func (mce *mockConnectionEndpoint) Config() (wg.ServiceConfig, error)    { return wg.ServiceConfig{}, nil }
@@ -164,7 +205,8 @@ func newManagerStub(pub, out, country string) *Manager {
 		connEndpointFactory: func() (wg.ConnectionEndpoint, error) {
 			return connectionEndpointStub, nil
 		},
-		dnsProxy: dns.NewProxy("", 0, dnsHandler),
+		dnsProxy:     dns.NewProxy("", 0, dnsHandler),
+		statsSampler: stats.NewSampler(nil, stats.DefaultInterval),
 	}
 }
 
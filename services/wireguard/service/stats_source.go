@@ -0,0 +1,42 @@
+/*
+ * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package service
+
+import (
+	"github.com/mysteriumnetwork/node/services/wireguard/wgcfg"
+	"github.com/mysteriumnetwork/node/session/stats"
+)
+
+type statsSupplier interface {
+	PeerStats() (wgcfg.Stats, error)
+}
+
+type statsSampler interface {
+	Track(sessionID string, source stats.Source)
+	Untrack(sessionID string)
+}
+
+func peerStatsSource(supplier statsSupplier) stats.Source {
+	return func() (up, down uint64, err error) {
+		peerStats, err := supplier.PeerStats()
+		if err != nil {
+			return 0, 0, err
+		}
+		return peerStats.BytesSent, peerStats.BytesReceived, nil
+	}
+}
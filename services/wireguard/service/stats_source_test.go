@@ -23,9 +23,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
-	"github.com/mysteriumnetwork/node/mocks"
 	"github.com/mysteriumnetwork/node/services/wireguard/wgcfg"
-	"github.com/mysteriumnetwork/node/session/event"
 )
 
 type fakeSupplier struct{}
@@ -38,30 +36,10 @@ func (f fakeSupplier) PeerStats() (wgcfg.Stats, error) {
 	}, nil
 }
 
-func Test_statsPublisher_start(t *testing.T) {
-	bus := mocks.NewEventBus()
-	publisher := newStatsPublisher(bus, time.Microsecond)
+func Test_peerStatsSource(t *testing.T) {
+	up, down, err := peerStatsSource(&fakeSupplier{})()
 
-	go publisher.start("kappa", &fakeSupplier{})
-
-	assert.Eventually(t, func() bool {
-		lastEvt := bus.Pop()
-		if lastEvt == nil {
-			return false
-		}
-		evt, ok := lastEvt.(event.AppEventDataTransferred)
-		assert.True(t, ok)
-		return evt.ID == "kappa" && evt.Down == 52 && evt.Up == 25
-	}, 2*time.Second, 10*time.Millisecond)
-
-	publisher.stop()
-
-	// After stop publisher may still publish one last event if it was started before stop
-	// so we need to wait and drain last event from bus before checking if publisher
-	// actually stopped publishing events.
-	time.Sleep(time.Millisecond)
-	bus.Pop()
-	assert.Never(t, func() bool {
-		return bus.Pop() != nil
-	}, time.Millisecond, time.Microsecond)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(25), up)
+	assert.Equal(t, uint64(52), down)
 }
@@ -20,17 +20,23 @@ package wireguard
 import (
 	"encoding/json"
 	"net"
+	"time"
 )
 
 // ServiceType indicates "wireguard" service type
 const ServiceType = "wireguard"
 
+// DefaultKeepAlive is the consumer keep-alive period used when it was not tuned to the NAT mapping lifetime.
+const DefaultKeepAlive = 18 * time.Second
+
 // ServiceConfig represent a Wireguard service provider configuration that will be passed to the consumer for establishing a connection.
 type ServiceConfig struct {
 	// LocalPort and RemotePort are needed for NAT hole punching only.
 	LocalPort  int   `json:"-"`
 	RemotePort int   `json:"-"`
 	Ports      []int `json:"ports"`
+	// KeepAlive is the consumer keep-alive period tuned to the NAT mapping lifetime, it is never sent to the peer.
+	KeepAlive time.Duration `json:"-"`
 
 	Provider struct {
 		PublicKey string
@@ -39,7 +45,18 @@ type ServiceConfig struct {
 	Consumer struct {
 		IPAddress net.IPNet
 		DNSIPs    string
+		// PrivateKey is set only for endpoints imported from outside of the network,
+		// otherwise consumer generates its own key.
+		PrivateKey string
+	}
+}
+
+// KeepAlivePeriodSeconds returns the consumer keep-alive period in seconds, the default one if it was not tuned.
+func (s ServiceConfig) KeepAlivePeriodSeconds() int {
+	if s.KeepAlive <= 0 {
+		return int(DefaultKeepAlive.Seconds())
 	}
+	return int(s.KeepAlive.Seconds())
 }
 
 // ConsumerConfig is used for sending the public key and IP from consumer to provider.
@@ -57,8 +74,9 @@ func (s ServiceConfig) MarshalJSON() ([]byte, error) {
 		Endpoint  string `json:"endpoint"`
 	}
 	type consumer struct {
-		IPAddress string `json:"ip_address"`
-		DNSIPs    string `json:"dns_ips"`
+		IPAddress  string `json:"ip_address"`
+		DNSIPs     string `json:"dns_ips"`
+		PrivateKey string `json:"private_key,omitempty"`
 	}
 
 	return json.Marshal(&struct {
@@ -76,8 +94,9 @@ func (s ServiceConfig) MarshalJSON() ([]byte, error) {
 			Endpoint:  s.Provider.Endpoint.String(),
 		},
 		Consumer: consumer{
-			IPAddress: s.Consumer.IPAddress.String(),
-			DNSIPs:    s.Consumer.DNSIPs,
+			IPAddress:  s.Consumer.IPAddress.String(),
+			DNSIPs:     s.Consumer.DNSIPs,
+			PrivateKey: s.Consumer.PrivateKey,
 		},
 	})
 }
@@ -89,8 +108,9 @@ func (s *ServiceConfig) UnmarshalJSON(data []byte) error {
 		Endpoint  string `json:"endpoint"`
 	}
 	type consumer struct {
-		IPAddress string `json:"ip_address"`
-		DNSIPs    string `json:"dns_ips"`
+		IPAddress  string `json:"ip_address"`
+		DNSIPs     string `json:"dns_ips"`
+		PrivateKey string `json:"private_key,omitempty"`
 	}
 	var config struct {
 		LocalPort  int      `json:"local_port"`
@@ -119,6 +139,7 @@ func (s *ServiceConfig) UnmarshalJSON(data []byte) error {
 	s.Provider.Endpoint = *endpoint
 	s.Provider.PublicKey = config.Provider.PublicKey
 	s.Consumer.DNSIPs = config.Consumer.DNSIPs
+	s.Consumer.PrivateKey = config.Consumer.PrivateKey
 	s.Consumer.IPAddress = *ipnet
 	s.Consumer.IPAddress.IP = ip
 
@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"net"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -38,8 +39,9 @@ func TestServiceConfig_MarshalJSON(t *testing.T) {
 			Endpoint:  *endpoint,
 		},
 		Consumer: struct {
-			IPAddress net.IPNet
-			DNSIPs    string
+			IPAddress  net.IPNet
+			DNSIPs     string
+			PrivateKey string
 		}{
 			IPAddress: net.IPNet{
 				IP:   net.IPv4(127, 0, 0, 1),
@@ -72,8 +74,9 @@ func TestServiceConfig_UnmarshalJSON(t *testing.T) {
 			Endpoint:  *endpoint,
 		},
 		Consumer: struct {
-			IPAddress net.IPNet
-			DNSIPs    string
+			IPAddress  net.IPNet
+			DNSIPs     string
+			PrivateKey string
 		}{
 			IPAddress: net.IPNet{
 				IP:   net.IPv4(127, 0, 0, 1),
@@ -89,3 +92,29 @@ func TestServiceConfig_UnmarshalJSON(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, expecteConfig, actualConfig)
 }
+
+func TestServiceConfig_ConsumerPrivateKeyRoundTrip(t *testing.T) {
+	configJSON := json.RawMessage(`{"provider":{"public_key":"wg1","endpoint":"127.0.0.1:51001"},"consumer":{"ip_address":"10.0.0.2/32","dns_ips":"1.1.1.1","private_key":"wg2"}}`)
+
+	var config ServiceConfig
+	err := json.Unmarshal(configJSON, &config)
+	assert.NoError(t, err)
+	assert.Equal(t, "wg2", config.Consumer.PrivateKey)
+
+	configBytes, err := json.Marshal(config)
+	assert.NoError(t, err)
+	assert.Contains(t, string(configBytes), `"private_key":"wg2"`)
+}
+
+func TestServiceConfig_KeepAlivePeriodSeconds(t *testing.T) {
+	config := ServiceConfig{}
+	assert.Equal(t, 18, config.KeepAlivePeriodSeconds())
+
+	config.KeepAlive = 45 * time.Second
+	assert.Equal(t, 45, config.KeepAlivePeriodSeconds())
+
+	// tuned keep-alive stays local
+	data, err := json.Marshal(config)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), "45")
+}
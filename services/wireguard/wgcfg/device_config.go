@@ -27,6 +27,8 @@ import (
 	"time"
 
 	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/core/connection/trafficcategory"
 )
 
 // Stats represents wireguard peer statistics information.
@@ -34,6 +36,8 @@ type Stats struct {
 	BytesSent     uint64    `json:"bytes_sent"`
 	BytesReceived uint64    `json:"bytes_received"`
 	LastHandshake time.Time `json:"last_handshake"`
+
+	Categories trafficcategory.Breakdown `json:"categories,omitempty"`
 }
 
 // DeviceConfig describes wireguard device configuration.
@@ -52,6 +56,9 @@ type DeviceConfig struct {
 	ReplacePeers bool `json:"replace_peers,omitempty"`
 
 	ProxyPort int `json:"proxy_port,omitempty"`
+
+	// SessionID is the consumer session using the device, only used for the local packet capture.
+	SessionID string `json:"-"`
 }
 
 // MarshalJSON implements json.Marshaler interface to provide human readable configuration.
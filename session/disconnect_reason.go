@@ -0,0 +1,56 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package session
+
+// DisconnectReason tells why the session was ended.
+type DisconnectReason string
+
+const (
+	// DisconnectReasonUnknown is recorded when the peer did not send the reason, e.g. it runs an older node version.
+	DisconnectReasonUnknown DisconnectReason = ""
+	// DisconnectReasonUserRequest means the consumer disconnected on user request.
+	DisconnectReasonUserRequest DisconnectReason = "user_request"
+	// DisconnectReasonLowBalance means the consumer could not pay for the session anymore.
+	DisconnectReasonLowBalance DisconnectReason = "low_balance"
+	// DisconnectReasonQualityFailover means the consumer dropped the session because of the connection quality
+	// and reconnects to the same or another provider.
+	DisconnectReasonQualityFailover DisconnectReason = "quality_failover"
+	// DisconnectReasonInactivity means the consumer disconnected the idle session.
+	DisconnectReasonInactivity DisconnectReason = "inactivity"
+	// DisconnectReasonShutdown means the consumer node is shutting down.
+	DisconnectReasonShutdown DisconnectReason = "shutdown"
+	// DisconnectReasonChainSwitch means the session was drained because the node switched its active chain.
+	DisconnectReasonChainSwitch DisconnectReason = "chain_switch"
+)
+
+var disconnectReasons = map[DisconnectReason]struct{}{
+	DisconnectReasonUserRequest:     {},
+	DisconnectReasonLowBalance:      {},
+	DisconnectReasonQualityFailover: {},
+	DisconnectReasonInactivity:      {},
+	DisconnectReasonShutdown:        {},
+	DisconnectReasonChainSwitch:     {},
+}
+
+// ParseDisconnectReason returns the reason received from the peer, unknown reasons are mapped to DisconnectReasonUnknown.
+func ParseDisconnectReason(reason string) DisconnectReason {
+	if _, ok := disconnectReasons[DisconnectReason(reason)]; ok {
+		return DisconnectReason(reason)
+	}
+	return DisconnectReasonUnknown
+}
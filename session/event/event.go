@@ -25,13 +25,14 @@ import (
 
 	"github.com/mysteriumnetwork/node/identity"
 	"github.com/mysteriumnetwork/node/market"
+	"github.com/mysteriumnetwork/node/session"
 )
 
 const (
 	// AppTopicSession represents the session change topic.
 	AppTopicSession = "Session change"
-	// AppTopicDataTransferred represents the data transfer topic.
-	AppTopicDataTransferred = "Session data transferred"
+	// AppTopicDataTransferredBatch represents the topic of data transferred by all sampled sessions.
+	AppTopicDataTransferredBatch = "Session data transferred"
 	// AppTopicTokensEarned is a topic for publish events about tokens earned as a provider.
 	AppTopicTokensEarned = "SessionTokensEarned"
 )
@@ -42,6 +43,12 @@ type AppEventDataTransferred struct {
 	Up, Down uint64
 }
 
+// AppEventDataTransferredBatch holds the data transferred by every sampled session,
+// published once per sampling interval instead of an event per session.
+type AppEventDataTransferredBatch struct {
+	Sessions []AppEventDataTransferred
+}
+
 // AppEventTokensEarned is an update on tokens earned during current session
 type AppEventTokensEarned struct {
 	ProviderID identity.Identity
@@ -81,4 +88,9 @@ type SessionContext struct {
 	ConsumerLocation market.Location
 	HermesID         common.Address
 	Proposal         market.ServiceProposal
+	Protocol         session.Protocol
+	// Peer is the node version and capabilities announced by the consumer.
+	Peer session.Peer
+	// DisconnectReason is reported by the consumer when ending the session, set for the removal event only.
+	DisconnectReason session.DisconnectReason
 }
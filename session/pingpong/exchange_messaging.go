@@ -48,7 +48,18 @@ func NewExchangeSender(ch p2p.ChannelSender) *ExchangeSender {
 
 // Send sends the given exchange message.
 func (es *ExchangeSender) Send(em crypto.ExchangeMessage) error {
-	pMessage := &pb.ExchangeMessage{
+	pMessage := ExchangeMessageProto(em)
+	log.Debug().Msgf("Sending P2P message to %q: %s", p2p.TopicPaymentMessage, pMessage.String())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+	_, err := es.ch.Send(ctx, p2p.TopicPaymentMessage, p2p.ProtoMessage(pMessage))
+	return err
+}
+
+// ExchangeMessageProto converts the exchange message into its p2p message.
+func ExchangeMessageProto(em crypto.ExchangeMessage) *pb.ExchangeMessage {
+	return &pb.ExchangeMessage{
 		Promise: &pb.Promise{
 			ChannelID: em.Promise.ChannelID,
 			Amount:    em.Promise.Amount.Text(bigIntBase),
@@ -65,10 +76,4 @@ func (es *ExchangeSender) Send(em crypto.ExchangeMessage) error {
 		HermesID:       em.HermesID,
 		ChainID:        em.ChainID,
 	}
-	log.Debug().Msgf("Sending P2P message to %q: %s", p2p.TopicPaymentMessage, pMessage.String())
-
-	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
-	defer cancel()
-	_, err := es.ch.Send(ctx, p2p.TopicPaymentMessage, p2p.ProtoMessage(pMessage))
-	return err
 }
@@ -156,7 +156,7 @@ func (ac *HermesCaller) promiseRequest(rp RequestPromise, endpoint string) (cryp
 			return fmt.Errorf("could not form %v request: %w", endpoint, err)
 		}
 
-		err = ac.doRequest(req, &res)
+		err = ac.doRequest(req, (*promiseJSON)(&res))
 		if err != nil {
 			// if too many requests, retry
 			if errors.Is(err, ErrTooManyRequests) {
@@ -410,7 +410,11 @@ func (ac *HermesCaller) doRequest(req *http.Request, to any) error {
 
 	if resp.StatusCode >= 200 && resp.StatusCode <= 300 {
 		// parse response
-		err = json.Unmarshal(body, &to)
+		if decoder, ok := to.(jsonDecoder); ok {
+			err = decoder.decodeJSON(body)
+		} else {
+			err = json.Unmarshal(body, &to)
+		}
 		if err != nil {
 			return fmt.Errorf("could not unmarshal response body: %w", err)
 		}
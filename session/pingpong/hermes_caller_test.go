@@ -28,6 +28,7 @@ import (
 	"time"
 
 	"github.com/mysteriumnetwork/node/requests"
+	"github.com/mysteriumnetwork/node/testkit/fakeapi"
 	"github.com/mysteriumnetwork/payments/crypto"
 	"github.com/stretchr/testify/assert"
 )
@@ -268,3 +269,25 @@ func TestLatestPromise_isValid(t *testing.T) {
 		})
 	}
 }
+
+func TestHermesCaller_GetProviderData(t *testing.T) {
+	hermes := fakeapi.NewHermes()
+	url := hermes.Start()
+	defer hermes.Close()
+
+	id := "0x000000000000000000000000000000000000000a"
+	hermes.SetProvider(1, fakeapi.UserInfo{Identity: id, Balance: big.NewInt(10), IsOffchain: true})
+
+	caller := NewHermesCaller(requests.NewHTTPClient("0.0.0.0", time.Second), url)
+	data, err := caller.GetProviderData(1, id)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(10), data.Balance)
+	assert.True(t, data.IsOffchain)
+
+	_, err = caller.GetProviderData(2, id)
+	assert.Error(t, err)
+
+	offchain, err := caller.IsIdentityOffchain(1, id)
+	assert.NoError(t, err)
+	assert.False(t, offchain)
+}
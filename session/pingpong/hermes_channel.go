@@ -59,6 +59,11 @@ type HermesChannel struct {
 	Beneficiary common.Address
 }
 
+// LastPromise returns the latest promise received from hermes.
+func (hc HermesChannel) LastPromise() HermesPromise {
+	return hc.lastPromise
+}
+
 // LifetimeBalance returns earnings of all history.
 func (hc HermesChannel) LifetimeBalance() *big.Int {
 	if hc.lastPromise.Promise.Amount == nil {
@@ -293,13 +293,7 @@ func (aph *HermesPromiseHandler) requestPromise(er enqueuedRequest) {
 		AgreementID: er.em.AgreementID,
 	}
 
-	bytes, err := json.Marshal(details)
-	if err != nil {
-		er.errChan <- fmt.Errorf("could not marshal R recovery details: %w", err)
-		return
-	}
-
-	encrypted, err := aph.deps.Encryption.Encrypt(providerID.ToCommonAddress(), bytes)
+	encrypted, err := aph.deps.Encryption.Encrypt(providerID.ToCommonAddress(), details.AppendJSON(nil))
 	if err != nil {
 		er.errChan <- fmt.Errorf("could not encrypt R: %w", err)
 		return
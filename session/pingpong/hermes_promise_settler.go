@@ -36,6 +36,7 @@ import (
 	"github.com/mysteriumnetwork/node/core/beneficiary"
 	nodevent "github.com/mysteriumnetwork/node/core/node/event"
 	"github.com/mysteriumnetwork/node/core/service/servicestate"
+	"github.com/mysteriumnetwork/node/core/watchdog"
 	"github.com/mysteriumnetwork/node/eventbus"
 	"github.com/mysteriumnetwork/node/identity"
 	"github.com/mysteriumnetwork/node/identity/registry"
@@ -107,6 +108,7 @@ type HermesPromiseSettler interface {
 	ForceSettle(chainID int64, providerID identity.Identity, hermesID ...common.Address) error
 	ForceSettleAsync(chainID int64, providerID identity.Identity, hermesID ...common.Address) error
 	SettleWithBeneficiary(chainID int64, providerID identity.Identity, beneficiary common.Address, hermeses []common.Address) error
+	PreviewSettlement(chainID int64, providerID identity.Identity, beneficiary common.Address, hermesIDs ...common.Address) ([]SettlementPreview, error)
 	SettleIntoStake(chainID int64, providerID identity.Identity, hermesID ...common.Address) error
 	GetHermesFee(chainID int64, hermesID common.Address) (uint16, error)
 	Withdraw(fromChainID int64, toChainID int64, providerID identity.Identity, hermesID, beneficiary common.Address, amount *big.Int) error
@@ -135,6 +137,8 @@ type hermesPromiseSettler struct {
 	beneficiaryLocalStorage    beneficiary.BeneficiaryStorage
 	currentState               map[identity.Identity]settlementState
 	settleQueue                chan receivedPromise
+	heartbeat                  *watchdog.Heartbeat
+	overrides                  identityOverrides
 	stop                       chan struct{}
 	once                       sync.Once
 }
@@ -175,6 +179,9 @@ type HermesPromiseSettlerConfig struct {
 
 var errFeeNotCovered = errors.New("fee not covered, cannot continue")
 
+// settlementHeartbeatInterval is how often the idle settlement request loop reports it is alive.
+const settlementHeartbeatInterval = time.Minute
+
 // NewHermesPromiseSettler creates a new instance of hermes promise settler.
 func NewHermesPromiseSettler(transactor transactor, promiseStorage promiseStorage, paySettler paySettler, addressProvider addressProvider, hermesCallerFactory HermesCallerFactory, hermesURLGetter hermesURLGetter, channelProvider hermesChannelProvider, providerChannelStatusProvider providerChannelStatusProvider, registrationStatusProvider registrationStatusProvider, ks ks, settlementHistoryStorage settlementHistoryStorage, publisher eventbus.Publisher, observerApi observerApi, beneficiaryLocalStorage beneficiary.BeneficiaryStorage, config HermesPromiseSettlerConfig) *hermesPromiseSettler {
 	return &hermesPromiseSettler{
@@ -357,7 +364,11 @@ func (aps *hermesPromiseSettler) handleHermesPromiseReceived(apep event.AppEvent
 
 	log.Info().Msgf("Hermes %q promise state updated for provider %q", apep.HermesID.Hex(), id)
 
-	needs, maxFee := aps.needsSettling(s, aps.config.BalanceThreshold, aps.config.MaxFeeThreshold, aps.config.MinAutoSettleAmount, aps.config.MaxUnSettledAmount, channel, apep.Promise.ChainID)
+	minSettleAmount := aps.config.MinAutoSettleAmount
+	if threshold := overridesOf(aps.overrides, id).SettleThreshold; threshold != nil {
+		minSettleAmount = *threshold
+	}
+	needs, maxFee := aps.needsSettling(s, aps.config.BalanceThreshold, aps.config.MaxFeeThreshold, minSettleAmount, aps.config.MaxUnSettledAmount, channel, apep.Promise.ChainID)
 	if needs {
 		log.Info().Msgf("Starting auto settle for provider %v", id)
 		aps.initiateSettling(channel, maxFee)
@@ -380,14 +391,31 @@ func (aps *hermesPromiseSettler) initiateSettling(channel HermesChannel, maxFee
 	}
 }
 
+// SetHeartbeat sets the heartbeat reported by the settlement request loop, it must be called before the node starts.
+func (aps *hermesPromiseSettler) SetHeartbeat(heartbeat *watchdog.Heartbeat) {
+	aps.heartbeat = heartbeat
+}
+
+// SetOverrides sets the identity overrides of beneficiary and automatic settlement threshold,
+// it must be called before the node starts.
+func (aps *hermesPromiseSettler) SetOverrides(o identityOverrides) {
+	aps.overrides = o
+}
+
 func (aps *hermesPromiseSettler) listenForSettlementRequests() {
 	log.Info().Msg("Listening for settlement events")
 	defer log.Info().Msg("Stopped listening for settlement events")
 
+	ticker := time.NewTicker(settlementHeartbeatInterval)
+	defer ticker.Stop()
+
 	for {
+		aps.heartbeat.Beat()
+
 		select {
 		case <-aps.stop:
 			return
+		case <-ticker.C:
 		case p := <-aps.settleQueue:
 			channel, found := aps.channelProvider.Get(p.promise.ChainID, p.provider, p.hermesID)
 			if !found {
@@ -1158,6 +1186,7 @@ func (aps *hermesPromiseSettler) listenForSettlement(hermesID, beneficiary commo
 					she := SettlementHistoryEntry{
 						TxHash:           info.Raw.TxHash,
 						BlockExplorerURL: uri,
+						BlockNumber:      info.Raw.BlockNumber,
 						ProviderID:       provider,
 						HermesID:         hermesID,
 						// TODO: this should probably be either provider channel address or the consumer address from the promise, not truncated provider channel address.
@@ -1420,13 +1449,10 @@ func formTXUrl(txHash string, chainID int64) (string, error) {
 	}
 }
 
-func (aps *hermesPromiseSettler) validateBeneficiary(chainID int64, identity, currentBeneficiary common.Address) (common.Address, bool, error) {
-	localBeneficiaryAddress, err := aps.beneficiaryLocalStorage.Address(identity.Hex())
+func (aps *hermesPromiseSettler) validateBeneficiary(chainID int64, provider, currentBeneficiary common.Address) (common.Address, bool, error) {
+	localBeneficiaryAddress, err := aps.localBeneficiary(provider)
 	if err != nil {
-		if !errors.Is(err, beneficiary.ErrNotFound) {
-			return common.Address{}, false, fmt.Errorf("could not get local beneficiary address: %w", err)
-		}
-		localBeneficiaryAddress = ""
+		return common.Address{}, false, err
 	}
 	beneficiary := currentBeneficiary
 	changed := false
@@ -1434,12 +1460,28 @@ func (aps *hermesPromiseSettler) validateBeneficiary(chainID int64, identity, cu
 		beneficiary = common.HexToAddress(localBeneficiaryAddress)
 		changed = true
 	}
-	beneficiaryEqualToAddress, err := aps.isBenenficiarySetToChannel(aps.chainID(), identity, beneficiary)
+	beneficiaryEqualToAddress, err := aps.isBenenficiarySetToChannel(aps.chainID(), provider, beneficiary)
 	if err != nil {
 		return common.Address{}, false, fmt.Errorf("could not verify beneficiary and channel equality: %w", err)
 	}
 	if beneficiaryEqualToAddress {
-		return common.Address{}, false, fmt.Errorf("payment channel for identity %s is set as beneficiary, skip settling", identity.Hex())
+		return common.Address{}, false, fmt.Errorf("payment channel for identity %s is set as beneficiary, skip settling", provider.Hex())
 	}
 	return beneficiary, changed, nil
 }
+
+// localBeneficiary returns the beneficiary overridden for the provider, or the saved one, empty if there is none.
+func (aps *hermesPromiseSettler) localBeneficiary(provider common.Address) (string, error) {
+	if address := overridesOf(aps.overrides, identity.FromAddress(provider.Hex())).Beneficiary; address != "" {
+		return address, nil
+	}
+
+	address, err := aps.beneficiaryLocalStorage.Address(provider.Hex())
+	if err != nil {
+		if !errors.Is(err, beneficiary.ErrNotFound) {
+			return "", fmt.Errorf("could not get local beneficiary address: %w", err)
+		}
+		return "", nil
+	}
+	return address, nil
+}
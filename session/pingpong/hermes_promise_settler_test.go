@@ -28,6 +28,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/identity/overrides"
 	"github.com/mysteriumnetwork/node/identity/registry"
 	"github.com/mysteriumnetwork/node/session/pingpong/event"
 	"github.com/mysteriumnetwork/payments/bindings"
@@ -298,6 +299,34 @@ func TestPromiseSettler_handleHermesPromiseReceivedWithLocalBeneficiary(t *testi
 	assert.Equal(t, mockID, p.provider)
 }
 
+func TestPromiseSettler_validateBeneficiaryPrefersOverride(t *testing.T) {
+	lbs := newMockAddressStorage()
+	settler := NewHermesPromiseSettler(&mockTransactor{}, &mockHermesPromiseStorage{}, &mockPayAndSettler{}, &mockAddressProvider{}, nil, &mockHermesURLGetter{}, &mockHermesChannelProvider{}, &mockProviderChannelStatusProvider{}, &mockRegistrationStatusProvider{}, identity.NewMockKeystore(), &settlementHistoryStorageMock{}, &mockPublisher{}, &mockObserver{}, lbs, cfg)
+
+	saved := common.HexToAddress("0x0000000000000000000000000000000000000133")
+	assert.NoError(t, lbs.Save(mockID.Address, saved.Hex()))
+
+	got, changed, err := settler.validateBeneficiary(1, mockID.ToCommonAddress(), beneficiaryID)
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, saved, got)
+
+	override := common.HexToAddress("0x0000000000000000000000000000000000000144")
+	settler.SetOverrides(&mockIdentityOverrides{overrides: map[identity.Identity]overrides.Overrides{
+		mockID: {Beneficiary: override.Hex()},
+	}})
+
+	got, changed, err = settler.validateBeneficiary(1, mockID.ToCommonAddress(), beneficiaryID)
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, override, got)
+
+	got, changed, err = settler.validateBeneficiary(1, mockID.ToCommonAddress(), override)
+	assert.NoError(t, err)
+	assert.False(t, changed)
+	assert.Equal(t, override, got)
+}
+
 func TestPromiseSettler_doNotSettleIfBeneficiaryIsAChannel(t *testing.T) {
 	channelProvider := &mockHermesChannelProvider{}
 	channelStatusProvider := &mockProviderChannelStatusProvider{}
@@ -547,6 +576,43 @@ func TestPromiseSettler_AcceptsIfFeesDoNotExceedSettlementAmount(t *testing.T) {
 	assert.True(t, ok)
 }
 
+func TestPromiseSettler_PreviewSettlement(t *testing.T) {
+	channelProvider := &mockHermesChannelProvider{}
+	bc := &mockProviderChannelStatusProvider{calculatedFees: big.NewInt(20000)}
+	tm := &mockTransactor{
+		feesToReturn: registry.FeesResponse{Fee: big.NewInt(5000)},
+		idToReturn:   "should-not-be-used",
+	}
+	settler := NewHermesPromiseSettler(tm, &mockHermesPromiseStorage{}, &mockPayAndSettler{}, &mockAddressProvider{}, (&mockHermesCallerFactory{}).Get, &mockHermesURLGetter{}, channelProvider, bc, &mockRegistrationStatusProvider{}, identity.NewMockKeystore(), &settlementHistoryStorageMock{}, &mockPublisher{}, &mockObserver{}, newMockAddressStorage(), cfg)
+
+	channel := client.ProviderChannel{Settled: big.NewInt(6000), Stake: big.NewInt(1000)}
+	promise := crypto.Promise{Amount: big.NewInt(35000)}
+	channelProvider.channelToReturn = NewHermesChannel("1", mockID, hermesID, channel, HermesPromise{Promise: promise}, beneficiaryID)
+
+	newBeneficiary := common.HexToAddress("0x00000000000000000000000000000000000000133")
+	previews, err := settler.PreviewSettlement(1, mockID, newBeneficiary, hermesID)
+	assert.NoError(t, err)
+	assert.Equal(t, []SettlementPreview{{
+		HermesID:          hermesID,
+		Beneficiary:       newBeneficiary,
+		BeneficiaryChange: true,
+		Amount:            big.NewInt(29000),
+		TransactorFee:     big.NewInt(5000),
+		HermesFee:         big.NewInt(20000),
+		Payout:            big.NewInt(4000),
+		WillSettle:        true,
+	}}, previews)
+
+	bc.calculatedFees = big.NewInt(25000)
+	previews, err = settler.PreviewSettlement(1, mockID, beneficiaryID, hermesID)
+	assert.NoError(t, err)
+	assert.Len(t, previews, 1)
+	assert.False(t, previews[0].WillSettle)
+	assert.False(t, previews[0].BeneficiaryChange)
+	assert.Equal(t, errFeeNotCovered.Error(), previews[0].Reason)
+	assert.Equal(t, big.NewInt(0), previews[0].Payout)
+}
+
 func TestPromiseSettlerState_needsSettling(t *testing.T) {
 	hps := &hermesPromiseSettler{
 		transactor: &mockTransactor{
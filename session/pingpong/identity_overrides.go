@@ -0,0 +1,47 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package pingpong
+
+import (
+	"errors"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/identity/overrides"
+)
+
+type identityOverrides interface {
+	Get(id identity.Identity) (overrides.Overrides, error)
+}
+
+// overridesOf returns overrides of the identity, zero value if there are none or overrides are not set.
+func overridesOf(o identityOverrides, id identity.Identity) overrides.Overrides {
+	if o == nil {
+		return overrides.Overrides{}
+	}
+
+	result, err := o.Get(id)
+	if err != nil {
+		if !errors.Is(err, overrides.ErrNotFound) {
+			log.Warn().Err(err).Msgf("Could not get overrides of identity %s", id.Address)
+		}
+		return overrides.Overrides{}
+	}
+	return result
+}
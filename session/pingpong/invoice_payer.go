@@ -36,6 +36,7 @@ import (
 	"github.com/mysteriumnetwork/node/identity"
 	"github.com/mysteriumnetwork/node/market"
 	"github.com/mysteriumnetwork/node/session/pingpong/event"
+	"github.com/mysteriumnetwork/node/trace"
 	"github.com/mysteriumnetwork/payments/crypto"
 )
 
@@ -159,7 +160,9 @@ func (ip *InvoicePayer) Start() error {
 				return errors.Wrap(err, "invoice not valid")
 			}
 
+			start := time.Now()
 			err = ip.issueExchangeMessage(invoice)
+			ip.publishExchangeSpan(invoice, start, err)
 			if err != nil {
 				return err
 			}
@@ -289,6 +292,28 @@ func (ip *InvoicePayer) publishInvoicePayedEvent(invoice crypto.Invoice) {
 	})
 }
 
+func (ip *InvoicePayer) publishExchangeSpan(invoice crypto.Invoice, start time.Time, err error) {
+	ip.sessionIDLock.Lock()
+	sessionID := ip.deps.SessionID
+	ip.sessionIDLock.Unlock()
+
+	span := trace.Span{
+		ID:    sessionID,
+		Name:  "Consumer payment exchange",
+		Start: start,
+		End:   time.Now(),
+		Attributes: map[string]string{
+			"agreement.id":    invoice.AgreementID.String(),
+			"agreement.total": invoice.AgreementTotal.String(),
+			"hermes.address":  ip.deps.HermesAddress.Hex(),
+		},
+	}
+	if err != nil {
+		span.Error = err.Error()
+	}
+	ip.deps.EventBus.Publish(trace.AppTopicTraceSpan, span)
+}
+
 // Stop stops the message tracker.
 func (ip *InvoicePayer) Stop() {
 	ip.once.Do(func() {
@@ -134,6 +134,12 @@ type InvoiceTracker struct {
 
 	lastExchangeMessage     crypto.ExchangeMessage
 	lastExchangeMessageLock sync.Mutex
+
+	paused      bool
+	pausedAt    time.Duration
+	pausedTotal time.Duration
+	refund      uint8
+	chargeLock  sync.Mutex
 }
 
 // InvoiceTrackerDeps contains all the deps needed for invoice tracker.
@@ -280,7 +286,7 @@ func (it *InvoiceTracker) Start() error {
 	log.Debug().Msgf("Starting invoice tracker for session %s", it.deps.SessionID)
 	it.deps.TimeTracker.StartTracking()
 
-	if err := it.deps.EventBus.SubscribeWithUID(sessionEvent.AppTopicDataTransferred, it.deps.SessionID, it.consumeDataTransferredEvent); err != nil {
+	if err := it.deps.EventBus.SubscribeWithUID(sessionEvent.AppTopicDataTransferredBatch, it.deps.SessionID, it.consumeDataTransferredBatchEvent); err != nil {
 		return err
 	}
 
@@ -347,23 +353,23 @@ func (it *InvoiceTracker) Start() error {
 }
 
 func (it *InvoiceTracker) sendInvoicesWhenNeeded(interval time.Duration) {
-	it.lastInvoiceSent = it.deps.TimeTracker.Elapsed()
+	it.lastInvoiceSent = it.elapsed()
 	for {
 		select {
 		case <-it.stop:
 			return
 		case <-time.After(interval):
-			currentlyElapsed := it.deps.TimeTracker.Elapsed()
-			shouldBe := CalculatePaymentAmount(currentlyElapsed, it.getDataTransferred(), it.deps.AgreedPrice)
+			currentlyElapsed := it.elapsed()
+			shouldBe := it.chargeableAmount(currentlyElapsed)
 			lastEM := it.getLastExchangeMessage()
 			diff := safeSub(shouldBe, lastEM.AgreementTotal)
 			if diff.Cmp(it.deps.MaxNotPaidInvoice) >= 0 && currentlyElapsed-it.lastInvoiceSent > it.invoiceDebounceRate {
-				it.lastInvoiceSent = it.deps.TimeTracker.Elapsed()
+				it.lastInvoiceSent = it.elapsed()
 				it.invoiceChannel <- true
 
 				it.updateMaxUnpaid()
 			} else if currentlyElapsed-it.lastInvoiceSent > it.deps.ChargePeriod {
-				it.lastInvoiceSent = it.deps.TimeTracker.Elapsed()
+				it.lastInvoiceSent = it.elapsed()
 				it.invoiceChannel <- false
 
 				it.updateTimer()
@@ -492,13 +498,16 @@ func (it *InvoiceTracker) sendInvoice(isCritical bool) error {
 		return ErrExchangeWaitTimeout
 	}
 
-	shouldBe := CalculatePaymentAmount(it.deps.TimeTracker.Elapsed(), it.getDataTransferred(), it.deps.AgreedPrice)
+	shouldBe := it.chargeableAmount(it.elapsed())
 
 	lastEm := it.getLastExchangeMessage()
 	if lastEm.AgreementTotal.Cmp(big.NewInt(0)) == 0 && shouldBe.Cmp(big.NewInt(0)) == 1 {
 		// The first invoice should have minimal static value.
 		shouldBe = providerFirstInvoiceValue
 		log.Debug().Msgf("Being lenient for the first payment, asking for %v", shouldBe)
+	} else if shouldBe.Cmp(lastEm.AgreementTotal) < 0 {
+		// Refunded session is not charged until its price catches up with the already paid amount.
+		shouldBe = new(big.Int).Set(lastEm.AgreementTotal)
 	}
 
 	r, err := crypto.GenerateR()
@@ -683,11 +692,105 @@ func (it *InvoiceTracker) validateExchangeMessage(em crypto.ExchangeMessage) err
 func (it *InvoiceTracker) Stop() {
 	it.once.Do(func() {
 		log.Debug().Msgf("Stopping invoice tracker for session %s", it.deps.SessionID)
-		_ = it.deps.EventBus.UnsubscribeWithUID(sessionEvent.AppTopicDataTransferred, it.deps.SessionID, it.consumeDataTransferredEvent)
+		_ = it.deps.EventBus.UnsubscribeWithUID(sessionEvent.AppTopicDataTransferredBatch, it.deps.SessionID, it.consumeDataTransferredBatchEvent)
 		close(it.stop)
 	})
 }
 
+// Pause stops charging for the session time until the tracker is resumed.
+func (it *InvoiceTracker) Pause() {
+	it.chargeLock.Lock()
+	defer it.chargeLock.Unlock()
+
+	if it.paused {
+		return
+	}
+
+	log.Debug().Msgf("Pausing invoice tracker for session %s", it.deps.SessionID)
+	it.paused = true
+	it.pausedAt = it.deps.TimeTracker.Elapsed()
+}
+
+// Resume continues charging for the session time.
+func (it *InvoiceTracker) Resume() {
+	it.chargeLock.Lock()
+	defer it.chargeLock.Unlock()
+
+	if !it.paused {
+		return
+	}
+
+	log.Debug().Msgf("Resuming invoice tracker for session %s", it.deps.SessionID)
+	it.paused = false
+	it.pausedTotal += it.deps.TimeTracker.Elapsed() - it.pausedAt
+}
+
+// Refund reduces the price of the whole session by the given share in percent.
+func (it *InvoiceTracker) Refund(percent uint8) {
+	if percent > 100 {
+		percent = 100
+	}
+
+	it.chargeLock.Lock()
+	defer it.chargeLock.Unlock()
+
+	log.Debug().Msgf("Refunding %d%% of session %s price", percent, it.deps.SessionID)
+	it.refund = percent
+}
+
+// Resync forgives invoices which could not be delivered or paid while the consumer was unreachable
+// after changing its network and sends a fresh invoice right away, so the consumer settles what it owes.
+func (it *InvoiceTracker) Resync() {
+	log.Debug().Msgf("Resyncing invoices of session %s", it.deps.SessionID)
+	it.resetNotReceivedExchangeMessageCount()
+	it.resetNotSentExchangeMessageCount()
+
+	go func() {
+		select {
+		case it.invoiceChannel <- false:
+		case <-it.stop:
+		}
+	}()
+}
+
+// chargeableAmount returns the amount consumer has to pay for the session, taking the refund into account.
+func (it *InvoiceTracker) chargeableAmount(elapsed time.Duration) *big.Int {
+	amount := CalculatePaymentAmount(elapsed, it.getDataTransferred(), it.deps.AgreedPrice)
+
+	it.chargeLock.Lock()
+	refund := it.refund
+	it.chargeLock.Unlock()
+
+	if refund == 0 {
+		return amount
+	}
+
+	amount.Mul(amount, big.NewInt(int64(100-refund)))
+	return amount.Div(amount, big.NewInt(100))
+}
+
+// elapsed returns the chargeable session time, which excludes the time session was paused.
+func (it *InvoiceTracker) elapsed() time.Duration {
+	it.chargeLock.Lock()
+	defer it.chargeLock.Unlock()
+
+	elapsed := it.deps.TimeTracker.Elapsed()
+	if it.paused {
+		elapsed = it.pausedAt
+	}
+
+	return elapsed - it.pausedTotal
+}
+
+func (it *InvoiceTracker) consumeDataTransferredBatchEvent(e sessionEvent.AppEventDataTransferredBatch) {
+	for _, transferred := range e.Sessions {
+		if strings.EqualFold(transferred.ID, it.deps.SessionID) {
+			it.consumeDataTransferredEvent(transferred)
+			return
+		}
+	}
+}
+
 func (it *InvoiceTracker) consumeDataTransferredEvent(e sessionEvent.AppEventDataTransferred) {
 	// skip irrelevant sessions
 	if !strings.EqualFold(e.ID, it.deps.SessionID) {
@@ -925,6 +925,60 @@ func TestInvoiceTracker_validateExchangeMessage(t *testing.T) {
 	}
 }
 
+func TestInvoiceTracker_PausedTimeIsNotCharged(t *testing.T) {
+	tracker := &mockTimeTracker{}
+	it := NewInvoiceTracker(InvoiceTrackerDeps{TimeTracker: tracker})
+
+	tracker.timeToReturn = time.Minute
+	assert.Equal(t, time.Minute, it.elapsed())
+
+	it.Pause()
+	tracker.timeToReturn = 5 * time.Minute
+	assert.Equal(t, time.Minute, it.elapsed())
+
+	it.Resume()
+	tracker.timeToReturn = 6 * time.Minute
+	assert.Equal(t, 2*time.Minute, it.elapsed())
+
+	// pausing twice does not lose the paused time
+	it.Pause()
+	it.Pause()
+	tracker.timeToReturn = 10 * time.Minute
+	it.Resume()
+	it.Resume()
+	assert.Equal(t, 2*time.Minute, it.elapsed())
+}
+
+func TestInvoiceTracker_RefundReducesChargeableAmount(t *testing.T) {
+	it := NewInvoiceTracker(InvoiceTrackerDeps{
+		AgreedPrice: market.Price{PricePerHour: big.NewInt(1000), PricePerGiB: big.NewInt(0)},
+	})
+	assert.Equal(t, "1000", it.chargeableAmount(time.Hour).String())
+
+	it.Refund(20)
+	assert.Equal(t, "800", it.chargeableAmount(time.Hour).String())
+
+	it.Refund(200)
+	assert.Equal(t, "0", it.chargeableAmount(time.Hour).String())
+}
+
+func TestInvoiceTracker_ResyncSendsInvoiceAndResetsFailures(t *testing.T) {
+	it := NewInvoiceTracker(InvoiceTrackerDeps{})
+	it.markExchangeMessageNotReceived()
+	it.markExchangeMessageNotSent()
+
+	it.Resync()
+	assert.Zero(t, it.getNotReceivedExchangeMessageCount())
+	assert.Zero(t, it.getNotSentExchangeMessageCount())
+
+	select {
+	case critical := <-it.invoiceChannel:
+		assert.False(t, critical)
+	case <-time.After(time.Second):
+		t.Fatal("invoice was not requested")
+	}
+}
+
 type mockHermesStatusChecker struct {
 	statusToReturn HermesStatus
 	errToReturn    error
@@ -22,6 +22,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/session/pingpong"
 )
 
 // NoopHermesPromiseSettler doesn't do much.
@@ -43,6 +44,11 @@ func (n *NoopHermesPromiseSettler) SettleWithBeneficiary(chainID int64, _ identi
 	return nil
 }
 
+// PreviewSettlement does nothing.
+func (n *NoopHermesPromiseSettler) PreviewSettlement(chainID int64, _ identity.Identity, _ common.Address, _ ...common.Address) ([]pingpong.SettlementPreview, error) {
+	return nil, nil
+}
+
 // GetHermesFee does absolutely nothing.
 func (n *NoopHermesPromiseSettler) GetHermesFee(chainID int64, _ common.Address) (uint16, error) {
 	return 0, nil
@@ -0,0 +1,362 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package pingpong
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/mysteriumnetwork/payments/crypto"
+)
+
+// The promise exchange with hermes runs for every paid invoice, so the hot structs
+// are encoded and decoded by hand instead of going through encoding/json reflection.
+// The output is byte for byte the same as encoding/json produces, and any input
+// the decoder does not recognise is handed over to encoding/json.
+
+// AppendJSON appends the JSON encoding of the promise request to dst.
+func (rp RequestPromise) AppendJSON(dst []byte) []byte {
+	dst = append(dst, `{"exchange_message":`...)
+	dst = appendExchangeMessageJSON(dst, rp.ExchangeMessage)
+	dst = append(dst, `,"transactor_fee":`...)
+	dst = appendBigIntJSON(dst, rp.TransactorFee)
+	dst = append(dst, `,"r_recovery_data":`...)
+	dst = appendStringJSON(dst, rp.RRecoveryData)
+	return append(dst, '}')
+}
+
+// AppendJSON appends the JSON encoding of the R recovery details to dst.
+func (rd rRecoveryDetails) AppendJSON(dst []byte) []byte {
+	dst = append(dst, `{"r":`...)
+	dst = appendStringJSON(dst, rd.R)
+	dst = append(dst, `,"agreement_id":`...)
+	dst = appendBigIntJSON(dst, rd.AgreementID)
+	return append(dst, '}')
+}
+
+func appendExchangeMessageJSON(dst []byte, em crypto.ExchangeMessage) []byte {
+	dst = append(dst, `{"Promise":`...)
+	dst = appendPromiseJSON(dst, em.Promise)
+	dst = append(dst, `,"AgreementID":`...)
+	dst = appendBigIntJSON(dst, em.AgreementID)
+	dst = append(dst, `,"AgreementTotal":`...)
+	dst = appendBigIntJSON(dst, em.AgreementTotal)
+	dst = append(dst, `,"Provider":`...)
+	dst = appendStringJSON(dst, em.Provider)
+	dst = append(dst, `,"Signature":`...)
+	dst = appendStringJSON(dst, em.Signature)
+	dst = append(dst, `,"HermesID":`...)
+	dst = appendStringJSON(dst, em.HermesID)
+	dst = append(dst, `,"ChainID":`...)
+	dst = strconv.AppendInt(dst, em.ChainID, 10)
+	return append(dst, '}')
+}
+
+func appendPromiseJSON(dst []byte, p crypto.Promise) []byte {
+	dst = append(dst, `{"ChannelID":`...)
+	dst = appendBytesJSON(dst, p.ChannelID)
+	dst = append(dst, `,"ChainID":`...)
+	dst = strconv.AppendInt(dst, p.ChainID, 10)
+	dst = append(dst, `,"Amount":`...)
+	dst = appendBigIntJSON(dst, p.Amount)
+	dst = append(dst, `,"Fee":`...)
+	dst = appendBigIntJSON(dst, p.Fee)
+	dst = append(dst, `,"Hashlock":`...)
+	dst = appendBytesJSON(dst, p.Hashlock)
+	dst = append(dst, `,"R":`...)
+	dst = appendBytesJSON(dst, p.R)
+	dst = append(dst, `,"Signature":`...)
+	dst = appendBytesJSON(dst, p.Signature)
+	return append(dst, '}')
+}
+
+func appendBigIntJSON(dst []byte, v *big.Int) []byte {
+	if v == nil {
+		return append(dst, "null"...)
+	}
+	if v.IsInt64() {
+		return strconv.AppendInt(dst, v.Int64(), 10)
+	}
+	return v.Append(dst, 10)
+}
+
+func appendBytesJSON(dst []byte, b []byte) []byte {
+	if b == nil {
+		return append(dst, "null"...)
+	}
+
+	n := base64.StdEncoding.EncodedLen(len(b))
+	start := len(dst) + 1
+	if cap(dst)-len(dst) < n+2 {
+		grown := make([]byte, len(dst), len(dst)+n+2)
+		copy(grown, dst)
+		dst = grown
+	}
+	dst = append(dst, '"')
+	dst = dst[:start+n]
+	base64.StdEncoding.Encode(dst[start:], b)
+	return append(dst, '"')
+}
+
+// appendStringJSON appends the string as is when nothing in it needs escaping,
+// which holds for the hex values of payment messages.
+func appendStringJSON(dst []byte, s string) []byte {
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; c < 0x20 || c >= utf8.RuneSelf || c == '"' || c == '\\' || c == '<' || c == '>' || c == '&' {
+			// Marshalling a string can not fail.
+			encoded, _ := json.Marshal(s)
+			return append(dst, encoded...)
+		}
+	}
+
+	dst = append(dst, '"')
+	dst = append(dst, s...)
+	return append(dst, '"')
+}
+
+type jsonDecoder interface {
+	decodeJSON(data []byte) error
+}
+
+// promiseJSON decodes hermes promise responses.
+type promiseJSON crypto.Promise
+
+func (p *promiseJSON) decodeJSON(data []byte) error {
+	var promise crypto.Promise
+	if !decodePromiseJSON(data, &promise) {
+		return json.Unmarshal(data, (*crypto.Promise)(p))
+	}
+
+	*p = promiseJSON(promise)
+	return nil
+}
+
+func decodePromiseJSON(data []byte, p *crypto.Promise) bool {
+	s := jsonScanner{data: data}
+	if !s.consume('{') {
+		return false
+	}
+	if s.consume('}') {
+		return s.end()
+	}
+
+	for {
+		key, ok := s.str()
+		if !ok || !s.consume(':') {
+			return false
+		}
+
+		switch {
+		case s.null():
+		case bytes.EqualFold(key, []byte("ChannelID")):
+			p.ChannelID, ok = s.base64()
+		case bytes.EqualFold(key, []byte("ChainID")):
+			p.ChainID, ok = s.int64()
+		case bytes.EqualFold(key, []byte("Amount")):
+			p.Amount, ok = s.bigInt()
+		case bytes.EqualFold(key, []byte("Fee")):
+			p.Fee, ok = s.bigInt()
+		case bytes.EqualFold(key, []byte("Hashlock")):
+			p.Hashlock, ok = s.base64()
+		case bytes.EqualFold(key, []byte("R")):
+			p.R, ok = s.base64()
+		case bytes.EqualFold(key, []byte("Signature")):
+			p.Signature, ok = s.base64()
+		default:
+			ok = s.skipScalar()
+		}
+		if !ok {
+			return false
+		}
+
+		if s.consume(',') {
+			continue
+		}
+		if s.consume('}') {
+			return s.end()
+		}
+		return false
+	}
+}
+
+// jsonScanner reads the flat JSON objects of payment messages. It only accepts
+// a strict subset of JSON and reports anything else as not ok.
+type jsonScanner struct {
+	data []byte
+	pos  int
+}
+
+func (s *jsonScanner) skipSpace() {
+	for s.pos < len(s.data) {
+		switch s.data[s.pos] {
+		case ' ', '\t', '\n', '\r':
+			s.pos++
+		default:
+			return
+		}
+	}
+}
+
+func (s *jsonScanner) consume(c byte) bool {
+	s.skipSpace()
+	if s.pos < len(s.data) && s.data[s.pos] == c {
+		s.pos++
+		return true
+	}
+	return false
+}
+
+func (s *jsonScanner) literal(lit string) bool {
+	s.skipSpace()
+	if !bytes.HasPrefix(s.data[s.pos:], []byte(lit)) {
+		return false
+	}
+	s.pos += len(lit)
+	return true
+}
+
+func (s *jsonScanner) null() bool {
+	return s.literal("null")
+}
+
+func (s *jsonScanner) end() bool {
+	s.skipSpace()
+	return s.pos == len(s.data)
+}
+
+// str reads a string without escape sequences.
+func (s *jsonScanner) str() ([]byte, bool) {
+	if !s.consume('"') {
+		return nil, false
+	}
+
+	start := s.pos
+	for s.pos < len(s.data) {
+		c := s.data[s.pos]
+		switch {
+		case c == '"':
+			s.pos++
+			return s.data[start : s.pos-1], true
+		case c == '\\' || c < 0x20 || c >= utf8.RuneSelf:
+			return nil, false
+		}
+		s.pos++
+	}
+	return nil, false
+}
+
+// integer reads a number without fraction and exponent parts.
+func (s *jsonScanner) integer() ([]byte, bool) {
+	s.skipSpace()
+
+	start := s.pos
+	if s.pos < len(s.data) && s.data[s.pos] == '-' {
+		s.pos++
+	}
+	digits := s.pos
+	for s.pos < len(s.data) && s.data[s.pos] >= '0' && s.data[s.pos] <= '9' {
+		s.pos++
+	}
+
+	switch {
+	case s.pos == digits:
+		return nil, false
+	case s.data[digits] == '0' && s.pos-digits > 1:
+		return nil, false
+	case s.pos < len(s.data) && (s.data[s.pos] == '.' || s.data[s.pos] == 'e' || s.data[s.pos] == 'E'):
+		return nil, false
+	}
+	return s.data[start:s.pos], true
+}
+
+func (s *jsonScanner) int64() (int64, bool) {
+	num, ok := s.integer()
+	if !ok {
+		return 0, false
+	}
+	return parseInt64(num)
+}
+
+func (s *jsonScanner) bigInt() (*big.Int, bool) {
+	num, ok := s.integer()
+	if !ok {
+		return nil, false
+	}
+	if v, ok := parseInt64(num); ok {
+		return big.NewInt(v), true
+	}
+	return new(big.Int).SetString(string(num), 10)
+}
+
+// parseInt64 parses the integer without allocating, reporting overflows as not ok.
+func parseInt64(num []byte) (int64, bool) {
+	neg := num[0] == '-'
+	if neg {
+		num = num[1:]
+	}
+	if len(num) > 18 {
+		return 0, false
+	}
+
+	var v int64
+	for _, c := range num {
+		v = v*10 + int64(c-'0')
+	}
+	if neg {
+		v = -v
+	}
+	return v, true
+}
+
+func (s *jsonScanner) base64() ([]byte, bool) {
+	encoded, ok := s.str()
+	if !ok {
+		return nil, false
+	}
+
+	b := make([]byte, base64.StdEncoding.DecodedLen(len(encoded)))
+	n, err := base64.StdEncoding.Decode(b, encoded)
+	if err != nil {
+		return nil, false
+	}
+	return b[:n], true
+}
+
+func (s *jsonScanner) skipScalar() bool {
+	s.skipSpace()
+	if s.pos == len(s.data) {
+		return false
+	}
+
+	switch s.data[s.pos] {
+	case '"':
+		_, ok := s.str()
+		return ok
+	case 't':
+		return s.literal("true")
+	case 'f':
+		return s.literal("false")
+	default:
+		_, ok := s.integer()
+		return ok
+	}
+}
@@ -0,0 +1,168 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package pingpong
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/payments/crypto"
+)
+
+func testExchangeMessage() crypto.ExchangeMessage {
+	return crypto.ExchangeMessage{
+		Promise: crypto.Promise{
+			ChannelID: []byte{0x1, 0x2, 0x3},
+			ChainID:   137,
+			Amount:    big.NewInt(1_000_000_000_000),
+			Fee:       big.NewInt(250),
+			Hashlock:  []byte("hashlock"),
+			R:         []byte{},
+			Signature: []byte("signature"),
+		},
+		AgreementID:    new(big.Int).Lsh(big.NewInt(1), 200),
+		AgreementTotal: big.NewInt(-5),
+		Provider:       "0x75c2067ca5b42467fd6cd789d785aafb52a6b95b",
+		Signature:      "0xabcdef",
+		HermesID:       "0x80ed28d84792d8b153bf2f25f0c4b7a1381de4ab",
+		ChainID:        137,
+	}
+}
+
+func TestRequestPromise_AppendJSON(t *testing.T) {
+	for name, rp := range map[string]RequestPromise{
+		"full": {
+			ExchangeMessage: testExchangeMessage(),
+			TransactorFee:   big.NewInt(42),
+			RRecoveryData:   "0123456789abcdef",
+		},
+		"empty": {},
+		"escaped strings": {
+			ExchangeMessage: crypto.ExchangeMessage{Provider: "<\"provider\"\n>", HermesID: "hermes & ąčę"},
+			RRecoveryData:   "\\",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			expected, err := json.Marshal(rp)
+			assert.NoError(t, err)
+			assert.Equal(t, string(expected), string(rp.AppendJSON(nil)))
+		})
+	}
+}
+
+func TestRRecoveryDetails_AppendJSON(t *testing.T) {
+	details := rRecoveryDetails{R: "abcdef", AgreementID: big.NewInt(12)}
+
+	expected, err := json.Marshal(details)
+	assert.NoError(t, err)
+	assert.Equal(t, string(expected), string(details.AppendJSON(nil)))
+}
+
+func TestPromiseJSON_DecodeJSON(t *testing.T) {
+	encoded, err := json.Marshal(testExchangeMessage().Promise)
+	assert.NoError(t, err)
+
+	for name, data := range map[string]string{
+		"encoded promise":  string(encoded),
+		"empty object":     `{}`,
+		"nulls":            `{"ChannelID":null,"ChainID":null,"Amount":null,"Fee":null,"Hashlock":null,"R":null,"Signature":null}`,
+		"folded keys":      ` { "channelid" : "AQID" , "chainid" : 5 , "AMOUNT" : 10 } `,
+		"unknown fields":   `{"ChainID":1,"extra":"value","flag":true,"other":false,"number":-3,"nothing":null}`,
+		"nested unknown":   `{"ChainID":1,"nested":{"a":[1,2]},"Fee":7}`,
+		"escaped base64":   `{"ChannelID":"AQID"}`,
+		"fraction":         `{"Amount":1.5}`,
+		"exponent":         `{"ChainID":1e3}`,
+		"leading zero":     `{"ChainID":01}`,
+		"overflow":         `{"ChainID":92233720368547758070}`,
+		"invalid base64":   `{"R":"!!!"}`,
+		"string number":    `{"Amount":"10"}`,
+		"trailing data":    `{"ChainID":1}}`,
+		"trailing comma":   `{"ChainID":1,}`,
+		"not an object":    `[]`,
+		"empty":            ``,
+		"duplicated field": `{"ChainID":1,"ChainID":2}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			var expected crypto.Promise
+			expectedErr := json.Unmarshal([]byte(data), &expected)
+
+			var decoded crypto.Promise
+			err := (*promiseJSON)(&decoded).decodeJSON([]byte(data))
+
+			if expectedErr != nil {
+				assert.EqualError(t, err, expectedErr.Error())
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, expected, decoded)
+		})
+	}
+}
+
+func BenchmarkRequestPromiseEncoding(b *testing.B) {
+	rp := RequestPromise{
+		ExchangeMessage: testExchangeMessage(),
+		TransactorFee:   big.NewInt(42),
+		RRecoveryData:   "0123456789abcdef0123456789abcdef0123456789abcdef",
+	}
+
+	b.Run("encoding/json", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := json.Marshal(rp); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("codec", func(b *testing.B) {
+		b.ReportAllocs()
+		buf := make([]byte, 0, 1024)
+		for i := 0; i < b.N; i++ {
+			buf = rp.AppendJSON(buf[:0])
+		}
+	})
+}
+
+func BenchmarkPromiseDecoding(b *testing.B) {
+	data, err := json.Marshal(testExchangeMessage().Promise)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.Run("encoding/json", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var p crypto.Promise
+			if err := json.Unmarshal(data, &p); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("codec", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var p crypto.Promise
+			if err := (*promiseJSON)(&p).decodeJSON(data); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
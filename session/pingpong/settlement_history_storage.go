@@ -20,6 +20,7 @@ package pingpong
 import (
 	"errors"
 	"math/big"
+	"sort"
 	"time"
 
 	"github.com/asdine/storm/v3"
@@ -57,10 +58,11 @@ func NewSettlementHistoryStorage(bolt *boltdb.Bolt) *SettlementHistoryStorage {
 type SettlementHistoryEntry struct {
 	TxHash           common.Hash `storm:"id"`
 	BlockExplorerURL string
-	ProviderID       identity.Identity
+	BlockNumber      uint64
+	ProviderID       identity.Identity `storm:"index"`
 	HermesID         common.Address
 	ChannelAddress   common.Address
-	Time             time.Time
+	Time             time.Time `storm:"index"`
 	Promise          crypto.Promise
 	Beneficiary      common.Address
 	Amount           *big.Int
@@ -115,18 +117,41 @@ func (shs *SettlementHistoryStorage) List(filter SettlementHistoryFilter) (resul
 
 	shs.bolt.RLock()
 	defer shs.bolt.RUnlock()
-	sq := shs.bolt.DB().
-		From(settlementHistoryBucket).
-		Select(q.And(where...)).
-		OrderBy("Time").
-		Reverse()
-
-	err = sq.Find(&result)
+	node := shs.bolt.DB().From(settlementHistoryBucket)
+
+	var candidates []SettlementHistoryEntry
+	switch {
+	case filter.ProviderID != nil && *filter.ProviderID != (identity.Identity{}):
+		err = node.Find("ProviderID", *filter.ProviderID, &candidates)
+	case filter.TimeFrom != nil:
+		min, max := boltdb.TimeIndexRange(*filter.TimeFrom, filter.TimeTo)
+		err = node.Range("Time", min, max, &candidates)
+	default:
+		err = node.All(&candidates)
+	}
 	if errors.Is(err, storm.ErrNotFound) {
 		return []SettlementHistoryEntry{}, nil
 	}
+	if err != nil {
+		return nil, err
+	}
+
+	matcher := q.And(where...)
+	result = make([]SettlementHistoryEntry, 0, len(candidates))
+	for i := range candidates {
+		ok, err := matcher.Match(&candidates[i])
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			result = append(result, candidates[i])
+		}
+	}
 
-	return result, err
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Time.After(result[j].Time)
+	})
+	return result, nil
 }
 
 func contains(sources []HistoryType, target HistoryType) bool {
@@ -45,6 +45,7 @@ func TestSettlementHistoryStorage(t *testing.T) {
 	providerID := identity.FromAddress("0x79bb2a1c5E0075005F084a66A44D5e930A88eC86")
 	entry1 := SettlementHistoryEntry{
 		TxHash:       common.BigToHash(big.NewInt(1)),
+		BlockNumber:  45132071,
 		ProviderID:   providerID,
 		HermesID:     hermesAddress,
 		Time:         time.Date(2020, 1, 1, 1, 0, 0, 0, time.UTC),
@@ -55,6 +56,7 @@ func TestSettlementHistoryStorage(t *testing.T) {
 	}
 	entry2 := SettlementHistoryEntry{
 		TxHash:       common.BigToHash(big.NewInt(2)),
+		BlockNumber:  45132098,
 		ProviderID:   providerID,
 		HermesID:     hermesAddress,
 		Time:         time.Date(2020, 1, 1, 2, 0, 0, 0, time.UTC),
@@ -92,4 +94,25 @@ func TestSettlementHistoryStorage(t *testing.T) {
 		assert.Len(t, entries, 2)
 		assert.EqualValues(t, []SettlementHistoryEntry{entry2, entry1}, entries)
 	})
+
+	t.Run("Filters by indexed fields", func(t *testing.T) {
+		entries, err := storage.List(SettlementHistoryFilter{ProviderID: &providerID})
+		assert.NoError(t, err)
+		assert.EqualValues(t, []SettlementHistoryEntry{entry2, entry1}, entries)
+
+		otherID := identity.FromAddress("0x0000000000000000000000000000000000000001")
+		entries, err = storage.List(SettlementHistoryFilter{ProviderID: &otherID})
+		assert.NoError(t, err)
+		assert.EqualValues(t, []SettlementHistoryEntry{}, entries)
+
+		from := entry2.Time
+		entries, err = storage.List(SettlementHistoryFilter{TimeFrom: &from})
+		assert.NoError(t, err)
+		assert.EqualValues(t, []SettlementHistoryEntry{entry2}, entries)
+
+		to := entry2.Time.Add(-time.Nanosecond)
+		entries, err = storage.List(SettlementHistoryFilter{ProviderID: &providerID, TimeTo: &to})
+		assert.NoError(t, err)
+		assert.EqualValues(t, []SettlementHistoryEntry{entry1}, entries)
+	})
 }
@@ -0,0 +1,109 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package pingpong
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/mysteriumnetwork/node/identity"
+)
+
+// SettlementPreview describes the expected outcome of a settlement with a single hermes.
+// Nothing is sent to transactor or the blockchain while preparing the preview.
+type SettlementPreview struct {
+	HermesID common.Address
+	// Beneficiary receives the settled earnings, BeneficiaryChange is set if settlement would also change it.
+	Beneficiary       common.Address
+	BeneficiaryChange bool
+	// Amount is the unsettled earnings of the channel.
+	Amount        *big.Int
+	TransactorFee *big.Int
+	HermesFee     *big.Int
+	// Payout is the amount expected to reach the beneficiary after fees.
+	Payout *big.Int
+	// WillSettle is false if the settlement would be rejected, Reason tells why.
+	WillSettle bool
+	Reason     string
+}
+
+// PreviewSettlement simulates the settlement with the given hermeses without submitting it.
+// Hermes fee is calculated by the hermes contract call and transactor fee is the current transactor quote.
+// Zero beneficiary means the beneficiary the settlement would use on its own.
+func (aps *hermesPromiseSettler) PreviewSettlement(chainID int64, providerID identity.Identity, beneficiary common.Address, hermesIDs ...common.Address) ([]SettlementPreview, error) {
+	fees, err := aps.transactor.FetchSettleFees(chainID)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch settle fees: %w", err)
+	}
+	transactorFee := new(big.Int)
+	if fees.Fee != nil {
+		transactorFee.Set(fees.Fee)
+	}
+
+	previews := make([]SettlementPreview, 0, len(hermesIDs))
+	for _, hermesID := range hermesIDs {
+		channel, err := aps.channelProvider.Fetch(chainID, providerID, hermesID)
+		if err != nil {
+			return nil, fmt.Errorf("could not fetch channel with hermes %q: %w", hermesID.Hex(), ErrNothingToSettle)
+		}
+
+		preview := SettlementPreview{
+			HermesID:      hermesID,
+			Beneficiary:   beneficiary,
+			Amount:        channel.UnsettledBalance(),
+			TransactorFee: new(big.Int).Set(transactorFee),
+			HermesFee:     new(big.Int),
+			Payout:        new(big.Int),
+		}
+		if beneficiary == (common.Address{}) {
+			preview.Beneficiary, preview.BeneficiaryChange, err = aps.validateBeneficiary(chainID, providerID.ToCommonAddress(), channel.Beneficiary)
+			if err != nil {
+				preview.Reason = err.Error()
+				previews = append(previews, preview)
+				continue
+			}
+		} else {
+			preview.BeneficiaryChange = beneficiary != channel.Beneficiary
+		}
+
+		if preview.Amount.Sign() > 0 {
+			preview.HermesFee, err = aps.bc.CalculateHermesFee(chainID, hermesID, preview.Amount)
+			if err != nil {
+				return nil, fmt.Errorf("could not calculate hermes fee: %w", err)
+			}
+		}
+		totalFees := new(big.Int).Add(preview.HermesFee, preview.TransactorFee)
+		preview.Payout = safeSub(preview.Amount, totalFees)
+
+		switch {
+		case aps.isSettling(providerID, hermesID):
+			preview.Reason = "provider already has settlement in progress"
+		case preview.Amount.Sign() <= 0:
+			preview.Reason = ErrNothingToSettle.Error()
+		case totalFees.Cmp(preview.Amount) > 0:
+			preview.Reason = errFeeNotCovered.Error()
+		default:
+			preview.WillSettle = true
+		}
+		previews = append(previews, preview)
+	}
+
+	return previews, nil
+}
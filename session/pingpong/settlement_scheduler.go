@@ -0,0 +1,223 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package pingpong
+
+import (
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/config"
+	nodevent "github.com/mysteriumnetwork/node/core/node/event"
+	"github.com/mysteriumnetwork/node/eventbus"
+	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/payments/units"
+)
+
+// SettlementSchedulerConfig configures the automatic settlement scheduler.
+type SettlementSchedulerConfig struct {
+	// CheckInterval is how often unsettled balances are checked.
+	CheckInterval time.Duration
+	// ThresholdAmount triggers a settlement once the unsettled balance of a channel reaches it. Nil or zero disables it.
+	ThresholdAmount *big.Int
+	// StakeThreshold triggers a settlement once the unsettled balance of a channel reaches
+	// the given fraction of the channel stake, e.g. 0.1 for 10%. Zero disables it.
+	StakeThreshold float64
+	// MinInterval is the minimum time between automatic settlements of the same channel.
+	MinInterval time.Duration
+	// MaxGasPrice postpones settlements while the suggested gas price is above it. Nil or zero disables it.
+	MaxGasPrice *big.Int
+}
+
+type scheduledChannelProvider interface {
+	List(chainID int64) []HermesChannel
+}
+
+type gasPriceSuggester interface {
+	SuggestGasPrice(chainID int64) (*big.Int, error)
+}
+
+type asyncSettler interface {
+	ForceSettleAsync(chainID int64, providerID identity.Identity, hermesIDs ...common.Address) error
+}
+
+// SettlementScheduler periodically settles channels whose unsettled balance exceeds the configured thresholds.
+// Settlement threshold overridden by the identity takes precedence over the configured threshold amount.
+type SettlementScheduler struct {
+	channels  scheduledChannelProvider
+	gas       gasPriceSuggester
+	settler   asyncSettler
+	config    SettlementSchedulerConfig
+	overrides identityOverrides
+
+	lastSettled map[string]time.Time
+	lock        sync.Mutex
+	stop        chan struct{}
+	running     bool
+}
+
+// NewSettlementScheduler creates a new instance of the settlement scheduler.
+func NewSettlementScheduler(channels scheduledChannelProvider, gas gasPriceSuggester, settler asyncSettler, config SettlementSchedulerConfig) *SettlementScheduler {
+	return &SettlementScheduler{
+		channels:    channels,
+		gas:         gas,
+		settler:     settler,
+		config:      config,
+		lastSettled: make(map[string]time.Time),
+	}
+}
+
+// SetOverrides sets the identity overrides of settlement threshold, it must be called before Start.
+func (ss *SettlementScheduler) SetOverrides(o identityOverrides) {
+	ss.overrides = o
+}
+
+// Subscribe starts and stops the scheduler together with the node.
+func (ss *SettlementScheduler) Subscribe(bus eventbus.Subscriber) error {
+	return bus.SubscribeAsync(nodevent.AppTopicNode, ss.handleNodeEvent)
+}
+
+func (ss *SettlementScheduler) handleNodeEvent(payload nodevent.Payload) {
+	switch payload.Status {
+	case nodevent.StatusStarted:
+		ss.Start()
+	case nodevent.StatusStopped:
+		ss.Stop()
+	}
+}
+
+// Start starts checking unsettled balances in the background.
+func (ss *SettlementScheduler) Start() {
+	ss.lock.Lock()
+	defer ss.lock.Unlock()
+
+	if ss.running {
+		return
+	}
+	ss.running = true
+	ss.stop = make(chan struct{})
+	go ss.run(ss.stop)
+}
+
+// Stop stops the scheduler.
+func (ss *SettlementScheduler) Stop() {
+	ss.lock.Lock()
+	defer ss.lock.Unlock()
+
+	if !ss.running {
+		return
+	}
+	ss.running = false
+	close(ss.stop)
+}
+
+func (ss *SettlementScheduler) run(stop <-chan struct{}) {
+	log.Info().Msgf("Settlement scheduler started, checking every %s", ss.config.CheckInterval)
+	defer log.Info().Msg("Settlement scheduler stopped")
+
+	ticker := time.NewTicker(ss.config.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			ss.check(config.GetInt64(config.FlagChainID), time.Now())
+		}
+	}
+}
+
+func (ss *SettlementScheduler) check(chainID int64, now time.Time) {
+	due := make(map[identity.Identity][]common.Address)
+	var dueChannels []string
+
+	ss.lock.Lock()
+	for _, channel := range ss.channels.List(chainID) {
+		if !ss.exceedsThreshold(channel) {
+			continue
+		}
+		if last, ok := ss.lastSettled[channel.ChannelID]; ok && now.Sub(last) < ss.config.MinInterval {
+			log.Debug().Msgf("Channel %s is over the settlement threshold, but was settled at %s", channel.ChannelID, last)
+			continue
+		}
+		due[channel.Identity] = append(due[channel.Identity], channel.HermesID)
+		dueChannels = append(dueChannels, channel.ChannelID)
+	}
+	ss.lock.Unlock()
+
+	if len(due) == 0 || !ss.gasPriceAcceptable(chainID) {
+		return
+	}
+
+	ss.lock.Lock()
+	for _, id := range dueChannels {
+		ss.lastSettled[id] = now
+	}
+	ss.lock.Unlock()
+
+	for id, hermeses := range due {
+		log.Info().Msgf("Unsettled balance of %s is over the threshold, settling with %d hermes(es)", id.Address, len(hermeses))
+		if err := ss.settler.ForceSettleAsync(chainID, id, hermeses...); err != nil {
+			log.Err(err).Msgf("Scheduled settlement failed for %s", id.Address)
+		}
+	}
+}
+
+func (ss *SettlementScheduler) exceedsThreshold(channel HermesChannel) bool {
+	unsettled := channel.UnsettledBalance()
+	if unsettled.Sign() <= 0 {
+		return false
+	}
+
+	thresholdAmount := ss.config.ThresholdAmount
+	if threshold := overridesOf(ss.overrides, channel.Identity).SettleThreshold; threshold != nil {
+		thresholdAmount = units.FloatEthToBigIntWei(*threshold)
+	}
+	if thresholdAmount != nil && thresholdAmount.Sign() > 0 && unsettled.Cmp(thresholdAmount) >= 0 {
+		return true
+	}
+
+	stake := channel.Channel.Stake
+	if ss.config.StakeThreshold > 0 && stake != nil && stake.Sign() > 0 {
+		threshold, _ := new(big.Float).Mul(new(big.Float).SetInt(stake), big.NewFloat(ss.config.StakeThreshold)).Int(nil)
+		return unsettled.Cmp(threshold) >= 0
+	}
+
+	return false
+}
+
+func (ss *SettlementScheduler) gasPriceAcceptable(chainID int64) bool {
+	if ss.config.MaxGasPrice == nil || ss.config.MaxGasPrice.Sign() <= 0 {
+		return true
+	}
+
+	price, err := ss.gas.SuggestGasPrice(chainID)
+	if err != nil {
+		log.Err(err).Msg("Could not get gas price, postponing scheduled settlements")
+		return false
+	}
+	if price.Cmp(ss.config.MaxGasPrice) > 0 {
+		log.Info().Msgf("Gas price %s is above the maximum of %s, postponing scheduled settlements", price, ss.config.MaxGasPrice)
+		return false
+	}
+	return true
+}
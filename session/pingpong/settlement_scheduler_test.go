@@ -0,0 +1,177 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package pingpong
+
+import (
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/mysteriumnetwork/payments/client"
+	"github.com/mysteriumnetwork/payments/crypto"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/identity/overrides"
+)
+
+func TestSettlementScheduler_Check(t *testing.T) {
+	hermesID := common.HexToAddress("0x1")
+	provider := identity.FromAddress("0x2")
+	channel := func(id string, stake, settled, promised int64) HermesChannel {
+		return NewHermesChannel(id, provider, hermesID, client.ProviderChannel{
+			Stake:   big.NewInt(stake),
+			Settled: big.NewInt(settled),
+		}, HermesPromise{Promise: crypto.Promise{Amount: big.NewInt(promised)}}, common.Address{})
+	}
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	oneMyst := 1.0
+
+	for name, test := range map[string]struct {
+		config    SettlementSchedulerConfig
+		overrides map[identity.Identity]overrides.Overrides
+		channels  []HermesChannel
+		gas       *mockGasPriceSuggester
+		settled   int
+	}{
+		"settles over absolute threshold": {
+			config:   SettlementSchedulerConfig{ThresholdAmount: big.NewInt(100)},
+			channels: []HermesChannel{channel("1", 0, 50, 150)},
+			settled:  1,
+		},
+		"skips under absolute threshold": {
+			config:   SettlementSchedulerConfig{ThresholdAmount: big.NewInt(100)},
+			channels: []HermesChannel{channel("1", 0, 50, 149)},
+		},
+		"settles over threshold overridden by identity": {
+			overrides: map[identity.Identity]overrides.Overrides{provider: {SettleThreshold: &oneMyst}},
+			channels:  []HermesChannel{channel("1", 0, 0, 2_000_000_000_000_000_000)},
+			settled:   1,
+		},
+		"skips under threshold overridden by identity": {
+			config:    SettlementSchedulerConfig{ThresholdAmount: big.NewInt(100)},
+			overrides: map[identity.Identity]overrides.Overrides{provider: {SettleThreshold: &oneMyst}},
+			channels:  []HermesChannel{channel("1", 0, 50, 150)},
+		},
+		"settles over stake threshold": {
+			config:   SettlementSchedulerConfig{StakeThreshold: 0.1},
+			channels: []HermesChannel{channel("1", 1000, 0, 100)},
+			settled:  1,
+		},
+		"skips without stake": {
+			config:   SettlementSchedulerConfig{StakeThreshold: 0.1},
+			channels: []HermesChannel{channel("1", 0, 0, 100)},
+		},
+		"postpones on high gas price": {
+			config:   SettlementSchedulerConfig{ThresholdAmount: big.NewInt(100), MaxGasPrice: big.NewInt(10)},
+			channels: []HermesChannel{channel("1", 0, 0, 100)},
+			gas:      &mockGasPriceSuggester{price: big.NewInt(11)},
+		},
+		"postpones when gas price is unknown": {
+			config:   SettlementSchedulerConfig{ThresholdAmount: big.NewInt(100), MaxGasPrice: big.NewInt(10)},
+			channels: []HermesChannel{channel("1", 0, 0, 100)},
+			gas:      &mockGasPriceSuggester{err: errors.New("boom")},
+		},
+		"settles on acceptable gas price": {
+			config:   SettlementSchedulerConfig{ThresholdAmount: big.NewInt(100), MaxGasPrice: big.NewInt(10)},
+			channels: []HermesChannel{channel("1", 0, 0, 100)},
+			gas:      &mockGasPriceSuggester{price: big.NewInt(10)},
+			settled:  1,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			settler := &mockAsyncSettler{}
+			gas := test.gas
+			if gas == nil {
+				gas = &mockGasPriceSuggester{price: big.NewInt(1)}
+			}
+			scheduler := NewSettlementScheduler(&mockScheduledChannelProvider{channels: test.channels}, gas, settler, test.config)
+			scheduler.SetOverrides(&mockIdentityOverrides{overrides: test.overrides})
+
+			scheduler.check(1, now)
+
+			assert.Len(t, settler.calls, test.settled)
+		})
+	}
+}
+
+func TestSettlementScheduler_RespectsMinInterval(t *testing.T) {
+	channels := &mockScheduledChannelProvider{channels: []HermesChannel{
+		NewHermesChannel("1", identity.FromAddress("0x2"), common.HexToAddress("0x1"), client.ProviderChannel{
+			Stake:   big.NewInt(0),
+			Settled: big.NewInt(0),
+		}, HermesPromise{Promise: crypto.Promise{Amount: big.NewInt(100)}}, common.Address{}),
+	}}
+	settler := &mockAsyncSettler{}
+	scheduler := NewSettlementScheduler(channels, &mockGasPriceSuggester{}, settler, SettlementSchedulerConfig{
+		ThresholdAmount: big.NewInt(100),
+		MinInterval:     time.Hour,
+	})
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	scheduler.check(1, now)
+	scheduler.check(1, now.Add(59*time.Minute))
+	assert.Len(t, settler.calls, 1)
+
+	scheduler.check(1, now.Add(time.Hour))
+	assert.Len(t, settler.calls, 2)
+}
+
+type mockScheduledChannelProvider struct {
+	channels []HermesChannel
+}
+
+func (m *mockScheduledChannelProvider) List(chainID int64) []HermesChannel {
+	return m.channels
+}
+
+type mockGasPriceSuggester struct {
+	price *big.Int
+	err   error
+}
+
+func (m *mockGasPriceSuggester) SuggestGasPrice(chainID int64) (*big.Int, error) {
+	return m.price, m.err
+}
+
+type mockAsyncSettler struct {
+	lock  sync.Mutex
+	calls [][]common.Address
+}
+
+func (m *mockAsyncSettler) ForceSettleAsync(chainID int64, providerID identity.Identity, hermesIDs ...common.Address) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.calls = append(m.calls, hermesIDs)
+	return nil
+}
+
+type mockIdentityOverrides struct {
+	overrides map[identity.Identity]overrides.Overrides
+}
+
+func (m *mockIdentityOverrides) Get(id identity.Identity) (overrides.Overrides, error) {
+	o, ok := m.overrides[id]
+	if !ok {
+		return overrides.Overrides{}, overrides.ErrNotFound
+	}
+	return o, nil
+}
@@ -0,0 +1,90 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package session
+
+// ProtocolVersion is the session setup protocol version implemented by this node.
+// Peers which do not announce the version are treated as version 0.
+const ProtocolVersion uint32 = 1
+
+// Capability is a bitmap of optional session features.
+type Capability uint64
+
+const (
+	// CapabilityReconciliation means session accounting is reconciled at the session end.
+	CapabilityReconciliation Capability = 1 << iota
+)
+
+// LocalCapabilities is a set of capabilities supported by this node.
+// Compression of control messages is negotiated separately during the p2p handshake.
+const LocalCapabilities = CapabilityReconciliation
+
+var capabilityNames = []struct {
+	capability Capability
+	name       string
+}{
+	{CapabilityReconciliation, "reconciliation"},
+}
+
+// Names returns names of the known capabilities in the set.
+func (c Capability) Names() []string {
+	names := make([]string, 0)
+	for _, cn := range capabilityNames {
+		if c&cn.capability != 0 {
+			names = append(names, cn.name)
+		}
+	}
+	return names
+}
+
+// Protocol describes session setup protocol version and capabilities.
+type Protocol struct {
+	Version      uint32
+	Capabilities Capability
+}
+
+// LocalProtocol returns protocol supported by this node.
+func LocalProtocol() Protocol {
+	return Protocol{
+		Version:      ProtocolVersion,
+		Capabilities: LocalCapabilities,
+	}
+}
+
+// Negotiate returns protocol which both sides support.
+func (p Protocol) Negotiate(peer Protocol) Protocol {
+	version := p.Version
+	if peer.Version < version {
+		version = peer.Version
+	}
+	return Protocol{
+		Version:      version,
+		Capabilities: p.Capabilities & peer.Capabilities,
+	}
+}
+
+// Has reports whether given capability was negotiated.
+func (p Protocol) Has(c Capability) bool {
+	return p.Capabilities&c == c
+}
+
+// Peer describes the node software version and the capabilities announced by the other side of a session.
+// Both are empty for peers which do not announce them.
+type Peer struct {
+	Version      string
+	Capabilities Capability
+}
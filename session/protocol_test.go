@@ -0,0 +1,42 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package session
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProtocol_Negotiate(t *testing.T) {
+	local := Protocol{Version: 2, Capabilities: CapabilityReconciliation}
+
+	negotiated := local.Negotiate(Protocol{Version: 1, Capabilities: CapabilityReconciliation | 1<<10})
+	assert.Equal(t, Protocol{Version: 1, Capabilities: CapabilityReconciliation}, negotiated)
+	assert.True(t, negotiated.Has(CapabilityReconciliation))
+	assert.False(t, negotiated.Has(1<<10))
+
+	legacy := local.Negotiate(Protocol{})
+	assert.Equal(t, Protocol{}, legacy)
+	assert.Empty(t, legacy.Capabilities.Names())
+}
+
+func TestCapability_Names(t *testing.T) {
+	assert.Equal(t, []string{"reconciliation"}, LocalCapabilities.Names())
+	assert.Empty(t, Capability(1<<10).Names())
+}
@@ -0,0 +1,124 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package sla
+
+import (
+	"sync"
+	"time"
+
+	"github.com/mysteriumnetwork/node/eventbus"
+	"github.com/mysteriumnetwork/node/market"
+	"github.com/mysteriumnetwork/node/session"
+	"github.com/mysteriumnetwork/node/session/event"
+)
+
+// Monitor measures the service level of provider sessions on the provider side,
+// so that consumer claims of SLA breaches can be checked against it.
+type Monitor struct {
+	window time.Duration
+	now    func() time.Time
+
+	lock     sync.Mutex
+	sessions map[string]*monitoredSession
+}
+
+type monitoredSession struct {
+	sla     market.SLA
+	started time.Time
+	tracker *Tracker
+	breach  string
+}
+
+// NewMonitor creates a new provider side SLA monitor.
+func NewMonitor(window time.Duration) *Monitor {
+	return &Monitor{
+		window:   window,
+		now:      time.Now,
+		sessions: make(map[string]*monitoredSession),
+	}
+}
+
+// Subscribe subscribes to provider session events.
+func (m *Monitor) Subscribe(bus eventbus.Subscriber) error {
+	if err := bus.SubscribeAsync(event.AppTopicDataTransferredBatch, m.handleDataTransferredBatch); err != nil {
+		return err
+	}
+	return bus.SubscribeAsync(event.AppTopicSession, m.handleSession)
+}
+
+// Breach returns the breach of the session SLA measured by the provider, empty if there is none.
+func (m *Monitor) Breach(sessionID session.ID) string {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	s, ok := m.sessions[string(sessionID)]
+	if !ok {
+		return ""
+	}
+	return s.breach
+}
+
+func (m *Monitor) handleSession(e event.AppEventSession) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	switch e.Status {
+	case event.CreatedStatus:
+		sla := e.Session.Proposal.SLA
+		if sla == nil {
+			return
+		}
+		m.sessions[e.Session.ID] = &monitoredSession{
+			sla:     *sla,
+			started: e.Session.StartedAt,
+		}
+	case event.AcknowledgedStatus:
+		s, ok := m.sessions[e.Session.ID]
+		if !ok || s.tracker != nil {
+			return
+		}
+		now := m.now()
+		s.tracker = NewTracker(s.sla, m.window, now)
+		if s.breach == "" {
+			s.breach = SetupBreach(s.sla, now.Sub(s.started))
+		}
+	case event.RemovedStatus:
+		delete(m.sessions, e.Session.ID)
+	}
+}
+
+func (m *Monitor) handleDataTransferredBatch(e event.AppEventDataTransferredBatch) {
+	for _, transferred := range e.Sessions {
+		m.handleDataTransferred(transferred)
+	}
+}
+
+func (m *Monitor) handleDataTransferred(e event.AppEventDataTransferred) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	s, ok := m.sessions[e.ID]
+	if !ok || s.tracker == nil || s.sla.MinThroughput == 0 {
+		return
+	}
+
+	reason := s.tracker.Update(e.Up+e.Down, m.now())
+	if s.breach == "" {
+		s.breach = reason
+	}
+}
@@ -0,0 +1,97 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package sla
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/market"
+	"github.com/mysteriumnetwork/node/session/event"
+)
+
+func newTestMonitor() (*Monitor, *time.Time) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	m := NewMonitor(10 * time.Second)
+	m.now = func() time.Time { return now }
+	return m, &now
+}
+
+func sessionEvent(status event.Status, startedAt time.Time, sla *market.SLA) event.AppEventSession {
+	return event.AppEventSession{
+		Status: status,
+		Session: event.SessionContext{
+			ID:        "s1",
+			StartedAt: startedAt,
+			Proposal:  market.ServiceProposal{SLA: sla},
+		},
+	}
+}
+
+func TestMonitor_NoBreachWhenSLAFulfilled(t *testing.T) {
+	m, now := newTestMonitor()
+	sla := &market.SLA{MinThroughput: 8000, MaxSetupTime: 2000}
+
+	m.handleSession(sessionEvent(event.CreatedStatus, *now, sla))
+	*now = now.Add(time.Second)
+	m.handleSession(sessionEvent(event.AcknowledgedStatus, *now, sla))
+
+	*now = now.Add(10 * time.Second)
+	m.handleDataTransferred(event.AppEventDataTransferred{ID: "s1", Up: 5000, Down: 5000})
+
+	assert.Empty(t, m.Breach("s1"))
+}
+
+func TestMonitor_DetectsSetupBreach(t *testing.T) {
+	m, now := newTestMonitor()
+	sla := &market.SLA{MaxSetupTime: 2000}
+
+	m.handleSession(sessionEvent(event.CreatedStatus, *now, sla))
+	*now = now.Add(3 * time.Second)
+	m.handleSession(sessionEvent(event.AcknowledgedStatus, *now, sla))
+
+	assert.Equal(t, "setup took 3s, promised 2s", m.Breach("s1"))
+
+	m.handleSession(sessionEvent(event.RemovedStatus, *now, sla))
+	assert.Empty(t, m.Breach("s1"))
+}
+
+func TestMonitor_DetectsThroughputBreach(t *testing.T) {
+	m, now := newTestMonitor()
+	sla := &market.SLA{MinThroughput: 8000}
+
+	m.handleSession(sessionEvent(event.CreatedStatus, *now, sla))
+	m.handleSession(sessionEvent(event.AcknowledgedStatus, *now, sla))
+
+	*now = now.Add(10 * time.Second)
+	m.handleDataTransferred(event.AppEventDataTransferred{ID: "s1", Up: 2000, Down: 3000})
+
+	assert.Equal(t, "throughput 4000 bps is below 8000 bps", m.Breach("s1"))
+}
+
+func TestMonitor_IgnoresSessionsWithoutSLA(t *testing.T) {
+	m, now := newTestMonitor()
+
+	m.handleSession(sessionEvent(event.CreatedStatus, *now, nil))
+	*now = now.Add(time.Hour)
+	m.handleSession(sessionEvent(event.AcknowledgedStatus, *now, nil))
+
+	assert.Empty(t, m.Breach("s1"))
+}
@@ -0,0 +1,84 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package sla
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mysteriumnetwork/node/market"
+)
+
+// DefaultWindow is the period over which the session throughput is measured against the SLA.
+const DefaultWindow = 30 * time.Second
+
+// Tracker measures the session throughput over windows of continuous traffic and
+// compares it with the throughput promised by the provider.
+type Tracker struct {
+	minThroughput uint64
+	window        time.Duration
+
+	lastBytes   uint64
+	windowBytes uint64
+	windowStart time.Time
+}
+
+// NewTracker creates a new throughput tracker starting its first window at the given time.
+func NewTracker(sla market.SLA, window time.Duration, now time.Time) *Tracker {
+	return &Tracker{
+		minThroughput: sla.MinThroughput,
+		window:        window,
+		windowStart:   now,
+	}
+}
+
+// Update takes the total bytes transferred in the session and returns the breach reason, empty if there is none.
+// Idle connection does not breach the SLA, so the measurement window restarts whenever no traffic was seen.
+func (t *Tracker) Update(total uint64, now time.Time) string {
+	if total == t.lastBytes {
+		t.Reset(now)
+		return ""
+	}
+	t.lastBytes = total
+
+	elapsed := now.Sub(t.windowStart)
+	if elapsed < t.window {
+		return ""
+	}
+
+	throughput := uint64(float64(total-t.windowBytes) * 8 / elapsed.Seconds())
+	t.Reset(now)
+	if throughput < t.minThroughput {
+		return fmt.Sprintf("throughput %d bps is below %d bps", throughput, t.minThroughput)
+	}
+	return ""
+}
+
+// Reset restarts the measurement window at the given time.
+func (t *Tracker) Reset(now time.Time) {
+	t.windowBytes = t.lastBytes
+	t.windowStart = now
+}
+
+// SetupBreach returns the breach reason if the session took longer to set up than promised.
+func SetupBreach(sla market.SLA, setupTime time.Duration) string {
+	if sla.MaxSetupTime == 0 || setupTime <= sla.SetupTimeLimit() {
+		return ""
+	}
+	return fmt.Sprintf("setup took %s, promised %s", setupTime.Round(time.Millisecond), sla.SetupTimeLimit())
+}
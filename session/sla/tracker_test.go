@@ -0,0 +1,51 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package sla
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/market"
+)
+
+func TestTracker_Update(t *testing.T) {
+	now := time.Now()
+	tracker := NewTracker(market.SLA{MinThroughput: 8000}, 10*time.Second, now)
+
+	// 10 KB in 10 seconds is 8000 bps, which fulfills the SLA.
+	assert.Empty(t, tracker.Update(5000, now.Add(5*time.Second)))
+	assert.Empty(t, tracker.Update(10000, now.Add(10*time.Second)))
+
+	// Idle connection restarts the window instead of breaching.
+	assert.Empty(t, tracker.Update(10000, now.Add(20*time.Second)))
+
+	// 5 KB in 10 seconds is 4000 bps.
+	assert.Empty(t, tracker.Update(12000, now.Add(25*time.Second)))
+	assert.Equal(t, "throughput 4000 bps is below 8000 bps", tracker.Update(15000, now.Add(30*time.Second)))
+}
+
+func TestSetupBreach(t *testing.T) {
+	sla := market.SLA{MaxSetupTime: 2000}
+
+	assert.Empty(t, SetupBreach(sla, 2*time.Second))
+	assert.Equal(t, "setup took 3s, promised 2s", SetupBreach(sla, 3*time.Second))
+	assert.Empty(t, SetupBreach(market.SLA{}, time.Hour))
+}
@@ -0,0 +1,203 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package stats
+
+import (
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/eventbus"
+	"github.com/mysteriumnetwork/node/session/event"
+)
+
+// DefaultInterval is the default interval of session statistics sampling.
+const DefaultInterval = time.Second
+
+// Source returns the bytes transferred by a session so far.
+type Source func() (up, down uint64, err error)
+
+type counter struct {
+	id       string
+	up, down uint64
+	source   Source
+	updated  bool
+}
+
+func (c *counter) reset() {
+	*c = counter{}
+}
+
+// Sampler keeps the transfer counters of all active sessions and samples them
+// with a single ticker, publishing one batch event per interval
+// instead of every session publishing its own events.
+type Sampler struct {
+	bus      eventbus.Publisher
+	interval time.Duration
+
+	lock     sync.Mutex
+	counters map[string]*counter
+	pool     sync.Pool
+
+	// snapshot is reused between samples and is only accessed from the sampling goroutine.
+	snapshot []counter
+
+	once sync.Once
+	stop chan struct{}
+}
+
+// NewSampler creates a new session statistics sampler.
+func NewSampler(bus eventbus.Publisher, interval time.Duration) *Sampler {
+	return &Sampler{
+		bus:      bus,
+		interval: interval,
+		counters: make(map[string]*counter),
+		pool: sync.Pool{
+			New: func() interface{} {
+				return new(counter)
+			},
+		},
+		stop: make(chan struct{}),
+	}
+}
+
+// Subscribe subscribes to session events to release the counters of removed sessions.
+func (s *Sampler) Subscribe(bus eventbus.Subscriber) error {
+	return bus.SubscribeAsync(event.AppTopicSession, s.handleSession)
+}
+
+// Track starts sampling the session transfer counters from the given source.
+func (s *Sampler) Track(sessionID string, source Source) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	c := s.counterFor(sessionID)
+	c.source = source
+}
+
+// Update records the bytes transferred by the session for services
+// which push their statistics instead of being polled.
+func (s *Sampler) Update(sessionID string, up, down uint64) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	c := s.counterFor(sessionID)
+	c.up, c.down = up, down
+	c.updated = true
+}
+
+// Untrack stops sampling the session and releases its counter.
+func (s *Sampler) Untrack(sessionID string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	c, ok := s.counters[sessionID]
+	if !ok {
+		return
+	}
+	delete(s.counters, sessionID)
+	c.reset()
+	s.pool.Put(c)
+}
+
+// Start starts sampling session statistics.
+func (s *Sampler) Start() {
+	go s.run()
+}
+
+// Stop stops sampling session statistics.
+func (s *Sampler) Stop() {
+	s.once.Do(func() {
+		close(s.stop)
+	})
+}
+
+func (s *Sampler) run() {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.sample()
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+func (s *Sampler) sample() {
+	s.lock.Lock()
+	s.snapshot = s.snapshot[:0]
+	for _, c := range s.counters {
+		s.snapshot = append(s.snapshot, *c)
+	}
+	s.lock.Unlock()
+
+	if len(s.snapshot) == 0 {
+		return
+	}
+
+	// Subscribers may still hold the previous batch, so every batch gets its own slice.
+	sessions := make([]event.AppEventDataTransferred, 0, len(s.snapshot))
+	for _, c := range s.snapshot {
+		up, down := c.up, c.down
+		if c.source != nil {
+			var err error
+			up, down, err = c.source()
+			if err != nil {
+				log.Warn().Err(err).Msgf("Could not get statistics of session %s", c.id)
+				continue
+			}
+		} else if !c.updated {
+			continue
+		}
+
+		sessions = append(sessions, event.AppEventDataTransferred{
+			ID:   c.id,
+			Up:   up,
+			Down: down,
+		})
+	}
+
+	if len(sessions) == 0 {
+		return
+	}
+
+	s.bus.Publish(event.AppTopicDataTransferredBatch, event.AppEventDataTransferredBatch{
+		Sessions: sessions,
+	})
+}
+
+func (s *Sampler) counterFor(sessionID string) *counter {
+	c, ok := s.counters[sessionID]
+	if !ok {
+		c = s.pool.Get().(*counter)
+		c.id = sessionID
+		s.counters[sessionID] = c
+	}
+	return c
+}
+
+func (s *Sampler) handleSession(e event.AppEventSession) {
+	if e.Status != event.RemovedStatus {
+		return
+	}
+	s.Untrack(e.Session.ID)
+}
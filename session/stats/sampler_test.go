@@ -0,0 +1,199 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package stats
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/eventbus"
+	"github.com/mysteriumnetwork/node/session/event"
+)
+
+type mockPublisher struct {
+	lock      sync.Mutex
+	published []interface{}
+}
+
+func (p *mockPublisher) Publish(_ string, data interface{}) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.published = append(p.published, data)
+}
+
+func (p *mockPublisher) batches() []event.AppEventDataTransferredBatch {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	var batches []event.AppEventDataTransferredBatch
+	for _, data := range p.published {
+		batches = append(batches, data.(event.AppEventDataTransferredBatch))
+	}
+	return batches
+}
+
+func staticSource(up, down uint64) Source {
+	return func() (uint64, uint64, error) {
+		return up, down, nil
+	}
+}
+
+func TestSampler_PublishesSingleBatchForAllSessions(t *testing.T) {
+	// given
+	publisher := &mockPublisher{}
+	sampler := NewSampler(publisher, DefaultInterval)
+	sampler.Track("s1", staticSource(1, 2))
+	sampler.Track("s2", staticSource(3, 4))
+	sampler.Update("s3", 5, 6)
+
+	// when
+	sampler.sample()
+
+	// then
+	batches := publisher.batches()
+	assert.Len(t, batches, 1)
+	assert.ElementsMatch(t, []event.AppEventDataTransferred{
+		{ID: "s1", Up: 1, Down: 2},
+		{ID: "s2", Up: 3, Down: 4},
+		{ID: "s3", Up: 5, Down: 6},
+	}, batches[0].Sessions)
+}
+
+func TestSampler_SkipsFailedAndNotUpdatedSessions(t *testing.T) {
+	// given
+	publisher := &mockPublisher{}
+	sampler := NewSampler(publisher, DefaultInterval)
+	sampler.Track("s1", func() (uint64, uint64, error) {
+		return 0, 0, errors.New("peer is gone")
+	})
+	sampler.Update("s2", 1, 1)
+	sampler.Untrack("s2")
+	sampler.Track("s3", staticSource(7, 8))
+
+	// when
+	sampler.sample()
+
+	// then
+	batches := publisher.batches()
+	assert.Len(t, batches, 1)
+	assert.Equal(t, []event.AppEventDataTransferred{{ID: "s3", Up: 7, Down: 8}}, batches[0].Sessions)
+}
+
+func TestSampler_DoesNotPublishWithoutSessions(t *testing.T) {
+	// given
+	publisher := &mockPublisher{}
+	sampler := NewSampler(publisher, DefaultInterval)
+	sampler.Track("s1", staticSource(1, 1))
+
+	// when
+	sampler.handleSession(event.AppEventSession{
+		Status:  event.RemovedStatus,
+		Session: event.SessionContext{ID: "s1"},
+	})
+	sampler.sample()
+
+	// then
+	assert.Empty(t, publisher.batches())
+}
+
+func TestSampler_ReusesReleasedCounters(t *testing.T) {
+	// given
+	publisher := &mockPublisher{}
+	sampler := NewSampler(publisher, DefaultInterval)
+	sampler.Update("s1", 1, 1)
+	sampler.Untrack("s1")
+
+	// when
+	sampler.Track("s2", staticSource(2, 2))
+	sampler.sample()
+
+	// then
+	batches := publisher.batches()
+	assert.Len(t, batches, 1)
+	assert.Equal(t, []event.AppEventDataTransferred{{ID: "s2", Up: 2, Down: 2}}, batches[0].Sessions)
+}
+
+const benchmarkSessions = 200
+
+func benchmarkSessionIDs() []string {
+	ids := make([]string, benchmarkSessions)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("session-%d", i)
+	}
+	return ids
+}
+
+// newBenchmarkBus creates an event bus with a per-session subscriber for each session,
+// the way invoice trackers subscribe to transferred data.
+func newBenchmarkBus(b *testing.B, topic string, ids []string, handler interface{}) eventbus.EventBus {
+	level := zerolog.GlobalLevel()
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	b.Cleanup(func() {
+		zerolog.SetGlobalLevel(level)
+	})
+
+	bus := eventbus.New()
+	for _, id := range ids {
+		if err := bus.SubscribeWithUID(topic, id, handler); err != nil {
+			b.Fatal(err)
+		}
+	}
+	return bus
+}
+
+// BenchmarkPerSessionPublication measures one sampling interval of the previous design,
+// where every session published its own event.
+func BenchmarkPerSessionPublication(b *testing.B) {
+	const topic = "Session data transferred per session"
+	ids := benchmarkSessionIDs()
+	bus := newBenchmarkBus(b, topic, ids, func(event.AppEventDataTransferred) {})
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		for _, id := range ids {
+			bus.Publish(topic, event.AppEventDataTransferred{
+				ID:   id,
+				Up:   uint64(n),
+				Down: uint64(n),
+			})
+		}
+	}
+}
+
+// BenchmarkSampledBatchPublication measures one sampling interval of the sampler.
+func BenchmarkSampledBatchPublication(b *testing.B) {
+	ids := benchmarkSessionIDs()
+	bus := newBenchmarkBus(b, event.AppTopicDataTransferredBatch, ids, func(event.AppEventDataTransferredBatch) {})
+	sampler := NewSampler(bus, DefaultInterval)
+	for i, id := range ids {
+		sampler.Track(id, staticSource(uint64(i), uint64(i)))
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		sampler.sample()
+	}
+}
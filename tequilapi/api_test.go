@@ -42,8 +42,8 @@ type tequilapiTestSuite struct {
 func (testSuite *tequilapiTestSuite) SetupSuite() {
 	listener, err := net.Listen("tcp", "localhost:0")
 	assert.Nil(testSuite.T(), err)
-	testSuite.server, err = NewServer(listener, *node.GetOptions(), nil, []func(e *gin.Engine) error{func(e *gin.Engine) error {
-		e.GET("/healthcheck", endpoints.HealthCheckEndpointFactory(time.Now, os.Getpid).HealthCheck)
+	testSuite.server, err = NewServer(listener, *node.GetOptions(), nil, nil, []func(e *gin.Engine) error{func(e *gin.Engine) error {
+		e.GET("/healthcheck", endpoints.HealthCheckEndpointFactory(time.Now, os.Getpid, nil).HealthCheck)
 		return nil
 	}})
 	assert.NoError(testSuite.T(), err)
@@ -190,6 +190,20 @@ func (client *Client) Identity(identityAddress string) (id contract.IdentityDTO,
 	return id, err
 }
 
+// IdentityAttestation returns node statement signed by the identity along with the given nonce
+func (client *Client) IdentityAttestation(identityAddress, nonce string) (attestation contract.IdentityAttestationDTO, err error) {
+	path := fmt.Sprintf("identities/%s/attestation", identityAddress)
+
+	response, err := client.http.Post(path, contract.IdentityAttestationRequest{Nonce: nonce})
+	if err != nil {
+		return attestation, err
+	}
+	defer response.Body.Close()
+
+	err = parseResponseJSON(response, &attestation)
+	return attestation, err
+}
+
 // IdentityRegistrationStatus returns information of identity needed to register it on blockchain
 func (client *Client) IdentityRegistrationStatus(address string) (contract.IdentityRegistrationResponse, error) {
 	response, err := client.http.Get("identities/"+address+"/registration", url.Values{})
@@ -304,6 +318,27 @@ func (client *Client) ConnectionDestroy(port int) (err error) {
 	return nil
 }
 
+// ConnectionPause suspends data flow and invoicing of the connection keeping its session alive
+func (client *Client) ConnectionPause(port int) error {
+	return client.connectionPauseRequest("connection/pause", port)
+}
+
+// ConnectionResume restores data flow and invoicing of the paused connection
+func (client *Client) ConnectionResume(port int) error {
+	return client.connectionPauseRequest("connection/resume", port)
+}
+
+func (client *Client) connectionPauseRequest(path string, port int) error {
+	path = fmt.Sprintf("%s?%s", path, url.Values{"id": []string{strconv.Itoa(port)}}.Encode())
+	response, err := client.http.Put(path, nil)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	return nil
+}
+
 // ConnectionStatistics returns statistics about current connection
 func (client *Client) ConnectionStatistics(sessionID ...string) (statistics contract.ConnectionStatisticsDTO, err error) {
 	response, err := client.http.Get("connection/statistics", url.Values{
@@ -0,0 +1,124 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package contract
+
+import (
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/mysteriumnetwork/go-rest/apierror"
+
+	"github.com/mysteriumnetwork/node/core/beneficiary"
+)
+
+// AddressBookEntryDTO is a named payout target.
+// swagger:model AddressBookEntryDTO
+type AddressBookEntryDTO struct {
+	// example: exchange
+	Name string `json:"name"`
+
+	// example: 0x0000000000000000000000000000000000000001
+	Address string `json:"address"`
+
+	// example: deposit address
+	Note string `json:"note,omitempty"`
+
+	// example: 2024-05-01T12:00:00Z
+	CreatedAt string `json:"created_at"`
+}
+
+// NewAddressBookEntryDTO maps to API address book entry.
+func NewAddressBookEntryDTO(entry beneficiary.AddressBookEntry) AddressBookEntryDTO {
+	return AddressBookEntryDTO{
+		Name:      entry.Name,
+		Address:   entry.Address.Hex(),
+		Note:      entry.Note,
+		CreatedAt: entry.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// AddressBookResponse lists the address book entries.
+// swagger:model AddressBookResponse
+type AddressBookResponse struct {
+	Entries []AddressBookEntryDTO `json:"entries"`
+}
+
+// NewAddressBookResponse maps to API address book.
+func NewAddressBookResponse(entries []beneficiary.AddressBookEntry) AddressBookResponse {
+	res := AddressBookResponse{Entries: make([]AddressBookEntryDTO, len(entries))}
+	for i, entry := range entries {
+		res.Entries[i] = NewAddressBookEntryDTO(entry)
+	}
+	return res
+}
+
+// AddAddressBookEntryRequest adds a named payout target.
+// swagger:model AddAddressBookEntryRequest
+type AddAddressBookEntryRequest struct {
+	// name to refer to the address by, must not be an address itself
+	// example: exchange
+	Name string `json:"name"`
+
+	// example: 0x0000000000000000000000000000000000000001
+	Address string `json:"address"`
+
+	// example: deposit address
+	Note string `json:"note,omitempty"`
+}
+
+// Validate validates the request.
+func (r AddAddressBookEntryRequest) Validate() *apierror.APIError {
+	v := apierror.NewValidator()
+	if name := strings.TrimSpace(r.Name); name == "" {
+		v.Required("name")
+	} else if common.IsHexAddress(name) {
+		v.Invalid("name", "'name' must not be an address")
+	}
+	if !common.IsHexAddress(r.Address) {
+		v.Invalid("address", "'address' should be a valid hex address")
+	}
+	return v.Err()
+}
+
+// AddressConfirmationRequiredResponse is returned when the payout target has to be confirmed before it is used.
+// The request has to be repeated with the confirmation token.
+// swagger:model AddressConfirmationRequiredResponse
+type AddressConfirmationRequiredResponse struct {
+	// example: 0x0000000000000000000000000000000000000001
+	Address string `json:"address"`
+
+	// example: 5d41402abc4b2a76b9719d911017c592
+	ConfirmationToken string `json:"confirmation_token"`
+
+	// example: 2024-05-01T12:10:00Z
+	ExpiresAt string `json:"expires_at"`
+
+	// reasons the address needs the confirmation
+	Warnings []string `json:"warnings"`
+}
+
+// NewAddressConfirmationRequiredResponse maps to API confirmation request.
+func NewAddressConfirmationRequiredResponse(err *beneficiary.ConfirmationRequiredError) AddressConfirmationRequiredResponse {
+	return AddressConfirmationRequiredResponse{
+		Address:           err.Address.Hex(),
+		ConfirmationToken: err.Token,
+		ExpiresAt:         err.ExpiresAt.UTC().Format(time.RFC3339),
+		Warnings:          err.Warnings,
+	}
+}
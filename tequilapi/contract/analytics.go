@@ -0,0 +1,112 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package contract
+
+import (
+	"time"
+
+	"github.com/mysteriumnetwork/go-rest/apierror"
+
+	"github.com/mysteriumnetwork/node/core/analytics"
+)
+
+// AnalyticsSettingsRequest request used to opt in to or out of anonymous usage analytics.
+// swagger:model AnalyticsSettingsRequest
+type AnalyticsSettingsRequest struct {
+	// example: true
+	Enabled bool `json:"enabled"`
+	// categories to send
+	// example: ["sessions","nat"]
+	Categories []string `json:"categories"`
+}
+
+// Validate validates fields in request.
+func (r AnalyticsSettingsRequest) Validate() *apierror.APIError {
+	v := apierror.NewValidator()
+	for _, c := range r.Categories {
+		if !analytics.IsCategory(c) {
+			v.Invalid("categories", "Unknown category "+c)
+		}
+	}
+	return v.Err()
+}
+
+// AnalyticsSettingsResponse describes anonymous usage analytics settings.
+// swagger:model AnalyticsSettingsResponse
+type AnalyticsSettingsResponse struct {
+	// example: false
+	Enabled bool `json:"enabled"`
+	// categories to send
+	// example: ["sessions","nat"]
+	Categories []string `json:"categories"`
+	// all categories
+	// example: ["sessions","connections","discovery","nat","identity","performance"]
+	Available []string `json:"available"`
+}
+
+// NewAnalyticsSettingsResponse maps to API analytics settings.
+func NewAnalyticsSettingsResponse(settings analytics.Settings) AnalyticsSettingsResponse {
+	response := AnalyticsSettingsResponse{
+		Enabled:    settings.Enabled,
+		Categories: settings.Categories,
+		Available:  analytics.Categories,
+	}
+	if response.Categories == nil {
+		response.Categories = []string{}
+	}
+	return response
+}
+
+// AnalyticsEventDTO is an anonymized analytics event.
+// swagger:model AnalyticsEventDTO
+type AnalyticsEventDTO struct {
+	// example: sessions
+	Category string `json:"category"`
+	// example: session_event
+	Name string `json:"name"`
+	// example: 2024-01-02T15:04:05Z
+	CreatedAt time.Time `json:"created_at"`
+	// anonymized event data
+	Data interface{} `json:"data"`
+}
+
+// AnalyticsPreviewResponse lists events of the next analytics upload.
+// swagger:model AnalyticsPreviewResponse
+type AnalyticsPreviewResponse struct {
+	// set when analytics are sent, otherwise the events are only kept locally
+	// example: false
+	Enabled bool                `json:"enabled"`
+	Events  []AnalyticsEventDTO `json:"events"`
+}
+
+// NewAnalyticsPreviewResponse maps to API analytics preview.
+func NewAnalyticsPreviewResponse(settings analytics.Settings, batch analytics.Batch) AnalyticsPreviewResponse {
+	response := AnalyticsPreviewResponse{
+		Enabled: settings.Enabled,
+		Events:  make([]AnalyticsEventDTO, len(batch.Events)),
+	}
+	for i, e := range batch.Events {
+		response.Events[i] = AnalyticsEventDTO{
+			Category:  e.Category,
+			Name:      e.Name,
+			CreatedAt: e.CreatedAt,
+			Data:      e.Data,
+		}
+	}
+	return response
+}
@@ -20,6 +20,8 @@ package contract
 import (
 	"time"
 
+	"github.com/mysteriumnetwork/go-rest/apierror"
+
 	"github.com/mysteriumnetwork/node/core/auth"
 )
 
@@ -55,3 +57,78 @@ type ChangePasswordRequest struct {
 	OldPassword string `json:"old_password"`
 	NewPassword string `json:"new_password"`
 }
+
+// LoginRecoveryRequest request used to clear login lockouts.
+// swagger:model LoginRecoveryRequest
+type LoginRecoveryRequest struct {
+	// token from the nodeui-recovery-token file in the node data directory
+	Token string `json:"token"`
+}
+
+// UserDTO represents a local API user.
+// swagger:model UserDTO
+type UserDTO struct {
+	// example: alice
+	Username string `json:"username"`
+
+	// example: viewer
+	Role string `json:"role"`
+}
+
+// NewUsersResponse maps local API users to a response.
+func NewUsersResponse(users []auth.User) UsersResponse {
+	resp := UsersResponse{Users: make([]UserDTO, 0, len(users))}
+	for _, u := range users {
+		resp.Users = append(resp.Users, UserDTO{Username: u.Username, Role: string(u.Role)})
+	}
+	return resp
+}
+
+// UsersResponse lists local API users.
+// swagger:model UsersResponse
+type UsersResponse struct {
+	Users []UserDTO `json:"users"`
+}
+
+// CreateUserRequest request used to add a local API user.
+// swagger:model CreateUserRequest
+type CreateUserRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+
+	// one of: viewer, operator, admin
+	// example: operator
+	Role string `json:"role"`
+}
+
+// Validate validates fields in request.
+func (r CreateUserRequest) Validate() *apierror.APIError {
+	v := apierror.NewValidator()
+	if r.Username == "" {
+		v.Required("username")
+	}
+	if r.Password == "" {
+		v.Required("password")
+	}
+	if _, err := auth.ParseRole(r.Role); err != nil {
+		v.Invalid("role", err.Error())
+	}
+	return v.Err()
+}
+
+// UpdateUserRequest request used to change the role of a local API user.
+// swagger:model UpdateUserRequest
+type UpdateUserRequest struct {
+	// one of: viewer, operator, admin
+	// example: viewer
+	Role string `json:"role"`
+}
+
+// Validate validates fields in request.
+func (r UpdateUserRequest) Validate() *apierror.APIError {
+	v := apierror.NewValidator()
+	if _, err := auth.ParseRole(r.Role); err != nil {
+		v.Invalid("role", err.Error())
+	}
+	return v.Err()
+}
@@ -0,0 +1,72 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package contract
+
+import (
+	"github.com/mysteriumnetwork/go-rest/apierror"
+
+	"github.com/mysteriumnetwork/node/core/chainswitch"
+)
+
+// ChainSwitchRequest is received in active chain switch endpoint.
+// swagger:model ChainSwitchRequestDTO
+type ChainSwitchRequest struct {
+	// chain to make active, one of the configured chains
+	// required: true
+	// example: 137
+	ChainID int64 `json:"chain_id"`
+
+	// close active provider sessions and consumer connections instead of refusing the switch
+	// example: false
+	DrainSessions bool `json:"drain_sessions"`
+}
+
+// Validate validates fields in request.
+func (r ChainSwitchRequest) Validate() *apierror.APIError {
+	v := apierror.NewValidator()
+	if r.ChainID == 0 {
+		v.Required("chain_id")
+	}
+	return v.Err()
+}
+
+// ChainSwitchResponse describes the performed active chain switch.
+// swagger:model ChainSwitchResponseDTO
+type ChainSwitchResponse struct {
+	// example: 80001
+	PreviousChainID int64 `json:"previous_chain_id"`
+
+	// example: 137
+	ChainID int64 `json:"chain_id"`
+
+	// example: 0
+	ClosedSessions int `json:"closed_sessions"`
+
+	// example: 0
+	ClosedConnections int `json:"closed_connections"`
+}
+
+// NewChainSwitchResponse maps to API chain switch response.
+func NewChainSwitchResponse(r chainswitch.Result) ChainSwitchResponse {
+	return ChainSwitchResponse{
+		PreviousChainID:   r.PreviousChainID,
+		ChainID:           r.ChainID,
+		ClosedSessions:    r.ClosedSessions,
+		ClosedConnections: r.ClosedConnections,
+	}
+}
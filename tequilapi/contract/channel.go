@@ -18,8 +18,11 @@
 package contract
 
 import (
+	"encoding/hex"
 	"math/big"
 
+	"github.com/mysteriumnetwork/payments/client"
+
 	"github.com/mysteriumnetwork/node/session/pingpong"
 )
 
@@ -59,3 +62,113 @@ type PaymentChannelDTO struct {
 	// Beneficiary - eth wallet address
 	Beneficiary string `json:"beneficiary"`
 }
+
+// NewPaymentChannelStateDTO maps local payment channel records and the current on-chain channel state to API.
+func NewPaymentChannelStateDTO(chainID int64, channel pingpong.HermesChannel, onChain *client.ProviderChannel, onChainErr error) PaymentChannelStateDTO {
+	dto := PaymentChannelStateDTO{
+		ChainID:       chainID,
+		ID:            channel.ChannelID,
+		HermesID:      channel.HermesID.Hex(),
+		Beneficiary:   channel.Beneficiary.Hex(),
+		Local:         NewProviderChannelDTO(channel.Channel),
+		Unsettled:     NewTokens(channel.UnsettledBalance()),
+		EarningsTotal: NewTokens(channel.LifetimeBalance()),
+	}
+
+	if promise := channel.LastPromise(); promise.Promise.Amount != nil {
+		dto.LatestPromise = &PromiseDTO{
+			Amount:      NewTokens(promise.Promise.Amount),
+			Fee:         NewTokens(promise.Promise.Fee),
+			Hashlock:    "0x" + hex.EncodeToString(promise.Promise.Hashlock),
+			Revealed:    promise.Revealed,
+			AgreementID: promise.AgreementID,
+		}
+	}
+
+	if onChain != nil {
+		onChainDTO := NewProviderChannelDTO(*onChain)
+		dto.OnChain = &onChainDTO
+	}
+	if onChainErr != nil {
+		dto.OnChainError = onChainErr.Error()
+	}
+	return dto
+}
+
+// PaymentChannelStateDTO represents detailed state of payment channel between identity and hermes.
+// swagger:model PaymentChannelStateDTO
+type PaymentChannelStateDTO struct {
+	// example: 137
+	ChainID int64 `json:"chain_id"`
+
+	// Unique identifier of payment channel
+	// example: 0x8fc5f7a1794dc39c6837df10613bddf1ec9810503a50306a8667f702457a739a
+	ID string `json:"id"`
+
+	// example: 0x42a537D649d6853C0a866470f2d084DA0f73b5E4
+	HermesID string `json:"hermes_id"`
+
+	// Beneficiary - eth wallet address
+	Beneficiary string `json:"beneficiary"`
+
+	// Channel state as recorded locally during the last channel update
+	Local ProviderChannelDTO `json:"local"`
+
+	// Channel state currently on chain
+	OnChain *ProviderChannelDTO `json:"on_chain,omitempty"`
+
+	// Reason why on chain state could not be fetched
+	OnChainError string `json:"on_chain_error,omitempty"`
+
+	// Latest promise amount not yet settled
+	Unsettled Tokens `json:"unsettled"`
+
+	// Earnings of all history
+	EarningsTotal Tokens `json:"earnings_total"`
+
+	LatestPromise *PromiseDTO `json:"latest_promise,omitempty"`
+}
+
+// NewProviderChannelDTO maps provider channel to API.
+func NewProviderChannelDTO(channel client.ProviderChannel) ProviderChannelDTO {
+	return ProviderChannelDTO{
+		Stake:    NewTokens(channel.Stake),
+		Settled:  NewTokens(channel.Settled),
+		Nonce:    channel.LastUsedNonce,
+		Timelock: channel.Timelock,
+	}
+}
+
+// ProviderChannelDTO represents provider channel state.
+// swagger:model ProviderChannelDTO
+type ProviderChannelDTO struct {
+	Stake   Tokens `json:"stake"`
+	Settled Tokens `json:"settled"`
+
+	// example: 3
+	Nonce *big.Int `json:"nonce"`
+
+	// example: 0
+	Timelock *big.Int `json:"timelock"`
+}
+
+// PromiseDTO represents promise issued by hermes.
+// swagger:model PromiseDTO
+type PromiseDTO struct {
+	Amount Tokens `json:"amount"`
+	Fee    Tokens `json:"fee"`
+
+	// example: 0x528a1a0f4ad6ec4e12fc4ae35d3b4a9bfcc9e4f0f4cdb0d9bb0e4b3fd4e3d5d9
+	Hashlock string `json:"hashlock"`
+
+	Revealed bool `json:"revealed"`
+
+	// example: 1
+	AgreementID *big.Int `json:"agreement_id"`
+}
+
+// PaymentChannelsStateResponse represents payment channels of identity.
+// swagger:model PaymentChannelsStateResponse
+type PaymentChannelsStateResponse struct {
+	Channels []PaymentChannelStateDTO `json:"channels"`
+}
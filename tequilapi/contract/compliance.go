@@ -0,0 +1,82 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package contract
+
+import (
+	"time"
+
+	"github.com/mysteriumnetwork/node/core/compliance"
+)
+
+// TermsAcceptanceDTO describes terms of use version accepted for a role.
+// swagger:model TermsAcceptanceDTO
+type TermsAcceptanceDTO struct {
+	// example: provider
+	Role string `json:"role"`
+	// example: 0.0.27
+	Version string `json:"version"`
+	// example: 2024-01-02T15:04:05Z
+	AcceptedAt time.Time `json:"accepted_at"`
+	// set when the accepted version is older than the current one and terms have to be accepted again
+	// example: false
+	ReacceptanceRequired bool `json:"reacceptance_required"`
+}
+
+// ComplianceResponse describes compliance requirements of the node and the recorded terms acceptances.
+// swagger:model ComplianceResponse
+type ComplianceResponse struct {
+	// country code of the configured jurisdiction, detected location country is used when empty
+	// example: US
+	Jurisdiction string `json:"jurisdiction,omitempty"`
+	// minimal terms version provider has to accept before starting services
+	// example: 0.0.27
+	RequiredTermsVersion string `json:"required_terms_version,omitempty"`
+	// terms version bundled with the node
+	// example: 0.0.28
+	CurrentTermsVersion string `json:"current_terms_version"`
+	// service types which must not run, keyed by country code
+	RestrictedServices map[string][]string  `json:"restricted_services"`
+	Acceptances        []TermsAcceptanceDTO `json:"acceptances"`
+}
+
+// NewComplianceResponse maps to API compliance status.
+func NewComplianceResponse(cfg compliance.Config, acceptances, outdated []compliance.Acceptance) ComplianceResponse {
+	response := ComplianceResponse{
+		Jurisdiction:         cfg.Jurisdiction,
+		RequiredTermsVersion: cfg.TermsVersion,
+		CurrentTermsVersion:  cfg.CurrentTermsVersion,
+		RestrictedServices:   cfg.Restrictions,
+		Acceptances:          make([]TermsAcceptanceDTO, len(acceptances)),
+	}
+	if response.RestrictedServices == nil {
+		response.RestrictedServices = map[string][]string{}
+	}
+	for i, a := range acceptances {
+		response.Acceptances[i] = TermsAcceptanceDTO{
+			Role:       a.Role,
+			Version:    a.Version,
+			AcceptedAt: a.AcceptedAt,
+		}
+		for _, o := range outdated {
+			if o.Role == a.Role {
+				response.Acceptances[i].ReacceptanceRequired = true
+			}
+		}
+	}
+	return response
+}
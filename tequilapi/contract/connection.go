@@ -19,6 +19,7 @@ package contract
 
 import (
 	"math/big"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 
@@ -26,6 +27,7 @@ import (
 	"github.com/mysteriumnetwork/node/consumer/bandwidth"
 	"github.com/mysteriumnetwork/node/core/connection"
 	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
+	"github.com/mysteriumnetwork/node/core/connection/external"
 	"github.com/mysteriumnetwork/node/core/quality"
 	"github.com/mysteriumnetwork/node/datasize"
 	"github.com/mysteriumnetwork/payments/crypto"
@@ -36,13 +38,24 @@ var emptyAddress = common.Address{}
 // NewConnectionInfoDTO maps to API connection status.
 func NewConnectionInfoDTO(session connectionstate.Status) ConnectionInfoDTO {
 	response := ConnectionInfoDTO{
-		Status:     string(session.State),
-		ConsumerID: session.ConsumerID.Address,
-		SessionID:  string(session.SessionID),
+		Status:      string(session.State),
+		ConsumerID:  session.ConsumerID.Address,
+		SessionID:   string(session.SessionID),
+		Mitigations: session.Mitigations,
 	}
 	if session.HermesID != emptyAddress {
 		response.HermesID = session.HermesID.Hex()
 	}
+	response.Peer = NewSessionPeerDTO(session.Peer)
+	if session.Online != nil {
+		response.Online = &ConnectionOnlineDTO{
+			Online:    session.Online.Online,
+			Succeeded: session.Online.Succeeded,
+			Quorum:    session.Online.Quorum,
+			Total:     session.Online.Total,
+			CheckedAt: session.Online.CheckedAt.UTC().Format(time.RFC3339),
+		}
+	}
 	// None exists, for not started connection
 	if session.Proposal.ProviderID != "" {
 		proposalRes := NewProposalDTO(session.Proposal)
@@ -68,6 +81,37 @@ type ConnectionInfoDTO struct {
 
 	// example: 4cfb0324-daf6-4ad8-448b-e61fe0a1f918
 	SessionID string `json:"session_id,omitempty"`
+
+	// Measures against traffic correlation enabled for the session.
+	// example: ["randomized-ports","keepalive-jitter","control-padding"]
+	Mitigations []string `json:"mitigations,omitempty"`
+
+	// Result of the last check that the internet is reachable through the tunnel.
+	Online *ConnectionOnlineDTO `json:"online,omitempty"`
+
+	// Node version and capabilities announced by the provider of the session.
+	Peer *SessionPeerDTO `json:"peer,omitempty"`
+}
+
+// ConnectionOnlineDTO holds the result of the connectivity check through the tunnel.
+// swagger:model ConnectionOnlineDTO
+type ConnectionOnlineDTO struct {
+	// example: true
+	Online bool `json:"online"`
+
+	// Number of check targets which responded.
+	// example: 2
+	Succeeded int `json:"succeeded"`
+
+	// Number of check targets required to respond.
+	// example: 1
+	Quorum int `json:"quorum"`
+
+	// example: 3
+	Total int `json:"total"`
+
+	// example: 2024-01-02T15:04:05Z
+	CheckedAt string `json:"checked_at"`
 }
 
 // NewConnectionDTO maps to API connection.
@@ -89,6 +133,22 @@ type ConnectionDTO struct {
 	Statistics *ConnectionStatisticsDTO `json:"statistics,omitempty"`
 }
 
+// ConnectionV2DTO holds details of one of the simultaneous consumer connections.
+// swagger:model ConnectionV2DTO
+type ConnectionV2DTO struct {
+	// connection ID, the proxy port the connection serves or 0 for the system wide tunnel
+	// example: 10000
+	ID int `json:"id"`
+
+	ConnectionDTO
+}
+
+// ConnectionListV2Response holds established consumer connections.
+// swagger:model ConnectionListV2Response
+type ConnectionListV2Response struct {
+	Connections []ConnectionV2DTO `json:"connections"`
+}
+
 // NewConnectionStatisticsDTO maps to API connection stats.
 func NewConnectionStatisticsDTO(session connectionstate.Status, statistics connectionstate.Statistics, throughput bandwidth.Throughput, invoice crypto.Invoice) ConnectionStatisticsDTO {
 	agreementTotal := new(big.Int)
@@ -143,6 +203,123 @@ type ConnectionTrafficDTO struct {
 	BytesReceived uint64 `json:"bytes_received"`
 }
 
+// NewProviderBlocklistResponse maps to API provider blocklist.
+func NewProviderBlocklistResponse(providers []connection.BlockedProvider, now time.Time) ProviderBlocklistResponse {
+	response := ProviderBlocklistResponse{Providers: make([]BlockedProviderDTO, len(providers))}
+	for i, p := range providers {
+		response.Providers[i] = BlockedProviderDTO{
+			ProviderID:    p.ProviderID,
+			Blocked:       now.Before(p.BlockedUntil),
+			Failures:      p.Failures,
+			LastError:     p.LastError,
+			LastFailureAt: p.LastFailure,
+		}
+		if !p.BlockedUntil.IsZero() {
+			blockedUntil := p.BlockedUntil
+			response.Providers[i].BlockedUntil = &blockedUntil
+		}
+	}
+	return response
+}
+
+// ProviderBlocklistResponse holds providers which recently failed to connect.
+// swagger:model ProviderBlocklistResponse
+type ProviderBlocklistResponse struct {
+	Providers []BlockedProviderDTO `json:"providers"`
+}
+
+// BlockedProviderDTO holds connection failures of a provider.
+// swagger:model BlockedProviderDTO
+type BlockedProviderDTO struct {
+	// example: 0x0000000000000000000000000000000000000001
+	ProviderID string `json:"provider_id"`
+
+	// whether the provider is currently excluded from selection
+	Blocked bool `json:"blocked"`
+
+	// consecutive failed connection attempts
+	// example: 3
+	Failures int `json:"failures"`
+
+	// example: could not create p2p channel during connect: connection refused
+	LastError string `json:"last_error"`
+
+	LastFailureAt time.Time  `json:"last_failure_at"`
+	BlockedUntil  *time.Time `json:"blocked_until,omitempty"`
+}
+
+// NewConnectionTraceDTO maps to API connection trace.
+func NewConnectionTraceDTO(trace connection.Trace) ConnectionTraceDTO {
+	dto := ConnectionTraceDTO{
+		SessionID:   string(trace.SessionID),
+		ProviderID:  trace.ProviderID,
+		ServiceType: trace.ServiceType,
+		StartedAt:   trace.Started,
+		Error:       trace.Error,
+		Stages:      make([]ConnectionTraceStageDTO, len(trace.Stages)),
+	}
+	if !trace.Finished.IsZero() {
+		dto.DurationMs = trace.Finished.Sub(trace.Started).Milliseconds()
+	}
+	if !trace.FirstByte.IsZero() {
+		firstByte := trace.FirstByte.Sub(trace.Started).Milliseconds()
+		dto.FirstByteMs = &firstByte
+	}
+	for i, stage := range trace.Stages {
+		dto.Stages[i] = ConnectionTraceStageDTO{
+			Name:       stage.Key,
+			OffsetMs:   stage.Start.Sub(trace.Started).Milliseconds(),
+			DurationMs: stage.Duration().Milliseconds(),
+			Finished:   !stage.End.IsZero(),
+		}
+	}
+	return dto
+}
+
+// ConnectionTraceDTO holds the timings of the last connection attempt.
+// swagger:model ConnectionTraceDTO
+type ConnectionTraceDTO struct {
+	// example: 4cfb0324-daf6-4ad8-448b-e61fe0a1f918
+	SessionID string `json:"session_id,omitempty"`
+
+	// example: 0x0000000000000000000000000000000000000001
+	ProviderID string `json:"provider_id,omitempty"`
+
+	// example: wireguard
+	ServiceType string `json:"service_type,omitempty"`
+
+	StartedAt time.Time `json:"started_at"`
+
+	// duration of the whole attempt in milliseconds, 0 while it is in progress
+	// example: 3500
+	DurationMs int64 `json:"duration_ms"`
+
+	// time from the start of the attempt until the first byte was received through the tunnel in milliseconds
+	// example: 4200
+	FirstByteMs *int64 `json:"first_byte_ms,omitempty"`
+
+	// example: could not create p2p channel during connect: connection refused
+	Error string `json:"error,omitempty"`
+
+	Stages []ConnectionTraceStageDTO `json:"stages"`
+}
+
+// ConnectionTraceStageDTO holds the timing of a connect flow stage.
+// swagger:model ConnectionTraceStageDTO
+type ConnectionTraceStageDTO struct {
+	// example: Consumer P2P channel creation
+	Name string `json:"name"`
+
+	// stage start relative to the start of the attempt in milliseconds
+	// example: 120
+	OffsetMs int64 `json:"offset_ms"`
+
+	// example: 1800
+	DurationMs int64 `json:"duration_ms"`
+
+	Finished bool `json:"finished"`
+}
+
 // ConnectionCreateRequest request used to start a connection.
 // swagger:model ConnectionCreateRequestDTO
 type ConnectionCreateRequest struct {
@@ -193,10 +370,15 @@ func (cr ConnectionCreateRequest) Validate() *apierror.APIError {
 }
 
 // Event creates a quality connection event to be send as a quality metric.
+// IDs of the endpoints imported from outside of the network are local to the node and are not reported.
 func (cr ConnectionCreateRequest) Event(stage string, errMsg string) quality.ConnectionEvent {
+	providerID := cr.ProviderID
+	if external.IsExternal(providerID) {
+		providerID = ""
+	}
 	return quality.ConnectionEvent{
 		ServiceType: cr.ServiceType,
-		ProviderID:  cr.ProviderID,
+		ProviderID:  providerID,
 		ConsumerID:  cr.ConsumerID,
 		HermesID:    cr.HermesID,
 		Error:       errMsg,
@@ -218,4 +400,9 @@ type ConnectOptions struct {
 	DNS connection.DNSOption `json:"dns"`
 
 	ProxyPort int `json:"proxy_port"`
+
+	// disconnect the session after given amount of minutes without any traffic, disabled if zero
+	// required: false
+	// example: 30
+	InactivityTimeout uint `json:"inactivity_timeout"`
 }
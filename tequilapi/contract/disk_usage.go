@@ -0,0 +1,86 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package contract
+
+import (
+	"time"
+
+	"github.com/mysteriumnetwork/node/core/diskusage"
+)
+
+// DiskUsageDTO describes node data directory usage and its growth.
+// swagger:model DiskUsageDTO
+type DiskUsageDTO struct {
+	// example: /home/user/.mysterium
+	DataDir string `json:"data_dir"`
+
+	// warning horizon, empty if warnings are disabled
+	// example: 168h0m0s
+	Horizon string `json:"horizon,omitempty"`
+
+	// samples collected during the last 24 hours, oldest first
+	Samples []DiskUsageSampleDTO `json:"samples"`
+
+	// data directory growth in bytes per hour
+	// example: 1048576
+	GrowthPerHour float64 `json:"growth_bytes_per_hour"`
+
+	// time the disk is projected to be full, omitted if usage is not growing
+	// example: 2024-05-01T12:00:00Z
+	ProjectedFullAt string `json:"projected_full_at,omitempty"`
+}
+
+// DiskUsageSampleDTO is a single data directory usage measurement in bytes.
+// swagger:model DiskUsageSampleDTO
+type DiskUsageSampleDTO struct {
+	// example: 2024-04-01T12:00:00Z
+	At string `json:"at"`
+
+	// example: 52428800
+	Total int64 `json:"total"`
+
+	// example: 10737418240
+	Free uint64 `json:"free"`
+
+	// bytes used per category: sessions, payments, logs and other
+	Categories map[string]int64 `json:"categories"`
+}
+
+// NewDiskUsageDTO maps to API disk usage report.
+func NewDiskUsageDTO(r diskusage.Report) DiskUsageDTO {
+	dto := DiskUsageDTO{
+		DataDir:       r.DataDir,
+		Samples:       make([]DiskUsageSampleDTO, len(r.Samples)),
+		GrowthPerHour: r.GrowthPerH,
+	}
+	if r.Horizon > 0 {
+		dto.Horizon = r.Horizon.String()
+	}
+	if r.ProjectedAt != nil {
+		dto.ProjectedFullAt = r.ProjectedAt.UTC().Format(time.RFC3339)
+	}
+	for i, s := range r.Samples {
+		dto.Samples[i] = DiskUsageSampleDTO{
+			At:         s.At.UTC().Format(time.RFC3339),
+			Total:      s.Total,
+			Free:       s.Free,
+			Categories: s.Categories,
+		}
+	}
+	return dto
+}
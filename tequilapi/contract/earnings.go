@@ -0,0 +1,96 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package contract
+
+import (
+	"github.com/mysteriumnetwork/node/core/earnings"
+)
+
+// NewEarningsProjectionResponse maps to API earnings projection.
+func NewEarningsProjectionResponse(p earnings.Projection) EarningsProjectionResponse {
+	resp := EarningsProjectionResponse{
+		HistoryDays: p.Days,
+		Daily:       NewTokens(p.Daily),
+		Weekly:      NewTokens(p.Weekly),
+		Monthly:     NewTokens(p.Monthly),
+		Services:    make([]ServiceEarningsProjectionDTO, 0, len(p.Services)),
+	}
+	for _, s := range p.Services {
+		resp.Services = append(resp.Services, ServiceEarningsProjectionDTO{
+			ServiceType:  s.ServiceType,
+			Sessions:     s.Stats.Count,
+			Bytes:        s.Stats.SumDataSent + s.Stats.SumDataReceived,
+			Duration:     uint64(s.Stats.SumDuration.Seconds()),
+			Earned:       NewTokens(s.Stats.SumTokens),
+			EarnedDaily:  NewTokens(s.Earned),
+			PricePerHour: NewTokens(s.Price.PricePerHour),
+			PricePerGiB:  NewTokens(s.Price.PricePerGiB),
+			Daily:        NewTokens(s.Daily),
+			Weekly:       NewTokens(s.Weekly),
+			Monthly:      NewTokens(s.Monthly),
+		})
+	}
+	return resp
+}
+
+// EarningsProjectionResponse represents the expected provider earnings at the current prices.
+// swagger:model EarningsProjectionResponse
+type EarningsProjectionResponse struct {
+	// number of past days the projection is based on
+	// example: 28
+	HistoryDays int `json:"history_days"`
+
+	Daily   Tokens `json:"daily"`
+	Weekly  Tokens `json:"weekly"`
+	Monthly Tokens `json:"monthly"`
+
+	Services []ServiceEarningsProjectionDTO `json:"services"`
+}
+
+// ServiceEarningsProjectionDTO represents the expected earnings of a single service type.
+// swagger:model ServiceEarningsProjectionDTO
+type ServiceEarningsProjectionDTO struct {
+	// example: wireguard
+	ServiceType string `json:"service_type"`
+
+	// number of sessions provided during the history window
+	// example: 42
+	Sessions int `json:"sessions"`
+
+	// traffic served during the history window
+	// example: 3221225472
+	Bytes uint64 `json:"bytes"`
+
+	// session time served during the history window in seconds
+	// example: 86400
+	Duration uint64 `json:"duration"`
+
+	// earned during the history window
+	Earned Tokens `json:"earned"`
+
+	// average daily earnings during the history window
+	EarnedDaily Tokens `json:"earned_daily"`
+
+	// current prices the projection is based on
+	PricePerHour Tokens `json:"price_per_hour"`
+	PricePerGiB  Tokens `json:"price_per_gib"`
+
+	Daily   Tokens `json:"daily"`
+	Weekly  Tokens `json:"weekly"`
+	Monthly Tokens `json:"monthly"`
+}
@@ -24,21 +24,32 @@ const (
 	// Identity
 
 	ErrCodeIDImport                      = "err_id_import"
+	ErrCodeIDBackup                      = "err_id_backup"
+	ErrCodeIDRestore                     = "err_id_restore"
 	ErrCodeIDSetDefault                  = "err_id_set_default"
 	ErrCodeIDUseOrCreate                 = "err_to_id_use_or_create"
 	ErrCodeIDUnlock                      = "err_id_unlock"
+	ErrCodeIDUnlockPolicy                = "err_id_unlock_policy"
+	ErrCodeIDUnlockPolicyFlagSet         = "err_id_unlock_policy_flag_set"
+	ErrCodeIDEarningsProjection          = "err_id_earnings_projection"
+	ErrCodeIDOverrides                   = "err_id_overrides"
 	ErrCodeIDLocked                      = "err_id_locked"
 	ErrCodeIDNotRegistered               = "err_id_not_registered"
 	ErrCodeIDStatusUnknown               = "err_id_status_unknown"
 	ErrCodeIDCreate                      = "err_id_create"
+	ErrCodeIDPassphraseChange            = "err_id_passphrase_change"
+	ErrCodeIDWeakPassphrase              = "err_id_weak_passphrase"
 	ErrCodeIDRegistrationCheck           = "err_id_registration_status_check"
 	ErrCodeIDBlockchainRegistrationCheck = "err_id_registration_blockchain_status_check"
 	ErrCodeIDRegistrationInProgress      = "err_id_registration_in_progress"
+	ErrCodeIDRegistrationWalletNotFunded = "err_id_registration_wallet_not_funded"
+	ErrCodeIDRegistrationOnChain         = "err_id_registration_onchain"
 	ErrCodeIDCalculateAddress            = "err_id_calculate_address"
 	ErrCodeIDSaveBeneficiaryAddress      = "err_id_save_beneficiary_invalid_address"
 	ErrCodeIDGetBeneficiaryAddress       = "err_id_get_beneficiary_address"
 	ErrCodeHermesMigration               = "err_id_check_hermes_migration"
 	ErrCodeCheckHermesMigrationStatus    = "err_id_check_hermes_migration_status"
+	ErrCodeIDAttest                      = "err_id_attest"
 
 	// Payment
 
@@ -53,19 +64,49 @@ const (
 	// Referral
 
 	ErrCodeReferralGetToken = "err_referral_get_token"
+	ErrCodeReferralSetToken = "err_referral_set_token"
 	ErrCodeBeneficiaryGet   = "err_beneficiary_get"
 
+	// Auth
+
+	ErrCodeUserList      = "err_user_list"
+	ErrCodeUserAdd       = "err_user_add"
+	ErrCodeUserUpdate    = "err_user_update"
+	ErrCodeUserRemove    = "err_user_remove"
+	ErrCodeAuthLockedOut = "err_auth_locked_out"
+	ErrCodeAuthRecover   = "err_auth_recover"
+
 	// Config
 
-	ErrCodeConfigSave = "err_config_save"
+	ErrCodeConfigSave    = "err_config_save"
+	ErrCodeLoggingConfig = "err_logging_config"
 
 	// Connection
 
 	ErrCodeConnectionAlreadyExists = "err_connection_already_exists"
 	ErrCodeConnectionCancelled     = "err_connection_cancelled"
 	ErrCodeConnect                 = "err_connect"
+	ErrCodeConnectionProviderBusy  = "err_connection_provider_busy"
 	ErrCodeNoConnectionExists      = "err_no_connection_exists"
 	ErrCodeDisconnect              = "err_disconnect"
+	ErrCodeConnectionNotActive     = "err_connection_not_active"
+	ErrCodeConnectionPause         = "err_connection_pause"
+	ErrCodeConnectionResume        = "err_connection_resume"
+	ErrCodeConnectionExternal      = "err_connection_external"
+	ErrCodeConnectionTraceNotFound = "err_connection_trace_not_found"
+
+	// External endpoints
+
+	ErrCodeExternalEndpointAdd    = "err_external_endpoint_add"
+	ErrCodeExternalEndpointList   = "err_external_endpoint_list"
+	ErrCodeExternalEndpointRemove = "err_external_endpoint_remove"
+
+	// Connection routes
+
+	ErrCodeConnectionRouteAdd     = "err_connection_route_add"
+	ErrCodeConnectionRouteList    = "err_connection_route_list"
+	ErrCodeConnectionRouteRemove  = "err_connection_route_remove"
+	ErrCodeConnectionRouteResolve = "err_connection_route_resolve"
 
 	// Feedback
 
@@ -94,12 +135,14 @@ const (
 
 	// Service
 
-	ErrCodeServiceList     = "err_service_list"
-	ErrCodeServiceGet      = "err_service_get"
-	ErrCodeServiceRunning  = "err_service_running"
-	ErrCodeServiceLocation = "err_service_location"
-	ErrCodeServiceStart    = "err_service_start"
-	ErrCodeServiceStop     = "err_service_stop"
+	ErrCodeServiceList       = "err_service_list"
+	ErrCodeServiceGet        = "err_service_get"
+	ErrCodeServiceRunning    = "err_service_running"
+	ErrCodeServiceLocation   = "err_service_location"
+	ErrCodeServiceStart      = "err_service_start"
+	ErrCodeServiceStop       = "err_service_stop"
+	ErrCodeServiceRestricted = "err_service_restricted"
+	ErrCodeServiceTerms      = "err_service_terms"
 
 	// Sessions
 
@@ -107,12 +150,20 @@ const (
 	ErrCodeSessionListPaginate = "err_session_list_paginate"
 	ErrCodeSessionStats        = "err_session_stats"
 	ErrCodeSessionStatsDaily   = "err_session_stats_daily"
+	ErrCodeSessionExport       = "err_session_export"
+	ErrCodeSessionForecast     = "err_session_forecast"
+	ErrCodeSessionReserve      = "err_session_reserve"
+	ErrCodeServiceStats        = "err_service_stats"
 
 	// Transactor
 
 	ErrCodeTransactorRegistration          = "err_transactor_registration"
+	ErrCodeTransactorBeneficiaryDenied     = "err_transactor_beneficiary_denied"
+	ErrCodeTransactorGasPrice              = "err_transactor_gas_price"
 	ErrCodeTransactorFetchFees             = "err_transactor_fetch_fees"
 	ErrCodeTransactorDecreaseStake         = "err_transactor_decrease_stake"
+	ErrCodeTransactorIncreaseStake         = "err_transactor_increase_stake"
+	ErrCodeTransactorGetStake              = "err_transactor_get_stake"
 	ErrCodeTransactorSettleHistory         = "err_transactor_settle_history"
 	ErrCodeTransactorSettleHistoryPaginate = "err_transactor_settle_history_paginate"
 	ErrCodeTransactorWithdraw              = "err_transactor_withdraw"
@@ -121,6 +172,10 @@ const (
 	ErrCodeTransactorNoReward              = "err_transactor_no_reward"
 	ErrCodeTransactorBeneficiary           = "err_transactor_beneficiary"
 	ErrCodeTransactorBeneficiaryTxStatus   = "err_transactor_beneficiary_tx_status"
+	ErrCodeTransactorSettlePreview         = "err_transactor_settle_preview"
+	ErrCodeTransactorBeneficiaryChange     = "err_transactor_beneficiary_change"
+	ErrCodeTransactorAudit                 = "err_transactor_audit"
+	ErrCodeTransactorAuditPaginate         = "err_transactor_audit_paginate"
 
 	// Affiliator
 
@@ -129,10 +184,21 @@ const (
 
 	// Other
 
+	ErrCodeAnalyticsSettings               = "err_analytics_settings"
 	ErrCodeActiveHermes                    = "err_get_active_hermes"
+	ErrCodeSSEFilter                       = "err_sse_filter"
+	ErrCodePcapStart                       = "err_pcap_start"
+	ErrCodePcapStop                        = "err_pcap_stop"
+	ErrCodePcapList                        = "err_pcap_list"
+	ErrCodeRecordingStart                  = "err_recording_start"
+	ErrCodeRecordingStop                   = "err_recording_stop"
+	ErrCodeRecordingList                   = "err_recording_list"
 	ErrCodeHermesFee                       = "err_hermes_fee"
 	ErrCodeHermesSettle                    = "err_hermes_settle"
 	ErrCodeHermesSettleAsync               = "err_hermes_settle_async"
+	ErrCodeTermsAccept                     = "err_terms_accept"
+	ErrCodeTermsCompliance                 = "err_terms_compliance"
+	ErrCodeTermsOutdated                   = "err_terms_outdated"
 	ErrCodeUILocalVersions                 = "err_ui_local_versions"
 	ErrCodeUISwitchVersion                 = "err_ui_switch_version"
 	ErrCodeUIDownload                      = "err_ui_download"
@@ -149,4 +215,11 @@ const (
 	ErrorCodeProviderActivityStats         = "err_provider_activity_stats"
 	ErrorCodeLatestReleaseInformation      = "err_latest_release_information"
 	ErrorCodeProviderServiceEarnings       = "err_provider_service_earnings"
+	ErrCodeNodeProfile                     = "err_node_profile"
+	ErrCodeChainUnknown                    = "err_chain_unknown"
+	ErrCodeChainSessionsActive             = "err_chain_sessions_active"
+	ErrCodeChainSwitch                     = "err_chain_switch"
+	ErrCodeRegistryMigrations              = "err_registry_migrations"
+	ErrCodeAddressBook                     = "err_address_book"
+	ErrCodeAddressBookEntryExists          = "err_address_book_entry_exists"
 )
@@ -0,0 +1,104 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package contract
+
+import (
+	"time"
+
+	"github.com/mysteriumnetwork/go-rest/apierror"
+	"github.com/mysteriumnetwork/node/core/connection/external"
+)
+
+// ExternalEndpointCreateRequest request used to import a VPN endpoint from outside of the network.
+// swagger:model ExternalEndpointCreateRequestDTO
+type ExternalEndpointCreateRequest struct {
+	// example: Home router
+	Name string `json:"name"`
+
+	// service type of the config, wireguard or openvpn
+	// example: wireguard
+	ServiceType string `json:"service_type"`
+
+	// country shown for the endpoint proposal, optional
+	// example: LT
+	Country string `json:"country,omitempty"`
+
+	// raw wg-quick or openvpn client config
+	Config string `json:"config"`
+}
+
+// Validate validates fields in request.
+func (r ExternalEndpointCreateRequest) Validate() *apierror.APIError {
+	v := apierror.NewValidator()
+	if len(r.ServiceType) == 0 {
+		v.Required("service_type")
+	}
+	if len(r.Config) == 0 {
+		v.Required("config")
+	}
+	return v.Err()
+}
+
+// NewExternalEndpointDTO maps to API external endpoint.
+func NewExternalEndpointDTO(e external.Endpoint) ExternalEndpointDTO {
+	return ExternalEndpointDTO{
+		ID:          e.ID,
+		ProviderID:  e.ProviderID(),
+		Name:        e.Name,
+		ServiceType: e.ServiceType,
+		Country:     e.Country,
+		CreatedAt:   e.CreatedAt,
+	}
+}
+
+// NewExternalEndpointListResponse maps to API external endpoint list.
+func NewExternalEndpointListResponse(endpoints []external.Endpoint) ExternalEndpointListResponse {
+	response := ExternalEndpointListResponse{Endpoints: make([]ExternalEndpointDTO, len(endpoints))}
+	for i, e := range endpoints {
+		response.Endpoints[i] = NewExternalEndpointDTO(e)
+	}
+	return response
+}
+
+// ExternalEndpointListResponse holds imported VPN endpoints.
+// swagger:model ExternalEndpointListResponse
+type ExternalEndpointListResponse struct {
+	Endpoints []ExternalEndpointDTO `json:"endpoints"`
+}
+
+// ExternalEndpointDTO describes an imported VPN endpoint, its config is never returned as it holds private keys.
+// swagger:model ExternalEndpointDTO
+type ExternalEndpointDTO struct {
+	// example: 5a51e0a0-3d1c-4a4e-9b43-3a0c6c4f1e6b
+	ID string `json:"id"`
+
+	// provider ID to connect to the endpoint with
+	// example: external:5a51e0a0-3d1c-4a4e-9b43-3a0c6c4f1e6b
+	ProviderID string `json:"provider_id"`
+
+	// example: Home router
+	Name string `json:"name"`
+
+	// example: wireguard
+	ServiceType string `json:"service_type"`
+
+	// example: LT
+	Country string `json:"country,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
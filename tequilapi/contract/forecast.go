@@ -0,0 +1,113 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package contract
+
+import (
+	"math/big"
+	"net/http"
+
+	"github.com/mysteriumnetwork/go-rest/apierror"
+	"github.com/mysteriumnetwork/node/consumer/forecast"
+)
+
+// UsageForecastQuery selects the consumer whose usage is forecasted.
+// swagger:parameters sessionForecast
+type UsageForecastQuery struct {
+	// Consumer identity to forecast the usage of.
+	// in: query
+	// required: true
+	ConsumerID string `json:"consumer_id"`
+
+	// Country to compare prices in. Defaults to the country of the most used providers.
+	// in: query
+	Country string `json:"country"`
+}
+
+// Bind creates and validates query from API request.
+func (q *UsageForecastQuery) Bind(request *http.Request) *apierror.APIError {
+	v := apierror.NewValidator()
+
+	qs := request.URL.Query()
+	q.ConsumerID = qs.Get("consumer_id")
+	if q.ConsumerID == "" {
+		v.Required("consumer_id")
+	}
+	q.Country = qs.Get("country")
+
+	return v.Err()
+}
+
+// NewUsageForecastResponse maps to API usage forecast.
+func NewUsageForecastResponse(fc forecast.Forecast) UsageForecastResponse {
+	return UsageForecastResponse{
+		HistoryWeeks:    fc.Weeks,
+		Bytes:           fc.Data,
+		Tokens:          fc.Spend,
+		PaidPricePerGiB: fc.PaidPerGiB,
+		Advice: UsageAdviceDTO{
+			Country:     fc.Advice.Country,
+			ServiceType: fc.Advice.ServiceType,
+			PricePerGiB: fc.Advice.PricePerGiB,
+			Savings:     fc.Advice.Savings,
+			Optimal:     fc.Advice.Optimal,
+			Message:     fc.Advice.Message,
+		},
+	}
+}
+
+// UsageForecastResponse represents the forecasted consumer usage for the next week.
+// swagger:model UsageForecastResponse
+type UsageForecastResponse struct {
+	// number of past weeks the forecast is based on
+	// example: 4
+	HistoryWeeks int `json:"history_weeks"`
+
+	// example: 3221225472
+	Bytes uint64 `json:"bytes"`
+
+	// expected spend in wei
+	Tokens *big.Int `json:"tokens"`
+
+	// average price per GiB paid during the history window, in wei
+	PaidPricePerGiB *big.Int `json:"paid_price_per_gib"`
+
+	Advice UsageAdviceDTO `json:"advice"`
+}
+
+// UsageAdviceDTO compares the consumer prices with the prices currently offered.
+// swagger:model UsageAdviceDTO
+type UsageAdviceDTO struct {
+	// example: DE
+	Country string `json:"country"`
+
+	// example: wireguard
+	ServiceType string `json:"service_type"`
+
+	// 25th percentile price per GiB of matching proposals, in wei
+	PricePerGiB *big.Int `json:"price_per_gib,omitempty"`
+
+	// share of spend that could have been saved, in percent
+	// example: 18
+	Savings float64 `json:"savings"`
+
+	// example: false
+	Optimal bool `json:"optimal"`
+
+	// example: Providers at p25 price in DE would have saved 18%
+	Message string `json:"message"`
+}
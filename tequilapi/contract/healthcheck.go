@@ -29,6 +29,17 @@ type HealthCheckDTO struct {
 	// example: 0.0.6
 	Version   string       `json:"version"`
 	BuildInfo BuildInfoDTO `json:"build_info"`
+
+	// Friendly node name, if configured.
+	// example: Garage node
+	Nickname string `json:"nickname,omitempty"`
+
+	// Hex encoded SHA-256 hash of the node avatar, if configured.
+	AvatarHash string `json:"avatar_hash,omitempty"`
+
+	// Long-running components which stopped reporting they are alive, omitted if all are healthy.
+	// example: ["registry-watcher"]
+	DegradedComponents []string `json:"degraded_components,omitempty"`
 }
 
 // BuildInfoDTO holds info about build.
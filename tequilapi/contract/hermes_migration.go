@@ -17,6 +17,12 @@
 
 package contract
 
+import (
+	"time"
+
+	"github.com/mysteriumnetwork/node/consumer/migration"
+)
+
 // MigrationStatus status of the migration
 type MigrationStatus = string
 
@@ -32,3 +38,34 @@ const (
 type MigrationStatusResponse struct {
 	Status MigrationStatus `json:"status"`
 }
+
+// MigrationProgressResponse represents progress of the latest migration.
+// swagger:model MigrationProgressResponse
+type MigrationProgressResponse struct {
+	// example: settling
+	Stage string `json:"stage"`
+
+	OldHermesID string `json:"old_hermes_id,omitempty"`
+	NewHermesID string `json:"new_hermes_id,omitempty"`
+
+	// error of the failed migration
+	Error string `json:"error,omitempty"`
+
+	StartedAt *time.Time `json:"started_at,omitempty"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+// NewMigrationProgressResponse maps to API migration progress.
+func NewMigrationProgressResponse(p migration.Progress) MigrationProgressResponse {
+	res := MigrationProgressResponse{
+		Stage:       string(p.Stage),
+		OldHermesID: p.OldHermes,
+		NewHermesID: p.NewHermes,
+		Error:       p.Error,
+	}
+	if !p.Started.IsZero() {
+		res.StartedAt = &p.Started
+		res.UpdatedAt = &p.Updated
+	}
+	return res
+}
@@ -21,8 +21,11 @@ import (
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/mysteriumnetwork/go-rest/apierror"
 	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/identity/backup"
+	"github.com/mysteriumnetwork/node/identity/registry"
 	pingpong_event "github.com/mysteriumnetwork/node/session/pingpong/event"
 )
 
@@ -67,6 +70,39 @@ type IdentityDTO struct {
 	EarningsPerHermes map[string]EarningsDTO `json:"earnings_per_hermes"`
 }
 
+// IdentityChainsResponse holds identity state on every configured chain.
+// swagger:model IdentityChainsResponse
+type IdentityChainsResponse struct {
+	Chains []IdentityChainDTO `json:"chains"`
+}
+
+// IdentityChainDTO holds registration, balance and earnings of identity on a single chain.
+// swagger:model IdentityChainDTO
+type IdentityChainDTO struct {
+	// example: 137
+	ChainID int64 `json:"chain_id"`
+
+	// whether the chain is the active one
+	// example: true
+	Active bool `json:"active"`
+
+	// example: Registered
+	RegistrationStatus string `json:"registration_status"`
+
+	// example: 0x100000000000000000000000000000000000000A
+	ChannelAddress string `json:"channel_address,omitempty"`
+
+	// example: 0x200000000000000000000000000000000000000A
+	HermesID string `json:"hermes_id,omitempty"`
+
+	BalanceTokens       Tokens `json:"balance_tokens"`
+	EarningsTokens      Tokens `json:"earnings_tokens"`
+	EarningsTotalTokens Tokens `json:"earnings_total_tokens"`
+
+	// reasons why some of the chain state could not be fetched
+	Errors []string `json:"errors,omitempty"`
+}
+
 // EarningsDTO holds earnings data.
 // swagger:model EarningsDTO
 type EarningsDTO struct {
@@ -139,6 +175,25 @@ func (r IdentityUnlockRequest) Validate() *apierror.APIError {
 	return v.Err()
 }
 
+// IdentityPassphraseChangeRequest request used for identity passphrase change.
+// swagger:model IdentityPassphraseChangeRequestDTO
+type IdentityPassphraseChangeRequest struct {
+	CurrentPassphrase *string `json:"current_passphrase"`
+	NewPassphrase     *string `json:"new_passphrase"`
+}
+
+// Validate validates fields in request
+func (r IdentityPassphraseChangeRequest) Validate() *apierror.APIError {
+	v := apierror.NewValidator()
+	if r.CurrentPassphrase == nil {
+		v.Required("current_passphrase")
+	}
+	if r.NewPassphrase == nil {
+		v.Required("new_passphrase")
+	}
+	return v.Err()
+}
+
 // IdentityCurrentRequest request used for current identity remembering.
 // swagger:model IdentityCurrentRequestDTO
 type IdentityCurrentRequest struct {
@@ -164,6 +219,34 @@ type IdentityRegisterRequest struct {
 	Beneficiary string `json:"beneficiary,omitempty"`
 	// Fee: an agreed amount to pay for registration
 	Fee *big.Int `json:"fee"`
+	// GasPrice: overrides the gas price of the registration transaction. Optional.
+	GasPrice *GasPriceDTO `json:"gas_price,omitempty"`
+	// Method: "transactor" to register through transactor, or "onchain" to send the registration transaction
+	// directly from the identity wallet, which has to hold coins for gas. Optional, defaults to "transactor".
+	// example: onchain
+	Method string `json:"method,omitempty"`
+}
+
+// Validate validates identity registration request.
+func (r IdentityRegisterRequest) Validate() *apierror.APIError {
+	v := apierror.NewValidator()
+	switch registry.RegistrationMethod(r.Method) {
+	case "", registry.RegistrationMethodTransactor:
+	case registry.RegistrationMethodOnChain:
+		if r.ReferralToken != nil {
+			v.Invalid("referral_token", "Referral token can not be used when registering on chain")
+		}
+	default:
+		v.Invalid("method", "Unknown registration method")
+	}
+	return v.Err()
+}
+
+// IdentityRegisterOnChainResponse represents the registration transaction sent directly to chain.
+// swagger:model IdentityRegisterOnChainResponseDTO
+type IdentityRegisterOnChainResponse struct {
+	// example: 0x20c070a9be65355adbd2ba479e095e2e8ed7e692596548734984eab75d3fdfa5
+	TxHash string `json:"tx_hash"`
 }
 
 // IdentityRegistrationResponse represents registration status and needed data for registering of given identity
@@ -172,6 +255,21 @@ type IdentityRegistrationResponse struct {
 	Status string `json:"status"`
 	// Returns true if identity is registered in payments smart contract
 	Registered bool `json:"registered"`
+	// Registration on each chain the identity is registered on, both L1 and L2 if registration on all chains is enabled
+	Chains []ChainRegistrationDTO `json:"chains"`
+}
+
+// ChainRegistrationDTO represents registration status of the identity on a single chain.
+// swagger:model ChainRegistrationDTO
+type ChainRegistrationDTO struct {
+	// example: 137
+	ChainID int64 `json:"chain_id"`
+
+	// example: Registered
+	Status string `json:"status"`
+
+	// example: true
+	Registered bool `json:"registered"`
 }
 
 // IdentityBeneficiaryResponse represents the provider beneficiary address.
@@ -220,6 +318,91 @@ func (i *IdentityExportRequest) Validate() *apierror.APIError {
 	return v.Err()
 }
 
+// IdentityBackupRequest is received in identity backup endpoint.
+// swagger:model IdentityBackupRequestDTO
+type IdentityBackupRequest struct {
+	// passphrase the bundle is encrypted with
+	// required: true
+	Passphrase string `json:"passphrase"`
+
+	// current identity passphrase, empty by default
+	CurrentPassphrase string `json:"current_passphrase,omitempty"`
+}
+
+// Validate validates the backup request.
+func (i *IdentityBackupRequest) Validate() *apierror.APIError {
+	v := apierror.NewValidator()
+	if len(i.Passphrase) == 0 {
+		v.Required("passphrase")
+	}
+	return v.Err()
+}
+
+// IdentityBackupResponse contains the encrypted identity bundle.
+// swagger:model IdentityBackupResponseDTO
+type IdentityBackupResponse struct {
+	// base64 encoded bundle with identity key, beneficiary and registration statuses
+	Data []byte `json:"data"`
+}
+
+// IdentityRestoreRequest is received in identity restore endpoint.
+// swagger:model IdentityRestoreRequestDTO
+type IdentityRestoreRequest struct {
+	// base64 encoded bundle exported by identity backup endpoint
+	// required: true
+	Data []byte `json:"data"`
+
+	// passphrase the bundle is encrypted with
+	// required: true
+	Passphrase string `json:"passphrase"`
+
+	// Optional. Default values are OK.
+	SetDefault    bool   `json:"set_default"`
+	NewPassphrase string `json:"new_passphrase"`
+}
+
+// Validate validates the restore request.
+func (i *IdentityRestoreRequest) Validate() *apierror.APIError {
+	v := apierror.NewValidator()
+	if len(i.Data) == 0 {
+		v.Required("data")
+	}
+	if len(i.Passphrase) == 0 {
+		v.Required("passphrase")
+	}
+	return v.Err()
+}
+
+// NewIdentityRestoreResponse maps to API restored identity.
+func NewIdentityRestoreResponse(r backup.Restored) IdentityRestoreResponse {
+	resp := IdentityRestoreResponse{
+		ID:            r.Identity.Address,
+		Beneficiary:   r.Beneficiary,
+		Registrations: make([]ChainRegistrationDTO, 0, len(r.Registrations)),
+	}
+	for _, reg := range r.Registrations {
+		resp.Registrations = append(resp.Registrations, ChainRegistrationDTO{
+			ChainID:    reg.ChainID,
+			Status:     reg.Status.String(),
+			Registered: reg.Status.Registered(),
+		})
+	}
+	return resp
+}
+
+// IdentityRestoreResponse represents identity restored from the bundle.
+// swagger:model IdentityRestoreResponseDTO
+type IdentityRestoreResponse struct {
+	// example: 0x0000000000000000000000000000000000000001
+	ID string `json:"id"`
+
+	// example: 0x0000000000000000000000000000000000000002
+	Beneficiary string `json:"beneficiary,omitempty"`
+
+	// registration statuses checked after the restore
+	Registrations []ChainRegistrationDTO `json:"registrations"`
+}
+
 // borrowed from github.com/ethereum/go-ethereum@v1.10.17/accounts/keystore/key.go
 
 // EncryptedKeyJSON represents response to IdentityExportRequest.
@@ -249,3 +432,58 @@ type cipherparamsJSON struct {
 type BeneficiaryAddressRequest struct {
 	Address string `json:"address"`
 }
+
+// IdentityAttestationRequest request used for identity attestation.
+// swagger:model IdentityAttestationRequestDTO
+type IdentityAttestationRequest struct {
+	// nonce supplied by the verifying party
+	// required: true
+	Nonce string `json:"nonce"`
+}
+
+// maxAttestationNonceLength limits the size of the signed message.
+const maxAttestationNonceLength = 256
+
+// Validate validates fields in request
+func (r IdentityAttestationRequest) Validate() *apierror.APIError {
+	v := apierror.NewValidator()
+	if r.Nonce == "" {
+		v.Required("nonce")
+	} else if len(r.Nonce) > maxAttestationNonceLength {
+		v.Invalid("nonce", "Nonce is too long")
+	}
+	return v.Err()
+}
+
+// IdentityAttestationDTO holds the node statement signed by the identity.
+// swagger:model IdentityAttestationDTO
+type IdentityAttestationDTO struct {
+	// example: 0x0000000000000000000000000000000000000001
+	Identity string `json:"identity"`
+	Nonce    string `json:"nonce"`
+	// example: 1.2.3
+	Version string `json:"version"`
+	// node uptime in seconds
+	// example: 3600
+	Uptime uint64 `json:"uptime"`
+	// unix timestamp of the attestation
+	// example: 1700000000
+	Timestamp int64 `json:"timestamp"`
+	// exact signed message
+	Message string `json:"message"`
+	// signature of the message in hex format
+	Signature string `json:"signature"`
+}
+
+// NewIdentityAttestationDTO maps to API identity attestation.
+func NewIdentityAttestationDTO(attestation identity.SignedAttestation) IdentityAttestationDTO {
+	return IdentityAttestationDTO{
+		Identity:  attestation.Identity,
+		Nonce:     attestation.Nonce,
+		Version:   attestation.Version,
+		Uptime:    attestation.Uptime,
+		Timestamp: attestation.Timestamp,
+		Message:   string(attestation.Message),
+		Signature: hexutil.Encode(attestation.Signature.Bytes()),
+	}
+}
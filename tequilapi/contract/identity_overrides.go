@@ -0,0 +1,93 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package contract
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/mysteriumnetwork/go-rest/apierror"
+
+	"github.com/mysteriumnetwork/node/identity/overrides"
+)
+
+// IdentityOverridesDTO represents payment and registration settings overridden by the identity.
+// Settings which are not overridden fall back to the global configuration.
+// swagger:model IdentityOverridesDTO
+type IdentityOverridesDTO struct {
+	// beneficiary receiving settled earnings, empty to use the saved beneficiary
+	// example: 0x0000000000000000000000000000000000000001
+	Beneficiary string `json:"beneficiary,omitempty"`
+
+	// unsettled amount of MYST which triggers automatic settlement, unset to use the global setting
+	// example: 5
+	SettleThreshold *float64 `json:"settle_threshold,omitempty"`
+
+	// chain the identity is registered on while providing services, 0 to use the global setting
+	// example: 137
+	ChainID int64 `json:"chain_id,omitempty"`
+
+	// example: 2024-02-01T12:00:00Z
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+// NewIdentityOverridesDTO maps to API identity overrides.
+func NewIdentityOverridesDTO(o overrides.Overrides) IdentityOverridesDTO {
+	dto := IdentityOverridesDTO{
+		Beneficiary:     o.Beneficiary,
+		SettleThreshold: o.SettleThreshold,
+		ChainID:         o.ChainID,
+	}
+	if !o.UpdatedAt.IsZero() {
+		dto.UpdatedAt = &o.UpdatedAt
+	}
+	return dto
+}
+
+// Validate validates identity overrides against the configured chains.
+func (r *IdentityOverridesDTO) Validate(chains []int64) *apierror.APIError {
+	v := apierror.NewValidator()
+	if r.Beneficiary != "" && !common.IsHexAddress(r.Beneficiary) {
+		v.Invalid("beneficiary", "Invalid address")
+	}
+	if r.SettleThreshold != nil && *r.SettleThreshold < 0 {
+		v.Invalid("settle_threshold", "Must not be negative")
+	}
+	if r.ChainID != 0 && !containsChain(chains, r.ChainID) {
+		v.Invalid("chain_id", "Chain is not configured")
+	}
+	return v.Err()
+}
+
+// Overrides returns the requested identity overrides.
+func (r *IdentityOverridesDTO) Overrides() overrides.Overrides {
+	return overrides.Overrides{
+		Beneficiary:     r.Beneficiary,
+		SettleThreshold: r.SettleThreshold,
+		ChainID:         r.ChainID,
+	}
+}
+
+func containsChain(chains []int64, chainID int64) bool {
+	for _, c := range chains {
+		if c == chainID {
+			return true
+		}
+	}
+	return false
+}
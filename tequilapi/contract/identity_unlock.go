@@ -0,0 +1,104 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package contract
+
+import (
+	"time"
+
+	"github.com/mysteriumnetwork/go-rest/apierror"
+
+	"github.com/mysteriumnetwork/node/identity/unlock"
+)
+
+// IdentityUnlockPolicyDTO represents the active identity unlock policy.
+// swagger:model IdentityUnlockPolicyDTO
+type IdentityUnlockPolicyDTO struct {
+	// on-demand or startup
+	// example: on-demand
+	Mode string `json:"mode"`
+
+	// identities idle for longer are locked, 0 keeps them unlocked
+	// example: 900
+	IdleTimeoutSeconds int64 `json:"idle_timeout_seconds"`
+}
+
+// NewIdentityUnlockPolicyDTO maps to API identity unlock policy.
+func NewIdentityUnlockPolicyDTO(p unlock.Policy) IdentityUnlockPolicyDTO {
+	return IdentityUnlockPolicyDTO{
+		Mode:               string(p.Mode),
+		IdleTimeoutSeconds: int64(p.IdleTimeout / time.Second),
+	}
+}
+
+// IdentityUnlockCredentialDTO holds the passphrase of identity unlocked at startup.
+// swagger:model IdentityUnlockCredentialDTO
+type IdentityUnlockCredentialDTO struct {
+	// required: true
+	// example: 0x0000000000000000000000000000000000000001
+	ID string `json:"id"`
+
+	Passphrase string `json:"passphrase"`
+}
+
+// IdentityUnlockPolicyRequest is received in identity unlock policy endpoint.
+// swagger:model IdentityUnlockPolicyRequestDTO
+type IdentityUnlockPolicyRequest struct {
+	IdentityUnlockPolicyDTO
+
+	// identities unlocked by the startup policy, replace the stored ones.
+	// Stored credentials are used if empty.
+	Credentials []IdentityUnlockCredentialDTO `json:"credentials,omitempty"`
+}
+
+// Validate validates the unlock policy request.
+func (r *IdentityUnlockPolicyRequest) Validate() *apierror.APIError {
+	v := apierror.NewValidator()
+	switch unlock.Mode(r.Mode) {
+	case unlock.ModeOnDemand, unlock.ModeStartup:
+	case "":
+		v.Required("mode")
+	default:
+		v.Invalid("mode", "Unknown unlock policy mode")
+	}
+	if r.IdleTimeoutSeconds < 0 {
+		v.Invalid("idle_timeout_seconds", "Must not be negative")
+	}
+	if len(r.Credentials) > 0 && unlock.Mode(r.Mode) != unlock.ModeStartup {
+		v.Invalid("credentials", "Credentials are used only by the startup policy")
+	}
+	for _, cred := range r.Credentials {
+		if cred.ID == "" {
+			v.Required("credentials.id")
+			break
+		}
+	}
+	return v.Err()
+}
+
+// Policy returns the requested unlock policy and credentials.
+func (r *IdentityUnlockPolicyRequest) Policy() (unlock.Policy, []unlock.Credential) {
+	policy := unlock.Policy{
+		Mode:        unlock.Mode(r.Mode),
+		IdleTimeout: time.Duration(r.IdleTimeoutSeconds) * time.Second,
+	}
+	var creds []unlock.Credential
+	for _, cred := range r.Credentials {
+		creds = append(creds, unlock.Credential{Address: cred.ID, Passphrase: cred.Passphrase})
+	}
+	return policy, creds
+}
@@ -54,4 +54,14 @@ type LocationDTO struct {
 	// IP type (data_center, residential, etc.)
 	// example: residential
 	IPType string `json:"ip_type"`
+
+	// Location was claimed by the provider instead of detected
+	// example: false
+	Overridden bool `json:"overridden,omitempty"`
+	// Claimed location is not confirmed by the one measured by GeoIP sources
+	// example: false
+	Mismatch bool `json:"mismatch,omitempty"`
+	// Claimed location could not be checked as no GeoIP source resolved the location
+	// example: false
+	Unverified bool `json:"unverified,omitempty"`
 }
@@ -0,0 +1,121 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package contract
+
+import (
+	"time"
+
+	"github.com/mysteriumnetwork/go-rest/apierror"
+	"github.com/rs/zerolog"
+
+	"github.com/mysteriumnetwork/node/logconfig"
+)
+
+const megabyte = 1024 * 1024
+
+// LoggingConfigDTO holds the log level and log file rotation settings.
+// swagger:model LoggingConfigDTO
+type LoggingConfigDTO struct {
+	// example: info
+	Level string `json:"level"`
+
+	// log file size in megabytes after which it is rotated, 0 if disabled
+	// example: 50
+	MaxSizeMB uint64 `json:"max_size_mb"`
+
+	// log file age after which it is rotated, 0s if disabled
+	// example: 24h0m0s
+	MaxAge string `json:"max_age"`
+
+	// number of rotated log files retained, 0 retains all of them
+	// example: 5
+	MaxBackups int `json:"max_backups"`
+
+	// whether rotated log files are compressed with gzip
+	// example: true
+	Compress bool `json:"compress"`
+}
+
+// NewLoggingConfigDTO maps to API logging config.
+func NewLoggingConfigDTO(opts logconfig.LogOptions) LoggingConfigDTO {
+	return LoggingConfigDTO{
+		Level:      opts.LogLevel.String(),
+		MaxSizeMB:  uint64(opts.Rotation.MaxSize / megabyte),
+		MaxAge:     opts.Rotation.MaxAge.String(),
+		MaxBackups: opts.Rotation.MaxBackups,
+		Compress:   opts.Rotation.Compress,
+	}
+}
+
+// LoggingConfigRequest changes logging settings, omitted fields are left unchanged.
+// swagger:model LoggingConfigRequest
+type LoggingConfigRequest struct {
+	// example: debug
+	Level *string `json:"level,omitempty"`
+
+	// example: 100
+	MaxSizeMB *uint64 `json:"max_size_mb,omitempty"`
+
+	// example: 24h
+	MaxAge *string `json:"max_age,omitempty"`
+
+	// example: 10
+	MaxBackups *int `json:"max_backups,omitempty"`
+
+	// example: true
+	Compress *bool `json:"compress,omitempty"`
+}
+
+// Validate validates fields in request.
+func (r LoggingConfigRequest) Validate() *apierror.APIError {
+	v := apierror.NewValidator()
+	if r.Level != nil {
+		if _, err := zerolog.ParseLevel(*r.Level); err != nil {
+			v.Invalid("level", "Unknown log level")
+		}
+	}
+	if r.MaxAge != nil {
+		if d, err := time.ParseDuration(*r.MaxAge); err != nil || d < 0 {
+			v.Invalid("max_age", "Must be a non negative duration, e.g. 24h")
+		}
+	}
+	if r.MaxBackups != nil && *r.MaxBackups < 0 {
+		v.Invalid("max_backups", "Must not be negative")
+	}
+	return v.Err()
+}
+
+// Apply returns options with the requested changes applied, the request must be valid.
+func (r LoggingConfigRequest) Apply(opts logconfig.LogOptions) logconfig.LogOptions {
+	if r.Level != nil {
+		opts.LogLevel, _ = zerolog.ParseLevel(*r.Level)
+	}
+	if r.MaxSizeMB != nil {
+		opts.Rotation.MaxSize = int64(*r.MaxSizeMB) * megabyte
+	}
+	if r.MaxAge != nil {
+		opts.Rotation.MaxAge, _ = time.ParseDuration(*r.MaxAge)
+	}
+	if r.MaxBackups != nil {
+		opts.Rotation.MaxBackups = *r.MaxBackups
+	}
+	if r.Compress != nil {
+		opts.Rotation.Compress = *r.Compress
+	}
+	return opts
+}
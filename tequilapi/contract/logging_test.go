@@ -0,0 +1,67 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package contract
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/logconfig"
+	"github.com/mysteriumnetwork/node/logconfig/rollingwriter"
+)
+
+func TestLoggingConfigRequest_Validate(t *testing.T) {
+	level, age, backups := "verbose", "-1h", -1
+	req := LoggingConfigRequest{Level: &level, MaxAge: &age, MaxBackups: &backups}
+	err := req.Validate()
+	assert.NotNil(t, err)
+	assert.Len(t, err.Err.Fields, 3)
+
+	level, age, backups = "info", "24h", 3
+	assert.Nil(t, req.Validate())
+	assert.Nil(t, LoggingConfigRequest{}.Validate())
+}
+
+func TestLoggingConfigRequest_Apply(t *testing.T) {
+	opts := logconfig.LogOptions{
+		LogLevel: zerolog.DebugLevel,
+		Filepath: "/var/log/mysterium-node",
+		Rotation: rollingwriter.DefaultConfig,
+	}
+	size, age, compress := uint64(100), "24h", false
+	req := LoggingConfigRequest{MaxSizeMB: &size, MaxAge: &age, Compress: &compress}
+
+	opts = req.Apply(opts)
+	assert.Equal(t, zerolog.DebugLevel, opts.LogLevel)
+	assert.Equal(t, "/var/log/mysterium-node", opts.Filepath)
+	assert.Equal(t, rollingwriter.Config{
+		MaxSize:    100 * 1024 * 1024,
+		MaxAge:     24 * time.Hour,
+		MaxBackups: 5,
+		Compress:   false,
+	}, opts.Rotation)
+	assert.Equal(t, LoggingConfigDTO{
+		Level:      "debug",
+		MaxSizeMB:  100,
+		MaxAge:     "24h0m0s",
+		MaxBackups: 5,
+	}, NewLoggingConfigDTO(opts))
+}
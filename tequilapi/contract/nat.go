@@ -18,7 +18,11 @@
 package contract
 
 import (
+	"sort"
+	"time"
+
 	"github.com/mysteriumnetwork/node/nat"
+	"github.com/mysteriumnetwork/node/nat/behavior"
 )
 
 // NATTypeDTO gives information about NAT type in terms of traversal capabilities
@@ -27,3 +31,57 @@ type NATTypeDTO struct {
 	Type  nat.NATType `json:"type"`
 	Error string      `json:"error,omitempty"`
 }
+
+// NewNATStatusV2DTO maps to API NAT status.
+func NewNATStatusV2DTO(status behavior.Status) NATStatusV2DTO {
+	dto := NATStatusV2DTO{
+		Type:                 status.Type,
+		ProbeError:           status.ProbeError,
+		LastSuccessfulMethod: status.LastSuccessfulMethod,
+		Methods:              make([]NATMethodStatusDTO, 0, len(status.Methods)),
+	}
+	if !status.LastProbe.IsZero() {
+		lastProbe := status.LastProbe
+		dto.LastProbeAt = &lastProbe
+	}
+	for method, s := range status.Methods {
+		dto.Methods = append(dto.Methods, NATMethodStatusDTO{
+			Method:     method,
+			Successful: s.Successful,
+			Error:      s.Error,
+			UpdatedAt:  s.Time,
+		})
+	}
+	sort.Slice(dto.Methods, func(i, j int) bool {
+		return dto.Methods[i].Method < dto.Methods[j].Method
+	})
+	return dto
+}
+
+// NATStatusV2DTO gives information about NAT type and outcomes of NAT traversal methods
+// swagger:model NATStatusV2DTO
+type NATStatusV2DTO struct {
+	// example: prcone
+	Type nat.NATType `json:"type"`
+
+	// error of the last NAT probe, type is kept from the last successful one
+	ProbeError  string     `json:"probe_error,omitempty"`
+	LastProbeAt *time.Time `json:"last_probe_at,omitempty"`
+
+	// example: upnp
+	LastSuccessfulMethod string               `json:"last_successful_method,omitempty"`
+	Methods              []NATMethodStatusDTO `json:"methods"`
+}
+
+// NATMethodStatusDTO holds the last outcome of a NAT traversal method.
+// swagger:model NATMethodStatusDTO
+type NATMethodStatusDTO struct {
+	// one of public_ip, upnp, nat_pmp, port_mapping, hole_punching
+	// example: hole_punching
+	Method     string `json:"method"`
+	Successful bool   `json:"successful"`
+
+	// example: too few connections were built
+	Error     string    `json:"error,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
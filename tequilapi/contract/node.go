@@ -25,8 +25,29 @@ import (
 	"github.com/shopspring/decimal"
 
 	"github.com/mysteriumnetwork/node/core/node"
+	"github.com/mysteriumnetwork/node/core/profile"
 )
 
+// NodeProfileDTO holds the friendly node name and avatar.
+// swagger:model NodeProfileDTO
+type NodeProfileDTO struct {
+	// up to 32 characters, sanitized by the node
+	// example: Garage node
+	Nickname string `json:"nickname"`
+
+	// hex encoded SHA-256 hash of the avatar image
+	// example: 9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08
+	AvatarHash string `json:"avatar_hash"`
+}
+
+// NewNodeProfileDTO maps to API node profile.
+func NewNodeProfileDTO(p profile.Profile) NodeProfileDTO {
+	return NodeProfileDTO{
+		Nickname:   p.Nickname,
+		AvatarHash: p.AvatarHash,
+	}
+}
+
 // NodeStatusResponse a node status reflects monitoring agent POV on node availability
 // swagger:model NodeStatusResponse
 type NodeStatusResponse struct {
@@ -154,3 +175,12 @@ type EarningsPerServiceResponse struct {
 	TotalEarningsScraping Tokens `json:"total_scraping_tokens"`
 	TotalEarningsDVPN     Tokens `json:"total_dvpn_tokens"`
 }
+
+// RejectedControlMessagesResponse contains numbers of p2p control messages rejected by the node since start
+// swagger:model RejectedControlMessagesResponse
+type RejectedControlMessagesResponse struct {
+	Unsigned         uint64 `json:"unsigned"`
+	InvalidSignature uint64 `json:"invalid_signature"`
+	Stale            uint64 `json:"stale"`
+	Replayed         uint64 `json:"replayed"`
+}
@@ -0,0 +1,123 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package contract
+
+import (
+	"time"
+
+	"github.com/mysteriumnetwork/node/core/connection/capture"
+)
+
+// PcapStartRequest starts capturing packet headers of a consumer session.
+// swagger:model PcapStartRequestDTO
+type PcapStartRequest struct {
+	// session to capture, the session of the current connection if empty
+	SessionID string `json:"session_id,omitempty"`
+
+	// capture duration, defaults to and is limited by the configured maximum
+	// example: 60
+	DurationSeconds int64 `json:"duration_seconds,omitempty"`
+}
+
+// PcapCaptureDTO describes a packet capture.
+// swagger:model PcapCaptureDTO
+type PcapCaptureDTO struct {
+	// file name to download the capture by
+	// example: 4e5a7e3c-6d3f-4b7e-9a43-3a2f4e1c2b1d_1714564800000000000.pcap
+	Name string `json:"name"`
+
+	SessionID string `json:"session_id"`
+
+	// example: 2024-05-01T12:00:00Z
+	StartedAt string `json:"started_at"`
+
+	// empty while the capture is running
+	// example: 2024-05-01T12:01:00Z
+	StoppedAt string `json:"stopped_at,omitempty"`
+
+	// example: false
+	Running bool `json:"running"`
+
+	// example: 1024
+	Packets int `json:"packets"`
+
+	// file size in bytes
+	// example: 65560
+	Size int64 `json:"size"`
+}
+
+// PcapFileDTO describes a stored packet capture file.
+// swagger:model PcapFileDTO
+type PcapFileDTO struct {
+	Name string `json:"name"`
+
+	// file size in bytes
+	// example: 65560
+	Size int64 `json:"size"`
+
+	// example: 2024-05-01T12:01:00Z
+	CreatedAt string `json:"created_at"`
+}
+
+// PcapStatusDTO describes the packet capture state and the stored captures.
+// swagger:model PcapStatusDTO
+type PcapStatusDTO struct {
+	// running or last finished capture
+	Last *PcapCaptureDTO `json:"last,omitempty"`
+
+	Files []PcapFileDTO `json:"files"`
+
+	// example: 300
+	MaxDurationSeconds int64 `json:"max_duration_seconds"`
+}
+
+// NewPcapCaptureDTO maps to API packet capture.
+func NewPcapCaptureDTO(info capture.Info) PcapCaptureDTO {
+	dto := PcapCaptureDTO{
+		Name:      info.Name,
+		SessionID: info.SessionID,
+		StartedAt: info.StartedAt.UTC().Format(time.RFC3339),
+		Running:   info.StoppedAt.IsZero(),
+		Packets:   info.Packets,
+		Size:      info.Size,
+	}
+	if !info.StoppedAt.IsZero() {
+		dto.StoppedAt = info.StoppedAt.UTC().Format(time.RFC3339)
+	}
+	return dto
+}
+
+// NewPcapStatusDTO maps to API packet capture status.
+func NewPcapStatusDTO(last *capture.Info, files []capture.File, maxDuration time.Duration) PcapStatusDTO {
+	dto := PcapStatusDTO{
+		Files:              make([]PcapFileDTO, len(files)),
+		MaxDurationSeconds: int64(maxDuration / time.Second),
+	}
+	if last != nil {
+		c := NewPcapCaptureDTO(*last)
+		dto.Last = &c
+	}
+	for i, f := range files {
+		dto.Files[i] = PcapFileDTO{
+			Name:      f.Name,
+			Size:      f.Size,
+			CreatedAt: f.CreatedAt.UTC().Format(time.RFC3339),
+		}
+	}
+	return dto
+}
@@ -19,6 +19,7 @@ package contract
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/mysteriumnetwork/node/core/discovery/proposal"
 	"github.com/mysteriumnetwork/node/market"
@@ -32,6 +33,11 @@ const AutoNATType = "auto"
 
 // NewProposalDTO maps to API service proposal.
 func NewProposalDTO(p proposal.PricedServiceProposal) ProposalDTO {
+	var lastConfirmedAt *time.Time
+	if !p.LastConfirmedAt.IsZero() {
+		lastConfirmedAt = &p.LastConfirmedAt
+	}
+
 	return ProposalDTO{
 		Format:         p.Format,
 		Compatibility:  p.Compatibility,
@@ -39,6 +45,9 @@ func NewProposalDTO(p proposal.PricedServiceProposal) ProposalDTO {
 		ServiceType:    p.ServiceType,
 		Location:       NewServiceLocationsDTO(p.Location),
 		AccessPolicies: p.AccessPolicies,
+		SLA:            p.SLA,
+		Metadata:       p.Metadata,
+		Maintenance:    p.Maintenance,
 		Quality: Quality{
 			Quality:   p.Quality.Quality,
 			Latency:   p.Quality.Latency,
@@ -52,6 +61,8 @@ func NewProposalDTO(p proposal.PricedServiceProposal) ProposalDTO {
 			PerGiB:        p.Price.PricePerGiB.Uint64(),
 			PerGiBTokens:  NewTokens(p.Price.PricePerGiB),
 		},
+		Freshness:       p.Freshness,
+		LastConfirmedAt: lastConfirmedAt,
 	}
 }
 
@@ -64,6 +75,10 @@ func NewServiceLocationsDTO(l market.Location) ServiceLocationDTO {
 		ASN:       l.ASN,
 		ISP:       l.ISP,
 		IPType:    l.IPType,
+
+		Overridden: l.Overridden,
+		Mismatch:   l.Mismatch,
+		Unverified: l.Unverified,
 	}
 }
 
@@ -103,8 +118,24 @@ type ProposalDTO struct {
 	// AccessPolicies
 	AccessPolicies *[]market.AccessPolicy `json:"access_policies,omitempty"`
 
+	// Service level agreement advertised by the provider.
+	SLA *market.SLA `json:"sla,omitempty"`
+
+	// Human-readable information about the provider node, if the provider shares any.
+	Metadata *market.ProposalMetadata `json:"metadata,omitempty"`
+
+	// Upcoming maintenance windows announced by the provider.
+	Maintenance []market.MaintenanceWindow `json:"maintenance,omitempty"`
+
 	// Quality of the service.
 	Quality Quality `json:"quality"`
+
+	// How recently the proposal was confirmed live, from 0 (likely offline) to 1 (just confirmed).
+	// example: 0.8
+	Freshness float64 `json:"freshness,omitempty"`
+
+	// When the proposal was last confirmed live by discovery or a connection.
+	LastConfirmedAt *time.Time `json:"last_confirmed_at,omitempty"`
 }
 
 // Price represents the service price.
@@ -159,6 +190,16 @@ type ServiceLocationDTO struct {
 	ISP string `json:"isp,omitempty"`
 	// example: residential
 	IPType string `json:"ip_type,omitempty"`
+
+	// Location was claimed by the provider instead of detected
+	// example: false
+	Overridden bool `json:"overridden,omitempty"`
+	// Claimed location is not confirmed by the one measured by GeoIP sources
+	// example: false
+	Mismatch bool `json:"mismatch,omitempty"`
+	// Claimed location could not be checked as no GeoIP source resolved the location
+	// example: false
+	Unverified bool `json:"unverified,omitempty"`
 }
 
 // Quality holds proposal quality metrics.
@@ -0,0 +1,118 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package contract
+
+import (
+	"time"
+
+	"github.com/mysteriumnetwork/node/tequilapi/recorder"
+)
+
+// RecordingStartRequest starts recording Tequilapi requests and responses.
+// swagger:model RecordingStartRequestDTO
+type RecordingStartRequest struct {
+	// recording duration, defaults to and is limited by the configured maximum
+	// example: 600
+	DurationSeconds int64 `json:"duration_seconds,omitempty"`
+}
+
+// RecordingDTO describes a Tequilapi recording.
+// swagger:model RecordingDTO
+type RecordingDTO struct {
+	// file name to download the recording by
+	// example: tequilapi_1714564800000000000.har
+	Name string `json:"name"`
+
+	// example: 2024-05-01T12:00:00Z
+	StartedAt string `json:"started_at"`
+
+	// empty while the recording is running
+	// example: 2024-05-01T12:10:00Z
+	StoppedAt string `json:"stopped_at,omitempty"`
+
+	// example: false
+	Running bool `json:"running"`
+
+	// number of recorded requests
+	// example: 42
+	Entries int `json:"entries"`
+
+	// recording size in bytes
+	// example: 65560
+	Size int64 `json:"size"`
+}
+
+// RecordingFileDTO describes a stored Tequilapi recording file.
+// swagger:model RecordingFileDTO
+type RecordingFileDTO struct {
+	Name string `json:"name"`
+
+	// file size in bytes
+	// example: 65560
+	Size int64 `json:"size"`
+
+	// example: 2024-05-01T12:10:00Z
+	CreatedAt string `json:"created_at"`
+}
+
+// RecordingStatusDTO describes the Tequilapi recording state and the stored recordings.
+// swagger:model RecordingStatusDTO
+type RecordingStatusDTO struct {
+	// running or last finished recording
+	Last *RecordingDTO `json:"last,omitempty"`
+
+	Files []RecordingFileDTO `json:"files"`
+
+	// example: 1800
+	MaxDurationSeconds int64 `json:"max_duration_seconds"`
+}
+
+// NewRecordingDTO maps to API Tequilapi recording.
+func NewRecordingDTO(info recorder.Info) RecordingDTO {
+	dto := RecordingDTO{
+		Name:      info.Name,
+		StartedAt: info.StartedAt.UTC().Format(time.RFC3339),
+		Running:   info.StoppedAt.IsZero(),
+		Entries:   info.Entries,
+		Size:      info.Size,
+	}
+	if !info.StoppedAt.IsZero() {
+		dto.StoppedAt = info.StoppedAt.UTC().Format(time.RFC3339)
+	}
+	return dto
+}
+
+// NewRecordingStatusDTO maps to API Tequilapi recording status.
+func NewRecordingStatusDTO(last *recorder.Info, files []recorder.File, maxDuration time.Duration) RecordingStatusDTO {
+	dto := RecordingStatusDTO{
+		Files:              make([]RecordingFileDTO, len(files)),
+		MaxDurationSeconds: int64(maxDuration / time.Second),
+	}
+	if last != nil {
+		r := NewRecordingDTO(*last)
+		dto.Last = &r
+	}
+	for i, f := range files {
+		dto.Files[i] = RecordingFileDTO{
+			Name:      f.Name,
+			Size:      f.Size,
+			CreatedAt: f.CreatedAt.UTC().Format(time.RFC3339),
+		}
+	}
+	return dto
+}
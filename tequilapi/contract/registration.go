@@ -0,0 +1,202 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package contract
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/mysteriumnetwork/go-rest/apierror"
+
+	"github.com/mysteriumnetwork/node/identity/registry"
+)
+
+// ListRegistrationsResponse lists registration state of the node identities.
+// swagger:model ListRegistrationsResponse
+type ListRegistrationsResponse struct {
+	Registrations []RegistrationStateDTO `json:"registrations"`
+}
+
+// RegistrationStateDTO describes registration of a single identity on a single chain.
+// swagger:model RegistrationStateDTO
+type RegistrationStateDTO struct {
+	// example: 0x0000000000000000000000000000000000000001
+	ID string `json:"id"`
+
+	// example: 137
+	ChainID int64 `json:"chain_id"`
+
+	// example: Registered
+	Status string `json:"status"`
+
+	// time the status was last stored, omitted if it was never stored
+	// example: 2024-05-01T12:00:00Z
+	UpdatedAt string `json:"updated_at,omitempty"`
+
+	// number of services currently provided by the identity
+	// example: 2
+	Services int `json:"services"`
+
+	// beneficiary used for registration, omitted if the default one is used
+	// example: 0x0000000000000000000000000000000000000002
+	Beneficiary string `json:"beneficiary,omitempty"`
+
+	// failed registration attempts since the last successful one
+	// example: 1
+	Attempts int `json:"attempts"`
+
+	// example: transactor unavailable
+	LastError string `json:"last_error,omitempty"`
+
+	// time of the next registration attempt, omitted if none is scheduled
+	// example: 2024-05-01T12:01:00Z
+	NextAttemptAt string `json:"next_attempt_at,omitempty"`
+}
+
+// NewListRegistrationsResponse maps to API registration list.
+func NewListRegistrationsResponse(registrations []registry.IdentityRegistration) ListRegistrationsResponse {
+	res := ListRegistrationsResponse{Registrations: make([]RegistrationStateDTO, len(registrations))}
+	for i, r := range registrations {
+		dto := RegistrationStateDTO{
+			ID:          r.Identity.Address,
+			ChainID:     r.ChainID,
+			Status:      r.Status.String(),
+			Services:    r.Services,
+			Beneficiary: r.Beneficiary,
+			Attempts:    r.Attempts,
+			LastError:   r.LastError,
+		}
+		if !r.UpdatedAt.IsZero() {
+			dto.UpdatedAt = r.UpdatedAt.UTC().Format(time.RFC3339)
+		}
+		if !r.NextAttempt.IsZero() {
+			dto.NextAttemptAt = r.NextAttempt.UTC().Format(time.RFC3339)
+		}
+		res.Registrations[i] = dto
+	}
+	return res
+}
+
+// RegistrationFeeDTO describes the current registration fee and whether it is accepted for automatic registration.
+// swagger:model RegistrationFeeDTO
+type RegistrationFeeDTO struct {
+	// example: 0x0000000000000000000000000000000000000001
+	ID string `json:"id"`
+
+	// example: 137
+	ChainID int64 `json:"chain_id"`
+
+	Fee        Tokens    `json:"fee"`
+	ValidUntil time.Time `json:"valid_until"`
+
+	// configured maximum fee, omitted if there is no limit
+	MaxFee *Tokens `json:"max_fee,omitempty"`
+
+	// whether the identity would be registered automatically for the current fee
+	// example: true
+	Allowed bool `json:"allowed"`
+}
+
+// NewRegistrationFeeDTO maps to API registration fee.
+func NewRegistrationFeeDTO(id string, fee registry.RegistrationFee) RegistrationFeeDTO {
+	dto := RegistrationFeeDTO{
+		ID:         id,
+		ChainID:    fee.ChainID,
+		Fee:        NewTokens(fee.Fee),
+		ValidUntil: fee.ValidUntil,
+		Allowed:    fee.Allowed,
+	}
+	if fee.MaxFee != nil {
+		maxFee := NewTokens(fee.MaxFee)
+		dto.MaxFee = &maxFee
+	}
+	return dto
+}
+
+// IdentityReferralTokenRequest sets the referral token used when the identity is registered automatically.
+// swagger:model IdentityReferralTokenRequest
+type IdentityReferralTokenRequest struct {
+	// referral token, empty to remove it
+	// example: 4db4b2b0
+	Token string `json:"token"`
+}
+
+// RegistryMigrationsDTO holds the registry migration table.
+// swagger:model RegistryMigrationsDTO
+type RegistryMigrationsDTO struct {
+	Migrations []RegistryMigrationDTO `json:"migrations"`
+}
+
+// RegistryMigrationDTO declares that identities registered in the from registry of the chain have to be registered in the to registry.
+// swagger:model RegistryMigrationDTO
+type RegistryMigrationDTO struct {
+	// example: 137
+	ChainID int64 `json:"chain_id"`
+
+	// example: 0x87F0F4b7e0FAb14A565C87BAbbA6c40c92281b51
+	From string `json:"from"`
+
+	// example: 0x935F2f2AFb60Ee4DD8a4f0c4a12B79FA8A2c0c91
+	To string `json:"to"`
+}
+
+// NewRegistryMigrationsDTO maps to API registry migration table.
+func NewRegistryMigrationsDTO(migrations []registry.Migration) RegistryMigrationsDTO {
+	res := RegistryMigrationsDTO{Migrations: make([]RegistryMigrationDTO, len(migrations))}
+	for i, m := range migrations {
+		res.Migrations[i] = RegistryMigrationDTO{
+			ChainID: m.ChainID,
+			From:    m.From.Hex(),
+			To:      m.To.Hex(),
+		}
+	}
+	return res
+}
+
+// Validate validates fields in request.
+func (r RegistryMigrationsDTO) Validate() *apierror.APIError {
+	v := apierror.NewValidator()
+	for i, m := range r.Migrations {
+		field := fmt.Sprintf("migrations[%d]", i)
+		switch {
+		case m.ChainID <= 0:
+			v.Invalid(field+".chain_id", "Must be a positive chain ID")
+		case !common.IsHexAddress(m.From):
+			v.Invalid(field+".from", "Must be a registry address")
+		case !common.IsHexAddress(m.To):
+			v.Invalid(field+".to", "Must be a registry address")
+		case common.HexToAddress(m.From) == common.HexToAddress(m.To):
+			v.Invalid(field+".to", "Must differ from the registry migrated from")
+		}
+	}
+	return v.Err()
+}
+
+// ToMigrations returns the migration table, the request must be valid.
+func (r RegistryMigrationsDTO) ToMigrations() []registry.Migration {
+	migrations := make([]registry.Migration, len(r.Migrations))
+	for i, m := range r.Migrations {
+		migrations[i] = registry.Migration{
+			ChainID: m.ChainID,
+			From:    common.HexToAddress(m.From),
+			To:      common.HexToAddress(m.To),
+		}
+	}
+	return migrations
+}
@@ -0,0 +1,51 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package contract
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/identity/registry"
+)
+
+func TestRegistryMigrationsDTO_Validate(t *testing.T) {
+	from := "0x0000000000000000000000000000000000000001"
+	to := "0x0000000000000000000000000000000000000002"
+
+	req := RegistryMigrationsDTO{Migrations: []RegistryMigrationDTO{
+		{ChainID: 0, From: from, To: to},
+		{ChainID: 137, From: "0x1", To: to},
+		{ChainID: 137, From: from, To: from},
+	}}
+	err := req.Validate()
+	assert.NotNil(t, err)
+	assert.Len(t, err.Err.Fields, 3)
+
+	assert.Nil(t, RegistryMigrationsDTO{}.Validate())
+	assert.Nil(t, RegistryMigrationsDTO{Migrations: []RegistryMigrationDTO{{ChainID: 137, From: from, To: to}}}.Validate())
+}
+
+func TestRegistryMigrationsDTO_ToMigrations(t *testing.T) {
+	migrations := []registry.Migration{
+		{ChainID: 137, From: common.HexToAddress("0x1"), To: common.HexToAddress("0x2")},
+	}
+	assert.Equal(t, migrations, NewRegistryMigrationsDTO(migrations).ToMigrations())
+}
@@ -0,0 +1,140 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package contract
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/mysteriumnetwork/go-rest/apierror"
+	consumer_reservation "github.com/mysteriumnetwork/node/consumer/reservation"
+	"github.com/mysteriumnetwork/node/core/service/reservation"
+)
+
+// ReservationRequest request used to reserve provider capacity ahead of time.
+// swagger:model ReservationRequestDTO
+type ReservationRequest struct {
+	// consumer identity
+	// required: true
+	// example: 0x0000000000000000000000000000000000000001
+	ConsumerID string `json:"consumer_id"`
+
+	// provider identity
+	// required: true
+	// example: 0x0000000000000000000000000000000000000002
+	ProviderID string `json:"provider_id"`
+
+	// required: true
+	// example: wireguard
+	ServiceType string `json:"service_type"`
+
+	// start of the reserved time slot
+	// required: true
+	// example: 2024-05-01T10:00:00Z
+	Start time.Time `json:"start"`
+
+	// end of the reserved time slot
+	// required: true
+	// example: 2024-05-01T12:00:00Z
+	End time.Time `json:"end"`
+}
+
+// Validate validates fields in request.
+func (r ReservationRequest) Validate() *apierror.APIError {
+	v := apierror.NewValidator()
+	if r.ConsumerID == "" {
+		v.Required("consumer_id")
+	}
+	if r.ProviderID == "" {
+		v.Required("provider_id")
+	}
+	if r.ServiceType == "" {
+		v.Required("service_type")
+	}
+	if r.Start.IsZero() {
+		v.Required("start")
+	}
+	if r.End.IsZero() {
+		v.Required("end")
+	} else if !r.End.After(r.Start) {
+		v.Invalid("end", "Reservation has to end after it starts")
+	}
+	return v.Err()
+}
+
+// ReservationDTO represents the reserved provider capacity.
+// swagger:model ReservationDTO
+type ReservationDTO struct {
+	// example: 4cfb0324-daf6-4ad8-448b-e61e0d2a4d1c
+	ID string `json:"id"`
+
+	// example: 0x0000000000000000000000000000000000000001
+	ConsumerID string `json:"consumer_id"`
+
+	// example: 0x0000000000000000000000000000000000000002
+	ProviderID string `json:"provider_id"`
+
+	// example: wireguard
+	ServiceType string `json:"service_type"`
+
+	// example: 2024-05-01T10:00:00Z
+	Start time.Time `json:"start"`
+
+	// example: 2024-05-01T12:00:00Z
+	End time.Time `json:"end"`
+
+	// prepaid reservation fee in wei
+	Fee *big.Int `json:"fee"`
+
+	// reservation status, reported for the provided reservations only
+	// example: confirmed
+	Status string `json:"status,omitempty"`
+}
+
+// NewReservationDTO maps to API reservation made by the consumer.
+func NewReservationDTO(r consumer_reservation.Reservation) ReservationDTO {
+	return ReservationDTO{
+		ID:          r.ID,
+		ConsumerID:  r.ConsumerID.Address,
+		ProviderID:  r.ProviderID.Address,
+		ServiceType: r.ServiceType,
+		Start:       r.Start.UTC(),
+		End:         r.End.UTC(),
+		Fee:         r.Fee,
+	}
+}
+
+// NewProvidedReservationDTO maps to API reservation of the provider capacity.
+func NewProvidedReservationDTO(r reservation.Reservation) ReservationDTO {
+	return ReservationDTO{
+		ID:          r.ID,
+		ConsumerID:  r.ConsumerID.Address,
+		ProviderID:  r.ProviderID.Address,
+		ServiceType: r.ServiceType,
+		Start:       r.Start.UTC(),
+		End:         r.End.UTC(),
+		Fee:         r.Fee,
+		Status:      string(r.Status),
+	}
+}
+
+// ListReservationsResponse defines reservations list representation as json.
+// swagger:model ListReservationsResponse
+type ListReservationsResponse struct {
+	Items []ReservationDTO `json:"items"`
+}
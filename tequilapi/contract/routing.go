@@ -0,0 +1,98 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package contract
+
+import (
+	"time"
+
+	"github.com/mysteriumnetwork/go-rest/apierror"
+	"github.com/mysteriumnetwork/node/core/connection/routing"
+)
+
+// ConnectionRouteCreateRequest request used to link traffic of an application or to a network to a connection.
+// swagger:model ConnectionRouteCreateRequestDTO
+type ConnectionRouteCreateRequest struct {
+	// connection ID, the proxy port the connection serves or 0 for the system wide tunnel
+	// example: 10000
+	ConnectionID int `json:"connection_id"`
+
+	// application name, optional if CIDR is given
+	// example: firefox
+	App string `json:"app,omitempty"`
+
+	// destination network, optional if app is given
+	// example: 10.0.0.0/8
+	CIDR string `json:"cidr,omitempty"`
+}
+
+// Validate validates fields in request.
+func (r ConnectionRouteCreateRequest) Validate() *apierror.APIError {
+	v := apierror.NewValidator()
+	if r.ConnectionID < 0 {
+		v.Invalid("connection_id", "Connection ID can not be negative")
+	}
+	if len(r.App) == 0 && len(r.CIDR) == 0 {
+		v.Required("app")
+		v.Required("cidr")
+	}
+	return v.Err()
+}
+
+// NewConnectionRouteDTO maps to API connection route.
+func NewConnectionRouteDTO(r routing.Route) ConnectionRouteDTO {
+	return ConnectionRouteDTO{
+		ID:           r.ID,
+		ConnectionID: r.ConnectionID,
+		App:          r.App,
+		CIDR:         r.CIDR,
+		CreatedAt:    r.CreatedAt,
+	}
+}
+
+// NewConnectionRouteListResponse maps to API connection route list.
+func NewConnectionRouteListResponse(routes []routing.Route) ConnectionRouteListResponse {
+	response := ConnectionRouteListResponse{Routes: make([]ConnectionRouteDTO, len(routes))}
+	for i, r := range routes {
+		response.Routes[i] = NewConnectionRouteDTO(r)
+	}
+	return response
+}
+
+// ConnectionRouteListResponse holds the routing policy of consumer connections.
+// swagger:model ConnectionRouteListResponse
+type ConnectionRouteListResponse struct {
+	Routes []ConnectionRouteDTO `json:"routes"`
+}
+
+// ConnectionRouteDTO links traffic of an application or to a network to a connection.
+// swagger:model ConnectionRouteDTO
+type ConnectionRouteDTO struct {
+	// example: 5a51e0a0-3d1c-4a4e-9b43-3a0c6c4f1e6b
+	ID string `json:"id"`
+
+	// example: 10000
+	ConnectionID int `json:"connection_id"`
+
+	// example: firefox
+	App string `json:"app,omitempty"`
+
+	// example: 10.0.0.0/8
+	CIDR string `json:"cidr,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
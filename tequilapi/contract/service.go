@@ -71,6 +71,17 @@ type ServiceInfoDTO struct {
 	// example: Running
 	Status string `json:"status"`
 
+	// version of the operator service template applied on service start
+	// example: 2024-03-01
+	TemplateVersion string `json:"template_version,omitempty"`
+
+	// true when proposal announcements are paused because of provider load
+	ProposalPaused bool `json:"proposal_paused,omitempty"`
+
+	// reason proposal announcements are paused for
+	// example: bandwidth 95.2 Mbit/s above 90 Mbit/s threshold
+	ProposalPauseReason string `json:"proposal_pause_reason,omitempty"`
+
 	Proposal *ProposalDTO `json:"proposal,omitempty"`
 
 	ConnectionStatistics *ServiceStatisticsDTO `json:"connection_statistics,omitempty"`
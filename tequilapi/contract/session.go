@@ -25,7 +25,9 @@ import (
 	"github.com/go-openapi/strfmt"
 	"github.com/mysteriumnetwork/go-rest/apierror"
 	"github.com/mysteriumnetwork/node/consumer/session"
+	"github.com/mysteriumnetwork/node/core/connection/trafficcategory"
 	"github.com/mysteriumnetwork/node/identity"
+	node_session "github.com/mysteriumnetwork/node/session"
 	"github.com/mysteriumnetwork/node/tequilapi/utils"
 )
 
@@ -233,9 +235,107 @@ func NewSessionStatsDTO(stats session.Stats) SessionStatsDTO {
 		SumBytesSent:     stats.SumDataSent,
 		SumDuration:      uint64(stats.SumDuration.Seconds()),
 		SumTokens:        stats.SumTokens,
+
+		SumTrafficCategories: newTrafficCategoriesDTO(stats.SumTrafficCategories),
+	}
+}
+
+// ServiceStatsQuery allows to filter provided sessions of the consumer statistics.
+// swagger:parameters serviceStats
+type ServiceStatsQuery struct {
+	// Count the sessions from this date. Formatted in RFC3339 e.g. 2020-07-01.
+	// in: query
+	DateFrom *strfmt.Date `json:"date_from"`
+
+	// Count the sessions until this date. Formatted in RFC3339 e.g. 2020-07-30.
+	// in: query
+	DateTo *strfmt.Date `json:"date_to"`
+
+	// Provider identity to filter the sessions by.
+	// in: query
+	ProviderID *string `json:"provider_id"`
+
+	// Service type to filter the sessions by.
+	// in: query
+	ServiceType *string `json:"service_type"`
+}
+
+// Bind creates and validates query from API request.
+func (q *ServiceStatsQuery) Bind(request *http.Request) *apierror.APIError {
+	v := apierror.NewValidator()
+
+	qs := request.URL.Query()
+	if qStr := qs.Get("date_from"); qStr != "" {
+		if qVal, err := parseDate(qStr); err != nil {
+			v.Invalid("date_from", "Cannot parse 'date_from'")
+		} else {
+			q.DateFrom = qVal
+		}
+	}
+	if qStr := qs.Get("date_to"); qStr != "" {
+		if qVal, err := parseDate(qStr); err != nil {
+			v.Invalid("date_to", "Cannot parse 'date_to'")
+		} else {
+			q.DateTo = qVal
+		}
+	}
+	if qStr := qs.Get("provider_id"); qStr != "" {
+		q.ProviderID = &qStr
+	}
+	if qStr := qs.Get("service_type"); qStr != "" {
+		q.ServiceType = &qStr
+	}
+
+	return v.Err()
+}
+
+// ToFilter converts API query to storage filter.
+func (q *ServiceStatsQuery) ToFilter() *session.Filter {
+	return (&SessionQuery{
+		DateFrom:    q.DateFrom,
+		DateTo:      q.DateTo,
+		ProviderID:  q.ProviderID,
+		ServiceType: q.ServiceType,
+	}).ToFilter()
+}
+
+// NewServiceStatsResponse maps to API consumer statistics.
+func NewServiceStatsResponse(stats session.ConsumerStats) ServiceStatsResponse {
+	return ServiceStatsResponse{
+		Sessions:               stats.Sessions,
+		Consumers:              stats.Consumers,
+		NewConsumers:           stats.NewConsumers,
+		ReturningConsumers:     stats.ReturningConsumers,
+		AverageSessionDuration: uint64(stats.AverageDuration.Seconds()),
+		ConsumersByCountry:     stats.ConsumersByCountry,
 	}
 }
 
+// ServiceStatsResponse holds aggregate statistics about consumers of the provided sessions.
+// Consumers are counted by hashed identity only, countries with few consumers are grouped as "other".
+// swagger:model ServiceStatsResponse
+type ServiceStatsResponse struct {
+	// example: 42
+	Sessions int `json:"sessions"`
+
+	// example: 12
+	Consumers int `json:"consumers"`
+
+	// consumers who had no provided sessions before the period
+	// example: 5
+	NewConsumers int `json:"new_consumers"`
+
+	// example: 7
+	ReturningConsumers int `json:"returning_consumers"`
+
+	// average session duration in seconds
+	// example: 1800
+	AverageSessionDuration uint64 `json:"average_session_duration"`
+
+	// example: {"US": 8, "other": 4}
+	ConsumersByCountry map[string]int `json:"consumers_by_country"`
+}
+
 // SessionStatsDTO represents the session aggregated statistics.
 // swagger:model SessionStatsDTO
 type SessionStatsDTO struct {
@@ -245,6 +345,9 @@ type SessionStatsDTO struct {
 	SumBytesSent     uint64   `json:"sum_bytes_sent"`
 	SumDuration      uint64   `json:"sum_duration"`
 	SumTokens        *big.Int `json:"sum_tokens"`
+
+	// Present only when local traffic categorization is enabled.
+	SumTrafficCategories map[string]TrafficCategoryDTO `json:"sum_traffic_categories,omitempty"`
 }
 
 // NewSessionDTO maps to API session.
@@ -265,6 +368,51 @@ func NewSessionDTO(se session.History) SessionDTO {
 		Tokens:          se.Tokens,
 		Status:          se.Status,
 		IPType:          se.IPType,
+		ProtocolVersion: se.Protocol.Version,
+		Capabilities:    se.Protocol.Capabilities.Names(),
+		Peer:            NewSessionPeerDTO(se.Peer),
+		Reconciliation:  newSessionReconciliationDTO(se.Reconciliation),
+
+		TrafficCategories: newTrafficCategoriesDTO(se.TrafficCategories),
+		SLABreach:         se.SLABreach,
+		SLARefund:         se.SLARefund,
+		DisconnectReason:  string(se.DisconnectReason),
+	}
+}
+
+func newTrafficCategoriesDTO(breakdown trafficcategory.Breakdown) map[string]TrafficCategoryDTO {
+	if len(breakdown) == 0 {
+		return nil
+	}
+
+	res := make(map[string]TrafficCategoryDTO, len(breakdown))
+	for category, c := range breakdown {
+		res[string(category)] = TrafficCategoryDTO{
+			BytesSent:     c.BytesSent,
+			BytesReceived: c.BytesReceived,
+		}
+	}
+	return res
+}
+
+// TrafficCategoryDTO represents amount of traffic in a single destination category.
+// swagger:model TrafficCategoryDTO
+type TrafficCategoryDTO struct {
+	BytesSent     uint64 `json:"bytes_sent"`
+	BytesReceived uint64 `json:"bytes_received"`
+}
+
+func newSessionReconciliationDTO(rec *session.Reconciliation) *SessionReconciliationDTO {
+	if rec == nil {
+		return nil
+	}
+
+	return &SessionReconciliationDTO{
+		PeerBytesSent:     rec.PeerDataSent,
+		PeerBytesReceived: rec.PeerDataReceived,
+		PeerTokens:        rec.PeerTokens,
+		Discrepancy:       rec.Discrepancy,
+		ReconciledAt:      rec.ReconciledAt.Format(time.RFC3339),
 	}
 }
 
@@ -316,4 +464,78 @@ type SessionDTO struct {
 
 	// example: residential
 	IPType string `json:"ip_type"`
+
+	// negotiated session protocol version, 0 for peers which do not announce it
+	// example: 1
+	ProtocolVersion uint32 `json:"protocol_version"`
+
+	// example: ["compression","reconciliation"]
+	Capabilities []string `json:"capabilities"`
+
+	// node version and capabilities announced by the other side of the session, absent for peers which do not announce them
+	Peer *SessionPeerDTO `json:"peer,omitempty"`
+
+	Reconciliation *SessionReconciliationDTO `json:"reconciliation,omitempty"`
+
+	// Local only traffic breakdown by destination category, present when categorization is enabled.
+	TrafficCategories map[string]TrafficCategoryDTO `json:"traffic_categories,omitempty"`
+
+	// breach of the provider SLA, empty if the SLA was fulfilled
+	// example: throughput 800000 bps is below 1000000 bps
+	SLABreach string `json:"sla_breach,omitempty"`
+
+	// refunded share of the session price in percent
+	// example: 10
+	SLARefund uint8 `json:"sla_refund,omitempty"`
+
+	// reason reported by the consumer when ending the session, empty if unknown
+	// example: user_request
+	DisconnectReason string `json:"disconnect_reason,omitempty"`
+}
+
+// SessionReconciliationDTO represents the peer view of the session accounting exchanged at the session end.
+// swagger:model SessionReconciliationDTO
+type SessionReconciliationDTO struct {
+	// example: 1024
+	PeerBytesSent uint64 `json:"peer_bytes_sent"`
+
+	// example: 1024
+	PeerBytesReceived uint64 `json:"peer_bytes_received"`
+
+	// example: 500000
+	PeerTokens *big.Int `json:"peer_tokens"`
+
+	// true if peer accounting differs from the local one beyond the tolerance
+	// example: false
+	Discrepancy bool `json:"discrepancy"`
+
+	// example: 2019-06-06T11:04:43.910035Z
+	ReconciledAt string `json:"reconciled_at"`
+}
+
+// SessionPeerDTO describes the node version and capabilities announced by the other side of a session.
+// swagger:model SessionPeerDTO
+type SessionPeerDTO struct {
+	// example: 1.30.0
+	Version string `json:"version,omitempty"`
+
+	// names of the known announced capabilities
+	// example: ["reconciliation"]
+	Capabilities []string `json:"capabilities"`
+
+	// bitmap of all announced capabilities, including the ones unknown to this node
+	// example: 1
+	CapabilityFlags uint64 `json:"capability_flags"`
+}
+
+// NewSessionPeerDTO maps to API session peer, nil if the peer announced nothing.
+func NewSessionPeerDTO(peer node_session.Peer) *SessionPeerDTO {
+	if peer == (node_session.Peer{}) {
+		return nil
+	}
+	return &SessionPeerDTO{
+		Version:         peer.Version,
+		Capabilities:    peer.Capabilities.Names(),
+		CapabilityFlags: uint64(peer.Capabilities),
+	}
 }
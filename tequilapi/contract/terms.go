@@ -18,6 +18,8 @@
 package contract
 
 import (
+	"github.com/mysteriumnetwork/go-rest/apierror"
+
 	"github.com/mysteriumnetwork/node/config"
 	"github.com/mysteriumnetwork/terms/terms-go"
 )
@@ -73,6 +75,21 @@ func NewTermsResp() *TermsResponse {
 	}
 }
 
+// ValidateCurrent validates that the request accepts the current terms version.
+func (t *TermsRequest) ValidateCurrent(current string) *apierror.APIError {
+	v := apierror.NewValidator()
+	if t.AgreedVersion == "" {
+		v.Required("agreed_version")
+	} else if t.AgreedVersion != current {
+		v.Invalid("agreed_version", "Current terms version "+current+" has to be accepted")
+	}
+	if t.AgreedProvider == nil && t.AgreedConsumer == nil {
+		v.Required("agreed_provider")
+		v.Required("agreed_consumer")
+	}
+	return v.Err()
+}
+
 // ToMap turns a TermsRequest in to an iterable map which
 // can be mapped directly to a user config.
 func (t *TermsRequest) ToMap() map[string]interface{} {
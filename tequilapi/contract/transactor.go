@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"math/big"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/mysteriumnetwork/go-rest/apierror"
@@ -103,6 +104,10 @@ type SettlementListQuery struct {
 	// in: query
 	ProviderID *string `json:"provider_id"`
 
+	// Identity to filter the settlements by. Alias of provider_id.
+	// in: query
+	Identity *string `json:"identity"`
+
 	// Hermes ID to filter the sessions by.
 	// in: query
 	HermesID *string `json:"hermes_id"`
@@ -139,6 +144,14 @@ func (q *SettlementListQuery) Bind(request *http.Request) *apierror.APIError {
 	if qStr := qs.Get("provider_id"); qStr != "" {
 		q.ProviderID = &qStr
 	}
+	if qStr := qs.Get("identity"); qStr != "" {
+		if q.ProviderID != nil && !strings.EqualFold(*q.ProviderID, qStr) {
+			v.Invalid("identity", "'identity' conflicts with 'provider_id'")
+		} else {
+			q.Identity = &qStr
+			q.ProviderID = &qStr
+		}
+	}
 	if qStr := qs.Get("hermes_id"); qStr != "" {
 		q.HermesID = &qStr
 	}
@@ -217,6 +230,8 @@ func NewSettlementDTO(settlement pingpong.SettlementHistoryEntry) SettlementDTO
 		Error:            settlement.Error,
 		IsWithdrawal:     settlement.IsWithdrawal,
 		BlockExplorerURL: settlement.BlockExplorerURL,
+		BlockNumber:      settlement.BlockNumber,
+		ChainID:          settlement.Promise.ChainID,
 	}
 }
 
@@ -253,6 +268,12 @@ type SettlementDTO struct {
 	// example: https://example.com
 	BlockExplorerURL string `json:"block_explorer_url"`
 
+	// example: 45132071
+	BlockNumber uint64 `json:"block_number"`
+
+	// example: 137
+	ChainID int64 `json:"chain_id"`
+
 	// example: internal server error
 	Error string `json:"error"`
 }
@@ -265,6 +286,43 @@ type SettleRequest struct {
 
 	// Deprecated
 	HermesID string `json:"hermes_id"`
+
+	GasPrice *GasPriceDTO `json:"gas_price,omitempty"`
+}
+
+// GasPriceDTO overrides the configured gas price strategy for the transactions of a single request.
+// swagger:model GasPriceDTO
+type GasPriceDTO struct {
+	// Speed: economy, normal or fast
+	Speed string `json:"speed,omitempty"`
+	// MaxFeePerGas: EIP-1559 fee cap in wei
+	// example: 1000000000000
+	MaxFeePerGas *big.Int `json:"max_fee_per_gas,omitempty"`
+	// MaxPriorityFeePerGas: EIP-1559 tip cap in wei
+	// example: 30000000000
+	MaxPriorityFeePerGas *big.Int `json:"max_priority_fee_per_gas,omitempty"`
+}
+
+// GasPrice returns the validated gas price.
+func (g *GasPriceDTO) GasPrice() (registry.GasPrice, error) {
+	price := registry.GasPrice{
+		MaxFeePerGas:         g.MaxFeePerGas,
+		MaxPriorityFeePerGas: g.MaxPriorityFeePerGas,
+	}
+	if g.Speed != "" {
+		speed, err := registry.ParseGasSpeed(g.Speed)
+		if err != nil {
+			return registry.GasPrice{}, err
+		}
+		price.Speed = speed
+	}
+	if g.MaxFeePerGas != nil && g.MaxFeePerGas.Sign() <= 0 {
+		return registry.GasPrice{}, fmt.Errorf("max fee per gas must be positive")
+	}
+	if g.MaxPriorityFeePerGas != nil && g.MaxPriorityFeePerGas.Sign() < 0 {
+		return registry.GasPrice{}, fmt.Errorf("max priority fee per gas must not be negative")
+	}
+	return price, nil
 }
 
 // WithdrawRequest represents the request to withdraw earnings to l1.
@@ -276,6 +334,10 @@ type WithdrawRequest struct {
 	FromChainID int64  `json:"from_chain_id"`
 	ToChainID   int64  `json:"to_chain_id"`
 	Amount      string `json:"amount,omitempty"`
+
+	PayoutTarget
+
+	GasPrice *GasPriceDTO `json:"gas_price,omitempty"`
 }
 
 // Validate will validate a given request
@@ -288,7 +350,11 @@ func (w *WithdrawRequest) Validate() *apierror.APIError {
 	if !common.IsHexAddress(w.ProviderID) || w.ProviderID == zeroAddr {
 		v.Invalid("provider_id", "'provider_id' should be a valid hex address")
 	}
-	if !common.IsHexAddress(w.Beneficiary) || w.Beneficiary == zeroAddr {
+	if w.AddressBookEntry != "" {
+		if w.Beneficiary != "" {
+			v.Invalid("address_book_entry", "'address_book_entry' can't be used together with 'beneficiary'")
+		}
+	} else if !common.IsHexAddress(w.Beneficiary) || w.Beneficiary == zeroAddr {
 		v.Invalid("beneficiary", "'beneficiary' should be a valid hex address")
 	}
 
@@ -323,6 +389,85 @@ type SettleWithBeneficiaryRequest struct {
 	ProviderID  string `json:"provider_id"`
 	HermesID    string `json:"hermes_id"`
 	Beneficiary string `json:"beneficiary"`
+
+	PayoutTarget
+
+	GasPrice *GasPriceDTO `json:"gas_price,omitempty"`
+}
+
+// ChangeBeneficiaryRequest represents the request to change beneficiary address.
+// swagger:model ChangeBeneficiaryRequest
+type ChangeBeneficiaryRequest struct {
+	Beneficiary string `json:"beneficiary"`
+	// Hermeses to settle into, active hermes is used if empty.
+	HermesIDs []string `json:"hermes_ids,omitempty"`
+
+	PayoutTarget
+}
+
+// PayoutTarget refers to the address book and confirms the payout address.
+type PayoutTarget struct {
+	// Address book entry to pay out to instead of the beneficiary address.
+	// example: exchange
+	AddressBookEntry string `json:"address_book_entry,omitempty"`
+
+	// Token confirming the payout address, returned by the previous request if the address needs a confirmation.
+	ConfirmationToken string `json:"confirmation_token,omitempty"`
+}
+
+// SettlementPreviewResponse represents the expected outcome of the settlement returned in the dry-run mode.
+// swagger:model SettlementPreviewResponse
+type SettlementPreviewResponse struct {
+	Settlements []SettlementPreviewDTO `json:"settlements"`
+}
+
+// SettlementPreviewDTO represents the expected outcome of the settlement with a single hermes.
+// swagger:model SettlementPreviewDTO
+type SettlementPreviewDTO struct {
+	// example: 0x0000000000000000000000000000000000000001
+	HermesID string `json:"hermes_id"`
+
+	// example: 0x0000000000000000000000000000000000000001
+	Beneficiary string `json:"beneficiary"`
+
+	// true if the settlement would also change the beneficiary
+	BeneficiaryChange bool `json:"beneficiary_change"`
+
+	// unsettled earnings
+	Amount Tokens `json:"amount"`
+
+	TransactorFee Tokens `json:"transactor_fee"`
+
+	HermesFee Tokens `json:"hermes_fee"`
+
+	// earnings expected to reach the beneficiary after fees
+	Payout Tokens `json:"payout"`
+
+	// false if the settlement would be rejected
+	WillSettle bool `json:"will_settle"`
+
+	// reason why the settlement would be rejected
+	// example: fee not covered, cannot continue
+	Reason string `json:"reason,omitempty"`
+}
+
+// NewSettlementPreviewResponse maps to API settlement preview.
+func NewSettlementPreviewResponse(previews []pingpong.SettlementPreview) SettlementPreviewResponse {
+	res := SettlementPreviewResponse{Settlements: make([]SettlementPreviewDTO, len(previews))}
+	for i, p := range previews {
+		res.Settlements[i] = SettlementPreviewDTO{
+			HermesID:          p.HermesID.Hex(),
+			Beneficiary:       p.Beneficiary.Hex(),
+			BeneficiaryChange: p.BeneficiaryChange,
+			Amount:            NewTokens(p.Amount),
+			TransactorFee:     NewTokens(p.TransactorFee),
+			HermesFee:         NewTokens(p.HermesFee),
+			Payout:            NewTokens(p.Payout),
+			WillSettle:        p.WillSettle,
+			Reason:            p.Reason,
+		}
+	}
+	return res
 }
 
 // DecreaseStakeRequest represents the decrease stake request
@@ -330,6 +475,28 @@ type SettleWithBeneficiaryRequest struct {
 type DecreaseStakeRequest struct {
 	ID     string   `json:"id,omitempty"`
 	Amount *big.Int `json:"amount,omitempty"`
+
+	GasPrice *GasPriceDTO `json:"gas_price,omitempty"`
+}
+
+// IdentityStakeIncreaseRequest represents the request to settle earnings into stake.
+// swagger:model IdentityStakeIncreaseRequest
+type IdentityStakeIncreaseRequest struct {
+	// Hermeses to settle, active hermes is used if empty.
+	HermesIDs []string `json:"hermes_ids,omitempty"`
+}
+
+// IdentityStakeDecreaseRequest represents the request to decrease stake.
+// swagger:model IdentityStakeDecreaseRequest
+type IdentityStakeDecreaseRequest struct {
+	Amount *big.Int `json:"amount"`
+}
+
+// IdentityStakeResponse represents the current and the minimum stake of identity.
+// swagger:model IdentityStakeResponse
+type IdentityStakeResponse struct {
+	Stake        *big.Int `json:"stake"`
+	MinimumStake *big.Int `json:"minimum_stake"`
 }
 
 // ReferralTokenResponse represents a response for referral token.
@@ -359,3 +526,73 @@ type ChainSummary struct {
 	Chains       map[int64]string `json:"chains"`
 	CurrentChain int64            `json:"current_chain"`
 }
+
+// NewTransactorAuditListResponse maps to API transactor audit log list.
+func NewTransactorAuditListResponse(entries []registry.TransactorAuditEntry, paginator *utils.Paginator, verifyErr error) TransactorAuditListResponse {
+	dtoArray := make([]TransactorAuditEntryDTO, len(entries))
+	for i, entry := range entries {
+		dtoArray[i] = TransactorAuditEntryDTO{
+			Seq:         entry.Seq,
+			Time:        entry.Time.Format(time.RFC3339Nano),
+			Endpoint:    entry.Endpoint,
+			Identity:    entry.Identity,
+			ChainID:     entry.ChainID,
+			PayloadHash: entry.PayloadHash,
+			Result:      entry.Result,
+			Error:       entry.Error,
+			PrevHash:    entry.PrevHash,
+			Hash:        entry.Hash,
+		}
+	}
+
+	response := TransactorAuditListResponse{
+		Items:       dtoArray,
+		Verified:    verifyErr == nil,
+		PageableDTO: NewPageableDTO(paginator),
+	}
+	if verifyErr != nil {
+		response.VerifyError = verifyErr.Error()
+	}
+	return response
+}
+
+// TransactorAuditListResponse defines transactor audit log list representable as json.
+// swagger:model TransactorAuditListResponse
+type TransactorAuditListResponse struct {
+	Items []TransactorAuditEntryDTO `json:"items"`
+	// Whether the hash chain of the whole audit log is intact.
+	Verified    bool   `json:"verified"`
+	VerifyError string `json:"verify_error,omitempty"`
+	PageableDTO
+}
+
+// TransactorAuditEntryDTO represents a request sent to transactor.
+// swagger:model TransactorAuditEntryDTO
+type TransactorAuditEntryDTO struct {
+	// example: 1
+	Seq uint64 `json:"seq"`
+
+	// example: 2019-06-06T11:04:43.910035Z
+	Time string `json:"time"`
+
+	// example: identity/settle_and_rebalance
+	Endpoint string `json:"endpoint"`
+
+	// example: 0x0000000000000000000000000000000000000001
+	Identity string `json:"identity"`
+
+	// example: 137
+	ChainID int64 `json:"chain_id"`
+
+	// SHA-256 of the request payload.
+	PayloadHash string `json:"payload_hash"`
+
+	// example: ok
+	Result string `json:"result"`
+
+	Error string `json:"error,omitempty"`
+
+	PrevHash string `json:"prev_hash"`
+
+	Hash string `json:"hash"`
+}
@@ -0,0 +1,65 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package contract
+
+import "github.com/mysteriumnetwork/node/requests"
+
+var (
+	// upstreamProxyProxied lists node traffic sent through the upstream proxy.
+	upstreamProxyProxied = []string{"discovery", "broker", "blockchain_rpc", "transactor", "hermes", "location"}
+	// upstreamProxyExempted lists node traffic which always goes directly, as UDP can not be proxied.
+	upstreamProxyExempted = []string{"p2p", "nat_traversal", "tunnel"}
+)
+
+// NewUpstreamProxyDTO maps to API upstream proxy status, nil proxy means it is not configured.
+func NewUpstreamProxyDTO(p *requests.UpstreamProxy) UpstreamProxyDTO {
+	dto := UpstreamProxyDTO{
+		Proxied:  upstreamProxyProxied,
+		Exempted: upstreamProxyExempted,
+	}
+	if p != nil {
+		dto.Enabled = true
+		dto.Type = p.Type()
+		dto.Address = p.Address()
+		dto.Authenticated = p.Authenticated()
+	}
+	return dto
+}
+
+// UpstreamProxyDTO describes the proxy node control traffic is routed through.
+// swagger:model UpstreamProxyDTO
+type UpstreamProxyDTO struct {
+	Enabled bool `json:"enabled"`
+
+	// example: socks5
+	Type string `json:"type,omitempty"`
+
+	// example: proxy.local:1080
+	Address string `json:"address,omitempty"`
+
+	// whether credentials are sent to the proxy, they are never returned
+	Authenticated bool `json:"authenticated"`
+
+	// node traffic routed through the proxy when it is enabled
+	// example: ["discovery","broker","blockchain_rpc","transactor","hermes","location"]
+	Proxied []string `json:"proxied"`
+
+	// node traffic never routed through the proxy, data plane goes directly to the peers
+	// example: ["p2p","nat_traversal","tunnel"]
+	Exempted []string `json:"exempted"`
+}
@@ -0,0 +1,66 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package contract
+
+import (
+	"time"
+
+	"github.com/mysteriumnetwork/node/core/watchdog"
+)
+
+// WatchdogDTO describes health of the long-running node components.
+// swagger:model WatchdogDTO
+type WatchdogDTO struct {
+	Components []ComponentHealthDTO `json:"components"`
+}
+
+// ComponentHealthDTO describes health of a single long-running component.
+// swagger:model ComponentHealthDTO
+type ComponentHealthDTO struct {
+	// example: registry-watcher
+	Name string `json:"name"`
+
+	// period within which the component is expected to report it is alive
+	// example: 3h0m0s
+	Timeout string `json:"timeout"`
+
+	// example: 2024-05-01T12:00:00Z
+	LastBeat string `json:"last_beat"`
+
+	// example: false
+	Stalled bool `json:"stalled"`
+
+	// number of times the stalled component was restarted
+	// example: 0
+	Restarts int `json:"restarts"`
+}
+
+// NewWatchdogDTO maps to API component health.
+func NewWatchdogDTO(status []watchdog.ComponentStatus) WatchdogDTO {
+	dto := WatchdogDTO{Components: make([]ComponentHealthDTO, len(status))}
+	for i, s := range status {
+		dto.Components[i] = ComponentHealthDTO{
+			Name:     s.Name,
+			Timeout:  s.Timeout.String(),
+			LastBeat: s.LastBeat.UTC().Format(time.RFC3339),
+			Stalled:  s.Stalled,
+			Restarts: s.Restarts,
+		}
+	}
+	return dto
+}
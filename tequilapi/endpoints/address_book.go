@@ -0,0 +1,223 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gin-gonic/gin"
+	"github.com/mysteriumnetwork/go-rest/apierror"
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/core/beneficiary"
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
+	"github.com/mysteriumnetwork/node/tequilapi/utils"
+)
+
+type addressResolver interface {
+	Address(target string) (common.Address, error)
+	Resolve(chainID int64, target, confirmationToken string) (common.Address, error)
+}
+
+type addressBook interface {
+	addressResolver
+	Add(name, address, note string) (beneficiary.AddressBookEntry, error)
+	List() ([]beneficiary.AddressBookEntry, error)
+	Remove(name string) error
+}
+
+type addressBookEndpoint struct {
+	book addressBook
+}
+
+// List returns the address book entries.
+//
+// swagger:operation GET /address-book AddressBook listAddressBook
+//
+//	---
+//	summary: Returns address book
+//	description: Returns the named payout targets which can be used instead of addresses for beneficiary changes and withdrawals.
+//	responses:
+//	  200:
+//	    description: Address book entries
+//	    schema:
+//	      "$ref": "#/definitions/AddressBookResponse"
+//	  500:
+//	    description: Internal server error
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+func (abe *addressBookEndpoint) List(c *gin.Context) {
+	entries, err := abe.book.List()
+	if err != nil {
+		c.Error(apierror.Internal("Could not list address book: "+err.Error(), contract.ErrCodeAddressBook))
+		return
+	}
+	utils.WriteAsJSON(contract.NewAddressBookResponse(entries), c.Writer)
+}
+
+// Add adds an address book entry.
+//
+// swagger:operation POST /address-book AddressBook addAddressBookEntry
+//
+//	---
+//	summary: Adds address book entry
+//	description: Adds a named payout target.
+//	parameters:
+//	- in: body
+//	  name: body
+//	  schema:
+//	    $ref: "#/definitions/AddAddressBookEntryRequest"
+//	responses:
+//	  201:
+//	    description: Entry added
+//	    schema:
+//	      "$ref": "#/definitions/AddressBookEntryDTO"
+//	  400:
+//	    description: Failed to parse or request validation failed
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  409:
+//	    description: Entry with the given name already exists
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  500:
+//	    description: Internal server error
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+func (abe *addressBookEndpoint) Add(c *gin.Context) {
+	var req contract.AddAddressBookEntryRequest
+	if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil {
+		c.Error(apierror.ParseFailed())
+		return
+	}
+	if err := req.Validate(); err != nil {
+		c.Error(err)
+		return
+	}
+
+	entry, err := abe.book.Add(req.Name, req.Address, req.Note)
+	switch {
+	case errors.Is(err, beneficiary.ErrEntryExists):
+		c.Error(apierror.Conflict(err.Error(), contract.ErrCodeAddressBookEntryExists, "name"))
+		return
+	case err != nil:
+		c.Error(apierror.Internal("Could not add address book entry: "+err.Error(), contract.ErrCodeAddressBook))
+		return
+	}
+	utils.WriteAsJSON(contract.NewAddressBookEntryDTO(entry), c.Writer, http.StatusCreated)
+}
+
+// Remove removes an address book entry.
+//
+// swagger:operation DELETE /address-book/{name} AddressBook removeAddressBookEntry
+//
+//	---
+//	summary: Removes address book entry
+//	parameters:
+//	- name: name
+//	  in: path
+//	  type: string
+//	  required: true
+//	responses:
+//	  204:
+//	    description: Entry removed
+//	  404:
+//	    description: Entry not found
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  500:
+//	    description: Internal server error
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+func (abe *addressBookEndpoint) Remove(c *gin.Context) {
+	err := abe.book.Remove(c.Param("name"))
+	switch {
+	case errors.Is(err, beneficiary.ErrEntryNotFound):
+		c.Error(apierror.NotFound("Address book entry not found"))
+		return
+	case err != nil:
+		c.Error(apierror.Internal("Could not remove address book entry: "+err.Error(), contract.ErrCodeAddressBook))
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// resolvePayoutAddress returns the payout address of the request, given either as an address or an address book
+// entry, after checking it against the address book rules. It writes the response and returns false if the
+// address can't be used yet. Without the address book only addresses are accepted and they are not checked.
+// Rules are not applied in the dry-run mode, so that the confirmation is left for the actual payout.
+func resolvePayoutAddress(c *gin.Context, book addressResolver, chainID int64, address string, target contract.PayoutTarget, dryRun bool, code string) (common.Address, bool) {
+	if target.AddressBookEntry != "" && address != "" {
+		c.Error(apierror.BadRequestField("'address_book_entry' can't be used together with 'beneficiary'", apierror.ValidateErrInvalidVal, "address_book_entry"))
+		return common.Address{}, false
+	}
+	if target.AddressBookEntry == "" && !common.IsHexAddress(address) {
+		c.Error(apierror.BadRequest("Invalid beneficiary address", code))
+		return common.Address{}, false
+	}
+	if book == nil {
+		if target.AddressBookEntry != "" {
+			c.Error(apierror.BadRequestField("Address book is not available", apierror.ValidateErrInvalidVal, "address_book_entry"))
+			return common.Address{}, false
+		}
+		return common.HexToAddress(address), true
+	}
+
+	name := target.AddressBookEntry
+	if name == "" {
+		name = address
+	}
+	var resolved common.Address
+	var err error
+	if dryRun {
+		resolved, err = book.Address(name)
+	} else {
+		resolved, err = book.Resolve(chainID, name, target.ConfirmationToken)
+	}
+	var confirmation *beneficiary.ConfirmationRequiredError
+	switch {
+	case errors.As(err, &confirmation):
+		log.Info().Msgf("Payout to %s needs a confirmation: %v", confirmation.Address.Hex(), confirmation.Warnings)
+		utils.WriteAsJSON(contract.NewAddressConfirmationRequiredResponse(confirmation), c.Writer, http.StatusPreconditionRequired)
+		return common.Address{}, false
+	case errors.Is(err, beneficiary.ErrEntryNotFound):
+		c.Error(apierror.BadRequestField("Address book entry not found", apierror.ValidateErrInvalidVal, "address_book_entry"))
+		return common.Address{}, false
+	case err != nil:
+		c.Error(apierror.Internal("Could not check payout address: "+err.Error(), code))
+		return common.Address{}, false
+	}
+	return resolved, true
+}
+
+// AddRoutesForAddressBook registers address book endpoints in Tequilapi.
+func AddRoutesForAddressBook(book addressBook) func(*gin.Engine) error {
+	abe := &addressBookEndpoint{book: book}
+	return func(e *gin.Engine) error {
+		g := e.Group("/address-book")
+		{
+			g.GET("", abe.List)
+			g.POST("", abe.Add)
+			g.DELETE("/:name", abe.Remove)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,119 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mysteriumnetwork/node/core/beneficiary"
+	"github.com/mysteriumnetwork/node/core/storage/boltdb"
+	"github.com/mysteriumnetwork/node/core/storage/boltdb/boltdbtest"
+	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
+)
+
+func newTestAddressBook(t *testing.T, rules beneficiary.AddressBookRules) *beneficiary.AddressBook {
+	dir := boltdbtest.CreateTempDir(t)
+	t.Cleanup(func() { boltdbtest.RemoveTempDir(t, dir) })
+	db, err := boltdb.NewStorage(dir)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return beneficiary.NewAddressBook(db, nil, rules)
+}
+
+func Test_AddressBook(t *testing.T) {
+	router := summonTestGin()
+	err := AddRoutesForAddressBook(newTestAddressBook(t, beneficiary.AddressBookRules{}))(router)
+	require.NoError(t, err)
+
+	request := func(method, url, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, url, strings.NewReader(body))
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+
+	resp := request(http.MethodPost, "/address-book", `{"name": "exchange", "address": "0x000000000000000000000000000000000000000b", "note": "deposit"}`)
+	assert.Equal(t, http.StatusCreated, resp.Code)
+
+	resp = request(http.MethodPost, "/address-book", `{"name": "exchange", "address": "0x000000000000000000000000000000000000000c"}`)
+	assert.Equal(t, http.StatusConflict, resp.Code)
+
+	resp = request(http.MethodPost, "/address-book", `{"name": "0x000000000000000000000000000000000000000c", "address": "0x000000000000000000000000000000000000000c"}`)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	resp = request(http.MethodGet, "/address-book", "")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var book contract.AddressBookResponse
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &book))
+	require.Len(t, book.Entries, 1)
+	assert.Equal(t, "exchange", book.Entries[0].Name)
+	assert.Equal(t, common.HexToAddress("0xb").Hex(), book.Entries[0].Address)
+	assert.Equal(t, "deposit", book.Entries[0].Note)
+
+	resp = request(http.MethodDelete, "/address-book/exchange", "")
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+	resp = request(http.MethodDelete, "/address-book/exchange", "")
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+}
+
+func Test_ChangeBeneficiary_AddressBook(t *testing.T) {
+	book := newTestAddressBook(t, beneficiary.AddressBookRules{ConfirmFirstUse: true, ConfirmationTTL: time.Minute})
+	_, err := book.Add("exchange", "0x000000000000000000000000000000000000000b", "")
+	require.NoError(t, err)
+
+	changer := &mockBeneficiaryChanger{}
+	router := summonTestGin()
+	err = AddRoutesForBeneficiary(changer, identity.NewIdentityManagerFake(existingIdentities, newIdentity), nil, book)(router)
+	require.NoError(t, err)
+
+	request := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/identities/0x000000000000000000000000000000000000000a/beneficiary-change", strings.NewReader(body))
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+
+	resp := request(`{"address_book_entry": "unknown"}`)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	resp = request(`{"address_book_entry": "exchange", "beneficiary": "0x000000000000000000000000000000000000000b"}`)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	resp = request(`{"address_book_entry": "exchange"}`)
+	require.Equal(t, http.StatusPreconditionRequired, resp.Code)
+	var confirmation contract.AddressConfirmationRequiredResponse
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &confirmation))
+	assert.Equal(t, common.HexToAddress("0xb").Hex(), confirmation.Address)
+	assert.NotEmpty(t, confirmation.ConfirmationToken)
+	assert.NotEmpty(t, confirmation.Warnings)
+	assert.Equal(t, common.Address{}, changer.beneficiary)
+
+	resp = request(`{"address_book_entry": "exchange", "confirmation_token": "` + confirmation.ConfirmationToken + `"}`)
+	assert.Equal(t, http.StatusAccepted, resp.Code)
+	assert.Equal(t, common.HexToAddress("0xb"), changer.beneficiary)
+}
@@ -0,0 +1,137 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"encoding/json"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mysteriumnetwork/go-rest/apierror"
+
+	"github.com/mysteriumnetwork/node/config"
+	"github.com/mysteriumnetwork/node/core/analytics"
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
+	"github.com/mysteriumnetwork/node/tequilapi/utils"
+)
+
+type analyticsPipeline interface {
+	Settings() analytics.Settings
+	SetSettings(settings analytics.Settings) error
+	Preview() analytics.Batch
+}
+
+type analyticsEndpoint struct {
+	pipeline analyticsPipeline
+	config   configProvider
+}
+
+// GetSettings returns anonymous usage analytics settings
+//
+// swagger:operation GET /analytics Analytics getAnalyticsSettings
+//
+//	---
+//	summary: Get analytics settings
+//	description: Returns whether anonymous usage analytics are sent and which categories are selected
+//	responses:
+//	  200:
+//	    description: Analytics settings
+//	    schema:
+//	      "$ref": "#/definitions/AnalyticsSettingsResponse"
+func (ae *analyticsEndpoint) GetSettings(c *gin.Context) {
+	utils.WriteAsJSON(contract.NewAnalyticsSettingsResponse(ae.pipeline.Settings()), c.Writer)
+}
+
+// UpdateSettings opts in to or out of anonymous usage analytics
+//
+// swagger:operation PUT /analytics Analytics updateAnalyticsSettings
+//
+//	---
+//	summary: Update analytics settings
+//	description: Enables or disables anonymous usage analytics and selects the categories to send
+//	parameters:
+//	- in: body
+//	  name: body
+//	  schema:
+//	    $ref: "#/definitions/AnalyticsSettingsRequest"
+//	responses:
+//	  200:
+//	    description: Analytics settings updated
+//	    schema:
+//	      "$ref": "#/definitions/AnalyticsSettingsResponse"
+//	  400:
+//	    description: Failed to parse or request validation failed
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  500:
+//	    description: Internal server error
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+func (ae *analyticsEndpoint) UpdateSettings(c *gin.Context) {
+	var req contract.AnalyticsSettingsRequest
+	if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil {
+		c.Error(apierror.ParseFailed())
+		return
+	}
+	if err := req.Validate(); err != nil {
+		c.Error(err)
+		return
+	}
+
+	settings := analytics.Settings{Enabled: req.Enabled, Categories: req.Categories}
+	if err := ae.pipeline.SetSettings(settings); err != nil {
+		c.Error(apierror.Internal("Failed to update analytics settings: "+err.Error(), contract.ErrCodeAnalyticsSettings))
+		return
+	}
+
+	ae.config.SetUser(config.FlagAnalyticsEnabled.Name, settings.Enabled)
+	ae.config.SetUser(config.FlagAnalyticsCategories.Name, settings.Categories)
+	if err := ae.config.SaveUserConfig(); err != nil {
+		c.Error(apierror.Internal("Failed to save config", contract.ErrCodeConfigSave))
+		return
+	}
+
+	utils.WriteAsJSON(contract.NewAnalyticsSettingsResponse(ae.pipeline.Settings()), c.Writer)
+}
+
+// Preview returns the anonymized events of the next analytics upload
+//
+// swagger:operation GET /analytics/preview Analytics previewAnalytics
+//
+//	---
+//	summary: Preview analytics
+//	description: Returns exactly what would be sent in the next upload with the current settings. Events are kept locally while analytics are disabled.
+//	responses:
+//	  200:
+//	    description: Anonymized analytics events
+//	    schema:
+//	      "$ref": "#/definitions/AnalyticsPreviewResponse"
+func (ae *analyticsEndpoint) Preview(c *gin.Context) {
+	utils.WriteAsJSON(contract.NewAnalyticsPreviewResponse(ae.pipeline.Settings(), ae.pipeline.Preview()), c.Writer)
+}
+
+// AddRoutesForAnalytics registers /analytics endpoints in Tequilapi
+func AddRoutesForAnalytics(pipeline analyticsPipeline) func(*gin.Engine) error {
+	ae := &analyticsEndpoint{pipeline: pipeline, config: config.Current}
+	return func(e *gin.Engine) error {
+		g := e.Group("/analytics")
+		g.GET("", ae.GetSettings)
+		g.PUT("", ae.UpdateSettings)
+		g.GET("/preview", ae.Preview)
+		return nil
+	}
+}
@@ -0,0 +1,104 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/core/analytics"
+)
+
+func TestAnalytics_UpdateSettings(t *testing.T) {
+	pipeline := &mockAnalyticsPipeline{}
+	cfg := &mockTermsConfig{user: map[string]interface{}{}}
+	ae := &analyticsEndpoint{pipeline: pipeline, config: cfg}
+
+	g := summonTestGin()
+	g.PUT("/analytics", ae.UpdateSettings)
+
+	req := httptest.NewRequest(http.MethodPut, "/analytics", strings.NewReader(`{"enabled": true, "categories": ["location"]}`))
+	resp := httptest.NewRecorder()
+	g.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.False(t, pipeline.settings.Enabled)
+
+	req = httptest.NewRequest(http.MethodPut, "/analytics", strings.NewReader(`{"enabled": true, "categories": ["nat"]}`))
+	resp = httptest.NewRecorder()
+	g.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, analytics.Settings{Enabled: true, Categories: []string{"nat"}}, pipeline.settings)
+	assert.Equal(t, true, cfg.user["analytics.enabled"])
+	assert.Equal(t, []string{"nat"}, cfg.user["analytics.categories"])
+	assert.True(t, cfg.saved)
+	assert.JSONEq(t, `{
+		"enabled": true,
+		"categories": ["nat"],
+		"available": ["sessions", "connections", "discovery", "nat", "identity", "performance"]
+	}`, resp.Body.String())
+}
+
+func TestAnalytics_Preview(t *testing.T) {
+	pipeline := &mockAnalyticsPipeline{
+		settings: analytics.Settings{Categories: []string{"nat"}},
+		batch: analytics.Batch{Events: []analytics.Event{{
+			Category:  "nat",
+			Name:      "nat_mapping",
+			CreatedAt: time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC),
+			Data:      map[string]interface{}{"successful": true},
+		}}},
+	}
+
+	g := summonTestGin()
+	assert.NoError(t, AddRoutesForAnalytics(pipeline)(g))
+
+	req := httptest.NewRequest(http.MethodGet, "/analytics/preview", nil)
+	resp := httptest.NewRecorder()
+	g.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `{
+		"enabled": false,
+		"events": [
+			{"category": "nat", "name": "nat_mapping", "created_at": "2024-01-02T15:04:05Z", "data": {"successful": true}}
+		]
+	}`, resp.Body.String())
+}
+
+type mockAnalyticsPipeline struct {
+	settings analytics.Settings
+	batch    analytics.Batch
+}
+
+func (m *mockAnalyticsPipeline) Settings() analytics.Settings {
+	return m.settings
+}
+
+func (m *mockAnalyticsPipeline) SetSettings(settings analytics.Settings) error {
+	m.settings = settings
+	return nil
+}
+
+func (m *mockAnalyticsPipeline) Preview() analytics.Batch {
+	return m.batch
+}
@@ -0,0 +1,128 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mysteriumnetwork/go-rest/apierror"
+
+	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/metadata"
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
+	"github.com/mysteriumnetwork/node/tequilapi/utils"
+)
+
+type attestationEndpoint struct {
+	idm             identity.Manager
+	signerFactory   identity.SignerFactory
+	startTime       time.Time
+	currentTimeFunc func() time.Time
+}
+
+// Attest signs a statement about the node with the given identity
+// swagger:operation POST /identities/{id}/attestation Identity identityAttestation
+//
+//	---
+//	summary: Attests node identity
+//	description: Signs node version and uptime along with the nonce supplied by a monitoring agent, proving that it talks to the node owning the identity
+//	parameters:
+//	- in: path
+//	  name: id
+//	  description: Identity stored in keystore
+//	  type: string
+//	  required: true
+//	- in: body
+//	  name: body
+//	  description: Nonce supplied by the verifying party
+//	  schema:
+//	    $ref: "#/definitions/IdentityAttestationRequestDTO"
+//	responses:
+//	  200:
+//	    description: Signed attestation
+//	    schema:
+//	      "$ref": "#/definitions/IdentityAttestationDTO"
+//	  400:
+//	    description: Failed to parse or request validation failed
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  403:
+//	    description: Identity is locked
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  404:
+//	    description: ID not found
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  500:
+//	    description: Internal server error
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+func (ae *attestationEndpoint) Attest(c *gin.Context) {
+	id, err := ae.idm.GetIdentity(c.Param("id"))
+	if err != nil {
+		c.Error(apierror.NotFound("ID not found"))
+		return
+	}
+	if !ae.idm.IsUnlocked(id.Address) {
+		c.Error(apierror.Forbidden("Identity is locked", contract.ErrCodeIDLocked))
+		return
+	}
+
+	var req contract.IdentityAttestationRequest
+	if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil {
+		c.Error(apierror.ParseFailed())
+		return
+	}
+	if err := req.Validate(); err != nil {
+		c.Error(err)
+		return
+	}
+
+	now := ae.currentTimeFunc()
+	attestation, err := identity.Attest(ae.signerFactory(id), identity.Attestation{
+		Identity:  id.Address,
+		Nonce:     req.Nonce,
+		Version:   metadata.VersionAsString(),
+		Uptime:    uint64(now.Sub(ae.startTime).Seconds()),
+		Timestamp: now.Unix(),
+	})
+	if err != nil {
+		c.Error(apierror.Internal("Could not sign attestation: "+err.Error(), contract.ErrCodeIDAttest))
+		return
+	}
+
+	utils.WriteAsJSON(contract.NewIdentityAttestationDTO(attestation), c.Writer)
+}
+
+// AddRoutesForAttestation creates /identities/{id}/attestation endpoint on tequilapi service,
+// uptime is measured from the node start time, currentTimeFunc is injected for easier testing
+func AddRoutesForAttestation(idm identity.Manager, signerFactory identity.SignerFactory, startTime time.Time, currentTimeFunc func() time.Time) func(*gin.Engine) error {
+	ae := &attestationEndpoint{
+		idm:             idm,
+		signerFactory:   signerFactory,
+		startTime:       startTime,
+		currentTimeFunc: currentTimeFunc,
+	}
+	return func(e *gin.Engine) error {
+		e.POST("/identities/:id/attestation", ae.Attest)
+		return nil
+	}
+}
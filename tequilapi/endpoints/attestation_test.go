@@ -0,0 +1,98 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+package endpoints
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/metadata"
+)
+
+func TestAttestation_Attest(t *testing.T) {
+	startTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := startTime.Add(time.Hour)
+	attestedID := existingIdentities[0].Address
+
+	tests := []struct {
+		name         string
+		id           string
+		body         string
+		expectedCode int
+	}{
+		{name: "missing nonce", id: attestedID, body: `{}`, expectedCode: http.StatusBadRequest},
+		{name: "too long nonce", id: attestedID, body: `{"nonce": "` + strings.Repeat("n", 257) + `"}`, expectedCode: http.StatusBadRequest},
+		{name: "invalid body", id: attestedID, body: `{"nonce": `, expectedCode: http.StatusBadRequest},
+		{name: "unknown identity", id: newIdentity.Address, body: `{"nonce": "server-nonce"}`, expectedCode: http.StatusNotFound},
+		{name: "attested", id: attestedID, body: `{"nonce": "server-nonce"}`, expectedCode: http.StatusOK},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := summonTestGin()
+			err := AddRoutesForAttestation(
+				identity.NewIdentityManagerFake(existingIdentities, newIdentity),
+				func(identity.Identity) identity.Signer { return &identity.SignerFake{} },
+				startTime,
+				func() time.Time { return now },
+			)(g)
+			assert.NoError(t, err)
+
+			req, err := http.NewRequest(http.MethodPost, "/identities/"+tc.id+"/attestation", bytes.NewBufferString(tc.body))
+			assert.NoError(t, err)
+			resp := httptest.NewRecorder()
+			g.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.expectedCode, resp.Code)
+			if tc.expectedCode != http.StatusOK {
+				return
+			}
+
+			message, err := json.Marshal(identity.Attestation{
+				Identity:  attestedID,
+				Nonce:     "server-nonce",
+				Version:   metadata.VersionAsString(),
+				Uptime:    3600,
+				Timestamp: now.Unix(),
+			})
+			assert.NoError(t, err)
+			signature, err := (&identity.SignerFake{}).Sign(message)
+			assert.NoError(t, err)
+
+			expected, err := json.Marshal(map[string]interface{}{
+				"identity":  attestedID,
+				"nonce":     "server-nonce",
+				"version":   metadata.VersionAsString(),
+				"uptime":    3600,
+				"timestamp": now.Unix(),
+				"message":   string(message),
+				"signature": hexutil.Encode(signature.Bytes()),
+			})
+			assert.NoError(t, err)
+			assert.JSONEq(t, string(expected), resp.Body.String())
+		})
+	}
+}
@@ -19,8 +19,11 @@ package endpoints
 
 import (
 	"encoding/json"
+	"errors"
+	"math"
 	"net/http"
 	"net/url"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -36,6 +39,8 @@ import (
 type authenticationAPI struct {
 	jwtAuthenticator jwtAuthenticator
 	authenticator    authenticator
+	users            userManager
+	limiter          loginLimiter
 	ssoMystnodes     *sso.Mystnodes
 }
 
@@ -48,6 +53,20 @@ type authenticator interface {
 	ChangePassword(username, oldPassword, newPassword string) error
 }
 
+type loginLimiter interface {
+	Check(ip, username string) time.Duration
+	Failure(ip, username string)
+	Success(ip, username string)
+	Recover(token string) error
+}
+
+type userManager interface {
+	Users() ([]auth.User, error)
+	AddUser(username, password string, role auth.Role) error
+	SetUserRole(username string, role auth.Role) error
+	RemoveUser(username string) error
+}
+
 // swagger:operation POST /auth/authenticate Authentication Authenticate
 //
 //	---
@@ -71,15 +90,17 @@ type authenticator interface {
 //	    description: Authentication failed
 //	    schema:
 //	      "$ref": "#/definitions/APIError"
+//	  429:
+//	    description: Too many failed logins, retry after the time in Retry-After header
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
 func (api *authenticationAPI) Authenticate(c *gin.Context) {
 	req, err := toAuthRequest(c.Request)
 	if err != nil {
 		c.Error(apierror.ParseFailed())
 		return
 	}
-	err = api.authenticator.CheckCredentials(req.Username, req.Password)
-	if err != nil {
-		c.Error(apierror.Unauthorized())
+	if !api.checkCredentials(c, req.Username, req.Password) {
 		return
 	}
 
@@ -116,15 +137,17 @@ func (api *authenticationAPI) Authenticate(c *gin.Context) {
 //	    description: Authentication failed
 //	    schema:
 //	      "$ref": "#/definitions/APIError"
+//	  429:
+//	    description: Too many failed logins, retry after the time in Retry-After header
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
 func (api *authenticationAPI) Login(c *gin.Context) {
 	req, err := toAuthRequest(c.Request)
 	if err != nil {
 		c.Error(apierror.ParseFailed())
 		return
 	}
-	err = api.authenticator.CheckCredentials(req.Username, req.Password)
-	if err != nil {
-		c.Error(apierror.Unauthorized())
+	if !api.checkCredentials(c, req.Username, req.Password) {
 		return
 	}
 
@@ -267,6 +290,10 @@ func (api *authenticationAPI) Logout(c *gin.Context) {
 //	    description: Unauthorized
 //	    schema:
 //	      "$ref": "#/definitions/APIError"
+//	  429:
+//	    description: Too many failed logins, retry after the time in Retry-After header
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
 func (api *authenticationAPI) ChangePassword(c *gin.Context) {
 	var req *contract.ChangePasswordRequest
 	var err error
@@ -275,6 +302,9 @@ func (api *authenticationAPI) ChangePassword(c *gin.Context) {
 		c.Error(apierror.ParseFailed())
 		return
 	}
+	if !api.checkCredentials(c, req.Username, req.OldPassword) {
+		return
+	}
 	err = api.authenticator.ChangePassword(req.Username, req.OldPassword, req.NewPassword)
 	if err != nil {
 		c.Error(apierror.Unauthorized())
@@ -282,6 +312,227 @@ func (api *authenticationAPI) ChangePassword(c *gin.Context) {
 	}
 }
 
+// swagger:operation POST /auth/recover Authentication recoverLogin
+//
+//	---
+//	summary: Recover from login lockout
+//	description: Clears all login lockouts using the single use token the node writes to its data directory when locking out
+//	parameters:
+//	  - in: body
+//	    name: body
+//	    schema:
+//	      $ref: "#/definitions/LoginRecoveryRequest"
+//	responses:
+//	  200:
+//	    description: Lockouts cleared
+//	  400:
+//	    description: Failed to parse or request validation failed
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  401:
+//	    description: Bad recovery token
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+func (api *authenticationAPI) Recover(c *gin.Context) {
+	var req contract.LoginRecoveryRequest
+	if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil {
+		c.Error(apierror.ParseFailed())
+		return
+	}
+
+	err := api.limiter.Recover(req.Token)
+	if errors.Is(err, auth.ErrBadRecoveryToken) {
+		c.Error(apierror.Unauthorized())
+		return
+	} else if err != nil {
+		c.Error(apierror.Internal("Could not recover from lockout: "+err.Error(), contract.ErrCodeAuthRecover))
+		return
+	}
+}
+
+// checkCredentials validates credentials unless the client or the user is locked out
+// and records the result. It writes the error response and returns false on failure.
+func (api *authenticationAPI) checkCredentials(c *gin.Context, username, password string) bool {
+	ip := c.ClientIP()
+	if wait := api.limiter.Check(ip, username); wait > 0 {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+		c.Error(apierror.Error(http.StatusTooManyRequests, "Too many failed login attempts", contract.ErrCodeAuthLockedOut))
+		return false
+	}
+
+	if err := api.authenticator.CheckCredentials(username, password); err != nil {
+		api.limiter.Failure(ip, username)
+		c.Error(apierror.Unauthorized())
+		return false
+	}
+
+	api.limiter.Success(ip, username)
+	return true
+}
+
+// swagger:operation GET /auth/users Authentication listUsers
+//
+//	---
+//	summary: List users
+//	description: Lists local API users and their roles, requires admin role
+//	responses:
+//	  200:
+//	    description: Local API users
+//	    schema:
+//	      "$ref": "#/definitions/UsersResponse"
+//	  500:
+//	    description: Internal server error
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+func (api *authenticationAPI) ListUsers(c *gin.Context) {
+	users, err := api.users.Users()
+	if err != nil {
+		c.Error(apierror.Internal("Could not list users: "+err.Error(), contract.ErrCodeUserList))
+		return
+	}
+	utils.WriteAsJSON(contract.NewUsersResponse(users), c.Writer)
+}
+
+// swagger:operation POST /auth/users Authentication addUser
+//
+//	---
+//	summary: Add user
+//	description: Adds a local API user with a role, requires admin role
+//	parameters:
+//	  - in: body
+//	    name: body
+//	    schema:
+//	      $ref: "#/definitions/CreateUserRequest"
+//	responses:
+//	  201:
+//	    description: User added
+//	  400:
+//	    description: Failed to parse or request validation failed
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  409:
+//	    description: User already exists
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  500:
+//	    description: Internal server error
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+func (api *authenticationAPI) AddUser(c *gin.Context) {
+	var req contract.CreateUserRequest
+	if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil {
+		c.Error(apierror.ParseFailed())
+		return
+	}
+	if err := req.Validate(); err != nil {
+		c.Error(err)
+		return
+	}
+
+	err := api.users.AddUser(req.Username, req.Password, auth.Role(req.Role))
+	if errors.Is(err, auth.ErrUserExists) || errors.Is(err, auth.ErrReservedUsername) {
+		c.Error(apierror.Conflict(err.Error(), contract.ErrCodeUserAdd, "username"))
+		return
+	} else if err != nil {
+		c.Error(apierror.Internal("Could not add user: "+err.Error(), contract.ErrCodeUserAdd))
+		return
+	}
+	c.Status(http.StatusCreated)
+}
+
+// swagger:operation PUT /auth/users/{username} Authentication updateUser
+//
+//	---
+//	summary: Update user
+//	description: Changes the role of a local API user, requires admin role. The default user is always an admin.
+//	parameters:
+//	  - in: path
+//	    name: username
+//	    type: string
+//	    required: true
+//	  - in: body
+//	    name: body
+//	    schema:
+//	      $ref: "#/definitions/UpdateUserRequest"
+//	responses:
+//	  200:
+//	    description: User updated
+//	  400:
+//	    description: Failed to parse or request validation failed
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  404:
+//	    description: User not found
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  500:
+//	    description: Internal server error
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+func (api *authenticationAPI) UpdateUser(c *gin.Context) {
+	var req contract.UpdateUserRequest
+	if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil {
+		c.Error(apierror.ParseFailed())
+		return
+	}
+	if err := req.Validate(); err != nil {
+		c.Error(err)
+		return
+	}
+
+	err := api.users.SetUserRole(c.Param("username"), auth.Role(req.Role))
+	if errors.Is(err, auth.ErrReservedUsername) {
+		c.Error(apierror.BadRequest(err.Error(), contract.ErrCodeUserUpdate))
+		return
+	} else if errors.Is(err, auth.ErrUserNotFound) {
+		c.Error(apierror.NotFound("User not found"))
+		return
+	} else if err != nil {
+		c.Error(apierror.Internal("Could not update user: "+err.Error(), contract.ErrCodeUserUpdate))
+		return
+	}
+}
+
+// swagger:operation DELETE /auth/users/{username} Authentication removeUser
+//
+//	---
+//	summary: Remove user
+//	description: Removes a local API user, requires admin role. The default user cannot be removed.
+//	parameters:
+//	  - in: path
+//	    name: username
+//	    type: string
+//	    required: true
+//	responses:
+//	  202:
+//	    description: User removed
+//	  400:
+//	    description: The default user cannot be removed
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  404:
+//	    description: User not found
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  500:
+//	    description: Internal server error
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+func (api *authenticationAPI) RemoveUser(c *gin.Context) {
+	err := api.users.RemoveUser(c.Param("username"))
+	if errors.Is(err, auth.ErrReservedUsername) {
+		c.Error(apierror.BadRequest(err.Error(), contract.ErrCodeUserRemove))
+		return
+	} else if errors.Is(err, auth.ErrUserNotFound) {
+		c.Error(apierror.NotFound("User not found"))
+		return
+	} else if err != nil {
+		c.Error(apierror.Internal("Could not remove user: "+err.Error(), contract.ErrCodeUserRemove))
+		return
+	}
+	c.Status(http.StatusAccepted)
+}
+
 func toAuthRequest(req *http.Request) (contract.AuthRequest, error) {
 	var request contract.AuthRequest
 	err := json.NewDecoder(req.Body).Decode(&request)
@@ -297,9 +548,11 @@ func toChangePasswordRequest(req *http.Request) (*contract.ChangePasswordRequest
 }
 
 // AddRoutesForAuthentication registers /auth endpoints in Tequilapi
-func AddRoutesForAuthentication(auth authenticator, jwtAuth jwtAuthenticator, ssoMystnodes *sso.Mystnodes) func(*gin.Engine) error {
+func AddRoutesForAuthentication(auth authenticator, users userManager, limiter loginLimiter, jwtAuth jwtAuthenticator, ssoMystnodes *sso.Mystnodes) func(*gin.Engine) error {
 	api := &authenticationAPI{
 		authenticator:    auth,
+		users:            users,
+		limiter:          limiter,
 		jwtAuthenticator: jwtAuth,
 		ssoMystnodes:     ssoMystnodes,
 	}
@@ -312,6 +565,11 @@ func AddRoutesForAuthentication(auth authenticator, jwtAuth jwtAuthenticator, ss
 			g.GET("/login-mystnodes", api.LoginMystnodesInit)
 			g.POST("/login-mystnodes", api.LoginMystnodesWithGrant)
 			g.DELETE("/logout", api.Logout)
+			g.POST("/recover", api.Recover)
+			g.GET("/users", api.ListUsers)
+			g.POST("/users", api.AddUser)
+			g.PUT("/users/:username", api.UpdateUser)
+			g.DELETE("/users/:username", api.RemoveUser)
 		}
 		return nil
 	}
@@ -0,0 +1,135 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gin-gonic/gin"
+	"github.com/mysteriumnetwork/go-rest/apierror"
+
+	"github.com/mysteriumnetwork/node/config"
+	"github.com/mysteriumnetwork/node/core/beneficiary"
+	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
+)
+
+type beneficiaryChanger interface {
+	RequestChange(id identity.Identity, beneficiary common.Address, hermeses []common.Address) error
+}
+
+type beneficiaryEndpoint struct {
+	manager     beneficiaryChanger
+	idm         identity.Manager
+	addressBook addressResolver
+}
+
+// ChangeBeneficiary requests beneficiary change
+//
+// swagger:operation POST /identities/{id}/beneficiary-change Identity changeBeneficiary
+//
+//	---
+//	summary: Change beneficiary
+//	description: Submits beneficiary change transaction and waits until the change is reflected on chain. This is async method, its progress is returned by beneficiary-status endpoint.
+//	parameters:
+//	- name: id
+//	  in: path
+//	  description: Identity address
+//	  type: string
+//	  required: true
+//	- in: body
+//	  name: body
+//	  description: Beneficiary change request
+//	  schema:
+//	    $ref: "#/definitions/ChangeBeneficiaryRequest"
+//	responses:
+//	  202:
+//	    description: Beneficiary change request accepted
+//	  400:
+//	    description: Failed to parse or request validation failed
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  404:
+//	    description: Identity not found
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  409:
+//	    description: Beneficiary change is already in progress
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  428:
+//	    description: Payout address has to be confirmed, repeat the request with the confirmation token
+//	    schema:
+//	      "$ref": "#/definitions/AddressConfirmationRequiredResponse"
+//	  500:
+//	    description: Internal server error
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+func (be *beneficiaryEndpoint) ChangeBeneficiary(c *gin.Context) {
+	id, err := be.idm.GetIdentity(c.Param("id"))
+	if err != nil {
+		c.Error(apierror.NotFound("Identity not found"))
+		return
+	}
+
+	var req contract.ChangeBeneficiaryRequest
+	if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil {
+		c.Error(apierror.ParseFailed())
+		return
+	}
+	address, ok := resolvePayoutAddress(c, be.addressBook, config.GetInt64(config.FlagChainID), req.Beneficiary, req.PayoutTarget, false, contract.ErrCodeTransactorBeneficiaryChange)
+	if !ok {
+		return
+	}
+
+	var hermeses []common.Address
+	for _, h := range req.HermesIDs {
+		if !common.IsHexAddress(h) {
+			c.Error(apierror.BadRequest("Invalid hermes address", contract.ErrCodeTransactorBeneficiaryChange))
+			return
+		}
+		hermeses = append(hermeses, common.HexToAddress(h))
+	}
+
+	err = be.manager.RequestChange(id, address, hermeses)
+	switch {
+	case errors.Is(err, beneficiary.ErrChangeInProgress):
+		c.Error(apierror.Conflict(err.Error(), contract.ErrCodeTransactorBeneficiaryChange, "beneficiary"))
+		return
+	case errors.Is(err, beneficiary.ErrInvalidAddress):
+		c.Error(apierror.BadRequest("Invalid beneficiary address", contract.ErrCodeTransactorBeneficiaryChange))
+		return
+	case err != nil:
+		c.Error(apierror.Internal("Failed to change beneficiary: "+err.Error(), contract.ErrCodeTransactorBeneficiaryChange))
+		return
+	}
+	c.Status(http.StatusAccepted)
+}
+
+// AddRoutesForBeneficiary registers beneficiary change endpoint in Tequilapi
+func AddRoutesForBeneficiary(manager beneficiaryChanger, idm identity.Manager, terms paymentsGate, addressBook addressResolver) func(*gin.Engine) error {
+	be := &beneficiaryEndpoint{manager: manager, idm: idm, addressBook: addressBook}
+	guard := newTermsGuard(terms)
+	return func(e *gin.Engine) error {
+		e.POST("/identities/:id/beneficiary-change", guard, be.ChangeBeneficiary)
+		return nil
+	}
+}
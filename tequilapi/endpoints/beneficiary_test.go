@@ -0,0 +1,98 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/core/beneficiary"
+	"github.com/mysteriumnetwork/node/identity"
+)
+
+type mockBeneficiaryChanger struct {
+	id          identity.Identity
+	beneficiary common.Address
+	hermeses    []common.Address
+	err         error
+}
+
+func (m *mockBeneficiaryChanger) RequestChange(id identity.Identity, beneficiary common.Address, hermeses []common.Address) error {
+	m.id, m.beneficiary, m.hermeses = id, beneficiary, hermeses
+	return m.err
+}
+
+func Test_ChangeBeneficiary(t *testing.T) {
+	tests := []struct {
+		name       string
+		id         string
+		body       string
+		err        error
+		wantStatus int
+	}{
+		{
+			name:       "accepted",
+			id:         "0x000000000000000000000000000000000000000a",
+			body:       `{"beneficiary": "0x000000000000000000000000000000000000000b", "hermes_ids": ["0x000000000000000000000000000000000000000c"]}`,
+			wantStatus: http.StatusAccepted,
+		},
+		{
+			name:       "unknown identity",
+			id:         "0x0000000000000000000000000000000000000001",
+			body:       `{"beneficiary": "0x000000000000000000000000000000000000000b"}`,
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "invalid beneficiary",
+			id:         "0x000000000000000000000000000000000000000a",
+			body:       `{"beneficiary": "not an address"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "change in progress",
+			id:         "0x000000000000000000000000000000000000000a",
+			body:       `{"beneficiary": "0x000000000000000000000000000000000000000b"}`,
+			err:        beneficiary.ErrChangeInProgress,
+			wantStatus: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changer := &mockBeneficiaryChanger{err: tt.err}
+			router := summonTestGin()
+			err := AddRoutesForBeneficiary(changer, identity.NewIdentityManagerFake(existingIdentities, newIdentity), nil, nil)(router)
+			assert.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPost, "/identities/"+tt.id+"/beneficiary-change", strings.NewReader(tt.body))
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, tt.wantStatus, resp.Code)
+			if tt.wantStatus == http.StatusAccepted {
+				assert.Equal(t, common.HexToAddress("0x000000000000000000000000000000000000000b"), changer.beneficiary)
+				assert.Equal(t, []common.Address{common.HexToAddress("0x000000000000000000000000000000000000000c")}, changer.hermeses)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mysteriumnetwork/go-rest/apierror"
+
+	"github.com/mysteriumnetwork/node/core/chainswitch"
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
+	"github.com/mysteriumnetwork/node/tequilapi/utils"
+)
+
+type chainSwitcher interface {
+	Switch(chainID int64, drain bool) (chainswitch.Result, error)
+}
+
+type chainsEndpoint struct {
+	switcher chainSwitcher
+}
+
+// SwitchActiveChain switches the active chain of the running node
+//
+// swagger:operation POST /chains/active Chains switchActiveChain
+//
+//	---
+//	summary: Switches the active chain
+//	description: Validates the target chain configuration, refuses the switch or closes active sessions if there are any, re-checks identity registrations on the new chain and announces proposals again. The chain is persisted to the config file.
+//	parameters:
+//	  - in: body
+//	    name: body
+//	    schema:
+//	      $ref: "#/definitions/ChainSwitchRequestDTO"
+//	responses:
+//	  200:
+//	    description: Active chain switched
+//	    schema:
+//	      "$ref": "#/definitions/ChainSwitchResponseDTO"
+//	  400:
+//	    description: Failed to parse or request validation failed
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  409:
+//	    description: Sessions are active and draining was not requested
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  422:
+//	    description: Chain is unknown, misconfigured or set by command line flag
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  500:
+//	    description: Internal server error
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+func (ce *chainsEndpoint) SwitchActiveChain(c *gin.Context) {
+	var req contract.ChainSwitchRequest
+	if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil {
+		c.Error(apierror.ParseFailed())
+		return
+	}
+	if err := req.Validate(); err != nil {
+		c.Error(err)
+		return
+	}
+
+	result, err := ce.switcher.Switch(req.ChainID, req.DrainSessions)
+	switch {
+	case errors.Is(err, chainswitch.ErrSessionsActive):
+		c.Error(apierror.Conflict(err.Error(), contract.ErrCodeChainSessionsActive, "drain_sessions"))
+		return
+	case errors.Is(err, chainswitch.ErrUnknownChain), errors.Is(err, chainswitch.ErrInvalidChain), errors.Is(err, chainswitch.ErrChainFlagSet):
+		c.Error(apierror.Unprocessable(err.Error(), contract.ErrCodeChainUnknown))
+		return
+	case err != nil:
+		c.Error(apierror.Internal("Could not switch active chain: "+err.Error(), contract.ErrCodeChainSwitch))
+		return
+	}
+
+	utils.WriteAsJSON(contract.NewChainSwitchResponse(result), c.Writer)
+}
+
+// AddRoutesForChains registers /chains endpoints in Tequilapi
+func AddRoutesForChains(switcher chainSwitcher) func(*gin.Engine) error {
+	ce := &chainsEndpoint{switcher: switcher}
+	return func(e *gin.Engine) error {
+		g := e.Group("/chains")
+		{
+			g.POST("/active", ce.SwitchActiveChain)
+		}
+		return nil
+	}
+}
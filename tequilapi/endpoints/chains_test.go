@@ -0,0 +1,73 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/core/chainswitch"
+)
+
+type mockChainSwitcher struct {
+	err     error
+	chainID int64
+	drain   bool
+}
+
+func (m *mockChainSwitcher) Switch(chainID int64, drain bool) (chainswitch.Result, error) {
+	m.chainID, m.drain = chainID, drain
+	return chainswitch.Result{PreviousChainID: 80001, ChainID: chainID, ClosedSessions: 1}, m.err
+}
+
+func TestChainsEndpoint_SwitchActiveChain(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		err      error
+		wantCode int
+	}{
+		{"switches chain", `{"chain_id": 137, "drain_sessions": true}`, nil, http.StatusOK},
+		{"requires chain", `{}`, nil, http.StatusBadRequest},
+		{"refuses active sessions", `{"chain_id": 137}`, fmt.Errorf("%w: 1 provider sessions, 0 connections", chainswitch.ErrSessionsActive), http.StatusConflict},
+		{"rejects unknown chain", `{"chain_id": 5}`, chainswitch.ErrUnknownChain, http.StatusUnprocessableEntity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			switcher := &mockChainSwitcher{err: tt.err}
+			router := summonTestGin()
+			assert.NoError(t, AddRoutesForChains(switcher)(router))
+
+			resp := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/chains/active", strings.NewReader(tt.body))
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, tt.wantCode, resp.Code)
+			if tt.wantCode == http.StatusOK {
+				assert.True(t, switcher.drain)
+				assert.JSONEq(t, `{"previous_chain_id": 80001, "chain_id": 137, "closed_sessions": 1, "closed_connections": 0}`, resp.Body.String())
+			}
+		})
+	}
+}
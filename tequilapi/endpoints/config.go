@@ -26,6 +26,8 @@ import (
 	"github.com/mysteriumnetwork/node/tequilapi/contract"
 
 	"github.com/mysteriumnetwork/node/config"
+	"github.com/mysteriumnetwork/node/identity/registry"
+	"github.com/mysteriumnetwork/node/logconfig"
 	"github.com/mysteriumnetwork/node/tequilapi/utils"
 	"github.com/rs/zerolog/log"
 )
@@ -37,6 +39,7 @@ type configProvider interface {
 	SetUser(key string, value interface{})
 	RemoveUser(key string)
 	SaveUserConfig() error
+	IsCLI(key string) bool
 }
 
 // swagger:model configPayload
@@ -174,6 +177,159 @@ func (api *configAPI) SetUserConfig(c *gin.Context) {
 	api.GetUserConfig(c)
 }
 
+// GetLoggingConfig returns log level and log file rotation settings
+// swagger:operation GET /config/logging Configuration getLoggingConfig
+//
+//	---
+//	summary: Returns logging configuration
+//	description: Returns log level and log file rotation settings in effect
+//	responses:
+//	  200:
+//	    description: Logging configuration
+//	    schema:
+//	      "$ref": "#/definitions/LoggingConfigDTO"
+func (api *configAPI) GetLoggingConfig(c *gin.Context) {
+	utils.WriteAsJSON(contract.NewLoggingConfigDTO(logconfig.CurrentLogOptions), c.Writer)
+}
+
+// SetLoggingConfig changes logging configuration of the running node
+// swagger:operation PUT /config/logging Configuration setLoggingConfig
+//
+//	---
+//	summary: Changes logging configuration
+//	description: Applies log level and log file rotation settings without restarting the node. Changes are persisted to the config file.
+//	parameters:
+//	  - in: body
+//	    name: body
+//	    schema:
+//	      $ref: "#/definitions/LoggingConfigRequest"
+//	responses:
+//	  200:
+//	    description: Logging configuration
+//	    schema:
+//	      "$ref": "#/definitions/LoggingConfigDTO"
+//	  400:
+//	    description: Failed to parse or request validation failed
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  500:
+//	    description: Internal server error
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+func (api *configAPI) SetLoggingConfig(c *gin.Context) {
+	var req contract.LoggingConfigRequest
+	if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil {
+		c.Error(apierror.ParseFailed())
+		return
+	}
+	if err := req.Validate(); err != nil {
+		c.Error(err)
+		return
+	}
+
+	opts := req.Apply(logconfig.CurrentLogOptions)
+	if err := logconfig.SetRotation(opts.Rotation); err != nil {
+		c.Error(apierror.Internal("Failed to apply log rotation: "+err.Error(), contract.ErrCodeLoggingConfig))
+		return
+	}
+	logconfig.SetLogLevel(opts.LogLevel)
+
+	res := contract.NewLoggingConfigDTO(opts)
+	api.config.SetUser(config.FlagLogLevel.Name, res.Level)
+	api.config.SetUser(config.FlagLogMaxSize.Name, res.MaxSizeMB)
+	api.config.SetUser(config.FlagLogMaxAge.Name, res.MaxAge)
+	api.config.SetUser(config.FlagLogMaxBackups.Name, res.MaxBackups)
+	api.config.SetUser(config.FlagLogCompress.Name, res.Compress)
+	if err := api.config.SaveUserConfig(); err != nil {
+		c.Error(apierror.Internal("Failed to save config", contract.ErrCodeConfigSave))
+		return
+	}
+
+	utils.WriteAsJSON(res, c.Writer)
+}
+
+// GetRegistryMigrations returns the registry migration table
+// swagger:operation GET /config/registry-migrations Configuration getRegistryMigrations
+//
+//	---
+//	summary: Returns registry migration table
+//	description: Returns registry migrations applied by the node, identities registered in the from registry are registered again once the chain uses the to registry
+//	responses:
+//	  200:
+//	    description: Registry migration table
+//	    schema:
+//	      "$ref": "#/definitions/RegistryMigrationsDTO"
+//	  500:
+//	    description: Internal server error
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+func (api *configAPI) GetRegistryMigrations(c *gin.Context) {
+	migrations, err := registry.ParseMigrations(config.GetStringSlice(config.FlagRegistryMigrations))
+	if err != nil {
+		c.Error(apierror.Internal("Invalid registry migration table: "+err.Error(), contract.ErrCodeRegistryMigrations))
+		return
+	}
+	utils.WriteAsJSON(contract.NewRegistryMigrationsDTO(migrations), c.Writer)
+}
+
+// SetRegistryMigrations replaces the registry migration table
+// swagger:operation PUT /config/registry-migrations Configuration setRegistryMigrations
+//
+//	---
+//	summary: Replaces registry migration table
+//	description: Replaces registry migrations without restarting the node, migrations into the registry currently used by the chain are applied right away. Changes are persisted to the config file.
+//	parameters:
+//	  - in: body
+//	    name: body
+//	    schema:
+//	      $ref: "#/definitions/RegistryMigrationsDTO"
+//	responses:
+//	  200:
+//	    description: Registry migration table
+//	    schema:
+//	      "$ref": "#/definitions/RegistryMigrationsDTO"
+//	  400:
+//	    description: Failed to parse or request validation failed
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  422:
+//	    description: Registry migrations are set by command line flag
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  500:
+//	    description: Internal server error
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+func (api *configAPI) SetRegistryMigrations(c *gin.Context) {
+	var req contract.RegistryMigrationsDTO
+	if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil {
+		c.Error(apierror.ParseFailed())
+		return
+	}
+	if err := req.Validate(); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if api.config.IsCLI(config.FlagRegistryMigrations.Name) {
+		c.Error(apierror.Unprocessable("Registry migrations are set by command line flag", contract.ErrCodeRegistryMigrations))
+		return
+	}
+
+	migrations := req.ToMigrations()
+	values := make([]string, len(migrations))
+	for i, m := range migrations {
+		values[i] = m.String()
+	}
+	api.config.SetUser(config.FlagRegistryMigrations.Name, values)
+	if err := api.config.SaveUserConfig(); err != nil {
+		c.Error(apierror.Internal("Failed to save config", contract.ErrCodeConfigSave))
+		return
+	}
+
+	utils.WriteAsJSON(contract.NewRegistryMigrationsDTO(migrations), c.Writer)
+}
+
 func isNil(val interface{}) bool {
 	if val == nil {
 		return true
@@ -197,6 +353,10 @@ func AddRoutesForConfig(
 		g.GET("/user", api.GetUserConfig)
 		g.POST("/user", api.SetUserConfig)
 		g.GET("/ui/features", api.GetUiFeatures)
+		g.GET("/logging", api.GetLoggingConfig)
+		g.PUT("/logging", api.SetLoggingConfig)
+		g.GET("/registry-migrations", api.GetRegistryMigrations)
+		g.PUT("/registry-migrations", api.SetRegistryMigrations)
 	}
 	return nil
 }
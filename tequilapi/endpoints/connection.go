@@ -19,9 +19,11 @@ package endpoints
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/gin-gonic/gin"
@@ -30,13 +32,16 @@ import (
 	"github.com/mysteriumnetwork/go-rest/apierror"
 	"github.com/mysteriumnetwork/node/config"
 	"github.com/mysteriumnetwork/node/core/connection"
+	"github.com/mysteriumnetwork/node/core/connection/external"
 	"github.com/mysteriumnetwork/node/core/discovery/proposal"
 	"github.com/mysteriumnetwork/node/core/quality"
 	"github.com/mysteriumnetwork/node/eventbus"
 	"github.com/mysteriumnetwork/node/identity"
 	"github.com/mysteriumnetwork/node/identity/registry"
 	"github.com/mysteriumnetwork/node/market"
+	"github.com/mysteriumnetwork/node/p2p"
 	"github.com/mysteriumnetwork/node/tequilapi/contract"
+	"github.com/mysteriumnetwork/node/tequilapi/middlewares"
 	"github.com/mysteriumnetwork/node/tequilapi/utils"
 )
 
@@ -51,6 +56,13 @@ type ProposalGetter interface {
 	GetProposal(id market.ProposalID) (*market.ServiceProposal, error)
 }
 
+type providerBlocklist interface {
+	Blocked(providerID string) bool
+	List() []connection.BlockedProvider
+	Remove(providerID string) bool
+	Clear()
+}
+
 type identityRegistry interface {
 	GetRegistrationStatus(int64, identity.Identity) (registry.RegistrationStatus, error)
 }
@@ -64,10 +76,11 @@ type ConnectionEndpoint struct {
 	proposalRepository proposalRepository
 	identityRegistry   identityRegistry
 	addressProvider    addressProvider
+	blocklist          providerBlocklist
 }
 
 // NewConnectionEndpoint creates and returns connection endpoint
-func NewConnectionEndpoint(manager connection.MultiManager, stateProvider stateProvider, proposalRepository proposalRepository, identityRegistry identityRegistry, publisher eventbus.Publisher, addressProvider addressProvider) *ConnectionEndpoint {
+func NewConnectionEndpoint(manager connection.MultiManager, stateProvider stateProvider, proposalRepository proposalRepository, identityRegistry identityRegistry, publisher eventbus.Publisher, addressProvider addressProvider, blocklist providerBlocklist) *ConnectionEndpoint {
 	return &ConnectionEndpoint{
 		manager:            manager,
 		publisher:          publisher,
@@ -75,6 +88,7 @@ func NewConnectionEndpoint(manager connection.MultiManager, stateProvider stateP
 		proposalRepository: proposalRepository,
 		identityRegistry:   identityRegistry,
 		addressProvider:    addressProvider,
+		blocklist:          blocklist,
 	}
 }
 
@@ -143,51 +157,43 @@ func (ce *ConnectionEndpoint) Status(c *gin.Context) {
 //	    schema:
 //	      "$ref": "#/definitions/APIError"
 func (ce *ConnectionEndpoint) Create(c *gin.Context) {
+	n, ok := ce.connect(c)
+	if !ok {
+		return
+	}
+
+	c.Status(http.StatusCreated)
+
+	statusResponse := contract.NewConnectionInfoDTO(ce.manager.Status(n))
+	utils.WriteAsJSON(statusResponse, c.Writer)
+}
+
+// connect starts a connection described by the request body and returns its ID.
+// Errors are written to the context, in which case false is returned.
+func (ce *ConnectionEndpoint) connect(c *gin.Context) (int, bool) {
 	hermes, err := ce.addressProvider.GetActiveHermes(config.GetInt64(config.FlagChainID))
 	if err != nil {
 		c.Error(apierror.Internal("Failed to get active hermes", contract.ErrCodeActiveHermes))
-		return
+		return 0, false
 	}
 
 	cr, err := toConnectionRequest(c.Request, hermes.Hex())
 	if err != nil {
 		ce.publisher.Publish(quality.AppTopicConnectionEvents, (&contract.ConnectionCreateRequest{}).Event(quality.StagePraseRequest, err.Error()))
 		c.Error(apierror.ParseFailed())
-		return
+		return 0, false
 	}
 
 	if err := cr.Validate(); err != nil {
 		ce.publisher.Publish(quality.AppTopicConnectionEvents, cr.Event(quality.StageValidateRequest, err.Detail()))
 		c.Error(err)
-		return
+		return 0, false
 	}
 
 	consumerID := identity.FromAddress(cr.ConsumerID)
-	status, err := ce.identityRegistry.GetRegistrationStatus(config.GetInt64(config.FlagChainID), consumerID)
-	if err != nil {
-		ce.publisher.Publish(quality.AppTopicConnectionEvents, cr.Event(quality.StageRegistrationGetStatus, err.Error()))
-		log.Error().Err(err).Stack().Msg("Could not check registration status")
-		c.Error(apierror.Internal("Failed to check ID registration status: "+err.Error(), contract.ErrCodeIDRegistrationCheck))
-		return
-	}
-
-	switch status {
-	case registry.Unregistered, registry.RegistrationError, registry.Unknown:
-		ce.publisher.Publish(quality.AppTopicConnectionEvents, cr.Event(quality.StageRegistrationUnregistered, ""))
-		log.Error().Msgf("Identity %q is not registered, aborting...", cr.ConsumerID)
-		c.Error(apierror.Unprocessable(fmt.Sprintf("Identity %q is not registered. Please register the identity first", cr.ConsumerID), contract.ErrCodeIDNotRegistered))
-		return
-	case registry.InProgress:
-		ce.publisher.Publish(quality.AppTopicConnectionEvents, cr.Event(quality.StageRegistrationInProgress, ""))
-		log.Info().Msgf("identity %q registration is in progress, continuing...", cr.ConsumerID)
-	case registry.Registered:
-		ce.publisher.Publish(quality.AppTopicConnectionEvents, cr.Event(quality.StageRegistrationRegistered, ""))
-		log.Info().Msgf("identity %q is registered, continuing...", cr.ConsumerID)
-	default:
-		ce.publisher.Publish(quality.AppTopicConnectionEvents, cr.Event(quality.StageRegistrationUnknown, ""))
-		log.Error().Msgf("identity %q has unknown status, aborting...", cr.ConsumerID)
-		c.Error(apierror.Unprocessable(fmt.Sprintf("Identity %q has unknown status. Aborting", cr.ConsumerID), contract.ErrCodeIDStatusUnknown))
-		return
+	// Endpoints imported from outside of the network are not paid for, so registration is not required.
+	if !external.IsExternal(cr.ProviderID) && !ce.checkRegistration(c, cr, consumerID) {
+		return 0, false
 	}
 
 	if len(cr.ProviderID) > 0 {
@@ -202,31 +208,64 @@ func (ce *ConnectionEndpoint) Create(c *gin.Context) {
 		IncludeMonitoringFailed: cr.Filter.IncludeMonitoringFailed,
 		AccessPolicy:            "all",
 	}
-	proposalLookup := connection.FilteredProposals(f, cr.Filter.SortBy, ce.proposalRepository)
+	proposalLookup := connection.FilteredProposals(f, cr.Filter.SortBy, ce.proposalRepository, ce.blocklist)
 
 	err = ce.manager.Connect(consumerID, common.HexToAddress(cr.HermesID), proposalLookup, getConnectOptions(cr))
 	if err != nil {
-		switch err {
-		case connection.ErrAlreadyExists:
+		var busy *p2p.BusyError
+		switch {
+		case errors.Is(err, connection.ErrAlreadyExists):
 			ce.publisher.Publish(quality.AppTopicConnectionEvents, cr.Event(quality.StageConnectionAlreadyExists, err.Error()))
 			c.Error(apierror.Unprocessable("Connection already exists", contract.ErrCodeConnectionAlreadyExists))
-		case connection.ErrConnectionCancelled:
+		case errors.Is(err, connection.ErrConnectionCancelled):
 			ce.publisher.Publish(quality.AppTopicConnectionEvents, cr.Event(quality.StageConnectionCanceled, err.Error()))
 			c.Error(apierror.Unprocessable("Connection cancelled", contract.ErrCodeConnectionCancelled))
+		case errors.As(err, &busy):
+			ce.publisher.Publish(quality.AppTopicConnectionEvents, cr.Event(quality.StageConnectionProviderBusy, err.Error()))
+			c.Header("Retry-After", strconv.Itoa(int(busy.RetryAfter.Seconds())))
+			c.Error(apierror.Error(http.StatusServiceUnavailable, "Provider is busy: "+busy.Reason, contract.ErrCodeConnectionProviderBusy))
 		default:
 			ce.publisher.Publish(quality.AppTopicConnectionEvents, cr.Event(quality.StageConnectionUnknownError, err.Error()))
 			log.Error().Err(err).Msg("Failed to connect")
 			c.Error(apierror.Internal("Failed to connect: "+err.Error(), contract.ErrCodeConnect))
 		}
-		return
+		return 0, false
 	}
 
 	ce.publisher.Publish(quality.AppTopicConnectionEvents, cr.Event(quality.StageConnectionOK, ""))
-	c.Status(http.StatusCreated)
+	c.Set(middlewares.TraceIDKey, string(ce.manager.Status(cr.ConnectOptions.ProxyPort).SessionID))
+	return cr.ConnectOptions.ProxyPort, true
+}
 
-	statusResp := ce.manager.Status(cr.ConnectOptions.ProxyPort)
-	statusResponse := contract.NewConnectionInfoDTO(statusResp)
-	utils.WriteAsJSON(statusResponse, c.Writer)
+// checkRegistration reports whether consumer identity is registered enough to connect to paid providers.
+func (ce *ConnectionEndpoint) checkRegistration(c *gin.Context, cr *contract.ConnectionCreateRequest, consumerID identity.Identity) bool {
+	status, err := ce.identityRegistry.GetRegistrationStatus(config.GetInt64(config.FlagChainID), consumerID)
+	if err != nil {
+		ce.publisher.Publish(quality.AppTopicConnectionEvents, cr.Event(quality.StageRegistrationGetStatus, err.Error()))
+		log.Error().Err(err).Stack().Msg("Could not check registration status")
+		c.Error(apierror.Internal("Failed to check ID registration status: "+err.Error(), contract.ErrCodeIDRegistrationCheck))
+		return false
+	}
+
+	switch status {
+	case registry.Unregistered, registry.RegistrationError, registry.Unknown:
+		ce.publisher.Publish(quality.AppTopicConnectionEvents, cr.Event(quality.StageRegistrationUnregistered, ""))
+		log.Error().Msgf("Identity %q is not registered, aborting...", cr.ConsumerID)
+		c.Error(apierror.Unprocessable(fmt.Sprintf("Identity %q is not registered. Please register the identity first", cr.ConsumerID), contract.ErrCodeIDNotRegistered))
+		return false
+	case registry.InProgress:
+		ce.publisher.Publish(quality.AppTopicConnectionEvents, cr.Event(quality.StageRegistrationInProgress, ""))
+		log.Info().Msgf("identity %q registration is in progress, continuing...", cr.ConsumerID)
+	case registry.Registered:
+		ce.publisher.Publish(quality.AppTopicConnectionEvents, cr.Event(quality.StageRegistrationRegistered, ""))
+		log.Info().Msgf("identity %q is registered, continuing...", cr.ConsumerID)
+	default:
+		ce.publisher.Publish(quality.AppTopicConnectionEvents, cr.Event(quality.StageRegistrationUnknown, ""))
+		log.Error().Msgf("identity %q has unknown status, aborting...", cr.ConsumerID)
+		c.Error(apierror.Unprocessable(fmt.Sprintf("Identity %q has unknown status. Aborting", cr.ConsumerID), contract.ErrCodeIDStatusUnknown))
+		return false
+	}
+	return true
 }
 
 // Kill stops connection
@@ -264,17 +303,153 @@ func (ce *ConnectionEndpoint) Kill(c *gin.Context) {
 
 	err := ce.manager.Disconnect(n)
 	if err != nil {
-		switch err {
-		case connection.ErrNoConnection:
-			c.Error(apierror.Unprocessable("No connection exists", contract.ErrCodeNoConnectionExists))
-		default:
-			c.Error(apierror.Internal("Could not disconnect: "+err.Error(), contract.ErrCodeDisconnect))
-		}
+		ce.handleDisconnectError(c, err)
+		return
+	}
+	c.Status(http.StatusAccepted)
+}
+
+func (ce *ConnectionEndpoint) handleDisconnectError(c *gin.Context, err error) {
+	switch err {
+	case connection.ErrNoConnection:
+		c.Error(apierror.Unprocessable("No connection exists", contract.ErrCodeNoConnectionExists))
+	default:
+		c.Error(apierror.Internal("Could not disconnect: "+err.Error(), contract.ErrCodeDisconnect))
+	}
+}
+
+// Pause suspends data flow and invoicing of the connection
+// swagger:operation PUT /connection/pause Connection connectionPause
+//
+//	---
+//	summary: Pauses connection
+//	description: Suspends data flow and invoicing of current connection, keeping the session alive for a quick resume
+//	parameters:
+//	  - in: query
+//	    name: id
+//	    description: Connection number
+//	    type: integer
+//	responses:
+//	  202:
+//	    description: Connection paused
+//	  400:
+//	    description: Failed to parse or request validation failed
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  422:
+//	    description: Unable to process the request at this point (e.g. no established connection exists)
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  500:
+//	    description: Internal server error
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+func (ce *ConnectionEndpoint) Pause(c *gin.Context) {
+	n, err := connectionNumber(c)
+	if err != nil {
+		c.Error(apierror.ParseFailed())
+		return
+	}
+
+	if err := ce.manager.Pause(n); err != nil {
+		ce.handlePauseError(c, "Could not pause connection: ", contract.ErrCodeConnectionPause, err)
+		return
+	}
+	c.Status(http.StatusAccepted)
+}
+
+// Resume restores data flow and invoicing of the paused connection
+// swagger:operation PUT /connection/resume Connection connectionResume
+//
+//	---
+//	summary: Resumes connection
+//	description: Restores data flow and invoicing of the paused connection
+//	parameters:
+//	  - in: query
+//	    name: id
+//	    description: Connection number
+//	    type: integer
+//	responses:
+//	  202:
+//	    description: Connection resumed
+//	  400:
+//	    description: Failed to parse or request validation failed
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  422:
+//	    description: Unable to process the request at this point (e.g. no paused connection exists)
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  500:
+//	    description: Internal server error
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+func (ce *ConnectionEndpoint) Resume(c *gin.Context) {
+	n, err := connectionNumber(c)
+	if err != nil {
+		c.Error(apierror.ParseFailed())
+		return
+	}
+
+	if err := ce.manager.Resume(n); err != nil {
+		ce.handlePauseError(c, "Could not resume connection: ", contract.ErrCodeConnectionResume, err)
 		return
 	}
 	c.Status(http.StatusAccepted)
 }
 
+func (ce *ConnectionEndpoint) handlePauseError(c *gin.Context, msg, code string, err error) {
+	switch {
+	case errors.Is(err, connection.ErrNoConnection):
+		c.Error(apierror.Unprocessable("No connection exists", contract.ErrCodeNoConnectionExists))
+	case errors.Is(err, connection.ErrConnectionNotActive):
+		c.Error(apierror.Unprocessable("Connection is not established", contract.ErrCodeConnectionNotActive))
+	case errors.Is(err, connection.ErrUnsupportedByExternal):
+		c.Error(apierror.Unprocessable("External endpoints can not be paused", contract.ErrCodeConnectionExternal))
+	default:
+		c.Error(apierror.Internal(msg+err.Error(), code))
+	}
+}
+
+// LastTrace returns the timings of the last connection attempt
+// swagger:operation GET /connection/last-trace Connection connectionLastTrace
+//
+//	---
+//	summary: Returns the last connection attempt trace
+//	description: Returns per stage timings of the last connection attempt, for debugging slow connects
+//	parameters:
+//	  - in: query
+//	    name: id
+//	    description: Connection number
+//	    type: integer
+//	responses:
+//	  200:
+//	    description: Connection attempt trace
+//	    schema:
+//	      "$ref": "#/definitions/ConnectionTraceDTO"
+//	  400:
+//	    description: Failed to parse or request validation failed
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  404:
+//	    description: No connection attempt was made
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+func (ce *ConnectionEndpoint) LastTrace(c *gin.Context) {
+	n, err := connectionNumber(c)
+	if err != nil {
+		c.Error(apierror.ParseFailed())
+		return
+	}
+
+	trace, ok := ce.manager.LastTrace(n)
+	if !ok {
+		c.Error(apierror.Error(http.StatusNotFound, "No connection attempt was made", contract.ErrCodeConnectionTraceNotFound))
+		return
+	}
+	utils.WriteAsJSON(contract.NewConnectionTraceDTO(trace), c.Writer)
+}
+
 // GetStatistics returns statistics about current connection
 // swagger:operation GET /connection/statistics Connection connectionStatistics
 //
@@ -330,6 +505,62 @@ func (ce *ConnectionEndpoint) GetTraffic(c *gin.Context) {
 	utils.WriteAsJSON(response, c.Writer)
 }
 
+// Blocklist returns providers which recently failed to connect
+// swagger:operation GET /connection/blocklist Connection connectionBlocklist
+//
+//	---
+//	summary: Returns provider blocklist
+//	description: Returns providers whose last connection attempts failed, blocked providers are excluded from selection for a while
+//	responses:
+//	  200:
+//	    description: Provider blocklist
+//	    schema:
+//	      "$ref": "#/definitions/ProviderBlocklistResponse"
+func (ce *ConnectionEndpoint) Blocklist(c *gin.Context) {
+	utils.WriteAsJSON(contract.NewProviderBlocklistResponse(ce.blocklist.List(), time.Now()), c.Writer)
+}
+
+// ClearBlocklist forgets connection failures of all providers
+// swagger:operation DELETE /connection/blocklist Connection connectionBlocklistClear
+//
+//	---
+//	summary: Clears provider blocklist
+//	description: Forgets connection failures of all providers
+//	responses:
+//	  202:
+//	    description: Provider blocklist cleared
+func (ce *ConnectionEndpoint) ClearBlocklist(c *gin.Context) {
+	ce.blocklist.Clear()
+	c.Status(http.StatusAccepted)
+}
+
+// RemoveFromBlocklist forgets connection failures of a provider
+// swagger:operation DELETE /connection/blocklist/{provider_id} Connection connectionBlocklistRemove
+//
+//	---
+//	summary: Removes provider from blocklist
+//	description: Forgets connection failures of the given provider
+//	parameters:
+//	  - name: provider_id
+//	    in: path
+//	    description: Provider identity
+//	    type: string
+//	    required: true
+//	responses:
+//	  202:
+//	    description: Provider removed from blocklist
+//	  404:
+//	    description: Provider is not in blocklist
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+func (ce *ConnectionEndpoint) RemoveFromBlocklist(c *gin.Context) {
+	if !ce.blocklist.Remove(c.Param("provider_id")) {
+		c.Error(apierror.NotFound("Provider is not in blocklist"))
+		return
+	}
+	c.Status(http.StatusAccepted)
+}
+
 type proposalRepository interface {
 	Proposal(id market.ProposalID) (*proposal.PricedServiceProposal, error)
 	Proposals(filter *proposal.Filter) ([]proposal.PricedServiceProposal, error)
@@ -345,21 +576,47 @@ func AddRoutesForConnection(
 	identityRegistry identityRegistry,
 	publisher eventbus.Publisher,
 	addressProvider addressProvider,
+	blocklist providerBlocklist,
 ) func(*gin.Engine) error {
-	connectionEndpoint := NewConnectionEndpoint(manager, stateProvider, proposalRepository, identityRegistry, publisher, addressProvider)
+	connectionEndpoint := NewConnectionEndpoint(manager, stateProvider, proposalRepository, identityRegistry, publisher, addressProvider, blocklist)
 	return func(e *gin.Engine) error {
 		connGroup := e.Group("")
 		{
 			connGroup.GET("/connection", connectionEndpoint.Status)
 			connGroup.PUT("/connection", connectionEndpoint.Create)
 			connGroup.DELETE("/connection", connectionEndpoint.Kill)
+			connGroup.PUT("/connection/pause", connectionEndpoint.Pause)
+			connGroup.PUT("/connection/resume", connectionEndpoint.Resume)
 			connGroup.GET("/connection/statistics", connectionEndpoint.GetStatistics)
 			connGroup.GET("/connection/traffic", connectionEndpoint.GetTraffic)
+			connGroup.GET("/connection/last-trace", connectionEndpoint.LastTrace)
+			connGroup.GET("/connection/blocklist", connectionEndpoint.Blocklist)
+			connGroup.DELETE("/connection/blocklist", connectionEndpoint.ClearBlocklist)
+			connGroup.DELETE("/connection/blocklist/:provider_id", connectionEndpoint.RemoveFromBlocklist)
+		}
+
+		v2Group := e.Group("/v2/connections")
+		{
+			v2Group.GET("", connectionEndpoint.ListV2)
+			v2Group.POST("", connectionEndpoint.CreateV2)
+			v2Group.GET("/:id", connectionEndpoint.GetV2)
+			v2Group.DELETE("/:id", connectionEndpoint.DeleteV2)
+			v2Group.PUT("/:id/pause", connectionEndpoint.PauseV2)
+			v2Group.PUT("/:id/resume", connectionEndpoint.ResumeV2)
 		}
 		return nil
 	}
 }
 
+func connectionNumber(c *gin.Context) (int, error) {
+	id := c.Query("id")
+	if len(id) == 0 {
+		return 0, nil
+	}
+
+	return strconv.Atoi(id)
+}
+
 func toConnectionRequest(req *http.Request, defaultHermes string) (*contract.ConnectionCreateRequest, error) {
 	connectionRequest := contract.ConnectionCreateRequest{
 		ConnectOptions: contract.ConnectOptions{
@@ -385,5 +642,6 @@ func getConnectOptions(cr *contract.ConnectionCreateRequest) connection.ConnectP
 		DisableKillSwitch: cr.ConnectOptions.DisableKillSwitch,
 		DNS:               dns,
 		ProxyPort:         cr.ConnectOptions.ProxyPort,
+		InactivityTimeout: time.Duration(cr.ConnectOptions.InactivityTimeout) * time.Minute,
 	}
 }
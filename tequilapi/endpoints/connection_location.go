@@ -40,6 +40,10 @@ func locationToRes(l locationstate.Location) contract.LocationDTO {
 		Region:    l.Region,
 		City:      l.City,
 		IPType:    l.IPType,
+
+		Overridden: l.Overridden,
+		Mismatch:   l.Mismatch,
+		Unverified: l.Unverified,
 	}
 }
 
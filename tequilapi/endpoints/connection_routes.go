@@ -0,0 +1,218 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mysteriumnetwork/go-rest/apierror"
+
+	"github.com/mysteriumnetwork/node/core/connection/routing"
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
+	"github.com/mysteriumnetwork/node/tequilapi/utils"
+)
+
+type connectionRoutes interface {
+	Add(connectionID int, app, cidr string) (routing.Route, error)
+	List() ([]routing.Route, error)
+	Remove(id string) error
+	Resolve(app string, ip net.IP) (routing.Route, error)
+}
+
+type connectionRoutesEndpoint struct {
+	routes connectionRoutes
+}
+
+// List returns routing policy of the consumer connections
+// swagger:operation GET /v2/routes Connection connectionRouteList
+//
+//	---
+//	summary: Returns connection routes
+//	description: Returns routes linking traffic of applications or to networks to the consumer connections
+//	responses:
+//	  200:
+//	    description: Connection routes
+//	    schema:
+//	      "$ref": "#/definitions/ConnectionRouteListResponse"
+//	  500:
+//	    description: Internal server error
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+func (cr *connectionRoutesEndpoint) List(c *gin.Context) {
+	routes, err := cr.routes.List()
+	if err != nil {
+		c.Error(apierror.Internal("Could not list connection routes: "+err.Error(), contract.ErrCodeConnectionRouteList))
+		return
+	}
+	utils.WriteAsJSON(contract.NewConnectionRouteListResponse(routes), c.Writer)
+}
+
+// Add links traffic of an application or to a network to a connection
+// swagger:operation POST /v2/routes Connection connectionRouteAdd
+//
+//	---
+//	summary: Adds connection route
+//	description: Links traffic of an application or to a network to a connection. Routes are kept while connections come and go.
+//	parameters:
+//	- in: body
+//	  name: body
+//	  description: Route
+//	  schema:
+//	    $ref: "#/definitions/ConnectionRouteCreateRequestDTO"
+//	responses:
+//	  201:
+//	    description: Route added
+//	    schema:
+//	      "$ref": "#/definitions/ConnectionRouteDTO"
+//	  400:
+//	    description: Failed to parse or request validation failed
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  500:
+//	    description: Internal server error
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+func (cr *connectionRoutesEndpoint) Add(c *gin.Context) {
+	var req contract.ConnectionRouteCreateRequest
+	if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil {
+		c.Error(apierror.ParseFailed())
+		return
+	}
+	if err := req.Validate(); err != nil {
+		c.Error(err)
+		return
+	}
+
+	route, err := cr.routes.Add(req.ConnectionID, req.App, req.CIDR)
+	if errors.Is(err, routing.ErrInvalidRoute) {
+		c.Error(apierror.BadRequest(err.Error(), contract.ErrCodeConnectionRouteAdd))
+		return
+	} else if err != nil {
+		c.Error(apierror.Internal("Could not add connection route: "+err.Error(), contract.ErrCodeConnectionRouteAdd))
+		return
+	}
+
+	c.Status(http.StatusCreated)
+	utils.WriteAsJSON(contract.NewConnectionRouteDTO(route), c.Writer)
+}
+
+// Remove deletes a connection route
+// swagger:operation DELETE /v2/routes/{id} Connection connectionRouteRemove
+//
+//	---
+//	summary: Removes connection route
+//	description: Removes connection route, the traffic it selected falls back to less specific routes
+//	parameters:
+//	- in: path
+//	  name: id
+//	  description: Route ID
+//	  type: string
+//	  required: true
+//	responses:
+//	  202:
+//	    description: Route removed
+//	  404:
+//	    description: Route not found
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  500:
+//	    description: Internal server error
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+func (cr *connectionRoutesEndpoint) Remove(c *gin.Context) {
+	err := cr.routes.Remove(c.Param("id"))
+	if errors.Is(err, routing.ErrNotFound) {
+		c.Error(apierror.NotFound("Connection route not found"))
+		return
+	} else if err != nil {
+		c.Error(apierror.Internal("Could not remove connection route: "+err.Error(), contract.ErrCodeConnectionRouteRemove))
+		return
+	}
+	c.Status(http.StatusAccepted)
+}
+
+// Resolve returns the route selecting traffic of an application to a destination
+// swagger:operation GET /v2/routes/resolve Connection connectionRouteResolve
+//
+//	---
+//	summary: Resolves connection route
+//	description: Returns the most specific route selecting traffic of the application to the destination IP, clients bind the traffic to the proxy port of its connection
+//	parameters:
+//	- in: query
+//	  name: app
+//	  description: Application name
+//	  type: string
+//	- in: query
+//	  name: ip
+//	  description: Destination IP
+//	  type: string
+//	responses:
+//	  200:
+//	    description: Route
+//	    schema:
+//	      "$ref": "#/definitions/ConnectionRouteDTO"
+//	  400:
+//	    description: Failed to parse or request validation failed
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  404:
+//	    description: No route selects the traffic
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  500:
+//	    description: Internal server error
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+func (cr *connectionRoutesEndpoint) Resolve(c *gin.Context) {
+	var ip net.IP
+	if query := c.Query("ip"); query != "" {
+		if ip = net.ParseIP(query); ip == nil {
+			c.Error(apierror.BadRequest("Invalid destination IP", contract.ErrCodeConnectionRouteResolve))
+			return
+		}
+	}
+
+	route, err := cr.routes.Resolve(c.Query("app"), ip)
+	if errors.Is(err, routing.ErrNotFound) {
+		c.Error(apierror.NotFound("No connection route selects the traffic"))
+		return
+	} else if err != nil {
+		c.Error(apierror.Internal("Could not resolve connection route: "+err.Error(), contract.ErrCodeConnectionRouteResolve))
+		return
+	}
+	utils.WriteAsJSON(contract.NewConnectionRouteDTO(route), c.Writer)
+}
+
+// AddRoutesForConnectionRoutes adds routes for managing routing policy of the consumer connections
+func AddRoutesForConnectionRoutes(routes connectionRoutes) func(*gin.Engine) error {
+	cr := &connectionRoutesEndpoint{routes: routes}
+	return func(e *gin.Engine) error {
+		g := e.Group("/v2/routes")
+		{
+			g.GET("", cr.List)
+			g.POST("", cr.Add)
+			g.GET("/resolve", cr.Resolve)
+			g.DELETE("/:id", cr.Remove)
+		}
+		return nil
+	}
+}
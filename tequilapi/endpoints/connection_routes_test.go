@@ -0,0 +1,142 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/core/connection/routing"
+)
+
+type mockConnectionRoutes struct {
+	routes []routing.Route
+}
+
+func (m *mockConnectionRoutes) Add(connectionID int, app, cidr string) (routing.Route, error) {
+	if cidr == "invalid" {
+		return routing.Route{}, fmt.Errorf("%w: broken", routing.ErrInvalidRoute)
+	}
+	r := routing.Route{ID: "1", ConnectionID: connectionID, App: app, CIDR: cidr}
+	m.routes = append(m.routes, r)
+	return r, nil
+}
+
+func (m *mockConnectionRoutes) List() ([]routing.Route, error) {
+	return m.routes, nil
+}
+
+func (m *mockConnectionRoutes) Remove(id string) error {
+	for i, r := range m.routes {
+		if r.ID == id {
+			m.routes = append(m.routes[:i], m.routes[i+1:]...)
+			return nil
+		}
+	}
+	return routing.ErrNotFound
+}
+
+func (m *mockConnectionRoutes) Resolve(app string, _ net.IP) (routing.Route, error) {
+	for _, r := range m.routes {
+		if r.App == app {
+			return r, nil
+		}
+	}
+	return routing.Route{}, routing.ErrNotFound
+}
+
+func TestConnectionRoutes_Add(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		expectedCode int
+	}{
+		{name: "invalid body", body: `{"app": `, expectedCode: http.StatusBadRequest},
+		{name: "missing selectors", body: `{"connection_id": 10000}`, expectedCode: http.StatusBadRequest},
+		{name: "negative connection", body: `{"connection_id": -1, "app": "firefox"}`, expectedCode: http.StatusBadRequest},
+		{name: "invalid CIDR", body: `{"connection_id": 10000, "cidr": "invalid"}`, expectedCode: http.StatusBadRequest},
+		{name: "added", body: `{"connection_id": 10000, "app": "firefox"}`, expectedCode: http.StatusCreated},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := summonTestGin()
+			err := AddRoutesForConnectionRoutes(&mockConnectionRoutes{})(g)
+			assert.NoError(t, err)
+
+			req, err := http.NewRequest(http.MethodPost, "/v2/routes", bytes.NewBufferString(tc.body))
+			assert.NoError(t, err)
+			resp := httptest.NewRecorder()
+			g.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.expectedCode, resp.Code)
+		})
+	}
+}
+
+func TestConnectionRoutes_Resolve(t *testing.T) {
+	g := summonTestGin()
+	err := AddRoutesForConnectionRoutes(&mockConnectionRoutes{
+		routes: []routing.Route{{ID: "1", ConnectionID: 10000, App: "firefox"}},
+	})(g)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		query        string
+		expectedCode int
+		expectedBody string
+	}{
+		{query: "?app=firefox", expectedCode: http.StatusOK, expectedBody: `{"id": "1", "connection_id": 10000, "app": "firefox", "created_at": "0001-01-01T00:00:00Z"}`},
+		{query: "?app=chrome", expectedCode: http.StatusNotFound},
+		{query: "?ip=not-an-ip", expectedCode: http.StatusBadRequest},
+	}
+	for _, tc := range tests {
+		req, err := http.NewRequest(http.MethodGet, "/v2/routes/resolve"+tc.query, nil)
+		assert.NoError(t, err)
+		resp := httptest.NewRecorder()
+		g.ServeHTTP(resp, req)
+
+		assert.Equal(t, tc.expectedCode, resp.Code, tc.query)
+		if tc.expectedBody != "" {
+			assert.JSONEq(t, tc.expectedBody, resp.Body.String())
+		}
+	}
+}
+
+func TestConnectionRoutes_Remove(t *testing.T) {
+	g := summonTestGin()
+	err := AddRoutesForConnectionRoutes(&mockConnectionRoutes{
+		routes: []routing.Route{{ID: "1"}},
+	})(g)
+	assert.NoError(t, err)
+
+	for _, expectedCode := range []int{http.StatusAccepted, http.StatusNotFound} {
+		req, err := http.NewRequest(http.MethodDelete, "/v2/routes/1", nil)
+		assert.NoError(t, err)
+		resp := httptest.NewRecorder()
+		g.ServeHTTP(resp, req)
+
+		assert.Equal(t, expectedCode, resp.Code)
+	}
+}
@@ -19,6 +19,7 @@ package endpoints
 
 import (
 	"context"
+	"encoding/json"
 	"math/big"
 	"net/http"
 	"net/http/httptest"
@@ -41,6 +42,9 @@ import (
 	"github.com/mysteriumnetwork/node/identity"
 	"github.com/mysteriumnetwork/node/identity/registry"
 	"github.com/mysteriumnetwork/node/market"
+	"github.com/mysteriumnetwork/node/session"
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
+	"github.com/mysteriumnetwork/node/trace"
 	"github.com/mysteriumnetwork/payments/crypto"
 )
 
@@ -48,7 +52,10 @@ type mockConnectionManager struct {
 	onConnectReturn      error
 	onDisconnectReturn   error
 	onCheckChannelReturn error
+	onPauseReturn        error
 	onStatusReturn       connectionstate.Status
+	onListReturn         []int
+	onLastTraceReturn    *connection.Trace
 	disconnectCount      int
 	requestedConsumerID  identity.Identity
 	requestedProvider    identity.Identity
@@ -73,6 +80,10 @@ func (cm *mockConnectionManager) Status(int) connectionstate.Status {
 	return cm.onStatusReturn
 }
 
+func (cm *mockConnectionManager) List() []int {
+	return cm.onListReturn
+}
+
 func (cm *mockConnectionManager) Stats(int) connectionstate.Statistics {
 	return connectionstate.Statistics{}
 }
@@ -82,6 +93,11 @@ func (cm *mockConnectionManager) Disconnect(int) error {
 	return cm.onDisconnectReturn
 }
 
+func (cm *mockConnectionManager) DisconnectWithReason(int, session.DisconnectReason) error {
+	cm.disconnectCount++
+	return cm.onDisconnectReturn
+}
+
 func (cm *mockConnectionManager) CheckChannel(context.Context) error {
 	return cm.onCheckChannelReturn
 }
@@ -90,6 +106,25 @@ func (cm *mockConnectionManager) Reconnect(int) {
 	return
 }
 
+func (cm *mockConnectionManager) Pause(int) error {
+	return cm.onPauseReturn
+}
+
+func (cm *mockConnectionManager) Resume(int) error {
+	return cm.onPauseReturn
+}
+
+func (cm *mockConnectionManager) Roam(int) error {
+	return nil
+}
+
+func (cm *mockConnectionManager) LastTrace(int) (connection.Trace, bool) {
+	if cm.onLastTraceReturn == nil {
+		return connection.Trace{}, false
+	}
+	return *cm.onLastTraceReturn, true
+}
+
 func mockRepositoryWithProposal(providerID, serviceType string) *mockProposalRepository {
 	sampleProposal := proposal.PricedServiceProposal{
 		ServiceProposal: market.ServiceProposal{
@@ -117,7 +152,7 @@ func TestAddRoutesForConnectionAddsRoutes(t *testing.T) {
 	}
 
 	mockedProposalProvider := mockRepositoryWithProposal("node1", "noop")
-	err := AddRoutesForConnection(fakeManager, fakeState, mockedProposalProvider, mockIdentityRegistryInstance, eventbus.New(), &mockAddressProvider{})(router)
+	err := AddRoutesForConnection(fakeManager, fakeState, mockedProposalProvider, mockIdentityRegistryInstance, eventbus.New(), &mockAddressProvider{}, nil)(router)
 	assert.NoError(t, err)
 
 	tests := []struct {
@@ -170,6 +205,48 @@ func TestAddRoutesForConnectionAddsRoutes(t *testing.T) {
 	}
 }
 
+func TestConnectionsV2ReturnStatisticsOfTheirSessions(t *testing.T) {
+	status := connectionstate.Status{State: connectionstate.Connected, SessionID: "1"}
+	manager := &mockConnectionManager{onStatusReturn: status, onListReturn: []int{10000}}
+	fakeState := &mockStateProvider{stateToReturn: event.State{Connections: make(map[string]event.Connection)}}
+	fakeState.stateToReturn.Connections["1"] = event.Connection{
+		Session:    status,
+		Statistics: connectionstate.Statistics{At: time.Now(), BytesSent: 1, BytesReceived: 2},
+	}
+
+	router := summonTestGin()
+	err := AddRoutesForConnection(manager, fakeState, &mockProposalRepository{}, mockIdentityRegistryInstance, eventbus.New(), &mockAddressProvider{}, nil)(router)
+	assert.NoError(t, err)
+
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/v2/connections", nil))
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	var list contract.ConnectionListV2Response
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &list))
+	assert.Len(t, list.Connections, 1)
+	assert.Equal(t, 10000, list.Connections[0].ID)
+	assert.Equal(t, "Connected", list.Connections[0].Status)
+	if assert.NotNil(t, list.Connections[0].Statistics) {
+		assert.Equal(t, uint64(2), list.Connections[0].Statistics.BytesReceived)
+	}
+
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/v2/connections/abc", nil))
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	manager.onStatusReturn = connectionstate.Status{State: connectionstate.NotConnected}
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/v2/connections/10001", nil))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `{"id": 10001, "status": "NotConnected"}`, resp.Body.String())
+
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, httptest.NewRequest(http.MethodDelete, "/v2/connections/10000", nil))
+	assert.Equal(t, http.StatusAccepted, resp.Code)
+	assert.Equal(t, 1, manager.disconnectCount)
+}
+
 func TestStateIsReturnedFromStore(t *testing.T) {
 	manager := &mockConnectionManager{
 		onStatusReturn: connectionstate.Status{
@@ -183,7 +260,7 @@ func TestStateIsReturnedFromStore(t *testing.T) {
 	}
 
 	router := summonTestGin()
-	err := AddRoutesForConnection(manager, nil, &mockProposalRepository{}, mockIdentityRegistryInstance, eventbus.New(), &mockAddressProvider{})(router)
+	err := AddRoutesForConnection(manager, nil, &mockProposalRepository{}, mockIdentityRegistryInstance, eventbus.New(), &mockAddressProvider{}, nil)(router)
 	assert.NoError(t, err)
 
 	req := httptest.NewRequest(http.MethodGet, "/connection", nil)
@@ -202,11 +279,117 @@ func TestStateIsReturnedFromStore(t *testing.T) {
 	)
 }
 
+func TestStateIncludesEnabledMitigations(t *testing.T) {
+	manager := &mockConnectionManager{
+		onStatusReturn: connectionstate.Status{
+			State:       connectionstate.Connected,
+			SessionID:   "1",
+			Mitigations: []string{"keepalive-jitter", "control-padding"},
+		},
+	}
+
+	router := summonTestGin()
+	err := AddRoutesForConnection(manager, nil, &mockProposalRepository{}, mockIdentityRegistryInstance, eventbus.New(), &mockAddressProvider{}, nil)(router)
+	assert.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/connection", nil)
+	resp := httptest.NewRecorder()
+
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(
+		t,
+		`{
+			"status" : "Connected",
+			"session_id" : "1",
+			"mitigations" : ["keepalive-jitter", "control-padding"]
+		}`,
+		resp.Body.String(),
+	)
+}
+
+func TestStateIncludesProviderPeer(t *testing.T) {
+	manager := &mockConnectionManager{
+		onStatusReturn: connectionstate.Status{
+			State:     connectionstate.Connected,
+			SessionID: "1",
+			Peer:      session.Peer{Version: "1.30.0", Capabilities: session.CapabilityReconciliation | 1<<10},
+		},
+	}
+
+	router := summonTestGin()
+	err := AddRoutesForConnection(manager, nil, &mockProposalRepository{}, mockIdentityRegistryInstance, eventbus.New(), &mockAddressProvider{}, nil)(router)
+	assert.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/connection", nil)
+	resp := httptest.NewRecorder()
+
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(
+		t,
+		`{
+			"status" : "Connected",
+			"session_id" : "1",
+			"peer" : {
+				"version": "1.30.0",
+				"capabilities": ["reconciliation"],
+				"capability_flags": 1025
+			}
+		}`,
+		resp.Body.String(),
+	)
+}
+
+func TestStateIncludesConnectivityCheck(t *testing.T) {
+	manager := &mockConnectionManager{
+		onStatusReturn: connectionstate.Status{
+			State:     connectionstate.Connected,
+			SessionID: "1",
+			Online: &connectionstate.OnlineStatus{
+				Online:    false,
+				Succeeded: 1,
+				Quorum:    2,
+				Total:     3,
+				CheckedAt: time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC),
+			},
+		},
+	}
+
+	router := summonTestGin()
+	err := AddRoutesForConnection(manager, nil, &mockProposalRepository{}, mockIdentityRegistryInstance, eventbus.New(), &mockAddressProvider{}, nil)(router)
+	assert.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/connection", nil)
+	resp := httptest.NewRecorder()
+
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(
+		t,
+		`{
+			"status" : "Connected",
+			"session_id" : "1",
+			"online" : {
+				"online": false,
+				"succeeded": 1,
+				"quorum": 2,
+				"total": 3,
+				"checked_at": "2024-01-02T15:04:05Z"
+			}
+		}`,
+		resp.Body.String(),
+	)
+}
+
 func TestPutReturns400ErrorIfRequestBodyIsNotJSON(t *testing.T) {
 	fakeManager := mockConnectionManager{}
 
 	router := summonTestGin()
-	err := AddRoutesForConnection(&fakeManager, nil, &mockProposalRepository{}, mockIdentityRegistryInstance, eventbus.New(), &mockAddressProvider{})(router)
+	err := AddRoutesForConnection(&fakeManager, nil, &mockProposalRepository{}, mockIdentityRegistryInstance, eventbus.New(), &mockAddressProvider{}, nil)(router)
 	assert.NoError(t, err)
 
 	req := httptest.NewRequest(http.MethodPut, "/connection", strings.NewReader("a"))
@@ -221,7 +404,7 @@ func TestPutReturns422ErrorIfRequestBodyIsMissingFieldValues(t *testing.T) {
 	fakeManager := mockConnectionManager{}
 
 	router := summonTestGin()
-	err := AddRoutesForConnection(&fakeManager, nil, &mockProposalRepository{}, mockIdentityRegistryInstance, eventbus.New(), &mockAddressProvider{})(router)
+	err := AddRoutesForConnection(&fakeManager, nil, &mockProposalRepository{}, mockIdentityRegistryInstance, eventbus.New(), &mockAddressProvider{}, nil)(router)
 	assert.NoError(t, err)
 
 	req := httptest.NewRequest(http.MethodPut, "/connection", strings.NewReader("{}"))
@@ -260,7 +443,7 @@ func TestPutWithValidBodyCreatesConnection(t *testing.T) {
 	resp := httptest.NewRecorder()
 
 	g := summonTestGin()
-	err := AddRoutesForConnection(&fakeManager, fakeState, proposalProvider, mockIdentityRegistryInstance, eventbus.New(), &mockAddressProvider{})(g)
+	err := AddRoutesForConnection(&fakeManager, fakeState, proposalProvider, mockIdentityRegistryInstance, eventbus.New(), &mockAddressProvider{}, nil)(g)
 	assert.NoError(t, err)
 
 	g.ServeHTTP(resp, req)
@@ -300,7 +483,7 @@ func TestPutUnregisteredIdentityReturnsError(t *testing.T) {
 	resp := httptest.NewRecorder()
 
 	g := summonTestGin()
-	err := AddRoutesForConnection(&fakeManager, &mockStateProvider{}, proposalProvider, &mir, eventbus.New(), &mockAddressProvider{})(g)
+	err := AddRoutesForConnection(&fakeManager, &mockStateProvider{}, proposalProvider, &mir, eventbus.New(), &mockAddressProvider{}, nil)(g)
 	assert.NoError(t, err)
 
 	g.ServeHTTP(resp, req)
@@ -328,7 +511,7 @@ func TestPutFailedRegistrationCheckReturnsError(t *testing.T) {
 	resp := httptest.NewRecorder()
 
 	g := summonTestGin()
-	err := AddRoutesForConnection(&fakeManager, &mockStateProvider{}, proposalProvider, &mir, eventbus.New(), &mockAddressProvider{})(g)
+	err := AddRoutesForConnection(&fakeManager, &mockStateProvider{}, proposalProvider, &mir, eventbus.New(), &mockAddressProvider{}, nil)(g)
 	assert.NoError(t, err)
 
 	g.ServeHTTP(resp, req)
@@ -356,7 +539,7 @@ func TestPutWithServiceTypeOverridesDefault(t *testing.T) {
 	resp := httptest.NewRecorder()
 
 	g := summonTestGin()
-	err := AddRoutesForConnection(&fakeManager, &mockStateProvider{}, mystAPI, mockIdentityRegistryInstance, eventbus.New(), &mockAddressProvider{})(g)
+	err := AddRoutesForConnection(&fakeManager, &mockStateProvider{}, mystAPI, mockIdentityRegistryInstance, eventbus.New(), &mockAddressProvider{}, nil)(g)
 	assert.NoError(t, err)
 
 	g.ServeHTTP(resp, req)
@@ -376,7 +559,7 @@ func TestDeleteCallsDisconnect(t *testing.T) {
 	resp := httptest.NewRecorder()
 
 	g := summonTestGin()
-	err := AddRoutesForConnection(&fakeManager, nil, &mockProposalRepository{}, mockIdentityRegistryInstance, eventbus.New(), &mockAddressProvider{})(g)
+	err := AddRoutesForConnection(&fakeManager, nil, &mockProposalRepository{}, mockIdentityRegistryInstance, eventbus.New(), &mockAddressProvider{}, nil)(g)
 	assert.NoError(t, err)
 
 	g.ServeHTTP(resp, req)
@@ -386,6 +569,68 @@ func TestDeleteCallsDisconnect(t *testing.T) {
 	assert.Equal(t, fakeManager.disconnectCount, 1)
 }
 
+func TestPauseReturnsUnprocessableWithoutEstablishedConnection(t *testing.T) {
+	fakeManager := mockConnectionManager{onPauseReturn: connection.ErrConnectionNotActive}
+
+	g := summonTestGin()
+	err := AddRoutesForConnection(&fakeManager, nil, &mockProposalRepository{}, mockIdentityRegistryInstance, eventbus.New(), &mockAddressProvider{}, nil)(g)
+	assert.NoError(t, err)
+
+	resp := httptest.NewRecorder()
+	g.ServeHTTP(resp, httptest.NewRequest(http.MethodPut, "/connection/pause", nil))
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+
+	fakeManager.onPauseReturn = nil
+	resp = httptest.NewRecorder()
+	g.ServeHTTP(resp, httptest.NewRequest(http.MethodPut, "/connection/resume", nil))
+	assert.Equal(t, http.StatusAccepted, resp.Code)
+}
+
+func TestLastTraceEndpointReturnsStageTimings(t *testing.T) {
+	fakeManager := mockConnectionManager{}
+
+	g := summonTestGin()
+	err := AddRoutesForConnection(&fakeManager, nil, &mockProposalRepository{}, mockIdentityRegistryInstance, eventbus.New(), &mockAddressProvider{}, nil)(g)
+	assert.NoError(t, err)
+
+	resp := httptest.NewRecorder()
+	g.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/connection/last-trace", nil))
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+
+	started := time.Date(2024, 5, 6, 10, 0, 0, 0, time.UTC)
+	fakeManager.onLastTraceReturn = &connection.Trace{
+		SessionID:   "session-1",
+		ProviderID:  "0x1",
+		ServiceType: "wireguard",
+		Started:     started,
+		Finished:    started.Add(3 * time.Second),
+		FirstByte:   started.Add(4 * time.Second),
+		Stages: []trace.Stage{
+			{Key: connection.TraceStageProposalLookup, Start: started, End: started.Add(100 * time.Millisecond)},
+			{Key: connection.TraceStageTunnel, Start: started.Add(2 * time.Second)},
+		},
+	}
+	resp = httptest.NewRecorder()
+	g.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/connection/last-trace", nil))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(
+		t,
+		`{
+			"session_id": "session-1",
+			"provider_id": "0x1",
+			"service_type": "wireguard",
+			"started_at": "2024-05-06T10:00:00Z",
+			"duration_ms": 3000,
+			"first_byte_ms": 4000,
+			"stages": [
+				{"name": "Consumer proposal lookup", "offset_ms": 0, "duration_ms": 100, "finished": true},
+				{"name": "Consumer tunnel handshake", "offset_ms": 2000, "duration_ms": 0, "finished": false}
+			]
+		}`,
+		resp.Body.String(),
+	)
+}
+
 func TestGetStatisticsEndpointReturnsStatistics(t *testing.T) {
 	fakeState := &mockStateProvider{stateToReturn: event.State{Connections: make(map[string]event.Connection)}}
 	fakeState.stateToReturn.Connections["1"] = event.Connection{
@@ -410,7 +655,7 @@ func TestGetStatisticsEndpointReturnsStatistics(t *testing.T) {
 			}`))
 
 	g := summonTestGin()
-	err := AddRoutesForConnection(&manager, fakeState, &mockProposalRepository{}, mockIdentityRegistryInstance, eventbus.New(), &mockAddressProvider{})(g)
+	err := AddRoutesForConnection(&manager, fakeState, &mockProposalRepository{}, mockIdentityRegistryInstance, eventbus.New(), &mockAddressProvider{}, nil)(g)
 	assert.NoError(t, err)
 
 	g.ServeHTTP(resp, req)
@@ -452,7 +697,7 @@ func TestEndpointReturnsConflictStatusIfConnectionAlreadyExists(t *testing.T) {
 	resp := httptest.NewRecorder()
 
 	g := summonTestGin()
-	err := AddRoutesForConnection(&manager, nil, mystAPI, mockIdentityRegistryInstance, eventbus.New(), &mockAddressProvider{})(g)
+	err := AddRoutesForConnection(&manager, nil, mystAPI, mockIdentityRegistryInstance, eventbus.New(), &mockAddressProvider{}, nil)(g)
 	assert.NoError(t, err)
 
 	g.ServeHTTP(resp, req)
@@ -465,7 +710,7 @@ func TestEndpointReturnsConflictStatusIfConnectionAlreadyExists(t *testing.T) {
 	manager := mockConnectionManager{}
 	manager.onDisconnectReturn = connection.ErrNoConnection
 
-	connectionEndpoint := NewConnectionEndpoint(&manager, nil, &mockProposalRepository{}, mockIdentityRegistryInstance, eventbus.New(), &mockAddressProvider{})
+	connectionEndpoint := NewConnectionEndpoint(&manager, nil, &mockProposalRepository{}, mockIdentityRegistryInstance, eventbus.New(), &mockAddressProvider{}, nil)
 
 	req := httptest.NewRequest(
 		http.MethodDelete,
@@ -503,7 +748,7 @@ func TestConnectReturnsConnectCancelledStatusWhenErrConnectionCancelledIsEncount
 	resp := httptest.NewRecorder()
 
 	g := summonTestGin()
-	err := AddRoutesForConnection(&manager, nil, mockProposalProvider, mockIdentityRegistryInstance, eventbus.New(), &mockAddressProvider{})(g)
+	err := AddRoutesForConnection(&manager, nil, mockProposalProvider, mockIdentityRegistryInstance, eventbus.New(), &mockAddressProvider{}, nil)(g)
 	assert.NoError(t, err)
 
 	g.ServeHTTP(resp, req)
@@ -528,7 +773,7 @@ func TestConnectReturnsErrorIfNoProposals(t *testing.T) {
 	resp := httptest.NewRecorder()
 
 	g := summonTestGin()
-	err := AddRoutesForConnection(&manager, nil, &mockProposalRepository{}, mockIdentityRegistryInstance, eventbus.New(), &mockAddressProvider{})(g)
+	err := AddRoutesForConnection(&manager, nil, &mockProposalRepository{}, mockIdentityRegistryInstance, eventbus.New(), &mockAddressProvider{}, nil)(g)
 	assert.NoError(t, err)
 
 	g.ServeHTTP(resp, req)
@@ -536,4 +781,34 @@ func TestConnectReturnsErrorIfNoProposals(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, resp.Code)
 }
 
+func TestConnectionBlocklist(t *testing.T) {
+	blocklist := connection.NewProviderBlocklist(1, time.Minute)
+	blocklist.Failed("0x1", "connection refused")
+	blocklist.Failed("0x2", "connection refused")
+
+	g := summonTestGin()
+	err := AddRoutesForConnection(&mockConnectionManager{}, nil, &mockProposalRepository{}, mockIdentityRegistryInstance, eventbus.New(), &mockAddressProvider{}, blocklist)(g)
+	assert.NoError(t, err)
+
+	resp := httptest.NewRecorder()
+	g.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/connection/blocklist", nil))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"provider_id":"0x1"`)
+	assert.Contains(t, resp.Body.String(), `"blocked":true`)
+
+	resp = httptest.NewRecorder()
+	g.ServeHTTP(resp, httptest.NewRequest(http.MethodDelete, "/connection/blocklist/0x1", nil))
+	assert.Equal(t, http.StatusAccepted, resp.Code)
+	assert.False(t, blocklist.Blocked("0x1"))
+
+	resp = httptest.NewRecorder()
+	g.ServeHTTP(resp, httptest.NewRequest(http.MethodDelete, "/connection/blocklist/0x1", nil))
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+
+	resp = httptest.NewRecorder()
+	g.ServeHTTP(resp, httptest.NewRequest(http.MethodDelete, "/connection/blocklist", nil))
+	assert.Equal(t, http.StatusAccepted, resp.Code)
+	assert.Empty(t, blocklist.List())
+}
+
 var mockIdentityRegistryInstance = &registry.FakeRegistry{RegistrationStatus: registry.Registered}
@@ -0,0 +1,268 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mysteriumnetwork/go-rest/apierror"
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
+	"github.com/mysteriumnetwork/node/tequilapi/utils"
+)
+
+// ListV2 returns established connections
+// swagger:operation GET /v2/connections Connection connectionListV2
+//
+//	---
+//	summary: Returns established connections
+//	description: Returns status and statistics of every established consumer connection
+//	responses:
+//	  200:
+//	    description: Connections
+//	    schema:
+//	      "$ref": "#/definitions/ConnectionListV2Response"
+func (ce *ConnectionEndpoint) ListV2(c *gin.Context) {
+	ids := ce.manager.List()
+
+	response := contract.ConnectionListV2Response{Connections: make([]contract.ConnectionV2DTO, len(ids))}
+	for i, id := range ids {
+		response.Connections[i] = ce.connectionV2(id)
+	}
+	utils.WriteAsJSON(response, c.Writer)
+}
+
+// CreateV2 starts new connection
+// swagger:operation POST /v2/connections Connection connectionCreateV2
+//
+//	---
+//	summary: Starts new connection
+//	description: Consumer opens connection to provider next to the already established ones. Connection ID is the proxy port given in connect options, 0 for the system wide tunnel.
+//	parameters:
+//	  - in: body
+//	    name: body
+//	    description: Parameters in body (consumer_id, provider_id, service_type) required for creating new connection
+//	    schema:
+//	      $ref: "#/definitions/ConnectionCreateRequestDTO"
+//	responses:
+//	  201:
+//	    description: Connection started
+//	    schema:
+//	      "$ref": "#/definitions/ConnectionV2DTO"
+//	  400:
+//	    description: Failed to parse or request validation failed
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  422:
+//	    description: Unable to process the request at this point
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  500:
+//	    description: Internal server error
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+func (ce *ConnectionEndpoint) CreateV2(c *gin.Context) {
+	id, ok := ce.connect(c)
+	if !ok {
+		return
+	}
+
+	c.Status(http.StatusCreated)
+	utils.WriteAsJSON(ce.connectionV2(id), c.Writer)
+}
+
+// GetV2 returns connection details
+// swagger:operation GET /v2/connections/{id} Connection connectionGetV2
+//
+//	---
+//	summary: Returns connection details
+//	description: Returns status and statistics of the connection
+//	parameters:
+//	  - name: id
+//	    in: path
+//	    description: Connection ID
+//	    type: integer
+//	    required: true
+//	responses:
+//	  200:
+//	    description: Connection
+//	    schema:
+//	      "$ref": "#/definitions/ConnectionV2DTO"
+//	  400:
+//	    description: Failed to parse or request validation failed
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+func (ce *ConnectionEndpoint) GetV2(c *gin.Context) {
+	id, err := connectionIDParam(c)
+	if err != nil {
+		c.Error(apierror.ParseFailed())
+		return
+	}
+
+	utils.WriteAsJSON(ce.connectionV2(id), c.Writer)
+}
+
+// DeleteV2 stops connection
+// swagger:operation DELETE /v2/connections/{id} Connection connectionDeleteV2
+//
+//	---
+//	summary: Stops connection
+//	description: Stops the connection, other connections are not affected
+//	parameters:
+//	  - name: id
+//	    in: path
+//	    description: Connection ID
+//	    type: integer
+//	    required: true
+//	responses:
+//	  202:
+//	    description: Connection stopped
+//	  400:
+//	    description: Failed to parse or request validation failed
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  422:
+//	    description: Unable to process the request at this point (e.g. no active connection exists)
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  500:
+//	    description: Internal server error
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+func (ce *ConnectionEndpoint) DeleteV2(c *gin.Context) {
+	id, err := connectionIDParam(c)
+	if err != nil {
+		c.Error(apierror.ParseFailed())
+		return
+	}
+
+	if err := ce.manager.Disconnect(id); err != nil {
+		ce.handleDisconnectError(c, err)
+		return
+	}
+	c.Status(http.StatusAccepted)
+}
+
+// PauseV2 suspends data flow and invoicing of the connection
+// swagger:operation PUT /v2/connections/{id}/pause Connection connectionPauseV2
+//
+//	---
+//	summary: Pauses connection
+//	description: Suspends data flow and invoicing of the connection, keeping the session alive for a quick resume
+//	parameters:
+//	  - name: id
+//	    in: path
+//	    description: Connection ID
+//	    type: integer
+//	    required: true
+//	responses:
+//	  202:
+//	    description: Connection paused
+//	  400:
+//	    description: Failed to parse or request validation failed
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  422:
+//	    description: Unable to process the request at this point (e.g. no established connection exists)
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  500:
+//	    description: Internal server error
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+func (ce *ConnectionEndpoint) PauseV2(c *gin.Context) {
+	id, err := connectionIDParam(c)
+	if err != nil {
+		c.Error(apierror.ParseFailed())
+		return
+	}
+
+	if err := ce.manager.Pause(id); err != nil {
+		ce.handlePauseError(c, "Could not pause connection: ", contract.ErrCodeConnectionPause, err)
+		return
+	}
+	c.Status(http.StatusAccepted)
+}
+
+// ResumeV2 restores data flow and invoicing of the paused connection
+// swagger:operation PUT /v2/connections/{id}/resume Connection connectionResumeV2
+//
+//	---
+//	summary: Resumes connection
+//	description: Restores data flow and invoicing of the paused connection
+//	parameters:
+//	  - name: id
+//	    in: path
+//	    description: Connection ID
+//	    type: integer
+//	    required: true
+//	responses:
+//	  202:
+//	    description: Connection resumed
+//	  400:
+//	    description: Failed to parse or request validation failed
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  422:
+//	    description: Unable to process the request at this point (e.g. no paused connection exists)
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  500:
+//	    description: Internal server error
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+func (ce *ConnectionEndpoint) ResumeV2(c *gin.Context) {
+	id, err := connectionIDParam(c)
+	if err != nil {
+		c.Error(apierror.ParseFailed())
+		return
+	}
+
+	if err := ce.manager.Resume(id); err != nil {
+		ce.handlePauseError(c, "Could not resume connection: ", contract.ErrCodeConnectionResume, err)
+		return
+	}
+	c.Status(http.StatusAccepted)
+}
+
+// connectionV2 returns status of the connection with statistics of its own session.
+func (ce *ConnectionEndpoint) connectionV2(id int) contract.ConnectionV2DTO {
+	status := ce.manager.Status(id)
+	dto := contract.ConnectionV2DTO{
+		ID:            id,
+		ConnectionDTO: contract.ConnectionDTO{ConnectionInfoDTO: contract.NewConnectionInfoDTO(status)},
+	}
+
+	// Empty session ID would match the first connection known to the state keeper.
+	if status.SessionID == "" {
+		return dto
+	}
+
+	conn := ce.stateProvider.GetConnection(string(status.SessionID))
+	if conn.Session.SessionID == status.SessionID && !conn.Statistics.At.IsZero() {
+		stats := contract.NewConnectionStatisticsDTO(conn.Session, conn.Statistics, conn.Throughput, conn.Invoice)
+		dto.Statistics = &stats
+	}
+	return dto
+}
+
+func connectionIDParam(c *gin.Context) (int, error) {
+	return strconv.Atoi(c.Param("id"))
+}
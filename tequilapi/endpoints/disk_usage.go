@@ -0,0 +1,59 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/mysteriumnetwork/node/core/diskusage"
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
+	"github.com/mysteriumnetwork/node/tequilapi/utils"
+)
+
+type diskUsageReporter interface {
+	Report() diskusage.Report
+}
+
+type diskUsageEndpoint struct {
+	monitor diskUsageReporter
+}
+
+// DiskUsage returns data directory usage samples and growth projection
+//
+// swagger:operation GET /debug/disk-usage Debug getDiskUsage
+//
+//	---
+//	summary: Get data directory disk usage
+//	description: Returns data directory usage per category sampled during the last 24 hours and the time the disk is projected to be full
+//	responses:
+//	  200:
+//	    description: Disk usage report
+//	    schema:
+//	      "$ref": "#/definitions/DiskUsageDTO"
+func (de *diskUsageEndpoint) DiskUsage(c *gin.Context) {
+	utils.WriteAsJSON(contract.NewDiskUsageDTO(de.monitor.Report()), c.Writer)
+}
+
+// AddRoutesForDiskUsage registers /debug/disk-usage endpoint in Tequilapi
+func AddRoutesForDiskUsage(monitor diskUsageReporter) func(*gin.Engine) error {
+	de := &diskUsageEndpoint{monitor: monitor}
+	return func(e *gin.Engine) error {
+		e.GET("/debug/disk-usage", de.DiskUsage)
+		return nil
+	}
+}
@@ -0,0 +1,85 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mysteriumnetwork/go-rest/apierror"
+
+	"github.com/mysteriumnetwork/node/core/earnings"
+	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
+	"github.com/mysteriumnetwork/node/tequilapi/utils"
+)
+
+type earningsProjector interface {
+	Project(providerID identity.Identity) (earnings.Projection, error)
+}
+
+type earningsProjectionEndpoint struct {
+	projector earningsProjector
+}
+
+// swagger:operation GET /identities/{id}/earnings/projection Identity earningsProjection
+//
+//	---
+//	summary: Projects provider earnings
+//	description: Projects daily, weekly and monthly earnings of the provider identity by pricing the traffic and session time served per day during the last 4 weeks at the current prices. Earnings are projected before fees.
+//	parameters:
+//	- in: path
+//	  name: id
+//	  description: Provider identity
+//	  type: string
+//	  required: true
+//	responses:
+//	  200:
+//	    description: Earnings projection
+//	    schema:
+//	      "$ref": "#/definitions/EarningsProjectionResponse"
+//	  404:
+//	    description: No provided sessions to project from
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  500:
+//	    description: Internal server error
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+func (e *earningsProjectionEndpoint) Projection(c *gin.Context) {
+	projection, err := e.projector.Project(identity.FromAddress(c.Param("id")))
+	if errors.Is(err, earnings.ErrNoHistory) {
+		c.Error(apierror.NotFound("No provided sessions to project earnings from"))
+		return
+	}
+	if err != nil {
+		c.Error(apierror.Internal("Could not project earnings: "+err.Error(), contract.ErrCodeIDEarningsProjection))
+		return
+	}
+
+	utils.WriteAsJSON(contract.NewEarningsProjectionResponse(projection), c.Writer)
+}
+
+// AddRoutesForEarningsProjection attaches provider earnings projection endpoints to router.
+func AddRoutesForEarningsProjection(projector earningsProjector) func(*gin.Engine) error {
+	endpoint := &earningsProjectionEndpoint{projector: projector}
+	return func(e *gin.Engine) error {
+		e.GET("/identities/:id/earnings/projection", endpoint.Projection)
+		return nil
+	}
+}
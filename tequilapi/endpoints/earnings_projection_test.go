@@ -0,0 +1,109 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mysteriumnetwork/node/consumer/session"
+	"github.com/mysteriumnetwork/node/core/earnings"
+	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/market"
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
+)
+
+type mockEarningsProjector struct {
+	projection earnings.Projection
+	err        error
+	providerID identity.Identity
+}
+
+func (m *mockEarningsProjector) Project(providerID identity.Identity) (earnings.Projection, error) {
+	m.providerID = providerID
+	return m.projection, m.err
+}
+
+func TestEarningsProjectionEndpoint_Projection(t *testing.T) {
+	// given
+	stats := session.NewStats()
+	stats.Count = 2
+	stats.SumDataSent = 1024
+	stats.SumDuration = time.Hour
+	stats.SumTokens = big.NewInt(280)
+	projector := &mockEarningsProjector{projection: earnings.Projection{
+		Days:    28,
+		Daily:   big.NewInt(10),
+		Weekly:  big.NewInt(70),
+		Monthly: big.NewInt(300),
+		Services: []earnings.ServiceProjection{{
+			ServiceType: "wireguard",
+			Stats:       stats,
+			Earned:      big.NewInt(10),
+			Price:       market.Price{PricePerHour: big.NewInt(1), PricePerGiB: big.NewInt(2)},
+			Daily:       big.NewInt(10),
+			Weekly:      big.NewInt(70),
+			Monthly:     big.NewInt(300),
+		}},
+	}}
+	router := summonTestGin()
+	err := AddRoutesForEarningsProjection(projector)(router)
+	require.NoError(t, err)
+
+	// when
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/identities/0xAB/earnings/projection", nil))
+
+	// then
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, identity.FromAddress("0xab"), projector.providerID)
+
+	var projection contract.EarningsProjectionResponse
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &projection))
+	assert.Equal(t, 28, projection.HistoryDays)
+	assert.Equal(t, "70", projection.Weekly.Wei)
+	require.Len(t, projection.Services, 1)
+	assert.Equal(t, "wireguard", projection.Services[0].ServiceType)
+	assert.Equal(t, 2, projection.Services[0].Sessions)
+	assert.Equal(t, uint64(1024), projection.Services[0].Bytes)
+	assert.Equal(t, uint64(3600), projection.Services[0].Duration)
+	assert.Equal(t, "280", projection.Services[0].Earned.Wei)
+	assert.Equal(t, "2", projection.Services[0].PricePerGiB.Wei)
+	assert.Equal(t, "300", projection.Services[0].Monthly.Wei)
+}
+
+func TestEarningsProjectionEndpoint_ProjectionWithoutHistory(t *testing.T) {
+	// given
+	router := summonTestGin()
+	err := AddRoutesForEarningsProjection(&mockEarningsProjector{err: earnings.ErrNoHistory})(router)
+	require.NoError(t, err)
+
+	// when
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/identities/0x1/earnings/projection", nil))
+
+	// then
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+}
@@ -0,0 +1,163 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mysteriumnetwork/go-rest/apierror"
+
+	"github.com/mysteriumnetwork/node/core/connection/external"
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
+	"github.com/mysteriumnetwork/node/tequilapi/utils"
+)
+
+type externalEndpoints interface {
+	Add(name, serviceType, country, config string) (external.Endpoint, error)
+	List() ([]external.Endpoint, error)
+	Remove(id string) error
+}
+
+type externalEndpointsEndpoint struct {
+	endpoints externalEndpoints
+}
+
+// List returns VPN endpoints imported from outside of the network
+// swagger:operation GET /connection/external Connection externalEndpointList
+//
+//	---
+//	summary: Returns imported endpoints
+//	description: Returns VPN endpoints imported from outside of the network, their configs are not included
+//	responses:
+//	  200:
+//	    description: Imported endpoints
+//	    schema:
+//	      "$ref": "#/definitions/ExternalEndpointListResponse"
+//	  500:
+//	    description: Internal server error
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+func (ee *externalEndpointsEndpoint) List(c *gin.Context) {
+	endpoints, err := ee.endpoints.List()
+	if err != nil {
+		c.Error(apierror.Internal("Could not list external endpoints: "+err.Error(), contract.ErrCodeExternalEndpointList))
+		return
+	}
+	utils.WriteAsJSON(contract.NewExternalEndpointListResponse(endpoints), c.Writer)
+}
+
+// Add imports a VPN endpoint from outside of the network
+// swagger:operation POST /connection/external Connection externalEndpointAdd
+//
+//	---
+//	summary: Imports endpoint
+//	description: Imports raw WireGuard or OpenVPN client config as a proposal, which can be connected to by its provider ID. The config is stored in the node database.
+//	parameters:
+//	- in: body
+//	  name: body
+//	  description: Endpoint config
+//	  schema:
+//	    $ref: "#/definitions/ExternalEndpointCreateRequestDTO"
+//	responses:
+//	  201:
+//	    description: Endpoint imported
+//	    schema:
+//	      "$ref": "#/definitions/ExternalEndpointDTO"
+//	  400:
+//	    description: Failed to parse or request validation failed
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  500:
+//	    description: Internal server error
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+func (ee *externalEndpointsEndpoint) Add(c *gin.Context) {
+	var req contract.ExternalEndpointCreateRequest
+	if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil {
+		c.Error(apierror.ParseFailed())
+		return
+	}
+	if err := req.Validate(); err != nil {
+		c.Error(err)
+		return
+	}
+
+	endpoint, err := ee.endpoints.Add(req.Name, req.ServiceType, req.Country, req.Config)
+	if errors.Is(err, external.ErrInvalidConfig) {
+		c.Error(apierror.BadRequest(err.Error(), contract.ErrCodeExternalEndpointAdd))
+		return
+	} else if err != nil {
+		c.Error(apierror.Internal("Could not add external endpoint: "+err.Error(), contract.ErrCodeExternalEndpointAdd))
+		return
+	}
+
+	c.Status(http.StatusCreated)
+	utils.WriteAsJSON(contract.NewExternalEndpointDTO(endpoint), c.Writer)
+}
+
+// Remove deletes an imported VPN endpoint
+// swagger:operation DELETE /connection/external/{id} Connection externalEndpointRemove
+//
+//	---
+//	summary: Removes imported endpoint
+//	description: Removes imported endpoint along with its stored config
+//	parameters:
+//	- in: path
+//	  name: id
+//	  description: Endpoint ID
+//	  type: string
+//	  required: true
+//	responses:
+//	  202:
+//	    description: Endpoint removed
+//	  404:
+//	    description: Endpoint not found
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  500:
+//	    description: Internal server error
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+func (ee *externalEndpointsEndpoint) Remove(c *gin.Context) {
+	err := ee.endpoints.Remove(c.Param("id"))
+	if errors.Is(err, external.ErrNotFound) {
+		c.Error(apierror.NotFound("External endpoint not found"))
+		return
+	} else if err != nil {
+		c.Error(apierror.Internal("Could not remove external endpoint: "+err.Error(), contract.ErrCodeExternalEndpointRemove))
+		return
+	}
+	c.Status(http.StatusAccepted)
+}
+
+// AddRoutesForExternalEndpoints adds routes for managing VPN endpoints imported from outside of the network
+func AddRoutesForExternalEndpoints(endpoints externalEndpoints) func(*gin.Engine) error {
+	ee := &externalEndpointsEndpoint{endpoints: endpoints}
+	return func(e *gin.Engine) error {
+		g := e.Group("/connection/external")
+		{
+			g.GET("", ee.List)
+			g.POST("", ee.Add)
+			g.DELETE("/:id", ee.Remove)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,121 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/core/connection/external"
+)
+
+type mockExternalEndpoints struct {
+	endpoints []external.Endpoint
+}
+
+func (m *mockExternalEndpoints) Add(name, serviceType, country, config string) (external.Endpoint, error) {
+	if config != "valid" {
+		return external.Endpoint{}, fmt.Errorf("%w: broken", external.ErrInvalidConfig)
+	}
+	e := external.Endpoint{ID: "1", Name: name, ServiceType: serviceType, Country: country, Config: config}
+	m.endpoints = append(m.endpoints, e)
+	return e, nil
+}
+
+func (m *mockExternalEndpoints) List() ([]external.Endpoint, error) {
+	return m.endpoints, nil
+}
+
+func (m *mockExternalEndpoints) Remove(id string) error {
+	for i, e := range m.endpoints {
+		if e.ID == id {
+			m.endpoints = append(m.endpoints[:i], m.endpoints[i+1:]...)
+			return nil
+		}
+	}
+	return external.ErrNotFound
+}
+
+func TestExternalEndpoints_Add(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		expectedCode int
+	}{
+		{name: "invalid body", body: `{"config": `, expectedCode: http.StatusBadRequest},
+		{name: "missing config", body: `{"service_type": "wireguard"}`, expectedCode: http.StatusBadRequest},
+		{name: "invalid config", body: `{"service_type": "wireguard", "config": "broken"}`, expectedCode: http.StatusBadRequest},
+		{name: "added", body: `{"name": "home", "service_type": "wireguard", "config": "valid"}`, expectedCode: http.StatusCreated},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := summonTestGin()
+			err := AddRoutesForExternalEndpoints(&mockExternalEndpoints{})(g)
+			assert.NoError(t, err)
+
+			req, err := http.NewRequest(http.MethodPost, "/connection/external", bytes.NewBufferString(tc.body))
+			assert.NoError(t, err)
+			resp := httptest.NewRecorder()
+			g.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.expectedCode, resp.Code)
+		})
+	}
+}
+
+func TestExternalEndpoints_ListDoesNotExposeConfig(t *testing.T) {
+	g := summonTestGin()
+	err := AddRoutesForExternalEndpoints(&mockExternalEndpoints{
+		endpoints: []external.Endpoint{{ID: "1", Name: "home", ServiceType: "wireguard", Config: "PrivateKey = secret"}},
+	})(g)
+	assert.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodGet, "/connection/external", nil)
+	assert.NoError(t, err)
+	resp := httptest.NewRecorder()
+	g.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t,
+		`{"endpoints": [{"id": "1", "provider_id": "external:1", "name": "home", "service_type": "wireguard", "created_at": "0001-01-01T00:00:00Z"}]}`,
+		resp.Body.String(),
+	)
+}
+
+func TestExternalEndpoints_Remove(t *testing.T) {
+	g := summonTestGin()
+	err := AddRoutesForExternalEndpoints(&mockExternalEndpoints{
+		endpoints: []external.Endpoint{{ID: "1"}},
+	})(g)
+	assert.NoError(t, err)
+
+	for _, expectedCode := range []int{http.StatusAccepted, http.StatusNotFound} {
+		req, err := http.NewRequest(http.MethodDelete, "/connection/external/1", nil)
+		assert.NoError(t, err)
+		resp := httptest.NewRecorder()
+		g.ServeHTTP(resp, req)
+
+		assert.Equal(t, expectedCode, resp.Code)
+	}
+}
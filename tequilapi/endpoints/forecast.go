@@ -0,0 +1,88 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mysteriumnetwork/go-rest/apierror"
+	"github.com/mysteriumnetwork/node/consumer/forecast"
+	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
+	"github.com/mysteriumnetwork/node/tequilapi/utils"
+)
+
+type forecaster interface {
+	Forecast(consumerID identity.Identity, country string) (forecast.Forecast, error)
+}
+
+type forecastEndpoint struct {
+	forecaster forecaster
+}
+
+// swagger:operation GET /sessions/forecast Session sessionForecast
+//
+//	---
+//	summary: Forecasts consumer usage
+//	description: Forecasts next week data usage and spend from the consumed sessions history and advises whether cheaper providers are available
+//	responses:
+//	  200:
+//	    description: Usage forecast
+//	    schema:
+//	      "$ref": "#/definitions/UsageForecastResponse"
+//	  400:
+//	    description: Failed to parse or request validation failed
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  404:
+//	    description: No consumed sessions to forecast from
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  500:
+//	    description: Internal server error
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+func (e *forecastEndpoint) Forecast(c *gin.Context) {
+	query := contract.UsageForecastQuery{}
+	if err := query.Bind(c.Request); err != nil {
+		c.Error(err)
+		return
+	}
+
+	fc, err := e.forecaster.Forecast(identity.FromAddress(query.ConsumerID), query.Country)
+	if errors.Is(err, forecast.ErrNoHistory) {
+		c.Error(apierror.NotFound("No consumed sessions to forecast from"))
+		return
+	}
+	if err != nil {
+		c.Error(apierror.Internal("Could not forecast usage: "+err.Error(), contract.ErrCodeSessionForecast))
+		return
+	}
+
+	utils.WriteAsJSON(contract.NewUsageForecastResponse(fc), c.Writer)
+}
+
+// AddRoutesForForecast attaches consumer usage forecast endpoints to router.
+func AddRoutesForForecast(forecaster forecaster) func(*gin.Engine) error {
+	endpoint := &forecastEndpoint{forecaster: forecaster}
+	return func(e *gin.Engine) error {
+		e.GET("/sessions/forecast", endpoint.Forecast)
+		return nil
+	}
+}
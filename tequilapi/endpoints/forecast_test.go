@@ -0,0 +1,132 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mysteriumnetwork/node/consumer/forecast"
+	"github.com/mysteriumnetwork/node/identity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockForecaster struct {
+	forecast   forecast.Forecast
+	err        error
+	consumerID identity.Identity
+	country    string
+}
+
+func (m *mockForecaster) Forecast(consumerID identity.Identity, country string) (forecast.Forecast, error) {
+	m.consumerID = consumerID
+	m.country = country
+	return m.forecast, m.err
+}
+
+func TestForecastEndpoint_Forecast(t *testing.T) {
+	// given
+	forecaster := &mockForecaster{forecast: forecast.Forecast{
+		Weeks:      4,
+		Data:       1024,
+		Spend:      big.NewInt(300),
+		PaidPerGiB: big.NewInt(100),
+		Advice: forecast.Advice{
+			Country:     "DE",
+			ServiceType: "wireguard",
+			PricePerGiB: big.NewInt(82),
+			Savings:     18,
+			Message:     "Providers at p25 price in DE would have saved 18%",
+		},
+	}}
+	router := summonTestGin()
+	err := AddRoutesForForecast(forecaster)(router)
+	require.NoError(t, err)
+
+	// when
+	resp := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/sessions/forecast?consumer_id=0x1&country=DE", nil)
+	router.ServeHTTP(resp, req)
+
+	// then
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, identity.FromAddress("0x1"), forecaster.consumerID)
+	assert.Equal(t, "DE", forecaster.country)
+	assert.JSONEq(t,
+		`{
+			"history_weeks": 4,
+			"bytes": 1024,
+			"tokens": 300,
+			"paid_price_per_gib": 100,
+			"advice": {
+				"country": "DE",
+				"service_type": "wireguard",
+				"price_per_gib": 82,
+				"savings": 18,
+				"optimal": false,
+				"message": "Providers at p25 price in DE would have saved 18%"
+			}
+		}`,
+		resp.Body.String(),
+	)
+}
+
+func TestForecastEndpoint_ForecastRequiresConsumer(t *testing.T) {
+	// given
+	router := summonTestGin()
+	err := AddRoutesForForecast(&mockForecaster{})(router)
+	require.NoError(t, err)
+
+	// when
+	resp := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/sessions/forecast", nil)
+	router.ServeHTTP(resp, req)
+
+	// then
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
+
+func TestForecastEndpoint_ForecastErrors(t *testing.T) {
+	tests := map[string]struct {
+		err    error
+		status int
+	}{
+		"no history": {err: forecast.ErrNoHistory, status: http.StatusNotFound},
+		"failure":    {err: errors.New("boom"), status: http.StatusInternalServerError},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			// given
+			router := summonTestGin()
+			err := AddRoutesForForecast(&mockForecaster{err: tt.err})(router)
+			require.NoError(t, err)
+
+			// when
+			resp := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/sessions/forecast?consumer_id=0x1", nil)
+			router.ServeHTTP(resp, req)
+
+			// then
+			assert.Equal(t, tt.status, resp.Code)
+		})
+	}
+}
@@ -22,6 +22,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/mysteriumnetwork/node/core/profile"
 	"github.com/mysteriumnetwork/node/metadata"
 	"github.com/mysteriumnetwork/node/tequilapi/contract"
 	"github.com/mysteriumnetwork/node/tequilapi/utils"
@@ -31,21 +32,38 @@ type healthCheckEndpoint struct {
 	startTime       time.Time
 	currentTimeFunc func() time.Time
 	processNumber   int
+	profile         profileProvider
+	watchdog        degradedReporter
+}
+
+type profileProvider interface {
+	Get() profile.Profile
+}
+
+type degradedReporter interface {
+	Degraded() []string
 }
 
 /*
 HealthCheckEndpointFactory creates a structure with single HealthCheck method for healthcheck serving as http,
-currentTimeFunc is injected for easier testing
+currentTimeFunc is injected for easier testing, profile may be nil
 */
-func HealthCheckEndpointFactory(currentTimeFunc func() time.Time, procID func() int) *healthCheckEndpoint {
+func HealthCheckEndpointFactory(currentTimeFunc func() time.Time, procID func() int, profile profileProvider) *healthCheckEndpoint {
 	startTime := currentTimeFunc()
 	return &healthCheckEndpoint{
 		startTime,
 		currentTimeFunc,
 		procID(),
+		profile,
+		nil,
 	}
 }
 
+// SetWatchdog makes health check report the stalled components of the watchdog.
+func (hce *healthCheckEndpoint) SetWatchdog(watchdog degradedReporter) {
+	hce.watchdog = watchdog
+}
+
 // swagger:operation GET /healthcheck Client healthCheck
 //
 //	---
@@ -67,5 +85,13 @@ func (hce *healthCheckEndpoint) HealthCheck(c *gin.Context) {
 			BuildNumber: metadata.BuildNumber,
 		},
 	}
+	if hce.profile != nil {
+		p := hce.profile.Get()
+		status.Nickname = p.Nickname
+		status.AvatarHash = p.AvatarHash
+	}
+	if hce.watchdog != nil {
+		status.DegradedComponents = hce.watchdog.Degraded()
+	}
 	utils.WriteAsJSON(status, c.Writer)
 }
@@ -18,6 +18,7 @@
 package endpoints
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -26,6 +27,7 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"github.com/mysteriumnetwork/node/metadata"
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -45,6 +47,7 @@ func TestHealthCheckReturnsExpectedJSONObject(t *testing.T) {
 	handlerFunc := HealthCheckEndpointFactory(
 		newMockTimer([]time.Time{tick1, tick2}).Now,
 		func() int { return 1 },
+		nil,
 	).HealthCheck
 	g.GET("/healthcheck", handlerFunc)
 
@@ -68,6 +71,29 @@ func TestHealthCheckReturnsExpectedJSONObject(t *testing.T) {
 		resp.Body.String())
 }
 
+func TestHealthCheckReportsDegradedComponents(t *testing.T) {
+	healthCheck := HealthCheckEndpointFactory(time.Now, func() int { return 1 }, nil)
+	healthCheck.SetWatchdog(mockDegradedReporter{"registry-watcher"})
+
+	g := gin.Default()
+	g.GET("/healthcheck", healthCheck.HealthCheck)
+
+	req, err := http.NewRequest("GET", "/healthcheck", nil)
+	assert.NoError(t, err)
+	resp := httptest.NewRecorder()
+	g.ServeHTTP(resp, req)
+
+	var dto contract.HealthCheckDTO
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &dto))
+	assert.Equal(t, []string{"registry-watcher"}, dto.DegradedComponents)
+}
+
+type mockDegradedReporter []string
+
+func (m mockDegradedReporter) Degraded() []string {
+	return m
+}
+
 type mockTimer struct {
 	values  []time.Time
 	current int
@@ -22,12 +22,14 @@ import (
 	"fmt"
 	"math/big"
 	"net/http"
+	"sync"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/gin-gonic/gin"
 	"github.com/mysteriumnetwork/go-rest/apierror"
 	"github.com/mysteriumnetwork/node/consumer/migration"
 	"github.com/mysteriumnetwork/payments/client"
+	payments_crypto "github.com/mysteriumnetwork/payments/crypto"
 	"github.com/pkg/errors"
 
 	"github.com/mysteriumnetwork/node/config"
@@ -35,6 +37,7 @@ import (
 	"github.com/mysteriumnetwork/node/identity"
 	"github.com/mysteriumnetwork/node/identity/registry"
 	identity_selector "github.com/mysteriumnetwork/node/identity/selector"
+	"github.com/mysteriumnetwork/node/session/pingpong"
 	pingpong_event "github.com/mysteriumnetwork/node/session/pingpong/event"
 	"github.com/mysteriumnetwork/node/tequilapi/contract"
 	"github.com/mysteriumnetwork/node/tequilapi/middlewares"
@@ -42,6 +45,9 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
+// maxChannelLookups limits concurrent on-chain channel queries of a single request.
+const maxChannelLookups = 4
+
 type balanceProvider interface {
 	GetBalance(chainID int64, id identity.Identity) *big.Int
 	ForceBalanceUpdateCached(chainID int64, id identity.Identity) *big.Int
@@ -49,6 +55,7 @@ type balanceProvider interface {
 
 type earningsProvider interface {
 	GetEarningsDetailed(chainID int64, id identity.Identity) *pingpong_event.EarningsDetailed
+	List(chainID int64) []pingpong.HermesChannel
 }
 
 type beneficiaryProvider interface {
@@ -77,6 +84,7 @@ type identitiesAPI struct {
 	bprovider          beneficiaryProvider
 	beneficiaryStorage beneficiary.BeneficiaryStorage
 	hermesMigrator     *migration.HermesMigrator
+	passphrasePolicy   identity.PassphrasePolicy
 }
 
 // AddressProvider provides sc addresses.
@@ -194,6 +202,11 @@ func (ia *identitiesAPI) Create(c *gin.Context) {
 		return
 	}
 
+	if err := ia.passphrasePolicy.Validate(*req.Passphrase); err != nil {
+		c.Error(apierror.BadRequestField(err.Error(), contract.ErrCodeIDWeakPassphrase, "passphrase"))
+		return
+	}
+
 	id, err := ia.idm.CreateNewIdentity(*req.Passphrase)
 	if err != nil {
 		c.Error(apierror.Internal("Failed to create ID", contract.ErrCodeIDCreate))
@@ -309,6 +322,77 @@ func (ia *identitiesAPI) Unlock(c *gin.Context) {
 	c.Status(http.StatusAccepted)
 }
 
+// swagger:operation PUT /identities/{id}/passphrase Identity changePassphrase
+//
+//	---
+//	summary: Changes identity passphrase
+//	description: Re-encrypts identity stored in keystore with the new passphrase
+//	parameters:
+//	- in: path
+//	  name: id
+//	  description: Identity stored in keystore
+//	  type: string
+//	  required: true
+//	- in: body
+//	  name: body
+//	  description: Current and new passphrases of the identity
+//	  schema:
+//	    $ref: "#/definitions/IdentityPassphraseChangeRequestDTO"
+//	responses:
+//	  202:
+//	    description: Passphrase changed
+//	  400:
+//	    description: Failed to parse or request validation failed
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  403:
+//	    description: Current passphrase is invalid
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  404:
+//	    description: ID not found
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  500:
+//	    description: Internal server error
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+func (ia *identitiesAPI) ChangePassphrase(c *gin.Context) {
+	address := c.Param("id")
+	id, err := ia.idm.GetIdentity(address)
+	if err != nil {
+		c.Error(apierror.NotFound("ID not found"))
+		return
+	}
+
+	var req contract.IdentityPassphraseChangeRequest
+	if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil {
+		c.Error(apierror.ParseFailed())
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := ia.passphrasePolicy.Validate(*req.NewPassphrase); err != nil {
+		c.Error(apierror.BadRequestField(err.Error(), contract.ErrCodeIDWeakPassphrase, "new_passphrase"))
+		return
+	}
+
+	err = ia.idm.ChangePassphrase(id.Address, *req.CurrentPassphrase, *req.NewPassphrase)
+	if errors.Is(err, identity.ErrInvalidPassphrase) {
+		c.Error(apierror.Forbidden("Current passphrase is invalid", contract.ErrCodeIDPassphraseChange))
+		return
+	}
+	if err != nil {
+		c.Error(apierror.Internal("Failed to change passphrase: "+err.Error(), contract.ErrCodeIDPassphraseChange))
+		return
+	}
+	c.Status(http.StatusAccepted)
+}
+
 // swagger:operation PUT /identities/{id}/balance/refresh Identity balance
 //
 //	---
@@ -438,7 +522,7 @@ func (ia *identitiesAPI) Get(c *gin.Context) {
 //
 //	---
 //	summary: Provide identity registration status
-//	description: Provides registration status for given identity, if identity is not registered - provides additional data required for identity registration
+//	description: Provides registration status for given identity on the active chain, along with the status on both chains if registration on all chains is enabled
 //	parameters:
 //	  - in: path
 //	    name: id
@@ -466,7 +550,8 @@ func (ia *identitiesAPI) RegistrationStatus(c *gin.Context) {
 		return
 	}
 
-	regStatus, err := ia.registry.GetRegistrationStatus(config.GetInt64(config.FlagChainID), id)
+	chainID := config.GetInt64(config.FlagChainID)
+	regStatus, err := ia.registry.GetRegistrationStatus(chainID, id)
 	if err != nil {
 		c.Error(apierror.Internal("Failed to check ID registration status", contract.ErrCodeIDRegistrationCheck))
 		return
@@ -476,6 +561,26 @@ func (ia *identitiesAPI) RegistrationStatus(c *gin.Context) {
 		Status:     regStatus.String(),
 		Registered: regStatus.Registered(),
 	}
+
+	chains := []int64{chainID}
+	if config.GetBool(config.FlagPaymentsRegistrationAllChains) {
+		chains = []int64{config.GetInt64(config.FlagChain1ChainID), config.GetInt64(config.FlagChain2ChainID)}
+	}
+	for _, cid := range chains {
+		status := regStatus
+		if cid != chainID {
+			status, err = ia.registry.GetRegistrationStatus(cid, id)
+			if err != nil {
+				c.Error(apierror.Internal(fmt.Sprintf("Failed to check ID registration status on chain %d", cid), contract.ErrCodeIDRegistrationCheck))
+				return
+			}
+		}
+		registrationDataDTO.Chains = append(registrationDataDTO.Chains, contract.ChainRegistrationDTO{
+			ChainID:    cid,
+			Status:     status.String(),
+			Registered: status.Registered(),
+		})
+	}
 	utils.WriteAsJSON(registrationDataDTO, c.Writer)
 }
 
@@ -521,6 +626,184 @@ func (ia *identitiesAPI) Beneficiary(c *gin.Context) {
 	utils.WriteAsJSON(registrationDataDTO, c.Writer)
 }
 
+// swagger:operation GET /identities/{id}/channels Identity identityChannels
+//
+//	---
+//	summary: Provide identity payment channels
+//	description: Provides payment channels of identity with every known hermes on every configured chain, combining local records with the current on-chain state
+//	parameters:
+//	  - in: path
+//	    name: id
+//	    description: hex address of identity
+//	    type: string
+//	    required: true
+//	responses:
+//	  200:
+//	    description: Payment channels
+//	    schema:
+//	      "$ref": "#/definitions/PaymentChannelsStateResponse"
+//	  404:
+//	    description: ID not found
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  500:
+//	    description: Internal server error
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+func (ia *identitiesAPI) Channels(c *gin.Context) {
+	id, err := ia.idm.GetIdentity(c.Param("id"))
+	if err != nil {
+		c.Error(apierror.NotFound("Identity not found"))
+		return
+	}
+
+	type lookup struct {
+		chainID int64
+		channel pingpong.HermesChannel
+	}
+
+	var lookups []lookup
+	for _, chainID := range configuredChains() {
+		hermeses, err := ia.addressProvider.GetKnownHermeses(chainID)
+		if err != nil {
+			log.Warn().Err(err).Msgf("Could not get known hermeses of chain %d", chainID)
+			continue
+		}
+
+		local := make(map[common.Address]pingpong.HermesChannel)
+		for _, channel := range ia.earningsProvider.List(chainID) {
+			if channel.Identity == id {
+				local[channel.HermesID] = channel
+			}
+		}
+		for hermesID := range local {
+			if !containsAddress(hermeses, hermesID) {
+				hermeses = append(hermeses, hermesID)
+			}
+		}
+
+		for _, hermesID := range hermeses {
+			channel, ok := local[hermesID]
+			if !ok {
+				channelID, err := payments_crypto.GenerateProviderChannelID(id.Address, hermesID.Hex())
+				if err != nil {
+					log.Warn().Err(err).Msgf("Could not calculate channel address of hermes %s", hermesID.Hex())
+					continue
+				}
+				channel = pingpong.HermesChannel{ChannelID: channelID, Identity: id, HermesID: hermesID}
+			}
+			lookups = append(lookups, lookup{chainID: chainID, channel: channel})
+		}
+	}
+
+	// On-chain state is queried concurrently, each lookup being a separate RPC call.
+	channels := make([]contract.PaymentChannelStateDTO, len(lookups))
+	sem := make(chan struct{}, maxChannelLookups)
+	var wg sync.WaitGroup
+	for i, l := range lookups {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, l lookup) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			var onChain *client.ProviderChannel
+			data, err := ia.bc.GetProviderChannel(l.chainID, l.channel.HermesID, id.ToCommonAddress(), false)
+			if err == nil {
+				onChain = &data
+			}
+			channels[i] = contract.NewPaymentChannelStateDTO(l.chainID, l.channel, onChain, err)
+		}(i, l)
+	}
+	wg.Wait()
+
+	utils.WriteAsJSON(contract.PaymentChannelsStateResponse{Channels: channels}, c.Writer)
+}
+
+// swagger:operation GET /identities/{id}/chains Identity identityChains
+//
+//	---
+//	summary: Provide identity state on every chain
+//	description: Provides registration status, channel address, hermes, balance and unsettled earnings of identity on every configured chain. State which could not be fetched is reported per chain.
+//	parameters:
+//	  - in: path
+//	    name: id
+//	    description: hex address of identity
+//	    type: string
+//	    required: true
+//	responses:
+//	  200:
+//	    description: Identity state per chain
+//	    schema:
+//	      "$ref": "#/definitions/IdentityChainsResponse"
+//	  404:
+//	    description: ID not found
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+func (ia *identitiesAPI) Chains(c *gin.Context) {
+	id, err := ia.idm.GetIdentity(c.Param("id"))
+	if err != nil {
+		c.Error(apierror.NotFound("Identity not found"))
+		return
+	}
+
+	activeChainID := config.GetInt64(config.FlagChainID)
+	chains := configuredChains()
+	res := contract.IdentityChainsResponse{Chains: make([]contract.IdentityChainDTO, len(chains))}
+	for i, chainID := range chains {
+		res.Chains[i] = ia.chainState(chainID, id)
+		res.Chains[i].Active = chainID == activeChainID
+	}
+	utils.WriteAsJSON(res, c.Writer)
+}
+
+func (ia *identitiesAPI) chainState(chainID int64, id identity.Identity) contract.IdentityChainDTO {
+	dto := contract.IdentityChainDTO{
+		ChainID:            chainID,
+		RegistrationStatus: registry.Unknown.String(),
+	}
+
+	if status, err := ia.registry.GetRegistrationStatus(chainID, id); err != nil {
+		dto.Errors = append(dto.Errors, "registration status: "+err.Error())
+	} else {
+		dto.RegistrationStatus = status.String()
+	}
+	if channel, err := ia.addressProvider.GetActiveChannelAddress(chainID, id.ToCommonAddress()); err != nil {
+		dto.Errors = append(dto.Errors, "channel address: "+err.Error())
+	} else {
+		dto.ChannelAddress = channel.Hex()
+	}
+	if hermesID, err := ia.addressProvider.GetActiveHermes(chainID); err != nil {
+		dto.Errors = append(dto.Errors, "hermes: "+err.Error())
+	} else {
+		dto.HermesID = hermesID.Hex()
+	}
+
+	dto.BalanceTokens = contract.NewTokens(ia.balanceProvider.GetBalance(chainID, id))
+	earnings := ia.earningsProvider.GetEarningsDetailed(chainID, id)
+	dto.EarningsTokens = contract.NewTokens(earnings.Total.UnsettledBalance)
+	dto.EarningsTotalTokens = contract.NewTokens(earnings.Total.LifetimeBalance)
+	return dto
+}
+
+func configuredChains() []int64 {
+	chain1 := config.GetInt64(config.FlagChain1ChainID)
+	chain2 := config.GetInt64(config.FlagChain2ChainID)
+	if chain1 == chain2 {
+		return []int64{chain1}
+	}
+	return []int64{chain1, chain2}
+}
+
+func containsAddress(addresses []common.Address, address common.Address) bool {
+	for _, a := range addresses {
+		if a == address {
+			return true
+		}
+	}
+	return false
+}
+
 // swagger:operation POST /identities-import Identities importIdentity
 //
 //	---
@@ -561,6 +844,11 @@ func (ia *identitiesAPI) Import(c *gin.Context) {
 		return
 	}
 
+	if err := ia.passphrasePolicy.Validate(req.NewPassphrase); err != nil {
+		c.Error(apierror.BadRequestField(err.Error(), contract.ErrCodeIDWeakPassphrase, "new_passphrase"))
+		return
+	}
+
 	id, err := ia.mover.Import(req.Data, req.CurrentPassphrase, req.NewPassphrase)
 	if err != nil {
 		c.Error(apierror.Unprocessable(fmt.Sprintf("Failed to import identity: %s", err), contract.ErrCodeIDImport))
@@ -676,6 +964,10 @@ func (ia *identitiesAPI) SaveBeneficiaryAddressAsync(c *gin.Context) {
 //	  403:
 //	    schema:
 //	      "$ref": "#/definitions/APIError"
+//	  409:
+//	    description: Migration is already in progress
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
 //	  500:
 //	    description: Internal server error
 //	    schema:
@@ -687,6 +979,10 @@ func (ia *identitiesAPI) MigrateHermes(c *gin.Context) {
 		return
 	}
 	err := ia.hermesMigrator.Start(id)
+	if errors.Is(err, migration.ErrMigrationInProgress) {
+		c.Error(apierror.Conflict(err.Error(), contract.ErrCodeHermesMigration, "id"))
+		return
+	}
 	if err != nil {
 		c.Error(apierror.Internal(err.Error(), contract.ErrCodeHermesMigration))
 		log.Err(err).Msgf("could not migrate identity %s", id)
@@ -735,6 +1031,27 @@ func (ia *identitiesAPI) MigrationHermesStatus(c *gin.Context) {
 	utils.WriteAsJSON(contract.MigrationStatusResponse{Status: status}, c.Writer)
 }
 
+// swagger:operation GET /identities/:id/migrate-hermes/progress
+//
+//	---
+//	summary: Migration Hermes progress
+//	description: Returns the progress of the latest migration from old to new Hermes
+//	parameters:
+//	- in: path
+//	  name: id
+//	  description: Identity stored in keystore
+//	  type: string
+//	  required: true
+//	responses:
+//	  200:
+//	    description: Migration progress
+//	    schema:
+//	      "$ref": "#/definitions/MigrationProgressResponse"
+func (ia *identitiesAPI) MigrationHermesProgress(c *gin.Context) {
+	id := c.Param("id")
+	utils.WriteAsJSON(contract.NewMigrationProgressResponse(ia.hermesMigrator.Progress(id)), c.Writer)
+}
+
 func isBenenficiarySetToChannel(addressProvider addressProvider, chainID int64, identity, beneficiary common.Address) (bool, error) {
 	hermeses, err := addressProvider.GetKnownHermeses(chainID)
 	if err != nil {
@@ -781,6 +1098,10 @@ func AddRoutesForIdentities(
 		bprovider:          bprovider,
 		beneficiaryStorage: addressStorage,
 		hermesMigrator:     hermesMigrator,
+		passphrasePolicy: identity.PassphrasePolicy{
+			MinLength:    config.GetInt(config.FlagKeystorePassphraseMinLength),
+			RequireMixed: config.GetBool(config.FlagKeystorePassphraseRequireMixed),
+		},
 	}
 	return func(e *gin.Engine) error {
 		identityGroup := e.Group("/identities")
@@ -791,13 +1112,17 @@ func AddRoutesForIdentities(
 			identityGroup.GET("/:id", idAPI.Get)
 			identityGroup.GET("/:id/status", idAPI.Get)
 			identityGroup.PUT("/:id/unlock", idAPI.Unlock)
+			identityGroup.PUT("/:id/passphrase", idAPI.ChangePassphrase)
 			identityGroup.GET("/:id/registration", idAPI.RegistrationStatus)
 			identityGroup.GET("/:id/beneficiary", idAPI.Beneficiary)
+			identityGroup.GET("/:id/channels", idAPI.Channels)
+			identityGroup.GET("/:id/chains", idAPI.Chains)
 			identityGroup.GET("/:id/beneficiary-async", idAPI.GetBeneficiaryAddressAsync)
 			identityGroup.POST("/:id/beneficiary-async", idAPI.SaveBeneficiaryAddressAsync)
 			identityGroup.PUT("/:id/balance/refresh", idAPI.BalanceRefresh)
 			identityGroup.POST("/:id/migrate-hermes", idAPI.MigrateHermes)
 			identityGroup.GET("/:id/migrate-hermes/status", idAPI.MigrationHermesStatus)
+			identityGroup.GET("/:id/migrate-hermes/progress", idAPI.MigrationHermesProgress)
 			identityGroup.POST("/export", middlewares.NewLocalhostOnlyFilter(), idAPI.Export)
 
 		}
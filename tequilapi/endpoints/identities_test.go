@@ -19,6 +19,8 @@ package endpoints
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"math/big"
 	"net/http"
@@ -28,11 +30,14 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/gin-gonic/gin"
 	"github.com/mysteriumnetwork/go-rest/apierror"
+	"github.com/mysteriumnetwork/node/config"
 	"github.com/mysteriumnetwork/node/identity"
 	"github.com/mysteriumnetwork/node/identity/registry"
 	"github.com/mysteriumnetwork/node/session/pingpong"
 	pingpongEvent "github.com/mysteriumnetwork/node/session/pingpong/event"
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
 	"github.com/mysteriumnetwork/payments/client"
+	"github.com/mysteriumnetwork/payments/crypto"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -287,6 +292,43 @@ func TestUnlockFailure(t *testing.T) {
 	assert.Equal(t, int64(0), mockIdm.LastUnlockChainID)
 }
 
+func TestChangePassphrase(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		changeErr error
+		wantCode  int
+	}{
+		{"changed", `{"current_passphrase": "old", "new_passphrase": "Strong1passphrase"}`, nil, http.StatusAccepted},
+		{"missing new passphrase", `{"current_passphrase": "old"}`, nil, http.StatusBadRequest},
+		{"weak new passphrase", `{"current_passphrase": "old", "new_passphrase": "weak"}`, nil, http.StatusBadRequest},
+		{"invalid current passphrase", `{"current_passphrase": "wrong", "new_passphrase": "Strong1passphrase"}`, identity.ErrInvalidPassphrase, http.StatusForbidden},
+		{"keystore failure", `{"current_passphrase": "old", "new_passphrase": "Strong1passphrase"}`, errors.New("disk full"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockIdm := identity.NewIdentityManagerFake(existingIdentities, newIdentity)
+			mockIdm.MarkChangePassphraseToFail(tt.changeErr)
+			resp := httptest.NewRecorder()
+			req, err := http.NewRequest(
+				http.MethodPut,
+				fmt.Sprintf("/identities/%s/passphrase", "0x000000000000000000000000000000000000000a"),
+				bytes.NewBufferString(tt.body),
+			)
+			assert.NoError(t, err)
+
+			endpoint := &identitiesAPI{idm: mockIdm, passphrasePolicy: identity.PassphrasePolicy{MinLength: 8, RequireMixed: true}}
+			g := summonTestGin()
+			g.PUT("/identities/:id/passphrase", endpoint.ChangePassphrase)
+
+			g.ServeHTTP(resp, req)
+
+			assert.Equal(t, tt.wantCode, resp.Code)
+		})
+	}
+}
+
 func TestCreateNewIdentityEmptyPassphrase(t *testing.T) {
 	mockIdm := identity.NewIdentityManagerFake(existingIdentities, newIdentity)
 	resp := httptest.NewRecorder()
@@ -488,6 +530,125 @@ func Test_IdentityGet(t *testing.T) {
 		resp.Body.String())
 }
 
+func Test_IdentityChains(t *testing.T) {
+	config.Current.SetUser(config.FlagChain1ChainID.Name, int64(1))
+	config.Current.SetUser(config.FlagChain2ChainID.Name, int64(2))
+	config.Current.SetUser(config.FlagChainID.Name, int64(2))
+	defer func() {
+		config.Current.RemoveUser(config.FlagChain1ChainID.Name)
+		config.Current.RemoveUser(config.FlagChain2ChainID.Name)
+		config.Current.RemoveUser(config.FlagChainID.Name)
+	}()
+
+	endpoint := &identitiesAPI{
+		idm:      identity.NewIdentityManagerFake(existingIdentities, newIdentity),
+		registry: &registry.FakeRegistry{RegistrationCheckError: errors.New("rpc unavailable")},
+		addressProvider: &mockAddressProvider{
+			channelAddressToReturn: common.HexToAddress("0x100000000000000000000000000000000000000a"),
+			hermesToReturn:         common.HexToAddress("0x200000000000000000000000000000000000000a"),
+		},
+		earningsProvider: &mockEarningsProvider{
+			earnings: pingpongEvent.EarningsDetailed{
+				Total: pingpongEvent.Earnings{
+					LifetimeBalance:  big.NewInt(100),
+					UnsettledBalance: big.NewInt(50),
+				},
+			},
+		},
+		balanceProvider: &mockBalanceProvider{
+			balance: big.NewInt(25),
+		},
+	}
+
+	router := summonTestGin()
+	router.GET("/identities/:id/chains", endpoint.Chains)
+
+	req, err := http.NewRequest(http.MethodGet, "/identities/0x000000000000000000000000000000000000000a/chains", nil)
+	assert.Nil(t, err)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	chain := func(chainID int64, active bool) string {
+		return fmt.Sprintf(`{
+			"chain_id": %d,
+			"active": %t,
+			"registration_status": "Unknown",
+			"channel_address": "0x100000000000000000000000000000000000000A",
+			"hermes_id": "0x200000000000000000000000000000000000000A",
+			"balance_tokens": {"wei": "25", "ether": "0.000000000000000025", "human": "0"},
+			"earnings_tokens": {"wei": "50", "ether": "0.00000000000000005", "human": "0"},
+			"earnings_total_tokens": {"wei": "100", "ether": "0.0000000000000001", "human": "0"},
+			"errors": ["registration status: rpc unavailable"]
+		}`, chainID, active)
+	}
+	assert.JSONEq(t, `{"chains": [`+chain(1, false)+`,`+chain(2, true)+`]}`, resp.Body.String())
+
+	req, err = http.NewRequest(http.MethodGet, "/identities/0x00000000000000000000000000000000000000ff/chains", nil)
+	assert.Nil(t, err)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+}
+
+func Test_IdentityChannels(t *testing.T) {
+	id := identity.FromAddress("0x000000000000000000000000000000000000000a")
+	hermesID := common.HexToAddress("0x200000000000000000000000000000000000000a")
+	promise := pingpong.HermesPromise{
+		Promise:  crypto.Promise{Amount: big.NewInt(10), Fee: big.NewInt(1), Hashlock: []byte{0xab}},
+		Revealed: true,
+	}
+	endpoint := &identitiesAPI{
+		idm: identity.NewIdentityManagerFake(existingIdentities, newIdentity),
+		addressProvider: &mockAddressProvider{
+			hermesToReturn: hermesID,
+		},
+		bc: &mockProviderChannelStatusProvider{
+			channelToReturn: client.ProviderChannel{
+				Settled:       big.NewInt(4),
+				Stake:         big.NewInt(2),
+				LastUsedNonce: big.NewInt(3),
+				Timelock:      big.NewInt(0),
+			},
+		},
+		earningsProvider: &mockEarningsProvider{
+			channels: []pingpong.HermesChannel{
+				pingpong.NewHermesChannel("0x1", id, hermesID, client.ProviderChannel{Settled: big.NewInt(1)}, promise, common.Address{}),
+			},
+		},
+	}
+
+	router := gin.Default()
+	router.GET("/identities/:id/channels", endpoint.Channels)
+
+	req, err := http.NewRequest(http.MethodGet, "/identities/0x000000000000000000000000000000000000000a/channels", nil)
+	assert.NoError(t, err)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	var res contract.PaymentChannelsStateResponse
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &res))
+	assert.NotEmpty(t, res.Channels)
+
+	channel := res.Channels[0]
+	assert.Equal(t, "0x1", channel.ID)
+	assert.Equal(t, hermesID.Hex(), channel.HermesID)
+	assert.Equal(t, "1", channel.Local.Settled.Wei)
+	assert.Equal(t, "4", channel.OnChain.Settled.Wei)
+	assert.Equal(t, big.NewInt(3), channel.OnChain.Nonce)
+	assert.Equal(t, "9", channel.Unsettled.Wei)
+	assert.Equal(t, "10", channel.LatestPromise.Amount.Wei)
+	assert.Equal(t, "0xab", channel.LatestPromise.Hashlock)
+	assert.True(t, channel.LatestPromise.Revealed)
+
+	req, err = http.NewRequest(http.MethodGet, "/identities/0x00000000000000000000000000000000000000ff/channels", nil)
+	assert.NoError(t, err)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+}
+
 type mockAddressProvider struct {
 	hermesToReturn         common.Address
 	registryToReturn       common.Address
@@ -0,0 +1,171 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mysteriumnetwork/go-rest/apierror"
+
+	"github.com/mysteriumnetwork/node/config"
+	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/identity/backup"
+	identity_selector "github.com/mysteriumnetwork/node/identity/selector"
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
+	"github.com/mysteriumnetwork/node/tequilapi/middlewares"
+	"github.com/mysteriumnetwork/node/tequilapi/utils"
+)
+
+type identityBackup interface {
+	Export(address, currPass, passphrase string) ([]byte, error)
+	Import(data []byte, passphrase, newPass string) (backup.Restored, error)
+}
+
+type identityBackupEndpoint struct {
+	backup           identityBackup
+	selector         identity_selector.Handler
+	passphrasePolicy identity.PassphrasePolicy
+}
+
+// swagger:operation POST /identities/{id}/backup Identity backupIdentity
+//
+//	---
+//	summary: Exports identity bundle
+//	description: Exports identity key, beneficiary and registration statuses as a bundle encrypted with the passphrase, which can be restored on another node
+//	parameters:
+//	- in: path
+//	  name: id
+//	  description: Identity stored in keystore
+//	  type: string
+//	  required: true
+//	- in: body
+//	  name: body
+//	  description: Passphrase to encrypt the bundle with
+//	  schema:
+//	    $ref: "#/definitions/IdentityBackupRequestDTO"
+//	responses:
+//	  200:
+//	    description: Encrypted identity bundle
+//	    schema:
+//	      "$ref": "#/definitions/IdentityBackupResponseDTO"
+//	  400:
+//	    description: Failed to parse or request validation failed
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  500:
+//	    description: Internal server error
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+func (e *identityBackupEndpoint) Backup(c *gin.Context) {
+	var req contract.IdentityBackupRequest
+	if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil {
+		c.Error(apierror.ParseFailed())
+		return
+	}
+	if err := req.Validate(); err != nil {
+		c.Error(err)
+		return
+	}
+
+	data, err := e.backup.Export(c.Param("id"), req.CurrentPassphrase, req.Passphrase)
+	if err != nil {
+		c.Error(apierror.Internal("Failed to export identity: "+err.Error(), contract.ErrCodeIDBackup))
+		return
+	}
+
+	utils.WriteAsJSON(contract.IdentityBackupResponse{Data: data}, c.Writer)
+}
+
+// swagger:operation POST /identities-restore Identities restoreIdentity
+//
+//	---
+//	summary: Restores identity bundle
+//	description: Imports identity from the bundle exported by the backup endpoint, restores its beneficiary and checks its registration status again
+//	parameters:
+//	- in: body
+//	  name: body
+//	  description: Bundle and its passphrase
+//	  schema:
+//	    $ref: "#/definitions/IdentityRestoreRequestDTO"
+//	responses:
+//	  200:
+//	    description: Restored identity
+//	    schema:
+//	      "$ref": "#/definitions/IdentityRestoreResponseDTO"
+//	  400:
+//	    description: Failed to parse or request validation failed
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  422:
+//	    description: Unable to process the request at this point
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+func (e *identityBackupEndpoint) Restore(c *gin.Context) {
+	var req contract.IdentityRestoreRequest
+	if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil {
+		c.Error(apierror.ParseFailed())
+		return
+	}
+	if err := req.Validate(); err != nil {
+		c.Error(err)
+		return
+	}
+	if err := e.passphrasePolicy.Validate(req.NewPassphrase); err != nil {
+		c.Error(apierror.BadRequestField(err.Error(), contract.ErrCodeIDWeakPassphrase, "new_passphrase"))
+		return
+	}
+
+	restored, err := e.backup.Import(req.Data, req.Passphrase, req.NewPassphrase)
+	if errors.Is(err, backup.ErrDecrypt) || errors.Is(err, backup.ErrUnsupportedBundle) {
+		c.Error(apierror.BadRequestField(err.Error(), contract.ErrCodeIDRestore, "data"))
+		return
+	}
+	if err != nil {
+		c.Error(apierror.Unprocessable(fmt.Sprintf("Failed to restore identity: %s", err), contract.ErrCodeIDRestore))
+		return
+	}
+
+	if req.SetDefault {
+		if err := e.selector.SetDefault(restored.Identity.Address); err != nil {
+			c.Error(apierror.Unprocessable(fmt.Sprintf("Failed to set default identity: %s", err), contract.ErrCodeIDSetDefault))
+			return
+		}
+	}
+
+	utils.WriteAsJSON(contract.NewIdentityRestoreResponse(restored), c.Writer)
+}
+
+// AddRoutesForIdentityBackup attaches identity backup and restore endpoints to router.
+func AddRoutesForIdentityBackup(idBackup identityBackup, selector identity_selector.Handler) func(*gin.Engine) error {
+	endpoint := &identityBackupEndpoint{
+		backup:   idBackup,
+		selector: selector,
+		passphrasePolicy: identity.PassphrasePolicy{
+			MinLength:    config.GetInt(config.FlagKeystorePassphraseMinLength),
+			RequireMixed: config.GetBool(config.FlagKeystorePassphraseRequireMixed),
+		},
+	}
+	return func(e *gin.Engine) error {
+		e.POST("/identities/:id/backup", middlewares.NewLocalhostOnlyFilter(), endpoint.Backup)
+		e.POST("/identities-restore", endpoint.Restore)
+		return nil
+	}
+}
@@ -0,0 +1,117 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/identity/backup"
+	"github.com/mysteriumnetwork/node/identity/registry"
+)
+
+type mockIdentityBackup struct {
+	address    string
+	passphrase string
+}
+
+func (m *mockIdentityBackup) Export(address, _, passphrase string) ([]byte, error) {
+	m.address = address
+	m.passphrase = passphrase
+	return []byte("bundle"), nil
+}
+
+func (m *mockIdentityBackup) Import(data []byte, passphrase, _ string) (backup.Restored, error) {
+	if string(data) != "bundle" || passphrase != m.passphrase {
+		return backup.Restored{}, backup.ErrDecrypt
+	}
+	return backup.Restored{
+		Identity:      identity.FromAddress(m.address),
+		Registrations: []backup.Registration{{ChainID: 137, Status: registry.Registered}},
+	}, nil
+}
+
+func TestIdentityBackupEndpoint_BackupRestore(t *testing.T) {
+	// given
+	idBackup := &mockIdentityBackup{}
+	router := summonTestGin()
+	err := AddRoutesForIdentityBackup(idBackup, &selectorFake{})(router)
+	require.NoError(t, err)
+
+	// when
+	resp := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/identities/0x1/backup", strings.NewReader(`{"passphrase": "secret"}`))
+	req.RemoteAddr = "127.0.0.1:4050"
+	router.ServeHTTP(resp, req)
+
+	// then
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "0x1", idBackup.address)
+	assert.JSONEq(t, `{"data": "YnVuZGxl"}`, resp.Body.String())
+
+	// when
+	resp = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodPost, "/identities-restore", strings.NewReader(`{"data": "YnVuZGxl", "passphrase": "secret"}`))
+	router.ServeHTTP(resp, req)
+
+	// then
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t,
+		`{
+			"id": "0x1",
+			"registrations": [{"chain_id": 137, "status": "Registered", "registered": true}]
+		}`,
+		resp.Body.String(),
+	)
+}
+
+func TestIdentityBackupEndpoint_RestoreWrongPassphrase(t *testing.T) {
+	// given
+	router := summonTestGin()
+	err := AddRoutesForIdentityBackup(&mockIdentityBackup{passphrase: "secret"}, &selectorFake{})(router)
+	require.NoError(t, err)
+
+	// when
+	resp := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/identities-restore", strings.NewReader(`{"data": "YnVuZGxl", "passphrase": "wrong"}`))
+	router.ServeHTTP(resp, req)
+
+	// then
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
+
+func TestIdentityBackupEndpoint_BackupLocalhostOnly(t *testing.T) {
+	// given
+	router := summonTestGin()
+	err := AddRoutesForIdentityBackup(&mockIdentityBackup{}, &selectorFake{})(router)
+	require.NoError(t, err)
+
+	// when
+	resp := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/identities/0x1/backup", strings.NewReader(`{"passphrase": "secret"}`))
+	router.ServeHTTP(resp, req)
+
+	// then
+	assert.Equal(t, http.StatusForbidden, resp.Code)
+}
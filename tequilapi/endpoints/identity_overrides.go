@@ -0,0 +1,160 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mysteriumnetwork/go-rest/apierror"
+
+	"github.com/mysteriumnetwork/node/config"
+	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/identity/overrides"
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
+	"github.com/mysteriumnetwork/node/tequilapi/utils"
+)
+
+type identityOverridesStorage interface {
+	Get(id identity.Identity) (overrides.Overrides, error)
+	Set(id identity.Identity, o overrides.Overrides) error
+	Remove(id identity.Identity) error
+}
+
+type identityOverridesEndpoint struct {
+	storage identityOverridesStorage
+}
+
+// swagger:operation GET /identities/{id}/overrides Identity getIdentityOverrides
+//
+//	---
+//	summary: Returns identity overrides
+//	description: Returns payment and registration settings overridden by the identity. Settings which are not overridden fall back to the global configuration.
+//	parameters:
+//	- in: path
+//	  name: id
+//	  description: Identity
+//	  type: string
+//	  required: true
+//	responses:
+//	  200:
+//	    description: Identity overrides, empty if there are none
+//	    schema:
+//	      "$ref": "#/definitions/IdentityOverridesDTO"
+//	  500:
+//	    description: Internal server error
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+func (e *identityOverridesEndpoint) Get(c *gin.Context) {
+	o, err := e.storage.Get(identity.FromAddress(c.Param("id")))
+	if err != nil && !errors.Is(err, overrides.ErrNotFound) {
+		c.Error(apierror.Internal("Could not get identity overrides: "+err.Error(), contract.ErrCodeIDOverrides))
+		return
+	}
+
+	utils.WriteAsJSON(contract.NewIdentityOverridesDTO(o), c.Writer)
+}
+
+// swagger:operation PUT /identities/{id}/overrides Identity setIdentityOverrides
+//
+//	---
+//	summary: Changes identity overrides
+//	description: Replaces payment and registration settings overridden by the identity. Beneficiary is used when registering the identity and settling its earnings, settlement threshold triggers automatic settlement and chain is the one the identity is registered on while providing services.
+//	parameters:
+//	- in: path
+//	  name: id
+//	  description: Identity
+//	  type: string
+//	  required: true
+//	- in: body
+//	  name: body
+//	  schema:
+//	    $ref: "#/definitions/IdentityOverridesDTO"
+//	responses:
+//	  200:
+//	    description: Identity overrides changed
+//	    schema:
+//	      "$ref": "#/definitions/IdentityOverridesDTO"
+//	  400:
+//	    description: Failed to parse or request validation failed
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  500:
+//	    description: Internal server error
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+func (e *identityOverridesEndpoint) Set(c *gin.Context) {
+	var req contract.IdentityOverridesDTO
+	if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil {
+		c.Error(apierror.ParseFailed())
+		return
+	}
+	chains := []int64{config.GetInt64(config.FlagChain1ChainID), config.GetInt64(config.FlagChain2ChainID)}
+	if err := req.Validate(chains); err != nil {
+		c.Error(err)
+		return
+	}
+
+	id := identity.FromAddress(c.Param("id"))
+	if err := e.storage.Set(id, req.Overrides()); err != nil {
+		c.Error(apierror.Internal("Could not save identity overrides: "+err.Error(), contract.ErrCodeIDOverrides))
+		return
+	}
+
+	e.Get(c)
+}
+
+// swagger:operation DELETE /identities/{id}/overrides Identity removeIdentityOverrides
+//
+//	---
+//	summary: Removes identity overrides
+//	description: Removes all settings overridden by the identity, so that the global configuration is used again
+//	parameters:
+//	- in: path
+//	  name: id
+//	  description: Identity
+//	  type: string
+//	  required: true
+//	responses:
+//	  204:
+//	    description: Identity overrides removed
+//	  500:
+//	    description: Internal server error
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+func (e *identityOverridesEndpoint) Remove(c *gin.Context) {
+	if err := e.storage.Remove(identity.FromAddress(c.Param("id"))); err != nil {
+		c.Error(apierror.Internal("Could not remove identity overrides: "+err.Error(), contract.ErrCodeIDOverrides))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// AddRoutesForIdentityOverrides attaches identity overrides endpoints to router.
+func AddRoutesForIdentityOverrides(storage identityOverridesStorage) func(*gin.Engine) error {
+	endpoint := &identityOverridesEndpoint{storage: storage}
+	return func(e *gin.Engine) error {
+		e.GET("/identities/:id/overrides", endpoint.Get)
+		e.PUT("/identities/:id/overrides", endpoint.Set)
+		e.DELETE("/identities/:id/overrides", endpoint.Remove)
+		return nil
+	}
+}
@@ -0,0 +1,131 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mysteriumnetwork/node/config"
+	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/identity/overrides"
+)
+
+type mockIdentityOverridesStorage struct {
+	overrides map[identity.Identity]overrides.Overrides
+}
+
+func (m *mockIdentityOverridesStorage) Get(id identity.Identity) (overrides.Overrides, error) {
+	o, ok := m.overrides[id]
+	if !ok {
+		return overrides.Overrides{}, overrides.ErrNotFound
+	}
+	return o, nil
+}
+
+func (m *mockIdentityOverridesStorage) Set(id identity.Identity, o overrides.Overrides) error {
+	m.overrides[id] = o
+	return nil
+}
+
+func (m *mockIdentityOverridesStorage) Remove(id identity.Identity) error {
+	delete(m.overrides, id)
+	return nil
+}
+
+func TestIdentityOverridesEndpoint(t *testing.T) {
+	// given
+	config.Current.SetUser(config.FlagChain1ChainID.Name, int64(1))
+	config.Current.SetUser(config.FlagChain2ChainID.Name, int64(137))
+	defer func() {
+		config.Current.RemoveUser(config.FlagChain1ChainID.Name)
+		config.Current.RemoveUser(config.FlagChain2ChainID.Name)
+	}()
+
+	storage := &mockIdentityOverridesStorage{overrides: make(map[identity.Identity]overrides.Overrides)}
+	router := summonTestGin()
+	err := AddRoutesForIdentityOverrides(storage)(router)
+	require.NoError(t, err)
+
+	// when
+	resp := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/identities/0x1/overrides", nil)
+	router.ServeHTTP(resp, req)
+
+	// then
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `{}`, resp.Body.String())
+
+	// when
+	resp = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodPut, "/identities/0x1/overrides", strings.NewReader(`{"beneficiary": "0x0000000000000000000000000000000000000001", "settle_threshold": 2.5, "chain_id": 137}`))
+	router.ServeHTTP(resp, req)
+
+	// then
+	assert.Equal(t, http.StatusOK, resp.Code)
+	o := storage.overrides[identity.FromAddress("0x1")]
+	assert.Equal(t, "0x0000000000000000000000000000000000000001", o.Beneficiary)
+	require.NotNil(t, o.SettleThreshold)
+	assert.Equal(t, 2.5, *o.SettleThreshold)
+	assert.Equal(t, int64(137), o.ChainID)
+	assert.Contains(t, resp.Body.String(), `"chain_id":137`)
+
+	// when
+	resp = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodDelete, "/identities/0x1/overrides", nil)
+	router.ServeHTTP(resp, req)
+
+	// then
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+	assert.Empty(t, storage.overrides)
+}
+
+func TestIdentityOverridesEndpoint_Validation(t *testing.T) {
+	config.Current.SetUser(config.FlagChain1ChainID.Name, int64(1))
+	config.Current.SetUser(config.FlagChain2ChainID.Name, int64(137))
+	defer func() {
+		config.Current.RemoveUser(config.FlagChain1ChainID.Name)
+		config.Current.RemoveUser(config.FlagChain2ChainID.Name)
+	}()
+
+	for name, body := range map[string]string{
+		"invalid beneficiary":           `{"beneficiary": "0xnope"}`,
+		"negative settle threshold":     `{"settle_threshold": -1}`,
+		"chain which is not configured": `{"chain_id": 5}`,
+		"malformed body":                `{`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			storage := &mockIdentityOverridesStorage{overrides: make(map[identity.Identity]overrides.Overrides)}
+			router := summonTestGin()
+			err := AddRoutesForIdentityOverrides(storage)(router)
+			require.NoError(t, err)
+
+			resp := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPut, "/identities/0x1/overrides", strings.NewReader(body))
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, http.StatusBadRequest, resp.Code)
+			assert.Empty(t, storage.overrides)
+		})
+	}
+}
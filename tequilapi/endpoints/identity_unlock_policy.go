@@ -0,0 +1,118 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mysteriumnetwork/go-rest/apierror"
+
+	"github.com/mysteriumnetwork/node/identity/unlock"
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
+	"github.com/mysteriumnetwork/node/tequilapi/utils"
+)
+
+type unlockPolicyManager interface {
+	Policy() unlock.Policy
+	SetPolicy(policy unlock.Policy, creds []unlock.Credential) error
+}
+
+type identityUnlockPolicyEndpoint struct {
+	manager unlockPolicyManager
+}
+
+// swagger:operation GET /identities-unlock-policy Identities getUnlockPolicy
+//
+//	---
+//	summary: Returns identity unlock policy
+//	description: Returns when identities are unlocked and after how long idle identities are locked again
+//	responses:
+//	  200:
+//	    description: Active identity unlock policy
+//	    schema:
+//	      "$ref": "#/definitions/IdentityUnlockPolicyDTO"
+func (e *identityUnlockPolicyEndpoint) Get(c *gin.Context) {
+	utils.WriteAsJSON(contract.NewIdentityUnlockPolicyDTO(e.manager.Policy()), c.Writer)
+}
+
+// swagger:operation PUT /identities-unlock-policy Identities setUnlockPolicy
+//
+//	---
+//	summary: Changes identity unlock policy
+//	description: Changes and persists the identity unlock policy. The startup policy unlocks identities with the given credentials, which are stored encrypted and used to unlock the identities when the node starts. Previously stored credentials are used if none are given.
+//	parameters:
+//	- in: body
+//	  name: body
+//	  schema:
+//	    $ref: "#/definitions/IdentityUnlockPolicyRequestDTO"
+//	responses:
+//	  200:
+//	    description: Identity unlock policy changed
+//	    schema:
+//	      "$ref": "#/definitions/IdentityUnlockPolicyDTO"
+//	  400:
+//	    description: Failed to parse or request validation failed
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  422:
+//	    description: Policy is set by command line flag, or identities could not be unlocked with the credentials
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  500:
+//	    description: Internal server error
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+func (e *identityUnlockPolicyEndpoint) Set(c *gin.Context) {
+	var req contract.IdentityUnlockPolicyRequest
+	if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil {
+		c.Error(apierror.ParseFailed())
+		return
+	}
+	if err := req.Validate(); err != nil {
+		c.Error(err)
+		return
+	}
+
+	policy, creds := req.Policy()
+	err := e.manager.SetPolicy(policy, creds)
+	switch {
+	case errors.Is(err, unlock.ErrPolicyFlagSet):
+		c.Error(apierror.Unprocessable(err.Error(), contract.ErrCodeIDUnlockPolicyFlagSet))
+		return
+	case errors.Is(err, unlock.ErrInvalidCredentials), errors.Is(err, unlock.ErrNoCredentials):
+		c.Error(apierror.Unprocessable(err.Error(), contract.ErrCodeIDUnlockPolicy))
+		return
+	case err != nil:
+		c.Error(apierror.Internal("Could not change identity unlock policy: "+err.Error(), contract.ErrCodeIDUnlockPolicy))
+		return
+	}
+
+	utils.WriteAsJSON(contract.NewIdentityUnlockPolicyDTO(e.manager.Policy()), c.Writer)
+}
+
+// AddRoutesForIdentityUnlockPolicy attaches identity unlock policy endpoints to router.
+func AddRoutesForIdentityUnlockPolicy(manager unlockPolicyManager) func(*gin.Engine) error {
+	endpoint := &identityUnlockPolicyEndpoint{manager: manager}
+	return func(e *gin.Engine) error {
+		e.GET("/identities-unlock-policy", endpoint.Get)
+		e.PUT("/identities-unlock-policy", endpoint.Set)
+		return nil
+	}
+}
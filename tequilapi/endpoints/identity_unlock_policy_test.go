@@ -0,0 +1,119 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mysteriumnetwork/node/identity/unlock"
+)
+
+type mockUnlockPolicyManager struct {
+	policy unlock.Policy
+	creds  []unlock.Credential
+	err    error
+}
+
+func (m *mockUnlockPolicyManager) Policy() unlock.Policy {
+	return m.policy
+}
+
+func (m *mockUnlockPolicyManager) SetPolicy(policy unlock.Policy, creds []unlock.Credential) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.policy = policy
+	m.creds = creds
+	return nil
+}
+
+func TestIdentityUnlockPolicyEndpoint_GetSet(t *testing.T) {
+	// given
+	manager := &mockUnlockPolicyManager{policy: unlock.Policy{Mode: unlock.ModeOnDemand, IdleTimeout: 15 * time.Minute}}
+	router := summonTestGin()
+	err := AddRoutesForIdentityUnlockPolicy(manager)(router)
+	require.NoError(t, err)
+
+	// when
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/identities-unlock-policy", nil))
+
+	// then
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `{"mode": "on-demand", "idle_timeout_seconds": 900}`, resp.Body.String())
+
+	// when
+	resp = httptest.NewRecorder()
+	req := httptest.NewRequest(
+		http.MethodPut,
+		"/identities-unlock-policy",
+		strings.NewReader(`{"mode": "startup", "idle_timeout_seconds": 60, "credentials": [{"id": "0x1", "passphrase": "secret"}]}`),
+	)
+	router.ServeHTTP(resp, req)
+
+	// then
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `{"mode": "startup", "idle_timeout_seconds": 60}`, resp.Body.String())
+	assert.Equal(t, []unlock.Credential{{Address: "0x1", Passphrase: "secret"}}, manager.creds)
+}
+
+func TestIdentityUnlockPolicyEndpoint_SetFailures(t *testing.T) {
+	tests := map[string]struct {
+		body     string
+		err      error
+		wantCode int
+	}{
+		"unknown mode": {
+			body:     `{"mode": "always"}`,
+			wantCode: http.StatusBadRequest,
+		},
+		"credentials with on-demand policy": {
+			body:     `{"mode": "on-demand", "credentials": [{"id": "0x1"}]}`,
+			wantCode: http.StatusBadRequest,
+		},
+		"set by flag": {
+			body:     `{"mode": "startup"}`,
+			err:      unlock.ErrPolicyFlagSet,
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		"wrong credentials": {
+			body:     `{"mode": "startup", "credentials": [{"id": "0x1", "passphrase": "wrong"}]}`,
+			err:      unlock.ErrInvalidCredentials,
+			wantCode: http.StatusUnprocessableEntity,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			router := summonTestGin()
+			err := AddRoutesForIdentityUnlockPolicy(&mockUnlockPolicyManager{err: tt.err})(router)
+			require.NoError(t, err)
+
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, httptest.NewRequest(http.MethodPut, "/identities-unlock-policy", strings.NewReader(tt.body)))
+
+			assert.Equal(t, tt.wantCode, resp.Code)
+		})
+	}
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/mysteriumnetwork/go-rest/apierror"
 
 	"github.com/mysteriumnetwork/node/nat"
+	"github.com/mysteriumnetwork/node/nat/behavior"
 	"github.com/mysteriumnetwork/node/tequilapi/contract"
 	"github.com/mysteriumnetwork/node/tequilapi/utils"
 )
@@ -34,21 +35,27 @@ import (
 type NATEndpoint struct {
 	stateProvider stateProvider
 	natProber     natProber
+	natStatus     natStatusProvider
 }
 
 type natProber interface {
 	Probe(context.Context) (nat.NATType, error)
 }
 
+type natStatusProvider interface {
+	Status() behavior.Status
+}
+
 type nodeStatusProvider interface {
 	Status() monitoring.Status
 }
 
 // NewNATEndpoint creates and returns nat endpoint
-func NewNATEndpoint(stateProvider stateProvider, natProber natProber) *NATEndpoint {
+func NewNATEndpoint(stateProvider stateProvider, natProber natProber, natStatus natStatusProvider) *NATEndpoint {
 	return &NATEndpoint{
 		stateProvider: stateProvider,
 		natProber:     natProber,
+		natStatus:     natStatus,
 	}
 }
 
@@ -79,15 +86,36 @@ func (ne *NATEndpoint) NATType(c *gin.Context) {
 	}, c.Writer)
 }
 
+// NATStatusV2 provides NAT type along with outcomes of NAT traversal methods
+// swagger:operation GET /v2/nat/status NAT NATStatusV2DTO
+//
+//	---
+//	summary: Shows NAT type and NAT traversal details.
+//	description: Returns NAT type found by the last successful probe, time and error of the last probe,
+//	  the traversal method which succeeded most recently and the last outcome of every attempted method.
+//	  Does not probe NAT, see /nat/type for that.
+//	responses:
+//	  200:
+//	    description: NAT status
+//	    schema:
+//	      "$ref": "#/definitions/NATStatusV2DTO"
+func (ne *NATEndpoint) NATStatusV2(c *gin.Context) {
+	utils.WriteAsJSON(contract.NewNATStatusV2DTO(ne.natStatus.Status()), c.Writer)
+}
+
 // AddRoutesForNAT adds nat routes to given router
-func AddRoutesForNAT(stateProvider stateProvider, natProber natProber) func(*gin.Engine) error {
-	natEndpoint := NewNATEndpoint(stateProvider, natProber)
+func AddRoutesForNAT(stateProvider stateProvider, natProber natProber, natStatus natStatusProvider) func(*gin.Engine) error {
+	natEndpoint := NewNATEndpoint(stateProvider, natProber, natStatus)
 
 	return func(e *gin.Engine) error {
 		v1Group := e.Group("/nat")
 		{
 			v1Group.GET("/type", natEndpoint.NATType)
 		}
+		v2Group := e.Group("/v2/nat")
+		{
+			v2Group.GET("/status", natEndpoint.NATStatusV2)
+		}
 		return nil
 	}
 }
@@ -0,0 +1,57 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/eventbus"
+	"github.com/mysteriumnetwork/node/nat"
+	"github.com/mysteriumnetwork/node/nat/behavior"
+	"github.com/mysteriumnetwork/node/nat/event"
+)
+
+func TestNATStatusV2(t *testing.T) {
+	tracker := behavior.NewStatusTracker()
+	bus := eventbus.New()
+	assert.NoError(t, tracker.Subscribe(bus))
+
+	probed := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	bus.Publish(behavior.AppTopicNATProbed, behavior.AppEventNATProbed{Type: nat.NATTypePortRestrictedCone, Time: probed})
+	bus.Publish(event.AppTopicTraversal, event.Event{Stage: "port_mapping", Method: "upnp", Error: errors.New("no gateway")})
+
+	router := gin.Default()
+	err := AddRoutesForNAT(nil, nil, tracker)(router)
+	assert.NoError(t, err)
+
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/v2/nat/status", nil))
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"type":"prcone"`)
+	assert.Contains(t, resp.Body.String(), `"last_probe_at":"2024-05-01T12:00:00Z"`)
+	assert.Contains(t, resp.Body.String(), `"method":"upnp","successful":false,"error":"no gateway"`)
+	assert.NotContains(t, resp.Body.String(), "last_successful_method")
+}
@@ -29,6 +29,7 @@ import (
 	"github.com/mysteriumnetwork/payments/units"
 
 	"github.com/mysteriumnetwork/node/core/node"
+	"github.com/mysteriumnetwork/node/p2p"
 	"github.com/mysteriumnetwork/node/tequilapi/contract"
 	"github.com/mysteriumnetwork/node/tequilapi/launchpad"
 	"github.com/mysteriumnetwork/node/tequilapi/utils"
@@ -551,6 +552,27 @@ func (ne *NodeEndpoint) GetProviderServiceEarnings(c *gin.Context) {
 	utils.WriteAsJSON(data, c.Writer)
 }
 
+// GetRejectedControlMessages returns numbers of rejected p2p control messages
+// swagger:operation GET /node/p2p/rejected-control-messages provider GetRejectedControlMessages
+//
+//	---
+//	summary: Provides numbers of rejected p2p control messages
+//	description: Numbers of unsigned, invalidly signed, stale and replayed p2p control messages rejected since node start
+//	responses:
+//	  200:
+//	    description: Rejected control messages by reason
+//	    schema:
+//	      "$ref": "#/definitions/RejectedControlMessagesResponse"
+func (ne *NodeEndpoint) GetRejectedControlMessages(c *gin.Context) {
+	counters := p2p.RejectedControlMessages()
+	utils.WriteAsJSON(contract.RejectedControlMessagesResponse{
+		Unsigned:         counters.Unsigned,
+		InvalidSignature: counters.InvalidSignature,
+		Stale:            counters.Stale,
+		Replayed:         counters.Replayed,
+	}, c.Writer)
+}
+
 // AddRoutesForNode adds nat routes to given router
 func AddRoutesForNode(nodeStatusProvider nodeStatusProvider, nodeMonitoringAgent nodeMonitoringAgent) func(*gin.Engine) error {
 	nodeEndpoints := NewNodeEndpoint(nodeStatusProvider, nodeMonitoringAgent)
@@ -571,6 +593,7 @@ func AddRoutesForNode(nodeStatusProvider nodeStatusProvider, nodeMonitoringAgent
 			nodeGroup.GET("/latest-release", nodeEndpoints.GetLatestRelease)
 			nodeGroup.GET("/provider/quality", nodeEndpoints.GetProviderQuality)
 			nodeGroup.GET("/provider/activity-stats", nodeEndpoints.GetProviderActivityStats)
+			nodeGroup.GET("/p2p/rejected-control-messages", nodeEndpoints.GetRejectedControlMessages)
 		}
 		return nil
 	}
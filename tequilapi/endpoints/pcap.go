@@ -0,0 +1,242 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mysteriumnetwork/go-rest/apierror"
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/core/connection/capture"
+	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
+	"github.com/mysteriumnetwork/node/tequilapi/utils"
+)
+
+type packetCapturer interface {
+	Start(sessionID string, duration time.Duration) (capture.Info, error)
+	Stop() (capture.Info, error)
+	Status() (capture.Info, bool)
+	List() ([]capture.File, error)
+	Open(name string) (*os.File, error)
+	MaxDuration() time.Duration
+}
+
+type connectionStatusProvider interface {
+	Status(n int) connectionstate.Status
+}
+
+type pcapEndpoint struct {
+	capturer    packetCapturer
+	connections connectionStatusProvider
+}
+
+// Status returns the packet capture state and the stored captures
+//
+// swagger:operation GET /debug/pcap Debug getPcapStatus
+//
+//	---
+//	summary: Get packet captures
+//	description: Returns the running or the last packet capture and the stored capture files, requires admin role
+//	responses:
+//	  200:
+//	    description: Packet capture status
+//	    schema:
+//	      "$ref": "#/definitions/PcapStatusDTO"
+//	  500:
+//	    description: Internal server error
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+func (pe *pcapEndpoint) Status(c *gin.Context) {
+	files, err := pe.capturer.List()
+	if err != nil {
+		c.Error(apierror.Internal("Could not list packet captures: "+err.Error(), contract.ErrCodePcapList))
+		return
+	}
+
+	var last *capture.Info
+	if info, ok := pe.capturer.Status(); ok {
+		last = &info
+	}
+	utils.WriteAsJSON(contract.NewPcapStatusDTO(last, files, pe.capturer.MaxDuration()), c.Writer)
+}
+
+// Start starts capturing packet headers of a consumer session
+//
+// swagger:operation POST /debug/pcap Debug startPcap
+//
+//	---
+//	summary: Start packet capture
+//	description: Starts capturing packet headers (never payloads) of a consumer session tunnel into a local file, requires admin role
+//	parameters:
+//	- in: body
+//	  name: body
+//	  schema:
+//	    $ref: "#/definitions/PcapStartRequestDTO"
+//	responses:
+//	  200:
+//	    description: Packet capture started
+//	    schema:
+//	      "$ref": "#/definitions/PcapCaptureDTO"
+//	  400:
+//	    description: Failed to parse request
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  409:
+//	    description: Packet capture is already running
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  422:
+//	    description: No session to capture
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  500:
+//	    description: Internal server error
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+func (pe *pcapEndpoint) Start(c *gin.Context) {
+	var req contract.PcapStartRequest
+	if c.Request.ContentLength != 0 {
+		if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil {
+			c.Error(apierror.ParseFailed())
+			return
+		}
+	}
+
+	sessionID := req.SessionID
+	if sessionID == "" {
+		sessionID = string(pe.connections.Status(0).SessionID)
+	}
+	if sessionID == "" {
+		c.Error(apierror.Unprocessable("No session to capture", contract.ErrCodeNoConnectionExists))
+		return
+	}
+
+	info, err := pe.capturer.Start(sessionID, time.Duration(req.DurationSeconds)*time.Second)
+	if errors.Is(err, capture.ErrRunning) {
+		c.Error(apierror.Conflict(err.Error(), contract.ErrCodePcapStart, "session_id"))
+		return
+	}
+	if err != nil {
+		log.Err(err).Msg("Could not start packet capture")
+		c.Error(apierror.Internal("Could not start packet capture: "+err.Error(), contract.ErrCodePcapStart))
+		return
+	}
+
+	utils.WriteAsJSON(contract.NewPcapCaptureDTO(info), c.Writer)
+}
+
+// Stop stops the running packet capture
+//
+// swagger:operation POST /debug/pcap/stop Debug stopPcap
+//
+//	---
+//	summary: Stop packet capture
+//	description: Stops the running packet capture before its duration passes, requires admin role
+//	responses:
+//	  200:
+//	    description: Packet capture stopped
+//	    schema:
+//	      "$ref": "#/definitions/PcapCaptureDTO"
+//	  422:
+//	    description: Packet capture is not running
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  500:
+//	    description: Internal server error
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+func (pe *pcapEndpoint) Stop(c *gin.Context) {
+	info, err := pe.capturer.Stop()
+	if errors.Is(err, capture.ErrNotRunning) {
+		c.Error(apierror.Unprocessable(err.Error(), contract.ErrCodePcapStop))
+		return
+	}
+	if err != nil {
+		c.Error(apierror.Internal("Could not stop packet capture: "+err.Error(), contract.ErrCodePcapStop))
+		return
+	}
+
+	utils.WriteAsJSON(contract.NewPcapCaptureDTO(info), c.Writer)
+}
+
+// Download downloads the stored packet capture file
+//
+// swagger:operation GET /debug/pcap/{name} Debug downloadPcap
+//
+//	---
+//	summary: Download packet capture
+//	description: Downloads the stored packet capture file in pcap format, requires admin role
+//	produces:
+//	- application/vnd.tcpdump.pcap
+//	parameters:
+//	- name: name
+//	  in: path
+//	  description: capture file name
+//	  type: string
+//	  required: true
+//	responses:
+//	  200:
+//	    description: Packet capture file
+//	  404:
+//	    description: Capture not found
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+func (pe *pcapEndpoint) Download(c *gin.Context) {
+	name := c.Param("name")
+	f, err := pe.capturer.Open(name)
+	if errors.Is(err, capture.ErrNotFound) {
+		c.Error(apierror.NotFound("Capture not found"))
+		return
+	}
+	if err != nil {
+		c.Error(apierror.Internal("Could not open packet capture: "+err.Error(), contract.ErrCodePcapList))
+		return
+	}
+	defer f.Close()
+
+	c.Header("Content-Type", "application/vnd.tcpdump.pcap")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name))
+	c.Status(http.StatusOK)
+	if _, err := io.Copy(c.Writer, f); err != nil {
+		log.Warn().Err(err).Msgf("Could not send packet capture %s", name)
+	}
+}
+
+// AddRoutesForPcap registers /debug/pcap endpoints in Tequilapi
+func AddRoutesForPcap(capturer packetCapturer, connections connectionStatusProvider) func(*gin.Engine) error {
+	pe := &pcapEndpoint{capturer: capturer, connections: connections}
+	return func(e *gin.Engine) error {
+		g := e.Group("/debug/pcap")
+		{
+			g.GET("", pe.Status)
+			g.POST("", pe.Start)
+			g.POST("/stop", pe.Stop)
+			g.GET("/:name", pe.Download)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,97 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mysteriumnetwork/node/core/connection/capture"
+	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
+	"github.com/mysteriumnetwork/node/session"
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
+)
+
+type mockConnectionStatus struct {
+	sessionID session.ID
+}
+
+func (m *mockConnectionStatus) Status(int) connectionstate.Status {
+	return connectionstate.Status{SessionID: m.sessionID}
+}
+
+func TestPcapEndpoint_CaptureCurrentSession(t *testing.T) {
+	capturer := capture.NewCapturer(capture.Config{Dir: t.TempDir()})
+	connections := &mockConnectionStatus{}
+	router := summonTestGin()
+	require.NoError(t, AddRoutesForPcap(capturer, connections)(router))
+
+	serve := func(method, url, body string) *httptest.ResponseRecorder {
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, httptest.NewRequest(method, url, strings.NewReader(body)))
+		return resp
+	}
+
+	resp := serve(http.MethodPost, "/debug/pcap", "")
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+
+	connections.sessionID = "session-1"
+	resp = serve(http.MethodPost, "/debug/pcap", `{"duration_seconds": 60}`)
+	require.Equal(t, http.StatusOK, resp.Code)
+	var started contract.PcapCaptureDTO
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &started))
+	assert.Equal(t, "session-1", started.SessionID)
+	assert.True(t, started.Running)
+
+	resp = serve(http.MethodPost, "/debug/pcap", `{"session_id": "session-2"}`)
+	assert.Equal(t, http.StatusConflict, resp.Code)
+
+	capturer.Tap("session-1").Packet([]byte{0x45, 0, 0, 20, 0, 0, 0, 0, 64, 6, 0, 0, 10, 0, 0, 1, 10, 0, 0, 2})
+
+	resp = serve(http.MethodPost, "/debug/pcap/stop", "")
+	require.Equal(t, http.StatusOK, resp.Code)
+	var stopped contract.PcapCaptureDTO
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &stopped))
+	assert.False(t, stopped.Running)
+	assert.Equal(t, 1, stopped.Packets)
+
+	resp = serve(http.MethodPost, "/debug/pcap/stop", "")
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+
+	resp = serve(http.MethodGet, "/debug/pcap", "")
+	require.Equal(t, http.StatusOK, resp.Code)
+	var status contract.PcapStatusDTO
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &status))
+	require.Len(t, status.Files, 1)
+	assert.Equal(t, started.Name, status.Files[0].Name)
+	assert.Equal(t, int64(300), status.MaxDurationSeconds)
+
+	resp = serve(http.MethodGet, "/debug/pcap/"+started.Name, "")
+	require.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "application/vnd.tcpdump.pcap", resp.Header().Get("Content-Type"))
+	assert.Equal(t, int(stopped.Size), resp.Body.Len())
+
+	resp = serve(http.MethodGet, "/debug/pcap/missing.pcap", "")
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+}
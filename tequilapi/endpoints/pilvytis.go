@@ -295,12 +295,13 @@ func (e *pilvytisEndpoint) GetRegistrationPaymentStatus(c *gin.Context) {
 }
 
 // AddRoutesForPilvytis adds the pilvytis routers to the given router.
-func AddRoutesForPilvytis(pilvytis api, pt paymentsIssuer, lf paymentLocationFallback) func(*gin.Engine) error {
+func AddRoutesForPilvytis(pilvytis api, pt paymentsIssuer, lf paymentLocationFallback, terms paymentsGate) func(*gin.Engine) error {
 	pil := NewPilvytisEndpoint(pilvytis, pt, lf)
+	guard := newTermsGuard(terms)
 	return func(e *gin.Engine) error {
 		idGroupV2 := e.Group("/v2/identities")
 		{
-			idGroupV2.POST("/:id/:gw/payment-order", pil.CreatePaymentGatewayOrder)
+			idGroupV2.POST("/:id/:gw/payment-order", guard, pil.CreatePaymentGatewayOrder)
 			idGroupV2.GET("/:id/payment-order/:order_id", pil.GetPaymentGatewayOrder)
 			idGroupV2.GET("/:id/payment-order/:order_id/invoice", pil.GetPaymentGatewayOrderInvoice)
 			idGroupV2.GET("/:id/payment-order", pil.GetPaymentGatewayOrders)
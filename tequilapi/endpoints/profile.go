@@ -0,0 +1,110 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mysteriumnetwork/go-rest/apierror"
+
+	"github.com/mysteriumnetwork/node/core/profile"
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
+	"github.com/mysteriumnetwork/node/tequilapi/utils"
+)
+
+type profileStore interface {
+	Get() profile.Profile
+	Set(p profile.Profile) (profile.Profile, error)
+}
+
+type nodeProfileEndpoint struct {
+	store profileStore
+}
+
+// GetProfile returns the node nickname and avatar
+//
+// swagger:operation GET /node/profile Node getNodeProfile
+//
+//	---
+//	summary: Get node profile
+//	description: Returns the friendly node name and avatar hash shown instead of the identity address
+//	responses:
+//	  200:
+//	    description: Node profile
+//	    schema:
+//	      "$ref": "#/definitions/NodeProfileDTO"
+func (ne *nodeProfileEndpoint) GetProfile(c *gin.Context) {
+	utils.WriteAsJSON(contract.NewNodeProfileDTO(ne.store.Get()), c.Writer)
+}
+
+// UpdateProfile sets the node nickname and avatar
+//
+// swagger:operation PUT /node/profile Node updateNodeProfile
+//
+//	---
+//	summary: Update node profile
+//	description: Sets the friendly node name and avatar hash. The nickname is sanitized and truncated to 32 characters, empty values clear the profile.
+//	parameters:
+//	- in: body
+//	  name: body
+//	  schema:
+//	    $ref: "#/definitions/NodeProfileDTO"
+//	responses:
+//	  200:
+//	    description: Node profile updated
+//	    schema:
+//	      "$ref": "#/definitions/NodeProfileDTO"
+//	  400:
+//	    description: Failed to parse or request validation failed
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  500:
+//	    description: Internal server error
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+func (ne *nodeProfileEndpoint) UpdateProfile(c *gin.Context) {
+	var req contract.NodeProfileDTO
+	if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil {
+		c.Error(apierror.ParseFailed())
+		return
+	}
+
+	p, err := ne.store.Set(profile.Profile{Nickname: req.Nickname, AvatarHash: req.AvatarHash})
+	if errors.Is(err, profile.ErrInvalidAvatarHash) {
+		c.Error(apierror.BadRequestField(err.Error(), contract.ErrCodeNodeProfile, "avatar_hash"))
+		return
+	} else if err != nil {
+		c.Error(apierror.Internal("Failed to update node profile: "+err.Error(), contract.ErrCodeNodeProfile))
+		return
+	}
+
+	utils.WriteAsJSON(contract.NewNodeProfileDTO(p), c.Writer)
+}
+
+// AddRoutesForNodeProfile registers /node/profile endpoints in Tequilapi
+func AddRoutesForNodeProfile(store profileStore) func(*gin.Engine) error {
+	ne := &nodeProfileEndpoint{store: store}
+	return func(e *gin.Engine) error {
+		g := e.Group("/node/profile")
+		g.GET("", ne.GetProfile)
+		g.PUT("", ne.UpdateProfile)
+		return nil
+	}
+}
@@ -110,6 +110,10 @@ func NewProposalsEndpoint(proposalRepository proposalRepository, pricer priceAPI
 //	    name: nat_compatibility
 //	    description: Pick nodes compatible with NAT of specified type. Specify "auto" to probe NAT.
 //	    type: string
+//	  - in: query
+//	    name: freshness_min
+//	    description: Minimum freshness of the proposal, from 0 to 1. Proposals not confirmed live recently score lower.
+//	    type: number
 //	responses:
 //	  200:
 //	    description: List of proposals
@@ -135,6 +139,7 @@ func (pe *proposalsEndpoint) List(c *gin.Context) {
 		}
 		return float32(f)
 	}()
+	freshnessMin, _ := strconv.ParseFloat(req.URL.Query().Get("freshness_min"), 64)
 
 	natCompatibility := nat.NATType(req.URL.Query().Get("nat_compatibility"))
 	if natCompatibility == contract.AutoNATType {
@@ -159,6 +164,7 @@ func (pe *proposalsEndpoint) List(c *gin.Context) {
 		CompatibilityMin:        compatibilityMin,
 		CompatibilityMax:        compatibilityMax,
 		QualityMin:              qualityMin,
+		FreshnessMin:            freshnessMin,
 		ExcludeUnsupported:      true,
 		IncludeMonitoringFailed: includeMonitoringFailed,
 	})
@@ -172,7 +178,7 @@ func (pe *proposalsEndpoint) List(c *gin.Context) {
 		proposalsRes.Proposals = append(proposalsRes.Proposals, contract.NewProposalDTO(p))
 	}
 
-	utils.WriteAsJSON(proposalsRes, c.Writer)
+	utils.WriteAsJSONWithETag(proposalsRes, c.Request, c.Writer)
 }
 
 // swagger:operation GET /proposals/countries Countries listCountries
@@ -127,7 +127,7 @@ func TestProposalsEndpointListByNodeId(t *testing.T) {
             "proposals": [
                 {
                     "format": "service-proposal/v3",
-                    "compatibility": 2,
+                    "compatibility": 3,
                     "provider_id": "0xProviderId",
                     "service_type": "testprotocol",
                     "location": {
@@ -199,7 +199,7 @@ func TestProposalsEndpointAcceptsAccessPolicyParams(t *testing.T) {
             "proposals": [
                 {
                     "format": "service-proposal/v3",
-                    "compatibility": 2,
+                    "compatibility": 3,
                     "provider_id": "0xProviderId",
                     "service_type": "testprotocol",
                     "location": {
@@ -335,7 +335,7 @@ func TestProposalsEndpointFilterByPresetID(t *testing.T) {
             "proposals": [
                 {
                     "format": "service-proposal/v3",
-                    "compatibility": 2,
+                    "compatibility": 3,
                     "provider_id": "0xProviderId",
                     "service_type": "testprotocol",
                     "location": {
@@ -367,7 +367,7 @@ func TestProposalsEndpointFilterByPresetID(t *testing.T) {
                 },
                 {
                     "format": "service-proposal/v3",
-                    "compatibility": 2,
+                    "compatibility": 3,
                     "provider_id": "other_provider",
                     "service_type": "testprotocol",
                     "location": {
@@ -0,0 +1,224 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mysteriumnetwork/go-rest/apierror"
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
+	"github.com/mysteriumnetwork/node/tequilapi/recorder"
+	"github.com/mysteriumnetwork/node/tequilapi/utils"
+)
+
+// RecordingRoutePrefix is the prefix of routes managing Tequilapi recordings, they are never recorded themselves.
+const RecordingRoutePrefix = "/debug/recording"
+
+type tequilapiRecorder interface {
+	Start(duration time.Duration) (recorder.Info, error)
+	Stop() (recorder.Info, error)
+	Status() (recorder.Info, bool)
+	List() ([]recorder.File, error)
+	Open(name string) (*os.File, error)
+	MaxDuration() time.Duration
+}
+
+type recordingEndpoint struct {
+	recorder tequilapiRecorder
+}
+
+// Status returns the Tequilapi recording state and the stored recordings
+//
+// swagger:operation GET /debug/recording Debug getRecordingStatus
+//
+//	---
+//	summary: Get Tequilapi recordings
+//	description: Returns the running or the last Tequilapi recording and the stored recording files, requires admin role
+//	responses:
+//	  200:
+//	    description: Tequilapi recording status
+//	    schema:
+//	      "$ref": "#/definitions/RecordingStatusDTO"
+//	  500:
+//	    description: Internal server error
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+func (re *recordingEndpoint) Status(c *gin.Context) {
+	files, err := re.recorder.List()
+	if err != nil {
+		c.Error(apierror.Internal("Could not list recordings: "+err.Error(), contract.ErrCodeRecordingList))
+		return
+	}
+
+	var last *recorder.Info
+	if info, ok := re.recorder.Status(); ok {
+		last = &info
+	}
+	utils.WriteAsJSON(contract.NewRecordingStatusDTO(last, files, re.recorder.MaxDuration()), c.Writer)
+}
+
+// Start starts recording Tequilapi requests and responses
+//
+// swagger:operation POST /debug/recording Debug startRecording
+//
+//	---
+//	summary: Start Tequilapi recording
+//	description: Starts recording Tequilapi requests and responses with secrets redacted into a local HAR file, requires admin role
+//	parameters:
+//	- in: body
+//	  name: body
+//	  schema:
+//	    $ref: "#/definitions/RecordingStartRequestDTO"
+//	responses:
+//	  200:
+//	    description: Tequilapi recording started
+//	    schema:
+//	      "$ref": "#/definitions/RecordingDTO"
+//	  400:
+//	    description: Failed to parse request
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  409:
+//	    description: Recording is already running
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  500:
+//	    description: Internal server error
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+func (re *recordingEndpoint) Start(c *gin.Context) {
+	var req contract.RecordingStartRequest
+	if c.Request.ContentLength != 0 {
+		if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil {
+			c.Error(apierror.ParseFailed())
+			return
+		}
+	}
+
+	info, err := re.recorder.Start(time.Duration(req.DurationSeconds) * time.Second)
+	if errors.Is(err, recorder.ErrRunning) {
+		c.Error(apierror.Error(http.StatusConflict, err.Error(), contract.ErrCodeRecordingStart))
+		return
+	}
+	if err != nil {
+		log.Err(err).Msg("Could not start Tequilapi recording")
+		c.Error(apierror.Internal("Could not start recording: "+err.Error(), contract.ErrCodeRecordingStart))
+		return
+	}
+
+	utils.WriteAsJSON(contract.NewRecordingDTO(info), c.Writer)
+}
+
+// Stop stops the running Tequilapi recording
+//
+// swagger:operation POST /debug/recording/stop Debug stopRecording
+//
+//	---
+//	summary: Stop Tequilapi recording
+//	description: Stops the running Tequilapi recording before its duration passes and stores it, requires admin role
+//	responses:
+//	  200:
+//	    description: Tequilapi recording stopped
+//	    schema:
+//	      "$ref": "#/definitions/RecordingDTO"
+//	  422:
+//	    description: Recording is not running
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  500:
+//	    description: Internal server error
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+func (re *recordingEndpoint) Stop(c *gin.Context) {
+	info, err := re.recorder.Stop()
+	if errors.Is(err, recorder.ErrNotRunning) {
+		c.Error(apierror.Unprocessable(err.Error(), contract.ErrCodeRecordingStop))
+		return
+	}
+	if err != nil {
+		c.Error(apierror.Internal("Could not stop recording: "+err.Error(), contract.ErrCodeRecordingStop))
+		return
+	}
+
+	utils.WriteAsJSON(contract.NewRecordingDTO(info), c.Writer)
+}
+
+// Download downloads the stored Tequilapi recording file
+//
+// swagger:operation GET /debug/recording/{name} Debug downloadRecording
+//
+//	---
+//	summary: Download Tequilapi recording
+//	description: Downloads the stored Tequilapi recording file in HAR format, requires admin role
+//	parameters:
+//	- name: name
+//	  in: path
+//	  description: recording file name
+//	  type: string
+//	  required: true
+//	responses:
+//	  200:
+//	    description: Tequilapi recording file
+//	  404:
+//	    description: Recording not found
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+func (re *recordingEndpoint) Download(c *gin.Context) {
+	name := c.Param("name")
+	f, err := re.recorder.Open(name)
+	if errors.Is(err, recorder.ErrNotFound) {
+		c.Error(apierror.NotFound("Recording not found"))
+		return
+	}
+	if err != nil {
+		c.Error(apierror.Internal("Could not open recording: "+err.Error(), contract.ErrCodeRecordingList))
+		return
+	}
+	defer f.Close()
+
+	c.Header("Content-Type", "application/json")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name))
+	c.Status(http.StatusOK)
+	if _, err := io.Copy(c.Writer, f); err != nil {
+		log.Warn().Err(err).Msgf("Could not send recording %s", name)
+	}
+}
+
+// AddRoutesForRecording registers /debug/recording endpoints in Tequilapi
+func AddRoutesForRecording(rec tequilapiRecorder) func(*gin.Engine) error {
+	re := &recordingEndpoint{recorder: rec}
+	return func(e *gin.Engine) error {
+		g := e.Group(RecordingRoutePrefix)
+		{
+			g.GET("", re.Status)
+			g.POST("", re.Start)
+			g.POST("/stop", re.Stop)
+			g.GET("/:name", re.Download)
+		}
+		return nil
+	}
+}
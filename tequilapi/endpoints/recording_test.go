@@ -0,0 +1,89 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
+	"github.com/mysteriumnetwork/node/tequilapi/middlewares"
+	"github.com/mysteriumnetwork/node/tequilapi/recorder"
+)
+
+func TestRecordingEndpoint_RecordsRequests(t *testing.T) {
+	rec := recorder.NewRecorder(recorder.Config{Dir: t.TempDir()})
+	router := summonTestGin()
+	router.Use(middlewares.NewRecordingMiddleware(rec, RecordingRoutePrefix))
+	router.GET("/healthcheck", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"uptime": "1m"})
+	})
+	require.NoError(t, AddRoutesForRecording(rec)(router))
+
+	serve := func(method, url, body string) *httptest.ResponseRecorder {
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, httptest.NewRequest(method, url, strings.NewReader(body)))
+		return resp
+	}
+
+	resp := serve(http.MethodPost, "/debug/recording/stop", "")
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+
+	resp = serve(http.MethodPost, "/debug/recording", `{"duration_seconds": 60}`)
+	require.Equal(t, http.StatusOK, resp.Code)
+	var started contract.RecordingDTO
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &started))
+	assert.True(t, started.Running)
+
+	resp = serve(http.MethodPost, "/debug/recording", "")
+	assert.Equal(t, http.StatusConflict, resp.Code)
+
+	resp = serve(http.MethodGet, "/healthcheck", "")
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	resp = serve(http.MethodPost, "/debug/recording/stop", "")
+	require.Equal(t, http.StatusOK, resp.Code)
+	var stopped contract.RecordingDTO
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &stopped))
+	assert.False(t, stopped.Running)
+	assert.Equal(t, 1, stopped.Entries)
+
+	resp = serve(http.MethodGet, "/debug/recording", "")
+	require.Equal(t, http.StatusOK, resp.Code)
+	var status contract.RecordingStatusDTO
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &status))
+	require.Len(t, status.Files, 1)
+	assert.Equal(t, started.Name, status.Files[0].Name)
+	assert.Equal(t, int64(1800), status.MaxDurationSeconds)
+
+	resp = serve(http.MethodGet, "/debug/recording/"+started.Name, "")
+	require.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, int(stopped.Size), resp.Body.Len())
+	assert.Contains(t, resp.Body.String(), "/healthcheck")
+	assert.NotContains(t, resp.Body.String(), "/debug/recording")
+
+	resp = serve(http.MethodGet, "/debug/recording/missing.har", "")
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+}
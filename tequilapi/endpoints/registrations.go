@@ -0,0 +1,183 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mysteriumnetwork/go-rest/apierror"
+	"github.com/spf13/cast"
+
+	"github.com/mysteriumnetwork/node/config"
+	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/identity/registry"
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
+	"github.com/mysteriumnetwork/node/tequilapi/utils"
+)
+
+type registrationWatcher interface {
+	Registrations() ([]registry.IdentityRegistration, error)
+	EstimateRegistrationFee(chainID int64) (registry.RegistrationFee, error)
+	SetReferralToken(id identity.Identity, token string) error
+}
+
+type registrationsEndpoint struct {
+	watcher registrationWatcher
+	idm     identity.Manager
+}
+
+// List returns registration state of every known and service providing identity
+//
+// swagger:operation GET /identities-registrations Identity listRegistrations
+//
+//	---
+//	summary: Returns registration state of the node identities
+//	description: Returns registration status, beneficiary, running services and failed registration attempts of each identity on each chain
+//	responses:
+//	  200:
+//	    description: Registration states
+//	    schema:
+//	      "$ref": "#/definitions/ListRegistrationsResponse"
+//	  500:
+//	    description: Internal server error
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+func (re *registrationsEndpoint) List(c *gin.Context) {
+	registrations, err := re.watcher.Registrations()
+	if err != nil {
+		c.Error(apierror.Internal("Could not list registrations: "+err.Error(), contract.ErrCodeIDRegistrationCheck))
+		return
+	}
+	utils.WriteAsJSON(contract.NewListRegistrationsResponse(registrations), c.Writer)
+}
+
+// RegistrationFee returns the current registration fee of the identity
+//
+// swagger:operation GET /identities/{id}/registration-fee Identity registrationFee
+//
+//	---
+//	summary: Returns registration fee estimate
+//	description: Returns the current registration fee and whether it is below the maximum fee configured for automatic registration
+//	parameters:
+//	  - in: path
+//	    name: id
+//	    description: hex address of identity
+//	    type: string
+//	    required: true
+//	  - in: query
+//	    name: chain_id
+//	    description: chain to estimate the fee on, the active chain if omitted
+//	    type: integer
+//	responses:
+//	  200:
+//	    description: Registration fee estimate
+//	    schema:
+//	      "$ref": "#/definitions/RegistrationFeeDTO"
+//	  404:
+//	    description: Identity not found
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  500:
+//	    description: Internal server error
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+func (re *registrationsEndpoint) RegistrationFee(c *gin.Context) {
+	id, err := re.idm.GetIdentity(c.Param("id"))
+	if err != nil {
+		c.Error(apierror.NotFound("Identity not found"))
+		return
+	}
+
+	chainID := config.GetInt64(config.FlagChainID)
+	if qcid, err := cast.ToInt64E(c.Query("chain_id")); err == nil {
+		chainID = qcid
+	}
+
+	fee, err := re.watcher.EstimateRegistrationFee(chainID)
+	if err != nil {
+		utils.ForwardError(c, err, apierror.Internal("Failed to fetch fees", contract.ErrCodeTransactorFetchFees))
+		return
+	}
+	utils.WriteAsJSON(contract.NewRegistrationFeeDTO(id.Address, fee), c.Writer)
+}
+
+// SetReferralToken sets the referral token used when the identity is registered automatically
+//
+// swagger:operation PUT /identities/{id}/referral-token Identity setReferralToken
+//
+//	---
+//	summary: Sets referral token of identity
+//	description: Sets the referral token used when the identity is registered automatically, it takes precedence over the configured one. Empty token removes it.
+//	parameters:
+//	  - in: path
+//	    name: id
+//	    description: hex address of identity
+//	    type: string
+//	    required: true
+//	  - in: body
+//	    name: body
+//	    description: Referral token
+//	    schema:
+//	      $ref: "#/definitions/IdentityReferralTokenRequest"
+//	responses:
+//	  202:
+//	    description: Referral token set
+//	  400:
+//	    description: Failed to parse request
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  404:
+//	    description: Identity not found
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  500:
+//	    description: Internal server error
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+func (re *registrationsEndpoint) SetReferralToken(c *gin.Context) {
+	id, err := re.idm.GetIdentity(c.Param("id"))
+	if err != nil {
+		c.Error(apierror.NotFound("Identity not found"))
+		return
+	}
+
+	var req contract.IdentityReferralTokenRequest
+	if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil {
+		c.Error(apierror.ParseFailed())
+		return
+	}
+
+	if err := re.watcher.SetReferralToken(id, req.Token); err != nil {
+		c.Error(apierror.Internal("Failed to set referral token: "+err.Error(), contract.ErrCodeReferralSetToken))
+		return
+	}
+	c.Status(http.StatusAccepted)
+}
+
+// AddRoutesForRegistrations registers /identities-registrations, registration fee and referral token endpoints in Tequilapi
+func AddRoutesForRegistrations(watcher registrationWatcher, idm identity.Manager) func(*gin.Engine) error {
+	re := &registrationsEndpoint{watcher: watcher, idm: idm}
+	return func(e *gin.Engine) error {
+		e.GET("/identities-registrations", re.List)
+		e.GET("/identities/:id/registration-fee", re.RegistrationFee)
+		e.PUT("/identities/:id/referral-token", re.SetReferralToken)
+		return nil
+	}
+}
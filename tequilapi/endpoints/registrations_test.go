@@ -0,0 +1,128 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/identity/registry"
+)
+
+type mockRegistrationWatcher struct {
+	registrations []registry.IdentityRegistration
+	fee           registry.RegistrationFee
+	feeErr        error
+	feeChainID    int64
+	tokens        map[identity.Identity]string
+}
+
+func (m *mockRegistrationWatcher) Registrations() ([]registry.IdentityRegistration, error) {
+	return m.registrations, nil
+}
+
+func (m *mockRegistrationWatcher) EstimateRegistrationFee(chainID int64) (registry.RegistrationFee, error) {
+	m.feeChainID = chainID
+	return m.fee, m.feeErr
+}
+
+func (m *mockRegistrationWatcher) SetReferralToken(id identity.Identity, token string) error {
+	if m.tokens == nil {
+		m.tokens = make(map[identity.Identity]string)
+	}
+	m.tokens[id] = token
+	return nil
+}
+
+func Test_RegistrationFee(t *testing.T) {
+	watcher := &mockRegistrationWatcher{
+		fee: registry.RegistrationFee{
+			ChainID:    80001,
+			Fee:        big.NewInt(200),
+			ValidUntil: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+			MaxFee:     big.NewInt(100),
+			Allowed:    false,
+		},
+	}
+	router := summonTestGin()
+	err := AddRoutesForRegistrations(watcher, identity.NewIdentityManagerFake(existingIdentities, newIdentity))(router)
+	assert.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/identities/0x000000000000000000000000000000000000000a/registration-fee?chain_id=80001", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, int64(80001), watcher.feeChainID)
+	assert.JSONEq(t, `{
+		"id": "0x000000000000000000000000000000000000000a",
+		"chain_id": 80001,
+		"fee": {"wei": "200", "ether": "0.0000000000000002", "human": "0"},
+		"valid_until": "2024-05-01T12:00:00Z",
+		"max_fee": {"wei": "100", "ether": "0.0000000000000001", "human": "0"},
+		"allowed": false
+	}`, resp.Body.String())
+}
+
+func Test_RegistrationFee_Errors(t *testing.T) {
+	watcher := &mockRegistrationWatcher{feeErr: errors.New("transactor unavailable")}
+	router := summonTestGin()
+	err := AddRoutesForRegistrations(watcher, identity.NewIdentityManagerFake(existingIdentities, newIdentity))(router)
+	assert.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/identities/0x00000000000000000000000000000000000000ff/registration-fee", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/identities/0x000000000000000000000000000000000000000a/registration-fee", nil)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusInternalServerError, resp.Code)
+}
+
+func Test_SetReferralToken(t *testing.T) {
+	watcher := &mockRegistrationWatcher{}
+	router := summonTestGin()
+	err := AddRoutesForRegistrations(watcher, identity.NewIdentityManagerFake(existingIdentities, newIdentity))(router)
+	assert.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPut, "/identities/0x000000000000000000000000000000000000000a/referral-token", strings.NewReader(`{"token": "4db4b2b0"}`))
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusAccepted, resp.Code)
+	assert.Equal(t, map[identity.Identity]string{identity.FromAddress("0x000000000000000000000000000000000000000a"): "4db4b2b0"}, watcher.tokens)
+
+	req = httptest.NewRequest(http.MethodPut, "/identities/0x000000000000000000000000000000000000000a/referral-token", strings.NewReader(`{"token":`))
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	req = httptest.NewRequest(http.MethodPut, "/identities/0x00000000000000000000000000000000000000ff/referral-token", strings.NewReader(`{"token": ""}`))
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+}
@@ -0,0 +1,161 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mysteriumnetwork/go-rest/apierror"
+	consumer_reservation "github.com/mysteriumnetwork/node/consumer/reservation"
+	"github.com/mysteriumnetwork/node/core/service/reservation"
+	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
+	"github.com/mysteriumnetwork/node/tequilapi/utils"
+)
+
+type reserver interface {
+	Reserve(ctx context.Context, consumerID, providerID identity.Identity, serviceType string, start, end time.Time) (consumer_reservation.Reservation, error)
+	List() []consumer_reservation.Reservation
+}
+
+type providedReservations interface {
+	List() []reservation.Reservation
+}
+
+type reservationEndpoint struct {
+	reserver reserver
+	provided providedReservations
+}
+
+// swagger:operation POST /reservations Reservation reservationCreate
+//
+//	---
+//	summary: Reserves provider capacity
+//	description: Experimental. Reserves provider capacity for a future time slot, paying the reservation fee if the provider asks for one. The reservation is converted into a session once the consumer connects during the slot.
+//	parameters:
+//	  - in: body
+//	    name: body
+//	    description: Reservation time slot of the provider service
+//	    schema:
+//	      $ref: "#/definitions/ReservationRequestDTO"
+//	responses:
+//	  201:
+//	    description: Capacity reserved
+//	    schema:
+//	      "$ref": "#/definitions/ReservationDTO"
+//	  400:
+//	    description: Failed to parse or request validation failed
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  404:
+//	    description: Provider does not offer the service
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  500:
+//	    description: Internal server error
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+func (e *reservationEndpoint) Reserve(c *gin.Context) {
+	var req contract.ReservationRequest
+	if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil {
+		c.Error(apierror.ParseFailed())
+		return
+	}
+	if err := req.Validate(); err != nil {
+		c.Error(err)
+		return
+	}
+
+	res, err := e.reserver.Reserve(
+		c.Request.Context(),
+		identity.FromAddress(req.ConsumerID),
+		identity.FromAddress(req.ProviderID),
+		req.ServiceType,
+		req.Start,
+		req.End,
+	)
+	if errors.Is(err, consumer_reservation.ErrProposalNotFound) {
+		c.Error(apierror.NotFound("Provider does not offer the service"))
+		return
+	}
+	if err != nil {
+		c.Error(apierror.Internal("Could not reserve capacity: "+err.Error(), contract.ErrCodeSessionReserve))
+		return
+	}
+
+	c.Status(http.StatusCreated)
+	utils.WriteAsJSON(contract.NewReservationDTO(res), c.Writer)
+}
+
+// swagger:operation GET /reservations Reservation reservationList
+//
+//	---
+//	summary: Lists consumer reservations
+//	description: Experimental. Lists provider capacity reserved by the consumer
+//	responses:
+//	  200:
+//	    description: List of reservations
+//	    schema:
+//	      "$ref": "#/definitions/ListReservationsResponse"
+func (e *reservationEndpoint) List(c *gin.Context) {
+	items := []contract.ReservationDTO{}
+	for _, r := range e.reserver.List() {
+		items = append(items, contract.NewReservationDTO(r))
+	}
+	utils.WriteAsJSON(contract.ListReservationsResponse{Items: items}, c.Writer)
+}
+
+// swagger:operation GET /reservations/provided Reservation reservationListProvided
+//
+//	---
+//	summary: Lists provided reservations
+//	description: Experimental. Lists capacity of the provider services reserved by consumers
+//	responses:
+//	  200:
+//	    description: List of reservations
+//	    schema:
+//	      "$ref": "#/definitions/ListReservationsResponse"
+func (e *reservationEndpoint) ListProvided(c *gin.Context) {
+	items := []contract.ReservationDTO{}
+	if e.provided != nil {
+		for _, r := range e.provided.List() {
+			items = append(items, contract.NewProvidedReservationDTO(r))
+		}
+	}
+	utils.WriteAsJSON(contract.ListReservationsResponse{Items: items}, c.Writer)
+}
+
+// AddRoutesForReservations attaches capacity reservation endpoints to router,
+// provided reservations are listed only when the provider accepts reservations.
+func AddRoutesForReservations(reserver reserver, provided providedReservations) func(*gin.Engine) error {
+	endpoint := &reservationEndpoint{reserver: reserver, provided: provided}
+	return func(e *gin.Engine) error {
+		g := e.Group("/reservations")
+		{
+			g.POST("", endpoint.Reserve)
+			g.GET("", endpoint.List)
+			g.GET("/provided", endpoint.ListProvided)
+		}
+		return nil
+	}
+}
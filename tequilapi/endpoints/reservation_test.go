@@ -0,0 +1,152 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mysteriumnetwork/node/consumer/reservation"
+	"github.com/mysteriumnetwork/node/identity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockReserver struct {
+	reservations []reservation.Reservation
+	err          error
+}
+
+func (m *mockReserver) Reserve(_ context.Context, consumerID, providerID identity.Identity, serviceType string, start, end time.Time) (reservation.Reservation, error) {
+	if m.err != nil {
+		return reservation.Reservation{}, m.err
+	}
+	res := reservation.Reservation{
+		ID:          "r1",
+		ConsumerID:  consumerID,
+		ProviderID:  providerID,
+		ServiceType: serviceType,
+		Start:       start,
+		End:         end,
+		Fee:         big.NewInt(1000),
+	}
+	m.reservations = append(m.reservations, res)
+	return res, nil
+}
+
+func (m *mockReserver) List() []reservation.Reservation {
+	return m.reservations
+}
+
+func TestReservationEndpoint_Reserve(t *testing.T) {
+	// given
+	reserver := &mockReserver{}
+	router := summonTestGin()
+	err := AddRoutesForReservations(reserver, nil)(router)
+	require.NoError(t, err)
+
+	// when
+	resp := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/reservations", strings.NewReader(`{
+		"consumer_id": "0x1",
+		"provider_id": "0x2",
+		"service_type": "wireguard",
+		"start": "2024-05-01T10:00:00Z",
+		"end": "2024-05-01T12:00:00Z"
+	}`))
+	router.ServeHTTP(resp, req)
+
+	// then
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	assert.JSONEq(t,
+		`{
+			"id": "r1",
+			"consumer_id": "0x1",
+			"provider_id": "0x2",
+			"service_type": "wireguard",
+			"start": "2024-05-01T10:00:00Z",
+			"end": "2024-05-01T12:00:00Z",
+			"fee": 1000
+		}`,
+		resp.Body.String(),
+	)
+
+	// when
+	resp = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, "/reservations", nil)
+	router.ServeHTTP(resp, req)
+
+	// then
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"id":"r1"`)
+}
+
+func TestReservationEndpoint_ReserveErrors(t *testing.T) {
+	tests := map[string]struct {
+		body   string
+		err    error
+		status int
+	}{
+		"invalid slot": {
+			body:   `{"consumer_id": "0x1", "provider_id": "0x2", "service_type": "wireguard", "start": "2024-05-01T12:00:00Z", "end": "2024-05-01T10:00:00Z"}`,
+			status: http.StatusBadRequest,
+		},
+		"unknown provider": {
+			body:   `{"consumer_id": "0x1", "provider_id": "0x2", "service_type": "wireguard", "start": "2024-05-01T10:00:00Z", "end": "2024-05-01T12:00:00Z"}`,
+			err:    reservation.ErrProposalNotFound,
+			status: http.StatusNotFound,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			// given
+			router := summonTestGin()
+			err := AddRoutesForReservations(&mockReserver{err: tt.err}, nil)(router)
+			require.NoError(t, err)
+
+			// when
+			resp := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/reservations", strings.NewReader(tt.body))
+			router.ServeHTTP(resp, req)
+
+			// then
+			assert.Equal(t, tt.status, resp.Code)
+		})
+	}
+}
+
+func TestReservationEndpoint_ListProvidedWithoutBook(t *testing.T) {
+	// given
+	router := summonTestGin()
+	err := AddRoutesForReservations(&mockReserver{}, nil)(router)
+	require.NoError(t, err)
+
+	// when
+	resp := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/reservations/provided", nil)
+	router.ServeHTTP(resp, req)
+
+	// then
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `{"items": []}`, resp.Body.String())
+}
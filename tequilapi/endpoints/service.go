@@ -19,6 +19,7 @@ package endpoints
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -28,6 +29,7 @@ import (
 	"github.com/mysteriumnetwork/go-rest/apierror"
 
 	"github.com/mysteriumnetwork/node/config"
+	"github.com/mysteriumnetwork/node/core/compliance"
 	"github.com/mysteriumnetwork/node/core/service"
 	"github.com/mysteriumnetwork/node/identity"
 	"github.com/mysteriumnetwork/node/services"
@@ -155,6 +157,10 @@ func (se *ServiceEndpoint) ServiceGet(c *gin.Context) {
 //	    description: Failed to parse or request validation failed
 //	    schema:
 //	      "$ref": "#/definitions/APIError"
+//	  403:
+//	    description: Service type is restricted in the jurisdiction or required terms are not accepted
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
 //	  422:
 //	    description: Unable to process the request at this point
 //	    schema:
@@ -190,6 +196,12 @@ func (se *ServiceEndpoint) ServiceStart(c *gin.Context) {
 	if err == service.ErrorLocation {
 		c.Error(apierror.Unprocessable("Cannot detect location", contract.ErrCodeServiceLocation))
 		return
+	} else if errors.Is(err, compliance.ErrServiceRestricted) {
+		c.Error(apierror.Forbidden(err.Error(), contract.ErrCodeServiceRestricted))
+		return
+	} else if errors.Is(err, compliance.ErrTermsNotAccepted) || errors.Is(err, compliance.ErrTermsOutdated) {
+		c.Error(apierror.Forbidden(err.Error(), contract.ErrCodeServiceTerms))
+		return
 	} else if err != nil {
 		c.Error(apierror.Internal("Cannot start service: "+err.Error(), contract.ErrCodeServiceStart))
 		return
@@ -357,13 +369,18 @@ func (se *ServiceEndpoint) toServiceInfoResponse(id service.ID, instance *servic
 		prop = &tmp
 	}
 
+	pauseReason := instance.ProposalPauseReason()
+
 	return contract.ServiceInfoDTO{
-		ID:         string(id),
-		ProviderID: instance.ProviderID.Address,
-		Type:       instance.Type,
-		Options:    instance.Options,
-		Status:     string(instance.State()),
-		Proposal:   prop,
+		ID:                  string(id),
+		ProviderID:          instance.ProviderID.Address,
+		Type:                instance.Type,
+		Options:             instance.Options,
+		Status:              string(instance.State()),
+		TemplateVersion:     instance.TemplateVersion,
+		ProposalPaused:      pauseReason != "",
+		ProposalPauseReason: pauseReason,
+		Proposal:            prop,
 	}, nil
 }
 
@@ -169,7 +169,7 @@ func Test_AddRoutesForServiceAddsRoutes(t *testing.T) {
 				"status": "Running",
 				"proposal": {
 		            "format": "service-proposal/v3",
-		            "compatibility": 2,
+		            "compatibility": 3,
 					"provider_id": "0xproviderid",
 					"service_type": "testprotocol",
 					"location": {
@@ -214,7 +214,7 @@ func Test_AddRoutesForServiceAddsRoutes(t *testing.T) {
 				"status": "Running",
 				"proposal": {
 		            "format": "service-proposal/v3",
-		            "compatibility": 2,
+		            "compatibility": 3,
 					"provider_id": "0xproviderid",
 					"service_type": "testprotocol",
 					"location": {
@@ -412,7 +412,7 @@ func Test_ServiceGetReturnsServiceInfo(t *testing.T) {
 			"status": "Running",
 			"proposal": {
 				"format": "service-proposal/v3",
-				"compatibility": 2,
+				"compatibility": 3,
 				"provider_id": "0xproviderid",
 				"service_type": "testprotocol",
 				"location": {
@@ -515,7 +515,7 @@ func Test_ServiceStart_WithAccessPolicy(t *testing.T) {
 			"status": "Running",
 			"proposal": {
 				"format": "service-proposal/v3",
-				"compatibility": 2,
+				"compatibility": 3,
 				"provider_id": "0xproviderid",
 				"service_type": "mockAccessPolicyService",
 				"location": {
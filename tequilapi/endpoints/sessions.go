@@ -18,6 +18,8 @@
 package endpoints
 
 import (
+	"encoding/json"
+	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -27,13 +29,19 @@ import (
 	"github.com/mysteriumnetwork/node/consumer/session"
 	"github.com/mysteriumnetwork/node/tequilapi/contract"
 	"github.com/mysteriumnetwork/node/tequilapi/utils"
+	"github.com/rs/zerolog/log"
 	"github.com/vcraescu/go-paginator/adapter"
 )
 
+// sessionExportFlushEvery is the number of exported sessions after which the response is flushed.
+const sessionExportFlushEvery = 100
+
 type sessionStorage interface {
 	List(*session.Filter) ([]session.History, error)
+	Each(*session.Filter, func(session.History) error) error
 	Stats(*session.Filter) (session.Stats, error)
 	StatsByDay(*session.Filter) (map[time.Time]session.Stats, error)
+	ConsumerStats(*session.Filter) (session.ConsumerStats, error)
 }
 
 type sessionsEndpoint struct {
@@ -169,6 +177,94 @@ func (endpoint *sessionsEndpoint) StatsDaily(c *gin.Context) {
 	utils.WriteAsJSON(sessionsDTO, c.Writer)
 }
 
+// swagger:operation GET /sessions/export Session sessionExport
+//
+//	---
+//	summary: Exports sessions history
+//	description: Streams sessions history filtered by given query as newline delimited JSON, one session per line in no particular order
+//	produces:
+//	- application/x-ndjson
+//	responses:
+//	  200:
+//	    description: Sessions, one per line
+//	    schema:
+//	      "$ref": "#/definitions/SessionDTO"
+//	  400:
+//	    description: Failed to parse or request validation failed
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  500:
+//	    description: Internal server error
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+func (endpoint *sessionsEndpoint) Export(c *gin.Context) {
+	query := contract.NewSessionQuery()
+	if err := query.Bind(c.Request); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Header("Content-Type", "application/x-ndjson")
+	flusher, _ := c.Writer.(http.Flusher)
+	encoder := json.NewEncoder(c.Writer)
+	exported := 0
+	err := endpoint.sessionStorage.Each(query.ToFilter(), func(se session.History) error {
+		if err := encoder.Encode(contract.NewSessionDTO(se)); err != nil {
+			return err
+		}
+
+		exported++
+		if flusher != nil && exported%sessionExportFlushEvery == 0 {
+			flusher.Flush()
+		}
+		return nil
+	})
+	if err != nil {
+		if exported == 0 {
+			c.Error(apierror.Internal("Could not export sessions: "+err.Error(), contract.ErrCodeSessionExport))
+			return
+		}
+		log.Error().Err(err).Msgf("Sessions export interrupted after %d sessions", exported)
+	}
+}
+
+// swagger:operation GET /service-stats Session serviceStats
+//
+//	---
+//	summary: Returns consumer statistics of the provided services
+//	description: Returns consumer counts per country, new and returning consumers and average session duration of the provided sessions filtered by given query (date_from=<now -30d> and date_to=<now> by default). Consumers are counted by hashed identity only and countries with few consumers are grouped as "other".
+//	responses:
+//	  200:
+//	    description: Consumer statistics
+//	    schema:
+//	      "$ref": "#/definitions/ServiceStatsResponse"
+//	  400:
+//	    description: Failed to parse or request validation failed
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  500:
+//	    description: Internal server error
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+func (endpoint *sessionsEndpoint) ServiceStats(c *gin.Context) {
+	query := contract.ServiceStatsQuery{
+		DateFrom: conv.Date(strfmt.Date(time.Now().UTC().AddDate(0, 0, -30))),
+		DateTo:   conv.Date(strfmt.Date(time.Now().UTC())),
+	}
+	if err := query.Bind(c.Request); err != nil {
+		c.Error(err)
+		return
+	}
+
+	stats, err := endpoint.sessionStorage.ConsumerStats(query.ToFilter())
+	if err != nil {
+		c.Error(apierror.Internal("Could not aggregate consumer stats: "+err.Error(), contract.ErrCodeServiceStats))
+		return
+	}
+
+	utils.WriteAsJSON(contract.NewServiceStatsResponse(stats), c.Writer)
+}
+
 // AddRoutesForSessions attaches sessions endpoints to router
 func AddRoutesForSessions(sessionStorage sessionStorage) func(*gin.Engine) error {
 	sessionsEndpoint := NewSessionsEndpoint(sessionStorage)
@@ -178,6 +274,24 @@ func AddRoutesForSessions(sessionStorage sessionStorage) func(*gin.Engine) error
 			g.GET("", sessionsEndpoint.List)
 			g.GET("/stats-aggregated", sessionsEndpoint.StatsAggregated)
 			g.GET("/stats-daily", sessionsEndpoint.StatsDaily)
+			g.GET("/export", sessionsEndpoint.Export)
+		}
+		e.GET("/service-stats", sessionsEndpoint.ServiceStats)
+		return nil
+	}
+}
+
+// AddRoutesForSessionArchive attaches endpoints serving the sessions history
+// of an archived node database to router
+func AddRoutesForSessionArchive(archiveStorage sessionStorage) func(*gin.Engine) error {
+	sessionsEndpoint := NewSessionsEndpoint(archiveStorage)
+	return func(e *gin.Engine) error {
+		g := e.Group("/sessions/archive")
+		{
+			g.GET("", sessionsEndpoint.List)
+			g.GET("/stats-aggregated", sessionsEndpoint.StatsAggregated)
+			g.GET("/stats-daily", sessionsEndpoint.StatsDaily)
+			g.GET("/export", sessionsEndpoint.Export)
 		}
 		return nil
 	}
@@ -246,10 +246,137 @@ func Test_SessionsEndpoint_StatsDaily(t *testing.T) {
 	assert.Equal(t, time.Now().UTC().Day(), ssm.calledWithFilter.StartedTo.Day())
 }
 
+func Test_SessionsEndpoint_Export(t *testing.T) {
+	path := "/sessions/export"
+	req, err := http.NewRequest(
+		http.MethodGet,
+		path+"?service_type=serviceType",
+		nil,
+	)
+	assert.Nil(t, err)
+
+	secondSession := connectionSessionMock
+	secondSession.SessionID = node_session.ID("ID2")
+	ssm := &sessionStorageMock{
+		sessionsToReturn: []session.History{connectionSessionMock, secondSession},
+	}
+
+	resp := httptest.NewRecorder()
+	g := summonTestGin()
+	g.GET(path, NewSessionsEndpoint(ssm).Export)
+	g.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "application/x-ndjson", resp.Header().Get("Content-Type"))
+	decoder := json.NewDecoder(resp.Body)
+	var exported []contract.SessionDTO
+	for decoder.More() {
+		var sessionDTO contract.SessionDTO
+		assert.NoError(t, decoder.Decode(&sessionDTO))
+		exported = append(exported, sessionDTO)
+	}
+	assert.Equal(
+		t,
+		[]contract.SessionDTO{
+			contract.NewSessionDTO(connectionSessionMock),
+			contract.NewSessionDTO(secondSession),
+		},
+		exported,
+	)
+	assert.Equal(t, session.NewFilter().SetServiceType("serviceType"), ssm.calledWithFilter)
+}
+
+func Test_SessionsEndpoint_ExportFailure(t *testing.T) {
+	path := "/sessions/export"
+	req, err := http.NewRequest(
+		http.MethodGet,
+		path,
+		nil,
+	)
+	assert.Nil(t, err)
+
+	ssm := &sessionStorageMock{
+		errToReturn: errors.New("storage closed"),
+	}
+
+	resp := httptest.NewRecorder()
+	g := summonTestGin()
+	g.GET(path, NewSessionsEndpoint(ssm).Export)
+	g.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusInternalServerError, resp.Code)
+	assert.Equal(t, contract.ErrCodeSessionExport, apierror.Parse(resp.Result()).Err.Code)
+}
+
+func Test_SessionsEndpoint_ServiceStats(t *testing.T) {
+	path := "/service-stats"
+	req, err := http.NewRequest(
+		http.MethodGet,
+		path+"?date_from=2020-09-01&date_to=2020-09-30&service_type=wireguard",
+		nil,
+	)
+	assert.Nil(t, err)
+
+	ssm := &sessionStorageMock{
+		consumerStats: session.ConsumerStats{
+			Sessions:           5,
+			Consumers:          4,
+			NewConsumers:       3,
+			ReturningConsumers: 1,
+			AverageDuration:    90 * time.Second,
+			ConsumersByCountry: map[string]int{"US": 3, session.OtherCountry: 1},
+		},
+	}
+
+	resp := httptest.NewRecorder()
+	g := summonTestGin()
+	g.GET(path, NewSessionsEndpoint(ssm).ServiceStats)
+	g.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(
+		t,
+		`{
+			"sessions": 5,
+			"consumers": 4,
+			"new_consumers": 3,
+			"returning_consumers": 1,
+			"average_session_duration": 90,
+			"consumers_by_country": {"US": 3, "other": 1}
+		}`,
+		resp.Body.String(),
+	)
+	assert.Equal(
+		t,
+		session.NewFilter().
+			SetStartedFrom(time.Date(2020, 9, 1, 0, 0, 0, 0, time.UTC)).
+			SetStartedTo(time.Date(2020, 9, 30, 23, 59, 59, 0, time.UTC)).
+			SetServiceType("wireguard"),
+		ssm.calledWithFilter,
+	)
+}
+
+func Test_SessionsEndpoint_ServiceStatsBubblesError(t *testing.T) {
+	path := "/service-stats"
+	req, err := http.NewRequest(http.MethodGet, path, nil)
+	assert.Nil(t, err)
+
+	ssm := &sessionStorageMock{errToReturn: errors.New("something bad")}
+
+	resp := httptest.NewRecorder()
+	g := summonTestGin()
+	g.GET(path, NewSessionsEndpoint(ssm).ServiceStats)
+	g.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusInternalServerError, resp.Code)
+	assert.Equal(t, contract.ErrCodeServiceStats, apierror.Parse(resp.Result()).Err.Code)
+}
+
 type sessionStorageMock struct {
 	sessionsToReturn   []session.History
 	statsToReturn      session.Stats
 	statsByDayToReturn map[time.Time]session.Stats
+	consumerStats      session.ConsumerStats
 	errToReturn        error
 
 	calledWithFilter *session.Filter
@@ -269,3 +396,21 @@ func (ssm *sessionStorageMock) StatsByDay(filter *session.Filter) (map[time.Time
 	ssm.calledWithFilter = filter
 	return ssm.statsByDayToReturn, ssm.errToReturn
 }
+
+func (ssm *sessionStorageMock) ConsumerStats(filter *session.Filter) (session.ConsumerStats, error) {
+	ssm.calledWithFilter = filter
+	return ssm.consumerStats, ssm.errToReturn
+}
+
+func (ssm *sessionStorageMock) Each(filter *session.Filter, fn func(session.History) error) error {
+	ssm.calledWithFilter = filter
+	if ssm.errToReturn != nil {
+		return ssm.errToReturn
+	}
+	for _, se := range ssm.sessionsToReturn {
+		if err := fn(se); err != nil {
+			return err
+		}
+	}
+	return nil
+}
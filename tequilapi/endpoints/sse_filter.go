@@ -0,0 +1,165 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// StatePatchEvent represents the partial state change
+const StatePatchEvent EventType = "state-patch"
+
+// stateSections maps section names accepted in the query to the state payload keys.
+var stateSections = map[string]string{
+	"services":       "service_info",
+	"sessions":       "sessions",
+	"sessions_stats": "sessions_stats",
+	"connection":     "consumer",
+	"identities":     "identities",
+	"channels":       "channels",
+}
+
+// PatchOperation represents a single JSON-patch style operation.
+type PatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// stateFilter narrows state events down to the requested sections
+// and optionally turns them into deltas against the last sent state.
+type stateFilter struct {
+	keys  map[string]struct{}
+	patch bool
+	last  map[string]json.RawMessage
+}
+
+// newStateFilter parses `sections` and `patch` query parameters.
+// Nil filter is returned if the client asked for the full state.
+func newStateFilter(query url.Values) (*stateFilter, error) {
+	f := &stateFilter{}
+
+	if raw := query.Get("sections"); raw != "" {
+		f.keys = make(map[string]struct{})
+		for _, section := range strings.Split(raw, ",") {
+			key, ok := stateSections[strings.TrimSpace(section)]
+			if !ok {
+				return nil, fmt.Errorf("unknown state section %q", section)
+			}
+			f.keys[key] = struct{}{}
+		}
+	}
+
+	if raw := query.Get("patch"); raw != "" {
+		patch, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid patch value %q", raw)
+		}
+		f.patch = patch
+	}
+
+	if f.keys == nil && !f.patch {
+		return nil, nil
+	}
+	return f, nil
+}
+
+// apply returns the message to be sent to the client, or false if there is nothing to send.
+func (f *stateFilter) apply(msg string) (string, bool, error) {
+	var e struct {
+		Type    EventType       `json:"type"`
+		Payload json.RawMessage `json:"payload"`
+		Version uint64          `json:"version"`
+	}
+	if err := json.Unmarshal([]byte(msg), &e); err != nil {
+		return "", false, err
+	}
+	if e.Type != StateChangeEvent {
+		return msg, true, nil
+	}
+
+	var sections map[string]json.RawMessage
+	if err := json.Unmarshal(e.Payload, &sections); err != nil {
+		return "", false, err
+	}
+	for key := range sections {
+		if _, ok := f.keys[key]; f.keys != nil && !ok {
+			delete(sections, key)
+		}
+	}
+
+	if f.last == nil {
+		f.last = sections
+		return f.marshal(Event{Type: StateChangeEvent, Payload: sections, Version: e.Version})
+	}
+
+	// Changes outside of the requested sections are not sent at all.
+	ops := diffSections(f.last, sections)
+	f.last = sections
+	if len(ops) == 0 {
+		return "", false, nil
+	}
+	if !f.patch {
+		return f.marshal(Event{Type: StateChangeEvent, Payload: sections, Version: e.Version})
+	}
+	return f.marshal(Event{Type: StatePatchEvent, Payload: ops, Version: e.Version})
+}
+
+func (f *stateFilter) marshal(e Event) (string, bool, error) {
+	res, err := json.Marshal(e)
+	if err != nil {
+		return "", false, err
+	}
+	return string(res), true, nil
+}
+
+func diffSections(prev, next map[string]json.RawMessage) []PatchOperation {
+	keys := make([]string, 0, len(next))
+	for key := range next {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	ops := make([]PatchOperation, 0)
+	for _, key := range keys {
+		old, ok := prev[key]
+		switch {
+		case !ok:
+			ops = append(ops, PatchOperation{Op: "add", Path: "/" + key, Value: next[key]})
+		case !bytes.Equal(old, next[key]):
+			ops = append(ops, PatchOperation{Op: "replace", Path: "/" + key, Value: next[key]})
+		}
+	}
+	removed := make([]string, 0)
+	for key := range prev {
+		if _, ok := next[key]; !ok {
+			removed = append(removed, key)
+		}
+	}
+	sort.Strings(removed)
+	for _, key := range removed {
+		ops = append(ops, PatchOperation{Op: "remove", Path: "/" + key})
+	}
+	return ops
+}
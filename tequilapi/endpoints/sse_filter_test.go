@@ -0,0 +1,98 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"encoding/json"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewStateFilter(t *testing.T) {
+	f, err := newStateFilter(url.Values{})
+	assert.NoError(t, err)
+	assert.Nil(t, f)
+
+	f, err = newStateFilter(url.Values{"sections": []string{"sessions,connection"}})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]struct{}{"sessions": {}, "consumer": {}}, f.keys)
+	assert.False(t, f.patch)
+
+	_, err = newStateFilter(url.Values{"sections": []string{"unknown"}})
+	assert.Error(t, err)
+
+	_, err = newStateFilter(url.Values{"patch": []string{"maybe"}})
+	assert.Error(t, err)
+}
+
+func TestStateFilter_Apply(t *testing.T) {
+	f, err := newStateFilter(url.Values{"sections": []string{"sessions,identities"}, "patch": []string{"true"}})
+	assert.NoError(t, err)
+
+	msg, ok, err := f.apply(`{"type":"state-change","payload":{"sessions":[],"identities":[],"channels":[]}}`)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.JSONEq(t, `{"type":"state-change","payload":{"sessions":[],"identities":[]}}`, msg)
+
+	_, ok, err = f.apply(`{"type":"state-change","payload":{"sessions":[],"identities":[],"channels":[{"id":"1"}]}}`)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	msg, ok, err = f.apply(`{"type":"state-change","payload":{"sessions":[{"id":"1"}],"identities":[],"channels":[]}}`)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.JSONEq(t, `{"type":"state-patch","payload":[{"op":"replace","path":"/sessions","value":[{"id":"1"}]}]}`, msg)
+
+	msg, ok, err = f.apply(`{"type":"nat","payload":{}}`)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, `{"type":"nat","payload":{}}`, msg)
+}
+
+func TestStateFilter_Apply_SkipsUnchangedSections(t *testing.T) {
+	f, err := newStateFilter(url.Values{"sections": []string{"sessions"}})
+	assert.NoError(t, err)
+
+	_, ok, err := f.apply(`{"type":"state-change","payload":{"sessions":[],"channels":[]}}`)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	_, ok, err = f.apply(`{"type":"state-change","payload":{"sessions":[],"channels":[{"id":"1"}]}}`)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	msg, ok, err := f.apply(`{"type":"state-change","payload":{"sessions":[{"id":"1"}],"channels":[]}}`)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.JSONEq(t, `{"type":"state-change","payload":{"sessions":[{"id":"1"}]}}`, msg)
+}
+
+func TestDiffSections_SortsRemovals(t *testing.T) {
+	ops := diffSections(
+		map[string]json.RawMessage{"a": []byte(`1`), "b": []byte(`2`), "c": []byte(`3`), "d": []byte(`4`)},
+		map[string]json.RawMessage{"a": []byte(`1`)},
+	)
+
+	assert.Equal(t, []PatchOperation{
+		{Op: "remove", Path: "/b"},
+		{Op: "remove", Path: "/c"},
+		{Op: "remove", Path: "/d"},
+	}, ops)
+}
@@ -25,6 +25,7 @@ import (
 	"sync"
 
 	"github.com/gin-gonic/gin"
+	"github.com/mysteriumnetwork/go-rest/apierror"
 	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
@@ -45,6 +46,7 @@ type EventType string
 type Event struct {
 	Payload interface{} `json:"payload"`
 	Type    EventType   `json:"type"`
+	Version uint64      `json:"version,omitempty"`
 }
 
 const (
@@ -54,6 +56,8 @@ const (
 	ServiceStatusEvent EventType = "service-status"
 	// StateChangeEvent represents the state change
 	StateChangeEvent EventType = "state-change"
+	// ConnectionInactivityEvent represents the warning about upcoming disconnect of the inactive connection
+	ConnectionInactivityEvent EventType = "connection-inactivity"
 )
 
 // Handler represents an sse handler
@@ -91,14 +95,28 @@ func (h *Handler) Subscribe(bus eventbus.Subscriber) error {
 		return err
 	}
 	err = bus.Subscribe(stateEvent.AppTopicState, h.ConsumeStateEvent)
-	return err
+	if err != nil {
+		return err
+	}
+	return bus.Subscribe(connectionstate.AppTopicConnectionInactivity, h.ConsumeConnectionInactivityEvent)
 }
 
 // Sub subscribes a user to sse
+//
+// Optional `sections` query parameter (comma separated list of services, sessions, sessions_stats,
+// connection, identities, channels) limits state events to the given sections.
+// Optional `patch=true` query parameter makes the stream emit only the changed sections
+// as JSON-patch style operations after the initial state.
 func (h *Handler) Sub(c *gin.Context) {
 	resp := c.Writer
 	req := c.Request
 
+	filter, err := newStateFilter(req.URL.Query())
+	if err != nil {
+		c.Error(apierror.BadRequest(err.Error(), contract.ErrCodeSSEFilter))
+		return
+	}
+
 	f, ok := resp.(http.Flusher)
 	if !ok {
 		resp.WriteHeader(http.StatusBadRequest)
@@ -115,7 +133,7 @@ func (h *Handler) Sub(c *gin.Context) {
 	resp.Header().Set("Connection", "keep-alive")
 
 	messageChan := make(chan string, 1)
-	err := h.sendInitialState(messageChan)
+	err = h.sendInitialState(messageChan)
 	if err != nil {
 		resp.WriteHeader(http.StatusBadRequest)
 		resp.Header().Set("Content-type", "application/json; charset=utf-8")
@@ -140,6 +158,18 @@ func (h *Handler) Sub(c *gin.Context) {
 				return
 			}
 
+			if filter != nil {
+				filtered, ok, err := filter.apply(msg)
+				if err != nil {
+					log.Error().Err(err).Msg("failed to filter state event")
+					continue
+				}
+				if !ok {
+					continue
+				}
+				msg = filtered
+			}
+
 			_, err := fmt.Fprintf(resp, "data: %s\n\n", msg)
 			if err != nil {
 				log.Error().Err(err).Msg("failed to print data in response")
@@ -154,9 +184,11 @@ func (h *Handler) Sub(c *gin.Context) {
 }
 
 func (h *Handler) sendInitialState(messageChan chan string) error {
+	state := h.stateProvider.GetState()
 	res, err := json.Marshal(Event{
 		Type:    StateChangeEvent,
-		Payload: mapState(h.stateProvider.GetState()),
+		Payload: mapState(state),
+		Version: state.Version,
 	})
 	if err != nil {
 		return err
@@ -226,6 +258,7 @@ type stateRes struct {
 	Consumer      consumerStateRes             `json:"consumer"`
 	Identities    []contract.IdentityDTO       `json:"identities"`
 	Channels      []contract.PaymentChannelDTO `json:"channels"`
+	Profile       contract.NodeProfileDTO      `json:"profile"`
 }
 
 type consumerStateRes struct {
@@ -290,6 +323,7 @@ func mapState(state stateEvent.State) stateRes {
 		},
 		Identities: identitiesRes,
 		Channels:   channelsRes,
+		Profile:    contract.NewNodeProfileDTO(state.Profile),
 	}
 	return res
 }
@@ -304,10 +338,29 @@ func identityChannel(address string, channels []pingpong.HermesChannel) *pingpon
 	return nil
 }
 
+type connectionInactivityRes struct {
+	SessionID    string `json:"session_id"`
+	IdleFor      uint64 `json:"idle_seconds"`
+	DisconnectIn uint64 `json:"disconnect_in_seconds"`
+}
+
+// ConsumeConnectionInactivityEvent consumes the connection inactivity warning
+func (h *Handler) ConsumeConnectionInactivityEvent(e connectionstate.AppEventConnectionInactivity) {
+	h.send(Event{
+		Type: ConnectionInactivityEvent,
+		Payload: connectionInactivityRes{
+			SessionID:    string(e.SessionInfo.SessionID),
+			IdleFor:      uint64(e.IdleFor.Seconds()),
+			DisconnectIn: uint64(e.DisconnectIn.Seconds()),
+		},
+	})
+}
+
 // ConsumeStateEvent consumes the state change event
 func (h *Handler) ConsumeStateEvent(event stateEvent.State) {
 	h.send(Event{
 		Type:    StateChangeEvent,
 		Payload: mapState(event),
+		Version: event.Version,
 	})
 }
@@ -154,7 +154,8 @@ func TestHandler_SendsInitialAndFollowingStates(t *testing.T) {
       }
     },
     "identities": [],
-    "channels": []
+    "channels": [],
+    "profile": {"nickname": "", "avatar_hash": ""}
   },
   "type": "state-change"
 }`
@@ -185,7 +186,8 @@ func TestHandler_SendsInitialAndFollowingStates(t *testing.T) {
       }
     },
     "identities": [],
-	"channels": []
+	"channels": [],
+	"profile": {"nickname": "", "avatar_hash": ""}
   },
   "type": "state-change"
 }`
@@ -278,7 +280,8 @@ func TestHandler_SendsInitialAndFollowingStates(t *testing.T) {
 				}
 			}
 		],
-		"channels": []
+		"channels": [],
+		"profile": {"nickname": "", "avatar_hash": ""}
 	},
 	"type": "state-change"
 }`
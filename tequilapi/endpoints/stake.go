@@ -0,0 +1,220 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gin-gonic/gin"
+	"github.com/mysteriumnetwork/go-rest/apierror"
+
+	"github.com/mysteriumnetwork/node/config"
+	"github.com/mysteriumnetwork/node/core/stake"
+	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
+	"github.com/mysteriumnetwork/node/tequilapi/utils"
+)
+
+type stakeManager interface {
+	Stake(chainID int64, id identity.Identity) (current, minimum *big.Int, err error)
+	IncreaseStake(chainID int64, id identity.Identity, hermeses ...common.Address) error
+	DecreaseStake(chainID int64, id identity.Identity, amount *big.Int) error
+}
+
+type stakeEndpoint struct {
+	manager stakeManager
+	idm     identity.Manager
+}
+
+// Stake returns the current and the minimum stake
+//
+// swagger:operation GET /identities/{id}/stake Identity getStake
+//
+//	---
+//	summary: Get stake
+//	description: Returns the current stake of the identity in the active hermes and the minimum stake required by it
+//	parameters:
+//	- name: id
+//	  in: path
+//	  description: Identity address
+//	  type: string
+//	  required: true
+//	responses:
+//	  200:
+//	    description: Identity stake
+//	    schema:
+//	      "$ref": "#/definitions/IdentityStakeResponse"
+//	  404:
+//	    description: Identity not found
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  500:
+//	    description: Internal server error
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+func (se *stakeEndpoint) Stake(c *gin.Context) {
+	id, err := se.idm.GetIdentity(c.Param("id"))
+	if err != nil {
+		c.Error(apierror.NotFound("Identity not found"))
+		return
+	}
+
+	current, minimum, err := se.manager.Stake(config.GetInt64(config.FlagChainID), id)
+	if err != nil {
+		c.Error(apierror.Internal("Failed to get stake: "+err.Error(), contract.ErrCodeTransactorGetStake))
+		return
+	}
+	utils.WriteAsJSON(contract.IdentityStakeResponse{Stake: current, MinimumStake: minimum}, c.Writer)
+}
+
+// IncreaseStake settles earnings into stake
+//
+// swagger:operation POST /identities/{id}/stake/increase Identity increaseStake
+//
+//	---
+//	summary: Increase stake
+//	description: Settles earnings into stake. This is async method, AppTopicStakeChanged event is published once settlement completes.
+//	parameters:
+//	- name: id
+//	  in: path
+//	  description: Identity address
+//	  type: string
+//	  required: true
+//	- in: body
+//	  name: body
+//	  description: Stake increase request
+//	  schema:
+//	    $ref: "#/definitions/IdentityStakeIncreaseRequest"
+//	responses:
+//	  202:
+//	    description: Stake increase request accepted
+//	  400:
+//	    description: Failed to parse or request validation failed
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  404:
+//	    description: Identity not found
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  500:
+//	    description: Internal server error
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+func (se *stakeEndpoint) IncreaseStake(c *gin.Context) {
+	id, err := se.idm.GetIdentity(c.Param("id"))
+	if err != nil {
+		c.Error(apierror.NotFound("Identity not found"))
+		return
+	}
+
+	var req contract.IdentityStakeIncreaseRequest
+	if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil {
+		c.Error(apierror.ParseFailed())
+		return
+	}
+
+	var hermeses []common.Address
+	for _, h := range req.HermesIDs {
+		if !common.IsHexAddress(h) {
+			c.Error(apierror.BadRequest("Invalid hermes address", contract.ErrCodeTransactorIncreaseStake))
+			return
+		}
+		hermeses = append(hermeses, common.HexToAddress(h))
+	}
+
+	if err := se.manager.IncreaseStake(config.GetInt64(config.FlagChainID), id, hermeses...); err != nil {
+		c.Error(apierror.Internal("Failed to increase stake: "+err.Error(), contract.ErrCodeTransactorIncreaseStake))
+		return
+	}
+	c.Status(http.StatusAccepted)
+}
+
+// DecreaseStake decreases stake
+//
+// swagger:operation POST /identities/{id}/stake/decrease Identity decreaseStake
+//
+//	---
+//	summary: Decrease stake
+//	description: Decreases stake by the given amount, the stake left must not be below the minimum stake. This is async method, AppTopicStakeChanged event is published once transaction completes.
+//	parameters:
+//	- name: id
+//	  in: path
+//	  description: Identity address
+//	  type: string
+//	  required: true
+//	- in: body
+//	  name: body
+//	  description: Stake decrease request
+//	  schema:
+//	    $ref: "#/definitions/IdentityStakeDecreaseRequest"
+//	responses:
+//	  202:
+//	    description: Stake decrease request accepted
+//	  400:
+//	    description: Failed to parse or request validation failed
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  404:
+//	    description: Identity not found
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  500:
+//	    description: Internal server error
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+func (se *stakeEndpoint) DecreaseStake(c *gin.Context) {
+	id, err := se.idm.GetIdentity(c.Param("id"))
+	if err != nil {
+		c.Error(apierror.NotFound("Identity not found"))
+		return
+	}
+
+	var req contract.IdentityStakeDecreaseRequest
+	if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil {
+		c.Error(apierror.ParseFailed())
+		return
+	}
+
+	err = se.manager.DecreaseStake(config.GetInt64(config.FlagChainID), id, req.Amount)
+	switch {
+	case errors.Is(err, stake.ErrInvalidAmount), errors.Is(err, stake.ErrInsufficientStake), errors.Is(err, stake.ErrBelowMinimumStake):
+		c.Error(apierror.BadRequestField(err.Error(), contract.ErrCodeTransactorDecreaseStake, "amount"))
+		return
+	case err != nil:
+		c.Error(apierror.Internal("Failed to decrease stake: "+err.Error(), contract.ErrCodeTransactorDecreaseStake))
+		return
+	}
+	c.Status(http.StatusAccepted)
+}
+
+// AddRoutesForStake registers identity stake endpoints in Tequilapi
+func AddRoutesForStake(manager stakeManager, idm identity.Manager, terms paymentsGate) func(*gin.Engine) error {
+	se := &stakeEndpoint{manager: manager, idm: idm}
+	guard := newTermsGuard(terms)
+	return func(e *gin.Engine) error {
+		g := e.Group("/identities/:id/stake")
+		g.GET("", se.Stake)
+		g.POST("/increase", guard, se.IncreaseStake)
+		g.POST("/decrease", guard, se.DecreaseStake)
+		return nil
+	}
+}
@@ -0,0 +1,111 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/core/stake"
+	"github.com/mysteriumnetwork/node/identity"
+)
+
+type mockStakeManager struct {
+	decreased   *big.Int
+	increased   []common.Address
+	decreaseErr error
+}
+
+func (m *mockStakeManager) Stake(chainID int64, id identity.Identity) (*big.Int, *big.Int, error) {
+	return big.NewInt(100), big.NewInt(50), nil
+}
+
+func (m *mockStakeManager) IncreaseStake(chainID int64, id identity.Identity, hermeses ...common.Address) error {
+	m.increased = hermeses
+	return nil
+}
+
+func (m *mockStakeManager) DecreaseStake(chainID int64, id identity.Identity, amount *big.Int) error {
+	m.decreased = amount
+	return m.decreaseErr
+}
+
+func Test_Stake(t *testing.T) {
+	router := summonTestGin()
+	err := AddRoutesForStake(&mockStakeManager{}, identity.NewIdentityManagerFake(existingIdentities, newIdentity), nil)(router)
+	assert.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/identities/0x000000000000000000000000000000000000000a/stake", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `{"stake": 100, "minimum_stake": 50}`, resp.Body.String())
+}
+
+func Test_IncreaseStake(t *testing.T) {
+	manager := &mockStakeManager{}
+	router := summonTestGin()
+	err := AddRoutesForStake(manager, identity.NewIdentityManagerFake(existingIdentities, newIdentity), nil)(router)
+	assert.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/identities/0x000000000000000000000000000000000000000a/stake/increase", strings.NewReader(`{"hermes_ids": ["0x0000000000000000000000000000000000000001"]}`))
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusAccepted, resp.Code)
+	assert.Equal(t, []common.Address{common.HexToAddress("0x0000000000000000000000000000000000000001")}, manager.increased)
+}
+
+func Test_DecreaseStake(t *testing.T) {
+	tests := []struct {
+		name       string
+		id         string
+		err        error
+		wantStatus int
+	}{
+		{name: "accepted", id: "0x000000000000000000000000000000000000000a", wantStatus: http.StatusAccepted},
+		{name: "unknown identity", id: "0x0000000000000000000000000000000000000001", wantStatus: http.StatusNotFound},
+		{name: "below minimum", id: "0x000000000000000000000000000000000000000a", err: fmt.Errorf("%w of 50", stake.ErrBelowMinimumStake), wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := &mockStakeManager{decreaseErr: tt.err}
+			router := summonTestGin()
+			err := AddRoutesForStake(manager, identity.NewIdentityManagerFake(existingIdentities, newIdentity), nil)(router)
+			assert.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPost, "/identities/"+tt.id+"/stake/decrease", strings.NewReader(`{"amount": 10}`))
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, tt.wantStatus, resp.Code)
+			if tt.wantStatus == http.StatusAccepted {
+				assert.Equal(t, big.NewInt(10), manager.decreased)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/mysteriumnetwork/node/tequilapi/utils"
+)
+
+// StateEndpoint serves a snapshot of the node state.
+type StateEndpoint struct {
+	stateProvider stateProvider
+}
+
+// NewStateEndpoint creates and returns state endpoint.
+func NewStateEndpoint(stateProvider stateProvider) *StateEndpoint {
+	return &StateEndpoint{stateProvider: stateProvider}
+}
+
+type versionedStateRes struct {
+	stateRes
+	Version uint64 `json:"version"`
+}
+
+// State returns current node state snapshot.
+// swagger:operation GET /state State getState
+//
+//	---
+//	summary: Returns node state snapshot
+//	description: Returns the same state as pushed over the SSE stream together with its version.
+//	  Response carries an ETag header; requests with matching If-None-Match header get 304 Not Modified.
+//	responses:
+//	  200:
+//	    description: Node state
+//	  304:
+//	    description: State not modified
+func (se *StateEndpoint) State(c *gin.Context) {
+	state := se.stateProvider.GetState()
+	utils.WriteAsJSONWithETag(versionedStateRes{
+		stateRes: mapState(state),
+		Version:  state.Version,
+	}, c.Request, c.Writer)
+}
+
+// AddRoutesForState adds state routes to given router.
+func AddRoutesForState(stateProvider stateProvider) func(*gin.Engine) error {
+	stateEndpoint := NewStateEndpoint(stateProvider)
+
+	return func(e *gin.Engine) error {
+		e.GET("/state", stateEndpoint.State)
+		return nil
+	}
+}
@@ -19,22 +19,37 @@ package endpoints
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/mysteriumnetwork/go-rest/apierror"
 
 	"github.com/mysteriumnetwork/node/config"
+	"github.com/mysteriumnetwork/node/core/compliance"
 	"github.com/mysteriumnetwork/node/tequilapi/contract"
+	"github.com/mysteriumnetwork/node/tequilapi/utils"
 	"github.com/rs/zerolog/log"
 )
 
 type termsAPI struct {
-	config configProvider
+	config     configProvider
+	compliance complianceGate
 }
 
-func newTermsAPI(config configProvider) *termsAPI {
-	return &termsAPI{config: config}
+type complianceGate interface {
+	Config() compliance.Config
+	Accept(role, version string) (compliance.Acceptance, error)
+	Acceptances() ([]compliance.Acceptance, error)
+	Outdated() ([]compliance.Acceptance, error)
+}
+
+type paymentsGate interface {
+	CheckPayments() error
+}
+
+func newTermsAPI(config configProvider, gate complianceGate) *termsAPI {
+	return &termsAPI{config: config, compliance: gate}
 }
 
 // GetTerms returns current terms config
@@ -68,7 +83,7 @@ func (api *termsAPI) GetTerms(c *gin.Context) {
 //	    $ref: "#/definitions/TermsRequest"
 //	responses:
 //	  200:
-//	    description: Terms agreement updated
+//	    description: Terms agreement updated and acceptance recorded
 //	  400:
 //	    description: Failed to parse or request validation failed
 //	    schema:
@@ -85,25 +100,135 @@ func (api *termsAPI) UpdateTerms(c *gin.Context) {
 		return
 	}
 
+	api.update(c, req)
+}
+
+// AcceptTerms accepts the current terms version
+//
+// swagger:operation PUT /terms Terms acceptTerms
+//
+//	---
+//	summary: Accept current terms
+//	description: Accepts the current terms version again after it was updated. Provider services and payments are blocked until then.
+//	parameters:
+//	- in: body
+//	  name: body
+//	  description: Terms agreement of the current version
+//	  schema:
+//	    $ref: "#/definitions/TermsRequest"
+//	responses:
+//	  200:
+//	    description: Terms accepted
+//	  400:
+//	    description: Failed to parse or request validation failed
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  500:
+//	    description: Internal server error
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+func (api *termsAPI) AcceptTerms(c *gin.Context) {
+	var req contract.TermsRequest
+	err := json.NewDecoder(c.Request.Body).Decode(&req)
+	if err != nil {
+		c.Error(apierror.ParseFailed())
+		return
+	}
+
+	if err := req.ValidateCurrent(api.compliance.Config().CurrentTermsVersion); err != nil {
+		c.Error(err)
+		return
+	}
+
+	api.update(c, req)
+}
+
+func (api *termsAPI) update(c *gin.Context, req contract.TermsRequest) {
 	for k, v := range req.ToMap() {
 		log.Debug().Msgf("Setting user config value: %q = %q", k, v)
 		api.config.SetUser(k, v)
 	}
 
-	err = api.config.SaveUserConfig()
+	err := api.config.SaveUserConfig()
 	if err != nil {
 		c.Error(apierror.Internal("Failed to save config", contract.ErrCodeConfigSave))
 		return
 	}
+
+	if req.AgreedVersion != "" {
+		for role, agreed := range map[string]*bool{
+			compliance.RoleProvider: req.AgreedProvider,
+			compliance.RoleConsumer: req.AgreedConsumer,
+		} {
+			if agreed == nil || !*agreed {
+				continue
+			}
+			if _, err := api.compliance.Accept(role, req.AgreedVersion); err != nil {
+				c.Error(apierror.Internal("Failed to record terms acceptance: "+err.Error(), contract.ErrCodeTermsAccept))
+				return
+			}
+		}
+	}
 	c.Status(http.StatusOK)
 }
 
-// AddRoutesForTerms registers /terms endpoints in Tequilapi
-func AddRoutesForTerms(e *gin.Engine) error {
-	api := newTermsAPI(config.Current)
+// GetCompliance returns compliance requirements and recorded terms acceptances
+//
+// swagger:operation GET /terms/compliance Terms getCompliance
+//
+//	---
+//	summary: Get compliance status
+//	description: Returns the configured jurisdiction, required terms version, restricted service types and terms acceptances
+//	responses:
+//	  200:
+//	    description: Compliance status
+//	    schema:
+//	      "$ref": "#/definitions/ComplianceResponse"
+//	  500:
+//	    description: Internal server error
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+func (api *termsAPI) GetCompliance(c *gin.Context) {
+	acceptances, err := api.compliance.Acceptances()
+	if err != nil {
+		c.Error(apierror.Internal("Failed to get terms acceptances: "+err.Error(), contract.ErrCodeTermsCompliance))
+		return
+	}
+	outdated, err := api.compliance.Outdated()
+	if err != nil {
+		c.Error(apierror.Internal("Failed to get terms acceptances: "+err.Error(), contract.ErrCodeTermsCompliance))
+		return
+	}
+	utils.WriteAsJSON(contract.NewComplianceResponse(api.compliance.Config(), acceptances, outdated), c.Writer)
+}
+
+// newTermsGuard blocks payment operations until updated terms are accepted again.
+func newTermsGuard(gate paymentsGate) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if gate == nil {
+			return
+		}
 
-	g := e.Group("/terms")
-	g.GET("", api.GetTerms)
-	g.POST("", api.UpdateTerms)
-	return nil
+		err := gate.CheckPayments()
+		if errors.Is(err, compliance.ErrTermsOutdated) {
+			c.Error(apierror.Forbidden(err.Error(), contract.ErrCodeTermsOutdated))
+			c.Abort()
+		} else if err != nil {
+			c.Error(apierror.Internal("Failed to check accepted terms: "+err.Error(), contract.ErrCodeTermsCompliance))
+			c.Abort()
+		}
+	}
+}
+
+// AddRoutesForTerms registers /terms endpoints in Tequilapi
+func AddRoutesForTerms(gate complianceGate) func(*gin.Engine) error {
+	api := newTermsAPI(config.Current, gate)
+	return func(e *gin.Engine) error {
+		g := e.Group("/terms")
+		g.GET("", api.GetTerms)
+		g.POST("", api.UpdateTerms)
+		g.PUT("", api.AcceptTerms)
+		g.GET("/compliance", api.GetCompliance)
+		return nil
+	}
 }
@@ -0,0 +1,170 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/core/compliance"
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
+)
+
+func TestTerms_UpdateRecordsAcceptance(t *testing.T) {
+	cfg := &mockTermsConfig{user: map[string]interface{}{}}
+	gate := &mockComplianceGate{}
+	api := newTermsAPI(cfg, gate)
+
+	g := summonTestGin()
+	g.POST("/terms", api.UpdateTerms)
+
+	req := httptest.NewRequest(http.MethodPost, "/terms", strings.NewReader(`{"agreed_provider": true, "agreed_consumer": false, "agreed_version": "0.0.27"}`))
+	resp := httptest.NewRecorder()
+	g.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.True(t, cfg.saved)
+	assert.Equal(t, true, cfg.user["terms.provider-agreed"])
+	assert.Equal(t, []compliance.Acceptance{{Role: compliance.RoleProvider, Version: "0.0.27"}}, gate.acceptances)
+}
+
+func TestTerms_GetCompliance(t *testing.T) {
+	gate := &mockComplianceGate{
+		config: compliance.Config{
+			Jurisdiction:        "US",
+			TermsVersion:        "0.0.27",
+			CurrentTermsVersion: "0.0.28",
+			Restrictions:        map[string][]string{"US": {"scraping"}},
+		},
+		acceptances: []compliance.Acceptance{
+			{Role: compliance.RoleProvider, Version: "0.0.27", AcceptedAt: time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)},
+		},
+	}
+	gate.outdated = gate.acceptances
+
+	g := summonTestGin()
+	assert.NoError(t, AddRoutesForTerms(gate)(g))
+
+	req := httptest.NewRequest(http.MethodGet, "/terms/compliance", nil)
+	resp := httptest.NewRecorder()
+	g.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `{
+		"jurisdiction": "US",
+		"required_terms_version": "0.0.27",
+		"current_terms_version": "0.0.28",
+		"restricted_services": {"US": ["scraping"]},
+		"acceptances": [
+			{"role": "provider", "version": "0.0.27", "accepted_at": "2024-01-02T15:04:05Z", "reacceptance_required": true}
+		]
+	}`, resp.Body.String())
+}
+
+func TestTerms_AcceptRequiresCurrentVersion(t *testing.T) {
+	cfg := &mockTermsConfig{user: map[string]interface{}{}}
+	gate := &mockComplianceGate{config: compliance.Config{CurrentTermsVersion: "0.0.28"}}
+	api := newTermsAPI(cfg, gate)
+
+	g := summonTestGin()
+	g.PUT("/terms", api.AcceptTerms)
+
+	req := httptest.NewRequest(http.MethodPut, "/terms", strings.NewReader(`{"agreed_provider": true, "agreed_version": "0.0.27"}`))
+	resp := httptest.NewRecorder()
+	g.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.False(t, cfg.saved)
+	assert.Empty(t, gate.acceptances)
+
+	req = httptest.NewRequest(http.MethodPut, "/terms", strings.NewReader(`{"agreed_provider": true, "agreed_version": "0.0.28"}`))
+	resp = httptest.NewRecorder()
+	g.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, []compliance.Acceptance{{Role: compliance.RoleProvider, Version: "0.0.28"}}, gate.acceptances)
+}
+
+func TestTerms_GuardBlocksPaymentsWithOutdatedTerms(t *testing.T) {
+	gate := &mockComplianceGate{}
+	g := summonTestGin()
+	g.POST("/transactor/settle/withdraw", newTermsGuard(gate), func(c *gin.Context) {
+		c.Status(http.StatusAccepted)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/transactor/settle/withdraw", nil)
+	resp := httptest.NewRecorder()
+	g.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusAccepted, resp.Code)
+
+	gate.paymentsErr = fmt.Errorf("%w: provider terms version 0.0.27 is accepted, but 0.0.28 is current", compliance.ErrTermsOutdated)
+	resp = httptest.NewRecorder()
+	g.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusForbidden, resp.Code)
+	assert.Contains(t, resp.Body.String(), contract.ErrCodeTermsOutdated)
+}
+
+type mockTermsConfig struct {
+	user  map[string]interface{}
+	saved bool
+}
+
+func (m *mockTermsConfig) GetConfig() map[string]interface{}        { return m.user }
+func (m *mockTermsConfig) GetDefaultConfig() map[string]interface{} { return nil }
+func (m *mockTermsConfig) GetUserConfig() map[string]interface{}    { return m.user }
+func (m *mockTermsConfig) SetUser(key string, value interface{})    { m.user[key] = value }
+func (m *mockTermsConfig) RemoveUser(key string)                    { delete(m.user, key) }
+func (m *mockTermsConfig) IsCLI(key string) bool                    { return false }
+func (m *mockTermsConfig) SaveUserConfig() error {
+	m.saved = true
+	return nil
+}
+
+type mockComplianceGate struct {
+	config      compliance.Config
+	acceptances []compliance.Acceptance
+	outdated    []compliance.Acceptance
+	paymentsErr error
+}
+
+func (m *mockComplianceGate) Config() compliance.Config {
+	return m.config
+}
+
+func (m *mockComplianceGate) Accept(role, version string) (compliance.Acceptance, error) {
+	acceptance := compliance.Acceptance{Role: role, Version: version}
+	m.acceptances = append(m.acceptances, acceptance)
+	return acceptance, nil
+}
+
+func (m *mockComplianceGate) Acceptances() ([]compliance.Acceptance, error) {
+	return m.acceptances, nil
+}
+
+func (m *mockComplianceGate) Outdated() ([]compliance.Acceptance, error) {
+	return m.outdated, nil
+}
+
+func (m *mockComplianceGate) CheckPayments() error {
+	return m.paymentsErr
+}
@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"math/big"
 	"net/http"
+	"time"
 
 	"github.com/mysteriumnetwork/go-rest/apierror"
 	"github.com/shopspring/decimal"
@@ -53,18 +54,24 @@ type Transactor interface {
 	FetchSettleFees(chainID int64) (registry.FeesResponse, error)
 	FetchStakeDecreaseFee(chainID int64) (registry.FeesResponse, error)
 	RegisterIdentity(id string, stake, fee *big.Int, beneficiary string, chainID int64, referralToken *string) error
+	RegisterIdentityOnChain(id string, stake *big.Int, beneficiary string, chainID int64) (common.Hash, error)
 	DecreaseStake(id string, chainID int64, amount, transactorFee *big.Int) error
 	GetFreeRegistrationEligibility(identity identity.Identity) (bool, error)
 	GetFreeProviderRegistrationEligibility() (bool, error)
 	OpenChannel(chainID int64, id, hermesID, registryAddress string) error
 	ChannelStatus(chainID int64, id, hermesID, registryAddress string) (registry.ChannelStatusResponse, error)
+	OverrideGasPrice(chainID int64, id string, price registry.GasPrice, ttl time.Duration) func()
 }
 
+// gasPriceOverrideTTL is how long the gas price requested for an asynchronous transaction stays in effect.
+const gasPriceOverrideTTL = 10 * time.Minute
+
 // promiseSettler settles the given promises
 type promiseSettler interface {
 	ForceSettle(chainID int64, providerID identity.Identity, hermesID ...common.Address) error
 	ForceSettleAsync(chainID int64, providerID identity.Identity, hermesID ...common.Address) error
 	SettleIntoStake(chainID int64, providerID identity.Identity, hermesID ...common.Address) error
+	PreviewSettlement(chainID int64, providerID identity.Identity, beneficiary common.Address, hermesIDs ...common.Address) ([]pingpong.SettlementPreview, error)
 	GetHermesFee(chainID int64, id common.Address) (uint16, error)
 	Withdraw(fromChainID int64, toChainID int64, providerID identity.Identity, hermesID, beneficiary common.Address, amount *big.Int) error
 }
@@ -98,6 +105,7 @@ type transactorEndpoint struct {
 	bprovider                 beneficiaryProvider
 	bhandler                  beneficiarySaver
 	pilvytis                  pilvytisApi
+	addressBook               addressResolver
 }
 
 // NewTransactorEndpoint creates and returns transactor endpoint
@@ -110,6 +118,7 @@ func NewTransactorEndpoint(
 	bprovider beneficiaryProvider,
 	bhandler beneficiarySaver,
 	pilvytis pilvytisApi,
+	addressBook addressResolver,
 ) *transactorEndpoint {
 	return &transactorEndpoint{
 		transactor:                transactor,
@@ -120,6 +129,7 @@ func NewTransactorEndpoint(
 		bprovider:                 bprovider,
 		bhandler:                  bhandler,
 		pilvytis:                  pilvytis,
+		addressBook:               addressBook,
 	}
 }
 
@@ -248,7 +258,15 @@ func (te *transactorEndpoint) TransactorFees(c *gin.Context) {
 //	  description: Settle request
 //	  schema:
 //	    $ref: "#/definitions/SettleRequestDTO"
+//	- in: query
+//	  name: dry_run
+//	  description: Simulate the settlement and return its expected outcome and cost without submitting it
+//	  type: boolean
 //	responses:
+//	  200:
+//	    description: Expected settlement outcome, returned in the dry-run mode only
+//	    schema:
+//	      "$ref": "#/definitions/SettlementPreviewResponse"
 //	  202:
 //	    description: Settle request accepted
 //	  500:
@@ -256,7 +274,12 @@ func (te *transactorEndpoint) TransactorFees(c *gin.Context) {
 //	    schema:
 //	      "$ref": "#/definitions/APIError"
 func (te *transactorEndpoint) SettleSync(c *gin.Context) {
-	err := te.settle(c.Request, te.promiseSettler.ForceSettle)
+	if isDryRun(c) {
+		te.previewSettle(c)
+		return
+	}
+
+	err := te.settle(c.Request, te.promiseSettler.ForceSettle, false)
 	if err != nil {
 		log.Err(err).Msg("Settle failed")
 		utils.ForwardError(c, err, apierror.Internal("Could not force settle", contract.ErrCodeHermesSettle))
@@ -276,7 +299,15 @@ func (te *transactorEndpoint) SettleSync(c *gin.Context) {
 //	  description: Settle request
 //	  schema:
 //	    $ref: "#/definitions/SettleRequestDTO"
+//	- in: query
+//	  name: dry_run
+//	  description: Simulate the settlement and return its expected outcome and cost without submitting it
+//	  type: boolean
 //	responses:
+//	  200:
+//	    description: Expected settlement outcome, returned in the dry-run mode only
+//	    schema:
+//	      "$ref": "#/definitions/SettlementPreviewResponse"
 //	  202:
 //	    description: Settle request accepted
 //	  500:
@@ -284,7 +315,12 @@ func (te *transactorEndpoint) SettleSync(c *gin.Context) {
 //	    schema:
 //	      "$ref": "#/definitions/APIError"
 func (te *transactorEndpoint) SettleAsync(c *gin.Context) {
-	err := te.settle(c.Request, te.promiseSettler.ForceSettleAsync)
+	if isDryRun(c) {
+		te.previewSettle(c)
+		return
+	}
+
+	err := te.settle(c.Request, te.promiseSettler.ForceSettleAsync, true)
 	if err != nil {
 		log.Err(err).Msg("Settle async failed")
 		utils.ForwardError(c, err, apierror.Internal("Failed to force settle async", contract.ErrCodeHermesSettleAsync))
@@ -293,12 +329,77 @@ func (te *transactorEndpoint) SettleAsync(c *gin.Context) {
 	c.Status(http.StatusAccepted)
 }
 
-func (te *transactorEndpoint) settle(request *http.Request, settler func(int64, identity.Identity, ...common.Address) error) error {
+func (te *transactorEndpoint) settle(request *http.Request, settler func(int64, identity.Identity, ...common.Address) error, async bool) error {
+	req, err := parseSettleRequest(request)
+	if err != nil {
+		return err
+	}
+
+	chainID := config.GetInt64(config.FlagChainID)
+	release, err := te.overrideGasPrice(chainID, req.ProviderID, req.GasPrice)
+	if err != nil {
+		return err
+	}
+	if !async {
+		defer release()
+	}
+
+	return settler(chainID, req.ProviderID, req.HermesIDs...)
+}
+
+// overrideGasPrice applies the gas price requested by the caller to the transactions of the given identity.
+// The returned func lifts the override, otherwise it expires by itself.
+func (te *transactorEndpoint) overrideGasPrice(chainID int64, id identity.Identity, gas *contract.GasPriceDTO) (func(), error) {
+	if gas == nil {
+		return func() {}, nil
+	}
+
+	price, err := gas.GasPrice()
+	if err != nil {
+		return nil, apierror.BadRequest(err.Error(), contract.ErrCodeTransactorGasPrice)
+	}
+	return te.transactor.OverrideGasPrice(chainID, id.Address, price, gasPriceOverrideTTL), nil
+}
+
+func (te *transactorEndpoint) previewSettle(c *gin.Context) {
+	req, err := parseSettleRequest(c.Request)
+	if err != nil {
+		c.Error(apierror.BadRequest(err.Error(), contract.ErrCodeTransactorSettlePreview))
+		return
+	}
+
+	te.writeSettlementPreview(c, req.ProviderID, common.Address{}, req.HermesIDs)
+}
+
+func (te *transactorEndpoint) writeSettlementPreview(c *gin.Context, providerID identity.Identity, beneficiary common.Address, hermesIDs []common.Address) {
+	chainID := config.GetInt64(config.FlagChainID)
+	previews, err := te.promiseSettler.PreviewSettlement(chainID, providerID, beneficiary, hermesIDs...)
+	if err != nil {
+		log.Err(err).Msg("Settlement preview failed")
+		utils.ForwardError(c, err, apierror.Internal("Could not preview settlement", contract.ErrCodeTransactorSettlePreview))
+		return
+	}
+
+	utils.WriteAsJSON(contract.NewSettlementPreviewResponse(previews), c.Writer)
+}
+
+// isDryRun reports whether the request asks to simulate the transaction instead of submitting it.
+func isDryRun(c *gin.Context) bool {
+	return cast.ToBool(c.Query("dry_run"))
+}
+
+type settleRequest struct {
+	ProviderID identity.Identity
+	HermesIDs  []common.Address
+	GasPrice   *contract.GasPriceDTO
+}
+
+func parseSettleRequest(request *http.Request) (settleRequest, error) {
 	req := contract.SettleRequest{}
 
 	err := json.NewDecoder(request.Body).Decode(&req)
 	if err != nil {
-		return errors.Wrap(err, "failed to unmarshal settle request")
+		return settleRequest{}, errors.Wrap(err, "failed to unmarshal settle request")
 	}
 
 	hermesIDs := []common.Address{
@@ -311,18 +412,23 @@ func (te *transactorEndpoint) settle(request *http.Request, settler func(int64,
 	}
 
 	if len(hermesIDs) == 0 {
-		return errors.New("must specify a hermes to settle with")
+		return settleRequest{}, errors.New("must specify a hermes to settle with")
 	}
 
-	chainID := config.GetInt64(config.FlagChainID)
-	return settler(chainID, identity.FromAddress(req.ProviderID), hermesIDs...)
+	return settleRequest{
+		ProviderID: identity.FromAddress(req.ProviderID),
+		HermesIDs:  hermesIDs,
+		GasPrice:   req.GasPrice,
+	}, nil
 }
 
 // swagger:operation POST /identities/{id}/register Identity RegisterIdentity
 //
 //	---
 //	summary: Registers identity
-//	description: Registers identity on Mysterium Network smart contracts using Transactor
+//	description: Registers identity on Mysterium Network smart contracts using Transactor.
+//	  With method "onchain" the registration transaction is sent directly from the identity wallet instead,
+//	  so that identity can be registered while Transactor is not available.
 //	parameters:
 //	- name: id
 //	  in: path
@@ -338,13 +444,15 @@ func (te *transactorEndpoint) settle(request *http.Request, settler func(int64,
 //	  200:
 //	    description: Identity registered.
 //	  202:
-//	    description: Identity registration accepted and will be processed.
+//	    description: Identity registration accepted and will be processed. Registration sent on chain returns its transaction.
+//	    schema:
+//	      "$ref": "#/definitions/IdentityRegisterOnChainResponseDTO"
 //	  400:
 //	    description: Failed to parse or request validation failed
 //	    schema:
 //	      "$ref": "#/definitions/APIError"
 //	  422:
-//	    description: Unable to process the request at this point
+//	    description: Unable to process the request at this point, e.g. identity wallet has no funds to register on chain
 //	    schema:
 //	      "$ref": "#/definitions/APIError"
 //	  500:
@@ -362,6 +470,10 @@ func (te *transactorEndpoint) RegisterIdentity(c *gin.Context) {
 		c.Error(apierror.ParseFailed())
 		return
 	}
+	if err := req.Validate(); err != nil {
+		c.Error(err)
+		return
+	}
 
 	registrationStatus, err := te.identityRegistry.GetRegistrationStatus(chainID, id)
 	if err != nil {
@@ -379,6 +491,11 @@ func (te *transactorEndpoint) RegisterIdentity(c *gin.Context) {
 		return
 	}
 
+	if registry.RegistrationMethod(req.Method) == registry.RegistrationMethodOnChain {
+		te.registerIdentityOnChain(c, id, req.Beneficiary, chainID)
+		return
+	}
+
 	regFee := big.NewInt(0)
 	if !te.canRegisterForFree(req, id) {
 		if req.Fee == nil || req.Fee.Cmp(big.NewInt(0)) == 0 {
@@ -394,7 +511,18 @@ func (te *transactorEndpoint) RegisterIdentity(c *gin.Context) {
 		}
 	}
 
+	release, err := te.overrideGasPrice(chainID, id, req.GasPrice)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	defer release()
+
 	err = te.transactor.RegisterIdentity(id.Address, big.NewInt(0), regFee, req.Beneficiary, chainID, req.ReferralToken)
+	if errors.Is(err, registry.ErrBeneficiaryDenied) {
+		c.Error(apierror.BadRequest(err.Error(), contract.ErrCodeTransactorBeneficiaryDenied))
+		return
+	}
 	if err != nil {
 		log.Err(err).Msgf("Failed identity registration request for ID: %s, %+v", id.Address, req)
 		utils.ForwardError(c, err, apierror.Internal("Failed to register identity", contract.ErrCodeTransactorRegistration))
@@ -404,11 +532,35 @@ func (te *transactorEndpoint) RegisterIdentity(c *gin.Context) {
 	c.Status(http.StatusAccepted)
 }
 
-// swagger:operation GET /settle/history settlementList
+func (te *transactorEndpoint) registerIdentityOnChain(c *gin.Context, id identity.Identity, beneficiary string, chainID int64) {
+	txHash, err := te.transactor.RegisterIdentityOnChain(id.Address, big.NewInt(0), beneficiary, chainID)
+	if errors.Is(err, registry.ErrBeneficiaryDenied) {
+		c.Error(apierror.BadRequest(err.Error(), contract.ErrCodeTransactorBeneficiaryDenied))
+		return
+	}
+	if errors.Is(err, registry.ErrWalletNotFunded) {
+		c.Error(apierror.Unprocessable(err.Error(), contract.ErrCodeIDRegistrationWalletNotFunded))
+		return
+	}
+	if errors.Is(err, registry.ErrDirectRegistrationUnavailable) {
+		c.Error(apierror.Unprocessable(err.Error(), contract.ErrCodeIDRegistrationOnChain))
+		return
+	}
+	if err != nil {
+		log.Err(err).Msgf("Failed on chain identity registration for ID: %s", id.Address)
+		c.Error(apierror.Internal("Failed to register identity on chain: "+err.Error(), contract.ErrCodeIDRegistrationOnChain))
+		return
+	}
+
+	utils.WriteAsJSON(contract.IdentityRegisterOnChainResponse{TxHash: txHash.Hex()}, c.Writer, http.StatusAccepted)
+}
+
+// swagger:operation GET /settlements settlementList
 //
 //	---
 //	summary: Returns settlement history
-//	description: Returns settlement history
+//	description: Returns the locally stored settlement history, including amounts, fees, hermes, transaction hashes and block numbers.
+//	  Also available under /transactor/settle/history.
 //	responses:
 //	  200:
 //	    description: Returns settlement history
@@ -490,6 +642,13 @@ func (te *transactorEndpoint) DecreaseStake(c *gin.Context) {
 		return
 	}
 
+	release, err := te.overrideGasPrice(chainID, identity.FromAddress(req.ID), req.GasPrice)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	defer release()
+
 	err = te.transactor.DecreaseStake(req.ID, chainID, req.Amount, fees.Fee)
 	if err != nil {
 		log.Err(err).Msgf("Failed decreases stake request for ID: %s, %+v", req.ID, req)
@@ -519,6 +678,10 @@ func (te *transactorEndpoint) DecreaseStake(c *gin.Context) {
 //	    description: Failed to parse or request validation failed
 //	    schema:
 //	      "$ref": "#/definitions/APIError"
+//	  428:
+//	    description: Payout address has to be confirmed, repeat the request with the confirmation token
+//	    schema:
+//	      "$ref": "#/definitions/AddressConfirmationRequiredResponse"
 //	  500:
 //	    description: Internal server error
 //	    schema:
@@ -562,14 +725,30 @@ func (te *transactorEndpoint) Withdraw(c *gin.Context) {
 		toChainID = req.ToChainID
 	}
 
-	err = te.promiseSettler.Withdraw(fromChainID, toChainID, identity.FromAddress(req.ProviderID), common.HexToAddress(req.HermesID), common.HexToAddress(req.Beneficiary), amount)
+	payoutChainID := toChainID
+	if payoutChainID == 0 {
+		payoutChainID = config.GetInt64(config.FlagChain1ChainID)
+	}
+	beneficiaryAddress, ok := resolvePayoutAddress(c, te.addressBook, payoutChainID, req.Beneficiary, req.PayoutTarget, false, contract.ErrCodeTransactorWithdraw)
+	if !ok {
+		return
+	}
+
+	release, err := te.overrideGasPrice(fromChainID, identity.FromAddress(req.ProviderID), req.GasPrice)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	defer release()
+
+	err = te.promiseSettler.Withdraw(fromChainID, toChainID, identity.FromAddress(req.ProviderID), common.HexToAddress(req.HermesID), beneficiaryAddress, amount)
 	if err != nil {
 		log.Err(err).Fields(map[string]interface{}{
 			"from_chain_id": fromChainID,
 			"to_chain_id":   toChainID,
 			"provider_id":   req.ProviderID,
 			"hermes_id":     req.HermesID,
-			"beneficiary":   req.Beneficiary,
+			"beneficiary":   beneficiaryAddress.Hex(),
 			"amount":        amount.String(),
 		}).Msg("Withdrawal failed")
 		utils.ForwardError(c, err, apierror.Internal("Could not withdraw", contract.ErrCodeTransactorWithdraw))
@@ -598,7 +777,7 @@ func (te *transactorEndpoint) Withdraw(c *gin.Context) {
 //	    schema:
 //	      "$ref": "#/definitions/APIError"
 func (te *transactorEndpoint) SettleIntoStakeSync(c *gin.Context) {
-	err := te.settle(c.Request, te.promiseSettler.SettleIntoStake)
+	err := te.settle(c.Request, te.promiseSettler.SettleIntoStake, false)
 	if err != nil {
 		log.Err(err).Msg("Settle into stake failed")
 		utils.ForwardError(c, err, apierror.Internal("Could not settle into stake", contract.ErrCodeTransactorSettle))
@@ -635,7 +814,7 @@ func (te *transactorEndpoint) SettleIntoStakeAsync(c *gin.Context) {
 			}
 		}()
 		return nil
-	})
+	}, true)
 	if err != nil {
 		utils.ForwardError(c, err, apierror.Internal("Could not settle into stake async", contract.ErrCodeTransactorSettle))
 		return
@@ -836,13 +1015,25 @@ func (te *transactorEndpoint) BeneficiaryTxStatus(c *gin.Context) {
 //	  description: Identity address to register
 //	  type: string
 //	  required: true
+//	- in: query
+//	  name: dry_run
+//	  description: Simulate the settlement and return its expected outcome and cost without submitting it
+//	  type: boolean
 //	responses:
+//	  200:
+//	    description: Expected settlement outcome, returned in the dry-run mode only
+//	    schema:
+//	      "$ref": "#/definitions/SettlementPreviewResponse"
 //	  202:
 //	    description: Settle request accepted
 //	  400:
 //	    description: Failed to parse or request validation failed
 //	    schema:
 //	      "$ref": "#/definitions/APIError"
+//	  428:
+//	    description: Payout address has to be confirmed, repeat the request with the confirmation token
+//	    schema:
+//	      "$ref": "#/definitions/AddressConfirmationRequiredResponse"
 func (te *transactorEndpoint) SettleWithBeneficiaryAsync(c *gin.Context) {
 	id := c.Param("id")
 
@@ -865,8 +1056,23 @@ func (te *transactorEndpoint) SettleWithBeneficiaryAsync(c *gin.Context) {
 		}
 	}
 
+	beneficiaryAddress, ok := resolvePayoutAddress(c, te.addressBook, chainID, req.Beneficiary, req.PayoutTarget, isDryRun(c), contract.ErrCodeTransactorBeneficiary)
+	if !ok {
+		return
+	}
+
+	if isDryRun(c) {
+		te.writeSettlementPreview(c, identity.FromAddress(id), beneficiaryAddress, hermeses)
+		return
+	}
+
+	if _, err := te.overrideGasPrice(chainID, identity.FromAddress(id), req.GasPrice); err != nil {
+		c.Error(err)
+		return
+	}
+
 	go func() {
-		err = te.bhandler.SettleAndSaveBeneficiary(identity.FromAddress(id), hermeses, common.HexToAddress(req.Beneficiary))
+		err = te.bhandler.SettleAndSaveBeneficiary(identity.FromAddress(id), hermeses, beneficiaryAddress)
 		if err != nil {
 			log.Err(err).Msgf("Failed set beneficiary request for ID: %s, %+v", id, req)
 		}
@@ -886,9 +1092,12 @@ func AddRoutesForTransactor(
 	bprovider beneficiaryProvider,
 	bhandler beneficiarySaver,
 	pilvytis pilvytisApi,
+	terms paymentsGate,
+	addressBook addressResolver,
 ) func(*gin.Engine) error {
-	te := NewTransactorEndpoint(transactor, identityRegistry, promiseSettler, settlementHistoryProvider, addressProvider, bprovider, bhandler, pilvytis)
+	te := NewTransactorEndpoint(transactor, identityRegistry, promiseSettler, settlementHistoryProvider, addressProvider, bprovider, bhandler, pilvytis, addressBook)
 	a := NewAffiliatorEndpoint(affiliator)
+	guard := newTermsGuard(terms)
 
 	return func(e *gin.Engine) error {
 		idGroup := e.Group("/identities")
@@ -897,22 +1106,24 @@ func AddRoutesForTransactor(
 			idGroup.GET("/provider/eligibility", te.FreeProviderRegistrationEligibility)
 			idGroup.GET("/:id/eligibility", te.FreeRegistrationEligibility)
 			idGroup.GET("/:id/beneficiary-status", te.BeneficiaryTxStatus)
-			idGroup.POST("/:id/beneficiary", te.SettleWithBeneficiaryAsync)
+			idGroup.POST("/:id/beneficiary", guard, te.SettleWithBeneficiaryAsync)
 		}
 
 		transGroup := e.Group("/transactor")
 		{
 			transGroup.GET("/fees", te.TransactorFees)
-			transGroup.POST("/settle/sync", te.SettleSync)
-			transGroup.POST("/settle/async", te.SettleAsync)
+			transGroup.POST("/settle/sync", guard, te.SettleSync)
+			transGroup.POST("/settle/async", guard, te.SettleAsync)
 			transGroup.GET("/settle/history", te.SettlementHistory)
-			transGroup.POST("/stake/increase/sync", te.SettleIntoStakeSync)
-			transGroup.POST("/stake/increase/async", te.SettleIntoStakeAsync)
-			transGroup.POST("/stake/decrease", te.DecreaseStake)
-			transGroup.POST("/settle/withdraw", te.Withdraw)
+			transGroup.POST("/stake/increase/sync", guard, te.SettleIntoStakeSync)
+			transGroup.POST("/stake/increase/async", guard, te.SettleIntoStakeAsync)
+			transGroup.POST("/stake/decrease", guard, te.DecreaseStake)
+			transGroup.POST("/settle/withdraw", guard, te.Withdraw)
 			transGroup.GET("/token/:token/reward", a.TokenRewardAmount)
 			transGroup.GET("/chain-summary", te.ChainSummary)
 		}
+		e.GET("/settlements", te.SettlementHistory)
+
 		transGroupV2 := e.Group("/v2/transactor")
 		{
 			transGroupV2.GET("/fees", te.TransactorFeesV2)
@@ -0,0 +1,97 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mysteriumnetwork/go-rest/apierror"
+	"github.com/vcraescu/go-paginator/adapter"
+
+	"github.com/mysteriumnetwork/node/identity/registry"
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
+	"github.com/mysteriumnetwork/node/tequilapi/utils"
+)
+
+type transactorAuditLog interface {
+	List() ([]registry.TransactorAuditEntry, error)
+	Verify() error
+}
+
+type transactorAuditEndpoint struct {
+	audit transactorAuditLog
+}
+
+// swagger:operation GET /transactor/audit transactorAuditList
+//
+//	---
+//	summary: Returns transactor audit log
+//	description: Returns the locally kept log of the requests sent to transactor, newest first.
+//	  Every entry is chained to the previous one by its hash, the response tells whether the chain of the whole log is intact.
+//	parameters:
+//	- in: query
+//	  name: page_size
+//	  type: integer
+//	  default: 50
+//	- in: query
+//	  name: page
+//	  type: integer
+//	  default: 1
+//	responses:
+//	  200:
+//	    description: Returns transactor audit log
+//	    schema:
+//	      "$ref": "#/definitions/TransactorAuditListResponse"
+//	  400:
+//	    description: Failed to parse or request validation failed
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+//	  500:
+//	    description: Internal server error
+//	    schema:
+//	      "$ref": "#/definitions/APIError"
+func (e *transactorAuditEndpoint) List(c *gin.Context) {
+	query := contract.NewPaginationQuery()
+	if err := query.Bind(c.Request); err != nil {
+		c.Error(err)
+		return
+	}
+
+	entriesAll, err := e.audit.List()
+	if err != nil {
+		c.Error(apierror.Internal("Could not list transactor audit log: "+err.Error(), contract.ErrCodeTransactorAudit))
+		return
+	}
+
+	var pagedEntries []registry.TransactorAuditEntry
+	p := utils.NewPaginator(adapter.NewSliceAdapter(entriesAll), query.PageSize, query.Page)
+	if err := p.Results(&pagedEntries); err != nil {
+		c.Error(apierror.Internal("Could not paginate transactor audit log: "+err.Error(), contract.ErrCodeTransactorAuditPaginate))
+		return
+	}
+
+	utils.WriteAsJSON(contract.NewTransactorAuditListResponse(pagedEntries, p, e.audit.Verify()), c.Writer)
+}
+
+// AddRoutesForTransactorAudit attaches transactor audit log endpoints to router.
+func AddRoutesForTransactorAudit(audit transactorAuditLog) func(*gin.Engine) error {
+	endpoint := &transactorAuditEndpoint{audit: audit}
+	return func(e *gin.Engine) error {
+		e.GET("/transactor/audit", endpoint.List)
+		return nil
+	}
+}
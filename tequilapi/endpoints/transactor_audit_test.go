@@ -0,0 +1,102 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mysteriumnetwork/node/identity/registry"
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
+)
+
+type mockTransactorAuditLog struct {
+	entries   []registry.TransactorAuditEntry
+	verifyErr error
+}
+
+func (m *mockTransactorAuditLog) List() ([]registry.TransactorAuditEntry, error) {
+	return m.entries, nil
+}
+
+func (m *mockTransactorAuditLog) Verify() error {
+	return m.verifyErr
+}
+
+func TestTransactorAuditEndpoint_List(t *testing.T) {
+	// given
+	audit := &mockTransactorAuditLog{}
+	for seq := uint64(3); seq > 0; seq-- {
+		audit.entries = append(audit.entries, registry.TransactorAuditEntry{
+			Seq:      seq,
+			Endpoint: "stake/decrease",
+			Result:   registry.TransactorAuditResultOK,
+			Hash:     fmt.Sprint(seq),
+		})
+	}
+	router := summonTestGin()
+	err := AddRoutesForTransactorAudit(audit)(router)
+	require.NoError(t, err)
+
+	// when
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/transactor/audit?page_size=2&page=2", nil))
+
+	// then
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	var list contract.TransactorAuditListResponse
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &list))
+	assert.True(t, list.Verified)
+	assert.Empty(t, list.VerifyError)
+	assert.Equal(t, 3, list.TotalItems)
+	assert.Equal(t, 2, list.TotalPages)
+	require.Len(t, list.Items, 1)
+	assert.Equal(t, uint64(1), list.Items[0].Seq)
+	assert.Equal(t, "stake/decrease", list.Items[0].Endpoint)
+}
+
+func TestTransactorAuditEndpoint_ListTampered(t *testing.T) {
+	// given
+	audit := &mockTransactorAuditLog{
+		entries:   []registry.TransactorAuditEntry{{Seq: 1}},
+		verifyErr: fmt.Errorf("%w: entry 1", registry.ErrAuditLogTampered),
+	}
+	router := summonTestGin()
+	err := AddRoutesForTransactorAudit(audit)(router)
+	require.NoError(t, err)
+
+	// when
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/transactor/audit", nil))
+
+	// then
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	var list contract.TransactorAuditListResponse
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &list))
+	assert.False(t, list.Verified)
+	assert.Contains(t, list.VerifyError, "entry 1")
+	assert.Len(t, list.Items, 1)
+}
@@ -43,6 +43,7 @@ import (
 
 	"github.com/mysteriumnetwork/node/identity"
 	"github.com/mysteriumnetwork/node/identity/registry"
+	"github.com/mysteriumnetwork/payments/crypto"
 )
 
 var identityRegData = `{
@@ -57,9 +58,9 @@ func Test_RegisterIdentity(t *testing.T) {
 
 	router := summonTestGin()
 
-	tr := registry.NewTransactor(requests.NewHTTPClient(server.URL, requests.DefaultTimeout), server.URL, &mockAddressProvider{}, fakeSignerFactory, mocks.NewEventBus(), nil, time.Minute)
+	tr := registry.NewTransactor(requests.NewHTTPClient(server.URL, requests.DefaultTimeout), server.URL, &mockAddressProvider{}, fakeSignerFactory, mocks.NewEventBus(), nil, time.Minute, nil)
 	a := registry.NewAffiliator(requests.NewHTTPClient(server.URL, requests.DefaultTimeout), server.URL)
-	err := AddRoutesForTransactor(&registry.FakeRegistry{RegistrationStatus: registry.Unregistered}, tr, a, nil, &settlementHistoryProviderMock{}, &mockAddressProvider{}, nil, nil, &mockPilvytis{})(router)
+	err := AddRoutesForTransactor(&registry.FakeRegistry{RegistrationStatus: registry.Unregistered}, tr, a, nil, &settlementHistoryProviderMock{}, &mockAddressProvider{}, nil, nil, &mockPilvytis{}, nil, nil)(router)
 	assert.NoError(t, err)
 
 	req, err := http.NewRequest(
@@ -76,17 +77,139 @@ func Test_RegisterIdentity(t *testing.T) {
 	assert.Equal(t, "", resp.Body.String())
 }
 
+func Test_RegisterIdentity_RejectsBurnBeneficiary(t *testing.T) {
+	server := newTestTransactorServer(http.StatusAccepted, `{ "fee": 1 }`)
+
+	router := summonTestGin()
+
+	tr := registry.NewTransactor(requests.NewHTTPClient(server.URL, requests.DefaultTimeout), server.URL, &mockAddressProvider{}, fakeSignerFactory, mocks.NewEventBus(), nil, time.Minute, nil)
+	a := registry.NewAffiliator(requests.NewHTTPClient(server.URL, requests.DefaultTimeout), server.URL)
+	err := AddRoutesForTransactor(&registry.FakeRegistry{RegistrationStatus: registry.Unregistered}, tr, a, nil, &settlementHistoryProviderMock{}, &mockAddressProvider{}, nil, nil, &mockPilvytis{}, nil, nil)(router)
+	assert.NoError(t, err)
+
+	req, err := http.NewRequest(
+		http.MethodPost,
+		"/identities/0x0000000000000000000000000000000000000000/register",
+		bytes.NewBufferString(`{"beneficiary": "0x000000000000000000000000000000000000dEaD", "fee": 1, "stake": 0}`),
+	)
+	assert.Nil(t, err)
+
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.Equal(t, contract.ErrCodeTransactorBeneficiaryDenied, apierror.Parse(resp.Result()).Err.Code)
+}
+
+func Test_RegisterIdentity_OverridesGasPrice(t *testing.T) {
+	var headers http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			headers = r.Header
+		}
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte(`{ "fee": 1 }`))
+	}))
+	defer server.Close()
+
+	router := summonTestGin()
+
+	tr := registry.NewTransactor(requests.NewHTTPClient(server.URL, requests.DefaultTimeout), server.URL, &mockAddressProvider{}, fakeSignerFactory, mocks.NewEventBus(), nil, time.Minute, nil)
+	a := registry.NewAffiliator(requests.NewHTTPClient(server.URL, requests.DefaultTimeout), server.URL)
+	err := AddRoutesForTransactor(&registry.FakeRegistry{RegistrationStatus: registry.Unregistered}, tr, a, nil, &settlementHistoryProviderMock{}, &mockAddressProvider{}, nil, nil, &mockPilvytis{}, nil, nil)(router)
+	assert.NoError(t, err)
+
+	req, err := http.NewRequest(
+		http.MethodPost,
+		"/identities/0x0000000000000000000000000000000000000000/register",
+		bytes.NewBufferString(`{"fee": 1, "gas_price": {"speed": "fast", "max_priority_fee_per_gas": 2}}`),
+	)
+	assert.Nil(t, err)
+
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusAccepted, resp.Code)
+	assert.Equal(t, "fast", headers.Get("X-Gas-Speed"))
+	assert.Equal(t, "2", headers.Get("X-Gas-Max-Priority-Fee-Per-Gas"))
+
+	req, err = http.NewRequest(
+		http.MethodPost,
+		"/identities/0x0000000000000000000000000000000000000000/register",
+		bytes.NewBufferString(`{"fee": 1, "gas_price": {"speed": "turbo"}}`),
+	)
+	assert.Nil(t, err)
+
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.Equal(t, contract.ErrCodeTransactorGasPrice, apierror.Parse(resp.Result()).Err.Code)
+}
+
+func Test_RegisterIdentity_OnChain(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte(`{ "fee": 1 }`))
+	}))
+	defer server.Close()
+
+	router := summonTestGin()
+
+	tr := registry.NewTransactor(requests.NewHTTPClient(server.URL, requests.DefaultTimeout), server.URL, &mockAddressProvider{}, fakeSignerFactory, mocks.NewEventBus(), nil, time.Minute, nil)
+	a := registry.NewAffiliator(requests.NewHTTPClient(server.URL, requests.DefaultTimeout), server.URL)
+	err := AddRoutesForTransactor(&registry.FakeRegistry{RegistrationStatus: registry.Unregistered}, tr, a, nil, &settlementHistoryProviderMock{}, &mockAddressProvider{}, nil, nil, &mockPilvytis{}, nil, nil)(router)
+	assert.NoError(t, err)
+
+	for name, tc := range map[string]struct {
+		body         string
+		expectedCode int
+		expectedErr  string
+	}{
+		"unknown method": {
+			body:         `{"method": "carrier-pigeon"}`,
+			expectedCode: http.StatusBadRequest,
+			expectedErr:  apierror.ErrCodeValidationFailed,
+		},
+		"referral token with on chain": {
+			body:         `{"method": "onchain", "referral_token": "abc"}`,
+			expectedCode: http.StatusBadRequest,
+			expectedErr:  apierror.ErrCodeValidationFailed,
+		},
+		"on chain without signer": {
+			body:         `{"method": "onchain"}`,
+			expectedCode: http.StatusUnprocessableEntity,
+			expectedErr:  contract.ErrCodeIDRegistrationOnChain,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			req, err := http.NewRequest(
+				http.MethodPost,
+				"/identities/0x0000000000000000000000000000000000000000/register",
+				bytes.NewBufferString(tc.body),
+			)
+			assert.Nil(t, err)
+
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.expectedCode, resp.Code)
+			assert.Equal(t, tc.expectedErr, apierror.Parse(resp.Result()).Err.Code)
+		})
+	}
+}
+
 func Test_Get_TransactorFees(t *testing.T) {
 	mockResponse := `{ "fee": 1000000000000000000 }`
 	server := newTestTransactorServer(http.StatusOK, mockResponse)
 
 	router := summonTestGin()
 
-	tr := registry.NewTransactor(requests.NewHTTPClient(server.URL, requests.DefaultTimeout), server.URL, &mockAddressProvider{}, fakeSignerFactory, mocks.NewEventBus(), nil, time.Minute)
+	tr := registry.NewTransactor(requests.NewHTTPClient(server.URL, requests.DefaultTimeout), server.URL, &mockAddressProvider{}, fakeSignerFactory, mocks.NewEventBus(), nil, time.Minute, nil)
 	a := registry.NewAffiliator(requests.NewHTTPClient(server.URL, requests.DefaultTimeout), server.URL)
 	err := AddRoutesForTransactor(mockIdentityRegistryInstance, tr, a, &mockSettler{
 		feeToReturn: 11_000,
-	}, &settlementHistoryProviderMock{}, &mockAddressProvider{}, nil, nil, nil)(router)
+	}, &settlementHistoryProviderMock{}, &mockAddressProvider{}, nil, nil, nil, nil, nil)(router)
 	assert.NoError(t, err)
 
 	req, err := http.NewRequest(
@@ -133,11 +256,11 @@ func Test_SettleAsync_OK(t *testing.T) {
 
 	router := summonTestGin()
 
-	tr := registry.NewTransactor(requests.NewHTTPClient(server.URL, requests.DefaultTimeout), server.URL, &mockAddressProvider{}, fakeSignerFactory, mocks.NewEventBus(), nil, time.Minute)
+	tr := registry.NewTransactor(requests.NewHTTPClient(server.URL, requests.DefaultTimeout), server.URL, &mockAddressProvider{}, fakeSignerFactory, mocks.NewEventBus(), nil, time.Minute, nil)
 	a := registry.NewAffiliator(requests.NewHTTPClient(server.URL, requests.DefaultTimeout), server.URL)
 	err := AddRoutesForTransactor(mockIdentityRegistryInstance, tr, a, &mockSettler{}, &settlementHistoryProviderMock{}, &mockAddressProvider{}, &mockBeneficiaryProvider{
 		b: common.HexToAddress("0x0000000000000000000000000000000000000001"),
-	}, nil, nil)(router)
+	}, nil, nil, nil, nil)(router)
 	assert.NoError(t, err)
 
 	settleRequest := `{"hermes_id": "0xbe180c8CA53F280C7BE8669596fF7939d933AA10", "provider_id": "0xbe180c8CA53F280C7BE8669596fF7939d933AA10"}`
@@ -161,9 +284,9 @@ func Test_SettleAsync_ReturnsError(t *testing.T) {
 
 	router := summonTestGin()
 
-	tr := registry.NewTransactor(requests.NewHTTPClient(server.URL, requests.DefaultTimeout), server.URL, &mockAddressProvider{}, fakeSignerFactory, mocks.NewEventBus(), nil, time.Minute)
+	tr := registry.NewTransactor(requests.NewHTTPClient(server.URL, requests.DefaultTimeout), server.URL, &mockAddressProvider{}, fakeSignerFactory, mocks.NewEventBus(), nil, time.Minute, nil)
 	a := registry.NewAffiliator(requests.NewHTTPClient(server.URL, requests.DefaultTimeout), server.URL)
-	err := AddRoutesForTransactor(mockIdentityRegistryInstance, tr, a, &mockSettler{errToReturn: errors.New("explosions everywhere")}, &settlementHistoryProviderMock{}, &mockAddressProvider{}, nil, nil, nil)(router)
+	err := AddRoutesForTransactor(mockIdentityRegistryInstance, tr, a, &mockSettler{errToReturn: errors.New("explosions everywhere")}, &settlementHistoryProviderMock{}, &mockAddressProvider{}, nil, nil, nil, nil, nil)(router)
 	assert.NoError(t, err)
 
 	settleRequest := `asdasdasd`
@@ -187,9 +310,9 @@ func Test_SettleSync_OK(t *testing.T) {
 
 	router := summonTestGin()
 
-	tr := registry.NewTransactor(requests.NewHTTPClient(server.URL, requests.DefaultTimeout), server.URL, &mockAddressProvider{}, fakeSignerFactory, mocks.NewEventBus(), nil, time.Minute)
+	tr := registry.NewTransactor(requests.NewHTTPClient(server.URL, requests.DefaultTimeout), server.URL, &mockAddressProvider{}, fakeSignerFactory, mocks.NewEventBus(), nil, time.Minute, nil)
 	a := registry.NewAffiliator(requests.NewHTTPClient(server.URL, requests.DefaultTimeout), server.URL)
-	err := AddRoutesForTransactor(mockIdentityRegistryInstance, tr, a, &mockSettler{}, &settlementHistoryProviderMock{}, &mockAddressProvider{}, nil, nil, nil)(router)
+	err := AddRoutesForTransactor(mockIdentityRegistryInstance, tr, a, &mockSettler{}, &settlementHistoryProviderMock{}, &mockAddressProvider{}, nil, nil, nil, nil, nil)(router)
 	assert.NoError(t, err)
 
 	settleRequest := `{"hermes_id": "0xbe180c8CA53F280C7BE8669596fF7939d933AA10", "provider_id": "0xbe180c8CA53F280C7BE8669596fF7939d933AA10"}`
@@ -213,9 +336,9 @@ func Test_SettleSync_ReturnsError(t *testing.T) {
 
 	router := summonTestGin()
 
-	tr := registry.NewTransactor(requests.NewHTTPClient(server.URL, requests.DefaultTimeout), server.URL, &mockAddressProvider{}, fakeSignerFactory, mocks.NewEventBus(), nil, time.Minute)
+	tr := registry.NewTransactor(requests.NewHTTPClient(server.URL, requests.DefaultTimeout), server.URL, &mockAddressProvider{}, fakeSignerFactory, mocks.NewEventBus(), nil, time.Minute, nil)
 	a := registry.NewAffiliator(requests.NewHTTPClient(server.URL, requests.DefaultTimeout), server.URL)
-	err := AddRoutesForTransactor(mockIdentityRegistryInstance, tr, a, &mockSettler{errToReturn: errors.New("explosions everywhere")}, &settlementHistoryProviderMock{}, &mockAddressProvider{}, nil, nil, nil)(router)
+	err := AddRoutesForTransactor(mockIdentityRegistryInstance, tr, a, &mockSettler{errToReturn: errors.New("explosions everywhere")}, &settlementHistoryProviderMock{}, &mockAddressProvider{}, nil, nil, nil, nil, nil)(router)
 	assert.NoError(t, err)
 
 	settleRequest := `{"hermes_id": "0xbe180c8CA53F280C7BE8669596fF7939d933AA10", "provider_id": "0xbe180c8CA53F280C7BE8669596fF7939d933AA10"}`
@@ -233,6 +356,47 @@ func Test_SettleSync_ReturnsError(t *testing.T) {
 	assert.Equal(t, "err_hermes_settle", apierror.Parse(resp.Result()).Err.Code)
 }
 
+func Test_SettleSync_DryRun(t *testing.T) {
+	router := summonTestGin()
+
+	settler := &mockSettler{
+		previewToReturn: []pingpong.SettlementPreview{
+			{
+				HermesID:      common.HexToAddress("0xbe180c8CA53F280C7BE8669596fF7939d933AA10"),
+				Beneficiary:   common.HexToAddress("0x1"),
+				Amount:        big.NewInt(30000),
+				TransactorFee: big.NewInt(5000),
+				HermesFee:     big.NewInt(1000),
+				Payout:        big.NewInt(24000),
+				WillSettle:    true,
+			},
+		},
+	}
+	err := AddRoutesForTransactor(mockIdentityRegistryInstance, nil, nil, settler, &settlementHistoryProviderMock{}, &mockAddressProvider{}, nil, nil, nil, nil, nil)(router)
+	assert.NoError(t, err)
+
+	settleRequest := `{"hermes_ids": ["0xbe180c8CA53F280C7BE8669596fF7939d933AA10"], "provider_id": "0xbe180c8CA53F280C7BE8669596fF7939d933AA10"}`
+	req, err := http.NewRequest(
+		http.MethodPost,
+		"/transactor/settle/sync?dry_run=true",
+		bytes.NewBufferString(settleRequest),
+	)
+	assert.Nil(t, err)
+
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Zero(t, settler.forceSettleCallCount)
+
+	var preview contract.SettlementPreviewResponse
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &preview))
+	assert.Len(t, preview.Settlements, 1)
+	assert.Equal(t, "24000", preview.Settlements[0].Payout.Wei)
+	assert.Equal(t, "5000", preview.Settlements[0].TransactorFee.Wei)
+	assert.True(t, preview.Settlements[0].WillSettle)
+}
+
 func Test_SettleHistory(t *testing.T) {
 	t.Run("returns error on failed history retrieval", func(t *testing.T) {
 		mockResponse := ""
@@ -240,9 +404,9 @@ func Test_SettleHistory(t *testing.T) {
 		defer server.Close()
 
 		router := summonTestGin()
-		tr := registry.NewTransactor(requests.NewHTTPClient(server.URL, requests.DefaultTimeout), server.URL, &mockAddressProvider{}, fakeSignerFactory, mocks.NewEventBus(), nil, time.Minute)
+		tr := registry.NewTransactor(requests.NewHTTPClient(server.URL, requests.DefaultTimeout), server.URL, &mockAddressProvider{}, fakeSignerFactory, mocks.NewEventBus(), nil, time.Minute, nil)
 		a := registry.NewAffiliator(requests.NewHTTPClient(server.URL, requests.DefaultTimeout), server.URL)
-		err := AddRoutesForTransactor(mockIdentityRegistryInstance, tr, a, nil, &settlementHistoryProviderMock{errToReturn: errors.New("explosions everywhere")}, &mockAddressProvider{}, nil, nil, nil)(router)
+		err := AddRoutesForTransactor(mockIdentityRegistryInstance, tr, a, nil, &settlementHistoryProviderMock{errToReturn: errors.New("explosions everywhere")}, &mockAddressProvider{}, nil, nil, nil, nil, nil)(router)
 		assert.NoError(t, err)
 
 		req, err := http.NewRequest(http.MethodGet, "/transactor/settle/history", nil)
@@ -258,6 +422,8 @@ func Test_SettleHistory(t *testing.T) {
 		mockStorage := &settlementHistoryProviderMock{settlementHistoryToReturn: []pingpong.SettlementHistoryEntry{
 			{
 				TxHash:       common.HexToHash("0x88af51047ff2da1e3626722fe239f70c3ddd668f067b2ac8d67b280d2eff39f7"),
+				BlockNumber:  45132071,
+				Promise:      crypto.Promise{ChainID: 137},
 				Time:         time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
 				Beneficiary:  common.HexToAddress("0x4443189b9b945DD38E7bfB6167F9909451582eE5"),
 				Amount:       big.NewInt(123),
@@ -277,9 +443,9 @@ func Test_SettleHistory(t *testing.T) {
 		defer server.Close()
 
 		router := summonTestGin()
-		tr := registry.NewTransactor(requests.NewHTTPClient(server.URL, requests.DefaultTimeout), server.URL, &mockAddressProvider{}, fakeSignerFactory, mocks.NewEventBus(), nil, time.Minute)
+		tr := registry.NewTransactor(requests.NewHTTPClient(server.URL, requests.DefaultTimeout), server.URL, &mockAddressProvider{}, fakeSignerFactory, mocks.NewEventBus(), nil, time.Minute, nil)
 		a := registry.NewAffiliator(requests.NewHTTPClient(server.URL, requests.DefaultTimeout), server.URL)
-		err := AddRoutesForTransactor(mockIdentityRegistryInstance, tr, a, nil, mockStorage, &mockAddressProvider{}, nil, nil, nil)(router)
+		err := AddRoutesForTransactor(mockIdentityRegistryInstance, tr, a, nil, mockStorage, &mockAddressProvider{}, nil, nil, nil, nil, nil)(router)
 		assert.NoError(t, err)
 
 		req, err := http.NewRequest(http.MethodGet, "/transactor/settle/history", nil)
@@ -304,6 +470,8 @@ func Test_SettleHistory(t *testing.T) {
 						"fees": 20,
 						"is_withdrawal": true,
  						"block_explorer_url": "",
+						"block_number": 45132071,
+						"chain_id": 137,
 						"error": ""
 					},
 					{
@@ -317,6 +485,8 @@ func Test_SettleHistory(t *testing.T) {
 						"fees": 50,
 						"is_withdrawal": true,
  						"block_explorer_url": "",
+						"block_number": 0,
+						"chain_id": 0,
 						"error": ""
 					}
 				],
@@ -335,9 +505,9 @@ func Test_SettleHistory(t *testing.T) {
 		server := newTestTransactorServer(http.StatusAccepted, "")
 		defer server.Close()
 		router := summonTestGin()
-		tr := registry.NewTransactor(requests.NewHTTPClient(server.URL, requests.DefaultTimeout), server.URL, &mockAddressProvider{}, fakeSignerFactory, mocks.NewEventBus(), nil, time.Minute)
+		tr := registry.NewTransactor(requests.NewHTTPClient(server.URL, requests.DefaultTimeout), server.URL, &mockAddressProvider{}, fakeSignerFactory, mocks.NewEventBus(), nil, time.Minute, nil)
 		a := registry.NewAffiliator(requests.NewHTTPClient(server.URL, requests.DefaultTimeout), server.URL)
-		err := AddRoutesForTransactor(mockIdentityRegistryInstance, tr, a, nil, mockStorage, &mockAddressProvider{}, nil, nil, nil)(router)
+		err := AddRoutesForTransactor(mockIdentityRegistryInstance, tr, a, nil, mockStorage, &mockAddressProvider{}, nil, nil, nil, nil, nil)(router)
 		assert.NoError(t, err)
 
 		req, err := http.NewRequest(
@@ -367,12 +537,50 @@ func Test_SettleHistory(t *testing.T) {
 			mockStorage.calledWithFilter,
 		)
 	})
+	t.Run("serves settlements filtered by identity", func(t *testing.T) {
+		mockStorage := &settlementHistoryProviderMock{}
+
+		router := summonTestGin()
+		err := AddRoutesForTransactor(mockIdentityRegistryInstance, nil, nil, nil, mockStorage, &mockAddressProvider{}, nil, nil, nil, nil, nil)(router)
+		assert.NoError(t, err)
+
+		req, err := http.NewRequest(http.MethodGet, "/settlements?identity=0xab1&date_from=2020-09-19&page=2&page_size=10", nil)
+		assert.Nil(t, err)
+
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		expectedTimeFrom := time.Date(2020, 9, 19, 0, 0, 0, 0, time.UTC)
+		expectedProviderID := identity.FromAddress("0xab1")
+		assert.Equal(
+			t,
+			&pingpong.SettlementHistoryFilter{
+				TimeFrom:   &expectedTimeFrom,
+				ProviderID: &expectedProviderID,
+			},
+			mockStorage.calledWithFilter,
+		)
+	})
+	t.Run("rejects conflicting identity filters", func(t *testing.T) {
+		router := summonTestGin()
+		err := AddRoutesForTransactor(mockIdentityRegistryInstance, nil, nil, nil, &settlementHistoryProviderMock{}, &mockAddressProvider{}, nil, nil, nil, nil, nil)(router)
+		assert.NoError(t, err)
+
+		req, err := http.NewRequest(http.MethodGet, "/settlements?identity=0xab1&provider_id=0xab2", nil)
+		assert.Nil(t, err)
+
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+	})
 }
 
 func Test_AvailableChains(t *testing.T) {
 	// given
 	router := summonTestGin()
-	err := AddRoutesForTransactor(nil, nil, nil, nil, nil, nil, nil, nil, nil)(router)
+	err := AddRoutesForTransactor(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)(router)
 	assert.NoError(t, err)
 	config.Current.SetUser(config.FlagChainID.Name, config.FlagChainID.Value)
 
@@ -401,7 +609,7 @@ func Test_Withdrawal(t *testing.T) {
 	settler := &mockSettler{
 		feeToReturn: 11,
 	}
-	err := AddRoutesForTransactor(nil, nil, nil, settler, nil, nil, nil, nil, nil)(router)
+	err := AddRoutesForTransactor(nil, nil, nil, settler, nil, nil, nil, nil, nil, nil, nil)(router)
 	assert.NoError(t, err)
 
 	config.Current.SetUser(config.FlagChainID.Name, config.FlagChainID.Value)
@@ -508,6 +716,10 @@ func (fs *fakeSigner) Sign(message []byte) (identity.Signature, error) {
 type mockSettler struct {
 	errToReturn error
 
+	previewToReturn      []pingpong.SettlementPreview
+	capturedBeneficiary  common.Address
+	forceSettleCallCount int
+
 	feeToReturn      uint16
 	feeErrorToReturn error
 
@@ -516,9 +728,15 @@ type mockSettler struct {
 }
 
 func (ms *mockSettler) ForceSettle(_ int64, _ identity.Identity, _ ...common.Address) error {
+	ms.forceSettleCallCount++
 	return ms.errToReturn
 }
 
+func (ms *mockSettler) PreviewSettlement(_ int64, _ identity.Identity, beneficiary common.Address, _ ...common.Address) ([]pingpong.SettlementPreview, error) {
+	ms.capturedBeneficiary = beneficiary
+	return ms.previewToReturn, ms.errToReturn
+}
+
 func (ms *mockSettler) ForceSettleAsync(_ int64, _ identity.Identity, _ ...common.Address) error {
 	return ms.errToReturn
 }
@@ -0,0 +1,54 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/mysteriumnetwork/node/requests"
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
+	"github.com/mysteriumnetwork/node/tequilapi/utils"
+)
+
+type upstreamProxyEndpoint struct {
+	proxy *requests.UpstreamProxy
+}
+
+// Status returns upstream proxy the node control traffic is routed through
+// swagger:operation GET /node/upstream-proxy Node upstreamProxyStatus
+//
+//	---
+//	summary: Returns upstream proxy status
+//	description: Returns upstream proxy configured for the node control traffic along with the traffic it applies to. P2P and tunnel traffic is never proxied.
+//	responses:
+//	  200:
+//	    description: Upstream proxy status
+//	    schema:
+//	      "$ref": "#/definitions/UpstreamProxyDTO"
+func (ep *upstreamProxyEndpoint) Status(c *gin.Context) {
+	utils.WriteAsJSON(contract.NewUpstreamProxyDTO(ep.proxy), c.Writer)
+}
+
+// AddRoutesForUpstreamProxy adds upstream proxy routes to given router
+func AddRoutesForUpstreamProxy(proxy *requests.UpstreamProxy) func(*gin.Engine) error {
+	ep := &upstreamProxyEndpoint{proxy: proxy}
+	return func(e *gin.Engine) error {
+		e.GET("/node/upstream-proxy", ep.Status)
+		return nil
+	}
+}
@@ -0,0 +1,59 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/mysteriumnetwork/node/core/watchdog"
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
+	"github.com/mysteriumnetwork/node/tequilapi/utils"
+)
+
+type componentHealthReporter interface {
+	Status() []watchdog.ComponentStatus
+}
+
+type watchdogEndpoint struct {
+	watchdog componentHealthReporter
+}
+
+// Watchdog returns health of the long-running components
+//
+// swagger:operation GET /debug/watchdog Debug getWatchdog
+//
+//	---
+//	summary: Get component health
+//	description: Returns health of the long-running components tracked by the watchdog, stalled components stopped reporting they are alive
+//	responses:
+//	  200:
+//	    description: Component health
+//	    schema:
+//	      "$ref": "#/definitions/WatchdogDTO"
+func (we *watchdogEndpoint) Watchdog(c *gin.Context) {
+	utils.WriteAsJSON(contract.NewWatchdogDTO(we.watchdog.Status()), c.Writer)
+}
+
+// AddRoutesForWatchdog registers /debug/watchdog endpoint in Tequilapi
+func AddRoutesForWatchdog(watchdog componentHealthReporter) func(*gin.Engine) error {
+	we := &watchdogEndpoint{watchdog: watchdog}
+	return func(e *gin.Engine) error {
+		e.GET("/debug/watchdog", we.Watchdog)
+		return nil
+	}
+}
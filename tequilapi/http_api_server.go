@@ -25,6 +25,7 @@ import (
 
 	"github.com/gin-contrib/cors"
 	"github.com/mysteriumnetwork/go-rest/apierror"
+	"github.com/mysteriumnetwork/node/core/auth"
 	"github.com/mysteriumnetwork/node/tequilapi/middlewares"
 
 	"github.com/mysteriumnetwork/node/core/node"
@@ -58,7 +59,11 @@ type apiServer struct {
 }
 
 type jwtAuthenticator interface {
-	ValidateToken(token string) (bool, error)
+	TokenUsername(token string) (string, error)
+}
+
+type roleResolver interface {
+	Role(username string) (auth.Role, error)
 }
 
 // NewServer creates http api server for given address port and http handler
@@ -66,6 +71,7 @@ func NewServer(
 	listener net.Listener,
 	nodeOptions node.Options,
 	authenticator jwtAuthenticator,
+	roles roleResolver,
 	handlers []func(e *gin.Engine) error,
 ) (APIServer, error) {
 	gin.SetMode(modeFromOptions(nodeOptions))
@@ -77,7 +83,7 @@ func NewServer(
 	g.Use(apierror.ErrorHandler)
 
 	if nodeOptions.TequilapiSecured {
-		g.Use(middlewares.ApplyMiddlewareTokenAuth(authenticator))
+		g.Use(middlewares.ApplyMiddlewareTokenAuth(authenticator, roles))
 	}
 
 	// Set to protect localhost-only endpoints due to use of nodeUI proxy
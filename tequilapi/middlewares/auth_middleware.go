@@ -26,12 +26,24 @@ import (
 	"github.com/mysteriumnetwork/node/core/auth"
 )
 
+// UsernameKey is the request context key holding the authenticated username.
+const UsernameKey = "auth_username"
+
+// RoleKey is the request context key holding the role of the authenticated user.
+const RoleKey = "auth_role"
+
 type jwtAuthenticator interface {
-	ValidateToken(token string) (bool, error)
+	TokenUsername(token string) (string, error)
+}
+
+type roleResolver interface {
+	Role(username string) (auth.Role, error)
 }
 
-// ApplyMiddlewareTokenAuth creates token authenticator
-func ApplyMiddlewareTokenAuth(authenticator jwtAuthenticator) gin.HandlerFunc {
+// ApplyMiddlewareTokenAuth creates token authenticator.
+// Requests are only let through if the role of the token user
+// is allowed to call the route, see tequil.RequiredRole.
+func ApplyMiddlewareTokenAuth(authenticator jwtAuthenticator, roles roleResolver) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		if tequil.IsUnprotectedRoute(c.Request.URL.Path) {
 			return
@@ -43,9 +55,25 @@ func ApplyMiddlewareTokenAuth(authenticator jwtAuthenticator) gin.HandlerFunc {
 			return
 		}
 
-		if _, err := authenticator.ValidateToken(token); err != nil {
+		username, err := authenticator.TokenUsername(token)
+		if err != nil {
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+
+		// Role is resolved on every request so that role changes and removed users take effect immediately.
+		role, err := roles.Role(username)
+		if err != nil {
 			c.AbortWithStatus(http.StatusUnauthorized)
 			return
 		}
+
+		if !role.Allows(tequil.RequiredRole(c.Request.Method, c.Request.URL.Path)) {
+			c.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+
+		c.Set(UsernameKey, username)
+		c.Set(RoleKey, role)
 	}
 }
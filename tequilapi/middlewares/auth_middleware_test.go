@@ -26,20 +26,34 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/core/auth"
 )
 
 type MockAuthenticator struct {
 	Err error
 }
 
-func (m *MockAuthenticator) ValidateToken(token string) (bool, error) {
-	return false, m.Err
+func (m *MockAuthenticator) TokenUsername(token string) (string, error) {
+	return "myst", m.Err
 }
 
 func (m *MockAuthenticator) SetErr(err error) {
 	m.Err = err
 }
 
+type mockRoles struct {
+	roles map[string]auth.Role
+}
+
+func (m *mockRoles) Role(username string) (auth.Role, error) {
+	role, ok := m.roles[username]
+	if !ok {
+		return "", auth.ErrUserNotFound
+	}
+	return role, nil
+}
+
 func TestTokenParsing(t *testing.T) {
 	// given
 	authenticator := &MockAuthenticator{Err: errors.New("")}
@@ -49,7 +63,7 @@ func TestTokenParsing(t *testing.T) {
 	respRecorder := httptest.NewRecorder()
 
 	g := gin.Default()
-	g.Use(ApplyMiddlewareTokenAuth(authenticator))
+	g.Use(ApplyMiddlewareTokenAuth(authenticator, &mockRoles{roles: map[string]auth.Role{"myst": auth.RoleAdmin}}))
 
 	// expect
 	g.ServeHTTP(respRecorder, req)
@@ -85,3 +99,43 @@ func TestTokenParsing(t *testing.T) {
 		respRecorder.Code,
 	)
 }
+
+func TestTokenRoles(t *testing.T) {
+	roles := &mockRoles{roles: map[string]auth.Role{"myst": auth.RoleViewer}}
+
+	g := gin.New()
+	g.Use(ApplyMiddlewareTokenAuth(&MockAuthenticator{}, roles))
+	g.GET("/connection", func(c *gin.Context) {
+		assert.Equal(t, "myst", c.GetString(UsernameKey))
+		c.Status(http.StatusOK)
+	})
+	g.PUT("/connection", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	g.GET("/auth/users", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	serve := func(method, url string) int {
+		req, err := http.NewRequest(method, url, nil)
+		assert.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer token")
+		respRecorder := httptest.NewRecorder()
+		g.ServeHTTP(respRecorder, req)
+		return respRecorder.Code
+	}
+
+	assert.Equal(t, http.StatusOK, serve(http.MethodGet, "/connection"))
+	assert.Equal(t, http.StatusForbidden, serve(http.MethodPut, "/connection"))
+	assert.Equal(t, http.StatusForbidden, serve(http.MethodGet, "/auth/users"))
+
+	roles.roles["myst"] = auth.RoleOperator
+	assert.Equal(t, http.StatusOK, serve(http.MethodPut, "/connection"))
+	assert.Equal(t, http.StatusForbidden, serve(http.MethodGet, "/auth/users"))
+
+	roles.roles["myst"] = auth.RoleAdmin
+	assert.Equal(t, http.StatusOK, serve(http.MethodGet, "/auth/users"))
+
+	delete(roles.roles, "myst")
+	assert.Equal(t, http.StatusUnauthorized, serve(http.MethodGet, "/connection"))
+}
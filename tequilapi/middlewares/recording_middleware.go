@@ -0,0 +1,139 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package middlewares
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mysteriumnetwork/go-rest/apierror"
+
+	"github.com/mysteriumnetwork/node/tequilapi/recorder"
+)
+
+type exchangeRecorder interface {
+	Active() bool
+	Record(x recorder.Exchange)
+}
+
+// NewRecordingMiddleware returns middleware passing handled requests and their responses to the recorder
+// while it is active. Requests under skipPrefix, e.g. the ones managing recordings, are not recorded.
+func NewRecordingMiddleware(rec exchangeRecorder, skipPrefix string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !rec.Active() || strings.HasPrefix(c.Request.URL.Path, skipPrefix) {
+			c.Next()
+			return
+		}
+
+		x := recorder.Exchange{
+			StartedAt:     time.Now(),
+			Method:        c.Request.Method,
+			URL:           c.Request.URL.String(),
+			Proto:         c.Request.Proto,
+			RequestHeader: c.Request.Header.Clone(),
+		}
+		if c.Request.Body != nil {
+			x.RequestBody, x.RequestBodySize = readRequestBody(c.Request)
+		}
+
+		w := &recordingWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+		c.Next()
+
+		x.Duration = time.Since(x.StartedAt)
+		x.Status = w.Status()
+		x.ResponseHeader = w.Header().Clone()
+		x.ResponseBody, x.ResponseBodySize = w.body.Bytes(), w.size
+		if w.size > recorder.MaxBodySize {
+			x.ResponseBody = nil
+		}
+		// Errors are written by the error handler after this middleware returns.
+		if err := c.Errors.Last(); err != nil && w.size == 0 {
+			recordError(&x, c, err.Err)
+		}
+
+		rec.Record(x)
+	}
+}
+
+func readRequestBody(r *http.Request) ([]byte, int64) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, recorder.MaxBodySize+1))
+	r.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(body), r.Body), Closer: r.Body}
+	if err != nil || len(body) > recorder.MaxBodySize {
+		size := r.ContentLength
+		if size < int64(len(body)) {
+			size = int64(len(body))
+		}
+		return nil, size
+	}
+	return body, int64(len(body))
+}
+
+func recordError(x *recorder.Exchange, c *gin.Context, err error) {
+	var apiErr *apierror.APIError
+	if !errors.As(err, &apiErr) {
+		apiErr = &apierror.APIError{
+			Err:    apierror.Err{Code: apierror.ErrCodeInternal, Message: err.Error()},
+			Status: http.StatusInternalServerError,
+		}
+	}
+	e := *apiErr
+	e.Path = c.Request.URL.String()
+	blob, merr := json.Marshal(e)
+	if merr != nil {
+		return
+	}
+
+	x.Status = e.Status
+	x.ResponseHeader.Set("Content-Type", apierror.ContentTypeV1)
+	x.ResponseBody, x.ResponseBodySize = blob, int64(len(blob))
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+type recordingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+	size int64
+}
+
+func (w *recordingWriter) Write(b []byte) (int, error) {
+	w.capture(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *recordingWriter) WriteString(s string) (int, error) {
+	w.capture([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *recordingWriter) capture(b []byte) {
+	w.size += int64(len(b))
+	if w.body.Len()+len(b) <= recorder.MaxBodySize {
+		w.body.Write(b)
+	}
+}
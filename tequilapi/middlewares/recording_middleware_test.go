@@ -0,0 +1,129 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package middlewares
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mysteriumnetwork/go-rest/apierror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mysteriumnetwork/node/tequilapi/recorder"
+)
+
+type mockExchangeRecorder struct {
+	active   bool
+	recorded []recorder.Exchange
+}
+
+func (m *mockExchangeRecorder) Active() bool {
+	return m.active
+}
+
+func (m *mockExchangeRecorder) Record(x recorder.Exchange) {
+	m.recorded = append(m.recorded, x)
+}
+
+func TestRecordingMiddlewareRecordsExchanges(t *testing.T) {
+	rec := &mockExchangeRecorder{active: true}
+
+	g := gin.New()
+	g.Use(apierror.ErrorHandler)
+	g.Use(NewRecordingMiddleware(rec, "/debug/recording"))
+	g.POST("/auth/login", func(c *gin.Context) {
+		body, err := io.ReadAll(c.Request.Body)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"username":"myst","password":"secret"}`, string(body))
+		c.JSON(http.StatusOK, gin.H{"token": "jwt"})
+	})
+	g.GET("/sessions/:id", func(c *gin.Context) {
+		c.Error(apierror.NotFound("Session not found"))
+	})
+	g.GET("/debug/recording", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/auth/login", strings.NewReader(`{"username":"myst","password":"secret"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	g.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	resp = httptest.NewRecorder()
+	g.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/sessions/1", nil))
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+
+	g.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/debug/recording", nil))
+
+	require.Len(t, rec.recorded, 2)
+
+	login := rec.recorded[0]
+	assert.Equal(t, http.MethodPost, login.Method)
+	assert.Equal(t, "/auth/login", login.URL)
+	assert.Equal(t, `{"username":"myst","password":"secret"}`, string(login.RequestBody))
+	assert.Equal(t, http.StatusOK, login.Status)
+	assert.JSONEq(t, `{"token":"jwt"}`, string(login.ResponseBody))
+
+	notFound := rec.recorded[1]
+	assert.Equal(t, http.StatusNotFound, notFound.Status)
+	assert.Equal(t, apierror.ContentTypeV1, notFound.ResponseHeader.Get("Content-Type"))
+	assert.Contains(t, string(notFound.ResponseBody), "Session not found")
+}
+
+func TestRecordingMiddlewarePassesThroughWhileInactive(t *testing.T) {
+	rec := &mockExchangeRecorder{}
+
+	g := gin.New()
+	g.Use(NewRecordingMiddleware(rec, "/debug/recording"))
+	g.GET("/healthcheck", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	resp := httptest.NewRecorder()
+	g.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/healthcheck", nil))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Empty(t, rec.recorded)
+}
+
+func TestRecordingMiddlewareOmitsLargeBodies(t *testing.T) {
+	rec := &mockExchangeRecorder{active: true}
+	large := strings.Repeat("a", recorder.MaxBodySize+1)
+
+	g := gin.New()
+	g.Use(NewRecordingMiddleware(rec, "/debug/recording"))
+	g.POST("/feedback", func(c *gin.Context) {
+		body, err := io.ReadAll(c.Request.Body)
+		assert.NoError(t, err)
+		assert.Len(t, body, len(large))
+		c.String(http.StatusOK, large)
+	})
+
+	g.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/feedback", strings.NewReader(large)))
+
+	require.Len(t, rec.recorded, 1)
+	assert.Nil(t, rec.recorded[0].RequestBody)
+	assert.Equal(t, int64(len(large)), rec.recorded[0].RequestBodySize)
+	assert.Nil(t, rec.recorded[0].ResponseBody)
+	assert.Equal(t, int64(len(large)), rec.recorded[0].ResponseBodySize)
+}
@@ -0,0 +1,69 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package middlewares
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mysteriumnetwork/go-rest/apierror"
+
+	"github.com/mysteriumnetwork/node/eventbus"
+	"github.com/mysteriumnetwork/node/trace"
+)
+
+// TraceIDKey is the request context key of the ID correlating the request span
+// with the flow started by the request, e.g. the session ID.
+const TraceIDKey = "trace_id"
+
+// NewTraceMiddleware returns middleware publishing a span for every handled request.
+func NewTraceMiddleware(publisher eventbus.Publisher) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+
+		status := c.Writer.Status()
+		span := trace.Span{
+			ID:    c.GetString(TraceIDKey),
+			Name:  "API " + c.Request.Method + " " + route,
+			Start: start,
+			End:   time.Now(),
+		}
+		if err := c.Errors.Last(); err != nil {
+			var apiErr *apierror.APIError
+			if errors.As(err.Err, &apiErr) {
+				status = apiErr.Status
+			}
+			span.Error = err.Error()
+		}
+		span.Attributes = map[string]string{
+			"http.method":      c.Request.Method,
+			"http.route":       route,
+			"http.status_code": strconv.Itoa(status),
+		}
+
+		publisher.Publish(trace.AppTopicTraceSpan, span)
+	}
+}
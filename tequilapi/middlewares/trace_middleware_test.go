@@ -0,0 +1,75 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mysteriumnetwork/go-rest/apierror"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/trace"
+)
+
+type mockPublisher struct {
+	published []interface{}
+}
+
+func (mp *mockPublisher) Publish(_ string, data interface{}) {
+	mp.published = append(mp.published, data)
+}
+
+func TestTraceMiddlewarePublishesRequestSpan(t *testing.T) {
+	publisher := &mockPublisher{}
+
+	g := gin.New()
+	g.Use(apierror.ErrorHandler)
+	g.Use(NewTraceMiddleware(publisher))
+	g.POST("/connection", func(c *gin.Context) {
+		c.Set(TraceIDKey, "session-1")
+		c.Status(http.StatusCreated)
+	})
+	g.GET("/sessions/:id", func(c *gin.Context) {
+		c.Error(apierror.NotFound("Session not found"))
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/connection", nil)
+	g.ServeHTTP(httptest.NewRecorder(), req)
+
+	req = httptest.NewRequest(http.MethodGet, "/sessions/1", nil)
+	g.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Len(t, publisher.published, 2)
+
+	span := publisher.published[0].(trace.Span)
+	assert.Equal(t, "session-1", span.ID)
+	assert.Equal(t, "API POST /connection", span.Name)
+	assert.Empty(t, span.Error)
+	assert.Equal(t, "201", span.Attributes["http.status_code"])
+	assert.False(t, span.End.Before(span.Start))
+
+	span = publisher.published[1].(trace.Span)
+	assert.Empty(t, span.ID)
+	assert.Equal(t, "API GET /sessions/:id", span.Name)
+	assert.Equal(t, "/sessions/:id", span.Attributes["http.route"])
+	assert.Equal(t, "404", span.Attributes["http.status_code"])
+	assert.NotEmpty(t, span.Error)
+}
@@ -0,0 +1,233 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package recorder
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const creatorName = "mysterium-node"
+
+// Exchange is a single request and response handled by Tequilapi.
+type Exchange struct {
+	StartedAt time.Time
+	Duration  time.Duration
+
+	Method string
+	URL    string
+	Proto  string
+
+	RequestHeader http.Header
+	// RequestBody is nil when the body was longer than MaxBodySize.
+	RequestBody     []byte
+	RequestBodySize int64
+
+	Status         int
+	ResponseHeader http.Header
+	// ResponseBody is nil when the body was longer than MaxBodySize.
+	ResponseBody     []byte
+	ResponseBodySize int64
+}
+
+type archive struct {
+	Log harLog `json:"log"`
+}
+
+type harLog struct {
+	Version string            `json:"version"`
+	Creator harCreator        `json:"creator"`
+	Entries []json.RawMessage `json:"entries"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+}
+
+type harRequest struct {
+	Method      string      `json:"method"`
+	URL         string      `json:"url"`
+	HTTPVersion string      `json:"httpVersion"`
+	Headers     []harNVP    `json:"headers"`
+	QueryString []harNVP    `json:"queryString"`
+	Cookies     []harNVP    `json:"cookies"`
+	PostData    *harContent `json:"postData,omitempty"`
+	HeadersSize int         `json:"headersSize"`
+	BodySize    int64       `json:"bodySize"`
+}
+
+type harResponse struct {
+	Status      int        `json:"status"`
+	StatusText  string     `json:"statusText"`
+	HTTPVersion string     `json:"httpVersion"`
+	Headers     []harNVP   `json:"headers"`
+	Cookies     []harNVP   `json:"cookies"`
+	Content     harContent `json:"content"`
+	RedirectURL string     `json:"redirectURL"`
+	HeadersSize int        `json:"headersSize"`
+	BodySize    int64      `json:"bodySize"`
+}
+
+type harNVP struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harContent struct {
+	Size     int64  `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+	Comment  string `json:"comment,omitempty"`
+}
+
+type harTimings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+func newArchive(version string, entries []json.RawMessage) archive {
+	if entries == nil {
+		entries = []json.RawMessage{}
+	}
+	return archive{Log: harLog{
+		Version: "1.2",
+		Creator: harCreator{Name: creatorName, Version: version},
+		Entries: entries,
+	}}
+}
+
+func newEntry(x Exchange) harEntry {
+	ms := float64(x.Duration) / float64(time.Millisecond)
+	entry := harEntry{
+		StartedDateTime: x.StartedAt.UTC().Format(time.RFC3339Nano),
+		Time:            ms,
+		Request: harRequest{
+			Method:      x.Method,
+			HTTPVersion: x.Proto,
+			Headers:     redactHeaders(x.RequestHeader),
+			Cookies:     []harNVP{},
+			HeadersSize: -1,
+			BodySize:    x.RequestBodySize,
+		},
+		Response: harResponse{
+			Status:      x.Status,
+			StatusText:  http.StatusText(x.Status),
+			HTTPVersion: x.Proto,
+			Headers:     redactHeaders(x.ResponseHeader),
+			Cookies:     []harNVP{},
+			Content:     newContent(x.ResponseHeader, x.ResponseBody, x.ResponseBodySize),
+			HeadersSize: -1,
+			BodySize:    x.ResponseBodySize,
+		},
+		Timings: harTimings{Wait: ms},
+	}
+	entry.Request.URL, entry.Request.QueryString = redactURL(x.URL)
+	if x.RequestBodySize > 0 {
+		content := newContent(x.RequestHeader, x.RequestBody, x.RequestBodySize)
+		entry.Request.PostData = &content
+	}
+	return entry
+}
+
+func newContent(header http.Header, body []byte, size int64) harContent {
+	content := harContent{Size: size, MimeType: header.Get("Content-Type")}
+	switch {
+	case size == 0:
+	case body == nil || int64(len(body)) < size:
+		content.Comment = "body omitted, it exceeds the recording limit"
+	case !isJSON(content.MimeType, body):
+		content.Comment = "body omitted, only JSON bodies are recorded"
+	default:
+		text, err := redactJSON(body)
+		if err != nil {
+			content.Comment = "body omitted, it could not be redacted"
+			break
+		}
+		content.Text = text
+	}
+	return content
+}
+
+func isJSON(mimeType string, body []byte) bool {
+	if mimeType != "" {
+		mt, _, err := mime.ParseMediaType(mimeType)
+		if err != nil || (mt != "application/json" && !strings.HasSuffix(mt, "+json")) {
+			return false
+		}
+	}
+	return json.Valid(body)
+}
+
+func redactHeaders(header http.Header) []harNVP {
+	nvps := make([]harNVP, 0, len(header))
+	for name, values := range header {
+		for _, v := range values {
+			if isSensitiveHeader(name) {
+				v = redacted
+			}
+			nvps = append(nvps, harNVP{Name: name, Value: v})
+		}
+	}
+	sort.SliceStable(nvps, func(i, j int) bool {
+		return nvps[i].Name < nvps[j].Name
+	})
+	return nvps
+}
+
+func redactURL(raw string) (string, []harNVP) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return raw, []harNVP{}
+	}
+
+	query := u.Query()
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	nvps := make([]harNVP, 0, len(query))
+	for _, name := range names {
+		for i, v := range query[name] {
+			if isSensitiveKey(name) {
+				v = redacted
+				query[name][i] = v
+			}
+			nvps = append(nvps, harNVP{Name: name, Value: v})
+		}
+	}
+	u.RawQuery = query.Encode()
+	return u.String(), nvps
+}
@@ -0,0 +1,293 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package recorder records Tequilapi requests and responses into local HAR
+// files for a bounded time window, so UI developers can reproduce and report
+// backend interaction bugs precisely. Secrets are redacted before anything is
+// written and the files never leave the device.
+package recorder
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// DefaultMaxDuration is the longest recording allowed unless configured otherwise.
+	DefaultMaxDuration = 30 * time.Minute
+	// DefaultMaxSize is the largest recording allowed unless configured otherwise.
+	DefaultMaxSize = 16 << 20
+	// MaxBodySize is the largest request or response body recorded, longer ones are omitted.
+	MaxBodySize = 256 << 10
+
+	fileExt = ".har"
+)
+
+var (
+	// ErrRunning is returned when a recording is already in progress.
+	ErrRunning = errors.New("recording is already running")
+	// ErrNotRunning is returned when there is no recording in progress.
+	ErrNotRunning = errors.New("recording is not running")
+	// ErrNotFound is returned when the requested recording file does not exist.
+	ErrNotFound = errors.New("recording not found")
+)
+
+// Config configures the recorder.
+type Config struct {
+	// Dir is where recording files are stored.
+	Dir string
+	// MaxDuration limits the duration of a single recording.
+	MaxDuration time.Duration
+	// MaxSize limits the size in bytes of a single recording file.
+	MaxSize int64
+	// Version is the node version noted as the creator of recordings.
+	Version string
+}
+
+// Info describes a recording.
+type Info struct {
+	Name      string
+	StartedAt time.Time
+	// StoppedAt is zero while the recording is running.
+	StoppedAt time.Time
+	Entries   int
+	Size      int64
+}
+
+// File describes a stored recording file.
+type File struct {
+	Name      string
+	Size      int64
+	CreatedAt time.Time
+}
+
+// Recorder records Tequilapi exchanges of a single time window at a time.
+type Recorder struct {
+	cfg Config
+
+	active atomic.Bool
+	lock   sync.Mutex
+	run    *run
+	last   *Info
+}
+
+type run struct {
+	info    Info
+	entries []json.RawMessage
+	timer   *time.Timer
+}
+
+// NewRecorder returns a new recorder storing recordings in the configured directory.
+func NewRecorder(cfg Config) *Recorder {
+	if cfg.MaxDuration <= 0 {
+		cfg.MaxDuration = DefaultMaxDuration
+	}
+	if cfg.MaxSize <= 0 {
+		cfg.MaxSize = DefaultMaxSize
+	}
+	return &Recorder{cfg: cfg}
+}
+
+// MaxDuration returns the longest recording allowed.
+func (r *Recorder) MaxDuration() time.Duration {
+	return r.cfg.MaxDuration
+}
+
+// Active reports whether exchanges are being recorded.
+func (r *Recorder) Active() bool {
+	return r.active.Load()
+}
+
+// Start starts recording exchanges for the given duration, which is limited by the configured maximum.
+func (r *Recorder) Start(duration time.Duration) (Info, error) {
+	if duration <= 0 || duration > r.cfg.MaxDuration {
+		duration = r.cfg.MaxDuration
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.run != nil {
+		return Info{}, ErrRunning
+	}
+
+	if err := os.MkdirAll(r.cfg.Dir, 0700); err != nil {
+		return Info{}, fmt.Errorf("could not create recording directory: %w", err)
+	}
+
+	now := time.Now().UTC()
+	rn := &run{
+		info: Info{Name: fmt.Sprintf("tequilapi_%d%s", now.UnixNano(), fileExt), StartedAt: now},
+	}
+	rn.timer = time.AfterFunc(duration, func() {
+		r.stopRun(rn)
+	})
+	r.run = rn
+	r.active.Store(true)
+
+	log.Info().Msgf("Started recording Tequilapi exchanges for %s into %s", duration, rn.info.Name)
+	return rn.info, nil
+}
+
+// Stop stops the running recording and writes it to a file.
+func (r *Recorder) Stop() (Info, error) {
+	r.lock.Lock()
+	rn := r.run
+	r.lock.Unlock()
+
+	if rn == nil {
+		return Info{}, ErrNotRunning
+	}
+	return r.stopRun(rn)
+}
+
+// Status returns the running recording or the last finished one.
+func (r *Recorder) Status() (Info, bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.run != nil {
+		return r.run.info, true
+	}
+	if r.last != nil {
+		return *r.last, true
+	}
+	return Info{}, false
+}
+
+// List returns the stored recording files, newest first.
+func (r *Recorder) List() ([]File, error) {
+	entries, err := os.ReadDir(r.cfg.Dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]File, 0, len(entries))
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), fileExt) {
+			continue
+		}
+		fi, err := e.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, File{Name: e.Name(), Size: fi.Size(), CreatedAt: fi.ModTime()})
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].CreatedAt.After(files[j].CreatedAt)
+	})
+	return files, nil
+}
+
+// Open opens the stored recording file for reading.
+func (r *Recorder) Open(name string) (*os.File, error) {
+	if name != filepath.Base(name) || !strings.HasSuffix(name, fileExt) {
+		return nil, ErrNotFound
+	}
+
+	f, err := os.Open(filepath.Join(r.cfg.Dir, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return f, err
+}
+
+// Record redacts and appends the exchange to the running recording.
+// Recording stops once it reaches the configured size.
+func (r *Recorder) Record(x Exchange) {
+	if !r.Active() {
+		return
+	}
+
+	entry, err := json.Marshal(newEntry(x))
+	if err != nil {
+		log.Warn().Err(err).Msg("Could not record Tequilapi exchange")
+		return
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	rn := r.run
+	if rn == nil {
+		return
+	}
+	if rn.info.Size+int64(len(entry)) > r.cfg.MaxSize {
+		r.active.Store(false)
+		go r.stopRun(rn)
+		return
+	}
+	rn.entries = append(rn.entries, entry)
+	rn.info.Entries++
+	rn.info.Size += int64(len(entry))
+}
+
+func (r *Recorder) stopRun(rn *run) (Info, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.run != rn {
+		return Info{}, ErrNotRunning
+	}
+
+	rn.timer.Stop()
+	r.active.Store(false)
+	r.run = nil
+
+	rn.info.StoppedAt = time.Now().UTC()
+	err := r.write(rn)
+	info := rn.info
+	r.last = &info
+
+	log.Info().Msgf("Stopped recording Tequilapi exchanges, %d exchanges recorded", info.Entries)
+	if err != nil {
+		return info, fmt.Errorf("could not write recording file: %w", err)
+	}
+	return info, nil
+}
+
+func (r *Recorder) write(rn *run) error {
+	blob, err := json.MarshalIndent(newArchive(r.cfg.Version, rn.entries), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(r.cfg.Dir, rn.info.Name)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, blob, 0600); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	rn.info.Size = int64(len(blob))
+	return nil
+}
@@ -0,0 +1,193 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package recorder
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func loginExchange() Exchange {
+	x := Exchange{
+		StartedAt: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		Duration:  15 * time.Millisecond,
+		Method:    http.MethodPost,
+		URL:       "/auth/login?remember=true&token=abc",
+		Proto:     "HTTP/1.1",
+		RequestHeader: http.Header{
+			"Content-Type":  {"application/json"},
+			"Authorization": {"Bearer abc"},
+		},
+		RequestBody: []byte(`{"username":"myst","password":"secret","nested":[{"referral_token":"ref","balance_tokens":10}]}`),
+		Status:      http.StatusOK,
+		ResponseHeader: http.Header{
+			"Content-Type": {"application/json"},
+			"Set-Cookie":   {"token=abc"},
+		},
+		ResponseBody: []byte(`{"token":"jwt","expires_at":"2024-05-02T12:00:00Z"}`),
+	}
+	x.RequestBodySize = int64(len(x.RequestBody))
+	x.ResponseBodySize = int64(len(x.ResponseBody))
+	return x
+}
+
+func TestRecorder_RecordsRedactedExchanges(t *testing.T) {
+	rec := NewRecorder(Config{Dir: t.TempDir(), Version: "1.0.0"})
+
+	rec.Record(loginExchange())
+	_, ok := rec.Status()
+	assert.False(t, ok, "exchanges are not recorded before start")
+
+	started, err := rec.Start(time.Minute)
+	require.NoError(t, err)
+	assert.True(t, rec.Active())
+
+	_, err = rec.Start(time.Minute)
+	assert.ErrorIs(t, err, ErrRunning)
+
+	rec.Record(loginExchange())
+	stopped, err := rec.Stop()
+	require.NoError(t, err)
+	assert.False(t, rec.Active())
+	assert.Equal(t, started.Name, stopped.Name)
+	assert.Equal(t, 1, stopped.Entries)
+
+	_, err = rec.Stop()
+	assert.ErrorIs(t, err, ErrNotRunning)
+
+	files, err := rec.List()
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, stopped.Name, files[0].Name)
+	assert.Equal(t, stopped.Size, files[0].Size)
+
+	f, err := rec.Open(stopped.Name)
+	require.NoError(t, err)
+	defer f.Close()
+	blob, err := io.ReadAll(f)
+	require.NoError(t, err)
+
+	var har struct {
+		Log struct {
+			Version string
+			Creator harCreator
+			Entries []harEntry
+		}
+	}
+	require.NoError(t, json.Unmarshal(blob, &har))
+	assert.Equal(t, "1.2", har.Log.Version)
+	assert.Equal(t, harCreator{Name: creatorName, Version: "1.0.0"}, har.Log.Creator)
+	require.Len(t, har.Log.Entries, 1)
+
+	entry := har.Log.Entries[0]
+	assert.Equal(t, "2024-05-01T12:00:00Z", entry.StartedDateTime)
+	assert.Equal(t, float64(15), entry.Time)
+	assert.Equal(t, "/auth/login?remember=true&token=%5BREDACTED%5D", entry.Request.URL)
+	assert.Equal(t, []harNVP{{Name: "remember", Value: "true"}, {Name: "token", Value: redacted}}, entry.Request.QueryString)
+	assert.Contains(t, entry.Request.Headers, harNVP{Name: "Authorization", Value: redacted})
+	require.NotNil(t, entry.Request.PostData)
+	assert.JSONEq(t, `{"username":"myst","password":"[REDACTED]","nested":[{"referral_token":"[REDACTED]","balance_tokens":10}]}`, entry.Request.PostData.Text)
+	assert.Equal(t, http.StatusOK, entry.Response.Status)
+	assert.Equal(t, "OK", entry.Response.StatusText)
+	assert.Contains(t, entry.Response.Headers, harNVP{Name: "Set-Cookie", Value: redacted})
+	assert.JSONEq(t, `{"token":"[REDACTED]","expires_at":"2024-05-02T12:00:00Z"}`, entry.Response.Content.Text)
+	assert.NotContains(t, string(blob), "secret")
+	assert.NotContains(t, string(blob), "jwt")
+}
+
+func TestRecorder_OmitsBodiesItCannotRedact(t *testing.T) {
+	x := loginExchange()
+	x.RequestBody = nil
+	x.ResponseHeader.Set("Content-Type", "text/plain")
+	x.ResponseBody = []byte("password=secret")
+	x.ResponseBodySize = int64(len(x.ResponseBody))
+
+	entry := newEntry(x)
+	require.NotNil(t, entry.Request.PostData)
+	assert.Empty(t, entry.Request.PostData.Text)
+	assert.NotEmpty(t, entry.Request.PostData.Comment)
+	assert.Empty(t, entry.Response.Content.Text)
+	assert.NotEmpty(t, entry.Response.Content.Comment)
+}
+
+func TestRecorder_StopsAtMaxSize(t *testing.T) {
+	rec := NewRecorder(Config{Dir: t.TempDir(), MaxSize: 2048})
+	_, err := rec.Start(time.Minute)
+	require.NoError(t, err)
+
+	for i := 0; i < 10 && rec.Active(); i++ {
+		rec.Record(loginExchange())
+	}
+	assert.False(t, rec.Active())
+
+	assert.Eventually(t, func() bool {
+		info, ok := rec.Status()
+		return ok && !info.StoppedAt.IsZero()
+	}, time.Second, 10*time.Millisecond)
+	info, _ := rec.Status()
+	assert.Greater(t, info.Entries, 0)
+	assert.Less(t, info.Entries, 10)
+}
+
+func TestRecorder_StopsAfterDuration(t *testing.T) {
+	rec := NewRecorder(Config{Dir: t.TempDir()})
+	_, err := rec.Start(10 * time.Millisecond)
+	require.NoError(t, err)
+
+	assert.Eventually(t, func() bool {
+		return !rec.Active()
+	}, time.Second, 5*time.Millisecond)
+	files, err := rec.List()
+	require.NoError(t, err)
+	assert.Len(t, files, 1)
+}
+
+func TestRecorder_Open(t *testing.T) {
+	rec := NewRecorder(Config{Dir: t.TempDir()})
+
+	for _, name := range []string{"missing.har", "../secrets.har", "tequilapi.json"} {
+		_, err := rec.Open(name)
+		assert.ErrorIs(t, err, ErrNotFound, name)
+	}
+}
+
+func TestIsSensitiveKey(t *testing.T) {
+	for key, sensitive := range map[string]bool{
+		"password":           true,
+		"new_passphrase":     true,
+		"api_key":            true,
+		"apiKey":             true,
+		"token":              true,
+		"confirmation_token": true,
+		"authorizationGrant": true,
+		"codeVerifierBase":   true,
+		"balance_tokens":     false,
+		"identity":           false,
+		"username":           false,
+	} {
+		assert.Equal(t, sensitive, isSensitiveKey(key), key)
+	}
+	assert.False(t, strings.Contains(redacted, "secret"))
+}
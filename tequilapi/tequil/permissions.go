@@ -0,0 +1,93 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package tequil
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/mysteriumnetwork/node/core/auth"
+)
+
+// RoutePermission requires a role for requests matching the method and the route pattern.
+// Empty method matches any method, "*" matches a single path segment
+// and the pattern matches the route itself as well as any route below it.
+type RoutePermission struct {
+	Method  string
+	Pattern string
+	Role    auth.Role
+}
+
+// RoutePermissions overrides the default role required by the request method.
+// The first matching permission wins.
+var RoutePermissions = []RoutePermission{
+	{Pattern: "/auth/users", Role: auth.RoleAdmin},
+	{Method: http.MethodPut, Pattern: "/auth/password", Role: auth.RoleViewer},
+	{Method: http.MethodDelete, Pattern: "/auth/logout", Role: auth.RoleViewer},
+	{Method: http.MethodPost, Pattern: "/stop", Role: auth.RoleAdmin},
+	{Pattern: "/debug/pprof", Role: auth.RoleAdmin},
+	{Pattern: "/debug/pcap", Role: auth.RoleAdmin},
+	{Pattern: "/debug/recording", Role: auth.RoleAdmin},
+	{Method: http.MethodPost, Pattern: "/identities/export", Role: auth.RoleAdmin},
+	{Method: http.MethodPost, Pattern: "/identities-import", Role: auth.RoleAdmin},
+	{Method: http.MethodPut, Pattern: "/identities-unlock-policy", Role: auth.RoleAdmin},
+	{Method: http.MethodPut, Pattern: "/identities/*/passphrase", Role: auth.RoleAdmin},
+	{Method: http.MethodPost, Pattern: "/identities/*/beneficiary", Role: auth.RoleAdmin},
+	{Method: http.MethodPost, Pattern: "/identities/*/beneficiary-async", Role: auth.RoleAdmin},
+	{Method: http.MethodPost, Pattern: "/transactor/settle/withdraw", Role: auth.RoleAdmin},
+	{Method: http.MethodPost, Pattern: "/transactor/stake/decrease", Role: auth.RoleAdmin},
+	{Method: http.MethodPost, Pattern: "/address-book", Role: auth.RoleAdmin},
+	{Method: http.MethodDelete, Pattern: "/address-book", Role: auth.RoleAdmin},
+	{Pattern: "/mmn/api-key", Role: auth.RoleAdmin},
+	{Method: http.MethodPost, Pattern: "/ui/switch-version", Role: auth.RoleAdmin},
+	{Method: http.MethodPost, Pattern: "/ui/download-version", Role: auth.RoleAdmin},
+}
+
+// RequiredRole returns the lowest role allowed to call the route.
+// Reading is allowed for viewers and changes for operators unless RoutePermissions says otherwise.
+func RequiredRole(method, url string) auth.Role {
+	for _, p := range RoutePermissions {
+		if p.Method != "" && p.Method != method {
+			continue
+		}
+		if matchRoute(p.Pattern, url) {
+			return p.Role
+		}
+	}
+
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return auth.RoleViewer
+	default:
+		return auth.RoleOperator
+	}
+}
+
+func matchRoute(pattern, url string) bool {
+	patternParts := strings.Split(strings.Trim(pattern, "/"), "/")
+	urlParts := strings.Split(strings.Trim(url, "/"), "/")
+	if len(urlParts) < len(patternParts) {
+		return false
+	}
+	for i, part := range patternParts {
+		if part != "*" && part != urlParts[i] {
+			return false
+		}
+	}
+	return true
+}
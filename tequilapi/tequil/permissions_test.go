@@ -0,0 +1,62 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package tequil
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/core/auth"
+)
+
+func TestRequiredRole(t *testing.T) {
+	for _, tc := range []struct {
+		method string
+		url    string
+		role   auth.Role
+	}{
+		{http.MethodGet, "/connection", auth.RoleViewer},
+		{http.MethodPut, "/connection", auth.RoleOperator},
+		{http.MethodDelete, "/connection", auth.RoleOperator},
+		{http.MethodGet, "/auth/users", auth.RoleAdmin},
+		{http.MethodDelete, "/auth/users/alice", auth.RoleAdmin},
+		{http.MethodPut, "/auth/password", auth.RoleViewer},
+		{http.MethodPut, "/identities/0x1/passphrase", auth.RoleAdmin},
+		{http.MethodPut, "/identities/0x1/unlock", auth.RoleOperator},
+		{http.MethodGet, "/identities/0x1/beneficiary", auth.RoleViewer},
+		{http.MethodPost, "/identities/0x1/beneficiary", auth.RoleAdmin},
+		{http.MethodPost, "/identities-import", auth.RoleAdmin},
+		{http.MethodGet, "/identities-unlock-policy", auth.RoleViewer},
+		{http.MethodPut, "/identities-unlock-policy", auth.RoleAdmin},
+		{http.MethodGet, "/identities-importer", auth.RoleViewer},
+		{http.MethodGet, "/debug/pprof/heap", auth.RoleAdmin},
+		{http.MethodGet, "/debug/pcap/session_1.pcap", auth.RoleAdmin},
+		{http.MethodPost, "/debug/pcap", auth.RoleAdmin},
+		{http.MethodGet, "/debug/recording", auth.RoleAdmin},
+		{http.MethodPost, "/debug/recording/stop", auth.RoleAdmin},
+		{http.MethodGet, "/address-book", auth.RoleViewer},
+		{http.MethodPost, "/address-book", auth.RoleAdmin},
+		{http.MethodDelete, "/address-book/exchange", auth.RoleAdmin},
+	} {
+		t.Run(tc.method+" "+tc.url, func(t *testing.T) {
+			assert.Equal(t, tc.role, RequiredRole(tc.method, tc.url))
+		})
+	}
+}
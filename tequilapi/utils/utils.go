@@ -18,10 +18,13 @@
 package utils
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/mysteriumnetwork/go-rest/apierror"
@@ -49,6 +52,41 @@ func WriteAsJSON(v interface{}, writer http.ResponseWriter, httpCode ...int) {
 	}
 }
 
+// WriteAsJSONWithETag writes a given value `v` as JSON together with ETag header
+// computed from the response content. If the request contains matching
+// If-None-Match header, only 304 Not Modified status is written.
+func WriteAsJSONWithETag(v interface{}, req *http.Request, writer http.ResponseWriter) {
+	blob, err := json.Marshal(v)
+	if err != nil {
+		http.Error(writer, "Http response write error", http.StatusInternalServerError)
+		return
+	}
+
+	sum := sha256.Sum256(blob)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	writer.Header().Set("ETag", etag)
+
+	if etagMatches(req.Header.Get("If-None-Match"), etag) {
+		writer.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	writer.Header().Set("Content-type", "application/json; charset=utf-8")
+	if _, err := writer.Write(blob); err != nil {
+		log.Error().Err(err).Msg("Writing response body failed")
+	}
+}
+
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == etag || candidate == "*" {
+			return true
+		}
+	}
+	return false
+}
+
 // swagger:model APIError
 type apiErrorSwagger struct {
 	apierror.APIError
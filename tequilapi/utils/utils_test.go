@@ -18,6 +18,7 @@
 package utils
 
 import (
+	"net/http"
 	"net/http/httptest"
 	"testing"
 
@@ -46,3 +47,25 @@ func TestWriteAsJSONReturnsExpectedResponse(t *testing.T) {
 		}`,
 		respRecorder.Body.String())
 }
+
+func TestWriteAsJSONWithETagReturnsNotModified(t *testing.T) {
+	respRecorder := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	WriteAsJSONWithETag(map[string]int{"version": 1}, req, respRecorder)
+
+	etag := respRecorder.Result().Header.Get("ETag")
+	assert.NotEmpty(t, etag)
+	assert.Equal(t, http.StatusOK, respRecorder.Code)
+	assert.JSONEq(t, `{"version": 1}`, respRecorder.Body.String())
+
+	respRecorder = httptest.NewRecorder()
+	req.Header.Set("If-None-Match", etag)
+	WriteAsJSONWithETag(map[string]int{"version": 1}, req, respRecorder)
+	assert.Equal(t, http.StatusNotModified, respRecorder.Code)
+	assert.Empty(t, respRecorder.Body.String())
+
+	respRecorder = httptest.NewRecorder()
+	WriteAsJSONWithETag(map[string]int{"version": 2}, req, respRecorder)
+	assert.Equal(t, http.StatusOK, respRecorder.Code)
+	assert.NotEqual(t, etag, respRecorder.Result().Header.Get("ETag"))
+}
@@ -0,0 +1,145 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package fakeapi
+
+import (
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Hermes error causes understood by the node.
+const (
+	HermesCauseInternal        = "internal error"
+	HermesCauseNotFound        = "resource not found"
+	HermesCauseTooManyRequests = "too many simultaneous requests"
+)
+
+// UserInfo is the consumer or provider data returned by hermes.
+type UserInfo struct {
+	Identity         string        `json:"Identity"`
+	Beneficiary      string        `json:"Beneficiary"`
+	ChannelID        string        `json:"ChannelID"`
+	Balance          *big.Int      `json:"Balance"`
+	Settled          *big.Int      `json:"Settled"`
+	Stake            *big.Int      `json:"Stake"`
+	LatestPromise    LatestPromise `json:"LatestPromise"`
+	LatestSettlement time.Time     `json:"LatestSettlement"`
+	IsOffchain       bool          `json:"IsOffchain"`
+}
+
+// LatestPromise is the latest promise of the user known to hermes.
+type LatestPromise struct {
+	ChainID   int64    `json:"ChainID"`
+	ChannelID string   `json:"ChannelID"`
+	Amount    *big.Int `json:"Amount"`
+	Fee       *big.Int `json:"Fee"`
+	Hashlock  string   `json:"Hashlock"`
+	Signature string   `json:"Signature"`
+}
+
+// Hermes is a fake hermes API.
+//
+// By default it accepts revealed R and synced promises, serves the data set with SetConsumer and SetProvider
+// and responds with an internal error to promise requests, which have to be scripted with On.
+type Hermes struct {
+	Server
+
+	lock      sync.Mutex
+	consumers map[string]map[int64]UserInfo
+	providers map[string]map[int64]UserInfo
+}
+
+// NewHermes returns a new fake hermes, call Start to serve it.
+func NewHermes() *Hermes {
+	h := &Hermes{
+		consumers: make(map[string]map[int64]UserInfo),
+		providers: make(map[string]map[int64]UserInfo),
+	}
+
+	for _, pattern := range []string{"request_promise", "pay_and_settle", "change_promise_fee", "refresh_promise"} {
+		h.On(http.MethodPost, pattern, HermesError(http.StatusInternalServerError, HermesCauseInternal))
+	}
+	h.On(http.MethodPost, "reveal_r", OK(nil))
+	h.On(http.MethodPost, "provider/sync_promise", OK(nil))
+	h.OnFunc(http.MethodGet, "data/consumer/*", func(r Request) Response {
+		return h.userInfo(h.consumers, r)
+	})
+	h.OnFunc(http.MethodGet, "data/provider/*", func(r Request) Response {
+		return h.userInfo(h.providers, r)
+	})
+
+	return h
+}
+
+// SetConsumer sets the consumer data of the identity on the given chain.
+func (h *Hermes) SetConsumer(chainID int64, info UserInfo) {
+	h.setUserInfo(h.consumers, chainID, info)
+}
+
+// SetProvider sets the provider data of the identity on the given chain.
+func (h *Hermes) SetProvider(chainID int64, info UserInfo) {
+	h.setUserInfo(h.providers, chainID, info)
+}
+
+// HermesError returns the hermes error response with the given cause.
+func HermesError(status int, cause string) Response {
+	return Response{
+		Status: status,
+		Body: hermesError{
+			Cause:   cause,
+			Message: cause,
+		},
+	}
+}
+
+func (h *Hermes) setUserInfo(users map[string]map[int64]UserInfo, chainID int64, info UserInfo) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	id := strings.ToLower(info.Identity)
+	if users[id] == nil {
+		users[id] = make(map[int64]UserInfo)
+	}
+	users[id][chainID] = info
+}
+
+func (h *Hermes) userInfo(users map[string]map[int64]UserInfo, r Request) Response {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	id := strings.ToLower(r.Path[strings.LastIndex(r.Path, "/")+1:])
+	chains, ok := users[id]
+	if !ok {
+		return HermesError(http.StatusNotFound, HermesCauseNotFound)
+	}
+
+	info := make(map[int64]UserInfo, len(chains))
+	for chainID, data := range chains {
+		info[chainID] = data
+	}
+	return OK(info)
+}
+
+type hermesError struct {
+	Cause   string `json:"cause"`
+	Message string `json:"message"`
+	Data    string `json:"data,omitempty"`
+}
@@ -0,0 +1,42 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package fakeapi
+
+import (
+	"math/big"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHermes_UserInfo(t *testing.T) {
+	h := NewHermes()
+	url := h.Start()
+	defer h.Close()
+
+	h.SetProvider(80001, UserInfo{Identity: "0x000000000000000000000000000000000000000A", Balance: big.NewInt(5)})
+
+	var info map[int64]UserInfo
+	assert.Equal(t, http.StatusOK, getJSON(t, url+"/data/provider/0x000000000000000000000000000000000000000a", &info))
+	assert.Equal(t, big.NewInt(5), info[80001].Balance)
+
+	var herr hermesError
+	assert.Equal(t, http.StatusNotFound, getJSON(t, url+"/data/consumer/0x000000000000000000000000000000000000000a", &herr))
+	assert.Equal(t, HermesCauseNotFound, herr.Cause)
+}
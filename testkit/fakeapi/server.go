@@ -0,0 +1,257 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package fakeapi provides lightweight scriptable fakes of the transactor and hermes APIs,
+// so that registration, settlement and payment flows can be tested without the real services.
+package fakeapi
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Response is a scripted response of the fake API.
+type Response struct {
+	// Status is the HTTP status code, 200 is used if empty.
+	Status int
+	// Body is written as is if it's a []byte and JSON encoded otherwise.
+	Body interface{}
+	// ContentType of the body, "application/json" is used if empty.
+	ContentType string
+	// Delay before the response is written.
+	Delay time.Duration
+}
+
+// OK returns a successful response with the given body.
+func OK(body interface{}) Response {
+	return Response{Status: http.StatusOK, Body: body}
+}
+
+// Delayed returns the given response delayed by d.
+func Delayed(d time.Duration, r Response) Response {
+	r.Delay = d
+	return r
+}
+
+// Request is a request received by the fake API.
+type Request struct {
+	Method string
+	Path   string
+	Header http.Header
+	Body   []byte
+}
+
+// Decode decodes JSON body of the request into v.
+func (r Request) Decode(v interface{}) error {
+	return json.Unmarshal(r.Body, v)
+}
+
+// ResponseFunc builds a response to the given request.
+type ResponseFunc func(r Request) Response
+
+type route struct {
+	method    string
+	pattern   []string
+	responses []ResponseFunc
+}
+
+func (r *route) matches(method string, path []string) bool {
+	if r.method != method || len(r.pattern) != len(path) {
+		return false
+	}
+	for i, segment := range r.pattern {
+		if segment != "*" && segment != path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// next returns the next scripted response, the last one is repeated once the script is exhausted.
+func (r *route) next() ResponseFunc {
+	next := r.responses[0]
+	if len(r.responses) > 1 {
+		r.responses = r.responses[1:]
+	}
+	return next
+}
+
+// Server is a fake API serving scripted responses.
+// Routes are matched by method and path, "*" in the path pattern matches any single path segment.
+type Server struct {
+	lock     sync.Mutex
+	routes   []*route
+	requests []Request
+	server   *httptest.Server
+}
+
+// On scripts the responses to the requests matching method and path pattern.
+// Responses are returned in the given order, the last one is repeated for all the following requests.
+// It replaces any responses scripted for the same route before.
+func (s *Server) On(method, pattern string, responses ...Response) {
+	funcs := make([]ResponseFunc, len(responses))
+	for i := range responses {
+		resp := responses[i]
+		funcs[i] = func(Request) Response { return resp }
+	}
+	s.OnFunc(method, pattern, funcs...)
+}
+
+// OnFunc scripts the response builders of the requests matching method and path pattern, see On.
+func (s *Server) OnFunc(method, pattern string, responses ...ResponseFunc) {
+	if len(responses) == 0 {
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	r := &route{method: method, pattern: splitPath(pattern), responses: responses}
+	for i, existing := range s.routes {
+		if existing.method == r.method && strings.Join(existing.pattern, "/") == strings.Join(r.pattern, "/") {
+			s.routes[i] = r
+			return
+		}
+	}
+	s.routes = append(s.routes, r)
+}
+
+// Requests returns the received requests matching method and path pattern.
+func (s *Server) Requests(method, pattern string) []Request {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	r := route{method: method, pattern: splitPath(pattern)}
+	var res []Request
+	for _, req := range s.requests {
+		if r.matches(req.Method, splitPath(req.Path)) {
+			res = append(res, req)
+		}
+	}
+	return res
+}
+
+// ServeHTTP serves the scripted responses, requests of unknown routes get 404.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	req := Request{Method: r.Method, Path: r.URL.Path, Header: r.Header, Body: body}
+
+	respond := s.match(req)
+	if respond == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	resp := respond(req)
+	if resp.Delay > 0 {
+		select {
+		case <-time.After(resp.Delay):
+		case <-r.Context().Done():
+			return
+		}
+	}
+	write(w, resp)
+}
+
+func (s *Server) match(req Request) ResponseFunc {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.requests = append(s.requests, req)
+	path := splitPath(req.Path)
+	for _, r := range s.routes {
+		if r.matches(req.Method, path) {
+			return r.next()
+		}
+	}
+	return nil
+}
+
+// Start starts serving the fake API on a local port and returns its base URL.
+func (s *Server) Start() string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.server == nil {
+		s.server = httptest.NewServer(s)
+	}
+	return s.server.URL
+}
+
+// URL returns the base URL of the started fake API.
+func (s *Server) URL() string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.server == nil {
+		return ""
+	}
+	return s.server.URL
+}
+
+// Close stops serving the fake API.
+func (s *Server) Close() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.server != nil {
+		s.server.Close()
+		s.server = nil
+	}
+}
+
+func write(w http.ResponseWriter, resp Response) {
+	status := resp.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	var body []byte
+	switch b := resp.Body.(type) {
+	case nil:
+	case []byte:
+		body = b
+	default:
+		encoded, err := json.Marshal(b)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		body = encoded
+	}
+
+	contentType := resp.ContentType
+	if contentType == "" {
+		contentType = "application/json"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(status)
+	w.Write(body)
+}
+
+func splitPath(path string) []string {
+	return strings.Split(strings.Trim(path, "/"), "/")
+}
@@ -0,0 +1,102 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package fakeapi
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_ScriptedResponses(t *testing.T) {
+	s := &Server{}
+	url := s.Start()
+	defer s.Close()
+
+	s.On(http.MethodGet, "items/*", OK("first"), Response{Status: http.StatusServiceUnavailable})
+
+	status, body := get(t, url+"/items/1")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, `"first"`, body)
+
+	for i := 0; i < 2; i++ {
+		status, _ = get(t, url+"/items/2")
+		assert.Equal(t, http.StatusServiceUnavailable, status)
+	}
+
+	status, _ = get(t, url+"/items/1/details")
+	assert.Equal(t, http.StatusNotFound, status)
+	assert.Len(t, s.Requests(http.MethodGet, "items/*"), 3)
+}
+
+func TestServer_Delay(t *testing.T) {
+	s := &Server{}
+	url := s.Start()
+	defer s.Close()
+
+	s.On(http.MethodPost, "slow", Delayed(50*time.Millisecond, OK(nil)))
+
+	started := time.Now()
+	resp, err := http.Post(url+"/slow", "application/json", strings.NewReader(`{"a":1}`))
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.GreaterOrEqual(t, time.Since(started), 50*time.Millisecond)
+	requests := s.Requests(http.MethodPost, "slow")
+	require.Len(t, requests, 1)
+	var body map[string]int
+	assert.NoError(t, requests[0].Decode(&body))
+	assert.Equal(t, 1, body["a"])
+}
+
+func TestServer_ReplacesRoute(t *testing.T) {
+	s := &Server{}
+	url := s.Start()
+	defer s.Close()
+
+	s.On(http.MethodGet, "value", OK(1))
+	s.On(http.MethodGet, "/value/", OK(2))
+
+	_, body := get(t, url+"/value")
+	assert.Equal(t, "2", body)
+}
+
+func get(t *testing.T, url string) (int, string) {
+	resp, err := http.Get(url)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, strings.TrimSpace(string(body))
+}
+
+func getJSON(t *testing.T, url string, v interface{}) int {
+	resp, err := http.Get(url)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(v))
+	return resp.StatusCode
+}
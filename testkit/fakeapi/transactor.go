@@ -0,0 +1,190 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package fakeapi
+
+import (
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// errorContentType is the content type of the errors returned by transactor.
+const errorContentType = "application/vnd.mysterium.error+json"
+
+// Transactor is a fake transactor API.
+//
+// By default it quotes zero fees, accepts registrations and settlements,
+// reports queued transactions as done and denies free registrations.
+type Transactor struct {
+	Server
+
+	lock  sync.Mutex
+	fees  map[string]*big.Int
+	valid time.Duration
+	queue int
+}
+
+// NewTransactor returns a new fake transactor, call Start to serve it.
+func NewTransactor() *Transactor {
+	t := &Transactor{
+		fees:  make(map[string]*big.Int),
+		valid: time.Hour,
+	}
+
+	for _, kind := range []string{"register", "settle", "stake/decrease"} {
+		kind := kind
+		t.OnFunc(http.MethodGet, "fee/*/"+kind, func(Request) Response {
+			return OK(feesResponse{Fee: t.fee(kind), ValidUntil: t.validUntil()})
+		})
+	}
+	t.OnFunc(http.MethodGet, "fee/*", func(Request) Response {
+		fees := t.combinedFees()
+		return OK(combinedFeesResponse{Current: fees, Last: fees, ServerTime: time.Now().UTC()})
+	})
+
+	for _, pattern := range []string{"identity/register", "identity/register/provider", "identity/register/referer", "channel/open", "stake/decrease"} {
+		t.On(http.MethodPost, pattern, OK(nil))
+	}
+	for _, pattern := range []string{"identity/settle_and_rebalance", "identity/settle_with_beneficiary", "identity/settle/into_stake", "identity/pay_and_settle"} {
+		t.OnFunc(http.MethodPost, pattern, func(Request) Response {
+			return OK(settleResponse{ID: t.nextQueueID()})
+		})
+	}
+	t.OnFunc(http.MethodGet, "queue/*", func(r Request) Response {
+		return OK(queueResponse{ID: r.Path[strings.LastIndex(r.Path, "/")+1:], State: "done"})
+	})
+	t.On(http.MethodPost, "channel/status", OK(channelStatusResponse{Status: "open"}))
+	t.On(http.MethodGet, "identity/*/status", OK([]struct{}{}))
+	t.SetFreeRegistration(false)
+
+	return t
+}
+
+// SetFee sets the fee quoted for the given kind of transaction: "register", "settle" or "stake/decrease".
+func (t *Transactor) SetFee(kind string, fee *big.Int) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.fees[kind] = fee
+}
+
+// SetFeeValidity sets for how long the quoted fees are valid.
+func (t *Transactor) SetFeeValidity(d time.Duration) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.valid = d
+}
+
+// SetFreeRegistration sets whether identities and providers are eligible for free registration.
+func (t *Transactor) SetFreeRegistration(eligible bool) {
+	t.On(http.MethodGet, "identity/register/eligibility/*", OK(eligibilityResponse{Eligible: eligible}))
+	t.On(http.MethodGet, "identity/register/provider/eligibility", OK(eligibilityResponse{Eligible: eligible}))
+}
+
+// TransactorError returns the transactor error response.
+func TransactorError(status int, code, message string) Response {
+	return Response{
+		Status:      status,
+		ContentType: errorContentType,
+		Body: apiError{
+			Err:    apiErrorDetails{Code: code, Message: message},
+			Status: status,
+		},
+	}
+}
+
+func (t *Transactor) fee(kind string) *big.Int {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if fee, ok := t.fees[kind]; ok {
+		return fee
+	}
+	return new(big.Int)
+}
+
+func (t *Transactor) validUntil() time.Time {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	return time.Now().UTC().Add(t.valid)
+}
+
+func (t *Transactor) combinedFees() fees {
+	return fees{
+		DecreaseStake: t.fee("stake/decrease"),
+		Settle:        t.fee("settle"),
+		Register:      t.fee("register"),
+		ValidUntil:    t.validUntil(),
+	}
+}
+
+func (t *Transactor) nextQueueID() string {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.queue++
+	return fmt.Sprintf("tx-%d", t.queue)
+}
+
+type feesResponse struct {
+	Fee        *big.Int  `json:"fee"`
+	ValidUntil time.Time `json:"valid_until"`
+}
+
+type fees struct {
+	DecreaseStake *big.Int  `json:"decreaseStake"`
+	Settle        *big.Int  `json:"settle"`
+	Register      *big.Int  `json:"register"`
+	ValidUntil    time.Time `json:"valid_until"`
+}
+
+type combinedFeesResponse struct {
+	Current    fees      `json:"current"`
+	Last       fees      `json:"last"`
+	ServerTime time.Time `json:"server_time"`
+}
+
+type settleResponse struct {
+	ID string `json:"id"`
+}
+
+type queueResponse struct {
+	ID    string `json:"id"`
+	Hash  string `json:"tx_hash"`
+	State string `json:"state"`
+	Error string `json:"error"`
+}
+
+type channelStatusResponse struct {
+	Status string `json:"status"`
+}
+
+type eligibilityResponse struct {
+	Eligible bool `json:"eligible"`
+}
+
+type apiError struct {
+	Err    apiErrorDetails `json:"error"`
+	Status int             `json:"status"`
+}
+
+type apiErrorDetails struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
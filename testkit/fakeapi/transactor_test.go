@@ -0,0 +1,61 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package fakeapi
+
+import (
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransactor_Fees(t *testing.T) {
+	tr := NewTransactor()
+	url := tr.Start()
+	defer tr.Close()
+
+	tr.SetFee("register", big.NewInt(100))
+
+	var fee feesResponse
+	assert.Equal(t, http.StatusOK, getJSON(t, url+"/fee/80001/register", &fee))
+	assert.Equal(t, big.NewInt(100), fee.Fee)
+	assert.True(t, fee.ValidUntil.After(time.Now()))
+
+	var combined combinedFeesResponse
+	assert.Equal(t, http.StatusOK, getJSON(t, url+"/fee/80001", &combined))
+	assert.Equal(t, big.NewInt(100), combined.Current.Register)
+	assert.Equal(t, big.NewInt(0), combined.Current.Settle)
+}
+
+func TestTransactor_Error(t *testing.T) {
+	tr := NewTransactor()
+	url := tr.Start()
+	defer tr.Close()
+
+	tr.On(http.MethodGet, "identity/register/eligibility/*", TransactorError(http.StatusBadRequest, "bad_request", "invalid identity"))
+
+	var apiErr apiError
+	assert.Equal(t, http.StatusBadRequest, getJSON(t, url+"/identity/register/eligibility/0x1", &apiErr))
+	assert.Equal(t, "invalid identity", apiErr.Err.Message)
+
+	var eligibility eligibilityResponse
+	assert.Equal(t, http.StatusOK, getJSON(t, url+"/identity/register/provider/eligibility", &eligibility))
+	assert.False(t, eligibility.Eligible)
+}
@@ -0,0 +1,179 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package replay records broker and Ethereum RPC exchanges of a real run into a fixture file
+// and serves them back deterministically, so that complex flows can be covered by regression tests.
+package replay
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// Exchange kinds.
+const (
+	KindRPC    = "rpc"
+	KindBroker = "broker"
+)
+
+// Exchange is a single recorded request and its response.
+type Exchange struct {
+	Kind string `json:"kind"`
+	// Key identifies the exchange, RPC method or broker subject.
+	Key      string `json:"key"`
+	Request  []byte `json:"request"`
+	Response []byte `json:"response,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// Recorder appends exchanges to a fixture file, one JSON encoded exchange per line.
+type Recorder struct {
+	lock sync.Mutex
+	file *os.File
+}
+
+// NewRecorder creates a recorder writing to the fixture file at path, the existing file is truncated.
+func NewRecorder(path string) (*Recorder, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not create fixture file: %w", err)
+	}
+	return &Recorder{file: file}, nil
+}
+
+// Record appends the exchange to the fixture file.
+func (r *Recorder) Record(ex Exchange) error {
+	line, err := json.Marshal(ex)
+	if err != nil {
+		return fmt.Errorf("could not encode exchange: %w", err)
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.file == nil {
+		return os.ErrClosed
+	}
+	_, err = r.file.Write(append(line, '\n'))
+	return err
+}
+
+// Close closes the fixture file.
+func (r *Recorder) Close() error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
+
+// Load reads the exchanges from the fixture file at path.
+func Load(path string) ([]Exchange, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not open fixture file: %w", err)
+	}
+	defer file.Close()
+
+	var exchanges []Exchange
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 16*1024*1024)
+	for scanner.Scan() {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var ex Exchange
+		if err := json.Unmarshal(scanner.Bytes(), &ex); err != nil {
+			return nil, fmt.Errorf("could not decode exchange: %w", err)
+		}
+		exchanges = append(exchanges, ex)
+	}
+	return exchanges, scanner.Err()
+}
+
+// Player serves the recorded exchanges back.
+type Player struct {
+	lock      sync.Mutex
+	exchanges []Exchange
+	used      []bool
+	last      map[string]Exchange
+}
+
+// NewPlayer returns a player of the given exchanges.
+func NewPlayer(exchanges []Exchange) *Player {
+	return &Player{
+		exchanges: exchanges,
+		used:      make([]bool, len(exchanges)),
+		last:      make(map[string]Exchange),
+	}
+}
+
+// Next returns the recorded exchange for the request.
+//
+// The first unused exchange with the same kind, key and request is preferred,
+// then the first unused one with the same kind and key, so requests carrying nonces or timestamps are replayed in the recorded order.
+// Once all of them are used, the last returned one is repeated, which keeps polling requests working.
+func (p *Player) Next(kind, key string, request []byte) (Exchange, bool) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	match := -1
+	for i, ex := range p.exchanges {
+		if p.used[i] || ex.Kind != kind || ex.Key != key {
+			continue
+		}
+		if bytes.Equal(ex.Request, request) {
+			match = i
+			break
+		}
+		if match < 0 {
+			match = i
+		}
+	}
+
+	lastKey := kind + "/" + key
+	if match < 0 {
+		ex, ok := p.last[lastKey]
+		return ex, ok
+	}
+
+	p.used[match] = true
+	p.last[lastKey] = p.exchanges[match]
+	return p.exchanges[match], true
+}
+
+// Unused returns the exchanges which were not replayed yet.
+func (p *Player) Unused() []Exchange {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	var res []Exchange
+	for i, ex := range p.exchanges {
+		if !p.used[i] {
+			res = append(res, ex)
+		}
+	}
+	return res
+}
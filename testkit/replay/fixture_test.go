@@ -0,0 +1,73 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package replay
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorder_Load(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fixture.jsonl")
+	rec, err := NewRecorder(path)
+	require.NoError(t, err)
+
+	exchanges := []Exchange{
+		{Kind: KindBroker, Key: "subject", Request: []byte("ping"), Response: []byte("pong")},
+		{Kind: KindRPC, Key: "eth_chainId", Request: []byte(`{"method":"eth_chainId"}`), Error: "unavailable"},
+	}
+	for _, ex := range exchanges {
+		require.NoError(t, rec.Record(ex))
+	}
+	require.NoError(t, rec.Close())
+	assert.Error(t, rec.Record(exchanges[0]))
+
+	loaded, err := Load(path)
+	assert.NoError(t, err)
+	assert.Equal(t, exchanges, loaded)
+}
+
+func TestPlayer_Next(t *testing.T) {
+	p := NewPlayer([]Exchange{
+		{Kind: KindBroker, Key: "a", Request: []byte("1"), Response: []byte("first")},
+		{Kind: KindBroker, Key: "a", Request: []byte("2"), Response: []byte("second")},
+		{Kind: KindRPC, Key: "a", Request: []byte("2"), Response: []byte("rpc")},
+	})
+
+	// exact request match is preferred
+	ex, ok := p.Next(KindBroker, "a", []byte("2"))
+	assert.True(t, ok)
+	assert.Equal(t, "second", string(ex.Response))
+
+	// recorded order is used otherwise
+	ex, ok = p.Next(KindBroker, "a", []byte("3"))
+	assert.True(t, ok)
+	assert.Equal(t, "first", string(ex.Response))
+
+	// last one is repeated
+	ex, ok = p.Next(KindBroker, "a", []byte("4"))
+	assert.True(t, ok)
+	assert.Equal(t, "first", string(ex.Response))
+
+	_, ok = p.Next(KindBroker, "b", nil)
+	assert.False(t, ok)
+	assert.Len(t, p.Unused(), 1)
+}
@@ -0,0 +1,220 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package replay
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+type rpcMessage struct {
+	Version string          `json:"jsonrpc,omitempty"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   json.RawMessage `json:"error,omitempty"`
+}
+
+// rpcCall is a single or batch JSON-RPC call with the ids stripped, so that it can be matched regardless of the ids.
+type rpcCall struct {
+	batch    bool
+	ids      []json.RawMessage
+	key      string
+	messages []rpcMessage
+}
+
+func parseRPCCall(body []byte) (*rpcCall, error) {
+	call := &rpcCall{}
+	body = bytes.TrimSpace(body)
+	if len(body) > 0 && body[0] == '[' {
+		call.batch = true
+		if err := json.Unmarshal(body, &call.messages); err != nil {
+			return nil, err
+		}
+	} else {
+		var msg rpcMessage
+		if err := json.Unmarshal(body, &msg); err != nil {
+			return nil, err
+		}
+		call.messages = []rpcMessage{msg}
+	}
+
+	methods := make([]string, len(call.messages))
+	for i := range call.messages {
+		call.ids = append(call.ids, call.messages[i].ID)
+		call.messages[i].ID = nil
+		methods[i] = call.messages[i].Method
+	}
+	call.key = strings.Join(methods, ",")
+	return call, nil
+}
+
+// request returns the call without ids.
+func (c *rpcCall) request() ([]byte, error) {
+	if c.batch {
+		return json.Marshal(c.messages)
+	}
+	return json.Marshal(c.messages[0])
+}
+
+// stripResponse orders the responses of the call as the requests and strips their ids.
+func (c *rpcCall) stripResponse(body []byte) ([]byte, error) {
+	if !c.batch {
+		var msg rpcMessage
+		if err := json.Unmarshal(body, &msg); err != nil {
+			return nil, err
+		}
+		msg.ID = nil
+		return json.Marshal(msg)
+	}
+
+	var msgs []rpcMessage
+	if err := json.Unmarshal(body, &msgs); err != nil {
+		return nil, err
+	}
+	ordered := make([]rpcMessage, len(c.ids))
+	for _, msg := range msgs {
+		for i, id := range c.ids {
+			if bytes.Equal(id, msg.ID) {
+				msg.ID = nil
+				ordered[i] = msg
+				break
+			}
+		}
+	}
+	return json.Marshal(ordered)
+}
+
+// restoreResponse sets the ids of the call to the recorded response.
+func (c *rpcCall) restoreResponse(body []byte) ([]byte, error) {
+	if !c.batch {
+		var msg rpcMessage
+		if err := json.Unmarshal(body, &msg); err != nil {
+			return nil, err
+		}
+		msg.ID = c.ids[0]
+		return json.Marshal(msg)
+	}
+
+	var msgs []rpcMessage
+	if err := json.Unmarshal(body, &msgs); err != nil {
+		return nil, err
+	}
+	if len(msgs) != len(c.ids) {
+		return nil, errors.New("recorded batch size does not match")
+	}
+	for i := range msgs {
+		msgs[i].ID = c.ids[i]
+	}
+	return json.Marshal(msgs)
+}
+
+// NewRPCRecorder returns a proxy forwarding HTTP JSON-RPC calls to target and recording the exchanges.
+func NewRPCRecorder(target string, client *http.Client, rec *Recorder) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		resp, err := client.Post(target, "application/json", bytes.NewReader(body))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		defer resp.Body.Close()
+
+		respBody, err := io.ReadAll(resp.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+
+		if resp.StatusCode == http.StatusOK {
+			if err := recordRPC(rec, body, respBody); err != nil {
+				log.Warn().Err(err).Msg("Could not record RPC exchange")
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(resp.StatusCode)
+		w.Write(respBody)
+	})
+}
+
+func recordRPC(rec *Recorder, request, response []byte) error {
+	call, err := parseRPCCall(request)
+	if err != nil {
+		return fmt.Errorf("could not parse request: %w", err)
+	}
+	req, err := call.request()
+	if err != nil {
+		return err
+	}
+	resp, err := call.stripResponse(response)
+	if err != nil {
+		return fmt.Errorf("could not parse response: %w", err)
+	}
+	return rec.Record(Exchange{Kind: KindRPC, Key: call.key, Request: req, Response: resp})
+}
+
+// NewRPCReplay returns a HTTP JSON-RPC server replaying the recorded exchanges.
+// Calls which were never recorded get 404.
+func NewRPCReplay(player *Player) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		call, err := parseRPCCall(body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		req, err := call.request()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		ex, ok := player.Next(KindRPC, call.key, req)
+		if !ok {
+			http.Error(w, fmt.Sprintf("no recorded exchange for %q", call.key), http.StatusNotFound)
+			return
+		}
+		resp, err := call.restoreResponse(ex.Response)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(resp)
+	})
+}
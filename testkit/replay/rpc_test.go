@@ -0,0 +1,87 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package replay
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRPC_RecordAndReplay(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if strings.HasPrefix(string(body), "[") {
+			// respond to the batch out of order
+			w.Write([]byte(`[{"jsonrpc":"2.0","id":2,"result":"0x2"},{"jsonrpc":"2.0","id":1,"result":"0x1"}]`))
+			return
+		}
+		var msg rpcMessage
+		json.Unmarshal(body, &msg)
+		w.Write([]byte(`{"jsonrpc":"2.0","id":` + string(msg.ID) + `,"result":"0x89"}`))
+	}))
+	defer upstream.Close()
+
+	path := filepath.Join(t.TempDir(), "fixture.jsonl")
+	rec, err := NewRecorder(path)
+	require.NoError(t, err)
+
+	proxy := httptest.NewServer(NewRPCRecorder(upstream.URL, http.DefaultClient, rec))
+	resp := post(t, proxy.URL, `{"jsonrpc":"2.0","id":7,"method":"eth_chainId","params":[]}`)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","id":7,"result":"0x89"}`, resp)
+	post(t, proxy.URL, `[{"jsonrpc":"2.0","id":1,"method":"eth_getBalance","params":["0x1"]},{"jsonrpc":"2.0","id":2,"method":"eth_getBalance","params":["0x2"]}]`)
+	proxy.Close()
+	require.NoError(t, rec.Close())
+
+	exchanges, err := Load(path)
+	require.NoError(t, err)
+	require.Len(t, exchanges, 2)
+	assert.Equal(t, "eth_chainId", exchanges[0].Key)
+	assert.Equal(t, "eth_getBalance,eth_getBalance", exchanges[1].Key)
+
+	replay := httptest.NewServer(NewRPCReplay(NewPlayer(exchanges)))
+	defer replay.Close()
+
+	resp = post(t, replay.URL, `{"jsonrpc":"2.0","id":42,"method":"eth_chainId","params":[]}`)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","id":42,"result":"0x89"}`, resp)
+
+	resp = post(t, replay.URL, `[{"jsonrpc":"2.0","id":5,"method":"eth_getBalance","params":["0x1"]},{"jsonrpc":"2.0","id":6,"method":"eth_getBalance","params":["0x2"]}]`)
+	assert.JSONEq(t, `[{"jsonrpc":"2.0","id":5,"result":"0x1"},{"jsonrpc":"2.0","id":6,"result":"0x2"}]`, resp)
+
+	res, err := http.Post(replay.URL, "application/json", strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"eth_blockNumber"}`))
+	require.NoError(t, err)
+	res.Body.Close()
+	assert.Equal(t, http.StatusNotFound, res.StatusCode)
+}
+
+func post(t *testing.T, url, body string) string {
+	resp, err := http.Post(url, "application/json", strings.NewReader(body))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	res, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return string(res)
+}
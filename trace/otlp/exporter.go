@@ -0,0 +1,252 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package otlp
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/eventbus"
+	"github.com/mysteriumnetwork/node/trace"
+)
+
+const (
+	tracesPath = "/v1/traces"
+	scopeName  = "github.com/mysteriumnetwork/node"
+
+	spanKindInternal = 1
+	statusCodeError  = 2
+)
+
+// Exporter sends traced spans to an OpenTelemetry collector using OTLP over HTTP with JSON encoding.
+// Spans sharing the same trace ID, e.g. the session ID, are exported as a single trace.
+type Exporter struct {
+	url         string
+	serviceName string
+	attributes  map[string]string
+	client      *http.Client
+	interval    time.Duration
+	batchSize   int
+
+	mu    sync.Mutex
+	spans []span
+
+	flush chan struct{}
+	stop  chan struct{}
+	done  chan struct{}
+	once  sync.Once
+}
+
+// NewExporter returns a new exporter sending spans every interval to the collector at the given endpoint.
+// Resource attributes are attached to every exported span.
+func NewExporter(endpoint, serviceName string, attributes map[string]string, interval time.Duration) (*Exporter, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid OTLP endpoint: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid OTLP endpoint %q: scheme must be http or https", endpoint)
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + tracesPath
+
+	return &Exporter{
+		url:         u.String(),
+		serviceName: serviceName,
+		attributes:  attributes,
+		client:      &http.Client{Timeout: 10 * time.Second},
+		interval:    interval,
+		batchSize:   512,
+		flush:       make(chan struct{}, 1),
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}, nil
+}
+
+// Subscribe subscribes the exporter to the traced events.
+func (e *Exporter) Subscribe(bus eventbus.Subscriber) error {
+	if err := bus.SubscribeAsync(trace.AppTopicTraceEvent, e.consumeTraceEvent); err != nil {
+		return err
+	}
+	return bus.SubscribeAsync(trace.AppTopicTraceSpan, e.consumeTraceSpan)
+}
+
+// Start starts sending collected spans. Blocks until stopped.
+func (e *Exporter) Start() {
+	defer close(e.done)
+
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-e.stop:
+			e.export()
+			return
+		case <-ticker.C:
+			e.export()
+		case <-e.flush:
+			e.export()
+		}
+	}
+}
+
+// Stop sends the remaining spans and stops the exporter.
+func (e *Exporter) Stop() {
+	e.once.Do(func() {
+		close(e.stop)
+		<-e.done
+	})
+}
+
+func (e *Exporter) consumeTraceEvent(ev trace.Event) {
+	e.add(trace.Span{
+		ID:    ev.ID,
+		Name:  ev.Key,
+		Start: ev.Start,
+		End:   ev.Start.Add(ev.Duration),
+	})
+}
+
+func (e *Exporter) consumeTraceSpan(s trace.Span) {
+	e.add(s)
+}
+
+func (e *Exporter) add(s trace.Span) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.spans = append(e.spans, newSpan(s))
+	if len(e.spans) < e.batchSize {
+		return
+	}
+
+	select {
+	case e.flush <- struct{}{}:
+	default:
+	}
+}
+
+func (e *Exporter) export() {
+	e.mu.Lock()
+	spans := e.spans
+	e.spans = nil
+	e.mu.Unlock()
+
+	if len(spans) == 0 {
+		return
+	}
+
+	if err := e.send(spans); err != nil {
+		log.Warn().Err(err).Msgf("Failed to export %d trace spans", len(spans))
+	}
+}
+
+func (e *Exporter) send(spans []span) error {
+	attributes := []attribute{stringAttribute("service.name", e.serviceName)}
+	attributes = append(attributes, toAttributes(e.attributes)...)
+
+	body, err := json.Marshal(exportRequest{
+		ResourceSpans: []resourceSpans{{
+			Resource: resource{Attributes: attributes},
+			ScopeSpans: []scopeSpans{{
+				Scope: scope{Name: scopeName},
+				Spans: spans,
+			}},
+		}},
+	})
+	if err != nil {
+		return fmt.Errorf("could not encode trace spans: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("could not send trace spans: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("collector responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func newSpan(s trace.Span) span {
+	res := span{
+		TraceID:           traceID(s.ID),
+		SpanID:            randomHex(8),
+		Name:              s.Name,
+		Kind:              spanKindInternal,
+		StartTimeUnixNano: strconv.FormatInt(s.Start.UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(s.End.UnixNano(), 10),
+		Attributes:        toAttributes(s.Attributes),
+	}
+	if s.ID != "" {
+		res.Attributes = append(res.Attributes, stringAttribute("mysterium.trace_id", s.ID))
+	}
+	if s.Error != "" {
+		res.Status = &status{Code: statusCodeError, Message: s.Error}
+	}
+	return res
+}
+
+// traceID derives the trace ID from the ID of the traced flow, so its spans are correlated.
+func traceID(id string) string {
+	if id == "" {
+		return randomHex(16)
+	}
+	sum := sha256.Sum256([]byte(id))
+	return hex.EncodeToString(sum[:16])
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		log.Error().Err(err).Msg("Failed to generate trace span ID")
+	}
+	return hex.EncodeToString(b)
+}
+
+func toAttributes(m map[string]string) []attribute {
+	attributes := make([]attribute, 0, len(m))
+	for k, v := range m {
+		attributes = append(attributes, stringAttribute(k, v))
+	}
+	return attributes
+}
+
+func stringAttribute(key, value string) attribute {
+	return attribute{Key: key, Value: attributeValue{StringValue: value}}
+}
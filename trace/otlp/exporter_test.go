@@ -0,0 +1,101 @@
+/*
+ * Copyright (C) 2024 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package otlp
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mysteriumnetwork/node/eventbus"
+	"github.com/mysteriumnetwork/node/trace"
+)
+
+func TestExporterSendsCorrelatedSpans(t *testing.T) {
+	requests := make(chan exportRequest, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/otlp/v1/traces", r.URL.Path)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		var req exportRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		requests <- req
+	}))
+	defer server.Close()
+
+	exporter, err := NewExporter(server.URL+"/otlp/", "myst", map[string]string{"node.id": "0x1"}, time.Hour)
+	require.NoError(t, err)
+
+	bus := eventbus.New()
+	require.NoError(t, exporter.Subscribe(bus))
+	go exporter.Start()
+
+	start := time.Unix(1, 500)
+	bus.Publish(trace.AppTopicTraceEvent, trace.Event{ID: "session-1", Key: "Consumer session creation", Start: start, Duration: time.Second})
+	bus.Publish(trace.AppTopicTraceSpan, trace.Span{ID: "session-1", Name: "Consumer payment exchange", Start: start, End: start.Add(time.Millisecond), Error: "boom"})
+	bus.Publish(trace.AppTopicTraceSpan, trace.Span{Name: "API GET /healthcheck", Start: start, End: start})
+
+	assert.Eventually(t, func() bool {
+		exporter.mu.Lock()
+		defer exporter.mu.Unlock()
+		return len(exporter.spans) == 3
+	}, 2*time.Second, 10*time.Millisecond)
+	exporter.Stop()
+
+	req := <-requests
+	require.Len(t, req.ResourceSpans, 1)
+	assert.Contains(t, req.ResourceSpans[0].Resource.Attributes, stringAttribute("service.name", "myst"))
+	assert.Contains(t, req.ResourceSpans[0].Resource.Attributes, stringAttribute("node.id", "0x1"))
+
+	require.Len(t, req.ResourceSpans[0].ScopeSpans, 1)
+	spans := req.ResourceSpans[0].ScopeSpans[0].Spans
+	require.Len(t, spans, 3)
+
+	byName := make(map[string]span)
+	for _, s := range spans {
+		assert.Len(t, s.TraceID, 32)
+		assert.Len(t, s.SpanID, 16)
+		byName[s.Name] = s
+	}
+
+	session := byName["Consumer session creation"]
+	assert.Equal(t, "1000000500", session.StartTimeUnixNano)
+	assert.Equal(t, "2000000500", session.EndTimeUnixNano)
+	assert.Nil(t, session.Status)
+
+	payment := byName["Consumer payment exchange"]
+	assert.Equal(t, session.TraceID, payment.TraceID)
+	assert.NotEqual(t, session.SpanID, payment.SpanID)
+	assert.Equal(t, &status{Code: statusCodeError, Message: "boom"}, payment.Status)
+
+	assert.NotEqual(t, session.TraceID, byName["API GET /healthcheck"].TraceID)
+}
+
+func TestNewExporterValidatesEndpoint(t *testing.T) {
+	_, err := NewExporter("localhost:4318", "myst", nil, time.Second)
+	assert.Error(t, err)
+
+	exporter, err := NewExporter("http://localhost:4318", "myst", nil, time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, "http://localhost:4318/v1/traces", exporter.url)
+}
@@ -30,6 +30,8 @@ import (
 const (
 	// AppTopicTraceEvent represents event topic for Trace events
 	AppTopicTraceEvent = "Trace"
+	// AppTopicTraceSpan represents event topic for spans of traced operations
+	AppTopicTraceSpan = "TraceSpan"
 )
 
 // NewTracer returns new tracer instance.
@@ -110,6 +112,22 @@ func (t *Tracer) Finish(eventPublisher eventbus.Publisher, id string) string {
 	return strings.Join(strs, ", ")
 }
 
+// Stages returns the stages traced so far in the order they were started.
+func (t *Tracer) Stages() []Stage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	stages := make([]Stage, len(t.stages))
+	for i, s := range t.stages {
+		stages[i] = Stage{
+			Key:   s.key,
+			Start: s.start,
+			End:   s.end,
+		}
+	}
+	return stages
+}
+
 func (t *Tracer) findStage(key string) (*stage, bool) {
 	for _, s := range t.stages {
 		if s.key == key {
@@ -128,6 +146,7 @@ func (t *Tracer) publishStageEvent(eventPublisher eventbus.Publisher, id string,
 		Event{
 			ID:       id,
 			Key:      stage.key,
+			Start:    stage.start,
 			Duration: stage.end.Sub(stage.start),
 		},
 	)
@@ -138,9 +157,34 @@ type stage struct {
 	start, end time.Time
 }
 
+// Stage represents a traced stage, End is zero while the stage is in progress.
+type Stage struct {
+	Key        string
+	Start, End time.Time
+}
+
+// Duration returns how long the stage took, zero while it is in progress.
+func (s Stage) Duration() time.Duration {
+	if s.End.IsZero() {
+		return 0
+	}
+	return s.End.Sub(s.Start)
+}
+
 // Event represents a published Trace event.
 type Event struct {
 	ID       string
 	Key      string
+	Start    time.Time
 	Duration time.Duration
 }
+
+// Span represents a published traced operation. Spans sharing the same ID,
+// e.g. the session ID, belong to the same flow.
+type Span struct {
+	ID         string
+	Name       string
+	Start, End time.Time
+	Error      string
+	Attributes map[string]string
+}